@@ -0,0 +1,12 @@
+// Package grafana embeds the OSM Grafana dashboards shipped in the Helm chart, so that the OSM controller provisions
+// the same dashboards as the chart.
+package grafana
+
+import (
+	"embed"
+)
+
+// Dashboards holds the OSM Grafana dashboards, under the dashboards directory
+//
+//go:embed dashboards/*.json
+var Dashboards embed.FS
@@ -15,6 +15,7 @@ import (
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
 	"helm.sh/helm/v3/pkg/strvals"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -22,6 +23,7 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/preflight"
 )
 
 const installDesc = `
@@ -50,6 +52,14 @@ alphanumerics between.
 Example:
   $ osm install --mesh-name "hello-osm"
 
+The --preflight flag only runs the preflight checks of the cluster, reporting
+the conditions that would prevent the mesh from working: an unsupported
+Kubernetes version, conflicting webhooks, outdated CRDs, resources left over
+by other meshes and pod security constraints. The mesh is not installed.
+
+Example:
+  $ osm install --mesh-name "hello-osm" --preflight
+
 The mesh name is used in various ways like for naming Kubernetes resources as
 well as for adding a Kubernetes Namespace to the list of Namespaces a control
 plane should watch for sidecar injection of Envoy proxies.
@@ -76,6 +86,9 @@ type installCmd struct {
 	atomic         bool
 	// Toggle this to enforce only one mesh in this cluster
 	enforceSingleMesh bool
+	// Toggle this to only run the preflight checks, without installing the mesh
+	preflight           bool
+	extensionsClientSet apiextensionsclientset.Interface
 }
 
 func newInstallCmd(config *helm.Configuration, out io.Writer) *cobra.Command {
@@ -98,6 +111,12 @@ func newInstallCmd(config *helm.Configuration, out io.Writer) *cobra.Command {
 				return errors.Errorf("Could not access Kubernetes cluster, check kubeconfig: %s", err)
 			}
 			inst.clientSet = clientset
+
+			extensionsClientSet, err := apiextensionsclientset.NewForConfig(kubeconfig)
+			if err != nil {
+				return errors.Errorf("Could not access Kubernetes cluster, check kubeconfig: %s", err)
+			}
+			inst.extensionsClientSet = extensionsClientSet
 			return inst.run(config)
 		},
 	}
@@ -109,11 +128,16 @@ func newInstallCmd(config *helm.Configuration, out io.Writer) *cobra.Command {
 	f.DurationVar(&inst.timeout, "timeout", 5*time.Minute, "Time to wait for installation and resources in a ready state, zero means no timeout")
 	f.StringArrayVar(&inst.setOptions, "set", nil, "Set arbitrary chart values (can specify multiple or separate values with commas: key1=val1,key2=val2)")
 	f.BoolVar(&inst.atomic, "atomic", false, "Automatically clean up resources if installation fails")
+	f.BoolVar(&inst.preflight, "preflight", false, "Only run the preflight checks of the cluster, without installing the mesh")
 
 	return cmd
 }
 
 func (i *installCmd) run(config *helm.Configuration) error {
+	if i.preflight {
+		return i.runPreflight()
+	}
+
 	if err := i.validateOptions(); err != nil {
 		return err
 	}
@@ -139,6 +163,28 @@ func (i *installCmd) run(config *helm.Configuration) error {
 	return nil
 }
 
+// runPreflight prints the report of the preflight checks of the mesh, and returns an error if a check failed
+func (i *installCmd) runPreflight() error {
+	if err := isValidMeshName(i.meshName); err != nil {
+		return err
+	}
+
+	report := preflight.NewChecker(i.clientSet, i.extensionsClientSet, i.meshName).Run()
+
+	w := newTabWriter(i.out)
+	fmt.Fprintln(w, "CHECK\tSTATUS\tMESSAGE")
+	for _, result := range report.Results {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", result.Name, result.Status, result.Message)
+	}
+	_ = w.Flush()
+
+	if !report.Passed() {
+		return errors.Errorf("Preflight checks of mesh [%s] failed", i.meshName)
+	}
+	fmt.Fprintf(i.out, "Preflight checks of mesh [%s] passed\n", i.meshName)
+	return nil
+}
+
 func (i *installCmd) loadOSMChart() error {
 	var err error
 	if i.chartPath != "" {
@@ -21,7 +21,10 @@ import (
 	"helm.sh/helm/v3/pkg/storage/driver"
 	"helm.sh/helm/v3/pkg/strvals"
 	v1 "k8s.io/api/apps/v1"
+	extensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
 
@@ -785,6 +788,62 @@ func TestEnforceSingleMeshWithExistingMesh(t *testing.T) {
 	assert.Equal(err, errAlreadyExists)
 }
 
+func TestInstallPreflight(t *testing.T) {
+	testCases := []struct {
+		name          string
+		serverVersion string
+		expErr        bool
+		expOutput     []string
+	}{
+		{
+			name:          "passing preflight checks do not install the mesh",
+			serverVersion: "v1.21.0",
+			expOutput: []string{
+				"kubernetes-version     pass     Kubernetes version 1.21.0 is supported",
+				"Preflight checks of mesh [osm] passed",
+			},
+		},
+		{
+			name:          "failing preflight checks return an error",
+			serverVersion: "v1.18.0",
+			expErr:        true,
+			expOutput: []string{
+				"kubernetes-version     fail     Kubernetes version 1.18.0 is not supported, the minimum supported version is 1.19.0",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			out := new(bytes.Buffer)
+			store := storage.Init(driver.NewMemory())
+			config := &helm.Configuration{Releases: store}
+
+			fakeClientSet := fake.NewSimpleClientset()
+			fakeClientSet.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: tc.serverVersion}
+
+			install := &installCmd{
+				out:                 out,
+				meshName:            defaultMeshName,
+				clientSet:           fakeClientSet,
+				extensionsClientSet: extensionsfake.NewSimpleClientset(),
+				preflight:           true,
+			}
+
+			err := install.run(config)
+			assert.Equal(tc.expErr, err != nil)
+			for _, line := range tc.expOutput {
+				assert.Contains(out.String(), line)
+			}
+			releases, err := store.ListReleases()
+			assert.Nil(err)
+			assert.Empty(releases)
+		})
+	}
+}
+
 func createDeploymentSpec(namespace, meshName string) *v1.Deployment {
 	labelMap := make(map[string]string)
 	if meshName != "" {
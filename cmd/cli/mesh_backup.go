@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openservicemesh/osm/pkg/cli"
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+const meshBackupDescription = `
+This command will export a backup of the policy state of the mesh from the
+osm-controller: a versioned tar.gz archive holding the MeshConfig, and the OSM
+policy and SMI resources of the namespaces monitored by the mesh.
+
+The metadata set by the API server and the status of the resources are not
+part of the backup. The backup can be restored with 'osm mesh restore'.
+`
+
+const meshBackupExample = `
+# Export a backup of the mesh in the osm-system namespace to osm-backup.tar.gz
+osm mesh backup --osm-namespace osm-system --out-file osm-backup.tar.gz
+`
+
+type meshBackupCmd struct {
+	out       io.Writer
+	config    *rest.Config
+	clientSet kubernetes.Interface
+	outFile   string
+	localPort uint16
+}
+
+func newMeshBackupCmd(out io.Writer) *cobra.Command {
+	backupCmd := &meshBackupCmd{
+		out: out,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "export a backup of the policy state of the mesh",
+		Long:  meshBackupDescription,
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			config, err := settings.RESTClientGetter().ToRESTConfig()
+			if err != nil {
+				return errors.Errorf("Error fetching kubeconfig: %s", err)
+			}
+			backupCmd.config = config
+
+			clientset, err := kubernetes.NewForConfig(config)
+			if err != nil {
+				return errors.Errorf("Could not access Kubernetes cluster, check kubeconfig: %s", err)
+			}
+			backupCmd.clientSet = clientset
+			return backupCmd.run()
+		},
+		Example: meshBackupExample,
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&backupCmd.outFile, "out-file", "o", "", "Output file of the backup, defaults to osm-backup-<timestamp>.tar.gz")
+	f.Uint16VarP(&backupCmd.localPort, "local-port", "p", constants.OSMHTTPServerPort, "Local port to use for port forwarding")
+
+	return cmd
+}
+
+func (cmd *meshBackupCmd) run() error {
+	outFile := cmd.outFile
+	if outFile == "" {
+		outFile = fmt.Sprintf("osm-backup-%s.tar.gz", time.Now().UTC().Format("20060102-150405"))
+	}
+
+	fd, err := os.Create(outFile)
+	if err != nil {
+		return errors.Errorf("Error opening file %s: %s", outFile, err)
+	}
+	defer fd.Close() //nolint: errcheck, gosec
+
+	if err := cli.WriteBackup(cmd.clientSet, cmd.config, settings.Namespace(), cmd.localPort, fd); err != nil {
+		return annotateErrorMessageWithOsmNamespace("Error exporting backup: %s", err)
+	}
+
+	fmt.Fprintf(cmd.out, "Backup exported to %s\n", outFile)
+	return nil
+}
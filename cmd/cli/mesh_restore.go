@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/openservicemesh/osm/pkg/backup"
+)
+
+const meshRestoreDescription = `
+This command restores a backup exported with 'osm mesh backup': the MeshConfig
+is restored to the OSM namespace, and the OSM policy and SMI resources to the
+namespaces they were backed up from, which must exist.
+
+The restore is idempotent: the resources matching the backup are left
+unchanged, and the existing resources differing from the backup are either
+kept (--conflict-policy=skip) or overwritten (--conflict-policy=overwrite).
+With --dry-run, the outcome of the restore is reported without modifying any
+resource.
+`
+
+const meshRestoreExample = `
+# Report the outcome of the restore of osm-backup.tar.gz without modifying any resource
+osm mesh restore --file osm-backup.tar.gz --dry-run
+
+# Restore osm-backup.tar.gz, overwriting the existing resources differing from the backup
+osm mesh restore --file osm-backup.tar.gz --conflict-policy overwrite
+`
+
+type meshRestoreCmd struct {
+	out            io.Writer
+	dynamicClient  dynamic.Interface
+	file           string
+	conflictPolicy string
+	dryRun         bool
+}
+
+func newMeshRestoreCmd(out io.Writer) *cobra.Command {
+	restoreCmd := &meshRestoreCmd{
+		out: out,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "restore a backup of the policy state of the mesh",
+		Long:  meshRestoreDescription,
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			config, err := settings.RESTClientGetter().ToRESTConfig()
+			if err != nil {
+				return errors.Errorf("Error fetching kubeconfig: %s", err)
+			}
+
+			dynamicClient, err := dynamic.NewForConfig(config)
+			if err != nil {
+				return errors.Errorf("Could not access Kubernetes cluster, check kubeconfig: %s", err)
+			}
+			restoreCmd.dynamicClient = dynamicClient
+			return restoreCmd.run()
+		},
+		Example: meshRestoreExample,
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&restoreCmd.file, "file", "f", "", "Backup archive to restore")
+	f.StringVar(&restoreCmd.conflictPolicy, "conflict-policy", string(backup.ConflictPolicySkip), fmt.Sprintf("Resolution of the conflicts with existing resources, one of [%s %s]", backup.ConflictPolicySkip, backup.ConflictPolicyOverwrite))
+	f.BoolVar(&restoreCmd.dryRun, "dry-run", false, "Report the outcome of the restore without modifying any resource")
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+func (cmd *meshRestoreCmd) run() error {
+	fd, err := os.Open(cmd.file)
+	if err != nil {
+		return errors.Errorf("Error opening file %s: %s", cmd.file, err)
+	}
+	defer fd.Close() //nolint: errcheck, gosec
+
+	b, err := backup.Load(fd)
+	if err != nil {
+		return err
+	}
+
+	results, err := backup.Restore(cmd.dynamicClient, b, backup.RestoreOptions{
+		OSMNamespace:   settings.Namespace(),
+		ConflictPolicy: backup.ConflictPolicy(cmd.conflictPolicy),
+		DryRun:         cmd.dryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	w := newTabWriter(cmd.out)
+	fmt.Fprintln(w, "KIND\tNAMESPACE\tNAME\tACTION\tMESSAGE")
+	failed := 0
+	for _, result := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", result.Kind, result.Namespace, result.Name, result.Action, result.Message)
+		if result.Action == backup.RestoreActionFailed {
+			failed++
+		}
+	}
+	_ = w.Flush()
+
+	if failed > 0 {
+		return errors.Errorf("Failed to restore %d of %d resources of backup %s", failed, len(results), cmd.file)
+	}
+	if cmd.dryRun {
+		fmt.Fprintf(cmd.out, "Dry run: no resource of backup %s was modified\n", cmd.file)
+		return nil
+	}
+	fmt.Fprintf(cmd.out, "Backup %s of mesh [%s] restored\n", cmd.file, b.Metadata.MeshName)
+	return nil
+}
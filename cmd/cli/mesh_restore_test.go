@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/openservicemesh/osm/pkg/backup"
+)
+
+func TestMeshRestore(t *testing.T) {
+	egressResource := backup.Resource{Group: "policy.openservicemesh.io", Version: "v1alpha1", Resource: "egresses"}
+	b := &backup.Backup{
+		Metadata: backup.Metadata{FormatVersion: backup.FormatVersion, OSMNamespace: "osm-system", MeshName: "osm"},
+		Resources: []backup.ResourceObjects{{
+			Resource: egressResource,
+			Objects: []unstructured.Unstructured{{Object: map[string]interface{}{
+				"apiVersion": "policy.openservicemesh.io/v1alpha1",
+				"kind":       "Egress",
+				"metadata":   map[string]interface{}{"name": "egress", "namespace": "ns-1"},
+				"spec":       map[string]interface{}{"hosts": []interface{}{"a.com"}},
+			}}},
+		}},
+	}
+
+	dir, err := ioutil.TempDir("", "osm-backup")
+	tassert.Nil(t, err)
+	defer os.RemoveAll(dir) //nolint: errcheck
+	file := filepath.Join(dir, "backup.tar.gz")
+	var archive bytes.Buffer
+	tassert.Nil(t, b.Write(&archive))
+	tassert.Nil(t, ioutil.WriteFile(file, archive.Bytes(), 0600))
+
+	testCases := []struct {
+		name           string
+		conflictPolicy string
+		dryRun         bool
+		expErr         bool
+		expOutput      []string
+	}{
+		{
+			name:           "restores the backup",
+			conflictPolicy: string(backup.ConflictPolicySkip),
+			expOutput:      []string{"Egress   ns-1        egress   created", "Backup " + file + " of mesh [osm] restored"},
+		},
+		{
+			name:           "reports the outcome of a dry run",
+			conflictPolicy: string(backup.ConflictPolicyOverwrite),
+			dryRun:         true,
+			expOutput:      []string{"Egress   ns-1        egress   created", "Dry run: no resource of backup " + file + " was modified"},
+		},
+		{
+			name:           "rejects an unsupported conflict policy",
+			conflictPolicy: "merge",
+			expErr:         true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			out := new(bytes.Buffer)
+			cmd := &meshRestoreCmd{
+				out: out,
+				dynamicClient: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+					egressResource.GroupVersionResource(): "EgressList",
+				}),
+				file:           file,
+				conflictPolicy: tc.conflictPolicy,
+				dryRun:         tc.dryRun,
+			}
+
+			err := cmd.run()
+			assert.Equal(tc.expErr, err != nil)
+			for _, line := range tc.expOutput {
+				assert.Contains(out.String(), line)
+			}
+		})
+	}
+}
@@ -44,6 +44,10 @@ If any CustomResourceDefinitions (CRDs) are different between the installed
 chart and the upgraded chart, the CRDs will be updated to include the latest versions.
 Any corresponding custom resources that wish to reference the newer CRD version can
 be updated post upgrade.
+
+Once the CRDs are updated, osm-bootstrap rewrites the custom resources stored in
+previous CRD versions in the new storage version, and removes the previous versions
+from the stored versions of the CRDs.
 `
 
 const meshUpgradeExample = `
@@ -25,6 +25,7 @@ func newMetricsCmd(out io.Writer) *cobra.Command {
 	}
 	cmd.AddCommand(newMetricsEnable(out))
 	cmd.AddCommand(newMetricsDisable(out))
+	cmd.AddCommand(newMetricsSummary(out))
 
 	return cmd
 }
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openservicemesh/osm/pkg/cli"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/meshmetrics"
+)
+
+const metricsSummaryDescription = `
+This command will summarize the metrics of the inbound traffic of a service:
+its request success rate, its 50th and 99th percentile latencies and its
+connection counts. The osm-controller scrapes the proxy sidecars of the ready
+pods of the service and aggregates their metrics, which are cumulative since
+the proxy sidecars started.
+
+Only the pods enabled for metrics are scraped, metrics are enabled with
+'osm metrics enable'.
+`
+
+const metricsSummaryExample = `
+# Summarize the metrics of the 'bookstore' service in the 'bookstore' namespace
+osm metrics summary bookstore -n bookstore
+`
+
+type metricsSummaryCmd struct {
+	out       io.Writer
+	config    *rest.Config
+	clientSet kubernetes.Interface
+	namespace string
+	service   string
+	localPort uint16
+}
+
+func newMetricsSummary(out io.Writer) *cobra.Command {
+	summaryCmd := &metricsSummaryCmd{
+		out: out,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "summary SERVICE",
+		Short: "summarize the metrics of a service",
+		Long:  metricsSummaryDescription,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			summaryCmd.service = args[0]
+
+			config, err := settings.RESTClientGetter().ToRESTConfig()
+			if err != nil {
+				return errors.Errorf("Error fetching kubeconfig: %s", err)
+			}
+			summaryCmd.config = config
+
+			clientset, err := kubernetes.NewForConfig(config)
+			if err != nil {
+				return errors.Errorf("Could not access Kubernetes cluster, check kubeconfig: %s", err)
+			}
+			summaryCmd.clientSet = clientset
+			return summaryCmd.run()
+		},
+		Example: metricsSummaryExample,
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&summaryCmd.namespace, "namespace", "n", metav1.NamespaceDefault, "Namespace of the service")
+	f.Uint16VarP(&summaryCmd.localPort, "local-port", "p", constants.OSMHTTPServerPort, "Local port to use for port forwarding")
+
+	return cmd
+}
+
+func (cmd *metricsSummaryCmd) run() error {
+	summary, err := cli.GetMetricsSummary(cmd.clientSet, cmd.config, settings.Namespace(), cmd.namespace, cmd.service, cmd.localPort)
+	if err != nil {
+		return annotateErrorMessageWithOsmNamespace("Error summarizing metrics: %s", err)
+	}
+
+	w := newTabWriter(cmd.out)
+	fmt.Fprint(w, getPrettyPrintedMetricsSummary(summary))
+	_ = w.Flush()
+
+	return nil
+}
+
+// getPrettyPrintedMetricsSummary returns the given summary as tab separated rows with a header, followed by the
+// reasons some pods of the service could not be scraped
+func getPrettyPrintedMetricsSummary(summary *meshmetrics.ServiceSummary) string {
+	s := "SERVICE\tPODS\tREQUESTS\tSUCCESS RATE\tP50 LATENCY\tP99 LATENCY\tACTIVE CONNECTIONS\tTOTAL CONNECTIONS\n"
+	s += fmt.Sprintf("%s\t%d\t%d\t%.2f%%\t%s\t%s\t%d\t%d\n",
+		summary.Service,
+		summary.Pods,
+		summary.Requests,
+		summary.SuccessRate,
+		summary.LatencyP50,
+		summary.LatencyP99,
+		summary.ActiveConnections,
+		summary.TotalConnections,
+	)
+	if len(summary.Errors) > 0 {
+		s += fmt.Sprintf("\nPods not scraped:\n%s\n", strings.Join(summary.Errors, "\n"))
+	}
+	return s
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/meshmetrics"
+)
+
+func TestGetPrettyPrintedMetricsSummary(t *testing.T) {
+	testCases := []struct {
+		name     string
+		summary  *meshmetrics.ServiceSummary
+		expected string
+	}{
+		{
+			name: "all pods scraped",
+			summary: &meshmetrics.ServiceSummary{
+				Service:           "bookstore/bookstore",
+				Pods:              2,
+				Requests:          1000,
+				SuccessRate:       99.5,
+				LatencyP50:        12 * time.Millisecond,
+				LatencyP99:        250 * time.Millisecond,
+				ActiveConnections: 4,
+				TotalConnections:  40,
+			},
+			expected: "SERVICE\tPODS\tREQUESTS\tSUCCESS RATE\tP50 LATENCY\tP99 LATENCY\tACTIVE CONNECTIONS\tTOTAL CONNECTIONS\n" +
+				"bookstore/bookstore\t2\t1000\t99.50%\t12ms\t250ms\t4\t40\n",
+		},
+		{
+			name: "pods not scraped",
+			summary: &meshmetrics.ServiceSummary{
+				Service:     "bookstore/bookstore",
+				Errors:      []string{"Pod bookstore/bookstore-1: metrics are not enabled"},
+				SuccessRate: 100,
+			},
+			expected: "SERVICE\tPODS\tREQUESTS\tSUCCESS RATE\tP50 LATENCY\tP99 LATENCY\tACTIVE CONNECTIONS\tTOTAL CONNECTIONS\n" +
+				"bookstore/bookstore\t0\t0\t100.00%\t0s\t0s\t0\t0\n" +
+				"\nPods not scraped:\nPod bookstore/bookstore-1: metrics are not enabled\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tassert.Equal(t, tc.expected, getPrettyPrintedMetricsSummary(tc.summary))
+		})
+	}
+}
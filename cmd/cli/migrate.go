@@ -0,0 +1,26 @@
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+const migrateDescription = `
+This command consists of subcommands easing the migration of workloads from
+other service meshes to osm side by side, such as detecting the pods carrying
+the sidecar of another mesh and converting Istio resources into SMI resources.
+`
+
+func newMigrateCmd(stdin io.Reader, stdout io.Writer, stderr io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "migrate workloads from other service meshes",
+		Long:  migrateDescription,
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(newMigrateScanCmd(stdout))
+	cmd.AddCommand(newMigrateIstioCmd(stdin, stdout, stderr))
+
+	return cmd
+}
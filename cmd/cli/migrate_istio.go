@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/openservicemesh/osm/pkg/migration"
+)
+
+const migrateIstioDescription = `
+This command will convert the basics of Istio VirtualServices and
+DestinationRules into the SMI resources osm implements, offline.
+
+Each HTTP route of a VirtualService for a Kubernetes service becomes a
+TrafficSplit of the service with the weights of the destinations of the route.
+The URI, method and header matches of the route become an HTTPRouteGroup the
+TrafficSplit matches. The destinations of a subset of a DestinationRule become
+a backend Service named '<service>-<subset>', selecting the pods with the labels
+of the subset, which must be created.
+
+The converted resources are written to stdout. The parts of the Istio resources
+osm has no equivalent of, such as retries, timeouts, fault injection and the
+traffic policies of DestinationRules, and the Services to create, are reported
+as warnings on stderr.
+`
+
+const migrateIstioExample = `
+# Convert the Istio resources of the bookinfo namespace
+kubectl get virtualservices,destinationrules -n bookinfo -o yaml | osm migrate istio -f - | kubectl apply -f -
+
+# Convert the Istio resources of a file
+osm migrate istio -f reviews.yaml
+`
+
+type migrateIstioCmd struct {
+	stdin    io.Reader
+	out      io.Writer
+	errOut   io.Writer
+	filename string
+}
+
+func newMigrateIstioCmd(stdin io.Reader, stdout io.Writer, stderr io.Writer) *cobra.Command {
+	istioCmd := &migrateIstioCmd{
+		stdin:  stdin,
+		out:    stdout,
+		errOut: stderr,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "istio",
+		Short: "convert Istio VirtualServices and DestinationRules into SMI resources",
+		Long:  migrateIstioDescription,
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return istioCmd.run()
+		},
+		Example: migrateIstioExample,
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&istioCmd.filename, "filename", "f", "", "File holding the Istio resources to convert, or - for stdin")
+	_ = cmd.MarkFlagRequired("filename")
+
+	return cmd
+}
+
+func (cmd *migrateIstioCmd) run() error {
+	in := cmd.stdin
+	if cmd.filename != "-" {
+		fd, err := os.Open(cmd.filename)
+		if err != nil {
+			return errors.Errorf("Error opening file %s: %s", cmd.filename, err)
+		}
+		defer fd.Close() //nolint: errcheck, gosec
+		in = fd
+	}
+
+	conversion, err := migration.ConvertIstioResources(in)
+	if err != nil {
+		return errors.Errorf("Error converting the Istio resources of %s: %s", cmd.filename, err)
+	}
+
+	for _, warning := range conversion.Warnings {
+		fmt.Fprintf(cmd.errOut, "WARNING: %s\n", warning)
+	}
+
+	manifests, err := conversion.Manifests()
+	if err != nil {
+		return err
+	}
+	_, err = cmd.out.Write(manifests)
+	return err
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestMigrateIstio(t *testing.T) {
+	assert := tassert.New(t)
+
+	resources := `
+apiVersion: networking.istio.io/v1beta1
+kind: VirtualService
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  hosts:
+  - reviews
+  http:
+  - route:
+    - destination:
+        host: reviews
+        subset: v1
+      weight: 90
+    - destination:
+        host: reviews
+        subset: v2
+      weight: 10
+    retries:
+      attempts: 3
+`
+
+	out := new(bytes.Buffer)
+	errOut := new(bytes.Buffer)
+	cmd := &migrateIstioCmd{
+		stdin:    strings.NewReader(resources),
+		out:      out,
+		errOut:   errOut,
+		filename: "-",
+	}
+
+	assert.NoError(cmd.run())
+	assert.Equal("apiVersion: split.smi-spec.io/v1alpha4\n"+
+		"kind: TrafficSplit\n"+
+		"metadata:\n"+
+		"  creationTimestamp: null\n"+
+		"  name: reviews-route-0\n"+
+		"  namespace: bookinfo\n"+
+		"spec:\n"+
+		"  backends:\n"+
+		"  - service: reviews-v1\n"+
+		"    weight: 90\n"+
+		"  - service: reviews-v2\n"+
+		"    weight: 10\n"+
+		"  service: reviews\n", out.String())
+	assert.Equal("WARNING: VirtualService bookinfo/reviews: retries of route route-0 is not converted\n"+
+		"WARNING: Create Service bookinfo/reviews-v1 with the ports of Service bookinfo/reviews, selecting the pods of subset v1 whose DestinationRule wasn't provided\n"+
+		"WARNING: Create Service bookinfo/reviews-v2 with the ports of Service bookinfo/reviews, selecting the pods of subset v2 whose DestinationRule wasn't provided\n",
+		errOut.String())
+}
+
+func TestMigrateIstioMissingFile(t *testing.T) {
+	assert := tassert.New(t)
+
+	cmd := &migrateIstioCmd{
+		out:      new(bytes.Buffer),
+		errOut:   new(bytes.Buffer),
+		filename: "does-not-exist.yaml",
+	}
+	assert.Error(cmd.run())
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/migration"
+)
+
+const migrateScanDescription = `
+This command will list the pods of the namespaces monitored by osm which carry
+the sidecar of another service mesh, Istio or Linkerd, or which the other mesh
+is configured to inject its sidecar into.
+
+osm doesn't inject its sidecar into these pods, so that they keep being part of
+the other mesh until they are migrated: remove the injection of the other mesh
+for the pod or its namespace, and restart the pod to inject the osm sidecar.
+`
+
+const migrateScanExample = `
+# List the pods carrying the sidecar of another mesh in the namespaces monitored by the mesh osm
+osm migrate scan --mesh-name osm
+`
+
+type migrateScanCmd struct {
+	out       io.Writer
+	meshName  string
+	clientSet kubernetes.Interface
+}
+
+func newMigrateScanCmd(out io.Writer) *cobra.Command {
+	scanCmd := &migrateScanCmd{
+		out: out,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "scan",
+		Short: "list the pods carrying the sidecar of another mesh in monitored namespaces",
+		Long:  migrateScanDescription,
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			config, err := settings.RESTClientGetter().ToRESTConfig()
+			if err != nil {
+				return errors.Errorf("Error fetching kubeconfig: %s", err)
+			}
+
+			clientset, err := kubernetes.NewForConfig(config)
+			if err != nil {
+				return errors.Errorf("Could not access Kubernetes cluster, check kubeconfig: %s", err)
+			}
+			scanCmd.clientSet = clientset
+			return scanCmd.run()
+		},
+		Example: migrateScanExample,
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&scanCmd.meshName, "mesh-name", "", "Name of the service mesh whose monitored namespaces are scanned, all meshes if unset")
+
+	return cmd
+}
+
+func (cmd *migrateScanCmd) run() error {
+	namespaces, err := selectNamespacesMonitoredByMesh(cmd.meshName, cmd.clientSet)
+	if err != nil {
+		return errors.Errorf("Could not list namespaces related to osm [%s]: %v", cmd.meshName, err)
+	}
+
+	w := newTabWriter(cmd.out)
+	found := false
+	for i := range namespaces.Items {
+		ns := &namespaces.Items[i]
+		pods, err := cmd.clientSet.CoreV1().Pods(ns.Name).List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			return errors.Errorf("Could not list pods of namespace %s: %v", ns.Name, err)
+		}
+
+		for j := range pods.Items {
+			pod := &pods.Items[j]
+			sidecar := migration.GetOtherMeshSidecar(pod, ns)
+			if sidecar == nil {
+				continue
+			}
+			if !found {
+				fmt.Fprintln(w, "NAMESPACE\tPOD\tMESH\tDETECTED FROM")
+				found = true
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", ns.Name, pod.Name, sidecar.Mesh, sidecar.Evidence)
+		}
+	}
+	_ = w.Flush()
+
+	if !found {
+		fmt.Fprintln(cmd.out, "No pods carrying the sidecar of another mesh in the monitored namespaces")
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+func TestMigrateScan(t *testing.T) {
+	monitored := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "bookinfo",
+			Labels: map[string]string{constants.OSMKubeResourceMonitorAnnotation: "osm"},
+		},
+	}
+	unmonitored := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "legacy",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		objects  []runtime.Object
+		expected string
+	}{
+		{
+			name:     "no pods carrying the sidecar of another mesh",
+			objects:  []runtime.Object{monitored, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"}}},
+			expected: "No pods carrying the sidecar of another mesh in the monitored namespaces\n",
+		},
+		{
+			name: "pods carrying the sidecar of another mesh",
+			objects: []runtime.Object{
+				monitored,
+				unmonitored,
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"},
+					Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "reviews"}, {Name: "istio-proxy"}}},
+				},
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "ratings", Namespace: "bookinfo", Annotations: map[string]string{"linkerd.io/inject": "enabled"}},
+				},
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "details", Namespace: "bookinfo"},
+				},
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "legacy", Namespace: "legacy"},
+					Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "istio-proxy"}}},
+				},
+			},
+			expected: "NAMESPACE\tPOD\tMESH\tDETECTED FROM\n" +
+				"bookinfo\tratings\tlinkerd\tpod annotation linkerd.io/inject: enabled\n" +
+				"bookinfo\treviews\tistio\tcontainer istio-proxy\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			out := new(bytes.Buffer)
+			cmd := &migrateScanCmd{
+				out:       out,
+				meshName:  "osm",
+				clientSet: fake.NewSimpleClientset(test.objects...),
+			}
+
+			assert.NoError(cmd.run())
+
+			expected := new(bytes.Buffer)
+			expTw := newTabWriter(expected)
+			_, err := expTw.Write([]byte(test.expected))
+			assert.NoError(err)
+			assert.NoError(expTw.Flush())
+
+			assert.Equal(expected.String(), out.String())
+		})
+	}
+}
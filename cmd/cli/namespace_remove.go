@@ -7,23 +7,38 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/constants"
+	policyClientset "github.com/openservicemesh/osm/pkg/gen/client/policy/clientset/versioned"
 )
 
 const namespaceRemoveDescription = `
 This command will remove a namespace from the mesh. All
 services in this namespace will be removed from the mesh.
+
+The pods of the namespace keep running their sidecars until they
+are restarted. With --uninstall-sidecars, the namespace is removed
+from the mesh by the OSM controller once its sidecars are
+uninstalled: sidecar injection is disabled, the Deployments,
+StatefulSets and DaemonSets running pods with sidecars are
+restarted, and the certificates of the namespace are released.
+The progress is tracked in the status of the NamespaceOffboarding
+resource created in the namespace.
 `
 
 type namespaceRemoveCmd struct {
-	out       io.Writer
-	namespace string
-	meshName  string
-	clientSet kubernetes.Interface
+	out                io.Writer
+	namespace          string
+	meshName           string
+	uninstallSidecars  bool
+	offboardingTimeout string
+	clientSet          kubernetes.Interface
+	policyClientSet    policyClientset.Interface
 }
 
 func newNamespaceRemove(out io.Writer) *cobra.Command {
@@ -48,6 +63,12 @@ func newNamespaceRemove(out io.Writer) *cobra.Command {
 				return errors.Errorf("Could not access Kubernetes cluster, check kubeconfig: %s", err)
 			}
 			namespaceRemove.clientSet = clientset
+
+			policyClient, err := policyClientset.NewForConfig(config)
+			if err != nil {
+				return errors.Errorf("Could not access Kubernetes cluster, check kubeconfig: %s", err)
+			}
+			namespaceRemove.policyClientSet = policyClient
 			return namespaceRemove.run()
 		},
 	}
@@ -55,6 +76,8 @@ func newNamespaceRemove(out io.Writer) *cobra.Command {
 	//add mesh name flag
 	f := cmd.Flags()
 	f.StringVar(&namespaceRemove.meshName, "mesh-name", "osm", "Name of the service mesh")
+	f.BoolVar(&namespaceRemove.uninstallSidecars, "uninstall-sidecars", false, "Remove the namespace from the mesh once the sidecars of its pods are uninstalled by the OSM controller")
+	f.StringVar(&namespaceRemove.offboardingTimeout, "uninstall-sidecars-timeout", "", "Duration after which uninstalling the sidecars fails if pods still run sidecars, defaults to 10m")
 
 	return cmd
 }
@@ -72,6 +95,10 @@ func (r *namespaceRemoveCmd) run() error {
 	val, exists := namespace.ObjectMeta.Labels[constants.OSMKubeResourceMonitorAnnotation]
 	if exists {
 		if val == r.meshName {
+			if r.uninstallSidecars {
+				return r.offboard(ctx)
+			}
+
 			// Setting null for a key in a map removes only that specific key, which is the desired behavior.
 			// Even if the key does not exist, there will be no side effects with setting the key to null, which
 			// will result in the same behavior as if the key were present - the key being removed.
@@ -104,3 +131,30 @@ func (r *namespaceRemoveCmd) run() error {
 
 	return nil
 }
+
+// offboard creates the NamespaceOffboarding resource of the namespace, so that the OSM controller removes the namespace
+// from the mesh once the sidecars of its pods are uninstalled
+func (r *namespaceRemoveCmd) offboard(ctx context.Context) error {
+	offboarding := &policyv1alpha1.NamespaceOffboarding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.meshName,
+			Namespace: r.namespace,
+		},
+		Spec: policyv1alpha1.NamespaceOffboardingSpec{
+			Timeout: r.offboardingTimeout,
+		},
+	}
+
+	_, err := r.policyClientSet.PolicyV1alpha1().NamespaceOffboardings(r.namespace).Create(ctx, offboarding, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		fmt.Fprintf(r.out, "Namespace [%s] is already being removed from mesh [%s]\n", r.namespace, r.meshName)
+		return nil
+	}
+	if err != nil {
+		return errors.Errorf("Could not remove namespace [%s] from mesh [%s]: %v", r.namespace, r.meshName, err)
+	}
+
+	fmt.Fprintf(r.out, "Namespace [%s] will be removed from mesh [%s] once its sidecars are uninstalled, run 'kubectl get namespaceoffboarding %s -n %s' to track the progress\n",
+		r.namespace, r.meshName, r.meshName, r.namespace)
+	return nil
+}
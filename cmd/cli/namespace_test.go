@@ -14,6 +14,7 @@ import (
 	"k8s.io/client-go/kubernetes/fake"
 
 	"github.com/openservicemesh/osm/pkg/constants"
+	fakePolicyClient "github.com/openservicemesh/osm/pkg/gen/client/policy/clientset/versioned/fake"
 )
 
 var (
@@ -437,6 +438,53 @@ var _ = Describe("Running the namespace remove command", func() {
 		})
 	})
 
+	Describe("with pre-existing namespace and uninstalling sidecars", func() {
+		var (
+			out                 *bytes.Buffer
+			fakeClientSet       kubernetes.Interface
+			fakePolicyClientSet *fakePolicyClient.Clientset
+			err                 error
+		)
+
+		BeforeEach(func() {
+			out = new(bytes.Buffer)
+			fakeClientSet = fake.NewSimpleClientset()
+			fakePolicyClientSet = fakePolicyClient.NewSimpleClientset()
+
+			nsSpec := createNamespaceSpec(testNamespace, testMeshName, true)
+			_, err = fakeClientSet.CoreV1().Namespaces().Create(context.TODO(), nsSpec, metav1.CreateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			namespaceRemoveCmd := &namespaceRemoveCmd{
+				out:                out,
+				meshName:           testMeshName,
+				namespace:          testNamespace,
+				uninstallSidecars:  true,
+				offboardingTimeout: "5m",
+				clientSet:          fakeClientSet,
+				policyClientSet:    fakePolicyClientSet,
+			}
+
+			err = namespaceRemoveCmd.run()
+		})
+
+		It("should not error", func() {
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should create the NamespaceOffboarding of the namespace", func() {
+			offboarding, err := fakePolicyClientSet.PolicyV1alpha1().NamespaceOffboardings(testNamespace).Get(context.TODO(), testMeshName, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(offboarding.Spec.Timeout).To(Equal("5m"))
+		})
+
+		It("should leave the label on the namespace for the controller to remove", func() {
+			ns, err := fakeClientSet.CoreV1().Namespaces().Get(context.TODO(), testNamespace, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ns.Labels).Should(HaveKeyWithValue(constants.OSMKubeResourceMonitorAnnotation, testMeshName))
+		})
+	})
+
 	Describe("with pre-existing namespace and incorrect label", func() {
 		var (
 			out           *bytes.Buffer
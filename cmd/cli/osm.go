@@ -45,8 +45,10 @@ func newRootCmd(config *action.Configuration, stdin io.Reader, stdout io.Writer,
 		newVersionCmd(stdout),
 		newProxyCmd(config, stdout),
 		newTrafficPolicyCmd(stdout),
+		newVerifyCmd(stdout),
 		newUninstallCmd(config, stdin, stdout),
 		newSupportCmd(config, stdout, stderr),
+		newMigrateCmd(stdin, stdout, stderr),
 	)
 
 	_ = flags.Parse(args)
@@ -20,6 +20,7 @@ func newProxyCmd(config *action.Configuration, out io.Writer) *cobra.Command {
 		Args:  cobra.NoArgs,
 	}
 	cmd.AddCommand(newProxyGetCmd(config, out))
+	cmd.AddCommand(newProxyListCmd(config, out))
 
 	return cmd
 }
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/action"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openservicemesh/osm/pkg/cli"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy/registry"
+)
+
+const proxyListDescription = `
+This command will list the Envoy proxies connected to the osm-controller of the
+mesh, along with their pod, service identity, Envoy version, the versions of the
+xDS configuration last acknowledged by each proxy and the age of its connection.
+`
+
+const proxyListExample = `
+# List all the proxies connected to the osm-controller in the 'osm-system' namespace
+osm proxy list --osm-namespace osm-system
+
+# List the proxies of the pods in the 'bookbuyer' namespace
+osm proxy list -n bookbuyer
+`
+
+type proxyListCmd struct {
+	out       io.Writer
+	config    *rest.Config
+	clientSet kubernetes.Interface
+	namespace string
+	localPort uint16
+}
+
+func newProxyListCmd(config *action.Configuration, out io.Writer) *cobra.Command {
+	listCmd := &proxyListCmd{
+		out: out,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "list proxies connected to the control plane",
+		Long:  proxyListDescription,
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			conf, err := config.RESTClientGetter.ToRESTConfig()
+			if err != nil {
+				return errors.Errorf("Error fetching kubeconfig: %s", err)
+			}
+			listCmd.config = conf
+
+			clientset, err := kubernetes.NewForConfig(conf)
+			if err != nil {
+				return errors.Errorf("Could not access Kubernetes cluster, check kubeconfig: %s", err)
+			}
+			listCmd.clientSet = clientset
+			return listCmd.run()
+		},
+		Example: proxyListExample,
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&listCmd.namespace, "namespace", "n", "", "Namespace of the pods whose proxies to list, all namespaces if unset")
+	f.Uint16VarP(&listCmd.localPort, "local-port", "p", constants.OSMHTTPServerPort, "Local port to use for port forwarding")
+
+	return cmd
+}
+
+func (cmd *proxyListCmd) run() error {
+	inventory, err := cli.GetProxyInventory(cmd.clientSet, cmd.config, settings.Namespace(), cmd.namespace, cmd.localPort)
+	if err != nil {
+		return annotateErrorMessageWithOsmNamespace("Error listing proxies: %s", err)
+	}
+
+	if len(inventory) == 0 {
+		fmt.Fprintf(cmd.out, "No proxies connected to the control plane\n")
+		return nil
+	}
+
+	w := newTabWriter(cmd.out)
+	fmt.Fprint(w, getPrettyPrintedProxyInventory(inventory))
+	_ = w.Flush()
+
+	return nil
+}
+
+// getPrettyPrintedProxyInventory returns the given proxy inventory as tab separated rows with a header
+func getPrettyPrintedProxyInventory(inventory []registry.ProxyInfo) string {
+	s := "NAMESPACE\tPOD\tKIND\tSERVICE IDENTITY\tENVOY VERSION\tLAST ACKED VERSIONS\tAGE\n"
+	for _, proxy := range inventory {
+		s += fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			valueOrUnknown(proxy.PodNamespace),
+			valueOrUnknown(proxy.PodName),
+			proxy.Kind,
+			proxy.ServiceIdentity,
+			valueOrUnknown(proxy.EnvoyVersion),
+			getPrettyPrintedVersions(proxy.LastAppliedVersions),
+			proxy.ConnectionAge,
+		)
+	}
+	return s
+}
+
+// getPrettyPrintedVersions returns the given versions keyed by xDS type as a comma separated list sorted by type
+func getPrettyPrintedVersions(versions map[string]uint64) string {
+	if len(versions) == 0 {
+		return "-"
+	}
+
+	var typeVersions []string
+	for typeURI, version := range versions {
+		typeVersions = append(typeVersions, fmt.Sprintf("%s:%d", typeURI, version))
+	}
+	sort.Strings(typeVersions)
+	return strings.Join(typeVersions, ",")
+}
+
+func valueOrUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/registry"
+)
+
+func TestGetPrettyPrintedProxyInventory(t *testing.T) {
+	assert := tassert.New(t)
+
+	inventory := []registry.ProxyInfo{
+		{
+			Kind:                envoy.KindSidecar,
+			ServiceIdentity:     "bookbuyer.bookbuyer.cluster.local",
+			PodName:             "bookbuyer-5ccf77f46d-rc5mg",
+			PodNamespace:        "bookbuyer",
+			EnvoyVersion:        "1.18.3",
+			LastAppliedVersions: map[string]uint64{"LDS": 4, "CDS": 3},
+			ConnectionAge:       "5m3s",
+		},
+		{
+			Kind:            envoy.KindGateway,
+			ServiceIdentity: "osm-multicluster-gateway.osm-system.cluster.local",
+			ConnectionAge:   "2s",
+		},
+	}
+
+	expected := "NAMESPACE\tPOD\tKIND\tSERVICE IDENTITY\tENVOY VERSION\tLAST ACKED VERSIONS\tAGE\n" +
+		"bookbuyer\tbookbuyer-5ccf77f46d-rc5mg\tsidecar\tbookbuyer.bookbuyer.cluster.local\t1.18.3\tCDS:3,LDS:4\t5m3s\n" +
+		"unknown\tunknown\tgateway\tosm-multicluster-gateway.osm-system.cluster.local\tunknown\t-\t2s\n"
+
+	assert.Equal(expected, getPrettyPrintedProxyInventory(inventory))
+}
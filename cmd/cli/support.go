@@ -20,7 +20,11 @@ func newSupportCmd(config *action.Configuration, stdout io.Writer, stderr io.Wri
 		Args:  cobra.NoArgs,
 	}
 	cmd.AddCommand(newSupportErrInfoCmd(stdout))
+	cmd.AddCommand(newSupportErrorsCmd(stdout))
 	cmd.AddCommand(newSupportBugReportCmd(config, stdout, stderr))
+	cmd.AddCommand(newSupportBundleCmd(stdout))
+	cmd.AddCommand(newSupportAnalyzeCmd(stdout))
+	cmd.AddCommand(newSupportReplayCmd(stdout))
 
 	return cmd
 }
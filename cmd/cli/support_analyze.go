@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/openservicemesh/osm/pkg/supportbundle"
+)
+
+const supportAnalyzeDescription = `
+This command will analyze a support bundle exported with 'osm support bundle',
+offline. It replays the state captured in the bundle through the mesh catalog,
+without access to the cluster, and reports:
+- the services each service identity is allowed to reach, and the service
+  identities allowed to reach it
+- for each proxy, the clusters, endpoints, listeners and routes which differ
+  between the configuration captured in the bundle and the replayed one
+
+A difference indicates the view of the mesh of the osm-controller diverged from
+the state it captured, or the configuration depends on state the bundle does
+not hold, such as Ingress resources.
+`
+
+const supportAnalyzeExample = `
+# Analyze the support bundle osm-support-bundle.tar.gz
+osm support analyze osm-support-bundle.tar.gz
+`
+
+type supportAnalyzeCmd struct {
+	out        io.Writer
+	bundleFile string
+}
+
+func newSupportAnalyzeCmd(out io.Writer) *cobra.Command {
+	analyzeCmd := &supportAnalyzeCmd{
+		out: out,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "analyze BUNDLE",
+		Short: "analyze a support bundle offline",
+		Long:  supportAnalyzeDescription,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			analyzeCmd.bundleFile = args[0]
+			return analyzeCmd.run()
+		},
+		Example: supportAnalyzeExample,
+	}
+
+	return cmd
+}
+
+func (cmd *supportAnalyzeCmd) run() error {
+	fd, err := os.Open(cmd.bundleFile)
+	if err != nil {
+		return errors.Errorf("Error opening file %s: %s", cmd.bundleFile, err)
+	}
+	defer fd.Close() //nolint: errcheck, gosec
+
+	bundle, err := supportbundle.Load(fd)
+	if err != nil {
+		return errors.Errorf("Error loading support bundle %s: %s", cmd.bundleFile, err)
+	}
+
+	analysis, err := supportbundle.Analyze(bundle)
+	if err != nil {
+		return errors.Errorf("Error analyzing support bundle %s: %s", cmd.bundleFile, err)
+	}
+
+	fmt.Fprintf(cmd.out, "Support bundle of mesh %s (%s), exported at %s\n\n", bundle.Metadata.MeshName, bundle.Metadata.Version, bundle.Metadata.CreatedAt)
+	w := newTabWriter(cmd.out)
+	fmt.Fprint(w, getPrettyPrintedAnalysis(analysis))
+	_ = w.Flush()
+
+	return nil
+}
+
+// getPrettyPrintedAnalysis returns the warnings of the given analysis, followed by the traffic allowed for each
+// service identity and the xDS differences of each proxy as tab separated rows with a header
+func getPrettyPrintedAnalysis(analysis *supportbundle.Analysis) string {
+	var s string
+	for _, warning := range analysis.Warnings {
+		s += fmt.Sprintf("WARNING: %s\n", warning)
+	}
+	if len(analysis.Warnings) > 0 {
+		s += "\n"
+	}
+
+	s += "SERVICE IDENTITY\tOUTBOUND SERVICES\tINBOUND IDENTITIES\n"
+	for _, identity := range analysis.Identities {
+		s += fmt.Sprintf("%s\t%s\t%s\n", identity.ServiceIdentity, joinOrNone(identity.OutboundServices), joinOrNone(identity.InboundIdentities))
+	}
+
+	s += "\nPROXY\tTYPE\tONLY IN BUNDLE\tONLY IN REPLAY\tCHANGED\n"
+	for _, proxy := range analysis.Proxies {
+		if len(proxy.Diffs) == 0 {
+			s += fmt.Sprintf("%s\t-\t-\t-\t-\n", proxy.CommonName)
+			continue
+		}
+		for _, diff := range proxy.Diffs {
+			s += fmt.Sprintf("%s\t%s\t%s\t%s\t%s\n", proxy.CommonName, diff.TypeURI, joinOrNone(diff.OnlyInBundle), joinOrNone(diff.OnlyInReplay), joinOrNone(diff.Changed))
+		}
+	}
+
+	if analysis.HasDrift() {
+		s += "\nThe replayed configuration of some proxies differs from the configuration captured in the bundle\n"
+	} else {
+		s += "\nThe replayed configuration of the proxies matches the configuration captured in the bundle\n"
+	}
+	return s
+}
+
+// joinOrNone returns the given values separated by commas, or '-' if there are none
+func joinOrNone(values []string) string {
+	if len(values) == 0 {
+		return "-"
+	}
+	return strings.Join(values, ",")
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/supportbundle"
+)
+
+func TestGetPrettyPrintedAnalysis(t *testing.T) {
+	assert := tassert.New(t)
+
+	analysis := &supportbundle.Analysis{
+		Warnings: []string{"Multicluster mode is enabled: endpoints of remote clusters are not replayed"},
+		Identities: []supportbundle.IdentityAnalysis{
+			{ServiceIdentity: "bookbuyer.bookbuyer.cluster.local", OutboundServices: []string{"bookstore/bookstore"}},
+			{ServiceIdentity: "bookstore.bookstore.cluster.local", InboundIdentities: []string{"bookbuyer.bookbuyer.cluster.local"}},
+		},
+		Proxies: []supportbundle.ProxyAnalysis{
+			{CommonName: "a.sidecar.bookbuyer.bookbuyer.cluster.local"},
+			{
+				CommonName: "b.sidecar.bookstore.bookstore.cluster.local",
+				Diffs: []supportbundle.XDSDiff{
+					{TypeURI: "CDS", OnlyInReplay: []string{"bookstore/bookstore-local"}},
+				},
+			},
+		},
+	}
+
+	expected := "WARNING: Multicluster mode is enabled: endpoints of remote clusters are not replayed\n" +
+		"\nSERVICE IDENTITY\tOUTBOUND SERVICES\tINBOUND IDENTITIES\n" +
+		"bookbuyer.bookbuyer.cluster.local\tbookstore/bookstore\t-\n" +
+		"bookstore.bookstore.cluster.local\t-\tbookbuyer.bookbuyer.cluster.local\n" +
+		"\nPROXY\tTYPE\tONLY IN BUNDLE\tONLY IN REPLAY\tCHANGED\n" +
+		"a.sidecar.bookbuyer.bookbuyer.cluster.local\t-\t-\t-\t-\n" +
+		"b.sidecar.bookstore.bookstore.cluster.local\tCDS\t-\tbookstore/bookstore-local\t-\n" +
+		"\nThe replayed configuration of some proxies differs from the configuration captured in the bundle\n"
+
+	assert.Equal(expected, getPrettyPrintedAnalysis(analysis))
+}
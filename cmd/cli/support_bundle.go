@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openservicemesh/osm/pkg/cli"
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+const supportBundleDescription = `
+This command will export a support bundle from the osm-controller: a tar.gz
+archive capturing the state of the control plane. The bundle holds the
+MeshConfig, the Kubernetes objects, SMI and policy resources of the monitored
+namespaces, the xDS configuration (clusters, endpoints, listeners and routes)
+of the connected proxies, the controller metrics and its recent logs.
+
+Secrets and certificates are never part of the bundle. The bundle can be
+analyzed offline with 'osm support analyze'.
+`
+
+const supportBundleExample = `
+# Export a support bundle to osm-support-bundle.tar.gz
+osm support bundle --out-file osm-support-bundle.tar.gz
+`
+
+type supportBundleCmd struct {
+	out       io.Writer
+	config    *rest.Config
+	clientSet kubernetes.Interface
+	outFile   string
+	localPort uint16
+}
+
+func newSupportBundleCmd(out io.Writer) *cobra.Command {
+	bundleCmd := &supportBundleCmd{
+		out: out,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "export a support bundle",
+		Long:  supportBundleDescription,
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			config, err := settings.RESTClientGetter().ToRESTConfig()
+			if err != nil {
+				return errors.Errorf("Error fetching kubeconfig: %s", err)
+			}
+			bundleCmd.config = config
+
+			clientset, err := kubernetes.NewForConfig(config)
+			if err != nil {
+				return errors.Errorf("Could not access Kubernetes cluster, check kubeconfig: %s", err)
+			}
+			bundleCmd.clientSet = clientset
+			return bundleCmd.run()
+		},
+		Example: supportBundleExample,
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&bundleCmd.outFile, "out-file", "o", "", "Output file of the support bundle, defaults to osm-support-bundle-<timestamp>.tar.gz")
+	f.Uint16VarP(&bundleCmd.localPort, "local-port", "p", constants.OSMHTTPServerPort, "Local port to use for port forwarding")
+
+	return cmd
+}
+
+func (cmd *supportBundleCmd) run() error {
+	outFile := cmd.outFile
+	if outFile == "" {
+		outFile = fmt.Sprintf("osm-support-bundle-%s.tar.gz", time.Now().UTC().Format("20060102-150405"))
+	}
+
+	fd, err := os.Create(outFile)
+	if err != nil {
+		return errors.Errorf("Error opening file %s: %s", outFile, err)
+	}
+	defer fd.Close() //nolint: errcheck, gosec
+
+	if err := cli.WriteSupportBundle(cmd.clientSet, cmd.config, settings.Namespace(), cmd.localPort, fd); err != nil {
+		return annotateErrorMessageWithOsmNamespace("Error exporting support bundle: %s", err)
+	}
+
+	fmt.Fprintf(cmd.out, "Support bundle exported to %s\n", outFile)
+	return nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openservicemesh/osm/pkg/cli"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/errcode"
+)
+
+const supportErrorsDescription = `
+This command lists the errors recently encountered by osm-controller, counted
+by error code, proxy and resource, most recently seen first, along with a link
+to the documentation of each error code and its remediation.
+
+Proxies are identified by the common name of their certificate, as listed by
+'osm proxy list'. Resources are identified by their kind, namespace and name,
+ex. Pod/bookstore/bookstore-v1. Errors are forgotten an hour after they were
+last seen.
+
+The errors are also counted by error code in the 'osm_error_err_code_count'
+metric of osm-controller, which alerts can be based on.
+`
+
+const supportErrorsExample = `
+# List the errors recently encountered by osm-controller
+osm support errors
+
+# List the recent occurrences of the error code E5000
+osm support errors --code E5000
+`
+
+type supportErrorsCmd struct {
+	out       io.Writer
+	config    *rest.Config
+	clientSet kubernetes.Interface
+	code      string
+	proxy     string
+	resource  string
+	localPort uint16
+}
+
+func newSupportErrorsCmd(out io.Writer) *cobra.Command {
+	errorsCmd := &supportErrorsCmd{
+		out: out,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "errors",
+		Short: "list errors recently encountered by osm-controller",
+		Long:  supportErrorsDescription,
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if errorsCmd.code != "" {
+				if _, err := errcode.FromStr(errorsCmd.code); err != nil {
+					return err
+				}
+			}
+
+			config, err := settings.RESTClientGetter().ToRESTConfig()
+			if err != nil {
+				return errors.Errorf("Error fetching kubeconfig: %s", err)
+			}
+			errorsCmd.config = config
+
+			clientset, err := kubernetes.NewForConfig(config)
+			if err != nil {
+				return errors.Errorf("Could not access Kubernetes cluster, check kubeconfig: %s", err)
+			}
+			errorsCmd.clientSet = clientset
+			return errorsCmd.run()
+		},
+		Example: supportErrorsExample,
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&errorsCmd.code, "code", "", "Error code of the errors to list, ex. E5000, all error codes if unset")
+	f.StringVar(&errorsCmd.proxy, "proxy", "", "Certificate common name of the proxy whose errors to list, all proxies if unset")
+	f.StringVar(&errorsCmd.resource, "resource", "", "Resource whose errors to list, ex. Pod/bookstore/bookstore-v1, all resources if unset")
+	f.Uint16VarP(&errorsCmd.localPort, "local-port", "p", constants.OSMHTTPServerPort, "Local port to use for port forwarding")
+
+	return cmd
+}
+
+func (cmd *supportErrorsCmd) run() error {
+	counts, err := cli.GetErrorCounts(cmd.clientSet, cmd.config, settings.Namespace(), cmd.code, cmd.proxy, cmd.resource, cmd.localPort)
+	if err != nil {
+		return annotateErrorMessageWithOsmNamespace("Error listing errors: %s", err)
+	}
+
+	if len(counts) == 0 {
+		fmt.Fprintf(cmd.out, "No errors recently encountered by osm-controller\n")
+		return nil
+	}
+
+	w := newTabWriter(cmd.out)
+	fmt.Fprint(w, getPrettyPrintedErrorCounts(counts))
+	_ = w.Flush()
+
+	return nil
+}
+
+// getPrettyPrintedErrorCounts returns the given error counts as tab separated rows with a header
+func getPrettyPrintedErrorCounts(counts []errcode.ErrorCount) string {
+	s := "CODE\tCOUNT\tLAST SEEN\tPROXY\tRESOURCE\tREMEDIATION\n"
+	for _, count := range counts {
+		s += fmt.Sprintf("%s\t%d\t%s\t%s\t%s\t%s\n",
+			count.Code,
+			count.Count,
+			count.LastSeen.Format(time.RFC3339),
+			valueOrNone(count.Proxy),
+			valueOrNone(count.Resource),
+			count.Remediation,
+		)
+	}
+	return s
+}
+
+// valueOrNone returns the given value, or '-' if it is empty
+func valueOrNone(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/errcode"
+)
+
+func TestGetPrettyPrintedErrorCounts(t *testing.T) {
+	assert := tassert.New(t)
+
+	counts := []errcode.ErrorCount{
+		{
+			Code:        "E5000",
+			Remediation: "https://docs.openservicemesh.io/docs/guides/troubleshooting/control_plane_error_codes/#e5000",
+			Proxy:       "a5b2c3d4.sidecar.bookstore.bookstore.cluster.local",
+			Count:       3,
+			LastSeen:    time.Date(2021, time.June, 1, 12, 0, 5, 0, time.UTC),
+		},
+		{
+			Code:        "E4038",
+			Remediation: "https://docs.openservicemesh.io/docs/guides/troubleshooting/control_plane_error_codes/#e4038",
+			Resource:    "Pod/bookstore/bookstore-v1",
+			Count:       1,
+			LastSeen:    time.Date(2021, time.June, 1, 12, 0, 0, 0, time.UTC),
+		},
+	}
+
+	expected := "CODE\tCOUNT\tLAST SEEN\tPROXY\tRESOURCE\tREMEDIATION\n" +
+		"E5000\t3\t2021-06-01T12:00:05Z\ta5b2c3d4.sidecar.bookstore.bookstore.cluster.local\t-\thttps://docs.openservicemesh.io/docs/guides/troubleshooting/control_plane_error_codes/#e5000\n" +
+		"E4038\t1\t2021-06-01T12:00:00Z\t-\tPod/bookstore/bookstore-v1\thttps://docs.openservicemesh.io/docs/guides/troubleshooting/control_plane_error_codes/#e4038\n"
+
+	assert.Equal(expected, getPrettyPrintedErrorCounts(counts))
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/supportbundle"
+)
+
+const supportReplayDescription = `
+This command will replay a recording of the inputs of the mesh catalog, written
+by the osm-controller when started with --catalog-recording-file, offline.
+
+The replay starts from the state captured in the first snapshot of the
+recording, and applies the recorded changes to Kubernetes objects, SMI and
+policy resources and the MeshConfig in the order the osm-controller observed
+them, without access to the cluster. At each later snapshot, it reports the
+clusters, endpoints, listeners and routes of each proxy which differ between the
+configuration captured in the snapshot and the replayed one.
+
+The first snapshot after which a proxy's configuration differs narrows down the
+changes that led the view of the mesh of the osm-controller to diverge.
+`
+
+const supportReplayExample = `
+# Replay the recording osm-catalog-recording.jsonl
+osm support replay osm-catalog-recording.jsonl
+`
+
+type supportReplayCmd struct {
+	out           io.Writer
+	recordingFile string
+}
+
+func newSupportReplayCmd(out io.Writer) *cobra.Command {
+	replayCmd := &supportReplayCmd{
+		out: out,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "replay RECORDING",
+		Short: "replay a recording of the inputs of the mesh catalog offline",
+		Long:  supportReplayDescription,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			replayCmd.recordingFile = args[0]
+			return replayCmd.run()
+		},
+		Example: supportReplayExample,
+	}
+
+	return cmd
+}
+
+func (cmd *supportReplayCmd) run() error {
+	fd, err := os.Open(cmd.recordingFile)
+	if err != nil {
+		return errors.Errorf("Error opening file %s: %s", cmd.recordingFile, err)
+	}
+	defer fd.Close() //nolint: errcheck, gosec
+
+	recording, err := supportbundle.LoadRecording(fd)
+	if err != nil {
+		return errors.Errorf("Error loading recording %s: %s", cmd.recordingFile, err)
+	}
+
+	report, err := supportbundle.ReplayRecording(recording)
+	if err != nil {
+		return errors.Errorf("Error replaying recording %s: %s", cmd.recordingFile, err)
+	}
+
+	w := newTabWriter(cmd.out)
+	fmt.Fprint(w, getPrettyPrintedReplayReport(report))
+	_ = w.Flush()
+
+	return nil
+}
+
+// getPrettyPrintedReplayReport returns the warnings of the given replay report and the messages it skipped, followed by
+// the xDS differences of each proxy at each snapshot as tab separated rows with a header
+func getPrettyPrintedReplayReport(report *supportbundle.ReplayReport) string {
+	var s string
+	for _, warning := range report.Warnings {
+		s += fmt.Sprintf("WARNING: %s\n", warning)
+	}
+	if len(report.Warnings) > 0 {
+		s += "\n"
+	}
+
+	s += fmt.Sprintf("Applied %d recorded changes\n", report.AppliedMessages)
+	if len(report.SkippedMessages) > 0 {
+		var announcementTypes []announcements.AnnouncementType
+		for announcementType := range report.SkippedMessages {
+			announcementTypes = append(announcementTypes, announcementType)
+		}
+		sort.Slice(announcementTypes, func(i, j int) bool {
+			return announcementTypes[i] < announcementTypes[j]
+		})
+
+		s += "\nSKIPPED ANNOUNCEMENT\tMESSAGES\n"
+		for _, announcementType := range announcementTypes {
+			s += fmt.Sprintf("%s\t%d\n", announcementType, report.SkippedMessages[announcementType])
+		}
+	}
+
+	s += "\nSNAPSHOT\tMESSAGES\tPROXY\tTYPE\tONLY IN SNAPSHOT\tONLY IN REPLAY\tCHANGED\n"
+	for _, snapshot := range report.Snapshots {
+		snapshotTime := snapshot.Time.UTC().Format("2006-01-02T15:04:05Z")
+		for _, proxy := range snapshot.Proxies {
+			if len(proxy.Diffs) == 0 {
+				s += fmt.Sprintf("%s\t%d\t%s\t-\t-\t-\t-\n", snapshotTime, snapshot.Messages, proxy.CommonName)
+				continue
+			}
+			for _, diff := range proxy.Diffs {
+				s += fmt.Sprintf("%s\t%d\t%s\t%s\t%s\t%s\t%s\n", snapshotTime, snapshot.Messages, proxy.CommonName, diff.TypeURI,
+					joinOrNone(diff.OnlyInBundle), joinOrNone(diff.OnlyInReplay), joinOrNone(diff.Changed))
+			}
+		}
+	}
+
+	if report.HasDrift() {
+		s += "\nThe replayed configuration of some proxies differs from the configuration captured in the snapshots\n"
+	} else {
+		s += "\nThe replayed configuration of the proxies matches the configuration captured in the snapshots\n"
+	}
+	return s
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/supportbundle"
+)
+
+func TestGetPrettyPrintedReplayReport(t *testing.T) {
+	assert := tassert.New(t)
+
+	first := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	report := &supportbundle.ReplayReport{
+		Warnings:        []string{"Timed out waiting for the replay to observe pod-added of Pod bookstore/bookstore"},
+		AppliedMessages: 3,
+		SkippedMessages: map[announcements.AnnouncementType]int{
+			announcements.ScheduleProxyBroadcast: 2,
+			announcements.SecretUpdated:          1,
+		},
+		Snapshots: []supportbundle.SnapshotAnalysis{
+			{
+				Time:    first,
+				Proxies: []supportbundle.ProxyAnalysis{{CommonName: "a.sidecar.bookbuyer.bookbuyer.cluster.local"}},
+			},
+			{
+				Time:     first.Add(5 * time.Minute),
+				Messages: 6,
+				Proxies: []supportbundle.ProxyAnalysis{{
+					CommonName: "a.sidecar.bookbuyer.bookbuyer.cluster.local",
+					Diffs: []supportbundle.XDSDiff{
+						{TypeURI: "CDS", OnlyInBundle: []string{"bookstore/bookstore"}},
+					},
+				}},
+			},
+		},
+	}
+
+	expected := "WARNING: Timed out waiting for the replay to observe pod-added of Pod bookstore/bookstore\n" +
+		"\nApplied 3 recorded changes\n" +
+		"\nSKIPPED ANNOUNCEMENT\tMESSAGES\n" +
+		"schedule-proxy-broadcast\t2\n" +
+		"secret-updated\t1\n" +
+		"\nSNAPSHOT\tMESSAGES\tPROXY\tTYPE\tONLY IN SNAPSHOT\tONLY IN REPLAY\tCHANGED\n" +
+		"2021-06-01T10:00:00Z\t0\ta.sidecar.bookbuyer.bookbuyer.cluster.local\t-\t-\t-\t-\n" +
+		"2021-06-01T10:05:00Z\t6\ta.sidecar.bookbuyer.bookbuyer.cluster.local\tCDS\tbookstore/bookstore\t-\t-\n" +
+		"\nThe replayed configuration of some proxies differs from the configuration captured in the snapshots\n"
+
+	assert.Equal(expected, getPrettyPrintedReplayReport(report))
+}
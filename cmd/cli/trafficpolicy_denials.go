@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openservicemesh/osm/pkg/cli"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy/als"
+)
+
+const trafficPolicyDenialsDescription = `
+This command will list the recent inbound requests denied by the RBAC policies
+of the proxies in the mesh, most recent first, along with the principal of the
+downstream and the route of the request. Requests denied in shadow mode were
+allowed through and are listed with the 'shadow' mode.
+
+The denials are only recorded when the denial log is enabled in the MeshConfig
+with 'spec.traffic.rbacAudit.enableDenialLog'. Denials in shadow mode are only
+recorded when 'spec.traffic.rbacAudit.shadowMode' is enabled.
+`
+
+const trafficPolicyDenialsExample = `
+# List the recent denials of all the services in the mesh
+osm policy denials
+
+# List the recent denials of the 'bookstore' service in the 'bookstore' namespace
+osm policy denials -n bookstore --service bookstore
+`
+
+type trafficPolicyDenialsCmd struct {
+	out       io.Writer
+	config    *rest.Config
+	clientSet kubernetes.Interface
+	namespace string
+	service   string
+	localPort uint16
+}
+
+func newTrafficPolicyDenials(out io.Writer) *cobra.Command {
+	denialsCmd := &trafficPolicyDenialsCmd{
+		out: out,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "denials",
+		Short: "list requests denied by traffic policies",
+		Long:  trafficPolicyDenialsDescription,
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if denialsCmd.service != "" && denialsCmd.namespace == "" {
+				return errors.New("The namespace of the service must be specified with --namespace")
+			}
+
+			config, err := settings.RESTClientGetter().ToRESTConfig()
+			if err != nil {
+				return errors.Errorf("Error fetching kubeconfig: %s", err)
+			}
+			denialsCmd.config = config
+
+			clientset, err := kubernetes.NewForConfig(config)
+			if err != nil {
+				return errors.Errorf("Could not access Kubernetes cluster, check kubeconfig: %s", err)
+			}
+			denialsCmd.clientSet = clientset
+			return denialsCmd.run()
+		},
+		Example: trafficPolicyDenialsExample,
+	}
+
+	f := cmd.Flags()
+	f.StringVarP(&denialsCmd.namespace, "namespace", "n", "", "Namespace of the services whose denials to list, all namespaces if unset")
+	f.StringVar(&denialsCmd.service, "service", "", "Name of the service whose denials to list, all services if unset")
+	f.Uint16VarP(&denialsCmd.localPort, "local-port", "p", constants.OSMHTTPServerPort, "Local port to use for port forwarding")
+
+	return cmd
+}
+
+func (cmd *trafficPolicyDenialsCmd) run() error {
+	denials, err := cli.GetRBACDenials(cmd.clientSet, cmd.config, settings.Namespace(), cmd.namespace, cmd.service, cmd.localPort)
+	if err != nil {
+		return annotateErrorMessageWithOsmNamespace("Error listing denials: %s", err)
+	}
+
+	if len(denials) == 0 {
+		fmt.Fprintf(cmd.out, "No recent requests denied by traffic policies\n")
+		return nil
+	}
+
+	w := newTabWriter(cmd.out)
+	fmt.Fprint(w, getPrettyPrintedDenials(denials))
+	_ = w.Flush()
+
+	return nil
+}
+
+// getPrettyPrintedDenials returns the given denials as tab separated rows with a header
+func getPrettyPrintedDenials(denials []als.Denial) string {
+	s := "TIME\tNAMESPACE\tSERVICES\tPRINCIPAL\tMETHOD\tAUTHORITY\tPATH\tMODE\n"
+	for _, denial := range denials {
+		mode := "enforced"
+		if denial.Shadow {
+			mode = "shadow"
+		}
+		services := "unknown"
+		if len(denial.Services) > 0 {
+			services = strings.Join(denial.Services, ",")
+		}
+		s += fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			denial.Time.Format(time.RFC3339),
+			valueOrUnknown(denial.Namespace),
+			services,
+			valueOrUnknown(denial.Principal),
+			denial.Method,
+			denial.Authority,
+			denial.Path,
+			mode,
+		)
+	}
+	return s
+}
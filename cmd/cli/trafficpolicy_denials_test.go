@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/envoy/als"
+)
+
+func TestGetPrettyPrintedDenials(t *testing.T) {
+	assert := tassert.New(t)
+
+	denials := []als.Denial{
+		{
+			Time:      time.Date(2021, time.June, 1, 12, 0, 5, 0, time.UTC),
+			Namespace: "bookstore",
+			Services:  []string{"bookstore", "bookstore-v1"},
+			Principal: "bookthief.bookthief.cluster.local",
+			Method:    "GET",
+			Authority: "bookstore.bookstore",
+			Path:      "/books-bought",
+			Shadow:    true,
+		},
+		{
+			Time:      time.Date(2021, time.June, 1, 12, 0, 0, 0, time.UTC),
+			Namespace: "bookwarehouse",
+			Method:    "POST",
+			Authority: "bookwarehouse.bookwarehouse",
+			Path:      "/restock-books",
+		},
+	}
+
+	expected := "TIME\tNAMESPACE\tSERVICES\tPRINCIPAL\tMETHOD\tAUTHORITY\tPATH\tMODE\n" +
+		"2021-06-01T12:00:05Z\tbookstore\tbookstore,bookstore-v1\tbookthief.bookthief.cluster.local\tGET\tbookstore.bookstore\t/books-bought\tshadow\n" +
+		"2021-06-01T12:00:00Z\tbookwarehouse\tunknown\tunknown\tPOST\tbookwarehouse.bookwarehouse\t/restock-books\tenforced\n"
+
+	assert.Equal(expected, getPrettyPrintedDenials(denials))
+}
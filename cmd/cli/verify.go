@@ -0,0 +1,24 @@
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+const verifyCmdDescription = `
+This command consists of subcommands verifying the state of the mesh
+to diagnose why traffic fails.
+`
+
+func newVerifyCmd(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "verify the state of the mesh",
+		Long:  verifyCmdDescription,
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(newVerifyConnectivityCmd(out))
+
+	return cmd
+}
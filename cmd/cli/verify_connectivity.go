@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openservicemesh/osm/pkg/cli"
+	"github.com/openservicemesh/osm/pkg/connectivity"
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+const verifyConnectivityDescription = `
+This command will verify whether the source pod can reach the destination
+service. The osm-controller walks the state of the mesh and the configuration
+generated for the proxy of the source pod: whether the namespaces are
+monitored, the pod has a sidecar, the service has ready endpoints, the traffic
+policies allow the traffic, the proxy is connected and configured with the
+endpoints and routes of the service, and the certificates are valid.
+
+The checks are listed in order, the first failing check pinpoints why the
+traffic fails.
+`
+
+const verifyConnectivityExample = `
+# Verify the pod 'bookbuyer-client' in the 'bookbuyer' namespace can reach the 'bookstore' service in the 'bookstore' namespace
+osm verify connectivity bookbuyer/bookbuyer-client bookstore/bookstore
+`
+
+type verifyConnectivityCmd struct {
+	out        io.Writer
+	config     *rest.Config
+	clientSet  kubernetes.Interface
+	sourcePod  string
+	dstService string
+	localPort  uint16
+}
+
+func newVerifyConnectivityCmd(out io.Writer) *cobra.Command {
+	verifyCmd := &verifyConnectivityCmd{
+		out: out,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "connectivity SOURCE_POD DESTINATION_SERVICE",
+		Short: "verify a pod can reach a service",
+		Long:  verifyConnectivityDescription,
+		Args:  cobra.ExactArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			verifyCmd.sourcePod = args[0]
+			verifyCmd.dstService = args[1]
+
+			config, err := settings.RESTClientGetter().ToRESTConfig()
+			if err != nil {
+				return errors.Errorf("Error fetching kubeconfig: %s", err)
+			}
+			verifyCmd.config = config
+
+			clientset, err := kubernetes.NewForConfig(config)
+			if err != nil {
+				return errors.Errorf("Could not access Kubernetes cluster, check kubeconfig: %s", err)
+			}
+			verifyCmd.clientSet = clientset
+			return verifyCmd.run()
+		},
+		Example: verifyConnectivityExample,
+	}
+
+	f := cmd.Flags()
+	f.Uint16VarP(&verifyCmd.localPort, "local-port", "p", constants.OSMHTTPServerPort, "Local port to use for port forwarding")
+
+	return cmd
+}
+
+func (cmd *verifyConnectivityCmd) run() error {
+	srcNs, srcPodName, err := unmarshalNamespacedPod(cmd.sourcePod)
+	if err != nil {
+		return errors.Errorf("Invalid argument specified for the source pod: %s", err)
+	}
+	dstNs, dstSvcName, err := unmarshalNamespacedPod(cmd.dstService)
+	if err != nil {
+		return errors.Errorf("Invalid argument specified for the destination service: %s", err)
+	}
+
+	report, err := cli.VerifyConnectivity(cmd.clientSet, cmd.config, settings.Namespace(), srcNs, srcPodName, dstNs, dstSvcName, cmd.localPort)
+	if err != nil {
+		return annotateErrorMessageWithOsmNamespace("Error verifying connectivity: %s", err)
+	}
+
+	w := newTabWriter(cmd.out)
+	fmt.Fprint(w, getPrettyPrintedConnectivityReport(report))
+	_ = w.Flush()
+
+	if report.Status == connectivity.StatusFail {
+		return errors.Errorf("Pod %s cannot reach service %s", report.Source, report.Destination)
+	}
+	return nil
+}
+
+// getPrettyPrintedConnectivityReport returns the checks of the given report as tab separated rows with a header,
+// followed by the overall outcome of the verification
+func getPrettyPrintedConnectivityReport(report *connectivity.Report) string {
+	s := "STATUS\tCHECK\tMESSAGE\n"
+	for _, check := range report.Checks {
+		s += fmt.Sprintf("%s\t%s\t%s\n", check.Status, check.Name, check.Message)
+	}
+	s += fmt.Sprintf("\nConnectivity from pod %s to service %s: %s\n", report.Source, report.Destination, report.Status)
+	return s
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/connectivity"
+)
+
+func TestGetPrettyPrintedConnectivityReport(t *testing.T) {
+	assert := tassert.New(t)
+
+	report := &connectivity.Report{
+		Source:      "bookbuyer/bookbuyer-client",
+		Destination: "bookstore/bookstore",
+		Status:      connectivity.StatusFail,
+		Checks: []connectivity.Check{
+			{Name: "source namespace is monitored", Status: connectivity.StatusPass, Message: "Namespace bookbuyer is monitored by the mesh"},
+			{Name: "destination service exists", Status: connectivity.StatusFail, Message: "Service bookstore/bookstore was not found"},
+		},
+	}
+
+	expected := "STATUS\tCHECK\tMESSAGE\n" +
+		"pass\tsource namespace is monitored\tNamespace bookbuyer is monitored by the mesh\n" +
+		"fail\tdestination service exists\tService bookstore/bookstore was not found\n" +
+		"\nConnectivity from pod bookbuyer/bookbuyer-client to service bookstore/bookstore: fail\n"
+
+	assert.Equal(expected, getPrettyPrintedConnectivityReport(report))
+}
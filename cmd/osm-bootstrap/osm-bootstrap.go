@@ -18,6 +18,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/clientcmd"
@@ -34,6 +35,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/metricsstore"
 	"github.com/openservicemesh/osm/pkg/signals"
 	"github.com/openservicemesh/osm/pkg/version"
+	"github.com/openservicemesh/osm/pkg/webhook"
 )
 
 const (
@@ -72,6 +74,7 @@ func init() {
 	// Generic certificate manager/provider options
 	flags.StringVar(&certProviderKind, "certificate-manager", providers.TresorKind.String(), fmt.Sprintf("Certificate manager, one of [%v]", providers.ValidCertificateProviders))
 	flags.StringVar(&caBundleSecretName, "ca-bundle-secret-name", "", "Name of the Kubernetes Secret for the OSM CA bundle")
+	flags.StringVar(&crdConverterConfig.WebhookURL, "webhook-url", "", "Base URL (https://host:port) at which the API server reaches the crd conversion webhook when osm-bootstrap runs outside the cluster")
 
 	// Vault certificate manager/provider options
 	flags.StringVar(&vaultOptions.VaultProtocol, "vault-protocol", "http", "Host name of the Hashi Vault")
@@ -169,6 +172,9 @@ func main() {
 		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating crd conversion webhook")
 	}
 
+	// Rewrite the objects stored in previous versions of the CRDs in their storage version once they are converted
+	go crdconversion.NewStorageVersionMigrator(crdClient, dynamic.NewForConfigOrDie(kubeConfig)).Run(stop)
+
 	/*
 	 * Initialize osm-bootstrap's HTTP server
 	 */
@@ -222,6 +228,12 @@ func validateCLIParams() error {
 		return errors.Errorf("Please specify the CA bundle secret name using --ca-bundle-secret-name")
 	}
 
+	if crdConverterConfig.WebhookURL != "" {
+		if err := webhook.ValidateURL(crdConverterConfig.WebhookURL); err != nil {
+			return errors.Errorf("Error validating the webhook URL set using --webhook-url: %s", err)
+		}
+	}
+
 	return nil
 }
 
@@ -117,6 +117,25 @@ func TestValidateCLIParams(t *testing.T) {
 				assert.Nil(err)
 			},
 		},
+		{
+			caseName: "webhook-url is not an https URL",
+			setup: func() {
+				crdConverterConfig.WebhookURL = "http://10.0.0.1:9443"
+			},
+			verify: func(err error) {
+				assert.NotNil(err)
+				assert.Contains(err.Error(), "--webhook-url")
+			},
+		},
+		{
+			caseName: "webhook-url is valid",
+			setup: func() {
+				crdConverterConfig.WebhookURL = "https://10.0.0.1:9443"
+			},
+			verify: func(err error) {
+				assert.Nil(err)
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -127,4 +146,5 @@ func TestValidateCLIParams(t *testing.T) {
 
 	// restore original global values
 	osmNamespace = prevOsmNamespace
+	crdConverterConfig.WebhookURL = ""
 }
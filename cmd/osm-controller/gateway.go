@@ -3,7 +3,6 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 
 	"github.com/ghodss/yaml"
 	"github.com/pkg/errors"
@@ -15,7 +14,6 @@ import (
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/envoy/bootstrap"
-	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/multicluster"
 	"github.com/openservicemesh/osm/pkg/utils"
 )
@@ -46,12 +44,13 @@ func bootstrapOSMMulticlusterGateway(kubeClient kubernetes.Interface, certManage
 		return errors.Errorf("Error issuing bootstrap certificate for OSM gateway: %s", err)
 	}
 
+	host, port := getXDSAddress()
 	bootstrapConfig, err := bootstrap.BuildFromConfig(bootstrap.Config{
 		NodeID:           bootstrapCert.GetCommonName().String(),
 		AdminPort:        constants.EnvoyAdminPort,
 		XDSClusterName:   constants.OSMControllerName,
-		XDSHost:          fmt.Sprintf("%s.%s.svc.%s", constants.OSMControllerName, osmNamespace, identity.ClusterLocalTrustDomain),
-		XDSPort:          constants.ADSServerPort,
+		XDSHost:          host,
+		XDSPort:          port,
 		TrustedCA:        bootstrapCert.GetIssuingCA(),
 		CertificateChain: bootstrapCert.GetCertificateChain(),
 		PrivateKey:       bootstrapCert.GetPrivateKey(),
@@ -11,47 +11,70 @@ import (
 	"os"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
+	smiSplit "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha4"
+	smiSplitClientset "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/split/clientset/versioned"
 	"github.com/spf13/pflag"
 	admissionv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
 	extensionsClientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	"k8s.io/client-go/tools/clientcmd"
 
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
 	configClientset "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned"
 	policyClientset "github.com/openservicemesh/osm/pkg/gen/client/policy/clientset/versioned"
 
+	"github.com/openservicemesh/osm/pkg/backup"
 	"github.com/openservicemesh/osm/pkg/catalog"
-	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/certificate/providers"
+	"github.com/openservicemesh/osm/pkg/compliance"
 	"github.com/openservicemesh/osm/pkg/config"
 	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/connectivity"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/debugger"
+	"github.com/openservicemesh/osm/pkg/diagnostics"
 	"github.com/openservicemesh/osm/pkg/endpoint"
 	"github.com/openservicemesh/osm/pkg/envoy/ads"
+	"github.com/openservicemesh/osm/pkg/envoy/als"
 	"github.com/openservicemesh/osm/pkg/envoy/registry"
+	"github.com/openservicemesh/osm/pkg/envoy/snapshotstore"
 	"github.com/openservicemesh/osm/pkg/errcode"
+	"github.com/openservicemesh/osm/pkg/ha"
 	"github.com/openservicemesh/osm/pkg/health"
 	"github.com/openservicemesh/osm/pkg/httpserver"
+	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/ingress"
 	"github.com/openservicemesh/osm/pkg/k8s"
 	"github.com/openservicemesh/osm/pkg/k8s/events"
 	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/meshmetrics"
 	"github.com/openservicemesh/osm/pkg/metricsstore"
+	"github.com/openservicemesh/osm/pkg/multicluster"
+	"github.com/openservicemesh/osm/pkg/onboarding"
 	"github.com/openservicemesh/osm/pkg/policy"
+	"github.com/openservicemesh/osm/pkg/preflight"
+	"github.com/openservicemesh/osm/pkg/progressive"
+	"github.com/openservicemesh/osm/pkg/providers/consul"
 	"github.com/openservicemesh/osm/pkg/providers/kube"
+	"github.com/openservicemesh/osm/pkg/provisioning"
+	"github.com/openservicemesh/osm/pkg/scraping"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/signals"
 	"github.com/openservicemesh/osm/pkg/smi"
+	"github.com/openservicemesh/osm/pkg/supportbundle"
 	"github.com/openservicemesh/osm/pkg/validator"
 	"github.com/openservicemesh/osm/pkg/version"
+	"github.com/openservicemesh/osm/pkg/webhook"
 )
 
 const (
@@ -62,18 +85,60 @@ const (
 var (
 	verbosity                  string
 	meshName                   string // An ID that uniquely identifies an OSM instance
+	kubeConfigFile             string
 	osmNamespace               string
 	osmServiceAccount          string
 	validatorWebhookConfigName string
+	validatorWebhookURL        string
 	caBundleSecretName         string
 	osmMeshConfigName          string
 
+	// xDS server address used in the bootstrap config of proxies created by osm-controller
+	xdsHost string
+	xdsPort uint32
+
+	catalogShards int
+
+	initialSyncPacingConfig ads.InitialSyncPacingConfig
+
+	proxyRegistryGCConfig registry.GCConfig
+
+	xdsSnapshotStore        string
+	xdsSnapshotStoreDir     string
+	xdsSnapshotWarmupPeriod time.Duration
+
+	proxyDesyncConfig ads.DesyncMonitorConfig
+
+	enableLeaderElection bool
+	leaderElectionConfig ha.Config
+
 	certProviderKind string
 
 	tresorOptions      providers.TresorOptions
 	vaultOptions       providers.VaultOptions
 	certManagerOptions providers.CertManagerOptions
 
+	multiclusterBrokerKubeconfig string
+	multiclusterSyncerConfig     multicluster.SyncerConfig
+
+	consulConfig consul.Config
+
+	complianceSnapshotSink   string
+	complianceSnapshotConfig compliance.Config
+	complianceS3Config       compliance.S3Config
+
+	progressiveDeliveryConfig progressive.Config
+
+	namespaceOnboardingConfig onboarding.Config
+
+	namespaceRemovalPolicy                string
+	namespaceOffboardingRestartsWorkloads bool
+
+	watchScope k8s.WatchScope
+
+	catalogRecordingFile             string
+	catalogRecordingSnapshotInterval time.Duration
+
 	scheme = runtime.NewScheme()
 )
 
@@ -85,10 +150,39 @@ var (
 func init() {
 	flags.StringVarP(&verbosity, "verbosity", "v", constants.DefaultOSMLogLevel, "Set boot log verbosity level")
 	flags.StringVar(&meshName, "mesh-name", "", "OSM mesh name")
+	flags.StringVar(&kubeConfigFile, "kubeconfig", "", "Path to Kubernetes config file, used when osm-controller runs outside the cluster")
 	flags.StringVar(&osmNamespace, "osm-namespace", "", "OSM controller's namespace")
 	flags.StringVar(&osmServiceAccount, "osm-service-account", "", "OSM controller's service account")
 	flags.StringVar(&validatorWebhookConfigName, "validator-webhook-config", "", "Name of the ValidatingWebhookConfiguration for the resource validator webhook")
+	flags.StringVar(&validatorWebhookURL, "validator-webhook-url", "", "Base URL (https://host:port) at which the API server reaches the resource validator webhook when osm-controller runs outside the cluster")
 	flags.StringVar(&osmMeshConfigName, "osm-config-name", "osm-mesh-config", "Name of the OSM MeshConfig")
+	flags.StringVar(&xdsHost, "xds-host", "", "Host at which proxies reach the xDS server, defaults to the osm-controller service. Required when osm-controller runs outside the cluster")
+	flags.Uint32Var(&xdsPort, "xds-port", constants.ADSServerPort, "Port at which proxies reach the xDS server")
+	flags.IntVar(&catalogShards, "catalog-shards", catalog.DefaultNumShards, "Number of namespace shards that config change events are dispatched by independently")
+
+	// Pacing of the initial config delivery to reconnecting proxies
+	flags.Float64Var(&initialSyncPacingConfig.Rate, "initial-sync-rate", ads.DefaultInitialSyncRate, "Number of proxies admitted per second for their initial config delivery, not paced if 0")
+	flags.IntVar(&initialSyncPacingConfig.Burst, "initial-sync-burst", ads.DefaultInitialSyncBurst, "Number of proxies admitted at once for their initial config delivery")
+	flags.DurationVar(&initialSyncPacingConfig.MaxJitter, "initial-sync-max-jitter", ads.DefaultInitialSyncMaxJitter, "Maximum random delay before the initial config delivery to an admitted proxy is scheduled")
+
+	// Garbage collection of the registry entries of disconnected proxies
+	flags.DurationVar(&proxyRegistryGCConfig.TTL, "proxy-registry-gc-ttl", registry.DefaultGCTTL, "Period after which the registry entries and certificates of proxies that disconnected and whose pods no longer exist are reclaimed, never reclaimed if 0")
+	flags.DurationVar(&proxyRegistryGCConfig.Interval, "proxy-registry-gc-interval", registry.DefaultGCInterval, "Interval at which the registry entries of disconnected proxies are garbage collected")
+
+	// Persisted last known good xDS snapshots
+	flags.StringVar(&xdsSnapshotStore, "xds-snapshot-store", "", fmt.Sprintf("Store the last xDS config generated for each proxy identity is persisted to, one of [%s %s], not persisted if unset", snapshotstore.StoreKindConfigMap, snapshotstore.StoreKindFile))
+	flags.StringVar(&xdsSnapshotStoreDir, "xds-snapshot-store-dir", "", "Directory xDS snapshots are persisted to when using the file store")
+	flags.DurationVar(&xdsSnapshotWarmupPeriod, "xds-snapshot-warmup-period", ads.DefaultSnapshotWarmupPeriod, "Period after startup during which proxies are served their persisted xDS snapshot")
+
+	// Detection of the proxies lagging behind the config sent to them
+	flags.DurationVar(&proxyDesyncConfig.Threshold, "proxy-desync-threshold", ads.DefaultDesyncThreshold, "Time after which a proxy that has not acknowledged the config sent to it is reported as desynced, not detected if 0")
+	flags.DurationVar(&proxyDesyncConfig.CheckInterval, "proxy-desync-check-interval", ads.DefaultDesyncCheckInterval, "Interval at which proxies are checked for desyncs")
+	flags.BoolVar(&proxyDesyncConfig.Repush, "proxy-desync-repush", false, "Push the config desynced proxies lag behind again")
+
+	// Active/standby controllers
+	flags.BoolVar(&enableLeaderElection, "enable-leader-election", false, "Elect a leader among the osm-controller replicas to serve ADS, the other replicas stand by with warm caches")
+	flags.DurationVar(&leaderElectionConfig.LeaseDuration, "leader-election-lease-duration", ha.DefaultLeaseDuration, "Duration standby replicas wait before taking over from a leader that stopped renewing its lease")
+	flags.DurationVar(&leaderElectionConfig.HandoffInterval, "leader-election-handoff-interval", ha.DefaultHandoffInterval, "Interval at which the leader hands off the config versions of proxies to standby replicas")
 
 	// Generic certificate manager/provider options
 	flags.StringVar(&certProviderKind, "certificate-manager", providers.TresorKind.String(), fmt.Sprintf("Certificate manager, one of [%v]", providers.ValidCertificateProviders))
@@ -106,6 +200,47 @@ func init() {
 	flags.StringVar(&certManagerOptions.IssuerKind, "cert-manager-issuer-kind", "Issuer", "cert-manager issuer kind")
 	flags.StringVar(&certManagerOptions.IssuerGroup, "cert-manager-issuer-group", "cert-manager.io", "cert-manager issuer group")
 
+	// Multicluster sync controller options
+	flags.StringVar(&multiclusterSyncerConfig.ClusterName, "cluster-name", "", "Name of the cluster, unique among the clusters sharing the multicluster broker")
+	flags.StringVar(&multiclusterSyncerConfig.GatewayAddress, "multicluster-gateway-address", "", "Address (IP:port) of the multicluster gateway reachable from remote clusters")
+	flags.StringVar(&multiclusterBrokerKubeconfig, "multicluster-broker-kubeconfig", "", "Path to the kubeconfig of the cluster hosting the multicluster broker, services are not synced between clusters if unset")
+	flags.StringVar(&multiclusterSyncerConfig.BrokerNamespace, "multicluster-broker-namespace", "osm-multicluster-broker", "Namespace on the broker cluster in which exported services are stored")
+	flags.DurationVar(&multiclusterSyncerConfig.Interval, "multicluster-sync-interval", multicluster.DefaultSyncInterval, "Interval at which exported services are synced between clusters")
+
+	// Consul catalog service discovery
+	flags.StringVar(&consulConfig.Address, "consul-address", "", "Base URL (http[s]://host:port) of the Consul HTTP API, services are not discovered from Consul if unset")
+	flags.StringVar(&consulConfig.Datacenter, "consul-datacenter", "", "Consul datacenter services are discovered from, defaults to the datacenter of the Consul agent")
+	flags.StringVar(&consulConfig.TokenFile, "consul-token-file", "", fmt.Sprintf("Path of a file, typically mounted from a Secret, holding the ACL token used to read the Consul catalog. Defaults to the %s environment variable if unset", consul.TokenEnvVar))
+	flags.DurationVar(&consulConfig.Interval, "consul-sync-interval", consul.DefaultSyncInterval, "Interval at which services are synced from the Consul catalog")
+
+	// Compliance snapshots
+	flags.StringVar(&complianceSnapshotSink, "compliance-snapshot-sink", "", fmt.Sprintf("Sink signed traffic policy snapshots are stored to, one of [%s %s], snapshots are not taken if unset", compliance.SinkKindConfigMap, compliance.SinkKindS3))
+	flags.DurationVar(&complianceSnapshotConfig.Interval, "compliance-snapshot-interval", compliance.DefaultSnapshotInterval, "Interval at which traffic policy snapshots are taken")
+	flags.StringVar(&complianceS3Config.Endpoint, "compliance-s3-endpoint", "", "Base URL of the S3 API snapshots are uploaded to, defaults to the AWS S3 endpoint of the region")
+	flags.StringVar(&complianceS3Config.Region, "compliance-s3-region", "", "Region of the S3 bucket snapshots are uploaded to")
+	flags.StringVar(&complianceS3Config.Bucket, "compliance-s3-bucket", "", "Name of the S3 bucket snapshots are uploaded to")
+	flags.StringVar(&complianceS3Config.Prefix, "compliance-s3-prefix", "", "Prefix of the keys snapshots are uploaded under")
+
+	// Progressive delivery
+	flags.StringVar(&progressiveDeliveryConfig.PrometheusURL, "progressive-delivery-prometheus-url", "", "Base URL (http[s]://host:port) of the Prometheus HTTP API the metrics of canary backends are queried from, ProgressiveDelivery policies are not reconciled if unset")
+	flags.DurationVar(&progressiveDeliveryConfig.ReconcileInterval, "progressive-delivery-reconcile-interval", progressive.DefaultReconcileInterval, "Interval at which ProgressiveDelivery policies are reconciled")
+
+	// Namespace onboarding
+	flags.StringVar(&namespaceOnboardingConfig.Selector, "namespace-onboarding-selector", "", "Label selector of the namespaces automatically added to the mesh, namespaces are not added automatically if unset")
+	flags.StringSliceVar(&namespaceOnboardingConfig.ProtectedNamespaces, "namespace-onboarding-protected-namespaces", nil, "Namespaces never added to the mesh automatically, in addition to the Kubernetes system namespaces and the OSM namespace")
+
+	// Namespace offboarding
+	flags.StringVar(&namespaceRemovalPolicy, "namespace-removal-policy", validator.NamespaceRemovalPolicyWarn, fmt.Sprintf("Policy applied when a namespace running pods with sidecars is removed from the mesh, one of [%s %s]", validator.NamespaceRemovalPolicyWarn, validator.NamespaceRemovalPolicyBlock))
+	flags.BoolVar(&namespaceOffboardingRestartsWorkloads, "namespace-offboarding-restart-workloads", false, "Restart the Deployments, StatefulSets and DaemonSets running pods with sidecars in the namespaces removed from the mesh")
+
+	// Watch scope
+	flags.StringSliceVar(&watchScope.Namespaces, "watch-namespaces", nil, "Namespaces whose resources are watched, only these namespaces are monitored even if labeled for the mesh. Resources are watched in all namespaces if unset")
+	flags.StringVar(&watchScope.LabelSelector, "watch-label-selector", "", "Label selector of the Services, ServiceAccounts, Pods and TLS Secrets watched, all of them are watched if unset")
+
+	// Recording of the inputs of the mesh catalog
+	flags.StringVar(&catalogRecordingFile, "catalog-recording-file", "", "Path of the file the messages published in the controller and periodic snapshots of the state of the control plane are recorded to, for replay with 'osm support replay'. Not recorded if unset")
+	flags.DurationVar(&catalogRecordingSnapshotInterval, "catalog-recording-snapshot-interval", supportbundle.DefaultRecordingSnapshotInterval, "Interval at which snapshots of the state of the control plane are recorded, only recorded when the controller starts and stops if 0")
+
 	_ = clientgoscheme.AddToScheme(scheme)
 	_ = admissionv1.AddToScheme(scheme)
 }
@@ -120,21 +255,21 @@ func main() {
 		log.Fatal().Err(err).Msg("Error setting log level")
 	}
 
-	// Initialize kube config and client
-	kubeConfig, err := clientcmd.BuildConfigFromFlags("", "")
+	// Initialize kube config and client. The in-cluster config is used when a kubeconfig is not specified.
+	kubeConfig, err := clientcmd.BuildConfigFromFlags("", kubeConfigFile)
 	if err != nil {
-		log.Fatal().Err(err).Msg("Error creating kube configs using in-cluster config")
+		log.Fatal().Err(err).Msgf("Error creating kube config (kubeconfig=%s)", kubeConfigFile)
 	}
 	kubeClient := kubernetes.NewForConfigOrDie(kubeConfig)
 	policyClient := policyClientset.NewForConfigOrDie(kubeConfig)
 
 	// Initialize the generic Kubernetes event recorder and associate it with the osm-controller pod resource
-	controllerPod, err := getOSMControllerPod(kubeClient)
+	eventObject, err := getEventRecorderObject(kubeClient)
 	if err != nil {
 		log.Fatal().Msg("Error fetching osm-controller pod")
 	}
 	eventRecorder := events.GenericEventRecorder()
-	if err := eventRecorder.Initialize(controllerPod, kubeClient, osmNamespace); err != nil {
+	if err := eventRecorder.Initialize(eventObject, kubeClient, osmNamespace); err != nil {
 		log.Fatal().Msg("Error initializing generic event recorder")
 	}
 
@@ -157,11 +292,25 @@ func main() {
 	// Start Global log level handler, reads from configurator (meshconfig)
 	StartGlobalLogLevelHandler(cfg, stop)
 
-	k8sClient, err := k8s.NewKubernetesController(kubeClient, policyClient, meshName, stop)
+	// The informers resync and back off from watch errors per the performance settings as of the controller's start
+	performanceSettings := cfg.GetPerformanceSettings()
+	k8sClient, err := k8s.NewKubernetesControllerWithOptions(kubeClient, policyClient, meshName, k8s.ControllerOptions{
+		ResyncInterval:       performanceSettings.InformerResyncInterval,
+		ResyncIntervals:      performanceSettings.InformerResyncIntervals,
+		WatchErrorBackoff:    performanceSettings.InformerWatchErrorBackoff,
+		WatchErrorMaxBackoff: performanceSettings.InformerWatchErrorMaxBackoff,
+		WatchScope:           watchScope,
+	}, stop)
 	if err != nil {
 		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating Kubernetes Controller")
 	}
 
+	// Records events against the mesh resources the events are about
+	objectEventRecorder, err := events.NewObjectEventRecorder(kubeClient, smiSplit.AddToScheme, configv1alpha1.AddToScheme, policyv1alpha1.AddToScheme)
+	if err != nil {
+		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating Kubernetes event recorder for mesh resources")
+	}
+
 	meshSpec, err := smi.NewMeshSpecClient(kubeConfig, kubeClient, osmNamespace, k8sClient, stop)
 	if err != nil {
 		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating MeshSpec")
@@ -176,6 +325,12 @@ func main() {
 	}
 
 	if cfg.GetFeatureFlags().EnableMulticlusterMode {
+		// Store the CA bundles of remote trust domains so the multicluster gateway can validate remote proxies
+		if certManager, err = providers.NewTrustBundleManager(certManager, kubeClient, osmNamespace); err != nil {
+			events.GenericEventRecorder().FatalEvent(err, events.InvalidCertificateManager,
+				"Error creating trust bundle store for certificate manager of kind %s", certProviderKind)
+		}
+
 		log.Info().Msgf("Bootstrapping OSM multicluster gateway")
 		if err := bootstrapOSMMulticlusterGateway(kubeClient, certManager, osmNamespace); err != nil {
 			events.GenericEventRecorder().FatalEvent(err, events.InitializationError,
@@ -183,20 +338,45 @@ func main() {
 		}
 	}
 
+	// Tasks writing to the cluster or polling external systems. When leader election is enabled, they are only run
+	// by the leader, along with the ADS server.
+	var leaderTasks []func()
+
 	var configClient config.Controller
 
 	if cfg.GetFeatureFlags().EnableMulticlusterMode {
 		if configClient, err = config.NewConfigController(kubeConfig, k8sClient, stop); err != nil {
 			events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating Kubernetes config client")
 		}
+
+		if multiclusterBrokerKubeconfig != "" {
+			brokerConfig, err := clientcmd.BuildConfigFromFlags("", multiclusterBrokerKubeconfig)
+			if err != nil {
+				events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating kube config for the multicluster broker")
+			}
+			multiclusterSyncerConfig.BrokerClient = kubernetes.NewForConfigOrDie(brokerConfig)
+			multiclusterSyncerConfig.TrustDomain = cfg.GetTrustDomain()
+			multiclusterSyncerConfig.EventRecorder = objectEventRecorder
+			multiclusterSyncer := multicluster.NewSyncer(multiclusterSyncerConfig, k8sClient, configClientset.NewForConfigOrDie(kubeConfig), certManager)
+			leaderTasks = append(leaderTasks, func() { multiclusterSyncer.Run(stop) })
+		}
 	}
 
 	// A nil configClient is passed in if multi cluster mode is not enabled.
 	kubeProvider := kube.NewClient(k8sClient, configClient, constants.KubeProviderName, cfg)
+	kubeProvider.Run(stop)
 
 	endpointsProviders := []endpoint.Provider{kubeProvider}
 	serviceProviders := []service.Provider{kubeProvider}
 
+	var externalEndpointsProviders []catalog.ExternalEndpointsProvider
+	if consulConfig.Address != "" {
+		consulConfig.Token = os.Getenv(consul.TokenEnvVar)
+		consulProvider := consul.NewClient(constants.ConsulProviderName, consulConfig)
+		leaderTasks = append(leaderTasks, func() { consulProvider.Run(stop) })
+		externalEndpointsProviders = append(externalEndpointsProviders, consulProvider)
+	}
+
 	ingressClient, err := ingress.NewIngressClient(kubeClient, k8sClient, stop, cfg, certManager)
 	if err != nil {
 		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating Ingress monitor client")
@@ -217,7 +397,13 @@ func main() {
 		cfg,
 		serviceProviders,
 		endpointsProviders,
+		externalEndpointsProviders,
+		catalogShards,
 	)
+	if cfg.GetFeatureFlags().EnableMulticlusterMode {
+		// The multicluster gateway runs with the OSM controller's service account
+		meshCatalog.SetMulticlusterGatewayIdentity(identity.K8sServiceAccount{Name: osmServiceAccount, Namespace: osmNamespace}.ToServiceIdentity())
+	}
 
 	var proxyMapper registry.ProxyServiceMapper
 	if cfg.GetFeatureFlags().EnableAsyncProxyServiceMapping {
@@ -229,6 +415,7 @@ func main() {
 	}
 	proxyRegistry := registry.NewProxyRegistry(proxyMapper)
 	proxyRegistry.ReleaseCertificateHandler(certManager)
+	proxyRegistry.RunGarbageCollector(proxyRegistryGCConfig, certManager, k8sClient, stop)
 
 	adsCert, err := certManager.IssueCertificate(xdsServerCertificateCommonName, constants.XDSCertificateValidityPeriod)
 	if err != nil {
@@ -236,28 +423,61 @@ func main() {
 	}
 
 	// Create and start the ADS gRPC service
-	xdsServer := ads.NewADSServer(meshCatalog, proxyRegistry, cfg.IsDebugServerEnabled(), osmNamespace, cfg, certManager, k8sClient)
-	if err := xdsServer.Start(ctx, cancel, constants.ADSServerPort, adsCert); err != nil {
-		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error initializing ADS server")
+	xdsServer := ads.NewADSServer(meshCatalog, proxyRegistry, cfg.IsDebugServerEnabled(), osmNamespace, cfg, certManager, k8sClient, initialSyncPacingConfig)
+	if xdsSnapshotStore != "" {
+		store, err := getXDSSnapshotStore(kubeClient)
+		if err != nil {
+			events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating xDS snapshot store")
+		}
+		xdsServer.EnableSnapshotPersistence(store, xdsSnapshotWarmupPeriod)
+	}
+	xdsServer.EnableDesyncMonitor(proxyDesyncConfig)
+	// Proxies stream the inbound requests denied by RBAC policies over their ADS connection when the denial log is enabled
+	rbacDenialLog := als.NewDenialLog(als.DefaultMaxDenials)
+	xdsServer.EnableAccessLogService(als.NewServer(proxyRegistry, rbacDenialLog))
+	xdsServer.SetEventRecorder(objectEventRecorder)
+	var adsProbe health.Probes = xdsServer
+	leaderTasks = append(leaderTasks, func() {
+		if err := xdsServer.Start(ctx, cancel, constants.ADSServerPort, adsCert); err != nil {
+			events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error initializing ADS server")
+		}
+	})
+	var elector *ha.Elector
+	if enableLeaderElection {
+		// Only the leader serves ADS, standby replicas keep their certificate cache warm to take over
+		ha.RunCertificateCacheWarmer(certManager, k8sClient, cfg, stop)
+		leaderElectionConfig.Namespace = osmNamespace
+		leaderElectionConfig.Identity = getLeaderElectionIdentity()
+		elector = ha.NewElector(leaderElectionConfig, kubeClient, xdsServer)
+		adsProbe = elector
 	}
 
 	clientset := extensionsClientset.NewForConfigOrDie(kubeConfig)
 
-	webhookHandlerCert, err := certManager.IssueCertificate(
-		certificate.CommonName(fmt.Sprintf("%s.%s.svc", validatorWebhookSvc, osmNamespace)),
-		constants.XDSCertificateValidityPeriod)
+	// The preflight checks report the cluster conditions preventing the mesh from working, without stopping the controller
+	preflightChecker := preflight.NewChecker(kubeClient, clientset, meshName)
+	for _, result := range preflightChecker.Run().Results {
+		switch result.Status {
+		case preflight.StatusFail:
+			events.GenericEventRecorder().WarnEvent(events.PreflightCheckFailed, "Preflight check %s failed: %s", result.Name, result.Message)
+		case preflight.StatusWarn:
+			log.Warn().Msgf("Preflight check %s: %s", result.Name, result.Message)
+		}
+	}
+
+	webhookHandlerCert, err := certManager.IssueCertificate(webhook.GetCommonName(validatorWebhookURL, validatorWebhookSvc, osmNamespace), constants.XDSCertificateValidityPeriod)
 	if err != nil {
 		events.GenericEventRecorder().FatalEvent(err, events.CertificateIssuanceFailure, "Error issuing certificate for the validating webhook")
 	}
 
-	if err := validator.NewValidatingWebhook(validatorWebhookConfigName, constants.ValidatorWebhookPort, webhookHandlerCert, kubeClient, stop); err != nil {
+	if err := validator.NewValidatingWebhook(validatorWebhookConfigName, constants.ValidatorWebhookPort, validatorWebhookURL, osmNamespace, meshName, namespaceRemovalPolicy, webhookHandlerCert, kubeClient, stop); err != nil {
 		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error starting the validating webhook server")
 	}
 
 	// Initialize OSM's http service server
 	httpServer := httpserver.NewHTTPServer(constants.OSMHTTPServerPort)
 	// Health/Liveness probes
-	funcProbes := []health.Probes{xdsServer, smi.HealthChecker{DiscoveryClient: clientset.Discovery()}}
+	funcProbes := []health.Probes{adsProbe, meshCatalog, smi.HealthChecker{DiscoveryClient: clientset.Discovery()}}
 	httpServer.AddHandlers(map[string]http.Handler{
 		"/health/ready": health.ReadinessHandler(funcProbes, getHTTPHealthProbes()),
 		"/health/alive": health.LivenessHandler(funcProbes, getHTTPHealthProbes()),
@@ -268,6 +488,24 @@ func main() {
 	httpServer.AddHandler("/version", version.GetVersionHandler())
 	// Supported SMI Versions
 	httpServer.AddHandler(constants.HTTPServerSmiVersionPath, smi.GetSmiClientVersionHTTPHandler())
+	// Inventory of the connected proxies
+	httpServer.AddHandler(constants.HTTPServerProxyInventoryPath, proxyRegistry.GetProxyInventoryHTTPHandler())
+	// Recent inbound requests denied by RBAC policies
+	httpServer.AddHandler(constants.HTTPServerRBACDenialsPath, rbacDenialLog.GetDenialsHTTPHandler())
+	// Verification of the connectivity from a pod to a service
+	httpServer.AddHandler(constants.HTTPServerVerifyConnectivityPath, connectivity.NewVerifier(meshCatalog, proxyRegistry, certManager, cfg).GetVerifyHTTPHandler())
+	// Support bundle capturing the state of the control plane
+	supportBundleExporter := supportbundle.NewExporter(meshCatalog, meshSpec, policyClient, proxyRegistry, certManager, cfg, osmNamespace, meshName)
+	httpServer.AddHandler(constants.HTTPServerSupportBundlePath, supportBundleExporter.GetSupportBundleHTTPHandler())
+	// Recent errors by error code, proxy and resource
+	httpServer.AddHandler(constants.HTTPServerErrorsPath, errcode.DefaultErrorLog.GetErrorsHTTPHandler())
+	// Report of the preflight checks of the mesh
+	httpServer.AddHandler(constants.HTTPServerPreflightPath, preflightChecker.GetReportHTTPHandler())
+	// Backup of the policy state of the mesh
+	httpServer.AddHandler(constants.HTTPServerBackupPath,
+		backup.NewExporter(kubeClient, dynamic.NewForConfigOrDie(kubeConfig), osmNamespace, meshName, osmMeshConfigName).GetBackupHTTPHandler())
+	// Metrics of the inbound traffic of a service aggregated across the proxy sidecars of its pods
+	httpServer.AddHandler(constants.HTTPServerMetricsSummaryPath, meshmetrics.NewAggregator(k8sClient, cfg, certManager).GetSummaryHTTPHandler())
 
 	// Start HTTP server
 	err = httpServer.Start()
@@ -277,12 +515,94 @@ func main() {
 
 	// Create DebugServer and start its config event listener.
 	// Listener takes care to start and stop the debug server as appropriate
-	debugConfig := debugger.NewDebugConfig(certDebugger, xdsServer, meshCatalog, proxyRegistry, kubeConfig, kubeClient, cfg, k8sClient)
+	diagnosticsRunner := diagnostics.NewRunner(kubeClient, diagnostics.NewPortForwardCollector(kubeConfig, kubeClient),
+		diagnostics.NewConfigMapArtifactStore(kubeClient, osmNamespace), diagnostics.DefaultMaxConcurrency)
+	debugConfig := debugger.NewDebugConfig(certDebugger, xdsServer, meshCatalog, proxyRegistry, kubeConfig, kubeClient, cfg, k8sClient, diagnosticsRunner)
 	debugConfig.StartDebugServerConfigListener()
 
+	// The inputs of the mesh catalog are recorded by every replica, to the file system of its pod
+	var recordingDone chan struct{}
+	if catalogRecordingFile != "" {
+		recordingFile, err := os.Create(catalogRecordingFile)
+		if err != nil {
+			events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating catalog recording file %s", catalogRecordingFile)
+		}
+		recorder := supportbundle.NewRecorder(supportBundleExporter, recordingFile, catalogRecordingSnapshotInterval)
+		recordingDone = make(chan struct{})
+		go func() {
+			recorder.Run(stop)
+			_ = recordingFile.Close()
+			close(recordingDone)
+		}()
+	}
+
+	if complianceSnapshotSink != "" {
+		sink, err := getComplianceSnapshotSink(kubeClient)
+		if err != nil {
+			events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating compliance snapshot sink")
+		}
+		complianceSnapshotConfig.MeshName = meshName
+		complianceSnapshotConfig.SigningCommonName = compliance.GetSigningCommonName(osmNamespace)
+		snapshotter := compliance.NewSnapshotter(complianceSnapshotConfig, meshCatalog, k8sClient, cfg, certManager, sink)
+		leaderTasks = append(leaderTasks, func() { snapshotter.Run(stop) })
+	}
+
+	scrapingReconciler := scraping.NewReconciler(kubeClient, dynamic.NewForConfigOrDie(kubeConfig), k8sClient, cfg, certManager, osmNamespace)
+	leaderTasks = append(leaderTasks, func() { scrapingReconciler.Run(stop) })
+
+	provisioningReconciler := provisioning.NewReconciler(kubeClient, cfg, osmNamespace)
+	leaderTasks = append(leaderTasks, func() { provisioningReconciler.Run(stop) })
+
+	if progressiveDeliveryConfig.PrometheusURL != "" {
+		progressiveController := progressive.NewController(progressiveDeliveryConfig, policyController, meshSpec, k8sClient,
+			smiSplitClientset.NewForConfigOrDie(kubeConfig), objectEventRecorder)
+		leaderTasks = append(leaderTasks, func() { progressiveController.Run(stop) })
+	}
+
+	if namespaceOnboardingConfig.Selector != "" {
+		onboardingController, err := onboarding.NewController(namespaceOnboardingConfig, kubeClient, meshName, osmNamespace, objectEventRecorder)
+		if err != nil {
+			events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating namespace onboarding controller")
+		}
+		leaderTasks = append(leaderTasks, func() { onboardingController.Run(stop) })
+	}
+
+	if namespaceOffboardingRestartsWorkloads {
+		offboardingController := onboarding.NewOffboardingController(kubeClient, meshName, objectEventRecorder)
+		leaderTasks = append(leaderTasks, func() { offboardingController.Run(stop) })
+	}
+
+	namespaceOffboardingController := onboarding.NewNamespaceOffboardingController(kubeClient, k8sClient, policyController, certManager, meshName, objectEventRecorder)
+	leaderTasks = append(leaderTasks, func() { namespaceOffboardingController.Run(stop) })
+
 	k8s.PatchSecretHandler(kubeClient)
+	leaderTasks = append(leaderTasks, func() {
+		k8s.AppProtocolMismatchHandler(objectEventRecorder)
+		smi.TrafficSplitStatusHandler(objectEventRecorder)
+		policy.ConflictHandler(policyController, objectEventRecorder)
+	})
+
+	runLeaderTasks := func() {
+		for _, task := range leaderTasks {
+			task()
+		}
+	}
+	if elector != nil {
+		if err := elector.Run(ctx, runLeaderTasks, func() {
+			// Exit so that the replica restarts as a standby and proxies reconnect to the new leader
+			log.Fatal().Msg("Lost leadership, exiting")
+		}); err != nil {
+			events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error starting leader election")
+		}
+	} else {
+		runLeaderTasks()
+	}
 
 	<-stop
+	if recordingDone != nil {
+		// Wait for the final snapshot of the recording
+		<-recordingDone
+	}
 	log.Info().Msgf("Stopping osm-controller %s; %s; %s", version.Version, version.GitCommit, version.BuildDate)
 }
 
@@ -290,12 +610,38 @@ func main() {
 func startMetricsStore() {
 	metricsstore.DefaultMetricsStore.Start(
 		metricsstore.DefaultMetricsStore.K8sAPIEventCounter,
+		metricsstore.DefaultMetricsStore.K8sInformerResyncPeriod,
+		metricsstore.DefaultMetricsStore.K8sInformerWatchErrorCount,
+		metricsstore.DefaultMetricsStore.K8sInformerRelistCount,
+		metricsstore.DefaultMetricsStore.K8sInformerLastSyncTime,
+		metricsstore.DefaultMetricsStore.K8sInformerCacheSize,
 		metricsstore.DefaultMetricsStore.ProxyConnectCount,
 		metricsstore.DefaultMetricsStore.ProxyReconnectCount,
 		metricsstore.DefaultMetricsStore.ProxyConfigUpdateTime,
 		metricsstore.DefaultMetricsStore.ProxyBroadcastEventCount,
+		metricsstore.DefaultMetricsStore.ProxyUnchangedConfigCount,
+		metricsstore.DefaultMetricsStore.ProxyCertRotationPushTime,
+		metricsstore.DefaultMetricsStore.ProxyStaleCertCount,
+		metricsstore.DefaultMetricsStore.ProxyDesyncCount,
+		metricsstore.DefaultMetricsStore.ProxyDesyncRepushCount,
+		metricsstore.DefaultMetricsStore.ProxyRestartCount,
+		metricsstore.DefaultMetricsStore.ProxyRegistryReclaimedCount,
+		metricsstore.DefaultMetricsStore.RBACDenialCount,
+		metricsstore.DefaultMetricsStore.RBACPolicyPrincipalCount,
+		metricsstore.DefaultMetricsStore.RBACCompactedPrincipalCount,
+		metricsstore.DefaultMetricsStore.CatalogShardNamespaceCount,
+		metricsstore.DefaultMetricsStore.CatalogShardEventCount,
+		metricsstore.DefaultMetricsStore.CatalogShardBroadcastCount,
+		metricsstore.DefaultMetricsStore.CatalogShardRebalanceCount,
+		metricsstore.DefaultMetricsStore.EndpointFlapPinCount,
 		metricsstore.DefaultMetricsStore.CertIssuedCount,
 		metricsstore.DefaultMetricsStore.CertIssuedTime,
+		metricsstore.DefaultMetricsStore.CertProviderIssuedCount,
+		metricsstore.DefaultMetricsStore.CertRotatedCount,
+		metricsstore.DefaultMetricsStore.CertReleasedCount,
+		metricsstore.DefaultMetricsStore.CertExpiringCount,
+		metricsstore.DefaultMetricsStore.CertProviderErrorCount,
+		metricsstore.DefaultMetricsStore.ControllerGoroutineCount,
 		metricsstore.DefaultMetricsStore.ErrCodeCounter,
 	)
 }
@@ -324,6 +670,45 @@ func joinURL(baseURL string, paths ...string) string {
 	return fmt.Sprintf("%s/%s", strings.TrimRight(baseURL, "/"), strings.TrimLeft(p, "/"))
 }
 
+// getEventRecorderObject returns the object the generic event recorder associates events with. This is the
+// osm-controller pod when running within the cluster, and the MeshConfig when running outside the cluster.
+func getEventRecorderObject(kubeClient kubernetes.Interface) (runtime.Object, error) {
+	if kubeConfigFile != "" && os.Getenv("CONTROLLER_POD_NAME") == "" {
+		return &corev1.ObjectReference{
+			APIVersion: configv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "MeshConfig",
+			Namespace:  osmNamespace,
+			Name:       osmMeshConfigName,
+		}, nil
+	}
+
+	return getOSMControllerPod(kubeClient)
+}
+
+// getLeaderElectionIdentity returns the identity of the controller in the leader election, its pod name when known
+func getLeaderElectionIdentity() string {
+	if podName := os.Getenv("CONTROLLER_POD_NAME"); podName != "" {
+		return podName
+	}
+	hostname, _ := os.Hostname()
+	return hostname
+}
+
+// getXDSAddress returns the host and port at which proxies reach the xDS server
+func getXDSAddress() (string, uint32) {
+	host := xdsHost
+	if host == "" {
+		host = fmt.Sprintf("%s.%s.svc.%s", constants.OSMControllerName, osmNamespace, identity.ClusterLocalTrustDomain)
+	}
+
+	port := xdsPort
+	if port == 0 {
+		port = constants.ADSServerPort
+	}
+
+	return host, port
+}
+
 // getOSMControllerPod returns the osm-controller pod.
 // The pod name is inferred from the 'CONTROLLER_POD_NAME' env variable which is set during deployment.
 func getOSMControllerPod(kubeClient kubernetes.Interface) (*corev1.Pod, error) {
@@ -342,3 +727,32 @@ func getOSMControllerPod(kubeClient kubernetes.Interface) (*corev1.Pod, error) {
 
 	return pod, nil
 }
+
+// getComplianceSnapshotSink returns the sink compliance snapshots are stored to.
+// Credentials for the S3 sink are read from the standard AWS environment variables.
+func getComplianceSnapshotSink(kubeClient kubernetes.Interface) (compliance.Sink, error) {
+	switch complianceSnapshotSink {
+	case compliance.SinkKindConfigMap:
+		return compliance.NewConfigMapSink(kubeClient, osmNamespace, compliance.DefaultMaxRetainedSnapshots), nil
+	case compliance.SinkKindS3:
+		complianceS3Config.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+		complianceS3Config.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		complianceS3Config.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+		return compliance.NewS3Sink(complianceS3Config)
+	default:
+		return nil, errors.Errorf("Invalid compliance snapshot sink %s", complianceSnapshotSink)
+	}
+}
+
+// getXDSSnapshotStore returns the store the last known good xDS snapshots are persisted to.
+// It returns an error if the store kind is invalid or the store cannot be created.
+func getXDSSnapshotStore(kubeClient kubernetes.Interface) (snapshotstore.Store, error) {
+	switch xdsSnapshotStore {
+	case snapshotstore.StoreKindConfigMap:
+		return snapshotstore.NewConfigMapStore(kubeClient, osmNamespace), nil
+	case snapshotstore.StoreKindFile:
+		return snapshotstore.NewFileStore(xdsSnapshotStoreDir)
+	default:
+		return nil, errors.Errorf("Invalid xDS snapshot store %s", xdsSnapshotStore)
+	}
+}
@@ -2,8 +2,13 @@ package main
 
 import (
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/openservicemesh/osm/pkg/certificate/providers"
+	"github.com/openservicemesh/osm/pkg/envoy/snapshotstore"
+	"github.com/openservicemesh/osm/pkg/validator"
+	"github.com/openservicemesh/osm/pkg/webhook"
 )
 
 // validateCLIParams contains all checks necessary that various permutations of the CLI flags are consistent
@@ -28,9 +33,65 @@ func validateCLIParams() error {
 		return errors.Errorf("Please specify the CA bundle secret name using --ca-bundle-secret-name")
 	}
 
+	if err := validateMulticlusterSyncOptions(); err != nil {
+		return errors.Errorf("Error validating multicluster sync options: %s", err)
+	}
+
+	if err := validateOutOfClusterOptions(); err != nil {
+		return errors.Errorf("Error validating out-of-cluster options: %s", err)
+	}
+
+	if err := validateInitialSyncPacingOptions(); err != nil {
+		return errors.Errorf("Error validating initial sync pacing options: %s", err)
+	}
+
+	if err := validateProxyRegistryGCOptions(); err != nil {
+		return errors.Errorf("Error validating proxy registry garbage collection options: %s", err)
+	}
+
+	if err := validateXDSSnapshotOptions(); err != nil {
+		return errors.Errorf("Error validating xDS snapshot options: %s", err)
+	}
+
+	if err := validateWatchScopeOptions(); err != nil {
+		return errors.Errorf("Error validating watch scope options: %s", err)
+	}
+
+	if err := validateNamespaceOffboardingOptions(); err != nil {
+		return errors.Errorf("Error validating namespace offboarding options: %s", err)
+	}
+
+	if err := validateCatalogRecordingOptions(); err != nil {
+		return errors.Errorf("Error validating catalog recording options: %s", err)
+	}
+
 	return nil
 }
 
+func validateMulticlusterSyncOptions() error {
+	if multiclusterBrokerKubeconfig == "" {
+		return nil
+	}
+
+	if multiclusterSyncerConfig.ClusterName == "" {
+		return errors.New("Please specify the cluster name using --cluster-name")
+	}
+
+	if multiclusterSyncerConfig.GatewayAddress == "" {
+		return errors.New("Please specify the multicluster gateway address using --multicluster-gateway-address")
+	}
+
+	return nil
+}
+
+func validateOutOfClusterOptions() error {
+	if validatorWebhookURL == "" {
+		return nil
+	}
+
+	return webhook.ValidateURL(validatorWebhookURL)
+}
+
 func validateCertificateManagerOptions() error {
 	switch providers.Kind(certProviderKind) {
 	case providers.TresorKind:
@@ -47,3 +108,80 @@ func validateCertificateManagerOptions() error {
 			certProviderKind, providers.ValidCertificateProviders)
 	}
 }
+
+func validateInitialSyncPacingOptions() error {
+	if initialSyncPacingConfig.Rate < 0 {
+		return errors.Errorf("Invalid initial sync rate %v, must not be negative", initialSyncPacingConfig.Rate)
+	}
+
+	if initialSyncPacingConfig.Rate > 0 && initialSyncPacingConfig.Burst < 1 {
+		return errors.Errorf("Invalid initial sync burst %d, must be at least 1", initialSyncPacingConfig.Burst)
+	}
+
+	if initialSyncPacingConfig.MaxJitter < 0 {
+		return errors.Errorf("Invalid initial sync max jitter %s, must not be negative", initialSyncPacingConfig.MaxJitter)
+	}
+
+	return nil
+}
+
+func validateProxyRegistryGCOptions() error {
+	if proxyRegistryGCConfig.TTL < 0 {
+		return errors.Errorf("Invalid proxy registry garbage collection TTL %s, must not be negative", proxyRegistryGCConfig.TTL)
+	}
+
+	if proxyRegistryGCConfig.TTL > 0 && proxyRegistryGCConfig.Interval <= 0 {
+		return errors.Errorf("Invalid proxy registry garbage collection interval %s, must be positive", proxyRegistryGCConfig.Interval)
+	}
+
+	return nil
+}
+
+func validateXDSSnapshotOptions() error {
+	switch xdsSnapshotStore {
+	case "", snapshotstore.StoreKindConfigMap:
+	case snapshotstore.StoreKindFile:
+		if xdsSnapshotStoreDir == "" {
+			return errors.New("Please specify the xDS snapshot directory using --xds-snapshot-store-dir")
+		}
+	default:
+		return errors.Errorf("Invalid xDS snapshot store %s, must be one of [%s %s]", xdsSnapshotStore, snapshotstore.StoreKindConfigMap, snapshotstore.StoreKindFile)
+	}
+
+	if xdsSnapshotWarmupPeriod < 0 {
+		return errors.Errorf("Invalid xDS snapshot warm-up period %s, must not be negative", xdsSnapshotWarmupPeriod)
+	}
+
+	return nil
+}
+
+func validateWatchScopeOptions() error {
+	for _, ns := range watchScope.Namespaces {
+		if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+			return errors.Errorf("Invalid namespace %s in --watch-namespaces: %v", ns, errs)
+		}
+	}
+
+	if _, err := labels.Parse(watchScope.LabelSelector); err != nil {
+		return errors.Errorf("Invalid label selector %s in --watch-label-selector: %s", watchScope.LabelSelector, err)
+	}
+
+	return nil
+}
+
+func validateNamespaceOffboardingOptions() error {
+	switch namespaceRemovalPolicy {
+	case validator.NamespaceRemovalPolicyWarn, validator.NamespaceRemovalPolicyBlock:
+		return nil
+	default:
+		return errors.Errorf("Invalid namespace removal policy %s, must be one of [%s %s]", namespaceRemovalPolicy, validator.NamespaceRemovalPolicyWarn, validator.NamespaceRemovalPolicyBlock)
+	}
+}
+
+func validateCatalogRecordingOptions() error {
+	if catalogRecordingSnapshotInterval < 0 {
+		return errors.Errorf("Invalid catalog recording snapshot interval %s, must not be negative", catalogRecordingSnapshotInterval)
+	}
+
+	return nil
+}
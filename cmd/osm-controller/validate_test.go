@@ -1,10 +1,14 @@
 package main
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	"github.com/openservicemesh/osm/pkg/certificate/providers"
+	"github.com/openservicemesh/osm/pkg/envoy/registry"
+	"github.com/openservicemesh/osm/pkg/validator"
 )
 
 var _ = Describe("Test validateCertificateManagerOptions", func() {
@@ -158,3 +162,218 @@ var _ = Describe("Test validateCLIParams", func() {
 		})
 	})
 })
+
+var _ = Describe("Test validateMulticlusterSyncOptions", func() {
+	Context("multicluster broker kubeconfig is not set", func() {
+		multiclusterBrokerKubeconfig = ""
+		multiclusterSyncerConfig.ClusterName = ""
+
+		err := validateMulticlusterSyncOptions()
+
+		It("should not error", func() {
+			Expect(err).To(BeNil())
+		})
+	})
+	Context("multicluster broker kubeconfig is set without a cluster name", func() {
+		multiclusterBrokerKubeconfig = "/broker/kubeconfig"
+		multiclusterSyncerConfig.ClusterName = ""
+		multiclusterSyncerConfig.GatewayAddress = "1.2.3.4:15443"
+
+		err := validateMulticlusterSyncOptions()
+
+		It("should error", func() {
+			Expect(err).To(HaveOccurred())
+		})
+	})
+	Context("multicluster broker kubeconfig is set without a gateway address", func() {
+		multiclusterBrokerKubeconfig = "/broker/kubeconfig"
+		multiclusterSyncerConfig.ClusterName = "alpha"
+		multiclusterSyncerConfig.GatewayAddress = ""
+
+		err := validateMulticlusterSyncOptions()
+
+		It("should error", func() {
+			Expect(err).To(HaveOccurred())
+		})
+	})
+	Context("multicluster broker kubeconfig is set with a cluster name and gateway address", func() {
+		multiclusterBrokerKubeconfig = "/broker/kubeconfig"
+		multiclusterSyncerConfig.ClusterName = "alpha"
+		multiclusterSyncerConfig.GatewayAddress = "1.2.3.4:15443"
+
+		err := validateMulticlusterSyncOptions()
+
+		It("should not error", func() {
+			Expect(err).To(BeNil())
+		})
+
+		multiclusterBrokerKubeconfig = ""
+	})
+})
+
+var _ = Describe("Test validateOutOfClusterOptions", func() {
+	Context("validator webhook URL is not set", func() {
+		validatorWebhookURL = ""
+
+		err := validateOutOfClusterOptions()
+
+		It("should not error", func() {
+			Expect(err).To(BeNil())
+		})
+	})
+	Context("validator webhook URL is a valid https URL", func() {
+		validatorWebhookURL = "https://osm.example.com:9093"
+
+		err := validateOutOfClusterOptions()
+
+		It("should not error", func() {
+			Expect(err).To(BeNil())
+		})
+	})
+	Context("validator webhook URL is not an https URL", func() {
+		validatorWebhookURL = "http://osm.example.com:9093"
+
+		err := validateOutOfClusterOptions()
+
+		It("should error", func() {
+			Expect(err).To(HaveOccurred())
+		})
+	})
+	Context("validator webhook URL does not have a host", func() {
+		validatorWebhookURL = "https://"
+
+		err := validateOutOfClusterOptions()
+
+		It("should error", func() {
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("Test validateWatchScopeOptions", func() {
+	Context("watch scope is not set", func() {
+		watchScope.Namespaces = nil
+		watchScope.LabelSelector = ""
+
+		err := validateWatchScopeOptions()
+
+		It("should not error", func() {
+			Expect(err).To(BeNil())
+		})
+	})
+	Context("watch scope has valid namespaces and label selector", func() {
+		watchScope.Namespaces = []string{"tenant-a", "tenant-b"}
+		watchScope.LabelSelector = "mesh in (tenant-a)"
+
+		err := validateWatchScopeOptions()
+
+		It("should not error", func() {
+			Expect(err).To(BeNil())
+		})
+	})
+	Context("watch scope has an invalid namespace", func() {
+		watchScope.Namespaces = []string{"Tenant_A"}
+		watchScope.LabelSelector = ""
+
+		err := validateWatchScopeOptions()
+
+		It("should error", func() {
+			Expect(err).To(HaveOccurred())
+		})
+	})
+	Context("watch scope has an invalid label selector", func() {
+		watchScope.Namespaces = nil
+		watchScope.LabelSelector = "mesh in tenant-a"
+
+		err := validateWatchScopeOptions()
+
+		It("should error", func() {
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("Test validateProxyRegistryGCOptions", func() {
+	Context("garbage collection is disabled", func() {
+		proxyRegistryGCConfig = registry.GCConfig{}
+
+		err := validateProxyRegistryGCOptions()
+
+		It("should not error", func() {
+			Expect(err).To(BeNil())
+		})
+	})
+	Context("garbage collection has a TTL and an interval", func() {
+		proxyRegistryGCConfig = registry.GCConfig{TTL: time.Hour, Interval: time.Minute}
+
+		err := validateProxyRegistryGCOptions()
+
+		It("should not error", func() {
+			Expect(err).To(BeNil())
+		})
+	})
+	Context("garbage collection has a negative TTL", func() {
+		proxyRegistryGCConfig = registry.GCConfig{TTL: -time.Hour, Interval: time.Minute}
+
+		err := validateProxyRegistryGCOptions()
+
+		It("should error", func() {
+			Expect(err).To(HaveOccurred())
+		})
+	})
+	Context("garbage collection has a TTL without an interval", func() {
+		proxyRegistryGCConfig = registry.GCConfig{TTL: time.Hour}
+
+		err := validateProxyRegistryGCOptions()
+
+		It("should error", func() {
+			Expect(err).To(HaveOccurred())
+		})
+
+		proxyRegistryGCConfig = registry.GCConfig{}
+	})
+})
+
+var _ = Describe("Test validateNamespaceOffboardingOptions", func() {
+	Context("namespace removal policy is block", func() {
+		namespaceRemovalPolicy = validator.NamespaceRemovalPolicyBlock
+
+		err := validateNamespaceOffboardingOptions()
+
+		It("should not error", func() {
+			Expect(err).To(BeNil())
+		})
+	})
+	Context("namespace removal policy is invalid", func() {
+		namespaceRemovalPolicy = "ignore"
+
+		err := validateNamespaceOffboardingOptions()
+
+		It("should error", func() {
+			Expect(err).To(HaveOccurred())
+		})
+
+		namespaceRemovalPolicy = validator.NamespaceRemovalPolicyWarn
+	})
+})
+
+var _ = Describe("Test validateCatalogRecordingOptions", func() {
+	Context("snapshots are only recorded on start and stop", func() {
+		catalogRecordingSnapshotInterval = 0
+
+		err := validateCatalogRecordingOptions()
+
+		It("should not error", func() {
+			Expect(err).To(BeNil())
+		})
+	})
+	Context("snapshot interval is negative", func() {
+		catalogRecordingSnapshotInterval = -time.Minute
+
+		err := validateCatalogRecordingOptions()
+
+		It("should error", func() {
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
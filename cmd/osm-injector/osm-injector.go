@@ -35,6 +35,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/metricsstore"
 	"github.com/openservicemesh/osm/pkg/signals"
 	"github.com/openservicemesh/osm/pkg/version"
+	"github.com/openservicemesh/osm/pkg/webhook"
 )
 
 var (
@@ -72,6 +73,16 @@ func init() {
 
 	// sidecar injector options
 	flags.IntVar(&injectorConfig.ListenPort, "webhook-port", constants.InjectorWebhookPort, "Webhook port for sidecar-injector")
+	flags.StringVar(&injectorConfig.XDSHost, "xds-host", "", "Host at which proxies reach osm-controller's xDS server, defaults to the osm-controller service. Required when osm-controller runs outside the cluster")
+	flags.Uint32Var(&injectorConfig.XDSPort, "xds-port", constants.ADSServerPort, "Port at which proxies reach osm-controller's xDS server")
+	flags.StringVar(&injectorConfig.WebhookURL, "webhook-url", "", "Base URL (https://host:port) at which the API server reaches the sidecar injector webhook when osm-injector runs outside the cluster")
+	flags.StringVar(&injectorConfig.BootstrapDelivery, "bootstrap-delivery", injector.BootstrapDeliverySecret, fmt.Sprintf("How the Envoy bootstrap config is delivered to sidecars, one of [%s %s]: a Secret per pod, or a volume rendered by an init container", injector.BootstrapDeliverySecret, injector.BootstrapDeliveryVolume))
+	flags.StringVar(&injectorConfig.Platform, "platform", injector.PlatformAuto, fmt.Sprintf("Platform of the cluster, one of [%s %s %s]: detected with the discovery client, Kubernetes, or OpenShift with SecurityContextConstraints", injector.PlatformAuto, injector.PlatformKubernetes, injector.PlatformOpenShift))
+	flags.StringVar(&injectorConfig.TrafficRedirection, "traffic-redirection", injector.TrafficRedirectionInitContainer, fmt.Sprintf("How the traffic of pods is redirected to their sidecar, one of [%s %s]: by an init container, or by a CNI plugin programming the rules of the pod's iptables rules annotation. Always a CNI plugin in namespaces enforcing the baseline or restricted Pod Security Standard", injector.TrafficRedirectionInitContainer, injector.TrafficRedirectionCNI))
+	flags.StringSliceVar(&injectorConfig.PodSecurityExemptions.Usernames, "pod-security-exempt-usernames", nil, "Users whose pod creations are exempted from Pod Security Admission on the API server")
+	flags.StringSliceVar(&injectorConfig.PodSecurityExemptions.RuntimeClasses, "pod-security-exempt-runtime-classes", nil, "Runtime classes of the pods exempted from Pod Security Admission on the API server")
+	flags.StringSliceVar(&injectorConfig.PodSecurityExemptions.Namespaces, "pod-security-exempt-namespaces", nil, "Namespaces exempted from Pod Security Admission on the API server")
+	flags.BoolVar(&injectorConfig.BootstrapSecretGCDryRun, "bootstrap-secret-gc-dry-run", false, "Log the orphaned Envoy bootstrap Secrets instead of deleting them")
 
 	// Generic certificate manager/provider options
 	flags.StringVar(&certProviderKind, "certificate-manager", providers.TresorKind.String(), fmt.Sprintf("Certificate manager, one of [%v]", providers.ValidCertificateProviders))
@@ -133,8 +144,15 @@ func main() {
 	metricsstore.DefaultMetricsStore.Start(
 		metricsstore.DefaultMetricsStore.InjectorRqTime,
 		metricsstore.DefaultMetricsStore.InjectorSidecarCount,
+		metricsstore.DefaultMetricsStore.InjectorBootstrapSecretOrphanedCount,
+		metricsstore.DefaultMetricsStore.InjectorBootstrapSecretReclaimedCount,
 		metricsstore.DefaultMetricsStore.CertIssuedCount,
 		metricsstore.DefaultMetricsStore.CertIssuedTime,
+		metricsstore.DefaultMetricsStore.CertProviderIssuedCount,
+		metricsstore.DefaultMetricsStore.CertRotatedCount,
+		metricsstore.DefaultMetricsStore.CertReleasedCount,
+		metricsstore.DefaultMetricsStore.CertExpiringCount,
+		metricsstore.DefaultMetricsStore.CertProviderErrorCount,
 		metricsstore.DefaultMetricsStore.ErrCodeCounter,
 	)
 
@@ -142,7 +160,7 @@ func main() {
 	cfg := configurator.NewConfigurator(configClientset.NewForConfigOrDie(kubeConfig), stop, osmNamespace, osmMeshConfigName)
 
 	// Initialize kubernetes.Controller to watch kubernetes resources
-	kubeController, err := k8s.NewKubernetesController(kubeClient, policyClient, meshName, stop, k8s.Namespaces)
+	kubeController, err := k8s.NewKubernetesController(kubeClient, policyClient, meshName, stop, k8s.Namespaces, k8s.ExternalWorkloads)
 	if err != nil {
 		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating Kubernetes Controller")
 	}
@@ -157,6 +175,12 @@ func main() {
 			"Error initializing certificate manager of kind %s", certProviderKind)
 	}
 
+	// Initialize the recorder of the events reporting why sidecars aren't injected into pods
+	injectorConfig.EventRecorder, err = events.NewObjectEventRecorder(kubeClient)
+	if err != nil {
+		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating Kubernetes event recorder for pods")
+	}
+
 	// Initialize the sidecar injector webhook
 	if err := injector.NewMutatingWebhook(injectorConfig, kubeClient, certManager, kubeController, meshName, osmNamespace, webhookConfigName, stop, cfg); err != nil {
 		events.GenericEventRecorder().FatalEvent(err, events.InitializationError, "Error creating sidecar injector webhook")
@@ -170,6 +194,8 @@ func main() {
 	httpServer.AddHandler("/metrics", metricsstore.DefaultMetricsStore.Handler())
 	// Version
 	httpServer.AddHandler("/version", version.GetVersionHandler())
+	// Recent errors
+	httpServer.AddHandler(constants.HTTPServerErrorsPath, errcode.DefaultErrorLog.GetErrorsHTTPHandler())
 	// Start HTTP server
 	err = httpServer.Start()
 	if err != nil {
@@ -226,9 +252,30 @@ func validateCLIParams() error {
 		return errors.Errorf("Please specify the mutatingwebhookconfiguration name using --webhook-config-name value")
 	}
 
+	if injectorConfig.WebhookURL != "" {
+		if err := webhook.ValidateURL(injectorConfig.WebhookURL); err != nil {
+			return errors.Errorf("Error validating the webhook URL set using --webhook-url: %s", err)
+		}
+	}
+
 	if caBundleSecretName == "" {
 		return errors.Errorf("Please specify the CA bundle secret name using --ca-bundle-secret-name")
 	}
 
+	if injectorConfig.BootstrapDelivery != injector.BootstrapDeliverySecret && injectorConfig.BootstrapDelivery != injector.BootstrapDeliveryVolume {
+		return errors.Errorf("Invalid bootstrap delivery %s set using --bootstrap-delivery, must be one of [%s %s]",
+			injectorConfig.BootstrapDelivery, injector.BootstrapDeliverySecret, injector.BootstrapDeliveryVolume)
+	}
+
+	if injectorConfig.Platform != injector.PlatformAuto && injectorConfig.Platform != injector.PlatformKubernetes && injectorConfig.Platform != injector.PlatformOpenShift {
+		return errors.Errorf("Invalid platform %s set using --platform, must be one of [%s %s %s]",
+			injectorConfig.Platform, injector.PlatformAuto, injector.PlatformKubernetes, injector.PlatformOpenShift)
+	}
+
+	if injectorConfig.TrafficRedirection != injector.TrafficRedirectionInitContainer && injectorConfig.TrafficRedirection != injector.TrafficRedirectionCNI {
+		return errors.Errorf("Invalid traffic redirection %s set using --traffic-redirection, must be one of [%s %s]",
+			injectorConfig.TrafficRedirection, injector.TrafficRedirectionInitContainer, injector.TrafficRedirectionCNI)
+	}
+
 	return nil
 }
@@ -72,7 +72,7 @@ function generate_client() {
 }
 
 echo "##### Generating config.openservicemesh.io client ######"
-generate_client "config" "v1alpha1"
+generate_client "config" "v1alpha1,v1alpha2"
 
 echo "##### Generating policy.openservicemesh.io client ######"
 generate_client "policy" "v1alpha1"
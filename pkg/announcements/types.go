@@ -22,6 +22,10 @@ const (
 	// ProxyBroadcast is used to notify all Proxy streams that they need to trigger an update
 	ProxyBroadcast AnnouncementType = "proxy-broadcast"
 
+	// ProxyResyncRequested is used to request the stream of a proxy lagging behind the config sent to it to push the
+	// lagging types again, its NewObj identifies the proxy and the types
+	ProxyResyncRequested AnnouncementType = "proxy-resync-requested"
+
 	// PodAdded is the type of announcement emitted when we observe an addition of a Kubernetes Pod
 	PodAdded AnnouncementType = "pod-added"
 
@@ -44,6 +48,28 @@ const (
 
 	// ---
 
+	// EndpointSliceAdded is the type of announcement emitted when we observe an addition of a Kubernetes EndpointSlice
+	EndpointSliceAdded AnnouncementType = "endpointslice-added"
+
+	// EndpointSliceDeleted the type of announcement emitted when we observe the deletion of a Kubernetes EndpointSlice
+	EndpointSliceDeleted AnnouncementType = "endpointslice-deleted"
+
+	// EndpointSliceUpdated is the type of announcement emitted when we observe an update to a Kubernetes EndpointSlice
+	EndpointSliceUpdated AnnouncementType = "endpointslice-updated"
+
+	// ---
+
+	// SecretAdded is the type of announcement emitted when we observe an addition of a Kubernetes TLS Secret
+	SecretAdded AnnouncementType = "secret-added"
+
+	// SecretDeleted the type of announcement emitted when we observe the deletion of a Kubernetes TLS Secret
+	SecretDeleted AnnouncementType = "secret-deleted"
+
+	// SecretUpdated is the type of announcement emitted when we observe an update to a Kubernetes TLS Secret
+	SecretUpdated AnnouncementType = "secret-updated"
+
+	// ---
+
 	// NamespaceAdded is the type of announcement emitted when we observe an addition of a Kubernetes Namespace
 	NamespaceAdded AnnouncementType = "namespace-added"
 
@@ -146,6 +172,95 @@ const (
 	// MeshConfigUpdated is the type of announcement emitted when we observe an update to a Kubernetes MeshConfig
 	MeshConfigUpdated AnnouncementType = "meshconfig-updated"
 
+	// --- MeshConfig field-level changes, emitted on MeshConfig updates alongside MeshConfigUpdated so that subsystems
+	// only react to the changes of the fields they depend on. Their OldObj and NewObj are the previous and new MeshConfigs.
+
+	// MeshConfigEgressChanged is the type of announcement emitted when mesh-wide Egress is enabled or disabled
+	MeshConfigEgressChanged AnnouncementType = "meshconfig-egress-changed"
+
+	// MeshConfigPermissiveTrafficPolicyModeChanged is the type of announcement emitted when permissive traffic policy mode is enabled or disabled
+	MeshConfigPermissiveTrafficPolicyModeChanged AnnouncementType = "meshconfig-permissive-traffic-policy-mode-changed"
+
+	// MeshConfigHTTPSIngressChanged is the type of announcement emitted when HTTPS ingress is enabled or disabled
+	MeshConfigHTTPSIngressChanged AnnouncementType = "meshconfig-https-ingress-changed"
+
+	// MeshConfigTerminatingEndpointsChanged is the type of announcement emitted when the inclusion of the endpoints of terminating pods changes
+	MeshConfigTerminatingEndpointsChanged AnnouncementType = "meshconfig-terminating-endpoints-changed"
+
+	// MeshConfigClientCertDetailsChanged is the type of announcement emitted when the forwarding of client certificate details changes
+	MeshConfigClientCertDetailsChanged AnnouncementType = "meshconfig-client-cert-details-changed"
+
+	// MeshConfigRBACAuditChanged is the type of announcement emitted when the audit of RBAC policies changes
+	MeshConfigRBACAuditChanged AnnouncementType = "meshconfig-rbac-audit-changed"
+
+	// MeshConfigTrustDomainsChanged is the type of announcement emitted when the trust domain aliases or federated trust domains change
+	MeshConfigTrustDomainsChanged AnnouncementType = "meshconfig-trust-domains-changed"
+
+	// MeshConfigTracingChanged is the type of announcement emitted when the tracing configuration changes
+	MeshConfigTracingChanged AnnouncementType = "meshconfig-tracing-changed"
+
+	// MeshConfigExternalAuthorizationChanged is the type of announcement emitted when the inbound external authorization configuration changes
+	MeshConfigExternalAuthorizationChanged AnnouncementType = "meshconfig-external-authorization-changed"
+
+	// MeshConfigIngressGatewayCertChanged is the type of announcement emitted when the ingress gateway certificate configuration changes
+	MeshConfigIngressGatewayCertChanged AnnouncementType = "meshconfig-ingress-gateway-cert-changed"
+
+	// MeshConfigNamespaceIsolationChanged is the type of announcement emitted when namespace isolation is enabled or disabled
+	MeshConfigNamespaceIsolationChanged AnnouncementType = "meshconfig-namespace-isolation-changed"
+
+	// MeshConfigOutboundPassthroughChanged is the type of announcement emitted when the destinations allowed through the outbound passthrough cluster change
+	MeshConfigOutboundPassthroughChanged AnnouncementType = "meshconfig-outbound-passthrough-changed"
+
+	// MeshConfigRouteRegexChanged is the type of announcement emitted when the options of the regular expressions of route matches change
+	MeshConfigRouteRegexChanged AnnouncementType = "meshconfig-route-regex-changed"
+
+	// MeshConfigHTTPSanitizationChanged is the type of announcement emitted when the normalization and sanitization of HTTP requests change
+	MeshConfigHTTPSanitizationChanged AnnouncementType = "meshconfig-http-sanitization-changed"
+
+	// MeshConfigDownstreamLimitsChanged is the type of announcement emitted when the limits on downstream connections and requests change
+	MeshConfigDownstreamLimitsChanged AnnouncementType = "meshconfig-downstream-limits-changed"
+
+	// MeshConfigIngressClientAddressChanged is the type of announcement emitted when the determination of the address of ingress clients changes
+	MeshConfigIngressClientAddressChanged AnnouncementType = "meshconfig-ingress-client-address-changed"
+
+	// MeshConfigPrometheusScrapingChanged is the type of announcement emitted when the scraping of the metrics by Prometheus changes
+	MeshConfigPrometheusScrapingChanged AnnouncementType = "meshconfig-prometheus-scraping-changed"
+
+	// MeshConfigProvisioningChanged is the type of announcement emitted when the dashboards and alert rules provisioned by the controller change
+	MeshConfigProvisioningChanged AnnouncementType = "meshconfig-provisioning-changed"
+
+	// MeshConfigEndpointFlapDampeningChanged is the type of announcement emitted when the dampening of flapping endpoints changes
+	MeshConfigEndpointFlapDampeningChanged AnnouncementType = "meshconfig-endpoint-flap-dampening-changed"
+
+	// MeshConfigDNSResolutionChanged is the type of announcement emitted when the DNS resolution of egress hosts changes
+	MeshConfigDNSResolutionChanged AnnouncementType = "meshconfig-dns-resolution-changed"
+
+	// MeshConfigProtocolDetectionChanged is the type of announcement emitted when the namespaces or the timeout of protocol detection change
+	MeshConfigProtocolDetectionChanged AnnouncementType = "meshconfig-protocol-detection-changed"
+
+	// MeshConfigCompressionChanged is the type of announcement emitted when the compression of HTTP responses changes
+	MeshConfigCompressionChanged AnnouncementType = "meshconfig-compression-changed"
+
+	// MeshConfigRequestLimitsChanged is the type of announcement emitted when the limits on the size of HTTP requests change
+	MeshConfigRequestLimitsChanged AnnouncementType = "meshconfig-request-limits-changed"
+
+	// MeshConfigIngressHTTP3Changed is the type of announcement emitted when HTTP/3 ingress is enabled or disabled
+	MeshConfigIngressHTTP3Changed AnnouncementType = "meshconfig-ingress-http3-changed"
+
+	// MeshConfigDNSProxyChanged is the type of announcement emitted when the DNS proxy of sidecars is enabled or disabled
+	MeshConfigDNSProxyChanged AnnouncementType = "meshconfig-dns-proxy-changed"
+
+	// MeshConfigScopedRoutesChanged is the type of announcement emitted when scoped routes are enabled or disabled
+	MeshConfigScopedRoutesChanged AnnouncementType = "meshconfig-scoped-routes-changed"
+
+	// MeshConfigFeatureFlagsChanged is the type of announcement emitted when the feature flags affecting the proxy config
+	// that have no announcement of their own change
+	MeshConfigFeatureFlagsChanged AnnouncementType = "meshconfig-feature-flags-changed"
+
+	// MeshConfigDrainStrategyChanged is the type of announcement emitted when the drain strategy of sidecars changes. The
+	// strategy applies to the sidecars injected after the change.
+	MeshConfigDrainStrategyChanged AnnouncementType = "meshconfig-drain-strategy-changed"
+
 	// --- policy.openservicemesh.io API events
 
 	// EgressAdded is the type of announcement emitted when we observe an addition of egresses.policy.openservicemesh.io
@@ -166,6 +281,51 @@ const (
 	// IngressBackendUpdated is the type of announcement emitted when we observe an update to ingressbackends.policy.openservicemesh.io
 	IngressBackendUpdated AnnouncementType = "ingressbackend-updated"
 
+	// ExternalWorkloadAdded is the type of announcement emitted when we observe an addition of externalworkloads.policy.openservicemesh.io
+	ExternalWorkloadAdded AnnouncementType = "externalworkload-added"
+
+	// ExternalWorkloadDeleted the type of announcement emitted when we observe a deletion of externalworkloads.policy.openservicemesh.io
+	ExternalWorkloadDeleted AnnouncementType = "externalworkload-deleted"
+
+	// ExternalWorkloadUpdated is the type of announcement emitted when we observe an update to externalworkloads.policy.openservicemesh.io
+	ExternalWorkloadUpdated AnnouncementType = "externalworkload-updated"
+
+	// ProgressiveDeliveryAdded is the type of announcement emitted when we observe an addition of progressivedeliveries.policy.openservicemesh.io
+	ProgressiveDeliveryAdded AnnouncementType = "progressivedelivery-added"
+
+	// ProgressiveDeliveryDeleted the type of announcement emitted when we observe a deletion of progressivedeliveries.policy.openservicemesh.io
+	ProgressiveDeliveryDeleted AnnouncementType = "progressivedelivery-deleted"
+
+	// ProgressiveDeliveryUpdated is the type of announcement emitted when we observe an update to progressivedeliveries.policy.openservicemesh.io
+	ProgressiveDeliveryUpdated AnnouncementType = "progressivedelivery-updated"
+
+	// UpstreamTrafficSettingAdded is the type of announcement emitted when we observe an addition of upstreamtrafficsettings.policy.openservicemesh.io
+	UpstreamTrafficSettingAdded AnnouncementType = "upstreamtrafficsetting-added"
+
+	// UpstreamTrafficSettingDeleted the type of announcement emitted when we observe a deletion of upstreamtrafficsettings.policy.openservicemesh.io
+	UpstreamTrafficSettingDeleted AnnouncementType = "upstreamtrafficsetting-deleted"
+
+	// UpstreamTrafficSettingUpdated is the type of announcement emitted when we observe an update to upstreamtrafficsettings.policy.openservicemesh.io
+	UpstreamTrafficSettingUpdated AnnouncementType = "upstreamtrafficsetting-updated"
+
+	// NamespaceIsolationAdded is the type of announcement emitted when we observe an addition of namespaceisolations.policy.openservicemesh.io
+	NamespaceIsolationAdded AnnouncementType = "namespaceisolation-added"
+
+	// NamespaceIsolationDeleted the type of announcement emitted when we observe a deletion of namespaceisolations.policy.openservicemesh.io
+	NamespaceIsolationDeleted AnnouncementType = "namespaceisolation-deleted"
+
+	// NamespaceIsolationUpdated is the type of announcement emitted when we observe an update to namespaceisolations.policy.openservicemesh.io
+	NamespaceIsolationUpdated AnnouncementType = "namespaceisolation-updated"
+
+	// NamespaceOffboardingAdded is the type of announcement emitted when we observe an addition of namespaceoffboardings.policy.openservicemesh.io
+	NamespaceOffboardingAdded AnnouncementType = "namespaceoffboarding-added"
+
+	// NamespaceOffboardingDeleted the type of announcement emitted when we observe a deletion of namespaceoffboardings.policy.openservicemesh.io
+	NamespaceOffboardingDeleted AnnouncementType = "namespaceoffboarding-deleted"
+
+	// NamespaceOffboardingUpdated is the type of announcement emitted when we observe an update to namespaceoffboardings.policy.openservicemesh.io
+	NamespaceOffboardingUpdated AnnouncementType = "namespaceoffboarding-updated"
+
 	// ---
 
 	// MultiClusterServiceAdded is the type of announcement emitted when we observe an addition of a multiclusterservice.config.openservicemesh.io
@@ -47,6 +47,27 @@ type ClusterSpec struct {
 
 	// Name defines the name of the remote cluster.
 	Name string `json:"name,omitempty"`
+
+	// TrustDomain defines the trust domain of the remote cluster. When set, the multicluster gateway
+	// validates the certificates of downstreams from the remote cluster against the CA bundle of this
+	// trust domain instead of passing their TLS connections through.
+	// +optional
+	TrustDomain string `json:"trustDomain,omitempty"`
+
+	// Health defines the health of the service's endpoints in the remote cluster,
+	// as observed by the OSM control plane of the remote cluster.
+	// If unset, the remote cluster is assumed to be healthy.
+	// +optional
+	Health *ClusterHealth `json:"health,omitempty"`
+}
+
+// ClusterHealth is the type used to represent the health of a service's endpoints in a remote cluster.
+type ClusterHealth struct {
+	// HealthyEndpoints defines the number of healthy endpoints backing the service in the remote cluster.
+	HealthyEndpoints uint32 `json:"healthyEndpoints"`
+
+	// TotalEndpoints defines the total number of endpoints backing the service in the remote cluster.
+	TotalEndpoints uint32 `json:"totalEndpoints"`
 }
 
 // PortSpec contains information on service's port.
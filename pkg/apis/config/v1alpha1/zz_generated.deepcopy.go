@@ -21,9 +21,37 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdminInterfaceSpec) DeepCopyInto(out *AdminInterfaceSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdminInterfaceSpec.
+func (in *AdminInterfaceSpec) DeepCopy() *AdminInterfaceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AdminInterfaceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertificateSpec) DeepCopyInto(out *CertificateSpec) {
 	*out = *in
+	if in.TrustDomainAliases != nil {
+		in, out := &in.TrustDomainAliases, &out.TrustDomainAliases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FederatedTrustDomains != nil {
+		in, out := &in.FederatedTrustDomains, &out.FederatedTrustDomains
+		*out = make([]FederatedTrustDomainSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.IngressGateway != nil {
 		in, out := &in.IngressGateway, &out.IngressGateway
 		*out = new(IngressGatewayCertSpec)
@@ -42,9 +70,47 @@ func (in *CertificateSpec) DeepCopy() *CertificateSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientCertDetailsSpec) DeepCopyInto(out *ClientCertDetailsSpec) {
+	*out = *in
+	out.SetCurrentClientCertDetails = in.SetCurrentClientCertDetails
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertDetailsSpec.
+func (in *ClientCertDetailsSpec) DeepCopy() *ClientCertDetailsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientCertDetailsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHealth) DeepCopyInto(out *ClusterHealth) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHealth.
+func (in *ClusterHealth) DeepCopy() *ClusterHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 	*out = *in
+	if in.Health != nil {
+		in, out := &in.Health, &out.Health
+		*out = new(ClusterHealth)
+		**out = **in
+	}
 	return
 }
 
@@ -58,6 +124,80 @@ func (in *ClusterSpec) DeepCopy() *ClusterSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompressionSpec) DeepCopyInto(out *CompressionSpec) {
+	*out = *in
+	if in.Algorithms != nil {
+		in, out := &in.Algorithms, &out.Algorithms
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ContentTypes != nil {
+		in, out := &in.ContentTypes, &out.ContentTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompressionSpec.
+func (in *CompressionSpec) DeepCopy() *CompressionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CompressionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSResolutionSpec) DeepCopyInto(out *DNSResolutionSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSResolutionSpec.
+func (in *DNSResolutionSpec) DeepCopy() *DNSResolutionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSResolutionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DownstreamLimitsSpec) DeepCopyInto(out *DownstreamLimitsSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DownstreamLimitsSpec.
+func (in *DownstreamLimitsSpec) DeepCopy() *DownstreamLimitsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DownstreamLimitsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndpointFlapDampeningSpec) DeepCopyInto(out *EndpointFlapDampeningSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointFlapDampeningSpec.
+func (in *EndpointFlapDampeningSpec) DeepCopy() *EndpointFlapDampeningSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointFlapDampeningSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalAuthzSpec) DeepCopyInto(out *ExternalAuthzSpec) {
 	*out = *in
@@ -90,6 +230,76 @@ func (in *FeatureFlags) DeepCopy() *FeatureFlags {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedIdentityMappingSpec) DeepCopyInto(out *FederatedIdentityMappingSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedIdentityMappingSpec.
+func (in *FederatedIdentityMappingSpec) DeepCopy() *FederatedIdentityMappingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedIdentityMappingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedTrustDomainSpec) DeepCopyInto(out *FederatedTrustDomainSpec) {
+	*out = *in
+	if in.IdentityMappings != nil {
+		in, out := &in.IdentityMappings, &out.IdentityMappings
+		*out = make([]FederatedIdentityMappingSpec, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedTrustDomainSpec.
+func (in *FederatedTrustDomainSpec) DeepCopy() *FederatedTrustDomainSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedTrustDomainSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPSanitizationSpec) DeepCopyInto(out *HTTPSanitizationSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPSanitizationSpec.
+func (in *HTTPSanitizationSpec) DeepCopy() *HTTPSanitizationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPSanitizationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressClientAddressSpec) DeepCopyInto(out *IngressClientAddressSpec) {
+	*out = *in
+	out.InternalAddressConfig = in.InternalAddressConfig
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressClientAddressSpec.
+func (in *IngressClientAddressSpec) DeepCopy() *IngressClientAddressSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressClientAddressSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IngressGatewayCertSpec) DeepCopyInto(out *IngressGatewayCertSpec) {
 	*out = *in
@@ -112,6 +322,38 @@ func (in *IngressGatewayCertSpec) DeepCopy() *IngressGatewayCertSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternalAddressConfigSpec) DeepCopyInto(out *InternalAddressConfigSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalAddressConfigSpec.
+func (in *InternalAddressConfigSpec) DeepCopy() *InternalAddressConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InternalAddressConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryProfilingSpec) DeepCopyInto(out *MemoryProfilingSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryProfilingSpec.
+func (in *MemoryProfilingSpec) DeepCopy() *MemoryProfilingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryProfilingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MeshConfig) DeepCopyInto(out *MeshConfig) {
 	*out = *in
@@ -177,9 +419,10 @@ func (in *MeshConfigSpec) DeepCopyInto(out *MeshConfigSpec) {
 	*out = *in
 	in.Sidecar.DeepCopyInto(&out.Sidecar)
 	in.Traffic.DeepCopyInto(&out.Traffic)
-	out.Observability = in.Observability
+	in.Observability.DeepCopyInto(&out.Observability)
 	in.Certificate.DeepCopyInto(&out.Certificate)
 	out.FeatureFlags = in.FeatureFlags
+	in.Performance.DeepCopyInto(&out.Performance)
 	return
 }
 
@@ -259,7 +502,9 @@ func (in *MultiClusterServiceSpec) DeepCopyInto(out *MultiClusterServiceSpec) {
 	if in.Clusters != nil {
 		in, out := &in.Clusters, &out.Clusters
 		*out = make([]ClusterSpec, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Ports != nil {
 		in, out := &in.Ports, &out.Ports
@@ -279,10 +524,30 @@ func (in *MultiClusterServiceSpec) DeepCopy() *MultiClusterServiceSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceIsolationSpec) DeepCopyInto(out *NamespaceIsolationSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceIsolationSpec.
+func (in *NamespaceIsolationSpec) DeepCopy() *NamespaceIsolationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceIsolationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObservabilitySpec) DeepCopyInto(out *ObservabilitySpec) {
 	*out = *in
 	out.Tracing = in.Tracing
+	in.Stats.DeepCopyInto(&out.Stats)
+	out.MemoryProfiling = in.MemoryProfiling
+	out.PrometheusScraping = in.PrometheusScraping
+	out.Provisioning = in.Provisioning
 	return
 }
 
@@ -296,6 +561,48 @@ func (in *ObservabilitySpec) DeepCopy() *ObservabilitySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutboundPassthroughSpec) DeepCopyInto(out *OutboundPassthroughSpec) {
+	*out = *in
+	if in.AllowedPorts != nil {
+		in, out := &in.AllowedPorts, &out.AllowedPorts
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedIPRanges != nil {
+		in, out := &in.AllowedIPRanges, &out.AllowedIPRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutboundPassthroughSpec.
+func (in *OutboundPassthroughSpec) DeepCopy() *OutboundPassthroughSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OutboundPassthroughSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OverloadManagerSpec) DeepCopyInto(out *OverloadManagerSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OverloadManagerSpec.
+func (in *OverloadManagerSpec) DeepCopy() *OverloadManagerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OverloadManagerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PortSpec) DeepCopyInto(out *PortSpec) {
 	*out = *in
@@ -312,10 +619,131 @@ func (in *PortSpec) DeepCopy() *PortSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PerformanceSpec) DeepCopyInto(out *PerformanceSpec) {
+	*out = *in
+	if in.InformerResyncIntervals != nil {
+		in, out := &in.InformerResyncIntervals, &out.InformerResyncIntervals
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PerformanceSpec.
+func (in *PerformanceSpec) DeepCopy() *PerformanceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PerformanceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusScrapingSpec) DeepCopyInto(out *PrometheusScrapingSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusScrapingSpec.
+func (in *PrometheusScrapingSpec) DeepCopy() *PrometheusScrapingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusScrapingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvisioningSpec) DeepCopyInto(out *ProvisioningSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisioningSpec.
+func (in *ProvisioningSpec) DeepCopy() *ProvisioningSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvisioningSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBACAuditSpec) DeepCopyInto(out *RBACAuditSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBACAuditSpec.
+func (in *RBACAuditSpec) DeepCopy() *RBACAuditSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RBACAuditSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequestLimitsSpec) DeepCopyInto(out *RequestLimitsSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequestLimitsSpec.
+func (in *RequestLimitsSpec) DeepCopy() *RequestLimitsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RequestLimitsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteRegexSpec) DeepCopyInto(out *RouteRegexSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteRegexSpec.
+func (in *RouteRegexSpec) DeepCopy() *RouteRegexSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteRegexSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SetCurrentClientCertDetailsSpec) DeepCopyInto(out *SetCurrentClientCertDetailsSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SetCurrentClientCertDetailsSpec.
+func (in *SetCurrentClientCertDetailsSpec) DeepCopy() *SetCurrentClientCertDetailsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SetCurrentClientCertDetailsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SidecarSpec) DeepCopyInto(out *SidecarSpec) {
 	*out = *in
 	in.Resources.DeepCopyInto(&out.Resources)
+	out.AdminInterface = in.AdminInterface
+	out.OverloadManager = in.OverloadManager
 	return
 }
 
@@ -329,6 +757,53 @@ func (in *SidecarSpec) DeepCopy() *SidecarSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatsSpec) DeepCopyInto(out *StatsSpec) {
+	*out = *in
+	if in.InclusionRegexes != nil {
+		in, out := &in.InclusionRegexes, &out.InclusionRegexes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExclusionRegexes != nil {
+		in, out := &in.ExclusionRegexes, &out.ExclusionRegexes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]StatsTagSpec, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatsSpec.
+func (in *StatsSpec) DeepCopy() *StatsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StatsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatsTagSpec) DeepCopyInto(out *StatsTagSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatsTagSpec.
+func (in *StatsTagSpec) DeepCopy() *StatsTagSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StatsTagSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TracingSpec) DeepCopyInto(out *TracingSpec) {
 	*out = *in
@@ -364,6 +839,23 @@ func (in *TrafficSpec) DeepCopyInto(out *TrafficSpec) {
 		copy(*out, *in)
 	}
 	out.InboundExternalAuthorization = in.InboundExternalAuthorization
+	out.EndpointFlapDampening = in.EndpointFlapDampening
+	out.DNSResolution = in.DNSResolution
+	if in.ProtocolDetectionNamespaces != nil {
+		in, out := &in.ProtocolDetectionNamespaces, &out.ProtocolDetectionNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Compression.DeepCopyInto(&out.Compression)
+	out.RequestLimits = in.RequestLimits
+	out.ClientCertDetails = in.ClientCertDetails
+	out.RBACAudit = in.RBACAudit
+	out.NamespaceIsolation = in.NamespaceIsolation
+	in.OutboundPassthrough.DeepCopyInto(&out.OutboundPassthrough)
+	out.RouteRegex = in.RouteRegex
+	out.HTTPSanitization = in.HTTPSanitization
+	out.DownstreamLimits = in.DownstreamLimits
+	out.IngressClientAddress = in.IngressClientAddress
 	return
 }
 
@@ -0,0 +1,279 @@
+package v1alpha2
+
+import (
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+)
+
+// The conversions between v1alpha1 and v1alpha2 are lossless: every field of one version is represented in the other,
+// so that objects round trip through either version. Structs whose fields are identical in both versions are converted
+// with Go type conversions, which fail to compile once the structs diverge.
+
+// ConvertFromV1alpha1 converts a v1alpha1 MeshConfig to v1alpha2.
+func ConvertFromV1alpha1(in *v1alpha1.MeshConfig) *MeshConfig {
+	in = in.DeepCopy()
+	out := &MeshConfig{
+		TypeMeta:   in.TypeMeta,
+		ObjectMeta: in.ObjectMeta,
+		Spec: MeshConfigSpec{
+			Sidecar:       convertSidecarFromV1alpha1(in.Spec.Sidecar),
+			Traffic:       convertTrafficFromV1alpha1(in.Spec.Traffic),
+			Observability: convertObservabilityFromV1alpha1(in.Spec.Observability),
+			Certificate:   convertCertificateFromV1alpha1(in.Spec.Certificate),
+			FeatureFlags:  FeatureFlags(in.Spec.FeatureFlags),
+			Performance:   PerformanceSpec(in.Spec.Performance),
+		},
+	}
+	if out.APIVersion != "" {
+		out.APIVersion = SchemeGroupVersion.String()
+	}
+	return out
+}
+
+// ConvertToV1alpha1 converts a v1alpha2 MeshConfig to v1alpha1.
+func ConvertToV1alpha1(in *MeshConfig) *v1alpha1.MeshConfig {
+	in = in.DeepCopy()
+	out := &v1alpha1.MeshConfig{
+		TypeMeta:   in.TypeMeta,
+		ObjectMeta: in.ObjectMeta,
+		Spec: v1alpha1.MeshConfigSpec{
+			Sidecar:       convertSidecarToV1alpha1(in.Spec.Sidecar),
+			Traffic:       convertTrafficToV1alpha1(in.Spec.Traffic),
+			Observability: convertObservabilityToV1alpha1(in.Spec.Observability),
+			Certificate:   convertCertificateToV1alpha1(in.Spec.Certificate),
+			FeatureFlags:  v1alpha1.FeatureFlags(in.Spec.FeatureFlags),
+			Performance:   v1alpha1.PerformanceSpec(in.Spec.Performance),
+		},
+	}
+	if out.APIVersion != "" {
+		out.APIVersion = v1alpha1.SchemeGroupVersion.String()
+	}
+	return out
+}
+
+func convertSidecarFromV1alpha1(in v1alpha1.SidecarSpec) SidecarSpec {
+	return SidecarSpec{
+		EnablePrivilegedInitContainer: in.EnablePrivilegedInitContainer,
+		LogLevel:                      in.LogLevel,
+		EnvoyImage:                    in.EnvoyImage,
+		EnvoyWindowsImage:             in.EnvoyWindowsImage,
+		InitContainerImage:            in.InitContainerImage,
+		InitContainerDistroless:       in.InitContainerDistroless,
+		MaxDataPlaneConnections:       in.MaxDataPlaneConnections,
+		ConfigResyncInterval:          in.ConfigResyncInterval,
+		Resources:                     in.Resources,
+		AdminInterface:                AdminInterfaceSpec(in.AdminInterface),
+		OverloadManager:               OverloadManagerSpec(in.OverloadManager),
+		DrainStrategy:                 in.DrainStrategy,
+	}
+}
+
+func convertSidecarToV1alpha1(in SidecarSpec) v1alpha1.SidecarSpec {
+	return v1alpha1.SidecarSpec{
+		EnablePrivilegedInitContainer: in.EnablePrivilegedInitContainer,
+		LogLevel:                      in.LogLevel,
+		EnvoyImage:                    in.EnvoyImage,
+		EnvoyWindowsImage:             in.EnvoyWindowsImage,
+		InitContainerImage:            in.InitContainerImage,
+		InitContainerDistroless:       in.InitContainerDistroless,
+		MaxDataPlaneConnections:       in.MaxDataPlaneConnections,
+		ConfigResyncInterval:          in.ConfigResyncInterval,
+		Resources:                     in.Resources,
+		AdminInterface:                v1alpha1.AdminInterfaceSpec(in.AdminInterface),
+		OverloadManager:               v1alpha1.OverloadManagerSpec(in.OverloadManager),
+		DrainStrategy:                 in.DrainStrategy,
+	}
+}
+
+func convertTrafficFromV1alpha1(in v1alpha1.TrafficSpec) TrafficSpec {
+	return TrafficSpec{
+		EnableEgress:                      in.EnableEgress,
+		OutboundIPRangeExclusionList:      in.OutboundIPRangeExclusionList,
+		OutboundPortExclusionList:         in.OutboundPortExclusionList,
+		InboundPortExclusionList:          in.InboundPortExclusionList,
+		UseHTTPSIngress:                   in.UseHTTPSIngress,
+		EnablePermissiveTrafficPolicyMode: in.EnablePermissiveTrafficPolicyMode,
+		InboundExternalAuthorization:      ExternalAuthzSpec(in.InboundExternalAuthorization),
+		EndpointFlapDampening:             EndpointFlapDampeningSpec(in.EndpointFlapDampening),
+		DNSResolution:                     DNSResolutionSpec(in.DNSResolution),
+		ProtocolDetectionNamespaces:       in.ProtocolDetectionNamespaces,
+		ProtocolDetectionTimeout:          in.ProtocolDetectionTimeout,
+		InboundHTTP: InboundHTTPSpec{
+			Compression:   CompressionSpec(in.Compression),
+			RequestLimits: RequestLimitsSpec(in.RequestLimits),
+			ClientCertDetails: ClientCertDetailsSpec{
+				ForwardClientCertDetails:    in.ClientCertDetails.ForwardClientCertDetails,
+				SetCurrentClientCertDetails: SetCurrentClientCertDetailsSpec(in.ClientCertDetails.SetCurrentClientCertDetails),
+			},
+		},
+		IncludeTerminatingEndpoints: in.IncludeTerminatingEndpoints,
+		RBACAudit:                   RBACAuditSpec(in.RBACAudit),
+		NamespaceIsolation:          NamespaceIsolationSpec(in.NamespaceIsolation),
+		OutboundPassthrough:         OutboundPassthroughSpec(in.OutboundPassthrough),
+		RouteRegex: RouteRegexSpec{
+			MaxProgramSize: in.RouteRegex.MaxProgramSize,
+			PathAnchoring:  RoutePathAnchoring(in.RouteRegex.PathAnchoring),
+		},
+		HTTPSanitization: HTTPSanitizationSpec{
+			NormalizePath:                in.HTTPSanitization.NormalizePath,
+			MergeSlashes:                 in.HTTPSanitization.MergeSlashes,
+			PathWithEscapedSlashesAction: EscapedSlashesAction(in.HTTPSanitization.PathWithEscapedSlashesAction),
+			HeadersWithUnderscoresAction: HeadersWithUnderscoresAction(in.HTTPSanitization.HeadersWithUnderscoresAction),
+		},
+		DownstreamLimits: DownstreamLimitsSpec(in.DownstreamLimits),
+		IngressClientAddress: IngressClientAddressSpec{
+			UseRemoteAddress:      in.IngressClientAddress.UseRemoteAddress,
+			XFFNumTrustedHops:     in.IngressClientAddress.XFFNumTrustedHops,
+			InternalAddressConfig: InternalAddressConfigSpec(in.IngressClientAddress.InternalAddressConfig),
+		},
+	}
+}
+
+func convertTrafficToV1alpha1(in TrafficSpec) v1alpha1.TrafficSpec {
+	return v1alpha1.TrafficSpec{
+		EnableEgress:                      in.EnableEgress,
+		OutboundIPRangeExclusionList:      in.OutboundIPRangeExclusionList,
+		OutboundPortExclusionList:         in.OutboundPortExclusionList,
+		InboundPortExclusionList:          in.InboundPortExclusionList,
+		UseHTTPSIngress:                   in.UseHTTPSIngress,
+		EnablePermissiveTrafficPolicyMode: in.EnablePermissiveTrafficPolicyMode,
+		InboundExternalAuthorization:      v1alpha1.ExternalAuthzSpec(in.InboundExternalAuthorization),
+		EndpointFlapDampening:             v1alpha1.EndpointFlapDampeningSpec(in.EndpointFlapDampening),
+		DNSResolution:                     v1alpha1.DNSResolutionSpec(in.DNSResolution),
+		ProtocolDetectionNamespaces:       in.ProtocolDetectionNamespaces,
+		ProtocolDetectionTimeout:          in.ProtocolDetectionTimeout,
+		Compression:                       v1alpha1.CompressionSpec(in.InboundHTTP.Compression),
+		RequestLimits:                     v1alpha1.RequestLimitsSpec(in.InboundHTTP.RequestLimits),
+		IncludeTerminatingEndpoints:       in.IncludeTerminatingEndpoints,
+		ClientCertDetails: v1alpha1.ClientCertDetailsSpec{
+			ForwardClientCertDetails:    in.InboundHTTP.ClientCertDetails.ForwardClientCertDetails,
+			SetCurrentClientCertDetails: v1alpha1.SetCurrentClientCertDetailsSpec(in.InboundHTTP.ClientCertDetails.SetCurrentClientCertDetails),
+		},
+		RBACAudit:           v1alpha1.RBACAuditSpec(in.RBACAudit),
+		NamespaceIsolation:  v1alpha1.NamespaceIsolationSpec(in.NamespaceIsolation),
+		OutboundPassthrough: v1alpha1.OutboundPassthroughSpec(in.OutboundPassthrough),
+		RouteRegex: v1alpha1.RouteRegexSpec{
+			MaxProgramSize: in.RouteRegex.MaxProgramSize,
+			PathAnchoring:  v1alpha1.RoutePathAnchoring(in.RouteRegex.PathAnchoring),
+		},
+		HTTPSanitization: v1alpha1.HTTPSanitizationSpec{
+			NormalizePath:                in.HTTPSanitization.NormalizePath,
+			MergeSlashes:                 in.HTTPSanitization.MergeSlashes,
+			PathWithEscapedSlashesAction: v1alpha1.EscapedSlashesAction(in.HTTPSanitization.PathWithEscapedSlashesAction),
+			HeadersWithUnderscoresAction: v1alpha1.HeadersWithUnderscoresAction(in.HTTPSanitization.HeadersWithUnderscoresAction),
+		},
+		DownstreamLimits: v1alpha1.DownstreamLimitsSpec(in.DownstreamLimits),
+		IngressClientAddress: v1alpha1.IngressClientAddressSpec{
+			UseRemoteAddress:      in.IngressClientAddress.UseRemoteAddress,
+			XFFNumTrustedHops:     in.IngressClientAddress.XFFNumTrustedHops,
+			InternalAddressConfig: v1alpha1.InternalAddressConfigSpec(in.IngressClientAddress.InternalAddressConfig),
+		},
+	}
+}
+
+func convertObservabilityFromV1alpha1(in v1alpha1.ObservabilitySpec) ObservabilitySpec {
+	out := ObservabilitySpec{
+		OSMLogLevel:        in.OSMLogLevel,
+		EnableDebugServer:  in.EnableDebugServer,
+		Tracing:            TracingSpec(in.Tracing),
+		MemoryProfiling:    MemoryProfilingSpec(in.MemoryProfiling),
+		PrometheusScraping: PrometheusScrapingSpec(in.PrometheusScraping),
+		Provisioning:       ProvisioningSpec(in.Provisioning),
+		Stats: StatsSpec{
+			InclusionRegexes: in.Stats.InclusionRegexes,
+			ExclusionRegexes: in.Stats.ExclusionRegexes,
+		},
+	}
+	if in.Stats.Tags != nil {
+		out.Stats.Tags = make([]StatsTagSpec, 0, len(in.Stats.Tags))
+		for _, tag := range in.Stats.Tags {
+			out.Stats.Tags = append(out.Stats.Tags, StatsTagSpec(tag))
+		}
+	}
+	return out
+}
+
+func convertObservabilityToV1alpha1(in ObservabilitySpec) v1alpha1.ObservabilitySpec {
+	out := v1alpha1.ObservabilitySpec{
+		OSMLogLevel:        in.OSMLogLevel,
+		EnableDebugServer:  in.EnableDebugServer,
+		Tracing:            v1alpha1.TracingSpec(in.Tracing),
+		MemoryProfiling:    v1alpha1.MemoryProfilingSpec(in.MemoryProfiling),
+		PrometheusScraping: v1alpha1.PrometheusScrapingSpec(in.PrometheusScraping),
+		Provisioning:       v1alpha1.ProvisioningSpec(in.Provisioning),
+		Stats: v1alpha1.StatsSpec{
+			InclusionRegexes: in.Stats.InclusionRegexes,
+			ExclusionRegexes: in.Stats.ExclusionRegexes,
+		},
+	}
+	if in.Stats.Tags != nil {
+		out.Stats.Tags = make([]v1alpha1.StatsTagSpec, 0, len(in.Stats.Tags))
+		for _, tag := range in.Stats.Tags {
+			out.Stats.Tags = append(out.Stats.Tags, v1alpha1.StatsTagSpec(tag))
+		}
+	}
+	return out
+}
+
+func convertCertificateFromV1alpha1(in v1alpha1.CertificateSpec) CertificateSpec {
+	out := CertificateSpec{
+		ServiceCertValidityDuration: in.ServiceCertValidityDuration,
+		CertKeyBitSize:              in.CertKeyBitSize,
+		KeyAlgorithm:                in.KeyAlgorithm,
+		SubjectAltNameFormat:        in.SubjectAltNameFormat,
+		TrustDomain:                 in.TrustDomain,
+		TrustDomainAliases:          in.TrustDomainAliases,
+	}
+	if in.FederatedTrustDomains != nil {
+		out.FederatedTrustDomains = make([]FederatedTrustDomainSpec, 0, len(in.FederatedTrustDomains))
+		for _, federated := range in.FederatedTrustDomains {
+			converted := FederatedTrustDomainSpec{
+				TrustDomain: federated.TrustDomain,
+				TrustBundle: federated.TrustBundle,
+			}
+			if federated.IdentityMappings != nil {
+				converted.IdentityMappings = make([]FederatedIdentityMappingSpec, 0, len(federated.IdentityMappings))
+				for _, mapping := range federated.IdentityMappings {
+					converted.IdentityMappings = append(converted.IdentityMappings, FederatedIdentityMappingSpec(mapping))
+				}
+			}
+			out.FederatedTrustDomains = append(out.FederatedTrustDomains, converted)
+		}
+	}
+	if in.IngressGateway != nil {
+		ingressGateway := IngressGatewayCertSpec(*in.IngressGateway)
+		out.IngressGateway = &ingressGateway
+	}
+	return out
+}
+
+func convertCertificateToV1alpha1(in CertificateSpec) v1alpha1.CertificateSpec {
+	out := v1alpha1.CertificateSpec{
+		ServiceCertValidityDuration: in.ServiceCertValidityDuration,
+		CertKeyBitSize:              in.CertKeyBitSize,
+		KeyAlgorithm:                in.KeyAlgorithm,
+		SubjectAltNameFormat:        in.SubjectAltNameFormat,
+		TrustDomain:                 in.TrustDomain,
+		TrustDomainAliases:          in.TrustDomainAliases,
+	}
+	if in.FederatedTrustDomains != nil {
+		out.FederatedTrustDomains = make([]v1alpha1.FederatedTrustDomainSpec, 0, len(in.FederatedTrustDomains))
+		for _, federated := range in.FederatedTrustDomains {
+			converted := v1alpha1.FederatedTrustDomainSpec{
+				TrustDomain: federated.TrustDomain,
+				TrustBundle: federated.TrustBundle,
+			}
+			if federated.IdentityMappings != nil {
+				converted.IdentityMappings = make([]v1alpha1.FederatedIdentityMappingSpec, 0, len(federated.IdentityMappings))
+				for _, mapping := range federated.IdentityMappings {
+					converted.IdentityMappings = append(converted.IdentityMappings, v1alpha1.FederatedIdentityMappingSpec(mapping))
+				}
+			}
+			out.FederatedTrustDomains = append(out.FederatedTrustDomains, converted)
+		}
+	}
+	if in.IngressGateway != nil {
+		ingressGateway := v1alpha1.IngressGatewayCertSpec(*in.IngressGateway)
+		out.IngressGateway = &ingressGateway
+	}
+	return out
+}
@@ -0,0 +1,147 @@
+package v1alpha2
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+)
+
+const fuzzIterations = 1000
+
+var quantityType = reflect.TypeOf(resource.Quantity{})
+
+// fuzz fills v with random values. Slices, maps and pointers are randomly left nil so that the round trips also cover
+// the distinction between nil and empty values.
+func fuzz(v reflect.Value, r *rand.Rand) {
+	if v.Type() == quantityType {
+		v.Set(reflect.ValueOf(*resource.NewQuantity(r.Int63n(1<<20), resource.DecimalSI)))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(r.Intn(2) == 1)
+	case reflect.String:
+		v.SetString(fmt.Sprintf("s%d", r.Intn(1000)))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(r.Int63() >> (64 - v.Type().Bits()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(uint64(r.Int63() >> (64 - v.Type().Bits())))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(r.Float64())
+	case reflect.Ptr:
+		if r.Intn(4) == 0 {
+			return
+		}
+		v.Set(reflect.New(v.Type().Elem()))
+		fuzz(v.Elem(), r)
+	case reflect.Slice:
+		if r.Intn(4) == 0 {
+			return
+		}
+		n := r.Intn(4)
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+		for i := 0; i < n; i++ {
+			fuzz(v.Index(i), r)
+		}
+	case reflect.Map:
+		if r.Intn(4) == 0 {
+			return
+		}
+		v.Set(reflect.MakeMap(v.Type()))
+		for i := r.Intn(4); i > 0; i-- {
+			key := reflect.New(v.Type().Key()).Elem()
+			fuzz(key, r)
+			elem := reflect.New(v.Type().Elem()).Elem()
+			fuzz(elem, r)
+			v.SetMapIndex(key, elem)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				// unexported field
+				continue
+			}
+			fuzz(v.Field(i), r)
+		}
+	}
+}
+
+func TestRoundTripFromV1alpha1(t *testing.T) {
+	assert := tassert.New(t)
+	r := rand.New(rand.NewSource(1)) // #nosec G404
+
+	for i := 0; i < fuzzIterations; i++ {
+		original := &v1alpha1.MeshConfig{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "MeshConfig"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "osm-system", Name: "osm-mesh-config"},
+		}
+		fuzz(reflect.ValueOf(&original.Spec).Elem(), r)
+
+		converted := ConvertFromV1alpha1(original)
+		assert.Equal(SchemeGroupVersion.String(), converted.APIVersion)
+
+		roundTripped := ConvertToV1alpha1(converted)
+		if !assert.Equal(original, roundTripped) {
+			return
+		}
+	}
+}
+
+func TestRoundTripFromV1alpha2(t *testing.T) {
+	assert := tassert.New(t)
+	r := rand.New(rand.NewSource(1)) // #nosec G404
+
+	for i := 0; i < fuzzIterations; i++ {
+		original := &MeshConfig{
+			TypeMeta:   metav1.TypeMeta{APIVersion: SchemeGroupVersion.String(), Kind: "MeshConfig"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "osm-system", Name: "osm-mesh-config"},
+		}
+		fuzz(reflect.ValueOf(&original.Spec).Elem(), r)
+
+		converted := ConvertToV1alpha1(original)
+		assert.Equal(v1alpha1.SchemeGroupVersion.String(), converted.APIVersion)
+
+		roundTripped := ConvertFromV1alpha1(converted)
+		if !assert.Equal(original, roundTripped) {
+			return
+		}
+	}
+}
+
+func TestConvertFromV1alpha1(t *testing.T) {
+	assert := tassert.New(t)
+
+	original := &v1alpha1.MeshConfig{
+		Spec: v1alpha1.MeshConfigSpec{
+			Traffic: v1alpha1.TrafficSpec{
+				EnableEgress:  true,
+				Compression:   v1alpha1.CompressionSpec{Enable: true, Algorithms: []string{"gzip"}},
+				RequestLimits: v1alpha1.RequestLimitsSpec{MaxRequestBytes: 1024},
+				ClientCertDetails: v1alpha1.ClientCertDetailsSpec{
+					ForwardClientCertDetails: "SANITIZE_SET",
+				},
+			},
+		},
+	}
+
+	converted := ConvertFromV1alpha1(original)
+	assert.Empty(converted.APIVersion)
+	assert.True(converted.Spec.Traffic.EnableEgress)
+	assert.Equal(InboundHTTPSpec{
+		Compression:       CompressionSpec{Enable: true, Algorithms: []string{"gzip"}},
+		RequestLimits:     RequestLimitsSpec{MaxRequestBytes: 1024},
+		ClientCertDetails: ClientCertDetailsSpec{ForwardClientCertDetails: "SANITIZE_SET"},
+	}, converted.Spec.Traffic.InboundHTTP)
+
+	// The conversion does not share memory with the converted object
+	converted.Spec.Traffic.InboundHTTP.Compression.Algorithms[0] = "br"
+	assert.Equal("gzip", original.Spec.Traffic.Compression.Algorithms[0])
+}
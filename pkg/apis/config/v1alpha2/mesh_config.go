@@ -0,0 +1,875 @@
+package v1alpha2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MeshConfig is the type used to represent the mesh configuration.
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:storageversion
+type MeshConfig struct {
+	// Object's type metadata.
+	metav1.TypeMeta `json:",inline" yaml:",inline"`
+
+	// Object's metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	// Spec is the MeshConfig specification.
+	// +optional
+	Spec MeshConfigSpec `json:"spec,omitempty" yaml:"spec,omitempty"`
+}
+
+// MeshConfigSpec is the spec for OSM's configuration.
+type MeshConfigSpec struct {
+	// Sidecar defines the configurations of the proxy sidecar in a mesh.
+	Sidecar SidecarSpec `json:"sidecar,omitempty"`
+
+	// Traffic defines the traffic management configurations for a mesh instance.
+	Traffic TrafficSpec `json:"traffic,omitempty"`
+
+	// Observalility defines the observability configurations for a mesh instance.
+	Observability ObservabilitySpec `json:"observability,omitempty"`
+
+	// Certificate defines the certificate management configurations for a mesh instance.
+	Certificate CertificateSpec `json:"certificate,omitempty"`
+
+	// FeatureFlags defines the feature flags for a mesh instance.
+	FeatureFlags FeatureFlags `json:"featureFlags,omitempty"`
+
+	// Performance defines the performance profile of the control plane and the proxy sidecars for a mesh instance.
+	Performance PerformanceSpec `json:"performance,omitempty"`
+}
+
+// PerformanceSpec is the type used to represent the performance profile of the control plane and the proxy sidecars,
+// along with the overrides of the individual settings of the profile.
+// The connection limit of the profile is overridden by Sidecar.MaxDataPlaneConnections.
+type PerformanceSpec struct {
+	// Profile defines the name of the performance profile tuned for the size of the cluster: small, medium or large.
+	// The defaults of the control plane are used when no profile is set.
+	Profile string `json:"profile,omitempty"`
+
+	// BroadcastGracePeriod overrides the time the controller waits for additional config changes before updating the
+	// proxy sidecars, e.g. 3s.
+	BroadcastGracePeriod string `json:"broadcastGracePeriod,omitempty"`
+
+	// MaxBroadcastDelay overrides the maximum time the controller delays the update of the proxy sidecars while it
+	// coalesces config changes, e.g. 15s.
+	MaxBroadcastDelay string `json:"maxBroadcastDelay,omitempty"`
+
+	// WorkerPoolSize overrides the number of workers generating the config of the proxy sidecars.
+	WorkerPoolSize int `json:"workerPoolSize,omitempty"`
+
+	// InformerResyncInterval overrides the resync interval of the Kubernetes informers of the controller, e.g. 5m.
+	InformerResyncInterval string `json:"informerResyncInterval,omitempty"`
+
+	// InformerResyncIntervals overrides the resync interval of individual Kubernetes informers of the controller, keyed
+	// by the kind of resources they watch: Namespaces, Services, ServiceAccounts, Pods, Endpoints, EndpointSlices,
+	// Secrets or ExternalWorkloads, e.g. {"Pods": "1m"}.
+	InformerResyncIntervals map[string]string `json:"informerResyncIntervals,omitempty"`
+
+	// InformerWatchErrorBackoff sets the initial delay the Kubernetes informers of the controller wait before relisting
+	// their resources after a watch error, in addition to the backoff of the Kubernetes client, e.g. 1s. The delay
+	// doubles with each consecutive watch error, up to InformerWatchErrorMaxBackoff. No delay is added if unset.
+	InformerWatchErrorBackoff string `json:"informerWatchErrorBackoff,omitempty"`
+
+	// InformerWatchErrorMaxBackoff sets the maximum delay the Kubernetes informers of the controller wait before
+	// relisting their resources after consecutive watch errors, e.g. 5m.
+	InformerWatchErrorMaxBackoff string `json:"informerWatchErrorMaxBackoff,omitempty"`
+
+	// EnvoyConcurrency overrides the number of worker threads of the proxy sidecars.
+	EnvoyConcurrency int `json:"envoyConcurrency,omitempty"`
+}
+
+// SidecarSpec is the type used to represent the specifications for the proxy sidecar.
+type SidecarSpec struct {
+	// EnablePrivilegedInitContainer defines a boolean indicating whether the init container for a meshed pod should run as privileged.
+	EnablePrivilegedInitContainer bool `json:"enablePrivilegedInitContainer,omitempty"`
+
+	// LogLevel defines the  logging level for the sidecar's logs.
+	LogLevel string `json:"logLevel,omitempty"`
+
+	// EnvoyImage defines the container image used for the Envoy proxy sidecar.
+	EnvoyImage string `json:"envoyImage,omitempty"`
+
+	// EnvoyWindowsImage defines the windows container image used for the Envoy proxy sidecar.
+	EnvoyWindowsImage string `json:"envoyWindowsImage,omitempty"`
+
+	// InitContainerImage defines the container image used for the init container injected to meshed pods.
+	InitContainerImage string `json:"initContainerImage,omitempty"`
+
+	// InitContainerDistroless defines a boolean indicating whether the init container image is a distroless image
+	// without a shell, which only provides iptables-restore. The traffic interception rules are then passed to the init
+	// container through a file instead of shell commands. It is incompatible with source IP preservation and with the
+	// volume bootstrap delivery mode.
+	// +optional
+	InitContainerDistroless bool `json:"initContainerDistroless,omitempty"`
+
+	// MaxDataPlaneConnections defines the maximum allowed data plane connections from a proxy sidecar to the OSM controller.
+	MaxDataPlaneConnections int `json:"maxDataPlaneConnections,omitempty"`
+
+	// ConfigResyncInterval defines the resync interval for regular proxy broadcast updates.
+	ConfigResyncInterval string `json:"configResyncInterval,omitempty"`
+
+	// Resources defines the compute resources for the sidecar.
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// AdminInterface defines the exposure of the admin interface of the proxy sidecar.
+	// +optional
+	AdminInterface AdminInterfaceSpec `json:"adminInterface,omitempty"`
+
+	// OverloadManager defines the actions taken by the proxy sidecar when its memory usage grows too large.
+	// +optional
+	OverloadManager OverloadManagerSpec `json:"overloadManager,omitempty"`
+
+	// DrainStrategy defines how the proxy sidecar drains the connections of its listeners when they are modified or
+	// removed, one of gradual or immediate. With gradual, the share of connections encouraged to close increases over
+	// the drain period, and with immediate, all the connections are encouraged to close as soon as the drain starts.
+	// Defaults to gradual. It applies to pods injected after it is changed.
+	// +optional
+	DrainStrategy string `json:"drainStrategy,omitempty"`
+}
+
+// AdminInterfaceSpec is the type to represent the exposure of the admin interface of proxy sidecars. It applies to
+// pods injected after it is changed.
+type AdminInterfaceSpec struct {
+	// BindMode defines the address the admin interface binds to, one of localhost or uds. With uds, the admin
+	// interface binds to a unix domain socket only reachable by the proxy, and the allowed admin endpoints are served
+	// on the localhost admin port by a dedicated listener. Defaults to localhost.
+	// +optional
+	BindMode string `json:"bindMode,omitempty"`
+
+	// DisableDebugEndpoints defines a boolean indicating if the debug endpoints of the admin interface are disabled,
+	// in which case only the /stats/prometheus endpoint is served. Implies the uds bind mode.
+	// +optional
+	DisableDebugEndpoints bool `json:"disableDebugEndpoints,omitempty"`
+}
+
+// OverloadManagerSpec is the type to represent the configuration of the overload manager of proxy sidecars, which
+// takes actions when the heap of the proxy grows close to its maximum size. It applies to pods injected after it is
+// changed.
+type OverloadManagerSpec struct {
+	// MaxHeapSizeBytes defines the maximum size in bytes of the heap of the proxy sidecar. The overload manager is
+	// disabled if unset.
+	// +optional
+	MaxHeapSizeBytes uint64 `json:"maxHeapSizeBytes,omitempty"`
+
+	// ShrinkHeapThreshold defines the percentage of the maximum heap size above which the proxy sidecar releases its
+	// free memory to the system. Defaults to 95.
+	// +optional
+	ShrinkHeapThreshold uint32 `json:"shrinkHeapThreshold,omitempty"`
+
+	// StopAcceptingRequestsThreshold defines the percentage of the maximum heap size above which the proxy sidecar
+	// stops accepting requests, rejecting them with a 503 response. Defaults to 98.
+	// +optional
+	StopAcceptingRequestsThreshold uint32 `json:"stopAcceptingRequestsThreshold,omitempty"`
+}
+
+// TrafficSpec is the type used to represent OSM's traffic management configuration.
+type TrafficSpec struct {
+	// EnableEgress defines a boolean indicating if mesh-wide Egress is enabled.
+	EnableEgress bool `json:"enableEgress,omitempty"`
+
+	// OutboundIPRangeExclusionList defines a global list of IP address ranges to exclude from outbound traffic interception by the sidecar proxy.
+	OutboundIPRangeExclusionList []string `json:"outboundIPRangeExclusionList,omitempty"`
+
+	// OutboundPortExclusionList defines a global list of ports to exclude from outbound traffic interception by the sidecar proxy.
+	OutboundPortExclusionList []int `json:"outboundPortExclusionList,omitempty"`
+
+	// InboundPortExclusionList defines a global list of ports to exclude from inbound traffic interception by the sidecar proxy.
+	InboundPortExclusionList []int `json:"inboundPortExclusionList,omitempty"`
+
+	// UseHTTPSIngress defines a boolean indicating if HTTPS Ingress is enabled globally in the mesh.
+	UseHTTPSIngress bool `json:"useHTTPSIngress,omitempty"`
+
+	// EnablePermissiveTrafficPolicyMode defines a boolean indicating if permissive traffic policy mode is enabled mesh-wide.
+	EnablePermissiveTrafficPolicyMode bool `json:"enablePermissiveTrafficPolicyMode,omitempty"`
+
+	// InboundExternalAuthorization defines a ruleset that, if enabled, will configure a remote external authorization endpoint
+	// for all inbound and ingress traffic in the mesh.
+	InboundExternalAuthorization ExternalAuthzSpec `json:"inboundExternalAuthorization,omitempty"`
+
+	// EndpointFlapDampening defines the configuration used to pin out endpoints whose readiness flaps, if enabled.
+	EndpointFlapDampening EndpointFlapDampeningSpec `json:"endpointFlapDampening,omitempty"`
+
+	// DNSResolution defines how the addresses of clusters resolved using DNS, such as Egress hosts, are resolved by the proxies.
+	DNSResolution DNSResolutionSpec `json:"dnsResolution,omitempty"`
+
+	// ProtocolDetectionNamespaces defines the namespaces whose services' HTTP ports detect the application protocol of
+	// each connection, so that ports serving both HTTP and TCP traffic are proxied correctly.
+	// +optional
+	ProtocolDetectionNamespaces []string `json:"protocolDetectionNamespaces,omitempty"`
+
+	// ProtocolDetectionTimeout defines how long proxies wait for the first bytes of a connection to detect its
+	// application protocol, between 100ms and 1s. Connections detected on time out, such as server-first protocols,
+	// are proxied as TCP. Defaults to 250ms.
+	// +optional
+	ProtocolDetectionTimeout string `json:"protocolDetectionTimeout,omitempty"`
+
+	// InboundHTTP defines how the proxies of HTTP services handle inbound and ingress requests.
+	// +optional
+	InboundHTTP InboundHTTPSpec `json:"inboundHTTP,omitempty"`
+
+	// IncludeTerminatingEndpoints defines a boolean indicating if the endpoints of terminating pods that are still
+	// serving are included in the endpoints programmed on proxies, with a draining health status so that they only
+	// receive requests when too few other endpoints are healthy. Otherwise only ready endpoints are included.
+	// +optional
+	IncludeTerminatingEndpoints bool `json:"includeTerminatingEndpoints,omitempty"`
+
+	// RBACAudit defines how the authorization decisions of the RBAC policies enforcing TrafficTargets on inbound
+	// traffic are audited.
+	// +optional
+	RBACAudit RBACAuditSpec `json:"rbacAudit,omitempty"`
+
+	// NamespaceIsolation defines whether the workloads of a namespace may only initiate outbound connections to the
+	// services in their own namespace and the namespaces allowed by the NamespaceIsolation policies of their namespace,
+	// including in permissive traffic policy mode.
+	// +optional
+	NamespaceIsolation NamespaceIsolationSpec `json:"namespaceIsolation,omitempty"`
+
+	// OutboundPassthrough defines the destinations that may be reached through the outbound passthrough cluster
+	// when global egress is enabled.
+	// +optional
+	OutboundPassthrough OutboundPassthroughSpec `json:"outboundPassthrough,omitempty"`
+
+	// RouteRegex defines how the regular expressions of the route matches of HTTPRouteGroups are programmed on the
+	// proxies.
+	// +optional
+	RouteRegex RouteRegexSpec `json:"routeRegex,omitempty"`
+
+	// HTTPSanitization defines how the paths and headers of HTTP requests are normalized and sanitized by the proxies
+	// before their routes and RBAC policies are evaluated, for inbound, outbound, egress and ingress traffic.
+	// +optional
+	HTTPSanitization HTTPSanitizationSpec `json:"httpSanitization,omitempty"`
+
+	// DownstreamLimits defines the limits on the downstream connections and requests to services enforced by their
+	// proxies on inbound and ingress traffic, protecting services from clients holding connections and requests open
+	// to exhaust their resources. Services can override them using the openservicemesh.io/max-connections,
+	// openservicemesh.io/idle-timeout, openservicemesh.io/request-timeout and openservicemesh.io/stream-idle-timeout
+	// annotations.
+	// +optional
+	DownstreamLimits DownstreamLimitsSpec `json:"downstreamLimits,omitempty"`
+
+	// IngressClientAddress defines how the proxies of HTTP services determine the address of the clients of ingress
+	// requests, so that it is extracted correctly behind cloud load balancers and CDNs. IngressBackends can override
+	// it for their backends.
+	// +optional
+	IngressClientAddress IngressClientAddressSpec `json:"ingressClientAddress,omitempty"`
+}
+
+// InboundHTTPSpec is the type used to represent how the proxies of HTTP services handle inbound and ingress requests.
+type InboundHTTPSpec struct {
+	// Compression defines the compression of the responses of HTTP services by their proxies.
+	// +optional
+	Compression CompressionSpec `json:"compression,omitempty"`
+
+	// RequestLimits defines the limits on the size of the requests to HTTP services enforced by their proxies.
+	// +optional
+	RequestLimits RequestLimitsSpec `json:"requestLimits,omitempty"`
+
+	// ClientCertDetails defines how the details of the client certificates of mTLS connections are forwarded to
+	// applications in the x-forwarded-client-cert (XFCC) header of requests.
+	// +optional
+	ClientCertDetails ClientCertDetailsSpec `json:"clientCertDetails,omitempty"`
+}
+
+// ObservabilitySpec is the type to represent OSM's observability configurations.
+type ObservabilitySpec struct {
+	// OSMLogLevel defines the log level for OSM control plane logs.
+	OSMLogLevel string `json:"osmLogLevel,omitempty"`
+
+	// EnableDebugServer defines if the debug endpoint on the OSM controller pod is enabled.
+	EnableDebugServer bool `json:"enableDebugServer,omitempty"`
+
+	// Tracing defines OSM's tracing configuration.
+	Tracing TracingSpec `json:"tracing,omitempty"`
+
+	// Stats defines the stats generated by the proxy sidecars, to bound the cardinality of the metrics scraped from them.
+	// +optional
+	Stats StatsSpec `json:"stats,omitempty"`
+
+	// MemoryProfiling defines the memory profiling of the OSM controller, served by its debug server.
+	// +optional
+	MemoryProfiling MemoryProfilingSpec `json:"memoryProfiling,omitempty"`
+
+	// PrometheusScraping defines how the metrics of the proxy sidecars and the control plane are scraped by Prometheus.
+	// +optional
+	PrometheusScraping PrometheusScrapingSpec `json:"prometheusScraping,omitempty"`
+
+	// Provisioning defines the Grafana dashboards and Prometheus alert rules provisioned by the OSM controller.
+	// +optional
+	Provisioning ProvisioningSpec `json:"provisioning,omitempty"`
+}
+
+// ProvisioningSpec is the type to represent the Grafana dashboards and Prometheus alert rules provisioned as
+// ConfigMaps in the OSM namespace by the OSM controller, versioned with the controller.
+type ProvisioningSpec struct {
+	// EnableDashboards defines if the OSM Grafana dashboards are provisioned as a ConfigMap labelled to be loaded by the
+	// Grafana dashboard sidecar.
+	// +optional
+	EnableDashboards bool `json:"enableDashboards,omitempty"`
+
+	// EnableAlertRules defines if the OSM Prometheus alert rules are provisioned as a ConfigMap holding a Prometheus
+	// rule file.
+	// +optional
+	EnableAlertRules bool `json:"enableAlertRules,omitempty"`
+
+	// DashboardDatasource defines the name of the Grafana datasource queried by the provisioned dashboards. Defaults
+	// to Prometheus.
+	// +optional
+	DashboardDatasource string `json:"dashboardDatasource,omitempty"`
+}
+
+// PrometheusScrapingSpec is the type to represent how the metrics of the proxy sidecars and the control plane are
+// scraped by Prometheus.
+type PrometheusScrapingSpec struct {
+	// EnableMTLS defines if the metrics endpoint of the proxy sidecars is served over mTLS, only to clients presenting a
+	// certificate issued for the ClientIdentity. The certificate is stored in the ClientCertSecret.
+	// +optional
+	EnableMTLS bool `json:"enableMTLS,omitempty"`
+
+	// ClientIdentity defines the service identity, in the format <service-account>.<namespace>, of the Prometheus
+	// server allowed to scrape the metrics endpoint of the proxy sidecars over mTLS. Defaults to the osm-prometheus
+	// service account of the OSM namespace.
+	// +optional
+	ClientIdentity string `json:"clientIdentity,omitempty"`
+
+	// ClientCertSecret defines the name of the secret in the OSM namespace the certificate issued for the
+	// ClientIdentity is stored in, when EnableMTLS is set. Defaults to osm-prometheus-client-cert.
+	// +optional
+	ClientCertSecret string `json:"clientCertSecret,omitempty"`
+
+	// GenerateMonitors defines if PodMonitor and ServiceMonitor resources are generated in the OSM namespace for the
+	// proxy sidecars of the namespaces enabled for metrics and for the control plane, when the Prometheus Operator
+	// CRDs exist in the cluster.
+	// +optional
+	GenerateMonitors bool `json:"generateMonitors,omitempty"`
+
+	// ScrapeInterval defines the interval at which the targets of the generated monitors are scraped, as a duration.
+	// Defaults to the scrape interval of the Prometheus server.
+	// +optional
+	ScrapeInterval string `json:"scrapeInterval,omitempty"`
+}
+
+// MemoryProfilingSpec is the type to represent the memory profiling of the OSM controller, to troubleshoot its memory
+// usage. The profiles are served by the debug server, and are only available when EnableDebugServer is set.
+type MemoryProfilingSpec struct {
+	// Enable defines if the mutex and block profiles of the OSM controller are sampled, and if heap snapshots are
+	// periodically taken.
+	// +optional
+	Enable bool `json:"enable,omitempty"`
+
+	// HeapSnapshotInterval defines the interval at which heap snapshots are taken, as a duration. Defaults to 5m.
+	// +optional
+	HeapSnapshotInterval string `json:"heapSnapshotInterval,omitempty"`
+
+	// MaxHeapSnapshots defines the number of most recent heap snapshots kept in memory. Defaults to 12.
+	// +optional
+	MaxHeapSnapshots int `json:"maxHeapSnapshots,omitempty"`
+}
+
+// StatsSpec is the type to represent the stats generated by proxy sidecars. It applies to pods injected after it is
+// changed.
+type StatsSpec struct {
+	// InclusionRegexes defines the regular expressions matching the names of the only stats generated by the proxy
+	// sidecars. Takes precedence over ExclusionRegexes. Pods can override it using the
+	// openservicemesh.io/stats-inclusion-regexes annotation.
+	// +optional
+	InclusionRegexes []string `json:"inclusionRegexes,omitempty"`
+
+	// ExclusionRegexes defines the regular expressions matching the names of the stats not generated by the proxy
+	// sidecars. Pods can override it using the openservicemesh.io/stats-exclusion-regexes annotation.
+	// +optional
+	ExclusionRegexes []string `json:"exclusionRegexes,omitempty"`
+
+	// Tags defines the tags extracted from the names of the stats, in addition to the proxy's default tags.
+	// +optional
+	Tags []StatsTagSpec `json:"tags,omitempty"`
+}
+
+// StatsTagSpec is the type to represent a tag extracted from the names of the stats generated by proxy sidecars.
+type StatsTagSpec struct {
+	// Name defines the name of the tag.
+	Name string `json:"name"`
+
+	// Regex defines the regular expression extracting the tag from the names of the stats. The first capture group
+	// is removed from the names of the stats, and the second capture group, if any, is the value of the tag.
+	Regex string `json:"regex"`
+}
+
+// TracingSpec is the type to represent OSM's tracing configuration.
+type TracingSpec struct {
+	// Enable defines a boolean indicating if the sidecars are enabled for tracing.
+	Enable bool `json:"enable,omitempty"`
+
+	// Port defines the tracing collector's port.
+	Port int16 `json:"port,omitempty"`
+
+	// Address defines the tracing collectio's hostname.
+	Address string `json:"address,omitempty"`
+
+	// Endpoint defines the API endpoint for tracing requests sent to the collector.
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// ExternalAuthzSpec is a type to represent external authorization configuration.
+type ExternalAuthzSpec struct {
+	// Enable defines a boolean indicating if the external authorization policy is to be enabled.
+	Enable bool `json:"enable,omitempty"`
+
+	// Address defines the remote address of the external authorization endpoint.
+	Address string `json:"address,omitempty"`
+
+	// Port defines the destination port of the remote external authorization endpoint.
+	Port uint16 `json:"port,omitempty"`
+
+	// StatPrefix defines a prefix for the stats sink for this external authorization policy.
+	StatPrefix string `json:"statPrefix,omitempty"`
+
+	// Timeout defines the timeout in which a response from the external authorization endpoint.
+	// is expected to execute.
+	Timeout string `json:"timeout,omitempty"`
+
+	// FailureModeAllow defines a boolean indicating if traffic should be allowed on a failure to get a
+	// response against the external authorization endpoint.
+	FailureModeAllow bool `json:"failureModeAllow,omitempty"`
+}
+
+// EndpointFlapDampeningSpec is the type to represent the configuration used to dampen the churn caused by
+// endpoints whose readiness flaps.
+type EndpointFlapDampeningSpec struct {
+	// Enable defines a boolean indicating if endpoints whose readiness flaps are to be pinned out.
+	Enable bool `json:"enable,omitempty"`
+
+	// Window defines the window over which readiness transitions of an endpoint are counted. An endpoint
+	// that is pinned out remains pinned out until its readiness is stable for the duration of the window.
+	Window string `json:"window,omitempty"`
+
+	// MaxTransitions defines the number of readiness transitions within the window after which an endpoint
+	// is considered to be flapping and is pinned out.
+	MaxTransitions int `json:"maxTransitions,omitempty"`
+}
+
+// DNSResolutionSpec is the type to represent the configuration used by proxies to resolve the addresses of
+// clusters using DNS.
+type DNSResolutionSpec struct {
+	// ClusterType defines the Envoy service discovery type of clusters resolved using DNS, one of strict_dns or logical_dns.
+	// Defaults to strict_dns.
+	// +optional
+	ClusterType string `json:"clusterType,omitempty"`
+
+	// RefreshRate defines the interval at which the addresses of clusters resolved using DNS are refreshed.
+	// Defaults to the proxy's default refresh rate.
+	// +optional
+	RefreshRate string `json:"refreshRate,omitempty"`
+
+	// RespectDNSTTL defines a boolean indicating if the TTL of DNS records overrides the refresh rate.
+	// +optional
+	RespectDNSTTL bool `json:"respectDNSTTL,omitempty"`
+
+	// LookupFamily defines the IP address family used to resolve hostnames, one of auto, v4_only or v6_only.
+	// Defaults to auto.
+	// +optional
+	LookupFamily string `json:"lookupFamily,omitempty"`
+
+	// ResolveHTTPSHosts defines a boolean indicating if traffic matching the hosts of HTTPS Egress policies is routed
+	// to clusters resolved using DNS instead of to its original destination.
+	// +optional
+	ResolveHTTPSHosts bool `json:"resolveHTTPSHosts,omitempty"`
+}
+
+// CompressionSpec is the type to represent the configuration used by proxies to compress the responses of HTTP services.
+// Services can override it using the openservicemesh.io/compression* annotations.
+type CompressionSpec struct {
+	// Enable defines a boolean indicating if the responses of HTTP services are compressed.
+	// +optional
+	Enable bool `json:"enable,omitempty"`
+
+	// Algorithms defines the compression algorithms offered to the clients in order of preference, among gzip and brotli.
+	// Defaults to gzip.
+	// +optional
+	Algorithms []string `json:"algorithms,omitempty"`
+
+	// ContentTypes defines the content types of the responses that are compressed.
+	// Defaults to the proxy's default list of text content types.
+	// +optional
+	ContentTypes []string `json:"contentTypes,omitempty"`
+
+	// MinContentLength defines the minimum size in bytes of the responses that are compressed.
+	// Defaults to the proxy's default of 30 bytes.
+	// +optional
+	MinContentLength uint32 `json:"minContentLength,omitempty"`
+}
+
+// RequestLimitsSpec is the type to represent the limits on the size of the requests to HTTP services.
+type RequestLimitsSpec struct {
+	// MaxRequestBytes defines the maximum size in bytes of the body of the requests. Requests are buffered by the
+	// proxies to enforce the limit, and larger requests are rejected with a 413 response. Services can override it
+	// using the openservicemesh.io/max-request-bytes annotation. Unlimited if unset.
+	// +optional
+	MaxRequestBytes uint32 `json:"maxRequestBytes,omitempty"`
+
+	// MaxRequestHeadersKb defines the maximum size in KiB of the headers of the requests, at most 8192. Requests with
+	// larger headers are rejected with a 431 response. Defaults to the proxy's default of 60 KiB.
+	// +optional
+	MaxRequestHeadersKb uint32 `json:"maxRequestHeadersKb,omitempty"`
+}
+
+// ClientCertDetailsSpec is the type to represent how the details of the client certificates of mTLS connections are
+// forwarded to applications in the x-forwarded-client-cert (XFCC) header.
+type ClientCertDetailsSpec struct {
+	// ForwardClientCertDetails defines how the XFCC header of the requests is handled, one of 'sanitize',
+	// 'forward_only', 'append_forward', 'sanitize_set' and 'always_forward_only'. Defaults to 'sanitize', the header
+	// being removed from the requests.
+	// +optional
+	ForwardClientCertDetails string `json:"forwardClientCertDetails,omitempty"`
+
+	// SetCurrentClientCertDetails defines the fields of the client certificate set in the XFCC header when it is
+	// appended or set, in addition to the By and Hash fields which are always set.
+	// +optional
+	SetCurrentClientCertDetails SetCurrentClientCertDetailsSpec `json:"setCurrentClientCertDetails,omitempty"`
+}
+
+// RBACAuditSpec is the type to represent how the authorization decisions of the RBAC policies are audited.
+type RBACAuditSpec struct {
+	// ShadowMode defines a boolean indicating if the RBAC policies are evaluated in shadow mode, in which the inbound
+	// requests and connections they deny are counted and logged but allowed through. It allows evaluating the effect
+	// of TrafficTargets before enforcing them.
+	// +optional
+	ShadowMode bool `json:"shadowMode,omitempty"`
+
+	// EnableDenialLog defines a boolean indicating if the proxies stream the inbound requests denied by the RBAC
+	// policies, including those denied in shadow mode, to the controller, which counts them per service and lists
+	// the most recent ones.
+	// +optional
+	EnableDenialLog bool `json:"enableDenialLog,omitempty"`
+}
+
+// OutboundPassthroughSpec is the type to represent the destinations allowed through the outbound passthrough cluster.
+// A connection is passed through if its destination port is allowed and its destination IP is within an allowed
+// range. An empty list does not restrict the corresponding attribute.
+type OutboundPassthroughSpec struct {
+	// AllowedPorts defines the destination ports that may be reached through the outbound passthrough cluster.
+	// +optional
+	AllowedPorts []int `json:"allowedPorts,omitempty"`
+
+	// AllowedIPRanges defines the destination IP ranges, in CIDR notation, that may be reached through the outbound
+	// passthrough cluster.
+	// +optional
+	AllowedIPRanges []string `json:"allowedIPRanges,omitempty"`
+}
+
+// HTTPSanitizationSpec is the type to represent how the paths and headers of HTTP requests are normalized and
+// sanitized by the proxies, so that the routes and RBAC policies are evaluated against the same paths as the
+// applications.
+type HTTPSanitizationSpec struct {
+	// NormalizePath defines a boolean indicating if the paths of the requests are normalized according to RFC 3986,
+	// e.g. /books/../authors becoming /authors.
+	// +optional
+	NormalizePath bool `json:"normalizePath,omitempty"`
+
+	// MergeSlashes defines a boolean indicating if the adjacent slashes of the paths of the requests are merged into a
+	// single slash, e.g. //books becoming /books.
+	// +optional
+	MergeSlashes bool `json:"mergeSlashes,omitempty"`
+
+	// PathWithEscapedSlashesAction defines how the requests with escaped slashes (%2F, %5C) in their paths are
+	// handled, one of KeepUnchanged, RejectRequest, UnescapeAndRedirect and UnescapeAndForward. Defaults to the
+	// proxies' default, keeping the paths unchanged.
+	// +optional
+	PathWithEscapedSlashesAction EscapedSlashesAction `json:"pathWithEscapedSlashesAction,omitempty"`
+
+	// HeadersWithUnderscoresAction defines how the requests with underscores in the names of their headers are
+	// handled, one of Allow, RejectRequest and DropHeader. Defaults to the proxies' default, allowing them.
+	// +optional
+	HeadersWithUnderscoresAction HeadersWithUnderscoresAction `json:"headersWithUnderscoresAction,omitempty"`
+}
+
+// EscapedSlashesAction is a type to represent how requests with escaped slashes in their paths are handled
+type EscapedSlashesAction string
+
+const (
+	// EscapedSlashesKeepUnchanged forwards the requests with escaped slashes in their paths unchanged
+	EscapedSlashesKeepUnchanged EscapedSlashesAction = "KeepUnchanged"
+
+	// EscapedSlashesRejectRequest rejects the requests with escaped slashes in their paths
+	EscapedSlashesRejectRequest EscapedSlashesAction = "RejectRequest"
+
+	// EscapedSlashesUnescapeAndRedirect redirects the requests with escaped slashes in their paths to their unescaped
+	// paths
+	EscapedSlashesUnescapeAndRedirect EscapedSlashesAction = "UnescapeAndRedirect"
+
+	// EscapedSlashesUnescapeAndForward unescapes the slashes of the paths of the requests before forwarding them
+	EscapedSlashesUnescapeAndForward EscapedSlashesAction = "UnescapeAndForward"
+)
+
+// HeadersWithUnderscoresAction is a type to represent how requests with underscores in the names of their headers
+// are handled
+type HeadersWithUnderscoresAction string
+
+const (
+	// HeadersWithUnderscoresAllow allows the headers with underscores in their names
+	HeadersWithUnderscoresAllow HeadersWithUnderscoresAction = "Allow"
+
+	// HeadersWithUnderscoresRejectRequest rejects the requests with headers with underscores in their names
+	HeadersWithUnderscoresRejectRequest HeadersWithUnderscoresAction = "RejectRequest"
+
+	// HeadersWithUnderscoresDropHeader drops the headers with underscores in their names from the requests
+	HeadersWithUnderscoresDropHeader HeadersWithUnderscoresAction = "DropHeader"
+)
+
+// RouteRegexSpec is the type to represent how the regular expressions of the route matches of HTTPRouteGroups are
+// programmed on the proxies.
+type RouteRegexSpec struct {
+	// MaxProgramSize defines the maximum program size, a measure of complexity, of the regular expressions of the
+	// route matches. The proxies reject the routes whose regular expressions exceed it, which are therefore not
+	// programmed. Defaults to the proxies' limit of 100 when 0.
+	// +optional
+	MaxProgramSize uint32 `json:"maxProgramSize,omitempty"`
+
+	// PathAnchoring defines how the path regular expressions of the route matches are anchored: Full, the default,
+	// matches them against the entire request paths, Prefix against the beginning of the request paths, e.g. /books
+	// matching /books/123.
+	// +optional
+	PathAnchoring RoutePathAnchoring `json:"pathAnchoring,omitempty"`
+}
+
+// RoutePathAnchoring is a type to represent how the path regular expressions of route matches are anchored
+type RoutePathAnchoring string
+
+const (
+	// RoutePathAnchoringFull matches path regular expressions against the entire request paths
+	RoutePathAnchoringFull RoutePathAnchoring = "Full"
+
+	// RoutePathAnchoringPrefix matches path regular expressions against the beginning of the request paths
+	RoutePathAnchoringPrefix RoutePathAnchoring = "Prefix"
+)
+
+// NamespaceIsolationSpec is the type to represent the isolation of the outbound traffic of the namespaces.
+type NamespaceIsolationSpec struct {
+	// Enable defines a boolean indicating if the outbound traffic of the workloads of a namespace is restricted to
+	// the services in their own namespace and the namespaces allowed by the NamespaceIsolation policies of their
+	// namespace.
+	// +optional
+	Enable bool `json:"enable,omitempty"`
+}
+
+// SetCurrentClientCertDetailsSpec is the type to represent the fields of the client certificate set in the XFCC header.
+type SetCurrentClientCertDetailsSpec struct {
+	// Subject defines whether the subject of the client certificate is set.
+	// +optional
+	Subject bool `json:"subject,omitempty"`
+
+	// Cert defines whether the entire client certificate in URL encoded PEM format is set.
+	// +optional
+	Cert bool `json:"cert,omitempty"`
+
+	// Chain defines whether the entire client certificate chain in URL encoded PEM format is set.
+	// +optional
+	Chain bool `json:"chain,omitempty"`
+
+	// DNS defines whether the DNS type Subject Alternative Names of the client certificate are set.
+	// +optional
+	DNS bool `json:"dns,omitempty"`
+
+	// URI defines whether the URI type Subject Alternative Name of the client certificate is set.
+	// +optional
+	URI bool `json:"uri,omitempty"`
+}
+
+// CertificateSpec is the type to reperesent OSM's certificate management configuration.
+type CertificateSpec struct {
+	// ServiceCertValidityDuration defines the service certificate validity duration.
+	ServiceCertValidityDuration string `json:"serviceCertValidityDuration,omitempty"`
+
+	// CertKeyBitSize defines the certicate key bit size.
+	CertKeyBitSize int `json:"certKeyBitSize,omitempty"`
+
+	// KeyAlgorithm defines the algorithm of the keys of the service certificates, either rsa, whose key size is
+	// defined by CertKeyBitSize, or ecdsa for ECDSA P-256 keys. Defaults to rsa.
+	// +optional
+	KeyAlgorithm string `json:"keyAlgorithm,omitempty"`
+
+	// SubjectAltNameFormat defines the Subject Alternative Names of the service certificates, either dns for the DNS
+	// SAN of the service identity, or dns_spiffe for the SPIFFE ID of the service identity as a URI SAN in addition to
+	// the DNS SAN. Defaults to dns.
+	// +optional
+	SubjectAltNameFormat string `json:"subjectAltNameFormat,omitempty"`
+
+	// TrustDomain defines the trust domain of the certificates issued by this mesh instance. It identifies the
+	// mesh instance's CA bundle to the other clusters participating in a multicluster mesh.
+	// +optional
+	TrustDomain string `json:"trustDomain,omitempty"`
+
+	// TrustDomainAliases defines the trust domains, other than the cluster's own, the certificates of upstream service
+	// identities are accepted from. It allows migrating services between trust domains, and can be overridden per
+	// upstream service by an UpstreamTrafficSetting policy.
+	// +optional
+	TrustDomainAliases []string `json:"trustDomainAliases,omitempty"`
+
+	// FederatedTrustDomains defines the external trust domains, such as those of other meshes or SPIFFE federations,
+	// whose workloads are authorized to connect to the mesh's services as the service identities they are mapped to.
+	// +optional
+	FederatedTrustDomains []FederatedTrustDomainSpec `json:"federatedTrustDomains,omitempty"`
+
+	// IngressGateway defines the certificate specification for an ingress gateway.
+	// +optional
+	IngressGateway *IngressGatewayCertSpec `json:"ingressGateway,omitempty"`
+}
+
+// FederatedTrustDomainSpec is the type to represent an external trust domain federated with the mesh.
+type FederatedTrustDomainSpec struct {
+	// TrustDomain defines the name of the external trust domain.
+	TrustDomain string `json:"trustDomain"`
+
+	// TrustBundle defines the PEM encoded CA certificates of the external trust domain, which the certificates
+	// presented by its workloads are validated against.
+	TrustBundle string `json:"trustBundle"`
+
+	// IdentityMappings defines the identities of the external trust domain that are mapped to service identities
+	// of the mesh. A mapped external identity is granted the access of its service identity by TrafficTargets.
+	// +optional
+	IdentityMappings []FederatedIdentityMappingSpec `json:"identityMappings,omitempty"`
+}
+
+// FederatedIdentityMappingSpec is the type to represent the mapping of an external identity to a service identity.
+type FederatedIdentityMappingSpec struct {
+	// ExternalIdentity defines the identity of the external workload, as the URI or DNS Subject Alternative Name
+	// of its certificate, e.g. spiffe://example.org/ns/default/sa/bookbuyer.
+	ExternalIdentity string `json:"externalIdentity"`
+
+	// ServiceAccount defines the name of the service account of the service identity.
+	ServiceAccount string `json:"serviceAccount"`
+
+	// Namespace defines the namespace of the service account of the service identity.
+	Namespace string `json:"namespace"`
+}
+
+// IngressGatewayCertSpec is the type to represent the certificate specification for an ingress gateway.
+type IngressGatewayCertSpec struct {
+	// SubjectAltNames defines the Subject Alternative Names (domain names and IP addresses) secured by the certificate.
+	SubjectAltNames []string `json:"subjectAltNames"`
+
+	// ValidityDuration defines the validity duration of the certificate.
+	ValidityDuration string `json:"validityDuration"`
+
+	// Secret defines the secret in which the certificate is stored.
+	Secret corev1.SecretReference `json:"secret"`
+}
+
+// MeshConfigList lists the MeshConfig objects.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type MeshConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []MeshConfig `json:"items"`
+}
+
+// FeatureFlags is a type to represent OSM's feature flags.
+type FeatureFlags struct {
+	// EnableWASMStats defines if WASM Stats are enabled.
+	EnableWASMStats bool `json:"enableWASMStats,omitempty"`
+
+	// EnableEgressPolicy defines if OSM's Egress policy is enabled.
+	EnableEgressPolicy bool `json:"enableEgressPolicy,omitempty"`
+
+	// EnableMulticlusterMode defines if Multicluster mode is enabled.
+	EnableMulticlusterMode bool `json:"enableMulticlusterMode,omitempty"`
+
+	// EnableSnapshotCacheMode defines if XDS server starts with snapshot cache.
+	EnableSnapshotCacheMode bool `json:"enableSnapshotCacheMode,omitempty"`
+
+	//EnableAsyncProxyServiceMapping defines if OSM will map proxies to services asynchronously.
+	EnableAsyncProxyServiceMapping bool `json:"enableAsyncProxyServiceMapping,omitempty"`
+
+	// EnableValidatingWebhook defines if the OSM controller will create a validating webhook handler.
+	EnableValidatingWebhook bool `json:"enableValidatingWebhook,omitempty"`
+
+	// EnableIngressBackendPolicy defines if OSM will use the IngressBackend API to allow ingress traffic to
+	// service mesh backends.
+	EnableIngressBackendPolicy bool `json:"enableIngressBackendPolicy,omitempty"`
+
+	// EnableEnvoyActiveHealthChecks defines if OSM will Envoy active health
+	// checks between services allowed to communicate.
+	EnableEnvoyActiveHealthChecks bool `json:"enableEnvoyActiveHealthChecks,omitempty"`
+
+	// EnableIngressHTTP3 defines if OSM will configure a QUIC listener accepting HTTP/3 ingress traffic
+	// alongside the TCP listener on HTTPS ingress backends. HTTP/3 ingress is direct-to-pod: the listener
+	// is only configured for the ingress ports the backend's Service also exposes over UDP.
+	EnableIngressHTTP3 bool `json:"enableIngressHTTP3,omitempty"`
+
+	// EnableDNSProxy defines if the DNS queries of meshed pods are redirected to their proxy sidecar, which answers
+	// the queries for the hostnames of mesh services and forwards the other queries to the pod's DNS resolvers.
+	EnableDNSProxy bool `json:"enableDNSProxy,omitempty"`
+
+	// EnableScopedRoutes defines if the outbound HTTP routes of the proxies are scoped by host with scoped RDS, so that
+	// the proxies fetch on demand the routes of the hosts they actually address instead of a single route configuration
+	// with the routes of all the hosts. It is not supported with the snapshot cache.
+	EnableScopedRoutes bool `json:"enableScopedRoutes,omitempty"`
+}
+
+// DownstreamLimitsSpec is the type to represent the limits on the downstream connections and requests to services.
+// The timeouts are durations such as '30s' or '5m'.
+type DownstreamLimitsSpec struct {
+	// MaxConnections defines the maximum number of concurrent downstream connections to each port of a service on each
+	// of its proxies. Connections over the limit are closed as soon as they are accepted. Unlimited if unset.
+	// +optional
+	MaxConnections uint32 `json:"maxConnections,omitempty"`
+
+	// IdleTimeout defines how long downstream connections without active requests, or without any traffic for TCP
+	// services, are kept open before being closed. Defaults to the proxies' default of 1h.
+	// +optional
+	IdleTimeout string `json:"idleTimeout,omitempty"`
+
+	// RequestTimeout defines how long the proxies wait to receive the entire requests to HTTP services, from their
+	// first byte to their end, before responding with a 408. Unlimited if unset.
+	// +optional
+	RequestTimeout string `json:"requestTimeout,omitempty"`
+
+	// RequestHeadersTimeout defines how long the proxies wait to receive the headers of the requests to HTTP services
+	// before responding with a 408. Unlimited if unset.
+	// +optional
+	RequestHeadersTimeout string `json:"requestHeadersTimeout,omitempty"`
+
+	// StreamIdleTimeout defines how long requests to HTTP services, including HTTP/2 streams, are kept open without
+	// any activity before being reset. Defaults to the proxies' default of 5m.
+	// +optional
+	StreamIdleTimeout string `json:"streamIdleTimeout,omitempty"`
+}
+
+// IngressClientAddressSpec is the type to represent how the proxies of HTTP services determine the address of the
+// clients of ingress requests, used in the x-forwarded-for (XFF) header forwarded to the backends, in the access logs
+// and to tell internal from external requests.
+type IngressClientAddressSpec struct {
+	// UseRemoteAddress defines whether the remote address of the downstream connections is trusted as the client
+	// address and appended to the XFF header of the requests. It is always set when the source IP of the ingress
+	// clients is preserved up to the backend, such as for NodePort and LoadBalancer services with a Local external
+	// traffic policy and for backends accepting the PROXY protocol. Otherwise the client address is taken from the
+	// XFF header of the requests.
+	// +optional
+	UseRemoteAddress bool `json:"useRemoteAddress,omitempty"`
+
+	// XFFNumTrustedHops defines the number of trusted proxies in front of the ingress, such as cloud load balancers
+	// and CDNs, that append the address of their downstream to the XFF header of the requests. The client address is
+	// the address that many hops from the right end of the header, the addresses before it being set by untrusted
+	// clients. Defaults to 0, the client address being the rightmost address of the header.
+	// +optional
+	XFFNumTrustedHops uint32 `json:"xffNumTrustedHops,omitempty"`
+
+	// InternalAddressConfig defines the client addresses considered internal when UseRemoteAddress is set, in
+	// addition to the loopback and RFC1918 addresses. The x-envoy-internal header is set on the requests from
+	// internal addresses and the x-envoy headers are preserved on them.
+	// +optional
+	InternalAddressConfig InternalAddressConfigSpec `json:"internalAddressConfig,omitempty"`
+}
+
+// InternalAddressConfigSpec is the type to represent the client addresses considered internal by the proxies.
+type InternalAddressConfigSpec struct {
+	// UnixSockets defines whether the clients connected over Unix domain sockets are considered internal.
+	// +optional
+	UnixSockets bool `json:"unixSockets,omitempty"`
+}
@@ -56,8 +56,22 @@ type EgressSpec struct {
 	// Matches defines the list of object references the Egress policy should match on.
 	// +optional
 	Matches []corev1.TypedLocalObjectReference `json:"matches,omitempty"`
+
+	// ProxyProtocolVersion defines the version of the PROXY protocol header, 'v1' or 'v2', sent on the connections
+	// to the external hosts to convey the address of the source, for hosts behind L4 load balancers expecting it.
+	// The header isn't sent if unspecified.
+	// +optional
+	ProxyProtocolVersion string `json:"proxyProtocolVersion,omitempty"`
 }
 
+const (
+	// ProxyProtocolV1 is the human-readable version of the PROXY protocol header.
+	ProxyProtocolV1 = "v1"
+
+	// ProxyProtocolV2 is the binary version of the PROXY protocol header.
+	ProxyProtocolV2 = "v2"
+)
+
 // EgressSourceSpec is the type used to represent the Source in the list of Sources specified in an Egress policy specification.
 type EgressSourceSpec struct {
 	// Kind defines the kind for the source in the Egress policy, ex. ServiceAccount.
@@ -0,0 +1,65 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ExternalWorkload is the type used to represent a workload running outside Kubernetes, such as a VM,
+// that participates in the mesh. The proxy running alongside the workload connects to the control plane
+// using a certificate issued by OSM for the workload's service account, and the workload is selected by
+// Kubernetes services using its labels, similar to pods.
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ExternalWorkload struct {
+	// Object's type metadata
+	metav1.TypeMeta `json:",inline"`
+
+	// Object's metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the ExternalWorkload specification
+	// +optional
+	Spec ExternalWorkloadSpec `json:"spec,omitempty"`
+}
+
+// ExternalWorkloadSpec is the type used to represent the ExternalWorkload specification.
+type ExternalWorkloadSpec struct {
+	// ServiceAccount is the name of the service account in the ExternalWorkload's namespace
+	// the workload's identity maps to.
+	ServiceAccount string `json:"serviceAccount"`
+
+	// ProxyUUID is the unique ID of the workload's proxy, encoded in the common name of the
+	// certificate the proxy connects to the control plane with.
+	ProxyUUID string `json:"proxyUUID"`
+
+	// Addresses defines the list of IP addresses the workload can be reached at.
+	Addresses []string `json:"addresses"`
+
+	// Ports defines the list of ports exposed by the workload.
+	// +optional
+	Ports []ExternalWorkloadPort `json:"ports,omitempty"`
+}
+
+// ExternalWorkloadPort is the type used to represent a port exposed by an ExternalWorkload.
+type ExternalWorkloadPort struct {
+	// Name defines the name of the port, used to resolve named target ports of services selecting the workload.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Number defines the port number.
+	Number int `json:"number"`
+
+	// Protocol defines the protocol served by the port.
+	// +optional
+	Protocol string `json:"protocol,omitempty"`
+}
+
+// ExternalWorkloadList defines the list of ExternalWorkload objects.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ExternalWorkloadList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ExternalWorkload `json:"items"`
+}
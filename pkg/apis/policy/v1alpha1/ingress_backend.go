@@ -38,6 +38,179 @@ type IngressBackendSpec struct {
 	// Matches defines the list of object references the IngressBackend policy should match on.
 	// +optional
 	Matches []corev1.TypedLocalObjectReference `json:"matches,omitempty"`
+
+	// CORS defines the CORS policy applied to the requests to the backends.
+	// +optional
+	CORS *CORSSpec `json:"cors,omitempty"`
+
+	// Headers defines the manipulation of the headers of the requests to the backends and of their responses.
+	// +optional
+	Headers *HeadersSpec `json:"headers,omitempty"`
+
+	// Security defines the hardening of the requests to the backends and of their responses at the edge of the mesh.
+	// +optional
+	Security *IngressSecuritySpec `json:"security,omitempty"`
+
+	// ClientAddress defines how the proxies of the backends determine the address of the clients of the requests,
+	// overriding the MeshConfig's traffic.ingressClientAddress.
+	// +optional
+	ClientAddress *ClientAddressSpec `json:"clientAddress,omitempty"`
+}
+
+// ClientAddressSpec is the type used to represent how the proxies of the backends of an IngressBackend policy
+// determine the address of the clients of the requests, such as the requests proxied by cloud load balancers and
+// CDNs in front of the ingress.
+type ClientAddressSpec struct {
+	// UseRemoteAddress defines whether the remote address of the downstream connections is trusted as the client
+	// address and appended to the x-forwarded-for (XFF) header of the requests. It is always set when the source IP of
+	// the clients is preserved up to the backends. Otherwise the client address is taken from the XFF header.
+	// +optional
+	UseRemoteAddress bool `json:"useRemoteAddress,omitempty"`
+
+	// XFFNumTrustedHops defines the number of trusted proxies in front of the ingress that append the address of
+	// their downstream to the XFF header of the requests. The client address is the address that many hops from the
+	// right end of the header.
+	// +optional
+	XFFNumTrustedHops uint32 `json:"xffNumTrustedHops,omitempty"`
+
+	// InternalAddressConfig defines the client addresses considered internal when UseRemoteAddress is set, in
+	// addition to the loopback and RFC1918 addresses.
+	// +optional
+	InternalAddressConfig InternalAddressConfigSpec `json:"internalAddressConfig,omitempty"`
+}
+
+// InternalAddressConfigSpec is the type used to represent the client addresses considered internal by the proxies.
+type InternalAddressConfigSpec struct {
+	// UnixSockets defines whether the clients connected over Unix domain sockets are considered internal.
+	// +optional
+	UnixSockets bool `json:"unixSockets,omitempty"`
+}
+
+// IngressSecuritySpec is the type used to represent the hardening of the requests to the backends of an
+// IngressBackend policy and of their responses.
+type IngressSecuritySpec struct {
+	// CSRF defines the cross-site request forgery protection of the requests to the backends.
+	// +optional
+	CSRF *CSRFSpec `json:"csrf,omitempty"`
+
+	// ResponseHeaders defines the security headers set on the responses of the backends, replacing existing values.
+	// +optional
+	ResponseHeaders *SecurityHeadersSpec `json:"responseHeaders,omitempty"`
+
+	// Cookies defines the attributes set on the cookies of the responses of the backends.
+	// +optional
+	Cookies *CookieAttributesSpec `json:"cookies,omitempty"`
+}
+
+// CSRFSpec is the type used to represent the cross-site request forgery protection of the requests to the backends.
+// Mutating requests whose Origin header does not match their destination or one of the additional origins are
+// rejected.
+type CSRFSpec struct {
+	// AdditionalOrigins defines the origins allowed in addition to the destination of the requests.
+	// +optional
+	AdditionalOrigins []string `json:"additionalOrigins,omitempty"`
+
+	// ShadowMode defines whether the requests with invalid origins are only counted in the proxies' stats instead
+	// of being rejected.
+	// +optional
+	ShadowMode bool `json:"shadowMode,omitempty"`
+}
+
+// SecurityHeadersSpec is the type used to represent the security headers set on the responses of the backends.
+type SecurityHeadersSpec struct {
+	// FrameOptions defines the value of the X-Frame-Options header, one of DENY and SAMEORIGIN.
+	// +optional
+	FrameOptions string `json:"frameOptions,omitempty"`
+
+	// ContentSecurityPolicy defines the value of the Content-Security-Policy header.
+	// +optional
+	ContentSecurityPolicy string `json:"contentSecurityPolicy,omitempty"`
+
+	// StrictTransportSecurity defines the value of the Strict-Transport-Security header,
+	// e.g. 'max-age=31536000; includeSubDomains'.
+	// +optional
+	StrictTransportSecurity string `json:"strictTransportSecurity,omitempty"`
+
+	// ReferrerPolicy defines the value of the Referrer-Policy header, e.g. 'no-referrer'.
+	// +optional
+	ReferrerPolicy string `json:"referrerPolicy,omitempty"`
+
+	// ContentTypeNoSniff defines whether the X-Content-Type-Options header is set to 'nosniff'.
+	// +optional
+	ContentTypeNoSniff bool `json:"contentTypeNoSniff,omitempty"`
+}
+
+// CookieAttributesSpec is the type used to represent the attributes set on the cookies of the responses of the
+// backends, in their Set-Cookie headers.
+type CookieAttributesSpec struct {
+	// Secure defines whether the Secure attribute is set on the cookies.
+	// +optional
+	Secure bool `json:"secure,omitempty"`
+
+	// HTTPOnly defines whether the HttpOnly attribute is set on the cookies.
+	// +optional
+	HTTPOnly bool `json:"httpOnly,omitempty"`
+
+	// SameSite defines the value of the SameSite attribute of the cookies, one of Strict, Lax and None, replacing
+	// existing values. None requires Secure to be set.
+	// +optional
+	SameSite string `json:"sameSite,omitempty"`
+}
+
+// HeadersSpec is the type used to represent the manipulation of the headers of HTTP requests and responses.
+type HeadersSpec struct {
+	// RequestHeadersToAdd defines the headers added to the requests, replacing existing values.
+	// +optional
+	RequestHeadersToAdd []HeaderValueSpec `json:"requestHeadersToAdd,omitempty"`
+
+	// RequestHeadersToRemove defines the names of the headers removed from the requests.
+	// +optional
+	RequestHeadersToRemove []string `json:"requestHeadersToRemove,omitempty"`
+
+	// ResponseHeadersToAdd defines the headers added to the responses, replacing existing values.
+	// +optional
+	ResponseHeadersToAdd []HeaderValueSpec `json:"responseHeadersToAdd,omitempty"`
+
+	// ResponseHeadersToRemove defines the names of the headers removed from the responses.
+	// +optional
+	ResponseHeadersToRemove []string `json:"responseHeadersToRemove,omitempty"`
+}
+
+// HeaderValueSpec is the type used to represent an HTTP header.
+type HeaderValueSpec struct {
+	// Name defines the name of the header.
+	Name string `json:"name"`
+
+	// Value defines the value of the header, which can reference request and connection properties
+	// using Envoy's format strings, e.g. '%REQ(x-request-id)%'.
+	Value string `json:"value"`
+}
+
+// CORSSpec is the type used to represent the CORS policy applied to the requests to the backends
+// of an IngressBackend policy.
+type CORSSpec struct {
+	// AllowOrigins defines the origins allowed to make cross-origin requests, '*' allowing any origin.
+	AllowOrigins []string `json:"allowOrigins"`
+
+	// AllowMethods defines the HTTP methods allowed in cross-origin requests.
+	// +optional
+	AllowMethods []string `json:"allowMethods,omitempty"`
+
+	// AllowHeaders defines the HTTP headers allowed in cross-origin requests.
+	// +optional
+	AllowHeaders []string `json:"allowHeaders,omitempty"`
+
+	// ExposeHeaders defines the HTTP headers of the responses exposed to the clients.
+	// +optional
+	ExposeHeaders []string `json:"exposeHeaders,omitempty"`
+
+	// MaxAge defines how long the responses to preflight requests can be cached by the clients.
+	// +optional
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+
+	// AllowCredentials defines whether the clients can send credentials in cross-origin requests.
+	// +optional
+	AllowCredentials bool `json:"allowCredentials,omitempty"`
 }
 
 // BackendSpec is the type used to represent a Backend specified in the IngressBackend policy specification.
@@ -51,6 +224,56 @@ type BackendSpec struct {
 	// TLS defines the specification for the backend's TLS configuration.
 	// +optional
 	TLS TLSSpec `json:"tls,omitempty"`
+
+	// Rewrite defines the specification for rewriting the HTTP requests routed to the backend.
+	// +optional
+	Rewrite *RewriteSpec `json:"rewrite,omitempty"`
+
+	// Headers defines the manipulation of the headers of the requests routed to the backend and of their responses,
+	// taking precedence over the manipulation defined for all the backends.
+	// +optional
+	Headers *HeadersSpec `json:"headers,omitempty"`
+
+	// AcceptProxyProtocol defines whether the connections to the backend's port start with a PROXY protocol header,
+	// such as the connections from L4 load balancers prepending the address of the clients. The header is then
+	// required on this port, and the source addresses of the connections are the client addresses it carries.
+	// +optional
+	AcceptProxyProtocol bool `json:"acceptProxyProtocol,omitempty"`
+}
+
+// RewriteSpec is the type used to represent how the HTTP requests routed to a backend are rewritten.
+type RewriteSpec struct {
+	// PathPrefix defines the path prefix of the requests routed to the backend, defaults to '/'.
+	// +optional
+	PathPrefix string `json:"pathPrefix,omitempty"`
+
+	// PrefixRewrite defines the value replacing PathPrefix in the path of the requests.
+	// +optional
+	PrefixRewrite string `json:"prefixRewrite,omitempty"`
+
+	// RegexRewrite defines how the path of the requests is rewritten using a regular expression.
+	// It cannot be specified along with PrefixRewrite.
+	// +optional
+	RegexRewrite *RegexRewriteSpec `json:"regexRewrite,omitempty"`
+
+	// Host defines the value replacing the Host header of the requests.
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// AutoHost defines whether the Host header of the requests is replaced with the hostname of the
+	// upstream host the requests are forwarded to. It cannot be specified along with Host.
+	// +optional
+	AutoHost bool `json:"autoHost,omitempty"`
+}
+
+// RegexRewriteSpec is the type used to represent the rewrite of a path using a regular expression.
+type RegexRewriteSpec struct {
+	// Pattern defines the RE2 regular expression matched against the path.
+	Pattern string `json:"pattern"`
+
+	// Substitution defines the value replacing the portions of the path matching Pattern.
+	// It can reference the capture groups of Pattern, e.g. '\1'.
+	Substitution string `json:"substitution"`
 }
 
 const (
@@ -68,6 +291,8 @@ type IngressSourceSpec struct {
 	Kind string `json:"kind"`
 
 	// Name defines the name of the source for the given Kind.
+	// An AuthenticatedPrincipal named *.<namespace>.cluster.local matches the principals of all the service accounts
+	// of the namespace.
 	Name string `json:"name"`
 
 	// Namespace defines the namespace for the given source.
@@ -84,6 +309,12 @@ type TLSSpec struct {
 	// SNIHosts defines the SNI hostnames that the backend allows the client to connect to.
 	// +optional
 	SNIHosts []string `json:"sniHosts,omitempty"`
+
+	// SecretName defines the name of the kubernetes.io/tls Secret in the namespace of the IngressBackend
+	// holding the certificate the backend presents to the clients, instead of the certificate issued
+	// by OSM to the backend.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
 }
 
 // IngressBackendList defines the list of IngressBackend objects.
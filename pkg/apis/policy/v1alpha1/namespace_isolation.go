@@ -0,0 +1,39 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NamespaceIsolation is the type used to represent the namespaces the workloads of a namespace may initiate outbound
+// connections to when namespace isolation is enabled in the MeshConfig.
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type NamespaceIsolation struct {
+	// Object's type metadata
+	metav1.TypeMeta `json:",inline"`
+
+	// Object's metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the NamespaceIsolation policy specification
+	// +optional
+	Spec NamespaceIsolationSpec `json:"spec,omitempty"`
+}
+
+// NamespaceIsolationSpec is the type used to represent the NamespaceIsolation policy specification.
+type NamespaceIsolationSpec struct {
+	// AllowedNamespaces defines the namespaces, other than the NamespaceIsolation's own, whose services the workloads
+	// in the NamespaceIsolation's namespace may initiate outbound connections to. The allowed namespaces of all the
+	// NamespaceIsolation policies in a namespace are combined.
+	AllowedNamespaces []string `json:"allowedNamespaces"`
+}
+
+// NamespaceIsolationList defines the list of NamespaceIsolation objects.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type NamespaceIsolationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []NamespaceIsolation `json:"items"`
+}
@@ -0,0 +1,89 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NamespaceOffboarding is the type used to represent a namespace offboarding operation.
+// A namespace offboarding removes its namespace from the mesh once the sidecars are uninstalled from its pods:
+// sidecar injection is disabled, the workloads running pods with sidecars are restarted, and the certificates of the
+// namespace are released before the namespace is removed from the mesh.
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type NamespaceOffboarding struct {
+	// Object's type metadata
+	metav1.TypeMeta `json:",inline"`
+
+	// Object's metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the namespace offboarding specification
+	// +optional
+	Spec NamespaceOffboardingSpec `json:"spec,omitempty"`
+
+	// Status is the status of the namespace offboarding.
+	// +optional
+	Status NamespaceOffboardingStatus `json:"status,omitempty"`
+}
+
+// NamespaceOffboardingSpec is the type used to represent the NamespaceOffboarding specification.
+type NamespaceOffboardingSpec struct {
+	// Timeout defines the duration after which the offboarding fails if pods of the namespace still run sidecars,
+	// such as pods not managed by a Deployment, StatefulSet or DaemonSet which must be deleted. Defaults to 10m.
+	// +optional
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// NamespaceOffboardingList defines the list of NamespaceOffboarding objects.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type NamespaceOffboardingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []NamespaceOffboarding `json:"items"`
+}
+
+// NamespaceOffboardingPhase is the type used to represent the phase of a namespace offboarding.
+type NamespaceOffboardingPhase string
+
+const (
+	// NamespaceOffboardingInProgress is the phase of a namespace offboarding waiting for the pods of the namespace to
+	// be recreated without sidecars
+	NamespaceOffboardingInProgress NamespaceOffboardingPhase = "InProgress"
+
+	// NamespaceOffboardingCompleted is the phase of a namespace offboarding whose namespace was removed from the mesh
+	NamespaceOffboardingCompleted NamespaceOffboardingPhase = "Completed"
+
+	// NamespaceOffboardingFailed is the phase of a namespace offboarding whose configuration is invalid, or whose
+	// namespace still runs pods with sidecars after its timeout
+	NamespaceOffboardingFailed NamespaceOffboardingPhase = "Failed"
+)
+
+// NamespaceOffboardingStatus is the type used to represent the status of a NamespaceOffboarding resource.
+type NamespaceOffboardingStatus struct {
+	// Phase defines the current phase of the namespace offboarding.
+	// +optional
+	Phase NamespaceOffboardingPhase `json:"phase,omitempty"`
+
+	// StartTime defines the time the sidecar injection was disabled and the workloads were restarted.
+	// +optional
+	StartTime metav1.Time `json:"startTime,omitempty"`
+
+	// RestartedWorkloads defines the workloads restarted to remove their sidecars, as <kind>/<name>.
+	// +optional
+	RestartedWorkloads []string `json:"restartedWorkloads,omitempty"`
+
+	// PodsWithSidecars defines the pods of the namespace still running sidecars.
+	// +optional
+	PodsWithSidecars []string `json:"podsWithSidecars,omitempty"`
+
+	// ReleasedCertificates defines the number of certificates of the namespace released once it had no pods
+	// with sidecars left.
+	// +optional
+	ReleasedCertificates int `json:"releasedCertificates,omitempty"`
+
+	// Message defines a human readable description of the current phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,145 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProgressiveDelivery is the type used to represent a progressive delivery policy.
+// A progressive delivery policy shifts the traffic of an SMI TrafficSplit to a canary backend step by step,
+// as long as the metrics of the canary backend meet the configured thresholds, and rolls the traffic back to
+// the other backend when they don't.
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ProgressiveDelivery struct {
+	// Object's type metadata
+	metav1.TypeMeta `json:",inline"`
+
+	// Object's metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the progressive delivery policy specification
+	// +optional
+	Spec ProgressiveDeliverySpec `json:"spec,omitempty"`
+
+	// Status is the status of the progressive delivery.
+	// +optional
+	Status ProgressiveDeliveryStatus `json:"status,omitempty"`
+}
+
+// ProgressiveDeliverySpec is the type used to represent the ProgressiveDelivery policy specification.
+type ProgressiveDeliverySpec struct {
+	// TrafficSplit defines the name of the TrafficSplit in the ProgressiveDelivery's namespace whose weights
+	// are adjusted. The TrafficSplit must have exactly two backends.
+	TrafficSplit string `json:"trafficSplit"`
+
+	// CanaryBackend defines the name of the TrafficSplit backend traffic is progressively shifted to.
+	CanaryBackend string `json:"canaryBackend"`
+
+	// StepWeight defines the weight added to the canary backend at each step, out of a total weight of 100.
+	// Defaults to 10.
+	// +optional
+	StepWeight int `json:"stepWeight,omitempty"`
+
+	// MaxWeight defines the weight of the canary backend at which the delivery succeeds. Defaults to 100.
+	// +optional
+	MaxWeight int `json:"maxWeight,omitempty"`
+
+	// Interval defines the duration between two steps, over which the metrics of the canary backend are
+	// analyzed. Defaults to 1m.
+	// +optional
+	Interval string `json:"interval,omitempty"`
+
+	// FailureThreshold defines the number of failed analyses after which the traffic is rolled back.
+	// Defaults to 5.
+	// +optional
+	FailureThreshold int `json:"failureThreshold,omitempty"`
+
+	// Analysis defines the thresholds the metrics of the canary backend must meet.
+	// +optional
+	Analysis ProgressiveDeliveryAnalysis `json:"analysis,omitempty"`
+
+	// Paused defines whether the delivery is paused, in which case the weights of the TrafficSplit are left as is.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// ProgressiveDeliveryAnalysis is the type used to represent the thresholds the metrics of a canary backend must
+// meet for a progressive delivery to proceed.
+type ProgressiveDeliveryAnalysis struct {
+	// MinSuccessRate defines the minimum percentage of requests to the canary backend that must not fail
+	// with a 5xx response. Not checked if 0.
+	// +optional
+	MinSuccessRate float64 `json:"minSuccessRate,omitempty"`
+
+	// MaxLatency defines the maximum 99th percentile latency of requests to the canary backend.
+	// Not checked if unset.
+	// +optional
+	MaxLatency string `json:"maxLatency,omitempty"`
+
+	// MinRequests defines the minimum number of requests to the canary backend during an interval for the
+	// metrics to be analyzed, otherwise the delivery is paused until the canary backend receives enough traffic.
+	// Defaults to 1.
+	// +optional
+	MinRequests int `json:"minRequests,omitempty"`
+}
+
+// ProgressiveDeliveryList defines the list of ProgressiveDelivery objects.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ProgressiveDeliveryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ProgressiveDelivery `json:"items"`
+}
+
+// ProgressiveDeliveryPhase is the type used to represent the phase of a progressive delivery.
+type ProgressiveDeliveryPhase string
+
+const (
+	// ProgressiveDeliveryProgressing is the phase of a progressive delivery shifting traffic to the canary backend
+	ProgressiveDeliveryProgressing ProgressiveDeliveryPhase = "Progressing"
+
+	// ProgressiveDeliveryPaused is the phase of a progressive delivery that is paused, either by its spec or
+	// because the metrics of the canary backend cannot be analyzed
+	ProgressiveDeliveryPaused ProgressiveDeliveryPhase = "Paused"
+
+	// ProgressiveDeliverySucceeded is the phase of a progressive delivery whose canary backend reached its
+	// maximum weight
+	ProgressiveDeliverySucceeded ProgressiveDeliveryPhase = "Succeeded"
+
+	// ProgressiveDeliveryRolledBack is the phase of a progressive delivery whose traffic was rolled back after
+	// too many failed analyses
+	ProgressiveDeliveryRolledBack ProgressiveDeliveryPhase = "RolledBack"
+
+	// ProgressiveDeliveryFailed is the phase of a progressive delivery whose configuration is invalid
+	ProgressiveDeliveryFailed ProgressiveDeliveryPhase = "Failed"
+)
+
+// ProgressiveDeliveryStatus is the type used to represent the status of a ProgressiveDelivery resource.
+type ProgressiveDeliveryStatus struct {
+	// Phase defines the current phase of the progressive delivery.
+	// +optional
+	Phase ProgressiveDeliveryPhase `json:"phase,omitempty"`
+
+	// CanaryWeight defines the current weight of the canary backend.
+	// +optional
+	CanaryWeight int `json:"canaryWeight,omitempty"`
+
+	// FailedChecks defines the number of failed analyses since the delivery started.
+	// +optional
+	FailedChecks int `json:"failedChecks,omitempty"`
+
+	// LastStepTime defines the time of the last step or analysis of the delivery.
+	// +optional
+	LastStepTime metav1.Time `json:"lastStepTime,omitempty"`
+
+	// ObservedGeneration defines the generation of the spec the delivery started with. The delivery restarts
+	// when the spec changes.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Message defines a human readable description of the current phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
@@ -39,8 +39,18 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&Egress{},
 		&EgressList{},
+		&ExternalWorkload{},
+		&ExternalWorkloadList{},
 		&IngressBackend{},
 		&IngressBackendList{},
+		&NamespaceIsolation{},
+		&NamespaceIsolationList{},
+		&NamespaceOffboarding{},
+		&NamespaceOffboardingList{},
+		&ProgressiveDelivery{},
+		&ProgressiveDeliveryList{},
+		&UpstreamTrafficSetting{},
+		&UpstreamTrafficSettingList{},
 	)
 
 	metav1.AddToGroupVersion(
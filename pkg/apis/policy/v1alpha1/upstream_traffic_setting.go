@@ -0,0 +1,104 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// UpstreamTrafficSetting is the type used to represent the settings of the traffic to an upstream service.
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type UpstreamTrafficSetting struct {
+	// Object's type metadata
+	metav1.TypeMeta `json:",inline"`
+
+	// Object's metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the UpstreamTrafficSetting policy specification
+	// +optional
+	Spec UpstreamTrafficSettingSpec `json:"spec,omitempty"`
+}
+
+// UpstreamTrafficSettingSpec is the type used to represent the UpstreamTrafficSetting policy specification.
+type UpstreamTrafficSettingSpec struct {
+	// Host defines the upstream service the settings apply to, as its fully qualified domain name
+	// <service>.<namespace>.svc.cluster.local. The service must be in the UpstreamTrafficSetting's namespace.
+	Host string `json:"host"`
+
+	// PeerValidation defines how the certificates presented by the upstream service to its downstream
+	// clients are validated.
+	// +optional
+	PeerValidation *PeerValidationSpec `json:"peerValidation,omitempty"`
+
+	// RetryBudget defines the budget of the concurrent retries to the upstream service, which bounds the
+	// additional load retries put on the upstream service whatever the retry policies of its downstream clients.
+	// +optional
+	RetryBudget *RetryBudgetSpec `json:"retryBudget,omitempty"`
+
+	// RetryPolicy defines how the requests of the downstream clients to the upstream service are retried. The
+	// requests are not retried by default.
+	// +optional
+	RetryPolicy *RetryPolicySpec `json:"retryPolicy,omitempty"`
+}
+
+// PeerValidationSpec is the type used to represent the validation of the certificates presented by an upstream service.
+type PeerValidationSpec struct {
+	// TrustDomainAliases defines the trust domains, other than the cluster's own, the certificates of the upstream
+	// service's identities are accepted from. It overrides the trust domain aliases of the MeshConfig, which allows
+	// migrating the upstream service between trust domains independently of the other services.
+	// +optional
+	TrustDomainAliases []string `json:"trustDomainAliases,omitempty"`
+
+	// SubjectAltNames defines the Subject Alternative Names accepted in the certificates of the upstream service,
+	// in addition to those of its identities.
+	// +optional
+	SubjectAltNames []string `json:"subjectAltNames,omitempty"`
+}
+
+// RetryBudgetSpec is the type used to represent the budget of the concurrent retries to an upstream service.
+type RetryBudgetSpec struct {
+	// BudgetPercent defines the maximum percentage of the active requests to the upstream service that can be
+	// retries. Defaults to 20.
+	// +optional
+	BudgetPercent *uint32 `json:"budgetPercent,omitempty"`
+
+	// MinRetryConcurrency defines the number of concurrent retries allowed whatever the number of active
+	// requests, so that a low traffic upstream service can still be retried. Defaults to 3.
+	// +optional
+	MinRetryConcurrency *uint32 `json:"minRetryConcurrency,omitempty"`
+}
+
+// RetryPolicySpec is the type used to represent how the requests to an upstream service are retried.
+type RetryPolicySpec struct {
+	// RetryOn defines the conditions the requests are retried on, as a comma-separated list of the Envoy retry
+	// conditions, e.g. '5xx,reset'. Defaults to '5xx,reset,connect-failure'.
+	// +optional
+	RetryOn string `json:"retryOn,omitempty"`
+
+	// NumRetries defines the maximum number of retries of a request. Defaults to 1.
+	// +optional
+	NumRetries *uint32 `json:"numRetries,omitempty"`
+
+	// PerTryTimeout defines the timeout of each try of a request, including the initial request. The tries are
+	// only bounded by the timeout of the request if not set.
+	// +optional
+	PerTryTimeout *metav1.Duration `json:"perTryTimeout,omitempty"`
+
+	// Idempotent defines whether the requests to the upstream service can safely be sent more than once. The
+	// requests of idempotent HTTP methods (GET, HEAD, OPTIONS, TRACE, PUT and DELETE) to an idempotent upstream
+	// service whose try exceeds PerTryTimeout are hedged: they are retried without cancelling the outstanding try,
+	// and the first response to either is used, which cuts the tail latency of a slow upstream service. Requires
+	// PerTryTimeout.
+	// +optional
+	Idempotent bool `json:"idempotent,omitempty"`
+}
+
+// UpstreamTrafficSettingList defines the list of UpstreamTrafficSetting objects.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type UpstreamTrafficSettingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []UpstreamTrafficSetting `json:"items"`
+}
@@ -19,6 +19,7 @@ package v1alpha1
 
 import (
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -27,6 +28,16 @@ func (in *BackendSpec) DeepCopyInto(out *BackendSpec) {
 	*out = *in
 	out.Port = in.Port
 	in.TLS.DeepCopyInto(&out.TLS)
+	if in.Rewrite != nil {
+		in, out := &in.Rewrite, &out.Rewrite
+		*out = new(RewriteSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = new(HeadersSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -40,6 +51,101 @@ func (in *BackendSpec) DeepCopy() *BackendSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CORSSpec) DeepCopyInto(out *CORSSpec) {
+	*out = *in
+	if in.AllowOrigins != nil {
+		in, out := &in.AllowOrigins, &out.AllowOrigins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowMethods != nil {
+		in, out := &in.AllowMethods, &out.AllowMethods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowHeaders != nil {
+		in, out := &in.AllowHeaders, &out.AllowHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExposeHeaders != nil {
+		in, out := &in.ExposeHeaders, &out.ExposeHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CORSSpec.
+func (in *CORSSpec) DeepCopy() *CORSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CORSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CSRFSpec) DeepCopyInto(out *CSRFSpec) {
+	*out = *in
+	if in.AdditionalOrigins != nil {
+		in, out := &in.AdditionalOrigins, &out.AdditionalOrigins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CSRFSpec.
+func (in *CSRFSpec) DeepCopy() *CSRFSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CSRFSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientAddressSpec) DeepCopyInto(out *ClientAddressSpec) {
+	*out = *in
+	out.InternalAddressConfig = in.InternalAddressConfig
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientAddressSpec.
+func (in *ClientAddressSpec) DeepCopy() *ClientAddressSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientAddressSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CookieAttributesSpec) DeepCopyInto(out *CookieAttributesSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CookieAttributesSpec.
+func (in *CookieAttributesSpec) DeepCopy() *CookieAttributesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CookieAttributesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Egress) DeepCopyInto(out *Egress) {
 	*out = *in
@@ -159,6 +265,160 @@ func (in *EgressSpec) DeepCopy() *EgressSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalWorkload) DeepCopyInto(out *ExternalWorkload) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalWorkload.
+func (in *ExternalWorkload) DeepCopy() *ExternalWorkload {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalWorkload)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ExternalWorkload) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalWorkloadList) DeepCopyInto(out *ExternalWorkloadList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ExternalWorkload, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalWorkloadList.
+func (in *ExternalWorkloadList) DeepCopy() *ExternalWorkloadList {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalWorkloadList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ExternalWorkloadList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalWorkloadPort) DeepCopyInto(out *ExternalWorkloadPort) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalWorkloadPort.
+func (in *ExternalWorkloadPort) DeepCopy() *ExternalWorkloadPort {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalWorkloadPort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalWorkloadSpec) DeepCopyInto(out *ExternalWorkloadSpec) {
+	*out = *in
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]ExternalWorkloadPort, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalWorkloadSpec.
+func (in *ExternalWorkloadSpec) DeepCopy() *ExternalWorkloadSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalWorkloadSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HeaderValueSpec) DeepCopyInto(out *HeaderValueSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeaderValueSpec.
+func (in *HeaderValueSpec) DeepCopy() *HeaderValueSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HeaderValueSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HeadersSpec) DeepCopyInto(out *HeadersSpec) {
+	*out = *in
+	if in.RequestHeadersToAdd != nil {
+		in, out := &in.RequestHeadersToAdd, &out.RequestHeadersToAdd
+		*out = make([]HeaderValueSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.RequestHeadersToRemove != nil {
+		in, out := &in.RequestHeadersToRemove, &out.RequestHeadersToRemove
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResponseHeadersToAdd != nil {
+		in, out := &in.ResponseHeadersToAdd, &out.ResponseHeadersToAdd
+		*out = make([]HeaderValueSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResponseHeadersToRemove != nil {
+		in, out := &in.ResponseHeadersToRemove, &out.ResponseHeadersToRemove
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeadersSpec.
+func (in *HeadersSpec) DeepCopy() *HeadersSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HeadersSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IngressBackend) DeepCopyInto(out *IngressBackend) {
 	*out = *in
@@ -242,6 +502,26 @@ func (in *IngressBackendSpec) DeepCopyInto(out *IngressBackendSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CORS != nil {
+		in, out := &in.CORS, &out.CORS
+		*out = new(CORSSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = new(HeadersSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Security != nil {
+		in, out := &in.Security, &out.Security
+		*out = new(IngressSecuritySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientAddress != nil {
+		in, out := &in.ClientAddress, &out.ClientAddress
+		*out = new(ClientAddressSpec)
+		**out = **in
+	}
 	return
 }
 
@@ -271,6 +551,37 @@ func (in *IngressBackendStatus) DeepCopy() *IngressBackendStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressSecuritySpec) DeepCopyInto(out *IngressSecuritySpec) {
+	*out = *in
+	if in.CSRF != nil {
+		in, out := &in.CSRF, &out.CSRF
+		*out = new(CSRFSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResponseHeaders != nil {
+		in, out := &in.ResponseHeaders, &out.ResponseHeaders
+		*out = new(SecurityHeadersSpec)
+		**out = **in
+	}
+	if in.Cookies != nil {
+		in, out := &in.Cookies, &out.Cookies
+		*out = new(CookieAttributesSpec)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressSecuritySpec.
+func (in *IngressSecuritySpec) DeepCopy() *IngressSecuritySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressSecuritySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IngressSourceSpec) DeepCopyInto(out *IngressSourceSpec) {
 	*out = *in
@@ -288,38 +599,572 @@ func (in *IngressSourceSpec) DeepCopy() *IngressSourceSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PortSpec) DeepCopyInto(out *PortSpec) {
+func (in *InternalAddressConfigSpec) DeepCopyInto(out *InternalAddressConfigSpec) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PortSpec.
-func (in *PortSpec) DeepCopy() *PortSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternalAddressConfigSpec.
+func (in *InternalAddressConfigSpec) DeepCopy() *InternalAddressConfigSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(PortSpec)
+	out := new(InternalAddressConfigSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TLSSpec) DeepCopyInto(out *TLSSpec) {
+func (in *NamespaceIsolation) DeepCopyInto(out *NamespaceIsolation) {
 	*out = *in
-	if in.SNIHosts != nil {
-		in, out := &in.SNIHosts, &out.SNIHosts
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceIsolation.
+func (in *NamespaceIsolation) DeepCopy() *NamespaceIsolation {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceIsolation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespaceIsolation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceIsolationList) DeepCopyInto(out *NamespaceIsolationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NamespaceIsolation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSSpec.
-func (in *TLSSpec) DeepCopy() *TLSSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceIsolationList.
+func (in *NamespaceIsolationList) DeepCopy() *NamespaceIsolationList {
 	if in == nil {
 		return nil
 	}
-	out := new(TLSSpec)
+	out := new(NamespaceIsolationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespaceIsolationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceIsolationSpec) DeepCopyInto(out *NamespaceIsolationSpec) {
+	*out = *in
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceIsolationSpec.
+func (in *NamespaceIsolationSpec) DeepCopy() *NamespaceIsolationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceIsolationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceOffboarding) DeepCopyInto(out *NamespaceOffboarding) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceOffboarding.
+func (in *NamespaceOffboarding) DeepCopy() *NamespaceOffboarding {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceOffboarding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespaceOffboarding) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceOffboardingList) DeepCopyInto(out *NamespaceOffboardingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NamespaceOffboarding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceOffboardingList.
+func (in *NamespaceOffboardingList) DeepCopy() *NamespaceOffboardingList {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceOffboardingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespaceOffboardingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceOffboardingSpec) DeepCopyInto(out *NamespaceOffboardingSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceOffboardingSpec.
+func (in *NamespaceOffboardingSpec) DeepCopy() *NamespaceOffboardingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceOffboardingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceOffboardingStatus) DeepCopyInto(out *NamespaceOffboardingStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.RestartedWorkloads != nil {
+		in, out := &in.RestartedWorkloads, &out.RestartedWorkloads
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodsWithSidecars != nil {
+		in, out := &in.PodsWithSidecars, &out.PodsWithSidecars
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceOffboardingStatus.
+func (in *NamespaceOffboardingStatus) DeepCopy() *NamespaceOffboardingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceOffboardingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeerValidationSpec) DeepCopyInto(out *PeerValidationSpec) {
+	*out = *in
+	if in.TrustDomainAliases != nil {
+		in, out := &in.TrustDomainAliases, &out.TrustDomainAliases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SubjectAltNames != nil {
+		in, out := &in.SubjectAltNames, &out.SubjectAltNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeerValidationSpec.
+func (in *PeerValidationSpec) DeepCopy() *PeerValidationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PeerValidationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PortSpec) DeepCopyInto(out *PortSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PortSpec.
+func (in *PortSpec) DeepCopy() *PortSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PortSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProgressiveDelivery) DeepCopyInto(out *ProgressiveDelivery) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProgressiveDelivery.
+func (in *ProgressiveDelivery) DeepCopy() *ProgressiveDelivery {
+	if in == nil {
+		return nil
+	}
+	out := new(ProgressiveDelivery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProgressiveDelivery) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProgressiveDeliveryAnalysis) DeepCopyInto(out *ProgressiveDeliveryAnalysis) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProgressiveDeliveryAnalysis.
+func (in *ProgressiveDeliveryAnalysis) DeepCopy() *ProgressiveDeliveryAnalysis {
+	if in == nil {
+		return nil
+	}
+	out := new(ProgressiveDeliveryAnalysis)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProgressiveDeliveryList) DeepCopyInto(out *ProgressiveDeliveryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ProgressiveDelivery, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProgressiveDeliveryList.
+func (in *ProgressiveDeliveryList) DeepCopy() *ProgressiveDeliveryList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProgressiveDeliveryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProgressiveDeliveryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProgressiveDeliverySpec) DeepCopyInto(out *ProgressiveDeliverySpec) {
+	*out = *in
+	out.Analysis = in.Analysis
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProgressiveDeliverySpec.
+func (in *ProgressiveDeliverySpec) DeepCopy() *ProgressiveDeliverySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProgressiveDeliverySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProgressiveDeliveryStatus) DeepCopyInto(out *ProgressiveDeliveryStatus) {
+	*out = *in
+	in.LastStepTime.DeepCopyInto(&out.LastStepTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProgressiveDeliveryStatus.
+func (in *ProgressiveDeliveryStatus) DeepCopy() *ProgressiveDeliveryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProgressiveDeliveryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegexRewriteSpec) DeepCopyInto(out *RegexRewriteSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegexRewriteSpec.
+func (in *RegexRewriteSpec) DeepCopy() *RegexRewriteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RegexRewriteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryBudgetSpec) DeepCopyInto(out *RetryBudgetSpec) {
+	*out = *in
+	if in.BudgetPercent != nil {
+		in, out := &in.BudgetPercent, &out.BudgetPercent
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.MinRetryConcurrency != nil {
+		in, out := &in.MinRetryConcurrency, &out.MinRetryConcurrency
+		*out = new(uint32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryBudgetSpec.
+func (in *RetryBudgetSpec) DeepCopy() *RetryBudgetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryBudgetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicySpec) DeepCopyInto(out *RetryPolicySpec) {
+	*out = *in
+	if in.NumRetries != nil {
+		in, out := &in.NumRetries, &out.NumRetries
+		*out = new(uint32)
+		**out = **in
+	}
+	if in.PerTryTimeout != nil {
+		in, out := &in.PerTryTimeout, &out.PerTryTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicySpec.
+func (in *RetryPolicySpec) DeepCopy() *RetryPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RewriteSpec) DeepCopyInto(out *RewriteSpec) {
+	*out = *in
+	if in.RegexRewrite != nil {
+		in, out := &in.RegexRewrite, &out.RegexRewrite
+		*out = new(RegexRewriteSpec)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RewriteSpec.
+func (in *RewriteSpec) DeepCopy() *RewriteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RewriteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityHeadersSpec) DeepCopyInto(out *SecurityHeadersSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityHeadersSpec.
+func (in *SecurityHeadersSpec) DeepCopy() *SecurityHeadersSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityHeadersSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSSpec) DeepCopyInto(out *TLSSpec) {
+	*out = *in
+	if in.SNIHosts != nil {
+		in, out := &in.SNIHosts, &out.SNIHosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSSpec.
+func (in *TLSSpec) DeepCopy() *TLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpstreamTrafficSetting) DeepCopyInto(out *UpstreamTrafficSetting) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpstreamTrafficSetting.
+func (in *UpstreamTrafficSetting) DeepCopy() *UpstreamTrafficSetting {
+	if in == nil {
+		return nil
+	}
+	out := new(UpstreamTrafficSetting)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *UpstreamTrafficSetting) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpstreamTrafficSettingList) DeepCopyInto(out *UpstreamTrafficSettingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]UpstreamTrafficSetting, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpstreamTrafficSettingList.
+func (in *UpstreamTrafficSettingList) DeepCopy() *UpstreamTrafficSettingList {
+	if in == nil {
+		return nil
+	}
+	out := new(UpstreamTrafficSettingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *UpstreamTrafficSettingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpstreamTrafficSettingSpec) DeepCopyInto(out *UpstreamTrafficSettingSpec) {
+	*out = *in
+	if in.PeerValidation != nil {
+		in, out := &in.PeerValidation, &out.PeerValidation
+		*out = new(PeerValidationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RetryBudget != nil {
+		in, out := &in.RetryBudget, &out.RetryBudget
+		*out = new(RetryBudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpstreamTrafficSettingSpec.
+func (in *UpstreamTrafficSettingSpec) DeepCopy() *UpstreamTrafficSettingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UpstreamTrafficSettingSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -0,0 +1,117 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// FileName returns the name of the backup archive
+func (b *Backup) FileName() string {
+	return fmt.Sprintf("osm-backup-%s-%s.tar.gz", b.Metadata.MeshName, b.Metadata.CreatedAt.UTC().Format("20060102-150405"))
+}
+
+// Write writes the backup to the given writer, as a gzipped tar archive of JSON files
+func (b *Backup) Write(w io.Writer) error {
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	if err := writeJSONFile(tarWriter, metadataFile, b.Metadata); err != nil {
+		return err
+	}
+	for _, resourceObjects := range b.Resources {
+		if err := writeJSONFile(tarWriter, resourceFileName(resourceObjects.Resource), resourceObjects); err != nil {
+			return err
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return errors.Wrap(err, "Error closing backup archive")
+	}
+	return gzipWriter.Close()
+}
+
+// Load reads a backup from the given reader, as written by Backup.Write. Archives of a format version other than
+// FormatVersion are rejected.
+func Load(r io.Reader) (*Backup, error) {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading backup archive")
+	}
+	defer gzipReader.Close() //nolint: errcheck,gosec
+
+	backup := &Backup{}
+	hasMetadata := false
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "Error reading backup archive")
+		}
+
+		content, err := ioutil.ReadAll(tarReader)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error reading file %s of backup archive", header.Name)
+		}
+
+		switch {
+		case header.Name == metadataFile:
+			err = json.Unmarshal(content, &backup.Metadata)
+			hasMetadata = true
+		case strings.HasPrefix(header.Name, resourcesDir):
+			var resourceObjects ResourceObjects
+			if err = json.Unmarshal(content, &resourceObjects); err == nil {
+				backup.Resources = append(backup.Resources, resourceObjects)
+			}
+		default:
+			log.Debug().Msgf("Ignoring unknown file %s of backup archive", header.Name)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error decoding file %s of backup archive", header.Name)
+		}
+	}
+
+	if !hasMetadata {
+		return nil, errors.Errorf("Backup archive is missing %s", metadataFile)
+	}
+	if backup.Metadata.FormatVersion != FormatVersion {
+		return nil, errors.Errorf("Unsupported backup format version %s, expected %s", backup.Metadata.FormatVersion, FormatVersion)
+	}
+
+	return backup, nil
+}
+
+// resourceFileName returns the name of the file of the backup archive holding the objects of the given resource
+func resourceFileName(resource Resource) string {
+	return fmt.Sprintf("%s%s/%s/%s.json", resourcesDir, resource.Group, resource.Version, resource.Resource)
+}
+
+func writeJSONFile(tarWriter *tar.Writer, name string, value interface{}) error {
+	content, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "Error marshaling %s", name)
+	}
+
+	header := &tar.Header{
+		Name: name,
+		Mode: 0600,
+		Size: int64(len(content)),
+	}
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return errors.Wrapf(err, "Error writing header of %s", name)
+	}
+	if _, err := tarWriter.Write(content); err != nil {
+		return errors.Wrapf(err, "Error writing %s", name)
+	}
+	return nil
+}
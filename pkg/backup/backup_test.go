@@ -0,0 +1,245 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+const (
+	testOSMNamespace   = "osm-system"
+	testMeshName       = "osm"
+	testMeshConfigName = "osm-mesh-config"
+)
+
+func newObject(apiVersion, kind, namespace, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"name":            name,
+			"namespace":       namespace,
+			"resourceVersion": "42",
+			"uid":             "0a1b2c",
+		},
+		"spec":   spec,
+		"status": map[string]interface{}{"currentStatus": "committed"},
+	}}
+}
+
+func newDynamicClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	listKinds := map[schema.GroupVersionResource]string{
+		meshConfigResource.GroupVersionResource(): "MeshConfigList",
+	}
+	for _, resource := range namespacedResources {
+		listKinds[resource.GroupVersionResource()] = "List"
+	}
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...)
+}
+
+func TestExport(t *testing.T) {
+	assert := tassert.New(t)
+
+	kubeClient := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-1", Labels: map[string]string{constants.OSMKubeResourceMonitorAnnotation: testMeshName}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-2"}},
+	)
+	dynamicClient := newDynamicClient(
+		newObject("config.openservicemesh.io/v1alpha2", "MeshConfig", testOSMNamespace, testMeshConfigName, map[string]interface{}{"sidecar": map[string]interface{}{"logLevel": "error"}}),
+		newObject("policy.openservicemesh.io/v1alpha1", "Egress", "ns-1", "egress-b", map[string]interface{}{"hosts": []interface{}{"b.com"}}),
+		newObject("policy.openservicemesh.io/v1alpha1", "Egress", "ns-1", "egress-a", map[string]interface{}{"hosts": []interface{}{"a.com"}}),
+		newObject("policy.openservicemesh.io/v1alpha1", "Egress", "ns-2", "egress-c", map[string]interface{}{"hosts": []interface{}{"c.com"}}),
+		newObject("split.smi-spec.io/v1alpha4", "TrafficSplit", "ns-1", "split", map[string]interface{}{"service": "bookstore"}),
+	)
+
+	backup, err := NewExporter(kubeClient, dynamicClient, testOSMNamespace, testMeshName, testMeshConfigName).Export()
+	assert.Nil(err)
+	assert.Equal(FormatVersion, backup.Metadata.FormatVersion)
+	assert.Equal(testMeshName, backup.Metadata.MeshName)
+	assert.Len(backup.Resources, len(namespacedResources)+1)
+
+	objectNames := make(map[string][]string)
+	for _, resourceObjects := range backup.Resources {
+		for _, object := range resourceObjects.Objects {
+			objectNames[resourceObjects.Resource.Resource] = append(objectNames[resourceObjects.Resource.Resource], object.GetNamespace()+"/"+object.GetName())
+			assert.Empty(object.GetResourceVersion())
+			assert.Empty(object.GetUID())
+			_, hasStatus := object.Object["status"]
+			assert.False(hasStatus)
+		}
+	}
+	assert.Equal(map[string][]string{
+		"meshconfigs":   {"osm-system/osm-mesh-config"},
+		"egresses":      {"ns-1/egress-a", "ns-1/egress-b"},
+		"trafficsplits": {"ns-1/split"},
+	}, objectNames)
+
+	_, err = NewExporter(kubeClient, newDynamicClient(), testOSMNamespace, testMeshName, testMeshConfigName).Export()
+	assert.NotNil(err)
+}
+
+func TestWriteLoad(t *testing.T) {
+	assert := tassert.New(t)
+
+	backup := &Backup{
+		Metadata: Metadata{FormatVersion: FormatVersion, OSMNamespace: testOSMNamespace, MeshName: testMeshName},
+		Resources: []ResourceObjects{
+			{Resource: meshConfigResource, Objects: []unstructured.Unstructured{*stripObject(newObject("config.openservicemesh.io/v1alpha2", "MeshConfig", testOSMNamespace, testMeshConfigName, map[string]interface{}{}))}},
+			{Resource: namespacedResources[1], Objects: []unstructured.Unstructured{}},
+		},
+	}
+
+	var archive bytes.Buffer
+	assert.Nil(backup.Write(&archive))
+
+	loaded, err := Load(&archive)
+	assert.Nil(err)
+	assert.Equal(backup.Metadata, loaded.Metadata)
+	assert.Equal(backup.Resources, loaded.Resources)
+
+	backup.Metadata.FormatVersion = "v0"
+	archive.Reset()
+	assert.Nil(backup.Write(&archive))
+	_, err = Load(&archive)
+	assert.EqualError(err, "Unsupported backup format version v0, expected v1")
+}
+
+func TestRestore(t *testing.T) {
+	egressResource := namespacedResources[1]
+	newEgress := func(name, host string) *unstructured.Unstructured {
+		return stripObject(newObject("policy.openservicemesh.io/v1alpha1", "Egress", "ns-1", name, map[string]interface{}{"hosts": []interface{}{host}}))
+	}
+	backup := &Backup{
+		Metadata: Metadata{FormatVersion: FormatVersion, OSMNamespace: testOSMNamespace, MeshName: testMeshName},
+		Resources: []ResourceObjects{
+			{Resource: meshConfigResource, Objects: []unstructured.Unstructured{*stripObject(newObject("config.openservicemesh.io/v1alpha2", "MeshConfig", testOSMNamespace, testMeshConfigName, map[string]interface{}{}))}},
+			{Resource: egressResource, Objects: []unstructured.Unstructured{*newEgress("new", "a.com"), *newEgress("same", "b.com"), *newEgress("changed", "c.com")}},
+		},
+	}
+	existingObjects := func() []runtime.Object {
+		return []runtime.Object{newEgress("same", "b.com"), newEgress("changed", "other.com")}
+	}
+
+	testCases := []struct {
+		name         string
+		opts         RestoreOptions
+		expErr       bool
+		expActions   map[string]RestoreAction
+		expNamespace string
+		expHost      string
+	}{
+		{
+			name: "existing objects differing from the backup are skipped",
+			opts: RestoreOptions{ConflictPolicy: ConflictPolicySkip},
+			expActions: map[string]RestoreAction{
+				testMeshConfigName: RestoreActionCreated,
+				"new":              RestoreActionCreated,
+				"same":             RestoreActionUnchanged,
+				"changed":          RestoreActionSkipped,
+			},
+			expNamespace: testOSMNamespace,
+			expHost:      "other.com",
+		},
+		{
+			name: "existing objects differing from the backup are overwritten",
+			opts: RestoreOptions{ConflictPolicy: ConflictPolicyOverwrite, OSMNamespace: "other-system"},
+			expActions: map[string]RestoreAction{
+				testMeshConfigName: RestoreActionCreated,
+				"new":              RestoreActionCreated,
+				"same":             RestoreActionUnchanged,
+				"changed":          RestoreActionUpdated,
+			},
+			expNamespace: "other-system",
+			expHost:      "c.com",
+		},
+		{
+			name: "dry run does not modify objects",
+			opts: RestoreOptions{ConflictPolicy: ConflictPolicyOverwrite, DryRun: true},
+			expActions: map[string]RestoreAction{
+				testMeshConfigName: RestoreActionCreated,
+				"new":              RestoreActionCreated,
+				"same":             RestoreActionUnchanged,
+				"changed":          RestoreActionUpdated,
+			},
+			expHost: "other.com",
+		},
+		{
+			name:   "unsupported conflict policy fails",
+			opts:   RestoreOptions{ConflictPolicy: "merge"},
+			expErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			dynamicClient := newDynamicClient(existingObjects()...)
+			results, err := Restore(dynamicClient, backup, tc.opts)
+			if tc.expErr {
+				assert.NotNil(err)
+				return
+			}
+			assert.Nil(err)
+
+			actions := make(map[string]RestoreAction)
+			for _, result := range results {
+				actions[result.Name] = result.Action
+			}
+			assert.Equal(tc.expActions, actions)
+
+			changed, err := dynamicClient.Resource(egressResource.GroupVersionResource()).Namespace("ns-1").Get(context.Background(), "changed", metav1.GetOptions{})
+			assert.Nil(err)
+			hosts, _, _ := unstructured.NestedStringSlice(changed.Object, "spec", "hosts")
+			assert.Equal([]string{tc.expHost}, hosts)
+
+			_, err = dynamicClient.Resource(egressResource.GroupVersionResource()).Namespace("ns-1").Get(context.Background(), "new", metav1.GetOptions{})
+			assert.Equal(tc.opts.DryRun, err != nil)
+			if tc.expNamespace != "" {
+				_, err = dynamicClient.Resource(meshConfigResource.GroupVersionResource()).Namespace(tc.expNamespace).Get(context.Background(), testMeshConfigName, metav1.GetOptions{})
+				assert.Nil(err)
+			}
+
+			// Restoring again leaves every object unchanged, unless it was skipped
+			if tc.opts.DryRun {
+				return
+			}
+			results, err = Restore(dynamicClient, backup, tc.opts)
+			assert.Nil(err)
+			for _, result := range results {
+				if result.Name != "changed" || tc.opts.ConflictPolicy == ConflictPolicyOverwrite {
+					assert.Equal(RestoreActionUnchanged, result.Action, result.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestGetBackupHTTPHandler(t *testing.T) {
+	assert := tassert.New(t)
+
+	dynamicClient := newDynamicClient(newObject("config.openservicemesh.io/v1alpha2", "MeshConfig", testOSMNamespace, testMeshConfigName, map[string]interface{}{}))
+	handler := NewExporter(fake.NewSimpleClientset(), dynamicClient, testOSMNamespace, testMeshName, testMeshConfigName).GetBackupHTTPHandler()
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, constants.HTTPServerBackupPath, nil))
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.Equal("application/gzip", rr.Header().Get("Content-Type"))
+
+	backup, err := Load(rr.Body)
+	assert.Nil(err)
+	assert.Equal(testMeshName, backup.Metadata.MeshName)
+}
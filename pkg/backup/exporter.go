@@ -0,0 +1,172 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/version"
+)
+
+// meshConfigResource is the MeshConfig resource, backed up from the OSM namespace
+var meshConfigResource = Resource{Group: "config.openservicemesh.io", Version: "v1alpha2", Resource: "meshconfigs"}
+
+// namespacedResources are the resources backed up from the monitored namespaces, in the order they are restored. A
+// policy resource is backed up once added to this list.
+var namespacedResources = []Resource{
+	{Group: "config.openservicemesh.io", Version: "v1alpha1", Resource: "multiclusterservices"},
+	{Group: "policy.openservicemesh.io", Version: "v1alpha1", Resource: "egresses"},
+	{Group: "policy.openservicemesh.io", Version: "v1alpha1", Resource: "ingressbackends"},
+	{Group: "policy.openservicemesh.io", Version: "v1alpha1", Resource: "upstreamtrafficsettings"},
+	{Group: "policy.openservicemesh.io", Version: "v1alpha1", Resource: "externalworkloads"},
+	{Group: "policy.openservicemesh.io", Version: "v1alpha1", Resource: "namespaceisolations"},
+	{Group: "policy.openservicemesh.io", Version: "v1alpha1", Resource: "progressivedeliveries"},
+	{Group: "specs.smi-spec.io", Version: "v1alpha4", Resource: "httproutegroups"},
+	{Group: "specs.smi-spec.io", Version: "v1alpha4", Resource: "tcproutes"},
+	{Group: "access.smi-spec.io", Version: "v1alpha3", Resource: "traffictargets"},
+	{Group: "split.smi-spec.io", Version: "v1alpha4", Resource: "trafficsplits"},
+}
+
+// serverSetMetadataFields are the metadata fields set by the API server, which are not backed up
+var serverSetMetadataFields = []string{"resourceVersion", "uid", "creationTimestamp", "generation", "managedFields", "selfLink", "ownerReferences", "deletionTimestamp", "deletionGracePeriodSeconds"}
+
+// GroupVersionResource returns the GroupVersionResource of the resource
+func (r Resource) GroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: r.Group, Version: r.Version, Resource: r.Resource}
+}
+
+// String returns the resource as <resource>.<version>.<group>
+func (r Resource) String() string {
+	return fmt.Sprintf("%s.%s.%s", r.Resource, r.Version, r.Group)
+}
+
+// NewExporter returns an Exporter of backups of the policy state of the given mesh, whose control plane runs in the
+// given namespace with the given MeshConfig.
+func NewExporter(kubeClient kubernetes.Interface, dynamicClient dynamic.Interface, osmNamespace, meshName, meshConfigName string) *Exporter {
+	return &Exporter{
+		kubeClient:     kubeClient,
+		dynamicClient:  dynamicClient,
+		osmNamespace:   osmNamespace,
+		meshName:       meshName,
+		meshConfigName: meshConfigName,
+	}
+}
+
+// GetBackupHTTPHandler returns an HTTP handler responding with a backup archive of the current policy state of the
+// mesh.
+func (e *Exporter) GetBackupHTTPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		backup, err := e.Export()
+		if err != nil {
+			log.Error().Err(err).Msg("Error exporting backup")
+			http.Error(w, "Error exporting backup", http.StatusInternalServerError)
+			return
+		}
+
+		// The archive is built in memory, so that an error is reported with the status of the response
+		var archive bytes.Buffer
+		if err := backup.Write(&archive); err != nil {
+			log.Error().Err(err).Msg("Error writing backup")
+			http.Error(w, "Error writing backup", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", backup.FileName()))
+		_, _ = w.Write(archive.Bytes())
+	})
+}
+
+// Export captures the current policy state of the mesh in a backup. A backup is all or nothing: an error listing any
+// resource other than one whose CRD is not installed fails the export.
+func (e *Exporter) Export() (*Backup, error) {
+	backup := &Backup{
+		Metadata: Metadata{
+			FormatVersion: FormatVersion,
+			OSMNamespace:  e.osmNamespace,
+			MeshName:      e.meshName,
+			Version:       version.Version,
+			CreatedAt:     time.Now(),
+		},
+	}
+
+	meshConfigs := ResourceObjects{Resource: meshConfigResource, Objects: []unstructured.Unstructured{}}
+	meshConfig, err := e.dynamicClient.Resource(meshConfigResource.GroupVersionResource()).Namespace(e.osmNamespace).Get(context.Background(), e.meshConfigName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error getting MeshConfig %s/%s", e.osmNamespace, e.meshConfigName)
+	}
+	meshConfigs.Objects = append(meshConfigs.Objects, *stripObject(meshConfig))
+	backup.Resources = append(backup.Resources, meshConfigs)
+
+	monitoredNamespaces, err := e.listMonitoredNamespaces()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, resource := range namespacedResources {
+		objects := ResourceObjects{Resource: resource, Objects: []unstructured.Unstructured{}}
+		list, err := e.dynamicClient.Resource(resource.GroupVersionResource()).Namespace(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+		if apierrors.IsNotFound(err) {
+			log.Debug().Msgf("Resource %s is not served, skipping it", resource)
+			backup.Resources = append(backup.Resources, objects)
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error listing %s", resource)
+		}
+
+		for i := range list.Items {
+			if _, ok := monitoredNamespaces[list.Items[i].GetNamespace()]; ok {
+				objects.Objects = append(objects.Objects, *stripObject(&list.Items[i]))
+			}
+		}
+		sort.Slice(objects.Objects, func(i, j int) bool {
+			if objects.Objects[i].GetNamespace() != objects.Objects[j].GetNamespace() {
+				return objects.Objects[i].GetNamespace() < objects.Objects[j].GetNamespace()
+			}
+			return objects.Objects[i].GetName() < objects.Objects[j].GetName()
+		})
+		backup.Resources = append(backup.Resources, objects)
+	}
+
+	return backup, nil
+}
+
+// listMonitoredNamespaces returns the set of the namespaces monitored by the mesh
+func (e *Exporter) listMonitoredNamespaces() (map[string]struct{}, error) {
+	namespaces, err := e.kubeClient.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", constants.OSMKubeResourceMonitorAnnotation, e.meshName),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error listing the namespaces monitored by mesh %s", e.meshName)
+	}
+
+	monitoredNamespaces := make(map[string]struct{})
+	for _, ns := range namespaces.Items {
+		monitoredNamespaces[ns.Name] = struct{}{}
+	}
+	return monitoredNamespaces, nil
+}
+
+// stripObject returns a copy of the given object without the metadata fields set by the API server and without
+// status, which is owned by the controllers and recomputed once the object is restored
+func stripObject(object *unstructured.Unstructured) *unstructured.Unstructured {
+	stripped := object.DeepCopy()
+	for _, field := range serverSetMetadataFields {
+		unstructured.RemoveNestedField(stripped.Object, "metadata", field)
+	}
+	unstructured.RemoveNestedField(stripped.Object, "status")
+	return stripped
+}
@@ -0,0 +1,99 @@
+package backup
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// Restore restores the objects of the given backup, in the order of its resources, and returns the outcome of the
+// restore of each object. The restore is idempotent: the objects matching the backup are left unchanged, and the
+// existing objects differing from it are kept or overwritten according to the conflict policy. An object matches the
+// backup if its labels, annotations and content other than metadata and status are the same. The restore goes on when
+// an object fails to be restored, its result being RestoreActionFailed.
+func Restore(dynamicClient dynamic.Interface, backup *Backup, opts RestoreOptions) ([]RestoreResult, error) {
+	switch opts.ConflictPolicy {
+	case ConflictPolicySkip, ConflictPolicyOverwrite:
+	default:
+		return nil, errors.Errorf("Unsupported conflict policy %q, expected one of [%s %s]", opts.ConflictPolicy, ConflictPolicySkip, ConflictPolicyOverwrite)
+	}
+
+	var results []RestoreResult
+	for _, resourceObjects := range backup.Resources {
+		for i := range resourceObjects.Objects {
+			object := resourceObjects.Objects[i].DeepCopy()
+			if resourceObjects.Resource == meshConfigResource && opts.OSMNamespace != "" {
+				object.SetNamespace(opts.OSMNamespace)
+			}
+
+			resourceClient := dynamicClient.Resource(resourceObjects.GroupVersionResource()).Namespace(object.GetNamespace())
+			action, err := restoreObject(resourceClient, object, opts)
+			result := RestoreResult{
+				Kind:      object.GetKind(),
+				Namespace: object.GetNamespace(),
+				Name:      object.GetName(),
+				Action:    action,
+			}
+			if err != nil {
+				log.Error().Err(err).Msgf("Error restoring %s %s/%s", object.GetKind(), object.GetNamespace(), object.GetName())
+				result.Action = RestoreActionFailed
+				result.Message = err.Error()
+			}
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// restoreObject restores the given object and returns the outcome of its restore
+func restoreObject(resourceClient dynamic.ResourceInterface, object *unstructured.Unstructured, opts RestoreOptions) (RestoreAction, error) {
+	existing, err := resourceClient.Get(context.Background(), object.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		if !opts.DryRun {
+			if _, err := resourceClient.Create(context.Background(), object, metav1.CreateOptions{}); err != nil {
+				return RestoreActionFailed, err
+			}
+		}
+		return RestoreActionCreated, nil
+	}
+	if err != nil {
+		return RestoreActionFailed, err
+	}
+
+	if matchesBackup(existing, object) {
+		return RestoreActionUnchanged, nil
+	}
+	if opts.ConflictPolicy == ConflictPolicySkip {
+		return RestoreActionSkipped, nil
+	}
+
+	if !opts.DryRun {
+		object.SetResourceVersion(existing.GetResourceVersion())
+		if _, err := resourceClient.Update(context.Background(), object, metav1.UpdateOptions{}); err != nil {
+			return RestoreActionFailed, err
+		}
+	}
+	return RestoreActionUpdated, nil
+}
+
+// matchesBackup returns whether the given existing object matches the given backed up object: same labels,
+// annotations and content other than metadata and status
+func matchesBackup(existing, backedUp *unstructured.Unstructured) bool {
+	if !equality.Semantic.DeepEqual(existing.GetLabels(), backedUp.GetLabels()) ||
+		!equality.Semantic.DeepEqual(existing.GetAnnotations(), backedUp.GetAnnotations()) {
+		return false
+	}
+
+	existingContent := existing.DeepCopy().Object
+	backedUpContent := backedUp.DeepCopy().Object
+	for _, content := range []map[string]interface{}{existingContent, backedUpContent} {
+		delete(content, "metadata")
+		delete(content, "status")
+	}
+	return equality.Semantic.DeepEqual(existingContent, backedUpContent)
+}
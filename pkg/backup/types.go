@@ -0,0 +1,146 @@
+// Package backup implements the backup of the policy state of a mesh: the MeshConfig, and the OSM and SMI resources of
+// the monitored namespaces, exported as a versioned archive. It also implements the idempotent restore of a backup,
+// optionally as a dry run, resolving the conflicts with the existing resources according to a conflict policy.
+package backup
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var (
+	log = logger.New("backup")
+)
+
+// FormatVersion is the version of the format of the backup archives written, bumped whenever the format changes in a
+// way older releases cannot restore
+const FormatVersion = "v1"
+
+// Names of the files of a backup archive
+const (
+	metadataFile = "metadata.json"
+
+	// resourcesDir holds a file per backed up resource with its objects, named <group>/<version>/<resource>.json
+	resourcesDir = "resources/"
+)
+
+// Metadata describes the mesh a backup was exported from.
+type Metadata struct {
+	// FormatVersion is the version of the format of the backup archive
+	FormatVersion string `json:"formatVersion"`
+
+	// OSMNamespace is the namespace of the control plane
+	OSMNamespace string `json:"osmNamespace"`
+
+	// MeshName is the name of the mesh
+	MeshName string `json:"meshName"`
+
+	// Version is the version of the controller
+	Version string `json:"version"`
+
+	// CreatedAt is the time the backup was exported
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Resource identifies a backed up resource
+type Resource struct {
+	// Group is the API group of the resource
+	Group string `json:"group"`
+
+	// Version is the API version the objects of the resource are backed up in
+	Version string `json:"version"`
+
+	// Resource is the plural name of the resource
+	Resource string `json:"resource"`
+}
+
+// ResourceObjects are the backed up objects of a resource
+type ResourceObjects struct {
+	Resource
+
+	// Objects are the objects of the resource, stripped of the fields set by the API server and of their status
+	Objects []unstructured.Unstructured `json:"objects"`
+}
+
+// Backup is the content of a backup.
+type Backup struct {
+	Metadata Metadata
+
+	// Resources are the objects backed up, per resource in the order they are restored
+	Resources []ResourceObjects
+}
+
+// Exporter exports backups of the policy state of a mesh.
+type Exporter struct {
+	kubeClient     kubernetes.Interface
+	dynamicClient  dynamic.Interface
+	osmNamespace   string
+	meshName       string
+	meshConfigName string
+}
+
+// ConflictPolicy is the resolution of the conflicts between the objects of a backup and the existing objects
+type ConflictPolicy string
+
+const (
+	// ConflictPolicySkip keeps the existing objects differing from the backup
+	ConflictPolicySkip ConflictPolicy = "skip"
+
+	// ConflictPolicyOverwrite overwrites the existing objects differing from the backup
+	ConflictPolicyOverwrite ConflictPolicy = "overwrite"
+)
+
+// RestoreOptions are the options of the restore of a backup
+type RestoreOptions struct {
+	// OSMNamespace is the namespace the MeshConfig is restored to, the namespace it was backed up from if empty
+	OSMNamespace string
+
+	// ConflictPolicy is the resolution of the conflicts with the existing objects
+	ConflictPolicy ConflictPolicy
+
+	// DryRun reports the outcome of the restore without modifying any object
+	DryRun bool
+}
+
+// RestoreAction is the outcome of the restore of an object
+type RestoreAction string
+
+const (
+	// RestoreActionCreated signifies the object did not exist and was created
+	RestoreActionCreated RestoreAction = "created"
+
+	// RestoreActionUpdated signifies the existing object differed from the backup and was overwritten
+	RestoreActionUpdated RestoreAction = "updated"
+
+	// RestoreActionUnchanged signifies the existing object matched the backup
+	RestoreActionUnchanged RestoreAction = "unchanged"
+
+	// RestoreActionSkipped signifies the existing object differed from the backup and was kept
+	RestoreActionSkipped RestoreAction = "skipped"
+
+	// RestoreActionFailed signifies the object could not be restored
+	RestoreActionFailed RestoreAction = "failed"
+)
+
+// RestoreResult is the outcome of the restore of an object
+type RestoreResult struct {
+	// Kind is the kind of the object
+	Kind string `json:"kind"`
+
+	// Namespace is the namespace of the object
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the object
+	Name string `json:"name"`
+
+	// Action is the outcome of the restore of the object
+	Action RestoreAction `json:"action"`
+
+	// Message details the outcome, set when the object could not be restored
+	Message string `json:"message,omitempty"`
+}
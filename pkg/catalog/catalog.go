@@ -13,19 +13,23 @@ import (
 )
 
 // NewMeshCatalog creates a new service catalog
-func NewMeshCatalog(kubeController k8s.Controller, meshSpec smi.MeshSpec, certManager certificate.Manager, ingressMonitor ingress.Monitor, policyController policy.Controller, stop <-chan struct{}, cfg configurator.Configurator, serviceProviders []service.Provider, endpointsProviders []endpoint.Provider) *MeshCatalog {
+func NewMeshCatalog(kubeController k8s.Controller, meshSpec smi.MeshSpec, certManager certificate.Manager, ingressMonitor ingress.Monitor, policyController policy.Controller, stop <-chan struct{}, cfg configurator.Configurator, serviceProviders []service.Provider, endpointsProviders []endpoint.Provider, externalEndpointsProviders []ExternalEndpointsProvider, numShards int) *MeshCatalog {
 	log.Info().Msg("Create a new Service MeshCatalog.")
 	mc := MeshCatalog{
-		serviceProviders:   serviceProviders,
-		endpointsProviders: endpointsProviders,
-		meshSpec:           meshSpec,
-		certManager:        certManager,
-		ingressMonitor:     ingressMonitor,
-		policyController:   policyController,
-		configurator:       cfg,
+		serviceProviders:           serviceProviders,
+		endpointsProviders:         endpointsProviders,
+		externalEndpointsProviders: externalEndpointsProviders,
+		meshSpec:                   meshSpec,
+		certManager:                certManager,
+		ingressMonitor:             ingressMonitor,
+		policyController:           policyController,
+		configurator:               cfg,
 
 		kubeController: kubeController,
+
+		globalDispatchLoop: newDispatchLoop(globalShardLabel, cfg),
 	}
+	mc.namespaceShards, mc.shardDispatchLoops = newShardDispatchLoops(numShards, cfg)
 
 	go mc.dispatcher()
 	ticker.InitTicker(cfg)
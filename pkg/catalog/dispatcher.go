@@ -2,29 +2,133 @@ package catalog
 
 import (
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
+	access "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	a "github.com/openservicemesh/osm/pkg/announcements"
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy"
 	"github.com/openservicemesh/osm/pkg/k8s/events"
 	"github.com/openservicemesh/osm/pkg/metricsstore"
 )
 
 const (
-	// maxBroadcastDeadlineTime is the max time we will delay a global proxy update
-	// if multiple events that would trigger it get coalesced over time.
-	maxBroadcastDeadlineTime = 15 * time.Second
-	// maxGraceDeadlineTime is the time we will wait for an additional global proxy update
-	// trigger if we just received one.
-	maxGraceDeadlineTime = 3 * time.Second
+	// dispatchLoopQueueSize is the number of events that can be queued for a dispatch loop
+	// before routing events to it blocks.
+	dispatchLoopQueueSize = 1024
 )
 
+// meshConfigChangeTypeURIs are the xDS types of the proxy config affected by MeshConfig field-level changes. The
+// changes that are not listed here affect the whole proxy config.
+var meshConfigChangeTypeURIs = map[a.AnnouncementType][]envoy.TypeURI{
+	a.MeshConfigTerminatingEndpointsChanged:  {envoy.TypeEDS},
+	a.MeshConfigClientCertDetailsChanged:     {envoy.TypeLDS},
+	a.MeshConfigRBACAuditChanged:             {envoy.TypeLDS, envoy.TypeRDS},
+	a.MeshConfigRouteRegexChanged:            {envoy.TypeRDS},
+	a.MeshConfigHTTPSanitizationChanged:      {envoy.TypeLDS},
+	a.MeshConfigDownstreamLimitsChanged:      {envoy.TypeLDS},
+	a.MeshConfigIngressClientAddressChanged:  {envoy.TypeLDS},
+	a.MeshConfigPrometheusScrapingChanged:    {envoy.TypeLDS},
+	a.MeshConfigTracingChanged:               {envoy.TypeCDS, envoy.TypeLDS},
+	a.MeshConfigExternalAuthorizationChanged: {envoy.TypeLDS},
+	a.MeshConfigEndpointFlapDampeningChanged: {envoy.TypeEDS},
+	a.MeshConfigProtocolDetectionChanged:     {envoy.TypeCDS, envoy.TypeLDS},
+	a.MeshConfigCompressionChanged:           {envoy.TypeLDS},
+	a.MeshConfigRequestLimitsChanged:         {envoy.TypeLDS},
+	a.MeshConfigIngressHTTP3Changed:          {envoy.TypeLDS},
+	a.MeshConfigDNSProxyChanged:              {envoy.TypeLDS},
+}
+
+// broadcastTypeURIs are the xDS types updated by proxy broadcasts, in the order they are listed in broadcast scopes
+var broadcastTypeURIs = []envoy.TypeURI{envoy.TypeCDS, envoy.TypeEDS, envoy.TypeLDS, envoy.TypeSRDS, envoy.TypeRDS}
+
+// dispatchLoop coalesces the events routed to it into proxy broadcasts. Each namespace shard has
+// its own dispatch loop, so that a burst of events in one shard doesn't delay the broadcasts
+// scheduled by events in other shards.
+type dispatchLoop struct {
+	shard    string
+	messages chan events.PubSubMessage
+
+	// cfg provides the coalescing windows of the broadcasts
+	cfg configurator.Configurator
+
+	// scoped indicates whether the broadcasts of the dispatch loop are scoped to the namespaces of the
+	// events that triggered them, which is the case for the dispatch loops of namespace shards
+	scoped bool
+}
+
+// newDispatchLoop returns a dispatchLoop for the shard with the given label
+func newDispatchLoop(shard string, cfg configurator.Configurator) *dispatchLoop {
+	return &dispatchLoop{
+		shard:    shard,
+		messages: make(chan events.PubSubMessage, dispatchLoopQueueSize),
+		cfg:      cfg,
+	}
+}
+
+// newScopedDispatchLoop returns a dispatchLoop for the namespace shard with the given label, whose
+// broadcasts are scoped to the namespaces of the events that triggered them
+func newScopedDispatchLoop(shard string, cfg configurator.Configurator) *dispatchLoop {
+	loop := newDispatchLoop(shard, cfg)
+	loop.scoped = true
+	return loop
+}
+
 // isDeltaUpdate assesses and returns if a pubsub message contains an actual delta in config
 func isDeltaUpdate(psubMsg events.PubSubMessage) bool {
 	return !(strings.HasSuffix(psubMsg.AnnouncementType.String(), "updated") &&
 		reflect.DeepEqual(psubMsg.OldObj, psubMsg.NewObj))
 }
 
+// getEventNamespace returns the namespace of the object the given pubsub message refers to,
+// or an empty string if the object is not namespaced
+func getEventNamespace(psubMsg events.PubSubMessage) string {
+	obj := psubMsg.NewObj
+	if obj == nil {
+		obj = psubMsg.OldObj
+	}
+
+	switch o := obj.(type) {
+	case *configv1alpha1.MeshConfig:
+		// MeshConfig changes affect the proxies of all namespaces
+		return ""
+	case *corev1.Namespace:
+		// Namespace events are dispatched by the shard of the namespace itself
+		return o.Name
+	case metav1.Object:
+		return o.GetNamespace()
+	default:
+		return ""
+	}
+}
+
+// getEventNamespaces returns the namespaces whose proxies may be affected by the given pubsub message: the namespace
+// of the object it refers to, along with the namespaces of the sources of TrafficTarget and Egress policies, before
+// and after the change, since their proxies are configured by policies living in other namespaces.
+func getEventNamespaces(psubMsg events.PubSubMessage) []string {
+	namespaces := []string{getEventNamespace(psubMsg)}
+	for _, obj := range []interface{}{psubMsg.OldObj, psubMsg.NewObj} {
+		switch o := obj.(type) {
+		case *access.TrafficTarget:
+			for _, source := range o.Spec.Sources {
+				namespaces = append(namespaces, source.Namespace)
+			}
+		case *policyV1alpha1.Egress:
+			for _, source := range o.Spec.Sources {
+				namespaces = append(namespaces, source.Namespace)
+			}
+		}
+	}
+	return namespaces
+}
+
 func (mc *MeshCatalog) dispatcher() {
 	// This will be finely tuned in near future, we can instrument other modules
 	// to take ownership of certain events, and just notify dispatcher through
@@ -32,6 +136,8 @@ func (mc *MeshCatalog) dispatcher() {
 	subChannel := events.Subscribe(
 		a.ScheduleProxyBroadcast,                              // Other modules requesting a global envoy update
 		a.EndpointAdded, a.EndpointDeleted, a.EndpointUpdated, // endpoint
+		a.EndpointSliceAdded, a.EndpointSliceDeleted, a.EndpointSliceUpdated, // endpointslice
+		a.SecretAdded, a.SecretDeleted, a.SecretUpdated, // TLS secret
 		a.NamespaceAdded, a.NamespaceDeleted, a.NamespaceUpdated, // namespace
 		a.PodAdded, a.PodDeleted, a.PodUpdated, // pod
 		a.RouteGroupAdded, a.RouteGroupDeleted, a.RouteGroupUpdated, // routegroup
@@ -44,13 +150,80 @@ func (mc *MeshCatalog) dispatcher() {
 		a.TCPRouteAdded, a.TCPRouteDeleted, a.TCPRouteUpdated, // TCProute
 		a.EgressAdded, a.EgressDeleted, a.EgressUpdated, // Egress
 		a.IngressBackendAdded, a.IngressBackendDeleted, a.IngressBackendUpdated, // IngressBackend
+		a.ExternalWorkloadAdded, a.ExternalWorkloadDeleted, a.ExternalWorkloadUpdated, // ExternalWorkload
+		a.UpstreamTrafficSettingAdded, a.UpstreamTrafficSettingDeleted, a.UpstreamTrafficSettingUpdated, // UpstreamTrafficSetting
+		a.NamespaceIsolationAdded, a.NamespaceIsolationDeleted, a.NamespaceIsolationUpdated, // NamespaceIsolation
+		a.MeshConfigEgressChanged, a.MeshConfigPermissiveTrafficPolicyModeChanged, a.MeshConfigHTTPSIngressChanged, // MeshConfig
+		a.MeshConfigTerminatingEndpointsChanged, a.MeshConfigClientCertDetailsChanged, a.MeshConfigRBACAuditChanged,
+		a.MeshConfigTrustDomainsChanged, a.MeshConfigTracingChanged, a.MeshConfigExternalAuthorizationChanged,
+		a.MeshConfigNamespaceIsolationChanged, a.MeshConfigOutboundPassthroughChanged, a.MeshConfigRouteRegexChanged,
+		a.MeshConfigHTTPSanitizationChanged, a.MeshConfigDownstreamLimitsChanged, a.MeshConfigPrometheusScrapingChanged,
+		a.MeshConfigIngressClientAddressChanged, a.MeshConfigEndpointFlapDampeningChanged, a.MeshConfigDNSResolutionChanged,
+		a.MeshConfigProtocolDetectionChanged, a.MeshConfigCompressionChanged, a.MeshConfigRequestLimitsChanged,
+		a.MeshConfigIngressHTTP3Changed, a.MeshConfigDNSProxyChanged, a.MeshConfigScopedRoutesChanged,
+		a.MeshConfigFeatureFlagsChanged,
 	)
 
+	go mc.globalDispatchLoop.run()
+	for _, loop := range mc.shardDispatchLoops {
+		go loop.run()
+	}
+
+	for message := range subChannel {
+		psubMessage, ok := message.(events.PubSubMessage)
+		if !ok {
+			log.Error().Msgf("Error casting PubSubMessage: %v", psubMessage)
+			continue
+		}
+
+		// Identify if this is an actual delta, or just resync
+		delta := isDeltaUpdate(psubMessage)
+		log.Debug().Msgf("[Pubsub] %s - delta: %v", psubMessage.AnnouncementType, delta)
+
+		// Dispatch the event if we either:
+		// - detected a config delta
+		// - another module requested a broadcast through ScheduleProxyBroadcast
+		if delta || psubMessage.AnnouncementType == a.ScheduleProxyBroadcast {
+			mc.getDispatchLoop(psubMessage).messages <- psubMessage
+		}
+
+		// Shards are rebalanced once the events of a deleted namespace have been dispatched
+		if psubMessage.AnnouncementType == a.NamespaceDeleted && mc.namespaceShards != nil {
+			mc.namespaceShards.removeNamespace(getEventNamespace(psubMessage))
+		}
+	}
+}
+
+// getDispatchLoop returns the dispatch loop the given pubsub message should be dispatched by.
+// Events that are not namespaced are dispatched by the global dispatch loop, as are all events
+// when the dispatcher is not sharded.
+func (mc *MeshCatalog) getDispatchLoop(psubMsg events.PubSubMessage) *dispatchLoop {
+	if mc.namespaceShards == nil {
+		return mc.globalDispatchLoop
+	}
+
+	namespace := getEventNamespace(psubMsg)
+	if namespace == "" {
+		return mc.globalDispatchLoop
+	}
+
+	return mc.shardDispatchLoops[mc.namespaceShards.getShard(namespace)]
+}
+
+// run coalesces the events routed to the dispatch loop into proxy broadcasts
+func (d *dispatchLoop) run() {
 	// State and channels for event-coalescing
 	broadcastScheduled := false
 	chanMovingDeadline := make(<-chan time.Time)
 	chanMaxDeadline := make(<-chan time.Time)
 
+	// Namespaces of the events coalesced into the scheduled broadcast, when the dispatch loop is scoped
+	namespaces := make(map[string]struct{})
+
+	// xDS types affected by the events coalesced into the scheduled broadcast, unless allTypes is set
+	typeURIs := make(map[envoy.TypeURI]struct{})
+	allTypes := false
+
 	// tl;dr "When a broadcast request is scheduled, we will wait (3s) in case we receive another broadcast request
 	// during this delay that can be coalesced (and restart the (3s) count if we do) up to a maximum of (15s) delay"
 
@@ -64,45 +237,54 @@ func (mc *MeshCatalog) dispatcher() {
 	// and avoid issuing global envoy reconfiguration at large if new updates are meant to be received shortly after.
 	// Either deadline will trigger the broadcast, whichever happens first, given previous conditions.
 	// This mechanism is reset when the broadcast is published.
+	//
+	// The (3s) and (15s) windows are the defaults, they are set by the performance profile of the MeshConfig.
+	// The deadlines are tracked per dispatch loop, so events in one shard never delay the broadcast scheduled by another.
+	// The broadcasts of namespace shards are scoped to the namespaces of the events they coalesced, so that only the
+	// proxies whose config references those namespaces are updated. The global dispatch loop updates all proxies.
+	// Broadcasts only triggered by MeshConfig changes affecting specific xDS types are scoped to those types.
 
 	for {
 		select {
-		case message := <-subChannel:
-			psubMessage, ok := message.(events.PubSubMessage)
-			if !ok {
-				log.Error().Msgf("Error casting PubSubMessage: %v", psubMessage)
-				continue
+		case psubMessage := <-d.messages:
+			metricsstore.DefaultMetricsStore.CatalogShardEventCount.WithLabelValues(d.shard).Inc()
+			if d.scoped {
+				for _, ns := range getEventNamespaces(psubMessage) {
+					if ns != "" {
+						namespaces[ns] = struct{}{}
+					}
+				}
 			}
-
-			// Identify if this is an actual delta, or just resync
-			delta := isDeltaUpdate(psubMessage)
-			log.Debug().Msgf("[Pubsub] %s - delta: %v", psubMessage.AnnouncementType, delta)
-
-			// Schedule an envoy broadcast update if we either:
-			// - detected a config delta
-			// - another module requested a broadcast through ScheduleProxyBroadcast
-			if delta || psubMessage.AnnouncementType == a.ScheduleProxyBroadcast {
-				if !broadcastScheduled {
-					broadcastScheduled = true
-					chanMaxDeadline = time.After(maxBroadcastDeadlineTime)
-					chanMovingDeadline = time.After(maxGraceDeadlineTime)
-					log.Info().Msg("Broadcast scheduled by config changes")
-				} else {
-					// If a broadcast is already scheduled, just reset the moving deadline
-					chanMovingDeadline = time.After(maxGraceDeadlineTime)
+			if eventTypeURIs, ok := meshConfigChangeTypeURIs[psubMessage.AnnouncementType]; ok {
+				for _, typeURI := range eventTypeURIs {
+					typeURIs[typeURI] = struct{}{}
 				}
 			} else {
-				// Do nothing on non-delta updates
-				continue
+				allTypes = true
+			}
+
+			// The coalescing windows are those of the performance settings as of the event
+			settings := d.cfg.GetPerformanceSettings()
+			if !broadcastScheduled {
+				broadcastScheduled = true
+				chanMaxDeadline = time.After(settings.MaxBroadcastDelay)
+				chanMovingDeadline = time.After(settings.BroadcastGracePeriod)
+				log.Info().Msgf("Broadcast scheduled by config changes in dispatcher shard %s: %s", d.shard, psubMessage.AnnouncementType)
+			} else {
+				// If a broadcast is already scheduled, just reset the moving deadline
+				chanMovingDeadline = time.After(settings.BroadcastGracePeriod)
 			}
 
 		// A select-fallthrough doesn't exist, we are copying some code here
 		case <-chanMovingDeadline:
-			log.Info().Msgf("Moving deadline trigger - Broadcast envoy update")
-			events.Publish(events.PubSubMessage{
-				AnnouncementType: a.ProxyBroadcast,
-			})
-			metricsstore.DefaultMetricsStore.ProxyBroadcastEventCount.Inc()
+			log.Info().Msgf("Moving deadline trigger in dispatcher shard %s - Broadcast envoy update", d.shard)
+			if allTypes {
+				typeURIs = nil
+			}
+			d.broadcast(namespaces, typeURIs)
+			namespaces = make(map[string]struct{})
+			typeURIs = make(map[envoy.TypeURI]struct{})
+			allTypes = false
 
 			// broadcast done, reset timer channels
 			broadcastScheduled = false
@@ -110,11 +292,14 @@ func (mc *MeshCatalog) dispatcher() {
 			chanMaxDeadline = make(<-chan time.Time)
 
 		case <-chanMaxDeadline:
-			log.Info().Msgf("Max deadline trigger - Broadcast envoy update")
-			events.Publish(events.PubSubMessage{
-				AnnouncementType: a.ProxyBroadcast,
-			})
-			metricsstore.DefaultMetricsStore.ProxyBroadcastEventCount.Inc()
+			log.Info().Msgf("Max deadline trigger in dispatcher shard %s - Broadcast envoy update", d.shard)
+			if allTypes {
+				typeURIs = nil
+			}
+			d.broadcast(namespaces, typeURIs)
+			namespaces = make(map[string]struct{})
+			typeURIs = make(map[envoy.TypeURI]struct{})
+			allTypes = false
 
 			// broadcast done, reset timer channels
 			broadcastScheduled = false
@@ -123,3 +308,40 @@ func (mc *MeshCatalog) dispatcher() {
 		}
 	}
 }
+
+// broadcast publishes a proxy broadcast on behalf of the dispatch loop, scoped to the given namespaces
+// if the dispatch loop is scoped, and to the given xDS types unless they are nil
+func (d *dispatchLoop) broadcast(namespaces map[string]struct{}, typeURIs map[envoy.TypeURI]struct{}) {
+	msg := events.PubSubMessage{
+		AnnouncementType: a.ProxyBroadcast,
+	}
+	if d.scoped || typeURIs != nil {
+		scope := &ProxyBroadcastScope{}
+		if d.scoped {
+			scope.Namespaces = namespaces
+		}
+		for _, typeURI := range broadcastTypeURIs {
+			if _, ok := typeURIs[typeURI]; ok {
+				scope.TypeURIs = append(scope.TypeURIs, typeURI)
+			}
+		}
+		msg.NewObj = scope
+	}
+	events.Publish(msg)
+	metricsstore.DefaultMetricsStore.ProxyBroadcastEventCount.Inc()
+	metricsstore.DefaultMetricsStore.CatalogShardBroadcastCount.WithLabelValues(d.shard).Inc()
+}
+
+// newShardDispatchLoops returns the namespace shards and their dispatch loops for the given number of shards,
+// or nil if the dispatcher is not sharded
+func newShardDispatchLoops(numShards int, cfg configurator.Configurator) (*namespaceShards, []*dispatchLoop) {
+	if numShards <= 1 {
+		return nil, nil
+	}
+
+	loops := make([]*dispatchLoop, numShards)
+	for i := range loops {
+		loops[i] = newScopedDispatchLoop(strconv.Itoa(i), cfg)
+	}
+	return newNamespaceShards(numShards), loops
+}
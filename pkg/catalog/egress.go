@@ -47,9 +47,11 @@ func (mc *MeshCatalog) GetEgressTrafficPolicy(serviceIdentity identity.ServiceId
 			case constants.ProtocolTCP, constants.ProtocolTCPServerFirst:
 				// ---
 				// Build the TCP cluster config for this port
+				clusterName := getEgressClusterName(egress, fmt.Sprintf("%d", portSpec.Number))
 				clusterConfigs = append(clusterConfigs, &trafficpolicy.EgressClusterConfig{
-					Name: fmt.Sprintf("%d", portSpec.Number),
-					Port: portSpec.Number,
+					Name:                 clusterName,
+					Port:                 portSpec.Number,
+					ProxyProtocolVersion: egress.Spec.ProxyProtocolVersion,
 				})
 
 				// Configure port + IP range TrafficMatches
@@ -57,16 +59,28 @@ func (mc *MeshCatalog) GetEgressTrafficPolicy(serviceIdentity identity.ServiceId
 					DestinationPort:     portSpec.Number,
 					DestinationProtocol: portSpec.Protocol,
 					DestinationIPRanges: egress.Spec.IPAddresses,
-					Cluster:             fmt.Sprintf("%d", portSpec.Number),
+					Cluster:             clusterName,
 				})
 
 			case constants.ProtocolHTTPS:
+				if mc.configurator.GetDNSResolutionConfig().ResolveHTTPSHosts && len(egress.Spec.Hosts) > 0 {
+					// ---
+					// Route the traffic for each host to a cluster resolving the host using DNS, instead
+					// of to its original destination, so that hosts with rotating IP addresses are reachable
+					httpsClusterConfigs, httpsTrafficMatches := buildHTTPSHostConfigs(egress, portSpec)
+					clusterConfigs = append(clusterConfigs, httpsClusterConfigs...)
+					trafficMatches = append(trafficMatches, httpsTrafficMatches...)
+					continue
+				}
+
 				// ---
 				// Build the HTTPS cluster config for this port
 				// HTTPS is TLS encrypted, so will be proxied as a TCP stream
+				clusterName := getEgressClusterName(egress, fmt.Sprintf("%d", portSpec.Number))
 				clusterConfigs = append(clusterConfigs, &trafficpolicy.EgressClusterConfig{
-					Name: fmt.Sprintf("%d", portSpec.Number),
-					Port: portSpec.Number,
+					Name:                 clusterName,
+					Port:                 portSpec.Number,
+					ProxyProtocolVersion: egress.Spec.ProxyProtocolVersion,
 				})
 
 				// Configure port + IP range TrafficMatches
@@ -75,7 +89,7 @@ func (mc *MeshCatalog) GetEgressTrafficPolicy(serviceIdentity identity.ServiceId
 					DestinationProtocol: portSpec.Protocol,
 					DestinationIPRanges: egress.Spec.IPAddresses,
 					ServerNames:         egress.Spec.Hosts,
-					Cluster:             fmt.Sprintf("%d", portSpec.Number),
+					Cluster:             clusterName,
 				})
 			}
 		}
@@ -168,11 +182,12 @@ func (mc *MeshCatalog) buildHTTPRouteConfigs(egressPolicy *policyV1alpha1.Egress
 		hostnames := []string{host, hostnameWithPort}
 
 		// Create cluster config for this host and port combination
-		clusterName := hostnameWithPort
+		clusterName := getEgressClusterName(egressPolicy, hostnameWithPort)
 		clusterConfig := &trafficpolicy.EgressClusterConfig{
-			Name: clusterName,
-			Host: host,
-			Port: port,
+			Name:                 clusterName,
+			Host:                 host,
+			Port:                 port,
+			ProxyProtocolVersion: egressPolicy.Spec.ProxyProtocolVersion,
 		}
 		clusterConfigs = append(clusterConfigs, clusterConfig)
 
@@ -205,6 +220,34 @@ func (mc *MeshCatalog) buildHTTPRouteConfigs(egressPolicy *policyV1alpha1.Egress
 	return routeConfigs, clusterConfigs
 }
 
+// buildHTTPSHostConfigs returns the cluster configs and traffic matches routing the TLS traffic for each host
+// of the given Egress policy on the given port to a cluster resolving the host using DNS.
+// The host is matched using the SNI of the TLS connection.
+func buildHTTPSHostConfigs(egressPolicy *policyV1alpha1.Egress, portSpec policyV1alpha1.PortSpec) ([]*trafficpolicy.EgressClusterConfig, []*trafficpolicy.TrafficMatch) {
+	var clusterConfigs []*trafficpolicy.EgressClusterConfig
+	var trafficMatches []*trafficpolicy.TrafficMatch
+
+	for _, host := range egressPolicy.Spec.Hosts {
+		clusterName := getEgressClusterName(egressPolicy, fmt.Sprintf("%s:%d", host, portSpec.Number))
+		clusterConfigs = append(clusterConfigs, &trafficpolicy.EgressClusterConfig{
+			Name:                 clusterName,
+			Host:                 host,
+			Port:                 portSpec.Number,
+			ProxyProtocolVersion: egressPolicy.Spec.ProxyProtocolVersion,
+		})
+
+		trafficMatches = append(trafficMatches, &trafficpolicy.TrafficMatch{
+			DestinationPort:     portSpec.Number,
+			DestinationProtocol: portSpec.Protocol,
+			DestinationIPRanges: egressPolicy.Spec.IPAddresses,
+			ServerNames:         []string{host},
+			Cluster:             clusterName,
+		})
+	}
+
+	return clusterConfigs, trafficMatches
+}
+
 func getHTTPRouteMatchesFromHTTPRouteGroup(httpRouteGroup *smiSpecs.HTTPRouteGroup) []trafficpolicy.HTTPRouteMatch {
 	if httpRouteGroup == nil {
 		return nil
@@ -232,3 +275,13 @@ func getHTTPRouteMatchesFromHTTPRouteGroup(httpRouteGroup *smiSpecs.HTTPRouteGro
 
 	return matches
 }
+
+// getEgressClusterName returns the name of the egress cluster with the given name for the given Egress policy.
+// Clusters sending a PROXY protocol header are named after its version, so that they are distinct from the
+// clusters of other Egress policies for the same destination that don't send the header.
+func getEgressClusterName(egressPolicy *policyV1alpha1.Egress, name string) string {
+	if egressPolicy.Spec.ProxyProtocolVersion == "" {
+		return name
+	}
+	return fmt.Sprintf("%s-proxy-protocol-%s", name, egressPolicy.Spec.ProxyProtocolVersion)
+}
@@ -27,8 +27,6 @@ import (
 func TestGetEgressTrafficPolicy(t *testing.T) {
 	assert := tassert.New(t)
 	mockCtrl := gomock.NewController(t)
-	mockCfg := configurator.NewMockConfigurator(mockCtrl)
-
 	defer mockCtrl.Finish()
 
 	testCases := []struct {
@@ -36,6 +34,7 @@ func TestGetEgressTrafficPolicy(t *testing.T) {
 		egressPolicies       []*policyV1alpha1.Egress
 		egressPort           int
 		httpRouteGroups      []*specs.HTTPRouteGroup
+		dnsConfig            v1alpha1.DNSResolutionSpec
 		expectedEgressPolicy *trafficpolicy.EgressTrafficPolicy
 		expectError          bool
 	}{
@@ -334,6 +333,115 @@ func TestGetEgressTrafficPolicy(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "egress policy for HTTPS port with hosts resolved using DNS",
+			egressPolicies: []*policyV1alpha1.Egress{
+				{
+					Spec: policyV1alpha1.EgressSpec{
+						Hosts: []string{
+							"foo.com",
+							"bar.com",
+						},
+						Ports: []policyV1alpha1.PortSpec{
+							{
+								Number:   443,
+								Protocol: "https",
+							},
+						},
+					},
+				},
+			},
+			httpRouteGroups: nil, // no SMI HTTP route matches
+			dnsConfig:       v1alpha1.DNSResolutionSpec{ResolveHTTPSHosts: true},
+			expectedEgressPolicy: &trafficpolicy.EgressTrafficPolicy{
+				TrafficMatches: []*trafficpolicy.TrafficMatch{
+					{
+						DestinationPort:     443,
+						DestinationProtocol: "https",
+						ServerNames:         []string{"foo.com"},
+						Cluster:             "foo.com:443",
+					},
+					{
+						DestinationPort:     443,
+						DestinationProtocol: "https",
+						ServerNames:         []string{"bar.com"},
+						Cluster:             "bar.com:443",
+					},
+				},
+				HTTPRouteConfigsPerPort: map[int][]*trafficpolicy.EgressHTTPRouteConfig{},
+				ClustersConfigs: []*trafficpolicy.EgressClusterConfig{
+					{
+						Name: "foo.com:443",
+						Host: "foo.com",
+						Port: 443,
+					},
+					{
+						Name: "bar.com:443",
+						Host: "bar.com",
+						Port: 443,
+					},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "egress policies for the same TCP port sending and not sending the PROXY protocol",
+			egressPolicies: []*policyV1alpha1.Egress{
+				{
+					Spec: policyV1alpha1.EgressSpec{
+						IPAddresses: []string{"10.0.0.0/24"},
+						Ports: []policyV1alpha1.PortSpec{
+							{
+								Number:   3306,
+								Protocol: "tcp",
+							},
+						},
+						ProxyProtocolVersion: "v2",
+					},
+				},
+				{
+					Spec: policyV1alpha1.EgressSpec{
+						IPAddresses: []string{"10.0.1.0/24"},
+						Ports: []policyV1alpha1.PortSpec{
+							{
+								Number:   3306,
+								Protocol: "tcp",
+							},
+						},
+					},
+				},
+			},
+			httpRouteGroups: nil, // no SMI HTTP route matches
+			expectedEgressPolicy: &trafficpolicy.EgressTrafficPolicy{
+				TrafficMatches: []*trafficpolicy.TrafficMatch{
+					{
+						DestinationPort:     3306,
+						DestinationProtocol: "tcp",
+						DestinationIPRanges: []string{"10.0.0.0/24"},
+						Cluster:             "3306-proxy-protocol-v2",
+					},
+					{
+						DestinationPort:     3306,
+						DestinationProtocol: "tcp",
+						DestinationIPRanges: []string{"10.0.1.0/24"},
+						Cluster:             "3306",
+					},
+				},
+				HTTPRouteConfigsPerPort: map[int][]*trafficpolicy.EgressHTTPRouteConfig{},
+				ClustersConfigs: []*trafficpolicy.EgressClusterConfig{
+					{
+						Name:                 "3306-proxy-protocol-v2",
+						Port:                 3306,
+						ProxyProtocolVersion: "v2",
+					},
+					{
+						Name: "3306",
+						Port: 3306,
+					},
+				},
+			},
+			expectError: false,
+		},
 	}
 
 	testSourceIdentity := identity.ServiceIdentity("foo.bar.cluster.local")
@@ -342,6 +450,7 @@ func TestGetEgressTrafficPolicy(t *testing.T) {
 		t.Run(fmt.Sprintf("Running test case %d: %s", i, tc.name), func(t *testing.T) {
 			mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
 			mockPolicyController := policy.NewMockController(mockCtrl)
+			mockCfg := configurator.NewMockConfigurator(mockCtrl)
 
 			for _, rg := range tc.httpRouteGroups {
 				mockMeshSpec.EXPECT().GetHTTPRouteGroup(fmt.Sprintf("%s/%s", rg.Namespace, rg.Name)).Return(rg).AnyTimes()
@@ -355,6 +464,7 @@ func TestGetEgressTrafficPolicy(t *testing.T) {
 			}
 
 			mockCfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{EnableEgressPolicy: true}).Times(1)
+			mockCfg.EXPECT().GetDNSResolutionConfig().Return(tc.dnsConfig).AnyTimes()
 
 			actual, err := mc.GetEgressTrafficPolicy(testSourceIdentity)
 			assert.Equal(tc.expectError, err != nil)
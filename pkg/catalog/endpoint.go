@@ -1,6 +1,8 @@
 package catalog
 
 import (
+	"net"
+
 	"github.com/openservicemesh/osm/pkg/endpoint"
 	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/service"
@@ -89,3 +91,85 @@ func (mc *MeshCatalog) listEndpointsForServiceIdentity(serviceIdentity identity.
 	}
 	return endpoints
 }
+
+// ListExternalServices returns the services registered outside the mesh by the external endpoints providers
+func (mc *MeshCatalog) ListExternalServices() []endpoint.ExternalService {
+	var externalServices []endpoint.ExternalService
+	for _, provider := range mc.externalEndpointsProviders {
+		svcs := provider.ListExternalServices()
+		if len(svcs) == 0 {
+			log.Trace().Msgf("[%s] No external services found", provider.GetID())
+			continue
+		}
+		externalServices = append(externalServices, svcs...)
+	}
+	return externalServices
+}
+
+// ListExternalServicesForIdentity returns the services registered outside the mesh that the given downstream identity
+// is allowed to reach, along with the allowed endpoints of each service. All external services are allowed when
+// mesh-wide egress is enabled. Otherwise, an endpoint is allowed when an Egress policy applying to the identity
+// matches its IP address and port, and no endpoints are allowed when Egress policies are disabled.
+func (mc *MeshCatalog) ListExternalServicesForIdentity(downstreamIdentity identity.ServiceIdentity) []endpoint.ExternalService {
+	externalServices := mc.ListExternalServices()
+	if len(externalServices) == 0 || mc.configurator.IsEgressEnabled() {
+		return externalServices
+	}
+	if !mc.configurator.GetFeatureFlags().EnableEgressPolicy {
+		return nil
+	}
+
+	type egressMatch struct {
+		ipNets []*net.IPNet
+		ports  map[endpoint.Port]bool
+	}
+	var matches []egressMatch
+	for _, egress := range mc.policyController.ListEgressPoliciesForSourceIdentity(downstreamIdentity.ToK8sServiceAccount()) {
+		match := egressMatch{ports: make(map[endpoint.Port]bool)}
+		for _, ipAddress := range egress.Spec.IPAddresses {
+			_, ipNet, err := net.ParseCIDR(ipAddress)
+			if err != nil {
+				log.Error().Err(err).Msgf("Error parsing IP address range %s of Egress policy %s/%s", ipAddress, egress.Namespace, egress.Name)
+				continue
+			}
+			match.ipNets = append(match.ipNets, ipNet)
+		}
+		for _, port := range egress.Spec.Ports {
+			match.ports[endpoint.Port(port.Number)] = true
+		}
+		if len(match.ipNets) > 0 && len(match.ports) > 0 {
+			matches = append(matches, match)
+		}
+	}
+
+	isAllowed := func(ep endpoint.Endpoint) bool {
+		for _, match := range matches {
+			if !match.ports[ep.Port] {
+				continue
+			}
+			for _, ipNet := range match.ipNets {
+				if ipNet.Contains(ep.IP) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	var allowed []endpoint.ExternalService
+	for _, externalSvc := range externalServices {
+		var endpoints []endpoint.Endpoint
+		for _, ep := range externalSvc.Endpoints {
+			if isAllowed(ep) {
+				endpoints = append(endpoints, ep)
+			}
+		}
+		if len(endpoints) == 0 {
+			log.Trace().Msgf("No Egress policy allows %s to reach external service %s", downstreamIdentity, externalSvc.ClusterName())
+			continue
+		}
+		externalSvc.Endpoints = endpoints
+		allowed = append(allowed, externalSvc)
+	}
+	return allowed
+}
@@ -16,11 +16,14 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	testclient "k8s.io/client-go/kubernetes/fake"
 
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/endpoint"
 	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/policy"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/smi"
 	"github.com/openservicemesh/osm/pkg/tests"
@@ -203,3 +206,100 @@ func TestListEndpointsForServiceIdentity(t *testing.T) {
 		})
 	}
 }
+
+type fakeExternalEndpointsProvider []endpoint.ExternalService
+
+func (p fakeExternalEndpointsProvider) ListExternalServices() []endpoint.ExternalService {
+	return p
+}
+
+func (p fakeExternalEndpointsProvider) GetID() string {
+	return "fake"
+}
+
+func TestListExternalServicesForIdentity(t *testing.T) {
+	downstreamIdentity := identity.K8sServiceAccount{Name: "bookbuyer", Namespace: "bookbuyer"}.ToServiceIdentity()
+	web := endpoint.ExternalService{
+		Provider: "fake",
+		Name:     "web",
+		Endpoints: []endpoint.Endpoint{
+			{IP: net.ParseIP("10.0.0.1"), Port: 80},
+			{IP: net.ParseIP("10.1.0.1"), Port: 80},
+		},
+	}
+	db := endpoint.ExternalService{
+		Provider:  "fake",
+		Name:      "db",
+		Endpoints: []endpoint.Endpoint{{IP: net.ParseIP("10.0.0.2"), Port: 5432}},
+	}
+
+	testCases := []struct {
+		name             string
+		egressEnabled    bool
+		egressPolicy     bool
+		egressPolicies   []*policyV1alpha1.Egress
+		expectedServices []endpoint.ExternalService
+	}{
+		{
+			name:             "all external services are allowed with mesh-wide egress",
+			egressEnabled:    true,
+			expectedServices: []endpoint.ExternalService{web, db},
+		},
+		{
+			name:             "no external services are allowed without Egress policies",
+			egressEnabled:    false,
+			egressPolicy:     false,
+			expectedServices: nil,
+		},
+		{
+			name:         "endpoints matching an Egress policy's IP ranges and ports are allowed",
+			egressPolicy: true,
+			egressPolicies: []*policyV1alpha1.Egress{
+				{
+					Spec: policyV1alpha1.EgressSpec{
+						IPAddresses: []string{"10.0.0.0/24"},
+						Ports:       []policyV1alpha1.PortSpec{{Number: 80, Protocol: "tcp"}},
+					},
+				},
+			},
+			expectedServices: []endpoint.ExternalService{
+				{Provider: "fake", Name: "web", Endpoints: []endpoint.Endpoint{{IP: net.ParseIP("10.0.0.1"), Port: 80}}},
+			},
+		},
+		{
+			name:         "Egress policies matching hosts only do not allow external services",
+			egressPolicy: true,
+			egressPolicies: []*policyV1alpha1.Egress{
+				{
+					Spec: policyV1alpha1.EgressSpec{
+						Hosts: []string{"web"},
+						Ports: []policyV1alpha1.PortSpec{{Number: 80, Protocol: "http"}},
+					},
+				},
+			},
+			expectedServices: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockCfg := configurator.NewMockConfigurator(mockCtrl)
+			mockPolicyController := policy.NewMockController(mockCtrl)
+			mockCfg.EXPECT().IsEgressEnabled().Return(tc.egressEnabled).AnyTimes()
+			mockCfg.EXPECT().GetFeatureFlags().Return(configv1alpha1.FeatureFlags{EnableEgressPolicy: tc.egressPolicy}).AnyTimes()
+			mockPolicyController.EXPECT().ListEgressPoliciesForSourceIdentity(downstreamIdentity.ToK8sServiceAccount()).Return(tc.egressPolicies).AnyTimes()
+
+			mc := &MeshCatalog{
+				externalEndpointsProviders: []ExternalEndpointsProvider{fakeExternalEndpointsProvider{web, db}},
+				configurator:               mockCfg,
+				policyController:           mockPolicyController,
+			}
+
+			assert.Equal(tc.expectedServices, mc.ListExternalServicesForIdentity(downstreamIdentity))
+		})
+	}
+}
@@ -118,9 +118,12 @@ func NewFakeMeshCatalog(kubeClient kubernetes.Interface, meshConfigClient versio
 	mockKubeController.EXPECT().IsMetricsEnabled(gomock.Any()).Return(true).AnyTimes()
 
 	mockPolicyController.EXPECT().ListEgressPoliciesForSourceIdentity(gomock.Any()).Return(nil).AnyTimes()
+	mockPolicyController.EXPECT().GetIngressBackendPolicy(gomock.Any()).Return(nil).AnyTimes()
+	mockPolicyController.EXPECT().GetUpstreamTrafficSetting(gomock.Any()).Return(nil).AnyTimes()
+	mockPolicyController.EXPECT().ListNamespaceIsolationPolicies(gomock.Any()).Return(nil).AnyTimes()
 
 	return NewMeshCatalog(mockKubeController, meshSpec, certManager,
-		mockIngressMonitor, mockPolicyController, stop, cfg, serviceProviders, endpointProviders)
+		mockIngressMonitor, mockPolicyController, stop, cfg, serviceProviders, endpointProviders, nil, DefaultNumShards)
 }
 
 func newFakeMeshCatalog() *MeshCatalog {
@@ -237,5 +240,5 @@ func newFakeMeshCatalog() *MeshCatalog {
 	mockPolicyController.EXPECT().ListEgressPoliciesForSourceIdentity(gomock.Any()).Return(nil).AnyTimes()
 
 	return NewMeshCatalog(mockKubeController, meshSpec, certManager,
-		mockIngressMonitor, mockPolicyController, stop, cfg, serviceProviders, endpointProviders)
+		mockIngressMonitor, mockPolicyController, stop, cfg, serviceProviders, endpointProviders, nil, DefaultNumShards)
 }
@@ -0,0 +1,42 @@
+package catalog
+
+import (
+	access "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+
+	"github.com/openservicemesh/osm/pkg/identity"
+)
+
+// listInboundSourceIdentities returns the service identities of the sources of the given TrafficTarget, along with
+// the identities of federated trust domains mapped to them
+func (mc *MeshCatalog) listInboundSourceIdentities(t *access.TrafficTarget) []identity.ServiceIdentity {
+	var sourceIdentities []identity.ServiceIdentity
+	for _, sourceServiceAccount := range trafficTargetIdentitiesToSvcAccounts(t.Spec.Sources) {
+		sourceIdentities = append(sourceIdentities, sourceServiceAccount.ToServiceIdentity())
+	}
+	return mc.withFederatedIdentities(sourceIdentities)
+}
+
+// withFederatedIdentities returns the given downstream service identities along with the identities of federated
+// trust domains mapped to them. A mapped external identity is authorized to connect to an upstream wherever the
+// service identity it is mapped to is, so its principal is allowed alongside that of the service identity. A
+// namespace wildcard downstream allows the external identities mapped to any service account of its namespace.
+func (mc *MeshCatalog) withFederatedIdentities(downstreams []identity.ServiceIdentity) []identity.ServiceIdentity {
+	federatedTrustDomains := mc.configurator.GetFederatedTrustDomains()
+	if len(federatedTrustDomains) == 0 {
+		return downstreams
+	}
+
+	identities := downstreams
+	for _, downstream := range downstreams {
+		svcAccount := downstream.ToK8sServiceAccount()
+		for _, trustDomain := range federatedTrustDomains {
+			for _, mapping := range trustDomain.IdentityMappings {
+				if !svcAccount.Matches(identity.K8sServiceAccount{Namespace: mapping.Namespace, Name: mapping.ServiceAccount}) {
+					continue
+				}
+				identities = append(identities, identity.ServiceIdentity(mapping.ExternalIdentity))
+			}
+		}
+	}
+	return identities
+}
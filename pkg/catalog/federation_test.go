@@ -0,0 +1,125 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/tests"
+)
+
+func TestWithFederatedIdentities(t *testing.T) {
+	bookbuyer := tests.BookbuyerServiceIdentity
+	bookstore := tests.BookstoreServiceIdentity
+
+	testCases := []struct {
+		name                  string
+		federatedTrustDomains []configv1alpha1.FederatedTrustDomainSpec
+		downstreams           []identity.ServiceIdentity
+		expectedIdentities    []identity.ServiceIdentity
+	}{
+		{
+			name:                  "no federated trust domains",
+			federatedTrustDomains: nil,
+			downstreams:           []identity.ServiceIdentity{bookbuyer},
+			expectedIdentities:    []identity.ServiceIdentity{bookbuyer},
+		},
+		{
+			name: "external identities mapped to the downstreams",
+			federatedTrustDomains: []configv1alpha1.FederatedTrustDomainSpec{
+				{
+					TrustDomain: "example.org",
+					IdentityMappings: []configv1alpha1.FederatedIdentityMappingSpec{
+						{
+							ExternalIdentity: "spiffe://example.org/ns/default/sa/bookbuyer",
+							ServiceAccount:   tests.BookbuyerServiceAccountName,
+							Namespace:        tests.Namespace,
+						},
+						{
+							ExternalIdentity: "spiffe://example.org/ns/default/sa/bookthief",
+							ServiceAccount:   tests.BookstoreServiceAccountName,
+							Namespace:        tests.Namespace,
+						},
+					},
+				},
+				{
+					TrustDomain: "example.com",
+					IdentityMappings: []configv1alpha1.FederatedIdentityMappingSpec{
+						{
+							ExternalIdentity: "bookbuyer.default.example.com",
+							ServiceAccount:   tests.BookbuyerServiceAccountName,
+							Namespace:        tests.Namespace,
+						},
+					},
+				},
+			},
+			downstreams: []identity.ServiceIdentity{bookbuyer},
+			expectedIdentities: []identity.ServiceIdentity{
+				bookbuyer,
+				"spiffe://example.org/ns/default/sa/bookbuyer",
+				"bookbuyer.default.example.com",
+			},
+		},
+		{
+			name: "external identity mapped to a service account in another namespace",
+			federatedTrustDomains: []configv1alpha1.FederatedTrustDomainSpec{
+				{
+					TrustDomain: "example.org",
+					IdentityMappings: []configv1alpha1.FederatedIdentityMappingSpec{
+						{
+							ExternalIdentity: "spiffe://example.org/ns/default/sa/bookbuyer",
+							ServiceAccount:   tests.BookbuyerServiceAccountName,
+							Namespace:        "other",
+						},
+					},
+				},
+			},
+			downstreams:        []identity.ServiceIdentity{bookbuyer, bookstore},
+			expectedIdentities: []identity.ServiceIdentity{bookbuyer, bookstore},
+		},
+		{
+			name: "external identities mapped to the service accounts of a namespace wildcard downstream",
+			federatedTrustDomains: []configv1alpha1.FederatedTrustDomainSpec{
+				{
+					TrustDomain: "example.org",
+					IdentityMappings: []configv1alpha1.FederatedIdentityMappingSpec{
+						{
+							ExternalIdentity: "spiffe://example.org/ns/default/sa/bookbuyer",
+							ServiceAccount:   tests.BookbuyerServiceAccountName,
+							Namespace:        tests.Namespace,
+						},
+						{
+							ExternalIdentity: "spiffe://example.org/ns/other/sa/bookbuyer",
+							ServiceAccount:   tests.BookbuyerServiceAccountName,
+							Namespace:        "other",
+						},
+					},
+				},
+			},
+			downstreams: []identity.ServiceIdentity{"*.default.cluster.local"},
+			expectedIdentities: []identity.ServiceIdentity{
+				"*.default.cluster.local",
+				"spiffe://example.org/ns/default/sa/bookbuyer",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockConfigurator.EXPECT().GetFederatedTrustDomains().Return(tc.federatedTrustDomains)
+
+			mc := &MeshCatalog{configurator: mockConfigurator}
+
+			assert.Equal(tc.expectedIdentities, mc.withFederatedIdentities(tc.downstreams))
+		})
+	}
+}
@@ -24,6 +24,7 @@ const (
 // ListInboundTrafficPolicies returns all inbound traffic policies
 // 1. from service discovery for permissive mode
 // 2. for the given service account and upstream services from SMI Traffic Target and Traffic Split
+// The policies routing to services exposing multiple ports are split into a policy per port.
 // Note: ServiceIdentity must be in the format "name.namespace" [https://github.com/openservicemesh/osm/issues/3188]
 func (mc *MeshCatalog) ListInboundTrafficPolicies(upstreamIdentity identity.ServiceIdentity, upstreamServices []service.MeshService) []*trafficpolicy.InboundTrafficPolicy {
 	if mc.configurator.IsPermissiveTrafficPolicyMode() {
@@ -31,13 +32,13 @@ func (mc *MeshCatalog) ListInboundTrafficPolicies(upstreamIdentity identity.Serv
 		for _, svc := range upstreamServices {
 			inboundPolicies = trafficpolicy.MergeInboundPolicies(DisallowPartialHostnamesMatch, inboundPolicies, mc.buildInboundPermissiveModePolicies(svc)...)
 		}
-		return inboundPolicies
+		return mc.qualifyInboundPoliciesByPort(inboundPolicies)
 	}
 
 	inbound := mc.listInboundPoliciesFromTrafficTargets(upstreamIdentity, upstreamServices)
 	inboundPoliciesFromSplits := mc.listInboundPoliciesForTrafficSplits(upstreamIdentity, upstreamServices)
 	inbound = trafficpolicy.MergeInboundPolicies(AllowPartialHostnamesMatch, inbound, inboundPoliciesFromSplits...)
-	return inbound
+	return mc.qualifyInboundPoliciesByPort(inbound)
 }
 
 // listInboundPoliciesFromTrafficTargets builds inbound traffic policies for all inbound services
@@ -45,12 +46,14 @@ func (mc *MeshCatalog) ListInboundTrafficPolicies(upstreamIdentity identity.Serv
 // Note: ServiceIdentity must be in the format "name.namespace" [https://github.com/openservicemesh/osm/issues/3188]
 func (mc *MeshCatalog) listInboundPoliciesFromTrafficTargets(upstreamIdentity identity.ServiceIdentity, upstreamServices []service.MeshService) []*trafficpolicy.InboundTrafficPolicy {
 	upstreamServiceAccount := upstreamIdentity.ToK8sServiceAccount()
+	gatewaySource := mc.getMulticlusterGatewaySource(upstreamIdentity)
 	var inboundPolicies []*trafficpolicy.InboundTrafficPolicy
 
 	for _, t := range mc.meshSpec.ListTrafficTargets() { // loop through all traffic targets
 		if !isValidTrafficTarget(t) {
 			continue
 		}
+		t = withMulticlusterGatewaySource(t, gatewaySource)
 
 		// TODO(draychev): Add a check to ensure that ServiceIdentities are of the same kind! [https://github.com/openservicemesh/osm/issues/3173]
 		if t.Spec.Destination.Name != upstreamServiceAccount.Name { // not an inbound policy for the upstream services
@@ -71,12 +74,14 @@ func (mc *MeshCatalog) listInboundPoliciesFromTrafficTargets(upstreamIdentity id
 // Note: ServiceIdentity must be in the format "name.namespace" [https://github.com/openservicemesh/osm/issues/3188]
 func (mc *MeshCatalog) listInboundPoliciesForTrafficSplits(upstreamIdentity identity.ServiceIdentity, upstreamServices []service.MeshService) []*trafficpolicy.InboundTrafficPolicy {
 	upstreamServiceAccount := upstreamIdentity.ToK8sServiceAccount()
+	gatewaySource := mc.getMulticlusterGatewaySource(upstreamIdentity)
 	var inboundPolicies []*trafficpolicy.InboundTrafficPolicy
 
 	for _, t := range mc.meshSpec.ListTrafficTargets() { // loop through all traffic targets
 		if !isValidTrafficTarget(t) {
 			continue
 		}
+		t = withMulticlusterGatewaySource(t, gatewaySource)
 
 		// TODO(draychev): Add a check to ensure that ServiceIdentities are of the same kind! [https://github.com/openservicemesh/osm/issues/3173]
 		if t.Spec.Destination.Name != upstreamServiceAccount.Name { // not an inbound policy for the upstream identity
@@ -113,16 +118,16 @@ func (mc *MeshCatalog) listInboundPoliciesForTrafficSplits(upstreamIdentity iden
 				servicePolicy := trafficpolicy.NewInboundTrafficPolicy(apexService.FQDN(), hostnames)
 				weightedCluster := getDefaultWeightedClusterForService(upstreamSvc)
 
-				for _, sourceServiceAccount := range trafficTargetIdentitiesToSvcAccounts(t.Spec.Sources) {
+				for _, sourceIdentity := range mc.listInboundSourceIdentities(t) {
 					for _, routeMatch := range routeMatches {
 						// If the traffic target has a route with host headers
 						// we need to create a new inbound traffic policy with the host header as the required hostnames
 						// else the hosnames will be hostnames corresponding to the service
 						if _, ok := routeMatch.Headers[hostHeaderKey]; !ok {
-							servicePolicy.AddRule(*trafficpolicy.NewRouteWeightedCluster(routeMatch, []service.WeightedCluster{weightedCluster}), sourceServiceAccount.ToServiceIdentity())
+							servicePolicy.AddRule(*trafficpolicy.NewRouteWeightedCluster(routeMatch, []service.WeightedCluster{weightedCluster}), sourceIdentity)
 						} else {
 							servicePolicyWithHostHeader := trafficpolicy.NewInboundTrafficPolicy(routeMatch.Headers[hostHeaderKey], []string{routeMatch.Headers[hostHeaderKey]})
-							servicePolicyWithHostHeader.AddRule(*trafficpolicy.NewRouteWeightedCluster(routeMatch, []service.WeightedCluster{weightedCluster}), sourceServiceAccount.ToServiceIdentity())
+							servicePolicyWithHostHeader.AddRule(*trafficpolicy.NewRouteWeightedCluster(routeMatch, []service.WeightedCluster{weightedCluster}), sourceIdentity)
 							inboundPolicies = trafficpolicy.MergeInboundPolicies(AllowPartialHostnamesMatch, inboundPolicies, servicePolicyWithHostHeader)
 						}
 					}
@@ -155,16 +160,16 @@ func (mc *MeshCatalog) buildInboundPolicies(t *access.TrafficTarget, svc service
 	servicePolicy := trafficpolicy.NewInboundTrafficPolicy(svc.FQDN(), hostnames)
 	weightedCluster := getDefaultWeightedClusterForService(svc)
 
-	for _, sourceServiceAccount := range trafficTargetIdentitiesToSvcAccounts(t.Spec.Sources) {
+	for _, sourceIdentity := range mc.listInboundSourceIdentities(t) {
 		for _, routeMatch := range routeMatches {
 			// If the traffic target has a route with host headers
 			// we need to create a new inbound traffic policy with the host header as the required hostnames
 			// else the hosnames will be hostnames corresponding to the service
 			if _, ok := routeMatch.Headers[hostHeaderKey]; !ok {
-				servicePolicy.AddRule(*trafficpolicy.NewRouteWeightedCluster(routeMatch, []service.WeightedCluster{weightedCluster}), sourceServiceAccount.ToServiceIdentity())
+				servicePolicy.AddRule(*trafficpolicy.NewRouteWeightedCluster(routeMatch, []service.WeightedCluster{weightedCluster}), sourceIdentity)
 			} else {
 				servicePolicyWithHostHeader := trafficpolicy.NewInboundTrafficPolicy(routeMatch.Headers[hostHeaderKey], []string{routeMatch.Headers[hostHeaderKey]})
-				servicePolicyWithHostHeader.AddRule(*trafficpolicy.NewRouteWeightedCluster(routeMatch, []service.WeightedCluster{weightedCluster}), sourceServiceAccount.ToServiceIdentity())
+				servicePolicyWithHostHeader.AddRule(*trafficpolicy.NewRouteWeightedCluster(routeMatch, []service.WeightedCluster{weightedCluster}), sourceIdentity)
 				inboundPolicies = trafficpolicy.MergeInboundPolicies(AllowPartialHostnamesMatch, inboundPolicies, servicePolicyWithHostHeader)
 			}
 		}
@@ -247,6 +252,12 @@ func (mc *MeshCatalog) getHTTPPathsPerRoute() (map[trafficpolicy.TrafficSpecName
 				Headers:       trafficSpecsMatches.Headers,
 			}
 
+			// gRPC paths of the form /<package>.<Service>/<Method> of the routes allowing gRPC requests match the
+			// method exactly, other paths are regexes
+			if grpcMethod, ok := trafficpolicy.ParseGRPCPath(serviceRoute.Path); ok && trafficpolicy.AllowsGRPCRequests(serviceRoute.Methods) {
+				serviceRoute.Path, serviceRoute.PathMatchType = grpcMethod.PathMatch()
+			}
+
 			// When pathRegex or/and methods are not defined, they will be wildcarded
 			if serviceRoute.Path == "" {
 				serviceRoute.Path = constants.RegexMatchAll
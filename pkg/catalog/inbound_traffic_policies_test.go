@@ -9,7 +9,7 @@ import (
 	"github.com/golang/mock/gomock"
 	access "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
 	spec "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
-	split "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha2"
+	split "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha4"
 	tassert "github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -482,6 +482,7 @@ func TestListInboundTrafficPolicies(t *testing.T) {
 			mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
 			mockEndpointProvider := endpoint.NewMockProvider(mockCtrl)
 			mockServiceProvider := service.NewMockProvider(mockCtrl)
+			mockServiceProvider.EXPECT().ListServicePorts(gomock.Any()).Return(nil, nil).AnyTimes()
 			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
 
 			mc := MeshCatalog{
@@ -517,6 +518,7 @@ func TestListInboundTrafficPolicies(t *testing.T) {
 			}
 
 			mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(tc.permissiveMode).AnyTimes()
+			mockConfigurator.EXPECT().GetFederatedTrustDomains().Return(nil).AnyTimes()
 
 			for _, ms := range tc.meshServices {
 				locality := service.LocalCluster
@@ -967,14 +969,18 @@ func TestListInboundPoliciesForTrafficSplits(t *testing.T) {
 			mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
 			mockEndpointProvider := endpoint.NewMockProvider(mockCtrl)
 			mockServiceProvider := service.NewMockProvider(mockCtrl)
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
 
 			mc := MeshCatalog{
 				kubeController:     mockKubeController,
 				meshSpec:           mockMeshSpec,
 				endpointsProviders: []endpoint.Provider{mockEndpointProvider},
 				serviceProviders:   []service.Provider{mockServiceProvider},
+				configurator:       mockConfigurator,
 			}
 
+			mockConfigurator.EXPECT().GetFederatedTrustDomains().Return(nil).AnyTimes()
+
 			for _, meshSvc := range tc.meshServices {
 				k8sService := tests.NewServiceFixture(meshSvc.Name, meshSvc.Namespace, map[string]string{})
 				mockKubeController.EXPECT().GetService(meshSvc).Return(k8sService).AnyTimes()
@@ -1286,14 +1292,18 @@ func TestBuildInboundPolicies(t *testing.T) {
 			mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
 			mockEndpointProvider := endpoint.NewMockProvider(mockCtrl)
 			mockServiceProvider := service.NewMockProvider(mockCtrl)
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
 
 			mc := MeshCatalog{
 				kubeController:     mockKubeController,
 				meshSpec:           mockMeshSpec,
 				endpointsProviders: []endpoint.Provider{mockEndpointProvider},
 				serviceProviders:   []service.Provider{mockServiceProvider},
+				configurator:       mockConfigurator,
 			}
 
+			mockConfigurator.EXPECT().GetFederatedTrustDomains().Return(nil).AnyTimes()
+
 			destK8sService := tests.NewServiceFixture(tc.inboundService.Name, tc.inboundService.Namespace, map[string]string{})
 			mockKubeController.EXPECT().GetService(tc.inboundService).Return(destK8sService).AnyTimes()
 
@@ -1631,14 +1641,18 @@ func TestListInboundPoliciesFromTrafficTargets(t *testing.T) {
 			mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
 			mockEndpointProvider := endpoint.NewMockProvider(mockCtrl)
 			mockServiceProvider := service.NewMockProvider(mockCtrl)
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
 
 			mc := MeshCatalog{
 				kubeController:     mockKubeController,
 				meshSpec:           mockMeshSpec,
 				endpointsProviders: []endpoint.Provider{mockEndpointProvider},
 				serviceProviders:   []service.Provider{mockServiceProvider},
+				configurator:       mockConfigurator,
 			}
 
+			mockConfigurator.EXPECT().GetFederatedTrustDomains().Return(nil).AnyTimes()
+
 			for _, destMeshSvc := range tc.upstreamServices {
 				destK8sService := tests.NewServiceFixture(destMeshSvc.Name, destMeshSvc.Namespace, map[string]string{})
 				mockKubeController.EXPECT().GetService(destMeshSvc).Return(destK8sService).AnyTimes()
@@ -1901,6 +1915,99 @@ func TestGetHTTPPathsPerRoute(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "HTTP route with gRPC paths",
+			trafficSpec: spec.HTTPRouteGroup{
+				TypeMeta: v1.TypeMeta{
+					APIVersion: "specs.smi-spec.io/v1alpha4",
+					Kind:       "HTTPRouteGroup",
+				},
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+					Name:      tests.RouteGroupName,
+				},
+
+				Spec: spec.HTTPRouteGroupSpec{
+					Matches: []spec.HTTPMatch{
+						{
+							Name:      "say-hello",
+							PathRegex: "/hello.HelloService/SayHello",
+							Methods:   []string{"POST"},
+						},
+						{
+							Name:      "grpcbin",
+							PathRegex: "/grpcbin.GRPCBin/*",
+						},
+					},
+				},
+			},
+			expectedHTTPPathsPerRoute: map[trafficpolicy.TrafficSpecName]map[trafficpolicy.TrafficSpecMatchName]trafficpolicy.HTTPRouteMatch{
+				"HTTPRouteGroup/default/bookstore-service-routes": {
+					"say-hello": {
+						Path:          "/hello.HelloService/SayHello",
+						PathMatchType: trafficpolicy.PathMatchExact,
+						Methods:       []string{"POST"},
+					},
+					"grpcbin": {
+						Path:          "/grpcbin.GRPCBin/*",
+						PathMatchType: trafficpolicy.PathMatchRegex,
+						Methods:       []string{"*"},
+					},
+				},
+			},
+		},
+		{
+			name: "HTTP route with regex paths resembling gRPC paths",
+			trafficSpec: spec.HTTPRouteGroup{
+				TypeMeta: v1.TypeMeta{
+					APIVersion: "specs.smi-spec.io/v1alpha4",
+					Kind:       "HTTPRouteGroup",
+				},
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "default",
+					Name:      tests.RouteGroupName,
+				},
+
+				Spec: spec.HTTPRouteGroupSpec{
+					Matches: []spec.HTTPMatch{
+						{
+							Name:      "all-methods",
+							PathRegex: "/grpcbin.GRPCBin/.*",
+							Methods:   []string{"POST"},
+						},
+						{
+							Name:      "trailing-slash",
+							PathRegex: "/books.v1/",
+							Methods:   []string{"GET"},
+						},
+						{
+							Name:      "versioned-api",
+							PathRegex: "/api.v1/books",
+							Methods:   []string{"GET"},
+						},
+					},
+				},
+			},
+			expectedHTTPPathsPerRoute: map[trafficpolicy.TrafficSpecName]map[trafficpolicy.TrafficSpecMatchName]trafficpolicy.HTTPRouteMatch{
+				"HTTPRouteGroup/default/bookstore-service-routes": {
+					"all-methods": {
+						Path:          "/grpcbin.GRPCBin/.*",
+						PathMatchType: trafficpolicy.PathMatchRegex,
+						Methods:       []string{"POST"},
+					},
+					"trailing-slash": {
+						Path:          "/books.v1/",
+						PathMatchType: trafficpolicy.PathMatchRegex,
+						Methods:       []string{"GET"},
+					},
+					"versioned-api": {
+						Path:          "/api.v1/books",
+						PathMatchType: trafficpolicy.PathMatchRegex,
+						Methods:       []string{"GET"},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
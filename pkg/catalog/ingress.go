@@ -14,6 +14,7 @@ import (
 
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
@@ -62,6 +63,13 @@ func (mc *MeshCatalog) getIngressTrafficPolicy(svc service.MeshService) (*traffi
 	// Note: The original pointer returned by cache.Store must not be modified for thread safety.
 	ingressBackendWithStatus := *ingressBackendPolicy
 
+	// Ingress traffic to NodePort and LoadBalancer backends with a Local external traffic policy is not SNATed,
+	// so the source IP of the clients reaching the backend can be trusted
+	preserveSourceIP := false
+	if k8sSvc := mc.kubeController.GetService(svc); k8sSvc != nil {
+		preserveSourceIP = k8s.PreservesClientSourceIP(k8sSvc)
+	}
+
 	var trafficRoutingRules []*trafficpolicy.Rule
 	sourceServiceIdentities := mapset.NewSet()
 	var trafficMatches []*trafficpolicy.IngressTrafficMatch
@@ -70,12 +78,43 @@ func (mc *MeshCatalog) getIngressTrafficPolicy(svc service.MeshService) (*traffi
 			continue
 		}
 
+		// The PROXY protocol header accepted on the backend's port carries the source IP of the ingress clients
 		trafficMatch := &trafficpolicy.IngressTrafficMatch{
 			Name:                     fmt.Sprintf("ingress_%s_%d_%s", svc, backend.Port.Number, backend.Port.Protocol),
 			Port:                     uint32(backend.Port.Number),
 			Protocol:                 backend.Port.Protocol,
 			ServerNames:              backend.TLS.SNIHosts,
 			SkipClientCertValidation: backend.TLS.SkipClientCertValidation,
+			PreserveSourceIP:         preserveSourceIP || backend.AcceptProxyProtocol,
+			AcceptProxyProtocol:      backend.AcceptProxyProtocol,
+		}
+		if security := ingressBackendPolicy.Spec.Security; security != nil {
+			trafficMatch.EnableCSRF = security.CSRF != nil
+			trafficMatch.RewriteCookies = security.Cookies != nil
+		}
+		if clientAddress := ingressBackendPolicy.Spec.ClientAddress; clientAddress != nil {
+			trafficMatch.ClientAddress = &trafficpolicy.ClientAddressPolicy{
+				UseRemoteAddress:    clientAddress.UseRemoteAddress,
+				XFFNumTrustedHops:   clientAddress.XFFNumTrustedHops,
+				InternalUnixSockets: clientAddress.InternalAddressConfig.UnixSockets,
+			}
+		}
+
+		// The certificate presented to the clients is held by a TLS secret in the namespace of the IngressBackend,
+		// so that it can only be referenced by the owners of the backend
+		if backend.TLS.SecretName != "" {
+			if mc.kubeController.GetSecret(ingressBackendPolicy.Namespace, backend.TLS.SecretName) == nil {
+				ingressBackendWithStatus.Status = policyV1alpha1.IngressBackendStatus{
+					CurrentStatus: "error",
+					Reason:        fmt.Sprintf("TLS secret %s/%s not found", ingressBackendPolicy.Namespace, backend.TLS.SecretName),
+				}
+				if _, err := mc.kubeController.UpdateStatus(&ingressBackendWithStatus); err != nil {
+					log.Error().Err(err).Msg("Error updating status for IngressBackend")
+				}
+				return nil, errors.Errorf("Could not find the TLS secret %s/%s specified in the IngressBackend %s/%s",
+					ingressBackendPolicy.Namespace, backend.TLS.SecretName, ingressBackendPolicy.Namespace, ingressBackendPolicy.Name)
+			}
+			trafficMatch.CertificateSecret = fmt.Sprintf("%s/%s", ingressBackendPolicy.Namespace, backend.TLS.SecretName)
 		}
 
 		var sourceIPRanges []string
@@ -126,14 +165,17 @@ func (mc *MeshCatalog) getIngressTrafficPolicy(svc service.MeshService) (*traffi
 		trafficMatches = append(trafficMatches, trafficMatch)
 
 		// Build the routing rule for this backend and source combination.
-		// Currently IngressBackend only supports a wildcard HTTP route. The
-		// 'Matches' field in the spec can be used to extend this to perform
-		// stricter enforcement.
-		backendCluster := getDefaultWeightedClusterForService(svc)
+		// Currently IngressBackend only supports a wildcard HTTP route, or a
+		// path prefix when the requests are rewritten. The 'Matches' field in
+		// the spec can be used to extend this to perform stricter enforcement.
+		backendCluster := mc.getWeightedClusterForTargetPort(svc, uint32(backend.Port.Number))
+		routeMatch, routeRewrite := getIngressBackendRoute(backend)
 		routingRule := &trafficpolicy.Rule{
 			Route: trafficpolicy.RouteWeightedClusters{
-				HTTPRouteMatch:   trafficpolicy.WildCardRouteMatch,
+				HTTPRouteMatch:   routeMatch,
 				WeightedClusters: mapset.NewSet(backendCluster),
+				Rewrite:          routeRewrite,
+				Headers:          getIngressBackendHeaderMutations(backend.Headers),
 			},
 			AllowedServiceIdentities: sourceServiceIdentities,
 		}
@@ -154,6 +196,9 @@ func (mc *MeshCatalog) getIngressTrafficPolicy(svc service.MeshService) (*traffi
 		Name:      fmt.Sprintf("%s_from_%s", svc, ingressBackendPolicy.Name),
 		Hostnames: []string{"*"},
 		Rules:     trafficRoutingRules,
+		CORS:      getIngressBackendCORSPolicy(ingressBackendPolicy.Spec.CORS),
+		Headers:   getIngressBackendHeaderMutations(ingressBackendPolicy.Spec.Headers),
+		Security:  getIngressBackendSecurityPolicy(ingressBackendPolicy.Spec.Security),
 	}
 
 	return &trafficpolicy.IngressTrafficPolicy{
@@ -162,6 +207,122 @@ func (mc *MeshCatalog) getIngressTrafficPolicy(svc service.MeshService) (*traffi
 	}, nil
 }
 
+// getIngressBackendRoute returns the HTTP route match and rewrite of the requests to the given IngressBackend backend.
+// Requests rewritten are matched on the path prefix of the rewrite so that the prefix can be replaced.
+func getIngressBackendRoute(backend policyV1alpha1.BackendSpec) (trafficpolicy.HTTPRouteMatch, *trafficpolicy.HTTPRouteRewrite) {
+	if backend.Rewrite == nil {
+		return trafficpolicy.WildCardRouteMatch, nil
+	}
+
+	pathPrefix := backend.Rewrite.PathPrefix
+	if pathPrefix == "" {
+		pathPrefix = "/"
+	}
+	routeMatch := trafficpolicy.HTTPRouteMatch{
+		Path:          pathPrefix,
+		PathMatchType: trafficpolicy.PathMatchPrefix,
+		Methods:       []string{constants.WildcardHTTPMethod},
+	}
+
+	routeRewrite := &trafficpolicy.HTTPRouteRewrite{
+		PrefixRewrite:   backend.Rewrite.PrefixRewrite,
+		HostRewrite:     backend.Rewrite.Host,
+		AutoHostRewrite: backend.Rewrite.AutoHost,
+	}
+	if backend.Rewrite.RegexRewrite != nil {
+		routeRewrite.RegexRewritePattern = backend.Rewrite.RegexRewrite.Pattern
+		routeRewrite.RegexRewriteSubstitution = backend.Rewrite.RegexRewrite.Substitution
+	}
+
+	return routeMatch, routeRewrite
+}
+
+// getIngressBackendCORSPolicy returns the CORS policy corresponding to the given IngressBackend CORS spec, nil if unset
+func getIngressBackendCORSPolicy(cors *policyV1alpha1.CORSSpec) *trafficpolicy.CORSPolicy {
+	if cors == nil {
+		return nil
+	}
+
+	corsPolicy := &trafficpolicy.CORSPolicy{
+		AllowOrigins:     cors.AllowOrigins,
+		AllowMethods:     cors.AllowMethods,
+		AllowHeaders:     cors.AllowHeaders,
+		ExposeHeaders:    cors.ExposeHeaders,
+		AllowCredentials: cors.AllowCredentials,
+	}
+	if cors.MaxAge != nil {
+		corsPolicy.MaxAge = cors.MaxAge.Duration
+	}
+
+	return corsPolicy
+}
+
+// getIngressBackendHeaderMutations returns the header mutations corresponding to the given IngressBackend headers spec,
+// nil if unset
+func getIngressBackendHeaderMutations(headers *policyV1alpha1.HeadersSpec) *trafficpolicy.HTTPHeaderMutations {
+	if headers == nil {
+		return nil
+	}
+
+	toHeaderValues := func(specs []policyV1alpha1.HeaderValueSpec) []trafficpolicy.HTTPHeaderValue {
+		var headerValues []trafficpolicy.HTTPHeaderValue
+		for _, spec := range specs {
+			headerValues = append(headerValues, trafficpolicy.HTTPHeaderValue{Name: spec.Name, Value: spec.Value})
+		}
+		return headerValues
+	}
+
+	return &trafficpolicy.HTTPHeaderMutations{
+		RequestHeadersToAdd:     toHeaderValues(headers.RequestHeadersToAdd),
+		RequestHeadersToRemove:  headers.RequestHeadersToRemove,
+		ResponseHeadersToAdd:    toHeaderValues(headers.ResponseHeadersToAdd),
+		ResponseHeadersToRemove: headers.ResponseHeadersToRemove,
+	}
+}
+
+// getIngressBackendSecurityPolicy returns the security policy corresponding to the given IngressBackend security spec,
+// nil if unset
+func getIngressBackendSecurityPolicy(security *policyV1alpha1.IngressSecuritySpec) *trafficpolicy.IngressSecurityPolicy {
+	if security == nil {
+		return nil
+	}
+
+	securityPolicy := &trafficpolicy.IngressSecurityPolicy{}
+	if security.CSRF != nil {
+		securityPolicy.CSRF = &trafficpolicy.CSRFPolicy{
+			AdditionalOrigins: security.CSRF.AdditionalOrigins,
+			ShadowMode:        security.CSRF.ShadowMode,
+		}
+	}
+	if headers := security.ResponseHeaders; headers != nil {
+		// The values of the security headers are literal, unlike the header values referencing request properties
+		addHeader := func(name, value string) {
+			if value != "" {
+				securityPolicy.ResponseHeaders = append(securityPolicy.ResponseHeaders, trafficpolicy.HTTPHeaderValue{
+					Name:  name,
+					Value: strings.ReplaceAll(value, "%", "%%"),
+				})
+			}
+		}
+		addHeader("X-Frame-Options", headers.FrameOptions)
+		addHeader("Content-Security-Policy", headers.ContentSecurityPolicy)
+		addHeader("Strict-Transport-Security", headers.StrictTransportSecurity)
+		addHeader("Referrer-Policy", headers.ReferrerPolicy)
+		if headers.ContentTypeNoSniff {
+			addHeader("X-Content-Type-Options", "nosniff")
+		}
+	}
+	if security.Cookies != nil {
+		securityPolicy.Cookies = &trafficpolicy.CookieAttributes{
+			Secure:   security.Cookies.Secure,
+			HTTPOnly: security.Cookies.HTTPOnly,
+			SameSite: security.Cookies.SameSite,
+		}
+	}
+
+	return securityPolicy
+}
+
 // getIngressTrafficPolicyFromK8s returns the ingress traffic policy for the given mesh service from the corresponding k8s Ingress resource
 // TODO: DEPRECATE once IngressBackend API is the default for configuring an ingress backend.
 func (mc *MeshCatalog) getIngressTrafficPolicyFromK8s(svc service.MeshService) (*trafficpolicy.IngressTrafficPolicy, error) {
@@ -260,10 +421,9 @@ func (mc *MeshCatalog) getIngressPoliciesNetworkingV1beta1(svc service.MeshServi
 		return inboundIngressPolicies, err
 	}
 
-	ingressWeightedCluster := getDefaultWeightedClusterForService(svc)
-
 	for _, ingress := range ingresses {
 		if ingress.Spec.Backend != nil && ingress.Spec.Backend.ServiceName == svc.Name {
+			ingressWeightedCluster := mc.getWeightedClusterForServicePort(svc, uint32(ingress.Spec.Backend.ServicePort.IntValue()), ingress.Spec.Backend.ServicePort.StrVal)
 			wildcardIngressPolicy := trafficpolicy.NewInboundTrafficPolicy(getIngressTrafficPolicyName(ingress.ObjectMeta.Name, ingress.ObjectMeta.Namespace, constants.WildcardHTTPMethod), []string{constants.WildcardHTTPMethod})
 			wildcardIngressPolicy.AddRule(*trafficpolicy.NewRouteWeightedCluster(trafficpolicy.WildCardRouteMatch, []service.WeightedCluster{ingressWeightedCluster}), identity.WildcardServiceIdentity)
 			inboundIngressPolicies = trafficpolicy.MergeInboundPolicies(DisallowPartialHostnamesMatch, inboundIngressPolicies, wildcardIngressPolicy)
@@ -280,6 +440,7 @@ func (mc *MeshCatalog) getIngressPoliciesNetworkingV1beta1(svc service.MeshServi
 				if ingressPath.Backend.ServiceName != svc.Name {
 					continue
 				}
+				ingressWeightedCluster := mc.getWeightedClusterForServicePort(svc, uint32(ingressPath.Backend.ServicePort.IntValue()), ingressPath.Backend.ServicePort.StrVal)
 
 				httpRouteMatch := trafficpolicy.HTTPRouteMatch{
 					Methods: []string{constants.WildcardHTTPMethod},
@@ -364,10 +525,9 @@ func (mc *MeshCatalog) getIngressPoliciesNetworkingV1(svc service.MeshService) (
 		return inboundIngressPolicies, err
 	}
 
-	ingressWeightedCluster := getDefaultWeightedClusterForService(svc)
-
 	for _, ingress := range ingresses {
 		if ingress.Spec.DefaultBackend != nil && ingress.Spec.DefaultBackend.Service.Name == svc.Name {
+			ingressWeightedCluster := mc.getWeightedClusterForServicePort(svc, uint32(ingress.Spec.DefaultBackend.Service.Port.Number), ingress.Spec.DefaultBackend.Service.Port.Name)
 			wildcardIngressPolicy := trafficpolicy.NewInboundTrafficPolicy(getIngressTrafficPolicyName(ingress.ObjectMeta.Name, ingress.ObjectMeta.Namespace, constants.WildcardHTTPMethod), []string{constants.WildcardHTTPMethod})
 			wildcardIngressPolicy.AddRule(*trafficpolicy.NewRouteWeightedCluster(trafficpolicy.WildCardRouteMatch, []service.WeightedCluster{ingressWeightedCluster}), identity.WildcardServiceIdentity)
 			inboundIngressPolicies = trafficpolicy.MergeInboundPolicies(DisallowPartialHostnamesMatch, inboundIngressPolicies, wildcardIngressPolicy)
@@ -384,6 +544,7 @@ func (mc *MeshCatalog) getIngressPoliciesNetworkingV1(svc service.MeshService) (
 				if ingressPath.Backend.Service.Name != svc.Name {
 					continue
 				}
+				ingressWeightedCluster := mc.getWeightedClusterForServicePort(svc, uint32(ingressPath.Backend.Service.Port.Number), ingressPath.Backend.Service.Port.Name)
 
 				httpRouteMatch := trafficpolicy.HTTPRouteMatch{
 					Methods: []string{constants.WildcardHTTPMethod},
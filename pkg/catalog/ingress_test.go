@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"net"
 	"testing"
+	"time"
 
 	mapset "github.com/deckarep/golang-set"
 	"github.com/golang/mock/gomock"
 	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	networkingV1 "k8s.io/api/networking/v1"
 	networkingV1beta1 "k8s.io/api/networking/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -2162,6 +2164,7 @@ func TestGetIngressTrafficPolicy(t *testing.T) {
 		ingressBackendPolicyEnabled bool
 		enableHTTPSIngress          bool
 		meshSvc                     service.MeshService
+		backendSvc                  *corev1.Service
 		ingressV1                   []*networkingV1.Ingress
 		ingressBackend              *policyV1alpha1.IngressBackend
 		expectedPolicy              *trafficpolicy.IngressTrafficPolicy
@@ -2398,6 +2401,276 @@ func TestGetIngressTrafficPolicy(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name:                        "HTTP ingress using the IngressBackend API to a LoadBalancer backend preserving the client source IP",
+			ingressBackendPolicyEnabled: true,
+			meshSvc:                     service.MeshService{Name: "foo", Namespace: "testns"},
+			backendSvc: &corev1.Service{
+				Spec: corev1.ServiceSpec{
+					Type:                  corev1.ServiceTypeLoadBalancer,
+					ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+				},
+			},
+			ingressBackend: &policyV1alpha1.IngressBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress-backend-1",
+					Namespace: "testns",
+				},
+				Spec: policyV1alpha1.IngressBackendSpec{
+					Backends: []policyV1alpha1.BackendSpec{
+						{
+							Name: "foo",
+							Port: policyV1alpha1.PortSpec{
+								Number:   80,
+								Protocol: "http",
+							},
+						},
+					},
+					Sources: []policyV1alpha1.IngressSourceSpec{
+						{
+							Kind:      policyV1alpha1.KindService,
+							Name:      ingressSourceSvc.Name,
+							Namespace: ingressSourceSvc.Namespace,
+						},
+					},
+				},
+			},
+			expectedPolicy: &trafficpolicy.IngressTrafficPolicy{
+				HTTPRoutePolicies: []*trafficpolicy.InboundTrafficPolicy{
+					{
+						Name: "testns/foo_from_ingress-backend-1",
+						Hostnames: []string{
+							"*",
+						},
+						Rules: []*trafficpolicy.Rule{
+							{
+								Route: trafficpolicy.RouteWeightedClusters{
+									HTTPRouteMatch: trafficpolicy.WildCardRouteMatch,
+									WeightedClusters: mapset.NewSet(service.WeightedCluster{
+										ClusterName: "testns/foo",
+										Weight:      100,
+									}),
+								},
+								AllowedServiceIdentities: mapset.NewSet(identity.WildcardServiceIdentity),
+							},
+						},
+					},
+				},
+				TrafficMatches: []*trafficpolicy.IngressTrafficMatch{
+					{
+						Name:             "ingress_testns/foo_80_http",
+						Protocol:         "http",
+						Port:             80,
+						SourceIPRanges:   []string{"10.0.0.10/32"}, // Endpoint of 'ingressSourceSvc' referenced as a source
+						PreserveSourceIP: true,
+					},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name:                        "HTTP ingress using the IngressBackend API accepting the PROXY protocol",
+			ingressBackendPolicyEnabled: true,
+			meshSvc:                     service.MeshService{Name: "foo", Namespace: "testns"},
+			ingressBackend: &policyV1alpha1.IngressBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress-backend-1",
+					Namespace: "testns",
+				},
+				Spec: policyV1alpha1.IngressBackendSpec{
+					Backends: []policyV1alpha1.BackendSpec{
+						{
+							Name: "foo",
+							Port: policyV1alpha1.PortSpec{
+								Number:   80,
+								Protocol: "http",
+							},
+							AcceptProxyProtocol: true,
+						},
+					},
+					Sources: []policyV1alpha1.IngressSourceSpec{
+						{
+							Kind:      policyV1alpha1.KindService,
+							Name:      ingressSourceSvc.Name,
+							Namespace: ingressSourceSvc.Namespace,
+						},
+					},
+				},
+			},
+			expectedPolicy: &trafficpolicy.IngressTrafficPolicy{
+				HTTPRoutePolicies: []*trafficpolicy.InboundTrafficPolicy{
+					{
+						Name: "testns/foo_from_ingress-backend-1",
+						Hostnames: []string{
+							"*",
+						},
+						Rules: []*trafficpolicy.Rule{
+							{
+								Route: trafficpolicy.RouteWeightedClusters{
+									HTTPRouteMatch: trafficpolicy.WildCardRouteMatch,
+									WeightedClusters: mapset.NewSet(service.WeightedCluster{
+										ClusterName: "testns/foo",
+										Weight:      100,
+									}),
+								},
+								AllowedServiceIdentities: mapset.NewSet(identity.WildcardServiceIdentity),
+							},
+						},
+					},
+				},
+				TrafficMatches: []*trafficpolicy.IngressTrafficMatch{
+					{
+						Name:                "ingress_testns/foo_80_http",
+						Protocol:            "http",
+						Port:                80,
+						SourceIPRanges:      []string{"10.0.0.10/32"}, // Endpoint of 'ingressSourceSvc' referenced as a source
+						PreserveSourceIP:    true,                     // The source IP of the clients is carried by the PROXY protocol header
+						AcceptProxyProtocol: true,
+					},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name:                        "HTTP ingress using the IngressBackend API with CSRF protection and cookie attributes",
+			ingressBackendPolicyEnabled: true,
+			meshSvc:                     service.MeshService{Name: "foo", Namespace: "testns"},
+			ingressBackend: &policyV1alpha1.IngressBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress-backend-1",
+					Namespace: "testns",
+				},
+				Spec: policyV1alpha1.IngressBackendSpec{
+					Backends: []policyV1alpha1.BackendSpec{
+						{
+							Name: "foo",
+							Port: policyV1alpha1.PortSpec{
+								Number:   80,
+								Protocol: "http",
+							},
+						},
+					},
+					Sources: []policyV1alpha1.IngressSourceSpec{
+						{
+							Kind:      policyV1alpha1.KindService,
+							Name:      ingressSourceSvc.Name,
+							Namespace: ingressSourceSvc.Namespace,
+						},
+					},
+					Security: &policyV1alpha1.IngressSecuritySpec{
+						CSRF:    &policyV1alpha1.CSRFSpec{},
+						Cookies: &policyV1alpha1.CookieAttributesSpec{Secure: true},
+					},
+				},
+			},
+			expectedPolicy: &trafficpolicy.IngressTrafficPolicy{
+				HTTPRoutePolicies: []*trafficpolicy.InboundTrafficPolicy{
+					{
+						Name: "testns/foo_from_ingress-backend-1",
+						Hostnames: []string{
+							"*",
+						},
+						Rules: []*trafficpolicy.Rule{
+							{
+								Route: trafficpolicy.RouteWeightedClusters{
+									HTTPRouteMatch: trafficpolicy.WildCardRouteMatch,
+									WeightedClusters: mapset.NewSet(service.WeightedCluster{
+										ClusterName: "testns/foo",
+										Weight:      100,
+									}),
+								},
+								AllowedServiceIdentities: mapset.NewSet(identity.WildcardServiceIdentity),
+							},
+						},
+						Security: &trafficpolicy.IngressSecurityPolicy{
+							CSRF:    &trafficpolicy.CSRFPolicy{},
+							Cookies: &trafficpolicy.CookieAttributes{Secure: true},
+						},
+					},
+				},
+				TrafficMatches: []*trafficpolicy.IngressTrafficMatch{
+					{
+						Name:           "ingress_testns/foo_80_http",
+						Protocol:       "http",
+						Port:           80,
+						SourceIPRanges: []string{"10.0.0.10/32"}, // Endpoint of 'ingressSourceSvc' referenced as a source
+						EnableCSRF:     true,
+						RewriteCookies: true,
+					},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name:                        "HTTP ingress using the IngressBackend API behind trusted proxies",
+			ingressBackendPolicyEnabled: true,
+			meshSvc:                     service.MeshService{Name: "foo", Namespace: "testns"},
+			ingressBackend: &policyV1alpha1.IngressBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress-backend-1",
+					Namespace: "testns",
+				},
+				Spec: policyV1alpha1.IngressBackendSpec{
+					Backends: []policyV1alpha1.BackendSpec{
+						{
+							Name: "foo",
+							Port: policyV1alpha1.PortSpec{
+								Number:   80,
+								Protocol: "http",
+							},
+						},
+					},
+					Sources: []policyV1alpha1.IngressSourceSpec{
+						{
+							Kind:      policyV1alpha1.KindService,
+							Name:      ingressSourceSvc.Name,
+							Namespace: ingressSourceSvc.Namespace,
+						},
+					},
+					ClientAddress: &policyV1alpha1.ClientAddressSpec{
+						XFFNumTrustedHops: 2,
+						InternalAddressConfig: policyV1alpha1.InternalAddressConfigSpec{
+							UnixSockets: true,
+						},
+					},
+				},
+			},
+			expectedPolicy: &trafficpolicy.IngressTrafficPolicy{
+				HTTPRoutePolicies: []*trafficpolicy.InboundTrafficPolicy{
+					{
+						Name: "testns/foo_from_ingress-backend-1",
+						Hostnames: []string{
+							"*",
+						},
+						Rules: []*trafficpolicy.Rule{
+							{
+								Route: trafficpolicy.RouteWeightedClusters{
+									HTTPRouteMatch: trafficpolicy.WildCardRouteMatch,
+									WeightedClusters: mapset.NewSet(service.WeightedCluster{
+										ClusterName: "testns/foo",
+										Weight:      100,
+									}),
+								},
+								AllowedServiceIdentities: mapset.NewSet(identity.WildcardServiceIdentity),
+							},
+						},
+					},
+				},
+				TrafficMatches: []*trafficpolicy.IngressTrafficMatch{
+					{
+						Name:           "ingress_testns/foo_80_http",
+						Protocol:       "http",
+						Port:           80,
+						SourceIPRanges: []string{"10.0.0.10/32"}, // Endpoint of 'ingressSourceSvc' referenced as a source
+						ClientAddress: &trafficpolicy.ClientAddressPolicy{
+							XFFNumTrustedHops:   2,
+							InternalUnixSockets: true,
+						},
+					},
+				},
+			},
+			expectError: false,
+		},
 		{
 			name:                        "HTTPS ingress with mTLS using the IngressBackend API",
 			ingressBackendPolicyEnabled: true,
@@ -2536,6 +2809,115 @@ func TestGetIngressTrafficPolicy(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name:                        "HTTPS ingress with TLS using a certificate from a secret using the IngressBackend API",
+			ingressBackendPolicyEnabled: true,
+			meshSvc:                     service.MeshService{Name: "foo", Namespace: "testns"},
+			ingressBackend: &policyV1alpha1.IngressBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress-backend-1",
+					Namespace: "testns",
+				},
+				Spec: policyV1alpha1.IngressBackendSpec{
+					Backends: []policyV1alpha1.BackendSpec{
+						{
+							Name: "foo",
+							Port: policyV1alpha1.PortSpec{
+								Number:   80,
+								Protocol: "https",
+							},
+							TLS: policyV1alpha1.TLSSpec{
+								SkipClientCertValidation: true,
+								SecretName:               "foo-tls",
+							},
+						},
+					},
+					Sources: []policyV1alpha1.IngressSourceSpec{
+						{
+							Kind:      policyV1alpha1.KindService,
+							Name:      ingressSourceSvc.Name,
+							Namespace: ingressSourceSvc.Namespace,
+						},
+						{
+							Kind: policyV1alpha1.KindAuthenticatedPrincipal,
+							Name: "ingressGw.ingressGwNs.cluster.local",
+						},
+					},
+				},
+			},
+			expectedPolicy: &trafficpolicy.IngressTrafficPolicy{
+				HTTPRoutePolicies: []*trafficpolicy.InboundTrafficPolicy{
+					{
+						Name: "testns/foo_from_ingress-backend-1",
+						Hostnames: []string{
+							"*",
+						},
+						Rules: []*trafficpolicy.Rule{
+							{
+								Route: trafficpolicy.RouteWeightedClusters{
+									HTTPRouteMatch: trafficpolicy.WildCardRouteMatch,
+									WeightedClusters: mapset.NewSet(service.WeightedCluster{
+										ClusterName: "testns/foo",
+										Weight:      100,
+									}),
+								},
+								AllowedServiceIdentities: mapset.NewSet(identity.WildcardServiceIdentity),
+							},
+						},
+					},
+				},
+				TrafficMatches: []*trafficpolicy.IngressTrafficMatch{
+					{
+						Name:                     "ingress_testns/foo_80_https",
+						Protocol:                 "https",
+						Port:                     80,
+						SourceIPRanges:           []string{"10.0.0.10/32"}, // Endpoint of 'ingressSourceSvc' referenced as a source
+						SkipClientCertValidation: true,
+						CertificateSecret:        "testns/foo-tls",
+					},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name:                        "Specifying a TLS secret not found in an IngressBackend should error",
+			ingressBackendPolicyEnabled: true,
+			meshSvc:                     service.MeshService{Name: "foo", Namespace: "testns"},
+			ingressBackend: &policyV1alpha1.IngressBackend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress-backend-1",
+					Namespace: "testns",
+				},
+				Spec: policyV1alpha1.IngressBackendSpec{
+					Backends: []policyV1alpha1.BackendSpec{
+						{
+							Name: "foo",
+							Port: policyV1alpha1.PortSpec{
+								Number:   80,
+								Protocol: "https",
+							},
+							TLS: policyV1alpha1.TLSSpec{
+								SkipClientCertValidation: true,
+								SecretName:               "unknown-tls",
+							},
+						},
+					},
+					Sources: []policyV1alpha1.IngressSourceSpec{
+						{
+							Kind:      policyV1alpha1.KindService,
+							Name:      ingressSourceSvc.Name,
+							Namespace: ingressSourceSvc.Namespace,
+						},
+						{
+							Kind: policyV1alpha1.KindAuthenticatedPrincipal,
+							Name: "ingressGw.ingressGwNs.cluster.local",
+						},
+					},
+				},
+			},
+			expectedPolicy: nil,
+			expectError:    true,
+		},
 		{
 			name:                        "Specifying a source service without endpoints in an IngressBackend should error",
 			ingressBackendPolicyEnabled: true,
@@ -2577,6 +2959,7 @@ func TestGetIngressTrafficPolicy(t *testing.T) {
 
 			mockIngressMonitor := ingress.NewMockMonitor(mockCtrl)
 			mockServiceProvider := service.NewMockProvider(mockCtrl)
+			mockServiceProvider.EXPECT().ListServicePorts(gomock.Any()).Return(nil, nil).AnyTimes()
 			mockEndpointsProvider := endpoint.NewMockProvider(mockCtrl)
 			mockCfg := configurator.NewMockConfigurator(mockCtrl)
 			mockPolicyController := policy.NewMockController(mockCtrl)
@@ -2604,6 +2987,9 @@ func TestGetIngressTrafficPolicy(t *testing.T) {
 			mockEndpointsProvider.EXPECT().ListEndpointsForService(sourceSvcWithoutEndpoints).Return(nil).AnyTimes()
 			mockEndpointsProvider.EXPECT().GetID().Return("mock").AnyTimes()
 			mockKubeController.EXPECT().UpdateStatus(gomock.Any()).Return(nil, nil).AnyTimes()
+			mockKubeController.EXPECT().GetService(tc.meshSvc).Return(tc.backendSvc).AnyTimes()
+			mockKubeController.EXPECT().GetSecret("testns", "foo-tls").Return(&corev1.Secret{}).AnyTimes()
+			mockKubeController.EXPECT().GetSecret("testns", "unknown-tls").Return(nil).AnyTimes()
 
 			actual, err := meshCatalog.GetIngressTrafficPolicy(tc.meshSvc)
 			assert.Equal(tc.expectError, err != nil)
@@ -2611,3 +2997,217 @@ func TestGetIngressTrafficPolicy(t *testing.T) {
 		})
 	}
 }
+
+func TestGetIngressBackendRoute(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		backend              policyV1alpha1.BackendSpec
+		expectedRouteMatch   trafficpolicy.HTTPRouteMatch
+		expectedRouteRewrite *trafficpolicy.HTTPRouteRewrite
+	}{
+		{
+			name:               "backend without rewrite",
+			backend:            policyV1alpha1.BackendSpec{Name: "foo"},
+			expectedRouteMatch: trafficpolicy.WildCardRouteMatch,
+		},
+		{
+			name: "backend with prefix and host rewrite",
+			backend: policyV1alpha1.BackendSpec{
+				Name: "foo",
+				Rewrite: &policyV1alpha1.RewriteSpec{
+					PathPrefix:    "/api/v1/",
+					PrefixRewrite: "/",
+					Host:          "foo.example.com",
+				},
+			},
+			expectedRouteMatch: trafficpolicy.HTTPRouteMatch{
+				Path:          "/api/v1/",
+				PathMatchType: trafficpolicy.PathMatchPrefix,
+				Methods:       []string{constants.WildcardHTTPMethod},
+			},
+			expectedRouteRewrite: &trafficpolicy.HTTPRouteRewrite{
+				PrefixRewrite: "/",
+				HostRewrite:   "foo.example.com",
+			},
+		},
+		{
+			name: "backend with regex rewrite without path prefix",
+			backend: policyV1alpha1.BackendSpec{
+				Name: "foo",
+				Rewrite: &policyV1alpha1.RewriteSpec{
+					RegexRewrite: &policyV1alpha1.RegexRewriteSpec{
+						Pattern:      "^/api/(v[0-9]+)/(.*)$",
+						Substitution: "/\\2?version=\\1",
+					},
+					AutoHost: true,
+				},
+			},
+			expectedRouteMatch: trafficpolicy.HTTPRouteMatch{
+				Path:          "/",
+				PathMatchType: trafficpolicy.PathMatchPrefix,
+				Methods:       []string{constants.WildcardHTTPMethod},
+			},
+			expectedRouteRewrite: &trafficpolicy.HTTPRouteRewrite{
+				RegexRewritePattern:      "^/api/(v[0-9]+)/(.*)$",
+				RegexRewriteSubstitution: "/\\2?version=\\1",
+				AutoHostRewrite:          true,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			routeMatch, routeRewrite := getIngressBackendRoute(tc.backend)
+			assert.Equal(tc.expectedRouteMatch, routeMatch)
+			assert.Equal(tc.expectedRouteRewrite, routeRewrite)
+		})
+	}
+}
+
+func TestGetIngressBackendCORSPolicy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		cors     *policyV1alpha1.CORSSpec
+		expected *trafficpolicy.CORSPolicy
+	}{
+		{
+			name:     "CORS unset",
+			cors:     nil,
+			expected: nil,
+		},
+		{
+			name: "CORS with max age",
+			cors: &policyV1alpha1.CORSSpec{
+				AllowOrigins:     []string{"https://example.com"},
+				AllowMethods:     []string{"GET", "POST"},
+				AllowHeaders:     []string{"Content-Type"},
+				ExposeHeaders:    []string{"X-Request-Id"},
+				MaxAge:           &metav1.Duration{Duration: time.Hour},
+				AllowCredentials: true,
+			},
+			expected: &trafficpolicy.CORSPolicy{
+				AllowOrigins:     []string{"https://example.com"},
+				AllowMethods:     []string{"GET", "POST"},
+				AllowHeaders:     []string{"Content-Type"},
+				ExposeHeaders:    []string{"X-Request-Id"},
+				MaxAge:           time.Hour,
+				AllowCredentials: true,
+			},
+		},
+		{
+			name: "CORS without max age",
+			cors: &policyV1alpha1.CORSSpec{
+				AllowOrigins: []string{"*"},
+			},
+			expected: &trafficpolicy.CORSPolicy{
+				AllowOrigins: []string{"*"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expected, getIngressBackendCORSPolicy(tc.cors))
+		})
+	}
+}
+
+func TestGetIngressBackendHeaderMutations(t *testing.T) {
+	testCases := []struct {
+		name     string
+		headers  *policyV1alpha1.HeadersSpec
+		expected *trafficpolicy.HTTPHeaderMutations
+	}{
+		{
+			name:     "headers unset",
+			headers:  nil,
+			expected: nil,
+		},
+		{
+			name: "headers added and removed",
+			headers: &policyV1alpha1.HeadersSpec{
+				RequestHeadersToAdd:     []policyV1alpha1.HeaderValueSpec{{Name: "x-correlation-id", Value: "%REQ(x-request-id)%"}},
+				ResponseHeadersToAdd:    []policyV1alpha1.HeaderValueSpec{{Name: "strict-transport-security", Value: "max-age=31536000"}},
+				ResponseHeadersToRemove: []string{"x-internal"},
+			},
+			expected: &trafficpolicy.HTTPHeaderMutations{
+				RequestHeadersToAdd:     []trafficpolicy.HTTPHeaderValue{{Name: "x-correlation-id", Value: "%REQ(x-request-id)%"}},
+				ResponseHeadersToAdd:    []trafficpolicy.HTTPHeaderValue{{Name: "strict-transport-security", Value: "max-age=31536000"}},
+				ResponseHeadersToRemove: []string{"x-internal"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expected, getIngressBackendHeaderMutations(tc.headers))
+		})
+	}
+}
+
+func TestGetIngressBackendSecurityPolicy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		security *policyV1alpha1.IngressSecuritySpec
+		expected *trafficpolicy.IngressSecurityPolicy
+	}{
+		{
+			name:     "security unset",
+			security: nil,
+			expected: nil,
+		},
+		{
+			name: "CSRF protection, security headers and cookie attributes",
+			security: &policyV1alpha1.IngressSecuritySpec{
+				CSRF: &policyV1alpha1.CSRFSpec{
+					AdditionalOrigins: []string{"https://bookstore.example.com"},
+				},
+				ResponseHeaders: &policyV1alpha1.SecurityHeadersSpec{
+					FrameOptions:          "DENY",
+					ContentSecurityPolicy: "default-src 'self'; img-src https://cdn.example.com/100%",
+					ContentTypeNoSniff:    true,
+				},
+				Cookies: &policyV1alpha1.CookieAttributesSpec{
+					Secure:   true,
+					HTTPOnly: true,
+					SameSite: "Strict",
+				},
+			},
+			expected: &trafficpolicy.IngressSecurityPolicy{
+				CSRF: &trafficpolicy.CSRFPolicy{
+					AdditionalOrigins: []string{"https://bookstore.example.com"},
+				},
+				ResponseHeaders: []trafficpolicy.HTTPHeaderValue{
+					{Name: "X-Frame-Options", Value: "DENY"},
+					{Name: "Content-Security-Policy", Value: "default-src 'self'; img-src https://cdn.example.com/100%%"},
+					{Name: "X-Content-Type-Options", Value: "nosniff"},
+				},
+				Cookies: &trafficpolicy.CookieAttributes{
+					Secure:   true,
+					HTTPOnly: true,
+					SameSite: "Strict",
+				},
+			},
+		},
+		{
+			name: "CSRF protection in shadow mode only",
+			security: &policyV1alpha1.IngressSecuritySpec{
+				CSRF: &policyV1alpha1.CSRFSpec{ShadowMode: true},
+			},
+			expected: &trafficpolicy.IngressSecurityPolicy{
+				CSRF: &trafficpolicy.CSRFPolicy{ShadowMode: true},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expected, getIngressBackendSecurityPolicy(tc.security))
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package catalog
+
+import (
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// getIsolatedNamespaces returns the namespaces the workloads in the given source namespace are allowed to initiate
+// outbound connections to: the source namespace itself and the namespaces allowed by its NamespaceIsolation policies.
+// It returns nil if namespace isolation is disabled, in which case all namespaces are allowed.
+func (mc *MeshCatalog) getIsolatedNamespaces(sourceNamespace string) map[string]struct{} {
+	if !mc.configurator.IsNamespaceIsolationEnabled() {
+		return nil
+	}
+
+	allowedNamespaces := map[string]struct{}{sourceNamespace: {}}
+	for _, namespaceIsolation := range mc.policyController.ListNamespaceIsolationPolicies(sourceNamespace) {
+		for _, ns := range namespaceIsolation.Spec.AllowedNamespaces {
+			allowedNamespaces[ns] = struct{}{}
+		}
+	}
+	return allowedNamespaces
+}
+
+// filterIsolatedServices returns the given services the workloads in the given source namespace are allowed to
+// initiate outbound connections to when namespace isolation is enabled, all of them otherwise
+func (mc *MeshCatalog) filterIsolatedServices(sourceNamespace string, services []service.MeshService) []service.MeshService {
+	allowedNamespaces := mc.getIsolatedNamespaces(sourceNamespace)
+	if allowedNamespaces == nil {
+		return services
+	}
+
+	var allowedServices []service.MeshService
+	for _, svc := range services {
+		if _, ok := allowedNamespaces[svc.Namespace]; !ok {
+			log.Trace().Msgf("Namespace isolation: service %s is not allowed for the workloads in namespace %s", svc, sourceNamespace)
+			continue
+		}
+		allowedServices = append(allowedServices, svc)
+	}
+	return allowedServices
+}
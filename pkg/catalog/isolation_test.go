@@ -0,0 +1,93 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/policy"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+func TestListOutboundServicesForIdentityWithNamespaceIsolation(t *testing.T) {
+	tenantA := service.MeshService{Name: "web", Namespace: "tenant-a"}
+	tenantB := service.MeshService{Name: "web", Namespace: "tenant-b"}
+	shared := service.MeshService{Name: "db", Namespace: "shared"}
+	meshServices := []service.MeshService{tenantA, tenantB, shared}
+
+	namespaceIsolation := &policyV1alpha1.NamespaceIsolation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "shared",
+			Namespace: "tenant-a",
+		},
+		Spec: policyV1alpha1.NamespaceIsolationSpec{
+			AllowedNamespaces: []string{"shared"},
+		},
+	}
+
+	testCases := []struct {
+		name                string
+		isolationEnabled    bool
+		sourceNamespace     string
+		namespaceIsolations []*policyV1alpha1.NamespaceIsolation
+		expectedServices    []service.MeshService
+	}{
+		{
+			name:             "namespace isolation disabled",
+			isolationEnabled: false,
+			sourceNamespace:  "tenant-a",
+			expectedServices: meshServices,
+		},
+		{
+			name:             "namespace isolation enabled without NamespaceIsolation policies",
+			isolationEnabled: true,
+			sourceNamespace:  "tenant-a",
+			expectedServices: []service.MeshService{tenantA},
+		},
+		{
+			name:                "namespace isolation enabled with a NamespaceIsolation policy",
+			isolationEnabled:    true,
+			sourceNamespace:     "tenant-a",
+			namespaceIsolations: []*policyV1alpha1.NamespaceIsolation{namespaceIsolation},
+			expectedServices:    []service.MeshService{tenantA, shared},
+		},
+		{
+			name:             "namespace isolation enabled for a namespace without services",
+			isolationEnabled: true,
+			sourceNamespace:  "tenant-c",
+			expectedServices: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockPolicyController := policy.NewMockController(mockCtrl)
+			mockServiceProvider := service.NewMockProvider(mockCtrl)
+
+			mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(true).AnyTimes()
+			mockConfigurator.EXPECT().IsNamespaceIsolationEnabled().Return(tc.isolationEnabled).AnyTimes()
+			mockPolicyController.EXPECT().ListNamespaceIsolationPolicies(tc.sourceNamespace).Return(tc.namespaceIsolations).AnyTimes()
+			mockServiceProvider.EXPECT().ListServices().Return(meshServices, nil).AnyTimes()
+
+			mc := MeshCatalog{
+				configurator:     mockConfigurator,
+				policyController: mockPolicyController,
+				serviceProviders: []service.Provider{mockServiceProvider},
+			}
+
+			svcIdentity := identity.K8sServiceAccount{Name: "sa", Namespace: tc.sourceNamespace}.ToServiceIdentity()
+			assert.ElementsMatch(tc.expectedServices, mc.ListOutboundServicesForIdentity(svcIdentity))
+		})
+	}
+}
@@ -142,6 +142,34 @@ func (mr *MockMeshCatalogerMockRecorder) GetTargetPortToProtocolMappingForServic
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTargetPortToProtocolMappingForService", reflect.TypeOf((*MockMeshCataloger)(nil).GetTargetPortToProtocolMappingForService), arg0)
 }
 
+// GetUpstreamPeerValidation mocks base method
+func (m *MockMeshCataloger) GetUpstreamPeerValidation(arg0 service.MeshService) *trafficpolicy.UpstreamPeerValidation {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUpstreamPeerValidation", arg0)
+	ret0, _ := ret[0].(*trafficpolicy.UpstreamPeerValidation)
+	return ret0
+}
+
+// GetUpstreamPeerValidation indicates an expected call of GetUpstreamPeerValidation
+func (mr *MockMeshCatalogerMockRecorder) GetUpstreamPeerValidation(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUpstreamPeerValidation", reflect.TypeOf((*MockMeshCataloger)(nil).GetUpstreamPeerValidation), arg0)
+}
+
+// GetUpstreamRetryBudget mocks base method
+func (m *MockMeshCataloger) GetUpstreamRetryBudget(arg0 service.MeshService) *trafficpolicy.RetryBudget {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUpstreamRetryBudget", arg0)
+	ret0, _ := ret[0].(*trafficpolicy.RetryBudget)
+	return ret0
+}
+
+// GetUpstreamRetryBudget indicates an expected call of GetUpstreamRetryBudget
+func (mr *MockMeshCatalogerMockRecorder) GetUpstreamRetryBudget(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUpstreamRetryBudget", reflect.TypeOf((*MockMeshCataloger)(nil).GetUpstreamRetryBudget), arg0)
+}
+
 // GetWeightedClustersForUpstream mocks base method
 func (m *MockMeshCataloger) GetWeightedClustersForUpstream(arg0 service.MeshService) []service.WeightedCluster {
 	m.ctrl.T.Helper()
@@ -171,6 +199,34 @@ func (mr *MockMeshCatalogerMockRecorder) ListEndpointsForServiceIdentity(arg0, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEndpointsForServiceIdentity", reflect.TypeOf((*MockMeshCataloger)(nil).ListEndpointsForServiceIdentity), arg0, arg1)
 }
 
+// ListExternalServices mocks base method
+func (m *MockMeshCataloger) ListExternalServices() []endpoint.ExternalService {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExternalServices")
+	ret0, _ := ret[0].([]endpoint.ExternalService)
+	return ret0
+}
+
+// ListExternalServices indicates an expected call of ListExternalServices
+func (mr *MockMeshCatalogerMockRecorder) ListExternalServices() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExternalServices", reflect.TypeOf((*MockMeshCataloger)(nil).ListExternalServices))
+}
+
+// ListExternalServicesForIdentity mocks base method
+func (m *MockMeshCataloger) ListExternalServicesForIdentity(arg0 identity.ServiceIdentity) []endpoint.ExternalService {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExternalServicesForIdentity", arg0)
+	ret0, _ := ret[0].([]endpoint.ExternalService)
+	return ret0
+}
+
+// ListExternalServicesForIdentity indicates an expected call of ListExternalServicesForIdentity
+func (mr *MockMeshCatalogerMockRecorder) ListExternalServicesForIdentity(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExternalServicesForIdentity", reflect.TypeOf((*MockMeshCataloger)(nil).ListExternalServicesForIdentity), arg0)
+}
+
 // ListInboundServiceIdentities mocks base method
 func (m *MockMeshCataloger) ListInboundServiceIdentities(arg0 identity.ServiceIdentity) ([]identity.ServiceIdentity, error) {
 	m.ctrl.T.Helper()
@@ -258,6 +314,20 @@ func (mr *MockMeshCatalogerMockRecorder) ListOutboundServicesForIdentity(arg0 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOutboundServicesForIdentity", reflect.TypeOf((*MockMeshCataloger)(nil).ListOutboundServicesForIdentity), arg0)
 }
 
+// ListExportedServicesForMulticlusterGateway mocks base method
+func (m *MockMeshCataloger) ListExportedServicesForMulticlusterGateway() []service.MeshService {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExportedServicesForMulticlusterGateway")
+	ret0, _ := ret[0].([]service.MeshService)
+	return ret0
+}
+
+// ListExportedServicesForMulticlusterGateway indicates an expected call of ListExportedServicesForMulticlusterGateway
+func (mr *MockMeshCatalogerMockRecorder) ListExportedServicesForMulticlusterGateway() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExportedServicesForMulticlusterGateway", reflect.TypeOf((*MockMeshCataloger)(nil).ListExportedServicesForMulticlusterGateway))
+}
+
 // ListOutboundServicesForMulticlusterGateway mocks base method
 func (m *MockMeshCataloger) ListOutboundServicesForMulticlusterGateway() []service.MeshService {
 	m.ctrl.T.Helper()
@@ -286,6 +356,20 @@ func (mr *MockMeshCatalogerMockRecorder) ListOutboundTrafficPolicies(arg0 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOutboundTrafficPolicies", reflect.TypeOf((*MockMeshCataloger)(nil).ListOutboundTrafficPolicies), arg0)
 }
 
+// ListOutboundTrafficPoliciesForHosts mocks base method
+func (m *MockMeshCataloger) ListOutboundTrafficPoliciesForHosts(arg0 identity.ServiceIdentity, arg1 []string) []*trafficpolicy.OutboundTrafficPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOutboundTrafficPoliciesForHosts", arg0, arg1)
+	ret0, _ := ret[0].([]*trafficpolicy.OutboundTrafficPolicy)
+	return ret0
+}
+
+// ListOutboundTrafficPoliciesForHosts indicates an expected call of ListOutboundTrafficPoliciesForHosts
+func (mr *MockMeshCatalogerMockRecorder) ListOutboundTrafficPoliciesForHosts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOutboundTrafficPoliciesForHosts", reflect.TypeOf((*MockMeshCataloger)(nil).ListOutboundTrafficPoliciesForHosts), arg0, arg1)
+}
+
 // ListServiceIdentitiesForService mocks base method
 func (m *MockMeshCataloger) ListServiceIdentitiesForService(arg0 service.MeshService) ([]identity.ServiceIdentity, error) {
 	m.ctrl.T.Helper()
@@ -300,3 +384,18 @@ func (mr *MockMeshCatalogerMockRecorder) ListServiceIdentitiesForService(arg0 in
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServiceIdentitiesForService", reflect.TypeOf((*MockMeshCataloger)(nil).ListServiceIdentitiesForService), arg0)
 }
+
+// ListServicePorts mocks base method
+func (m *MockMeshCataloger) ListServicePorts(arg0 service.MeshService) ([]service.ServicePort, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListServicePorts", arg0)
+	ret0, _ := ret[0].([]service.ServicePort)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListServicePorts indicates an expected call of ListServicePorts
+func (mr *MockMeshCatalogerMockRecorder) ListServicePorts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServicePorts", reflect.TypeOf((*MockMeshCataloger)(nil).ListServicePorts), arg0)
+}
@@ -0,0 +1,67 @@
+package catalog
+
+import (
+	access "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// SetMulticlusterGatewayIdentity sets the service identity of the multicluster gateway. Downstreams from remote trust
+// domains reach exported services through the gateway, which originates connections to them with this identity.
+func (mc *MeshCatalog) SetMulticlusterGatewayIdentity(gatewayIdentity identity.ServiceIdentity) {
+	mc.multiclusterGatewayIdentity = gatewayIdentity
+}
+
+// ListExportedServicesForMulticlusterGateway lists the services exported to remote clusters, which the multicluster
+// gateway forwards connections from downstreams in remote trust domains to
+func (mc *MeshCatalog) ListExportedServicesForMulticlusterGateway() []service.MeshService {
+	var exported []service.MeshService
+	for _, svc := range mc.kubeController.ListServices() {
+		if k8s.IsMulticlusterExported(svc) {
+			exported = append(exported, service.MeshService{Name: svc.Name, Namespace: svc.Namespace})
+		}
+	}
+	return exported
+}
+
+// getMulticlusterGatewaySource returns the identity of the multicluster gateway if the gateway forwards connections
+// from remote trust domains to the given upstream identity, or an empty identity otherwise
+func (mc *MeshCatalog) getMulticlusterGatewaySource(upstream identity.ServiceIdentity) identity.ServiceIdentity {
+	if mc.multiclusterGatewayIdentity == "" || !mc.configurator.GetFeatureFlags().EnableMulticlusterMode {
+		return ""
+	}
+
+	for _, svc := range mc.ListExportedServicesForMulticlusterGateway() {
+		identities, err := mc.ListServiceIdentitiesForService(svc)
+		if err != nil {
+			continue
+		}
+		for _, svcIdentity := range identities {
+			if svcIdentity == upstream {
+				return mc.multiclusterGatewayIdentity
+			}
+		}
+	}
+	return ""
+}
+
+// withMulticlusterGatewaySource returns a copy of the given TrafficTarget with the given multicluster gateway identity
+// added to its sources, or the TrafficTarget itself if the gateway identity is empty. The gateway enforces the sources
+// of the TrafficTarget on the connections from remote trust domains it forwards, so the upstream grants the gateway
+// the routes of the TrafficTarget.
+func withMulticlusterGatewaySource(t *access.TrafficTarget, gatewayIdentity identity.ServiceIdentity) *access.TrafficTarget {
+	if gatewayIdentity == "" {
+		return t
+	}
+
+	gatewaySvcAccount := gatewayIdentity.ToK8sServiceAccount()
+	t = t.DeepCopy()
+	t.Spec.Sources = append(t.Spec.Sources, access.IdentityBindingSubject{
+		Kind:      serviceAccountKind,
+		Name:      gatewaySvcAccount.Name,
+		Namespace: gatewaySvcAccount.Namespace,
+	})
+	return t
+}
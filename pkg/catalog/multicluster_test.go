@@ -0,0 +1,82 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	access "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/tests"
+)
+
+func TestGetMulticlusterGatewaySource(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockServiceProvider := service.NewMockProvider(mockCtrl)
+	mc := &MeshCatalog{
+		kubeController:   mockKubeController,
+		configurator:     mockConfigurator,
+		serviceProviders: []service.Provider{mockServiceProvider},
+	}
+
+	gatewayIdentity := identity.K8sServiceAccount{Name: "osm", Namespace: "osm-system"}.ToServiceIdentity()
+	exportedSvc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        tests.BookstoreV1ServiceName,
+			Namespace:   tests.Namespace,
+			Annotations: map[string]string{constants.MulticlusterExportAnnotation: "true"},
+		},
+	}
+	notExportedSvc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: tests.BookbuyerServiceName, Namespace: tests.Namespace},
+	}
+
+	mockConfigurator.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{EnableMulticlusterMode: true}).AnyTimes()
+	mockKubeController.EXPECT().ListServices().Return([]*corev1.Service{exportedSvc, notExportedSvc}).AnyTimes()
+	mockServiceProvider.EXPECT().ListServiceIdentitiesForService(tests.BookstoreV1Service).Return([]identity.ServiceIdentity{tests.BookstoreServiceIdentity}, nil).AnyTimes()
+
+	assert.Equal([]service.MeshService{tests.BookstoreV1Service}, mc.ListExportedServicesForMulticlusterGateway())
+
+	// The gateway is not a source until its identity is known
+	assert.Empty(mc.getMulticlusterGatewaySource(tests.BookstoreServiceIdentity))
+
+	mc.SetMulticlusterGatewayIdentity(gatewayIdentity)
+	assert.Equal(gatewayIdentity, mc.getMulticlusterGatewaySource(tests.BookstoreServiceIdentity))
+
+	// The gateway does not forward connections to identities that do not back an exported service
+	assert.Empty(mc.getMulticlusterGatewaySource(tests.BookbuyerServiceIdentity))
+}
+
+func TestWithMulticlusterGatewaySource(t *testing.T) {
+	assert := tassert.New(t)
+
+	trafficTarget := &access.TrafficTarget{
+		Spec: access.TrafficTargetSpec{
+			Sources: []access.IdentityBindingSubject{{Kind: serviceAccountKind, Name: "bookbuyer", Namespace: "bookbuyer"}},
+		},
+	}
+
+	assert.Same(trafficTarget, withMulticlusterGatewaySource(trafficTarget, ""))
+
+	withGateway := withMulticlusterGatewaySource(trafficTarget, identity.K8sServiceAccount{Name: "osm", Namespace: "osm-system"}.ToServiceIdentity())
+	assert.Equal([]access.IdentityBindingSubject{
+		{Kind: serviceAccountKind, Name: "bookbuyer", Namespace: "bookbuyer"},
+		{Kind: serviceAccountKind, Name: "osm", Namespace: "osm-system"},
+	}, withGateway.Spec.Sources)
+
+	// The original TrafficTarget is left untouched
+	assert.Len(trafficTarget.Spec.Sources, 1)
+}
@@ -1,22 +1,48 @@
 package catalog
 
 import (
+	"fmt"
+	"strings"
+
 	mapset "github.com/deckarep/golang-set"
 	"github.com/pkg/errors"
 	access "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	smiSplit "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha4"
 
 	"github.com/openservicemesh/osm/pkg/errcode"
 	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/k8s"
 	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/smi"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
 // ListOutboundTrafficPolicies returns all outbound traffic policies
 // 1. from service discovery for permissive mode
 // 2. for the given service account from SMI Traffic Target and Traffic Split
+// The policies routing to services exposing multiple ports are split into a policy per port, and the policies of
+// upstream services with a retry policy are given the retry policy.
 // Note: ServiceIdentity must be in the format "name.namespace" [https://github.com/openservicemesh/osm/issues/3188]
 func (mc *MeshCatalog) ListOutboundTrafficPolicies(downstreamIdentity identity.ServiceIdentity) []*trafficpolicy.OutboundTrafficPolicy {
+	return mc.applyUpstreamRetryPolicies(mc.qualifyOutboundPoliciesByPort(mc.listUnqualifiedOutboundTrafficPolicies(downstreamIdentity)))
+}
+
+// ListOutboundTrafficPoliciesForHosts returns the outbound traffic policies of the given service identity for the
+// given hosts, which are the names of the policies. Only the policies of the given hosts are split into a policy per
+// port, so that the policies of a few hosts are listed without qualifying the policies of all the hosts by port.
+// Note: ServiceIdentity must be in the format "name.namespace" [https://github.com/openservicemesh/osm/issues/3188]
+func (mc *MeshCatalog) ListOutboundTrafficPoliciesForHosts(downstreamIdentity identity.ServiceIdentity, hosts []string) []*trafficpolicy.OutboundTrafficPolicy {
+	if len(hosts) == 0 {
+		return nil
+	}
+
+	outbound := filterOutboundPoliciesForHosts(mc.listUnqualifiedOutboundTrafficPolicies(downstreamIdentity), hosts)
+	return mc.applyUpstreamRetryPolicies(filterOutboundPoliciesForHosts(mc.qualifyOutboundPoliciesByPort(outbound), hosts))
+}
+
+// listUnqualifiedOutboundTrafficPolicies returns the outbound traffic policies of the given service identity, before
+// the policies routing to services exposing multiple ports are split into a policy per port
+func (mc *MeshCatalog) listUnqualifiedOutboundTrafficPolicies(downstreamIdentity identity.ServiceIdentity) []*trafficpolicy.OutboundTrafficPolicy {
 	downstreamServiceAccount := downstreamIdentity.ToK8sServiceAccount()
 	if mc.configurator.IsPermissiveTrafficPolicyMode() {
 		var outboundPolicies []*trafficpolicy.OutboundTrafficPolicy
@@ -27,9 +53,28 @@ func (mc *MeshCatalog) ListOutboundTrafficPolicies(downstreamIdentity identity.S
 
 	outbound := mc.listOutboundPoliciesForTrafficTargets(downstreamIdentity)
 	outboundPoliciesFromSplits := mc.listOutboundTrafficPoliciesForTrafficSplits(downstreamServiceAccount.Namespace)
-	outbound = trafficpolicy.MergeOutboundPolicies(AllowPartialHostnamesMatch, outbound, outboundPoliciesFromSplits...)
+	return trafficpolicy.MergeOutboundPolicies(AllowPartialHostnamesMatch, outbound, outboundPoliciesFromSplits...)
+}
+
+// filterOutboundPoliciesForHosts returns the given outbound policies of the given hosts. The policy of a host
+// qualified by port, in the form <name>:<port>, is the policy named after the host, or else the policy of the name of
+// the host before it is split into a policy per port.
+func filterOutboundPoliciesForHosts(policies []*trafficpolicy.OutboundTrafficPolicy, hosts []string) []*trafficpolicy.OutboundTrafficPolicy {
+	names := make(map[string]struct{})
+	for _, host := range hosts {
+		names[host] = struct{}{}
+		if i := strings.LastIndex(host, ":"); i > 0 {
+			names[host[:i]] = struct{}{}
+		}
+	}
 
-	return outbound
+	var filtered []*trafficpolicy.OutboundTrafficPolicy
+	for _, policy := range policies {
+		if _, ok := names[policy.Name]; ok {
+			filtered = append(filtered, policy)
+		}
+	}
+	return filtered
 }
 
 // listOutboundPoliciesForTrafficTargets loops through all SMI Traffic Target resources and returns outbound traffic policies
@@ -39,14 +84,18 @@ func (mc *MeshCatalog) listOutboundPoliciesForTrafficTargets(downstreamIdentity
 	downstreamServiceAccount := downstreamIdentity.ToK8sServiceAccount()
 	var outboundPolicies []*trafficpolicy.OutboundTrafficPolicy
 
+	allowedNamespaces := mc.getIsolatedNamespaces(downstreamServiceAccount.Namespace)
 	for _, t := range mc.meshSpec.ListTrafficTargets() { // loop through all traffic targets
 		if !isValidTrafficTarget(t) {
 			continue
 		}
+		if _, ok := allowedNamespaces[t.Spec.Destination.Namespace]; allowedNamespaces != nil && !ok {
+			continue
+		}
 
 		for _, source := range t.Spec.Sources {
 			// TODO(draychev): must check for the correct type of ServiceIdentity as well
-			if source.Name == downstreamServiceAccount.Name && source.Namespace == downstreamServiceAccount.Namespace { // found outbound
+			if trafficTargetIdentityToSvcAccount(source).Matches(downstreamServiceAccount) { // found outbound
 				mergedPolicies := trafficpolicy.MergeOutboundPolicies(AllowPartialHostnamesMatch, outboundPolicies, mc.buildOutboundPolicies(downstreamIdentity, t)...)
 				outboundPolicies = mergedPolicies
 				break
@@ -56,27 +105,42 @@ func (mc *MeshCatalog) listOutboundPoliciesForTrafficTargets(downstreamIdentity
 	return outboundPolicies
 }
 
+// listOutboundTrafficPoliciesForTrafficSplits returns an outbound traffic policy per apex service of the TrafficSplits.
+// The requests matching the HTTPRouteGroups referenced by a TrafficSplit are split between its backends, while the
+// remaining requests are split between the backends of the TrafficSplit without matches for the same apex service,
+// or sent to the apex service itself if there is none.
 func (mc *MeshCatalog) listOutboundTrafficPoliciesForTrafficSplits(sourceNamespace string) []*trafficpolicy.OutboundTrafficPolicy {
 	var outboundPoliciesFromSplits []*trafficpolicy.OutboundTrafficPolicy
 
-	apexServices := mapset.NewSet()
+	policies := make(map[service.MeshService]*trafficpolicy.OutboundTrafficPolicy)
+	defaultBackends := make(map[service.MeshService][]service.WeightedCluster)
+	allowedNamespaces := mc.getIsolatedNamespaces(sourceNamespace)
 	for _, split := range mc.meshSpec.ListTrafficSplits() {
+		if _, ok := allowedNamespaces[split.Namespace]; allowedNamespaces != nil && !ok {
+			continue
+		}
+
 		svc := service.MeshService{
 			Name:      k8s.GetServiceFromHostname(split.Spec.Service),
 			Namespace: split.Namespace,
 		}
 
-		locality := service.LocalCluster
-		if svc.Namespace == sourceNamespace {
-			locality = service.LocalNS
-		}
-		hostnames, err := mc.GetServiceHostnames(svc, locality)
-		if err != nil {
-			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrServiceHostnames)).
-				Msgf("Error getting service hostnames for apex service %v", svc)
-			continue
+		policy, ok := policies[svc]
+		if !ok {
+			locality := service.LocalCluster
+			if svc.Namespace == sourceNamespace {
+				locality = service.LocalNS
+			}
+			hostnames, err := mc.GetServiceHostnames(svc, locality)
+			if err != nil {
+				log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrServiceHostnames)).
+					Msgf("Error getting service hostnames for apex service %v", svc)
+				continue
+			}
+			policy = trafficpolicy.NewOutboundTrafficPolicy(svc.FQDN(), hostnames)
+			policies[svc] = policy
+			outboundPoliciesFromSplits = append(outboundPoliciesFromSplits, policy)
 		}
-		policy := trafficpolicy.NewOutboundTrafficPolicy(svc.FQDN(), hostnames)
 
 		var weightedClusters []service.WeightedCluster
 		for _, backend := range split.Spec.Backends {
@@ -91,39 +155,81 @@ func (mc *MeshCatalog) listOutboundTrafficPoliciesForTrafficSplits(sourceNamespa
 			weightedClusters = append(weightedClusters, wc)
 		}
 
-		rwc := trafficpolicy.NewRouteWeightedCluster(trafficpolicy.WildCardRouteMatch, weightedClusters)
-		policy.Routes = []*trafficpolicy.RouteWeightedClusters{rwc}
+		if len(split.Spec.Matches) == 0 {
+			if _, ok := defaultBackends[svc]; ok {
+				// TODO: enhancement(#2759)
+				log.Error().Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMultipleSMISplitPerServiceUnsupported)).
+					Msgf("Skipping Traffic Split policy %s in namespaces %s as there is already a traffic split policy without matches for apex service %v", split.Name, split.Namespace, svc)
+				continue
+			}
+			defaultBackends[svc] = weightedClusters
+			continue
+		}
 
-		if apexServices.Contains(svc) {
-			// TODO: enhancement(#2759)
-			log.Error().Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMultipleSMISplitPerServiceUnsupported)).
-				Msgf("Skipping Traffic Split policy %s in namespaces %s as there is already a traffic split policy for apex service %v", split.Name, split.Namespace, svc)
-		} else {
-			outboundPoliciesFromSplits = append(outboundPoliciesFromSplits, policy)
-			apexServices.Add(svc)
+		routeMatches, err := mc.getTrafficSplitRouteMatches(split)
+		if err != nil {
+			log.Error().Err(err).Msgf("Skipping Traffic Split policy %s in namespace %s with invalid matches", split.Name, split.Namespace)
+			continue
+		}
+		for _, routeMatch := range routeMatches {
+			if err := policy.AddRoute(routeMatch, weightedClusters...); err != nil {
+				log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrAddingRouteToOutboundTrafficPolicy)).
+					Msgf("Error adding route of Traffic Split policy %s in namespace %s to outbound policy for apex service %v", split.Name, split.Namespace, svc)
+			}
 		}
 	}
+
+	// The wildcard route is added last so the routes of the matches take precedence
+	for svc, policy := range policies {
+		weightedClusters, ok := defaultBackends[svc]
+		if !ok {
+			weightedClusters = []service.WeightedCluster{getDefaultWeightedClusterForService(svc)}
+		}
+		if err := policy.AddRoute(trafficpolicy.WildCardRouteMatch, weightedClusters...); err != nil {
+			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrAddingRouteToOutboundTrafficPolicy)).
+				Msgf("Error adding wildcard route to outbound policy for apex service %v", svc)
+		}
+	}
+
 	return outboundPoliciesFromSplits
 }
 
+// getTrafficSplitRouteMatches returns the HTTP route matches of the HTTPRouteGroups referenced by the given TrafficSplit
+func (mc *MeshCatalog) getTrafficSplitRouteMatches(split *smiSplit.TrafficSplit) ([]trafficpolicy.HTTPRouteMatch, error) {
+	var routeMatches []trafficpolicy.HTTPRouteMatch
+	for _, match := range split.Spec.Matches {
+		if match.Kind != httpRouteGroupKind {
+			return nil, errors.Errorf("Unsupported match kind %s", match.Kind)
+		}
+		routeGroup := mc.meshSpec.GetHTTPRouteGroup(fmt.Sprintf("%s/%s", split.Namespace, match.Name))
+		if routeGroup == nil {
+			return nil, errors.Errorf("HTTPRouteGroup %s/%s not found", split.Namespace, match.Name)
+		}
+		routeMatches = append(routeMatches, getHTTPRouteMatchesFromHTTPRouteGroup(routeGroup)...)
+	}
+	return routeMatches, nil
+}
+
 // ListOutboundServicesForMulticlusterGateway lists the upstream services for the multicluster gateway
 // TODO: improve code by combining with ListOutboundServicesForIdentity
 func (mc *MeshCatalog) ListOutboundServicesForMulticlusterGateway() []service.MeshService {
 	return mc.listMeshServices()
 }
 
-// ListOutboundServicesForIdentity list the services the given service account is allowed to initiate outbound connections to
+// ListOutboundServicesForIdentity list the services the given service account is allowed to initiate outbound connections to.
+// When namespace isolation is enabled, the services are restricted to the namespaces allowed for the service account's
+// namespace, including in permissive traffic policy mode.
 // Note: ServiceIdentity must be in the format "name.namespace" [https://github.com/openservicemesh/osm/issues/3188]
 func (mc *MeshCatalog) ListOutboundServicesForIdentity(serviceIdentity identity.ServiceIdentity) []service.MeshService {
 	ident := serviceIdentity.ToK8sServiceAccount()
 	if mc.configurator.IsPermissiveTrafficPolicyMode() {
-		return mc.listMeshServices()
+		return mc.filterIsolatedServices(ident.Namespace, mc.listMeshServices())
 	}
 
 	serviceSet := mapset.NewSet()
 	for _, t := range mc.meshSpec.ListTrafficTargets() { // loop through all traffic targets
 		for _, source := range t.Spec.Sources {
-			if source.Name == ident.Name && source.Namespace == ident.Namespace { // found outbound
+			if trafficTargetIdentityToSvcAccount(source).Matches(ident) { // found outbound
 				sa := identity.K8sServiceAccount{
 					Name:      t.Spec.Destination.Name,
 					Namespace: t.Spec.Destination.Namespace,
@@ -146,13 +252,13 @@ func (mc *MeshCatalog) ListOutboundServicesForIdentity(serviceIdentity identity.
 	for elem := range serviceSet.Iter() {
 		allowedServices = append(allowedServices, elem.(service.MeshService))
 	}
-	return allowedServices
+	return mc.filterIsolatedServices(ident.Namespace, allowedServices)
 }
 
 func (mc *MeshCatalog) buildOutboundPermissiveModePolicies(sourceNamespace string) []*trafficpolicy.OutboundTrafficPolicy {
 	var outPolicies []*trafficpolicy.OutboundTrafficPolicy
 
-	destServices := mc.listMeshServices()
+	destServices := mc.filterIsolatedServices(sourceNamespace, mc.listMeshServices())
 
 	for _, destService := range destServices {
 		locality := service.LocalCluster
@@ -263,7 +369,8 @@ func (mc *MeshCatalog) getDestinationServicesFromTrafficTarget(t *access.Traffic
 }
 
 // GetWeightedClustersForUpstream returns Envoy cluster weights for the given
-// upstream service, the apex service of a TrafficSplit.
+// upstream service, the apex service of a TrafficSplit. The weights are normalized
+// to smi.TrafficSplitWeightTotal, and TrafficSplits with invalid weights or with matches are ignored.
 func (mc *MeshCatalog) GetWeightedClustersForUpstream(upstream service.MeshService) []service.WeightedCluster {
 	var weightedClusters []service.WeightedCluster
 	apexServices := mapset.NewSet()
@@ -279,21 +386,33 @@ func (mc *MeshCatalog) GetWeightedClustersForUpstream(upstream service.MeshServi
 			continue
 		}
 
+		// Requests can't be matched against HTTPRouteGroups at the TCP level
+		if len(split.Spec.Matches) > 0 {
+			continue
+		}
+
 		if apexServices.Contains(split.Spec.Service) {
 			log.Error().Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMultipleSMISplitPerServiceUnsupported)).
 				Msgf("Skipping traffic split policy %s/%s as there is already a corresponding policy for apex service %s", split.Namespace, split.Name, split.Spec.Service)
 			continue
 		}
 
-		for _, backend := range split.Spec.Backends {
-			if backend.Weight == 0 {
+		if err := smi.ValidateTrafficSplitBackends(split.Spec.Backends); err != nil {
+			log.Error().Err(err).Msgf("Skipping traffic split policy %s/%s with invalid backend weights", split.Namespace, split.Name)
+			continue
+		}
+
+		// Normalize the weights to the total expected by Envoy
+		weights := smi.NormalizeTrafficSplitWeights(split.Spec.Backends)
+		for i, backend := range split.Spec.Backends {
+			if weights[i] == 0 {
 				// Skip backends with a weight of 0
 				log.Warn().Msgf("Skipping backend %s that has a weight of 0 in traffic split policy %s/%s", backend.Service, split.Namespace, split.Name)
 				continue
 			}
 			backendCluster := service.WeightedCluster{
 				ClusterName: service.ClusterName(split.Namespace + "/" + backend.Service),
-				Weight:      backend.Weight,
+				Weight:      weights[i],
 			}
 			weightedClusters = append(weightedClusters, backendCluster)
 		}
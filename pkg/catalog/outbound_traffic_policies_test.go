@@ -1,13 +1,14 @@
 package catalog
 
 import (
+	"fmt"
 	"testing"
 
 	mapset "github.com/deckarep/golang-set"
 	"github.com/golang/mock/gomock"
 	access "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
 	spec "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
-	split "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha2"
+	split "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha4"
 	tassert "github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -17,6 +18,8 @@ import (
 	"github.com/openservicemesh/osm/pkg/endpoint"
 	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/policy"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/smi"
 	"github.com/openservicemesh/osm/pkg/tests"
@@ -339,6 +342,7 @@ func TestListOutboundTrafficPolicies(t *testing.T) {
 			mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
 			mockEndpointProvider := endpoint.NewMockProvider(mockCtrl)
 			mockServiceProvider := service.NewMockProvider(mockCtrl)
+			mockServiceProvider.EXPECT().ListServicePorts(gomock.Any()).Return(nil, nil).AnyTimes()
 			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
 
 			mockEndpointProvider.EXPECT().GetID().Return("fake").AnyTimes()
@@ -372,12 +376,16 @@ func TestListOutboundTrafficPolicies(t *testing.T) {
 				mockKubeController.EXPECT().GetService(tests.BookstoreApexService).Return(tests.NewServiceFixture(tests.BookstoreApexService.Name, tests.BookstoreApexService.Namespace, map[string]string{})).AnyTimes()
 			}
 
+			mockPolicyController := policy.NewMockController(mockCtrl)
+			mockPolicyController.EXPECT().GetUpstreamTrafficSetting(gomock.Any()).Return(nil).AnyTimes()
+
 			mc := MeshCatalog{
 				kubeController:     mockKubeController,
 				meshSpec:           mockMeshSpec,
 				endpointsProviders: []endpoint.Provider{mockEndpointProvider},
 				serviceProviders:   []service.Provider{mockServiceProvider},
 				configurator:       mockConfigurator,
+				policyController:   mockPolicyController,
 			}
 
 			expectedServices := tc.meshServices
@@ -392,12 +400,66 @@ func TestListOutboundTrafficPolicies(t *testing.T) {
 			mockServiceProvider.EXPECT().ListServices().Return(tc.meshServices, nil).AnyTimes()
 
 			mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(tc.permissiveMode).AnyTimes()
+			mockConfigurator.EXPECT().IsNamespaceIsolationEnabled().Return(false).AnyTimes()
 			outbound := mc.ListOutboundTrafficPolicies(tc.downstreamSA)
 			assert.ElementsMatch(tc.expectedOutbound, outbound)
 		})
 	}
 }
 
+func BenchmarkListOutboundTrafficPolicies(b *testing.B) {
+	// Keep the logging of the catalog out of the measurements
+	if err := logger.SetLogLevel("error"); err != nil {
+		b.Fatal(err)
+	}
+
+	for _, services := range tests.BenchmarkTopologyServices {
+		b.Run(fmt.Sprintf("%d services", services), func(b *testing.B) {
+			topology := tests.NewTopologyBuilder().
+				WithNamespaces(tests.BenchmarkTopologyNamespaces).
+				WithServicesPerNamespace(services / tests.BenchmarkTopologyNamespaces).
+				Build()
+			k8sServices := make(map[service.MeshService]*corev1.Service)
+			for _, svc := range topology.Services {
+				k8sServices[tests.NewMeshServiceFixture(svc.Name, svc.Namespace)] = svc
+			}
+
+			mockCtrl := gomock.NewController(b)
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mockServiceProvider := service.NewMockProvider(mockCtrl)
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockKubeController.EXPECT().GetService(gomock.Any()).DoAndReturn(func(svc service.MeshService) *corev1.Service {
+				return k8sServices[svc]
+			}).AnyTimes()
+			mockServiceProvider.EXPECT().ListServices().Return(topology.MeshServices(), nil).AnyTimes()
+			mockServiceProvider.EXPECT().GetHostnamesForService(gomock.Any(), gomock.Any()).DoAndReturn(func(svc service.MeshService, _ service.Locality) ([]string, error) {
+				return topology.ServiceHostnames(svc), nil
+			}).AnyTimes()
+			mockServiceProvider.EXPECT().ListServicePorts(gomock.Any()).Return(nil, nil).AnyTimes()
+			mockServiceProvider.EXPECT().GetID().Return("fake").AnyTimes()
+			mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(true).AnyTimes()
+			mockConfigurator.EXPECT().IsNamespaceIsolationEnabled().Return(false).AnyTimes()
+
+			mockPolicyController := policy.NewMockController(mockCtrl)
+			mockPolicyController.EXPECT().GetUpstreamTrafficSetting(gomock.Any()).Return(nil).AnyTimes()
+
+			mc := MeshCatalog{
+				kubeController:   mockKubeController,
+				serviceProviders: []service.Provider{mockServiceProvider},
+				configurator:     mockConfigurator,
+				policyController: mockPolicyController,
+			}
+			downstream := identity.ServiceIdentity("svc-0.ns-0.cluster.local")
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				mc.ListOutboundTrafficPolicies(downstream)
+			}
+		})
+	}
+}
+
 func TestListOutboundTrafficPoliciesForTrafficSplits(t *testing.T) {
 	assert := tassert.New(t)
 
@@ -488,6 +550,49 @@ func TestListOutboundTrafficPoliciesForTrafficSplits(t *testing.T) {
 		},
 	}
 
+	testSplit5 := split.TrafficSplit{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: "bar",
+		},
+		Spec: split.TrafficSplitSpec{
+			Service: "apex-split-1",
+			Matches: []corev1.TypedLocalObjectReference{
+				{
+					Kind: "HTTPRouteGroup",
+					Name: "canary-users",
+				},
+			},
+			Backends: []split.TrafficSplitBackend{
+				{
+					Service: tests.BookstoreV2ServiceName,
+					Weight:  tests.Weight90,
+				},
+			},
+		},
+	}
+
+	canaryUsersRouteGroup := &spec.HTTPRouteGroup{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: "bar",
+			Name:      "canary-users",
+		},
+		Spec: spec.HTTPRouteGroupSpec{
+			Matches: []spec.HTTPMatch{
+				{
+					Name:    "canary-header",
+					Headers: map[string]string{"x-canary": "true"},
+				},
+			},
+		},
+	}
+
+	canaryUsersRouteMatch := trafficpolicy.HTTPRouteMatch{
+		Path:          ".*",
+		PathMatchType: trafficpolicy.PathMatchRegex,
+		Methods:       []string{"*"},
+		Headers:       map[string]string{"x-canary": "true"},
+	}
+
 	testSplit3NamespacedHostnames := []string{
 		"apex-split-1.baz",
 		"apex-split-1.baz.svc",
@@ -506,6 +611,7 @@ func TestListOutboundTrafficPoliciesForTrafficSplits(t *testing.T) {
 		expectedPolicies []*trafficpolicy.OutboundTrafficPolicy
 		expectedRoutes   []*trafficpolicy.RouteWeightedClusters
 		apexMeshServices []service.MeshService
+		routeGroups      []*spec.HTTPRouteGroup
 	}{
 		{
 			name:            "single traffic split policy in different namespace",
@@ -697,6 +803,97 @@ func TestListOutboundTrafficPoliciesForTrafficSplits(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:            "traffic splits with and without matches",
+			sourceNamespace: "foo",
+			trafficsplits:   []*split.TrafficSplit{&testSplit5, &testSplit1},
+			routeGroups:     []*spec.HTTPRouteGroup{canaryUsersRouteGroup},
+			apexMeshServices: []service.MeshService{
+				{
+					Name:      "apex-split-1",
+					Namespace: "bar",
+				},
+			},
+			expectedPolicies: []*trafficpolicy.OutboundTrafficPolicy{
+				{
+					Name:      "apex-split-1.bar.svc.cluster.local",
+					Hostnames: testSplit1NamespacedHostnames,
+					Routes: []*trafficpolicy.RouteWeightedClusters{
+						{
+							HTTPRouteMatch: canaryUsersRouteMatch,
+							WeightedClusters: mapset.NewSetFromSlice([]interface{}{
+								service.WeightedCluster{ClusterName: "bar/bookstore-v2", Weight: 90},
+							}),
+						},
+						{
+							HTTPRouteMatch: tests.WildCardRouteMatch,
+							WeightedClusters: mapset.NewSetFromSlice([]interface{}{
+								service.WeightedCluster{ClusterName: "bar/bookstore-v1", Weight: 10},
+								service.WeightedCluster{ClusterName: "bar/bookstore-v2", Weight: 90},
+							}),
+						},
+					},
+				},
+			},
+		},
+		{
+			name:            "traffic split with matches only routes other requests to the apex service",
+			sourceNamespace: "foo",
+			trafficsplits:   []*split.TrafficSplit{&testSplit5},
+			routeGroups:     []*spec.HTTPRouteGroup{canaryUsersRouteGroup},
+			apexMeshServices: []service.MeshService{
+				{
+					Name:      "apex-split-1",
+					Namespace: "bar",
+				},
+			},
+			expectedPolicies: []*trafficpolicy.OutboundTrafficPolicy{
+				{
+					Name:      "apex-split-1.bar.svc.cluster.local",
+					Hostnames: testSplit1NamespacedHostnames,
+					Routes: []*trafficpolicy.RouteWeightedClusters{
+						{
+							HTTPRouteMatch: canaryUsersRouteMatch,
+							WeightedClusters: mapset.NewSetFromSlice([]interface{}{
+								service.WeightedCluster{ClusterName: "bar/bookstore-v2", Weight: 90},
+							}),
+						},
+						{
+							HTTPRouteMatch: tests.WildCardRouteMatch,
+							WeightedClusters: mapset.NewSetFromSlice([]interface{}{
+								service.WeightedCluster{ClusterName: "bar/apex-split-1", Weight: 100},
+							}),
+						},
+					},
+				},
+			},
+		},
+		{
+			name:            "traffic split with a missing HTTPRouteGroup is ignored",
+			sourceNamespace: "foo",
+			trafficsplits:   []*split.TrafficSplit{&testSplit5, &testSplit1},
+			apexMeshServices: []service.MeshService{
+				{
+					Name:      "apex-split-1",
+					Namespace: "bar",
+				},
+			},
+			expectedPolicies: []*trafficpolicy.OutboundTrafficPolicy{
+				{
+					Name:      "apex-split-1.bar.svc.cluster.local",
+					Hostnames: testSplit1NamespacedHostnames,
+					Routes: []*trafficpolicy.RouteWeightedClusters{
+						{
+							HTTPRouteMatch: tests.WildCardRouteMatch,
+							WeightedClusters: mapset.NewSetFromSlice([]interface{}{
+								service.WeightedCluster{ClusterName: "bar/bookstore-v1", Weight: 10},
+								service.WeightedCluster{ClusterName: "bar/bookstore-v2", Weight: 90},
+							}),
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -708,18 +905,25 @@ func TestListOutboundTrafficPoliciesForTrafficSplits(t *testing.T) {
 			mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
 			mockEndpointProvider := endpoint.NewMockProvider(mockCtrl)
 			mockServiceProvider := service.NewMockProvider(mockCtrl)
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
 
 			for _, ms := range tc.apexMeshServices {
 				apexK8sService := tests.NewServiceFixture(ms.Name, ms.Namespace, map[string]string{})
 				mockKubeController.EXPECT().GetService(ms).Return(apexK8sService).AnyTimes()
 			}
 			mockMeshSpec.EXPECT().ListTrafficSplits().Return(tc.trafficsplits).AnyTimes()
+			for _, routeGroup := range tc.routeGroups {
+				mockMeshSpec.EXPECT().GetHTTPRouteGroup(routeGroup.Namespace + "/" + routeGroup.Name).Return(routeGroup).AnyTimes()
+			}
+			mockMeshSpec.EXPECT().GetHTTPRouteGroup(gomock.Any()).Return(nil).AnyTimes()
+			mockConfigurator.EXPECT().IsNamespaceIsolationEnabled().Return(false).AnyTimes()
 
 			mc := MeshCatalog{
 				kubeController:     mockKubeController,
 				meshSpec:           mockMeshSpec,
 				endpointsProviders: []endpoint.Provider{mockEndpointProvider},
 				serviceProviders:   []service.Provider{mockServiceProvider},
+				configurator:       mockConfigurator,
 			}
 
 			for _, ms := range tc.apexMeshServices {
@@ -799,12 +1003,15 @@ func TestBuildOutboundPermissiveModePolicies(t *testing.T) {
 	mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
 	mockEndpointProvider := endpoint.NewMockProvider(mockCtrl)
 	mockServiceProvider := service.NewMockProvider(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().IsNamespaceIsolationEnabled().Return(false).AnyTimes()
 
 	mc := MeshCatalog{
 		kubeController:     mockKubeController,
 		meshSpec:           mockMeshSpec,
 		endpointsProviders: []endpoint.Provider{mockEndpointProvider},
 		serviceProviders:   []service.Provider{mockServiceProvider},
+		configurator:       mockConfigurator,
 	}
 
 	testCases := []struct {
@@ -1123,12 +1330,15 @@ func TestListOutboundPoliciesForTrafficTargets(t *testing.T) {
 			mockKubeController.EXPECT().GetService(tests.BookstoreV1Service).Return(tests.NewServiceFixture(tests.BookstoreV1Service.Name, tests.BookstoreV1Service.Namespace, map[string]string{})).AnyTimes()
 			mockKubeController.EXPECT().GetService(tests.BookstoreV2Service).Return(tests.NewServiceFixture(tests.BookstoreV2Service.Name, tests.BookstoreV2Service.Namespace, map[string]string{})).AnyTimes()
 			mockKubeController.EXPECT().GetService(tests.BookstoreApexService).Return(tests.NewServiceFixture(tests.BookstoreApexService.Name, tests.BookstoreApexService.Namespace, map[string]string{})).AnyTimes()
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockConfigurator.EXPECT().IsNamespaceIsolationEnabled().Return(false).AnyTimes()
 
 			mc := MeshCatalog{
 				kubeController:     mockKubeController,
 				meshSpec:           mockMeshSpec,
 				endpointsProviders: []endpoint.Provider{mockEndpointProvider},
 				serviceProviders:   []service.Provider{mockServiceProvider},
+				configurator:       mockConfigurator,
 			}
 
 			meshServices := []service.MeshService{
@@ -1416,6 +1626,7 @@ func TestListMeshServicesForIdentity(t *testing.T) {
 	mockServiceProvider := service.NewMockProvider(mockCtrl)
 	mockConfigurator.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{EnableMulticlusterMode: true}).AnyTimes()
 	mockConfigurator.EXPECT().GetOSMNamespace().Return("osm-system").AnyTimes()
+	mockConfigurator.EXPECT().IsNamespaceIsolationEnabled().Return(false).AnyTimes()
 
 	mc := MeshCatalog{
 		meshSpec:         mockMeshSpec,
@@ -1512,3 +1723,51 @@ func TestListMeshServicesForIdentity(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterOutboundPoliciesForHosts(t *testing.T) {
+	policies := []*trafficpolicy.OutboundTrafficPolicy{
+		{Name: "bookstore-v1.default.svc.cluster.local"},
+		{Name: "bookstore-v2.default.svc.cluster.local:8080"},
+		{Name: "bookstore-v2.default.svc.cluster.local:9090"},
+		{Name: "bookstore-apex.default.svc.cluster.local"},
+	}
+
+	testCases := []struct {
+		name          string
+		hosts         []string
+		expectedNames []string
+	}{
+		{
+			name:          "no hosts",
+			hosts:         nil,
+			expectedNames: nil,
+		},
+		{
+			name:          "hosts matching policies",
+			hosts:         []string{"bookstore-v1.default.svc.cluster.local", "bookstore-v2.default.svc.cluster.local:9090"},
+			expectedNames: []string{"bookstore-v1.default.svc.cluster.local", "bookstore-v2.default.svc.cluster.local:9090"},
+		},
+		{
+			name:          "host qualified by port matching the policy of its name",
+			hosts:         []string{"bookstore-apex.default.svc.cluster.local:8080"},
+			expectedNames: []string{"bookstore-apex.default.svc.cluster.local"},
+		},
+		{
+			name:          "unknown host",
+			hosts:         []string{"bookbuyer.default.svc.cluster.local"},
+			expectedNames: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			var actualNames []string
+			for _, policy := range filterOutboundPoliciesForHosts(policies, tc.hosts) {
+				actualNames = append(actualNames, policy.Name)
+			}
+			assert.Equal(tc.expectedNames, actualNames)
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package catalog
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	mapset "github.com/deckarep/golang-set"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// defaultHTTPPort is the port implied by a Host header that doesn't specify one
+const defaultHTTPPort = 80
+
+// servicePortsCache caches the ports of the services referenced by the traffic policies being qualified by port
+type servicePortsCache struct {
+	mc    *MeshCatalog
+	ports map[service.MeshService][]service.ServicePort
+}
+
+func (mc *MeshCatalog) newServicePortsCache() *servicePortsCache {
+	return &servicePortsCache{
+		mc:    mc,
+		ports: make(map[service.MeshService][]service.ServicePort),
+	}
+}
+
+// get returns the ports of the given service, nil if they can't be retrieved
+func (c *servicePortsCache) get(svc service.MeshService) []service.ServicePort {
+	if ports, ok := c.ports[svc]; ok {
+		return ports
+	}
+	ports, err := c.mc.ListServicePorts(svc)
+	if err != nil {
+		log.Debug().Err(err).Msgf("Error listing the ports of service %s", svc)
+	}
+	c.ports[svc] = ports
+	return ports
+}
+
+// getMultiPortServices returns the services exposing multiple ports among the services backing the given routes,
+// along with their ports
+func (c *servicePortsCache) getMultiPortServices(routes []*trafficpolicy.RouteWeightedClusters) map[service.MeshService][]service.ServicePort {
+	multiPortServices := make(map[service.MeshService][]service.ServicePort)
+	for _, route := range routes {
+		for elem := range route.WeightedClusters.Iter() {
+			svc, port, err := service.ParseClusterName(elem.(service.WeightedCluster).ClusterName.String())
+			if err != nil || port != 0 {
+				// Clusters that aren't named after a service, or that already back a single port, aren't qualified
+				continue
+			}
+			if ports := c.get(svc); len(ports) > 1 {
+				multiPortServices[svc] = ports
+			}
+		}
+	}
+	return multiPortServices
+}
+
+// qualifyRoute returns a copy of the given route whose clusters backing services that expose multiple ports are
+// replaced by the clusters backing the given port of these services
+func (c *servicePortsCache) qualifyRoute(route trafficpolicy.RouteWeightedClusters, port uint32) trafficpolicy.RouteWeightedClusters {
+	weightedClusters := mapset.NewSet()
+	for elem := range route.WeightedClusters.Iter() {
+		wc := elem.(service.WeightedCluster)
+		if svc, svcPort, err := service.ParseClusterName(wc.ClusterName.String()); err == nil && svcPort == 0 {
+			wc.ClusterName = service.GetClusterNameForPort(svc, c.get(svc), port)
+		}
+		weightedClusters.Add(wc)
+	}
+	route.WeightedClusters = weightedClusters
+	return route
+}
+
+// qualifyOutboundPoliciesByPort returns the given outbound policies, where the policies routing to services exposing
+// multiple ports are split into a policy per port. Each of these policies routes the hostnames qualified with its port
+// to the clusters backing the port, so that the requests to each port are load balanced to the target port of the
+// service, see getHostnamesByPort.
+func (mc *MeshCatalog) qualifyOutboundPoliciesByPort(policies []*trafficpolicy.OutboundTrafficPolicy) []*trafficpolicy.OutboundTrafficPolicy {
+	cache := mc.newServicePortsCache()
+
+	var qualifiedPolicies []*trafficpolicy.OutboundTrafficPolicy
+	for _, policy := range policies {
+		multiPortServices := cache.getMultiPortServices(policy.Routes)
+		if len(multiPortServices) == 0 {
+			qualifiedPolicies = append(qualifiedPolicies, policy)
+			continue
+		}
+
+		hostnamesByPort := getHostnamesByPort(policy.Hostnames, multiPortServices)
+		for _, port := range getSortedPorts(hostnamesByPort) {
+			portPolicy := trafficpolicy.NewOutboundTrafficPolicy(fmt.Sprintf("%s:%d", policy.Name, port), hostnamesByPort[port])
+			for _, route := range policy.Routes {
+				qualifiedRoute := cache.qualifyRoute(*route, port)
+				portPolicy.Routes = append(portPolicy.Routes, &qualifiedRoute)
+			}
+			qualifiedPolicies = append(qualifiedPolicies, portPolicy)
+		}
+	}
+
+	return qualifiedPolicies
+}
+
+// qualifyInboundPoliciesByPort returns the given inbound policies, where the policies routing to services exposing
+// multiple ports are split into a policy per port, similarly to qualifyOutboundPoliciesByPort.
+func (mc *MeshCatalog) qualifyInboundPoliciesByPort(policies []*trafficpolicy.InboundTrafficPolicy) []*trafficpolicy.InboundTrafficPolicy {
+	cache := mc.newServicePortsCache()
+
+	var qualifiedPolicies []*trafficpolicy.InboundTrafficPolicy
+	for _, policy := range policies {
+		var routes []*trafficpolicy.RouteWeightedClusters
+		for _, rule := range policy.Rules {
+			routes = append(routes, &rule.Route)
+		}
+		multiPortServices := cache.getMultiPortServices(routes)
+		if len(multiPortServices) == 0 {
+			qualifiedPolicies = append(qualifiedPolicies, policy)
+			continue
+		}
+
+		hostnamesByPort := getHostnamesByPort(policy.Hostnames, multiPortServices)
+		for _, port := range getSortedPorts(hostnamesByPort) {
+			portPolicy := *policy
+			portPolicy.Name = fmt.Sprintf("%s:%d", policy.Name, port)
+			portPolicy.Hostnames = hostnamesByPort[port]
+			portPolicy.Rules = nil
+			for _, rule := range policy.Rules {
+				portPolicy.Rules = append(portPolicy.Rules, &trafficpolicy.Rule{
+					Route:                    cache.qualifyRoute(rule.Route, port),
+					AllowedServiceIdentities: rule.AllowedServiceIdentities,
+				})
+			}
+			qualifiedPolicies = append(qualifiedPolicies, &portPolicy)
+		}
+	}
+
+	return qualifiedPolicies
+}
+
+// getWeightedClusterForServicePort returns the weighted cluster routing to the port of the given service referenced by
+// number, or by name when the number is 0
+func (mc *MeshCatalog) getWeightedClusterForServicePort(svc service.MeshService, portNumber uint32, portName string) service.WeightedCluster {
+	return mc.getWeightedClusterForMatchingPort(svc, func(port service.ServicePort) bool {
+		if portNumber != 0 {
+			return port.Port == portNumber
+		}
+		return port.Name == portName
+	})
+}
+
+// getWeightedClusterForTargetPort returns the weighted cluster routing to the port of the given service with the given
+// target port
+func (mc *MeshCatalog) getWeightedClusterForTargetPort(svc service.MeshService, targetPort uint32) service.WeightedCluster {
+	return mc.getWeightedClusterForMatchingPort(svc, func(port service.ServicePort) bool {
+		return port.TargetPort == targetPort
+	})
+}
+
+// getWeightedClusterForMatchingPort returns the weighted cluster routing to the first port of the given service
+// matching the given function, or to the service itself if it exposes a single port or no port matches
+func (mc *MeshCatalog) getWeightedClusterForMatchingPort(svc service.MeshService, matches func(service.ServicePort) bool) service.WeightedCluster {
+	weightedCluster := getDefaultWeightedClusterForService(svc)
+
+	ports, err := mc.ListServicePorts(svc)
+	if err != nil || len(ports) <= 1 {
+		return weightedCluster
+	}
+	for _, port := range ports {
+		if matches(port) {
+			weightedCluster.ClusterName = svc.PortClusterName(port.Port)
+			break
+		}
+	}
+	return weightedCluster
+}
+
+// getHostnamesByPort groups the given hostnames by the port of the given services they refer to. A hostname qualified
+// with a port, ex. 'bookstore:8080', refers to that port. A hostname without a port refers to port 80 when a service
+// exposes it, since HTTP clients omit the default port from the Host header, and to the first HTTP or gRPC port of the
+// services otherwise.
+func getHostnamesByPort(hostnames []string, services map[service.MeshService][]service.ServicePort) map[uint32][]string {
+	defaultPort := getDefaultHTTPPort(services)
+
+	hostnamesByPort := make(map[uint32][]string)
+	for _, hostname := range hostnames {
+		port := defaultPort
+		if i := strings.LastIndex(hostname, ":"); i != -1 {
+			if p, err := strconv.ParseUint(hostname[i+1:], 10, 32); err == nil {
+				port = uint32(p)
+			}
+		}
+		hostnamesByPort[port] = append(hostnamesByPort[port], hostname)
+	}
+	return hostnamesByPort
+}
+
+// getDefaultHTTPPort returns the port of the given services that requests without a port in their Host header refer to
+func getDefaultHTTPPort(services map[service.MeshService][]service.ServicePort) uint32 {
+	var defaultPort uint32
+	for _, ports := range services {
+		for _, port := range ports {
+			if port.Port == defaultHTTPPort {
+				return defaultHTTPPort
+			}
+			protocol := strings.ToLower(port.Protocol)
+			if (protocol == constants.ProtocolHTTP || protocol == constants.ProtocolGRPC) && (defaultPort == 0 || port.Port < defaultPort) {
+				defaultPort = port.Port
+			}
+		}
+	}
+	if defaultPort == 0 {
+		return defaultHTTPPort
+	}
+	return defaultPort
+}
+
+func getSortedPorts(hostnamesByPort map[uint32][]string) []uint32 {
+	var ports []uint32
+	for port := range hostnamesByPort {
+		ports = append(ports, port)
+	}
+	sort.Slice(ports, func(i, j int) bool {
+		return ports[i] < ports[j]
+	})
+	return ports
+}
@@ -0,0 +1,194 @@
+package catalog
+
+import (
+	"testing"
+
+	mapset "github.com/deckarep/golang-set"
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+var (
+	multiPortService = service.MeshService{Namespace: "ns", Name: "multi"}
+	multiPorts       = []service.ServicePort{
+		{Name: "http", Port: 80, TargetPort: 8080, Protocol: "http"},
+		{Name: "grpc", Port: 9090, TargetPort: 9091, Protocol: "grpc"},
+		{Name: "db", Port: 5432, TargetPort: 5432, Protocol: "tcp"},
+	}
+	singlePortService = service.MeshService{Namespace: "ns", Name: "single"}
+	singlePorts       = []service.ServicePort{
+		{Name: "http", Port: 80, TargetPort: 8080, Protocol: "http"},
+	}
+)
+
+func newMultiPortTestCatalog(mockCtrl *gomock.Controller) *MeshCatalog {
+	provider := service.NewMockProvider(mockCtrl)
+	provider.EXPECT().GetID().Return("mock").AnyTimes()
+	provider.EXPECT().ListServicePorts(multiPortService).Return(multiPorts, nil).AnyTimes()
+	provider.EXPECT().ListServicePorts(singlePortService).Return(singlePorts, nil).AnyTimes()
+
+	return &MeshCatalog{
+		serviceProviders: []service.Provider{provider},
+	}
+}
+
+func TestListServicePorts(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	svc := service.MeshService{Namespace: "ns", Name: "svc"}
+
+	provider1 := service.NewMockProvider(mockCtrl)
+	provider2 := service.NewMockProvider(mockCtrl)
+	provider1.EXPECT().GetID().Return("provider1").AnyTimes()
+	provider2.EXPECT().GetID().Return("provider2").AnyTimes()
+	mc := &MeshCatalog{
+		serviceProviders: []service.Provider{provider1, provider2},
+	}
+
+	// The ports of the providers are merged and sorted by port
+	provider1.EXPECT().ListServicePorts(svc).Return([]service.ServicePort{multiPorts[1]}, nil)
+	provider2.EXPECT().ListServicePorts(svc).Return([]service.ServicePort{multiPorts[1], multiPorts[0]}, nil)
+	ports, err := mc.ListServicePorts(svc)
+	assert.Nil(err)
+	assert.Equal([]service.ServicePort{multiPorts[0], multiPorts[1]}, ports)
+
+	// A port with a different protocol across providers is an error
+	conflictingPort := multiPorts[0]
+	conflictingPort.Protocol = "tcp"
+	provider1.EXPECT().ListServicePorts(svc).Return([]service.ServicePort{multiPorts[0]}, nil)
+	provider2.EXPECT().ListServicePorts(svc).Return([]service.ServicePort{conflictingPort}, nil)
+	_, err = mc.ListServicePorts(svc)
+	assert.NotNil(err)
+
+	// A service without ports is an error
+	provider1.EXPECT().ListServicePorts(svc).Return(nil, nil)
+	provider2.EXPECT().ListServicePorts(svc).Return(nil, nil)
+	_, err = mc.ListServicePorts(svc)
+	assert.NotNil(err)
+}
+
+func TestQualifyOutboundPoliciesByPort(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mc := newMultiPortTestCatalog(mockCtrl)
+
+	singlePortPolicy := trafficpolicy.NewOutboundTrafficPolicy(singlePortService.FQDN(), []string{"single", "single:80"})
+	assert.Nil(singlePortPolicy.AddRoute(trafficpolicy.WildCardRouteMatch, getDefaultWeightedClusterForService(singlePortService)))
+
+	multiPortPolicy := trafficpolicy.NewOutboundTrafficPolicy(multiPortService.FQDN(), []string{"multi", "multi:80", "multi:9090", "multi.ns:5432"})
+	assert.Nil(multiPortPolicy.AddRoute(trafficpolicy.WildCardRouteMatch, getDefaultWeightedClusterForService(multiPortService)))
+
+	actual := mc.qualifyOutboundPoliciesByPort([]*trafficpolicy.OutboundTrafficPolicy{singlePortPolicy, multiPortPolicy})
+	assert.Len(actual, 4)
+
+	// Policies routing to services exposing a single port are unchanged
+	assert.Equal(singlePortPolicy, actual[0])
+
+	// Policies routing to services exposing multiple ports are split per port
+	expected := []struct {
+		name      string
+		hostnames []string
+		cluster   service.ClusterName
+	}{
+		{name: "multi.ns.svc.cluster.local:80", hostnames: []string{"multi", "multi:80"}, cluster: "ns/multi|80"},
+		{name: "multi.ns.svc.cluster.local:5432", hostnames: []string{"multi.ns:5432"}, cluster: "ns/multi|5432"},
+		{name: "multi.ns.svc.cluster.local:9090", hostnames: []string{"multi:9090"}, cluster: "ns/multi|9090"},
+	}
+	for i, e := range expected {
+		policy := actual[i+1]
+		assert.Equal(e.name, policy.Name)
+		assert.Equal(e.hostnames, policy.Hostnames)
+		assert.Len(policy.Routes, 1)
+		assert.Equal(trafficpolicy.WildCardRouteMatch, policy.Routes[0].HTTPRouteMatch)
+		assert.True(policy.Routes[0].WeightedClusters.Equal(mapset.NewSet(service.WeightedCluster{ClusterName: e.cluster, Weight: 100})))
+	}
+}
+
+func TestQualifyInboundPoliciesByPort(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mc := newMultiPortTestCatalog(mockCtrl)
+
+	policy := trafficpolicy.NewInboundTrafficPolicy(multiPortService.FQDN(), []string{"multi", "multi:9090"})
+	policy.AddRule(*trafficpolicy.NewRouteWeightedCluster(trafficpolicy.WildCardRouteMatch, []service.WeightedCluster{getDefaultWeightedClusterForService(multiPortService)}), identity.WildcardServiceIdentity)
+
+	actual := mc.qualifyInboundPoliciesByPort([]*trafficpolicy.InboundTrafficPolicy{policy})
+	assert.Len(actual, 2)
+
+	assert.Equal("multi.ns.svc.cluster.local:80", actual[0].Name)
+	assert.Equal([]string{"multi"}, actual[0].Hostnames)
+	assert.Len(actual[0].Rules, 1)
+	assert.True(actual[0].Rules[0].Route.WeightedClusters.Equal(mapset.NewSet(service.WeightedCluster{ClusterName: "ns/multi|80", Weight: 100})))
+	assert.True(actual[0].Rules[0].AllowedServiceIdentities.Contains(identity.WildcardServiceIdentity))
+
+	assert.Equal("multi.ns.svc.cluster.local:9090", actual[1].Name)
+	assert.Equal([]string{"multi:9090"}, actual[1].Hostnames)
+	assert.True(actual[1].Rules[0].Route.WeightedClusters.Equal(mapset.NewSet(service.WeightedCluster{ClusterName: "ns/multi|9090", Weight: 100})))
+
+	// The original policy is not modified
+	assert.Equal(multiPortService.FQDN(), policy.Name)
+	assert.True(policy.Rules[0].Route.WeightedClusters.Equal(mapset.NewSet(getDefaultWeightedClusterForService(multiPortService))))
+}
+
+func TestGetWeightedClusterForServicePort(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mc := newMultiPortTestCatalog(mockCtrl)
+
+	assert.Equal(service.ClusterName("ns/multi|5432"), mc.getWeightedClusterForServicePort(multiPortService, 5432, "").ClusterName)
+	assert.Equal(service.ClusterName("ns/multi|9090"), mc.getWeightedClusterForServicePort(multiPortService, 0, "grpc").ClusterName)
+	assert.Equal(service.ClusterName("ns/multi|9090"), mc.getWeightedClusterForTargetPort(multiPortService, 9091).ClusterName)
+	assert.Equal(service.ClusterName("ns/multi"), mc.getWeightedClusterForTargetPort(multiPortService, 1234).ClusterName)
+	assert.Equal(service.ClusterName("ns/single"), mc.getWeightedClusterForServicePort(singlePortService, 80, "").ClusterName)
+}
+
+func TestGetHostnamesByPort(t *testing.T) {
+	testCases := []struct {
+		name     string
+		ports    []service.ServicePort
+		expected map[uint32][]string
+	}{
+		{
+			name:  "hostnames without a port refer to port 80",
+			ports: multiPorts,
+			expected: map[uint32][]string{
+				80:   {"svc", "svc:80"},
+				9090: {"svc:9090"},
+			},
+		},
+		{
+			name: "hostnames without a port refer to the first HTTP port when port 80 isn't exposed",
+			ports: []service.ServicePort{
+				{Port: 5432, Protocol: "tcp"},
+				{Port: 9090, Protocol: "grpc"},
+				{Port: 8080, Protocol: "http"},
+			},
+			expected: map[uint32][]string{
+				8080: {"svc"},
+				80:   {"svc:80"},
+				9090: {"svc:9090"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			services := map[service.MeshService][]service.ServicePort{multiPortService: tc.ports}
+			assert.Equal(tc.expected, getHostnamesByPort([]string{"svc", "svc:80", "svc:9090"}, services))
+		})
+	}
+}
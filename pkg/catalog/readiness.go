@@ -0,0 +1,70 @@
+package catalog
+
+import (
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// readinessProbeID is the ID of the readiness probe of the mesh catalog
+	readinessProbeID = "mesh-catalog"
+)
+
+// Liveness is the Kubernetes liveness probe handler of the mesh catalog
+func (mc *MeshCatalog) Liveness() bool {
+	return true
+}
+
+// Readiness is the Kubernetes readiness probe handler of the mesh catalog.
+// The catalog is ready once the caches of the informers of all the Kubernetes, SMI and policy resources have synced,
+// and it produced a consistent snapshot of the mesh. Once ready, the catalog remains ready.
+func (mc *MeshCatalog) Readiness() bool {
+	if atomic.LoadInt32(&mc.ready) == 1 {
+		return true
+	}
+
+	if err := mc.checkReadiness(); err != nil {
+		log.Debug().Err(err).Msg("Mesh catalog is not ready")
+		return false
+	}
+
+	if atomic.CompareAndSwapInt32(&mc.ready, 0, 1) {
+		log.Info().Msg("Mesh catalog is ready: informer caches synced and mesh snapshot produced")
+	}
+	return true
+}
+
+// GetID returns the ID of the probe
+func (mc *MeshCatalog) GetID() string {
+	return readinessProbeID
+}
+
+// checkReadiness returns an error if the informer caches the catalog depends on have not synced, or the catalog cannot
+// produce a consistent snapshot of the mesh
+func (mc *MeshCatalog) checkReadiness() error {
+	if mc.kubeController != nil && !mc.kubeController.HasSynced() {
+		return errors.New("Kubernetes informer caches have not synced")
+	}
+	if mc.meshSpec != nil && !mc.meshSpec.HasSynced() {
+		return errors.New("SMI informer caches have not synced")
+	}
+	if mc.policyController != nil && !mc.policyController.HasSynced() {
+		return errors.New("Policy informer caches have not synced")
+	}
+
+	// Every service listed must resolve to its service identities, otherwise the caches are not consistent
+	for _, provider := range mc.serviceProviders {
+		services, err := provider.ListServices()
+		if err != nil {
+			return errors.Wrapf(err, "Error listing services for provider %s", provider.GetID())
+		}
+		for _, svc := range services {
+			if _, err := provider.ListServiceIdentitiesForService(svc); err != nil {
+				return errors.Wrapf(err, "Error getting service identities of service %s for provider %s", svc, provider.GetID())
+			}
+		}
+	}
+
+	return nil
+}
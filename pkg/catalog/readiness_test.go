@@ -0,0 +1,121 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/policy"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/smi"
+	"github.com/openservicemesh/osm/pkg/tests"
+)
+
+func TestReadiness(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		kubeSynced            bool
+		smiSynced             bool
+		policySynced          bool
+		listServicesErr       error
+		listServiceIdentities error
+		expectedReady         bool
+	}{
+		{
+			name:          "all caches synced and consistent snapshot",
+			kubeSynced:    true,
+			smiSynced:     true,
+			policySynced:  true,
+			expectedReady: true,
+		},
+		{
+			name:          "Kubernetes caches not synced",
+			kubeSynced:    false,
+			smiSynced:     true,
+			policySynced:  true,
+			expectedReady: false,
+		},
+		{
+			name:          "SMI caches not synced",
+			kubeSynced:    true,
+			smiSynced:     false,
+			policySynced:  true,
+			expectedReady: false,
+		},
+		{
+			name:          "policy caches not synced",
+			kubeSynced:    true,
+			smiSynced:     true,
+			policySynced:  false,
+			expectedReady: false,
+		},
+		{
+			name:            "error listing services",
+			kubeSynced:      true,
+			smiSynced:       true,
+			policySynced:    true,
+			listServicesErr: errors.New("unavailable"),
+			expectedReady:   false,
+		},
+		{
+			name:                  "service identities of a listed service not found",
+			kubeSynced:            true,
+			smiSynced:             true,
+			policySynced:          true,
+			listServiceIdentities: errors.New("service not found"),
+			expectedReady:         false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
+			mockPolicyController := policy.NewMockController(mockCtrl)
+			mockServiceProvider := service.NewMockProvider(mockCtrl)
+
+			mockKubeController.EXPECT().HasSynced().Return(tc.kubeSynced).AnyTimes()
+			mockMeshSpec.EXPECT().HasSynced().Return(tc.smiSynced).AnyTimes()
+			mockPolicyController.EXPECT().HasSynced().Return(tc.policySynced).AnyTimes()
+			mockServiceProvider.EXPECT().GetID().Return("mock").AnyTimes()
+			mockServiceProvider.EXPECT().ListServices().Return([]service.MeshService{tests.BookstoreV1Service}, tc.listServicesErr).AnyTimes()
+			mockServiceProvider.EXPECT().ListServiceIdentitiesForService(tests.BookstoreV1Service).
+				Return([]identity.ServiceIdentity{tests.BookstoreServiceIdentity}, tc.listServiceIdentities).AnyTimes()
+
+			mc := &MeshCatalog{
+				kubeController:   mockKubeController,
+				meshSpec:         mockMeshSpec,
+				policyController: mockPolicyController,
+				serviceProviders: []service.Provider{mockServiceProvider},
+			}
+
+			assert.Equal(tc.expectedReady, mc.Readiness())
+			assert.True(mc.Liveness())
+			assert.Equal("mesh-catalog", mc.GetID())
+		})
+	}
+}
+
+func TestReadinessRemainsReady(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mc := &MeshCatalog{
+		kubeController: mockKubeController,
+	}
+
+	// Once ready, the caches are no longer checked
+	mockKubeController.EXPECT().HasSynced().Return(true).Times(1)
+	assert.True(mc.Readiness())
+	assert.True(mc.Readiness())
+}
@@ -2,6 +2,7 @@ package catalog
 
 import (
 	"reflect"
+	"sort"
 	"strings"
 
 	mapset "github.com/deckarep/golang-set"
@@ -168,6 +169,40 @@ func (mc *MeshCatalog) GetPortToProtocolMappingForService(svc service.MeshServic
 	return portToProtocolMap, nil
 }
 
+// ListServicePorts returns the ports exposed by the service, along with their target ports and application protocols,
+// sorted by port. The function ensures a port has the same target port and protocol across the service providers, and
+// returns an error otherwise.
+func (mc *MeshCatalog) ListServicePorts(svc service.MeshService) ([]service.ServicePort, error) {
+	portsByNumber := make(map[uint32]service.ServicePort)
+
+	for _, provider := range mc.serviceProviders {
+		ports, err := provider.ListServicePorts(svc)
+		if err != nil {
+			return nil, err
+		}
+		for _, port := range ports {
+			if existing, ok := portsByNumber[port.Port]; ok && existing != port {
+				return nil, errors.Errorf("Unexpected port %+v for service %s from provider %s, expected %+v", port, svc, provider.GetID(), existing)
+			}
+			portsByNumber[port.Port] = port
+		}
+	}
+
+	if len(portsByNumber) == 0 {
+		return nil, errors.Errorf("Error fetching ports for service %s", svc)
+	}
+
+	ports := make([]service.ServicePort, 0, len(portsByNumber))
+	for _, port := range portsByNumber {
+		ports = append(ports, port)
+	}
+	sort.Slice(ports, func(i, j int) bool {
+		return ports[i].Port < ports[j].Port
+	})
+
+	return ports, nil
+}
+
 // listMeshServices returns all services in the mesh
 func (mc *MeshCatalog) listMeshServices() []service.MeshService {
 	var services []service.MeshService
@@ -0,0 +1,168 @@
+package catalog
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+)
+
+const (
+	// DefaultNumShards is the default number of namespace shards the dispatcher is partitioned into.
+	// A single shard preserves the behavior of a single dispatch loop for the whole mesh.
+	DefaultNumShards = 1
+
+	// globalShardLabel is the shard label of the dispatch loop handling events that are not namespaced
+	globalShardLabel = "global"
+)
+
+// namespaceShards assigns namespaces to a fixed number of shards, keeping the number of namespaces
+// assigned to each shard balanced as namespaces are added and removed
+type namespaceShards struct {
+	sync.RWMutex
+
+	// assignments maps a namespace to the index of the shard it is assigned to
+	assignments map[string]int
+
+	// namespaces is the set of namespaces assigned to each shard
+	namespaces []map[string]struct{}
+}
+
+// newNamespaceShards returns a namespaceShards with the given number of shards
+func newNamespaceShards(numShards int) *namespaceShards {
+	if numShards < 1 {
+		numShards = 1
+	}
+
+	s := &namespaceShards{
+		assignments: make(map[string]int),
+		namespaces:  make([]map[string]struct{}, numShards),
+	}
+	for i := range s.namespaces {
+		s.namespaces[i] = make(map[string]struct{})
+		s.updateMetrics(i)
+	}
+	return s
+}
+
+// getShard returns the index of the shard the given namespace is assigned to, assigning the namespace
+// to the least loaded shard if it isn't assigned yet
+func (s *namespaceShards) getShard(namespace string) int {
+	s.RLock()
+	shard, ok := s.assignments[namespace]
+	s.RUnlock()
+	if ok {
+		return shard
+	}
+
+	return s.addNamespace(namespace)
+}
+
+// addNamespace assigns the given namespace to the least loaded shard and returns its index.
+// If the namespace is already assigned, the index of its shard is returned.
+func (s *namespaceShards) addNamespace(namespace string) int {
+	s.Lock()
+	defer s.Unlock()
+
+	if shard, ok := s.assignments[namespace]; ok {
+		return shard
+	}
+
+	shard := s.leastLoadedShard()
+	s.assign(namespace, shard)
+	log.Debug().Msgf("Assigned namespace %s to dispatcher shard %d", namespace, shard)
+
+	return shard
+}
+
+// removeNamespace unassigns the given namespace and rebalances the remaining namespaces across shards
+func (s *namespaceShards) removeNamespace(namespace string) {
+	s.Lock()
+	defer s.Unlock()
+
+	shard, ok := s.assignments[namespace]
+	if !ok {
+		return
+	}
+
+	delete(s.assignments, namespace)
+	delete(s.namespaces[shard], namespace)
+	s.updateMetrics(shard)
+	log.Debug().Msgf("Unassigned namespace %s from dispatcher shard %d", namespace, shard)
+
+	s.rebalance()
+}
+
+// rebalance moves namespaces from the most loaded shard to the least loaded shard until the number of
+// namespaces assigned to any two shards differs by at most one. Must be called with the lock held.
+func (s *namespaceShards) rebalance() {
+	for {
+		from, to := s.mostLoadedShard(), s.leastLoadedShard()
+		if len(s.namespaces[from])-len(s.namespaces[to]) <= 1 {
+			return
+		}
+
+		// Move the namespace that sorts first so that rebalancing is deterministic
+		candidates := make([]string, 0, len(s.namespaces[from]))
+		for namespace := range s.namespaces[from] {
+			candidates = append(candidates, namespace)
+		}
+		sort.Strings(candidates)
+		namespace := candidates[0]
+
+		delete(s.namespaces[from], namespace)
+		s.updateMetrics(from)
+		s.assign(namespace, to)
+
+		metricsstore.DefaultMetricsStore.CatalogShardRebalanceCount.Inc()
+		log.Info().Msgf("Rebalanced namespace %s from dispatcher shard %d to shard %d", namespace, from, to)
+	}
+}
+
+// assign assigns the given namespace to the given shard. Must be called with the lock held.
+func (s *namespaceShards) assign(namespace string, shard int) {
+	s.assignments[namespace] = shard
+	s.namespaces[shard][namespace] = struct{}{}
+	s.updateMetrics(shard)
+}
+
+// leastLoadedShard returns the index of the shard with the fewest namespaces, preferring lower indices
+func (s *namespaceShards) leastLoadedShard() int {
+	least := 0
+	for i := range s.namespaces {
+		if len(s.namespaces[i]) < len(s.namespaces[least]) {
+			least = i
+		}
+	}
+	return least
+}
+
+// mostLoadedShard returns the index of the shard with the most namespaces, preferring lower indices
+func (s *namespaceShards) mostLoadedShard() int {
+	most := 0
+	for i := range s.namespaces {
+		if len(s.namespaces[i]) > len(s.namespaces[most]) {
+			most = i
+		}
+	}
+	return most
+}
+
+// listNamespaces returns the sorted namespaces assigned to the given shard
+func (s *namespaceShards) listNamespaces(shard int) []string {
+	s.RLock()
+	defer s.RUnlock()
+
+	namespaces := make([]string, 0, len(s.namespaces[shard]))
+	for namespace := range s.namespaces[shard] {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// updateMetrics records the number of namespaces assigned to the given shard
+func (s *namespaceShards) updateMetrics(shard int) {
+	metricsstore.DefaultMetricsStore.CatalogShardNamespaceCount.WithLabelValues(strconv.Itoa(shard)).Set(float64(len(s.namespaces[shard])))
+}
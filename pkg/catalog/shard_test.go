@@ -0,0 +1,215 @@
+package catalog
+
+import (
+	"testing"
+
+	access "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	a "github.com/openservicemesh/osm/pkg/announcements"
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+)
+
+func TestNamespaceShards(t *testing.T) {
+	assert := tassert.New(t)
+
+	s := newNamespaceShards(3)
+
+	// Namespaces are assigned to the least loaded shard
+	assert.Equal(0, s.addNamespace("ns-1"))
+	assert.Equal(1, s.addNamespace("ns-2"))
+	assert.Equal(2, s.addNamespace("ns-3"))
+	assert.Equal(0, s.getShard("ns-4"))
+	assert.Equal(1, s.getShard("ns-5"))
+
+	// Assignments are stable
+	assert.Equal(0, s.addNamespace("ns-1"))
+	assert.Equal(1, s.getShard("ns-5"))
+
+	assert.Equal([]string{"ns-1", "ns-4"}, s.listNamespaces(0))
+	assert.Equal([]string{"ns-2", "ns-5"}, s.listNamespaces(1))
+	assert.Equal([]string{"ns-3"}, s.listNamespaces(2))
+
+	// Removing a namespace that keeps the shards balanced doesn't move namespaces
+	s.removeNamespace("ns-4")
+	assert.Equal([]string{"ns-1"}, s.listNamespaces(0))
+	assert.Equal([]string{"ns-2", "ns-5"}, s.listNamespaces(1))
+	assert.Equal([]string{"ns-3"}, s.listNamespaces(2))
+
+	// Removing namespaces that unbalance the shards moves namespaces from the most loaded shard
+	s.removeNamespace("ns-1")
+	s.removeNamespace("ns-3")
+	assert.Equal([]string{"ns-2"}, s.listNamespaces(0))
+	assert.Equal([]string{"ns-5"}, s.listNamespaces(1))
+	assert.Empty(s.listNamespaces(2))
+	assert.Equal(0, s.getShard("ns-2"))
+
+	// Removing an unknown namespace is a no-op
+	s.removeNamespace("unknown")
+	assert.Equal(1, s.getShard("ns-5"))
+}
+
+func TestGetEventNamespace(t *testing.T) {
+	testCases := []struct {
+		name              string
+		msg               events.PubSubMessage
+		expectedNamespace string
+	}{
+		{
+			name: "namespaced object",
+			msg: events.PubSubMessage{
+				AnnouncementType: a.PodAdded,
+				NewObj:           &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "ns-1"}},
+			},
+			expectedNamespace: "ns-1",
+		},
+		{
+			name: "deleted namespaced object",
+			msg: events.PubSubMessage{
+				AnnouncementType: a.ServiceDeleted,
+				OldObj:           &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "ns-2"}},
+			},
+			expectedNamespace: "ns-2",
+		},
+		{
+			name: "namespace",
+			msg: events.PubSubMessage{
+				AnnouncementType: a.NamespaceAdded,
+				NewObj:           &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-3"}},
+			},
+			expectedNamespace: "ns-3",
+		},
+		{
+			name: "MeshConfig change",
+			msg: events.PubSubMessage{
+				AnnouncementType: a.MeshConfigTracingChanged,
+				NewObj:           &configv1alpha1.MeshConfig{ObjectMeta: metav1.ObjectMeta{Name: "osm-mesh-config", Namespace: "osm-system"}},
+			},
+			expectedNamespace: "",
+		},
+		{
+			name: "broadcast request",
+			msg: events.PubSubMessage{
+				AnnouncementType: a.ScheduleProxyBroadcast,
+			},
+			expectedNamespace: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expectedNamespace, getEventNamespace(tc.msg))
+		})
+	}
+}
+
+func TestGetEventNamespaces(t *testing.T) {
+	testCases := []struct {
+		name               string
+		msg                events.PubSubMessage
+		expectedNamespaces []string
+	}{
+		{
+			name: "pod event",
+			msg: events.PubSubMessage{
+				AnnouncementType: a.PodAdded,
+				NewObj:           &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "ns-1"}},
+			},
+			expectedNamespaces: []string{"ns-1"},
+		},
+		{
+			name: "traffic target event includes the namespaces of its sources before and after the change",
+			msg: events.PubSubMessage{
+				AnnouncementType: a.TrafficTargetUpdated,
+				OldObj: &access.TrafficTarget{
+					ObjectMeta: metav1.ObjectMeta{Name: "tt", Namespace: "ns-1"},
+					Spec:       access.TrafficTargetSpec{Sources: []access.IdentityBindingSubject{{Name: "sa", Namespace: "ns-2"}}},
+				},
+				NewObj: &access.TrafficTarget{
+					ObjectMeta: metav1.ObjectMeta{Name: "tt", Namespace: "ns-1"},
+					Spec:       access.TrafficTargetSpec{Sources: []access.IdentityBindingSubject{{Name: "sa", Namespace: "ns-3"}}},
+				},
+			},
+			expectedNamespaces: []string{"ns-1", "ns-2", "ns-3"},
+		},
+		{
+			name: "egress event includes the namespaces of its sources",
+			msg: events.PubSubMessage{
+				AnnouncementType: a.EgressAdded,
+				NewObj: &policyV1alpha1.Egress{
+					ObjectMeta: metav1.ObjectMeta{Name: "egress", Namespace: "ns-1"},
+					Spec:       policyV1alpha1.EgressSpec{Sources: []policyV1alpha1.EgressSourceSpec{{Name: "sa", Namespace: "ns-2"}}},
+				},
+			},
+			expectedNamespaces: []string{"ns-1", "ns-2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expectedNamespaces, getEventNamespaces(tc.msg))
+		})
+	}
+}
+
+func TestDispatchLoopBroadcastScope(t *testing.T) {
+	assert := tassert.New(t)
+
+	broadcasts := events.Subscribe(a.ProxyBroadcast)
+	defer events.Unsub(broadcasts)
+
+	namespaces := map[string]struct{}{"ns-1": {}}
+
+	// Broadcasts of the global dispatch loop are not scoped
+	newDispatchLoop(globalShardLabel, nil).broadcast(namespaces, nil)
+	msg := <-broadcasts
+	assert.Nil(msg.(events.PubSubMessage).NewObj)
+
+	// Broadcasts of namespace shards are scoped to the given namespaces
+	newScopedDispatchLoop("0", nil).broadcast(namespaces, nil)
+	msg = <-broadcasts
+	assert.Equal(&ProxyBroadcastScope{Namespaces: namespaces}, msg.(events.PubSubMessage).NewObj)
+
+	// Broadcasts are scoped to the given xDS types, listed in a stable order
+	typeURIs := map[envoy.TypeURI]struct{}{envoy.TypeRDS: {}, envoy.TypeLDS: {}}
+	newDispatchLoop(globalShardLabel, nil).broadcast(namespaces, typeURIs)
+	msg = <-broadcasts
+	assert.Equal(&ProxyBroadcastScope{TypeURIs: []envoy.TypeURI{envoy.TypeLDS, envoy.TypeRDS}}, msg.(events.PubSubMessage).NewObj)
+}
+
+func TestGetDispatchLoop(t *testing.T) {
+	assert := tassert.New(t)
+
+	podEvent := events.PubSubMessage{
+		AnnouncementType: a.PodAdded,
+		NewObj:           &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "ns-1"}},
+	}
+	broadcastEvent := events.PubSubMessage{AnnouncementType: a.ScheduleProxyBroadcast}
+
+	// All events are dispatched by the global dispatch loop when the dispatcher is not sharded
+	mc := &MeshCatalog{globalDispatchLoop: newDispatchLoop(globalShardLabel, nil)}
+	mc.namespaceShards, mc.shardDispatchLoops = newShardDispatchLoops(DefaultNumShards, nil)
+	assert.Nil(mc.namespaceShards)
+	assert.Equal(mc.globalDispatchLoop, mc.getDispatchLoop(podEvent))
+	assert.Equal(mc.globalDispatchLoop, mc.getDispatchLoop(broadcastEvent))
+
+	// Namespaced events are dispatched by the dispatch loop of their namespace's shard when sharded
+	mc = &MeshCatalog{globalDispatchLoop: newDispatchLoop(globalShardLabel, nil)}
+	mc.namespaceShards, mc.shardDispatchLoops = newShardDispatchLoops(2, nil)
+	assert.Len(mc.shardDispatchLoops, 2)
+	mc.namespaceShards.addNamespace("ns-0")
+
+	loop := mc.getDispatchLoop(podEvent)
+	assert.Equal("1", loop.shard)
+	assert.True(loop.scoped)
+	assert.False(mc.globalDispatchLoop.scoped)
+	assert.Equal(loop, mc.getDispatchLoop(podEvent))
+	assert.Equal(mc.globalDispatchLoop, mc.getDispatchLoop(broadcastEvent))
+}
@@ -43,6 +43,7 @@ func (mc *MeshCatalog) ListInboundTrafficTargetsWithRoutes(upstream identity.Ser
 		return nil, nil
 	}
 
+	gatewaySource := mc.getMulticlusterGatewaySource(upstream)
 	for _, t := range mc.meshSpec.ListTrafficTargets() { // loop through all traffic targets
 		if !isValidTrafficTarget(t) {
 			continue
@@ -52,6 +53,7 @@ func (mc *MeshCatalog) ListInboundTrafficTargetsWithRoutes(upstream identity.Ser
 		if destinationSvcIdentity != upstream {
 			continue
 		}
+		t = withMulticlusterGatewaySource(t, gatewaySource)
 
 		destinationIdentity := trafficTargetIdentityToServiceIdentity(t.Spec.Destination)
 
@@ -67,7 +69,7 @@ func (mc *MeshCatalog) ListInboundTrafficTargetsWithRoutes(upstream identity.Ser
 			srcIdentity := trafficTargetIdentityToServiceIdentity(source)
 			sourceIdentities = append(sourceIdentities, srcIdentity)
 		}
-		trafficTarget.Sources = sourceIdentities
+		trafficTarget.Sources = mc.withFederatedIdentities(sourceIdentities)
 
 		// TCP routes for this traffic target
 		if tcpRouteMatches, err := mc.getTCPRouteMatchesFromTrafficTarget(*t); err != nil {
@@ -126,7 +128,7 @@ func (mc *MeshCatalog) getAllowedDirectionalServiceAccounts(svcIdentity identity
 					continue
 				}
 
-				if source.Name != svcAccount.Name || source.Namespace != svcAccount.Namespace {
+				if !trafficTargetIdentityToSvcAccount(source).Matches(svcAccount) {
 					// This TrafficTarget source does not match the given service account, ignore it
 					continue
 				}
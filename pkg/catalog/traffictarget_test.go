@@ -13,7 +13,9 @@ import (
 	"github.com/openservicemesh/osm/pkg/configurator"
 
 	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/logger"
 	"github.com/openservicemesh/osm/pkg/smi"
+	"github.com/openservicemesh/osm/pkg/tests"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
@@ -365,6 +367,74 @@ func TestListOutboundServiceIdentities(t *testing.T) {
 			false, // will log an error but function will ignore policy with error
 		},
 		// Test case 3 end ------------------------------------
+
+		// Test case 4 begin ------------------------------------
+		// The source matches all the service accounts of its namespace
+		{
+			[]*smiAccess.TrafficTarget{
+				{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: "access.smi-spec.io/v1alpha3",
+						Kind:       "TrafficTarget",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-1",
+						Namespace: "ns-2",
+					},
+					Spec: smiAccess.TrafficTargetSpec{
+						Destination: smiAccess.IdentityBindingSubject{
+							Kind:      "ServiceAccount",
+							Name:      "sa-2",
+							Namespace: "ns-2",
+						},
+						Sources: []smiAccess.IdentityBindingSubject{{
+							Kind:      "ServiceAccount",
+							Name:      identity.WildcardServiceAccountName,
+							Namespace: "ns-1",
+						}},
+					},
+				},
+				{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: "access.smi-spec.io/v1alpha3",
+						Kind:       "TrafficTarget",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-2",
+						Namespace: "ns-3",
+					},
+					Spec: smiAccess.TrafficTargetSpec{
+						Destination: smiAccess.IdentityBindingSubject{
+							Kind:      "ServiceAccount",
+							Name:      "sa-3",
+							Namespace: "ns-3",
+						},
+						Sources: []smiAccess.IdentityBindingSubject{{
+							Kind:      "ServiceAccount",
+							Name:      identity.WildcardServiceAccountName,
+							Namespace: "ns-3",
+						}},
+					},
+				},
+			},
+
+			// given service account to test
+			identity.K8sServiceAccount{
+				Name:      "sa-1",
+				Namespace: "ns-1",
+			}.ToServiceIdentity(),
+
+			// allowed outbound service accounts: the destination of the wildcard source of the namespace
+			[]identity.ServiceIdentity{
+				identity.K8sServiceAccount{
+					Name:      "sa-2",
+					Namespace: "ns-2",
+				}.ToServiceIdentity(),
+			},
+
+			false, // no errors expected
+		},
+		// Test case 4 end ------------------------------------
 	}
 
 	for i, tc := range testCases {
@@ -867,6 +937,7 @@ func TestListInboundTrafficTargetsWithRoutes(t *testing.T) {
 			}
 
 			mockCfg.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
+			mockCfg.EXPECT().GetFederatedTrustDomains().Return(nil).AnyTimes()
 
 			// Mock TrafficTargets returned by MeshSpec, should return all TrafficTargets relevant for this test
 			mockMeshSpec.EXPECT().ListTrafficTargets().Return(tc.trafficTargets).AnyTimes()
@@ -887,6 +958,111 @@ func TestListInboundTrafficTargetsWithRoutes(t *testing.T) {
 	}
 }
 
+func TestListInboundTrafficTargetsWithRoutesForTopology(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		namespaces           int
+		servicesPerNamespace int
+		sourcesPerTarget     int
+		expectedSources      int
+	}{
+		{
+			name:                 "single source per traffic target",
+			namespaces:           2,
+			servicesPerNamespace: 2,
+			sourcesPerTarget:     1,
+			expectedSources:      1,
+		},
+		{
+			name:                 "sources across namespaces",
+			namespaces:           10,
+			servicesPerNamespace: 20,
+			sourcesPerTarget:     50,
+			expectedSources:      50,
+		},
+		{
+			name:                 "sources capped to the other service accounts of the topology",
+			namespaces:           2,
+			servicesPerNamespace: 3,
+			sourcesPerTarget:     10,
+			expectedSources:      5,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			topology := tests.NewTopologyBuilder().
+				WithNamespaces(tc.namespaces).
+				WithServicesPerNamespace(tc.servicesPerNamespace).
+				WithTrafficTargets(tc.sourcesPerTarget).
+				Build()
+
+			mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
+			mockCfg := configurator.NewMockConfigurator(mockCtrl)
+			meshCatalog := MeshCatalog{
+				meshSpec:     mockMeshSpec,
+				configurator: mockCfg,
+			}
+
+			mockCfg.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
+			mockCfg.EXPECT().GetFederatedTrustDomains().Return(nil).AnyTimes()
+			mockMeshSpec.EXPECT().ListTrafficTargets().Return(topology.TrafficTargets).AnyTimes()
+
+			serviceIdentities := topology.ServiceIdentities()
+			assert.Len(serviceIdentities, tc.namespaces*tc.servicesPerNamespace)
+			for _, upstream := range serviceIdentities {
+				actual, err := meshCatalog.ListInboundTrafficTargetsWithRoutes(upstream)
+				assert.Nil(err)
+				assert.Len(actual, 1)
+				assert.Len(actual[0].Sources, tc.expectedSources)
+				assert.ElementsMatch(topology.InboundTrafficTargetsWithRoutes(upstream), actual)
+			}
+		})
+	}
+}
+
+func BenchmarkListInboundTrafficTargetsWithRoutes(b *testing.B) {
+	// Keep the logging of the catalog out of the measurements
+	if err := logger.SetLogLevel("error"); err != nil {
+		b.Fatal(err)
+	}
+
+	for _, services := range tests.BenchmarkTopologyServices {
+		b.Run(fmt.Sprintf("%d services", services), func(b *testing.B) {
+			topology := tests.NewTopologyBuilder().
+				WithNamespaces(tests.BenchmarkTopologyNamespaces).
+				WithServicesPerNamespace(services / tests.BenchmarkTopologyNamespaces).
+				WithTrafficTargets(10).
+				Build()
+
+			mockCtrl := gomock.NewController(b)
+			mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
+			mockCfg := configurator.NewMockConfigurator(mockCtrl)
+			mockCfg.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
+			mockCfg.EXPECT().GetFederatedTrustDomains().Return(nil).AnyTimes()
+			mockMeshSpec.EXPECT().ListTrafficTargets().Return(topology.TrafficTargets).AnyTimes()
+
+			meshCatalog := MeshCatalog{
+				meshSpec:     mockMeshSpec,
+				configurator: mockCfg,
+			}
+			upstream := identity.ServiceIdentity("svc-0.ns-0.cluster.local")
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := meshCatalog.ListInboundTrafficTargetsWithRoutes(upstream); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
 func TestIsValidTrafficTarget(t *testing.T) {
 	assert := tassert.New(t)
 
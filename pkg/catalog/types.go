@@ -8,6 +8,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/endpoint"
+	"github.com/openservicemesh/osm/pkg/envoy"
 	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/ingress"
 	"github.com/openservicemesh/osm/pkg/k8s"
@@ -24,12 +25,13 @@ var (
 
 // MeshCatalog is the struct for the service catalog
 type MeshCatalog struct {
-	endpointsProviders []endpoint.Provider
-	serviceProviders   []service.Provider
-	meshSpec           smi.MeshSpec
-	certManager        certificate.Manager
-	ingressMonitor     ingress.Monitor
-	configurator       configurator.Configurator
+	endpointsProviders         []endpoint.Provider
+	externalEndpointsProviders []ExternalEndpointsProvider
+	serviceProviders           []service.Provider
+	meshSpec                   smi.MeshSpec
+	certManager                certificate.Manager
+	ingressMonitor             ingress.Monitor
+	configurator               configurator.Configurator
 
 	// This is the kubernetes client that operates async caches to avoid issuing synchronous
 	// calls through kubeClient and instead relies on background cache synchronization and local
@@ -39,6 +41,33 @@ type MeshCatalog struct {
 	// policyController implements the functionality related to the resources part of the policy.openrservicemesh.io
 	// API group, such as egress.
 	policyController policy.Controller
+
+	// globalDispatchLoop dispatches the events that are not namespaced, and all events when the dispatcher is not sharded
+	globalDispatchLoop *dispatchLoop
+
+	// namespaceShards assigns namespaces to the shard dispatch loops, nil when the dispatcher is not sharded
+	namespaceShards *namespaceShards
+
+	// shardDispatchLoops dispatches the events of the namespaces assigned to each shard
+	shardDispatchLoops []*dispatchLoop
+
+	// multiclusterGatewayIdentity is the identity of the multicluster gateway, empty if multicluster mode is disabled
+	multiclusterGatewayIdentity identity.ServiceIdentity
+
+	// ready is set to 1 once the catalog is ready, see Readiness
+	ready int32
+}
+
+// ProxyBroadcastScope is the type used to represent the scope of a proxy broadcast published by a dispatcher loop.
+// Only the proxies in, or whose config references services or identities in, the given namespaces are affected,
+// and only the given xDS types of their config are updated.
+// A proxy broadcast without a scope affects all proxies.
+type ProxyBroadcastScope struct {
+	// Namespaces is the set of namespaces whose config changes triggered the broadcast, nil for all namespaces
+	Namespaces map[string]struct{}
+
+	// TypeURIs are the xDS types affected by the config changes that triggered the broadcast, nil for all types
+	TypeURIs []envoy.TypeURI
 }
 
 // MeshCataloger is the mechanism by which the Service Mesh controller discovers all Envoy proxies connected to the catalog.
@@ -49,12 +78,19 @@ type MeshCataloger interface {
 	// ListOutboundTrafficPolicies returns all outbound traffic policies related to the given service identity
 	ListOutboundTrafficPolicies(identity.ServiceIdentity) []*trafficpolicy.OutboundTrafficPolicy
 
+	// ListOutboundTrafficPoliciesForHosts returns the outbound traffic policies related to the given service identity
+	// for the given hosts, which are the names of the policies
+	ListOutboundTrafficPoliciesForHosts(identity.ServiceIdentity, []string) []*trafficpolicy.OutboundTrafficPolicy
+
 	// ListOutboundServicesForIdentity list the services the given service identity is allowed to initiate outbound connections to
 	ListOutboundServicesForIdentity(identity.ServiceIdentity) []service.MeshService
 
 	// ListOutboundServicesForMulticlusterGateway  lists the upstream services for the multicluster gateway
 	ListOutboundServicesForMulticlusterGateway() []service.MeshService
 
+	// ListExportedServicesForMulticlusterGateway lists the services the multicluster gateway exposes to remote trust domains
+	ListExportedServicesForMulticlusterGateway() []service.MeshService
+
 	// ListInboundServiceIdentities lists the downstream service identities that are allowed to connect to the given service identity
 	ListInboundServiceIdentities(identity.ServiceIdentity) ([]identity.ServiceIdentity, error)
 
@@ -76,6 +112,12 @@ type MeshCataloger interface {
 	// GetIngressTrafficPolicy returns the ingress traffic policy for the given mesh service
 	GetIngressTrafficPolicy(service.MeshService) (*trafficpolicy.IngressTrafficPolicy, error)
 
+	// GetUpstreamPeerValidation returns how the certificates presented by the given upstream service are validated
+	GetUpstreamPeerValidation(service.MeshService) *trafficpolicy.UpstreamPeerValidation
+
+	// GetUpstreamRetryBudget returns the budget of the concurrent retries to the given upstream service
+	GetUpstreamRetryBudget(service.MeshService) *trafficpolicy.RetryBudget
+
 	// GetTargetPortToProtocolMappingForService returns a mapping of the service's ports to their corresponding application protocol.
 	// The ports returned are the actual ports on which the application exposes the service derived from the service's endpoints,
 	// ie. 'spec.ports[].targetPort' instead of 'spec.ports[].port' for a Kubernetes service.
@@ -86,6 +128,10 @@ type MeshCataloger interface {
 	// actually exposed by the application binary, ie. 'spec.ports[].port' instead of 'spec.ports[].targetPort' for a Kubernetes service.
 	GetPortToProtocolMappingForService(service.MeshService) (map[uint32]string, error)
 
+	// ListServicePorts returns the ports exposed by the service, along with their target ports and application protocols,
+	// sorted by port.
+	ListServicePorts(service.MeshService) ([]service.ServicePort, error)
+
 	// ListInboundTrafficTargetsWithRoutes returns a list traffic target objects composed of its routes for the given destination service identity
 	ListInboundTrafficTargetsWithRoutes(identity.ServiceIdentity) ([]trafficpolicy.TrafficTargetWithRoutes, error)
 
@@ -103,6 +149,24 @@ type MeshCataloger interface {
 
 	// GetServiceHostnames returns the hostnames for this service, based on the locality of the source.
 	GetServiceHostnames(service.MeshService, service.Locality) ([]string, error)
+
+	// ListExternalServices returns the services registered outside the mesh by the external endpoints providers
+	ListExternalServices() []endpoint.ExternalService
+
+	// ListExternalServicesForIdentity returns the services registered outside the mesh that the given identity is
+	// allowed to reach by Egress policies, along with their allowed endpoints
+	ListExternalServicesForIdentity(identity.ServiceIdentity) []endpoint.ExternalService
+}
+
+// ExternalEndpointsProvider is an interface to be implemented by components discovering services registered outside
+// the mesh, such as in a Consul catalog. Mesh workloads are allowed to reach the services listed by external endpoints
+// providers according to Egress policies, see MeshCataloger.ListExternalServicesForIdentity.
+type ExternalEndpointsProvider interface {
+	// ListExternalServices retrieves the services registered with the provider along with their endpoints
+	ListExternalServices() []endpoint.ExternalService
+
+	// GetID returns the unique identifier of the ExternalEndpointsProvider.
+	GetID() string
 }
 
 type trafficDirection string
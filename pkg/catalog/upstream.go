@@ -0,0 +1,113 @@
+package catalog
+
+import (
+	"strings"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// GetUpstreamPeerValidation returns how the certificates presented by the given upstream service are validated.
+// The trust domain aliases of the MeshConfig apply to all upstream services, unless overridden by the
+// UpstreamTrafficSetting policy of the upstream service.
+func (mc *MeshCatalog) GetUpstreamPeerValidation(upstream service.MeshService) *trafficpolicy.UpstreamPeerValidation {
+	peerValidation := &trafficpolicy.UpstreamPeerValidation{
+		TrustDomainAliases: mc.configurator.GetTrustDomainAliases(),
+	}
+
+	upstreamTrafficSetting := mc.policyController.GetUpstreamTrafficSetting(upstream)
+	if upstreamTrafficSetting == nil || upstreamTrafficSetting.Spec.PeerValidation == nil {
+		return peerValidation
+	}
+
+	if upstreamTrafficSetting.Spec.PeerValidation.TrustDomainAliases != nil {
+		peerValidation.TrustDomainAliases = upstreamTrafficSetting.Spec.PeerValidation.TrustDomainAliases
+	}
+	peerValidation.SubjectAltNames = upstreamTrafficSetting.Spec.PeerValidation.SubjectAltNames
+
+	return peerValidation
+}
+
+// GetUpstreamRetryBudget returns the budget of the concurrent retries to the given upstream service. Every upstream
+// service is given the default retry budget, so that misconfigured retry policies cannot amplify an outage of the
+// upstream service, unless overridden by the UpstreamTrafficSetting policy of the upstream service.
+func (mc *MeshCatalog) GetUpstreamRetryBudget(upstream service.MeshService) *trafficpolicy.RetryBudget {
+	retryBudget := &trafficpolicy.RetryBudget{
+		BudgetPercent:       constants.DefaultRetryBudgetPercent,
+		MinRetryConcurrency: constants.DefaultMinRetryConcurrency,
+	}
+
+	upstreamTrafficSetting := mc.policyController.GetUpstreamTrafficSetting(upstream)
+	if upstreamTrafficSetting == nil || upstreamTrafficSetting.Spec.RetryBudget == nil {
+		return retryBudget
+	}
+
+	if upstreamTrafficSetting.Spec.RetryBudget.BudgetPercent != nil {
+		retryBudget.BudgetPercent = *upstreamTrafficSetting.Spec.RetryBudget.BudgetPercent
+	}
+	if upstreamTrafficSetting.Spec.RetryBudget.MinRetryConcurrency != nil {
+		retryBudget.MinRetryConcurrency = *upstreamTrafficSetting.Spec.RetryBudget.MinRetryConcurrency
+	}
+
+	return retryBudget
+}
+
+// getUpstreamRetryPolicy returns how the requests to the given upstream service are retried, as defined by the
+// UpstreamTrafficSetting policy of the upstream service, or nil if the requests are not retried. The requests are
+// only hedged when a per-try timeout is set, since Envoy hedges the tries exceeding it.
+func (mc *MeshCatalog) getUpstreamRetryPolicy(upstream service.MeshService) *trafficpolicy.RetryPolicy {
+	upstreamTrafficSetting := mc.policyController.GetUpstreamTrafficSetting(upstream)
+	if upstreamTrafficSetting == nil || upstreamTrafficSetting.Spec.RetryPolicy == nil {
+		return nil
+	}
+	spec := upstreamTrafficSetting.Spec.RetryPolicy
+
+	retryPolicy := &trafficpolicy.RetryPolicy{
+		RetryOn:    constants.DefaultRetryOn,
+		NumRetries: constants.DefaultNumRetries,
+	}
+	if spec.RetryOn != "" {
+		retryPolicy.RetryOn = spec.RetryOn
+	}
+	if spec.NumRetries != nil {
+		retryPolicy.NumRetries = *spec.NumRetries
+	}
+	if spec.PerTryTimeout != nil && spec.PerTryTimeout.Duration > 0 {
+		retryPolicy.PerTryTimeout = spec.PerTryTimeout.Duration
+		retryPolicy.HedgeOnPerTryTimeout = spec.Idempotent
+	}
+
+	return retryPolicy
+}
+
+// applyUpstreamRetryPolicies sets the retry policy of the upstream service of each of the given outbound policies.
+// The upstream service of a policy is the service whose FQDN the policy is named after, optionally qualified with a
+// port. The policies of the other hosts, such as those of host headers, are not retried.
+func (mc *MeshCatalog) applyUpstreamRetryPolicies(policies []*trafficpolicy.OutboundTrafficPolicy) []*trafficpolicy.OutboundTrafficPolicy {
+	for _, policy := range policies {
+		if upstream, ok := getOutboundPolicyUpstream(policy.Name); ok {
+			policy.RetryPolicy = mc.getUpstreamRetryPolicy(upstream)
+		}
+	}
+	return policies
+}
+
+// getOutboundPolicyUpstream returns the upstream service of an outbound policy of the given name, the FQDN of the
+// service optionally qualified with a port, and whether the name is of this form
+func getOutboundPolicyUpstream(policyName string) (service.MeshService, bool) {
+	host := policyName
+	if i := strings.LastIndex(host, ":"); i > 0 {
+		host = host[:i]
+	}
+
+	chunks := strings.SplitN(host, ".", 3)
+	if len(chunks) != 3 || chunks[0] == "" || chunks[1] == "" {
+		return service.MeshService{}, false
+	}
+	upstream := service.MeshService{Name: chunks[0], Namespace: chunks[1]}
+	if upstream.FQDN() != host {
+		return service.MeshService{}, false
+	}
+	return upstream, true
+}
@@ -0,0 +1,306 @@
+package catalog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/policy"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestGetUpstreamPeerValidation(t *testing.T) {
+	upstreamTrafficSetting := func(peerValidation *policyV1alpha1.PeerValidationSpec) *policyV1alpha1.UpstreamTrafficSetting {
+		return &policyV1alpha1.UpstreamTrafficSetting{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bookstore",
+				Namespace: tests.BookstoreV1Service.Namespace,
+			},
+			Spec: policyV1alpha1.UpstreamTrafficSettingSpec{
+				Host:           tests.BookstoreV1Service.FQDN(),
+				PeerValidation: peerValidation,
+			},
+		}
+	}
+
+	testCases := []struct {
+		name                   string
+		trustDomainAliases     []string
+		upstreamTrafficSetting *policyV1alpha1.UpstreamTrafficSetting
+		expectedPeerValidation *trafficpolicy.UpstreamPeerValidation
+	}{
+		{
+			name:                   "no trust domain aliases and no UpstreamTrafficSetting",
+			trustDomainAliases:     nil,
+			upstreamTrafficSetting: nil,
+			expectedPeerValidation: &trafficpolicy.UpstreamPeerValidation{},
+		},
+		{
+			name:                   "trust domain aliases of the MeshConfig",
+			trustDomainAliases:     []string{"old.example.com"},
+			upstreamTrafficSetting: nil,
+			expectedPeerValidation: &trafficpolicy.UpstreamPeerValidation{
+				TrustDomainAliases: []string{"old.example.com"},
+			},
+		},
+		{
+			name:                   "UpstreamTrafficSetting without peer validation",
+			trustDomainAliases:     []string{"old.example.com"},
+			upstreamTrafficSetting: upstreamTrafficSetting(nil),
+			expectedPeerValidation: &trafficpolicy.UpstreamPeerValidation{
+				TrustDomainAliases: []string{"old.example.com"},
+			},
+		},
+		{
+			name:               "UpstreamTrafficSetting with additional SANs",
+			trustDomainAliases: []string{"old.example.com"},
+			upstreamTrafficSetting: upstreamTrafficSetting(&policyV1alpha1.PeerValidationSpec{
+				SubjectAltNames: []string{"bookstore.example.com"},
+			}),
+			expectedPeerValidation: &trafficpolicy.UpstreamPeerValidation{
+				TrustDomainAliases: []string{"old.example.com"},
+				SubjectAltNames:    []string{"bookstore.example.com"},
+			},
+		},
+		{
+			name:               "UpstreamTrafficSetting overriding the trust domain aliases",
+			trustDomainAliases: []string{"old.example.com"},
+			upstreamTrafficSetting: upstreamTrafficSetting(&policyV1alpha1.PeerValidationSpec{
+				TrustDomainAliases: []string{},
+			}),
+			expectedPeerValidation: &trafficpolicy.UpstreamPeerValidation{
+				TrustDomainAliases: []string{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockPolicyController := policy.NewMockController(mockCtrl)
+
+			mockConfigurator.EXPECT().GetTrustDomainAliases().Return(tc.trustDomainAliases)
+			mockPolicyController.EXPECT().GetUpstreamTrafficSetting(tests.BookstoreV1Service).Return(tc.upstreamTrafficSetting)
+
+			mc := &MeshCatalog{
+				configurator:     mockConfigurator,
+				policyController: mockPolicyController,
+			}
+
+			assert.Equal(tc.expectedPeerValidation, mc.GetUpstreamPeerValidation(tests.BookstoreV1Service))
+		})
+	}
+}
+
+func TestGetUpstreamRetryBudget(t *testing.T) {
+	uint32Ptr := func(v uint32) *uint32 { return &v }
+	upstreamTrafficSetting := func(retryBudget *policyV1alpha1.RetryBudgetSpec) *policyV1alpha1.UpstreamTrafficSetting {
+		return &policyV1alpha1.UpstreamTrafficSetting{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bookstore",
+				Namespace: tests.BookstoreV1Service.Namespace,
+			},
+			Spec: policyV1alpha1.UpstreamTrafficSettingSpec{
+				Host:        tests.BookstoreV1Service.FQDN(),
+				RetryBudget: retryBudget,
+			},
+		}
+	}
+	defaultRetryBudget := &trafficpolicy.RetryBudget{
+		BudgetPercent:       constants.DefaultRetryBudgetPercent,
+		MinRetryConcurrency: constants.DefaultMinRetryConcurrency,
+	}
+
+	testCases := []struct {
+		name                   string
+		upstreamTrafficSetting *policyV1alpha1.UpstreamTrafficSetting
+		expectedRetryBudget    *trafficpolicy.RetryBudget
+	}{
+		{
+			name:                   "default retry budget without UpstreamTrafficSetting",
+			upstreamTrafficSetting: nil,
+			expectedRetryBudget:    defaultRetryBudget,
+		},
+		{
+			name:                   "default retry budget with UpstreamTrafficSetting without retry budget",
+			upstreamTrafficSetting: upstreamTrafficSetting(nil),
+			expectedRetryBudget:    defaultRetryBudget,
+		},
+		{
+			name: "UpstreamTrafficSetting overriding the budget percent",
+			upstreamTrafficSetting: upstreamTrafficSetting(&policyV1alpha1.RetryBudgetSpec{
+				BudgetPercent: uint32Ptr(50),
+			}),
+			expectedRetryBudget: &trafficpolicy.RetryBudget{
+				BudgetPercent:       50,
+				MinRetryConcurrency: constants.DefaultMinRetryConcurrency,
+			},
+		},
+		{
+			name: "UpstreamTrafficSetting overriding the budget percent and min retry concurrency",
+			upstreamTrafficSetting: upstreamTrafficSetting(&policyV1alpha1.RetryBudgetSpec{
+				BudgetPercent:       uint32Ptr(0),
+				MinRetryConcurrency: uint32Ptr(1),
+			}),
+			expectedRetryBudget: &trafficpolicy.RetryBudget{
+				BudgetPercent:       0,
+				MinRetryConcurrency: 1,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockPolicyController := policy.NewMockController(mockCtrl)
+			mockPolicyController.EXPECT().GetUpstreamTrafficSetting(tests.BookstoreV1Service).Return(tc.upstreamTrafficSetting)
+
+			mc := &MeshCatalog{
+				policyController: mockPolicyController,
+			}
+
+			assert.Equal(tc.expectedRetryBudget, mc.GetUpstreamRetryBudget(tests.BookstoreV1Service))
+		})
+	}
+}
+
+func TestGetUpstreamRetryPolicy(t *testing.T) {
+	uint32Ptr := func(v uint32) *uint32 { return &v }
+	upstreamTrafficSetting := func(retryPolicy *policyV1alpha1.RetryPolicySpec) *policyV1alpha1.UpstreamTrafficSetting {
+		return &policyV1alpha1.UpstreamTrafficSetting{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bookstore",
+				Namespace: tests.BookstoreV1Service.Namespace,
+			},
+			Spec: policyV1alpha1.UpstreamTrafficSettingSpec{
+				Host:        tests.BookstoreV1Service.FQDN(),
+				RetryPolicy: retryPolicy,
+			},
+		}
+	}
+
+	testCases := []struct {
+		name                   string
+		upstreamTrafficSetting *policyV1alpha1.UpstreamTrafficSetting
+		expectedRetryPolicy    *trafficpolicy.RetryPolicy
+	}{
+		{
+			name:                   "no retry policy without UpstreamTrafficSetting",
+			upstreamTrafficSetting: nil,
+			expectedRetryPolicy:    nil,
+		},
+		{
+			name:                   "no retry policy with UpstreamTrafficSetting without retry policy",
+			upstreamTrafficSetting: upstreamTrafficSetting(nil),
+			expectedRetryPolicy:    nil,
+		},
+		{
+			name:                   "default retry policy with an empty retry policy",
+			upstreamTrafficSetting: upstreamTrafficSetting(&policyV1alpha1.RetryPolicySpec{}),
+			expectedRetryPolicy: &trafficpolicy.RetryPolicy{
+				RetryOn:    constants.DefaultRetryOn,
+				NumRetries: constants.DefaultNumRetries,
+			},
+		},
+		{
+			name: "idempotent requests without per-try timeout are not hedged",
+			upstreamTrafficSetting: upstreamTrafficSetting(&policyV1alpha1.RetryPolicySpec{
+				RetryOn:    "5xx",
+				NumRetries: uint32Ptr(0),
+				Idempotent: true,
+			}),
+			expectedRetryPolicy: &trafficpolicy.RetryPolicy{
+				RetryOn:    "5xx",
+				NumRetries: 0,
+			},
+		},
+		{
+			name: "idempotent requests with per-try timeout are hedged",
+			upstreamTrafficSetting: upstreamTrafficSetting(&policyV1alpha1.RetryPolicySpec{
+				NumRetries:    uint32Ptr(2),
+				PerTryTimeout: &metav1.Duration{Duration: 250 * time.Millisecond},
+				Idempotent:    true,
+			}),
+			expectedRetryPolicy: &trafficpolicy.RetryPolicy{
+				RetryOn:              constants.DefaultRetryOn,
+				NumRetries:           2,
+				PerTryTimeout:        250 * time.Millisecond,
+				HedgeOnPerTryTimeout: true,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockPolicyController := policy.NewMockController(mockCtrl)
+			mockPolicyController.EXPECT().GetUpstreamTrafficSetting(tests.BookstoreV1Service).Return(tc.upstreamTrafficSetting)
+
+			mc := &MeshCatalog{
+				policyController: mockPolicyController,
+			}
+
+			assert.Equal(tc.expectedRetryPolicy, mc.getUpstreamRetryPolicy(tests.BookstoreV1Service))
+		})
+	}
+}
+
+func TestGetOutboundPolicyUpstream(t *testing.T) {
+	testCases := []struct {
+		policyName       string
+		expectedUpstream service.MeshService
+		expectedOk       bool
+	}{
+		{
+			policyName:       "bookstore-v1.default.svc.cluster.local",
+			expectedUpstream: service.MeshService{Name: "bookstore-v1", Namespace: "default"},
+			expectedOk:       true,
+		},
+		{
+			policyName:       "bookstore-v1.default.svc.cluster.local:8888",
+			expectedUpstream: service.MeshService{Name: "bookstore-v1", Namespace: "default"},
+			expectedOk:       true,
+		},
+		{
+			policyName: "bookstore-v1.default",
+			expectedOk: false,
+		},
+		{
+			policyName: "foo.bookstore-v1.default.svc.cluster.local",
+			expectedOk: false,
+		},
+		{
+			policyName: "*",
+			expectedOk: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.policyName, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			upstream, ok := getOutboundPolicyUpstream(tc.policyName)
+			assert.Equal(tc.expectedOk, ok)
+			assert.Equal(tc.expectedUpstream, upstream)
+		})
+	}
+}
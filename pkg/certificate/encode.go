@@ -2,6 +2,7 @@ package certificate
 
 import (
 	"bytes"
+	"crypto"
 	"crypto/rsa"
 	"crypto/x509"
 	pemEnc "encoding/pem"
@@ -26,7 +27,7 @@ func EncodeCertDERtoPEM(derBytes []byte) (pem.Certificate, error) {
 }
 
 // EncodeKeyDERtoPEM converts a DER encoded private key into a PEM encoded key
-func EncodeKeyDERtoPEM(priv *rsa.PrivateKey) (pem.PrivateKey, error) {
+func EncodeKeyDERtoPEM(priv crypto.PrivateKey) (pem.PrivateKey, error) {
 	keyOut := &bytes.Buffer{}
 	privBytes, err := x509.MarshalPKCS8PrivateKey(priv)
 	if err != nil {
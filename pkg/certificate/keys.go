@@ -0,0 +1,32 @@
+package certificate
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+)
+
+const (
+	// KeyAlgorithmRSA is the algorithm of RSA keys, whose size is the configured certificate key bit size
+	KeyAlgorithmRSA = "rsa"
+
+	// KeyAlgorithmECDSA is the algorithm of ECDSA keys on the P-256 curve
+	KeyAlgorithmECDSA = "ecdsa"
+
+	// SubjectAltNameFormatDNS is the format of the SANs of certificates only carrying the DNS SAN of their common name
+	SubjectAltNameFormatDNS = "dns"
+
+	// SubjectAltNameFormatDNSSPIFFE is the format of the SANs of certificates carrying the SPIFFE ID of their service
+	// identity as a URI SAN, in addition to the DNS SAN of their common name
+	SubjectAltNameFormatDNSSPIFFE = "dns_spiffe"
+)
+
+// GeneratePrivateKey generates a private key with the given algorithm, RSA keys being of the given size in bits
+func GeneratePrivateKey(algorithm string, rsaKeyBitSize int) (crypto.Signer, error) {
+	if algorithm == KeyAlgorithmECDSA {
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	}
+	return rsa.GenerateKey(rand.Reader, rsaKeyBitSize)
+}
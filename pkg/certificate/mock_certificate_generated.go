@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/openservicemesh/osm/pkg/certificate (interfaces: Certificater,Manager)
+// Source: github.com/openservicemesh/osm/pkg/certificate (interfaces: Certificater,Manager,TrustBundleStore)
 
 // Package certificate is a generated GoMock package.
 package certificate
@@ -76,6 +76,20 @@ func (mr *MockCertificaterMockRecorder) GetExpiration() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiration", reflect.TypeOf((*MockCertificater)(nil).GetExpiration))
 }
 
+// GetIssueTime mocks base method
+func (m *MockCertificater) GetIssueTime() time.Time {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIssueTime")
+	ret0, _ := ret[0].(time.Time)
+	return ret0
+}
+
+// GetIssueTime indicates an expected call of GetIssueTime
+func (mr *MockCertificaterMockRecorder) GetIssueTime() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIssueTime", reflect.TypeOf((*MockCertificater)(nil).GetIssueTime))
+}
+
 // GetIssuingCA mocks base method
 func (m *MockCertificater) GetIssuingCA() []byte {
 	m.ctrl.T.Helper()
@@ -227,3 +241,69 @@ func (mr *MockManagerMockRecorder) RotateCertificate(arg0 interface{}) *gomock.C
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateCertificate", reflect.TypeOf((*MockManager)(nil).RotateCertificate), arg0)
 }
+
+// MockTrustBundleStore is a mock of TrustBundleStore interface
+type MockTrustBundleStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTrustBundleStoreMockRecorder
+}
+
+// MockTrustBundleStoreMockRecorder is the mock recorder for MockTrustBundleStore
+type MockTrustBundleStoreMockRecorder struct {
+	mock *MockTrustBundleStore
+}
+
+// NewMockTrustBundleStore creates a new mock instance
+func NewMockTrustBundleStore(ctrl *gomock.Controller) *MockTrustBundleStore {
+	mock := &MockTrustBundleStore{ctrl: ctrl}
+	mock.recorder = &MockTrustBundleStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTrustBundleStore) EXPECT() *MockTrustBundleStoreMockRecorder {
+	return m.recorder
+}
+
+// GetTrustBundle mocks base method
+func (m *MockTrustBundleStore) GetTrustBundle(arg0 string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrustBundle", arg0)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrustBundle indicates an expected call of GetTrustBundle
+func (mr *MockTrustBundleStoreMockRecorder) GetTrustBundle(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrustBundle", reflect.TypeOf((*MockTrustBundleStore)(nil).GetTrustBundle), arg0)
+}
+
+// ListTrustDomains mocks base method
+func (m *MockTrustBundleStore) ListTrustDomains() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrustDomains")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// ListTrustDomains indicates an expected call of ListTrustDomains
+func (mr *MockTrustBundleStoreMockRecorder) ListTrustDomains() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrustDomains", reflect.TypeOf((*MockTrustBundleStore)(nil).ListTrustDomains))
+}
+
+// SetTrustBundle mocks base method
+func (m *MockTrustBundleStore) SetTrustBundle(arg0 string, arg1 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTrustBundle", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTrustBundle indicates an expected call of SetTrustBundle
+func (mr *MockTrustBundleStoreMockRecorder) SetTrustBundle(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrustBundle", reflect.TypeOf((*MockTrustBundleStore)(nil).SetTrustBundle), arg0, arg1)
+}
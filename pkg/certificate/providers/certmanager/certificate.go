@@ -31,6 +31,11 @@ func (c Certificate) GetExpiration() time.Time {
 	return c.expiration
 }
 
+// GetIssueTime implements certificate.Certificater and returns the time the given certificate was issued.
+func (c Certificate) GetIssueTime() time.Time {
+	return c.issueTime
+}
+
 // GetSerialNumber returns the serial number of the given certificate.
 func (c Certificate) GetSerialNumber() certificate.SerialNumber {
 	return c.serialNumber
@@ -3,10 +3,11 @@ package certmanager
 import (
 	"context"
 	"crypto/rand"
-	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"fmt"
+	"net"
+	"net/url"
 	"time"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
@@ -21,6 +22,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/errcode"
 	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
 )
 
 // IssueCertificate implements certificate.Manager and returns a newly issued certificate.
@@ -35,9 +37,12 @@ func (cm *CertManager) IssueCertificate(cn certificate.CommonName, validityPerio
 	// Cache miss/needs rotation so issue new certificate.
 	cert, err := cm.issue(cn, validityPeriod)
 	if err != nil {
+		metricsstore.DefaultMetricsStore.CertProviderErrorCount.WithLabelValues(providerKind, "issue").Inc()
 		return nil, err
 	}
 
+	metricsstore.DefaultMetricsStore.CertProviderIssuedCount.WithLabelValues(providerKind).Inc()
+
 	log.Debug().Msgf("It took %+v to issue certificate with SerialNumber=%s", time.Since(start), cert.GetSerialNumber())
 
 	return cert, nil
@@ -46,6 +51,7 @@ func (cm *CertManager) IssueCertificate(cn certificate.CommonName, validityPerio
 // ReleaseCertificate is called when a cert will no longer be needed and should be removed from the system.
 func (cm *CertManager) ReleaseCertificate(cn certificate.CommonName) {
 	cm.deleteFromCache(cn)
+	metricsstore.DefaultMetricsStore.CertReleasedCount.WithLabelValues(providerKind).Inc()
 }
 
 // GetCertificate returns a certificate given its Common Name (CN)
@@ -91,9 +97,12 @@ func (cm *CertManager) RotateCertificate(cn certificate.CommonName) (certificate
 	}
 	newCert, err := cm.issue(cn, cm.serviceCertValidityDuration)
 	if err != nil {
+		metricsstore.DefaultMetricsStore.CertProviderErrorCount.WithLabelValues(providerKind, "rotate").Inc()
 		return newCert, err
 	}
 
+	metricsstore.DefaultMetricsStore.CertRotatedCount.WithLabelValues(providerKind).Inc()
+
 	cm.cacheLock.Lock()
 	oldCert := cm.cache[cn]
 	cm.cache[cn] = newCert
@@ -141,6 +150,7 @@ func (cm *CertManager) certificaterFromCertificateRequest(cr *cmapi.CertificateR
 	return Certificate{
 		commonName:   certificate.CommonName(cert.Subject.CommonName),
 		serialNumber: certificate.SerialNumber(cert.SerialNumber.String()),
+		issueTime:    cert.NotBefore,
 		expiration:   cert.NotAfter,
 		certChain:    cr.Status.Certificate,
 		privateKey:   privateKey,
@@ -161,7 +171,7 @@ func (cm *CertManager) issue(cn certificate.CommonName, validityPeriod time.Dura
 	if cm.keySize == 0 {
 		cm.keySize = cm.cfg.GetCertKeyBitSize()
 	}
-	certPrivKey, err := rsa.GenerateKey(rand.Reader, cm.keySize)
+	certPrivKey, err := certificate.GeneratePrivateKey(cm.keyAlgorithm, cm.keySize)
 	if err != nil {
 		// TODO: Need to push metric?
 		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGeneratingPrivateKey)).
@@ -184,7 +194,18 @@ func (cm *CertManager) issue(cn certificate.CommonName, validityPeriod time.Dura
 		Subject: pkix.Name{
 			CommonName: cn.String(),
 		},
-		DNSNames: []string{cn.String()},
+	}
+	if cm.keyAlgorithm == certificate.KeyAlgorithmECDSA {
+		csr.SignatureAlgorithm = x509.ECDSAWithSHA256
+		csr.PublicKeyAlgorithm = x509.ECDSA
+	}
+	if ip := cn.IP(); ip != nil {
+		csr.IPAddresses = []net.IP{ip}
+	} else {
+		csr.DNSNames = []string{cn.String()}
+	}
+	if spiffeID := cn.SPIFFEID(cm.spiffeTrustDomain); spiffeID != nil {
+		csr.URIs = []*url.URL{spiffeID}
 	}
 
 	csrDER, err := x509.CreateCertificateRequest(rand.Reader, csr, certPrivKey)
@@ -258,6 +279,8 @@ func NewCertManager(
 	cfg configurator.Configurator,
 	serviceCertValidityDuration time.Duration,
 	keySize int,
+	keyAlgorithm string,
+	spiffeTrustDomain string,
 ) (*CertManager, error) {
 	informerFactory := cminformers.NewSharedInformerFactory(client, time.Second*30)
 	crLister := informerFactory.Certmanager().V1().CertificateRequests().Lister().CertificateRequests(namespace)
@@ -275,10 +298,12 @@ func NewCertManager(
 		cfg:                         cfg,
 		serviceCertValidityDuration: serviceCertValidityDuration,
 		keySize:                     keySize,
+		keyAlgorithm:                keyAlgorithm,
+		spiffeTrustDomain:           spiffeTrustDomain,
 	}
 
 	// Instantiating a new certificate rotation mechanism will start a goroutine for certificate rotation.
-	rotor.New(cm).Start(checkCertificateExpirationInterval)
+	rotor.New(cm, providerKind).Start(checkCertificateExpirationInterval)
 
 	return cm, nil
 }
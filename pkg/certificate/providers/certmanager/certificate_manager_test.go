@@ -119,6 +119,8 @@ var _ = Describe("Test cert-manager Certificate Manager", func() {
 			mockConfigurator,
 			mockConfigurator.GetServiceCertValidityPeriod(),
 			mockConfigurator.GetCertKeyBitSize(),
+			certificate.KeyAlgorithmRSA,
+			"",
 		)
 		It("should get an issued certificate from the cache", func() {
 			mockConfigurator.EXPECT().GetCertKeyBitSize().Return(keySize).AnyTimes()
@@ -223,6 +225,8 @@ func TestCertificaterFromCertificateRequest(t *testing.T) {
 		mockConfigurator,
 		mockConfigurator.GetServiceCertValidityPeriod(),
 		mockConfigurator.GetCertKeyBitSize(),
+		certificate.KeyAlgorithmRSA,
+		"",
 	)
 	assert.Nil(err)
 
@@ -25,6 +25,7 @@ func NewRootCertificateFromPEM(pemCert pem.Certificate) (certificate.Certificate
 		commonName:   certificate.CommonName(cert.Subject.CommonName),
 		serialNumber: certificate.SerialNumber(cert.SerialNumber.String()),
 		certChain:    pemCert,
+		issueTime:    cert.NotBefore,
 		expiration:   cert.NotAfter,
 		issuingCA:    pem.RootCertificate(pemCert),
 	}, nil
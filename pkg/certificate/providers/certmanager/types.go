@@ -19,6 +19,9 @@ const (
 	// checkCertificateExpirationInterval is the interval to check whether a
 	// certificate is close to expiration and needs renewal.
 	checkCertificateExpirationInterval = 5 * time.Second
+
+	// providerKind is the kind of the certificate provider labeling the certificate metrics
+	providerKind = "cert-manager"
 )
 
 var (
@@ -53,6 +56,13 @@ type CertManager struct {
 	// Issuing certificate properties.
 	serviceCertValidityDuration time.Duration
 	keySize                     int
+
+	// keyAlgorithm is the algorithm of the keys of the issued certificates, RSA if unset
+	keyAlgorithm string
+
+	// spiffeTrustDomain is the trust domain of the SPIFFE IDs carried by the certificates of service identities,
+	// the certificates carry no SPIFFE ID if unset
+	spiffeTrustDomain string
 }
 
 // Certificate implements certificate.Certificater
@@ -63,6 +73,9 @@ type Certificate struct {
 	// The serial number of the certificate
 	serialNumber certificate.SerialNumber
 
+	// When the cert was issued
+	issueTime time.Time
+
 	// When the cert expires
 	expiration time.Time
 
@@ -236,6 +236,8 @@ func (c *Config) getTresorOSMCertificateManager() (certificate.Manager, debugger
 		c.cfg,
 		c.cfg.GetServiceCertValidityPeriod(),
 		c.cfg.GetCertKeyBitSize(),
+		c.cfg.GetCertKeyAlgorithm(),
+		c.cfg.GetSPIFFETrustDomain(),
 	)
 	if err != nil {
 		return nil, nil, errors.Errorf("Failed to instantiate Tresor as a Certificate Manager")
@@ -310,6 +312,7 @@ func (c *Config) getHashiVaultOSMCertificateManager(options VaultOptions) (certi
 		options.VaultRole,
 		c.cfg,
 		c.cfg.GetServiceCertValidityPeriod(),
+		c.cfg.GetSPIFFETrustDomain(),
 	)
 	if err != nil {
 		return nil, nil, errors.Errorf("Error instantiating Hashicorp Vault as a Certificate Manager: %+v", err)
@@ -352,6 +355,8 @@ func (c *Config) getCertManagerOSMCertificateManager(options CertManagerOptions)
 		c.cfg,
 		c.cfg.GetServiceCertValidityPeriod(),
 		c.cfg.GetCertKeyBitSize(),
+		c.cfg.GetCertKeyAlgorithm(),
+		c.cfg.GetSPIFFETrustDomain(),
 	)
 	if err != nil {
 		return nil, nil, errors.Errorf("Error instantiating Jetstack cert-manager as a Certificate Manager: %+v", err)
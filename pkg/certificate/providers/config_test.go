@@ -27,6 +27,8 @@ func TestGetCertificateManager(t *testing.T) {
 
 	mockConfigurator.EXPECT().IsDebugServerEnabled().Return(false).AnyTimes()
 	mockConfigurator.EXPECT().GetCertKeyBitSize().Return(2048).AnyTimes()
+	mockConfigurator.EXPECT().GetCertKeyAlgorithm().Return(certificate.KeyAlgorithmRSA).AnyTimes()
+	mockConfigurator.EXPECT().GetSPIFFETrustDomain().Return("").AnyTimes()
 	mockConfigurator.EXPECT().GetServiceCertValidityPeriod().Return(1 * time.Hour).AnyTimes()
 
 	testCases := []struct {
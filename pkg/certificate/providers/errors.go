@@ -7,4 +7,7 @@ import (
 var (
 	errInvalidCertSecret = errors.New("Invalid secret for certificate")
 	errSecretNotFound    = errors.Errorf("Secret not found")
+
+	// ErrTrustBundleNotFound is the error returned when the CA bundle of a trust domain is not known
+	ErrTrustBundleNotFound = errors.New("Trust bundle not found")
 )
@@ -75,6 +75,7 @@ func NewCA(cn certificate.CommonName, validityPeriod time.Duration, rootCertCoun
 		serialNumber: certificate.SerialNumber(serialNumber.String()),
 		certChain:    pemCert,
 		privateKey:   pemKey,
+		issueTime:    template.NotBefore,
 		expiration:   template.NotAfter,
 	}
 
@@ -97,6 +98,7 @@ func NewCertificateFromPEM(pemCert pem.Certificate, pemKey pem.PrivateKey, expir
 		serialNumber: certificate.SerialNumber(x509Cert.SerialNumber.String()),
 		certChain:    pemCert,
 		privateKey:   pemKey,
+		issueTime:    x509Cert.NotBefore,
 		expiration:   expiration,
 	}
 
@@ -10,6 +10,9 @@ import (
 
 const (
 	checkCertificateExpirationInterval = 5 * time.Second
+
+	// providerKind is the kind of the certificate provider labeling the certificate metrics
+	providerKind = "tresor"
 )
 
 // GetCommonName implements certificate.Certificater and returns the CN of the cert.
@@ -37,6 +40,11 @@ func (c Certificate) GetExpiration() time.Time {
 	return c.expiration
 }
 
+// GetIssueTime implements certificate.Certificater and returns the time the given certificate was issued.
+func (c Certificate) GetIssueTime() time.Time {
+	return c.issueTime
+}
+
 // GetSerialNumber returns the serial number of the given certificate.
 func (c Certificate) GetSerialNumber() certificate.SerialNumber {
 	return c.serialNumber
@@ -48,7 +56,9 @@ func NewCertManager(
 	certificatesOrganization string,
 	cfg configurator.Configurator,
 	serviceCertValidityDuration time.Duration,
-	keySize int) (*CertManager, error) {
+	keySize int,
+	keyAlgorithm string,
+	spiffeTrustDomain string) (*CertManager, error) {
 	if ca == nil {
 		return nil, errNoIssuingCA
 	}
@@ -60,10 +70,12 @@ func NewCertManager(
 		cfg:                         cfg,
 		serviceCertValidityDuration: serviceCertValidityDuration,
 		keySize:                     keySize,
+		keyAlgorithm:                keyAlgorithm,
+		spiffeTrustDomain:           spiffeTrustDomain,
 	}
 
 	// Instantiating a new certificate rotation mechanism will start a goroutine for certificate rotation.
-	rotor.New(&certManager).Start(checkCertificateExpirationInterval)
+	rotor.New(&certManager, providerKind).Start(checkCertificateExpirationInterval)
 
 	return &certManager, nil
 }
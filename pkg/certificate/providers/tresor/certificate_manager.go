@@ -2,9 +2,10 @@ package tresor
 
 import (
 	"crypto/rand"
-	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"net"
+	"net/url"
 	"time"
 
 	"github.com/pkg/errors"
@@ -14,6 +15,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/certificate/rotor"
 	"github.com/openservicemesh/osm/pkg/errcode"
 	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
 )
 
 func (cm *CertManager) issue(cn certificate.CommonName, validityPeriod time.Duration) (certificate.Certificater, error) {
@@ -30,7 +32,7 @@ func (cm *CertManager) issue(cn certificate.CommonName, validityPeriod time.Dura
 	if cm.keySize == 0 {
 		cm.keySize = cm.cfg.GetCertKeyBitSize()
 	}
-	certPrivKey, err := rsa.GenerateKey(rand.Reader, cm.keySize)
+	certPrivKey, err := certificate.GeneratePrivateKey(cm.keyAlgorithm, cm.keySize)
 	if err != nil {
 		// TODO: Need to push metric?
 		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGeneratingPrivateKey)).
@@ -47,8 +49,6 @@ func (cm *CertManager) issue(cn certificate.CommonName, validityPeriod time.Dura
 	template := x509.Certificate{
 		SerialNumber: serialNumber,
 
-		DNSNames: []string{string(cn)},
-
 		Subject: pkix.Name{
 			CommonName:   string(cn),
 			Organization: []string{cm.certificatesOrganization},
@@ -60,6 +60,14 @@ func (cm *CertManager) issue(cn certificate.CommonName, validityPeriod time.Dura
 		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
 		BasicConstraintsValid: true,
 	}
+	if ip := cn.IP(); ip != nil {
+		template.IPAddresses = []net.IP{ip}
+	} else {
+		template.DNSNames = []string{string(cn)}
+	}
+	if spiffeID := cn.SPIFFEID(cm.spiffeTrustDomain); spiffeID != nil {
+		template.URIs = []*url.URL{spiffeID}
+	}
 
 	x509Root, err := certificate.DecodePEMCertificate(cm.ca.GetCertificateChain())
 	if err != nil {
@@ -75,7 +83,7 @@ func (cm *CertManager) issue(cn certificate.CommonName, validityPeriod time.Dura
 			Msg("Error decoding Root Certificate's Private Key PEM ")
 	}
 
-	derBytes, err := x509.CreateCertificate(rand.Reader, &template, x509Root, &certPrivKey.PublicKey, rsaKeyRoot)
+	derBytes, err := x509.CreateCertificate(rand.Reader, &template, x509Root, certPrivKey.Public(), rsaKeyRoot)
 	if err != nil {
 		// TODO: Need to push metric?
 		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrCreatingCert)).
@@ -105,6 +113,7 @@ func (cm *CertManager) issue(cn certificate.CommonName, validityPeriod time.Dura
 		certChain:    certPEM,
 		privateKey:   privKeyPEM,
 		issuingCA:    cm.ca.GetCertificateChain(),
+		issueTime:    template.NotBefore,
 		expiration:   template.NotAfter,
 	}
 
@@ -140,9 +149,12 @@ func (cm *CertManager) IssueCertificate(cn certificate.CommonName, validityPerio
 
 	cert, err := cm.issue(cn, validityPeriod)
 	if err != nil {
+		metricsstore.DefaultMetricsStore.CertProviderErrorCount.WithLabelValues(providerKind, "issue").Inc()
 		return cert, err
 	}
 
+	metricsstore.DefaultMetricsStore.CertProviderIssuedCount.WithLabelValues(providerKind).Inc()
+
 	cm.cache.Store(cn, cert)
 
 	log.Trace().Msgf("It took %+v to issue certificate with SerialNumber=%s", time.Since(start), cert.GetSerialNumber())
@@ -154,6 +166,7 @@ func (cm *CertManager) IssueCertificate(cn certificate.CommonName, validityPerio
 func (cm *CertManager) ReleaseCertificate(cn certificate.CommonName) {
 	log.Trace().Msgf("Releasing certificate %s", cn)
 	cm.deleteFromCache(cn)
+	metricsstore.DefaultMetricsStore.CertReleasedCount.WithLabelValues(providerKind).Inc()
 }
 
 // GetCertificate returns a certificate given its Common Name (CN)
@@ -182,9 +195,12 @@ func (cm *CertManager) RotateCertificate(cn certificate.CommonName) (certificate
 	}
 	newCert, err := cm.issue(cn, cm.serviceCertValidityDuration)
 	if err != nil {
+		metricsstore.DefaultMetricsStore.CertProviderErrorCount.WithLabelValues(providerKind, "rotate").Inc()
 		return nil, err
 	}
 
+	metricsstore.DefaultMetricsStore.CertRotatedCount.WithLabelValues(providerKind).Inc()
+
 	cm.cache.Store(cn, newCert)
 
 	events.Publish(events.PubSubMessage{
@@ -1,6 +1,8 @@
 package tresor
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"testing"
 	"time"
 
@@ -50,6 +52,8 @@ var _ = Describe("Test Certificate Manager", func() {
 			mockConfigurator,
 			mockConfigurator.GetServiceCertValidityPeriod(),
 			mockConfigurator.GetCertKeyBitSize(),
+			certificate.KeyAlgorithmRSA,
+			"",
 		)
 		It("should issue a certificate", func() {
 			Expect(newCertError).ToNot(HaveOccurred())
@@ -97,6 +101,8 @@ var _ = Describe("Test Certificate Manager", func() {
 			mockConfigurator,
 			mockConfigurator.GetServiceCertValidityPeriod(),
 			mockConfigurator.GetCertKeyBitSize(),
+			certificate.KeyAlgorithmRSA,
+			"",
 		)
 		It("should get an issued certificate from the cache", func() {
 			Expect(newCertError).ToNot(HaveOccurred())
@@ -342,3 +348,68 @@ func TestGetRootCertificate(t *testing.T) {
 	assert.Nil(err)
 	assert.Equal(rootCert, got)
 }
+
+func TestIssueCertificateSubjectAltNames(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().GetCertKeyBitSize().Return(2048).AnyTimes()
+
+	rootCert, err := NewCA("Test CA", time.Hour, "US", "CA", "Open Service Mesh Tresor")
+	assert.Nil(err)
+	m, err := NewCertManager(rootCert, "org", mockConfigurator, time.Hour, 2048, certificate.KeyAlgorithmRSA, "")
+	assert.Nil(err)
+
+	// A host name is issued a DNS SAN
+	cert, err := m.IssueCertificate("osm.example.com", time.Hour)
+	assert.Nil(err)
+	x509Cert, err := certificate.DecodePEMCertificate(cert.GetCertificateChain())
+	assert.Nil(err)
+	assert.Equal([]string{"osm.example.com"}, x509Cert.DNSNames)
+	assert.Empty(x509Cert.IPAddresses)
+
+	// An IP address is issued an IP SAN
+	cert, err = m.IssueCertificate("10.0.0.1", time.Hour)
+	assert.Nil(err)
+	x509Cert, err = certificate.DecodePEMCertificate(cert.GetCertificateChain())
+	assert.Nil(err)
+	assert.Empty(x509Cert.DNSNames)
+	assert.Len(x509Cert.IPAddresses, 1)
+	assert.Equal("10.0.0.1", x509Cert.IPAddresses[0].String())
+}
+
+func TestIssueCertificateKeyAlgorithmAndSPIFFEID(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+
+	rootCert, err := NewCA("Test CA", time.Hour, "US", "CA", "Open Service Mesh Tresor")
+	assert.Nil(err)
+	m, err := NewCertManager(rootCert, "org", mockConfigurator, time.Hour, 2048, certificate.KeyAlgorithmECDSA, "example.org")
+	assert.Nil(err)
+
+	// A service identity is issued its SPIFFE ID as a URI SAN in addition to its DNS SAN
+	cert, err := m.IssueCertificate("bookbuyer.bookbuyer-ns.cluster.local", time.Hour)
+	assert.Nil(err)
+	x509Cert, err := certificate.DecodePEMCertificate(cert.GetCertificateChain())
+	assert.Nil(err)
+	assert.Equal(x509.ECDSA, x509Cert.PublicKeyAlgorithm)
+	assert.Equal([]string{"bookbuyer.bookbuyer-ns.cluster.local"}, x509Cert.DNSNames)
+	assert.Len(x509Cert.URIs, 1)
+	assert.Equal("spiffe://example.org/ns/bookbuyer-ns/sa/bookbuyer", x509Cert.URIs[0].String())
+
+	// The private key matches the certificate
+	_, err = tls.X509KeyPair(cert.GetCertificateChain(), cert.GetPrivateKey())
+	assert.Nil(err)
+
+	// Other common names are not issued a URI SAN
+	cert, err = m.IssueCertificate("osm.example.com", time.Hour)
+	assert.Nil(err)
+	x509Cert, err = certificate.DecodePEMCertificate(cert.GetCertificateChain())
+	assert.Nil(err)
+	assert.Empty(x509Cert.URIs)
+}
@@ -40,6 +40,13 @@ type CertManager struct {
 
 	serviceCertValidityDuration time.Duration
 	keySize                     int
+
+	// keyAlgorithm is the algorithm of the keys of the issued certificates, RSA if unset
+	keyAlgorithm string
+
+	// spiffeTrustDomain is the trust domain of the SPIFFE IDs carried by the certificates of service identities,
+	// the certificates carry no SPIFFE ID if unset
+	spiffeTrustDomain string
 }
 
 // Certificate implements certificate.Certificater
@@ -50,6 +57,9 @@ type Certificate struct {
 	// The serial number of the certificate
 	serialNumber certificate.SerialNumber
 
+	// When the cert was issued
+	issueTime time.Time
+
 	// When the cert expires
 	expiration time.Time
 
@@ -0,0 +1,155 @@
+package providers
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/version"
+)
+
+const (
+	// trustBundleSecretPrefix is the prefix of the names of the secrets storing the CA bundles of remote trust domains
+	trustBundleSecretPrefix = "osm-trust-bundle-" // #nosec G101: Potential hardcoded credentials
+
+	// trustDomainLabelKey is the label identifying the trust domain of a trust bundle secret
+	trustDomainLabelKey = "openservicemesh.io/trust-domain"
+)
+
+// trustBundleManager is a certificate.Manager that additionally stores the CA bundles of remote trust domains,
+// persisted in Kubernetes secrets so they survive restarts of the controller.
+type trustBundleManager struct {
+	certificate.Manager
+
+	kubeClient kubernetes.Interface
+	namespace  string
+
+	bundlesLock sync.RWMutex
+	bundles     map[string][]byte
+}
+
+// NewTrustBundleManager returns a certificate.Manager wrapping the given certificate manager that also implements
+// certificate.TrustBundleStore, storing the CA bundles of remote trust domains in secrets in the given namespace.
+func NewTrustBundleManager(certManager certificate.Manager, kubeClient kubernetes.Interface, namespace string) (certificate.Manager, error) {
+	m := &trustBundleManager{
+		Manager:    certManager,
+		kubeClient: kubeClient,
+		namespace:  namespace,
+		bundles:    make(map[string][]byte),
+	}
+
+	secrets, err := kubeClient.CoreV1().Secrets(namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: trustDomainLabelKey,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error listing trust bundle secrets in namespace %s", namespace)
+	}
+	for _, secret := range secrets.Items {
+		trustDomain := secret.Labels[trustDomainLabelKey]
+		if caBundle, ok := secret.Data[constants.KubernetesOpaqueSecretCAKey]; ok && trustDomain != "" {
+			m.bundles[trustDomain] = caBundle
+		}
+	}
+
+	return m, nil
+}
+
+// GetTrustBundle returns the CA bundle of the given trust domain
+func (m *trustBundleManager) GetTrustBundle(trustDomain string) ([]byte, error) {
+	m.bundlesLock.RLock()
+	defer m.bundlesLock.RUnlock()
+
+	caBundle, ok := m.bundles[trustDomain]
+	if !ok {
+		return nil, ErrTrustBundleNotFound
+	}
+	return caBundle, nil
+}
+
+// SetTrustBundle stores the CA bundle of the given trust domain
+func (m *trustBundleManager) SetTrustBundle(trustDomain string, caBundle []byte) error {
+	m.bundlesLock.RLock()
+	existing, ok := m.bundles[trustDomain]
+	m.bundlesLock.RUnlock()
+	if ok && string(existing) == string(caBundle) {
+		return nil
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getTrustBundleSecretName(trustDomain),
+			Namespace: m.namespace,
+			Labels: map[string]string{
+				constants.OSMAppNameLabelKey:    constants.OSMAppNameLabelValue,
+				constants.OSMAppVersionLabelKey: version.Version,
+				trustDomainLabelKey:             trustDomain,
+			},
+		},
+		Data: map[string][]byte{
+			constants.KubernetesOpaqueSecretCAKey: caBundle,
+		},
+	}
+
+	secrets := m.kubeClient.CoreV1().Secrets(m.namespace)
+	existingSecret, err := secrets.Get(context.Background(), secret.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		_, err = secrets.Create(context.Background(), secret, metav1.CreateOptions{})
+	case err == nil:
+		existingSecret.Labels = secret.Labels
+		existingSecret.Data = secret.Data
+		_, err = secrets.Update(context.Background(), existingSecret, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return errors.Wrapf(err, "Error storing trust bundle for trust domain %s", trustDomain)
+	}
+
+	m.bundlesLock.Lock()
+	m.bundles[trustDomain] = caBundle
+	m.bundlesLock.Unlock()
+
+	log.Info().Msgf("Stored trust bundle for trust domain %s", trustDomain)
+	return nil
+}
+
+// ListTrustDomains returns the sorted list of trust domains with a stored CA bundle
+func (m *trustBundleManager) ListTrustDomains() []string {
+	m.bundlesLock.RLock()
+	defer m.bundlesLock.RUnlock()
+
+	trustDomains := make([]string, 0, len(m.bundles))
+	for trustDomain := range m.bundles {
+		trustDomains = append(trustDomains, trustDomain)
+	}
+	sort.Strings(trustDomains)
+	return trustDomains
+}
+
+// GetLocalTrustBundle returns the CA bundle used to validate the certificates issued by the given certificate manager,
+// to be exchanged with the other clusters participating in the mesh.
+func GetLocalTrustBundle(certManager certificate.Manager) ([]byte, error) {
+	rootCert, err := certManager.GetRootCertificate()
+	if err != nil {
+		return nil, err
+	}
+
+	// Fall back to the certificate chain for self-signed root certificates that do not set their issuing CA
+	if caBundle := rootCert.GetIssuingCA(); len(caBundle) > 0 {
+		return caBundle, nil
+	}
+	return rootCert.GetCertificateChain(), nil
+}
+
+// getTrustBundleSecretName returns the name of the secret storing the CA bundle of the given trust domain
+func getTrustBundleSecretName(trustDomain string) string {
+	return trustBundleSecretPrefix + strings.ReplaceAll(trustDomain, ".", "-")
+}
@@ -0,0 +1,66 @@
+package providers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+func TestTrustBundleManager(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	const namespace = "osm-system"
+	kubeClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getTrustBundleSecretName("cluster-b.example.com"),
+			Namespace: namespace,
+			Labels:    map[string]string{trustDomainLabelKey: "cluster-b.example.com"},
+		},
+		Data: map[string][]byte{
+			constants.KubernetesOpaqueSecretCAKey: []byte("ca-b"),
+		},
+	})
+
+	certManager, err := NewTrustBundleManager(certificate.NewMockManager(mockCtrl), kubeClient, namespace)
+	assert.Nil(err)
+
+	store, ok := certManager.(certificate.TrustBundleStore)
+	assert.True(ok)
+
+	// Trust bundles stored in secrets are loaded on creation
+	caBundle, err := store.GetTrustBundle("cluster-b.example.com")
+	assert.Nil(err)
+	assert.Equal([]byte("ca-b"), caBundle)
+
+	_, err = store.GetTrustBundle("cluster-c.example.com")
+	assert.Equal(ErrTrustBundleNotFound, err)
+
+	// Storing a trust bundle persists it in a secret
+	assert.Nil(store.SetTrustBundle("cluster-c.example.com", []byte("ca-c")))
+	caBundle, err = store.GetTrustBundle("cluster-c.example.com")
+	assert.Nil(err)
+	assert.Equal([]byte("ca-c"), caBundle)
+
+	secret, err := kubeClient.CoreV1().Secrets(namespace).Get(context.Background(), getTrustBundleSecretName("cluster-c.example.com"), metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Equal([]byte("ca-c"), secret.Data[constants.KubernetesOpaqueSecretCAKey])
+	assert.Equal("cluster-c.example.com", secret.Labels[trustDomainLabelKey])
+
+	// Storing a new trust bundle for a known trust domain updates its secret
+	assert.Nil(store.SetTrustBundle("cluster-b.example.com", []byte("ca-b-rotated")))
+	secret, err = kubeClient.CoreV1().Secrets(namespace).Get(context.Background(), getTrustBundleSecretName("cluster-b.example.com"), metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Equal([]byte("ca-b-rotated"), secret.Data[constants.KubernetesOpaqueSecretCAKey])
+
+	assert.Equal([]string{"cluster-b.example.com", "cluster-c.example.com"}, store.ListTrustDomains())
+}
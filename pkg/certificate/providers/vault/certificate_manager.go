@@ -15,6 +15,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/errcode"
 	"github.com/openservicemesh/osm/pkg/k8s/events"
 	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
 )
 
 var log = logger.New("vault")
@@ -27,10 +28,15 @@ const (
 	privateKeyField   = "private_key"
 	issuingCAField    = "issuing_ca"
 	commonNameField   = "common_name"
+	ipSANsField       = "ip_sans"
+	uriSANsField      = "uri_sans"
 	ttlField          = "ttl"
 
 	checkCertificateExpirationInterval = 5 * time.Second
 	decade                             = 8765 * time.Hour
+
+	// providerKind is the kind of the certificate provider labeling the certificate metrics
+	providerKind = "vault"
 )
 
 // NewCertManager implements certificate.Manager and wraps a Hashi Vault with methods to allow easy certificate issuance.
@@ -39,11 +45,13 @@ func NewCertManager(
 	token string,
 	role string,
 	cfg configurator.Configurator,
-	serviceCertValidityDuration time.Duration) (*CertManager, error) {
+	serviceCertValidityDuration time.Duration,
+	spiffeTrustDomain string) (*CertManager, error) {
 	c := &CertManager{
 		role:                        vaultRole(role),
 		cfg:                         cfg,
 		serviceCertValidityDuration: serviceCertValidityDuration,
+		spiffeTrustDomain:           spiffeTrustDomain,
 	}
 	config := api.DefaultConfig()
 	config.Address = vaultAddr
@@ -65,13 +73,14 @@ func NewCertManager(
 	c.ca = &Certificate{
 		commonName:   constants.CertificationAuthorityCommonName,
 		serialNumber: serialNumber,
+		issueTime:    time.Now(),
 		expiration:   time.Now().Add(decade),
 		certChain:    issuingCA,
 		issuingCA:    issuingCA,
 	}
 
 	// Instantiating a new certificate rotation mechanism will start a goroutine for certificate rotation.
-	rotor.New(c).Start(checkCertificateExpirationInterval)
+	rotor.New(c, providerKind).Start(checkCertificateExpirationInterval)
 
 	return c, nil
 }
@@ -92,7 +101,7 @@ func (cm *CertManager) getIssuingCA(issue func(certificate.CommonName, time.Dura
 }
 
 func (cm *CertManager) issue(cn certificate.CommonName, validityPeriod time.Duration) (certificate.Certificater, error) {
-	secret, err := cm.client.Logical().Write(getIssueURL(cm.role).String(), getIssuanceData(cn, validityPeriod))
+	secret, err := cm.client.Logical().Write(getIssueURL(cm.role).String(), getIssuanceData(cn, validityPeriod, cm.spiffeTrustDomain))
 	if err != nil {
 		// TODO: Need to push metric?
 		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrIssuingCert)).
@@ -100,7 +109,8 @@ func (cm *CertManager) issue(cn certificate.CommonName, validityPeriod time.Dura
 		return nil, err
 	}
 
-	return newCert(cn, secret, time.Now().Add(validityPeriod)), nil
+	issueTime := time.Now()
+	return newCert(cn, secret, issueTime, issueTime.Add(validityPeriod)), nil
 }
 
 func (cm *CertManager) deleteFromCache(cn certificate.CommonName) {
@@ -130,9 +140,12 @@ func (cm *CertManager) IssueCertificate(cn certificate.CommonName, validityPerio
 
 	cert, err := cm.issue(cn, validityPeriod)
 	if err != nil {
+		metricsstore.DefaultMetricsStore.CertProviderErrorCount.WithLabelValues(providerKind, "issue").Inc()
 		return cert, err
 	}
 
+	metricsstore.DefaultMetricsStore.CertProviderIssuedCount.WithLabelValues(providerKind).Inc()
+
 	cm.cache.Store(cn, cert)
 
 	log.Trace().Msgf("Issued new certificate with SerialNumber=%s took %+v", cert.GetSerialNumber(), time.Since(start))
@@ -144,6 +157,7 @@ func (cm *CertManager) IssueCertificate(cn certificate.CommonName, validityPerio
 func (cm *CertManager) ReleaseCertificate(cn certificate.CommonName) {
 	// TODO(draychev): implement Hashicorp Vault delete-cert API here: https://github.com/openservicemesh/osm/issues/2068
 	cm.deleteFromCache(cn)
+	metricsstore.DefaultMetricsStore.CertReleasedCount.WithLabelValues(providerKind).Inc()
 }
 
 // ListCertificates lists all certificates issued
@@ -187,9 +201,12 @@ func (cm *CertManager) RotateCertificate(cn certificate.CommonName) (certificate
 	}
 	newCert, err := cm.issue(cn, cm.serviceCertValidityDuration)
 	if err != nil {
+		metricsstore.DefaultMetricsStore.CertProviderErrorCount.WithLabelValues(providerKind, "rotate").Inc()
 		return nil, err
 	}
 
+	metricsstore.DefaultMetricsStore.CertRotatedCount.WithLabelValues(providerKind).Inc()
+
 	cm.cache.Store(cn, newCert)
 
 	events.Publish(events.PubSubMessage{
@@ -208,6 +225,9 @@ type Certificate struct {
 	// The commonName of the certificate
 	commonName certificate.CommonName
 
+	// When the cert was issued
+	issueTime time.Time
+
 	// When the cert expires
 	expiration time.Time
 
@@ -247,10 +267,16 @@ func (c Certificate) GetExpiration() time.Time {
 	return c.expiration
 }
 
-func newCert(cn certificate.CommonName, secret *api.Secret, expiration time.Time) *Certificate {
+// GetIssueTime implements certificate.Certificater and returns the time the given certificate was issued.
+func (c Certificate) GetIssueTime() time.Time {
+	return c.issueTime
+}
+
+func newCert(cn certificate.CommonName, secret *api.Secret, issueTime, expiration time.Time) *Certificate {
 	return &Certificate{
 		commonName:   cn,
 		serialNumber: certificate.SerialNumber(secret.Data[serialNumberField].(string)),
+		issueTime:    issueTime,
 		expiration:   expiration,
 		certChain:    pem.Certificate(secret.Data[certificateField].(string)),
 		privateKey:   []byte(secret.Data[privateKeyField].(string)),
@@ -75,6 +75,7 @@ var _ = Describe("Test client helpers", func() {
 				vaultRole,
 				mockConfigurator,
 				mockConfigurator.GetServiceCertValidityPeriod(),
+				"",
 			)
 			Expect(err).To(HaveOccurred())
 			vaultError := err.(*url.Error)
@@ -97,14 +98,16 @@ var _ = Describe("Test client helpers", func() {
 				},
 			}
 
-			expiration := time.Now().Add(1 * time.Hour)
+			issueTime := time.Now()
+			expiration := issueTime.Add(1 * time.Hour)
 
-			actual := newCert(cn, secret, expiration)
+			actual := newCert(cn, secret, issueTime, expiration)
 
 			expected := &Certificate{
 				issuingCA:    pem.RootCertificate("zz"),
 				privateKey:   pem.PrivateKey("yy"),
 				certChain:    pem.Certificate("xx"),
+				issueTime:    issueTime,
 				expiration:   expiration,
 				commonName:   "foo.bar.co.uk",
 				serialNumber: "123",
@@ -19,9 +19,16 @@ func getRoleConfigURL(role vaultRole) vaultPath {
 	return vaultPath(fmt.Sprintf("pki/roles/%s", role))
 }
 
-func getIssuanceData(cn certificate.CommonName, validityPeriod time.Duration) map[string]interface{} {
-	return map[string]interface{}{
+func getIssuanceData(cn certificate.CommonName, validityPeriod time.Duration, spiffeTrustDomain string) map[string]interface{} {
+	data := map[string]interface{}{
 		commonNameField: cn.String(),
 		ttlField:        getDurationInMinutes(validityPeriod),
 	}
+	if ip := cn.IP(); ip != nil {
+		data[ipSANsField] = ip.String()
+	}
+	if spiffeID := cn.SPIFFEID(spiffeTrustDomain); spiffeID != nil {
+		data[uriSANsField] = spiffeID.String()
+	}
+	return data
 }
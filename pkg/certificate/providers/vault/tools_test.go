@@ -42,12 +42,23 @@ var _ = Describe("Test tools", func() {
 	Context("Test cert issuance data for request", func() {
 		It("creates a map w/ correct fields", func() {
 			cn := certificate.CommonName("blah.foo.com")
-			actual := getIssuanceData(cn, 8123*time.Minute)
+			actual := getIssuanceData(cn, 8123*time.Minute, "")
 			expected := map[string]interface{}{
 				"common_name": "blah.foo.com",
 				"ttl":         "135h",
 			}
 			Expect(actual).To(Equal(expected))
 		})
+
+		It("requests the SPIFFE ID of a service identity as a URI SAN", func() {
+			cn := certificate.CommonName("bookbuyer.bookbuyer-ns.cluster.local")
+			actual := getIssuanceData(cn, 8123*time.Minute, "example.org")
+			expected := map[string]interface{}{
+				"common_name": "bookbuyer.bookbuyer-ns.cluster.local",
+				"ttl":         "135h",
+				"uri_sans":    "spiffe://example.org/ns/bookbuyer-ns/sa/bookbuyer",
+			}
+			Expect(actual).To(Equal(expected))
+		})
 	})
 })
@@ -29,6 +29,11 @@ type CertManager struct {
 	cfg configurator.Configurator
 
 	serviceCertValidityDuration time.Duration
+
+	// spiffeTrustDomain is the trust domain of the SPIFFE IDs carried by the certificates of service identities,
+	// the certificates carry no SPIFFE ID if unset. The algorithm of the keys of the certificates is defined by the
+	// Vault role.
+	spiffeTrustDomain string
 }
 
 type vaultRole string
@@ -2,16 +2,23 @@ package rotor
 
 import (
 	"math/rand"
+	"strconv"
 	"time"
 
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/errcode"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
 )
 
 const (
-	// How much earlier (before expiration) should a certificate be renewed
+	// How much earlier (before expiration) should a certificate be renewed at the least
 	renewBeforeCertExpires = 30 * time.Second
 
+	// The fraction of its lifetime before its expiration at which a certificate is renewed, when earlier than
+	// renewBeforeCertExpires. The renewal of short-lived certificates is thereby pipelined: the next certificate is
+	// issued and pushed to the proxies while the current one is still valid for a good part of its lifetime.
+	renewBeforeCertExpiresLifetimeDivisor = 5
+
 	// So that we do not renew all certs at the same time - add noise.
 	// These define the min and max of the seconds of noise to be added
 	// to the early certificate renewal.
@@ -19,10 +26,14 @@ const (
 	maxNoiseSeconds = 5
 )
 
+// expiringCertWindowsHours are the numbers of hours within which the expiring certificates are counted
+var expiringCertWindowsHours = []int{1, 6, 24}
+
 // New creates and starts a new facility for automatic certificate rotation.
-func New(certManager certificate.Manager) *CertRotor {
+func New(certManager certificate.Manager, providerKind string) *CertRotor {
 	return &CertRotor{
-		certManager: certManager,
+		certManager:  certManager,
+		providerKind: providerKind,
 	}
 }
 
@@ -43,8 +54,15 @@ func (r *CertRotor) checkAndRotate() {
 	certs, err := r.certManager.ListCertificates()
 	if err != nil {
 		log.Error().Err(err).Msgf("Error listing all certificates")
+		metricsstore.DefaultMetricsStore.CertProviderErrorCount.WithLabelValues(r.providerKind, "list").Inc()
 	}
 
+	// The certificates remaining after the expiring certificates are rotated
+	var currentCerts []certificate.Certificater
+	defer func() {
+		r.updateExpiringCertMetrics(currentCerts)
+	}()
+
 	for _, cert := range certs {
 		shouldRotate := ShouldRotate(cert)
 
@@ -53,7 +71,7 @@ func (r *CertRotor) checkAndRotate() {
 			cert.GetCommonName(),
 			word,
 			time.Until(cert.GetExpiration()),
-			renewBeforeCertExpires)
+			getRenewBefore(cert))
 
 		if shouldRotate {
 			// Remove the certificate from the cache of the certificate manager
@@ -62,21 +80,53 @@ func (r *CertRotor) checkAndRotate() {
 				// TODO: Need to push metric?
 				log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrRotatingCert)).
 					Msgf("Error rotating cert SerialNumber=%s", cert.GetSerialNumber())
+				currentCerts = append(currentCerts, cert)
 				continue
 			}
 			log.Trace().Msgf("Rotated cert SerialNumber=%s", newCert.GetSerialNumber())
+			cert = newCert
 		}
+
+		currentCerts = append(currentCerts, cert)
+	}
+}
+
+// updateExpiringCertMetrics updates the number of the given certificates expiring within each window
+func (r *CertRotor) updateExpiringCertMetrics(certs []certificate.Certificater) {
+	for _, hours := range expiringCertWindowsHours {
+		window := time.Duration(hours) * time.Hour
+		expiring := 0
+		for _, cert := range certs {
+			if time.Until(cert.GetExpiration()) <= window {
+				expiring++
+			}
+		}
+		metricsstore.DefaultMetricsStore.CertExpiringCount.WithLabelValues(r.providerKind, strconv.Itoa(hours)).Set(float64(expiring))
 	}
 }
 
 // ShouldRotate determines whether a certificate should be rotated.
 func ShouldRotate(cert certificate.Certificater) bool {
 	// The certificate is going to expire at a timestamp T
-	// We want to renew earlier. How much earlier is defined in renewBeforeCertExpires.
+	// We want to renew earlier. How much earlier is defined by getRenewBefore.
 	// We add a few seconds noise to the early renew period so that certificates that may have been
 	// created at the same time are not renewed at the exact same time.
 
 	intNoise := rand.Intn(maxNoiseSeconds-minNoiseSeconds) + minNoiseSeconds /* #nosec G404 */
 	secondsNoise := time.Duration(intNoise) * time.Second
-	return time.Until(cert.GetExpiration()) <= (renewBeforeCertExpires + secondsNoise)
+	return time.Until(cert.GetExpiration()) <= (getRenewBefore(cert) + secondsNoise)
+}
+
+// getRenewBefore returns how much earlier than its expiration the given certificate is renewed: a fraction of its
+// lifetime, and at least renewBeforeCertExpires
+func getRenewBefore(cert certificate.Certificater) time.Duration {
+	if cert.GetIssueTime().IsZero() {
+		return renewBeforeCertExpires
+	}
+
+	renewBefore := cert.GetExpiration().Sub(cert.GetIssueTime()) / renewBeforeCertExpiresLifetimeDivisor
+	if renewBefore < renewBeforeCertExpires {
+		return renewBeforeCertExpires
+	}
+	return renewBefore
 }
@@ -78,7 +78,7 @@ var _ = Describe("Test Rotor", func() {
 			done := make(chan interface{})
 
 			start := time.Now()
-			rotor.New(certManager).Start(360 * time.Second)
+			rotor.New(certManager, "tresor").Start(360 * time.Second)
 			// Wait for one certificate rotation to be announced and terminate
 			<-certAnnouncement
 			close(done)
@@ -92,4 +92,31 @@ var _ = Describe("Test Rotor", func() {
 		})
 	})
 
+	Context("Testing rotating short-lived certificates", func() {
+		It("rotates a certificate a fraction of its lifetime before it expires", func() {
+			// The certificate expires in a minute out of its 10 minutes lifetime
+			cert := certificate.NewMockCertificater(mockCtrl)
+			cert.EXPECT().GetIssueTime().Return(time.Now().Add(-9 * time.Minute)).AnyTimes()
+			cert.EXPECT().GetExpiration().Return(time.Now().Add(1 * time.Minute)).AnyTimes()
+
+			Expect(rotor.ShouldRotate(cert)).To(BeTrue())
+		})
+
+		It("does not rotate a certificate before a fraction of its lifetime before it expires", func() {
+			// The certificate expires in 5 minutes out of its 10 minutes lifetime
+			cert := certificate.NewMockCertificater(mockCtrl)
+			cert.EXPECT().GetIssueTime().Return(time.Now().Add(-5 * time.Minute)).AnyTimes()
+			cert.EXPECT().GetExpiration().Return(time.Now().Add(5 * time.Minute)).AnyTimes()
+
+			Expect(rotor.ShouldRotate(cert)).To(BeFalse())
+		})
+
+		It("rotates a certificate with an unknown lifetime shortly before it expires", func() {
+			cert := certificate.NewMockCertificater(mockCtrl)
+			cert.EXPECT().GetIssueTime().Return(time.Time{}).AnyTimes()
+			cert.EXPECT().GetExpiration().Return(time.Now().Add(1 * time.Minute)).AnyTimes()
+
+			Expect(rotor.ShouldRotate(cert)).To(BeFalse())
+		})
+	})
 })
@@ -13,4 +13,7 @@ var (
 // CertRotor is a simple facility, which rotates expired certificates.
 type CertRotor struct {
 	certManager certificate.Manager
+
+	// providerKind is the kind of the certificate provider of certManager, labeling the certificate metrics
+	providerKind string
 }
@@ -3,7 +3,11 @@
 package certificate
 
 import (
+	"net"
+	"net/url"
 	"time"
+
+	"github.com/openservicemesh/osm/pkg/identity"
 )
 
 const (
@@ -32,6 +36,22 @@ func (cn CommonName) String() string {
 	return string(cn)
 }
 
+// IP returns the IP address the CommonName is made of, or nil if the CommonName is not an IP address. Certificates
+// for IP addresses carry the address as an IP SAN instead of a DNS SAN, so that TLS clients dialing the IP accept them.
+func (cn CommonName) IP() net.IP {
+	return net.ParseIP(string(cn))
+}
+
+// SPIFFEID returns the SPIFFE ID in the given trust domain of the service identity the CommonName is made of, or nil
+// if the trust domain is empty or the CommonName is not a service identity. Certificates of service identities carry
+// their SPIFFE ID as a URI SAN when a SPIFFE trust domain is configured, so that SPIFFE-aware peers authenticate them.
+func (cn CommonName) SPIFFEID(trustDomain string) *url.URL {
+	if trustDomain == "" {
+		return nil
+	}
+	return identity.ServiceIdentity(cn).SPIFFEID(trustDomain)
+}
+
 // Certificater is the interface declaring methods each Certificate object must have.
 type Certificater interface {
 
@@ -50,6 +70,9 @@ type Certificater interface {
 	// GetExpiration returns the time the certificate would expire.
 	GetExpiration() time.Time
 
+	// GetIssueTime returns the time the certificate was issued, i.e. the start of its validity period.
+	GetIssueTime() time.Time
+
 	// GetSerialNumber returns the serial number of the given certificate.
 	GetSerialNumber() SerialNumber
 }
@@ -75,3 +98,16 @@ type Manager interface {
 	// This method could be called when a given payload is terminated. Calling this should remove certs from cache and free memory if possible.
 	ReleaseCertificate(CommonName)
 }
+
+// TrustBundleStore is the interface declaring the methods to store and retrieve the CA bundles of remote trust domains.
+// It is implemented by certificate managers that support exchanging trust bundles with other clusters.
+type TrustBundleStore interface {
+	// GetTrustBundle returns the CA bundle in PEM format of the given trust domain.
+	GetTrustBundle(trustDomain string) ([]byte, error)
+
+	// SetTrustBundle stores the CA bundle in PEM format of the given trust domain.
+	SetTrustBundle(trustDomain string, caBundle []byte) error
+
+	// ListTrustDomains returns the sorted list of trust domains with a stored CA bundle.
+	ListTrustDomains() []string
+}
@@ -0,0 +1,24 @@
+package cli
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+// WriteBackup writes to the given writer the backup archive of the policy state of the mesh exported by the
+// osm-controller running in the given OSM namespace.
+func WriteBackup(clientSet kubernetes.Interface, config *rest.Config, osmNamespace string, localPort uint16, w io.Writer) error {
+	err := readFromController(clientSet, config, osmNamespace, constants.HTTPServerBackupPath, nil, localPort, func(body io.Reader) error {
+		_, err := io.Copy(w, body)
+		return err
+	})
+	if err != nil {
+		return errors.Wrap(err, "Error exporting backup")
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"net/url"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/errcode"
+)
+
+// GetErrorCounts returns the recent error counts of the osm-controller running in the given OSM namespace by error
+// code, proxy and resource, most recently seen first. Only the counts of the given error code, proxy and resource are
+// returned for each of them that is not empty.
+func GetErrorCounts(clientSet kubernetes.Interface, config *rest.Config, osmNamespace string, code string, proxy string, resource string, localPort uint16) ([]errcode.ErrorCount, error) {
+	query := url.Values{}
+	if code != "" {
+		query.Set("code", code)
+	}
+	if proxy != "" {
+		query.Set("proxy", proxy)
+	}
+	if resource != "" {
+		query.Set("resource", resource)
+	}
+
+	var counts []errcode.ErrorCount
+	if err := getFromController(clientSet, config, osmNamespace, constants.HTTPServerErrorsPath, query, localPort, &counts); err != nil {
+		return nil, errors.Wrap(err, "Error retrieving error counts")
+	}
+	return counts, nil
+}
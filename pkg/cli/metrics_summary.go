@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"net/url"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/meshmetrics"
+)
+
+// GetMetricsSummary returns the metrics of the inbound traffic of the given service, aggregated across the proxy
+// sidecars of its pods by the osm-controller running in the given OSM namespace.
+func GetMetricsSummary(clientSet kubernetes.Interface, config *rest.Config, osmNamespace string, namespace string, service string, localPort uint16) (*meshmetrics.ServiceSummary, error) {
+	query := url.Values{
+		"namespace": []string{namespace},
+		"service":   []string{service},
+	}
+
+	summary := &meshmetrics.ServiceSummary{}
+	if err := getFromController(clientSet, config, osmNamespace, constants.HTTPServerMetricsSummaryPath, query, localPort, summary); err != nil {
+		return nil, errors.Wrap(err, "Error retrieving metrics summary")
+	}
+	return summary, nil
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy/registry"
+	"github.com/openservicemesh/osm/pkg/ha"
+	"github.com/openservicemesh/osm/pkg/k8s"
+)
+
+// GetProxyInventory returns the inventory of the proxies connected to the osm-controller running in the given OSM
+// namespace. Only the proxies of the pods in the given namespace are returned if the namespace is not empty.
+func GetProxyInventory(clientSet kubernetes.Interface, config *rest.Config, osmNamespace string, namespace string, localPort uint16) ([]registry.ProxyInfo, error) {
+	query := url.Values{}
+	if namespace != "" {
+		query.Set("namespace", namespace)
+	}
+
+	var inventory []registry.ProxyInfo
+	if err := getFromController(clientSet, config, osmNamespace, constants.HTTPServerProxyInventoryPath, query, localPort, &inventory); err != nil {
+		return nil, errors.Wrap(err, "Error retrieving proxy inventory")
+	}
+	return inventory, nil
+}
+
+// getFromController decodes into the given value the JSON response of the HTTP server of the osm-controller running in
+// the given OSM namespace to a GET request on the given path and query, port forwarded from the given local port
+func getFromController(clientSet kubernetes.Interface, config *rest.Config, osmNamespace string, path string, query url.Values, localPort uint16, v interface{}) error {
+	return readFromController(clientSet, config, osmNamespace, path, query, localPort, func(body io.Reader) error {
+		if err := json.NewDecoder(body).Decode(v); err != nil {
+			return errors.Errorf("Error rendering HTTP response: %s", err)
+		}
+		return nil
+	})
+}
+
+// readFromController reads with the given function the response of the HTTP server of the osm-controller running in
+// the given OSM namespace to a GET request on the given path and query, port forwarded from the given local port
+func readFromController(clientSet kubernetes.Interface, config *rest.Config, osmNamespace string, path string, query url.Values, localPort uint16, read func(io.Reader) error) error {
+	controllerPod, err := getRunningControllerPod(clientSet, osmNamespace)
+	if err != nil {
+		return err
+	}
+
+	dialer, err := k8s.DialerToPod(config, clientSet, controllerPod, osmNamespace)
+	if err != nil {
+		return err
+	}
+
+	portForwarder, err := k8s.NewPortForwarder(dialer, fmt.Sprintf("%d:%d", localPort, constants.OSMHTTPServerPort))
+	if err != nil {
+		return errors.Errorf("Error setting up port forwarding: %s", err)
+	}
+
+	err = portForwarder.Start(func(pf *k8s.PortForwarder) error {
+		defer pf.Stop()
+		requestURL := fmt.Sprintf("http://localhost:%d%s", localPort, path)
+		if len(query) > 0 {
+			requestURL = fmt.Sprintf("%s?%s", requestURL, query.Encode())
+		}
+
+		// #nosec G107: Potential HTTP request made with variable url
+		resp, err := http.Get(requestURL)
+		if err != nil {
+			return errors.Errorf("Error fetching url %s: %s", requestURL, err)
+		}
+		defer resp.Body.Close() //nolint: errcheck,gosec
+
+		if resp.StatusCode != http.StatusOK {
+			return errors.Errorf("Error fetching url %s: %s", requestURL, resp.Status)
+		}
+
+		return read(resp.Body)
+	})
+	if err != nil {
+		return errors.Errorf("Error fetching %s from pod %s in namespace %s: %s", path, controllerPod, osmNamespace, err)
+	}
+	return nil
+}
+
+// getRunningControllerPod returns the name of a running osm-controller pod in the given namespace. With high
+// availability, the pod of the leader is returned, since the proxies only connect to the leader, and the pod of any
+// running controller only when no pod is labeled as the leader, such as when high availability is disabled.
+func getRunningControllerPod(clientSet kubernetes.Interface, osmNamespace string) (string, error) {
+	leaderSelector := labels.SelectorFromSet(labels.Set{"app": constants.OSMControllerName, ha.LeaderLabelKey: "true"}).String()
+	leaderPod, err := getRunningPod(clientSet, osmNamespace, leaderSelector)
+	if err != nil || leaderPod != "" {
+		return leaderPod, err
+	}
+
+	controllerPod, err := getRunningPod(clientSet, osmNamespace, labels.SelectorFromSet(labels.Set{"app": constants.OSMControllerName}).String())
+	if err != nil || controllerPod != "" {
+		return controllerPod, err
+	}
+	return "", errors.Errorf("No running %s pod found in namespace %s", constants.OSMControllerName, osmNamespace)
+}
+
+// getRunningPod returns the name of a running osm-controller pod matching the given label selector in the given
+// namespace, or an empty name if none
+func getRunningPod(clientSet kubernetes.Interface, osmNamespace string, selector string) (string, error) {
+	pods, err := clientSet.CoreV1().Pods(osmNamespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return "", errors.Errorf("Error listing %s pods in namespace %s: %s", constants.OSMControllerName, osmNamespace, err)
+	}
+
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodRunning {
+			return pod.Name, nil
+		}
+	}
+	return "", nil
+}
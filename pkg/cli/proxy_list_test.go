@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeKube "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/ha"
+)
+
+func TestGetRunningControllerPod(t *testing.T) {
+	controllerPod := func(name string, phase corev1.PodPhase, leader bool) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "osm-system",
+				Labels:    map[string]string{"app": constants.OSMControllerName},
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+		if leader {
+			pod.Labels[ha.LeaderLabelKey] = "true"
+		}
+		return pod
+	}
+
+	testCases := []struct {
+		name        string
+		pods        []runtime.Object
+		expectedPod string
+		expectErr   bool
+	}{
+		{
+			name:        "running controller without high availability",
+			pods:        []runtime.Object{controllerPod("osm-controller-1", corev1.PodPending, false), controllerPod("osm-controller-2", corev1.PodRunning, false)},
+			expectedPod: "osm-controller-2",
+		},
+		{
+			name:        "leader is preferred over the standby controllers",
+			pods:        []runtime.Object{controllerPod("osm-controller-1", corev1.PodRunning, false), controllerPod("osm-controller-2", corev1.PodRunning, true)},
+			expectedPod: "osm-controller-2",
+		},
+		{
+			name:      "no running controller",
+			pods:      []runtime.Object{controllerPod("osm-controller-1", corev1.PodFailed, true)},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			pod, err := getRunningControllerPod(fakeKube.NewSimpleClientset(tc.pods...), "osm-system")
+			assert.Equal(tc.expectErr, err != nil)
+			assert.Equal(tc.expectedPod, pod)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"net/url"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy/als"
+)
+
+// GetRBACDenials returns the recent inbound requests denied by the RBAC policies of the proxies, as aggregated by the
+// osm-controller running in the given OSM namespace, most recent first. Only the denials of the services in the given
+// namespace are returned if the namespace is not empty, and only those of the given service if it is not empty.
+func GetRBACDenials(clientSet kubernetes.Interface, config *rest.Config, osmNamespace string, namespace string, service string, localPort uint16) ([]als.Denial, error) {
+	query := url.Values{}
+	if namespace != "" {
+		query.Set("namespace", namespace)
+	}
+	if service != "" {
+		query.Set("service", service)
+	}
+
+	var denials []als.Denial
+	if err := getFromController(clientSet, config, osmNamespace, constants.HTTPServerRBACDenialsPath, query, localPort, &denials); err != nil {
+		return nil, errors.Wrap(err, "Error retrieving RBAC denials")
+	}
+	return denials, nil
+}
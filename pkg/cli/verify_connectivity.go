@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"net/url"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openservicemesh/osm/pkg/connectivity"
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+// VerifyConnectivity returns the report of the verification, by the osm-controller running in the given OSM namespace,
+// of the connectivity from the given source pod to the given destination service.
+func VerifyConnectivity(clientSet kubernetes.Interface, config *rest.Config, osmNamespace string, srcNamespace string, srcPod string, dstNamespace string, dstService string, localPort uint16) (*connectivity.Report, error) {
+	query := url.Values{
+		"srcNamespace": []string{srcNamespace},
+		"srcPod":       []string{srcPod},
+		"dstNamespace": []string{dstNamespace},
+		"dstService":   []string{dstService},
+	}
+
+	report := &connectivity.Report{}
+	if err := getFromController(clientSet, config, osmNamespace, constants.HTTPServerVerifyConnectivityPath, query, localPort, report); err != nil {
+		return nil, errors.Wrap(err, "Error verifying connectivity")
+	}
+	return report, nil
+}
@@ -0,0 +1,177 @@
+package compliance
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+
+	"github.com/pkg/errors"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+)
+
+var (
+	errNoPrivateKeyInPEM        = errors.New("No private key found in PEM")
+	errUnsupportedKeyType       = errors.New("Unsupported private key type")
+	errUnsupportedSignatureAlgo = errors.New("Unsupported signature algorithm")
+	errInvalidSignature         = errors.New("Invalid snapshot signature")
+	errUnexpectedSigner         = errors.New("Snapshot signed by an unexpected identity")
+)
+
+// signSnapshot returns the given snapshot signed with the private key of the given certificate
+func signSnapshot(snapshot Snapshot, cert certificate.Certificater) (SignedSnapshot, error) {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return SignedSnapshot{}, errors.Wrap(err, "Error marshalling compliance snapshot")
+	}
+
+	signer, err := parsePrivateKey(cert.GetPrivateKey())
+	if err != nil {
+		return SignedSnapshot{}, errors.Wrapf(err, "Error parsing private key of certificate %s", cert.GetCommonName())
+	}
+
+	var algorithm string
+	switch signer.Public().(type) {
+	case *rsa.PublicKey:
+		algorithm = SignatureAlgorithmRSA
+	case *ecdsa.PublicKey:
+		algorithm = SignatureAlgorithmECDSA
+	default:
+		return SignedSnapshot{}, errUnsupportedKeyType
+	}
+
+	digest := sha256.Sum256(data)
+	signature, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return SignedSnapshot{}, errors.Wrap(err, "Error signing compliance snapshot")
+	}
+
+	return SignedSnapshot{
+		Snapshot:           data,
+		SignatureAlgorithm: algorithm,
+		Signature:          base64.StdEncoding.EncodeToString(signature),
+		Certificate:        string(cert.GetCertificateChain()),
+		IssuingCA:          string(cert.GetIssuingCA()),
+	}, nil
+}
+
+// VerifySnapshot verifies the signature of the given signed snapshot, that the certificate it was signed with chained
+// up to the given PEM encoded root certificates at the time the snapshot was taken, and that the certificate was issued
+// to the expected signer, as returned by GetSigningCommonName, and returns the decoded snapshot
+func VerifySnapshot(signed SignedSnapshot, rootsPEM []byte, expectedSigner certificate.CommonName) (*Snapshot, error) {
+	if expectedSigner == "" {
+		return nil, errors.New("Expected signer of the snapshot is required")
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(signed.Snapshot, &snapshot); err != nil {
+		return nil, errors.Wrap(err, "Error decoding compliance snapshot")
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(rootsPEM) {
+		return nil, errors.New("No root certificate found in PEM")
+	}
+
+	cert, err := certificate.DecodePEMCertificate([]byte(signed.Certificate))
+	if err != nil {
+		return nil, errors.Wrap(err, "Error decoding signing certificate")
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:       roots,
+		CurrentTime: snapshot.Timestamp,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return nil, errors.Wrap(err, "Error verifying signing certificate")
+	}
+	if !isIssuedTo(cert, expectedSigner) {
+		return nil, errUnexpectedSigner
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(signed.Signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error decoding snapshot signature")
+	}
+
+	digest := sha256.Sum256(signed.Snapshot)
+	switch signed.SignatureAlgorithm {
+	case SignatureAlgorithmRSA:
+		publicKey, ok := cert.PublicKey.(*rsa.PublicKey)
+		if !ok {
+			return nil, errUnsupportedKeyType
+		}
+		if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature); err != nil {
+			return nil, errInvalidSignature
+		}
+	case SignatureAlgorithmECDSA:
+		publicKey, ok := cert.PublicKey.(*ecdsa.PublicKey)
+		if !ok {
+			return nil, errUnsupportedKeyType
+		}
+		if !ecdsa.VerifyASN1(publicKey, digest[:], signature) {
+			return nil, errInvalidSignature
+		}
+	default:
+		return nil, errUnsupportedSignatureAlgo
+	}
+
+	return &snapshot, nil
+}
+
+// isIssuedTo returns true if the given certificate was issued to the given common name: its subject common name must
+// match, and so must one of its DNS SANs if it has any
+func isIssuedTo(cert *x509.Certificate, cn certificate.CommonName) bool {
+	if cert.Subject.CommonName != cn.String() {
+		return false
+	}
+	if len(cert.DNSNames) == 0 {
+		return true
+	}
+	for _, dnsName := range cert.DNSNames {
+		if dnsName == cn.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePrivateKey returns the signer for the first PKCS#8, PKCS#1 or EC private key in the given PEM
+func parsePrivateKey(keyPEM []byte) (crypto.Signer, error) {
+	for len(keyPEM) > 0 {
+		var block *pem.Block
+		block, keyPEM = pem.Decode(keyPEM)
+		if block == nil {
+			break
+		}
+
+		var key interface{}
+		var err error
+		switch block.Type {
+		case certificate.TypePrivateKey:
+			key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		case "RSA PRIVATE KEY":
+			key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			key, err = x509.ParseECPrivateKey(block.Bytes)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, errUnsupportedKeyType
+		}
+		return signer, nil
+	}
+
+	return nil, errNoPrivateKeyInPEM
+}
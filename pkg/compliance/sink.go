@@ -0,0 +1,278 @@
+package compliance
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+const (
+	// SinkKindConfigMap is the kind of the sink storing snapshots in ConfigMaps
+	SinkKindConfigMap = "configmap"
+
+	// SinkKindS3 is the kind of the sink storing snapshots in an S3 bucket
+	SinkKindS3 = "s3"
+
+	// SnapshotKey is the key in the snapshot ConfigMap holding the signed snapshot
+	SnapshotKey = "snapshot.json"
+
+	// snapshotConfigMapPrefix is the prefix of the names of ConfigMaps storing snapshots
+	snapshotConfigMapPrefix = "osm-compliance-"
+
+	// snapshotLabelKey is the label identifying ConfigMaps storing snapshots
+	snapshotLabelKey = "compliance.openservicemesh.io/snapshot"
+
+	// DefaultMaxRetainedSnapshots is the default number of snapshots retained by the ConfigMap sink
+	DefaultMaxRetainedSnapshots = 48
+
+	// maxConfigMapSnapshotSize is the maximum size of a snapshot that can be stored in a ConfigMap
+	maxConfigMapSnapshotSize = 1024 * 1024
+
+	// s3RequestTimeout is the timeout of requests to the S3 API
+	s3RequestTimeout = 30 * time.Second
+)
+
+var errSnapshotTooLarge = errors.Errorf("Snapshot exceeds the maximum size of %d bytes", maxConfigMapSnapshotSize)
+
+// configMapSink is a Sink that stores snapshots in ConfigMaps, retaining the most recent ones
+type configMapSink struct {
+	kubeClient  kubernetes.Interface
+	namespace   string
+	maxRetained int
+}
+
+// NewConfigMapSink returns a Sink storing snapshots in ConfigMaps in the given namespace, retaining at most
+// maxRetained snapshots
+func NewConfigMapSink(kubeClient kubernetes.Interface, namespace string, maxRetained int) Sink {
+	if maxRetained <= 0 {
+		maxRetained = DefaultMaxRetainedSnapshots
+	}
+
+	return &configMapSink{
+		kubeClient:  kubeClient,
+		namespace:   namespace,
+		maxRetained: maxRetained,
+	}
+}
+
+// Store stores the given snapshot in a ConfigMap, prunes the oldest snapshots beyond the retention limit, and returns
+// the namespaced name of the ConfigMap
+func (s *configMapSink) Store(name string, data []byte) (string, error) {
+	if len(data) > maxConfigMapSnapshotSize {
+		return "", errSnapshotTooLarge
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      snapshotConfigMapPrefix + name,
+			Namespace: s.namespace,
+			Labels: map[string]string{
+				constants.OSMAppNameLabelKey: constants.OSMAppNameLabelValue,
+				snapshotLabelKey:             "true",
+			},
+		},
+		Data: map[string]string{
+			SnapshotKey: string(data),
+		},
+	}
+
+	if _, err := s.kubeClient.CoreV1().ConfigMaps(s.namespace).Create(context.Background(), configMap, metav1.CreateOptions{}); err != nil {
+		return "", errors.Wrapf(err, "Error creating ConfigMap %s/%s for compliance snapshot", configMap.Namespace, configMap.Name)
+	}
+
+	s.prune()
+
+	return fmt.Sprintf("%s/%s", configMap.Namespace, configMap.Name), nil
+}
+
+// prune deletes the oldest snapshot ConfigMaps beyond the retention limit
+func (s *configMapSink) prune() {
+	configMaps, err := s.kubeClient.CoreV1().ConfigMaps(s.namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=true", snapshotLabelKey),
+	})
+	if err != nil {
+		log.Error().Err(err).Msgf("Error listing compliance snapshot ConfigMaps in namespace %s", s.namespace)
+		return
+	}
+	if len(configMaps.Items) <= s.maxRetained {
+		return
+	}
+
+	// Snapshot names embed their timestamp, so they sort in the order they were taken
+	names := make([]string, 0, len(configMaps.Items))
+	for _, configMap := range configMaps.Items {
+		names = append(names, configMap.Name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names[:len(names)-s.maxRetained] {
+		if err := s.kubeClient.CoreV1().ConfigMaps(s.namespace).Delete(context.Background(), name, metav1.DeleteOptions{}); err != nil {
+			log.Error().Err(err).Msgf("Error deleting compliance snapshot ConfigMap %s/%s", s.namespace, name)
+		}
+	}
+}
+
+// S3Config is the type used to represent the configuration of the S3 sink
+type S3Config struct {
+	// Endpoint is the base URL of the S3 API, defaults to the AWS S3 endpoint of the region when unset.
+	// Objects are addressed using path-style URLs so that S3 compatible object stores are supported.
+	Endpoint string
+
+	// Region is the region of the bucket
+	Region string
+
+	// Bucket is the name of the bucket snapshots are stored in
+	Bucket string
+
+	// Prefix is the prefix of the keys snapshots are stored under
+	Prefix string
+
+	// AccessKeyID is the access key ID used to sign requests
+	AccessKeyID string
+
+	// SecretAccessKey is the secret access key used to sign requests
+	SecretAccessKey string
+
+	// SessionToken is the session token of temporary credentials
+	// +optional
+	SessionToken string
+}
+
+// s3Sink is a Sink that stores snapshots as objects in an S3 bucket
+type s3Sink struct {
+	config     S3Config
+	httpClient *http.Client
+}
+
+// NewS3Sink returns a Sink storing snapshots in the S3 bucket described by the given config
+func NewS3Sink(config S3Config) (Sink, error) {
+	if config.Bucket == "" {
+		return nil, errors.New("An S3 bucket must be specified")
+	}
+	if config.Region == "" {
+		return nil, errors.New("An S3 region must be specified")
+	}
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, errors.New("S3 credentials must be specified")
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", config.Region)
+	}
+	config.Endpoint = strings.TrimSuffix(config.Endpoint, "/")
+
+	return &s3Sink{
+		config:     config,
+		httpClient: &http.Client{Timeout: s3RequestTimeout},
+	}, nil
+}
+
+// Store uploads the given snapshot to the bucket and returns the S3 URI of the object
+func (s *s3Sink) Store(name string, data []byte) (string, error) {
+	key := s.config.Prefix + name + ".json"
+
+	objectURL, err := url.Parse(fmt.Sprintf("%s/%s/%s", s.config.Endpoint, s.config.Bucket, key))
+	if err != nil {
+		return "", errors.Wrapf(err, "Error building URL for object %s", key)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPut, objectURL.String(), bytes.NewReader(data))
+	if err != nil {
+		return "", errors.Wrapf(err, "Error creating request for object %s", key)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	s.sign(req, data, time.Now())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", errors.Wrapf(err, "Error uploading object %s to bucket %s", key, s.config.Bucket)
+	}
+	defer resp.Body.Close() //nolint: errcheck,gosec
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("Unexpected status code %d uploading object %s to bucket %s", resp.StatusCode, key, s.config.Bucket)
+	}
+
+	return fmt.Sprintf("s3://%s/%s", s.config.Bucket, key), nil
+}
+
+// sign signs the given request with AWS Signature Version 4
+func (s *s3Sink) sign(req *http.Request, payload []byte, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.config.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.config.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(req.Header.Get(name))
+	}
+	headerNames := make([]string, 0, len(headers))
+	for name := range headers {
+		headerNames = append(headerNames, name)
+	}
+	sort.Strings(headerNames)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range headerNames {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(headerNames, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, s.config.Region)
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.config.SecretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, s.config.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.config.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
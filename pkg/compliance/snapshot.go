@@ -0,0 +1,176 @@
+package compliance
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s"
+)
+
+// NewSnapshotter returns a Snapshotter that stores signed snapshots of the mesh's traffic policy state to the given sink
+func NewSnapshotter(config Config, meshCatalog catalog.MeshCataloger, kubeController k8s.Controller, cfg configurator.Configurator, certManager certificate.Manager, sink Sink) *Snapshotter {
+	interval := config.Interval
+	if interval <= 0 {
+		interval = DefaultSnapshotInterval
+	}
+
+	return &Snapshotter{
+		meshName:          config.MeshName,
+		interval:          interval,
+		signingCommonName: config.SigningCommonName,
+		meshCatalog:       meshCatalog,
+		kubeController:    kubeController,
+		cfg:               cfg,
+		certManager:       certManager,
+		sink:              sink,
+	}
+}
+
+// Run starts taking snapshots at the configured interval until the stop channel is closed
+func (s *Snapshotter) Run(stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if location, err := s.takeSnapshot(); err != nil {
+					log.Error().Err(err).Msg("Error taking compliance snapshot")
+				} else {
+					log.Info().Msgf("Stored compliance snapshot in %s", location)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// takeSnapshot builds, signs and stores a snapshot, and returns the location it was stored at.
+// The signing certificate is issued before the snapshot is taken, so that the snapshot is taken within the validity
+// period of the certificate, which the snapshot is verified against at the time it was taken.
+func (s *Snapshotter) takeSnapshot() (string, error) {
+	signingCert, err := s.certManager.IssueCertificate(s.signingCommonName, signingCertificateValidity)
+	if err != nil {
+		return "", errors.Wrapf(err, "Error issuing certificate %s to sign compliance snapshot", s.signingCommonName)
+	}
+
+	snapshot := s.buildSnapshot(time.Now())
+
+	signed, err := signSnapshot(snapshot, signingCert)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(signed)
+	if err != nil {
+		return "", errors.Wrap(err, "Error marshalling signed compliance snapshot")
+	}
+
+	return s.sink.Store(getSnapshotName(snapshot.Timestamp), data)
+}
+
+// buildSnapshot returns the effective traffic policy state of the mesh at the given time
+func (s *Snapshotter) buildSnapshot(now time.Time) Snapshot {
+	snapshot := Snapshot{
+		Timestamp:         now.UTC(),
+		MeshName:          s.meshName,
+		TrafficPolicyMode: TrafficPolicyModeSMI,
+		Encryption: EncryptionSettings{
+			CertificateValidityPeriod: s.cfg.GetServiceCertValidityPeriod().String(),
+			TrustDomain:               s.cfg.GetTrustDomain(),
+			EgressEnabled:             s.cfg.IsEgressEnabled(),
+		},
+		Identities: []IdentityPolicy{},
+	}
+
+	permissive := s.cfg.IsPermissiveTrafficPolicyMode()
+	if permissive {
+		snapshot.TrafficPolicyMode = TrafficPolicyModePermissive
+	}
+
+	for _, svcIdentity := range s.listServiceIdentities() {
+		policy := IdentityPolicy{
+			Identity: svcIdentity.String(),
+		}
+
+		if permissive {
+			// All mesh identities can communicate with each other in permissive mode
+			policy.AllowedInbound = []string{identity.WildcardServiceIdentity.String()}
+			policy.AllowedOutbound = []string{identity.WildcardServiceIdentity.String()}
+		} else {
+			inbound, err := s.meshCatalog.ListInboundServiceIdentities(svcIdentity)
+			if err != nil {
+				log.Error().Err(err).Msgf("Error listing inbound identities of %s for compliance snapshot", svcIdentity)
+			}
+			outbound, err := s.meshCatalog.ListOutboundServiceIdentities(svcIdentity)
+			if err != nil {
+				log.Error().Err(err).Msgf("Error listing outbound identities of %s for compliance snapshot", svcIdentity)
+			}
+			policy.AllowedInbound = sortedIdentities(inbound)
+			policy.AllowedOutbound = sortedIdentities(outbound)
+		}
+
+		snapshot.Identities = append(snapshot.Identities, policy)
+	}
+
+	for _, externalSvc := range s.meshCatalog.ListExternalServices() {
+		snapshot.ExternalServices = append(snapshot.ExternalServices, ExternalServicePolicy{
+			Provider: externalSvc.Provider,
+			Name:     externalSvc.Name,
+			// Connections to services outside the mesh are not encrypted by the proxies
+			MTLS: false,
+		})
+	}
+	sort.Slice(snapshot.ExternalServices, func(i, j int) bool {
+		if snapshot.ExternalServices[i].Provider != snapshot.ExternalServices[j].Provider {
+			return snapshot.ExternalServices[i].Provider < snapshot.ExternalServices[j].Provider
+		}
+		return snapshot.ExternalServices[i].Name < snapshot.ExternalServices[j].Name
+	})
+
+	return snapshot
+}
+
+// listServiceIdentities returns the sorted identities of the service accounts in the namespaces monitored by the mesh
+func (s *Snapshotter) listServiceIdentities() []identity.ServiceIdentity {
+	var svcIdentities []identity.ServiceIdentity
+	for _, sa := range s.kubeController.ListServiceAccounts() {
+		svcIdentities = append(svcIdentities, identity.K8sServiceAccount{Name: sa.Name, Namespace: sa.Namespace}.ToServiceIdentity())
+	}
+
+	sort.Slice(svcIdentities, func(i, j int) bool {
+		return svcIdentities[i] < svcIdentities[j]
+	})
+	return svcIdentities
+}
+
+// sortedIdentities returns the given identities as a sorted list of strings
+func sortedIdentities(svcIdentities []identity.ServiceIdentity) []string {
+	identities := make([]string, 0, len(svcIdentities))
+	for _, svcIdentity := range svcIdentities {
+		identities = append(identities, svcIdentity.String())
+	}
+	sort.Strings(identities)
+	return identities
+}
+
+// GetSigningCommonName returns the common name of the certificate snapshots are signed with by the OSM controller
+// running in the given namespace. It is the identity VerifySnapshot expects the signer of snapshots to have.
+func GetSigningCommonName(osmNamespace string) certificate.CommonName {
+	return certificate.CommonName(fmt.Sprintf("%s.%s", signingCertificateName, osmNamespace))
+}
+
+// getSnapshotName returns the name a snapshot taken at the given time is stored under
+func getSnapshotName(timestamp time.Time) string {
+	return fmt.Sprintf("snapshot-%s", timestamp.UTC().Format(snapshotNameTimeFormat))
+}
@@ -0,0 +1,146 @@
+package compliance
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	trequire "github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/endpoint"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s"
+)
+
+var (
+	bookbuyerIdentity = identity.K8sServiceAccount{Name: "bookbuyer", Namespace: "bookbuyer"}.ToServiceIdentity()
+	bookstoreIdentity = identity.K8sServiceAccount{Name: "bookstore", Namespace: "bookstore"}.ToServiceIdentity()
+
+	testServiceAccounts = []*corev1.ServiceAccount{
+		{ObjectMeta: metav1.ObjectMeta{Name: "bookstore", Namespace: "bookstore"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "bookbuyer", Namespace: "bookbuyer"}},
+	}
+)
+
+func newTestSnapshotter(mockCtrl *gomock.Controller, permissive bool) (*Snapshotter, *catalog.MockMeshCataloger) {
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+
+	mockKubeController.EXPECT().ListServiceAccounts().Return(testServiceAccounts).AnyTimes()
+	mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(permissive).AnyTimes()
+	mockConfigurator.EXPECT().IsEgressEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetServiceCertValidityPeriod().Return(24 * time.Hour).AnyTimes()
+	mockConfigurator.EXPECT().GetTrustDomain().Return("cluster.local").AnyTimes()
+	mockCatalog.EXPECT().ListExternalServices().Return([]endpoint.ExternalService{{Provider: "Consul", Name: "web"}}).AnyTimes()
+
+	certManager := tresor.NewFakeCertManager(mockConfigurator)
+
+	return NewSnapshotter(Config{MeshName: "osm", SigningCommonName: GetSigningCommonName("osm-system")}, mockCatalog, mockKubeController, mockConfigurator, certManager, nil), mockCatalog
+}
+
+func TestBuildSnapshot(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s, mockCatalog := newTestSnapshotter(mockCtrl, false)
+	mockCatalog.EXPECT().ListInboundServiceIdentities(bookbuyerIdentity).Return(nil, nil)
+	mockCatalog.EXPECT().ListOutboundServiceIdentities(bookbuyerIdentity).Return([]identity.ServiceIdentity{bookstoreIdentity}, nil)
+	mockCatalog.EXPECT().ListInboundServiceIdentities(bookstoreIdentity).Return([]identity.ServiceIdentity{bookbuyerIdentity}, nil)
+	mockCatalog.EXPECT().ListOutboundServiceIdentities(bookstoreIdentity).Return(nil, nil)
+
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	snapshot := s.buildSnapshot(now)
+
+	assert.Equal(Snapshot{
+		Timestamp:         now,
+		MeshName:          "osm",
+		TrafficPolicyMode: TrafficPolicyModeSMI,
+		Encryption: EncryptionSettings{
+			CertificateValidityPeriod: "24h0m0s",
+			TrustDomain:               "cluster.local",
+		},
+		Identities: []IdentityPolicy{
+			{Identity: bookbuyerIdentity.String(), AllowedInbound: []string{}, AllowedOutbound: []string{bookstoreIdentity.String()}},
+			{Identity: bookstoreIdentity.String(), AllowedInbound: []string{bookbuyerIdentity.String()}, AllowedOutbound: []string{}},
+		},
+		ExternalServices: []ExternalServicePolicy{{Provider: "Consul", Name: "web"}},
+	}, snapshot)
+}
+
+func TestBuildSnapshotPermissive(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s, _ := newTestSnapshotter(mockCtrl, true)
+
+	snapshot := s.buildSnapshot(time.Now())
+	assert.Equal(TrafficPolicyModePermissive, snapshot.TrafficPolicyMode)
+	assert.Len(snapshot.Identities, 2)
+	for _, policy := range snapshot.Identities {
+		assert.Equal([]string{"*"}, policy.AllowedInbound)
+		assert.Equal([]string{"*"}, policy.AllowedOutbound)
+	}
+}
+
+type fakeSink struct {
+	name string
+	data []byte
+}
+
+func (s *fakeSink) Store(name string, data []byte) (string, error) {
+	s.name = name
+	s.data = data
+	return name, nil
+}
+
+func TestTakeSnapshot(t *testing.T) {
+	assert := tassert.New(t)
+	require := trequire.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	s, _ := newTestSnapshotter(mockCtrl, true)
+	sink := &fakeSink{}
+	s.sink = sink
+
+	location, err := s.takeSnapshot()
+	require.Nil(err)
+
+	var signed SignedSnapshot
+	require.Nil(json.Unmarshal(sink.data, &signed))
+	assert.Equal(SignatureAlgorithmRSA, signed.SignatureAlgorithm)
+
+	snapshot, err := VerifySnapshot(signed, []byte(signed.IssuingCA), GetSigningCommonName("osm-system"))
+	require.Nil(err)
+	assert.Equal(getSnapshotName(snapshot.Timestamp), location)
+	assert.Equal("osm", snapshot.MeshName)
+	assert.Len(snapshot.Identities, 2)
+
+	// The signing certificate must be issued to the expected signer
+	_, err = VerifySnapshot(signed, []byte(signed.IssuingCA), GetSigningCommonName("other"))
+	assert.Equal(errUnexpectedSigner, err)
+	_, err = VerifySnapshot(signed, []byte(signed.IssuingCA), "")
+	assert.NotNil(err)
+
+	// Tampering with the snapshot invalidates the signature
+	signed.Snapshot = []byte(`{"timestamp":"` + snapshot.Timestamp.Format(time.RFC3339Nano) + `","meshName":"tampered"}`)
+	_, err = VerifySnapshot(signed, []byte(signed.IssuingCA), GetSigningCommonName("osm-system"))
+	assert.Equal(errInvalidSignature, err)
+
+	// The signing certificate must chain up to the given roots
+	otherCertManager := tresor.NewFakeCertManager(nil)
+	otherRoot, err := otherCertManager.GetRootCertificate()
+	assert.Nil(err)
+	_, err = VerifySnapshot(signed, otherRoot.GetCertificateChain(), GetSigningCommonName("osm-system"))
+	assert.NotNil(err)
+}
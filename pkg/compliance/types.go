@@ -0,0 +1,157 @@
+// Package compliance implements periodic snapshots of the effective traffic policy state of the mesh, signed by a
+// certificate issued by the mesh's certificate manager, so that auditors can obtain point-in-time evidence of which
+// identities are allowed to communicate and how their traffic is encrypted without access to the cluster.
+package compliance
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var (
+	log = logger.New("compliance")
+)
+
+const (
+	// DefaultSnapshotInterval is the default interval at which snapshots are taken
+	DefaultSnapshotInterval = 1 * time.Hour
+
+	// signingCertificateValidity is the validity period of the certificate snapshots are signed with
+	signingCertificateValidity = 24 * time.Hour
+
+	// snapshotNameTimeFormat is the format of the timestamp in the names of stored snapshots
+	snapshotNameTimeFormat = "20060102-150405"
+
+	// signingCertificateName is the name of the certificate snapshots are signed with, qualified by the OSM namespace
+	signingCertificateName = "osm-compliance"
+)
+
+const (
+	// TrafficPolicyModePermissive is the traffic policy mode in which all mesh identities can communicate with each other
+	TrafficPolicyModePermissive = "permissive"
+
+	// TrafficPolicyModeSMI is the traffic policy mode in which communication is governed by SMI traffic targets
+	TrafficPolicyModeSMI = "smi"
+)
+
+const (
+	// SignatureAlgorithmRSA is the signature algorithm of snapshots signed with an RSA key
+	SignatureAlgorithmRSA = "SHA256-RSA-PKCS1v15"
+
+	// SignatureAlgorithmECDSA is the signature algorithm of snapshots signed with an ECDSA key
+	SignatureAlgorithmECDSA = "SHA256-ECDSA"
+)
+
+// Snapshot is the type used to represent the effective traffic policy state of the mesh at a point in time
+type Snapshot struct {
+	// Timestamp is the time at which the snapshot was taken
+	Timestamp time.Time `json:"timestamp"`
+
+	// MeshName is the name of the mesh the snapshot was taken for
+	MeshName string `json:"meshName"`
+
+	// TrafficPolicyMode is the traffic policy mode of the mesh, one of permissive or smi
+	TrafficPolicyMode string `json:"trafficPolicyMode"`
+
+	// Encryption describes how traffic between mesh identities is encrypted
+	Encryption EncryptionSettings `json:"encryption"`
+
+	// Identities is the list of mesh identities along with the identities they are allowed to communicate with
+	Identities []IdentityPolicy `json:"identities"`
+
+	// ExternalServices is the list of services registered outside the mesh that mesh identities can reach
+	// +optional
+	ExternalServices []ExternalServicePolicy `json:"externalServices,omitempty"`
+}
+
+// EncryptionSettings is the type used to represent the encryption settings of the mesh
+type EncryptionSettings struct {
+	// CertificateValidityPeriod is the validity period of the certificates issued to proxies
+	CertificateValidityPeriod string `json:"certificateValidityPeriod"`
+
+	// TrustDomain is the trust domain of the certificates issued to proxies
+	// +optional
+	TrustDomain string `json:"trustDomain,omitempty"`
+
+	// EgressEnabled indicates whether traffic to destinations outside the mesh is allowed to pass through proxies
+	EgressEnabled bool `json:"egressEnabled"`
+}
+
+// IdentityPolicy is the type used to represent the identities a mesh identity is allowed to communicate with
+type IdentityPolicy struct {
+	// Identity is the mesh identity
+	Identity string `json:"identity"`
+
+	// AllowedInbound is the list of identities allowed to connect to the identity
+	AllowedInbound []string `json:"allowedInbound"`
+
+	// AllowedOutbound is the list of identities the identity is allowed to connect to
+	AllowedOutbound []string `json:"allowedOutbound"`
+}
+
+// ExternalServicePolicy is the type used to represent a service registered outside the mesh that mesh identities can reach
+type ExternalServicePolicy struct {
+	// Provider is the provider the service was discovered from
+	Provider string `json:"provider"`
+
+	// Name is the name of the service
+	Name string `json:"name"`
+
+	// MTLS indicates whether traffic to the service is encrypted with mutual TLS
+	MTLS bool `json:"mtls"`
+}
+
+// SignedSnapshot is the type used to represent a snapshot along with its signature and the certificate it was signed with
+type SignedSnapshot struct {
+	// Snapshot is the JSON encoded snapshot the signature was computed over
+	Snapshot json.RawMessage `json:"snapshot"`
+
+	// SignatureAlgorithm is the algorithm the signature was computed with
+	SignatureAlgorithm string `json:"signatureAlgorithm"`
+
+	// Signature is the base64 encoded signature of the snapshot
+	Signature string `json:"signature"`
+
+	// Certificate is the PEM encoded certificate the snapshot was signed with
+	Certificate string `json:"certificate"`
+
+	// IssuingCA is the PEM encoded root certificate of the signing certificate
+	IssuingCA string `json:"issuingCA"`
+}
+
+// Sink is the interface to be implemented by the destinations snapshots are stored to
+type Sink interface {
+	// Store stores the given signed snapshot under the given name and returns the location it was stored at
+	Store(name string, data []byte) (string, error)
+}
+
+// Config is the type used to represent the configuration of the Snapshotter
+type Config struct {
+	// MeshName is the name of the mesh snapshots are taken for
+	MeshName string
+
+	// Interval is the interval at which snapshots are taken
+	Interval time.Duration
+
+	// SigningCommonName is the common name of the certificate snapshots are signed with
+	SigningCommonName certificate.CommonName
+}
+
+// Snapshotter periodically takes signed snapshots of the effective traffic policy state of the mesh
+type Snapshotter struct {
+	meshName          string
+	interval          time.Duration
+	signingCommonName certificate.CommonName
+
+	meshCatalog    catalog.MeshCataloger
+	kubeController k8s.Controller
+	cfg            configurator.Configurator
+	certManager    certificate.Manager
+	sink           Sink
+}
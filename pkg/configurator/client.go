@@ -2,10 +2,13 @@ package configurator
 
 import (
 	"fmt"
+	"reflect"
+	"sync"
 
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
 	"github.com/openservicemesh/osm/pkg/errcode"
 	"github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned"
 	informers "github.com/openservicemesh/osm/pkg/gen/client/config/informers/externalversions"
@@ -34,7 +37,17 @@ func newConfigurator(meshConfigClientSet versioned.Interface, stop <-chan struct
 		k8s.DefaultKubeEventResyncInterval,
 		informers.WithNamespace(osmNamespace),
 	)
-	informer := informerFactory.Config().V1alpha1().MeshConfigs().Informer()
+
+	var informer cache.SharedIndexInformer
+	if isMeshConfigV1alpha2Served(meshConfigClientSet) {
+		log.Info().Msgf("Watching MeshConfig %s/%s through the %s API", osmNamespace, meshConfigName, v1alpha2.SchemeGroupVersion)
+		informer = informerFactory.Config().V1alpha2().MeshConfigs().Informer()
+	} else {
+		log.Info().Msgf("MeshConfig %s API is not served, watching MeshConfig %s/%s through the %s API",
+			v1alpha2.SchemeGroupVersion, osmNamespace, meshConfigName, v1alpha1.SchemeGroupVersion)
+		informer = informerFactory.Config().V1alpha1().MeshConfigs().Informer()
+	}
+
 	client := Client{
 		informer:       informer,
 		cache:          informer.GetStore(),
@@ -58,6 +71,23 @@ func newConfigurator(meshConfigClientSet versioned.Interface, stop <-chan struct
 	return &client
 }
 
+// isMeshConfigV1alpha2Served returns whether the API server serves the v1alpha2 MeshConfig API. The MeshConfig CRD of a
+// control plane being upgraded may only serve v1alpha1, through which the configurator keeps watching the MeshConfig
+// until the CRD is upgraded and the configurator restarted.
+func isMeshConfigV1alpha2Served(meshConfigClientSet versioned.Interface) bool {
+	resources, err := meshConfigClientSet.Discovery().ServerResourcesForGroupVersion(v1alpha2.SchemeGroupVersion.String())
+	if err != nil {
+		log.Debug().Err(err).Msgf("Error discovering the resources of the %s API", v1alpha2.SchemeGroupVersion)
+		return false
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Name == "meshconfigs" {
+			return true
+		}
+	}
+	return false
+}
+
 // Listens to MeshConfig events and notifies dispatcher to issue config updates to the envoys based
 // on config seen on the MeshConfig
 func (c *Client) runMeshConfigListener(stop <-chan struct{}) {
@@ -131,55 +161,257 @@ func meshConfigDeletedMessageHandler(psubMsg *events.PubSubMessage) {
 
 func meshConfigUpdatedMessageHandler(psubMsg *events.PubSubMessage) {
 	// Get the MeshConfig resource
-	prevMeshConfig, okPrevCast := psubMsg.OldObj.(*v1alpha1.MeshConfig)
-	newMeshConfig, okNewCast := psubMsg.NewObj.(*v1alpha1.MeshConfig)
+	prevMeshConfig, okPrevCast := toV1alpha1MeshConfig(psubMsg.OldObj)
+	newMeshConfig, okNewCast := toV1alpha1MeshConfig(psubMsg.NewObj)
 	if !okPrevCast || !okNewCast {
 		log.Error().Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMeshConfigStructCasting)).Msgf("[%s] Error casting old/new MeshConfigs objects (%v %v)",
 			psubMsg.AnnouncementType, okPrevCast, okNewCast)
 		return
 	}
 
-	prevSpec := prevMeshConfig.Spec
-	newSpec := newMeshConfig.Spec
-
-	// Determine if we should issue new global config update to all envoys
-	triggerGlobalBroadcast := false
-
-	triggerGlobalBroadcast = triggerGlobalBroadcast || (prevSpec.Traffic.EnableEgress != newSpec.Traffic.EnableEgress)
-	triggerGlobalBroadcast = triggerGlobalBroadcast || (prevSpec.Traffic.EnablePermissiveTrafficPolicyMode != newSpec.Traffic.EnablePermissiveTrafficPolicyMode)
-	triggerGlobalBroadcast = triggerGlobalBroadcast || (prevSpec.Traffic.UseHTTPSIngress != newSpec.Traffic.UseHTTPSIngress)
-	triggerGlobalBroadcast = triggerGlobalBroadcast || (prevSpec.Observability.Tracing.Enable != newSpec.Observability.Tracing.Enable)
-	triggerGlobalBroadcast = triggerGlobalBroadcast || (prevSpec.Observability.Tracing.Address != newSpec.Observability.Tracing.Address)
-	triggerGlobalBroadcast = triggerGlobalBroadcast || (prevSpec.Observability.Tracing.Endpoint != newSpec.Observability.Tracing.Endpoint)
-	triggerGlobalBroadcast = triggerGlobalBroadcast || (prevSpec.Observability.Tracing.Port != newSpec.Observability.Tracing.Port)
-	triggerGlobalBroadcast = triggerGlobalBroadcast || (prevSpec.Traffic.InboundExternalAuthorization.Enable != newSpec.Traffic.InboundExternalAuthorization.Enable)
-
-	// Do not trigger updates on the inner configuration changes of ExtAuthz if disabled,
-	// or otherwise skip checking if the update is to be scheduled anyway
-	if newSpec.Traffic.InboundExternalAuthorization.Enable && !triggerGlobalBroadcast {
-		triggerGlobalBroadcast = triggerGlobalBroadcast ||
-			(prevSpec.Traffic.InboundExternalAuthorization.Address != newSpec.Traffic.InboundExternalAuthorization.Address)
-		triggerGlobalBroadcast = triggerGlobalBroadcast ||
-			(prevSpec.Traffic.InboundExternalAuthorization.Port != newSpec.Traffic.InboundExternalAuthorization.Port)
-		triggerGlobalBroadcast = triggerGlobalBroadcast ||
-			(prevSpec.Traffic.InboundExternalAuthorization.StatPrefix != newSpec.Traffic.InboundExternalAuthorization.StatPrefix)
-		triggerGlobalBroadcast = triggerGlobalBroadcast ||
-			(prevSpec.Traffic.InboundExternalAuthorization.Timeout != newSpec.Traffic.InboundExternalAuthorization.Timeout)
-		triggerGlobalBroadcast = triggerGlobalBroadcast ||
-			(prevSpec.Traffic.InboundExternalAuthorization.FailureModeAllow != newSpec.Traffic.InboundExternalAuthorization.FailureModeAllow)
-	}
+	prevSpec := &prevMeshConfig.Spec
+	newSpec := &newMeshConfig.Spec
 
-	if triggerGlobalBroadcast {
-		log.Debug().Msgf("[%s] OSM MeshConfig update triggered global proxy broadcast",
-			psubMsg.AnnouncementType)
+	// Notify the subsystems of the fields that changed, so that they only react to the changes relevant to them
+	// rather than the whole mesh being updated on every MeshConfig change
+	changed := false
+	for _, fieldChange := range meshConfigFieldChanges {
+		if !fieldChange.changed(prevSpec, newSpec) {
+			continue
+		}
+		changed = true
+		log.Debug().Msgf("[%s] OSM MeshConfig update triggered %s", psubMsg.AnnouncementType, fieldChange.announcementType)
 		events.Publish(events.PubSubMessage{
-			AnnouncementType: announcements.ScheduleProxyBroadcast,
-			OldObj:           nil,
-			NewObj:           nil,
+			AnnouncementType: fieldChange.announcementType,
+			OldObj:           prevMeshConfig,
+			NewObj:           newMeshConfig,
 		})
-	} else {
-		log.Trace().Msgf("[%s] OSM MeshConfig update, NOT triggering global proxy broadcast",
-			psubMsg.AnnouncementType)
+	}
+
+	if !changed {
+		log.Trace().Msgf("[%s] OSM MeshConfig update, NOT triggering any field change", psubMsg.AnnouncementType)
+	}
+}
+
+// meshConfigFieldChanges are the MeshConfig field-level changes announced on MeshConfig updates
+var meshConfigFieldChanges = []struct {
+	announcementType announcements.AnnouncementType
+	changed          func(prev, next *v1alpha1.MeshConfigSpec) bool
+}{
+	{
+		announcementType: announcements.MeshConfigEgressChanged,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			return prev.Traffic.EnableEgress != next.Traffic.EnableEgress
+		},
+	},
+	{
+		announcementType: announcements.MeshConfigPermissiveTrafficPolicyModeChanged,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			return prev.Traffic.EnablePermissiveTrafficPolicyMode != next.Traffic.EnablePermissiveTrafficPolicyMode
+		},
+	},
+	{
+		announcementType: announcements.MeshConfigHTTPSIngressChanged,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			return prev.Traffic.UseHTTPSIngress != next.Traffic.UseHTTPSIngress
+		},
+	},
+	{
+		announcementType: announcements.MeshConfigTerminatingEndpointsChanged,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			return prev.Traffic.IncludeTerminatingEndpoints != next.Traffic.IncludeTerminatingEndpoints
+		},
+	},
+	{
+		announcementType: announcements.MeshConfigClientCertDetailsChanged,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			return prev.Traffic.ClientCertDetails != next.Traffic.ClientCertDetails
+		},
+	},
+	{
+		announcementType: announcements.MeshConfigRBACAuditChanged,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			return prev.Traffic.RBACAudit != next.Traffic.RBACAudit
+		},
+	},
+	{
+		announcementType: announcements.MeshConfigTrustDomainsChanged,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			return !reflect.DeepEqual(prev.Certificate.TrustDomainAliases, next.Certificate.TrustDomainAliases) ||
+				!reflect.DeepEqual(prev.Certificate.FederatedTrustDomains, next.Certificate.FederatedTrustDomains)
+		},
+	},
+	{
+		announcementType: announcements.MeshConfigTracingChanged,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			return prev.Observability.Tracing != next.Observability.Tracing
+		},
+	},
+	{
+		announcementType: announcements.MeshConfigExternalAuthorizationChanged,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			// Do not announce the inner configuration changes of ExtAuthz if disabled
+			if prev.Traffic.InboundExternalAuthorization.Enable != next.Traffic.InboundExternalAuthorization.Enable {
+				return true
+			}
+			return next.Traffic.InboundExternalAuthorization.Enable &&
+				prev.Traffic.InboundExternalAuthorization != next.Traffic.InboundExternalAuthorization
+		},
+	},
+	{
+		announcementType: announcements.MeshConfigNamespaceIsolationChanged,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			return prev.Traffic.NamespaceIsolation != next.Traffic.NamespaceIsolation
+		},
+	},
+	{
+		announcementType: announcements.MeshConfigOutboundPassthroughChanged,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			return !reflect.DeepEqual(prev.Traffic.OutboundPassthrough, next.Traffic.OutboundPassthrough)
+		},
+	},
+	{
+		announcementType: announcements.MeshConfigRouteRegexChanged,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			return prev.Traffic.RouteRegex != next.Traffic.RouteRegex
+		},
+	},
+	{
+		announcementType: announcements.MeshConfigHTTPSanitizationChanged,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			return prev.Traffic.HTTPSanitization != next.Traffic.HTTPSanitization
+		},
+	},
+	{
+		announcementType: announcements.MeshConfigDownstreamLimitsChanged,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			return prev.Traffic.DownstreamLimits != next.Traffic.DownstreamLimits
+		},
+	},
+	{
+		announcementType: announcements.MeshConfigIngressClientAddressChanged,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			return prev.Traffic.IngressClientAddress != next.Traffic.IngressClientAddress
+		},
+	},
+	{
+		announcementType: announcements.MeshConfigPrometheusScrapingChanged,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			return prev.Observability.PrometheusScraping != next.Observability.PrometheusScraping
+		},
+	},
+	{
+		announcementType: announcements.MeshConfigProvisioningChanged,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			return prev.Observability.Provisioning != next.Observability.Provisioning
+		},
+	},
+	{
+		announcementType: announcements.MeshConfigIngressGatewayCertChanged,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			return !reflect.DeepEqual(prev.Certificate.IngressGateway, next.Certificate.IngressGateway)
+		},
+	},
+	{
+		announcementType: announcements.MeshConfigEndpointFlapDampeningChanged,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			return prev.Traffic.EndpointFlapDampening != next.Traffic.EndpointFlapDampening
+		},
+	},
+	{
+		announcementType: announcements.MeshConfigDNSResolutionChanged,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			return prev.Traffic.DNSResolution != next.Traffic.DNSResolution
+		},
+	},
+	{
+		announcementType: announcements.MeshConfigProtocolDetectionChanged,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			return !reflect.DeepEqual(prev.Traffic.ProtocolDetectionNamespaces, next.Traffic.ProtocolDetectionNamespaces) ||
+				prev.Traffic.ProtocolDetectionTimeout != next.Traffic.ProtocolDetectionTimeout
+		},
+	},
+	{
+		announcementType: announcements.MeshConfigCompressionChanged,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			return !reflect.DeepEqual(prev.Traffic.Compression, next.Traffic.Compression)
+		},
+	},
+	{
+		announcementType: announcements.MeshConfigRequestLimitsChanged,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			return prev.Traffic.RequestLimits != next.Traffic.RequestLimits
+		},
+	},
+	{
+		announcementType: announcements.MeshConfigIngressHTTP3Changed,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			return prev.FeatureFlags.EnableIngressHTTP3 != next.FeatureFlags.EnableIngressHTTP3
+		},
+	},
+	{
+		announcementType: announcements.MeshConfigDNSProxyChanged,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			return prev.FeatureFlags.EnableDNSProxy != next.FeatureFlags.EnableDNSProxy
+		},
+	},
+	{
+		announcementType: announcements.MeshConfigScopedRoutesChanged,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			return prev.FeatureFlags.EnableScopedRoutes != next.FeatureFlags.EnableScopedRoutes
+		},
+	},
+	{
+		announcementType: announcements.MeshConfigFeatureFlagsChanged,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			return prev.FeatureFlags.EnableWASMStats != next.FeatureFlags.EnableWASMStats ||
+				prev.FeatureFlags.EnableEgressPolicy != next.FeatureFlags.EnableEgressPolicy ||
+				prev.FeatureFlags.EnableIngressBackendPolicy != next.FeatureFlags.EnableIngressBackendPolicy ||
+				prev.FeatureFlags.EnableEnvoyActiveHealthChecks != next.FeatureFlags.EnableEnvoyActiveHealthChecks
+		},
+	},
+	{
+		announcementType: announcements.MeshConfigDrainStrategyChanged,
+		changed: func(prev, next *v1alpha1.MeshConfigSpec) bool {
+			return prev.Sidecar.DrainStrategy != next.Sidecar.DrainStrategy
+		},
+	},
+}
+
+// convertedMeshConfig caches the v1alpha1 representation of a MeshConfig watched through the v1alpha2 API, so that the
+// MeshConfig is converted once per update rather than on every read
+type convertedMeshConfig struct {
+	sync.Mutex
+	source     *v1alpha2.MeshConfig
+	meshConfig *v1alpha1.MeshConfig
+}
+
+// get returns the v1alpha1 representation of a MeshConfig cached by the informer
+func (c *convertedMeshConfig) get(item interface{}) *v1alpha1.MeshConfig {
+	meshConfig, ok := item.(*v1alpha2.MeshConfig)
+	if !ok {
+		return item.(*v1alpha1.MeshConfig)
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	// The informer caches a new object on every update
+	if c.source != meshConfig {
+		c.source = meshConfig
+		c.meshConfig = v1alpha2.ConvertToV1alpha1(meshConfig)
+	}
+	return c.meshConfig
+}
+
+// toV1alpha1MeshConfig returns the v1alpha1 representation of a MeshConfig watched through either version of the API
+func toV1alpha1MeshConfig(obj interface{}) (*v1alpha1.MeshConfig, bool) {
+	switch meshConfig := obj.(type) {
+	case *v1alpha1.MeshConfig:
+		return meshConfig, true
+	case *v1alpha2.MeshConfig:
+		return v1alpha2.ConvertToV1alpha1(meshConfig), true
+	default:
+		return nil, false
 	}
 }
 
@@ -201,7 +433,7 @@ func (c *Client) getMeshConfig() *v1alpha1.MeshConfig {
 		log.Warn().Msgf("MeshConfig %s does not exist. Default config values will be used.", meshConfigCacheKey)
 		meshConfig = &v1alpha1.MeshConfig{}
 	} else {
-		meshConfig = item.(*v1alpha1.MeshConfig)
+		meshConfig = c.converted.get(item)
 	}
 
 	return meshConfig
@@ -2,6 +2,7 @@ package configurator
 
 import (
 	"context"
+	"reflect"
 	"testing"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 
 	"github.com/openservicemesh/osm/pkg/announcements"
 	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
 	"github.com/openservicemesh/osm/pkg/k8s/events"
 )
 
@@ -36,6 +38,13 @@ func TestMeshConfigEventTriggers(t *testing.T) {
 	proxyBroadcastChannel := events.Subscribe(announcements.ScheduleProxyBroadcast)
 	defer events.Unsub(proxyBroadcastChannel)
 
+	var fieldChanges []announcements.AnnouncementType
+	for _, fieldChange := range meshConfigFieldChanges {
+		fieldChanges = append(fieldChanges, fieldChange.announcementType)
+	}
+	fieldChangeChannel := events.Subscribe(fieldChanges...)
+	defer events.Unsub(fieldChangeChannel)
+
 	stop := make(chan struct{})
 	defer close(stop)
 	_ = newConfigurator(meshConfigClientSet, stop, osmNamespace, meshConfigInformerName)
@@ -58,119 +67,267 @@ func TestMeshConfigEventTriggers(t *testing.T) {
 	tests := []struct {
 		caseName             string
 		updateMeshConfigSpec func(*v1alpha1.MeshConfigSpec)
-		expectProxyBroadcast bool
+		expectedChange       announcements.AnnouncementType
 	}{
 		{
 			caseName: "EnableEgress",
 			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
 				spec.Traffic.EnableEgress = true
 			},
-			expectProxyBroadcast: true,
+			expectedChange: announcements.MeshConfigEgressChanged,
 		},
 		{
 			caseName: "EnablePermissiveTrafficPolicyMode",
 			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
 				spec.Traffic.EnablePermissiveTrafficPolicyMode = true
 			},
-			expectProxyBroadcast: true,
+			expectedChange: announcements.MeshConfigPermissiveTrafficPolicyModeChanged,
 		},
 		{
 			caseName: "UseHTTPSIngress",
 			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
 				spec.Traffic.UseHTTPSIngress = true
 			},
-			expectProxyBroadcast: true,
+			expectedChange: announcements.MeshConfigHTTPSIngressChanged,
 		},
 		{
 			caseName: "TracingEnable",
 			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
 				spec.Observability.Tracing.Enable = true
 			},
-			expectProxyBroadcast: true,
+			expectedChange: announcements.MeshConfigTracingChanged,
 		},
 		{
 			caseName: "TracingAddress",
 			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
 				spec.Observability.Tracing.Address = "jaeger.jagnamespace.cluster.svc.local"
 			},
-			expectProxyBroadcast: true,
+			expectedChange: announcements.MeshConfigTracingChanged,
 		},
 		{
 			caseName: "TracingEndpoint",
 			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
 				spec.Observability.Tracing.Endpoint = "/my/endpoint"
 			},
-			expectProxyBroadcast: true,
+			expectedChange: announcements.MeshConfigTracingChanged,
 		},
 		{
 			caseName: "TracingPort",
 			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
 				spec.Observability.Tracing.Port = 3521
 			},
-			expectProxyBroadcast: true,
+			expectedChange: announcements.MeshConfigTracingChanged,
 		},
 		{
 			caseName: "SidecarLogLevel",
 			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
 				spec.Sidecar.LogLevel = "warn"
 			},
-			expectProxyBroadcast: false,
 		},
 		{
 			caseName: "EnableDebugServer",
 			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
 				spec.Observability.EnableDebugServer = true
 			},
-			expectProxyBroadcast: false,
 		},
 		{
 			caseName: "ServiceCertValidityDuration",
 			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
 				spec.Certificate.ServiceCertValidityDuration = "30h"
 			},
-			expectProxyBroadcast: false,
 		},
 		{
 			caseName: "EnablePrivilegedInitContainer",
 			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
 				spec.Sidecar.EnablePrivilegedInitContainer = true
 			},
-			expectProxyBroadcast: false,
 		},
 		{
 			caseName: "OutboundIPRangeExclusionList",
 			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
 				spec.Traffic.OutboundIPRangeExclusionList = []string{"1.2.3.4/24", "10.0.0.1/8"}
 			},
-			expectProxyBroadcast: false,
 		},
 		{
 			caseName: "OutboundPortExclusionList",
 			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
 				spec.Traffic.OutboundPortExclusionList = []int{7070, 6080}
 			},
-			expectProxyBroadcast: false,
 		},
 		{
 			caseName: "ConfigResyncInterval",
 			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
 				spec.Sidecar.ConfigResyncInterval = "24h"
 			},
-			expectProxyBroadcast: false,
 		},
 		{
 			caseName: "InboundExternalAuthorization",
 			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
 				spec.Traffic.InboundExternalAuthorization.Enable = true
 			},
-			expectProxyBroadcast: true,
+			expectedChange: announcements.MeshConfigExternalAuthorizationChanged,
+		},
+		{
+			caseName: "InboundExternalAuthorizationAddress",
+			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
+				spec.Traffic.InboundExternalAuthorization.Address = "extauthz.osm-system.svc.cluster.local"
+			},
+			expectedChange: announcements.MeshConfigExternalAuthorizationChanged,
+		},
+		{
+			caseName: "IncludeTerminatingEndpoints",
+			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
+				spec.Traffic.IncludeTerminatingEndpoints = true
+			},
+			expectedChange: announcements.MeshConfigTerminatingEndpointsChanged,
+		},
+		{
+			caseName: "IngressGatewayCert",
+			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
+				spec.Certificate.IngressGateway = &v1alpha1.IngressGatewayCertSpec{
+					SubjectAltNames: []string{"osm-ingress.osm-system.cluster.local"},
+				}
+			},
+			expectedChange: announcements.MeshConfigIngressGatewayCertChanged,
+		},
+		{
+			caseName: "NamespaceIsolation",
+			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
+				spec.Traffic.NamespaceIsolation.Enable = true
+			},
+			expectedChange: announcements.MeshConfigNamespaceIsolationChanged,
+		},
+		{
+			caseName: "OutboundPassthrough",
+			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
+				spec.Traffic.OutboundPassthrough.AllowedPorts = []int{443}
+			},
+			expectedChange: announcements.MeshConfigOutboundPassthroughChanged,
+		},
+		{
+			caseName: "RouteRegex",
+			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
+				spec.Traffic.RouteRegex.PathAnchoring = v1alpha1.RoutePathAnchoringPrefix
+			},
+			expectedChange: announcements.MeshConfigRouteRegexChanged,
+		},
+		{
+			caseName: "HTTPSanitization",
+			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
+				spec.Traffic.HTTPSanitization.NormalizePath = true
+			},
+			expectedChange: announcements.MeshConfigHTTPSanitizationChanged,
+		},
+		{
+			caseName: "DownstreamLimits",
+			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
+				spec.Traffic.DownstreamLimits.RequestTimeout = "30s"
+			},
+			expectedChange: announcements.MeshConfigDownstreamLimitsChanged,
+		},
+		{
+			caseName: "IngressClientAddress",
+			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
+				spec.Traffic.IngressClientAddress.XFFNumTrustedHops = 1
+			},
+			expectedChange: announcements.MeshConfigIngressClientAddressChanged,
+		},
+		{
+			caseName: "PrometheusScraping",
+			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
+				spec.Observability.PrometheusScraping.EnableMTLS = true
+			},
+			expectedChange: announcements.MeshConfigPrometheusScrapingChanged,
+		},
+		{
+			caseName: "Provisioning",
+			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
+				spec.Observability.Provisioning.EnableDashboards = true
+			},
+			expectedChange: announcements.MeshConfigProvisioningChanged,
+		},
+		{
+			caseName: "EndpointFlapDampening",
+			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
+				spec.Traffic.EndpointFlapDampening.Enable = true
+			},
+			expectedChange: announcements.MeshConfigEndpointFlapDampeningChanged,
+		},
+		{
+			caseName: "DNSResolution",
+			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
+				spec.Traffic.DNSResolution.ResolveHTTPSHosts = true
+			},
+			expectedChange: announcements.MeshConfigDNSResolutionChanged,
+		},
+		{
+			caseName: "ProtocolDetectionNamespaces",
+			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
+				spec.Traffic.ProtocolDetectionNamespaces = []string{"bookstore"}
+			},
+			expectedChange: announcements.MeshConfigProtocolDetectionChanged,
+		},
+		{
+			caseName: "ProtocolDetectionTimeout",
+			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
+				spec.Traffic.ProtocolDetectionTimeout = "2s"
+			},
+			expectedChange: announcements.MeshConfigProtocolDetectionChanged,
+		},
+		{
+			caseName: "Compression",
+			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
+				spec.Traffic.Compression.Algorithms = []string{"gzip"}
+			},
+			expectedChange: announcements.MeshConfigCompressionChanged,
+		},
+		{
+			caseName: "RequestLimits",
+			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
+				spec.Traffic.RequestLimits.MaxRequestBytes = 1024
+			},
+			expectedChange: announcements.MeshConfigRequestLimitsChanged,
+		},
+		{
+			caseName: "EnableIngressHTTP3",
+			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
+				spec.FeatureFlags.EnableIngressHTTP3 = true
+			},
+			expectedChange: announcements.MeshConfigIngressHTTP3Changed,
+		},
+		{
+			caseName: "EnableDNSProxy",
+			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
+				spec.FeatureFlags.EnableDNSProxy = true
+			},
+			expectedChange: announcements.MeshConfigDNSProxyChanged,
+		},
+		{
+			caseName: "EnableScopedRoutes",
+			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
+				spec.FeatureFlags.EnableScopedRoutes = true
+			},
+			expectedChange: announcements.MeshConfigScopedRoutesChanged,
+		},
+		{
+			caseName: "EnableEgressPolicy",
+			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
+				spec.FeatureFlags.EnableEgressPolicy = true
+			},
+			expectedChange: announcements.MeshConfigFeatureFlagsChanged,
+		},
+		{
+			caseName: "DrainStrategy",
+			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
+				spec.Sidecar.DrainStrategy = "immediate"
+			},
+			expectedChange: announcements.MeshConfigDrainStrategyChanged,
 		},
 		{
 			caseName: "osmLogLevel",
 			updateMeshConfigSpec: func(spec *v1alpha1.MeshConfigSpec) {
 				spec.Observability.OSMLogLevel = "warn"
 			},
-			expectProxyBroadcast: false,
 		},
 	}
 
@@ -182,16 +339,150 @@ func TestMeshConfigEventTriggers(t *testing.T) {
 		assert.NoError(err)
 		<-confChannel
 
-		proxyEventReceived := false
+		var change announcements.AnnouncementType
 		select {
-		case <-proxyBroadcastChannel:
-			proxyEventReceived = true
+		case msg := <-fieldChangeChannel:
+			change = msg.(events.PubSubMessage).AnnouncementType
 
 		case <-time.NewTimer(300 * time.Millisecond).C:
 			// one third of a second should be plenty
 		}
-		assert.Equal(tc.expectProxyBroadcast, proxyEventReceived, tc.caseName)
+		assert.Equal(tc.expectedChange, change, tc.caseName)
+	}
+
+	// Field changes must not trigger a global proxy broadcast
+	select {
+	case <-proxyBroadcastChannel:
+		assert.Fail("unexpected global proxy broadcast on MeshConfig update")
+	default:
+	}
+}
+
+// meshConfigFieldsNotAnnounced are the MeshConfig fields whose changes are not announced, because they are read
+// when they are used rather than pushed to the proxies or the controller subsystems
+var meshConfigFieldsNotAnnounced = map[string]bool{
+	// Applied to the sidecars on injection
+	"Sidecar.EnablePrivilegedInitContainer":                  true,
+	"Sidecar.LogLevel":                                       true,
+	"Sidecar.EnvoyImage":                                     true,
+	"Sidecar.EnvoyWindowsImage":                              true,
+	"Sidecar.InitContainerImage":                             true,
+	"Sidecar.InitContainerDistroless":                        true,
+	"Sidecar.Resources":                                      true,
+	"Sidecar.AdminInterface.BindMode":                        true,
+	"Sidecar.AdminInterface.DisableDebugEndpoints":           true,
+	"Sidecar.OverloadManager.MaxHeapSizeBytes":               true,
+	"Sidecar.OverloadManager.ShrinkHeapThreshold":            true,
+	"Sidecar.OverloadManager.StopAcceptingRequestsThreshold": true,
+	"Traffic.OutboundIPRangeExclusionList":                   true,
+	"Traffic.OutboundPortExclusionList":                      true,
+	"Traffic.InboundPortExclusionList":                       true,
+	"Observability.Stats.InclusionRegexes":                   true,
+	"Observability.Stats.ExclusionRegexes":                   true,
+	"Observability.Stats.Tags":                               true,
+	"Performance.EnvoyConcurrency":                           true,
+
+	// Read by the subsystems listening to MeshConfigUpdated
+	"Sidecar.ConfigResyncInterval":                       true,
+	"Observability.OSMLogLevel":                          true,
+	"Observability.EnableDebugServer":                    true,
+	"Observability.MemoryProfiling.Enable":               true,
+	"Observability.MemoryProfiling.HeapSnapshotInterval": true,
+	"Observability.MemoryProfiling.MaxHeapSnapshots":     true,
+
+	// Read when the proxies connect, when certificates are issued or when MeshConfigs are validated
+	"Sidecar.MaxDataPlaneConnections":         true,
+	"Certificate.ServiceCertValidityDuration": true,
+	"Certificate.CertKeyBitSize":              true,
+	"Certificate.KeyAlgorithm":                true,
+	"Certificate.SubjectAltNameFormat":        true,
+	"Certificate.TrustDomain":                 true,
+	"FeatureFlags.EnableValidatingWebhook":    true,
+
+	// Read when the controller starts
+	"FeatureFlags.EnableMulticlusterMode":         true,
+	"FeatureFlags.EnableSnapshotCacheMode":        true,
+	"FeatureFlags.EnableAsyncProxyServiceMapping": true,
+	"Performance.Profile":                         true,
+	"Performance.BroadcastGracePeriod":            true,
+	"Performance.MaxBroadcastDelay":               true,
+	"Performance.WorkerPoolSize":                  true,
+	"Performance.InformerResyncInterval":          true,
+	"Performance.InformerResyncIntervals":         true,
+	"Performance.InformerWatchErrorBackoff":       true,
+	"Performance.InformerWatchErrorMaxBackoff":    true,
+}
+
+// TestMeshConfigFieldChangesCoverSpec walks the MeshConfig spec, so that a field can't be added to the spec without
+// its changes being announced or it being listed in meshConfigFieldsNotAnnounced
+func TestMeshConfigFieldChangesCoverSpec(t *testing.T) {
+	assert := tassert.New(t)
+
+	// The inner configuration changes of ExtAuthz are only announced when it is enabled
+	newSpec := func() *v1alpha1.MeshConfigSpec {
+		spec := &v1alpha1.MeshConfigSpec{}
+		spec.Traffic.InboundExternalAuthorization.Enable = true
+		return spec
+	}
+	prev := newSpec()
+
+	var walk func(path string, field func(*v1alpha1.MeshConfigSpec) reflect.Value, typ reflect.Type)
+	walk = func(path string, field func(*v1alpha1.MeshConfigSpec) reflect.Value, typ reflect.Type) {
+		if typ.Kind() == reflect.Struct && typ.PkgPath() == reflect.TypeOf(v1alpha1.MeshConfigSpec{}).PkgPath() {
+			for i := 0; i < typ.NumField(); i++ {
+				i := i
+				childPath := typ.Field(i).Name
+				if path != "" {
+					childPath = path + "." + childPath
+				}
+				walk(childPath, func(spec *v1alpha1.MeshConfigSpec) reflect.Value {
+					return field(spec).Field(i)
+				}, typ.Field(i).Type)
+			}
+			return
+		}
+
+		announced := false
+		next := newSpec()
+		if !changeMeshConfigField(field(next)) {
+			assert.True(meshConfigFieldsNotAnnounced[path], "MeshConfig field %s can't be changed by the test", path)
+			return
+		}
+		for _, fieldChange := range meshConfigFieldChanges {
+			announced = announced || fieldChange.changed(prev, next)
+		}
+		if meshConfigFieldsNotAnnounced[path] {
+			assert.False(announced, "MeshConfig field %s is announced but listed as not announced", path)
+		} else {
+			assert.True(announced, "MeshConfig field %s is neither announced nor listed as not announced", path)
+		}
 	}
+	walk("", func(spec *v1alpha1.MeshConfigSpec) reflect.Value { return reflect.ValueOf(spec).Elem() }, reflect.TypeOf(v1alpha1.MeshConfigSpec{}))
+}
+
+// changeMeshConfigField changes the value of a MeshConfig field, and returns whether it knows how to change it
+func changeMeshConfigField(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(!v.Bool())
+	case reflect.String:
+		v.SetString(v.String() + "-changed")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(v.Int() + 1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(v.Uint() + 1)
+	case reflect.Slice:
+		v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		m.SetMapIndex(reflect.Zero(v.Type().Key()), reflect.Zero(v.Type().Elem()))
+		v.Set(m)
+	case reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+	default:
+		return false
+	}
+	return true
 }
 
 func TestGetMeshConfig(t *testing.T) {
@@ -205,3 +496,78 @@ func TestGetMeshConfig(t *testing.T) {
 	// returns empty MeshConfig if informer cache is empty
 	assert.Equal(meshConfig, &v1alpha1.MeshConfig{})
 }
+
+func TestGetMeshConfigV1alpha2(t *testing.T) {
+	assert := tassert.New(t)
+
+	meshConfig := &v1alpha2.MeshConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: osmNamespace,
+			Name:      osmMeshConfigName,
+		},
+		Spec: v1alpha2.MeshConfigSpec{
+			Traffic: v1alpha2.TrafficSpec{
+				EnableEgress: true,
+				InboundHTTP: v1alpha2.InboundHTTPSpec{
+					RequestLimits: v1alpha2.RequestLimitsSpec{MaxRequestBytes: 1024},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name                    string
+		servedResources         []*metav1.APIResourceList
+		expectedMaxRequestBytes uint32
+	}{
+		{
+			name: "v1alpha2 API served",
+			servedResources: []*metav1.APIResourceList{{
+				GroupVersion: v1alpha2.SchemeGroupVersion.String(),
+				APIResources: []metav1.APIResource{{Name: "meshconfigs"}},
+			}},
+			expectedMaxRequestBytes: 1024,
+		},
+		{
+			name:                    "v1alpha2 API not served",
+			servedResources:         nil,
+			expectedMaxRequestBytes: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			meshConfigClient := fakeConfig.NewSimpleClientset(meshConfig)
+			meshConfigClient.Resources = tc.servedResources
+
+			stop := make(chan struct{})
+			defer close(stop)
+			client := newConfigurator(meshConfigClient, stop, osmNamespace, osmMeshConfigName)
+
+			assert.Equal(tc.expectedMaxRequestBytes, client.GetRequestLimitsConfig().MaxRequestBytes)
+			if tc.expectedMaxRequestBytes != 0 {
+				assert.True(client.IsEgressEnabled())
+				// The converted MeshConfig is cached until the MeshConfig is updated
+				assert.Same(client.getMeshConfig(), client.getMeshConfig())
+			}
+		})
+	}
+}
+
+func TestToV1alpha1MeshConfig(t *testing.T) {
+	assert := tassert.New(t)
+
+	meshConfig, ok := toV1alpha1MeshConfig(&v1alpha1.MeshConfig{Spec: v1alpha1.MeshConfigSpec{Traffic: v1alpha1.TrafficSpec{EnableEgress: true}}})
+	assert.True(ok)
+	assert.True(meshConfig.Spec.Traffic.EnableEgress)
+
+	meshConfig, ok = toV1alpha1MeshConfig(&v1alpha2.MeshConfig{Spec: v1alpha2.MeshConfigSpec{Traffic: v1alpha2.TrafficSpec{
+		InboundHTTP: v1alpha2.InboundHTTPSpec{Compression: v1alpha2.CompressionSpec{Enable: true}},
+	}}})
+	assert.True(ok)
+	assert.True(meshConfig.Spec.Traffic.Compression.Enable)
+
+	meshConfig, ok = toV1alpha1MeshConfig(nil)
+	assert.False(ok)
+	assert.Nil(meshConfig)
+}
@@ -10,8 +10,10 @@ import (
 	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
 
 	"github.com/openservicemesh/osm/pkg/auth"
+	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/errcode"
+	"github.com/openservicemesh/osm/pkg/identity"
 )
 
 const (
@@ -21,11 +23,43 @@ const (
 	// defaultCertKeyBitSize is the default certificate key bit size
 	defaultCertKeyBitSize = 2048
 
-	// minCertKeyBitSize is the minimum certificate key bit size
-	minCertKeyBitSize = 2048
+	// MinCertKeyBitSize is the minimum certificate key bit size
+	MinCertKeyBitSize = 2048
 
-	// maxCertKeyBitSize is the maximum certificate key bit size
-	maxCertKeyBitSize = 4096
+	// MaxCertKeyBitSize is the maximum certificate key bit size
+	MaxCertKeyBitSize = 4096
+
+	// defaultProtocolDetectionTimeout is the default time proxies wait to detect the application protocol of a connection
+	defaultProtocolDetectionTimeout = 250 * time.Millisecond
+
+	// MinProtocolDetectionTimeout is the minimum time proxies wait to detect the application protocol of a connection
+	MinProtocolDetectionTimeout = 100 * time.Millisecond
+
+	// MaxProtocolDetectionTimeout is the maximum time proxies wait to detect the application protocol of a connection
+	MaxProtocolDetectionTimeout = 1 * time.Second
+
+	// defaultHeapSnapshotInterval is the default interval at which heap snapshots of the controller are taken
+	defaultHeapSnapshotInterval = 5 * time.Minute
+
+	// MinHeapSnapshotInterval is the minimum interval at which heap snapshots of the controller are taken
+	MinHeapSnapshotInterval = 30 * time.Second
+
+	// defaultMaxHeapSnapshots is the default number of heap snapshots of the controller kept in memory
+	defaultMaxHeapSnapshots = 12
+
+	// MaxHeapSnapshots is the maximum number of heap snapshots of the controller kept in memory
+	MaxHeapSnapshots = 100
+
+	// defaultPrometheusServiceAccount is the service account of the Prometheus server allowed to scrape the proxy
+	// sidecars over mTLS by default, within the OSM namespace
+	defaultPrometheusServiceAccount = "osm-prometheus"
+
+	// defaultPrometheusClientCertSecret is the default name of the secret the certificate of the Prometheus server is
+	// stored in, within the OSM namespace
+	defaultPrometheusClientCertSecret = "osm-prometheus-client-cert"
+
+	// defaultDashboardDatasource is the name of the Grafana datasource queried by the provisioned dashboards by default
+	defaultDashboardDatasource = "Prometheus"
 )
 
 // The functions in this file implement the configurator.Configurator interface
@@ -76,6 +110,45 @@ func (c *Client) IsDebugServerEnabled() bool {
 	return c.getMeshConfig().Spec.Observability.EnableDebugServer
 }
 
+// IsMemoryProfilingEnabled returns whether the mutex and block profiles of the controller are sampled, and heap
+// snapshots of the controller are periodically taken
+func (c *Client) IsMemoryProfilingEnabled() bool {
+	return c.getMeshConfig().Spec.Observability.MemoryProfiling.Enable
+}
+
+// GetHeapSnapshotInterval returns the interval at which heap snapshots of the controller are taken, and a default in
+// case of invalid or out of range duration
+func (c *Client) GetHeapSnapshotInterval() time.Duration {
+	intervalStr := c.getMeshConfig().Spec.Observability.MemoryProfiling.HeapSnapshotInterval
+	if intervalStr == "" {
+		return defaultHeapSnapshotInterval
+	}
+
+	interval, err := time.ParseDuration(intervalStr)
+	if err != nil || interval < MinHeapSnapshotInterval {
+		log.Error().Err(err).Msgf("Invalid heap snapshot interval %s, must be at least %s", intervalStr, MinHeapSnapshotInterval)
+		return defaultHeapSnapshotInterval
+	}
+
+	return interval
+}
+
+// GetMaxHeapSnapshots returns the number of most recent heap snapshots of the controller kept in memory, and a
+// default in case of out of range value
+func (c *Client) GetMaxHeapSnapshots() int {
+	maxSnapshots := c.getMeshConfig().Spec.Observability.MemoryProfiling.MaxHeapSnapshots
+	if maxSnapshots == 0 {
+		return defaultMaxHeapSnapshots
+	}
+
+	if maxSnapshots < 0 || maxSnapshots > MaxHeapSnapshots {
+		log.Error().Msgf("Invalid max heap snapshots %d, must be between 1 and %d", maxSnapshots, MaxHeapSnapshots)
+		return defaultMaxHeapSnapshots
+	}
+
+	return maxSnapshots
+}
+
 // IsTracingEnabled returns whether tracing is enabled
 func (c *Client) IsTracingEnabled() bool {
 	return c.getMeshConfig().Spec.Observability.Tracing.Enable
@@ -115,7 +188,7 @@ func (c *Client) UseHTTPSIngress() bool {
 
 // GetMaxDataPlaneConnections returns the max data plane connections allowed, 0 if disabled
 func (c *Client) GetMaxDataPlaneConnections() int {
-	return c.getMeshConfig().Spec.Sidecar.MaxDataPlaneConnections
+	return c.GetPerformanceSettings().MaxDataPlaneConnections
 }
 
 // GetEnvoyLogLevel returns the envoy log level
@@ -169,7 +242,7 @@ func (c *Client) GetServiceCertValidityPeriod() time.Duration {
 // GetCertKeyBitSize returns the certificate key bit size to be used
 func (c *Client) GetCertKeyBitSize() int {
 	bitSize := c.getMeshConfig().Spec.Certificate.CertKeyBitSize
-	if bitSize < minCertKeyBitSize || bitSize > maxCertKeyBitSize {
+	if bitSize < MinCertKeyBitSize || bitSize > MaxCertKeyBitSize {
 		log.Error().Msgf("Invalid key bit size: %d", bitSize)
 		return defaultCertKeyBitSize
 	}
@@ -177,6 +250,53 @@ func (c *Client) GetCertKeyBitSize() int {
 	return bitSize
 }
 
+// GetCertKeyAlgorithm returns the algorithm of the keys of the service certificates
+func (c *Client) GetCertKeyAlgorithm() string {
+	switch algorithm := c.getMeshConfig().Spec.Certificate.KeyAlgorithm; algorithm {
+	case certificate.KeyAlgorithmRSA, certificate.KeyAlgorithmECDSA:
+		return algorithm
+	case "":
+		return certificate.KeyAlgorithmRSA
+	default:
+		log.Error().Msgf("Invalid certificate key algorithm: %s", algorithm)
+		return certificate.KeyAlgorithmRSA
+	}
+}
+
+// GetSPIFFETrustDomain returns the trust domain of the SPIFFE IDs carried by the service certificates, if any. The
+// certificates carry SPIFFE IDs in the trust domain of the mesh instance, cluster.local if unset, when their SAN format
+// includes SPIFFE IDs.
+func (c *Client) GetSPIFFETrustDomain() string {
+	certSpec := c.getMeshConfig().Spec.Certificate
+	switch certSpec.SubjectAltNameFormat {
+	case certificate.SubjectAltNameFormatDNSSPIFFE:
+		if certSpec.TrustDomain != "" {
+			return certSpec.TrustDomain
+		}
+		return identity.ClusterLocalTrustDomain
+	case "", certificate.SubjectAltNameFormatDNS:
+		return ""
+	default:
+		log.Error().Msgf("Invalid certificate SAN format: %s", certSpec.SubjectAltNameFormat)
+		return ""
+	}
+}
+
+// GetTrustDomain returns the trust domain of the certificates issued by the mesh instance, if any
+func (c *Client) GetTrustDomain() string {
+	return c.getMeshConfig().Spec.Certificate.TrustDomain
+}
+
+// GetTrustDomainAliases returns the trust domains the certificates of upstream service identities are also accepted from
+func (c *Client) GetTrustDomainAliases() []string {
+	return c.getMeshConfig().Spec.Certificate.TrustDomainAliases
+}
+
+// GetFederatedTrustDomains returns the external trust domains federated with the mesh
+func (c *Client) GetFederatedTrustDomains() []configv1alpha1.FederatedTrustDomainSpec {
+	return c.getMeshConfig().Spec.Certificate.FederatedTrustDomains
+}
+
 // GetOutboundIPRangeExclusionList returns the list of IP ranges of the form x.x.x.x/y to exclude from outbound sidecar interception
 func (c *Client) GetOutboundIPRangeExclusionList() []string {
 	return c.getMeshConfig().Spec.Traffic.OutboundIPRangeExclusionList
@@ -197,6 +317,11 @@ func (c *Client) IsPrivilegedInitContainer() bool {
 	return c.getMeshConfig().Spec.Sidecar.EnablePrivilegedInitContainer
 }
 
+// IsInitContainerDistroless returns whether the init container image is a distroless image only providing iptables-restore
+func (c *Client) IsInitContainerDistroless() bool {
+	return c.getMeshConfig().Spec.Sidecar.InitContainerDistroless
+}
+
 // GetConfigResyncInterval returns the duration for resync interval.
 // If error or non-parsable value, returns 0 duration
 func (c *Client) GetConfigResyncInterval() time.Duration {
@@ -240,6 +365,147 @@ func (c *Client) GetFeatureFlags() configv1alpha1.FeatureFlags {
 	return c.getMeshConfig().Spec.FeatureFlags
 }
 
+// GetEndpointFlapDampeningConfig returns the configuration used to pin out endpoints whose readiness flaps
+func (c *Client) GetEndpointFlapDampeningConfig() configv1alpha1.EndpointFlapDampeningSpec {
+	return c.getMeshConfig().Spec.Traffic.EndpointFlapDampening
+}
+
+// GetDNSResolutionConfig returns the configuration used by proxies to resolve the addresses of clusters using DNS
+func (c *Client) GetDNSResolutionConfig() configv1alpha1.DNSResolutionSpec {
+	return c.getMeshConfig().Spec.Traffic.DNSResolution
+}
+
+// IsProtocolDetectionEnabled returns whether the HTTP ports of services in the given namespace detect the application
+// protocol of each connection
+func (c *Client) IsProtocolDetectionEnabled(namespace string) bool {
+	for _, ns := range c.getMeshConfig().Spec.Traffic.ProtocolDetectionNamespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// GetProtocolDetectionTimeout returns how long proxies wait for the first bytes of a connection to detect its
+// application protocol, and a default in case of invalid or out of range duration
+func (c *Client) GetProtocolDetectionTimeout() time.Duration {
+	timeoutStr := c.getMeshConfig().Spec.Traffic.ProtocolDetectionTimeout
+	if timeoutStr == "" {
+		return defaultProtocolDetectionTimeout
+	}
+
+	timeout, err := time.ParseDuration(timeoutStr)
+	if err != nil || timeout < MinProtocolDetectionTimeout || timeout > MaxProtocolDetectionTimeout {
+		log.Error().Err(err).Msgf("Invalid protocol detection timeout %s, must be between %s and %s", timeoutStr, MinProtocolDetectionTimeout, MaxProtocolDetectionTimeout)
+		return defaultProtocolDetectionTimeout
+	}
+
+	return timeout
+}
+
+// GetCompressionConfig returns the configuration used by proxies to compress the responses of HTTP services
+func (c *Client) GetCompressionConfig() configv1alpha1.CompressionSpec {
+	return c.getMeshConfig().Spec.Traffic.Compression
+}
+
+// GetRequestLimitsConfig returns the limits on the size of the requests to HTTP services
+func (c *Client) GetRequestLimitsConfig() configv1alpha1.RequestLimitsSpec {
+	return c.getMeshConfig().Spec.Traffic.RequestLimits
+}
+
+// GetClientCertDetailsConfig returns how the details of client certificates are forwarded to applications
+func (c *Client) GetClientCertDetailsConfig() configv1alpha1.ClientCertDetailsSpec {
+	return c.getMeshConfig().Spec.Traffic.ClientCertDetails
+}
+
+// GetRBACAuditConfig returns how the authorization decisions of the RBAC policies are audited
+func (c *Client) GetRBACAuditConfig() configv1alpha1.RBACAuditSpec {
+	return c.getMeshConfig().Spec.Traffic.RBACAudit
+}
+
+// GetRouteRegexConfig returns how the regular expressions of the route matches of HTTPRouteGroups are programmed
+func (c *Client) GetRouteRegexConfig() configv1alpha1.RouteRegexSpec {
+	return c.getMeshConfig().Spec.Traffic.RouteRegex
+}
+
+// GetHTTPSanitizationConfig returns how the paths and headers of HTTP requests are normalized and sanitized
+func (c *Client) GetHTTPSanitizationConfig() configv1alpha1.HTTPSanitizationSpec {
+	return c.getMeshConfig().Spec.Traffic.HTTPSanitization
+}
+
+// GetDownstreamLimitsConfig returns the limits on the downstream connections and requests to services
+func (c *Client) GetDownstreamLimitsConfig() configv1alpha1.DownstreamLimitsSpec {
+	return c.getMeshConfig().Spec.Traffic.DownstreamLimits
+}
+
+// GetIngressClientAddressConfig returns how the address of the clients of ingress requests is determined
+func (c *Client) GetIngressClientAddressConfig() configv1alpha1.IngressClientAddressSpec {
+	return c.getMeshConfig().Spec.Traffic.IngressClientAddress
+}
+
+// IsNamespaceIsolationEnabled determines whether the outbound traffic of the namespaces is restricted to their own
+// namespace and the namespaces allowed by their NamespaceIsolation policies
+func (c *Client) IsNamespaceIsolationEnabled() bool {
+	return c.getMeshConfig().Spec.Traffic.NamespaceIsolation.Enable
+}
+
+// GetOutboundPassthroughConfig returns the destinations allowed through the outbound passthrough cluster
+func (c *Client) GetOutboundPassthroughConfig() configv1alpha1.OutboundPassthroughSpec {
+	return c.getMeshConfig().Spec.Traffic.OutboundPassthrough
+}
+
+// IncludeTerminatingEndpoints determines whether the serving endpoints of terminating pods are programmed as draining
+func (c *Client) IncludeTerminatingEndpoints() bool {
+	return c.getMeshConfig().Spec.Traffic.IncludeTerminatingEndpoints
+}
+
+// GetAdminInterfaceConfig returns the exposure of the admin interface of proxy sidecars
+func (c *Client) GetAdminInterfaceConfig() configv1alpha1.AdminInterfaceSpec {
+	return c.getMeshConfig().Spec.Sidecar.AdminInterface
+}
+
+// GetOverloadManagerConfig returns the configuration of the overload manager of proxy sidecars
+func (c *Client) GetOverloadManagerConfig() configv1alpha1.OverloadManagerSpec {
+	return c.getMeshConfig().Spec.Sidecar.OverloadManager
+}
+
+// GetEnvoyDrainStrategy returns the strategy of proxy sidecars to drain the connections of their listeners
+func (c *Client) GetEnvoyDrainStrategy() string {
+	drainStrategy := c.getMeshConfig().Spec.Sidecar.DrainStrategy
+	if drainStrategy != "" {
+		return drainStrategy
+	}
+	return constants.DefaultEnvoyDrainStrategy
+}
+
+// GetStatsConfig returns the configuration of the stats generated by proxy sidecars
+func (c *Client) GetStatsConfig() configv1alpha1.StatsSpec {
+	return c.getMeshConfig().Spec.Observability.Stats
+}
+
+// GetPrometheusScrapingConfig returns how the metrics of the proxy sidecars and the control plane are scraped by
+// Prometheus, with the defaults of its unset fields applied
+func (c *Client) GetPrometheusScrapingConfig() configv1alpha1.PrometheusScrapingSpec {
+	scraping := c.getMeshConfig().Spec.Observability.PrometheusScraping
+	if scraping.ClientIdentity == "" {
+		scraping.ClientIdentity = fmt.Sprintf("%s.%s", defaultPrometheusServiceAccount, c.osmNamespace)
+	}
+	if scraping.ClientCertSecret == "" {
+		scraping.ClientCertSecret = defaultPrometheusClientCertSecret
+	}
+	return scraping
+}
+
+// GetProvisioningConfig returns the Grafana dashboards and Prometheus alert rules provisioned by the OSM controller,
+// with the defaults of its unset fields applied
+func (c *Client) GetProvisioningConfig() configv1alpha1.ProvisioningSpec {
+	provisioning := c.getMeshConfig().Spec.Observability.Provisioning
+	if provisioning.DashboardDatasource == "" {
+		provisioning.DashboardDatasource = defaultDashboardDatasource
+	}
+	return provisioning
+}
+
 // GetOSMLogLevel returns the configured OSM log level
 func (c *Client) GetOSMLogLevel() string {
 	return c.getMeshConfig().Spec.Observability.OSMLogLevel
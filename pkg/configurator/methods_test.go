@@ -14,6 +14,7 @@ import (
 	testclient "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned/fake"
 
 	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/k8s/events"
 )
@@ -163,6 +164,109 @@ func TestCreateUpdateConfig(t *testing.T) {
 				assert.False(cfg.IsDebugServerEnabled())
 			},
 		},
+		{
+			name: "IsMemoryProfilingEnabled",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Observability: v1alpha1.ObservabilitySpec{
+					MemoryProfiling: v1alpha1.MemoryProfilingSpec{
+						Enable: true,
+					},
+				},
+			},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.True(cfg.IsMemoryProfilingEnabled())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Observability: v1alpha1.ObservabilitySpec{
+					MemoryProfiling: v1alpha1.MemoryProfilingSpec{
+						Enable: false,
+					},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.False(cfg.IsMemoryProfilingEnabled())
+			},
+		},
+		{
+			name:                  "GetHeapSnapshotInterval",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(defaultHeapSnapshotInterval, cfg.GetHeapSnapshotInterval())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Observability: v1alpha1.ObservabilitySpec{
+					MemoryProfiling: v1alpha1.MemoryProfilingSpec{
+						HeapSnapshotInterval: "1m",
+					},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(time.Minute, cfg.GetHeapSnapshotInterval())
+			},
+		},
+		{
+			name: "GetHeapSnapshotInterval out of range",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Observability: v1alpha1.ObservabilitySpec{
+					MemoryProfiling: v1alpha1.MemoryProfilingSpec{
+						HeapSnapshotInterval: "1s",
+					},
+				},
+			},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(defaultHeapSnapshotInterval, cfg.GetHeapSnapshotInterval())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Observability: v1alpha1.ObservabilitySpec{
+					MemoryProfiling: v1alpha1.MemoryProfilingSpec{
+						HeapSnapshotInterval: "invalid",
+					},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(defaultHeapSnapshotInterval, cfg.GetHeapSnapshotInterval())
+			},
+		},
+		{
+			name:                  "GetMaxHeapSnapshots",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(defaultMaxHeapSnapshots, cfg.GetMaxHeapSnapshots())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Observability: v1alpha1.ObservabilitySpec{
+					MemoryProfiling: v1alpha1.MemoryProfilingSpec{
+						MaxHeapSnapshots: 3,
+					},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(3, cfg.GetMaxHeapSnapshots())
+			},
+		},
+		{
+			name: "GetMaxHeapSnapshots out of range",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Observability: v1alpha1.ObservabilitySpec{
+					MemoryProfiling: v1alpha1.MemoryProfilingSpec{
+						MaxHeapSnapshots: MaxHeapSnapshots + 1,
+					},
+				},
+			},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(defaultMaxHeapSnapshots, cfg.GetMaxHeapSnapshots())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Observability: v1alpha1.ObservabilitySpec{
+					MemoryProfiling: v1alpha1.MemoryProfilingSpec{
+						MaxHeapSnapshots: -1,
+					},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(defaultMaxHeapSnapshots, cfg.GetMaxHeapSnapshots())
+			},
+		},
 		{
 			name: "IsTracingEnabled",
 			initialMeshConfigData: &v1alpha1.MeshConfigSpec{
@@ -297,6 +401,129 @@ func TestCreateUpdateConfig(t *testing.T) {
 				assert.Equal(defaultCertKeyBitSize, cfg.GetCertKeyBitSize())
 			},
 		},
+		{
+			name:                  "GetCertKeyAlgorithm",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(certificate.KeyAlgorithmRSA, cfg.GetCertKeyAlgorithm())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Certificate: v1alpha1.CertificateSpec{
+					KeyAlgorithm: "ecdsa",
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(certificate.KeyAlgorithmECDSA, cfg.GetCertKeyAlgorithm())
+			},
+		},
+		{
+			name: "GetCertKeyAlgorithm with an invalid algorithm",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Certificate: v1alpha1.CertificateSpec{
+					KeyAlgorithm: "dsa",
+				},
+			},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(certificate.KeyAlgorithmRSA, cfg.GetCertKeyAlgorithm())
+			},
+		},
+		{
+			name:                  "GetSPIFFETrustDomain",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal("", cfg.GetSPIFFETrustDomain())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Certificate: v1alpha1.CertificateSpec{
+					SubjectAltNameFormat: "dns_spiffe",
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal("cluster.local", cfg.GetSPIFFETrustDomain())
+			},
+		},
+		{
+			name: "GetSPIFFETrustDomain with a trust domain",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Certificate: v1alpha1.CertificateSpec{
+					SubjectAltNameFormat: "dns_spiffe",
+					TrustDomain:          "example.org",
+				},
+			},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal("example.org", cfg.GetSPIFFETrustDomain())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Certificate: v1alpha1.CertificateSpec{
+					SubjectAltNameFormat: "dns",
+					TrustDomain:          "example.org",
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal("", cfg.GetSPIFFETrustDomain())
+			},
+		},
+		{
+			name:                  "GetTrustDomain",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal("", cfg.GetTrustDomain())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Certificate: v1alpha1.CertificateSpec{
+					TrustDomain: "cluster-a.example.com",
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal("cluster-a.example.com", cfg.GetTrustDomain())
+			},
+		},
+		{
+			name:                  "GetTrustDomainAliases",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Nil(cfg.GetTrustDomainAliases())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Certificate: v1alpha1.CertificateSpec{
+					TrustDomainAliases: []string{"old.example.com"},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal([]string{"old.example.com"}, cfg.GetTrustDomainAliases())
+			},
+		},
+		{
+			name:                  "GetFederatedTrustDomains",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Nil(cfg.GetFederatedTrustDomains())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Certificate: v1alpha1.CertificateSpec{
+					FederatedTrustDomains: []v1alpha1.FederatedTrustDomainSpec{
+						{
+							TrustDomain: "example.org",
+							TrustBundle: "bundle",
+							IdentityMappings: []v1alpha1.FederatedIdentityMappingSpec{
+								{ExternalIdentity: "spiffe://example.org/ns/default/sa/bookbuyer", ServiceAccount: "bookbuyer", Namespace: "default"},
+							},
+						},
+					},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal([]v1alpha1.FederatedTrustDomainSpec{
+					{
+						TrustDomain: "example.org",
+						TrustBundle: "bundle",
+						IdentityMappings: []v1alpha1.FederatedIdentityMappingSpec{
+							{ExternalIdentity: "spiffe://example.org/ns/default/sa/bookbuyer", ServiceAccount: "bookbuyer", Namespace: "default"},
+						},
+					},
+				}, cfg.GetFederatedTrustDomains())
+			},
+		},
 		{
 			name:                  "GetOutboundIPRangeExclusionList",
 			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
@@ -361,6 +588,25 @@ func TestCreateUpdateConfig(t *testing.T) {
 				assert.False(cfg.IsPrivilegedInitContainer())
 			},
 		},
+		{
+			name: "IsInitContainerDistroless",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Sidecar: v1alpha1.SidecarSpec{
+					InitContainerDistroless: true,
+				},
+			},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.True(cfg.IsInitContainerDistroless())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Sidecar: v1alpha1.SidecarSpec{
+					InitContainerDistroless: false,
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.False(cfg.IsInitContainerDistroless())
+			},
+		},
 		{
 			name:                  "GetResyncInterval",
 			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
@@ -410,6 +656,475 @@ func TestCreateUpdateConfig(t *testing.T) {
 				assert.Equal(1000, cfg.GetMaxDataPlaneConnections())
 			},
 		},
+		{
+			name:                  "GetEndpointFlapDampeningConfig",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.EndpointFlapDampeningSpec{}, cfg.GetEndpointFlapDampeningConfig())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Traffic: v1alpha1.TrafficSpec{
+					EndpointFlapDampening: v1alpha1.EndpointFlapDampeningSpec{
+						Enable:         true,
+						Window:         "30s",
+						MaxTransitions: 3,
+					},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.EndpointFlapDampeningSpec{
+					Enable:         true,
+					Window:         "30s",
+					MaxTransitions: 3,
+				}, cfg.GetEndpointFlapDampeningConfig())
+			},
+		},
+		{
+			name:                  "GetDNSResolutionConfig",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.DNSResolutionSpec{}, cfg.GetDNSResolutionConfig())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Traffic: v1alpha1.TrafficSpec{
+					DNSResolution: v1alpha1.DNSResolutionSpec{
+						ClusterType:   "logical_dns",
+						RefreshRate:   "10s",
+						RespectDNSTTL: true,
+						LookupFamily:  "v4_only",
+					},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.DNSResolutionSpec{
+					ClusterType:   "logical_dns",
+					RefreshRate:   "10s",
+					RespectDNSTTL: true,
+					LookupFamily:  "v4_only",
+				}, cfg.GetDNSResolutionConfig())
+			},
+		},
+		{
+			name:                  "GetCompressionConfig",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.CompressionSpec{}, cfg.GetCompressionConfig())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Traffic: v1alpha1.TrafficSpec{
+					Compression: v1alpha1.CompressionSpec{
+						Enable:           true,
+						Algorithms:       []string{"brotli", "gzip"},
+						MinContentLength: 1024,
+					},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.CompressionSpec{
+					Enable:           true,
+					Algorithms:       []string{"brotli", "gzip"},
+					MinContentLength: 1024,
+				}, cfg.GetCompressionConfig())
+			},
+		},
+		{
+			name:                  "GetRequestLimitsConfig",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.RequestLimitsSpec{}, cfg.GetRequestLimitsConfig())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Traffic: v1alpha1.TrafficSpec{
+					RequestLimits: v1alpha1.RequestLimitsSpec{
+						MaxRequestBytes:     1048576,
+						MaxRequestHeadersKb: 96,
+					},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.RequestLimitsSpec{
+					MaxRequestBytes:     1048576,
+					MaxRequestHeadersKb: 96,
+				}, cfg.GetRequestLimitsConfig())
+			},
+		},
+		{
+			name:                  "GetClientCertDetailsConfig",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.ClientCertDetailsSpec{}, cfg.GetClientCertDetailsConfig())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Traffic: v1alpha1.TrafficSpec{
+					ClientCertDetails: v1alpha1.ClientCertDetailsSpec{
+						ForwardClientCertDetails:    "sanitize_set",
+						SetCurrentClientCertDetails: v1alpha1.SetCurrentClientCertDetailsSpec{URI: true},
+					},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.ClientCertDetailsSpec{
+					ForwardClientCertDetails:    "sanitize_set",
+					SetCurrentClientCertDetails: v1alpha1.SetCurrentClientCertDetailsSpec{URI: true},
+				}, cfg.GetClientCertDetailsConfig())
+			},
+		},
+		{
+			name:                  "GetRBACAuditConfig",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.RBACAuditSpec{}, cfg.GetRBACAuditConfig())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Traffic: v1alpha1.TrafficSpec{
+					RBACAudit: v1alpha1.RBACAuditSpec{
+						ShadowMode:      true,
+						EnableDenialLog: true,
+					},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.RBACAuditSpec{ShadowMode: true, EnableDenialLog: true}, cfg.GetRBACAuditConfig())
+			},
+		},
+		{
+			name:                  "GetRouteRegexConfig",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.RouteRegexSpec{}, cfg.GetRouteRegexConfig())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Traffic: v1alpha1.TrafficSpec{
+					RouteRegex: v1alpha1.RouteRegexSpec{
+						MaxProgramSize: 200,
+						PathAnchoring:  v1alpha1.RoutePathAnchoringPrefix,
+					},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.RouteRegexSpec{MaxProgramSize: 200, PathAnchoring: v1alpha1.RoutePathAnchoringPrefix}, cfg.GetRouteRegexConfig())
+			},
+		},
+		{
+			name:                  "GetHTTPSanitizationConfig",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.HTTPSanitizationSpec{}, cfg.GetHTTPSanitizationConfig())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Traffic: v1alpha1.TrafficSpec{
+					HTTPSanitization: v1alpha1.HTTPSanitizationSpec{
+						NormalizePath:                true,
+						MergeSlashes:                 true,
+						PathWithEscapedSlashesAction: v1alpha1.EscapedSlashesUnescapeAndRedirect,
+						HeadersWithUnderscoresAction: v1alpha1.HeadersWithUnderscoresDropHeader,
+					},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.HTTPSanitizationSpec{
+					NormalizePath:                true,
+					MergeSlashes:                 true,
+					PathWithEscapedSlashesAction: v1alpha1.EscapedSlashesUnescapeAndRedirect,
+					HeadersWithUnderscoresAction: v1alpha1.HeadersWithUnderscoresDropHeader,
+				}, cfg.GetHTTPSanitizationConfig())
+			},
+		},
+		{
+			name:                  "GetDownstreamLimitsConfig",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.DownstreamLimitsSpec{}, cfg.GetDownstreamLimitsConfig())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Traffic: v1alpha1.TrafficSpec{
+					DownstreamLimits: v1alpha1.DownstreamLimitsSpec{
+						MaxConnections:    1024,
+						IdleTimeout:       "5m",
+						RequestTimeout:    "30s",
+						StreamIdleTimeout: "1m",
+					},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.DownstreamLimitsSpec{
+					MaxConnections:    1024,
+					IdleTimeout:       "5m",
+					RequestTimeout:    "30s",
+					StreamIdleTimeout: "1m",
+				}, cfg.GetDownstreamLimitsConfig())
+			},
+		},
+		{
+			name:                  "GetIngressClientAddressConfig",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.IngressClientAddressSpec{}, cfg.GetIngressClientAddressConfig())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Traffic: v1alpha1.TrafficSpec{
+					IngressClientAddress: v1alpha1.IngressClientAddressSpec{
+						UseRemoteAddress:  true,
+						XFFNumTrustedHops: 2,
+						InternalAddressConfig: v1alpha1.InternalAddressConfigSpec{
+							UnixSockets: true,
+						},
+					},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.IngressClientAddressSpec{
+					UseRemoteAddress:  true,
+					XFFNumTrustedHops: 2,
+					InternalAddressConfig: v1alpha1.InternalAddressConfigSpec{
+						UnixSockets: true,
+					},
+				}, cfg.GetIngressClientAddressConfig())
+			},
+		},
+		{
+			name:                  "IsNamespaceIsolationEnabled",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.False(cfg.IsNamespaceIsolationEnabled())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Traffic: v1alpha1.TrafficSpec{
+					NamespaceIsolation: v1alpha1.NamespaceIsolationSpec{
+						Enable: true,
+					},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.True(cfg.IsNamespaceIsolationEnabled())
+			},
+		},
+		{
+			name:                  "GetOutboundPassthroughConfig",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.OutboundPassthroughSpec{}, cfg.GetOutboundPassthroughConfig())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Traffic: v1alpha1.TrafficSpec{
+					OutboundPassthrough: v1alpha1.OutboundPassthroughSpec{
+						AllowedPorts:    []int{443},
+						AllowedIPRanges: []string{"10.0.0.0/8"},
+					},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.OutboundPassthroughSpec{
+					AllowedPorts:    []int{443},
+					AllowedIPRanges: []string{"10.0.0.0/8"},
+				}, cfg.GetOutboundPassthroughConfig())
+			},
+		},
+		{
+			name:                  "IncludeTerminatingEndpoints",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.False(cfg.IncludeTerminatingEndpoints())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Traffic: v1alpha1.TrafficSpec{
+					IncludeTerminatingEndpoints: true,
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.True(cfg.IncludeTerminatingEndpoints())
+			},
+		},
+		{
+			name:                  "GetAdminInterfaceConfig",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.AdminInterfaceSpec{}, cfg.GetAdminInterfaceConfig())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Sidecar: v1alpha1.SidecarSpec{
+					AdminInterface: v1alpha1.AdminInterfaceSpec{
+						BindMode:              "uds",
+						DisableDebugEndpoints: true,
+					},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.AdminInterfaceSpec{
+					BindMode:              "uds",
+					DisableDebugEndpoints: true,
+				}, cfg.GetAdminInterfaceConfig())
+			},
+		},
+		{
+			name:                  "GetOverloadManagerConfig",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.OverloadManagerSpec{}, cfg.GetOverloadManagerConfig())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Sidecar: v1alpha1.SidecarSpec{
+					OverloadManager: v1alpha1.OverloadManagerSpec{
+						MaxHeapSizeBytes:               1073741824,
+						ShrinkHeapThreshold:            90,
+						StopAcceptingRequestsThreshold: 95,
+					},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.OverloadManagerSpec{
+					MaxHeapSizeBytes:               1073741824,
+					ShrinkHeapThreshold:            90,
+					StopAcceptingRequestsThreshold: 95,
+				}, cfg.GetOverloadManagerConfig())
+			},
+		},
+		{
+			name:                  "GetEnvoyDrainStrategy",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(constants.DefaultEnvoyDrainStrategy, cfg.GetEnvoyDrainStrategy())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Sidecar: v1alpha1.SidecarSpec{
+					DrainStrategy: "immediate",
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal("immediate", cfg.GetEnvoyDrainStrategy())
+			},
+		},
+		{
+			name:                  "GetStatsConfig",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.StatsSpec{}, cfg.GetStatsConfig())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Observability: v1alpha1.ObservabilitySpec{
+					Stats: v1alpha1.StatsSpec{
+						ExclusionRegexes: []string{"^cluster\\..*\\.upstream_cx_.*"},
+						Tags: []v1alpha1.StatsTagSpec{
+							{Name: "route", Regex: "^http\\.(route\\.(.+?)\\.)"},
+						},
+					},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.StatsSpec{
+					ExclusionRegexes: []string{"^cluster\\..*\\.upstream_cx_.*"},
+					Tags: []v1alpha1.StatsTagSpec{
+						{Name: "route", Regex: "^http\\.(route\\.(.+?)\\.)"},
+					},
+				}, cfg.GetStatsConfig())
+			},
+		},
+		{
+			name:                  "GetPrometheusScrapingConfig",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.PrometheusScrapingSpec{
+					ClientIdentity:   "osm-prometheus." + osmNamespace,
+					ClientCertSecret: "osm-prometheus-client-cert",
+				}, cfg.GetPrometheusScrapingConfig())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Observability: v1alpha1.ObservabilitySpec{
+					PrometheusScraping: v1alpha1.PrometheusScrapingSpec{
+						EnableMTLS:       true,
+						ClientIdentity:   "prometheus.monitoring",
+						ClientCertSecret: "prometheus-cert",
+						GenerateMonitors: true,
+						ScrapeInterval:   "30s",
+					},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.PrometheusScrapingSpec{
+					EnableMTLS:       true,
+					ClientIdentity:   "prometheus.monitoring",
+					ClientCertSecret: "prometheus-cert",
+					GenerateMonitors: true,
+					ScrapeInterval:   "30s",
+				}, cfg.GetPrometheusScrapingConfig())
+			},
+		},
+		{
+			name:                  "GetProvisioningConfig",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.ProvisioningSpec{
+					DashboardDatasource: "Prometheus",
+				}, cfg.GetProvisioningConfig())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Observability: v1alpha1.ObservabilitySpec{
+					Provisioning: v1alpha1.ProvisioningSpec{
+						EnableDashboards:    true,
+						EnableAlertRules:    true,
+						DashboardDatasource: "Thanos",
+					},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(v1alpha1.ProvisioningSpec{
+					EnableDashboards:    true,
+					EnableAlertRules:    true,
+					DashboardDatasource: "Thanos",
+				}, cfg.GetProvisioningConfig())
+			},
+		},
+		{
+			name:                  "IsProtocolDetectionEnabled",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.False(cfg.IsProtocolDetectionEnabled("ns-1"))
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Traffic: v1alpha1.TrafficSpec{
+					ProtocolDetectionNamespaces: []string{"ns-1", "ns-2"},
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.True(cfg.IsProtocolDetectionEnabled("ns-1"))
+				assert.True(cfg.IsProtocolDetectionEnabled("ns-2"))
+				assert.False(cfg.IsProtocolDetectionEnabled("ns-3"))
+			},
+		},
+		{
+			name:                  "GetProtocolDetectionTimeout",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(defaultProtocolDetectionTimeout, cfg.GetProtocolDetectionTimeout())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Traffic: v1alpha1.TrafficSpec{
+					ProtocolDetectionTimeout: "500ms",
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(500*time.Millisecond, cfg.GetProtocolDetectionTimeout())
+			},
+		},
+		{
+			name: "GetProtocolDetectionTimeout out of range",
+			initialMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Traffic: v1alpha1.TrafficSpec{
+					ProtocolDetectionTimeout: "10s",
+				},
+			},
+			checkCreate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(defaultProtocolDetectionTimeout, cfg.GetProtocolDetectionTimeout())
+			},
+			updatedMeshConfigData: &v1alpha1.MeshConfigSpec{
+				Traffic: v1alpha1.TrafficSpec{
+					ProtocolDetectionTimeout: "invalid",
+				},
+			},
+			checkUpdate: func(assert *tassert.Assertions, cfg Configurator) {
+				assert.Equal(defaultProtocolDetectionTimeout, cfg.GetProtocolDetectionTimeout())
+			},
+		},
 		{
 			name:                  "GetProxyResources",
 			initialMeshConfigData: &v1alpha1.MeshConfigSpec{},
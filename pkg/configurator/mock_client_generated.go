@@ -37,6 +37,34 @@ func (m *MockConfigurator) EXPECT() *MockConfiguratorMockRecorder {
 	return m.recorder
 }
 
+// GetAdminInterfaceConfig mocks base method
+func (m *MockConfigurator) GetAdminInterfaceConfig() v1alpha1.AdminInterfaceSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdminInterfaceConfig")
+	ret0, _ := ret[0].(v1alpha1.AdminInterfaceSpec)
+	return ret0
+}
+
+// GetAdminInterfaceConfig indicates an expected call of GetAdminInterfaceConfig
+func (mr *MockConfiguratorMockRecorder) GetAdminInterfaceConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdminInterfaceConfig", reflect.TypeOf((*MockConfigurator)(nil).GetAdminInterfaceConfig))
+}
+
+// GetCertKeyAlgorithm mocks base method
+func (m *MockConfigurator) GetCertKeyAlgorithm() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCertKeyAlgorithm")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetCertKeyAlgorithm indicates an expected call of GetCertKeyAlgorithm
+func (mr *MockConfiguratorMockRecorder) GetCertKeyAlgorithm() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCertKeyAlgorithm", reflect.TypeOf((*MockConfigurator)(nil).GetCertKeyAlgorithm))
+}
+
 // GetCertKeyBitSize mocks base method
 func (m *MockConfigurator) GetCertKeyBitSize() int {
 	m.ctrl.T.Helper()
@@ -51,6 +79,34 @@ func (mr *MockConfiguratorMockRecorder) GetCertKeyBitSize() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCertKeyBitSize", reflect.TypeOf((*MockConfigurator)(nil).GetCertKeyBitSize))
 }
 
+// GetClientCertDetailsConfig mocks base method
+func (m *MockConfigurator) GetClientCertDetailsConfig() v1alpha1.ClientCertDetailsSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClientCertDetailsConfig")
+	ret0, _ := ret[0].(v1alpha1.ClientCertDetailsSpec)
+	return ret0
+}
+
+// GetClientCertDetailsConfig indicates an expected call of GetClientCertDetailsConfig
+func (mr *MockConfiguratorMockRecorder) GetClientCertDetailsConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClientCertDetailsConfig", reflect.TypeOf((*MockConfigurator)(nil).GetClientCertDetailsConfig))
+}
+
+// GetCompressionConfig mocks base method
+func (m *MockConfigurator) GetCompressionConfig() v1alpha1.CompressionSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCompressionConfig")
+	ret0, _ := ret[0].(v1alpha1.CompressionSpec)
+	return ret0
+}
+
+// GetCompressionConfig indicates an expected call of GetCompressionConfig
+func (mr *MockConfiguratorMockRecorder) GetCompressionConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCompressionConfig", reflect.TypeOf((*MockConfigurator)(nil).GetCompressionConfig))
+}
+
 // GetConfigResyncInterval mocks base method
 func (m *MockConfigurator) GetConfigResyncInterval() time.Duration {
 	m.ctrl.T.Helper()
@@ -65,6 +121,48 @@ func (mr *MockConfiguratorMockRecorder) GetConfigResyncInterval() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigResyncInterval", reflect.TypeOf((*MockConfigurator)(nil).GetConfigResyncInterval))
 }
 
+// GetDNSResolutionConfig mocks base method
+func (m *MockConfigurator) GetDNSResolutionConfig() v1alpha1.DNSResolutionSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDNSResolutionConfig")
+	ret0, _ := ret[0].(v1alpha1.DNSResolutionSpec)
+	return ret0
+}
+
+// GetDNSResolutionConfig indicates an expected call of GetDNSResolutionConfig
+func (mr *MockConfiguratorMockRecorder) GetDNSResolutionConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDNSResolutionConfig", reflect.TypeOf((*MockConfigurator)(nil).GetDNSResolutionConfig))
+}
+
+// GetDownstreamLimitsConfig mocks base method
+func (m *MockConfigurator) GetDownstreamLimitsConfig() v1alpha1.DownstreamLimitsSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDownstreamLimitsConfig")
+	ret0, _ := ret[0].(v1alpha1.DownstreamLimitsSpec)
+	return ret0
+}
+
+// GetDownstreamLimitsConfig indicates an expected call of GetDownstreamLimitsConfig
+func (mr *MockConfiguratorMockRecorder) GetDownstreamLimitsConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDownstreamLimitsConfig", reflect.TypeOf((*MockConfigurator)(nil).GetDownstreamLimitsConfig))
+}
+
+// GetEndpointFlapDampeningConfig mocks base method
+func (m *MockConfigurator) GetEndpointFlapDampeningConfig() v1alpha1.EndpointFlapDampeningSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEndpointFlapDampeningConfig")
+	ret0, _ := ret[0].(v1alpha1.EndpointFlapDampeningSpec)
+	return ret0
+}
+
+// GetEndpointFlapDampeningConfig indicates an expected call of GetEndpointFlapDampeningConfig
+func (mr *MockConfiguratorMockRecorder) GetEndpointFlapDampeningConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEndpointFlapDampeningConfig", reflect.TypeOf((*MockConfigurator)(nil).GetEndpointFlapDampeningConfig))
+}
+
 // GetEnvoyImage mocks base method
 func (m *MockConfigurator) GetEnvoyImage() string {
 	m.ctrl.T.Helper()
@@ -79,6 +177,20 @@ func (mr *MockConfiguratorMockRecorder) GetEnvoyImage() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEnvoyImage", reflect.TypeOf((*MockConfigurator)(nil).GetEnvoyImage))
 }
 
+// GetEnvoyDrainStrategy mocks base method
+func (m *MockConfigurator) GetEnvoyDrainStrategy() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEnvoyDrainStrategy")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetEnvoyDrainStrategy indicates an expected call of GetEnvoyDrainStrategy
+func (mr *MockConfiguratorMockRecorder) GetEnvoyDrainStrategy() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEnvoyDrainStrategy", reflect.TypeOf((*MockConfigurator)(nil).GetEnvoyDrainStrategy))
+}
+
 // GetEnvoyLogLevel mocks base method
 func (m *MockConfigurator) GetEnvoyLogLevel() string {
 	m.ctrl.T.Helper()
@@ -107,6 +219,20 @@ func (mr *MockConfiguratorMockRecorder) GetEnvoyWindowsImage() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEnvoyWindowsImage", reflect.TypeOf((*MockConfigurator)(nil).GetEnvoyWindowsImage))
 }
 
+// GetFederatedTrustDomains mocks base method
+func (m *MockConfigurator) GetFederatedTrustDomains() []v1alpha1.FederatedTrustDomainSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFederatedTrustDomains")
+	ret0, _ := ret[0].([]v1alpha1.FederatedTrustDomainSpec)
+	return ret0
+}
+
+// GetFederatedTrustDomains indicates an expected call of GetFederatedTrustDomains
+func (mr *MockConfiguratorMockRecorder) GetFederatedTrustDomains() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFederatedTrustDomains", reflect.TypeOf((*MockConfigurator)(nil).GetFederatedTrustDomains))
+}
+
 // GetFeatureFlags mocks base method
 func (m *MockConfigurator) GetFeatureFlags() v1alpha1.FeatureFlags {
 	m.ctrl.T.Helper()
@@ -121,6 +247,34 @@ func (mr *MockConfiguratorMockRecorder) GetFeatureFlags() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeatureFlags", reflect.TypeOf((*MockConfigurator)(nil).GetFeatureFlags))
 }
 
+// GetHTTPSanitizationConfig mocks base method
+func (m *MockConfigurator) GetHTTPSanitizationConfig() v1alpha1.HTTPSanitizationSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHTTPSanitizationConfig")
+	ret0, _ := ret[0].(v1alpha1.HTTPSanitizationSpec)
+	return ret0
+}
+
+// GetHTTPSanitizationConfig indicates an expected call of GetHTTPSanitizationConfig
+func (mr *MockConfiguratorMockRecorder) GetHTTPSanitizationConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHTTPSanitizationConfig", reflect.TypeOf((*MockConfigurator)(nil).GetHTTPSanitizationConfig))
+}
+
+// GetHeapSnapshotInterval mocks base method
+func (m *MockConfigurator) GetHeapSnapshotInterval() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHeapSnapshotInterval")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// GetHeapSnapshotInterval indicates an expected call of GetHeapSnapshotInterval
+func (mr *MockConfiguratorMockRecorder) GetHeapSnapshotInterval() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHeapSnapshotInterval", reflect.TypeOf((*MockConfigurator)(nil).GetHeapSnapshotInterval))
+}
+
 // GetInboundExternalAuthConfig mocks base method
 func (m *MockConfigurator) GetInboundExternalAuthConfig() auth.ExtAuthConfig {
 	m.ctrl.T.Helper()
@@ -149,6 +303,20 @@ func (mr *MockConfiguratorMockRecorder) GetInboundPortExclusionList() *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInboundPortExclusionList", reflect.TypeOf((*MockConfigurator)(nil).GetInboundPortExclusionList))
 }
 
+// GetIngressClientAddressConfig mocks base method
+func (m *MockConfigurator) GetIngressClientAddressConfig() v1alpha1.IngressClientAddressSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngressClientAddressConfig")
+	ret0, _ := ret[0].(v1alpha1.IngressClientAddressSpec)
+	return ret0
+}
+
+// GetIngressClientAddressConfig indicates an expected call of GetIngressClientAddressConfig
+func (mr *MockConfiguratorMockRecorder) GetIngressClientAddressConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngressClientAddressConfig", reflect.TypeOf((*MockConfigurator)(nil).GetIngressClientAddressConfig))
+}
+
 // GetInitContainerImage mocks base method
 func (m *MockConfigurator) GetInitContainerImage() string {
 	m.ctrl.T.Helper()
@@ -177,6 +345,20 @@ func (mr *MockConfiguratorMockRecorder) GetMaxDataPlaneConnections() *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaxDataPlaneConnections", reflect.TypeOf((*MockConfigurator)(nil).GetMaxDataPlaneConnections))
 }
 
+// GetMaxHeapSnapshots mocks base method
+func (m *MockConfigurator) GetMaxHeapSnapshots() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMaxHeapSnapshots")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// GetMaxHeapSnapshots indicates an expected call of GetMaxHeapSnapshots
+func (mr *MockConfiguratorMockRecorder) GetMaxHeapSnapshots() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaxHeapSnapshots", reflect.TypeOf((*MockConfigurator)(nil).GetMaxHeapSnapshots))
+}
+
 // GetMeshConfig mocks base method
 func (m *MockConfigurator) GetMeshConfig() *v1alpha1.MeshConfig {
 	m.ctrl.T.Helper()
@@ -248,6 +430,20 @@ func (mr *MockConfiguratorMockRecorder) GetOutboundIPRangeExclusionList() *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutboundIPRangeExclusionList", reflect.TypeOf((*MockConfigurator)(nil).GetOutboundIPRangeExclusionList))
 }
 
+// GetOutboundPassthroughConfig mocks base method
+func (m *MockConfigurator) GetOutboundPassthroughConfig() v1alpha1.OutboundPassthroughSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutboundPassthroughConfig")
+	ret0, _ := ret[0].(v1alpha1.OutboundPassthroughSpec)
+	return ret0
+}
+
+// GetOutboundPassthroughConfig indicates an expected call of GetOutboundPassthroughConfig
+func (mr *MockConfiguratorMockRecorder) GetOutboundPassthroughConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutboundPassthroughConfig", reflect.TypeOf((*MockConfigurator)(nil).GetOutboundPassthroughConfig))
+}
+
 // GetOutboundPortExclusionList mocks base method
 func (m *MockConfigurator) GetOutboundPortExclusionList() []int {
 	m.ctrl.T.Helper()
@@ -262,6 +458,76 @@ func (mr *MockConfiguratorMockRecorder) GetOutboundPortExclusionList() *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutboundPortExclusionList", reflect.TypeOf((*MockConfigurator)(nil).GetOutboundPortExclusionList))
 }
 
+// GetOverloadManagerConfig mocks base method
+func (m *MockConfigurator) GetOverloadManagerConfig() v1alpha1.OverloadManagerSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOverloadManagerConfig")
+	ret0, _ := ret[0].(v1alpha1.OverloadManagerSpec)
+	return ret0
+}
+
+// GetOverloadManagerConfig indicates an expected call of GetOverloadManagerConfig
+func (mr *MockConfiguratorMockRecorder) GetOverloadManagerConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOverloadManagerConfig", reflect.TypeOf((*MockConfigurator)(nil).GetOverloadManagerConfig))
+}
+
+// GetPerformanceSettings mocks base method
+func (m *MockConfigurator) GetPerformanceSettings() PerformanceSettings {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPerformanceSettings")
+	ret0, _ := ret[0].(PerformanceSettings)
+	return ret0
+}
+
+// GetPerformanceSettings indicates an expected call of GetPerformanceSettings
+func (mr *MockConfiguratorMockRecorder) GetPerformanceSettings() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPerformanceSettings", reflect.TypeOf((*MockConfigurator)(nil).GetPerformanceSettings))
+}
+
+// GetPrometheusScrapingConfig mocks base method
+func (m *MockConfigurator) GetPrometheusScrapingConfig() v1alpha1.PrometheusScrapingSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrometheusScrapingConfig")
+	ret0, _ := ret[0].(v1alpha1.PrometheusScrapingSpec)
+	return ret0
+}
+
+// GetPrometheusScrapingConfig indicates an expected call of GetPrometheusScrapingConfig
+func (mr *MockConfiguratorMockRecorder) GetPrometheusScrapingConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrometheusScrapingConfig", reflect.TypeOf((*MockConfigurator)(nil).GetPrometheusScrapingConfig))
+}
+
+// GetProvisioningConfig mocks base method
+func (m *MockConfigurator) GetProvisioningConfig() v1alpha1.ProvisioningSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisioningConfig")
+	ret0, _ := ret[0].(v1alpha1.ProvisioningSpec)
+	return ret0
+}
+
+// GetProvisioningConfig indicates an expected call of GetProvisioningConfig
+func (mr *MockConfiguratorMockRecorder) GetProvisioningConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisioningConfig", reflect.TypeOf((*MockConfigurator)(nil).GetProvisioningConfig))
+}
+
+// GetProtocolDetectionTimeout mocks base method
+func (m *MockConfigurator) GetProtocolDetectionTimeout() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProtocolDetectionTimeout")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// GetProtocolDetectionTimeout indicates an expected call of GetProtocolDetectionTimeout
+func (mr *MockConfiguratorMockRecorder) GetProtocolDetectionTimeout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProtocolDetectionTimeout", reflect.TypeOf((*MockConfigurator)(nil).GetProtocolDetectionTimeout))
+}
+
 // GetProxyResources mocks base method
 func (m *MockConfigurator) GetProxyResources() v1.ResourceRequirements {
 	m.ctrl.T.Helper()
@@ -276,6 +542,62 @@ func (mr *MockConfiguratorMockRecorder) GetProxyResources() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProxyResources", reflect.TypeOf((*MockConfigurator)(nil).GetProxyResources))
 }
 
+// GetRBACAuditConfig mocks base method
+func (m *MockConfigurator) GetRBACAuditConfig() v1alpha1.RBACAuditSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRBACAuditConfig")
+	ret0, _ := ret[0].(v1alpha1.RBACAuditSpec)
+	return ret0
+}
+
+// GetRBACAuditConfig indicates an expected call of GetRBACAuditConfig
+func (mr *MockConfiguratorMockRecorder) GetRBACAuditConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRBACAuditConfig", reflect.TypeOf((*MockConfigurator)(nil).GetRBACAuditConfig))
+}
+
+// GetRequestLimitsConfig mocks base method
+func (m *MockConfigurator) GetRequestLimitsConfig() v1alpha1.RequestLimitsSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRequestLimitsConfig")
+	ret0, _ := ret[0].(v1alpha1.RequestLimitsSpec)
+	return ret0
+}
+
+// GetRequestLimitsConfig indicates an expected call of GetRequestLimitsConfig
+func (mr *MockConfiguratorMockRecorder) GetRequestLimitsConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRequestLimitsConfig", reflect.TypeOf((*MockConfigurator)(nil).GetRequestLimitsConfig))
+}
+
+// GetRouteRegexConfig mocks base method
+func (m *MockConfigurator) GetRouteRegexConfig() v1alpha1.RouteRegexSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRouteRegexConfig")
+	ret0, _ := ret[0].(v1alpha1.RouteRegexSpec)
+	return ret0
+}
+
+// GetRouteRegexConfig indicates an expected call of GetRouteRegexConfig
+func (mr *MockConfiguratorMockRecorder) GetRouteRegexConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRouteRegexConfig", reflect.TypeOf((*MockConfigurator)(nil).GetRouteRegexConfig))
+}
+
+// GetSPIFFETrustDomain mocks base method
+func (m *MockConfigurator) GetSPIFFETrustDomain() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSPIFFETrustDomain")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetSPIFFETrustDomain indicates an expected call of GetSPIFFETrustDomain
+func (mr *MockConfiguratorMockRecorder) GetSPIFFETrustDomain() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSPIFFETrustDomain", reflect.TypeOf((*MockConfigurator)(nil).GetSPIFFETrustDomain))
+}
+
 // GetServiceCertValidityPeriod mocks base method
 func (m *MockConfigurator) GetServiceCertValidityPeriod() time.Duration {
 	m.ctrl.T.Helper()
@@ -290,6 +612,20 @@ func (mr *MockConfiguratorMockRecorder) GetServiceCertValidityPeriod() *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceCertValidityPeriod", reflect.TypeOf((*MockConfigurator)(nil).GetServiceCertValidityPeriod))
 }
 
+// GetStatsConfig mocks base method
+func (m *MockConfigurator) GetStatsConfig() v1alpha1.StatsSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatsConfig")
+	ret0, _ := ret[0].(v1alpha1.StatsSpec)
+	return ret0
+}
+
+// GetStatsConfig indicates an expected call of GetStatsConfig
+func (mr *MockConfiguratorMockRecorder) GetStatsConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatsConfig", reflect.TypeOf((*MockConfigurator)(nil).GetStatsConfig))
+}
+
 // GetTracingEndpoint mocks base method
 func (m *MockConfigurator) GetTracingEndpoint() string {
 	m.ctrl.T.Helper()
@@ -332,6 +668,48 @@ func (mr *MockConfiguratorMockRecorder) GetTracingPort() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTracingPort", reflect.TypeOf((*MockConfigurator)(nil).GetTracingPort))
 }
 
+// GetTrustDomain mocks base method
+func (m *MockConfigurator) GetTrustDomain() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrustDomain")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetTrustDomain indicates an expected call of GetTrustDomain
+func (mr *MockConfiguratorMockRecorder) GetTrustDomain() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrustDomain", reflect.TypeOf((*MockConfigurator)(nil).GetTrustDomain))
+}
+
+// GetTrustDomainAliases mocks base method
+func (m *MockConfigurator) GetTrustDomainAliases() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrustDomainAliases")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// GetTrustDomainAliases indicates an expected call of GetTrustDomainAliases
+func (mr *MockConfiguratorMockRecorder) GetTrustDomainAliases() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrustDomainAliases", reflect.TypeOf((*MockConfigurator)(nil).GetTrustDomainAliases))
+}
+
+// IncludeTerminatingEndpoints mocks base method
+func (m *MockConfigurator) IncludeTerminatingEndpoints() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncludeTerminatingEndpoints")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IncludeTerminatingEndpoints indicates an expected call of IncludeTerminatingEndpoints
+func (mr *MockConfiguratorMockRecorder) IncludeTerminatingEndpoints() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncludeTerminatingEndpoints", reflect.TypeOf((*MockConfigurator)(nil).IncludeTerminatingEndpoints))
+}
+
 // IsDebugServerEnabled mocks base method
 func (m *MockConfigurator) IsDebugServerEnabled() bool {
 	m.ctrl.T.Helper()
@@ -360,6 +738,48 @@ func (mr *MockConfiguratorMockRecorder) IsEgressEnabled() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEgressEnabled", reflect.TypeOf((*MockConfigurator)(nil).IsEgressEnabled))
 }
 
+// IsInitContainerDistroless mocks base method
+func (m *MockConfigurator) IsInitContainerDistroless() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsInitContainerDistroless")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsInitContainerDistroless indicates an expected call of IsInitContainerDistroless
+func (mr *MockConfiguratorMockRecorder) IsInitContainerDistroless() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsInitContainerDistroless", reflect.TypeOf((*MockConfigurator)(nil).IsInitContainerDistroless))
+}
+
+// IsMemoryProfilingEnabled mocks base method
+func (m *MockConfigurator) IsMemoryProfilingEnabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsMemoryProfilingEnabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsMemoryProfilingEnabled indicates an expected call of IsMemoryProfilingEnabled
+func (mr *MockConfiguratorMockRecorder) IsMemoryProfilingEnabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsMemoryProfilingEnabled", reflect.TypeOf((*MockConfigurator)(nil).IsMemoryProfilingEnabled))
+}
+
+// IsNamespaceIsolationEnabled mocks base method
+func (m *MockConfigurator) IsNamespaceIsolationEnabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsNamespaceIsolationEnabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsNamespaceIsolationEnabled indicates an expected call of IsNamespaceIsolationEnabled
+func (mr *MockConfiguratorMockRecorder) IsNamespaceIsolationEnabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsNamespaceIsolationEnabled", reflect.TypeOf((*MockConfigurator)(nil).IsNamespaceIsolationEnabled))
+}
+
 // IsPermissiveTrafficPolicyMode mocks base method
 func (m *MockConfigurator) IsPermissiveTrafficPolicyMode() bool {
 	m.ctrl.T.Helper()
@@ -388,6 +808,20 @@ func (mr *MockConfiguratorMockRecorder) IsPrivilegedInitContainer() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsPrivilegedInitContainer", reflect.TypeOf((*MockConfigurator)(nil).IsPrivilegedInitContainer))
 }
 
+// IsProtocolDetectionEnabled mocks base method
+func (m *MockConfigurator) IsProtocolDetectionEnabled(arg0 string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsProtocolDetectionEnabled", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsProtocolDetectionEnabled indicates an expected call of IsProtocolDetectionEnabled
+func (mr *MockConfiguratorMockRecorder) IsProtocolDetectionEnabled(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsProtocolDetectionEnabled", reflect.TypeOf((*MockConfigurator)(nil).IsProtocolDetectionEnabled), arg0)
+}
+
 // IsTracingEnabled mocks base method
 func (m *MockConfigurator) IsTracingEnabled() bool {
 	m.ctrl.T.Helper()
@@ -0,0 +1,201 @@
+package configurator
+
+import (
+	"time"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/k8s"
+)
+
+const (
+	// PerformanceProfileSmall is the performance profile tuned for clusters of up to a hundred proxies
+	PerformanceProfileSmall = "small"
+
+	// PerformanceProfileMedium is the performance profile tuned for clusters of up to a thousand proxies
+	PerformanceProfileMedium = "medium"
+
+	// PerformanceProfileLarge is the performance profile tuned for clusters of thousands of proxies
+	PerformanceProfileLarge = "large"
+)
+
+// PerformanceSettings are the effective performance settings of the control plane and the proxy sidecars, resolved
+// from the performance profile selected in the MeshConfig and the overrides of its individual settings.
+type PerformanceSettings struct {
+	// Profile is the name of the performance profile, empty when the defaults of the control plane are used
+	Profile string
+
+	// BroadcastGracePeriod is the time the controller waits for additional config changes before updating the proxies
+	BroadcastGracePeriod time.Duration
+
+	// MaxBroadcastDelay is the maximum time the controller delays the update of the proxies while it coalesces config
+	// changes
+	MaxBroadcastDelay time.Duration
+
+	// WorkerPoolSize is the number of workers generating the config of the proxies, 0 for GOMAXPROCS
+	WorkerPoolSize int
+
+	// MaxDataPlaneConnections is the maximum number of proxies connected to the controller, 0 if not limited
+	MaxDataPlaneConnections int
+
+	// InformerResyncInterval is the resync interval of the Kubernetes informers of the controller
+	InformerResyncInterval time.Duration
+
+	// InformerResyncIntervals overrides InformerResyncInterval for individual Kubernetes informers of the controller
+	InformerResyncIntervals map[k8s.InformerKey]time.Duration
+
+	// InformerWatchErrorBackoff is the initial delay the Kubernetes informers of the controller wait before relisting
+	// their resources after a watch error, 0 if they only use the backoff of the Kubernetes client
+	InformerWatchErrorBackoff time.Duration
+
+	// InformerWatchErrorMaxBackoff is the maximum delay the Kubernetes informers of the controller wait before
+	// relisting their resources after consecutive watch errors
+	InformerWatchErrorMaxBackoff time.Duration
+
+	// EnvoyConcurrency is the number of worker threads of the proxies, 0 for the number of cores of their node
+	EnvoyConcurrency int
+}
+
+// defaultPerformanceSettings are the performance settings used when no performance profile is selected
+var defaultPerformanceSettings = PerformanceSettings{
+	BroadcastGracePeriod:    3 * time.Second,
+	MaxBroadcastDelay:       15 * time.Second,
+	WorkerPoolSize:          0,
+	MaxDataPlaneConnections: 0,
+	InformerResyncInterval:  k8s.DefaultKubeEventResyncInterval,
+	EnvoyConcurrency:        0,
+
+	InformerWatchErrorMaxBackoff: defaultInformerWatchErrorMaxBackoff,
+}
+
+// defaultInformerWatchErrorMaxBackoff is the maximum delay the informers wait before relisting after watch errors
+const defaultInformerWatchErrorMaxBackoff = 5 * time.Minute
+
+// informerKeys are the informers whose resync interval can be overridden individually
+var informerKeys = []k8s.InformerKey{k8s.Namespaces, k8s.Services, k8s.ServiceAccounts, k8s.Pods, k8s.Endpoints,
+	k8s.EndpointSlices, k8s.Secrets, k8s.ExternalWorkloads}
+
+// performanceProfiles are the settings of the performance profiles. Larger clusters coalesce config changes over
+// longer windows and resync their informers less often, trading the latency of config updates for a lower load on the
+// controller and the Kubernetes API server.
+var performanceProfiles = map[string]PerformanceSettings{
+	PerformanceProfileSmall: {
+		Profile:                 PerformanceProfileSmall,
+		BroadcastGracePeriod:    1 * time.Second,
+		MaxBroadcastDelay:       5 * time.Second,
+		WorkerPoolSize:          2,
+		MaxDataPlaneConnections: 500,
+		InformerResyncInterval:  5 * time.Minute,
+		EnvoyConcurrency:        1,
+
+		InformerWatchErrorMaxBackoff: defaultInformerWatchErrorMaxBackoff,
+	},
+	PerformanceProfileMedium: {
+		Profile:                 PerformanceProfileMedium,
+		BroadcastGracePeriod:    3 * time.Second,
+		MaxBroadcastDelay:       15 * time.Second,
+		WorkerPoolSize:          8,
+		MaxDataPlaneConnections: 5000,
+		InformerResyncInterval:  10 * time.Minute,
+		EnvoyConcurrency:        2,
+
+		InformerWatchErrorMaxBackoff: defaultInformerWatchErrorMaxBackoff,
+	},
+	PerformanceProfileLarge: {
+		Profile:                 PerformanceProfileLarge,
+		BroadcastGracePeriod:    5 * time.Second,
+		MaxBroadcastDelay:       30 * time.Second,
+		WorkerPoolSize:          32,
+		MaxDataPlaneConnections: 0,
+		InformerResyncInterval:  30 * time.Minute,
+		EnvoyConcurrency:        2,
+
+		InformerWatchErrorBackoff:    1 * time.Second,
+		InformerWatchErrorMaxBackoff: defaultInformerWatchErrorMaxBackoff,
+	},
+}
+
+// GetPerformanceSettings returns the effective performance settings of the control plane and the proxy sidecars
+func (c *Client) GetPerformanceSettings() PerformanceSettings {
+	spec := c.getMeshConfig().Spec
+	return getPerformanceSettings(spec.Performance, spec.Sidecar.MaxDataPlaneConnections)
+}
+
+// getPerformanceSettings resolves the performance settings of the given performance spec, whose connection limit is
+// overridden by the given max data plane connections. Invalid overrides are ignored.
+func getPerformanceSettings(spec configv1alpha1.PerformanceSpec, maxDataPlaneConnections int) PerformanceSettings {
+	settings := defaultPerformanceSettings
+	if spec.Profile != "" {
+		if profile, ok := performanceProfiles[spec.Profile]; ok {
+			settings = profile
+		} else {
+			log.Error().Msgf("Invalid performance profile %s, using the default performance settings", spec.Profile)
+		}
+	}
+
+	overrideDuration := func(name, value string, setting *time.Duration) {
+		if value == "" {
+			return
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration <= 0 {
+			log.Error().Err(err).Msgf("Invalid performance setting %s %s, using %s", name, value, *setting)
+			return
+		}
+		*setting = duration
+	}
+	overrideDuration("broadcastGracePeriod", spec.BroadcastGracePeriod, &settings.BroadcastGracePeriod)
+	overrideDuration("maxBroadcastDelay", spec.MaxBroadcastDelay, &settings.MaxBroadcastDelay)
+	overrideDuration("informerResyncInterval", spec.InformerResyncInterval, &settings.InformerResyncInterval)
+	overrideDuration("informerWatchErrorBackoff", spec.InformerWatchErrorBackoff, &settings.InformerWatchErrorBackoff)
+	overrideDuration("informerWatchErrorMaxBackoff", spec.InformerWatchErrorMaxBackoff, &settings.InformerWatchErrorMaxBackoff)
+
+	if len(spec.InformerResyncIntervals) > 0 {
+		settings.InformerResyncIntervals = make(map[k8s.InformerKey]time.Duration)
+	}
+	for name, value := range spec.InformerResyncIntervals {
+		if !isInformerKey(name) {
+			log.Error().Msgf("Invalid performance setting informerResyncIntervals, unknown informer %s", name)
+			continue
+		}
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			log.Error().Err(err).Msgf("Invalid performance setting informerResyncIntervals %s %s, using %s", name, value, settings.InformerResyncInterval)
+			continue
+		}
+		settings.InformerResyncIntervals[k8s.InformerKey(name)] = interval
+	}
+
+	if settings.MaxBroadcastDelay < settings.BroadcastGracePeriod {
+		log.Error().Msgf("Max broadcast delay %s is shorter than the broadcast grace period %s, using %s",
+			settings.MaxBroadcastDelay, settings.BroadcastGracePeriod, settings.BroadcastGracePeriod)
+		settings.MaxBroadcastDelay = settings.BroadcastGracePeriod
+	}
+
+	if settings.InformerWatchErrorMaxBackoff < settings.InformerWatchErrorBackoff {
+		log.Error().Msgf("Informer watch error max backoff %s is shorter than the informer watch error backoff %s, using %s",
+			settings.InformerWatchErrorMaxBackoff, settings.InformerWatchErrorBackoff, settings.InformerWatchErrorBackoff)
+		settings.InformerWatchErrorMaxBackoff = settings.InformerWatchErrorBackoff
+	}
+
+	if spec.WorkerPoolSize > 0 {
+		settings.WorkerPoolSize = spec.WorkerPoolSize
+	}
+	if maxDataPlaneConnections > 0 {
+		settings.MaxDataPlaneConnections = maxDataPlaneConnections
+	}
+	if spec.EnvoyConcurrency > 0 {
+		settings.EnvoyConcurrency = spec.EnvoyConcurrency
+	}
+
+	return settings
+}
+
+// isInformerKey returns whether the given name is that of an informer whose resync interval can be overridden
+func isInformerKey(name string) bool {
+	for _, key := range informerKeys {
+		if string(key) == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package configurator
+
+import (
+	"testing"
+	"time"
+
+	tassert "github.com/stretchr/testify/assert"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/k8s"
+)
+
+func TestGetPerformanceSettings(t *testing.T) {
+	testCases := []struct {
+		name                    string
+		spec                    configv1alpha1.PerformanceSpec
+		maxDataPlaneConnections int
+		expected                PerformanceSettings
+	}{
+		{
+			name:     "no profile",
+			expected: defaultPerformanceSettings,
+		},
+		{
+			name:     "profile",
+			spec:     configv1alpha1.PerformanceSpec{Profile: PerformanceProfileLarge},
+			expected: performanceProfiles[PerformanceProfileLarge],
+		},
+		{
+			name:     "unknown profile",
+			spec:     configv1alpha1.PerformanceSpec{Profile: "huge"},
+			expected: defaultPerformanceSettings,
+		},
+		{
+			name: "overrides",
+			spec: configv1alpha1.PerformanceSpec{
+				Profile:                PerformanceProfileSmall,
+				BroadcastGracePeriod:   "2s",
+				MaxBroadcastDelay:      "10s",
+				WorkerPoolSize:         4,
+				InformerResyncInterval: "1h",
+				EnvoyConcurrency:       3,
+
+				InformerResyncIntervals:      map[string]string{"Pods": "1m", "Secrets": "2h"},
+				InformerWatchErrorBackoff:    "2s",
+				InformerWatchErrorMaxBackoff: "1m",
+			},
+			maxDataPlaneConnections: 100,
+			expected: PerformanceSettings{
+				Profile:                 PerformanceProfileSmall,
+				BroadcastGracePeriod:    2 * time.Second,
+				MaxBroadcastDelay:       10 * time.Second,
+				WorkerPoolSize:          4,
+				MaxDataPlaneConnections: 100,
+				InformerResyncInterval:  time.Hour,
+				EnvoyConcurrency:        3,
+
+				InformerResyncIntervals:      map[k8s.InformerKey]time.Duration{k8s.Pods: time.Minute, k8s.Secrets: 2 * time.Hour},
+				InformerWatchErrorBackoff:    2 * time.Second,
+				InformerWatchErrorMaxBackoff: time.Minute,
+			},
+		},
+		{
+			name: "invalid overrides are ignored",
+			spec: configv1alpha1.PerformanceSpec{
+				Profile:                PerformanceProfileMedium,
+				BroadcastGracePeriod:   "soon",
+				InformerResyncInterval: "-1m",
+
+				InformerWatchErrorBackoff: "0s",
+			},
+			expected: performanceProfiles[PerformanceProfileMedium],
+		},
+		{
+			name: "invalid informer resync intervals are ignored",
+			spec: configv1alpha1.PerformanceSpec{
+				InformerResyncIntervals: map[string]string{"Pods": "often", "Deployments": "1m", "Services": "1m"},
+			},
+			expected: PerformanceSettings{
+				BroadcastGracePeriod:         defaultPerformanceSettings.BroadcastGracePeriod,
+				MaxBroadcastDelay:            defaultPerformanceSettings.MaxBroadcastDelay,
+				InformerResyncInterval:       defaultPerformanceSettings.InformerResyncInterval,
+				InformerResyncIntervals:      map[k8s.InformerKey]time.Duration{k8s.Services: time.Minute},
+				InformerWatchErrorMaxBackoff: defaultPerformanceSettings.InformerWatchErrorMaxBackoff,
+			},
+		},
+		{
+			name: "informer watch error max backoff shorter than the backoff",
+			spec: configv1alpha1.PerformanceSpec{
+				Profile:                      PerformanceProfileLarge,
+				InformerWatchErrorBackoff:    "10m",
+				InformerWatchErrorMaxBackoff: "1m",
+			},
+			expected: func() PerformanceSettings {
+				settings := performanceProfiles[PerformanceProfileLarge]
+				settings.InformerWatchErrorBackoff = 10 * time.Minute
+				settings.InformerWatchErrorMaxBackoff = 10 * time.Minute
+				return settings
+			}(),
+		},
+		{
+			name: "max broadcast delay shorter than the grace period",
+			spec: configv1alpha1.PerformanceSpec{
+				BroadcastGracePeriod: "10s",
+				MaxBroadcastDelay:    "5s",
+			},
+			expected: PerformanceSettings{
+				BroadcastGracePeriod:   10 * time.Second,
+				MaxBroadcastDelay:      10 * time.Second,
+				InformerResyncInterval: defaultPerformanceSettings.InformerResyncInterval,
+
+				InformerWatchErrorMaxBackoff: defaultPerformanceSettings.InformerWatchErrorMaxBackoff,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expected, getPerformanceSettings(tc.spec, tc.maxDataPlaneConnections))
+		})
+	}
+}
@@ -24,6 +24,9 @@ type Client struct {
 	informer       cache.SharedIndexInformer
 	cache          cache.Store
 	meshConfigName string
+
+	// converted is the v1alpha1 representation of the MeshConfig when it is watched through the v1alpha2 API
+	converted convertedMeshConfig
 }
 
 // Configurator is the controller interface for K8s namespaces
@@ -46,6 +49,16 @@ type Configurator interface {
 	// IsDebugServerEnabled determines whether osm debug HTTP server is enabled
 	IsDebugServerEnabled() bool
 
+	// IsMemoryProfilingEnabled returns whether the mutex and block profiles of the controller are sampled, and heap
+	// snapshots of the controller are periodically taken
+	IsMemoryProfilingEnabled() bool
+
+	// GetHeapSnapshotInterval returns the interval at which heap snapshots of the controller are taken
+	GetHeapSnapshotInterval() time.Duration
+
+	// GetMaxHeapSnapshots returns the number of most recent heap snapshots of the controller kept in memory
+	GetMaxHeapSnapshots() int
+
 	// IsTracingEnabled returns whether tracing is enabled
 	IsTracingEnabled() bool
 
@@ -64,6 +77,9 @@ type Configurator interface {
 	// GetMaxDataPlaneConnections returns the max data plane connections allowed, 0 if disabled
 	GetMaxDataPlaneConnections() int
 
+	// GetPerformanceSettings returns the effective performance settings of the control plane and the proxy sidecars
+	GetPerformanceSettings() PerformanceSettings
+
 	// GetOsmLogLevel returns the configured OSM log level
 	GetOSMLogLevel() string
 
@@ -85,6 +101,21 @@ type Configurator interface {
 	// GetCertKeyBitSize returns the certificate key bit size
 	GetCertKeyBitSize() int
 
+	// GetCertKeyAlgorithm returns the algorithm of the keys of the service certificates
+	GetCertKeyAlgorithm() string
+
+	// GetSPIFFETrustDomain returns the trust domain of the SPIFFE IDs carried by the service certificates, if any
+	GetSPIFFETrustDomain() string
+
+	// GetTrustDomain returns the trust domain of the certificates issued by the mesh instance, if any
+	GetTrustDomain() string
+
+	// GetTrustDomainAliases returns the trust domains the certificates of upstream service identities are also accepted from
+	GetTrustDomainAliases() []string
+
+	// GetFederatedTrustDomains returns the external trust domains federated with the mesh
+	GetFederatedTrustDomains() []configv1alpha1.FederatedTrustDomainSpec
+
 	// GetOutboundIPRangeExclusionList returns the list of IP ranges of the form x.x.x.x/y to exclude from outbound sidecar interception
 	GetOutboundIPRangeExclusionList() []string
 
@@ -97,6 +128,9 @@ type Configurator interface {
 	// IsPrivilegedInitContainer determines whether init containers should be privileged
 	IsPrivilegedInitContainer() bool
 
+	// IsInitContainerDistroless determines whether the init container image only provides iptables-restore
+	IsInitContainerDistroless() bool
+
 	// GetConfigResyncInterval returns the duration for resync interval.
 	// If error or non-parsable value, returns 0 duration
 	GetConfigResyncInterval() time.Duration
@@ -109,4 +143,72 @@ type Configurator interface {
 
 	// GetFeatureFlags returns OSM's feature flags
 	GetFeatureFlags() configv1alpha1.FeatureFlags
+
+	// GetEndpointFlapDampeningConfig returns the configuration used to pin out endpoints whose readiness flaps
+	GetEndpointFlapDampeningConfig() configv1alpha1.EndpointFlapDampeningSpec
+
+	// GetDNSResolutionConfig returns the configuration used by proxies to resolve the addresses of clusters using DNS
+	GetDNSResolutionConfig() configv1alpha1.DNSResolutionSpec
+
+	// IsProtocolDetectionEnabled returns whether the HTTP ports of services in the given namespace detect the
+	// application protocol of each connection
+	IsProtocolDetectionEnabled(namespace string) bool
+
+	// GetProtocolDetectionTimeout returns how long proxies wait for the first bytes of a connection to detect its
+	// application protocol
+	GetProtocolDetectionTimeout() time.Duration
+
+	// GetCompressionConfig returns the configuration used by proxies to compress the responses of HTTP services
+	GetCompressionConfig() configv1alpha1.CompressionSpec
+
+	// GetRequestLimitsConfig returns the limits on the size of the requests to HTTP services
+	GetRequestLimitsConfig() configv1alpha1.RequestLimitsSpec
+
+	// GetClientCertDetailsConfig returns how the details of client certificates are forwarded to applications
+	GetClientCertDetailsConfig() configv1alpha1.ClientCertDetailsSpec
+
+	// GetRBACAuditConfig returns how the authorization decisions of the RBAC policies are audited
+	GetRBACAuditConfig() configv1alpha1.RBACAuditSpec
+
+	// GetRouteRegexConfig returns how the regular expressions of the route matches of HTTPRouteGroups are programmed
+	GetRouteRegexConfig() configv1alpha1.RouteRegexSpec
+
+	// GetHTTPSanitizationConfig returns how the paths and headers of HTTP requests are normalized and sanitized
+	GetHTTPSanitizationConfig() configv1alpha1.HTTPSanitizationSpec
+
+	// GetDownstreamLimitsConfig returns the limits on the downstream connections and requests to services
+	GetDownstreamLimitsConfig() configv1alpha1.DownstreamLimitsSpec
+
+	// GetIngressClientAddressConfig returns how the address of the clients of ingress requests is determined
+	GetIngressClientAddressConfig() configv1alpha1.IngressClientAddressSpec
+
+	// IsNamespaceIsolationEnabled determines whether the outbound traffic of the namespaces is restricted to their own
+	// namespace and the namespaces allowed by their NamespaceIsolation policies
+	IsNamespaceIsolationEnabled() bool
+
+	// GetOutboundPassthroughConfig returns the destinations allowed through the outbound passthrough cluster
+	GetOutboundPassthroughConfig() configv1alpha1.OutboundPassthroughSpec
+
+	// IncludeTerminatingEndpoints determines whether the serving endpoints of terminating pods are programmed as draining
+	IncludeTerminatingEndpoints() bool
+
+	// GetAdminInterfaceConfig returns the exposure of the admin interface of proxy sidecars
+	GetAdminInterfaceConfig() configv1alpha1.AdminInterfaceSpec
+
+	// GetOverloadManagerConfig returns the configuration of the overload manager of proxy sidecars
+	GetOverloadManagerConfig() configv1alpha1.OverloadManagerSpec
+
+	// GetEnvoyDrainStrategy returns the strategy of proxy sidecars to drain the connections of their listeners
+	GetEnvoyDrainStrategy() string
+
+	// GetStatsConfig returns the configuration of the stats generated by proxy sidecars
+	GetStatsConfig() configv1alpha1.StatsSpec
+
+	// GetPrometheusScrapingConfig returns how the metrics of the proxy sidecars and the control plane are scraped by
+	// Prometheus, with the defaults of its unset fields applied
+	GetPrometheusScrapingConfig() configv1alpha1.PrometheusScrapingSpec
+
+	// GetProvisioningConfig returns the Grafana dashboards and Prometheus alert rules provisioned by the OSM controller,
+	// with the defaults of its unset fields applied
+	GetProvisioningConfig() configv1alpha1.ProvisioningSpec
 }
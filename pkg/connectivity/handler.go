@@ -0,0 +1,39 @@
+package connectivity
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// GetVerifyHTTPHandler returns an HTTP handler verifying the connectivity from the pod identified by the 'srcNamespace'
+// and 'srcPod' query parameters to the service identified by the 'dstNamespace' and 'dstService' query parameters,
+// and returning the report of the verification in JSON.
+func (v *Verifier) GetVerifyHTTPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		srcNamespace, srcPod := query.Get(sourceNamespaceQueryKey), query.Get(sourcePodQueryKey)
+		dst := service.MeshService{
+			Namespace: query.Get(destinationNamespaceQueryKey),
+			Name:      query.Get(destinationServiceQueryKey),
+		}
+		if srcNamespace == "" || srcPod == "" || dst.Namespace == "" || dst.Name == "" {
+			http.Error(w, fmt.Sprintf("The %s, %s, %s and %s query parameters are required",
+				sourceNamespaceQueryKey, sourcePodQueryKey, destinationNamespaceQueryKey, destinationServiceQueryKey), http.StatusBadRequest)
+			return
+		}
+
+		report := v.Verify(srcNamespace, srcPod, dst)
+		jsonReport, err := json.Marshal(report)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error marshaling connectivity report %+v", report)
+			http.Error(w, "Error marshaling connectivity report", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, string(jsonReport))
+	})
+}
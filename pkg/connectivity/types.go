@@ -0,0 +1,81 @@
+// Package connectivity implements a diagnostic verifying whether a pod in the mesh can reach a service, by walking
+// the state of the mesh catalog and of the xDS configuration generated for the proxy of the pod, to pinpoint why
+// traffic between them fails.
+package connectivity
+
+import (
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy/registry"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var (
+	log = logger.New("connectivity")
+)
+
+// Status is the type used to represent the outcome of a check.
+type Status string
+
+const (
+	// StatusPass indicates the check passed
+	StatusPass Status = "pass"
+
+	// StatusWarn indicates the check passed, but found a condition which may prevent the traffic from flowing
+	StatusWarn Status = "warn"
+
+	// StatusFail indicates the check failed, the traffic from the source to the destination is expected to fail
+	StatusFail Status = "fail"
+)
+
+const (
+	// sourceNamespaceQueryKey and sourcePodQueryKey are the query parameters of the verification handler
+	// identifying the source pod
+	sourceNamespaceQueryKey = "srcNamespace"
+	sourcePodQueryKey       = "srcPod"
+
+	// destinationNamespaceQueryKey and destinationServiceQueryKey are the query parameters of the verification
+	// handler identifying the destination service
+	destinationNamespaceQueryKey = "dstNamespace"
+	destinationServiceQueryKey   = "dstService"
+)
+
+// Check is the type used to represent the outcome of a single step of the verification.
+type Check struct {
+	// Name describes what was checked
+	Name string `json:"name"`
+
+	// Status is the outcome of the check
+	Status Status `json:"status"`
+
+	// Message details the outcome of the check, and how to address it if it did not pass
+	Message string `json:"message"`
+}
+
+// Report is the type used to represent the outcome of the verification of the connectivity from a source pod to a
+// destination service.
+type Report struct {
+	// Source is the namespaced name of the source pod
+	Source string `json:"source"`
+
+	// Destination is the namespaced name of the destination service
+	Destination string `json:"destination"`
+
+	// Status is the overall outcome of the verification: fail if any check failed, warn if any check warned
+	Status Status `json:"status"`
+
+	// Checks are the checks performed, in order. The verification stops at the first failing check whose outcome
+	// the following checks depend on.
+	Checks []Check `json:"checks"`
+}
+
+// Verifier verifies the connectivity between pods and services in the mesh.
+type Verifier struct {
+	meshCatalog    catalog.MeshCataloger
+	kubeController k8s.Controller
+	proxyRegistry  *registry.ProxyRegistry
+	certManager    certificate.Manager
+	cfg            configurator.Configurator
+}
@@ -0,0 +1,266 @@
+package connectivity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/registry"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// NewVerifier returns a Verifier walking the given mesh catalog, the proxies connected to the given registry and
+// the certificates issued by the given certificate manager.
+func NewVerifier(meshCatalog catalog.MeshCataloger, proxyRegistry *registry.ProxyRegistry, certManager certificate.Manager, cfg configurator.Configurator) *Verifier {
+	return &Verifier{
+		meshCatalog:    meshCatalog,
+		kubeController: meshCatalog.GetKubeController(),
+		proxyRegistry:  proxyRegistry,
+		certManager:    certManager,
+		cfg:            cfg,
+	}
+}
+
+// Verify returns a report of the checks verifying whether the given pod can reach the given service.
+func (v *Verifier) Verify(srcNamespace string, srcPodName string, dst service.MeshService) *Report {
+	report := &Report{
+		Source:      fmt.Sprintf("%s/%s", srcNamespace, srcPodName),
+		Destination: dst.String(),
+	}
+	defer report.setStatus()
+
+	srcPod := v.verifySource(report, srcNamespace, srcPodName)
+	if !v.verifyDestination(report, dst) || srcPod == nil {
+		return report
+	}
+
+	srcIdentity := identity.K8sServiceAccount{Name: srcPod.Spec.ServiceAccountName, Namespace: srcPod.Namespace}.ToServiceIdentity()
+	dstIdentities, err := v.meshCatalog.ListServiceIdentitiesForService(dst)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error listing service identities of service %s", dst)
+	}
+
+	if v.verifyTrafficPolicies(report, srcIdentity, dst, dstIdentities) {
+		v.verifyProxyConfig(report, srcPod, srcIdentity, dst)
+	}
+	v.verifyCertificates(report, srcIdentity, dstIdentities)
+
+	return report
+}
+
+// verifySource checks the given source pod is part of the mesh, and returns it if so
+func (v *Verifier) verifySource(report *Report, namespace string, podName string) *corev1.Pod {
+	if !v.kubeController.IsMonitoredNamespace(namespace) {
+		report.add("source namespace is monitored", StatusFail,
+			"Namespace %s is not monitored by the mesh, add it with 'osm namespace add %s'", namespace, namespace)
+		return nil
+	}
+	report.add("source namespace is monitored", StatusPass, "Namespace %s is monitored by the mesh", namespace)
+
+	pod := v.kubeController.GetPod(namespace, podName)
+	if pod == nil {
+		report.add("source pod has a sidecar", StatusFail, "Pod %s/%s was not found", namespace, podName)
+		return nil
+	}
+	if _, ok := pod.Labels[constants.EnvoyUniqueIDLabelName]; !ok {
+		report.add("source pod has a sidecar", StatusFail,
+			"Pod %s/%s has no Envoy sidecar, it was created before sidecar injection was enabled in its namespace and must be restarted", namespace, podName)
+		return nil
+	}
+	report.add("source pod has a sidecar", StatusPass, "Pod %s/%s has an Envoy sidecar", namespace, podName)
+	return pod
+}
+
+// verifyDestination checks the given destination service is part of the mesh and has ready endpoints
+func (v *Verifier) verifyDestination(report *Report, dst service.MeshService) bool {
+	if !v.kubeController.IsMonitoredNamespace(dst.Namespace) {
+		return report.add("destination namespace is monitored", StatusFail,
+			"Namespace %s is not monitored by the mesh, add it with 'osm namespace add %s'", dst.Namespace, dst.Namespace)
+	}
+	report.add("destination namespace is monitored", StatusPass, "Namespace %s is monitored by the mesh", dst.Namespace)
+
+	if v.kubeController.GetService(dst) == nil {
+		return report.add("destination service exists", StatusFail, "Service %s was not found", dst)
+	}
+	report.add("destination service exists", StatusPass, "Service %s exists", dst)
+
+	endpoints, err := v.kubeController.GetEndpoints(dst)
+	if err != nil || endpoints == nil {
+		return report.add("destination service has ready endpoints", StatusFail, "Endpoints of service %s were not found", dst)
+	}
+	var readyAddresses int
+	for _, subset := range endpoints.Subsets {
+		readyAddresses += len(subset.Addresses)
+	}
+	if readyAddresses == 0 {
+		return report.add("destination service has ready endpoints", StatusFail,
+			"Service %s has no ready endpoints, check its selector matches pods which are ready", dst)
+	}
+	return report.add("destination service has ready endpoints", StatusPass, "Service %s has %d ready endpoints", dst, readyAddresses)
+}
+
+// verifyTrafficPolicies checks the traffic policies allow the given source identity to reach the given destination
+// service, both on the outbound side of the source and the inbound side of the destination
+func (v *Verifier) verifyTrafficPolicies(report *Report, srcIdentity identity.ServiceIdentity, dst service.MeshService, dstIdentities []identity.ServiceIdentity) bool {
+	if v.cfg.IsPermissiveTrafficPolicyMode() {
+		return report.add("traffic is allowed by policies", StatusPass,
+			"Permissive traffic policy mode is enabled, all traffic within the mesh is allowed")
+	}
+
+	var outboundAllowed bool
+	for _, svc := range v.meshCatalog.ListOutboundServicesForIdentity(srcIdentity) {
+		if svc.Equals(dst) {
+			outboundAllowed = true
+			break
+		}
+	}
+	if !outboundAllowed {
+		return report.add("traffic is allowed by policies", StatusFail,
+			"No TrafficTarget has service identity %s as a source and a service identity of service %s as its destination", srcIdentity, dst)
+	}
+
+	for _, dstIdentity := range dstIdentities {
+		inboundIdentities, err := v.meshCatalog.ListInboundServiceIdentities(dstIdentity)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error listing inbound service identities of service identity %s", dstIdentity)
+			continue
+		}
+		for _, inboundIdentity := range inboundIdentities {
+			if inboundIdentity == srcIdentity {
+				return report.add("traffic is allowed by policies", StatusPass,
+					"A TrafficTarget allows service identity %s to access service identity %s of service %s", srcIdentity, dstIdentity, dst)
+			}
+		}
+	}
+	return report.add("traffic is allowed by policies", StatusFail,
+		"The inbound policies of service identities %v of service %s do not allow service identity %s", dstIdentities, dst, srcIdentity)
+}
+
+// verifyProxyConfig checks the proxy of the given source pod is connected to the control plane and the
+// configuration generated for it routes the traffic to the given destination service
+func (v *Verifier) verifyProxyConfig(report *Report, srcPod *corev1.Pod, srcIdentity identity.ServiceIdentity, dst service.MeshService) {
+	proxyUUID, err := uuid.Parse(srcPod.Labels[constants.EnvoyUniqueIDLabelName])
+	if err != nil {
+		report.add("source proxy is connected", StatusFail, "Pod %s/%s has an invalid %s label", srcPod.Namespace, srcPod.Name, constants.EnvoyUniqueIDLabelName)
+		return
+	}
+	cn := envoy.NewXDSCertCommonName(proxyUUID, envoy.KindSidecar, srcPod.Spec.ServiceAccountName, srcPod.Namespace)
+	proxy, ok := v.proxyRegistry.ListConnectedProxies()[cn]
+	if !ok {
+		report.add("source proxy is connected", StatusFail,
+			"The proxy of pod %s/%s is not connected to the control plane, check the logs of its Envoy container", srcPod.Namespace, srcPod.Name)
+		return
+	}
+	report.add("source proxy is connected", StatusPass, "The proxy of pod %s/%s is connected to the control plane", srcPod.Namespace, srcPod.Name)
+
+	var unacknowledged []string
+	for _, typeURI := range []envoy.TypeURI{envoy.TypeCDS, envoy.TypeEDS, envoy.TypeLDS, envoy.TypeRDS} {
+		if proxy.GetLastAppliedVersion(typeURI) == 0 {
+			unacknowledged = append(unacknowledged, typeURI.Short())
+		}
+	}
+	if len(unacknowledged) > 0 {
+		report.add("source proxy applied its configuration", StatusWarn,
+			"The proxy has not acknowledged any %s configuration yet, check the logs of its Envoy container for rejected configuration", strings.Join(unacknowledged, ", "))
+	} else {
+		report.add("source proxy applied its configuration", StatusPass, "The proxy acknowledged its CDS, EDS, LDS and RDS configuration")
+	}
+
+	endpoints, err := v.meshCatalog.ListEndpointsForServiceIdentity(srcIdentity, dst)
+	if err != nil || len(endpoints) == 0 {
+		report.add("outbound endpoints are configured", StatusFail,
+			"No endpoints of service %s are configured for the proxy, check the pods of the service run with a service account allowed by the policies", dst)
+		return
+	}
+	report.add("outbound endpoints are configured", StatusPass, "%d endpoints of service %s are configured for the proxy", len(endpoints), dst)
+
+	if !v.hasHTTPPort(dst) {
+		return
+	}
+	for _, policy := range v.meshCatalog.ListOutboundTrafficPolicies(srcIdentity) {
+		if policy.Name == dst.FQDN() && len(policy.Routes) > 0 {
+			report.add("outbound HTTP routes are configured", StatusPass,
+				"%d routes to service %s are configured for the hostnames %s", len(policy.Routes), dst, strings.Join(policy.Hostnames, ", "))
+			return
+		}
+	}
+	report.add("outbound HTTP routes are configured", StatusFail,
+		"No HTTP routes to service %s are configured for the proxy, check the HTTPRouteGroup referenced by the TrafficTarget matches the requests", dst)
+}
+
+// verifyCertificates checks the root certificate and the certificates of the given source and destination
+// identities are valid
+func (v *Verifier) verifyCertificates(report *Report, srcIdentity identity.ServiceIdentity, dstIdentities []identity.ServiceIdentity) {
+	now := time.Now()
+
+	rootCert, err := v.certManager.GetRootCertificate()
+	switch {
+	case err != nil:
+		report.add("root certificate is valid", StatusFail, "Error getting the root certificate: %s", err)
+	case rootCert.GetExpiration().Before(now):
+		report.add("root certificate is valid", StatusFail, "The root certificate expired at %s", rootCert.GetExpiration().Format(time.RFC3339))
+	default:
+		report.add("root certificate is valid", StatusPass, "The root certificate expires at %s", rootCert.GetExpiration().Format(time.RFC3339))
+	}
+
+	for _, svcIdentity := range append([]identity.ServiceIdentity{srcIdentity}, dstIdentities...) {
+		name := fmt.Sprintf("certificate of service identity %s is valid", svcIdentity)
+		cert, err := v.certManager.GetCertificate(certificate.CommonName(svcIdentity))
+		switch {
+		case err != nil:
+			report.add(name, StatusWarn, "No certificate was issued for service identity %s, it is issued when its proxies request it", svcIdentity)
+		case cert.GetExpiration().Before(now):
+			report.add(name, StatusFail, "The certificate of service identity %s expired at %s", svcIdentity, cert.GetExpiration().Format(time.RFC3339))
+		default:
+			report.add(name, StatusPass, "The certificate of service identity %s expires at %s", svcIdentity, cert.GetExpiration().Format(time.RFC3339))
+		}
+	}
+}
+
+// hasHTTPPort returns whether the given service has a port whose traffic is routed by HTTP routes
+func (v *Verifier) hasHTTPPort(svc service.MeshService) bool {
+	portToProtocol, err := v.meshCatalog.GetPortToProtocolMappingForService(svc)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error getting port to protocol mapping of service %s", svc)
+		return false
+	}
+	for _, protocol := range portToProtocol {
+		if protocol == constants.ProtocolHTTP || protocol == constants.ProtocolGRPC {
+			return true
+		}
+	}
+	return false
+}
+
+// add adds a check with the given outcome to the report, and returns whether the check did not fail
+func (r *Report) add(name string, status Status, format string, args ...interface{}) bool {
+	r.Checks = append(r.Checks, Check{
+		Name:    name,
+		Status:  status,
+		Message: fmt.Sprintf(format, args...),
+	})
+	return status != StatusFail
+}
+
+// setStatus sets the overall outcome of the report from the outcome of its checks
+func (r *Report) setStatus() {
+	r.Status = StatusPass
+	for _, check := range r.Checks {
+		if check.Status == StatusFail {
+			r.Status = StatusFail
+			return
+		}
+		if check.Status == StatusWarn {
+			r.Status = StatusWarn
+		}
+	}
+}
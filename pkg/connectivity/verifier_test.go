@@ -0,0 +1,212 @@
+package connectivity
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/endpoint"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/registry"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestVerify(t *testing.T) {
+	proxyUUID := uuid.New()
+	srcIdentity := identity.K8sServiceAccount{Name: "bookbuyer", Namespace: "bookbuyer"}.ToServiceIdentity()
+	dstIdentity := identity.K8sServiceAccount{Name: "bookstore", Namespace: "bookstore"}.ToServiceIdentity()
+	dst := service.MeshService{Namespace: "bookstore", Name: "bookstore"}
+
+	srcPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "bookbuyer",
+			Name:      "bookbuyer-client",
+			Labels:    map[string]string{constants.EnvoyUniqueIDLabelName: proxyUUID.String()},
+		},
+		Spec: corev1.PodSpec{ServiceAccountName: "bookbuyer"},
+	}
+	podWithoutSidecar := srcPod.DeepCopy()
+	podWithoutSidecar.Labels = nil
+
+	readyEndpoints := &corev1.Endpoints{
+		Subsets: []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}}},
+	}
+
+	testCases := []struct {
+		name             string
+		srcMonitored     bool
+		srcPod           *corev1.Pod
+		permissive       bool
+		outboundServices []service.MeshService
+		proxyConnected   bool
+		expectedStatus   Status
+		expectedChecks   map[string]Status
+	}{
+		{
+			name:           "source namespace not monitored",
+			srcMonitored:   false,
+			expectedStatus: StatusFail,
+			expectedChecks: map[string]Status{
+				"source namespace is monitored":           StatusFail,
+				"destination service has ready endpoints": StatusPass,
+			},
+		},
+		{
+			name:           "source pod without sidecar",
+			srcMonitored:   true,
+			srcPod:         podWithoutSidecar,
+			expectedStatus: StatusFail,
+			expectedChecks: map[string]Status{
+				"source pod has a sidecar": StatusFail,
+			},
+		},
+		{
+			name:             "no traffic target allowing the source",
+			srcMonitored:     true,
+			srcPod:           srcPod,
+			outboundServices: nil,
+			expectedStatus:   StatusFail,
+			expectedChecks: map[string]Status{
+				"source pod has a sidecar":       StatusPass,
+				"traffic is allowed by policies": StatusFail,
+				"root certificate is valid":      StatusPass,
+			},
+		},
+		{
+			name:             "source proxy not connected",
+			srcMonitored:     true,
+			srcPod:           srcPod,
+			outboundServices: []service.MeshService{dst},
+			proxyConnected:   false,
+			expectedStatus:   StatusFail,
+			expectedChecks: map[string]Status{
+				"traffic is allowed by policies": StatusPass,
+				"source proxy is connected":      StatusFail,
+			},
+		},
+		{
+			name:           "traffic allowed in permissive mode, destination certificate not issued yet",
+			srcMonitored:   true,
+			srcPod:         srcPod,
+			permissive:     true,
+			proxyConnected: true,
+			expectedStatus: StatusWarn,
+			expectedChecks: map[string]Status{
+				"traffic is allowed by policies":                                             StatusPass,
+				"source proxy is connected":                                                  StatusPass,
+				"source proxy applied its configuration":                                     StatusPass,
+				"outbound endpoints are configured":                                          StatusPass,
+				"outbound HTTP routes are configured":                                        StatusPass,
+				"certificate of service identity bookbuyer.bookbuyer.cluster.local is valid": StatusPass,
+				"certificate of service identity bookstore.bookstore.cluster.local is valid": StatusWarn,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockCertManager := certificate.NewMockManager(mockCtrl)
+			validCert := certificate.NewMockCertificater(mockCtrl)
+			validCert.EXPECT().GetExpiration().Return(time.Now().Add(time.Hour)).AnyTimes()
+
+			mockCatalog.EXPECT().GetKubeController().Return(mockKubeController)
+			mockKubeController.EXPECT().IsMonitoredNamespace("bookbuyer").Return(tc.srcMonitored).AnyTimes()
+			mockKubeController.EXPECT().IsMonitoredNamespace("bookstore").Return(true).AnyTimes()
+			mockKubeController.EXPECT().GetPod("bookbuyer", "bookbuyer-client").Return(tc.srcPod).AnyTimes()
+			mockKubeController.EXPECT().GetService(dst).Return(&corev1.Service{}).AnyTimes()
+			mockKubeController.EXPECT().GetEndpoints(dst).Return(readyEndpoints, nil).AnyTimes()
+			mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(tc.permissive).AnyTimes()
+			mockCatalog.EXPECT().ListServiceIdentitiesForService(dst).Return([]identity.ServiceIdentity{dstIdentity}, nil).AnyTimes()
+			mockCatalog.EXPECT().ListOutboundServicesForIdentity(srcIdentity).Return(tc.outboundServices).AnyTimes()
+			mockCatalog.EXPECT().ListInboundServiceIdentities(dstIdentity).Return([]identity.ServiceIdentity{srcIdentity}, nil).AnyTimes()
+			mockCatalog.EXPECT().ListEndpointsForServiceIdentity(srcIdentity, dst).Return([]endpoint.Endpoint{{Port: 80}}, nil).AnyTimes()
+			mockCatalog.EXPECT().GetPortToProtocolMappingForService(dst).Return(map[uint32]string{80: constants.ProtocolHTTP}, nil).AnyTimes()
+			mockCatalog.EXPECT().ListOutboundTrafficPolicies(srcIdentity).Return([]*trafficpolicy.OutboundTrafficPolicy{
+				{
+					Name:      dst.FQDN(),
+					Hostnames: []string{"bookstore.bookstore"},
+					Routes:    []*trafficpolicy.RouteWeightedClusters{{}},
+				},
+			}).AnyTimes()
+			mockCertManager.EXPECT().GetRootCertificate().Return(validCert, nil).AnyTimes()
+			mockCertManager.EXPECT().GetCertificate(certificate.CommonName(srcIdentity)).Return(validCert, nil).AnyTimes()
+			mockCertManager.EXPECT().GetCertificate(certificate.CommonName(dstIdentity)).Return(nil, errors.New("not found")).AnyTimes()
+
+			proxyRegistry := registry.NewProxyRegistry(nil)
+			if tc.proxyConnected {
+				proxy, err := envoy.NewProxy(envoy.NewXDSCertCommonName(proxyUUID, envoy.KindSidecar, "bookbuyer", "bookbuyer"), "123", nil)
+				assert.Nil(err)
+				for _, typeURI := range []envoy.TypeURI{envoy.TypeCDS, envoy.TypeEDS, envoy.TypeLDS, envoy.TypeRDS} {
+					proxy.SetLastAppliedVersion(typeURI, 1)
+				}
+				proxyRegistry.RegisterProxy(proxy)
+			}
+
+			verifier := NewVerifier(mockCatalog, proxyRegistry, mockCertManager, mockConfigurator)
+			report := verifier.Verify("bookbuyer", "bookbuyer-client", dst)
+
+			assert.Equal("bookbuyer/bookbuyer-client", report.Source)
+			assert.Equal("bookstore/bookstore", report.Destination)
+			assert.Equal(tc.expectedStatus, report.Status)
+
+			checks := map[string]Status{}
+			for _, check := range report.Checks {
+				checks[check.Name] = check.Status
+			}
+			for name, status := range tc.expectedChecks {
+				assert.Equal(status, checks[name], name)
+			}
+		})
+	}
+}
+
+func TestGetVerifyHTTPHandler(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockCatalog.EXPECT().GetKubeController().Return(mockKubeController)
+	mockKubeController.EXPECT().IsMonitoredNamespace(gomock.Any()).Return(false).AnyTimes()
+
+	verifier := NewVerifier(mockCatalog, registry.NewProxyRegistry(nil), nil, nil)
+
+	// Missing query parameters
+	rr := httptest.NewRecorder()
+	verifier.GetVerifyHTTPHandler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/verify-connectivity?srcNamespace=bookbuyer", nil))
+	assert.Equal(http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	verifier.GetVerifyHTTPHandler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet,
+		"/verify-connectivity?srcNamespace=bookbuyer&srcPod=bookbuyer-client&dstNamespace=bookstore&dstService=bookstore", nil))
+	assert.Equal(http.StatusOK, rr.Code)
+
+	report := &Report{}
+	assert.Nil(json.Unmarshal(rr.Body.Bytes(), report))
+	assert.Equal(StatusFail, report.Status)
+	assert.Equal("source namespace is monitored", report.Checks[0].Name)
+}
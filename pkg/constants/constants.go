@@ -7,6 +7,9 @@ const (
 	// KubeProviderName is a string constant used for the ID string of the Kubernetes endpoints provider.
 	KubeProviderName = "Kubernetes"
 
+	// ConsulProviderName is a string constant used for the ID string of the Consul catalog endpoints provider.
+	ConsulProviderName = "Consul"
+
 	// WildcardIPAddr is a string constant.
 	WildcardIPAddr = "0.0.0.0"
 
@@ -34,6 +37,14 @@ const (
 	// EnvoyUID is the Envoy's User ID
 	EnvoyUID int64 = 1500
 
+	// EnvoyGID is the Envoy's Group ID, identifying the traffic of Envoy when it runs as root to preserve the source IP
+	// of inbound traffic
+	EnvoyGID int64 = 1500
+
+	// EnvoyOriginalSourceMark is the mark of the connections Envoy proxies inbound traffic on from the original source
+	// IP of the clients, used to route the responses of the application back to Envoy
+	EnvoyOriginalSourceMark = 1500
+
 	// EnvoyWindowsUser is the Envoy's User name on Windows.
 	EnvoyWindowsUser string = "EnvoyUser"
 
@@ -58,6 +69,26 @@ const (
 	// DefaultEnvoyLogLevel is the default envoy log level if not defined in the osm MeshConfig
 	DefaultEnvoyLogLevel = "error"
 
+	// DefaultEnvoyDrainStrategy is the default strategy of envoy to drain the connections of its listeners if not
+	// defined in the osm MeshConfig
+	DefaultEnvoyDrainStrategy = "gradual"
+
+	// DefaultRetryBudgetPercent is the default maximum percentage of the active requests to an upstream service
+	// that can be retries
+	DefaultRetryBudgetPercent = uint32(20)
+
+	// DefaultMinRetryConcurrency is the default number of concurrent retries to an upstream service allowed
+	// whatever the number of active requests
+	DefaultMinRetryConcurrency = uint32(3)
+
+	// DefaultRetryOn is the default comma-separated list of the conditions the requests to an upstream service are
+	// retried on when the upstream service has a retry policy
+	DefaultRetryOn = "5xx,reset,connect-failure"
+
+	// DefaultNumRetries is the default maximum number of retries of a request to an upstream service when the
+	// upstream service has a retry policy
+	DefaultNumRetries = uint32(1)
+
 	// DefaultOSMLogLevel is the default OSM log level if none is specified
 	DefaultOSMLogLevel = "info"
 
@@ -75,6 +106,10 @@ const (
 	// EnvoyPrometheusInboundListenerPort is Envoy's inbound listener port number for prometheus
 	EnvoyPrometheusInboundListenerPort = 15010
 
+	// EnvoyDNSListenerPort is Envoy's DNS listener port number, to which the DNS queries of the pod are redirected when
+	// the DNS proxy is enabled
+	EnvoyDNSListenerPort = 15053
+
 	// InjectorWebhookPort is the port on which the sidecar injection webhook listens
 	InjectorWebhookPort = 9090
 
@@ -173,6 +208,56 @@ const (
 
 	// MetricsAnnotation is the annotation used for enabling/disabling metrics
 	MetricsAnnotation = "openservicemesh.io/metrics"
+
+	// SourceIPPreservationAnnotation is the annotation used for enabling/disabling the preservation of the source IP of
+	// the inbound traffic proxied to the applications of a namespace, instead of the traffic originating from localhost
+	SourceIPPreservationAnnotation = "openservicemesh.io/preserve-source-ip"
+
+	// MulticlusterExportAnnotation is the annotation used to export a service to remote clusters
+	MulticlusterExportAnnotation = "openservicemesh.io/multicluster-export"
+
+	// CompressionAnnotation is the annotation used to enable/disable the compression of the responses of a service
+	CompressionAnnotation = "openservicemesh.io/compression"
+
+	// CompressionAlgorithmsAnnotation is the annotation used to configure the comma-separated compression algorithms
+	// offered to the clients of a service
+	CompressionAlgorithmsAnnotation = "openservicemesh.io/compression-algorithms"
+
+	// CompressionContentTypesAnnotation is the annotation used to configure the comma-separated content types of the
+	// responses of a service that are compressed
+	CompressionContentTypesAnnotation = "openservicemesh.io/compression-content-types"
+
+	// CompressionMinContentLengthAnnotation is the annotation used to configure the minimum size in bytes of the
+	// responses of a service that are compressed
+	CompressionMinContentLengthAnnotation = "openservicemesh.io/compression-min-content-length"
+
+	// MaxRequestBytesAnnotation is the annotation used to configure the maximum size in bytes of the body of the
+	// requests to a service
+	MaxRequestBytesAnnotation = "openservicemesh.io/max-request-bytes"
+
+	// LoadSheddingTargetLatencyAnnotation is the annotation used to enable the shedding of the excess load of a service
+	// by its proxies, and to configure the latency the service is expected to serve requests in while overloaded
+	LoadSheddingTargetLatencyAnnotation = "openservicemesh.io/load-shedding-target-latency"
+
+	// LoadSheddingMinRPSAnnotation is the annotation used to configure the minimum rate of requests per second a
+	// service keeps serving while its proxies shed its excess load
+	LoadSheddingMinRPSAnnotation = "openservicemesh.io/load-shedding-min-rps"
+
+	// MaxConnectionsAnnotation is the annotation used to configure the maximum number of concurrent downstream
+	// connections to each port of a service on each of its proxies
+	MaxConnectionsAnnotation = "openservicemesh.io/max-connections"
+
+	// IdleTimeoutAnnotation is the annotation used to configure how long idle downstream connections to a service are
+	// kept open
+	IdleTimeoutAnnotation = "openservicemesh.io/idle-timeout"
+
+	// RequestTimeoutAnnotation is the annotation used to configure how long the proxies of a service wait to receive
+	// the entire requests to it
+	RequestTimeoutAnnotation = "openservicemesh.io/request-timeout"
+
+	// StreamIdleTimeoutAnnotation is the annotation used to configure how long requests to a service are kept open
+	// without any activity
+	StreamIdleTimeoutAnnotation = "openservicemesh.io/stream-idle-timeout"
 )
 
 // Labels used by the control plane
@@ -191,6 +276,9 @@ const (
 
 	// PrometheusPathAnnotation is the annotation used to configure the path to scrape on
 	PrometheusPathAnnotation = "prometheus.io/path"
+
+	// PrometheusSchemeAnnotation is the annotation used to configure the scheme to scrape with
+	PrometheusSchemeAnnotation = "prometheus.io/scheme"
 )
 
 // App labels as defined in the "osm.labels" template in _helpers.tpl of the Helm chart.
@@ -204,6 +292,31 @@ const (
 // OSM HTTP Server Paths
 const (
 	HTTPServerSmiVersionPath = "/smi/version"
+
+	// HTTPServerProxyInventoryPath is the path of the inventory of the proxies connected to osm-controller
+	HTTPServerProxyInventoryPath = "/proxies"
+
+	// HTTPServerRBACDenialsPath is the path of the recent inbound requests denied by the RBAC policies of the proxies
+	HTTPServerRBACDenialsPath = "/rbac-denials"
+
+	// HTTPServerVerifyConnectivityPath is the path of the verification of the connectivity from a pod to a service
+	HTTPServerVerifyConnectivityPath = "/verify-connectivity"
+
+	// HTTPServerSupportBundlePath is the path of the support bundle capturing the state of the control plane
+	HTTPServerSupportBundlePath = "/support-bundle"
+
+	// HTTPServerErrorsPath is the path of the recent error counts by error code, proxy and resource
+	HTTPServerErrorsPath = "/errors"
+
+	// HTTPServerPreflightPath is the path of the report of the preflight checks of the mesh
+	HTTPServerPreflightPath = "/preflight"
+
+	// HTTPServerBackupPath is the path of the backup of the policy state of the mesh
+	HTTPServerBackupPath = "/backup"
+
+	// HTTPServerMetricsSummaryPath is the path of the metrics of the inbound traffic of a service aggregated across
+	// the proxy sidecars of its pods
+	HTTPServerMetricsSummaryPath = "/metrics-summary"
 )
 
 // Application protocols
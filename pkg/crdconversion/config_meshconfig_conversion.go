@@ -1,10 +1,15 @@
 package crdconversion
 
 import (
+	"encoding/json"
 	"net/http"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
 )
 
 // serveMeshConfigConversion servers endpoint for the converter defined as convertMeshConfig function.
@@ -15,13 +20,43 @@ func serveMeshConfigConversion(w http.ResponseWriter, r *http.Request) {
 // convertMeshConfig contains the business logic to convert meshconfigs.config.openservicemesh.io CRD
 // Example implementation reference : https://github.com/kubernetes/kubernetes/blob/release-1.21/test/images/agnhost/crd-conversion-webhook/converter/example_converter.go
 func convertMeshConfig(Object *unstructured.Unstructured, toVersion string) (*unstructured.Unstructured, metav1.Status) {
-	convertedObject := Object.DeepCopy()
 	fromVersion := Object.GetAPIVersion()
 
 	if toVersion == fromVersion {
 		return nil, statusErrorWithMessage("MeshConfig: conversion from a version to itself should not call the webhook: %s", toVersion)
 	}
 
-	log.Debug().Msg("MeshConfig: successfully converted object")
+	var converted runtime.Object
+	switch {
+	case fromVersion == configv1alpha1.SchemeGroupVersion.String() && toVersion == configv1alpha2.SchemeGroupVersion.String():
+		meshConfig := &configv1alpha1.MeshConfig{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(Object.Object, meshConfig); err != nil {
+			return nil, statusErrorWithMessage("MeshConfig: error decoding %s object: %v", fromVersion, err)
+		}
+		converted = configv1alpha2.ConvertFromV1alpha1(meshConfig)
+
+	case fromVersion == configv1alpha2.SchemeGroupVersion.String() && toVersion == configv1alpha1.SchemeGroupVersion.String():
+		meshConfig := &configv1alpha2.MeshConfig{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(Object.Object, meshConfig); err != nil {
+			return nil, statusErrorWithMessage("MeshConfig: error decoding %s object: %v", fromVersion, err)
+		}
+		converted = configv1alpha2.ConvertToV1alpha1(meshConfig)
+
+	default:
+		return nil, statusErrorWithMessage("MeshConfig: unexpected conversion from %s to %s", fromVersion, toVersion)
+	}
+
+	// The object is encoded through JSON so that its fields hold the types unstructured objects expect, e.g. int64
+	// rather than uint32 for numbers
+	convertedJSON, err := json.Marshal(converted)
+	if err != nil {
+		return nil, statusErrorWithMessage("MeshConfig: error encoding %s object: %v", toVersion, err)
+	}
+	convertedObject := &unstructured.Unstructured{}
+	if err := convertedObject.UnmarshalJSON(convertedJSON); err != nil {
+		return nil, statusErrorWithMessage("MeshConfig: error encoding %s object: %v", toVersion, err)
+	}
+
+	log.Debug().Msgf("MeshConfig: successfully converted object from %s to %s", fromVersion, toVersion)
 	return convertedObject, statusSucceed()
 }
@@ -0,0 +1,136 @@
+package crdconversion
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestConvertMeshConfig(t *testing.T) {
+	v1alpha1MeshConfig := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "config.openservicemesh.io/v1alpha1",
+			"kind":       "MeshConfig",
+			"metadata": map[string]interface{}{
+				"namespace": "osm-system",
+				"name":      "osm-mesh-config",
+			},
+			"spec": map[string]interface{}{
+				"traffic": map[string]interface{}{
+					"enableEgress": true,
+					"compression": map[string]interface{}{
+						"enable":     true,
+						"algorithms": []interface{}{"gzip"},
+					},
+				},
+			},
+		}}
+	}
+
+	testCases := []struct {
+		name              string
+		object            *unstructured.Unstructured
+		toVersion         string
+		expectedStatus    string
+		expectedSpecField []string
+	}{
+		{
+			name:              "v1alpha1 to v1alpha2 moves the inbound HTTP settings",
+			object:            v1alpha1MeshConfig(),
+			toVersion:         "config.openservicemesh.io/v1alpha2",
+			expectedStatus:    metav1.StatusSuccess,
+			expectedSpecField: []string{"spec", "traffic", "inboundHTTP", "compression", "enable"},
+		},
+		{
+			name: "v1alpha2 to v1alpha1 restores the inbound HTTP settings",
+			object: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "config.openservicemesh.io/v1alpha2",
+				"kind":       "MeshConfig",
+				"metadata": map[string]interface{}{
+					"namespace": "osm-system",
+					"name":      "osm-mesh-config",
+				},
+				"spec": map[string]interface{}{
+					"traffic": map[string]interface{}{
+						"inboundHTTP": map[string]interface{}{
+							"compression": map[string]interface{}{
+								"enable": true,
+							},
+						},
+					},
+				},
+			}},
+			toVersion:         "config.openservicemesh.io/v1alpha1",
+			expectedStatus:    metav1.StatusSuccess,
+			expectedSpecField: []string{"spec", "traffic", "compression", "enable"},
+		},
+		{
+			name:           "conversion to the same version",
+			object:         v1alpha1MeshConfig(),
+			toVersion:      "config.openservicemesh.io/v1alpha1",
+			expectedStatus: metav1.StatusFailure,
+		},
+		{
+			name:           "conversion to an unknown version",
+			object:         v1alpha1MeshConfig(),
+			toVersion:      "config.openservicemesh.io/v1beta1",
+			expectedStatus: metav1.StatusFailure,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			converted, status := convertMeshConfig(tc.object, tc.toVersion)
+			assert.Equal(tc.expectedStatus, status.Status)
+			if tc.expectedStatus != metav1.StatusSuccess {
+				assert.Nil(converted)
+				return
+			}
+
+			assert.Equal("osm-mesh-config", converted.GetName())
+			enabled, found, err := unstructured.NestedBool(converted.Object, tc.expectedSpecField...)
+			assert.Nil(err)
+			assert.True(found)
+			assert.True(enabled)
+		})
+	}
+}
+
+func TestConvertMeshConfigRoundTrip(t *testing.T) {
+	assert := tassert.New(t)
+
+	original := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "config.openservicemesh.io/v1alpha1",
+		"kind":       "MeshConfig",
+		"metadata": map[string]interface{}{
+			"namespace": "osm-system",
+			"name":      "osm-mesh-config",
+		},
+		"spec": map[string]interface{}{
+			"traffic": map[string]interface{}{
+				"requestLimits": map[string]interface{}{
+					"maxRequestBytes": int64(1024),
+				},
+				"clientCertDetails": map[string]interface{}{
+					"forwardClientCertDetails": "SANITIZE_SET",
+				},
+			},
+		},
+	}}
+
+	v1alpha2, status := convertMeshConfig(original, "config.openservicemesh.io/v1alpha2")
+	assert.Equal(metav1.StatusSuccess, status.Status)
+	v1alpha2.SetAPIVersion("config.openservicemesh.io/v1alpha2")
+
+	v1alpha1, status := convertMeshConfig(v1alpha2, "config.openservicemesh.io/v1alpha1")
+	assert.Equal(metav1.StatusSuccess, status.Status)
+
+	traffic, _, err := unstructured.NestedMap(v1alpha1.Object, "spec", "traffic")
+	assert.Nil(err)
+	assert.Equal(map[string]interface{}{"maxRequestBytes": int64(1024)}, traffic["requestLimits"])
+	assert.Equal("SANITIZE_SET", traffic["clientCertDetails"].(map[string]interface{})["forwardClientCertDetails"])
+}
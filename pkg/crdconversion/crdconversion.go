@@ -15,6 +15,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/certificate/providers"
 	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/webhook"
 )
 
 const (
@@ -28,25 +29,35 @@ const (
 	healthPort = 9095
 
 	// paths to convert CRD's
-	trafficAccessConverterPath         = "/convert/trafficaccess"
-	httpRouteGroupConverterPath        = "/convert/httproutegroup"
-	meshConfigConverterPath            = "/convert/meshconfig"
-	multiclusterServiceConverterPath   = "/convert/multiclusterservice"
-	egressPolicyConverterPath          = "/convert/egresspolicy"
-	trafficSplitConverterPath          = "/convert/trafficsplit"
-	tcpRoutesConverterPath             = "/convert/tcproutes"
-	ingressBackendsPolicyConverterPath = "/convert/ingressbackendspolicy"
+	trafficAccessConverterPath           = "/convert/trafficaccess"
+	httpRouteGroupConverterPath          = "/convert/httproutegroup"
+	meshConfigConverterPath              = "/convert/meshconfig"
+	multiclusterServiceConverterPath     = "/convert/multiclusterservice"
+	egressPolicyConverterPath            = "/convert/egresspolicy"
+	trafficSplitConverterPath            = "/convert/trafficsplit"
+	tcpRoutesConverterPath               = "/convert/tcproutes"
+	ingressBackendsPolicyConverterPath   = "/convert/ingressbackendspolicy"
+	externalWorkloadsConverterPath       = "/convert/externalworkloads"
+	progressiveDeliveriesConverterPath   = "/convert/progressivedeliveries"
+	upstreamTrafficSettingsConverterPath = "/convert/upstreamtrafficsettings"
+	namespaceIsolationsConverterPath     = "/convert/namespaceisolations"
+	namespaceOffboardingsConverterPath   = "/convert/namespaceoffboardings"
 )
 
 var crdConversionWebhookConfiguration = map[string]string{
-	"traffictargets.access.smi-spec.io":              trafficAccessConverterPath,
-	"httproutegroups.specs.smi-spec.io":              httpRouteGroupConverterPath,
-	"meshconfigs.config.openservicemesh.io":          meshConfigConverterPath,
-	"multiclusterservices.config.openservicemesh.io": multiclusterServiceConverterPath,
-	"egresses.policy.openservicemesh.io":             egressPolicyConverterPath,
-	"trafficsplits.split.smi-spec.io":                trafficSplitConverterPath,
-	"tcproutes.specs.smi-spec.io":                    tcpRoutesConverterPath,
-	"ingressbackends.policy.openservicemesh.io":      ingressBackendsPolicyConverterPath,
+	"traffictargets.access.smi-spec.io":                 trafficAccessConverterPath,
+	"httproutegroups.specs.smi-spec.io":                 httpRouteGroupConverterPath,
+	"meshconfigs.config.openservicemesh.io":             meshConfigConverterPath,
+	"multiclusterservices.config.openservicemesh.io":    multiclusterServiceConverterPath,
+	"egresses.policy.openservicemesh.io":                egressPolicyConverterPath,
+	"trafficsplits.split.smi-spec.io":                   trafficSplitConverterPath,
+	"tcproutes.specs.smi-spec.io":                       tcpRoutesConverterPath,
+	"ingressbackends.policy.openservicemesh.io":         ingressBackendsPolicyConverterPath,
+	"externalworkloads.policy.openservicemesh.io":       externalWorkloadsConverterPath,
+	"progressivedeliveries.policy.openservicemesh.io":   progressiveDeliveriesConverterPath,
+	"upstreamtrafficsettings.policy.openservicemesh.io": upstreamTrafficSettingsConverterPath,
+	"namespaceisolations.policy.openservicemesh.io":     namespaceIsolationsConverterPath,
+	"namespaceoffboardings.policy.openservicemesh.io":   namespaceOffboardingsConverterPath,
 }
 
 var conversionReviewVersions = []string{"v1beta1", "v1"}
@@ -57,7 +68,7 @@ func NewConversionWebhook(config Config, kubeClient kubernetes.Interface, crdCli
 	// This cert does not have to be related to the Envoy certs, but it does have to match
 	// the cert provisioned with the ConversionWebhook on the CRD's
 	crdConversionWebhookHandlerCert, err := certManager.IssueCertificate(
-		certificate.CommonName(fmt.Sprintf("%s.%s.svc", crdConverterServiceName, osmNamespace)),
+		webhook.GetCommonName(config.WebhookURL, crdConverterServiceName, osmNamespace),
 		constants.XDSCertificateValidityPeriod)
 	if err != nil {
 		return errors.Errorf("Error issuing certificate for the crd-converter: %+v", err)
@@ -65,9 +76,13 @@ func NewConversionWebhook(config Config, kubeClient kubernetes.Interface, crdCli
 
 	// The following function ensures to atomically create or get the certificate from Kubernetes
 	// secret API store. Multiple instances should end up with the same crdConversionwebhookHandlerCert after this function executed.
-	crdConversionWebhookHandlerCert, err = providers.GetCertificateFromSecret(osmNamespace, constants.CrdConverterCertificateSecretName, crdConversionWebhookHandlerCert, kubeClient)
-	if err != nil {
-		return errors.Errorf("Error fetching crd-converter certificate from k8s secret: %s", err)
+	// A single instance running outside the cluster keeps its own certificate, since the one stored in the secret is
+	// issued for the osm-bootstrap service.
+	if config.WebhookURL == "" {
+		crdConversionWebhookHandlerCert, err = providers.GetCertificateFromSecret(osmNamespace, constants.CrdConverterCertificateSecretName, crdConversionWebhookHandlerCert, kubeClient)
+		if err != nil {
+			return errors.Errorf("Error fetching crd-converter certificate from k8s secret: %s", err)
+		}
 	}
 
 	crdWh := crdConversionWebhook{
@@ -78,7 +93,7 @@ func NewConversionWebhook(config Config, kubeClient kubernetes.Interface, crdCli
 	// Start the ConversionWebhook web server
 	go crdWh.run(stop)
 
-	if err = patchCrdsWithConversionWehook(crdConversionWebhookHandlerCert, crdClient, osmNamespace); err != nil {
+	if err = patchCrdsWithConversionWehook(crdConversionWebhookHandlerCert, crdClient, osmNamespace, config.WebhookURL); err != nil {
 		return errors.Errorf("Error patching crds with conversion webhook %v", err)
 	}
 
@@ -98,6 +113,11 @@ func (crdWh *crdConversionWebhook) run(stop <-chan struct{}) {
 	webhookMux.HandleFunc(trafficSplitConverterPath, serveTrafficSplitConversion)
 	webhookMux.HandleFunc(tcpRoutesConverterPath, serveTCPRouteConversion)
 	webhookMux.HandleFunc(ingressBackendsPolicyConverterPath, serveIngressBackendsPolicyConversion)
+	webhookMux.HandleFunc(externalWorkloadsConverterPath, serveExternalWorkloadsConversion)
+	webhookMux.HandleFunc(progressiveDeliveriesConverterPath, serveProgressiveDeliveriesConversion)
+	webhookMux.HandleFunc(upstreamTrafficSettingsConverterPath, serveUpstreamTrafficSettingsConversion)
+	webhookMux.HandleFunc(namespaceIsolationsConverterPath, serveNamespaceIsolationsConversion)
+	webhookMux.HandleFunc(namespaceOffboardingsConverterPath, serveNamespaceOffboardingsConversion)
 
 	webhookServer := &http.Server{
 		Addr:    fmt.Sprintf(":%d", crdWh.config.ListenPort),
@@ -163,9 +183,9 @@ func healthHandler(w http.ResponseWriter, _ *http.Request) {
 	}
 }
 
-func patchCrdsWithConversionWehook(cert certificate.Certificater, crdClient apiclient.ApiextensionsV1Interface, osmNamespace string) error {
+func patchCrdsWithConversionWehook(cert certificate.Certificater, crdClient apiclient.ApiextensionsV1Interface, osmNamespace, webhookURL string) error {
 	for crdName, crdConversionPath := range crdConversionWebhookConfiguration {
-		if err := updateCrdConversionWebhookConfiguration(cert, crdClient, osmNamespace, crdName, crdConversionPath); err != nil {
+		if err := updateCrdConversionWebhookConfiguration(cert, crdClient, osmNamespace, webhookURL, crdName, crdConversionPath); err != nil {
 			log.Error().Err(err).Msgf("Error updating conversion webhook configuration for crd : %s", crdName)
 			return err
 		}
@@ -174,23 +194,31 @@ func patchCrdsWithConversionWehook(cert certificate.Certificater, crdClient apic
 }
 
 // updateCrdConversionWebhookConfiguration updates the Conversion section of the CRD that needs to be updated.
-func updateCrdConversionWebhookConfiguration(cert certificate.Certificater, crdClient apiclient.ApiextensionsV1Interface, osmNamespace, crdName, crdConversionPath string) error {
+// The CRD is pointed at the webhook URL if one is set, or at the osm-bootstrap service otherwise.
+func updateCrdConversionWebhookConfiguration(cert certificate.Certificater, crdClient apiclient.ApiextensionsV1Interface, osmNamespace, webhookURL, crdName, crdConversionPath string) error {
 	crd, err := crdClient.CustomResourceDefinitions().Get(context.Background(), crdName, metav1.GetOptions{})
 	if err != nil {
 		return err
 	}
 
+	clientConfig := &apiv1.WebhookClientConfig{
+		CABundle: cert.GetCertificateChain(),
+	}
+	if webhookURL != "" {
+		url := webhook.GetURL(webhookURL, crdConversionPath)
+		clientConfig.URL = &url
+	} else {
+		clientConfig.Service = &apiv1.ServiceReference{
+			Namespace: osmNamespace,
+			Name:      crdConverterServiceName,
+			Path:      &crdConversionPath,
+		}
+	}
+
 	crd.Spec.Conversion = &apiv1.CustomResourceConversion{
 		Strategy: apiv1.WebhookConverter,
 		Webhook: &apiv1.WebhookConversion{
-			ClientConfig: &apiv1.WebhookClientConfig{
-				Service: &apiv1.ServiceReference{
-					Namespace: osmNamespace,
-					Name:      crdConverterServiceName,
-					Path:      &crdConversionPath,
-				},
-				CABundle: cert.GetCertificateChain(),
-			},
+			ClientConfig:             clientConfig,
 			ConversionReviewVersions: conversionReviewVersions,
 		},
 	}
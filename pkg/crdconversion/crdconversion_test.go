@@ -25,6 +25,7 @@ func (mc mockCertificate) GetCertificateChain() []byte               { return []
 func (mc mockCertificate) GetPrivateKey() []byte                     { return []byte("key") }
 func (mc mockCertificate) GetIssuingCA() []byte                      { return []byte("ca") }
 func (mc mockCertificate) GetExpiration() time.Time                  { return time.Now() }
+func (mc mockCertificate) GetIssueTime() time.Time                   { return time.Now() }
 func (mc mockCertificate) GetSerialNumber() certificate.SerialNumber { return "serial_number" }
 
 func TestUpdateCrdConversionWebhookConfiguration(t *testing.T) {
@@ -62,7 +63,7 @@ func TestUpdateCrdConversionWebhookConfiguration(t *testing.T) {
 		},
 	})
 
-	err := updateCrdConversionWebhookConfiguration(cert, crdClient.ApiextensionsV1(), tests.Namespace, "tests.test.openservicemesh.io", "/testconversion")
+	err := updateCrdConversionWebhookConfiguration(cert, crdClient.ApiextensionsV1(), tests.Namespace, "", "tests.test.openservicemesh.io", "/testconversion")
 	assert.Nil(err)
 
 	crds, err := crdClient.ApiextensionsV1().CustomResourceDefinitions().List(context.TODO(), metav1.ListOptions{})
@@ -74,6 +75,16 @@ func TestUpdateCrdConversionWebhookConfiguration(t *testing.T) {
 	assert.Equal(crd.Spec.Conversion.Webhook.ClientConfig.Service.Namespace, tests.Namespace)
 	assert.Equal(crd.Spec.Conversion.Webhook.ClientConfig.Service.Name, crdConverterServiceName)
 	assert.Equal(crd.Spec.Conversion.Webhook.ConversionReviewVersions, conversionReviewVersions)
+
+	err = updateCrdConversionWebhookConfiguration(cert, crdClient.ApiextensionsV1(), tests.Namespace, "https://10.0.0.1:9443/", "tests.test.openservicemesh.io", "/testconversion")
+	assert.Nil(err)
+
+	crd2, err := crdClient.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), "tests.test.openservicemesh.io", metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Nil(crd2.Spec.Conversion.Webhook.ClientConfig.Service)
+	assert.NotNil(crd2.Spec.Conversion.Webhook.ClientConfig.URL)
+	assert.Equal("https://10.0.0.1:9443/testconversion", *crd2.Spec.Conversion.Webhook.ClientConfig.URL)
+	assert.Equal(crd2.Spec.Conversion.Webhook.ClientConfig.CABundle, []byte("chain"))
 }
 
 func TestNewConversionWebhook(t *testing.T) {
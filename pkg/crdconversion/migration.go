@@ -0,0 +1,180 @@
+package crdconversion
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+)
+
+// storageVersionMigrationInterval is the interval at which the migration of the CRDs not migrated yet is retried
+const storageVersionMigrationInterval = time.Minute
+
+// NewStorageVersionMigrator returns a StorageVersionMigrator rewriting the stored objects of OSM's CRDs
+func NewStorageVersionMigrator(crdClient apiclient.ApiextensionsV1Interface, dynamicClient dynamic.Interface) *StorageVersionMigrator {
+	return &StorageVersionMigrator{
+		crdClient:     crdClient,
+		dynamicClient: dynamicClient,
+	}
+}
+
+// Run migrates the stored objects of OSM's CRDs to their storage version, retrying every
+// storageVersionMigrationInterval until all the CRDs are migrated or the stop channel is closed.
+func (m *StorageVersionMigrator) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(storageVersionMigrationInterval)
+	defer ticker.Stop()
+	for {
+		if m.migrateAll() {
+			log.Info().Msg("The stored objects of all CRDs are in their storage version")
+			return
+		}
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// migrateAll migrates the stored objects of OSM's CRDs to their storage version, and returns whether all the CRDs
+// were migrated
+func (m *StorageVersionMigrator) migrateAll() bool {
+	crdNames := make([]string, 0, len(crdConversionWebhookConfiguration))
+	for crdName := range crdConversionWebhookConfiguration {
+		crdNames = append(crdNames, crdName)
+	}
+	sort.Strings(crdNames)
+
+	migrated := true
+	for _, crdName := range crdNames {
+		if err := m.migrate(crdName); err != nil {
+			log.Error().Err(err).Msgf("Error migrating the stored objects of CRD %s to its storage version", crdName)
+			migrated = false
+		}
+	}
+	return migrated
+}
+
+// migrate rewrites the objects of the given CRD in its storage version, then drops the other versions from the
+// stored versions of the CRD. The API server only re-serializes an object in the storage version when it is written,
+// so objects created before the storage version changed remain stored in a version which can only be removed from
+// the CRD once they are rewritten. The conversion webhook must convert the objects of every served version before
+// they are rewritten, otherwise the stored versions are kept.
+func (m *StorageVersionMigrator) migrate(crdName string) error {
+	crd, err := m.crdClient.CustomResourceDefinitions().Get(context.Background(), crdName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	storageVersion := getStorageVersion(crd)
+	if storageVersion == "" {
+		return errors.Errorf("CRD %s has no storage version", crdName)
+	}
+	if len(crd.Status.StoredVersions) == 1 && crd.Status.StoredVersions[0] == storageVersion {
+		return nil
+	}
+
+	if err := m.verifyConversion(crd); err != nil {
+		return errors.Wrapf(err, "Error converting the objects of CRD %s, the conversion webhook may be unhealthy", crdName)
+	}
+
+	rewritten, err := m.rewriteObjects(crd, storageVersion)
+	if err != nil {
+		return err
+	}
+
+	crd.Status.StoredVersions = []string{storageVersion}
+	if _, err := m.crdClient.CustomResourceDefinitions().UpdateStatus(context.Background(), crd, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrapf(err, "Error updating the stored versions of CRD %s", crdName)
+	}
+
+	log.Info().Msgf("Rewrote %d objects of CRD %s in storage version %s", rewritten, crdName, storageVersion)
+	return nil
+}
+
+// getStorageVersion returns the storage version of the given CRD
+func getStorageVersion(crd *apiv1.CustomResourceDefinition) string {
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			return version.Name
+		}
+	}
+	return ""
+}
+
+// verifyConversion checks that the given CRD serving several versions is converted by the conversion webhook, and
+// lists its objects in every served version, which requires the webhook to convert the objects stored in the other
+// versions
+func (m *StorageVersionMigrator) verifyConversion(crd *apiv1.CustomResourceDefinition) error {
+	var servedVersions []string
+	for _, version := range crd.Spec.Versions {
+		if version.Served {
+			servedVersions = append(servedVersions, version.Name)
+		}
+	}
+	if len(servedVersions) > 1 && (crd.Spec.Conversion == nil || crd.Spec.Conversion.Strategy != apiv1.WebhookConverter) {
+		return errors.Errorf("CRD %s serving versions %v is not converted by the conversion webhook", crd.Name, servedVersions)
+	}
+
+	for _, version := range servedVersions {
+		if _, err := m.resourceClient(crd, version).List(context.Background(), metav1.ListOptions{}); err != nil {
+			return errors.Wrapf(err, "Error listing the objects of CRD %s in version %s", crd.Name, version)
+		}
+	}
+	return nil
+}
+
+// rewriteObjects writes back the objects of the given CRD unchanged in the given storage version, which stores them
+// in that version, and returns the number of objects rewritten
+func (m *StorageVersionMigrator) rewriteObjects(crd *apiv1.CustomResourceDefinition, storageVersion string) (int, error) {
+	objects, err := m.resourceClient(crd, storageVersion).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return 0, errors.Wrapf(err, "Error listing the objects of CRD %s in version %s", crd.Name, storageVersion)
+	}
+
+	rewritten := 0
+	for _, object := range objects.Items {
+		resource := m.namespacedResourceClient(crd, storageVersion, object.GetNamespace())
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			current, err := resource.Get(context.Background(), object.GetName(), metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			_, err = resource.Update(context.Background(), current, metav1.UpdateOptions{})
+			return err
+		})
+		if err != nil {
+			return rewritten, errors.Wrapf(err, "Error rewriting %s %s/%s in version %s", crd.Spec.Names.Kind, object.GetNamespace(), object.GetName(), storageVersion)
+		}
+		rewritten++
+	}
+	return rewritten, nil
+}
+
+// resourceClient returns the client of the objects of the given CRD in the given version, across all namespaces
+func (m *StorageVersionMigrator) resourceClient(crd *apiv1.CustomResourceDefinition, version string) dynamic.ResourceInterface {
+	return m.namespacedResourceClient(crd, version, metav1.NamespaceAll)
+}
+
+// namespacedResourceClient returns the client of the objects of the given CRD in the given version and namespace
+func (m *StorageVersionMigrator) namespacedResourceClient(crd *apiv1.CustomResourceDefinition, version, namespace string) dynamic.ResourceInterface {
+	gvr := schema.GroupVersionResource{Group: crd.Spec.Group, Version: version, Resource: crd.Spec.Names.Plural}
+	if crd.Spec.Scope == apiv1.ClusterScoped {
+		return m.dynamicClient.Resource(gvr)
+	}
+	return m.dynamicClient.Resource(gvr).Namespace(namespace)
+}
@@ -0,0 +1,120 @@
+package crdconversion
+
+import (
+	"context"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestMigrateStorageVersion(t *testing.T) {
+	const crdName = "meshconfigs.config.openservicemesh.io"
+
+	newCRD := func(storedVersions []string, conversion *apiv1.CustomResourceConversion) *apiv1.CustomResourceDefinition {
+		return &apiv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: crdName},
+			Spec: apiv1.CustomResourceDefinitionSpec{
+				Group: "config.openservicemesh.io",
+				Names: apiv1.CustomResourceDefinitionNames{Plural: "meshconfigs", Kind: "MeshConfig", ListKind: "MeshConfigList"},
+				Scope: apiv1.NamespaceScoped,
+				Versions: []apiv1.CustomResourceDefinitionVersion{
+					{Name: "v1alpha1", Served: true},
+					{Name: "v1alpha2", Served: true, Storage: true},
+				},
+				Conversion: conversion,
+			},
+			Status: apiv1.CustomResourceDefinitionStatus{StoredVersions: storedVersions},
+		}
+	}
+	webhookConversion := &apiv1.CustomResourceConversion{Strategy: apiv1.WebhookConverter}
+	meshConfig := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "config.openservicemesh.io/v1alpha2",
+		"kind":       "MeshConfig",
+		"metadata":   map[string]interface{}{"name": "osm-mesh-config", "namespace": "osm-system"},
+	}}
+
+	testCases := []struct {
+		name              string
+		crd               *apiv1.CustomResourceDefinition
+		listErr           error
+		expErr            bool
+		expStoredVersions []string
+		expUpdates        int
+	}{
+		{
+			name: "CRD not installed is skipped",
+		},
+		{
+			name:              "CRD whose objects are stored in the storage version is skipped",
+			crd:               newCRD([]string{"v1alpha2"}, webhookConversion),
+			expStoredVersions: []string{"v1alpha2"},
+		},
+		{
+			name:              "objects stored in a previous version are rewritten",
+			crd:               newCRD([]string{"v1alpha1", "v1alpha2"}, webhookConversion),
+			expStoredVersions: []string{"v1alpha2"},
+			expUpdates:        1,
+		},
+		{
+			name:              "CRD without conversion webhook is not migrated",
+			crd:               newCRD([]string{"v1alpha1", "v1alpha2"}, nil),
+			expErr:            true,
+			expStoredVersions: []string{"v1alpha1", "v1alpha2"},
+		},
+		{
+			name:              "CRD whose objects fail to be converted is not migrated",
+			crd:               newCRD([]string{"v1alpha1", "v1alpha2"}, webhookConversion),
+			listErr:           errors.NewInternalError(context.DeadlineExceeded),
+			expErr:            true,
+			expStoredVersions: []string{"v1alpha1", "v1alpha2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			crdClient := fake.NewSimpleClientset()
+			if tc.crd != nil {
+				crdClient = fake.NewSimpleClientset(tc.crd)
+			}
+			dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+				{Group: "config.openservicemesh.io", Version: "v1alpha1", Resource: "meshconfigs"}: "MeshConfigList",
+				{Group: "config.openservicemesh.io", Version: "v1alpha2", Resource: "meshconfigs"}: "MeshConfigList",
+			}, meshConfig.DeepCopy())
+			if tc.listErr != nil {
+				dynamicClient.PrependReactor("list", "meshconfigs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, tc.listErr
+				})
+			}
+
+			m := NewStorageVersionMigrator(crdClient.ApiextensionsV1(), dynamicClient)
+			err := m.migrate(crdName)
+			assert.Equal(tc.expErr, err != nil)
+
+			updates := 0
+			for _, action := range dynamicClient.Actions() {
+				if action.GetVerb() == "update" {
+					updates++
+				}
+			}
+			assert.Equal(tc.expUpdates, updates)
+
+			if tc.crd == nil {
+				return
+			}
+			crd, err := crdClient.ApiextensionsV1().CustomResourceDefinitions().Get(context.Background(), crdName, metav1.GetOptions{})
+			assert.Nil(err)
+			assert.Equal(tc.expStoredVersions, crd.Status.StoredVersions)
+		})
+	}
+}
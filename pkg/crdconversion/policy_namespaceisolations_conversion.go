@@ -0,0 +1,27 @@
+package crdconversion
+
+import (
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// serveNamespaceIsolationsConversion servers endpoint for the converter defined as convertNamespaceIsolations function.
+func serveNamespaceIsolationsConversion(w http.ResponseWriter, r *http.Request) {
+	serve(w, r, convertNamespaceIsolations)
+}
+
+// convertNamespaceIsolations contains the business logic to convert namespaceisolations.policy.openservicemesh.io CRD
+// Example implementation reference : https://github.com/kubernetes/kubernetes/blob/release-1.21/test/images/agnhost/crd-conversion-webhook/converter/example_converter.go
+func convertNamespaceIsolations(Object *unstructured.Unstructured, toVersion string) (*unstructured.Unstructured, metav1.Status) {
+	convertedObject := Object.DeepCopy()
+	fromVersion := Object.GetAPIVersion()
+
+	if toVersion == fromVersion {
+		return nil, statusErrorWithMessage("NamespaceIsolations: conversion from a version to itself should not call the webhook: %s", toVersion)
+	}
+
+	log.Debug().Msg("NamespaceIsolations: successfully converted object")
+	return convertedObject, statusSucceed()
+}
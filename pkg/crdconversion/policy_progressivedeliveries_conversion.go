@@ -0,0 +1,27 @@
+package crdconversion
+
+import (
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// serveProgressiveDeliveriesConversion servers endpoint for the converter defined as convertProgressiveDeliveries function.
+func serveProgressiveDeliveriesConversion(w http.ResponseWriter, r *http.Request) {
+	serve(w, r, convertProgressiveDeliveries)
+}
+
+// convertProgressiveDeliveries contains the business logic to convert progressivedeliveries.policy.openservicemesh.io CRD
+// Example implementation reference : https://github.com/kubernetes/kubernetes/blob/release-1.21/test/images/agnhost/crd-conversion-webhook/converter/example_converter.go
+func convertProgressiveDeliveries(Object *unstructured.Unstructured, toVersion string) (*unstructured.Unstructured, metav1.Status) {
+	convertedObject := Object.DeepCopy()
+	fromVersion := Object.GetAPIVersion()
+
+	if toVersion == fromVersion {
+		return nil, statusErrorWithMessage("ProgressiveDeliveries: conversion from a version to itself should not call the webhook: %s", toVersion)
+	}
+
+	log.Debug().Msg("ProgressiveDeliveries: successfully converted object")
+	return convertedObject, statusSucceed()
+}
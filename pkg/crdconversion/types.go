@@ -3,6 +3,9 @@
 package crdconversion
 
 import (
+	apiclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1"
+	"k8s.io/client-go/dynamic"
+
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/logger"
 )
@@ -19,4 +22,15 @@ type crdConversionWebhook struct {
 type Config struct {
 	// ListenPort defines the port on which the crd-conversion webhook listens
 	ListenPort int
+
+	// WebhookURL is the base URL (https://host:port) at which the API server reaches the crd-conversion webhook when
+	// osm-bootstrap runs outside the cluster. The osm-bootstrap service within the OSM namespace is used if unset.
+	WebhookURL string
+}
+
+// StorageVersionMigrator is the type used to represent the migrator rewriting the stored objects of OSM's CRDs in
+// their storage version
+type StorageVersionMigrator struct {
+	crdClient     apiclient.ApiextensionsV1Interface
+	dynamicClient dynamic.Interface
 }
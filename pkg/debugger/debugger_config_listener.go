@@ -7,7 +7,8 @@ import (
 	"github.com/openservicemesh/osm/pkg/k8s/events"
 )
 
-// StartDebugServerConfigListener registers a go routine to listen to configuration and configure debug server as needed
+// StartDebugServerConfigListener registers a go routine to listen to configuration and configure debug server and
+// memory profiling as needed
 func (d *DebugConfig) StartDebugServerConfigListener() {
 	// Subscribe to configuration updates
 	ch := events.Subscribe(
@@ -24,15 +25,30 @@ func (d *DebugConfig) StartDebugServerConfigListener() {
 		// Bootstrap after subscribing
 		started := false
 
+		// Memory profiling is only enabled along with the debug server serving the profiles
+		var stopMemoryProfiling chan struct{}
+		configureMemoryProfiling := func() {
+			isMemProfilingEnabled := d.configurator.IsDebugServerEnabled() && d.configurator.IsMemoryProfilingEnabled()
+			if isMemProfilingEnabled && stopMemoryProfiling == nil {
+				stopMemoryProfiling = d.startMemoryProfiling()
+			}
+			if !isMemProfilingEnabled && stopMemoryProfiling != nil {
+				d.stopMemoryProfiling(stopMemoryProfiling)
+				stopMemoryProfiling = nil
+			}
+		}
+
 		if d.configurator.IsDebugServerEnabled() {
 			if err := httpDebugServer.Start(); err != nil {
 				log.Error().Err(err).Msgf("error starting debug server")
 			}
 			started = true
 		}
+		configureMemoryProfiling()
 
 		for {
 			<-cfgSubChannel
+			configureMemoryProfiling()
 			isDbgSrvEnabled := d.configurator.IsDebugServerEnabled()
 
 			if isDbgSrvEnabled && !started {
@@ -0,0 +1,71 @@
+package debugger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/openservicemesh/osm/pkg/diagnostics"
+)
+
+const (
+	diagnosticsJobIDQueryKey     = "id"
+	diagnosticsNamespaceQueryKey = "namespace"
+	diagnosticsSelectorQueryKey  = "selector"
+	diagnosticsQueryQueryKey     = "query"
+)
+
+// getDiagnosticsHandler returns a handler to start diagnostics jobs collecting read-only Envoy admin API endpoints of
+// a set of proxies, and to get the status of these jobs. A POST request with the 'namespace', 'selector' and
+// repeated 'query' parameters starts a job. A GET request returns the job with the given 'id', or all the retained
+// jobs if no ID is given.
+func (ds DebugConfig) getDiagnosticsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if ds.diagnosticsRunner == nil {
+			http.Error(w, "Diagnostics jobs are not enabled", http.StatusServiceUnavailable)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodPost:
+			query := r.URL.Query()
+			job, err := ds.diagnosticsRunner.Submit(diagnostics.JobSpec{
+				Namespace:     query.Get(diagnosticsNamespaceQueryKey),
+				LabelSelector: query.Get(diagnosticsSelectorQueryKey),
+				Queries:       query[diagnosticsQueryQueryKey],
+			})
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error starting diagnostics job: %s", err), http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusAccepted)
+			writeDiagnosticsJSON(w, job)
+
+		case http.MethodGet:
+			if id := r.URL.Query().Get(diagnosticsJobIDQueryKey); id != "" {
+				job, ok := ds.diagnosticsRunner.GetJob(id)
+				if !ok {
+					http.Error(w, fmt.Sprintf("Diagnostics job %s not found", id), http.StatusNotFound)
+					return
+				}
+				writeDiagnosticsJSON(w, job)
+				return
+			}
+			writeDiagnosticsJSON(w, ds.diagnosticsRunner.ListJobs())
+
+		default:
+			http.Error(w, fmt.Sprintf("Method %s not allowed", r.Method), http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+func writeDiagnosticsJSON(w http.ResponseWriter, v interface{}) {
+	jobsJSON, err := json.Marshal(v)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error marshalling diagnostics jobs %+v", v)
+		return
+	}
+	_, _ = fmt.Fprint(w, string(jobsJSON))
+}
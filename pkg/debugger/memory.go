@@ -0,0 +1,167 @@
+package debugger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+)
+
+const (
+	// mutexProfileFraction is the rate at which mutex contention events are sampled while memory profiling is enabled,
+	// on average 1 event out of mutexProfileFraction
+	mutexProfileFraction = 5
+
+	// blockProfileRate is the rate at which blocking events are sampled while memory profiling is enabled, on average
+	// 1 event per blockProfileRate nanoseconds spent blocked
+	blockProfileRate = int(time.Millisecond)
+)
+
+// heapSnapshot is a heap profile of the controller taken at a point in time
+type heapSnapshot struct {
+	id      int
+	time    time.Time
+	profile []byte
+}
+
+// heapSnapshots are the most recent heap snapshots of the controller, taken periodically while memory profiling is
+// enabled to compare the heap of the controller over time
+type heapSnapshots struct {
+	mu        sync.RWMutex
+	snapshots []heapSnapshot
+	nextID    int
+}
+
+// heapSnapshotInfo is the representation of a heap snapshot in the list served by the debug server
+type heapSnapshotInfo struct {
+	ID   int    `json:"id"`
+	Time string `json:"time"`
+	Size int    `json:"size"`
+}
+
+// take takes a heap snapshot of the controller, and discards the oldest snapshots beyond the given maximum
+func (h *heapSnapshots) take(maxSnapshots int) error {
+	var profile bytes.Buffer
+	if err := pprof.Lookup("heap").WriteTo(&profile, 0); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.snapshots = append(h.snapshots, heapSnapshot{id: h.nextID, time: time.Now(), profile: profile.Bytes()})
+	h.nextID++
+	if len(h.snapshots) > maxSnapshots {
+		h.snapshots = append([]heapSnapshot(nil), h.snapshots[len(h.snapshots)-maxSnapshots:]...)
+	}
+	return nil
+}
+
+// get returns the heap snapshot with the given ID
+func (h *heapSnapshots) get(id int) (heapSnapshot, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, snapshot := range h.snapshots {
+		if snapshot.id == id {
+			return snapshot, true
+		}
+	}
+	return heapSnapshot{}, false
+}
+
+// list returns the heap snapshots from the oldest to the most recent
+func (h *heapSnapshots) list() []heapSnapshotInfo {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	infos := make([]heapSnapshotInfo, 0, len(h.snapshots))
+	for _, snapshot := range h.snapshots {
+		infos = append(infos, heapSnapshotInfo{
+			ID:   snapshot.id,
+			Time: snapshot.time.UTC().Format(time.RFC3339),
+			Size: len(snapshot.profile),
+		})
+	}
+	return infos
+}
+
+// clear discards the heap snapshots to release their memory
+func (h *heapSnapshots) clear() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.snapshots = nil
+}
+
+// run takes heap snapshots at the interval configured in the MeshConfig until the given channel is closed. Changes of
+// the interval apply after the next snapshot.
+func (h *heapSnapshots) run(cfg configurator.Configurator, stop <-chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		case <-time.After(cfg.GetHeapSnapshotInterval()):
+			if err := h.take(cfg.GetMaxHeapSnapshots()); err != nil {
+				log.Error().Err(err).Msg("Error taking heap snapshot")
+			}
+		}
+	}
+}
+
+// startMemoryProfiling enables the sampling of the mutex and block profiles of the controller and starts taking heap
+// snapshots, until the returned channel is closed with stopMemoryProfiling
+func (ds DebugConfig) startMemoryProfiling() chan struct{} {
+	log.Info().Msg("Starting memory profiling")
+	runtime.SetMutexProfileFraction(mutexProfileFraction)
+	runtime.SetBlockProfileRate(blockProfileRate)
+
+	stop := make(chan struct{})
+	go ds.heapSnapshots.run(ds.configurator, stop)
+	return stop
+}
+
+// stopMemoryProfiling disables the sampling of the mutex and block profiles of the controller, stops taking heap
+// snapshots and discards them
+func (ds DebugConfig) stopMemoryProfiling(stop chan struct{}) {
+	log.Info().Msg("Stopping memory profiling")
+	close(stop)
+	runtime.SetMutexProfileFraction(0)
+	runtime.SetBlockProfileRate(0)
+	ds.heapSnapshots.clear()
+}
+
+// getHeapSnapshotsHandler lists the heap snapshots of the controller, or serves the heap snapshot given by the id
+// query parameter in the format of the heap profiles of pprof
+func (ds DebugConfig) getHeapSnapshotsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idStr := r.URL.Query().Get("id")
+		if idStr == "" {
+			snapshots := ds.heapSnapshots.list()
+			if snapshotsJSON, err := json.Marshal(snapshots); err != nil {
+				log.Error().Err(err).Msgf("Error marshaling heap snapshots: %+v", snapshots)
+			} else {
+				_, _ = fmt.Fprint(w, string(snapshotsJSON))
+			}
+			return
+		}
+
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid heap snapshot id %s", idStr), http.StatusBadRequest)
+			return
+		}
+		snapshot, ok := ds.heapSnapshots.get(id)
+		if !ok {
+			http.Error(w, fmt.Sprintf("Heap snapshot %d not found", id), http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="heap-%d.pb.gz"`, snapshot.time.Unix()))
+		_, _ = w.Write(snapshot.profile)
+	})
+}
@@ -0,0 +1,120 @@
+package debugger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+)
+
+func TestHeapSnapshots(t *testing.T) {
+	assert := tassert.New(t)
+
+	snapshots := &heapSnapshots{}
+	for i := 0; i < 3; i++ {
+		assert.Nil(snapshots.take(2))
+	}
+
+	// Only the 2 most recent snapshots are kept
+	infos := snapshots.list()
+	assert.Len(infos, 2)
+	assert.Equal(1, infos[0].ID)
+	assert.Equal(2, infos[1].ID)
+	assert.NotZero(infos[1].Size)
+
+	_, found := snapshots.get(0)
+	assert.False(found)
+	snapshot, found := snapshots.get(2)
+	assert.True(found)
+	assert.NotEmpty(snapshot.profile)
+
+	snapshots.clear()
+	assert.Empty(snapshots.list())
+
+	// IDs are not reused after the snapshots are cleared
+	assert.Nil(snapshots.take(2))
+	assert.Equal(3, snapshots.list()[0].ID)
+}
+
+func TestHeapSnapshotsHandler(t *testing.T) {
+	ds := DebugConfig{
+		heapSnapshots: &heapSnapshots{},
+	}
+	tassert.Nil(t, ds.heapSnapshots.take(1))
+
+	testCases := []struct {
+		name               string
+		url                string
+		expectedStatusCode int
+		expectedSnapshots  int
+		expectedProfile    bool
+	}{
+		{
+			name:               "list the heap snapshots",
+			url:                "/debug/heap-snapshots",
+			expectedStatusCode: http.StatusOK,
+			expectedSnapshots:  1,
+		},
+		{
+			name:               "get a heap snapshot",
+			url:                "/debug/heap-snapshots?id=0",
+			expectedStatusCode: http.StatusOK,
+			expectedProfile:    true,
+		},
+		{
+			name:               "get a missing heap snapshot",
+			url:                "/debug/heap-snapshots?id=1",
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "get a heap snapshot with an invalid id",
+			url:                "/debug/heap-snapshots?id=invalid",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			responseRecorder := httptest.NewRecorder()
+			ds.getHeapSnapshotsHandler().ServeHTTP(responseRecorder, httptest.NewRequest("GET", tc.url, nil))
+			assert.Equal(tc.expectedStatusCode, responseRecorder.Code)
+
+			if tc.expectedSnapshots > 0 {
+				var snapshots []heapSnapshotInfo
+				assert.Nil(json.Unmarshal(responseRecorder.Body.Bytes(), &snapshots))
+				assert.Len(snapshots, tc.expectedSnapshots)
+			}
+			if tc.expectedProfile {
+				assert.Equal("application/octet-stream", responseRecorder.Header().Get("Content-Type"))
+				assert.NotZero(responseRecorder.Body.Len())
+			}
+		})
+	}
+}
+
+func TestMemoryProfiling(t *testing.T) {
+	assert := tassert.New(t)
+
+	mockConfig := configurator.NewMockConfigurator(gomock.NewController(t))
+	mockConfig.EXPECT().GetHeapSnapshotInterval().Return(configurator.MinHeapSnapshotInterval).AnyTimes()
+	ds := DebugConfig{
+		configurator:  mockConfig,
+		heapSnapshots: &heapSnapshots{},
+	}
+	assert.Nil(ds.heapSnapshots.take(1))
+
+	stop := ds.startMemoryProfiling()
+	assert.Equal(mutexProfileFraction, runtime.SetMutexProfileFraction(-1))
+
+	ds.stopMemoryProfiling(stop)
+	assert.Equal(0, runtime.SetMutexProfileFraction(-1))
+	assert.Empty(ds.heapSnapshots.list())
+}
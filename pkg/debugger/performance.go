@@ -0,0 +1,50 @@
+package debugger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// performanceSettings is the representation of the effective performance settings served by the debug server, with
+// durations in a human readable format
+type performanceSettings struct {
+	Profile                      string            `json:"profile"`
+	BroadcastGracePeriod         string            `json:"broadcastGracePeriod"`
+	MaxBroadcastDelay            string            `json:"maxBroadcastDelay"`
+	WorkerPoolSize               int               `json:"workerPoolSize"`
+	MaxDataPlaneConnections      int               `json:"maxDataPlaneConnections"`
+	InformerResyncInterval       string            `json:"informerResyncInterval"`
+	InformerResyncIntervals      map[string]string `json:"informerResyncIntervals,omitempty"`
+	InformerWatchErrorBackoff    string            `json:"informerWatchErrorBackoff"`
+	InformerWatchErrorMaxBackoff string            `json:"informerWatchErrorMaxBackoff"`
+	EnvoyConcurrency             int               `json:"envoyConcurrency"`
+}
+
+func (ds DebugConfig) getPerformanceSettingsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		settings := ds.configurator.GetPerformanceSettings()
+		performance := performanceSettings{
+			Profile:                      settings.Profile,
+			BroadcastGracePeriod:         settings.BroadcastGracePeriod.String(),
+			MaxBroadcastDelay:            settings.MaxBroadcastDelay.String(),
+			WorkerPoolSize:               settings.WorkerPoolSize,
+			MaxDataPlaneConnections:      settings.MaxDataPlaneConnections,
+			InformerResyncInterval:       settings.InformerResyncInterval.String(),
+			InformerWatchErrorBackoff:    settings.InformerWatchErrorBackoff.String(),
+			InformerWatchErrorMaxBackoff: settings.InformerWatchErrorMaxBackoff.String(),
+			EnvoyConcurrency:             settings.EnvoyConcurrency,
+		}
+		for informer, interval := range settings.InformerResyncIntervals {
+			if performance.InformerResyncIntervals == nil {
+				performance.InformerResyncIntervals = make(map[string]string)
+			}
+			performance.InformerResyncIntervals[string(informer)] = interval.String()
+		}
+		if performanceJSON, err := json.Marshal(performance); err != nil {
+			log.Error().Err(err).Msgf("Error marshaling performance settings struct: %+v", performance)
+		} else {
+			_, _ = fmt.Fprint(w, string(performanceJSON))
+		}
+	})
+}
@@ -0,0 +1,41 @@
+package debugger
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/k8s"
+)
+
+// Tests getPerformanceSettingsHandler through HTTP handler returns the effective performance settings
+func TestPerformanceSettingsHandler(t *testing.T) {
+	assert := tassert.New(t)
+
+	mockConfig := configurator.NewMockConfigurator(gomock.NewController(t))
+	ds := DebugConfig{
+		configurator: mockConfig,
+	}
+
+	mockConfig.EXPECT().GetPerformanceSettings().Return(configurator.PerformanceSettings{
+		Profile:                 configurator.PerformanceProfileSmall,
+		BroadcastGracePeriod:    time.Second,
+		MaxBroadcastDelay:       5 * time.Second,
+		WorkerPoolSize:          2,
+		MaxDataPlaneConnections: 500,
+		InformerResyncInterval:  5 * time.Minute,
+		EnvoyConcurrency:        1,
+
+		InformerResyncIntervals:      map[k8s.InformerKey]time.Duration{k8s.Pods: time.Minute},
+		InformerWatchErrorMaxBackoff: 5 * time.Minute,
+	})
+
+	responseRecorder := httptest.NewRecorder()
+	ds.getPerformanceSettingsHandler().ServeHTTP(responseRecorder, nil)
+	expectedResponseBody := `{"profile":"small","broadcastGracePeriod":"1s","maxBroadcastDelay":"5s","workerPoolSize":2,"maxDataPlaneConnections":500,"informerResyncInterval":"5m0s","informerResyncIntervals":{"Pods":"1m0s"},"informerWatchErrorBackoff":"0s","informerWatchErrorMaxBackoff":"5m0s","envoyConcurrency":1}`
+	assert.Equal(expectedResponseBody, responseRecorder.Body.String())
+}
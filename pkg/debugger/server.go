@@ -8,6 +8,7 @@ import (
 	"k8s.io/client-go/rest"
 
 	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/diagnostics"
 	"github.com/openservicemesh/osm/pkg/envoy/registry"
 	"github.com/openservicemesh/osm/pkg/k8s"
 )
@@ -22,6 +23,11 @@ func (ds DebugConfig) GetHandlers() map[string]http.Handler {
 		"/debug/config":        ds.getOSMConfigHandler(),
 		"/debug/namespaces":    ds.getMonitoredNamespacesHandler(),
 		"/debug/feature-flags": ds.getFeatureFlags(),
+		"/debug/diagnostics":   ds.getDiagnosticsHandler(),
+		"/debug/performance":   ds.getPerformanceSettingsHandler(),
+
+		// Heap snapshots taken while memory profiling is enabled in the MeshConfig
+		"/debug/heap-snapshots": ds.getHeapSnapshotsHandler(),
 
 		// Pprof handlers
 		"/debug/pprof/":        http.HandlerFunc(pprof.Index),
@@ -29,6 +35,14 @@ func (ds DebugConfig) GetHandlers() map[string]http.Handler {
 		"/debug/pprof/profile": http.HandlerFunc(pprof.Profile),
 		"/debug/pprof/symbol":  http.HandlerFunc(pprof.Symbol),
 		"/debug/pprof/trace":   http.HandlerFunc(pprof.Trace),
+
+		// Profiles of the controller's memory and contention, the mutex and block profiles are only sampled while
+		// memory profiling is enabled in the MeshConfig
+		"/debug/pprof/heap":      pprof.Handler("heap"),
+		"/debug/pprof/allocs":    pprof.Handler("allocs"),
+		"/debug/pprof/goroutine": pprof.Handler("goroutine"),
+		"/debug/pprof/mutex":     pprof.Handler("mutex"),
+		"/debug/pprof/block":     pprof.Handler("block"),
 	}
 
 	// provides an index of the available /debug endpoints
@@ -38,7 +52,7 @@ func (ds DebugConfig) GetHandlers() map[string]http.Handler {
 }
 
 // NewDebugConfig returns an implementation of DebugConfig interface.
-func NewDebugConfig(certDebugger CertificateManagerDebugger, xdsDebugger XDSDebugger, meshCatalogDebugger MeshCatalogDebugger, proxyRegistry *registry.ProxyRegistry, kubeConfig *rest.Config, kubeClient kubernetes.Interface, cfg configurator.Configurator, kubeController k8s.Controller) DebugConfig {
+func NewDebugConfig(certDebugger CertificateManagerDebugger, xdsDebugger XDSDebugger, meshCatalogDebugger MeshCatalogDebugger, proxyRegistry *registry.ProxyRegistry, kubeConfig *rest.Config, kubeClient kubernetes.Interface, cfg configurator.Configurator, kubeController k8s.Controller, diagnosticsRunner *diagnostics.Runner) DebugConfig {
 	return DebugConfig{
 		certDebugger:        certDebugger,
 		xdsDebugger:         xdsDebugger,
@@ -50,6 +64,8 @@ func NewDebugConfig(certDebugger CertificateManagerDebugger, xdsDebugger XDSDebu
 		// We need the Kubernetes config to be able to establish port forwarding to the Envoy pod we want to debug.
 		kubeConfig: kubeConfig,
 
-		configurator: cfg,
+		configurator:      cfg,
+		diagnosticsRunner: diagnosticsRunner,
+		heapSnapshots:     &heapSnapshots{},
 	}
 }
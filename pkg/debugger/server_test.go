@@ -32,7 +32,8 @@ func TestGetHandlers(t *testing.T) {
 		nil,
 		client,
 		mockConfig,
-		mockKubeController)
+		mockKubeController,
+		nil)
 
 	handlers := ds.GetHandlers()
 
@@ -43,12 +44,20 @@ func TestGetHandlers(t *testing.T) {
 		"/debug/policies",
 		"/debug/config",
 		"/debug/namespaces",
+		"/debug/diagnostics",
+		"/debug/performance",
+		"/debug/heap-snapshots",
 		// Pprof handlers
 		"/debug/pprof/",
 		"/debug/pprof/cmdline",
 		"/debug/pprof/profile",
 		"/debug/pprof/symbol",
 		"/debug/pprof/trace",
+		"/debug/pprof/heap",
+		"/debug/pprof/allocs",
+		"/debug/pprof/goroutine",
+		"/debug/pprof/mutex",
+		"/debug/pprof/block",
 	}
 
 	for _, endpoint := range debugEndpoints {
@@ -6,12 +6,13 @@ import (
 
 	access "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
 	spec "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
-	split "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha2"
+	split "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha4"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/diagnostics"
 	"github.com/openservicemesh/osm/pkg/envoy"
 	"github.com/openservicemesh/osm/pkg/envoy/registry"
 	"github.com/openservicemesh/osm/pkg/identity"
@@ -31,6 +32,8 @@ type DebugConfig struct {
 	kubeClient          kubernetes.Interface
 	kubeController      k8s.Controller
 	configurator        configurator.Configurator
+	diagnosticsRunner   *diagnostics.Runner
+	heapSnapshots       *heapSnapshots
 }
 
 // CertificateManagerDebugger is an interface with methods for debugging certificate issuance.
@@ -0,0 +1,148 @@
+package diagnostics
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+const (
+	// artifactConfigMapPrefix is the prefix of the names of ConfigMaps storing diagnostics artifacts
+	artifactConfigMapPrefix = "osm-diagnostics-"
+
+	// ArtifactKey is the key in the artifact ConfigMap holding the gzipped tarball of the collected diagnostics
+	ArtifactKey = "diagnostics.tar.gz"
+
+	// JobIDLabelKey is the label identifying the job that created an artifact ConfigMap
+	JobIDLabelKey = "diagnostics.openservicemesh.io/job-id"
+
+	// maxArtifactSize is the maximum size of an artifact that can be stored in a ConfigMap
+	maxArtifactSize = 1024 * 1024
+
+	// errorsFileName is the name of the file in the artifact listing the errors encountered while collecting diagnostics
+	errorsFileName = "errors.txt"
+)
+
+var errArtifactTooLarge = errors.Errorf("Artifact exceeds the maximum size of %d bytes", maxArtifactSize)
+
+// buildArtifact returns a gzipped tarball with a file per proxy and Envoy admin API endpoint collected, laid out
+// as <namespace>/<pod>/<query>, along with a file listing the errors encountered while collecting diagnostics
+func buildArtifact(results []collectResult, collectErrors []string) ([]byte, error) {
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for _, result := range results {
+		if result.err != nil {
+			continue
+		}
+
+		name := path.Join(result.pod.Namespace, result.pod.Name, getQueryFileName(result.query))
+		if err := writeArtifactFile(tarWriter, name, result.data); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(collectErrors) > 0 {
+		if err := writeArtifactFile(tarWriter, errorsFileName, []byte(strings.Join(collectErrors, "\n")+"\n")); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return nil, errors.Wrap(err, "Error writing diagnostics artifact")
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, errors.Wrap(err, "Error compressing diagnostics artifact")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeArtifactFile writes a file with the given name and data to the given tarball
+func writeArtifactFile(tarWriter *tar.Writer, name string, data []byte) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return errors.Wrapf(err, "Error writing %s to diagnostics artifact", name)
+	}
+	if _, err := tarWriter.Write(data); err != nil {
+		return errors.Wrapf(err, "Error writing %s to diagnostics artifact", name)
+	}
+	return nil
+}
+
+// getQueryFileName returns the name of the file storing the response of the given Envoy admin API endpoint
+func getQueryFileName(query string) string {
+	query = strings.Trim(query, "/")
+	query = strings.NewReplacer("/", "_", "?", "_", "&", "_", "=", "_").Replace(query)
+	return query + ".txt"
+}
+
+// configMapArtifactStore is an ArtifactStore that stores artifacts in ConfigMaps in the OSM namespace
+type configMapArtifactStore struct {
+	kubeClient kubernetes.Interface
+	namespace  string
+}
+
+// NewConfigMapArtifactStore returns an ArtifactStore storing artifacts in ConfigMaps in the given namespace
+func NewConfigMapArtifactStore(kubeClient kubernetes.Interface, namespace string) ArtifactStore {
+	return &configMapArtifactStore{
+		kubeClient: kubeClient,
+		namespace:  namespace,
+	}
+}
+
+// Store stores the given artifact in a ConfigMap and returns the namespaced name of the ConfigMap
+func (s *configMapArtifactStore) Store(jobID string, artifact []byte) (string, error) {
+	if len(artifact) > maxArtifactSize {
+		return "", errArtifactTooLarge
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      artifactConfigMapPrefix + jobID,
+			Namespace: s.namespace,
+			Labels: map[string]string{
+				constants.OSMAppNameLabelKey: constants.OSMAppNameLabelValue,
+				JobIDLabelKey:                jobID,
+			},
+		},
+		BinaryData: map[string][]byte{
+			ArtifactKey: artifact,
+		},
+	}
+
+	if _, err := s.kubeClient.CoreV1().ConfigMaps(s.namespace).Create(context.Background(), configMap, metav1.CreateOptions{}); err != nil {
+		return "", errors.Wrapf(err, "Error creating ConfigMap %s/%s for diagnostics artifact", configMap.Namespace, configMap.Name)
+	}
+
+	return fmt.Sprintf("%s/%s", configMap.Namespace, configMap.Name), nil
+}
+
+// Delete deletes the ConfigMap storing the artifact of the given job
+func (s *configMapArtifactStore) Delete(jobID string) error {
+	name := artifactConfigMapPrefix + jobID
+	err := s.kubeClient.CoreV1().ConfigMaps(s.namespace).Delete(context.Background(), name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "Error deleting ConfigMap %s/%s for diagnostics artifact", s.namespace, name)
+	}
+	return nil
+}
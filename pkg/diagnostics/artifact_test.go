@@ -0,0 +1,83 @@
+package diagnostics
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/pkg/errors"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func readArtifact(t *testing.T, artifact []byte) map[string]string {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(artifact))
+	tassert.Nil(t, err)
+
+	files := make(map[string]string)
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		tassert.Nil(t, err)
+
+		data, err := ioutil.ReadAll(tarReader)
+		tassert.Nil(t, err)
+		files[header.Name] = string(data)
+	}
+	return files
+}
+
+func TestBuildArtifact(t *testing.T) {
+	assert := tassert.New(t)
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "bookstore-1", Namespace: "bookstore"}}
+	results := []collectResult{
+		{pod: pod, query: "stats", data: []byte("stats data")},
+		{pod: pod, query: "/clusters?format=json", data: []byte("clusters data")},
+		{pod: pod, query: "certs", err: errors.New("connection refused")},
+	}
+
+	artifact, err := buildArtifact(results, []string{"bookstore/bookstore-1 certs: connection refused"})
+	assert.Nil(err)
+
+	assert.Equal(map[string]string{
+		"bookstore/bookstore-1/stats.txt":                "stats data",
+		"bookstore/bookstore-1/clusters_format_json.txt": "clusters data",
+		"errors.txt": "bookstore/bookstore-1 certs: connection refused\n",
+	}, readArtifact(t, artifact))
+}
+
+func TestConfigMapArtifactStore(t *testing.T) {
+	assert := tassert.New(t)
+
+	kubeClient := fake.NewSimpleClientset()
+	store := NewConfigMapArtifactStore(kubeClient, "osm-system")
+
+	name, err := store.Store("job-1", []byte("artifact"))
+	assert.Nil(err)
+	assert.Equal("osm-system/osm-diagnostics-job-1", name)
+
+	configMap, err := kubeClient.CoreV1().ConfigMaps("osm-system").Get(context.Background(), "osm-diagnostics-job-1", metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Equal([]byte("artifact"), configMap.BinaryData[ArtifactKey])
+	assert.Equal("job-1", configMap.Labels[JobIDLabelKey])
+
+	_, err = store.Store("job-2", make([]byte, maxArtifactSize+1))
+	assert.Equal(errArtifactTooLarge, err)
+
+	assert.Nil(store.Delete("job-1"))
+	_, err = kubeClient.CoreV1().ConfigMaps("osm-system").Get(context.Background(), "osm-diagnostics-job-1", metav1.GetOptions{})
+	assert.NotNil(err)
+
+	// Deleting a missing artifact is not an error
+	assert.Nil(store.Delete("job-2"))
+}
@@ -0,0 +1,82 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s"
+)
+
+// portForwardCollector is a Collector that reaches the Envoy admin API of proxies by port forwarding through the
+// Kubernetes API server
+type portForwardCollector struct {
+	kubeConfig *rest.Config
+	kubeClient kubernetes.Interface
+	httpClient *http.Client
+}
+
+// NewPortForwardCollector returns a Collector that port forwards to the Envoy admin port of pods using the Kubernetes API
+func NewPortForwardCollector(kubeConfig *rest.Config, kubeClient kubernetes.Interface) Collector {
+	return &portForwardCollector{
+		kubeConfig: kubeConfig,
+		kubeClient: kubeClient,
+		httpClient: &http.Client{Timeout: collectTimeout},
+	}
+}
+
+// Collect returns the response of the given Envoy admin API endpoint of the proxy in the given pod
+func (c *portForwardCollector) Collect(ctx context.Context, pod *corev1.Pod, query string) ([]byte, error) {
+	dialer, err := k8s.DialerToPod(c.kubeConfig, c.kubeClient, pod.Name, pod.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	// A local port of 0 lets the system choose an available port, allowing proxies to be collected from concurrently
+	portForwarder, err := k8s.NewPortForwarder(dialer, fmt.Sprintf("0:%d", constants.EnvoyAdminPort))
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	err = portForwarder.Start(func(pf *k8s.PortForwarder) error {
+		defer pf.Stop()
+
+		localPort, err := pf.GetLocalPort()
+		if err != nil {
+			return err
+		}
+
+		url := fmt.Sprintf("http://%s:%d/%s", constants.LocalhostIPAddress, localPort, strings.TrimLeft(query, "/"))
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return errors.Errorf("Error creating request for url %s: %s", url, err)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return errors.Errorf("Error fetching url %s: %s", url, err)
+		}
+		defer resp.Body.Close() //nolint: errcheck,gosec
+
+		if resp.StatusCode != http.StatusOK {
+			return errors.Errorf("Error fetching url %s: HTTP %d", url, resp.StatusCode)
+		}
+
+		data, err = ioutil.ReadAll(resp.Body)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error collecting %s from pod %s/%s", query, pod.Namespace, pod.Name)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,273 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/mesh"
+)
+
+// NewRunner returns a Runner that collects diagnostics using the given collector and stores them in the given store
+func NewRunner(kubeClient kubernetes.Interface, collector Collector, store ArtifactStore, maxConcurrency int) *Runner {
+	if maxConcurrency <= 0 {
+		maxConcurrency = DefaultMaxConcurrency
+	}
+
+	return &Runner{
+		kubeClient:     kubeClient,
+		collector:      collector,
+		store:          store,
+		maxConcurrency: maxConcurrency,
+		jobs:           make(map[string]*Job),
+	}
+}
+
+// Submit validates the given job spec and starts a job collecting the requested diagnostics in the background
+func (r *Runner) Submit(spec JobSpec) (Job, error) {
+	if spec.Namespace == "" {
+		return Job{}, errors.New("A namespace must be specified")
+	}
+	if _, err := labels.Parse(spec.LabelSelector); err != nil {
+		return Job{}, errors.Wrapf(err, "Invalid label selector %s", spec.LabelSelector)
+	}
+	if len(spec.Queries) == 0 {
+		spec.Queries = DefaultQueries
+	}
+	for _, query := range spec.Queries {
+		if err := validateQuery(query); err != nil {
+			return Job{}, err
+		}
+	}
+
+	job := &Job{
+		ID:        uuid.New().String(),
+		Spec:      spec,
+		State:     JobRunning,
+		StartedAt: time.Now(),
+	}
+
+	r.mu.Lock()
+	if r.runningJobsLocked() >= maxRunningJobs {
+		r.mu.Unlock()
+		return Job{}, errors.Errorf("Too many running jobs, at most %d jobs can run concurrently", maxRunningJobs)
+	}
+	r.jobs[job.ID] = job
+	r.order = append(r.order, job.ID)
+	pruned := r.pruneLocked()
+	submitted := *job
+	r.mu.Unlock()
+
+	for _, prunedJob := range pruned {
+		if prunedJob.Artifact == "" {
+			continue
+		}
+		if err := r.store.Delete(prunedJob.ID); err != nil {
+			log.Error().Err(err).Msgf("Error deleting artifact of pruned diagnostics job %s", prunedJob.ID)
+		}
+	}
+
+	go r.run(job.ID, spec)
+
+	return submitted, nil
+}
+
+// validateQuery returns an error if the given query is not a read-only Envoy admin API endpoint.
+// Query parameters, such as 'stats?format=json', are allowed.
+func validateQuery(query string) error {
+	parsed, err := url.Parse(query)
+	if err != nil {
+		return errors.Wrapf(err, "Invalid query %s", query)
+	}
+	if parsed.Scheme != "" || parsed.Host != "" || !allowedQueries[strings.Trim(parsed.Path, "/")] {
+		return errors.Errorf("Query %s is not an allowed Envoy admin API endpoint", query)
+	}
+	return nil
+}
+
+// GetJob returns the job with the given ID
+func (r *Runner) GetJob(id string) (Job, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	job, ok := r.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+// ListJobs returns the retained jobs in the order they were submitted
+func (r *Runner) ListJobs() []Job {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	jobs := make([]Job, 0, len(r.order))
+	for _, id := range r.order {
+		jobs = append(jobs, *r.jobs[id])
+	}
+	return jobs
+}
+
+// run collects the diagnostics for the given job and stores the aggregated artifact
+func (r *Runner) run(id string, spec JobSpec) {
+	pods, err := r.listPods(spec)
+	if err != nil {
+		r.complete(id, JobFailed, 0, []string{err.Error()}, "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), jobTimeout)
+	defer cancel()
+
+	results := r.collect(ctx, pods, spec.Queries)
+
+	var collectErrors []string
+	collectedPods := make(map[*corev1.Pod]bool)
+	for _, result := range results {
+		if result.err != nil {
+			collectErrors = append(collectErrors, fmt.Sprintf("%s/%s %s: %s", result.pod.Namespace, result.pod.Name, result.query, result.err))
+			continue
+		}
+		collectedPods[result.pod] = true
+	}
+	proxies := len(collectedPods)
+
+	artifact, err := buildArtifact(results, collectErrors)
+	if err != nil {
+		r.complete(id, JobFailed, proxies, append(collectErrors, err.Error()), "")
+		return
+	}
+
+	name, err := r.store.Store(id, artifact)
+	if err != nil {
+		r.complete(id, JobFailed, proxies, append(collectErrors, err.Error()), "")
+		return
+	}
+
+	log.Info().Msgf("Diagnostics job %s collected %v from %d/%d proxies in namespace %s, stored in %s", id, spec.Queries, proxies, len(pods), spec.Namespace, name)
+	r.complete(id, JobSucceeded, proxies, collectErrors, name)
+}
+
+// listPods returns the running pods with a proxy selected by the given job spec
+func (r *Runner) listPods(spec JobSpec) ([]*corev1.Pod, error) {
+	podList, err := r.kubeClient.CoreV1().Pods(spec.Namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: spec.LabelSelector,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error listing pods in namespace %s", spec.Namespace)
+	}
+
+	var pods []*corev1.Pod
+	for idx := range podList.Items {
+		pod := &podList.Items[idx]
+		if !mesh.ProxyLabelExists(*pod) || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		pods = append(pods, pod)
+	}
+
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+	return pods, nil
+}
+
+// collectResult is the result of collecting an Envoy admin API endpoint from a proxy
+type collectResult struct {
+	pod   *corev1.Pod
+	query string
+	data  []byte
+	err   error
+}
+
+// collect collects the given queries from the proxies in the given pods, at most maxConcurrency proxies at a time
+func (r *Runner) collect(ctx context.Context, pods []*corev1.Pod, queries []string) []collectResult {
+	results := make([]collectResult, len(pods)*len(queries))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, r.maxConcurrency)
+	for podIdx, pod := range pods {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(podIdx int, pod *corev1.Pod) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			for queryIdx, query := range queries {
+				data, err := r.collector.Collect(ctx, pod, query)
+				results[podIdx*len(queries)+queryIdx] = collectResult{
+					pod:   pod,
+					query: query,
+					data:  data,
+					err:   err,
+				}
+			}
+		}(podIdx, pod)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// complete records the completion of the job with the given ID
+func (r *Runner) complete(id string, state JobState, proxies int, jobErrors []string, artifact string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job, ok := r.jobs[id]
+	if !ok {
+		return
+	}
+	job.State = state
+	job.CompletedAt = time.Now()
+	job.Proxies = proxies
+	job.Errors = jobErrors
+	job.Artifact = artifact
+}
+
+// runningJobsLocked returns the number of running jobs, r.mu must be held
+func (r *Runner) runningJobsLocked() int {
+	running := 0
+	for _, job := range r.jobs {
+		if job.State == JobRunning {
+			running++
+		}
+	}
+	return running
+}
+
+// pruneLocked removes the oldest completed jobs beyond maxRetainedJobs and returns them, r.mu must be held
+func (r *Runner) pruneLocked() []Job {
+	var pruned []Job
+	for len(r.order) > maxRetainedJobs {
+		prunedJob := false
+		for idx, id := range r.order {
+			if r.jobs[id].State == JobRunning {
+				continue
+			}
+			pruned = append(pruned, *r.jobs[id])
+			delete(r.jobs, id)
+			r.order = append(r.order[:idx], r.order[idx+1:]...)
+			prunedJob = true
+			break
+		}
+		if !prunedJob {
+			break
+		}
+	}
+	return pruned
+}
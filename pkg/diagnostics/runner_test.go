@@ -0,0 +1,253 @@
+package diagnostics
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+type fakeCollector struct {
+	mu        sync.Mutex
+	collected []string
+	failPod   string
+	// block, if set, blocks collection until it is closed or the job times out
+	block chan struct{}
+}
+
+func (c *fakeCollector) Collect(ctx context.Context, pod *corev1.Pod, query string) ([]byte, error) {
+	if c.block != nil {
+		select {
+		case <-c.block:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if pod.Name == c.failPod {
+		return nil, errors.New("connection refused")
+	}
+	c.collected = append(c.collected, pod.Name+"/"+query)
+	return []byte(query + " of " + pod.Name), nil
+}
+
+type fakeArtifactStore struct {
+	mu        sync.Mutex
+	artifacts map[string][]byte
+	err       error
+}
+
+func (s *fakeArtifactStore) Store(jobID string, artifact []byte) (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.artifacts[jobID] = artifact
+	return "osm-system/" + artifactConfigMapPrefix + jobID, nil
+}
+
+func (s *fakeArtifactStore) Delete(jobID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.artifacts, jobID)
+	return nil
+}
+
+func newPod(name string, labels map[string]string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "bookstore",
+			Labels:    labels,
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func waitForJob(t *testing.T, r *Runner, id string) Job {
+	var job Job
+	tassert.Eventually(t, func() bool {
+		job, _ = r.GetJob(id)
+		return job.State != JobRunning
+	}, 5*time.Second, 10*time.Millisecond)
+	return job
+}
+
+func TestRunner(t *testing.T) {
+	meshLabels := func(app string) map[string]string {
+		return map[string]string{
+			constants.EnvoyUniqueIDLabelName: uuid.New().String(),
+			"app":                            app,
+		}
+	}
+
+	kubeClient := fake.NewSimpleClientset(
+		newPod("bookstore-1", meshLabels("bookstore"), corev1.PodRunning),
+		newPod("bookstore-2", meshLabels("bookstore"), corev1.PodRunning),
+		newPod("bookstore-pending", meshLabels("bookstore"), corev1.PodPending),
+		newPod("bookstore-no-proxy", map[string]string{"app": "bookstore"}, corev1.PodRunning),
+		newPod("bookbuyer", meshLabels("bookbuyer"), corev1.PodRunning),
+	)
+
+	t.Run("collects the requested queries from running proxies selected by the job", func(t *testing.T) {
+		assert := tassert.New(t)
+
+		collector := &fakeCollector{}
+		store := &fakeArtifactStore{artifacts: make(map[string][]byte)}
+		r := NewRunner(kubeClient, collector, store, 0)
+
+		submitted, err := r.Submit(JobSpec{Namespace: "bookstore", LabelSelector: "app=bookstore", Queries: []string{"stats"}})
+		assert.Nil(err)
+		assert.Equal(JobRunning, submitted.State)
+
+		job := waitForJob(t, r, submitted.ID)
+		assert.Equal(JobSucceeded, job.State)
+		assert.Equal(2, job.Proxies)
+		assert.Empty(job.Errors)
+		assert.Equal("osm-system/osm-diagnostics-"+job.ID, job.Artifact)
+		assert.ElementsMatch([]string{"bookstore-1/stats", "bookstore-2/stats"}, collector.collected)
+		assert.Contains(store.artifacts, job.ID)
+	})
+
+	t.Run("collects the default queries and records errors for proxies that could not be reached", func(t *testing.T) {
+		assert := tassert.New(t)
+
+		collector := &fakeCollector{failPod: "bookstore-2"}
+		store := &fakeArtifactStore{artifacts: make(map[string][]byte)}
+		r := NewRunner(kubeClient, collector, store, 1)
+
+		submitted, err := r.Submit(JobSpec{Namespace: "bookstore", LabelSelector: "app=bookstore"})
+		assert.Nil(err)
+		assert.Equal(DefaultQueries, submitted.Spec.Queries)
+
+		job := waitForJob(t, r, submitted.ID)
+		assert.Equal(JobSucceeded, job.State)
+		assert.Equal(1, job.Proxies)
+		assert.Len(job.Errors, len(DefaultQueries))
+		assert.ElementsMatch([]string{"bookstore-1/stats", "bookstore-1/clusters", "bookstore-1/certs"}, collector.collected)
+	})
+
+	t.Run("fails when the artifact cannot be stored", func(t *testing.T) {
+		assert := tassert.New(t)
+
+		store := &fakeArtifactStore{err: errArtifactTooLarge}
+		r := NewRunner(kubeClient, &fakeCollector{}, store, 0)
+
+		submitted, err := r.Submit(JobSpec{Namespace: "bookstore"})
+		assert.Nil(err)
+
+		job := waitForJob(t, r, submitted.ID)
+		assert.Equal(JobFailed, job.State)
+		assert.Equal([]string{errArtifactTooLarge.Error()}, job.Errors)
+		assert.Empty(job.Artifact)
+	})
+
+	t.Run("rejects invalid job specs", func(t *testing.T) {
+		assert := tassert.New(t)
+
+		r := NewRunner(kubeClient, &fakeCollector{}, &fakeArtifactStore{}, 0)
+
+		_, err := r.Submit(JobSpec{})
+		assert.NotNil(err)
+
+		_, err = r.Submit(JobSpec{Namespace: "bookstore", LabelSelector: "app in (bookstore"})
+		assert.NotNil(err)
+
+		_, err = r.Submit(JobSpec{Namespace: "bookstore", Queries: []string{"stats", "quitquitquit"}})
+		assert.NotNil(err)
+
+		_, err = r.Submit(JobSpec{Namespace: "bookstore", Queries: []string{"http://example.com/stats"}})
+		assert.NotNil(err)
+
+		assert.Empty(r.ListJobs())
+	})
+}
+
+func TestRunnerPrunesCompletedJobs(t *testing.T) {
+	assert := tassert.New(t)
+
+	store := &fakeArtifactStore{artifacts: make(map[string][]byte)}
+	r := NewRunner(fake.NewSimpleClientset(), &fakeCollector{}, store, 0)
+
+	var ids []string
+	for i := 0; i < maxRetainedJobs+5; i++ {
+		job, err := r.Submit(JobSpec{Namespace: "bookstore"})
+		assert.Nil(err)
+		waitForJob(t, r, job.ID)
+		ids = append(ids, job.ID)
+	}
+
+	jobs := r.ListJobs()
+	assert.Len(jobs, maxRetainedJobs)
+	assert.Equal(ids[len(ids)-1], jobs[len(jobs)-1].ID)
+
+	_, ok := r.GetJob(ids[0])
+	assert.False(ok)
+
+	// Artifacts of pruned jobs are deleted
+	assert.Len(store.artifacts, maxRetainedJobs)
+	assert.NotContains(store.artifacts, ids[0])
+}
+
+func TestRunnerLimitsRunningJobs(t *testing.T) {
+	assert := tassert.New(t)
+
+	kubeClient := fake.NewSimpleClientset(newPod("bookstore-1", map[string]string{constants.EnvoyUniqueIDLabelName: uuid.New().String()}, corev1.PodRunning))
+	collector := &fakeCollector{block: make(chan struct{})}
+	r := NewRunner(kubeClient, collector, &fakeArtifactStore{artifacts: make(map[string][]byte)}, 0)
+
+	var ids []string
+	for i := 0; i < maxRunningJobs; i++ {
+		job, err := r.Submit(JobSpec{Namespace: "bookstore"})
+		assert.Nil(err)
+		ids = append(ids, job.ID)
+	}
+
+	_, err := r.Submit(JobSpec{Namespace: "bookstore"})
+	assert.NotNil(err)
+
+	close(collector.block)
+	for _, id := range ids {
+		assert.Equal(JobSucceeded, waitForJob(t, r, id).State)
+	}
+
+	_, err = r.Submit(JobSpec{Namespace: "bookstore"})
+	assert.Nil(err)
+}
+
+func TestValidateQuery(t *testing.T) {
+	testCases := []struct {
+		query     string
+		expectErr bool
+	}{
+		{query: "stats", expectErr: false},
+		{query: "/config_dump", expectErr: false},
+		{query: "stats?format=json", expectErr: false},
+		{query: "stats/prometheus", expectErr: false},
+		{query: "quitquitquit", expectErr: true},
+		{query: "healthcheck/fail", expectErr: true},
+		{query: "logging?level=trace", expectErr: true},
+		{query: "//example.com/stats", expectErr: true},
+		{query: "%zz", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expectErr, validateQuery(tc.query) != nil)
+		})
+	}
+}
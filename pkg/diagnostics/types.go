@@ -0,0 +1,135 @@
+// Package diagnostics implements jobs collecting diagnostics from the Envoy admin API of a set of proxies on demand,
+// aggregating the results into a single artifact stored in the cluster.
+package diagnostics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var (
+	log = logger.New("diagnostics")
+)
+
+const (
+	// DefaultMaxConcurrency is the default number of proxies diagnostics are collected from concurrently by a job
+	DefaultMaxConcurrency = 5
+
+	// maxRetainedJobs is the number of completed jobs retained by the runner, along with their artifacts
+	maxRetainedJobs = 20
+
+	// maxRunningJobs is the number of jobs that can run concurrently, further jobs are rejected
+	maxRunningJobs = 5
+
+	// collectTimeout is the maximum duration of a request to the Envoy admin API of a proxy
+	collectTimeout = 30 * time.Second
+
+	// jobTimeout is the maximum duration of a job, proxies not collected from by then are recorded as errors
+	jobTimeout = 5 * time.Minute
+)
+
+// DefaultQueries is the list of Envoy admin API endpoints collected when a job does not specify any
+var DefaultQueries = []string{"stats", "clusters", "certs"}
+
+// allowedQueries is the set of read-only Envoy admin API endpoints jobs can collect. Other endpoints, such as
+// those modifying the proxy's state, are rejected.
+var allowedQueries = map[string]bool{
+	"certs":            true,
+	"clusters":         true,
+	"config_dump":      true,
+	"listeners":        true,
+	"memory":           true,
+	"ready":            true,
+	"runtime":          true,
+	"server_info":      true,
+	"stats":            true,
+	"stats/prometheus": true,
+}
+
+// JobState is the type used to represent the state of a diagnostics job
+type JobState string
+
+const (
+	// JobRunning indicates the job is collecting diagnostics
+	JobRunning JobState = "Running"
+
+	// JobSucceeded indicates diagnostics were collected and stored, possibly with errors for some proxies
+	JobSucceeded JobState = "Succeeded"
+
+	// JobFailed indicates the job could not collect or store diagnostics
+	JobFailed JobState = "Failed"
+)
+
+// JobSpec is the type used to represent the proxies diagnostics are collected from, and the diagnostics collected
+type JobSpec struct {
+	// Namespace is the namespace of the pods to collect diagnostics from.
+	Namespace string `json:"namespace"`
+
+	// LabelSelector selects the pods to collect diagnostics from, all pods in the namespace are selected if empty.
+	LabelSelector string `json:"labelSelector,omitempty"`
+
+	// Queries is the list of Envoy admin API endpoints to collect, DefaultQueries is used if empty.
+	Queries []string `json:"queries"`
+}
+
+// Job is the type used to represent a diagnostics job and its status
+type Job struct {
+	// ID is the unique ID of the job.
+	ID string `json:"id"`
+
+	// Spec is the spec of the job.
+	Spec JobSpec `json:"spec"`
+
+	// State is the state of the job.
+	State JobState `json:"state"`
+
+	// StartedAt is the time at which the job was started.
+	StartedAt time.Time `json:"startedAt"`
+
+	// CompletedAt is the time at which the job completed, zero if the job is running.
+	CompletedAt time.Time `json:"completedAt,omitempty"`
+
+	// Proxies is the number of proxies diagnostics were collected from.
+	Proxies int `json:"proxies"`
+
+	// Errors is the list of errors encountered while collecting diagnostics.
+	Errors []string `json:"errors,omitempty"`
+
+	// Artifact is the name of the artifact storing the collected diagnostics.
+	Artifact string `json:"artifact,omitempty"`
+}
+
+// Collector is the interface used to collect the response of an Envoy admin API endpoint from a proxy.
+// Collectors may reach proxies through the Kubernetes API or through an agent running on the pod's node.
+type Collector interface {
+	// Collect returns the response of the given Envoy admin API endpoint of the proxy in the given pod.
+	Collect(ctx context.Context, pod *corev1.Pod, query string) ([]byte, error)
+}
+
+// ArtifactStore is the interface used to store the diagnostics collected by a job
+type ArtifactStore interface {
+	// Store stores the given artifact for the given job and returns the name of the stored artifact.
+	Store(jobID string, artifact []byte) (string, error)
+
+	// Delete deletes the artifact stored for the given job, if any.
+	Delete(jobID string) error
+}
+
+// Runner runs diagnostics jobs and keeps track of their status
+type Runner struct {
+	kubeClient     kubernetes.Interface
+	collector      Collector
+	store          ArtifactStore
+	maxConcurrency int
+
+	mu   sync.RWMutex
+	jobs map[string]*Job
+	// order is the list of job IDs in the order they were submitted
+	order []string
+}
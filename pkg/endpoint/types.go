@@ -32,6 +32,18 @@ type Provider interface {
 type Endpoint struct {
 	net.IP `json:"ip"`
 	Port   `json:"port"`
+
+	// Weight is the load balancing weight of the endpoint relative to other endpoints of the same service.
+	// A zero weight indicates the endpoint does not carry an explicit weight.
+	Weight uint32 `json:"weight,omitempty"`
+
+	// Draining indicates the endpoint belongs to a terminating instance of the service that still serves requests.
+	// Proxies treat draining endpoints as unhealthy, only sending them requests when too few other endpoints are healthy.
+	Draining bool `json:"draining,omitempty"`
+
+	// PortName is the name of the service port the endpoint serves, if known.
+	// It identifies the service port of endpoints whose port was resolved from a named target port.
+	PortName string `json:"portName,omitempty"`
 }
 
 func (ep Endpoint) String() string {
@@ -40,3 +52,22 @@ func (ep Endpoint) String() string {
 
 // Port is a numerical type representing a port on which a service is exposed
 type Port uint32
+
+// ExternalService is a service registered with a service registry outside the mesh, such as a Consul catalog,
+// along with the endpoints of its instances
+type ExternalService struct {
+	// Provider is the unique identifier of the provider the service was discovered from
+	Provider string
+
+	// Name is the name of the service in the provider's registry
+	Name string
+
+	// Endpoints is the list of endpoints of the service's instances
+	Endpoints []Endpoint
+}
+
+// ClusterName returns the name of the Envoy cluster corresponding to the external service.
+// The name is made up of three parts so that it never collides with the <namespace>/<name> clusters of mesh services.
+func (es ExternalService) ClusterName() string {
+	return fmt.Sprintf("external/%s/%s", es.Provider, es.Name)
+}
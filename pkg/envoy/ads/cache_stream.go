@@ -16,11 +16,14 @@ import (
 	"github.com/openservicemesh/osm/pkg/envoy"
 	"github.com/openservicemesh/osm/pkg/errcode"
 	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
 )
 
 // Routine which fulfills listening to proxy broadcasts
 func (s *Server) broadcastListener() {
-	// Register to Envoy global broadcast updates
+	// Register to Envoy global broadcast updates.
+	// The snapshot cache does not track the namespaces referenced by each proxy's config, so all proxies are
+	// updated regardless of the scope of the broadcast.
 	broadcastUpdate := events.Subscribe(announcements.ProxyBroadcast)
 	for {
 		<-broadcastUpdate
@@ -81,16 +84,44 @@ func GetProxyFromPod(pod *v1.Pod) (*envoy.Proxy, error) {
 	return tempProxy, err
 }
 
+// snapshotTypeURIs are the types of the resources held by the snapshots recorded in the cache
+var snapshotTypeURIs = []envoy.TypeURI{envoy.TypeCDS, envoy.TypeEDS, envoy.TypeLDS, envoy.TypeRDS, envoy.TypeSDS}
+
 // RecordFullSnapshot stores a group of resources as a new Snapshot with a new version in the cache.
 // It also runs a consistency check on the snapshot (will warn if there are missing resources referenced in
 // the snapshot)
+// A snapshot identical to the last one recorded for the proxy is not recorded, so that its version is not bumped.
 func (s *Server) RecordFullSnapshot(proxy *envoy.Proxy, snapshotResources map[envoy.TypeURI][]types.Resource) error {
+	cn := proxy.GetCertificateCommonName().String()
+
+	// The hash is computed over the exact resources of the snapshot, which doesn't hold all the types of resources
+	var allResources []types.Resource
+	for _, typeURI := range snapshotTypeURIs {
+		allResources = append(allResources, snapshotResources[typeURI]...)
+	}
+	hash, err := hashResources(allResources)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error hashing the snapshot for proxy %s", cn)
+	}
+
 	s.configVerMutex.Lock()
-	s.configVersion[proxy.GetCertificateCommonName().String()]++
+	if lastHash, ok := s.configHash[cn]; ok && err == nil && hash == lastHash {
+		s.configVerMutex.Unlock()
+		log.Debug().Msgf("Snapshot for proxy %s unchanged, skipping update", cn)
+		metricsstore.DefaultMetricsStore.ProxyUnchangedConfigCount.WithLabelValues(envoy.TypeADS.String()).Inc()
+		return nil
+	}
+	s.configVersion[cn]++
+	version := s.configVersion[cn]
+	if err == nil {
+		s.configHash[cn] = hash
+	} else {
+		delete(s.configHash, cn)
+	}
 	s.configVerMutex.Unlock()
 
 	snapshot := cache.NewSnapshot(
-		fmt.Sprintf("%d", s.configVersion[proxy.GetCertificateCommonName().String()]),
+		fmt.Sprintf("%d", version),
 		snapshotResources[envoy.TypeEDS],
 		snapshotResources[envoy.TypeCDS],
 		snapshotResources[envoy.TypeRDS],
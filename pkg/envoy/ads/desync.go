@@ -0,0 +1,143 @@
+package ads
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+)
+
+const (
+	// DefaultDesyncThreshold is the default time after which a proxy that has not acknowledged the config sent to it
+	// is considered desynced
+	DefaultDesyncThreshold = 2 * time.Minute
+
+	// DefaultDesyncCheckInterval is the default interval at which the proxies are checked for desyncs
+	DefaultDesyncCheckInterval = 30 * time.Second
+)
+
+// DesyncMonitorConfig is the configuration of the detection of the proxies lagging behind the config sent to them,
+// i.e. which have not acknowledged the last version of a type of config sent to them.
+type DesyncMonitorConfig struct {
+	// Threshold is the time after which a proxy that has not acknowledged the config sent to it is desynced, desyncs
+	// are not detected if 0
+	Threshold time.Duration
+
+	// CheckInterval is the interval at which the proxies are checked for desyncs
+	CheckInterval time.Duration
+
+	// Repush is whether the lagging types of config are pushed again to the desynced proxies, at most once per
+	// Threshold
+	Repush bool
+}
+
+// proxyResync is the message of a ProxyResyncRequested announcement
+type proxyResync struct {
+	commonName certificate.CommonName
+	typeURIs   []envoy.TypeURI
+}
+
+// EnableDesyncMonitor enables the detection of the proxies lagging behind the config sent to them. Desynced proxies
+// are reported as metrics and events, and optionally get the lagging types of config pushed again.
+// Desyncs are only detected for proxies not using the snapshot cache. It must be called before the server starts.
+func (s *Server) EnableDesyncMonitor(config DesyncMonitorConfig) {
+	s.desyncConfig = config
+}
+
+// startDesyncMonitor checks the proxies for desyncs at the configured interval until the given context is done
+func (s *Server) startDesyncMonitor(ctx context.Context) {
+	if s.desyncConfig.Threshold <= 0 || s.cacheEnabled {
+		return
+	}
+
+	checkInterval := s.desyncConfig.CheckInterval
+	if checkInterval <= 0 {
+		checkInterval = DefaultDesyncCheckInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+
+		// The time desyncs were last reported at, keyed by proxy and type
+		reported := make(map[string]time.Time)
+		for {
+			select {
+			case <-ticker.C:
+				reported = s.checkDesyncs(time.Now(), reported)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// checkDesyncs reports the connected proxies that have not acknowledged the config sent to them for longer than the
+// desync threshold. A desync is reported, and the lagging type pushed again if enabled, when it is first detected and
+// then at most once per threshold while it persists. It returns the time the current desyncs were last reported at.
+func (s *Server) checkDesyncs(now time.Time, lastReported map[string]time.Time) map[string]time.Time {
+	threshold := s.desyncConfig.Threshold
+	reported := make(map[string]time.Time)
+	desyncCount := make(map[envoy.TypeURI]int)
+
+	for cn, proxy := range s.proxyRegistry.ListConnectedProxies() {
+		unacknowledged := proxy.GetUnacknowledgedTypes()
+
+		var lagging []envoy.TypeURI
+		for _, typeURI := range envoy.XDSResponseOrder {
+			since, ok := unacknowledged[typeURI]
+			if !ok || now.Sub(since) < threshold {
+				continue
+			}
+			desyncCount[typeURI]++
+
+			key := fmt.Sprintf("%s/%s", cn, typeURI)
+			if reportedAt, ok := lastReported[key]; ok && now.Sub(reportedAt) < threshold {
+				reported[key] = reportedAt
+				continue
+			}
+			reported[key] = now
+			lagging = append(lagging, typeURI)
+
+			log.Warn().Msgf("Proxy %s has not acknowledged the %s config sent to it since %s: version %d sent, version %d acknowledged",
+				proxy.String(), typeURI.Short(), since.Format(time.RFC3339), proxy.GetLastSentVersion(typeURI), proxy.GetLastAppliedVersion(typeURI))
+			s.warnPodEvent(proxy, events.ProxyDesynced, "Proxy %s has not acknowledged the %s config sent to it for %s",
+				proxy.String(), typeURI.Short(), now.Sub(since).Round(time.Second))
+		}
+
+		if len(lagging) > 0 && s.desyncConfig.Repush {
+			for _, typeURI := range lagging {
+				metricsstore.DefaultMetricsStore.ProxyDesyncRepushCount.WithLabelValues(typeURI.Short()).Inc()
+			}
+			events.Publish(events.PubSubMessage{
+				AnnouncementType: announcements.ProxyResyncRequested,
+				NewObj:           proxyResync{commonName: cn, typeURIs: lagging},
+			})
+		}
+	}
+
+	for _, typeURI := range envoy.XDSResponseOrder {
+		metricsstore.DefaultMetricsStore.ProxyDesyncCount.WithLabelValues(typeURI.Short()).Set(float64(desyncCount[typeURI]))
+	}
+
+	return reported
+}
+
+// getResyncTypeURIs returns the types of config to push again to the given proxy on the given ProxyResyncRequested
+// announcement, none if the announcement is for another proxy
+func getResyncTypeURIs(proxy *envoy.Proxy, msg interface{}) []envoy.TypeURI {
+	pubSubMessage, ok := msg.(events.PubSubMessage)
+	if !ok {
+		return nil
+	}
+	resync, ok := pubSubMessage.NewObj.(proxyResync)
+	if !ok || resync.commonName != proxy.GetCertificateCommonName() {
+		return nil
+	}
+	return resync.typeURIs
+}
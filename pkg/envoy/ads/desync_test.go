@@ -0,0 +1,143 @@
+package ads
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/registry"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+	"github.com/openservicemesh/osm/pkg/tests"
+)
+
+func TestCheckDesyncs(t *testing.T) {
+	assert := tassert.New(t)
+
+	resyncRequests := events.Subscribe(announcements.ProxyResyncRequested)
+	defer events.Unsub(resyncRequests)
+
+	certCommonName := envoy.NewXDSCertCommonName(uuid.New(), envoy.KindSidecar, tests.BookstoreServiceAccountName, tests.Namespace)
+	proxy, err := envoy.NewProxy(certCommonName, certificate.SerialNumber("123456"), nil)
+	assert.Nil(err)
+	proxyRegistry := registry.NewProxyRegistry(nil)
+	proxyRegistry.RegisterProxy(proxy)
+
+	threshold := time.Minute
+	s := &Server{
+		proxyRegistry: proxyRegistry,
+		desyncConfig:  DesyncMonitorConfig{Threshold: threshold, Repush: true},
+	}
+	desyncCount := metricsstore.DefaultMetricsStore.ProxyDesyncCount.WithLabelValues(envoy.TypeRDS.Short())
+	repushCount := metricsstore.DefaultMetricsStore.ProxyDesyncRepushCount.WithLabelValues(envoy.TypeRDS.Short())
+	repushes := testutil.ToFloat64(repushCount)
+
+	// CDS is acknowledged, RDS is not
+	proxy.IncrementLastSentVersion(envoy.TypeCDS)
+	proxy.SetLastAppliedVersion(envoy.TypeCDS, 1)
+	proxy.IncrementLastSentVersion(envoy.TypeRDS)
+	sentAt := time.Now()
+
+	testCases := []struct {
+		name                string
+		at                  time.Time
+		ackRDS              bool
+		expectedDesyncCount float64
+		expectedRepush      bool
+	}{
+		{
+			name:                "lagging within the threshold",
+			at:                  sentAt.Add(threshold / 2),
+			expectedDesyncCount: 0,
+		},
+		{
+			name:                "lagging beyond the threshold",
+			at:                  sentAt.Add(threshold + time.Second),
+			expectedDesyncCount: 1,
+			expectedRepush:      true,
+		},
+		{
+			name:                "still lagging, already reported",
+			at:                  sentAt.Add(threshold + 2*time.Second),
+			expectedDesyncCount: 1,
+		},
+		{
+			name:                "still lagging, reported again after the threshold",
+			at:                  sentAt.Add(2*threshold + 2*time.Second),
+			expectedDesyncCount: 1,
+			expectedRepush:      true,
+		},
+		{
+			name:                "acknowledged",
+			at:                  sentAt.Add(3 * threshold),
+			ackRDS:              true,
+			expectedDesyncCount: 0,
+		},
+	}
+
+	reported := make(map[string]time.Time)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			if tc.ackRDS {
+				proxy.SetLastAppliedVersion(envoy.TypeRDS, proxy.GetLastSentVersion(envoy.TypeRDS))
+			}
+
+			reported = s.checkDesyncs(tc.at, reported)
+			assert.Equal(tc.expectedDesyncCount, testutil.ToFloat64(desyncCount))
+			assert.Equal(0.0, testutil.ToFloat64(metricsstore.DefaultMetricsStore.ProxyDesyncCount.WithLabelValues(envoy.TypeCDS.Short())))
+
+			if !tc.expectedRepush {
+				assert.Equal(repushes, testutil.ToFloat64(repushCount))
+				return
+			}
+			repushes++
+			assert.Equal(repushes, testutil.ToFloat64(repushCount))
+			select {
+			case msg := <-resyncRequests:
+				assert.Equal([]envoy.TypeURI{envoy.TypeRDS}, getResyncTypeURIs(proxy, msg))
+			case <-time.After(5 * time.Second):
+				assert.Fail("Expected a resync request")
+			}
+		})
+	}
+}
+
+func TestGetResyncTypeURIs(t *testing.T) {
+	certCommonName := envoy.NewXDSCertCommonName(uuid.New(), envoy.KindSidecar, tests.BookstoreServiceAccountName, tests.Namespace)
+	proxy, err := envoy.NewProxy(certCommonName, certificate.SerialNumber("123456"), nil)
+	tassert.Nil(t, err)
+
+	testCases := []struct {
+		name     string
+		msg      interface{}
+		expected []envoy.TypeURI
+	}{
+		{
+			name:     "resync of the proxy",
+			msg:      events.PubSubMessage{NewObj: proxyResync{commonName: certCommonName, typeURIs: []envoy.TypeURI{envoy.TypeEDS}}},
+			expected: []envoy.TypeURI{envoy.TypeEDS},
+		},
+		{
+			name: "resync of another proxy",
+			msg:  events.PubSubMessage{NewObj: proxyResync{commonName: "other", typeURIs: []envoy.TypeURI{envoy.TypeEDS}}},
+		},
+		{
+			name: "unexpected message",
+			msg:  "unexpected",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tassert.Equal(t, tc.expected, getResyncTypeURIs(proxy, tc.msg))
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package ads
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+)
+
+// SetEventRecorder sets the recorder of the Kubernetes events reporting why the proxies of pods are rejected or can't
+// be configured, so that users can diagnose them with kubectl describe. It must be called before the server starts.
+func (s *Server) SetEventRecorder(recorder *events.ObjectEventRecorder) {
+	s.eventRecorder = recorder
+}
+
+// warnPodEvent records a warning event against the pod of the given proxy, if known
+func (s *Server) warnPodEvent(proxy *envoy.Proxy, reason string, messageFmt string, args ...interface{}) {
+	if s.eventRecorder == nil || proxy.PodMetadata == nil {
+		return
+	}
+
+	pod := &corev1.ObjectReference{
+		Kind:       "Pod",
+		APIVersion: "v1",
+		Name:       proxy.PodMetadata.Name,
+		Namespace:  proxy.PodMetadata.Namespace,
+		UID:        types.UID(proxy.PodMetadata.UID),
+	}
+	s.eventRecorder.WarnEvent(pod, reason, messageFmt, args...)
+}
@@ -0,0 +1,51 @@
+package ads
+
+import (
+	"testing"
+	"time"
+
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+)
+
+func TestWarnPodEvent(t *testing.T) {
+	assert := tassert.New(t)
+
+	kubeClient := testclient.NewSimpleClientset()
+	recorder, err := events.NewObjectEventRecorder(kubeClient)
+	assert.Nil(err)
+
+	proxy := &envoy.Proxy{}
+
+	// Events are not recorded without a recorder or against proxies whose pod is unknown
+	(&Server{}).warnPodEvent(proxy, events.ProxyServiceAccountMismatch, "mismatch")
+	s := &Server{}
+	s.SetEventRecorder(recorder)
+	s.warnPodEvent(proxy, events.ProxyServiceAccountMismatch, "mismatch")
+
+	proxy.PodMetadata = &envoy.PodMetadata{UID: "pod-uid", Name: "bookstore", Namespace: "test"}
+	s.warnPodEvent(proxy, events.ProxyServiceAccountMismatch, "mismatch of %s", "bookstore")
+
+	// The fake clientset rejects the events created in all namespaces, but records their creation
+	assert.Eventually(func() bool {
+		var recorded []*corev1.Event
+		for _, action := range kubeClient.Actions() {
+			if create, ok := action.(k8stesting.CreateAction); ok {
+				if event, ok := create.GetObject().(*corev1.Event); ok {
+					recorded = append(recorded, event)
+				}
+			}
+		}
+		if len(recorded) != 1 {
+			return false
+		}
+		event := recorded[0]
+		return event.Reason == events.ProxyServiceAccountMismatch && event.Message == "mismatch of bookstore" &&
+			event.InvolvedObject.Kind == "Pod" && event.InvolvedObject.Name == "bookstore" && event.InvolvedObject.UID == "pod-uid"
+	}, 5*time.Second, 50*time.Millisecond)
+}
@@ -19,6 +19,9 @@ type proxyResponseJob struct {
 
 	// Optional waiter
 	done chan struct{}
+
+	// err is the error sending the response, set once the job has been finished
+	err error
 }
 
 // GetDoneCh returns the channel, which when closed, indicates the job has been finished.
@@ -33,9 +36,23 @@ func (proxyJob *proxyResponseJob) Run() {
 		log.Error().Err(err).Msgf("Failed to create and send %v update to proxy %s",
 			proxyJob.typeURIs, proxyJob.proxy.String())
 	}
+	proxyJob.err = err
 	close(proxyJob.done)
 }
 
+// sentType returns whether the job, once finished, sent a response of the given type to the proxy
+func (proxyJob *proxyResponseJob) sentType(typeURI envoy.TypeURI) bool {
+	if proxyJob.err != nil {
+		return false
+	}
+	for _, t := range proxyJob.typeURIs {
+		if t == typeURI {
+			return true
+		}
+	}
+	return false
+}
+
 // JobName implementation for this job, for logging purposes
 func (proxyJob *proxyResponseJob) JobName() string {
 	return fmt.Sprintf("sendJob-%s", proxyJob.proxy.GetCertificateSerialNumber())
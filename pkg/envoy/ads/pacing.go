@@ -0,0 +1,62 @@
+package ads
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/client-go/util/flowcontrol"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+const (
+	// DefaultInitialSyncRate is the default number of initial config deliveries admitted per second
+	DefaultInitialSyncRate = 100
+
+	// DefaultInitialSyncBurst is the default number of initial config deliveries admitted at once
+	DefaultInitialSyncBurst = 200
+
+	// DefaultInitialSyncMaxJitter is the default maximum random delay of the initial config delivery of a proxy
+	DefaultInitialSyncMaxJitter = 2 * time.Second
+)
+
+// InitialSyncPacingConfig is the configuration pacing the initial config delivery to the proxies connecting to the
+// xDS server, so that the proxies reconnecting at once on a controller restart are not all synced at the same time.
+type InitialSyncPacingConfig struct {
+	// Rate is the number of initial config deliveries admitted per second, pacing is disabled if 0
+	Rate float64
+
+	// Burst is the number of initial config deliveries admitted at once
+	Burst int
+
+	// MaxJitter is the maximum random delay before an admitted initial config delivery is scheduled
+	MaxJitter time.Duration
+}
+
+// newInitialSyncLimiter returns the token bucket admitting the initial config deliveries, nil if pacing is disabled
+func newInitialSyncLimiter(config InitialSyncPacingConfig) flowcontrol.RateLimiter {
+	if config.Rate <= 0 {
+		return nil
+	}
+
+	burst := config.Burst
+	if burst < 1 {
+		burst = 1
+	}
+	return flowcontrol.NewTokenBucketRateLimiter(float32(config.Rate), burst)
+}
+
+// waitForInitialSync blocks until the initial config delivery to the given proxy is admitted, or the given context
+// is done in which case an error is returned
+func (s *Server) waitForInitialSync(ctx context.Context, proxy *envoy.Proxy) error {
+	if s.initialSyncLimiter == nil {
+		return nil
+	}
+
+	start := time.Now()
+	if err := s.initialSyncLimiter.Wait(ctx); err != nil {
+		return err
+	}
+	log.Debug().Msgf("Initial config delivery to proxy %s admitted after %+v", proxy.String(), time.Since(start))
+	return nil
+}
@@ -0,0 +1,53 @@
+package ads
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+func TestNewInitialSyncLimiter(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.Nil(newInitialSyncLimiter(InitialSyncPacingConfig{}))
+	assert.Nil(newInitialSyncLimiter(InitialSyncPacingConfig{Rate: -1, Burst: 10}))
+
+	limiter := newInitialSyncLimiter(InitialSyncPacingConfig{Rate: 1, Burst: 2})
+	assert.NotNil(limiter)
+	assert.True(limiter.TryAccept())
+	assert.True(limiter.TryAccept())
+	assert.False(limiter.TryAccept())
+
+	// The burst is at least 1
+	limiter = newInitialSyncLimiter(InitialSyncPacingConfig{Rate: 1})
+	assert.True(limiter.TryAccept())
+	assert.False(limiter.TryAccept())
+}
+
+func TestWaitForInitialSync(t *testing.T) {
+	assert := tassert.New(t)
+
+	proxy, err := envoy.NewProxy(certificate.CommonName(fmt.Sprintf("%s.sidecar.sa.ns.cluster.local", uuid.New())), "123", nil)
+	assert.Nil(err)
+
+	// Not paced
+	s := &Server{}
+	assert.Nil(s.waitForInitialSync(context.Background(), proxy))
+
+	// Paced at 1 initial sync per second, the second one is not admitted before the context is done
+	s = &Server{
+		initialSyncLimiter: newInitialSyncLimiter(InitialSyncPacingConfig{Rate: 1, Burst: 1}),
+	}
+	assert.Nil(s.waitForInitialSync(context.Background(), proxy))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.NotNil(s.waitForInitialSync(ctx, proxy))
+}
@@ -0,0 +1,172 @@
+package ads
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/snapshotstore"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+)
+
+const (
+	// DefaultSnapshotWarmupPeriod is the default period after the server starts during which proxies are served the
+	// persisted snapshots instead of freshly generated config
+	DefaultSnapshotWarmupPeriod = 30 * time.Second
+
+	// snapshotPersistInterval is the interval at which the snapshots generated since the last interval are persisted
+	snapshotPersistInterval = 10 * time.Second
+)
+
+// EnableSnapshotPersistence enables persisting the last xDS resources generated for each kind of proxy and service
+// identity in the given store. For the given warm-up period after the server starts, proxies are served the persisted
+// resources when available, after which a proxy broadcast updates them with freshly generated config.
+// It must be called before the server starts.
+func (s *Server) EnableSnapshotPersistence(store snapshotstore.Store, warmupPeriod time.Duration) {
+	s.snapshotStore = store
+	s.snapshotWarmupPeriod = warmupPeriod
+	s.snapshotWarmupDone = make(chan struct{})
+	s.generatedSnapshots = make(map[string]snapshotstore.Snapshot)
+	s.dirtySnapshots = make(map[string]struct{})
+	s.persistedSnapshots = make(map[string]snapshotstore.Snapshot)
+}
+
+// startSnapshotPersistence starts the warm-up period and the periodic persistence of the generated snapshots until
+// the given context is done
+func (s *Server) startSnapshotPersistence(ctx context.Context) {
+	if s.snapshotStore == nil {
+		return
+	}
+
+	time.AfterFunc(s.snapshotWarmupPeriod, func() {
+		close(s.snapshotWarmupDone)
+		log.Info().Msgf("xDS snapshot warm-up period of %s elapsed, updating proxies with generated config", s.snapshotWarmupPeriod)
+		events.Publish(events.PubSubMessage{
+			AnnouncementType: announcements.ScheduleProxyBroadcast,
+		})
+	})
+
+	go func() {
+		ticker := time.NewTicker(snapshotPersistInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.persistSnapshots()
+			case <-ctx.Done():
+				s.persistSnapshots()
+				return
+			}
+		}
+	}()
+}
+
+// isSnapshotWarmup returns whether the proxies are served the persisted snapshots
+func (s *Server) isSnapshotWarmup() bool {
+	if s.snapshotStore == nil {
+		return false
+	}
+	select {
+	case <-s.snapshotWarmupDone:
+		return false
+	default:
+		return true
+	}
+}
+
+// getSnapshotKey returns the key of the snapshots of the given proxy: its kind and service identity
+func getSnapshotKey(proxy *envoy.Proxy) (string, error) {
+	proxyIdentity, err := envoy.GetServiceIdentityFromProxyCertificate(proxy.GetCertificateCommonName())
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/%s", proxy.Kind(), proxyIdentity), nil
+}
+
+// getPersistedResources returns the persisted resources of the given type for the given proxy during the warm-up
+// period, and whether there are any. SDS resources are never persisted.
+func (s *Server) getPersistedResources(proxy *envoy.Proxy, typeURI envoy.TypeURI) ([]types.Resource, bool) {
+	if typeURI == envoy.TypeSDS || !s.isSnapshotWarmup() {
+		return nil, false
+	}
+
+	key, err := getSnapshotKey(proxy)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error getting xDS snapshot key of proxy %s", proxy.String())
+		return nil, false
+	}
+
+	s.snapshotMutex.Lock()
+	defer s.snapshotMutex.Unlock()
+
+	snapshot, loaded := s.persistedSnapshots[key]
+	if !loaded {
+		snapshot, err = s.snapshotStore.Load(key)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error loading xDS snapshot %s for proxy %s", key, proxy.String())
+			return nil, false
+		}
+		// Also record missing snapshots so the store is only queried once per key
+		s.persistedSnapshots[key] = snapshot
+	}
+
+	resources, ok := snapshot[typeURI]
+	return resources, ok
+}
+
+// recordGeneratedResources records the given resources of the given type generated for the given proxy, to be
+// persisted on the next persistence interval. SDS resources are never recorded.
+func (s *Server) recordGeneratedResources(proxy *envoy.Proxy, typeURI envoy.TypeURI, resources []types.Resource) {
+	if s.snapshotStore == nil || typeURI == envoy.TypeSDS {
+		return
+	}
+
+	key, err := getSnapshotKey(proxy)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error getting xDS snapshot key of proxy %s", proxy.String())
+		return
+	}
+
+	s.snapshotMutex.Lock()
+	defer s.snapshotMutex.Unlock()
+
+	snapshot, ok := s.generatedSnapshots[key]
+	if !ok {
+		snapshot = make(snapshotstore.Snapshot)
+		s.generatedSnapshots[key] = snapshot
+	}
+	snapshot[typeURI] = resources
+	s.dirtySnapshots[key] = struct{}{}
+}
+
+// persistSnapshots persists the snapshots generated since they were last persisted
+func (s *Server) persistSnapshots() {
+	s.snapshotMutex.Lock()
+	snapshots := make(map[string]snapshotstore.Snapshot, len(s.dirtySnapshots))
+	for key := range s.dirtySnapshots {
+		snapshot := make(snapshotstore.Snapshot)
+		for typeURI, resources := range s.generatedSnapshots[key] {
+			snapshot[typeURI] = resources
+		}
+		snapshots[key] = snapshot
+	}
+	s.dirtySnapshots = make(map[string]struct{})
+	s.snapshotMutex.Unlock()
+
+	for key, snapshot := range snapshots {
+		if err := s.snapshotStore.Save(key, snapshot); err != nil {
+			log.Error().Err(err).Msgf("Error persisting xDS snapshot %s", key)
+
+			// Retry on the next interval, unless a newer snapshot was generated in the meantime
+			s.snapshotMutex.Lock()
+			s.dirtySnapshots[key] = struct{}{}
+			s.snapshotMutex.Unlock()
+			continue
+		}
+		log.Trace().Msgf("Persisted xDS snapshot %s", key)
+	}
+}
@@ -0,0 +1,111 @@
+package ads
+
+import (
+	"fmt"
+	"testing"
+
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/snapshotstore"
+)
+
+// fakeSnapshotStore is an in-memory snapshotstore.Store
+type fakeSnapshotStore struct {
+	snapshots map[string]snapshotstore.Snapshot
+	saveErr   error
+}
+
+func (f *fakeSnapshotStore) Save(key string, snapshot snapshotstore.Snapshot) error {
+	if f.saveErr != nil {
+		return f.saveErr
+	}
+	f.snapshots[key] = snapshot
+	return nil
+}
+
+func (f *fakeSnapshotStore) Load(key string) (snapshotstore.Snapshot, error) {
+	return f.snapshots[key], nil
+}
+
+func TestGetPersistedResources(t *testing.T) {
+	assert := tassert.New(t)
+
+	proxy, err := envoy.NewProxy(certificate.CommonName(fmt.Sprintf("%s.sidecar.sa.ns.cluster.local", uuid.New())), "123", nil)
+	assert.Nil(err)
+	key, err := getSnapshotKey(proxy)
+	assert.Nil(err)
+	assert.Equal("sidecar/sa.ns.cluster.local", key)
+
+	clusters := []types.Resource{&xds_cluster.Cluster{Name: "bookstore"}}
+	store := &fakeSnapshotStore{
+		snapshots: map[string]snapshotstore.Snapshot{
+			key: {
+				envoy.TypeCDS: clusters,
+				envoy.TypeSDS: []types.Resource{&xds_auth.Secret{Name: "service-cert:ns/sa"}},
+			},
+		},
+	}
+
+	// Persistence disabled
+	s := &Server{}
+	_, ok := s.getPersistedResources(proxy, envoy.TypeCDS)
+	assert.False(ok)
+
+	s.EnableSnapshotPersistence(store, DefaultSnapshotWarmupPeriod)
+
+	// Served during the warm-up period
+	resources, ok := s.getPersistedResources(proxy, envoy.TypeCDS)
+	assert.True(ok)
+	assert.Equal(clusters, resources)
+
+	// Types without persisted resources and SDS are not served
+	_, ok = s.getPersistedResources(proxy, envoy.TypeLDS)
+	assert.False(ok)
+	_, ok = s.getPersistedResources(proxy, envoy.TypeSDS)
+	assert.False(ok)
+
+	// Not served after the warm-up period
+	close(s.snapshotWarmupDone)
+	_, ok = s.getPersistedResources(proxy, envoy.TypeCDS)
+	assert.False(ok)
+}
+
+func TestPersistSnapshots(t *testing.T) {
+	assert := tassert.New(t)
+
+	proxy, err := envoy.NewProxy(certificate.CommonName(fmt.Sprintf("%s.sidecar.sa.ns.cluster.local", uuid.New())), "123", nil)
+	assert.Nil(err)
+
+	store := &fakeSnapshotStore{
+		snapshots: make(map[string]snapshotstore.Snapshot),
+		saveErr:   errors.New("unavailable"),
+	}
+	s := &Server{}
+	s.EnableSnapshotPersistence(store, DefaultSnapshotWarmupPeriod)
+
+	clusters := []types.Resource{&xds_cluster.Cluster{Name: "bookstore"}}
+	s.recordGeneratedResources(proxy, envoy.TypeCDS, clusters)
+	s.recordGeneratedResources(proxy, envoy.TypeSDS, []types.Resource{&xds_auth.Secret{Name: "service-cert:ns/sa"}})
+
+	// Failed saves are retried on the next persistence
+	s.persistSnapshots()
+	assert.Empty(store.snapshots)
+	assert.Len(s.dirtySnapshots, 1)
+
+	store.saveErr = nil
+	s.persistSnapshots()
+	assert.Empty(s.dirtySnapshots)
+	assert.Equal(snapshotstore.Snapshot{envoy.TypeCDS: clusters}, store.snapshots["sidecar/sa.ns.cluster.local"])
+
+	// Nothing is saved again until new resources are generated
+	delete(store.snapshots, "sidecar/sa.ns.cluster.local")
+	s.persistSnapshots()
+	assert.Empty(store.snapshots)
+}
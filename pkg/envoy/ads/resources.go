@@ -0,0 +1,40 @@
+package ads
+
+import (
+	"hash/fnv"
+	"sort"
+
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	protov1 "github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// deterministicMarshaling marshals the map fields of the resources with their keys sorted, so that identical resources
+// are always marshaled to the same bytes
+var deterministicMarshaling = proto.MarshalOptions{Deterministic: true}
+
+// sortResources sorts the given resources by name, so that the resources generated from the same config are always
+// sent in the same order regardless of the order of the maps and sets they were built from
+func sortResources(resources []types.Resource) {
+	sort.SliceStable(resources, func(i, j int) bool {
+		return cache.GetResourceName(resources[i]) < cache.GetResourceName(resources[j])
+	})
+}
+
+// hashResources returns a hash of the content of the given resources, which is the same for identical resources
+// listed in the same order
+func hashResources(resources []types.Resource) (uint64, error) {
+	h := fnv.New64a()
+	for _, res := range resources {
+		b, err := deterministicMarshaling.Marshal(protov1.MessageV2(res))
+		if err != nil {
+			return 0, err
+		}
+		// Prefix each resource with its size so that the boundaries between resources are part of the hash
+		size := uint64(len(b))
+		_, _ = h.Write([]byte{byte(size), byte(size >> 8), byte(size >> 16), byte(size >> 24)})
+		_, _ = h.Write(b)
+	}
+	return h.Sum64(), nil
+}
@@ -0,0 +1,178 @@
+package ads
+
+import (
+	"testing"
+
+	mapset "github.com/deckarep/golang-set"
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/tests"
+)
+
+func TestSortResources(t *testing.T) {
+	assert := tassert.New(t)
+
+	resources := []types.Resource{
+		&xds_cluster.Cluster{Name: "ns/svc-b"},
+		&xds_cluster.Cluster{Name: "ns/svc-c"},
+		&xds_cluster.Cluster{Name: "ns/svc-a"},
+	}
+	sortResources(resources)
+
+	assert.Equal([]types.Resource{
+		&xds_cluster.Cluster{Name: "ns/svc-a"},
+		&xds_cluster.Cluster{Name: "ns/svc-b"},
+		&xds_cluster.Cluster{Name: "ns/svc-c"},
+	}, resources)
+}
+
+func TestHashResources(t *testing.T) {
+	assert := tassert.New(t)
+
+	newCluster := func(filterMetadataKeys ...string) *xds_cluster.Cluster {
+		cluster := &xds_cluster.Cluster{
+			Name:     "ns/svc",
+			Metadata: &xds_core.Metadata{FilterMetadata: map[string]*structpb.Struct{}},
+		}
+		for _, key := range filterMetadataKeys {
+			cluster.Metadata.FilterMetadata[key] = &structpb.Struct{}
+		}
+		return cluster
+	}
+
+	hash, err := hashResources([]types.Resource{newCluster("a", "b", "c", "d"), &xds_cluster.Cluster{Name: "ns/other"}})
+	assert.Nil(err)
+
+	// Identical resources have the same hash regardless of the iteration order of their maps
+	for i := 0; i < 10; i++ {
+		sameHash, err := hashResources([]types.Resource{newCluster("d", "c", "b", "a"), &xds_cluster.Cluster{Name: "ns/other"}})
+		assert.Nil(err)
+		assert.Equal(hash, sameHash)
+	}
+
+	// Different resources have a different hash
+	otherHash, err := hashResources([]types.Resource{newCluster("a", "b", "c"), &xds_cluster.Cluster{Name: "ns/other"}})
+	assert.Nil(err)
+	assert.NotEqual(hash, otherHash)
+
+	// The boundaries between resources are part of the hash
+	split, err := hashResources([]types.Resource{&xds_cluster.Cluster{Name: "ab"}, &xds_cluster.Cluster{Name: "c"}})
+	assert.Nil(err)
+	merged, err := hashResources([]types.Resource{&xds_cluster.Cluster{Name: "a"}, &xds_cluster.Cluster{Name: "bc"}})
+	assert.Nil(err)
+	assert.NotEqual(split, merged)
+}
+
+func TestIsUnchanged(t *testing.T) {
+	assert := tassert.New(t)
+
+	proxy, err := envoy.NewProxy(certificate.CommonName(envoy.NewXDSCertCommonName(uuid.New(), envoy.KindSidecar, "sa", "ns")), "", nil)
+	assert.Nil(err)
+
+	hash, err := hashResources([]types.Resource{&xds_cluster.Cluster{Name: "ns/svc"}})
+	assert.Nil(err)
+	otherHash, err := hashResources([]types.Resource{&xds_cluster.Cluster{Name: "ns/other"}})
+	assert.Nil(err)
+
+	// Nothing was sent yet
+	assert.False(isUnchanged(proxy, envoy.TypeCDS, hash))
+
+	proxy.SetLastSentResourcesHash(envoy.TypeCDS, hash)
+
+	assert.True(isUnchanged(proxy, envoy.TypeCDS, hash))
+	assert.False(isUnchanged(proxy, envoy.TypeCDS, otherHash))
+	assert.False(isUnchanged(proxy, envoy.TypeEDS, hash))
+
+	proxy.DeleteLastSentResourcesHash(envoy.TypeCDS)
+	assert.False(isUnchanged(proxy, envoy.TypeCDS, hash))
+}
+
+func TestSendDiscoveryResponseUnchanged(t *testing.T) {
+	assert := tassert.New(t)
+
+	s := &Server{}
+	proxy, err := envoy.NewProxy(certificate.CommonName(envoy.NewXDSCertCommonName(uuid.New(), envoy.KindSidecar, "sa", "ns")), "", nil)
+	assert.Nil(err)
+	server, responses := tests.NewFakeXDSServer(nil, nil, nil)
+
+	// The proxy only subscribed to one of the route configurations generated for it
+	proxy.SetSubscribedResources(envoy.TypeRDS, mapset.NewSet("rds-inbound"))
+	request := &xds_discovery.DiscoveryRequest{TypeUrl: envoy.TypeRDS.String(), ResourceNames: []string{"rds-inbound"}}
+	newResources := func(outboundVirtualHost string) []types.Resource {
+		return []types.Resource{
+			&xds_route.RouteConfiguration{Name: "rds-inbound"},
+			&xds_route.RouteConfiguration{Name: "rds-outbound", VirtualHosts: []*xds_route.VirtualHost{{Name: outboundVirtualHost}}},
+		}
+	}
+
+	assert.Nil(s.SendDiscoveryResponse(proxy, request, &server, newResources("bookstore"), false))
+	assert.Len(*responses, 1)
+	assert.Len((*responses)[0].Resources, 2)
+	assert.True(proxy.GetLastResourcesSent(envoy.TypeRDS).Equal(mapset.NewSet("rds-inbound")))
+
+	// The same resources are not sent again on a control plane driven update
+	assert.Nil(s.SendDiscoveryResponse(proxy, request, &server, newResources("bookstore"), true))
+	assert.Len(*responses, 1)
+	assert.Equal(uint64(1), proxy.GetLastSentVersion(envoy.TypeRDS))
+
+	// The resources of the response are compared, including those the proxy didn't request
+	assert.Nil(s.SendDiscoveryResponse(proxy, request, &server, newResources("bookbuyer"), true))
+	assert.Len(*responses, 2)
+	assert.Equal(uint64(2), proxy.GetLastSentVersion(envoy.TypeRDS))
+
+	// Only the requested resources being generated is a change as well
+	assert.Nil(s.SendDiscoveryResponse(proxy, request, &server, []types.Resource{&xds_route.RouteConfiguration{Name: "rds-inbound"}}, true))
+	assert.Len(*responses, 3)
+	assert.Len((*responses)[2].Resources, 1)
+}
+
+func TestRecordFullSnapshotUnchanged(t *testing.T) {
+	assert := tassert.New(t)
+
+	s := Server{
+		ch:            cachev3.NewSnapshotCache(false, cachev3.IDHash{}, nil),
+		configVersion: make(map[string]uint64),
+		configHash:    make(map[string]uint64),
+	}
+	proxy, err := envoy.NewProxy(certificate.CommonName(envoy.NewXDSCertCommonName(uuid.New(), envoy.KindSidecar, "sa", "ns")), "", nil)
+	assert.Nil(err)
+	cn := proxy.GetCertificateCommonName().String()
+
+	newResources := func(clusterName string) map[envoy.TypeURI][]types.Resource {
+		return map[envoy.TypeURI][]types.Resource{
+			envoy.TypeCDS: {&xds_cluster.Cluster{Name: clusterName}},
+		}
+	}
+
+	assert.Nil(s.RecordFullSnapshot(proxy, newResources("ns/svc")))
+	assert.Equal(uint64(1), s.configVersion[cn])
+
+	// An identical snapshot doesn't bump the version
+	assert.Nil(s.RecordFullSnapshot(proxy, newResources("ns/svc")))
+	assert.Equal(uint64(1), s.configVersion[cn])
+	snapshot, err := s.ch.GetSnapshot(cn)
+	assert.Nil(err)
+	assert.Equal("1", snapshot.GetVersion(string(envoy.TypeCDS)))
+
+	// Resources of types the snapshot doesn't hold don't change it
+	resources := newResources("ns/svc")
+	resources[envoy.TypeSRDS] = []types.Resource{&xds_route.ScopedRouteConfiguration{Name: "scope"}}
+	assert.Nil(s.RecordFullSnapshot(proxy, resources))
+	assert.Equal(uint64(1), s.configVersion[cn])
+
+	assert.Nil(s.RecordFullSnapshot(proxy, newResources("ns/other")))
+	assert.Equal(uint64(2), s.configVersion[cn])
+	snapshot, err = s.ch.GetSnapshot(cn)
+	assert.Nil(err)
+	assert.Equal("2", snapshot.GetVersion(string(envoy.TypeCDS)))
+}
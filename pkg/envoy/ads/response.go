@@ -1,6 +1,7 @@
 package ads
 
 import (
+	"errors"
 	"strconv"
 	"time"
 
@@ -9,10 +10,14 @@ import (
 	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
 	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
 	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
 
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/sds"
 	"github.com/openservicemesh/osm/pkg/errcode"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
 )
 
 // getTypeResource invokes the XDS handler (LDS, CDS etc.) to respond to the XDS request containing the requests' type and associated resources
@@ -31,13 +36,27 @@ func (s *Server) getTypeResources(proxy *envoy.Proxy, request *xds_discovery.Dis
 		s.trackXDSLog(proxy.GetCertificateCommonName(), typeURI)
 	}
 
+	// Serve the last known good resources while the control plane warms up after a restart
+	if resources, ok := s.getPersistedResources(proxy, typeURI); ok {
+		log.Debug().Msgf("Proxy %s: serving persisted %s resources during warm-up", proxy.String(), typeURI.Short())
+		return resources, nil
+	}
+
 	// Invoke XDS handler
 	resources, err := handler(s.catalog, proxy, request, s.cfg, s.certManager, s.proxyRegistry)
 	if err != nil {
 		xdsPathTimeTrack(startedAt, log.Debug(), typeURI, proxy, false)
+		if errors.Is(err, sds.ErrIssuingCertificate) {
+			s.warnPodEvent(proxy, events.ProxyCertificateIssuanceFailed, "Proxy %s can't be configured: %s", proxy.String(), err)
+		}
 		return nil, errCreatingResponse
 	}
 
+	// Envoy treats any difference in the resources as a config change, so the resources are always sent in the same order
+	sortResources(resources)
+
+	s.recordGeneratedResources(proxy, typeURI, resources)
+
 	xdsPathTimeTrack(startedAt, log.Debug(), typeURI, proxy, true)
 	return resources, nil
 }
@@ -57,6 +76,11 @@ func (s *Server) sendResponse(proxy *envoy.Proxy, server *xds_discovery.Aggregat
 	// Order is important: CDS, EDS, LDS, RDS
 	// See: https://github.com/envoyproxy/go-control-plane/issues/59
 	for _, typeURI := range typeURIsToSend {
+		// Scoped route configurations are only pushed to the proxies which requested them, those using scoped routes
+		if osmDrivenUpdate && !s.cacheEnabled && typeURI == envoy.TypeSRDS && proxy.GetLastSentNonce(envoy.TypeSRDS) == "" {
+			continue
+		}
+
 		// Handle request when is not provided, and the SDS case
 		var finalReq *xds_discovery.DiscoveryRequest
 		if osmDrivenUpdate {
@@ -77,7 +101,7 @@ func (s *Server) sendResponse(proxy *envoy.Proxy, server *xds_discovery.Aggregat
 		// Generate the resources for this request
 		resources, err := s.getTypeResources(proxy, finalReq)
 		if err != nil {
-			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGeneratingReqResource)).
+			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetricForProxy(errcode.ErrGeneratingReqResource, proxy.GetCertificateCommonName())).
 				Msgf("Error generating response for typeURI: %s, proxy %s", typeURI.Short(), proxy.String())
 			thereWereErrors = true
 			continue
@@ -87,8 +111,9 @@ func (s *Server) sendResponse(proxy *envoy.Proxy, server *xds_discovery.Aggregat
 			// Keep a reference to later set the full snapshot in the cache
 			cacheResourceMap[typeURI] = resources
 		} else {
-			// If cache disabled, craft and send a reply to the proxy on the stream
-			if err := s.SendDiscoveryResponse(proxy, finalReq, server, resources); err != nil {
+			// If cache disabled, craft and send a reply to the proxy on the stream. Sending the same resources again on
+			// a control plane driven update would only bump the version of the proxy's config.
+			if err := s.SendDiscoveryResponse(proxy, finalReq, server, resources, osmDrivenUpdate); err != nil {
 				log.Error().Err(err).Msgf("Creating %s update for Proxy %s", typeURI.Short(), proxy.GetCertificateCommonName())
 				thereWereErrors = true
 			}
@@ -98,7 +123,7 @@ func (s *Server) sendResponse(proxy *envoy.Proxy, server *xds_discovery.Aggregat
 	if s.cacheEnabled {
 		// Store the aggregated resources as a full snapshot
 		if err := s.RecordFullSnapshot(proxy, cacheResourceMap); err != nil {
-			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrRecordingSnapshot)).
+			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetricForProxy(errcode.ErrRecordingSnapshot, proxy.GetCertificateCommonName())).
 				Msgf("Failed to record snapshot for proxy %s: %v", proxy.GetCertificateCommonName(), err)
 			thereWereErrors = true
 		}
@@ -113,29 +138,36 @@ func (s *Server) sendResponse(proxy *envoy.Proxy, server *xds_discovery.Aggregat
 	return nil
 }
 
-// SendDiscoveryResponse creates a new response for <proxy> given <resourcesToSend> and <request.TypeURI> and sends it
-func (s *Server) SendDiscoveryResponse(proxy *envoy.Proxy, request *xds_discovery.DiscoveryRequest, server *xds_discovery.AggregatedDiscoveryService_StreamAggregatedResourcesServer, resourcesToSend []types.Resource) error {
+// SendDiscoveryResponse creates a new response for <proxy> given <resourcesToSend> and <request.TypeURI> and sends it.
+// If skipUnchanged is set, the response is not sent if its resources are identical to those of the last response of
+// the same type sent to the proxy.
+func (s *Server) SendDiscoveryResponse(proxy *envoy.Proxy, request *xds_discovery.DiscoveryRequest, server *xds_discovery.AggregatedDiscoveryService_StreamAggregatedResourcesServer, resourcesToSend []types.Resource, skipUnchanged bool) error {
 	// request.Node is only available on the first Discovery Request; will be nil on the following
 	typeURI := envoy.TypeURI(request.TypeUrl)
 
+	// The resources failing to be marshaled are left out of the response, the hash is computed over the exact
+	// resources of the response
+	protos, sentResources := marshalResources(proxy, typeURI, resourcesToSend)
+	hash, hashErr := hashResources(sentResources)
+	if hashErr != nil {
+		log.Error().Err(hashErr).Msgf("Error hashing %s resources for proxy %s", typeURI.Short(), proxy.String())
+	}
+	if skipUnchanged && hashErr == nil && isUnchanged(proxy, typeURI, hash) {
+		log.Debug().Msgf("Proxy %s: %s resources unchanged, skipping update", proxy.String(), typeURI.Short())
+		metricsstore.DefaultMetricsStore.ProxyUnchangedConfigCount.WithLabelValues(typeURI.Short()).Inc()
+		return nil
+	}
+
 	response := &xds_discovery.DiscoveryResponse{
 		TypeUrl:     request.TypeUrl,
 		VersionInfo: strconv.FormatUint(proxy.IncrementLastSentVersion(typeURI), 10),
 		Nonce:       proxy.SetNewNonce(typeURI),
+		Resources:   protos,
 	}
 
 	resourcesSent := mapset.NewSet()
 	subscribedResources := proxy.GetSubscribedResources(typeURI)
-	for _, res := range resourcesToSend {
-		proto, err := ptypes.MarshalAny(res)
-		if err != nil {
-			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingXDSResource)).
-				Msgf("Error marshalling resource %s for proxy %s", typeURI, proxy.GetCertificateSerialNumber())
-			continue
-		}
-		// Add resource to response
-		response.Resources = append(response.Resources, proto)
-
+	for _, res := range sentResources {
 		// Only track as resources sent if they are subscribed resources.
 		// By doing so, we are making sure a legitimate request down the line is not treated as an ACK just because
 		// a vertical had potentially sent more resources when they had not been requested yet by the proxy.
@@ -155,11 +187,11 @@ func (s *Server) SendDiscoveryResponse(proxy *envoy.Proxy, request *xds_discover
 	log.Trace().Msgf("Constructed %s response: VersionInfo=%s", response.TypeUrl, response.VersionInfo)
 
 	// Validate the generated resources given the request
-	validateRequestResponse(proxy, request, resourcesToSend)
+	validateRequestResponse(proxy, request, sentResources)
 
 	// Send the response
 	if err := (*server).Send(response); err != nil {
-		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrSendingDiscoveryResponse)).
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetricForProxy(errcode.ErrSendingDiscoveryResponse, proxy.GetCertificateCommonName())).
 			Msgf("Error sending response for type %s to proxy %s", typeURI.Short(), proxy.String())
 		return err
 	}
@@ -167,6 +199,37 @@ func (s *Server) SendDiscoveryResponse(proxy *envoy.Proxy, request *xds_discover
 	// Sending discovery response succeeded, record last resources sent
 	// TODO: increase version and nonce only if Send succeeded
 	proxy.SetLastResourcesSent(typeURI, resourcesSent)
+	if hashErr == nil {
+		proxy.SetLastSentResourcesHash(typeURI, hash)
+	} else {
+		proxy.DeleteLastSentResourcesHash(typeURI)
+	}
 
 	return nil
 }
+
+// marshalResources marshals the given resources of the given type for a discovery response to the proxy, and returns
+// the marshaled resources along with the resources they were marshaled from. The resources failing to be marshaled
+// are left out.
+func marshalResources(proxy *envoy.Proxy, typeURI envoy.TypeURI, resources []types.Resource) ([]*any.Any, []types.Resource) {
+	var protos []*any.Any
+	var marshaled []types.Resource
+	for _, res := range resources {
+		proto, err := ptypes.MarshalAny(res)
+		if err != nil {
+			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetricForProxy(errcode.ErrMarshallingXDSResource, proxy.GetCertificateCommonName())).
+				Msgf("Error marshalling resource %s for proxy %s", typeURI, proxy.GetCertificateSerialNumber())
+			continue
+		}
+		protos = append(protos, proto)
+		marshaled = append(marshaled, res)
+	}
+	return protos, marshaled
+}
+
+// isUnchanged returns whether the given hash of resources is the hash of the resources of the given type last sent to
+// the proxy
+func isUnchanged(proxy *envoy.Proxy, typeURI envoy.TypeURI, hash uint64) bool {
+	lastHash, ok := proxy.GetLastSentResourcesHash(typeURI)
+	return ok && hash == lastHash
+}
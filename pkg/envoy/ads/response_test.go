@@ -138,13 +138,18 @@ var _ = Describe("Test ADS response functions", func() {
 		mockConfigurator.EXPECT().GetServiceCertValidityPeriod().Return(certDuration).AnyTimes()
 		mockConfigurator.EXPECT().GetCertKeyBitSize().Return(2048).AnyTimes()
 		mockConfigurator.EXPECT().IsDebugServerEnabled().Return(true).AnyTimes()
+		mockConfigurator.EXPECT().GetPerformanceSettings().Return(configurator.PerformanceSettings{}).AnyTimes()
+		mockConfigurator.EXPECT().GetFederatedTrustDomains().Return(nil).AnyTimes()
+		mockConfigurator.EXPECT().GetSPIFFETrustDomain().Return("").AnyTimes()
 		mockConfigurator.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{
 			EnableWASMStats:    false,
 			EnableEgressPolicy: false,
 		}).AnyTimes()
+		mockConfigurator.EXPECT().GetRouteRegexConfig().Return(v1alpha1.RouteRegexSpec{}).AnyTimes()
+		mockConfigurator.EXPECT().GetPrometheusScrapingConfig().Return(v1alpha1.PrometheusScrapingSpec{}).AnyTimes()
 
 		It("returns Aggregated Discovery Service response", func() {
-			s := NewADSServer(mc, proxyRegistry, true, tests.Namespace, mockConfigurator, mockCertManager, kubectrlMock)
+			s := NewADSServer(mc, proxyRegistry, true, tests.Namespace, mockConfigurator, mockCertManager, kubectrlMock, InitialSyncPacingConfig{})
 
 			Expect(s).ToNot(BeNil())
 
@@ -156,6 +161,7 @@ var _ = Describe("Test ADS response functions", func() {
 			err := s.sendResponse(proxy, &server, nil, mockConfigurator, envoy.XDSResponseOrder...)
 			Expect(err).To(BeNil())
 			Expect(actualResponses).ToNot(BeNil())
+			// SRDS is not pushed to the proxy, which never requested it
 			Expect(len(*actualResponses)).To(Equal(5))
 
 			Expect((*actualResponses)[0].VersionInfo).To(Equal("1"))
@@ -216,12 +222,13 @@ var _ = Describe("Test ADS response functions", func() {
 		mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
 		mockConfigurator.EXPECT().GetServiceCertValidityPeriod().Return(certDuration).AnyTimes()
 		mockConfigurator.EXPECT().IsDebugServerEnabled().Return(true).AnyTimes()
+		mockConfigurator.EXPECT().GetPerformanceSettings().Return(configurator.PerformanceSettings{}).AnyTimes()
 		mockConfigurator.EXPECT().GetInboundExternalAuthConfig().Return(auth.ExtAuthConfig{
 			Enable: false,
 		}).AnyTimes()
 
 		It("returns Aggregated Discovery Service response", func() {
-			s := NewADSServer(mc, proxyRegistry, true, tests.Namespace, mockConfigurator, mockCertManager, kubectrlMock)
+			s := NewADSServer(mc, proxyRegistry, true, tests.Namespace, mockConfigurator, mockCertManager, kubectrlMock, InitialSyncPacingConfig{})
 
 			Expect(s).ToNot(BeNil())
 
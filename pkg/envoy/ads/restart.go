@@ -0,0 +1,38 @@
+package ads
+
+import (
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+)
+
+// isProxyRestart returns whether the given first discovery request on the stream of a proxy comes from a restarted
+// Envoy. An Envoy reconnecting to the control plane keeps the config it applied and acknowledges its version in its
+// first requests, while a restarted Envoy starts over with the same certificate but without any config.
+func isProxyRestart(knownProxy bool, discoveryRequest *xds_discovery.DiscoveryRequest) bool {
+	return knownProxy && discoveryRequest.VersionInfo == "" && discoveryRequest.ResponseNonce == ""
+}
+
+// handleProxyRestart handles the restart of the Envoy of the given proxy as a hard reset: the xDS state kept by the
+// server for the proxy's certificate is cleared, and the restart is counted for the proxy's pod.
+// The state kept by the proxy itself is reset with the new stream.
+func (s *Server) handleProxyRestart(proxy *envoy.Proxy) {
+	log.Info().Msgf("Proxy %s restarted, resyncing its config", proxy.String())
+
+	var namespace, pod string
+	if proxy.PodMetadata != nil {
+		namespace = proxy.PodMetadata.Namespace
+		pod = proxy.PodMetadata.Name
+	}
+	metricsstore.DefaultMetricsStore.ProxyRestartCount.WithLabelValues(namespace, pod).Inc()
+
+	cn := proxy.GetCertificateCommonName()
+	s.withXdsLogMutex(func() {
+		delete(s.xdsLog, cn)
+	})
+
+	s.configVerMutex.Lock()
+	defer s.configVerMutex.Unlock()
+	delete(s.configHash, cn.String())
+}
@@ -0,0 +1,75 @@
+package ads
+
+import (
+	"testing"
+	"time"
+
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+	"github.com/openservicemesh/osm/pkg/tests"
+)
+
+func TestIsProxyRestart(t *testing.T) {
+	testCases := []struct {
+		name       string
+		knownProxy bool
+		request    *xds_discovery.DiscoveryRequest
+		expected   bool
+	}{
+		{
+			name:       "new proxy",
+			knownProxy: false,
+			request:    &xds_discovery.DiscoveryRequest{TypeUrl: envoy.TypeCDS.String()},
+			expected:   false,
+		},
+		{
+			name:       "known proxy without config",
+			knownProxy: true,
+			request:    &xds_discovery.DiscoveryRequest{TypeUrl: envoy.TypeCDS.String()},
+			expected:   true,
+		},
+		{
+			name:       "known proxy reconnecting with its config",
+			knownProxy: true,
+			request:    &xds_discovery.DiscoveryRequest{TypeUrl: envoy.TypeCDS.String(), VersionInfo: "3", ResponseNonce: "1234"},
+			expected:   false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tassert.Equal(t, tc.expected, isProxyRestart(tc.knownProxy, tc.request))
+		})
+	}
+}
+
+func TestHandleProxyRestart(t *testing.T) {
+	assert := tassert.New(t)
+
+	certCommonName := envoy.NewXDSCertCommonName(uuid.New(), envoy.KindSidecar, tests.BookstoreServiceAccountName, tests.Namespace)
+	proxy, err := envoy.NewProxy(certCommonName, certificate.SerialNumber("123456"), nil)
+	assert.Nil(err)
+	proxy.PodMetadata = &envoy.PodMetadata{Name: "bookstore", Namespace: tests.Namespace}
+
+	s := &Server{
+		xdsLog:        map[certificate.CommonName]map[envoy.TypeURI][]time.Time{certCommonName: {envoy.TypeCDS: {time.Now()}}},
+		configVersion: map[string]uint64{certCommonName.String(): 3},
+		configHash:    map[string]uint64{certCommonName.String(): 1234},
+	}
+	restarts := metricsstore.DefaultMetricsStore.ProxyRestartCount.WithLabelValues(tests.Namespace, "bookstore")
+	restartCount := testutil.ToFloat64(restarts)
+
+	s.handleProxyRestart(proxy)
+
+	assert.Equal(restartCount+1, testutil.ToFloat64(restarts))
+	assert.NotContains(s.xdsLog, certCommonName)
+	assert.NotContains(s.configHash, certCommonName.String())
+	// Versions keep increasing across restarts
+	assert.Equal(uint64(3), s.configVersion[certCommonName.String()])
+}
@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	xds_accesslog "github.com/envoyproxy/go-control-plane/envoy/service/accesslog/v3"
 	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
 	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
@@ -20,6 +21,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/envoy/rds"
 	"github.com/openservicemesh/osm/pkg/envoy/registry"
 	"github.com/openservicemesh/osm/pkg/envoy/sds"
+	"github.com/openservicemesh/osm/pkg/envoy/srds"
 	"github.com/openservicemesh/osm/pkg/errcode"
 	"github.com/openservicemesh/osm/pkg/k8s"
 	"github.com/openservicemesh/osm/pkg/utils"
@@ -29,33 +31,35 @@ import (
 const (
 	// ServerType is the type identifier for the ADS server
 	ServerType = "ADS"
-
-	// workerPoolSize is the default number of workerpool workers (0 is GOMAXPROCS)
-	workerPoolSize = 0
 )
 
 // NewADSServer creates a new Aggregated Discovery Service server
-func NewADSServer(meshCatalog catalog.MeshCataloger, proxyRegistry *registry.ProxyRegistry, enableDebug bool, osmNamespace string, cfg configurator.Configurator, certManager certificate.Manager, kubecontroller k8s.Controller) *Server {
+func NewADSServer(meshCatalog catalog.MeshCataloger, proxyRegistry *registry.ProxyRegistry, enableDebug bool, osmNamespace string, cfg configurator.Configurator, certManager certificate.Manager, kubecontroller k8s.Controller, pacing InitialSyncPacingConfig) *Server {
 	server := Server{
 		catalog:       meshCatalog,
 		proxyRegistry: proxyRegistry,
 		xdsHandlers: map[envoy.TypeURI]func(catalog.MeshCataloger, *envoy.Proxy, *xds_discovery.DiscoveryRequest, configurator.Configurator, certificate.Manager, *registry.ProxyRegistry) ([]types.Resource, error){
-			envoy.TypeEDS: eds.NewResponse,
-			envoy.TypeCDS: cds.NewResponse,
-			envoy.TypeRDS: rds.NewResponse,
-			envoy.TypeLDS: lds.NewResponse,
-			envoy.TypeSDS: sds.NewResponse,
+			envoy.TypeEDS:  eds.NewResponse,
+			envoy.TypeCDS:  cds.NewResponse,
+			envoy.TypeRDS:  rds.NewResponse,
+			envoy.TypeSRDS: srds.NewResponse,
+			envoy.TypeLDS:  lds.NewResponse,
+			envoy.TypeSDS:  sds.NewResponse,
 		},
 		osmNamespace:   osmNamespace,
 		cfg:            cfg,
 		certManager:    certManager,
 		xdsMapLogMutex: sync.Mutex{},
 		xdsLog:         make(map[certificate.CommonName]map[envoy.TypeURI][]time.Time),
-		workqueues:     workerpool.NewWorkerPool(workerPoolSize),
+		workqueues:     workerpool.NewWorkerPool(cfg.GetPerformanceSettings().WorkerPoolSize),
 		kubecontroller: kubecontroller,
 		cacheEnabled:   cfg.GetFeatureFlags().EnableSnapshotCacheMode,
 		configVerMutex: sync.Mutex{},
 		configVersion:  make(map[string]uint64),
+		configHash:     make(map[string]uint64),
+
+		initialSyncLimiter:   newInitialSyncLimiter(pacing),
+		initialSyncMaxJitter: pacing.MaxJitter,
 	}
 
 	return &server
@@ -68,6 +72,12 @@ func (s *Server) withXdsLogMutex(f func()) {
 	f()
 }
 
+// EnableAccessLogService enables serving the Envoy gRPC Access Log Service with the given server, on the same port as
+// ADS. It must be called before the server starts.
+func (s *Server) EnableAccessLogService(accessLogServer xds_accesslog.AccessLogServiceServer) {
+	s.accessLogServer = accessLogServer
+}
+
 // Start starts the ADS server
 func (s *Server) Start(ctx context.Context, cancel context.CancelFunc, port int, adsCert certificate.Certificater) error {
 	grpcServer, lis, err := utils.NewGrpc(ServerType, port, adsCert.GetCertificateChain(), adsCert.GetPrivateKey(), adsCert.GetIssuingCA())
@@ -88,6 +98,10 @@ func (s *Server) Start(ctx context.Context, cancel context.CancelFunc, port int,
 		xds_discovery.RegisterAggregatedDiscoveryServiceServer(grpcServer, s)
 	}
 
+	if s.accessLogServer != nil {
+		xds_accesslog.RegisterAccessLogServiceServer(grpcServer, s.accessLogServer)
+	}
+
 	go utils.GrpcServe(ctx, grpcServer, lis, cancel, ServerType, nil)
 
 	if s.cacheEnabled {
@@ -96,6 +110,9 @@ func (s *Server) Start(ctx context.Context, cancel context.CancelFunc, port int,
 		go s.broadcastListener()
 	}
 
+	s.startSnapshotPersistence(ctx)
+	s.startDesyncMonitor(ctx)
+
 	s.ready = true
 
 	return nil
@@ -0,0 +1,139 @@
+package ads
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"testing"
+
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	protov1 "github.com/golang/protobuf/proto"
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/registry"
+	configFake "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned/fake"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/tests/golden"
+)
+
+const (
+	// directoryForSnapshots is the directory of the golden files of the xDS snapshots of the proxy profiles
+	directoryForSnapshots = "../../../tests/envoy_xds_expectations/snapshots"
+
+	// The MeshConfig watched by the fake mesh catalog
+	snapshotOSMNamespace   = "-test-osm-namespace-"
+	snapshotMeshConfigName = "-test-osm-mesh-config-"
+)
+
+// snapshotRedactedFields are the fields of the xDS resources changing from one run to the other, such as the
+// certificates issued for the proxies, which are redacted from the snapshots
+var snapshotRedactedFields = []string{"inline_bytes"}
+
+// snapshotUnorderedFields are the lists of the xDS resources whose order is not meaningful and is not stable from one
+// run to the other, which are sorted in the snapshots
+var snapshotUnorderedFields = []string{"virtual_hosts", "match_subject_alt_names"}
+
+// TestXDSSnapshots renders the complete xDS configuration of canonical proxy profiles and compares it to the golden
+// files of the profiles. Run the test with -update to update the golden files after an intended change of the xDS
+// output.
+func TestXDSSnapshots(t *testing.T) {
+	testCases := []struct {
+		name              string
+		meshConfig        configv1alpha1.MeshConfigSpec
+		proxySvcAccount   identity.K8sServiceAccount
+		proxyMeshServices []service.MeshService
+	}{
+		{
+			name:              "bookbuyer",
+			proxySvcAccount:   tests.BookbuyerServiceAccount,
+			proxyMeshServices: []service.MeshService{tests.BookbuyerService},
+		},
+		{
+			name: "bookbuyer-scoped-routes",
+			meshConfig: configv1alpha1.MeshConfigSpec{
+				FeatureFlags: configv1alpha1.FeatureFlags{EnableScopedRoutes: true},
+			},
+			proxySvcAccount:   tests.BookbuyerServiceAccount,
+			proxyMeshServices: []service.MeshService{tests.BookbuyerService},
+		},
+		{
+			name:              "bookstore-v1",
+			proxySvcAccount:   tests.BookstoreServiceAccount,
+			proxyMeshServices: []service.MeshService{tests.BookstoreV1Service},
+		},
+		{
+			name: "bookstore-v1-permissive",
+			meshConfig: configv1alpha1.MeshConfigSpec{
+				Traffic: configv1alpha1.TrafficSpec{EnablePermissiveTrafficPolicyMode: true},
+			},
+			proxySvcAccount:   tests.BookstoreServiceAccount,
+			proxyMeshServices: []service.MeshService{tests.BookstoreV1Service},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			stop := make(chan struct{})
+			defer close(stop)
+
+			kubeClient := testclient.NewSimpleClientset()
+			for _, svc := range []service.MeshService{tests.BookstoreV1Service, tests.BookstoreV2Service, tests.BookbuyerService, tests.BookstoreApexService} {
+				_, err := kubeClient.CoreV1().Services(svc.Namespace).Create(context.TODO(), tests.NewServiceFixture(svc.Name, svc.Namespace, nil), metav1.CreateOptions{})
+				assert.Nil(err)
+			}
+			configClient := configFake.NewSimpleClientset(&configv1alpha1.MeshConfig{
+				ObjectMeta: metav1.ObjectMeta{Namespace: snapshotOSMNamespace, Name: snapshotMeshConfigName},
+				Spec:       tc.meshConfig,
+			})
+
+			meshCatalog := catalog.NewFakeMeshCatalog(kubeClient, configClient)
+			cfg := configurator.NewConfigurator(configClient, stop, snapshotOSMNamespace, snapshotMeshConfigName)
+			certManager := tresor.NewFakeCertManager(cfg)
+			proxyRegistry := registry.NewProxyRegistry(registry.ExplicitProxyServiceMapper(func(*envoy.Proxy) ([]service.MeshService, error) {
+				return tc.proxyMeshServices, nil
+			}))
+
+			// The proxy UUID is fixed to keep it out of the diffs of the snapshots
+			proxyUUID := uuid.NewSHA1(uuid.Nil, []byte(tc.name))
+			certCommonName := envoy.NewXDSCertCommonName(proxyUUID, envoy.KindSidecar, tc.proxySvcAccount.Name, tc.proxySvcAccount.Namespace)
+			proxy, err := envoy.NewProxy(certCommonName, certificate.SerialNumber("123456"), nil)
+			assert.Nil(err)
+
+			s := NewADSServer(meshCatalog, proxyRegistry, false, tests.Namespace, cfg, certManager, nil, InitialSyncPacingConfig{})
+
+			snapshot := make(map[string][]interface{})
+			for _, typeURI := range envoy.XDSResponseOrder {
+				request := &xds_discovery.DiscoveryRequest{TypeUrl: typeURI.String()}
+				if typeURI == envoy.TypeSDS {
+					request = makeRequestForAllSecrets(proxy, meshCatalog)
+				}
+
+				resources, err := s.xdsHandlers[typeURI](meshCatalog, proxy, request, cfg, certManager, proxyRegistry)
+				assert.Nil(err)
+				sortResources(resources)
+
+				snapshot[typeURI.Short()] = []interface{}{}
+				for _, resource := range resources {
+					object, err := golden.ProtoToObject(protov1.MessageV2(resource))
+					assert.Nil(err)
+					snapshot[typeURI.Short()] = append(snapshot[typeURI.Short()], golden.SortLists(golden.Redact(object, snapshotRedactedFields...), snapshotUnorderedFields...))
+				}
+			}
+
+			golden.AssertYAML(t, path.Join(directoryForSnapshots, fmt.Sprintf("expected_snapshot_%s.yaml", tc.name)), snapshot)
+		})
+	}
+}
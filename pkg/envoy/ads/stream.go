@@ -2,15 +2,19 @@ package ads
 
 import (
 	"context"
+	"fmt"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	mapset "github.com/deckarep/golang-set"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	"github.com/pkg/errors"
 
 	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/catalog"
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/envoy"
@@ -53,9 +57,15 @@ func (s *Server) StreamAggregatedResources(server xds_discovery.AggregatedDiscov
 	if err := s.recordPodMetadata(proxy); err == errServiceAccountMismatch {
 		// Service Account mismatch
 		log.Error().Err(err).Msgf("Mismatched service account for proxy with certificate SerialNumber=%s", certSerialNumber)
+		s.warnPodEvent(proxy, events.ProxyServiceAccountMismatch,
+			"Proxy rejected: the service account %s of the pod does not match the service account of its xDS certificate %s",
+			proxy.PodMetadata.ServiceAccount, certCommonName)
 		return err
 	}
 
+	// A proxy known to the registry connecting again either reconnected or restarted, told apart on its first request
+	knownProxy := s.proxyRegistry.IsKnownProxy(certCommonName)
+
 	s.proxyRegistry.RegisterProxy(proxy)
 
 	defer s.proxyRegistry.UnregisterProxy(proxy)
@@ -76,6 +86,27 @@ func (s *Server) StreamAggregatedResources(server xds_discovery.AggregatedDiscov
 	// Register for certificate rotation updates
 	certAnnouncement := events.Subscribe(announcements.CertificateRotated)
 
+	// Register for the requests to push again the config the proxy lags behind
+	resyncRequests := events.Subscribe(announcements.ProxyResyncRequested)
+
+	// The time the certificate of the proxy was rotated at, while the rotated certificate is not yet pushed to it
+	var certRotatedAt time.Time
+	defer func() {
+		if !certRotatedAt.IsZero() {
+			metricsstore.DefaultMetricsStore.ProxyStaleCertCount.Dec()
+		}
+	}()
+
+	// Namespaces referenced by the proxy's config as of the last broadcast, used to scope broadcasts to the proxy
+	var proxyNamespaces map[string]struct{}
+
+	// Whether the first response to the proxy was scheduled, the initial config delivery being paced
+	initialSyncScheduled := false
+
+	// Whether the first request of the stream was received, and whether it came from a restarted Envoy
+	firstRequestReceived := false
+	restarted := false
+
 	newJob := func(typeURIs []envoy.TypeURI, discoveryRequest *xds_discovery.DiscoveryRequest) *proxyResponseJob {
 		return &proxyResponseJob{
 			typeURIs:  typeURIs,
@@ -100,12 +131,25 @@ func (s *Server) StreamAggregatedResources(server xds_discovery.AggregatedDiscov
 
 		case discoveryRequest, ok := <-requests:
 			if !ok {
-				log.Error().Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGRPCStreamClosedByProxy)).
+				log.Error().Str(errcode.Kind, errcode.GetErrCodeWithMetricForProxy(errcode.ErrGRPCStreamClosedByProxy, proxy.GetCertificateCommonName())).
 					Msgf("gRPC stream closed by proxy %s!", proxy.String())
 				metricsstore.DefaultMetricsStore.ProxyConnectCount.Dec()
 				return errGrpcClosed
 			}
 
+			// The node is only set on the first discovery request of the stream
+			if discoveryRequest.Node != nil {
+				proxy.SetEnvoyVersion(getEnvoyVersion(discoveryRequest.Node))
+			}
+
+			if !firstRequestReceived {
+				firstRequestReceived = true
+				if isProxyRestart(knownProxy, &discoveryRequest) {
+					restarted = true
+					s.handleProxyRestart(proxy)
+				}
+			}
+
 			// This function call runs xDS proto state machine given DiscoveryRequest as input.
 			// It's output is the decision to reply or not to this request.
 			if !respondToRequest(proxy, &discoveryRequest) {
@@ -114,9 +158,30 @@ func (s *Server) StreamAggregatedResources(server xds_discovery.AggregatedDiscov
 
 			typesRequest := []envoy.TypeURI{envoy.TypeURI(discoveryRequest.TypeUrl)}
 
-			<-s.workqueues.AddJob(newJob(typesRequest, &discoveryRequest))
+			// Pace the initial config deliveries so proxies reconnecting at once are not all synced at the same time.
+			// A restarted Envoy runs without any config until it is synced, so its initial config delivery is not paced.
+			if !initialSyncScheduled && !restarted {
+				initialSyncScheduled = true
+				if err := s.waitForInitialSync(ctx, proxy); err != nil {
+					log.Debug().Err(err).Msgf("Stream of proxy %s closed while waiting for its initial config delivery", proxy.String())
+					metricsstore.DefaultMetricsStore.ProxyConnectCount.Dec()
+					return nil
+				}
+				<-s.workqueues.AddJobWithJitter(newJob(typesRequest, &discoveryRequest), s.initialSyncMaxJitter)
+				continue
+			}
+
+			job := newJob(typesRequest, &discoveryRequest)
+			<-s.workqueues.AddJob(job)
+			certRotatedAt = recordCertPush(job, certRotatedAt)
 
-		case <-broadcastUpdate:
+		case broadcastMsg := <-broadcastUpdate:
+			var affected bool
+			affected, proxyNamespaces = s.isAffectedByBroadcast(proxy, broadcastMsg, proxyNamespaces)
+			if !affected {
+				log.Debug().Msgf("Broadcast update out of scope for proxy %s", proxy.String())
+				continue
+			}
 			log.Info().Msgf("Broadcast update received for proxy %s", proxy.String())
 
 			// Per protocol, we have to wait for the proxy to go through init phase (initial no-nonce request),
@@ -127,9 +192,26 @@ func (s *Server) StreamAggregatedResources(server xds_discovery.AggregatedDiscov
 				continue
 			}
 
-			// Queue a full configuration update
+			// Queue a configuration update of the xDS types affected by the broadcast
 			// Do not send SDS, let envoy figure out what certs does it want.
-			<-s.workqueues.AddJob(newJob([]envoy.TypeURI{envoy.TypeCDS, envoy.TypeEDS, envoy.TypeLDS, envoy.TypeRDS}, nil))
+			<-s.workqueues.AddJob(newJob(getBroadcastTypeURIs(broadcastMsg), nil))
+
+		case resyncMsg := <-resyncRequests:
+			typeURIs := getResyncTypeURIs(proxy, resyncMsg)
+			if len(typeURIs) == 0 || !shouldPushUpdate(proxy) {
+				continue
+			}
+			log.Info().Msgf("Pushing %v config again to desynced proxy %s", typeURIs, proxy.String())
+
+			// Each type is pushed as if requested by the proxy for its subscribed resources, so that it is sent again
+			// even though it is unchanged since it was last sent
+			for _, typeURI := range typeURIs {
+				request := &xds_discovery.DiscoveryRequest{
+					TypeUrl:       typeURI.String(),
+					ResourceNames: getResourceSliceFromMapset(proxy.GetSubscribedResources(typeURI)),
+				}
+				<-s.workqueues.AddJob(newJob([]envoy.TypeURI{typeURI}, request))
+			}
 
 		case certUpdateMsg := <-certAnnouncement:
 			cert := certUpdateMsg.(events.PubSubMessage).NewObj.(certificate.Certificater)
@@ -138,21 +220,113 @@ func (s *Server) StreamAggregatedResources(server xds_discovery.AggregatedDiscov
 				// with this proxy, so update the secrets corresponding to this certificate via SDS.
 				log.Debug().Msgf("Certificate has been updated for proxy %s", proxy.String())
 
+				// The proxy runs on a stale certificate until the rotated one is pushed to it
+				if certRotatedAt.IsZero() {
+					certRotatedAt = time.Now()
+					metricsstore.DefaultMetricsStore.ProxyStaleCertCount.Inc()
+				}
+
 				// Empty DiscoveryRequest should create the SDS specific request
 				// Prepare to queue the SDS proxy response job on the worker pool
-				<-s.workqueues.AddJob(newJob([]envoy.TypeURI{envoy.TypeSDS}, nil))
+				job := newJob([]envoy.TypeURI{envoy.TypeSDS}, nil)
+				<-s.workqueues.AddJob(job)
+				certRotatedAt = recordCertPush(job, certRotatedAt)
 			}
 		}
 	}
 }
 
+// recordCertPush records the push of the rotated certificate of a proxy, rotated at the given time, if the given
+// finished job sent the secrets of the proxy to it. It returns the time the certificate of the proxy was rotated at
+// while the rotated certificate is not yet pushed to it, zero once pushed.
+func recordCertPush(job *proxyResponseJob, certRotatedAt time.Time) time.Time {
+	if certRotatedAt.IsZero() || !job.sentType(envoy.TypeSDS) {
+		return certRotatedAt
+	}
+
+	metricsstore.DefaultMetricsStore.ProxyCertRotationPushTime.Observe(time.Since(certRotatedAt).Seconds())
+	metricsstore.DefaultMetricsStore.ProxyStaleCertCount.Dec()
+	return time.Time{}
+}
+
+// isAffectedByBroadcast returns whether the given proxy must be updated on the given proxy broadcast, along with the
+// namespaces the proxy's config currently references, to be passed back on the next broadcast.
+// A broadcast scoped to namespaces affects the proxy if its config referenced any of them either as of the previous
+// broadcast or now, so that the proxy is also updated when the references to a namespace are removed.
+// Gateways reference services in all namespaces and are affected by all broadcasts.
+func (s *Server) isAffectedByBroadcast(proxy *envoy.Proxy, msg interface{}, lastNamespaces map[string]struct{}) (bool, map[string]struct{}) {
+	if proxy.Kind() == envoy.KindGateway {
+		return true, nil
+	}
+
+	proxyIdentity, err := envoy.GetServiceIdentityFromProxyCertificate(proxy.GetCertificateCommonName())
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetricForProxy(errcode.ErrGettingServiceIdentity, proxy.GetCertificateCommonName())).
+			Msgf("Error looking up identity for proxy %s", proxy.String())
+		return true, nil
+	}
+	namespaces := getProxyNamespaces(s.catalog, proxyIdentity)
+
+	psubMessage, ok := msg.(events.PubSubMessage)
+	if !ok {
+		return true, namespaces
+	}
+	scope, ok := psubMessage.NewObj.(*catalog.ProxyBroadcastScope)
+	if !ok || scope.Namespaces == nil || lastNamespaces == nil {
+		return true, namespaces
+	}
+
+	for ns := range scope.Namespaces {
+		if _, ok := namespaces[ns]; ok {
+			return true, namespaces
+		}
+		if _, ok := lastNamespaces[ns]; ok {
+			return true, namespaces
+		}
+	}
+	return false, namespaces
+}
+
+// getBroadcastTypeURIs returns the xDS types to update on the given proxy broadcast: those of its scope if the
+// broadcast is scoped to specific types, or else the full configuration of the proxy
+func getBroadcastTypeURIs(msg interface{}) []envoy.TypeURI {
+	if psubMessage, ok := msg.(events.PubSubMessage); ok {
+		if scope, ok := psubMessage.NewObj.(*catalog.ProxyBroadcastScope); ok && scope.TypeURIs != nil {
+			return scope.TypeURIs
+		}
+	}
+	return []envoy.TypeURI{envoy.TypeCDS, envoy.TypeEDS, envoy.TypeLDS, envoy.TypeSRDS, envoy.TypeRDS}
+}
+
+// getProxyNamespaces returns the namespaces referenced by the config of the proxies of the given identity: the
+// namespace of the identity, and those of its upstream services and downstream identities
+func getProxyNamespaces(meshCatalog catalog.MeshCataloger, proxyIdentity identity.ServiceIdentity) map[string]struct{} {
+	namespaces := map[string]struct{}{
+		proxyIdentity.ToK8sServiceAccount().Namespace: {},
+	}
+
+	for _, svc := range meshCatalog.ListOutboundServicesForIdentity(proxyIdentity) {
+		namespaces[svc.Namespace] = struct{}{}
+	}
+
+	inboundIdentities, err := meshCatalog.ListInboundServiceIdentities(proxyIdentity)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error listing inbound identities of %s", proxyIdentity)
+	}
+	for _, inboundIdentity := range inboundIdentities {
+		namespaces[inboundIdentity.ToK8sServiceAccount().Namespace] = struct{}{}
+	}
+
+	return namespaces
+}
+
 // shouldPushUpdate handles allowing new updates to envoy from control-plane driven config changes.
 // Its use is to make sure we don't unintentintionally push new versions if at least a first request has not arrived yet.
 func shouldPushUpdate(proxy *envoy.Proxy) bool {
 	// In ADS, CDS and LDS will come first in all cases. Only allow an control-plane-push update push if
 	// we have sent either to the proxy already.
 	if proxy.GetLastSentNonce(envoy.TypeLDS) == "" && proxy.GetLastSentNonce(envoy.TypeCDS) == "" {
-		log.Error().Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrUnexpectedXDSRequest)).
+		log.Error().Str(errcode.Kind, errcode.GetErrCodeWithMetricForProxy(errcode.ErrUnexpectedXDSRequest, proxy.GetCertificateCommonName())).
 			Msgf("Proxy %s: LDS and CDS unrequested yet, waiting for first request for this proxy to be responded to",
 				proxy.String())
 		return false
@@ -170,6 +344,15 @@ func parseRequestVersion(discoveryRequest *xds_discovery.DiscoveryRequest) (uint
 	return strconv.ParseUint(discoveryRequest.VersionInfo, 10, 64)
 }
 
+// getEnvoyVersion returns the version of Envoy reported in the given node of a DiscoveryRequest
+func getEnvoyVersion(node *xds_core.Node) string {
+	if buildVersion := node.GetUserAgentBuildVersion(); buildVersion != nil && buildVersion.GetVersion() != nil {
+		version := buildVersion.GetVersion()
+		return fmt.Sprintf("%d.%d.%d", version.GetMajorNumber(), version.GetMinorNumber(), version.GetPatch())
+	}
+	return node.GetUserAgentVersion()
+}
+
 // respondToRequest assesses if a given DiscoveryRequest for a given proxy should be responded with
 // an xDS DiscoveryResponse.
 func respondToRequest(proxy *envoy.Proxy, discoveryRequest *xds_discovery.DiscoveryRequest) bool {
@@ -186,7 +369,7 @@ func respondToRequest(proxy *envoy.Proxy, discoveryRequest *xds_discovery.Discov
 	// Parse TypeURL of the request
 	typeURL, ok := envoy.ValidURI[discoveryRequest.TypeUrl]
 	if !ok {
-		log.Error().Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrInvalidXDSTypeURI)).
+		log.Error().Str(errcode.Kind, errcode.GetErrCodeWithMetricForProxy(errcode.ErrInvalidXDSTypeURI, proxy.GetCertificateCommonName())).
 			Msgf("Proxy %s: Unknown/Unsupported URI: %s",
 				proxy.String(), discoveryRequest.TypeUrl)
 		return false
@@ -201,7 +384,7 @@ func respondToRequest(proxy *envoy.Proxy, discoveryRequest *xds_discovery.Discov
 	// Parse ACK'd verion on the proxy for this given resource
 	requestVersion, err = parseRequestVersion(discoveryRequest)
 	if err != nil {
-		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrParsingDiscoveryReqVersion)).
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetricForProxy(errcode.ErrParsingDiscoveryReqVersion, proxy.GetCertificateCommonName())).
 			Msgf("Proxy %s: Error parsing version %s for type %s", proxy.String(), discoveryRequest.VersionInfo, typeURL)
 		return false
 	}
@@ -341,6 +524,13 @@ func (s *Server) recordPodMetadata(p *envoy.Proxy) error {
 		return nil
 	}
 
+	if p.Kind() == envoy.KindExternalWorkload {
+		if _, err := envoy.GetExternalWorkloadFromCertificate(p.GetCertificateCommonName(), s.kubecontroller); err != nil {
+			log.Warn().Msgf("Could not find external workload for connecting proxy %s", p.GetCertificateSerialNumber())
+		}
+		return nil
+	}
+
 	pod, err := envoy.GetPodFromCertificate(p.GetCertificateCommonName(), s.kubecontroller)
 	if err != nil {
 		log.Warn().Msgf("Could not find pod for connecting proxy %s. No metadata was recorded.", p.GetCertificateSerialNumber())
@@ -378,7 +568,7 @@ func (s *Server) recordPodMetadata(p *envoy.Proxy) error {
 	}
 
 	if certSA.ToK8sServiceAccount() != p.PodMetadata.ServiceAccount {
-		log.Error().Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMismatchedServiceAccount)).
+		log.Error().Str(errcode.Kind, errcode.GetErrCodeWithMetricForProxy(errcode.ErrMismatchedServiceAccount, p.GetCertificateCommonName())).
 			Msgf("Service Account referenced in NodeID (%s) does not match Service Account in Certificate (%s). This proxy is not allowed to join the mesh.", p.PodMetadata.ServiceAccount, certSA)
 		return errServiceAccountMismatch
 	}
@@ -3,13 +3,22 @@ package ads
 import (
 	"fmt"
 	"testing"
+	"time"
 
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	xds_type "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	tassert "github.com/stretchr/testify/assert"
 
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/catalog"
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/service"
 )
 
 func TestIsCNForProxy(t *testing.T) {
@@ -53,6 +62,153 @@ func TestIsCNForProxy(t *testing.T) {
 	}
 }
 
+func TestIsAffectedByBroadcast(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	s := &Server{catalog: mockCatalog}
+
+	proxyIdentity := identity.K8sServiceAccount{Name: "bookbuyer", Namespace: "bookbuyer"}.ToServiceIdentity()
+	proxy, err := envoy.NewProxy(envoy.NewXDSCertCommonName(uuid.New(), envoy.KindSidecar, "bookbuyer", "bookbuyer"), "1", nil)
+	assert.Nil(err)
+
+	upstreams := []service.MeshService{{Name: "bookstore", Namespace: "bookstore"}}
+	mockCatalog.EXPECT().ListOutboundServicesForIdentity(proxyIdentity).DoAndReturn(func(identity.ServiceIdentity) []service.MeshService {
+		return upstreams
+	}).AnyTimes()
+	mockCatalog.EXPECT().ListInboundServiceIdentities(proxyIdentity).Return(nil, nil).AnyTimes()
+
+	scoped := func(namespaces ...string) events.PubSubMessage {
+		scope := &catalog.ProxyBroadcastScope{Namespaces: map[string]struct{}{}}
+		for _, ns := range namespaces {
+			scope.Namespaces[ns] = struct{}{}
+		}
+		return events.PubSubMessage{AnnouncementType: announcements.ProxyBroadcast, NewObj: scope}
+	}
+
+	// The first broadcast always affects the proxy since the namespaces its config referenced are unknown
+	affected, namespaces := s.isAffectedByBroadcast(proxy, scoped("other"), nil)
+	assert.True(affected)
+	assert.Equal(map[string]struct{}{"bookbuyer": {}, "bookstore": {}}, namespaces)
+
+	// Unscoped broadcasts affect all proxies
+	affected, namespaces = s.isAffectedByBroadcast(proxy, events.PubSubMessage{AnnouncementType: announcements.ProxyBroadcast}, namespaces)
+	assert.True(affected)
+
+	// Broadcasts only scoped to xDS types affect all proxies
+	typeScoped := events.PubSubMessage{
+		AnnouncementType: announcements.ProxyBroadcast,
+		NewObj:           &catalog.ProxyBroadcastScope{TypeURIs: []envoy.TypeURI{envoy.TypeLDS}},
+	}
+	affected, namespaces = s.isAffectedByBroadcast(proxy, typeScoped, namespaces)
+	assert.True(affected)
+
+	// Scoped broadcasts only affect proxies whose config references the namespaces in scope
+	affected, namespaces = s.isAffectedByBroadcast(proxy, scoped("other"), namespaces)
+	assert.False(affected)
+	affected, namespaces = s.isAffectedByBroadcast(proxy, scoped("bookbuyer"), namespaces)
+	assert.True(affected)
+	affected, namespaces = s.isAffectedByBroadcast(proxy, scoped("other", "bookstore"), namespaces)
+	assert.True(affected)
+
+	// Removing the references to a namespace affects the proxy
+	upstreams = nil
+	affected, namespaces = s.isAffectedByBroadcast(proxy, scoped("bookstore"), namespaces)
+	assert.True(affected)
+	affected, _ = s.isAffectedByBroadcast(proxy, scoped("bookstore"), namespaces)
+	assert.False(affected)
+
+	// Gateways are affected by all broadcasts
+	gateway, err := envoy.NewProxy(envoy.NewXDSCertCommonName(uuid.New(), envoy.KindGateway, "osm", "osm-system"), "1", nil)
+	assert.Nil(err)
+	affected, _ = s.isAffectedByBroadcast(gateway, scoped("other"), map[string]struct{}{})
+	assert.True(affected)
+}
+
+func TestGetBroadcastTypeURIs(t *testing.T) {
+	allTypes := []envoy.TypeURI{envoy.TypeCDS, envoy.TypeEDS, envoy.TypeLDS, envoy.TypeSRDS, envoy.TypeRDS}
+
+	testCases := []struct {
+		name     string
+		msg      interface{}
+		expected []envoy.TypeURI
+	}{
+		{
+			name:     "unscoped broadcast",
+			msg:      events.PubSubMessage{AnnouncementType: announcements.ProxyBroadcast},
+			expected: allTypes,
+		},
+		{
+			name: "broadcast scoped to namespaces",
+			msg: events.PubSubMessage{
+				AnnouncementType: announcements.ProxyBroadcast,
+				NewObj:           &catalog.ProxyBroadcastScope{Namespaces: map[string]struct{}{"ns": {}}},
+			},
+			expected: allTypes,
+		},
+		{
+			name: "broadcast scoped to xDS types",
+			msg: events.PubSubMessage{
+				AnnouncementType: announcements.ProxyBroadcast,
+				NewObj:           &catalog.ProxyBroadcastScope{TypeURIs: []envoy.TypeURI{envoy.TypeEDS}},
+			},
+			expected: []envoy.TypeURI{envoy.TypeEDS},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expected, getBroadcastTypeURIs(tc.msg))
+		})
+	}
+}
+
+func TestRecordCertPush(t *testing.T) {
+	rotatedAt := time.Now().Add(-1 * time.Second)
+
+	testCases := []struct {
+		name          string
+		job           *proxyResponseJob
+		certRotatedAt time.Time
+		expected      time.Time
+	}{
+		{
+			name:          "certificate not rotated",
+			job:           &proxyResponseJob{typeURIs: []envoy.TypeURI{envoy.TypeSDS}},
+			certRotatedAt: time.Time{},
+			expected:      time.Time{},
+		},
+		{
+			name:          "rotated certificate pushed",
+			job:           &proxyResponseJob{typeURIs: []envoy.TypeURI{envoy.TypeSDS}},
+			certRotatedAt: rotatedAt,
+			expected:      time.Time{},
+		},
+		{
+			name:          "rotated certificate not pushed by a job for other types",
+			job:           &proxyResponseJob{typeURIs: []envoy.TypeURI{envoy.TypeCDS, envoy.TypeEDS}},
+			certRotatedAt: rotatedAt,
+			expected:      rotatedAt,
+		},
+		{
+			name:          "rotated certificate not pushed by a failed job",
+			job:           &proxyResponseJob{typeURIs: []envoy.TypeURI{envoy.TypeSDS}, err: errCreatingResponse},
+			certRotatedAt: rotatedAt,
+			expected:      rotatedAt,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expected, recordCertPush(tc.job, tc.certRotatedAt))
+		})
+	}
+}
+
 func findSliceElem(slice []string, elem string) bool {
 	for _, v := range slice {
 		if v == elem {
@@ -82,3 +238,44 @@ func TestMapsetToSliceConvFunctions(t *testing.T) {
 	assert.True(findSliceElem(nameSlice, "C"))
 	assert.False(findSliceElem(nameSlice, "D"))
 }
+
+func TestGetEnvoyVersion(t *testing.T) {
+	testCases := []struct {
+		name     string
+		node     *xds_core.Node
+		expected string
+	}{
+		{
+			name: "build version",
+			node: &xds_core.Node{
+				UserAgentVersionType: &xds_core.Node_UserAgentBuildVersion{
+					UserAgentBuildVersion: &xds_core.BuildVersion{
+						Version: &xds_type.SemanticVersion{MajorNumber: 1, MinorNumber: 18, Patch: 3},
+					},
+				},
+			},
+			expected: "1.18.3",
+		},
+		{
+			name: "free-form version",
+			node: &xds_core.Node{
+				UserAgentVersionType: &xds_core.Node_UserAgentVersion{
+					UserAgentVersion: "1.18.3-dev",
+				},
+			},
+			expected: "1.18.3-dev",
+		},
+		{
+			name:     "no version",
+			node:     &xds_core.Node{},
+			expected: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expected, getEnvoyVersion(tc.node))
+		})
+	}
+}
@@ -5,17 +5,21 @@ import (
 	"sync"
 	"time"
 
+	xds_accesslog "github.com/envoyproxy/go-control-plane/envoy/service/accesslog/v3"
 	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
 	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
 	serverv3 "github.com/envoyproxy/go-control-plane/pkg/server/v3"
+	"k8s.io/client-go/util/flowcontrol"
 
 	"github.com/openservicemesh/osm/pkg/catalog"
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/envoy"
 	"github.com/openservicemesh/osm/pkg/envoy/registry"
+	"github.com/openservicemesh/osm/pkg/envoy/snapshotstore"
 	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
 	"github.com/openservicemesh/osm/pkg/logger"
 	"github.com/openservicemesh/osm/pkg/workerpool"
 )
@@ -38,6 +42,32 @@ type Server struct {
 	workqueues     *workerpool.WorkerPool
 	kubecontroller k8s.Controller
 
+	// initialSyncLimiter admits the initial config deliveries to the connecting proxies, nil if they are not paced
+	initialSyncLimiter flowcontrol.RateLimiter
+
+	// initialSyncMaxJitter is the maximum random delay before the initial config delivery to a proxy is scheduled
+	initialSyncMaxJitter time.Duration
+
+	// snapshotStore persists the last resources generated for each kind of proxy and identity, nil if disabled
+	snapshotStore        snapshotstore.Store
+	snapshotWarmupPeriod time.Duration
+	// snapshotWarmupDone is closed when the warm-up period during which the persisted snapshots are served elapses
+	snapshotWarmupDone chan struct{}
+	// snapshotMutex protects the maps of snapshots below, keyed by kind of proxy and service identity
+	snapshotMutex      sync.Mutex
+	generatedSnapshots map[string]snapshotstore.Snapshot
+	dirtySnapshots     map[string]struct{}
+	persistedSnapshots map[string]snapshotstore.Snapshot
+
+	// accessLogServer serves the access logs streamed by the proxies over the ADS connection's gRPC server, nil if disabled
+	accessLogServer xds_accesslog.AccessLogServiceServer
+
+	// eventRecorder records Kubernetes events against the pods of the proxies, nil if disabled
+	eventRecorder *events.ObjectEventRecorder
+
+	// desyncConfig is the configuration of the detection of the proxies lagging behind the config sent to them
+	desyncConfig DesyncMonitorConfig
+
 	// ---
 	// SnapshotCache implementation structrues below
 	cacheEnabled bool
@@ -48,4 +78,6 @@ type Server struct {
 	// tracks at which version we are at given a proxy UUID
 	configVerMutex sync.Mutex
 	configVersion  map[string]uint64
+	// configHash tracks the hash of the content of the last snapshot recorded for a given proxy, to skip identical ones
+	configHash map[string]uint64
 }
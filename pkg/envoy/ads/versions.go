@@ -0,0 +1,29 @@
+package ads
+
+// GetConfigVersions returns a copy of the config versions of the snapshots recorded for each proxy, keyed by
+// the proxy's certificate common name.
+// Proxy streams not using the snapshot cache establish version continuity when reconnecting, see respondToRequest.
+func (s *Server) GetConfigVersions() map[string]uint64 {
+	s.configVerMutex.Lock()
+	defer s.configVerMutex.Unlock()
+
+	versions := make(map[string]uint64, len(s.configVersion))
+	for cn, version := range s.configVersion {
+		versions[cn] = version
+	}
+	return versions
+}
+
+// SetConfigVersions sets the config versions of the snapshots recorded for the given proxies, so that the
+// versions of the snapshots recorded next keep increasing after being handed off from another controller.
+// A version is never lowered.
+func (s *Server) SetConfigVersions(versions map[string]uint64) {
+	s.configVerMutex.Lock()
+	defer s.configVerMutex.Unlock()
+
+	for cn, version := range versions {
+		if version > s.configVersion[cn] {
+			s.configVersion[cn] = version
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package ads
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestConfigVersions(t *testing.T) {
+	assert := tassert.New(t)
+
+	s := Server{
+		configVersion: map[string]uint64{"proxy-1": 5, "proxy-2": 2},
+	}
+
+	versions := s.GetConfigVersions()
+	assert.Equal(map[string]uint64{"proxy-1": 5, "proxy-2": 2}, versions)
+
+	// The returned versions are a copy
+	versions["proxy-1"] = 10
+	assert.Equal(uint64(5), s.configVersion["proxy-1"])
+
+	// Versions are never lowered
+	s.SetConfigVersions(map[string]uint64{"proxy-1": 3, "proxy-2": 7, "proxy-3": 1})
+	assert.Equal(map[string]uint64{"proxy-1": 5, "proxy-2": 7, "proxy-3": 1}, s.GetConfigVersions())
+}
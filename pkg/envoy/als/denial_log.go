@@ -0,0 +1,99 @@
+package als
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+)
+
+const (
+	// enforcedMode and shadowMode are the values of the mode label of the RBAC denial metric
+	enforcedMode = "enforced"
+	shadowMode   = "shadow"
+)
+
+// NewDenialLog returns a DenialLog retaining the given number of most recent denials.
+func NewDenialLog(maxDenials int) *DenialLog {
+	if maxDenials <= 0 {
+		maxDenials = DefaultMaxDenials
+	}
+	return &DenialLog{
+		denials: make([]Denial, maxDenials),
+	}
+}
+
+// Record adds the given denial to the log, evicting the oldest denial if the log is full, and counts it in the
+// RBAC denial metric of each of its services.
+func (l *DenialLog) Record(denial Denial) {
+	mode := enforcedMode
+	if denial.Shadow {
+		mode = shadowMode
+	}
+	for _, svc := range denial.Services {
+		metricsstore.DefaultMetricsStore.RBACDenialCount.WithLabelValues(denial.Namespace, svc, mode).Inc()
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.denials[l.next] = denial
+	l.next = (l.next + 1) % len(l.denials)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// List returns the retained denials, most recent first. Only the denials in the given namespace are returned if the
+// namespace is not empty, and only those of the given service in that namespace if the service is not empty.
+func (l *DenialLog) List(namespace string, service string) []Denial {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	count := l.next
+	if l.full {
+		count = len(l.denials)
+	}
+
+	denials := []Denial{}
+	for i := 1; i <= count; i++ {
+		denial := l.denials[(l.next-i+len(l.denials))%len(l.denials)]
+		if namespace != "" && denial.Namespace != namespace {
+			continue
+		}
+		if service != "" && !hasService(denial, service) {
+			continue
+		}
+		denials = append(denials, denial)
+	}
+	return denials
+}
+
+// GetDenialsHTTPHandler returns an HTTP handler listing the retained denials in JSON, most recent first, optionally
+// filtered by the 'namespace' and 'service' query parameters.
+func (l *DenialLog) GetDenialsHTTPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		denials := l.List(query.Get(denialsNamespaceQueryKey), query.Get(denialsServiceQueryKey))
+
+		jsonDenials, err := json.Marshal(denials)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error marshaling RBAC denials %+v", denials)
+			http.Error(w, "Error marshaling RBAC denials", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, string(jsonDenials))
+	})
+}
+
+func hasService(denial Denial, service string) bool {
+	for _, svc := range denial.Services {
+		if svc == service {
+			return true
+		}
+	}
+	return false
+}
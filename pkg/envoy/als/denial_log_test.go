@@ -0,0 +1,110 @@
+package als
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+)
+
+func TestDenialLog(t *testing.T) {
+	bookstore := Denial{Namespace: "bookstore", Services: []string{"bookstore", "bookstore-v1"}, Path: "/books-bought"}
+	bookstoreShadow := Denial{Namespace: "bookstore", Services: []string{"bookstore-v1"}, Path: "/buy-a-book", Shadow: true}
+	bookwarehouse := Denial{Namespace: "bookwarehouse", Services: []string{"bookwarehouse"}, Path: "/restock-books"}
+
+	testCases := []struct {
+		name            string
+		maxDenials      int
+		recorded        []Denial
+		namespace       string
+		service         string
+		expectedDenials []Denial
+	}{
+		{
+			name:            "no denials",
+			maxDenials:      3,
+			recorded:        nil,
+			expectedDenials: []Denial{},
+		},
+		{
+			name:            "denials are listed most recent first",
+			maxDenials:      3,
+			recorded:        []Denial{bookstore, bookstoreShadow, bookwarehouse},
+			expectedDenials: []Denial{bookwarehouse, bookstoreShadow, bookstore},
+		},
+		{
+			name:            "oldest denials are evicted",
+			maxDenials:      2,
+			recorded:        []Denial{bookstore, bookstoreShadow, bookwarehouse},
+			expectedDenials: []Denial{bookwarehouse, bookstoreShadow},
+		},
+		{
+			name:            "denials filtered by namespace",
+			maxDenials:      3,
+			recorded:        []Denial{bookstore, bookstoreShadow, bookwarehouse},
+			namespace:       "bookstore",
+			expectedDenials: []Denial{bookstoreShadow, bookstore},
+		},
+		{
+			name:            "denials filtered by namespace and service",
+			maxDenials:      3,
+			recorded:        []Denial{bookstore, bookstoreShadow, bookwarehouse},
+			namespace:       "bookstore",
+			service:         "bookstore",
+			expectedDenials: []Denial{bookstore},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			denialLog := NewDenialLog(tc.maxDenials)
+			for _, denial := range tc.recorded {
+				denialLog.Record(denial)
+			}
+
+			assert.Equal(tc.expectedDenials, denialLog.List(tc.namespace, tc.service))
+		})
+	}
+}
+
+func TestDenialLogMetric(t *testing.T) {
+	assert := tassert.New(t)
+
+	denialCount := metricsstore.DefaultMetricsStore.RBACDenialCount
+	enforced := testutil.ToFloat64(denialCount.WithLabelValues("metrics", "bookstore", enforcedMode))
+	shadow := testutil.ToFloat64(denialCount.WithLabelValues("metrics", "bookstore", shadowMode))
+
+	denialLog := NewDenialLog(DefaultMaxDenials)
+	denialLog.Record(Denial{Namespace: "metrics", Services: []string{"bookstore"}})
+	denialLog.Record(Denial{Namespace: "metrics", Services: []string{"bookstore"}, Shadow: true})
+	denialLog.Record(Denial{Namespace: "metrics", Services: []string{"bookstore"}, Shadow: true})
+
+	assert.Equal(enforced+1, testutil.ToFloat64(denialCount.WithLabelValues("metrics", "bookstore", enforcedMode)))
+	assert.Equal(shadow+2, testutil.ToFloat64(denialCount.WithLabelValues("metrics", "bookstore", shadowMode)))
+}
+
+func TestGetDenialsHTTPHandler(t *testing.T) {
+	assert := tassert.New(t)
+
+	denialLog := NewDenialLog(DefaultMaxDenials)
+	denialLog.Record(Denial{Namespace: "bookstore", Services: []string{"bookstore"}, Principal: "bookbuyer.bookbuyer.cluster.local"})
+	denialLog.Record(Denial{Namespace: "bookwarehouse", Services: []string{"bookwarehouse"}})
+
+	req := httptest.NewRequest(http.MethodGet, "/rbac-denials?namespace=bookstore&service=bookstore", nil)
+	rr := httptest.NewRecorder()
+	denialLog.GetDenialsHTTPHandler().ServeHTTP(rr, req)
+
+	assert.Equal(http.StatusOK, rr.Code)
+
+	var denials []Denial
+	assert.Nil(json.Unmarshal(rr.Body.Bytes(), &denials))
+	assert.Len(denials, 1)
+	assert.Equal("bookbuyer.bookbuyer.cluster.local", denials[0].Principal)
+}
@@ -0,0 +1,120 @@
+package als
+
+import (
+	"io"
+	"strings"
+
+	xds_accesslog_data "github.com/envoyproxy/go-control-plane/envoy/data/accesslog/v3"
+	xds_accesslog "github.com/envoyproxy/go-control-plane/envoy/service/accesslog/v3"
+	"github.com/golang/protobuf/ptypes"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/registry"
+	"github.com/openservicemesh/osm/pkg/utils"
+)
+
+// NewServer returns an access log server recording the denied requests streamed by the proxies in the given log.
+// The denials are attributed to the services of the proxies in the given registry.
+func NewServer(proxyRegistry *registry.ProxyRegistry, denialLog *DenialLog) *Server {
+	return &Server{
+		proxyRegistry: proxyRegistry,
+		denialLog:     denialLog,
+	}
+}
+
+// StreamAccessLogs implements xds_accesslog.AccessLogServiceServer, recording the HTTP requests denied by the RBAC
+// policies of the proxy streaming its access logs.
+func (s *Server) StreamAccessLogs(stream xds_accesslog.AccessLogService_StreamAccessLogsServer) error {
+	certCommonName, _, err := utils.ValidateClient(stream.Context(), nil)
+	if err != nil {
+		log.Error().Err(err).Msg("Error validating the client of an access log stream")
+		return err
+	}
+
+	var destination *Denial
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			log.Error().Err(err).Msgf("Error receiving access logs from proxy with certificate CN %s", certCommonName)
+			return err
+		}
+
+		for _, entry := range msg.GetHttpLogs().GetLogEntry() {
+			denial, ok := newDenial(entry)
+			if !ok {
+				continue
+			}
+
+			// The proxy's services are resolved once per stream, on its first denial
+			if destination == nil {
+				destination = s.getDestination(certCommonName)
+			}
+			denial.Namespace = destination.Namespace
+			denial.Services = destination.Services
+			denial.ServiceIdentity = destination.ServiceIdentity
+			s.denialLog.Record(denial)
+		}
+	}
+}
+
+// getDestination returns a denial identifying the proxy with the given certificate CN by its service identity, and
+// the namespace and names of its services
+func (s *Server) getDestination(certCommonName certificate.CommonName) *Denial {
+	destination := &Denial{}
+
+	svcIdentity, err := envoy.GetServiceIdentityFromProxyCertificate(certCommonName)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error getting service identity from proxy certificate CN %s", certCommonName)
+		return destination
+	}
+	destination.ServiceIdentity = svcIdentity.String()
+	destination.Namespace = svcIdentity.ToK8sServiceAccount().Namespace
+
+	proxy, ok := s.proxyRegistry.ListConnectedProxies()[certCommonName]
+	if !ok {
+		log.Warn().Msgf("Proxy with certificate CN %s streaming access logs is not connected, its services are unknown", certCommonName)
+		return destination
+	}
+	services, err := s.proxyRegistry.ListProxyServices(proxy)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error listing services of proxy with certificate CN %s", certCommonName)
+		return destination
+	}
+	for _, svc := range services {
+		destination.Services = append(destination.Services, svc.Name)
+	}
+	return destination
+}
+
+// newDenial returns the denial corresponding to the given access log entry, and whether the request was denied by
+// the RBAC policies, in which case it was either rejected, or only denied by the shadow policies and allowed through
+func newDenial(entry *xds_accesslog_data.HTTPAccessLogEntry) (Denial, bool) {
+	common := entry.GetCommonProperties()
+
+	shadow := common.GetMetadata().GetFilterMetadata()[rbacFilterName].GetFields()[shadowEngineResultKey].GetStringValue() == shadowEngineResultDenied
+	enforced := strings.HasPrefix(entry.GetResponse().GetResponseCodeDetails(), rbacAccessDeniedDetailsPrefix)
+	if !shadow && !enforced {
+		return Denial{}, false
+	}
+
+	denial := Denial{
+		Method:    entry.GetRequest().GetRequestMethod().String(),
+		Authority: entry.GetRequest().GetAuthority(),
+		Path:      entry.GetRequest().GetPath(),
+		Shadow:    !enforced,
+	}
+	if startTime, err := ptypes.Timestamp(common.GetStartTime()); err == nil {
+		denial.Time = startTime
+	}
+	for _, san := range common.GetTlsProperties().GetPeerCertificateProperties().GetSubjectAltName() {
+		if principal := san.GetUri() + san.GetDns(); principal != "" {
+			denial.Principal = principal
+			break
+		}
+	}
+	return denial, true
+}
@@ -0,0 +1,100 @@
+package als
+
+import (
+	"testing"
+	"time"
+
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_accesslog_data "github.com/envoyproxy/go-control-plane/envoy/data/accesslog/v3"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	tassert "github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestNewDenial(t *testing.T) {
+	startTime := time.Date(2021, time.June, 1, 12, 0, 0, 0, time.UTC)
+	startTimestamp, _ := ptypes.TimestampProto(startTime)
+
+	newEntry := func(responseCodeDetails string, shadowResult string) *xds_accesslog_data.HTTPAccessLogEntry {
+		entry := &xds_accesslog_data.HTTPAccessLogEntry{
+			CommonProperties: &xds_accesslog_data.AccessLogCommon{
+				StartTime: startTimestamp,
+				TlsProperties: &xds_accesslog_data.TLSProperties{
+					PeerCertificateProperties: &xds_accesslog_data.TLSProperties_CertificateProperties{
+						SubjectAltName: []*xds_accesslog_data.TLSProperties_CertificateProperties_SubjectAltName{
+							{San: &xds_accesslog_data.TLSProperties_CertificateProperties_SubjectAltName_Uri{Uri: "spiffe://cluster.local/ns/bookbuyer/sa/bookbuyer"}},
+						},
+					},
+				},
+			},
+			Request: &xds_accesslog_data.HTTPRequestProperties{
+				RequestMethod: xds_core.RequestMethod_GET,
+				Authority:     "bookstore.bookstore",
+				Path:          "/books-bought",
+			},
+			Response: &xds_accesslog_data.HTTPResponseProperties{
+				ResponseCode:        &wrappers.UInt32Value{Value: 200},
+				ResponseCodeDetails: responseCodeDetails,
+			},
+		}
+		if shadowResult != "" {
+			entry.CommonProperties.Metadata = &xds_core.Metadata{
+				FilterMetadata: map[string]*structpb.Struct{
+					rbacFilterName: {
+						Fields: map[string]*structpb.Value{
+							shadowEngineResultKey: structpb.NewStringValue(shadowResult),
+						},
+					},
+				},
+			}
+		}
+		return entry
+	}
+
+	testCases := []struct {
+		name           string
+		entry          *xds_accesslog_data.HTTPAccessLogEntry
+		expectedDenied bool
+		expectedShadow bool
+	}{
+		{
+			name:           "request allowed",
+			entry:          newEntry("via_upstream", "allowed"),
+			expectedDenied: false,
+		},
+		{
+			name:           "request denied by the RBAC rules",
+			entry:          newEntry("rbac_access_denied_matched_policy[none]", ""),
+			expectedDenied: true,
+			expectedShadow: false,
+		},
+		{
+			name:           "request denied by the RBAC shadow rules",
+			entry:          newEntry("via_upstream", shadowEngineResultDenied),
+			expectedDenied: true,
+			expectedShadow: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			denial, denied := newDenial(tc.entry)
+			assert.Equal(tc.expectedDenied, denied)
+			if !denied {
+				return
+			}
+
+			assert.Equal(Denial{
+				Time:      startTime,
+				Principal: "spiffe://cluster.local/ns/bookbuyer/sa/bookbuyer",
+				Method:    "GET",
+				Authority: "bookstore.bookstore",
+				Path:      "/books-bought",
+				Shadow:    tc.expectedShadow,
+			}, denial)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Package als implements the Envoy gRPC Access Log Service (ALS) receiving the inbound requests denied by the RBAC
+// policies of the proxies, and aggregates them into a log of the recent authorization denials per service.
+package als
+
+import (
+	"sync"
+	"time"
+
+	"github.com/openservicemesh/osm/pkg/envoy/registry"
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var (
+	log = logger.New("envoy/als")
+)
+
+const (
+	// DenialLogName is the name of the gRPC access log proxies stream the inbound requests denied by RBAC policies to
+	DenialLogName = "osm-rbac-denials"
+
+	// DefaultMaxDenials is the default number of recent denials retained by a DenialLog
+	DefaultMaxDenials = 1000
+
+	// rbacFilterName is the name of the HTTP RBAC filter, the key of the dynamic metadata it sets
+	rbacFilterName = "envoy.filters.http.rbac"
+
+	// shadowEngineResultKey is the key of the dynamic metadata of the HTTP RBAC filter holding the result of the shadow rules
+	shadowEngineResultKey = "shadow_engine_result"
+
+	// shadowEngineResultDenied is the result of the shadow rules when they deny a request
+	shadowEngineResultDenied = "denied"
+
+	// rbacAccessDeniedDetailsPrefix is the prefix of the response code details of requests denied by the HTTP RBAC filter
+	rbacAccessDeniedDetailsPrefix = "rbac_access_denied"
+
+	// denialsNamespaceQueryKey is the query parameter of the denials handler filtering the denials by namespace
+	denialsNamespaceQueryKey = "namespace"
+
+	// denialsServiceQueryKey is the query parameter of the denials handler filtering the denials by service
+	denialsServiceQueryKey = "service"
+)
+
+// Denial is the type used to represent an inbound request denied by the RBAC policies of a proxy.
+type Denial struct {
+	// Time is the time the request was received
+	Time time.Time `json:"time"`
+
+	// Namespace is the namespace of the proxy which denied the request
+	Namespace string `json:"namespace"`
+
+	// Services are the names of the services in Namespace of the proxy which denied the request
+	Services []string `json:"services"`
+
+	// ServiceIdentity is the service identity of the proxy which denied the request
+	ServiceIdentity string `json:"serviceIdentity"`
+
+	// Principal is the Subject Alternative Name of the certificate presented by the downstream, empty if it did not
+	// present any
+	Principal string `json:"principal,omitempty"`
+
+	// Method, Authority and Path identify the route of the request
+	Method    string `json:"method"`
+	Authority string `json:"authority"`
+	Path      string `json:"path"`
+
+	// Shadow indicates the request was denied by the RBAC policies in shadow mode, and allowed through
+	Shadow bool `json:"shadow"`
+}
+
+// DenialLog is a bounded log of the most recent inbound requests denied by the RBAC policies of the proxies.
+type DenialLog struct {
+	mutex   sync.RWMutex
+	denials []Denial
+	next    int
+	full    bool
+}
+
+// Server implements the Envoy gRPC Access Log Service, recording the denied requests streamed by the proxies in a
+// DenialLog.
+type Server struct {
+	proxyRegistry *registry.ProxyRegistry
+	denialLog     *DenialLog
+}
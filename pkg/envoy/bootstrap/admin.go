@@ -0,0 +1,183 @@
+package bootstrap
+
+import (
+	"net/http"
+	"time"
+
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/errcode"
+)
+
+const (
+	// AdminProxyListenerName is the name of the listener serving the allowed admin endpoints when the admin
+	// interface is bound to a unix domain socket
+	AdminProxyListenerName = "admin_proxy_listener"
+
+	// AdminClusterName is the name of the cluster targeting the admin interface bound to a unix domain socket
+	AdminClusterName = "envoy_admin_cluster"
+
+	// adminSocketMode is the file mode of the unix domain socket the admin interface binds to, only accessible to
+	// the proxy's user
+	adminSocketMode = 0600
+)
+
+// getAdminAddress returns the address the admin interface binds to, the unix domain socket at
+// config.AdminSocketPath if set, localhost on config.AdminPort otherwise
+func getAdminAddress(config Config) *xds_core.Address {
+	if config.AdminSocketPath != "" {
+		return &xds_core.Address{
+			Address: &xds_core.Address_Pipe{
+				Pipe: &xds_core.Pipe{
+					Path: config.AdminSocketPath,
+					Mode: adminSocketMode,
+				},
+			},
+		}
+	}
+
+	return getLocalhostAddress(config.AdminPort)
+}
+
+// getLocalhostAddress returns the localhost address on the given port
+func getLocalhostAddress(port uint32) *xds_core.Address {
+	return &xds_core.Address{
+		Address: &xds_core.Address_SocketAddress{
+			SocketAddress: &xds_core.SocketAddress{
+				Address: constants.LocalhostIPAddress,
+				PortSpecifier: &xds_core.SocketAddress_PortValue{
+					PortValue: port,
+				},
+			},
+		},
+	}
+}
+
+// getAdminProxyResources returns the listener serving the GET requests to config.AdminProxyPaths on localhost on
+// config.AdminPort, and the cluster forwarding them to the admin interface bound to config.AdminSocketPath.
+// Requests to other paths are rejected with a 404 response.
+func getAdminProxyResources(config Config) (*xds_listener.Listener, *xds_cluster.Cluster, error) {
+	var routes []*xds_route.Route
+	for _, path := range config.AdminProxyPaths {
+		routes = append(routes, &xds_route.Route{
+			Match: &xds_route.RouteMatch{
+				PathSpecifier: &xds_route.RouteMatch_Path{
+					Path: path,
+				},
+				Headers: []*xds_route.HeaderMatcher{
+					{
+						Name: ":method",
+						HeaderMatchSpecifier: &xds_route.HeaderMatcher_ExactMatch{
+							ExactMatch: http.MethodGet,
+						},
+					},
+				},
+			},
+			Action: &xds_route.Route_Route{
+				Route: &xds_route.RouteAction{
+					ClusterSpecifier: &xds_route.RouteAction_Cluster{
+						Cluster: AdminClusterName,
+					},
+				},
+			},
+		})
+	}
+	routes = append(routes, &xds_route.Route{
+		Match: &xds_route.RouteMatch{
+			PathSpecifier: &xds_route.RouteMatch_Prefix{
+				Prefix: "/",
+			},
+		},
+		Action: &xds_route.Route_DirectResponse{
+			DirectResponse: &xds_route.DirectResponseAction{
+				Status: http.StatusNotFound,
+			},
+		},
+	})
+
+	connManager := &xds_hcm.HttpConnectionManager{
+		CodecType:  xds_hcm.HttpConnectionManager_AUTO,
+		StatPrefix: "admin_proxy",
+		RouteSpecifier: &xds_hcm.HttpConnectionManager_RouteConfig{
+			RouteConfig: &xds_route.RouteConfiguration{
+				Name: "admin_proxy_route",
+				VirtualHosts: []*xds_route.VirtualHost{
+					{
+						Name:    "admin_proxy_virtual_host",
+						Domains: []string{"*"},
+						Routes:  routes,
+					},
+				},
+			},
+		},
+		HttpFilters: []*xds_hcm.HttpFilter{
+			{
+				Name: wellknown.Router,
+			},
+		},
+	}
+	pbConnManager, err := ptypes.MarshalAny(connManager)
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingXDSResource)).
+			Msgf("Error marshaling HttpConnectionManager struct into an anypb.Any message")
+		return nil, nil, err
+	}
+
+	listener := &xds_listener.Listener{
+		Name:    AdminProxyListenerName,
+		Address: getLocalhostAddress(config.AdminPort),
+		FilterChains: []*xds_listener.FilterChain{
+			{
+				Filters: []*xds_listener.Filter{
+					{
+						Name: wellknown.HTTPConnectionManager,
+						ConfigType: &xds_listener.Filter_TypedConfig{
+							TypedConfig: pbConnManager,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	cluster := &xds_cluster.Cluster{
+		Name:           AdminClusterName,
+		ConnectTimeout: durationpb.New(time.Second),
+		ClusterDiscoveryType: &xds_cluster.Cluster_Type{
+			Type: xds_cluster.Cluster_STATIC,
+		},
+		LoadAssignment: &xds_endpoint.ClusterLoadAssignment{
+			ClusterName: AdminClusterName,
+			Endpoints: []*xds_endpoint.LocalityLbEndpoints{
+				{
+					LbEndpoints: []*xds_endpoint.LbEndpoint{
+						{
+							HostIdentifier: &xds_endpoint.LbEndpoint_Endpoint{
+								Endpoint: &xds_endpoint.Endpoint{
+									Address: &xds_core.Address{
+										Address: &xds_core.Address_Pipe{
+											Pipe: &xds_core.Pipe{
+												Path: config.AdminSocketPath,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	return listener, cluster, nil
+}
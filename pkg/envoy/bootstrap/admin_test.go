@@ -0,0 +1,114 @@
+package bootstrap
+
+import (
+	"net/http"
+	"testing"
+
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestGetAdminAddress(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   Config
+		expected *xds_core.Address
+	}{
+		{
+			name:   "admin bound to localhost",
+			config: Config{AdminPort: 15000},
+			expected: &xds_core.Address{
+				Address: &xds_core.Address_SocketAddress{
+					SocketAddress: &xds_core.SocketAddress{
+						Address: "127.0.0.1",
+						PortSpecifier: &xds_core.SocketAddress_PortValue{
+							PortValue: 15000,
+						},
+					},
+				},
+			},
+		},
+		{
+			name:   "admin bound to a unix domain socket",
+			config: Config{AdminPort: 15000, AdminSocketPath: "/var/run/osm/admin.sock"},
+			expected: &xds_core.Address{
+				Address: &xds_core.Address_Pipe{
+					Pipe: &xds_core.Pipe{
+						Path: "/var/run/osm/admin.sock",
+						Mode: 0600,
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expected, getAdminAddress(tc.config))
+		})
+	}
+}
+
+func TestGetAdminProxyResources(t *testing.T) {
+	assert := tassert.New(t)
+
+	config := Config{
+		AdminPort:       15000,
+		AdminSocketPath: "/var/run/osm/admin.sock",
+		AdminProxyPaths: []string{"/stats/prometheus", "/ready"},
+	}
+
+	listener, cluster, err := getAdminProxyResources(config)
+	assert.Nil(err)
+
+	assert.Equal(AdminProxyListenerName, listener.Name)
+	assert.Equal("127.0.0.1", listener.Address.GetSocketAddress().Address)
+	assert.Equal(uint32(15000), listener.Address.GetSocketAddress().GetPortValue())
+	assert.Len(listener.FilterChains, 1)
+	assert.Len(listener.FilterChains[0].Filters, 1)
+
+	connManager := &xds_hcm.HttpConnectionManager{}
+	err = ptypes.UnmarshalAny(listener.FilterChains[0].Filters[0].GetTypedConfig(), connManager)
+	assert.Nil(err)
+
+	routes := connManager.GetRouteConfig().VirtualHosts[0].Routes
+	assert.Len(routes, 3)
+	for i, path := range config.AdminProxyPaths {
+		assert.Equal(path, routes[i].Match.GetPath())
+		assert.Equal(":method", routes[i].Match.Headers[0].Name)
+		assert.Equal(http.MethodGet, routes[i].Match.Headers[0].GetExactMatch())
+		assert.Equal(AdminClusterName, routes[i].GetRoute().GetCluster())
+	}
+	assert.Equal("/", routes[2].Match.GetPrefix())
+	assert.Equal(&xds_route.DirectResponseAction{Status: http.StatusNotFound}, routes[2].GetDirectResponse())
+
+	assert.Equal(AdminClusterName, cluster.Name)
+	endpoint := cluster.LoadAssignment.Endpoints[0].LbEndpoints[0].GetEndpoint()
+	assert.Equal("/var/run/osm/admin.sock", endpoint.Address.GetPipe().Path)
+}
+
+func TestBuildFromConfigWithAdminSocket(t *testing.T) {
+	assert := tassert.New(t)
+
+	bootstrapConfig, err := BuildFromConfig(Config{
+		NodeID:          "foo.bar.co.uk",
+		AdminPort:       15000,
+		AdminSocketPath: "/var/run/osm/admin.sock",
+		AdminProxyPaths: []string{"/stats/prometheus"},
+		XDSClusterName:  "osm-controller",
+		XDSHost:         "osm-controller.osm-system.svc.cluster.local",
+		XDSPort:         15128,
+	})
+	assert.Nil(err)
+
+	assert.Equal("/var/run/osm/admin.sock", bootstrapConfig.Admin.Address.GetPipe().Path)
+	assert.Len(bootstrapConfig.StaticResources.Listeners, 1)
+	assert.Equal(AdminProxyListenerName, bootstrapConfig.StaticResources.Listeners[0].Name)
+	assert.Len(bootstrapConfig.StaticResources.Clusters, 2)
+	assert.Equal("osm-controller", bootstrapConfig.StaticResources.Clusters[0].Name)
+	assert.Equal(AdminClusterName, bootstrapConfig.StaticResources.Clusters[1].Name)
+}
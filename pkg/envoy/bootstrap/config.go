@@ -15,7 +15,6 @@ import (
 	"github.com/golang/protobuf/ptypes/any"
 	"google.golang.org/protobuf/types/known/durationpb"
 
-	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/envoy"
 	"github.com/openservicemesh/osm/pkg/errcode"
 )
@@ -98,16 +97,7 @@ func BuildFromConfig(config Config) (*xds_bootstrap.Bootstrap, error) {
 					},
 				},
 			},
-			Address: &xds_core.Address{
-				Address: &xds_core.Address_SocketAddress{
-					SocketAddress: &xds_core.SocketAddress{
-						Address: constants.LocalhostIPAddress,
-						PortSpecifier: &xds_core.SocketAddress_PortValue{
-							PortValue: config.AdminPort,
-						},
-					},
-				},
-			},
+			Address: getAdminAddress(config),
 		},
 		DynamicResources: &xds_bootstrap.Bootstrap_DynamicResources{
 			AdsConfig: &xds_core.ApiConfigSource{
@@ -185,5 +175,21 @@ func BuildFromConfig(config Config) (*xds_bootstrap.Bootstrap, error) {
 		},
 	}
 
+	overloadManager, err := getOverloadManager(config)
+	if err != nil {
+		return nil, err
+	}
+	bootstrap.OverloadManager = overloadManager
+	bootstrap.StatsConfig = getStatsConfig(config)
+
+	if config.AdminSocketPath != "" {
+		adminProxyListener, adminCluster, err := getAdminProxyResources(config)
+		if err != nil {
+			return nil, err
+		}
+		bootstrap.StaticResources.Listeners = append(bootstrap.StaticResources.Listeners, adminProxyListener)
+		bootstrap.StaticResources.Clusters = append(bootstrap.StaticResources.Clusters, adminCluster)
+	}
+
 	return bootstrap, nil
 }
@@ -0,0 +1,82 @@
+package bootstrap
+
+import (
+	"time"
+
+	xds_overload "github.com/envoyproxy/go-control-plane/envoy/config/overload/v3"
+	xds_fixed_heap "github.com/envoyproxy/go-control-plane/envoy/extensions/resource_monitors/fixed_heap/v3"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/openservicemesh/osm/pkg/errcode"
+)
+
+const (
+	// fixedHeapResourceMonitor is the name of the resource monitor tracking the heap usage against a fixed maximum
+	fixedHeapResourceMonitor = "envoy.resource_monitors.fixed_heap"
+
+	// shrinkHeapOverloadAction is the name of the overload action releasing the free memory of the heap to the system
+	shrinkHeapOverloadAction = "envoy.overload_actions.shrink_heap"
+
+	// stopAcceptingRequestsOverloadAction is the name of the overload action rejecting new requests
+	stopAcceptingRequestsOverloadAction = "envoy.overload_actions.stop_accepting_requests"
+
+	// overloadManagerRefreshInterval is the interval at which the overload manager refreshes the heap usage
+	overloadManagerRefreshInterval = 250 * time.Millisecond
+)
+
+// getOverloadManager returns the overload manager monitoring the heap usage against config.MaxHeapSizeBytes, nil if
+// config.MaxHeapSizeBytes is unset. The overload actions are only configured for non-zero thresholds.
+func getOverloadManager(config Config) (*xds_overload.OverloadManager, error) {
+	if config.MaxHeapSizeBytes == 0 {
+		return nil, nil
+	}
+
+	pbFixedHeap, err := ptypes.MarshalAny(&xds_fixed_heap.FixedHeapConfig{
+		MaxHeapSizeBytes: config.MaxHeapSizeBytes,
+	})
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingXDSResource)).
+			Msgf("Error marshaling FixedHeapConfig struct into an anypb.Any message")
+		return nil, err
+	}
+
+	overloadManager := &xds_overload.OverloadManager{
+		RefreshInterval: durationpb.New(overloadManagerRefreshInterval),
+		ResourceMonitors: []*xds_overload.ResourceMonitor{
+			{
+				Name: fixedHeapResourceMonitor,
+				ConfigType: &xds_overload.ResourceMonitor_TypedConfig{
+					TypedConfig: pbFixedHeap,
+				},
+			},
+		},
+	}
+
+	if config.ShrinkHeapThreshold > 0 {
+		overloadManager.Actions = append(overloadManager.Actions, getHeapOverloadAction(shrinkHeapOverloadAction, config.ShrinkHeapThreshold))
+	}
+	if config.StopAcceptingRequestsThreshold > 0 {
+		overloadManager.Actions = append(overloadManager.Actions, getHeapOverloadAction(stopAcceptingRequestsOverloadAction, config.StopAcceptingRequestsThreshold))
+	}
+
+	return overloadManager, nil
+}
+
+// getHeapOverloadAction returns the overload action with the given name, triggered when the heap usage reaches the
+// given fraction of the maximum heap size
+func getHeapOverloadAction(name string, threshold float64) *xds_overload.OverloadAction {
+	return &xds_overload.OverloadAction{
+		Name: name,
+		Triggers: []*xds_overload.Trigger{
+			{
+				Name: fixedHeapResourceMonitor,
+				TriggerOneof: &xds_overload.Trigger_Threshold{
+					Threshold: &xds_overload.ThresholdTrigger{
+						Value: threshold,
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,80 @@
+package bootstrap
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/utils"
+)
+
+func TestGetOverloadManager(t *testing.T) {
+	testCases := []struct {
+		name         string
+		config       Config
+		expectedYAML string
+	}{
+		{
+			name:         "overload manager disabled",
+			config:       Config{},
+			expectedYAML: "",
+		},
+		{
+			name: "overload manager with shrink heap and stop accepting requests actions",
+			config: Config{
+				MaxHeapSizeBytes:               1073741824,
+				ShrinkHeapThreshold:            0.95,
+				StopAcceptingRequestsThreshold: 0.98,
+			},
+			expectedYAML: `actions:
+- name: envoy.overload_actions.shrink_heap
+  triggers:
+  - name: envoy.resource_monitors.fixed_heap
+    threshold:
+      value: 0.95
+- name: envoy.overload_actions.stop_accepting_requests
+  triggers:
+  - name: envoy.resource_monitors.fixed_heap
+    threshold:
+      value: 0.98
+refresh_interval: 0.250s
+resource_monitors:
+- name: envoy.resource_monitors.fixed_heap
+  typed_config:
+    '@type': type.googleapis.com/envoy.extensions.resource_monitors.fixed_heap.v3.FixedHeapConfig
+    max_heap_size_bytes: "1073741824"
+`,
+		},
+		{
+			name: "overload manager without actions",
+			config: Config{
+				MaxHeapSizeBytes: 1073741824,
+			},
+			expectedYAML: `refresh_interval: 0.250s
+resource_monitors:
+- name: envoy.resource_monitors.fixed_heap
+  typed_config:
+    '@type': type.googleapis.com/envoy.extensions.resource_monitors.fixed_heap.v3.FixedHeapConfig
+    max_heap_size_bytes: "1073741824"
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			overloadManager, err := getOverloadManager(tc.config)
+			assert.Nil(err)
+
+			if tc.expectedYAML == "" {
+				assert.Nil(overloadManager)
+				return
+			}
+
+			actualYAML, err := utils.ProtoToYAML(overloadManager)
+			assert.Nil(err)
+			assert.Equal(tc.expectedYAML, string(actualYAML))
+		})
+	}
+}
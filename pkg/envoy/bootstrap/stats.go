@@ -0,0 +1,58 @@
+package bootstrap
+
+import (
+	xds_metrics "github.com/envoyproxy/go-control-plane/envoy/config/metrics/v3"
+	xds_matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+)
+
+// getStatsConfig returns the configuration of the stats generated by Envoy, nil if the default configuration is used.
+// Only the stats matching config.StatsInclusionRegexes are generated if set, otherwise the stats matching
+// config.StatsExclusionRegexes are not generated.
+func getStatsConfig(config Config) *xds_metrics.StatsConfig {
+	if len(config.StatsInclusionRegexes) == 0 && len(config.StatsExclusionRegexes) == 0 && len(config.StatsTags) == 0 {
+		return nil
+	}
+
+	statsConfig := &xds_metrics.StatsConfig{}
+
+	for _, tag := range config.StatsTags {
+		statsConfig.StatsTags = append(statsConfig.StatsTags, &xds_metrics.TagSpecifier{
+			TagName: tag.Name,
+			TagValue: &xds_metrics.TagSpecifier_Regex{
+				Regex: tag.Regex,
+			},
+		})
+	}
+
+	if len(config.StatsInclusionRegexes) > 0 {
+		statsConfig.StatsMatcher = &xds_metrics.StatsMatcher{
+			StatsMatcher: &xds_metrics.StatsMatcher_InclusionList{
+				InclusionList: getRegexListMatcher(config.StatsInclusionRegexes),
+			},
+		}
+	} else if len(config.StatsExclusionRegexes) > 0 {
+		statsConfig.StatsMatcher = &xds_metrics.StatsMatcher{
+			StatsMatcher: &xds_metrics.StatsMatcher_ExclusionList{
+				ExclusionList: getRegexListMatcher(config.StatsExclusionRegexes),
+			},
+		}
+	}
+
+	return statsConfig
+}
+
+// getRegexListMatcher returns the matcher matching the strings matching any of the given regular expressions
+func getRegexListMatcher(regexes []string) *xds_matcher.ListStringMatcher {
+	listMatcher := &xds_matcher.ListStringMatcher{}
+	for _, regex := range regexes {
+		listMatcher.Patterns = append(listMatcher.Patterns, &xds_matcher.StringMatcher{
+			MatchPattern: &xds_matcher.StringMatcher_SafeRegex{
+				SafeRegex: &xds_matcher.RegexMatcher{
+					EngineType: &xds_matcher.RegexMatcher_GoogleRe2{GoogleRe2: &xds_matcher.RegexMatcher_GoogleRE2{}},
+					Regex:      regex,
+				},
+			},
+		})
+	}
+	return listMatcher
+}
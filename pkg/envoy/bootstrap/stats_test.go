@@ -0,0 +1,88 @@
+package bootstrap
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/utils"
+)
+
+func TestGetStatsConfig(t *testing.T) {
+	testCases := []struct {
+		name         string
+		config       Config
+		expectedYAML string
+	}{
+		{
+			name:         "default stats config",
+			config:       Config{},
+			expectedYAML: "",
+		},
+		{
+			name: "inclusion list and tags",
+			config: Config{
+				StatsInclusionRegexes: []string{"^cluster\\..*\\.upstream_rq_.*"},
+				StatsTags: []StatsTag{
+					{Name: "route", Regex: "^http\\.(route\\.(.+?)\\.)"},
+				},
+			},
+			expectedYAML: `stats_matcher:
+  inclusion_list:
+    patterns:
+    - safe_regex:
+        google_re2: {}
+        regex: ^cluster\..*\.upstream_rq_.*
+stats_tags:
+- regex: ^http\.(route\.(.+?)\.)
+  tag_name: route
+`,
+		},
+		{
+			name: "inclusion list takes precedence over exclusion list",
+			config: Config{
+				StatsInclusionRegexes: []string{"^cluster\\..*"},
+				StatsExclusionRegexes: []string{"^listener\\..*"},
+			},
+			expectedYAML: `stats_matcher:
+  inclusion_list:
+    patterns:
+    - safe_regex:
+        google_re2: {}
+        regex: ^cluster\..*
+`,
+		},
+		{
+			name: "exclusion list",
+			config: Config{
+				StatsExclusionRegexes: []string{"^listener\\..*", ".*\\.upstream_cx_.*"},
+			},
+			expectedYAML: `stats_matcher:
+  exclusion_list:
+    patterns:
+    - safe_regex:
+        google_re2: {}
+        regex: ^listener\..*
+    - safe_regex:
+        google_re2: {}
+        regex: .*\.upstream_cx_.*
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			statsConfig := getStatsConfig(tc.config)
+			if tc.expectedYAML == "" {
+				assert.Nil(statsConfig)
+				return
+			}
+
+			actualYAML, err := utils.ProtoToYAML(statsConfig)
+			assert.Nil(err)
+			assert.Equal(tc.expectedYAML, string(actualYAML))
+		})
+	}
+}
@@ -12,6 +12,34 @@ type Config struct {
 	// Admin port is the Envoy admin port
 	AdminPort uint32
 
+	// AdminSocketPath is the path of the unix domain socket the Envoy admin interface binds to. When set, the admin
+	// interface is not bound to AdminPort, which instead serves only the admin endpoints in AdminProxyPaths.
+	AdminSocketPath string
+
+	// AdminProxyPaths are the paths of the admin endpoints served on AdminPort when the admin interface is bound
+	// to AdminSocketPath
+	AdminProxyPaths []string
+
+	// MaxHeapSizeBytes is the maximum size in bytes of the Envoy heap monitored by the overload manager, which is
+	// disabled if unset
+	MaxHeapSizeBytes uint64
+
+	// ShrinkHeapThreshold is the fraction of MaxHeapSizeBytes above which Envoy releases its free memory to the system
+	ShrinkHeapThreshold float64
+
+	// StopAcceptingRequestsThreshold is the fraction of MaxHeapSizeBytes above which Envoy stops accepting requests
+	StopAcceptingRequestsThreshold float64
+
+	// StatsInclusionRegexes are the regular expressions matching the names of the only stats generated by Envoy.
+	// Takes precedence over StatsExclusionRegexes.
+	StatsInclusionRegexes []string
+
+	// StatsExclusionRegexes are the regular expressions matching the names of the stats not generated by Envoy
+	StatsExclusionRegexes []string
+
+	// StatsTags are the tags extracted from the names of the stats, in addition to Envoy's default tags
+	StatsTags []StatsTag
+
 	// XDSClusterName is the name of the XDS cluster to connect to
 	XDSClusterName string
 
@@ -34,3 +62,12 @@ type Config struct {
 	// PrivateKey is the private key for the certificate used by the proxy to connect to the XDS cluster
 	PrivateKey []byte
 }
+
+// StatsTag is the type used to represent a tag extracted from the names of the Envoy stats
+type StatsTag struct {
+	// Name is the name of the tag
+	Name string
+
+	// Regex is the regular expression extracting the tag from the names of the stats
+	Regex string
+}
@@ -7,18 +7,26 @@ import (
 	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	xds_proxy_protocol "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/proxy_protocol/v3"
+	xds_raw_buffer "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/raw_buffer/v3"
+	xds_type "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/pkg/errors"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
+	corev1 "k8s.io/api/core/v1"
 
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/catalog"
 	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/endpoint"
 	"github.com/openservicemesh/osm/pkg/envoy"
 	"github.com/openservicemesh/osm/pkg/errcode"
 	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
@@ -26,6 +34,18 @@ import (
 const (
 	// clusterConnectTimeout is the timeout duration used by Envoy to timeout connections to the cluster
 	clusterConnectTimeout = 1 * time.Second
+
+	// dnsClusterTypeLogical is the cluster type of clusters resolved using logical DNS
+	dnsClusterTypeLogical = "logical_dns"
+
+	// dnsLookupFamilyV4Only is the lookup family resolving hostnames to IPv4 addresses only
+	dnsLookupFamilyV4Only = "v4_only"
+
+	// dnsLookupFamilyV6Only is the lookup family resolving hostnames to IPv6 addresses only
+	dnsLookupFamilyV6Only = "v6_only"
+
+	// upstreamProxyProtocolTransportSocket is the name of the transport socket sending a PROXY protocol header
+	upstreamProxyProtocolTransportSocket = "envoy.transport_sockets.upstream_proxy_protocol"
 )
 
 // replacer used to configure an Envoy cluster's altStatName
@@ -34,6 +54,10 @@ var replacer = strings.NewReplacer(".", "_", ":", "_")
 type clusterOptions struct {
 	permissive             bool
 	withActiveHealthChecks bool
+	trustDomain            string
+	tcpProtocolDetection   bool
+	servicePort            uint32
+	retryBudget            *trafficpolicy.RetryBudget
 }
 
 // clusterOption is type of function that edits the defaults of the options struct.
@@ -50,6 +74,36 @@ func withActiveHealthChecks(o *clusterOptions) {
 	o.withActiveHealthChecks = true
 }
 
+// withTrustDomain is an option to advertise the trust domain of the proxy when connecting to upstream
+// clusters, so that multicluster gateways can validate the proxy's certificate against its trust domain.
+func withTrustDomain(trustDomain string) clusterOption {
+	return func(o *clusterOptions) {
+		o.trustDomain = trustDomain
+	}
+}
+
+// tcpProtocolDetection is an option to build the cluster proxying TCP traffic detected on ports of the upstream
+// service with protocol detection enabled. The cluster advertises the ALPN matched by the upstream proxy's TCP
+// filter chains, and shares the endpoints of the upstream service cluster.
+func tcpProtocolDetection(o *clusterOptions) {
+	o.tcpProtocolDetection = true
+}
+
+// forServicePort is an option to build the cluster backing a single port of an upstream service exposing multiple
+// ports. The cluster is named after the port, see service.GetClusterNameForPort, and its endpoints are those serving
+// the target port of the port.
+func forServicePort(port uint32) clusterOption {
+	return func(o *clusterOptions) {
+		o.servicePort = port
+	}
+}
+
+func withRetryBudget(retryBudget *trafficpolicy.RetryBudget) clusterOption {
+	return func(o *clusterOptions) {
+		o.retryBudget = retryBudget
+	}
+}
+
 // getUpstreamServiceCluster returns an Envoy Cluster corresponding to the given upstream service
 // Note: ServiceIdentity must be in the format "name.namespace" [https://github.com/openservicemesh/osm/issues/3188]
 func getUpstreamServiceCluster(downstreamIdentity identity.ServiceIdentity, upstreamSvc service.MeshService, opts ...clusterOption) (*xds_cluster.Cluster, error) {
@@ -63,14 +117,30 @@ func getUpstreamServiceCluster(downstreamIdentity identity.ServiceIdentity, upst
 		return nil, err
 	}
 
-	marshalledUpstreamTLSContext, err := ptypes.MarshalAny(
-		envoy.GetUpstreamTLSContext(downstreamIdentity, upstreamSvc))
+	upstreamTLSContext := envoy.GetUpstreamTLSContext(downstreamIdentity, upstreamSvc)
+	if o.trustDomain != "" {
+		// The trust domain ALPN takes precedence over the in-mesh ALPN so that the multicluster gateway
+		// matches the filter chain specific to the proxy's trust domain when one exists
+		upstreamTLSContext.CommonTlsContext.AlpnProtocols = append([]string{envoy.GetTrustDomainALPN(o.trustDomain)}, envoy.ALPNInMesh...)
+	}
+	if o.tcpProtocolDetection {
+		// The TCP ALPN takes precedence so that the upstream proxy matches its TCP filter chain for the port
+		alpnProtocols := append([]string{}, envoy.ALPNInMeshTCP...)
+		upstreamTLSContext.CommonTlsContext.AlpnProtocols = append(alpnProtocols, upstreamTLSContext.CommonTlsContext.AlpnProtocols...)
+	}
+
+	marshalledUpstreamTLSContext, err := ptypes.MarshalAny(upstreamTLSContext)
 	if err != nil {
 		return nil, err
 	}
 
+	clusterName := upstreamSvc.String()
+	if o.servicePort != 0 {
+		clusterName = upstreamSvc.PortClusterName(o.servicePort).String()
+	}
+
 	remoteCluster := &xds_cluster.Cluster{
-		Name:                          upstreamSvc.String(),
+		Name:                          clusterName,
 		ConnectTimeout:                ptypes.DurationProto(clusterConnectTimeout),
 		TypedExtensionProtocolOptions: HTTP2ProtocolOptions,
 		TransportSocket: &xds_core.TransportSocket{
@@ -92,12 +162,43 @@ func getUpstreamServiceCluster(downstreamIdentity identity.ServiceIdentity, upst
 		remoteCluster.LbPolicy = xds_cluster.Cluster_ROUND_ROBIN
 	}
 
+	if o.tcpProtocolDetection {
+		remoteCluster.Name = envoy.GetTCPClusterNameForServiceCluster(clusterName)
+		remoteCluster.TypedExtensionProtocolOptions = nil
+		if remoteCluster.EdsClusterConfig != nil {
+			// Share the endpoints of the upstream service cluster
+			remoteCluster.EdsClusterConfig.ServiceName = clusterName
+		}
+		return remoteCluster, nil
+	}
+
 	if o.withActiveHealthChecks {
 		enableHealthChecksOnCluster(remoteCluster, upstreamSvc)
 	}
+	if o.retryBudget != nil {
+		remoteCluster.CircuitBreakers = getRetryBudgetCircuitBreakers(o.retryBudget)
+	}
 	return remoteCluster, nil
 }
 
+// getRetryBudgetCircuitBreakers returns the circuit breakers of a cluster bounding the concurrent retries to the
+// given budget. The remaining retries are tracked so that the budget left is exposed in the cluster's stats, along
+// with the retries overflowing it in upstream_rq_retry_overflow.
+func getRetryBudgetCircuitBreakers(retryBudget *trafficpolicy.RetryBudget) *xds_cluster.CircuitBreakers {
+	return &xds_cluster.CircuitBreakers{
+		Thresholds: []*xds_cluster.CircuitBreakers_Thresholds{
+			{
+				Priority: xds_core.RoutingPriority_DEFAULT,
+				RetryBudget: &xds_cluster.CircuitBreakers_Thresholds_RetryBudget{
+					BudgetPercent:       &xds_type.Percent{Value: float64(retryBudget.BudgetPercent)},
+					MinRetryConcurrency: wrapperspb.UInt32(retryBudget.MinRetryConcurrency),
+				},
+				TrackRemaining: true,
+			},
+		},
+	}
+}
+
 // getMulticlusterGatewayUpstreamServiceCluster returns an Envoy Cluster corresponding to the given upstream service for the multicluster gateway
 func getMulticlusterGatewayUpstreamServiceCluster(catalog catalog.MeshCataloger, upstreamSvc service.MeshService, opts ...clusterOption) (*xds_cluster.Cluster, error) {
 	o := &clusterOptions{}
@@ -154,6 +255,32 @@ func getMulticlusterGatewayUpstreamServiceCluster(catalog catalog.MeshCataloger,
 	return remoteCluster, nil
 }
 
+// getMulticlusterGatewayMTLSUpstreamServiceCluster returns an Envoy Cluster used by the multicluster gateway to originate
+// mTLS connections to the given upstream service on behalf of downstreams from remote trust domains. The gateway presents
+// its own certificate to the upstream, rewriting the identity of the remote downstream to the gateway's identity.
+func getMulticlusterGatewayMTLSUpstreamServiceCluster(catalog catalog.MeshCataloger, gatewayIdentity identity.ServiceIdentity, upstreamSvc service.MeshService, opts ...clusterOption) (*xds_cluster.Cluster, error) {
+	cluster, err := getMulticlusterGatewayUpstreamServiceCluster(catalog, upstreamSvc, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	marshalledUpstreamTLSContext, err := ptypes.MarshalAny(
+		envoy.GetUpstreamTLSContext(gatewayIdentity, upstreamSvc))
+	if err != nil {
+		return nil, err
+	}
+
+	cluster.Name = envoy.GetMulticlusterGatewayMTLSClusterName(upstreamSvc)
+	cluster.LoadAssignment.ClusterName = cluster.Name
+	cluster.TransportSocket = &xds_core.TransportSocket{
+		Name: wellknown.TransportSocketTls,
+		ConfigType: &xds_core.TransportSocket_TypedConfig{
+			TypedConfig: marshalledUpstreamTLSContext,
+		},
+	}
+	return cluster, nil
+}
+
 func enableHealthChecksOnCluster(cluster *xds_cluster.Cluster, upstreamSvc service.MeshService) {
 	cluster.HealthChecks = []*xds_core.HealthCheck{
 		{
@@ -179,8 +306,105 @@ func enableHealthChecksOnCluster(cluster *xds_cluster.Cluster, upstreamSvc servi
 	}
 }
 
-// getLocalServiceCluster returns an Envoy Cluster corresponding to the local service
-func getLocalServiceCluster(catalog catalog.MeshCataloger, proxyServiceName service.MeshService, clusterName string) (*xds_cluster.Cluster, error) {
+// getUpstreamServicePortOptions returns the options of the clusters backing the given upstream service: a single
+// cluster for a service exposing a single port, and a cluster per port for a service exposing multiple ports
+func getUpstreamServicePortOptions(catalog catalog.MeshCataloger, upstreamSvc service.MeshService) [][]clusterOption {
+	ports, err := catalog.ListServicePorts(upstreamSvc)
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGettingServicePorts)).
+			Msgf("Failed to get ports for service %s, building a single cluster for the service", upstreamSvc)
+	}
+	if len(ports) <= 1 {
+		return [][]clusterOption{nil}
+	}
+
+	var portOpts [][]clusterOption
+	for _, port := range ports {
+		portOpts = append(portOpts, []clusterOption{forServicePort(port.Port)})
+	}
+	return portOpts
+}
+
+// getLocalServiceClusters returns the Envoy Clusters corresponding to the local service: a single cluster for a
+// service exposing a single port, and a cluster per port for a service exposing multiple ports, so that the inbound
+// traffic to each port is proxied to its own target port. Named target ports are resolved on the proxy's pod if known.
+func getLocalServiceClusters(catalog catalog.MeshCataloger, proxyService service.MeshService, pod *corev1.Pod) ([]*xds_cluster.Cluster, error) {
+	address := getLocalServiceAddress(pod)
+	ports, err := catalog.ListServicePorts(proxyService)
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGettingServicePorts)).
+			Msgf("Failed to get ports for service %s, building a single local cluster for the service", proxyService)
+	}
+	if len(ports) == 1 && ports[0].TargetPortName != "" && pod != nil {
+		// The target ports of the service's endpoints include the ports other pods resolve the named target port to
+		localCluster, err := newLocalServiceCluster(envoy.GetLocalClusterNameForService(proxyService), address, []uint32{getTargetPortForPod(ports[0], pod)})
+		if err != nil {
+			return nil, err
+		}
+		return []*xds_cluster.Cluster{localCluster}, nil
+	}
+	if len(ports) <= 1 {
+		localCluster, err := getLocalServiceCluster(catalog, proxyService, envoy.GetLocalClusterNameForService(proxyService), address)
+		if err != nil {
+			return nil, err
+		}
+		return []*xds_cluster.Cluster{localCluster}, nil
+	}
+
+	var localClusters []*xds_cluster.Cluster
+	for _, port := range ports {
+		localClusterName := envoy.GetLocalClusterNameForServiceCluster(proxyService.PortClusterName(port.Port).String())
+		localCluster, err := newLocalServiceCluster(localClusterName, address, []uint32{getTargetPortForPod(port, pod)})
+		if err != nil {
+			return nil, err
+		}
+		localClusters = append(localClusters, localCluster)
+	}
+	return localClusters, nil
+}
+
+// getLocalServiceAddress returns the address the local clusters proxy inbound traffic to on the given pod: localhost,
+// or the pod's IP when inbound traffic is proxied from the source IP of the clients, since connections from
+// non-local addresses to localhost are dropped
+func getLocalServiceAddress(pod *corev1.Pod) string {
+	if pod != nil && pod.Status.PodIP != "" && k8s.PreservesInboundSourceIP(pod) {
+		return pod.Status.PodIP
+	}
+	return constants.LocalhostIPAddress
+}
+
+// getTargetPortForPod returns the target port of the given service port on the given pod. A named target port is
+// resolved to the pod's container port with the same name, defaulting to the target port resolved for the service
+// when the pod is unknown or doesn't name any of its container ports accordingly.
+func getTargetPortForPod(port service.ServicePort, pod *corev1.Pod) uint32 {
+	if port.TargetPortName == "" || pod == nil {
+		return port.TargetPort
+	}
+	if targetPort, ok := k8s.GetNamedContainerPort(pod, port.TargetPortName); ok {
+		return targetPort
+	}
+	return port.TargetPort
+}
+
+// getLocalServiceCluster returns an Envoy Cluster corresponding to the local service, proxying traffic to the given address
+func getLocalServiceCluster(catalog catalog.MeshCataloger, proxyServiceName service.MeshService, clusterName string, address string) (*xds_cluster.Cluster, error) {
+	ports, err := catalog.GetTargetPortToProtocolMappingForService(proxyServiceName)
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGettingServicePorts)).
+			Msgf("Failed to get ports for service %s", proxyServiceName)
+		return nil, err
+	}
+
+	var targetPorts []uint32
+	for port := range ports {
+		targetPorts = append(targetPorts, port)
+	}
+	return newLocalServiceCluster(clusterName, address, targetPorts)
+}
+
+// newLocalServiceCluster returns an Envoy Cluster with the given name proxying traffic to the given target ports on the
+// given local address
+func newLocalServiceCluster(clusterName string, address string, targetPorts []uint32) (*xds_cluster.Cluster, error) {
 	HTTP2ProtocolOptions, err := envoy.GetHTTP2ProtocolOptions()
 	if err != nil {
 		return nil, err
@@ -207,14 +431,7 @@ func getLocalServiceCluster(catalog catalog.MeshCataloger, proxyServiceName serv
 		TypedExtensionProtocolOptions: HTTP2ProtocolOptions,
 	}
 
-	ports, err := catalog.GetTargetPortToProtocolMappingForService(proxyServiceName)
-	if err != nil {
-		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGettingServicePorts)).
-			Msgf("Failed to get ports for service %s", proxyServiceName)
-		return nil, err
-	}
-
-	for port := range ports {
+	for _, port := range targetPorts {
 		localityEndpoint := &xds_endpoint.LocalityLbEndpoints{
 			Locality: &xds_core.Locality{
 				Zone: "zone",
@@ -222,7 +439,7 @@ func getLocalServiceCluster(catalog catalog.MeshCataloger, proxyServiceName serv
 			LbEndpoints: []*xds_endpoint.LbEndpoint{{
 				HostIdentifier: &xds_endpoint.LbEndpoint_Endpoint{
 					Endpoint: &xds_endpoint.Endpoint{
-						Address: envoy.GetAddress(constants.LocalhostIPAddress, port),
+						Address: envoy.GetAddress(address, port),
 					},
 				},
 				LoadBalancingWeight: &wrappers.UInt32Value{
@@ -269,41 +486,52 @@ func getPrometheusCluster() *xds_cluster.Cluster {
 
 // getEgressClusters returns a slice of XDS cluster objects for the given egress cluster configs.
 // If the cluster config is invalid, an error is logged and the corresponding cluster config is ignored.
-func getEgressClusters(clusterConfigs []*trafficpolicy.EgressClusterConfig) []*xds_cluster.Cluster {
+func getEgressClusters(clusterConfigs []*trafficpolicy.EgressClusterConfig, dnsConfig configv1alpha1.DNSResolutionSpec) []*xds_cluster.Cluster {
 	if clusterConfigs == nil {
 		return nil
 	}
 
 	var egressClusters []*xds_cluster.Cluster
 	for _, config := range clusterConfigs {
+		var cluster *xds_cluster.Cluster
+		var err error
 		switch config.Host {
 		case "":
 			// Cluster config does not have a Host specified, route it to its original destination.
 			// Used for TCP based clusters
-			if originalDestinationEgressCluster, err := getOriginalDestinationEgressCluster(config.Name); err != nil {
+			if cluster, err = getOriginalDestinationEgressCluster(config.Name); err != nil {
 				log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGettingOrgDstEgressCluster)).
 					Msg("Error building the original destination cluster for the given egress cluster config")
-			} else {
-				egressClusters = append(egressClusters, originalDestinationEgressCluster)
+				continue
 			}
 		default:
 			// Cluster config has a Host specified, route it based on the Host resolved using DNS.
 			// Used for HTTP based clusters
-			if cluster, err := getDNSResolvableEgressCluster(config); err != nil {
+			if cluster, err = getDNSResolvableEgressCluster(config, dnsConfig); err != nil {
 				log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGettingDNSEgressCluster)).
 					Msg("Error building cluster for the given egress cluster config")
-			} else {
-				egressClusters = append(egressClusters, cluster)
+				continue
 			}
 		}
+
+		if config.ProxyProtocolVersion != "" {
+			if cluster.TransportSocket, err = getUpstreamProxyProtocolTransportSocket(config.ProxyProtocolVersion); err != nil {
+				log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingXDSResource)).
+					Msgf("Error building the PROXY protocol transport socket for egress cluster %s", config.Name)
+				continue
+			}
+		}
+
+		egressClusters = append(egressClusters, cluster)
 	}
 
 	return egressClusters
 }
 
-// getDNSResolvableEgressCluster returns an XDS cluster object that is resolved using DNS for the given egress cluster config.
+// getDNSResolvableEgressCluster returns an XDS cluster object that is resolved using DNS for the given egress cluster config,
+// configured with the given DNS resolution settings.
 // If the egress cluster config is invalid, an error is returned.
-func getDNSResolvableEgressCluster(config *trafficpolicy.EgressClusterConfig) (*xds_cluster.Cluster, error) {
+func getDNSResolvableEgressCluster(config *trafficpolicy.EgressClusterConfig, dnsConfig configv1alpha1.DNSResolutionSpec) (*xds_cluster.Cluster, error) {
 	if config == nil {
 		return nil, errors.New("Invalid egress cluster config: nil type")
 	}
@@ -322,9 +550,12 @@ func getDNSResolvableEgressCluster(config *trafficpolicy.EgressClusterConfig) (*
 		AltStatName:    formatAltStatNameForPrometheus(config.Name),
 		ConnectTimeout: ptypes.DurationProto(clusterConnectTimeout),
 		ClusterDiscoveryType: &xds_cluster.Cluster_Type{
-			Type: xds_cluster.Cluster_STRICT_DNS,
+			Type: getDNSClusterType(dnsConfig.ClusterType),
 		},
-		LbPolicy: xds_cluster.Cluster_ROUND_ROBIN,
+		DnsRefreshRate:  getDNSRefreshRate(dnsConfig.RefreshRate),
+		RespectDnsTtl:   dnsConfig.RespectDNSTTL,
+		DnsLookupFamily: getDNSLookupFamily(dnsConfig.LookupFamily),
+		LbPolicy:        xds_cluster.Cluster_ROUND_ROBIN,
 		LoadAssignment: &xds_endpoint.ClusterLoadAssignment{
 			ClusterName: config.Name,
 			Endpoints: []*xds_endpoint.LocalityLbEndpoints{
@@ -345,6 +576,105 @@ func getDNSResolvableEgressCluster(config *trafficpolicy.EgressClusterConfig) (*
 	}, nil
 }
 
+// getUpstreamProxyProtocolTransportSocket returns the transport socket sending a PROXY protocol header of the given
+// version, 'v1' or 'v2', on the connections to the upstream hosts of a cluster. The header conveys the source and
+// original destination addresses of the downstream connection, and is followed by the plaintext connection data.
+func getUpstreamProxyProtocolTransportSocket(version string) (*xds_core.TransportSocket, error) {
+	var proxyProtocolVersion xds_core.ProxyProtocolConfig_Version
+	switch strings.ToLower(version) {
+	case policyv1alpha1.ProxyProtocolV1:
+		proxyProtocolVersion = xds_core.ProxyProtocolConfig_V1
+	case policyv1alpha1.ProxyProtocolV2:
+		proxyProtocolVersion = xds_core.ProxyProtocolConfig_V2
+	default:
+		return nil, errors.Errorf("Invalid PROXY protocol version %s, must be one of 'v1, v2'", version)
+	}
+
+	marshalledRawBuffer, err := ptypes.MarshalAny(&xds_raw_buffer.RawBuffer{})
+	if err != nil {
+		return nil, err
+	}
+
+	marshalledProxyProtocolTransport, err := ptypes.MarshalAny(&xds_proxy_protocol.ProxyProtocolUpstreamTransport{
+		Config: &xds_core.ProxyProtocolConfig{
+			Version: proxyProtocolVersion,
+		},
+		TransportSocket: &xds_core.TransportSocket{
+			Name: wellknown.TransportSocketRawBuffer,
+			ConfigType: &xds_core.TransportSocket_TypedConfig{
+				TypedConfig: marshalledRawBuffer,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &xds_core.TransportSocket{
+		Name: upstreamProxyProtocolTransportSocket,
+		ConfigType: &xds_core.TransportSocket_TypedConfig{
+			TypedConfig: marshalledProxyProtocolTransport,
+		},
+	}, nil
+}
+
+// getDNSClusterType returns the Envoy discovery type of clusters resolved using DNS for the given cluster type.
+// STRICT_DNS is used unless LOGICAL_DNS is requested.
+func getDNSClusterType(clusterType string) xds_cluster.Cluster_DiscoveryType {
+	if strings.EqualFold(clusterType, dnsClusterTypeLogical) {
+		return xds_cluster.Cluster_LOGICAL_DNS
+	}
+	return xds_cluster.Cluster_STRICT_DNS
+}
+
+// getDNSLookupFamily returns the Envoy DNS lookup family for the given lookup family.
+// AUTO is used unless the lookup is restricted to a single IP address family.
+func getDNSLookupFamily(lookupFamily string) xds_cluster.Cluster_DnsLookupFamily {
+	switch strings.ToLower(lookupFamily) {
+	case dnsLookupFamilyV4Only:
+		return xds_cluster.Cluster_V4_ONLY
+	case dnsLookupFamilyV6Only:
+		return xds_cluster.Cluster_V6_ONLY
+	default:
+		return xds_cluster.Cluster_AUTO
+	}
+}
+
+// getDNSRefreshRate returns the DNS refresh rate for the given duration, or nil to use Envoy's default
+// refresh rate when unset. Envoy requires the refresh rate to be at least 1ms, so an invalid refresh rate
+// falls back to Envoy's default instead of failing to build the clusters resolved using DNS.
+func getDNSRefreshRate(refreshRate string) *durationpb.Duration {
+	if refreshRate == "" {
+		return nil
+	}
+
+	duration, err := time.ParseDuration(refreshRate)
+	if err != nil || duration < time.Millisecond {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrInvalidDNSRefreshRate)).
+			Msgf("Invalid DNS refresh rate %s, must be a duration of at least 1ms; using the proxy's default refresh rate", refreshRate)
+		return nil
+	}
+
+	return durationpb.New(duration)
+}
+
+// getExternalServiceCluster returns an Envoy cluster for the given service registered outside the mesh.
+// The endpoints of the cluster are discovered over EDS, and connections to them are plaintext since
+// the service's instances are not part of the mesh.
+func getExternalServiceCluster(externalSvc endpoint.ExternalService) *xds_cluster.Cluster {
+	clusterName := externalSvc.ClusterName()
+	return &xds_cluster.Cluster{
+		Name:           clusterName,
+		AltStatName:    formatAltStatNameForPrometheus(clusterName),
+		ConnectTimeout: ptypes.DurationProto(clusterConnectTimeout),
+		ClusterDiscoveryType: &xds_cluster.Cluster_Type{
+			Type: xds_cluster.Cluster_EDS,
+		},
+		EdsClusterConfig: &xds_cluster.Cluster_EdsClusterConfig{EdsConfig: envoy.GetADSConfigSource()},
+		LbPolicy:         xds_cluster.Cluster_ROUND_ROBIN,
+	}
+}
+
 // getOriginalDestinationEgressCluster returns an Envoy cluster that routes traffic to its original destination.
 // The original destination is the original IP address and port prior to being redirected to the sidecar proxy.
 func getOriginalDestinationEgressCluster(name string) (*xds_cluster.Cluster, error) {
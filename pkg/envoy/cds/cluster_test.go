@@ -2,20 +2,29 @@ package cds
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	xds_proxy_protocol "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/proxy_protocol/v3"
+	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/golang/mock/gomock"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/wrappers"
 	tassert "github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+	corev1 "k8s.io/api/core/v1"
 
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/catalog"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/logger"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/tests"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
@@ -88,6 +97,82 @@ func TestGetUpstreamServiceCluster(t *testing.T) {
 	}
 }
 
+func BenchmarkGetUpstreamServiceClusters(b *testing.B) {
+	// Keep the logging of the builders out of the measurements
+	if err := logger.SetLogLevel("error"); err != nil {
+		b.Fatal(err)
+	}
+
+	for _, services := range tests.BenchmarkTopologyServices {
+		b.Run(fmt.Sprintf("%d services", services), func(b *testing.B) {
+			topology := tests.NewTopologyBuilder().
+				WithNamespaces(tests.BenchmarkTopologyNamespaces).
+				WithServicesPerNamespace(services / tests.BenchmarkTopologyNamespaces).
+				Build()
+			upstreams := topology.MeshServices()
+
+			mockCtrl := gomock.NewController(b)
+			mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+			mockCatalog.EXPECT().GetUpstreamRetryBudget(gomock.Any()).Return(nil).AnyTimes()
+			mockCatalog.EXPECT().ListServicePorts(gomock.Any()).Return(nil, nil).AnyTimes()
+			proxyIdentity := identity.ServiceIdentity("svc-0.ns-0.cluster.local")
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				// Clusters are built for all the upstreams of the proxy, as by NewResponse
+				for _, upstream := range upstreams {
+					retryBudget := withRetryBudget(mockCatalog.GetUpstreamRetryBudget(upstream))
+					for _, portOpts := range getUpstreamServicePortOptions(mockCatalog, upstream) {
+						if _, err := getUpstreamServiceCluster(proxyIdentity, upstream, append([]clusterOption{retryBudget, permissive}, portOpts...)...); err != nil {
+							b.Fatal(err)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGetUpstreamServiceClusterWithTrustDomain(t *testing.T) {
+	assert := tassert.New(t)
+
+	remoteCluster, err := getUpstreamServiceCluster(tests.BookbuyerServiceIdentity, tests.BookstoreV1Service, withTrustDomain("cluster-a.example.com"))
+	assert.NoError(err)
+
+	upstreamTLSContext := &xds_auth.UpstreamTlsContext{}
+	assert.NoError(ptypes.UnmarshalAny(remoteCluster.TransportSocket.GetTypedConfig(), upstreamTLSContext))
+	assert.Equal([]string{envoy.GetTrustDomainALPN("cluster-a.example.com"), "osm"}, upstreamTLSContext.CommonTlsContext.AlpnProtocols)
+
+	// The in-mesh ALPN must not be modified
+	assert.Equal([]string{"osm"}, envoy.ALPNInMesh)
+}
+
+func TestGetUpstreamServiceClusterWithTCPProtocolDetection(t *testing.T) {
+	assert := tassert.New(t)
+
+	remoteCluster, err := getUpstreamServiceCluster(tests.BookbuyerServiceIdentity, tests.BookstoreV1Service, withActiveHealthChecks, withTrustDomain("cluster-a.example.com"), tcpProtocolDetection)
+	assert.NoError(err)
+	assert.Equal("default/bookstore-v1|tcp", remoteCluster.Name)
+	assert.Equal(xds_cluster.Cluster_EDS, remoteCluster.GetType())
+	assert.Equal(tests.BookstoreV1Service.String(), remoteCluster.EdsClusterConfig.ServiceName)
+	assert.Nil(remoteCluster.TypedExtensionProtocolOptions)
+	assert.Nil(remoteCluster.HealthChecks)
+
+	upstreamTLSContext := &xds_auth.UpstreamTlsContext{}
+	assert.NoError(ptypes.UnmarshalAny(remoteCluster.TransportSocket.GetTypedConfig(), upstreamTLSContext))
+	assert.Equal([]string{"osm-tcp", envoy.GetTrustDomainALPN("cluster-a.example.com"), "osm"}, upstreamTLSContext.CommonTlsContext.AlpnProtocols)
+
+	// The TCP ALPN must not be modified
+	assert.Equal([]string{"osm-tcp"}, envoy.ALPNInMeshTCP)
+
+	// Permissive mode clusters rely on the original destination
+	remoteCluster, err = getUpstreamServiceCluster(tests.BookbuyerServiceIdentity, tests.BookstoreV1Service, permissive, tcpProtocolDetection)
+	assert.NoError(err)
+	assert.Equal("default/bookstore-v1|tcp", remoteCluster.Name)
+	assert.Equal(xds_cluster.Cluster_ORIGINAL_DST, remoteCluster.GetType())
+}
+
 func TestGetMulticlusterGatewayUpstreamServiceCluster(t *testing.T) {
 	upstreamSvc := tests.BookstoreV1Service
 
@@ -231,7 +316,7 @@ func TestGetLocalServiceCluster(t *testing.T) {
 				mockCatalog.EXPECT().GetTargetPortToProtocolMappingForService(tc.proxyService).Return(tc.portToProtocolMapping, nil).Times(1)
 			}
 
-			cluster, err := getLocalServiceCluster(mockCatalog, tc.proxyService, clusterName)
+			cluster, err := getLocalServiceCluster(mockCatalog, tc.proxyService, clusterName, constants.LocalhostIPAddress)
 
 			if tc.expectedErr {
 				assert.NotNil(err)
@@ -252,6 +337,145 @@ func TestGetLocalServiceCluster(t *testing.T) {
 	}
 }
 
+func TestGetLocalServiceClusters(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+
+	proxyService := service.MeshService{Name: "bookstore", Namespace: "bookstore-ns"}
+	mockCatalog.EXPECT().ListServicePorts(proxyService).Return([]service.ServicePort{
+		{Name: "http", Port: 80, TargetPort: 8080, Protocol: "http"},
+		{Name: "grpc", Port: 9090, TargetPort: 9091, Protocol: "grpc"},
+	}, nil).Times(1)
+
+	clusters, err := getLocalServiceClusters(mockCatalog, proxyService, nil)
+	assert.Nil(err)
+	assert.Len(clusters, 2)
+
+	expected := map[string]uint32{
+		"bookstore-ns/bookstore|80-local":   8080,
+		"bookstore-ns/bookstore|9090-local": 9091,
+	}
+	for _, cluster := range clusters {
+		targetPort, ok := expected[cluster.Name]
+		assert.True(ok, "unexpected cluster %s", cluster.Name)
+		assert.Len(cluster.LoadAssignment.Endpoints, 1)
+		assert.Len(cluster.LoadAssignment.Endpoints[0].LbEndpoints, 1)
+		assert.Equal(envoy.GetAddress(constants.LocalhostIPAddress, targetPort),
+			cluster.LoadAssignment.Endpoints[0].LbEndpoints[0].GetEndpoint().Address)
+	}
+}
+
+func TestGetLocalServiceClustersWithNamedTargetPorts(t *testing.T) {
+	proxyService := service.MeshService{Name: "bookstore", Namespace: "bookstore-ns"}
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Ports: []corev1.ContainerPort{{Name: "web", ContainerPort: 8081}},
+			}},
+		},
+	}
+
+	testCases := []struct {
+		name                string
+		ports               []service.ServicePort
+		pod                 *corev1.Pod
+		expectedTargetPorts map[string]uint32
+	}{
+		{
+			name: "single port resolved on the pod",
+			ports: []service.ServicePort{
+				{Name: "http", Port: 80, TargetPort: 8080, TargetPortName: "web", Protocol: "http"},
+			},
+			pod: pod,
+			expectedTargetPorts: map[string]uint32{
+				"bookstore-ns/bookstore-local": 8081,
+			},
+		},
+		{
+			name: "multiple ports resolved on the pod",
+			ports: []service.ServicePort{
+				{Name: "http", Port: 80, TargetPort: 8080, TargetPortName: "web", Protocol: "http"},
+				{Name: "admin", Port: 9000, TargetPort: 9090, TargetPortName: "admin", Protocol: "http"},
+			},
+			pod: pod,
+			expectedTargetPorts: map[string]uint32{
+				"bookstore-ns/bookstore|80-local":   8081,
+				"bookstore-ns/bookstore|9000-local": 9090,
+			},
+		},
+		{
+			name: "multiple ports without pod",
+			ports: []service.ServicePort{
+				{Name: "http", Port: 80, TargetPort: 8080, TargetPortName: "web", Protocol: "http"},
+				{Name: "admin", Port: 9000, TargetPort: 9090, TargetPortName: "admin", Protocol: "http"},
+			},
+			expectedTargetPorts: map[string]uint32{
+				"bookstore-ns/bookstore|80-local":   8080,
+				"bookstore-ns/bookstore|9000-local": 9090,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+
+			mockCatalog.EXPECT().ListServicePorts(proxyService).Return(tc.ports, nil).Times(1)
+
+			clusters, err := getLocalServiceClusters(mockCatalog, proxyService, tc.pod)
+			assert.Nil(err)
+			assert.Len(clusters, len(tc.expectedTargetPorts))
+			for _, cluster := range clusters {
+				targetPort, ok := tc.expectedTargetPorts[cluster.Name]
+				assert.True(ok, "unexpected cluster %s", cluster.Name)
+				assert.Equal(envoy.GetAddress(constants.LocalhostIPAddress, targetPort),
+					cluster.LoadAssignment.Endpoints[0].LbEndpoints[0].GetEndpoint().Address)
+			}
+		})
+	}
+}
+
+func TestGetLocalServiceAddress(t *testing.T) {
+	assert := tassert.New(t)
+
+	pod := &corev1.Pod{Status: corev1.PodStatus{PodIP: "10.0.0.1"}}
+	assert.Equal(constants.LocalhostIPAddress, getLocalServiceAddress(nil))
+	assert.Equal(constants.LocalhostIPAddress, getLocalServiceAddress(pod))
+
+	// Inbound traffic proxied from the source IP of the clients is proxied to the pod's IP
+	pod.Annotations = map[string]string{constants.SourceIPPreservationAnnotation: "true"}
+	assert.Equal("10.0.0.1", getLocalServiceAddress(pod))
+}
+
+func TestGetUpstreamServicePortOptions(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+
+	upstreamSvc := tests.BookstoreV1Service
+	mockCatalog.EXPECT().ListServicePorts(upstreamSvc).Return([]service.ServicePort{
+		{Name: "http", Port: 80, TargetPort: 8080, Protocol: "http"},
+		{Name: "tcp", Port: 5432, TargetPort: 5432, Protocol: "tcp"},
+	}, nil).Times(1)
+
+	portOpts := getUpstreamServicePortOptions(mockCatalog, upstreamSvc)
+	assert.Len(portOpts, 2)
+	for i, port := range []uint32{80, 5432} {
+		o := &clusterOptions{}
+		for _, opt := range portOpts[i] {
+			opt(o)
+		}
+		assert.Equal(port, o.servicePort)
+	}
+
+	// A service whose ports can't be retrieved is backed by a single cluster
+	mockCatalog.EXPECT().ListServicePorts(upstreamSvc).Return(nil, errors.New("error")).Times(1)
+	assert.Equal([][]clusterOption{nil}, getUpstreamServicePortOptions(mockCatalog, upstreamSvc))
+}
+
 func TestGetPrometheusCluster(t *testing.T) {
 	assert := tassert.New(t)
 
@@ -390,14 +614,78 @@ func TestGetEgressClusters(t *testing.T) {
 			},
 			expectedClusterCount: 2,
 		},
+		{
+			name: "some cluster configs have an invalid PROXY protocol version",
+			clusterConfigs: []*trafficpolicy.EgressClusterConfig{
+				{
+					Name:                 "foo.com:80-proxy-protocol-v2",
+					Host:                 "foo.com",
+					Port:                 80,
+					ProxyProtocolVersion: "v2",
+				},
+				{
+					Name:                 "90-proxy-protocol-v3",
+					Port:                 90,
+					ProxyProtocolVersion: "v3",
+				},
+			},
+			expectedClusterCount: 1,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			assert := tassert.New(t)
 
-			actual := getEgressClusters(tc.clusterConfigs)
+			actual := getEgressClusters(tc.clusterConfigs, configv1alpha1.DNSResolutionSpec{})
 			assert.Len(actual, tc.expectedClusterCount)
+			for i, cluster := range actual {
+				if tc.clusterConfigs[i].ProxyProtocolVersion != "" {
+					assert.Equal(upstreamProxyProtocolTransportSocket, cluster.TransportSocket.Name)
+				} else {
+					assert.Nil(cluster.TransportSocket)
+				}
+			}
+		})
+	}
+}
+
+func TestGetUpstreamProxyProtocolTransportSocket(t *testing.T) {
+	testCases := []struct {
+		version         string
+		expectedVersion xds_core.ProxyProtocolConfig_Version
+		expectError     bool
+	}{
+		{
+			version:         "v1",
+			expectedVersion: xds_core.ProxyProtocolConfig_V1,
+		},
+		{
+			version:         "V2",
+			expectedVersion: xds_core.ProxyProtocolConfig_V2,
+		},
+		{
+			version:     "v3",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.version, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			actual, err := getUpstreamProxyProtocolTransportSocket(tc.version)
+			assert.Equal(tc.expectError, err != nil)
+			if tc.expectError {
+				return
+			}
+
+			assert.Equal(upstreamProxyProtocolTransportSocket, actual.Name)
+			proxyProtocolTransport := &xds_proxy_protocol.ProxyProtocolUpstreamTransport{}
+			assert.Nil(ptypes.UnmarshalAny(actual.GetTypedConfig(), proxyProtocolTransport))
+			assert.Equal(tc.expectedVersion, proxyProtocolTransport.Config.Version)
+			// The connection data following the header is sent in plaintext
+			assert.Equal(wellknown.TransportSocketRawBuffer, proxyProtocolTransport.TransportSocket.Name)
 		})
 	}
 }
@@ -406,6 +694,7 @@ func TestGetDNSResolvableEgressCluster(t *testing.T) {
 	testCases := []struct {
 		name            string
 		clusterConfig   *trafficpolicy.EgressClusterConfig
+		dnsConfig       configv1alpha1.DNSResolutionSpec
 		expectedCluster *xds_cluster.Cluster
 		expectError     bool
 	}{
@@ -450,6 +739,90 @@ func TestGetDNSResolvableEgressCluster(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "egress cluster config with DNS resolution settings",
+			clusterConfig: &trafficpolicy.EgressClusterConfig{
+				Name: "foo.com:443",
+				Host: "foo.com",
+				Port: 443,
+			},
+			dnsConfig: configv1alpha1.DNSResolutionSpec{
+				ClusterType:   "logical_dns",
+				RefreshRate:   "10s",
+				RespectDNSTTL: true,
+				LookupFamily:  "v4_only",
+			},
+			expectedCluster: &xds_cluster.Cluster{
+				Name:           "foo.com:443",
+				AltStatName:    "foo_com_443",
+				ConnectTimeout: ptypes.DurationProto(clusterConnectTimeout),
+				ClusterDiscoveryType: &xds_cluster.Cluster_Type{
+					Type: xds_cluster.Cluster_LOGICAL_DNS,
+				},
+				DnsRefreshRate:  durationpb.New(10 * time.Second),
+				RespectDnsTtl:   true,
+				DnsLookupFamily: xds_cluster.Cluster_V4_ONLY,
+				LbPolicy:        xds_cluster.Cluster_ROUND_ROBIN,
+				LoadAssignment: &xds_endpoint.ClusterLoadAssignment{
+					ClusterName: "foo.com:443",
+					Endpoints: []*xds_endpoint.LocalityLbEndpoints{
+						{
+							LbEndpoints: []*xds_endpoint.LbEndpoint{{
+								HostIdentifier: &xds_endpoint.LbEndpoint_Endpoint{
+									Endpoint: &xds_endpoint.Endpoint{
+										Address: envoy.GetAddress("foo.com", 443),
+									},
+								},
+								LoadBalancingWeight: &wrappers.UInt32Value{
+									Value: constants.ClusterWeightAcceptAll,
+								},
+							}},
+						},
+					},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "egress cluster config with invalid DNS refresh rate",
+			clusterConfig: &trafficpolicy.EgressClusterConfig{
+				Name: "foo.com:443",
+				Host: "foo.com",
+				Port: 443,
+			},
+			dnsConfig: configv1alpha1.DNSResolutionSpec{
+				RefreshRate: "invalid",
+			},
+			// An invalid refresh rate falls back to the proxy's default refresh rate
+			expectedCluster: &xds_cluster.Cluster{
+				Name:           "foo.com:443",
+				AltStatName:    "foo_com_443",
+				ConnectTimeout: ptypes.DurationProto(clusterConnectTimeout),
+				ClusterDiscoveryType: &xds_cluster.Cluster_Type{
+					Type: xds_cluster.Cluster_STRICT_DNS,
+				},
+				DnsLookupFamily: xds_cluster.Cluster_AUTO,
+				LbPolicy:        xds_cluster.Cluster_ROUND_ROBIN,
+				LoadAssignment: &xds_endpoint.ClusterLoadAssignment{
+					ClusterName: "foo.com:443",
+					Endpoints: []*xds_endpoint.LocalityLbEndpoints{
+						{
+							LbEndpoints: []*xds_endpoint.LbEndpoint{{
+								HostIdentifier: &xds_endpoint.LbEndpoint_Endpoint{
+									Endpoint: &xds_endpoint.Endpoint{
+										Address: envoy.GetAddress("foo.com", 443),
+									},
+								},
+								LoadBalancingWeight: &wrappers.UInt32Value{
+									Value: constants.ClusterWeightAcceptAll,
+								},
+							}},
+						},
+					},
+				},
+			},
+			expectError: false,
+		},
 		{
 			name: "egress cluster config Name unspecified",
 			clusterConfig: &trafficpolicy.EgressClusterConfig{
@@ -483,13 +856,34 @@ func TestGetDNSResolvableEgressCluster(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			assert := tassert.New(t)
 
-			actual, err := getDNSResolvableEgressCluster(tc.clusterConfig)
+			actual, err := getDNSResolvableEgressCluster(tc.clusterConfig, tc.dnsConfig)
 			assert.Equal(tc.expectError, err != nil)
 			assert.Equal(tc.expectedCluster, actual)
 		})
 	}
 }
 
+func TestGetDNSRefreshRate(t *testing.T) {
+	testCases := []struct {
+		refreshRate string
+		expected    *durationpb.Duration
+	}{
+		{refreshRate: "", expected: nil},
+		{refreshRate: "30s", expected: durationpb.New(30 * time.Second)},
+		{refreshRate: "1ms", expected: durationpb.New(time.Millisecond)},
+		{refreshRate: "500us", expected: nil},
+		{refreshRate: "-1s", expected: nil},
+		{refreshRate: "invalid", expected: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.refreshRate, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expected, getDNSRefreshRate(tc.refreshRate))
+		})
+	}
+}
+
 func TestFormatAltStatNameForPrometheus(t *testing.T) {
 	testCases := []struct {
 		name                string
@@ -522,3 +916,27 @@ func TestFormatAltStatNameForPrometheus(t *testing.T) {
 		})
 	}
 }
+
+func TestGetUpstreamServiceClusterWithRetryBudget(t *testing.T) {
+	assert := tassert.New(t)
+
+	retryBudget := &trafficpolicy.RetryBudget{BudgetPercent: 25, MinRetryConcurrency: 5}
+	remoteCluster, err := getUpstreamServiceCluster(tests.BookbuyerServiceIdentity, tests.BookstoreV1Service, withRetryBudget(retryBudget))
+	assert.NoError(err)
+	assert.Len(remoteCluster.CircuitBreakers.Thresholds, 1)
+
+	thresholds := remoteCluster.CircuitBreakers.Thresholds[0]
+	assert.Equal(xds_core.RoutingPriority_DEFAULT, thresholds.Priority)
+	assert.Equal(float64(25), thresholds.RetryBudget.BudgetPercent.Value)
+	assert.Equal(uint32(5), thresholds.RetryBudget.MinRetryConcurrency.Value)
+	assert.True(thresholds.TrackRemaining)
+
+	// Retries are not bounded on the TCP clusters, which are never retried
+	remoteCluster, err = getUpstreamServiceCluster(tests.BookbuyerServiceIdentity, tests.BookstoreV1Service, withRetryBudget(retryBudget), tcpProtocolDetection)
+	assert.NoError(err)
+	assert.Nil(remoteCluster.CircuitBreakers)
+
+	remoteCluster, err = getUpstreamServiceCluster(tests.BookbuyerServiceIdentity, tests.BookstoreV1Service)
+	assert.NoError(err)
+	assert.Nil(remoteCluster.CircuitBreakers)
+}
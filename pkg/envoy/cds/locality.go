@@ -0,0 +1,55 @@
+package cds
+
+import (
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// getLocalServiceLocality returns the locality of the given pod backing the given service, used as the locality of the
+// local cluster ingress traffic to the service is routed to. It is only returned for NodePort and LoadBalancer services
+// whose external traffic is kept local, either to the node receiving it by a Local external traffic policy, or to the
+// zones of the endpoints by topology aware routing, and nil otherwise.
+//
+// The zone and region are those of the pod's endpoint in the EndpointSlices of the service. When the service is
+// topology aware, the zone hinted for the endpoint takes precedence, as it is the zone the endpoint serves. When the
+// client source IP is preserved, the sub-zone is the pod's node, the only node its external traffic comes through.
+func getLocalServiceLocality(kubeController k8s.Controller, svc service.MeshService, pod *corev1.Pod) *xds_core.Locality {
+	k8sSvc := kubeController.GetService(svc)
+	if k8sSvc == nil || !k8s.IsExposedOnNodes(k8sSvc) {
+		return nil
+	}
+
+	preserveSourceIP := k8s.PreservesClientSourceIP(k8sSvc)
+	topologyAware := k8s.IsTopologyAware(k8sSvc)
+	if !preserveSourceIP && !topologyAware {
+		return nil
+	}
+
+	for _, slice := range kubeController.ListEndpointSlicesForService(svc) {
+		for _, sliceEndpoint := range slice.Endpoints {
+			targetRef := sliceEndpoint.TargetRef
+			if targetRef == nil || targetRef.Kind != "Pod" || targetRef.Namespace != pod.Namespace || targetRef.Name != pod.Name {
+				continue
+			}
+
+			locality := &xds_core.Locality{
+				Region: sliceEndpoint.Topology[corev1.LabelTopologyRegion],
+				Zone:   sliceEndpoint.Topology[corev1.LabelTopologyZone],
+			}
+			if topologyAware && sliceEndpoint.Hints != nil && len(sliceEndpoint.Hints.ForZones) > 0 {
+				locality.Zone = sliceEndpoint.Hints.ForZones[0].Name
+			}
+			if preserveSourceIP {
+				locality.SubZone = pod.Spec.NodeName
+			}
+			return locality
+		}
+	}
+
+	log.Debug().Msgf("Endpoint of pod %s/%s not found in the EndpointSlices of service %s, using the default locality for its local cluster",
+		pod.Namespace, pod.Name, svc)
+	return nil
+}
@@ -0,0 +1,103 @@
+package cds
+
+import (
+	"testing"
+
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/tests"
+)
+
+func TestGetLocalServiceLocality(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: tests.BookstoreV1Service.Namespace, Name: "bookstore-v1-pod"},
+		Spec:       corev1.PodSpec{NodeName: "node-1"},
+	}
+	slice := &discoveryv1beta1.EndpointSlice{
+		Endpoints: []discoveryv1beta1.Endpoint{
+			{
+				Addresses: []string{"10.0.0.1"},
+				TargetRef: &corev1.ObjectReference{Kind: "Pod", Namespace: pod.Namespace, Name: "other-pod"},
+				Topology:  map[string]string{corev1.LabelTopologyZone: "zone-b"},
+			},
+			{
+				Addresses: []string{"10.0.0.2"},
+				TargetRef: &corev1.ObjectReference{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name},
+				Topology:  map[string]string{corev1.LabelTopologyRegion: "region", corev1.LabelTopologyZone: "zone-a"},
+				Hints:     &discoveryv1beta1.EndpointHints{ForZones: []discoveryv1beta1.ForZone{{Name: "zone-c"}}},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name             string
+		service          *corev1.Service
+		slices           []*discoveryv1beta1.EndpointSlice
+		expectedLocality *xds_core.Locality
+	}{
+		{
+			name:             "service not found",
+			service:          nil,
+			expectedLocality: nil,
+		},
+		{
+			name: "ClusterIP service",
+			service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"service.kubernetes.io/topology-aware-hints": "auto"}},
+			},
+			expectedLocality: nil,
+		},
+		{
+			name: "LoadBalancer service with Cluster external traffic policy",
+			service: &corev1.Service{
+				Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer, ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeCluster},
+			},
+			expectedLocality: nil,
+		},
+		{
+			name: "LoadBalancer service with Local external traffic policy",
+			service: &corev1.Service{
+				Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer, ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal},
+			},
+			slices:           []*discoveryv1beta1.EndpointSlice{slice},
+			expectedLocality: &xds_core.Locality{Region: "region", Zone: "zone-a", SubZone: "node-1"},
+		},
+		{
+			name: "NodePort service with topology aware hints",
+			service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"service.kubernetes.io/topology-aware-hints": "auto"}},
+				Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort},
+			},
+			slices:           []*discoveryv1beta1.EndpointSlice{slice},
+			expectedLocality: &xds_core.Locality{Region: "region", Zone: "zone-c"},
+		},
+		{
+			name: "endpoint of the pod not found",
+			service: &corev1.Service{
+				Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort, ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal},
+			},
+			slices:           nil,
+			expectedLocality: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mockKubeController.EXPECT().GetService(tests.BookstoreV1Service).Return(tc.service)
+			mockKubeController.EXPECT().ListEndpointSlicesForService(tests.BookstoreV1Service).Return(tc.slices).AnyTimes()
+
+			assert.Equal(tc.expectedLocality, getLocalServiceLocality(mockKubeController, tests.BookstoreV1Service, pod))
+		})
+	}
+}
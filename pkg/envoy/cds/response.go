@@ -15,12 +15,12 @@ import (
 )
 
 // NewResponse creates a new Cluster Discovery Response.
-func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_discovery.DiscoveryRequest, cfg configurator.Configurator, _ certificate.Manager, proxyRegistry *registry.ProxyRegistry) ([]types.Resource, error) {
+func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_discovery.DiscoveryRequest, cfg configurator.Configurator, certManager certificate.Manager, proxyRegistry *registry.ProxyRegistry) ([]types.Resource, error) {
 	var clusters []*xds_cluster.Cluster
 
 	proxyIdentity, err := envoy.GetServiceIdentityFromProxyCertificate(proxy.GetCertificateCommonName())
 	if err != nil {
-		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGettingServiceIdentity)).
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetricForProxy(errcode.ErrGettingServiceIdentity, proxy.GetCertificateCommonName())).
 			Msgf("Error looking up identity for proxy %s", proxy.String())
 		return nil, err
 	}
@@ -37,46 +37,94 @@ func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_d
 		for _, dstService := range meshCatalog.ListOutboundServicesForMulticlusterGateway() {
 			cluster, err := getMulticlusterGatewayUpstreamServiceCluster(meshCatalog, dstService, opts...)
 			if err != nil {
-				log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrObtainingUpstreamServiceCluster)).
+				log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetricForProxy(errcode.ErrObtainingUpstreamServiceCluster, proxy.GetCertificateCommonName())).
 					Msgf("Failed to construct service cluster for service %s for proxy with XDS Certificate SerialNumber=%s on Pod with UID=%s",
 						dstService.Name, proxy.GetCertificateSerialNumber(), proxy.String())
 				return nil, err
 			}
 			clusters = append(clusters, cluster)
 		}
+
+		// Downstreams from remote trust domains are terminated by the gateway, which originates a new
+		// mTLS connection to the exported upstream service
+		if store, ok := certManager.(certificate.TrustBundleStore); ok && len(store.ListTrustDomains()) > 0 {
+			for _, dstService := range meshCatalog.ListExportedServicesForMulticlusterGateway() {
+				mTLSCluster, err := getMulticlusterGatewayMTLSUpstreamServiceCluster(meshCatalog, proxyIdentity, dstService, opts...)
+				if err != nil {
+					log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetricForProxy(errcode.ErrObtainingUpstreamServiceCluster, proxy.GetCertificateCommonName())).
+						Msgf("Failed to construct mTLS service cluster for service %s for proxy %s", dstService.Name, proxy.String())
+					return nil, err
+				}
+				clusters = append(clusters, mTLSCluster)
+			}
+		}
 		return removeDups(clusters), nil
 	}
 
+	if cfg.GetFeatureFlags().EnableMulticlusterMode {
+		if trustDomain := cfg.GetTrustDomain(); trustDomain != "" {
+			opts = append(opts, withTrustDomain(trustDomain))
+		}
+	}
+
 	// Build remote clusters based on allowed outbound services
 	for _, dstService := range meshCatalog.ListOutboundServicesForIdentity(proxyIdentity) {
-		cluster, err := getUpstreamServiceCluster(proxyIdentity, dstService, opts...)
-		if err != nil {
-			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrObtainingUpstreamServiceCluster)).
-				Msgf("Failed to construct service cluster for service %s for proxy %s", dstService.Name, proxy.String())
-			return nil, err
-		}
+		retryBudget := withRetryBudget(meshCatalog.GetUpstreamRetryBudget(dstService))
+		for _, portOpts := range getUpstreamServicePortOptions(meshCatalog, dstService) {
+			serviceOpts := append(append([]clusterOption{retryBudget}, opts...), portOpts...)
+
+			cluster, err := getUpstreamServiceCluster(proxyIdentity, dstService, serviceOpts...)
+			if err != nil {
+				log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetricForProxy(errcode.ErrObtainingUpstreamServiceCluster, proxy.GetCertificateCommonName())).
+					Msgf("Failed to construct service cluster for service %s for proxy %s", dstService.Name, proxy.String())
+				return nil, err
+			}
 
-		clusters = append(clusters, cluster)
+			clusters = append(clusters, cluster)
+
+			if cfg.IsProtocolDetectionEnabled(dstService.Namespace) {
+				tcpCluster, err := getUpstreamServiceCluster(proxyIdentity, dstService, append(serviceOpts, tcpProtocolDetection)...)
+				if err != nil {
+					log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetricForProxy(errcode.ErrObtainingUpstreamServiceCluster, proxy.GetCertificateCommonName())).
+						Msgf("Failed to construct TCP service cluster for service %s for proxy %s", dstService.Name, proxy.String())
+					return nil, err
+				}
+				clusters = append(clusters, tcpCluster)
+			}
+		}
 	}
 
 	svcList, err := proxyRegistry.ListProxyServices(proxy)
 	if err != nil {
-		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrFetchingServiceList)).
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetricForProxy(errcode.ErrFetchingServiceList, proxy.GetCertificateCommonName())).
 			Msgf("Error looking up MeshService for proxy %s", proxy.String())
 		return nil, err
 	}
 
+	pod, err := envoy.GetPodFromCertificate(proxy.GetCertificateCommonName(), meshCatalog.GetKubeController())
+	if err != nil {
+		log.Warn().Msgf("Could not find pod for connecting proxy %s. No metadata was recorded.", proxy.GetCertificateSerialNumber())
+	}
+
 	// Create a local cluster for each service behind the proxy.
 	// The local cluster will be used to handle incoming traffic.
 	for _, proxyService := range svcList {
-		localClusterName := envoy.GetLocalClusterNameForService(proxyService)
-		localCluster, err := getLocalServiceCluster(meshCatalog, proxyService, localClusterName)
+		localClusters, err := getLocalServiceClusters(meshCatalog, proxyService, pod)
 		if err != nil {
-			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGettingLocalServiceCluster)).
+			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetricForProxy(errcode.ErrGettingLocalServiceCluster, proxy.GetCertificateCommonName())).
 				Msgf("Failed to get local cluster config for proxy %s", proxyService)
 			return nil, err
 		}
-		clusters = append(clusters, localCluster)
+		if pod != nil {
+			if locality := getLocalServiceLocality(meshCatalog.GetKubeController(), proxyService, pod); locality != nil {
+				for _, localCluster := range localClusters {
+					for _, localityEndpoints := range localCluster.LoadAssignment.Endpoints {
+						localityEndpoints.Locality = locality
+					}
+				}
+			}
+		}
+		clusters = append(clusters, localClusters...)
 	}
 
 	// Add egress clusters based on applied policies
@@ -84,10 +132,16 @@ func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_d
 		log.Error().Err(err).Msgf("Error retrieving egress policies for proxy with identity %s, skipping egress clusters", proxyIdentity)
 	} else {
 		if egressTrafficPolicy != nil {
-			clusters = append(clusters, getEgressClusters(egressTrafficPolicy.ClustersConfigs)...)
+			clusters = append(clusters, getEgressClusters(egressTrafficPolicy.ClustersConfigs, cfg.GetDNSResolutionConfig())...)
 		}
 	}
 
+	// Add clusters for the services registered outside the mesh by external endpoints providers that the proxy is
+	// allowed to reach
+	for _, externalSvc := range meshCatalog.ListExternalServicesForIdentity(proxyIdentity) {
+		clusters = append(clusters, getExternalServiceCluster(externalSvc))
+	}
+
 	outboundPassthroughCluser, err := getOriginalDestinationEgressCluster(envoy.OutboundPassthroughCluster)
 	if err != nil {
 		log.Error().Err(err).Str(errcode.Kind, errcode.ErrGettingOrgDstEgressCluster.String()).
@@ -101,9 +155,7 @@ func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_d
 	}
 
 	// Add an inbound prometheus cluster (from Prometheus to localhost)
-	if pod, err := envoy.GetPodFromCertificate(proxy.GetCertificateCommonName(), meshCatalog.GetKubeController()); err != nil {
-		log.Warn().Msgf("Could not find pod for connecting proxy %s. No metadata was recorded.", proxy.GetCertificateSerialNumber())
-	} else if meshCatalog.GetKubeController().IsMetricsEnabled(pod) {
+	if pod != nil && meshCatalog.GetKubeController().IsMetricsEnabled(pod) {
 		clusters = append(clusters, getPrometheusCluster())
 	}
 
@@ -3,6 +3,7 @@ package cds
 import (
 	"context"
 	"fmt"
+	"net"
 	"testing"
 	"time"
 
@@ -31,6 +32,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/endpoint"
 	"github.com/openservicemesh/osm/pkg/envoy"
 	"github.com/openservicemesh/osm/pkg/envoy/registry"
 	"github.com/openservicemesh/osm/pkg/envoy/secrets"
@@ -62,10 +64,15 @@ func TestNewResponse(t *testing.T) {
 		return []service.MeshService{tests.BookbuyerService}, nil
 	}))
 
+	retryBudget := &trafficpolicy.RetryBudget{BudgetPercent: constants.DefaultRetryBudgetPercent, MinRetryConcurrency: constants.DefaultMinRetryConcurrency}
 	mockCatalog.EXPECT().ListOutboundServicesForIdentity(tests.BookbuyerServiceIdentity).Return([]service.MeshService{tests.BookstoreV1Service, tests.BookstoreV2Service}).AnyTimes()
 	mockCatalog.EXPECT().GetTargetPortToProtocolMappingForService(tests.BookbuyerService).Return(map[uint32]string{uint32(80): "protocol"}, nil)
+	mockCatalog.EXPECT().ListServicePorts(gomock.Any()).Return([]service.ServicePort{{Port: 80, TargetPort: 80, Protocol: "protocol"}}, nil).AnyTimes()
+	mockCatalog.EXPECT().GetUpstreamRetryBudget(gomock.Any()).Return(retryBudget).AnyTimes()
 	mockCatalog.EXPECT().GetEgressTrafficPolicy(tests.BookbuyerServiceIdentity).Return(nil, nil).AnyTimes()
+	mockCatalog.EXPECT().ListExternalServicesForIdentity(gomock.Any()).Return(nil).AnyTimes()
 	mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().IsProtocolDetectionEnabled(gomock.Any()).Return(false).AnyTimes()
 	mockConfigurator.EXPECT().IsEgressEnabled().Return(true).AnyTimes()
 	mockConfigurator.EXPECT().IsTracingEnabled().Return(true).AnyTimes()
 	mockConfigurator.EXPECT().GetTracingHost().Return(constants.DefaultTracingHost).AnyTimes()
@@ -87,6 +94,7 @@ func TestNewResponse(t *testing.T) {
 
 	mockKubeController.EXPECT().ListPods().Return([]*v1.Pod{&newPod1})
 	mockKubeController.EXPECT().IsMetricsEnabled(&newPod1).Return(true)
+	mockKubeController.EXPECT().GetService(gomock.Any()).Return(nil).AnyTimes()
 
 	resp, err := NewResponse(mockCatalog, proxy, nil, mockConfigurator, nil, proxyRegistry)
 	assert.Nil(err)
@@ -170,7 +178,8 @@ func TestNewResponse(t *testing.T) {
 			},
 			ServiceName: "",
 		},
-		ConnectTimeout: ptypes.DurationProto(clusterConnectTimeout),
+		ConnectTimeout:  ptypes.DurationProto(clusterConnectTimeout),
+		CircuitBreakers: getRetryBudgetCircuitBreakers(retryBudget),
 		TransportSocket: &xds_core.TransportSocket{
 			Name: wellknown.TransportSocketTls,
 			ConfigType: &xds_core.TransportSocket_TypedConfig{
@@ -199,7 +208,8 @@ func TestNewResponse(t *testing.T) {
 			},
 			ServiceName: "",
 		},
-		ConnectTimeout: ptypes.DurationProto(clusterConnectTimeout),
+		ConnectTimeout:  ptypes.DurationProto(clusterConnectTimeout),
+		CircuitBreakers: getRetryBudgetCircuitBreakers(retryBudget),
 		TransportSocket: &xds_core.TransportSocket{
 			Name: wellknown.TransportSocketTls,
 			ConfigType: &xds_core.TransportSocket_TypedConfig{
@@ -395,6 +405,7 @@ func TestNewResponseListServicesError(t *testing.T) {
 	cfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{EnableMulticlusterMode: false}).AnyTimes()
 	meshCatalog.EXPECT().ListOutboundServicesForIdentity(proxyIdentity).Return(nil).AnyTimes()
 	cfg.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
+	cfg.EXPECT().IsProtocolDetectionEnabled(gomock.Any()).Return(false).AnyTimes()
 
 	resp, err := NewResponse(meshCatalog, proxy, nil, cfg, nil, proxyRegistry)
 	tassert.Error(t, err)
@@ -421,12 +432,14 @@ func TestNewResponseGetLocalServiceClusterError(t *testing.T) {
 	tassert.Nil(t, err)
 
 	meshCatalog.EXPECT().ListOutboundServicesForIdentity(proxyIdentity).Return(nil).Times(1)
+	meshCatalog.EXPECT().ListServicePorts(svc).Return([]service.ServicePort{{Port: 80, TargetPort: 8080, Protocol: "http"}}, nil).Times(1)
 	meshCatalog.EXPECT().GetTargetPortToProtocolMappingForService(svc).Return(nil, errors.New("some error")).Times(1)
 	meshCatalog.EXPECT().GetKubeController().Return(mockKubeController).AnyTimes()
 	mockKubeController.EXPECT().ListPods().Return([]*v1.Pod{})
 	cfg.EXPECT().IsTracingEnabled().Return(false).Times(1)
 	cfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{EnableMulticlusterMode: false}).AnyTimes()
 	cfg.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
+	cfg.EXPECT().IsProtocolDetectionEnabled(gomock.Any()).Return(false).AnyTimes()
 
 	resp, err := NewResponse(meshCatalog, proxy, nil, cfg, nil, proxyRegistry)
 	tassert.Error(t, err)
@@ -449,12 +462,14 @@ func TestNewResponseGetEgressTrafficPolicyError(t *testing.T) {
 
 	meshCatalog.EXPECT().ListOutboundServicesForIdentity(proxyIdentity).Return(nil).Times(1)
 	meshCatalog.EXPECT().GetEgressTrafficPolicy(proxyIdentity).Return(nil, errors.New("some error")).Times(1)
+	meshCatalog.EXPECT().ListExternalServicesForIdentity(gomock.Any()).Return(nil).Times(1)
 	meshCatalog.EXPECT().GetKubeController().Return(mockKubeController).AnyTimes()
 	mockKubeController.EXPECT().ListPods().Return([]*v1.Pod{})
 	cfg.EXPECT().IsEgressEnabled().Return(false).Times(1)
 	cfg.EXPECT().IsTracingEnabled().Return(false).Times(1)
 	cfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{EnableMulticlusterMode: false}).AnyTimes()
 	cfg.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
+	cfg.EXPECT().IsProtocolDetectionEnabled(gomock.Any()).Return(false).AnyTimes()
 
 	resp, err := NewResponse(meshCatalog, proxy, nil, cfg, nil, proxyRegistry)
 	tassert.NoError(t, err)
@@ -483,10 +498,13 @@ func TestNewResponseGetEgressTrafficPolicyNotEmpty(t *testing.T) {
 			{Name: "my-cluster"}, // the test ensures this duplicate is removed
 		},
 	}, nil).Times(1)
+	meshCatalog.EXPECT().ListExternalServicesForIdentity(gomock.Any()).Return(nil).Times(1)
 	cfg.EXPECT().IsEgressEnabled().Return(false).Times(1)
 	cfg.EXPECT().IsTracingEnabled().Return(false).Times(1)
 	cfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{EnableMulticlusterMode: false}).AnyTimes()
+	cfg.EXPECT().GetDNSResolutionConfig().Return(v1alpha1.DNSResolutionSpec{}).Times(1)
 	cfg.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
+	cfg.EXPECT().IsProtocolDetectionEnabled(gomock.Any()).Return(false).AnyTimes()
 
 	resp, err := NewResponse(meshCatalog, proxy, nil, cfg, nil, proxyRegistry)
 	tassert.NoError(t, err)
@@ -494,6 +512,42 @@ func TestNewResponseGetEgressTrafficPolicyNotEmpty(t *testing.T) {
 	tassert.Equal(t, resp[0].(*xds_cluster.Cluster).Name, "my-cluster")
 }
 
+func TestNewResponseWithExternalServices(t *testing.T) {
+	proxyIdentity := identity.K8sServiceAccount{Name: "svcacc", Namespace: "ns"}.ToServiceIdentity()
+	proxyRegistry := registry.NewProxyRegistry(registry.ExplicitProxyServiceMapper(func(*envoy.Proxy) ([]service.MeshService, error) {
+		return nil, nil
+	}))
+	cn := envoy.NewXDSCertCommonName(uuid.New(), envoy.KindSidecar, "svcacc", "ns")
+	proxy, err := envoy.NewProxy(cn, "", nil)
+	tassert.Nil(t, err)
+
+	ctrl := gomock.NewController(t)
+	meshCatalog := catalog.NewMockMeshCataloger(ctrl)
+	mockKubeController := k8s.NewMockController(ctrl)
+	cfg := configurator.NewMockConfigurator(ctrl)
+	meshCatalog.EXPECT().ListOutboundServicesForIdentity(proxyIdentity).Return(nil).Times(1)
+	meshCatalog.EXPECT().GetKubeController().Return(mockKubeController).AnyTimes()
+	mockKubeController.EXPECT().ListPods().Return([]*v1.Pod{})
+	meshCatalog.EXPECT().GetEgressTrafficPolicy(proxyIdentity).Return(nil, nil).Times(1)
+	meshCatalog.EXPECT().ListExternalServicesForIdentity(gomock.Any()).Return([]endpoint.ExternalService{
+		{Provider: "Consul", Name: "web", Endpoints: []endpoint.Endpoint{{IP: net.ParseIP("10.0.0.1"), Port: 8080}}},
+	}).Times(1)
+	cfg.EXPECT().IsEgressEnabled().Return(false).Times(1)
+	cfg.EXPECT().IsTracingEnabled().Return(false).Times(1)
+	cfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{EnableMulticlusterMode: false}).AnyTimes()
+	cfg.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
+	cfg.EXPECT().IsProtocolDetectionEnabled(gomock.Any()).Return(false).AnyTimes()
+
+	resp, err := NewResponse(meshCatalog, proxy, nil, cfg, nil, proxyRegistry)
+	tassert.NoError(t, err)
+	tassert.Len(t, resp, 1)
+
+	cluster := resp[0].(*xds_cluster.Cluster)
+	tassert.Equal(t, "external/Consul/web", cluster.Name)
+	tassert.Equal(t, xds_cluster.Cluster_EDS, cluster.GetType())
+	tassert.Nil(t, cluster.TransportSocket)
+}
+
 func TestNewResponseForMulticlusterGateway(t *testing.T) {
 	assert := tassert.New(t)
 
@@ -510,6 +564,7 @@ func TestNewResponseForMulticlusterGateway(t *testing.T) {
 
 	cfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{EnableMulticlusterMode: true}).AnyTimes()
 	cfg.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
+	cfg.EXPECT().IsProtocolDetectionEnabled(gomock.Any()).Return(false).AnyTimes()
 	meshCatalog.EXPECT().ListOutboundServicesForMulticlusterGateway().Return([]service.MeshService{
 		tests.BookstoreV1Service,
 	}).AnyTimes()
@@ -521,6 +576,58 @@ func TestNewResponseForMulticlusterGateway(t *testing.T) {
 	assert.Equal(tests.BookstoreV1Service.ServerName(), resp[0].(*xds_cluster.Cluster).Name)
 }
 
+func TestNewResponseForMulticlusterGatewayWithTrustDomains(t *testing.T) {
+	assert := tassert.New(t)
+
+	proxyRegistry := registry.NewProxyRegistry(registry.ExplicitProxyServiceMapper(func(*envoy.Proxy) ([]service.MeshService, error) {
+		return nil, nil
+	}))
+	cn := envoy.NewXDSCertCommonName(uuid.New(), envoy.KindGateway, "osm", "osm-system")
+	proxy, err := envoy.NewProxy(cn, "", nil)
+	assert.Nil(err)
+
+	ctrl := gomock.NewController(t)
+	meshCatalog := catalog.NewMockMeshCataloger(ctrl)
+	cfg := configurator.NewMockConfigurator(ctrl)
+	trustBundleStore := certificate.NewMockTrustBundleStore(ctrl)
+	certManager := struct {
+		certificate.Manager
+		certificate.TrustBundleStore
+	}{certificate.NewMockManager(ctrl), trustBundleStore}
+
+	cfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{EnableMulticlusterMode: true}).AnyTimes()
+	cfg.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
+	cfg.EXPECT().IsProtocolDetectionEnabled(gomock.Any()).Return(false).AnyTimes()
+	trustBundleStore.EXPECT().ListTrustDomains().Return([]string{"cluster-b.example.com"}).AnyTimes()
+	meshCatalog.EXPECT().ListOutboundServicesForMulticlusterGateway().Return([]service.MeshService{
+		tests.BookstoreV1Service,
+		tests.BookstoreV2Service,
+	}).AnyTimes()
+	// Only exported services are reachable from remote trust domains
+	meshCatalog.EXPECT().ListExportedServicesForMulticlusterGateway().Return([]service.MeshService{
+		tests.BookstoreV1Service,
+	}).AnyTimes()
+	meshCatalog.EXPECT().GetTargetPortToProtocolMappingForService(tests.BookstoreV1Service).Return(map[uint32]string{uint32(80): "protocol"}, nil).Times(2)
+	meshCatalog.EXPECT().GetTargetPortToProtocolMappingForService(tests.BookstoreV2Service).Return(map[uint32]string{uint32(80): "protocol"}, nil).Times(1)
+
+	resp, err := NewResponse(meshCatalog, proxy, nil, cfg, certManager, proxyRegistry)
+	assert.NoError(err)
+	assert.Len(resp, 3)
+
+	passthroughCluster := resp[0].(*xds_cluster.Cluster)
+	assert.Equal(tests.BookstoreV1Service.ServerName(), passthroughCluster.Name)
+	assert.Nil(passthroughCluster.TransportSocket)
+
+	passthroughCluster = resp[1].(*xds_cluster.Cluster)
+	assert.Equal(tests.BookstoreV2Service.ServerName(), passthroughCluster.Name)
+	assert.Nil(passthroughCluster.TransportSocket)
+
+	mTLSCluster := resp[2].(*xds_cluster.Cluster)
+	assert.Equal(envoy.GetMulticlusterGatewayMTLSClusterName(tests.BookstoreV1Service), mTLSCluster.Name)
+	assert.Equal(mTLSCluster.Name, mTLSCluster.LoadAssignment.ClusterName)
+	assert.NotNil(mTLSCluster.TransportSocket)
+}
+
 func TestRemoveDups(t *testing.T) {
 	assert := tassert.New(t)
 
@@ -1,6 +1,8 @@
 package eds
 
 import (
+	"sort"
+
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
 
@@ -17,8 +19,13 @@ const (
 
 // newClusterLoadAssignment returns the cluster load assignments for the given service and its endpoints
 func newClusterLoadAssignment(serviceName service.MeshService, serviceEndpoints []endpoint.Endpoint) *xds_endpoint.ClusterLoadAssignment {
+	return newClusterLoadAssignmentForCluster(serviceName.String(), serviceEndpoints)
+}
+
+// newClusterLoadAssignmentForCluster returns the cluster load assignments for the given cluster and its endpoints
+func newClusterLoadAssignmentForCluster(clusterName string, serviceEndpoints []endpoint.Endpoint) *xds_endpoint.ClusterLoadAssignment {
 	cla := &xds_endpoint.ClusterLoadAssignment{
-		ClusterName: serviceName.String(),
+		ClusterName: clusterName,
 		Endpoints: []*xds_endpoint.LocalityLbEndpoints{
 			{
 				Locality: &xds_core.Locality{
@@ -33,10 +40,21 @@ func newClusterLoadAssignment(serviceName service.MeshService, serviceEndpoints
 	if lenIPs == 0 {
 		lenIPs = 1
 	}
-	weight := uint32(100 / lenIPs)
+	defaultWeight := uint32(100 / lenIPs)
 
-	for _, meshEndpoint := range serviceEndpoints {
-		log.Trace().Msgf("[EDS][ClusterLoadAssignment] Adding Endpoint: Cluster=%s, Services=%s, Endpoint=%+v, Weight=%d", serviceName, serviceName, meshEndpoint, weight)
+	// If any endpoint carries an explicit weight (ex. a remote multicluster gateway weighted by the number of
+	// healthy endpoints it fronts), endpoints are weighted relative to each other, with each endpoint lacking an
+	// explicit weight representing a single backend.
+	if hasWeightedEndpoints(serviceEndpoints) {
+		defaultWeight = 1
+	}
+
+	for _, meshEndpoint := range sortEndpoints(serviceEndpoints) {
+		weight := defaultWeight
+		if meshEndpoint.Weight > 0 {
+			weight = meshEndpoint.Weight
+		}
+		log.Trace().Msgf("[EDS][ClusterLoadAssignment] Adding Endpoint: Cluster=%s, Endpoint=%+v, Weight=%d", clusterName, meshEndpoint, weight)
 		lbEpt := xds_endpoint.LbEndpoint{
 			HostIdentifier: &xds_endpoint.LbEndpoint_Endpoint{
 				Endpoint: &xds_endpoint.Endpoint{
@@ -47,8 +65,35 @@ func newClusterLoadAssignment(serviceName service.MeshService, serviceEndpoints
 				Value: weight,
 			},
 		}
+		if meshEndpoint.Draining {
+			lbEpt.HealthStatus = xds_core.HealthStatus_DRAINING
+		}
 		cla.Endpoints[0].LbEndpoints = append(cla.Endpoints[0].LbEndpoints, &lbEpt)
 	}
 	log.Debug().Msgf("[EDS] Constructed ClusterLoadAssignment: %+v", cla)
 	return cla
 }
+
+// hasWeightedEndpoints returns true if any of the given endpoints carries an explicit weight
+func hasWeightedEndpoints(endpoints []endpoint.Endpoint) bool {
+	for _, ep := range endpoints {
+		if ep.Weight > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// sortEndpoints returns a copy of the given endpoints sorted by IP and port, so that the cluster load assignment
+// doesn't change when the endpoints are listed in a different order
+func sortEndpoints(endpoints []endpoint.Endpoint) []endpoint.Endpoint {
+	sorted := make([]endpoint.Endpoint, len(endpoints))
+	copy(sorted, endpoints)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if ipI, ipJ := sorted[i].IP.String(), sorted[j].IP.String(); ipI != ipJ {
+			return ipI < ipJ
+		}
+		return sorted[i].Port < sorted[j].Port
+	})
+	return sorted
+}
@@ -4,6 +4,7 @@ import (
 	"net"
 	"testing"
 
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	tassert "github.com/stretchr/testify/assert"
 
 	"github.com/openservicemesh/osm/pkg/endpoint"
@@ -48,4 +49,45 @@ func TestNewClusterLoadAssignment(t *testing.T) {
 	assert.Equal(cla3.ClusterName, "osm/bookstore-1")
 	assert.Len(cla3.Endpoints, 1)
 	assert.Len(cla3.Endpoints[0].LbEndpoints, 0)
+
+	// Endpoints with explicit weights are weighted relative to endpoints without weights
+	weightedEndpoints := []endpoint.Endpoint{
+		{IP: net.IP("0.0.0.1")},
+		{IP: net.IP("0.0.0.2"), Weight: 3},
+	}
+	cla4 := newClusterLoadAssignment(namespacedServices[1], weightedEndpoints)
+	assert.NotNil(cla4)
+	assert.Len(cla4.Endpoints[0].LbEndpoints, 2)
+	assert.Equal(cla4.Endpoints[0].LbEndpoints[0].GetLoadBalancingWeight().Value, uint32(1))
+	assert.Equal(cla4.Endpoints[0].LbEndpoints[1].GetLoadBalancingWeight().Value, uint32(3))
+
+	// Draining endpoints have a draining health status
+	drainingEndpoints := []endpoint.Endpoint{
+		{IP: net.IP("0.0.0.1")},
+		{IP: net.IP("0.0.0.2"), Draining: true},
+	}
+	cla5 := newClusterLoadAssignment(namespacedServices[1], drainingEndpoints)
+	assert.NotNil(cla5)
+	assert.Len(cla5.Endpoints[0].LbEndpoints, 2)
+	assert.Equal(xds_core.HealthStatus_UNKNOWN, cla5.Endpoints[0].LbEndpoints[0].HealthStatus)
+	assert.Equal(xds_core.HealthStatus_DRAINING, cla5.Endpoints[0].LbEndpoints[1].HealthStatus)
+}
+
+func TestSortEndpoints(t *testing.T) {
+	assert := tassert.New(t)
+
+	endpoints := []endpoint.Endpoint{
+		{IP: net.ParseIP("10.0.0.2"), Port: 80},
+		{IP: net.ParseIP("10.0.0.1"), Port: 8080},
+		{IP: net.ParseIP("10.0.0.1"), Port: 80},
+	}
+
+	assert.Equal([]endpoint.Endpoint{
+		{IP: net.ParseIP("10.0.0.1"), Port: 80},
+		{IP: net.ParseIP("10.0.0.1"), Port: 8080},
+		{IP: net.ParseIP("10.0.0.2"), Port: 80},
+	}, sortEndpoints(endpoints))
+
+	// The given endpoints are not modified
+	assert.Equal(net.ParseIP("10.0.0.2"), endpoints[0].IP)
 }
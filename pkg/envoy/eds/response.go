@@ -1,8 +1,6 @@
 package eds
 
 import (
-	"strings"
-
 	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
 	"github.com/pkg/errors"
@@ -17,10 +15,6 @@ import (
 	"github.com/openservicemesh/osm/pkg/service"
 )
 
-const (
-	namespacedNameDelimiter = "/"
-)
-
 // NewResponse creates a new Endpoint Discovery Response.
 func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, request *xds_discovery.DiscoveryRequest, _ configurator.Configurator, _ certificate.Manager, _ *registry.ProxyRegistry) ([]types.Resource, error) {
 	// If request comes through and requests specific endpoints, just attempt to answer those
@@ -44,9 +38,16 @@ func fulfillEDSRequest(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, re
 		return nil, errors.Errorf("Endpoint discovery request for proxy %s cannot be nil", proxyIdentity)
 	}
 
+	externalServices := getExternalServicesByCluster(meshCatalog, proxyIdentity)
+
 	var rdsResources []types.Resource
 	for _, cluster := range request.ResourceNames {
-		meshSvc, err := clusterToMeshSvc(cluster)
+		if externalSvc, ok := externalServices[cluster]; ok {
+			rdsResources = append(rdsResources, newClusterLoadAssignmentForCluster(cluster, externalSvc.Endpoints))
+			continue
+		}
+
+		meshSvc, port, err := service.ParseClusterName(cluster)
 		if err != nil {
 			log.Error().Err(err).Msgf("Error retrieving MeshService from Cluster %s", cluster)
 			continue
@@ -56,8 +57,16 @@ func fulfillEDSRequest(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, re
 			log.Error().Err(err).Msgf("Failed listing allowed endpoints for service %s, for proxy identity %s", meshSvc, proxyIdentity)
 			continue
 		}
-		loadAssignment := newClusterLoadAssignment(meshSvc, endpoints)
-		rdsResources = append(rdsResources, loadAssignment)
+		if port != 0 {
+			// The cluster backs a single port of a service exposing multiple ports
+			ports, err := meshCatalog.ListServicePorts(meshSvc)
+			if err != nil {
+				log.Error().Err(err).Msgf("Failed listing the ports of service %s for cluster %s", meshSvc, cluster)
+				continue
+			}
+			endpoints = filterEndpointsForPort(endpoints, ports, port)
+		}
+		rdsResources = append(rdsResources, newClusterLoadAssignmentForCluster(cluster, endpoints))
 	}
 
 	return rdsResources, nil
@@ -79,22 +88,72 @@ func generateEDSConfig(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy) ([
 
 	var rdsResources []types.Resource
 	for svc, endpoints := range allowedEndpoints {
-		loadAssignment := newClusterLoadAssignment(svc, endpoints)
-		rdsResources = append(rdsResources, loadAssignment)
+		ports, err := meshCatalog.ListServicePorts(svc)
+		if err != nil || len(ports) <= 1 {
+			loadAssignment := newClusterLoadAssignment(svc, endpoints)
+			rdsResources = append(rdsResources, loadAssignment)
+			continue
+		}
+
+		// Services exposing multiple ports are backed by a cluster per port
+		for _, port := range ports {
+			loadAssignment := newClusterLoadAssignmentForCluster(svc.PortClusterName(port.Port).String(), filterEndpointsForPort(endpoints, ports, port.Port))
+			rdsResources = append(rdsResources, loadAssignment)
+		}
+	}
+
+	for cluster, externalSvc := range getExternalServicesByCluster(meshCatalog, proxyIdentity) {
+		rdsResources = append(rdsResources, newClusterLoadAssignmentForCluster(cluster, externalSvc.Endpoints))
 	}
 
 	return rdsResources, nil
 }
 
-func clusterToMeshSvc(cluster string) (service.MeshService, error) {
-	chunks := strings.Split(cluster, namespacedNameDelimiter)
-	if len(chunks) != 2 {
-		return service.MeshService{}, errors.Errorf("Invalid cluster name. Expected: <namespace>/<name>, Got: %s", cluster)
+// getExternalServicesByCluster returns the services registered outside the mesh that the given identity is allowed to
+// reach, keyed by the name of their cluster
+func getExternalServicesByCluster(meshCatalog catalog.MeshCataloger, proxyIdentity identity.ServiceIdentity) map[string]endpoint.ExternalService {
+	externalServices := make(map[string]endpoint.ExternalService)
+	for _, externalSvc := range meshCatalog.ListExternalServicesForIdentity(proxyIdentity) {
+		externalServices[externalSvc.ClusterName()] = externalSvc
+	}
+	return externalServices
+}
+
+// filterEndpointsForPort returns the endpoints of a service exposing the given ports that serve the given port. The
+// endpoints naming the service port they serve are matched by name, since the pods of the service may resolve a named
+// target port to different ports. Otherwise, the endpoints serving the target port of another port of the service are
+// excluded, while the endpoints that don't serve any of the target ports of the service, such as the multicluster
+// gateways fronting remote endpoints, are kept.
+func filterEndpointsForPort(endpoints []endpoint.Endpoint, ports []service.ServicePort, port uint32) []endpoint.Endpoint {
+	var portName string
+	var targetPort uint32
+	otherTargetPorts := make(map[uint32]struct{})
+	for _, p := range ports {
+		if p.Port == port {
+			portName = p.Name
+			targetPort = p.TargetPort
+		} else {
+			otherTargetPorts[p.TargetPort] = struct{}{}
+		}
+	}
+
+	var filtered []endpoint.Endpoint
+	for _, ep := range endpoints {
+		if ep.PortName != "" {
+			if ep.PortName == portName {
+				filtered = append(filtered, ep)
+			}
+			continue
+		}
+		if uint32(ep.Port) == targetPort {
+			filtered = append(filtered, ep)
+			continue
+		}
+		if _, ok := otherTargetPorts[uint32(ep.Port)]; !ok {
+			filtered = append(filtered, ep)
+		}
 	}
-	return service.MeshService{
-		Namespace: chunks[0],
-		Name:      chunks[1],
-	}, nil
+	return filtered
 }
 
 // getEndpointsForProxy returns only those service endpoints that belong to the allowed outbound service accounts for the proxy
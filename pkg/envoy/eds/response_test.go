@@ -2,6 +2,7 @@ package eds
 
 import (
 	"fmt"
+	"net"
 	"testing"
 
 	xds_endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
@@ -18,6 +19,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/endpoint"
 	"github.com/openservicemesh/osm/pkg/envoy"
 	"github.com/openservicemesh/osm/pkg/tests"
 )
@@ -86,43 +88,70 @@ func TestEndpointConfiguration(t *testing.T) {
 	assert.Len(loadAssignment.Endpoints, 1)
 }
 
-func TestClusterToMeshSvc(t *testing.T) {
-	testCases := []struct {
-		name            string
-		cluster         string
-		expectedMeshSvc service.MeshService
-		expectError     bool
-	}{
-		{
-			name:            "invalid cluster name",
-			cluster:         "foo/bar/local",
-			expectedMeshSvc: service.MeshService{},
-			expectError:     true,
-		},
-		{
-			name:            "invalid cluster name",
-			cluster:         "foobar",
-			expectedMeshSvc: service.MeshService{},
-			expectError:     true,
-		},
+func TestEndpointConfigurationForExternalServices(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+
+	certCommonName := certificate.CommonName(fmt.Sprintf("%s.%s.%s.%s", tests.ProxyUUID, envoy.KindSidecar, tests.BookbuyerServiceAccountName, tests.Namespace))
+	proxy, err := envoy.NewProxy(certCommonName, "123456", nil)
+	assert.Nil(err)
+
+	mockCatalog.EXPECT().ListExternalServicesForIdentity(gomock.Any()).Return([]endpoint.ExternalService{
 		{
-			name:    "valid cluster name",
-			cluster: "foo/bar",
-			expectedMeshSvc: service.MeshService{
-				Namespace: "foo",
-				Name:      "bar",
+			Provider: "Consul",
+			Name:     "web",
+			Endpoints: []endpoint.Endpoint{
+				{IP: net.ParseIP("10.0.0.1"), Port: 8080},
+				{IP: net.ParseIP("10.0.0.2"), Port: 8080},
 			},
-			expectError: false,
 		},
+	}).Times(1)
+
+	request := &xds_discovery.DiscoveryRequest{
+		ResourceNames: []string{"external/Consul/web"},
+	}
+	resources, err := fulfillEDSRequest(mockCatalog, proxy, request)
+	assert.Nil(err)
+	assert.Len(resources, 1)
+
+	loadAssignment, ok := resources[0].(*xds_endpoint.ClusterLoadAssignment)
+	assert.True(ok)
+	assert.Equal("external/Consul/web", loadAssignment.ClusterName)
+	assert.Len(loadAssignment.Endpoints, 1)
+	assert.Len(loadAssignment.Endpoints[0].LbEndpoints, 2)
+}
+
+func TestFilterEndpointsForPort(t *testing.T) {
+	assert := tassert.New(t)
+
+	ports := []service.ServicePort{
+		{Name: "http", Port: 80, TargetPort: 8080, Protocol: "http"},
+		{Name: "db", Port: 5432, TargetPort: 5432, Protocol: "tcp"},
 	}
+	httpEndpoint := endpoint.Endpoint{IP: net.ParseIP("10.0.0.1"), Port: 8080}
+	dbEndpoint := endpoint.Endpoint{IP: net.ParseIP("10.0.0.1"), Port: 5432}
+	gatewayEndpoint := endpoint.Endpoint{IP: net.ParseIP("10.1.0.1"), Port: 15443}
+	endpoints := []endpoint.Endpoint{httpEndpoint, dbEndpoint, gatewayEndpoint}
 
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			assert := tassert.New(t)
+	assert.ElementsMatch([]endpoint.Endpoint{httpEndpoint, gatewayEndpoint}, filterEndpointsForPort(endpoints, ports, 80))
+	assert.ElementsMatch([]endpoint.Endpoint{dbEndpoint, gatewayEndpoint}, filterEndpointsForPort(endpoints, ports, 5432))
+}
+
+func TestFilterEndpointsForNamedTargetPort(t *testing.T) {
+	assert := tassert.New(t)
 
-			meshSvc, err := clusterToMeshSvc(tc.cluster)
-			assert.Equal(tc.expectError, err != nil)
-			assert.Equal(tc.expectedMeshSvc, meshSvc)
-		})
+	// The 'web' target port is resolved to 8080 and 9090 on different pods, and 'admin' to 9090 on the first pod
+	ports := []service.ServicePort{
+		{Name: "http", Port: 80, TargetPort: 8080, TargetPortName: "web", Protocol: "http"},
+		{Name: "admin", Port: 9000, TargetPort: 9090, TargetPortName: "admin", Protocol: "http"},
 	}
+	pod1HTTPEndpoint := endpoint.Endpoint{IP: net.ParseIP("10.0.0.1"), Port: 8080, PortName: "http"}
+	pod1AdminEndpoint := endpoint.Endpoint{IP: net.ParseIP("10.0.0.1"), Port: 9090, PortName: "admin"}
+	pod2HTTPEndpoint := endpoint.Endpoint{IP: net.ParseIP("10.0.0.2"), Port: 9090, PortName: "http"}
+	gatewayEndpoint := endpoint.Endpoint{IP: net.ParseIP("10.1.0.1"), Port: 15443}
+	endpoints := []endpoint.Endpoint{pod1HTTPEndpoint, pod1AdminEndpoint, pod2HTTPEndpoint, gatewayEndpoint}
+
+	assert.ElementsMatch([]endpoint.Endpoint{pod1HTTPEndpoint, pod2HTTPEndpoint, gatewayEndpoint}, filterEndpointsForPort(endpoints, ports, 80))
+	assert.ElementsMatch([]endpoint.Endpoint{pod1AdminEndpoint, gatewayEndpoint}, filterEndpointsForPort(endpoints, ports, 9000))
 }
@@ -0,0 +1,43 @@
+package lds
+
+import (
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// getIngressClientAddressConfig returns how the address of the ingress clients is determined for the requests matching
+// the given ingress traffic match, the IngressBackend's configuration taking precedence over the MeshConfig's.
+// When the source IP of the ingress clients is preserved up to the backend, the remote address of the downstream
+// connections is the client address, so it is always trusted.
+func (lb *listenerBuilder) getIngressClientAddressConfig(trafficMatch *trafficpolicy.IngressTrafficMatch) trafficpolicy.ClientAddressPolicy {
+	var config trafficpolicy.ClientAddressPolicy
+	if trafficMatch.ClientAddress != nil {
+		config = *trafficMatch.ClientAddress
+	} else {
+		meshConfig := lb.cfg.GetIngressClientAddressConfig()
+		config = trafficpolicy.ClientAddressPolicy{
+			UseRemoteAddress:    meshConfig.UseRemoteAddress,
+			XFFNumTrustedHops:   meshConfig.XFFNumTrustedHops,
+			InternalUnixSockets: meshConfig.InternalAddressConfig.UnixSockets,
+		}
+	}
+
+	config.UseRemoteAddress = config.UseRemoteAddress || trafficMatch.PreserveSourceIP
+	return config
+}
+
+// setClientAddress configures how the given connection manager determines the address of the downstream clients, from
+// the remote address of the connections and the X-Forwarded-For header of the requests, as per the given config
+func setClientAddress(connManager *xds_hcm.HttpConnectionManager, config trafficpolicy.ClientAddressPolicy) {
+	if config.UseRemoteAddress {
+		connManager.UseRemoteAddress = &wrappers.BoolValue{Value: true}
+	}
+	connManager.XffNumTrustedHops = config.XFFNumTrustedHops
+	if config.InternalUnixSockets {
+		connManager.InternalAddressConfig = &xds_hcm.HttpConnectionManager_InternalAddressConfig{
+			UnixSockets: true,
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package lds
+
+import (
+	"testing"
+
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestGetIngressClientAddressConfig(t *testing.T) {
+	meshConfig := configv1alpha1.IngressClientAddressSpec{
+		XFFNumTrustedHops: 1,
+		InternalAddressConfig: configv1alpha1.InternalAddressConfigSpec{
+			UnixSockets: true,
+		},
+	}
+
+	testCases := []struct {
+		name           string
+		trafficMatch   *trafficpolicy.IngressTrafficMatch
+		expectedConfig trafficpolicy.ClientAddressPolicy
+	}{
+		{
+			name:         "MeshConfig applies without IngressBackend config",
+			trafficMatch: &trafficpolicy.IngressTrafficMatch{},
+			expectedConfig: trafficpolicy.ClientAddressPolicy{
+				XFFNumTrustedHops:   1,
+				InternalUnixSockets: true,
+			},
+		},
+		{
+			name: "IngressBackend config takes precedence over MeshConfig",
+			trafficMatch: &trafficpolicy.IngressTrafficMatch{
+				ClientAddress: &trafficpolicy.ClientAddressPolicy{
+					UseRemoteAddress:  true,
+					XFFNumTrustedHops: 3,
+				},
+			},
+			expectedConfig: trafficpolicy.ClientAddressPolicy{
+				UseRemoteAddress:  true,
+				XFFNumTrustedHops: 3,
+			},
+		},
+		{
+			name: "remote address is trusted when the source IP is preserved",
+			trafficMatch: &trafficpolicy.IngressTrafficMatch{
+				PreserveSourceIP: true,
+				ClientAddress:    &trafficpolicy.ClientAddressPolicy{XFFNumTrustedHops: 2},
+			},
+			expectedConfig: trafficpolicy.ClientAddressPolicy{
+				UseRemoteAddress:  true,
+				XFFNumTrustedHops: 2,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockConfigurator.EXPECT().GetIngressClientAddressConfig().Return(meshConfig).AnyTimes()
+
+			lb := &listenerBuilder{cfg: mockConfigurator}
+			assert.Equal(tc.expectedConfig, lb.getIngressClientAddressConfig(tc.trafficMatch))
+		})
+	}
+}
+
+func TestSetClientAddress(t *testing.T) {
+	testCases := []struct {
+		name                          string
+		config                        trafficpolicy.ClientAddressPolicy
+		expectedUseRemoteAddress      bool
+		expectedXFFNumTrustedHops     uint32
+		expectedInternalAddressConfig *xds_hcm.HttpConnectionManager_InternalAddressConfig
+	}{
+		{
+			name:   "default config keeps the proxy defaults",
+			config: trafficpolicy.ClientAddressPolicy{},
+		},
+		{
+			name: "remote address trusted behind trusted proxies",
+			config: trafficpolicy.ClientAddressPolicy{
+				UseRemoteAddress:    true,
+				XFFNumTrustedHops:   2,
+				InternalUnixSockets: true,
+			},
+			expectedUseRemoteAddress:  true,
+			expectedXFFNumTrustedHops: 2,
+			expectedInternalAddressConfig: &xds_hcm.HttpConnectionManager_InternalAddressConfig{
+				UnixSockets: true,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			connManager := &xds_hcm.HttpConnectionManager{}
+			setClientAddress(connManager, tc.config)
+			assert.Equal(tc.expectedUseRemoteAddress, connManager.GetUseRemoteAddress().GetValue())
+			assert.Equal(tc.expectedXFFNumTrustedHops, connManager.XffNumTrustedHops)
+			assert.Equal(tc.expectedInternalAddressConfig, connManager.InternalAddressConfig)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package lds
+
+import (
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+)
+
+// forwardClientCertDetails maps the MeshConfig values handling the x-forwarded-client-cert header to their Envoy values
+var forwardClientCertDetails = map[string]xds_hcm.HttpConnectionManager_ForwardClientCertDetails{
+	"sanitize":            xds_hcm.HttpConnectionManager_SANITIZE,
+	"forward_only":        xds_hcm.HttpConnectionManager_FORWARD_ONLY,
+	"append_forward":      xds_hcm.HttpConnectionManager_APPEND_FORWARD,
+	"sanitize_set":        xds_hcm.HttpConnectionManager_SANITIZE_SET,
+	"always_forward_only": xds_hcm.HttpConnectionManager_ALWAYS_FORWARD_ONLY,
+}
+
+// setClientCertDetails configures the given connection manager to forward the details of the client certificates of
+// mTLS connections in the x-forwarded-client-cert header as per the given config. Invalid values are ignored, the
+// header being sanitized by default.
+func setClientCertDetails(connManager *xds_hcm.HttpConnectionManager, config configv1alpha1.ClientCertDetailsSpec) {
+	if config.ForwardClientCertDetails != "" {
+		forwardDetails, ok := forwardClientCertDetails[config.ForwardClientCertDetails]
+		if !ok {
+			log.Warn().Msgf("Ignoring invalid forwardClientCertDetails value %q in the MeshConfig", config.ForwardClientCertDetails)
+			return
+		}
+		connManager.ForwardClientCertDetails = forwardDetails
+	}
+
+	currentDetails := config.SetCurrentClientCertDetails
+	if currentDetails == (configv1alpha1.SetCurrentClientCertDetailsSpec{}) {
+		return
+	}
+	connManager.SetCurrentClientCertDetails = &xds_hcm.HttpConnectionManager_SetCurrentClientCertDetails{
+		Subject: &wrappers.BoolValue{Value: currentDetails.Subject},
+		Cert:    currentDetails.Cert,
+		Chain:   currentDetails.Chain,
+		Dns:     currentDetails.DNS,
+		Uri:     currentDetails.URI,
+	}
+}
@@ -0,0 +1,73 @@
+package lds
+
+import (
+	"testing"
+
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	tassert "github.com/stretchr/testify/assert"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+)
+
+func TestSetClientCertDetails(t *testing.T) {
+	testCases := []struct {
+		name                   string
+		config                 configv1alpha1.ClientCertDetailsSpec
+		expectedForward        xds_hcm.HttpConnectionManager_ForwardClientCertDetails
+		expectedCurrentDetails *xds_hcm.HttpConnectionManager_SetCurrentClientCertDetails
+	}{
+		{
+			name:                   "default config sanitizes the header",
+			config:                 configv1alpha1.ClientCertDetailsSpec{},
+			expectedForward:        xds_hcm.HttpConnectionManager_SANITIZE,
+			expectedCurrentDetails: nil,
+		},
+		{
+			name: "sanitize and set the subject and URI of the client certificate",
+			config: configv1alpha1.ClientCertDetailsSpec{
+				ForwardClientCertDetails: "sanitize_set",
+				SetCurrentClientCertDetails: configv1alpha1.SetCurrentClientCertDetailsSpec{
+					Subject: true,
+					URI:     true,
+				},
+			},
+			expectedForward: xds_hcm.HttpConnectionManager_SANITIZE_SET,
+			expectedCurrentDetails: &xds_hcm.HttpConnectionManager_SetCurrentClientCertDetails{
+				Subject: &wrappers.BoolValue{Value: true},
+				Uri:     true,
+			},
+		},
+		{
+			name: "always forward only",
+			config: configv1alpha1.ClientCertDetailsSpec{
+				ForwardClientCertDetails: "always_forward_only",
+			},
+			expectedForward:        xds_hcm.HttpConnectionManager_ALWAYS_FORWARD_ONLY,
+			expectedCurrentDetails: nil,
+		},
+		{
+			name: "invalid value is ignored",
+			config: configv1alpha1.ClientCertDetailsSpec{
+				ForwardClientCertDetails: "invalid",
+				SetCurrentClientCertDetails: configv1alpha1.SetCurrentClientCertDetailsSpec{
+					Cert: true,
+				},
+			},
+			expectedForward:        xds_hcm.HttpConnectionManager_SANITIZE,
+			expectedCurrentDetails: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			connManager := &xds_hcm.HttpConnectionManager{}
+			setClientCertDetails(connManager, tc.config)
+
+			assert.Equal(tc.expectedForward, connManager.ForwardClientCertDetails)
+			assert.Equal(tc.expectedCurrentDetails, connManager.SetCurrentClientCertDetails)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package lds
+
+import (
+	"strconv"
+	"strings"
+
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_brotli "github.com/envoyproxy/go-control-plane/envoy/extensions/compression/brotli/compressor/v3"
+	xds_gzip "github.com/envoyproxy/go-control-plane/envoy/extensions/compression/gzip/compressor/v3"
+	xds_compressor "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/compressor/v3"
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+const (
+	// compressorFilterName is the name of the HTTP filter compressing responses
+	compressorFilterName = "envoy.filters.http.compressor"
+
+	// compressionAlgorithmGzip is the name of the gzip compression algorithm
+	compressionAlgorithmGzip = "gzip"
+
+	// compressionAlgorithmBrotli is the name of the brotli compression algorithm
+	compressionAlgorithmBrotli = "brotli"
+)
+
+// getCompressionConfig returns the compression configuration of the responses of the given service, nil if its
+// responses are not compressed. The MeshConfig compression configuration is overridden by the compression
+// annotations of the service.
+func (lb *listenerBuilder) getCompressionConfig(svc service.MeshService) *configv1alpha1.CompressionSpec {
+	compression := lb.cfg.GetCompressionConfig()
+
+	if k8sSvc := lb.meshCatalog.GetKubeController().GetService(svc); k8sSvc != nil {
+		annotations := k8sSvc.Annotations
+		if value, ok := annotations[constants.CompressionAnnotation]; ok {
+			if enable, err := strconv.ParseBool(value); err != nil {
+				log.Warn().Err(err).Msgf("Ignoring invalid %s annotation on service %s", constants.CompressionAnnotation, svc)
+			} else {
+				compression.Enable = enable
+			}
+		}
+		if value, ok := annotations[constants.CompressionAlgorithmsAnnotation]; ok {
+			compression.Algorithms = splitAnnotationList(value)
+		}
+		if value, ok := annotations[constants.CompressionContentTypesAnnotation]; ok {
+			compression.ContentTypes = splitAnnotationList(value)
+		}
+		if value, ok := annotations[constants.CompressionMinContentLengthAnnotation]; ok {
+			if minContentLength, err := strconv.ParseUint(value, 10, 32); err != nil {
+				log.Warn().Err(err).Msgf("Ignoring invalid %s annotation on service %s", constants.CompressionMinContentLengthAnnotation, svc)
+			} else {
+				compression.MinContentLength = uint32(minContentLength)
+			}
+		}
+	}
+
+	if !compression.Enable {
+		return nil
+	}
+	if len(compression.Algorithms) == 0 {
+		compression.Algorithms = []string{compressionAlgorithmGzip}
+	}
+
+	return &compression
+}
+
+// splitAnnotationList returns the non-empty items of the given comma-separated annotation value
+func splitAnnotationList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getCompressorFilters returns the HTTP filters compressing responses with the given compression configuration,
+// one per algorithm in order of preference
+func getCompressorFilters(compression *configv1alpha1.CompressionSpec) ([]*xds_hcm.HttpFilter, error) {
+	var filters []*xds_hcm.HttpFilter
+	for _, algorithm := range compression.Algorithms {
+		var library proto.Message
+		var libraryName string
+		switch strings.ToLower(algorithm) {
+		case compressionAlgorithmGzip:
+			library = &xds_gzip.Gzip{}
+			libraryName = "envoy.compression.gzip.compressor"
+		case compressionAlgorithmBrotli:
+			library = &xds_brotli.Brotli{}
+			libraryName = "envoy.compression.brotli.compressor"
+		default:
+			log.Warn().Msgf("Ignoring unsupported compression algorithm %s", algorithm)
+			continue
+		}
+
+		libraryAny, err := ptypes.MarshalAny(library)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error marshaling %s compressor library", algorithm)
+		}
+
+		commonConfig := &xds_compressor.Compressor_CommonDirectionConfig{
+			ContentType: compression.ContentTypes,
+		}
+		if compression.MinContentLength > 0 {
+			commonConfig.MinContentLength = wrapperspb.UInt32(compression.MinContentLength)
+		}
+
+		compressor := &xds_compressor.Compressor{
+			ResponseDirectionConfig: &xds_compressor.Compressor_ResponseDirectionConfig{
+				CommonConfig: commonConfig,
+			},
+			CompressorLibrary: &xds_core.TypedExtensionConfig{
+				Name:        libraryName,
+				TypedConfig: libraryAny,
+			},
+		}
+		compressorAny, err := ptypes.MarshalAny(compressor)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error marshaling %s compressor filter", algorithm)
+		}
+
+		filters = append(filters, &xds_hcm.HttpFilter{
+			Name: compressorFilterName,
+			ConfigType: &xds_hcm.HttpFilter_TypedConfig{
+				TypedConfig: compressorAny,
+			},
+		})
+	}
+
+	return filters, nil
+}
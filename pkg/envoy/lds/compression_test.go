@@ -0,0 +1,139 @@
+package lds
+
+import (
+	"testing"
+
+	xds_compressor "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/compressor/v3"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/tests"
+)
+
+func TestGetCompressionConfig(t *testing.T) {
+	testCases := []struct {
+		name              string
+		meshConfig        configv1alpha1.CompressionSpec
+		annotations       map[string]string
+		expectedConfig    *configv1alpha1.CompressionSpec
+		serviceNotInCache bool
+	}{
+		{
+			name:           "compression disabled",
+			meshConfig:     configv1alpha1.CompressionSpec{},
+			expectedConfig: nil,
+		},
+		{
+			name:       "compression enabled in MeshConfig defaults to gzip",
+			meshConfig: configv1alpha1.CompressionSpec{Enable: true, MinContentLength: 100},
+			expectedConfig: &configv1alpha1.CompressionSpec{
+				Enable:           true,
+				Algorithms:       []string{"gzip"},
+				MinContentLength: 100,
+			},
+			serviceNotInCache: true,
+		},
+		{
+			name:       "compression disabled by annotation",
+			meshConfig: configv1alpha1.CompressionSpec{Enable: true},
+			annotations: map[string]string{
+				constants.CompressionAnnotation: "false",
+			},
+			expectedConfig: nil,
+		},
+		{
+			name:       "compression enabled and configured by annotations",
+			meshConfig: configv1alpha1.CompressionSpec{MinContentLength: 100},
+			annotations: map[string]string{
+				constants.CompressionAnnotation:                 "true",
+				constants.CompressionAlgorithmsAnnotation:       "brotli, gzip",
+				constants.CompressionContentTypesAnnotation:     "application/json,text/html",
+				constants.CompressionMinContentLengthAnnotation: "1024",
+			},
+			expectedConfig: &configv1alpha1.CompressionSpec{
+				Enable:           true,
+				Algorithms:       []string{"brotli", "gzip"},
+				ContentTypes:     []string{"application/json", "text/html"},
+				MinContentLength: 1024,
+			},
+		},
+		{
+			name:       "invalid annotations are ignored",
+			meshConfig: configv1alpha1.CompressionSpec{Enable: true, MinContentLength: 100},
+			annotations: map[string]string{
+				constants.CompressionAnnotation:                 "maybe",
+				constants.CompressionMinContentLengthAnnotation: "-1",
+			},
+			expectedConfig: &configv1alpha1.CompressionSpec{
+				Enable:           true,
+				Algorithms:       []string{"gzip"},
+				MinContentLength: 100,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+			mockKubeController := k8s.NewMockController(mockCtrl)
+
+			lb := &listenerBuilder{
+				meshCatalog: mockCatalog,
+				cfg:         mockConfigurator,
+			}
+
+			var svc *corev1.Service
+			if !tc.serviceNotInCache {
+				svc = &corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        tests.BookstoreV1Service.Name,
+						Namespace:   tests.BookstoreV1Service.Namespace,
+						Annotations: tc.annotations,
+					},
+				}
+			}
+			mockConfigurator.EXPECT().GetCompressionConfig().Return(tc.meshConfig)
+			mockCatalog.EXPECT().GetKubeController().Return(mockKubeController)
+			mockKubeController.EXPECT().GetService(tests.BookstoreV1Service).Return(svc)
+
+			assert.Equal(tc.expectedConfig, lb.getCompressionConfig(tests.BookstoreV1Service))
+		})
+	}
+}
+
+func TestGetCompressorFilters(t *testing.T) {
+	assert := tassert.New(t)
+
+	filters, err := getCompressorFilters(&configv1alpha1.CompressionSpec{
+		Enable:           true,
+		Algorithms:       []string{"brotli", "unknown", "gzip"},
+		ContentTypes:     []string{"application/json"},
+		MinContentLength: 1024,
+	})
+	assert.Nil(err)
+	assert.Len(filters, 2) // unknown algorithms are ignored
+
+	expectedLibraries := []string{"envoy.compression.brotli.compressor", "envoy.compression.gzip.compressor"}
+	for i, filter := range filters {
+		assert.Equal(compressorFilterName, filter.Name)
+
+		compressor := &xds_compressor.Compressor{}
+		assert.Nil(ptypes.UnmarshalAny(filter.GetTypedConfig(), compressor))
+		assert.Equal(expectedLibraries[i], compressor.CompressorLibrary.Name)
+		assert.Equal([]string{"application/json"}, compressor.ResponseDirectionConfig.CommonConfig.ContentType)
+		assert.Equal(uint32(1024), compressor.ResponseDirectionConfig.CommonConfig.MinContentLength.GetValue())
+	}
+}
@@ -0,0 +1,161 @@
+package lds
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_dns "github.com/envoyproxy/go-control-plane/envoy/data/dns/v3"
+	xds_dns_filter "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/udp/dns_filter/v3alpha"
+	"github.com/golang/protobuf/ptypes"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/errcode"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+const (
+	dnsListenerName     = "dns-listener"
+	dnsFilterName       = "envoy.filters.udp.dns_filter"
+	dnsFilterStatPrefix = "dns-proxy"
+
+	// dnsAnswerTTL is the TTL of the answers for the hostnames of mesh services, kept short so that clients pick up
+	// changes to the addresses of the services, such as the pod IPs of headless services, in a timely manner
+	dnsAnswerTTL = 30 * time.Second
+
+	// dnsResolverTimeout is the timeout of the queries forwarded to the pod's DNS resolvers
+	dnsResolverTimeout = 5 * time.Second
+
+	// dnsMaxPendingLookups is the maximum number of queries forwarded to the pod's DNS resolvers awaiting an answer
+	dnsMaxPendingLookups = 256
+)
+
+// buildDNSListener returns the UDP listener answering the DNS queries of the pod, which are redirected to the proxy
+// when the DNS proxy is enabled. The queries for the hostnames of the proxy's upstream services are answered with the
+// addresses the outbound listener matches for these services, the other queries are forwarded to the pod's DNS resolvers.
+func (lb *listenerBuilder) buildDNSListener() (*xds_listener.Listener, error) {
+	dnsFilterConfig := &xds_dns_filter.DnsFilterConfig{
+		StatPrefix: dnsFilterStatPrefix,
+		ServerConfig: &xds_dns_filter.DnsFilterConfig_ServerContextConfig{
+			ConfigSource: &xds_dns_filter.DnsFilterConfig_ServerContextConfig_InlineDnsTable{
+				InlineDnsTable: lb.getDNSTable(),
+			},
+		},
+		// Without upstream resolvers, the queries are forwarded to the resolvers of the proxy's container, i.e. the
+		// pod's DNS resolvers. These queries are sent by the proxy's user and aren't redirected back to the proxy.
+		ClientConfig: &xds_dns_filter.DnsFilterConfig_ClientContextConfig{
+			ResolverTimeout:   ptypes.DurationProto(dnsResolverTimeout),
+			MaxPendingLookups: dnsMaxPendingLookups,
+		},
+	}
+
+	marshalledDNSFilterConfig, err := ptypes.MarshalAny(dnsFilterConfig)
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingXDSResource)).
+			Msgf("Error marshalling DNS filter config for proxy with identity %s", lb.serviceIdentity)
+		return nil, err
+	}
+
+	return &xds_listener.Listener{
+		Name: dnsListenerName,
+		Address: &xds_core.Address{
+			Address: &xds_core.Address_SocketAddress{
+				SocketAddress: &xds_core.SocketAddress{
+					Protocol: xds_core.SocketAddress_UDP,
+					Address:  constants.LocalhostIPAddress,
+					PortSpecifier: &xds_core.SocketAddress_PortValue{
+						PortValue: constants.EnvoyDNSListenerPort,
+					},
+				},
+			},
+		},
+		TrafficDirection: xds_core.TrafficDirection_OUTBOUND,
+		ListenerFilters: []*xds_listener.ListenerFilter{
+			{
+				Name:       dnsFilterName,
+				ConfigType: &xds_listener.ListenerFilter_TypedConfig{TypedConfig: marshalledDNSFilterConfig},
+			},
+		},
+	}, nil
+}
+
+// getDNSTable returns the DNS table mapping the hostnames of the proxy's upstream services to their resolvable
+// addresses. Services without resolvable addresses, such as ExternalName services, aren't part of the table and the
+// queries for their hostnames are forwarded to the pod's DNS resolvers.
+func (lb *listenerBuilder) getDNSTable() *xds_dns.DnsTable {
+	dnsTable := &xds_dns.DnsTable{}
+	domains := make(map[string]struct{})
+
+	upstreamServices := lb.meshCatalog.ListMeshServicesForIdentity(lb.serviceIdentity)
+	sort.Slice(upstreamServices, func(i, j int) bool {
+		return upstreamServices[i].String() < upstreamServices[j].String()
+	})
+
+	for _, upstreamSvc := range upstreamServices {
+		addresses := lb.getDNSAddresses(upstreamSvc)
+		if len(addresses) == 0 {
+			continue
+		}
+
+		// The search domains of the pod expand the names queried from the service's namespace, such as the service's
+		// name, to the qualified hostnames of the service
+		hostnames, err := lb.meshCatalog.GetServiceHostnames(upstreamSvc, service.LocalCluster)
+		if err != nil {
+			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrServiceHostnames)).
+				Msgf("Error getting the hostnames of upstream service %s for the DNS table of proxy with identity %s", upstreamSvc, lb.serviceIdentity)
+			continue
+		}
+
+		for _, hostname := range hostnames {
+			// Hostnames qualified with a port are HTTP host headers, not DNS names
+			if strings.Contains(hostname, ":") {
+				continue
+			}
+			if _, ok := domains[hostname]; ok {
+				continue
+			}
+			domains[hostname] = struct{}{}
+
+			dnsTable.VirtualDomains = append(dnsTable.VirtualDomains, &xds_dns.DnsTable_DnsVirtualDomain{
+				Name: hostname,
+				Endpoint: &xds_dns.DnsTable_DnsEndpoint{
+					EndpointConfig: &xds_dns.DnsTable_DnsEndpoint_AddressList{
+						AddressList: &xds_dns.DnsTable_AddressList{
+							Address: addresses,
+						},
+					},
+				},
+				AnswerTtl: ptypes.DurationProto(dnsAnswerTTL),
+			})
+		}
+	}
+
+	return dnsTable
+}
+
+// getDNSAddresses returns the sorted addresses the hostnames of the given upstream service resolve to, i.e. the
+// addresses its outbound filter chains match
+func (lb *listenerBuilder) getDNSAddresses(upstreamSvc service.MeshService) []string {
+	endpoints, err := lb.meshCatalog.GetResolvableServiceEndpoints(upstreamSvc)
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGettingResolvableServiceEndpoints)).
+			Msgf("Error getting the resolvable endpoints of upstream service %s for the DNS table of proxy with identity %s", upstreamSvc, lb.serviceIdentity)
+		return nil
+	}
+
+	addressSet := make(map[string]struct{})
+	var addresses []string
+	for _, ep := range endpoints {
+		address := ep.IP.String()
+		if _, ok := addressSet[address]; ok {
+			continue
+		}
+		addressSet[address] = struct{}{}
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	return addresses
+}
@@ -0,0 +1,74 @@
+package lds
+
+import (
+	"net"
+	"testing"
+
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_dns "github.com/envoyproxy/go-control-plane/envoy/data/dns/v3"
+	xds_dns_filter "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/udp/dns_filter/v3alpha"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/endpoint"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/tests"
+)
+
+func TestBuildDNSListener(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+
+	bookstore := service.MeshService{Namespace: "bookstore-ns", Name: "bookstore"}
+	external := service.MeshService{Namespace: "bookstore-ns", Name: "external"}
+
+	mockCatalog.EXPECT().ListMeshServicesForIdentity(tests.BookbuyerServiceIdentity).Return([]service.MeshService{external, bookstore})
+	mockCatalog.EXPECT().GetResolvableServiceEndpoints(bookstore).Return([]endpoint.Endpoint{
+		{IP: net.ParseIP("10.0.0.2"), Port: 80},
+		{IP: net.ParseIP("10.0.0.1"), Port: 80},
+		{IP: net.ParseIP("10.0.0.1"), Port: 90},
+	}, nil)
+	mockCatalog.EXPECT().GetServiceHostnames(bookstore, service.LocalCluster).Return([]string{
+		"bookstore.bookstore-ns",
+		"bookstore.bookstore-ns.svc.cluster.local",
+		"bookstore.bookstore-ns:80",
+		"bookstore.bookstore-ns.svc.cluster.local:80",
+	}, nil)
+	// Services without resolvable endpoints, such as ExternalName services, are resolved by the pod's DNS resolvers
+	mockCatalog.EXPECT().GetResolvableServiceEndpoints(external).Return(nil, nil)
+
+	lb := newListenerBuilder(mockCatalog, tests.BookbuyerServiceIdentity, mockConfigurator, nil)
+	listener, err := lb.buildDNSListener()
+	assert.Nil(err)
+
+	assert.Equal(dnsListenerName, listener.Name)
+	assert.Equal(xds_core.SocketAddress_UDP, listener.Address.GetSocketAddress().Protocol)
+	assert.Equal(constants.LocalhostIPAddress, listener.Address.GetSocketAddress().Address)
+	assert.Equal(uint32(constants.EnvoyDNSListenerPort), listener.Address.GetSocketAddress().GetPortValue())
+	assert.Empty(listener.FilterChains)
+	assert.Len(listener.ListenerFilters, 1)
+	assert.Equal(dnsFilterName, listener.ListenerFilters[0].Name)
+
+	dnsFilterConfig := &xds_dns_filter.DnsFilterConfig{}
+	assert.Nil(ptypes.UnmarshalAny(listener.ListenerFilters[0].GetTypedConfig(), dnsFilterConfig))
+
+	// Queries for other hostnames are forwarded to the pod's DNS resolvers
+	assert.NotNil(dnsFilterConfig.ClientConfig)
+	assert.Empty(dnsFilterConfig.ClientConfig.UpstreamResolvers)
+
+	virtualDomains := dnsFilterConfig.ServerConfig.GetInlineDnsTable().VirtualDomains
+	assert.Len(virtualDomains, 2)
+	for i, hostname := range []string{"bookstore.bookstore-ns", "bookstore.bookstore-ns.svc.cluster.local"} {
+		assert.Equal(hostname, virtualDomains[i].Name)
+		assert.Equal(&xds_dns.DnsTable_AddressList{Address: []string{"10.0.0.1", "10.0.0.2"}}, virtualDomains[i].Endpoint.GetAddressList())
+		assert.Equal(ptypes.DurationProto(dnsAnswerTTL), virtualDomains[i].AnswerTtl)
+	}
+}
@@ -0,0 +1,156 @@
+package lds
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	udpa_type "github.com/cncf/xds/go/udpa/type/v1"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/golang/protobuf/ptypes"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"github.com/pkg/errors"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+const (
+	// connectionLimitFilterName is the name of the network filter limiting the number of concurrent connections to a
+	// filter chain
+	connectionLimitFilterName = "envoy.filters.network.connection_limit"
+
+	// connectionLimitTypeURL is the type URL of the config of the connection limit filter. The filter is configured
+	// through a TypedStruct since its config is not part of the go-control-plane version in use.
+	connectionLimitTypeURL = "type.googleapis.com/envoy.extensions.filters.network.connection_limit.v3.ConnectionLimit"
+
+	// connectionLimitStatPrefix is the prefix of the stats of the connection limit filters
+	connectionLimitStatPrefix = "connection-limit"
+)
+
+// downstreamLimitsConfig is the configuration of the limits on the downstream connections and requests to a service,
+// the limits set to 0 being unset
+type downstreamLimitsConfig struct {
+	// maxConnections is the maximum number of concurrent downstream connections to each port of the service
+	maxConnections uint32
+
+	// idleTimeout is how long downstream connections without active requests are kept open
+	idleTimeout time.Duration
+
+	// requestTimeout is how long the proxy waits to receive an entire request
+	requestTimeout time.Duration
+
+	// requestHeadersTimeout is how long the proxy waits to receive the headers of a request
+	requestHeadersTimeout time.Duration
+
+	// streamIdleTimeout is how long a request is kept open without any activity
+	streamIdleTimeout time.Duration
+}
+
+// getDownstreamLimitsConfig returns the limits on the downstream connections and requests to the given service. The
+// MeshConfig limits are overridden by the downstream limit annotations of the service. Invalid values are ignored.
+func (lb *listenerBuilder) getDownstreamLimitsConfig(svc service.MeshService) downstreamLimitsConfig {
+	limits := lb.cfg.GetDownstreamLimitsConfig()
+	config := downstreamLimitsConfig{
+		maxConnections:        limits.MaxConnections,
+		idleTimeout:           parseDownstreamTimeout(limits.IdleTimeout, "idleTimeout"),
+		requestTimeout:        parseDownstreamTimeout(limits.RequestTimeout, "requestTimeout"),
+		requestHeadersTimeout: parseDownstreamTimeout(limits.RequestHeadersTimeout, "requestHeadersTimeout"),
+		streamIdleTimeout:     parseDownstreamTimeout(limits.StreamIdleTimeout, "streamIdleTimeout"),
+	}
+
+	k8sSvc := lb.meshCatalog.GetKubeController().GetService(svc)
+	if k8sSvc == nil {
+		return config
+	}
+
+	if value, ok := k8sSvc.Annotations[constants.MaxConnectionsAnnotation]; ok {
+		if maxConnections, err := strconv.ParseUint(value, 10, 32); err != nil {
+			log.Warn().Err(err).Msgf("Ignoring invalid %s annotation on service %s", constants.MaxConnectionsAnnotation, svc)
+		} else {
+			config.maxConnections = uint32(maxConnections)
+		}
+	}
+
+	for annotation, timeout := range map[string]*time.Duration{
+		constants.IdleTimeoutAnnotation:       &config.idleTimeout,
+		constants.RequestTimeoutAnnotation:    &config.requestTimeout,
+		constants.StreamIdleTimeoutAnnotation: &config.streamIdleTimeout,
+	} {
+		value, ok := k8sSvc.Annotations[annotation]
+		if !ok {
+			continue
+		}
+		if duration, err := time.ParseDuration(value); err != nil || duration < 0 {
+			log.Warn().Err(err).Msgf("Ignoring invalid %s annotation on service %s", annotation, svc)
+		} else {
+			*timeout = duration
+		}
+	}
+
+	return config
+}
+
+// parseDownstreamTimeout returns the duration of the given MeshConfig downstream timeout, 0 if unset or invalid
+func parseDownstreamTimeout(value string, field string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		log.Warn().Err(err).Msgf("Ignoring invalid downstreamLimits.%s value %q in the MeshConfig", field, value)
+		return 0
+	}
+	return duration
+}
+
+// setDownstreamTimeouts configures the given connection manager to close the downstream connections and to reset the
+// requests that are idle or take longer than the given timeouts, so that slow clients can't hold the resources of
+// the service indefinitely
+func setDownstreamTimeouts(connManager *xds_hcm.HttpConnectionManager, config downstreamLimitsConfig) {
+	if config.idleTimeout > 0 {
+		if connManager.CommonHttpProtocolOptions == nil {
+			connManager.CommonHttpProtocolOptions = &xds_core.HttpProtocolOptions{}
+		}
+		connManager.CommonHttpProtocolOptions.IdleTimeout = ptypes.DurationProto(config.idleTimeout)
+	}
+	if config.requestTimeout > 0 {
+		connManager.RequestTimeout = ptypes.DurationProto(config.requestTimeout)
+	}
+	if config.requestHeadersTimeout > 0 {
+		connManager.RequestHeadersTimeout = ptypes.DurationProto(config.requestHeadersTimeout)
+	}
+	if config.streamIdleTimeout > 0 {
+		connManager.StreamIdleTimeout = ptypes.DurationProto(config.streamIdleTimeout)
+	}
+}
+
+// getConnectionLimitFilter returns the network filter closing the connections to a filter chain over the given
+// maximum number of concurrent connections, as soon as they are accepted. It must precede the other network filters.
+func getConnectionLimitFilter(maxConnections uint32, statPrefix string) (*xds_listener.Filter, error) {
+	connectionLimitAny, err := ptypes.MarshalAny(&udpa_type.TypedStruct{
+		TypeUrl: connectionLimitTypeURL,
+		Value: &structpb.Struct{
+			Fields: map[string]*structpb.Value{
+				"stat_prefix": {
+					Kind: &structpb.Value_StringValue{StringValue: fmt.Sprintf("%s.%s", connectionLimitStatPrefix, statPrefix)},
+				},
+				"max_connections": {
+					Kind: &structpb.Value_NumberValue{NumberValue: float64(maxConnections)},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshaling connection limit filter")
+	}
+
+	return &xds_listener.Filter{
+		Name: connectionLimitFilterName,
+		ConfigType: &xds_listener.Filter_TypedConfig{
+			TypedConfig: connectionLimitAny,
+		},
+	}, nil
+}
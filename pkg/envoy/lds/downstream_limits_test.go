@@ -0,0 +1,159 @@
+package lds
+
+import (
+	"testing"
+	"time"
+
+	udpa_type "github.com/cncf/xds/go/udpa/type/v1"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/tests"
+)
+
+func TestGetDownstreamLimitsConfig(t *testing.T) {
+	testCases := []struct {
+		name           string
+		meshConfig     configv1alpha1.DownstreamLimitsSpec
+		annotations    map[string]string
+		expectedConfig downstreamLimitsConfig
+	}{
+		{
+			name:           "no limits",
+			meshConfig:     configv1alpha1.DownstreamLimitsSpec{},
+			expectedConfig: downstreamLimitsConfig{},
+		},
+		{
+			name: "MeshConfig limits",
+			meshConfig: configv1alpha1.DownstreamLimitsSpec{
+				MaxConnections:        1024,
+				IdleTimeout:           "5m",
+				RequestTimeout:        "30s",
+				RequestHeadersTimeout: "10s",
+				StreamIdleTimeout:     "1m",
+			},
+			expectedConfig: downstreamLimitsConfig{
+				maxConnections:        1024,
+				idleTimeout:           5 * time.Minute,
+				requestTimeout:        30 * time.Second,
+				requestHeadersTimeout: 10 * time.Second,
+				streamIdleTimeout:     time.Minute,
+			},
+		},
+		{
+			name: "MeshConfig limits overridden by annotations",
+			meshConfig: configv1alpha1.DownstreamLimitsSpec{
+				MaxConnections: 1024,
+				RequestTimeout: "30s",
+			},
+			annotations: map[string]string{
+				constants.MaxConnectionsAnnotation:    "64",
+				constants.IdleTimeoutAnnotation:       "2m",
+				constants.RequestTimeoutAnnotation:    "5s",
+				constants.StreamIdleTimeoutAnnotation: "15s",
+			},
+			expectedConfig: downstreamLimitsConfig{
+				maxConnections:    64,
+				idleTimeout:       2 * time.Minute,
+				requestTimeout:    5 * time.Second,
+				streamIdleTimeout: 15 * time.Second,
+			},
+		},
+		{
+			name: "invalid values are ignored",
+			meshConfig: configv1alpha1.DownstreamLimitsSpec{
+				MaxConnections: 1024,
+				IdleTimeout:    "5",
+				RequestTimeout: "30s",
+			},
+			annotations: map[string]string{
+				constants.MaxConnectionsAnnotation:    "-1",
+				constants.RequestTimeoutAnnotation:    "-5s",
+				constants.StreamIdleTimeoutAnnotation: "forever",
+			},
+			expectedConfig: downstreamLimitsConfig{
+				maxConnections: 1024,
+				requestTimeout: 30 * time.Second,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+			mockKubeController := k8s.NewMockController(mockCtrl)
+
+			lb := &listenerBuilder{
+				meshCatalog: mockCatalog,
+				cfg:         mockConfigurator,
+			}
+
+			mockConfigurator.EXPECT().GetDownstreamLimitsConfig().Return(tc.meshConfig)
+			mockCatalog.EXPECT().GetKubeController().Return(mockKubeController)
+			mockKubeController.EXPECT().GetService(tests.BookstoreV1Service).Return(&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        tests.BookstoreV1Service.Name,
+					Namespace:   tests.BookstoreV1Service.Namespace,
+					Annotations: tc.annotations,
+				},
+			})
+
+			assert.Equal(tc.expectedConfig, lb.getDownstreamLimitsConfig(tests.BookstoreV1Service))
+		})
+	}
+}
+
+func TestSetDownstreamTimeouts(t *testing.T) {
+	assert := tassert.New(t)
+
+	// Unset timeouts keep the proxy defaults
+	connManager := &xds_hcm.HttpConnectionManager{}
+	setDownstreamTimeouts(connManager, downstreamLimitsConfig{})
+	assert.Equal(&xds_hcm.HttpConnectionManager{}, connManager)
+
+	// The idle timeout is merged with the protocol options set by the HTTP sanitization
+	connManager = &xds_hcm.HttpConnectionManager{}
+	setHTTPSanitization(connManager, configv1alpha1.HTTPSanitizationSpec{
+		HeadersWithUnderscoresAction: configv1alpha1.HeadersWithUnderscoresRejectRequest,
+	})
+	setDownstreamTimeouts(connManager, downstreamLimitsConfig{
+		idleTimeout:           5 * time.Minute,
+		requestTimeout:        30 * time.Second,
+		requestHeadersTimeout: 10 * time.Second,
+		streamIdleTimeout:     time.Minute,
+	})
+	assert.Equal(xds_core.HttpProtocolOptions_REJECT_REQUEST, connManager.CommonHttpProtocolOptions.HeadersWithUnderscoresAction)
+	assert.Equal(ptypes.DurationProto(5*time.Minute), connManager.CommonHttpProtocolOptions.IdleTimeout)
+	assert.Equal(ptypes.DurationProto(30*time.Second), connManager.RequestTimeout)
+	assert.Equal(ptypes.DurationProto(10*time.Second), connManager.RequestHeadersTimeout)
+	assert.Equal(ptypes.DurationProto(time.Minute), connManager.StreamIdleTimeout)
+}
+
+func TestGetConnectionLimitFilter(t *testing.T) {
+	assert := tassert.New(t)
+
+	filter, err := getConnectionLimitFilter(64, "default/bookstore-v1")
+	assert.Nil(err)
+	assert.Equal(connectionLimitFilterName, filter.Name)
+
+	typedStruct := &udpa_type.TypedStruct{}
+	assert.Nil(ptypes.UnmarshalAny(filter.GetTypedConfig(), typedStruct))
+	assert.Equal(connectionLimitTypeURL, typedStruct.TypeUrl)
+	assert.Equal("connection-limit.default/bookstore-v1", typedStruct.Value.Fields["stat_prefix"].GetStringValue())
+	assert.Equal(float64(64), typedStruct.Value.Fields["max_connections"].GetNumberValue())
+}
@@ -3,6 +3,7 @@ package lds
 import (
 	"fmt"
 	"net"
+	"strconv"
 
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
@@ -58,7 +59,7 @@ func (lb *listenerBuilder) getEgressFilterChainsForMatches(matches []*trafficpol
 }
 
 func (lb *listenerBuilder) getEgressHTTPFilterChain(destinationPort int) (*xds_listener.FilterChain, error) {
-	filter, err := lb.getOutboundHTTPFilter(route.GetEgressRouteConfigNameForPort(destinationPort))
+	filter, err := lb.getOutboundHTTPFilter(route.GetEgressRouteConfigNameForPort(destinationPort), false)
 	if err != nil {
 		log.Error().Err(err).Msgf("Error building HTTP filter chain for destination port [%d]", destinationPort)
 		return nil, err
@@ -113,8 +114,15 @@ func (lb *listenerBuilder) getEgressTCPFilterChain(match trafficpolicy.TrafficMa
 		})
 	}
 
+	// Matches routed to a cluster other than the port's original destination cluster, such as
+	// HTTPS hosts resolved using DNS, share the port with other matches and are named after their cluster
+	filterChainName := fmt.Sprintf("%s.%d", egressTCPFilterChainPrefix, match.DestinationPort)
+	if match.Cluster != "" && match.Cluster != strconv.Itoa(match.DestinationPort) {
+		filterChainName = fmt.Sprintf("%s.%s", egressTCPFilterChainPrefix, match.Cluster)
+	}
+
 	return &xds_listener.FilterChain{
-		Name:    fmt.Sprintf("%s.%d", egressTCPFilterChainPrefix, match.DestinationPort),
+		Name:    filterChainName,
 		Filters: []*xds_listener.Filter{tcpFilter},
 		FilterChainMatch: &xds_listener.FilterChainMatch{
 			DestinationPort: &wrapperspb.UInt32Value{
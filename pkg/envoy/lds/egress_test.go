@@ -55,6 +55,8 @@ func TestGetEgressHTTPFilterChain(t *testing.T) {
 				cfg: mockConfigurator,
 			}
 			mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+			mockConfigurator.EXPECT().GetHTTPSanitizationConfig().Return(v1alpha1.HTTPSanitizationSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetDownstreamLimitsConfig().Return(v1alpha1.DownstreamLimitsSpec{}).AnyTimes()
 			mockConfigurator.EXPECT().GetTracingEndpoint().Return("some-endpoint").AnyTimes()
 			mockConfigurator.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{
 				EnableEgressPolicy: true,
@@ -78,6 +80,7 @@ func TestGetEgressTCPFilterChain(t *testing.T) {
 	testCases := []struct {
 		name                     string
 		trafficMatch             trafficpolicy.TrafficMatch
+		expectedFilterChainName  string
 		expectedFilterChainMatch *xds_listener.FilterChainMatch
 		expectError              bool
 	}{
@@ -87,6 +90,7 @@ func TestGetEgressTCPFilterChain(t *testing.T) {
 				DestinationPort:     80,
 				DestinationProtocol: "tcp",
 			},
+			expectedFilterChainName: "egress-tcp.80",
 			expectedFilterChainMatch: &xds_listener.FilterChainMatch{
 				DestinationPort: &wrapperspb.UInt32Value{Value: 80},
 			},
@@ -99,6 +103,7 @@ func TestGetEgressTCPFilterChain(t *testing.T) {
 				DestinationProtocol: "tcp",
 				DestinationIPRanges: []string{"10.0.0.0/24", "8.8.8.8/32"},
 			},
+			expectedFilterChainName: "egress-tcp.100",
 			expectedFilterChainMatch: &xds_listener.FilterChainMatch{
 				DestinationPort: &wrapperspb.UInt32Value{Value: 100},
 				PrefixRanges: []*xds_core.CidrRange{
@@ -126,6 +131,7 @@ func TestGetEgressTCPFilterChain(t *testing.T) {
 				DestinationIPRanges: []string{"10.0.0.0/24", "8.8.8.8/32"},
 				ServerNames:         []string{"foo.com"},
 			},
+			expectedFilterChainName: "egress-tcp.100",
 			expectedFilterChainMatch: &xds_listener.FilterChainMatch{
 				DestinationPort: &wrapperspb.UInt32Value{Value: 100},
 				PrefixRanges: []*xds_core.CidrRange{
@@ -146,6 +152,21 @@ func TestGetEgressTCPFilterChain(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "egress TCP filter chain for SNI match routed to a cluster resolved using DNS",
+			trafficMatch: trafficpolicy.TrafficMatch{
+				DestinationPort:     443,
+				DestinationProtocol: "https",
+				ServerNames:         []string{"foo.com"},
+				Cluster:             "foo.com:443",
+			},
+			expectedFilterChainName: "egress-tcp.foo.com:443",
+			expectedFilterChainMatch: &xds_listener.FilterChainMatch{
+				DestinationPort: &wrapperspb.UInt32Value{Value: 443},
+				ServerNames:     []string{"foo.com"},
+			},
+			expectError: false,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -162,6 +183,7 @@ func TestGetEgressTCPFilterChain(t *testing.T) {
 			actual, err := lb.getEgressTCPFilterChain(tc.trafficMatch)
 
 			assert.Equal(tc.expectError, err != nil)
+			assert.Equal(tc.expectedFilterChainName, actual.Name)
 			assert.Equal(tc.expectedFilterChainMatch, actual.FilterChainMatch)
 			assert.Len(actual.Filters, 1) // Single TCPProxy filter
 			assert.Equal(wellknown.TCPProxy, actual.Filters[0].Name)
@@ -227,6 +249,8 @@ func TestGetEgressFilterChainsForMatches(t *testing.T) {
 				cfg: mockConfigurator,
 			}
 			mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+			mockConfigurator.EXPECT().GetHTTPSanitizationConfig().Return(v1alpha1.HTTPSanitizationSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetDownstreamLimitsConfig().Return(v1alpha1.DownstreamLimitsSpec{}).AnyTimes()
 			mockConfigurator.EXPECT().GetTracingEndpoint().Return("some-endpoint").AnyTimes()
 			mockConfigurator.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{
 				EnableEgressPolicy: true,
@@ -0,0 +1,107 @@
+package lds
+
+import (
+	"fmt"
+	"sort"
+
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_tcp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/openservicemesh/osm/pkg/endpoint"
+	"github.com/openservicemesh/osm/pkg/errcode"
+)
+
+const (
+	externalTCPFilterChainPrefix = "external-tcp"
+	externalTCPProxyStatPrefix   = "external-tcp-proxy"
+)
+
+// getExternalServiceFilterChains returns the outbound filter chains for the given services registered outside the mesh.
+// A filter chain is built per port exposed by the service's endpoints, matching the IP addresses of the endpoints on
+// that port, and proxies the connection to the service's cluster.
+func getExternalServiceFilterChains(externalServices []endpoint.ExternalService) []*xds_listener.FilterChain {
+	var filterChains []*xds_listener.FilterChain
+
+	for _, externalSvc := range externalServices {
+		endpointsByPort := make(map[endpoint.Port][]endpoint.Endpoint)
+		for _, ep := range externalSvc.Endpoints {
+			endpointsByPort[ep.Port] = append(endpointsByPort[ep.Port], ep)
+		}
+
+		ports := make([]endpoint.Port, 0, len(endpointsByPort))
+		for port := range endpointsByPort {
+			ports = append(ports, port)
+		}
+		sort.Slice(ports, func(i, j int) bool {
+			return ports[i] < ports[j]
+		})
+
+		for _, port := range ports {
+			filterChain, err := getExternalServiceTCPFilterChain(externalSvc, port, endpointsByPort[port])
+			if err != nil {
+				log.Error().Err(err).Msgf("Error building filter chain for external service %s on port %d, skipping", externalSvc.ClusterName(), port)
+				continue
+			}
+			filterChains = append(filterChains, filterChain)
+		}
+	}
+
+	return filterChains
+}
+
+// getExternalServiceTCPFilterChain returns a filter chain proxying connections to the given endpoints of the given
+// external service on the given port to the service's cluster
+func getExternalServiceTCPFilterChain(externalSvc endpoint.ExternalService, port endpoint.Port, endpoints []endpoint.Endpoint) (*xds_listener.FilterChain, error) {
+	name := fmt.Sprintf("%s.%s.%s.%d", externalTCPFilterChainPrefix, externalSvc.Provider, externalSvc.Name, port)
+
+	tcpProxy := &xds_tcp_proxy.TcpProxy{
+		StatPrefix:       fmt.Sprintf("%s.%s.%s.%d", externalTCPProxyStatPrefix, externalSvc.Provider, externalSvc.Name, port),
+		ClusterSpecifier: &xds_tcp_proxy.TcpProxy_Cluster{Cluster: externalSvc.ClusterName()},
+	}
+
+	marshalledTCPProxy, err := ptypes.MarshalAny(tcpProxy)
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingXDSResource)).
+			Msgf("Error marshalling TcpProxy for filter chain %s", name)
+		return nil, err
+	}
+
+	var destinationPrefixes []*xds_core.CidrRange
+	for _, ep := range endpoints {
+		prefixLen := uint32(singleIpv4Mask)
+		if ep.IP.To4() == nil {
+			prefixLen = singleIpv6Mask
+		}
+		destinationPrefixes = append(destinationPrefixes, &xds_core.CidrRange{
+			AddressPrefix: ep.IP.String(),
+			PrefixLen: &wrapperspb.UInt32Value{
+				Value: prefixLen,
+			},
+		})
+	}
+
+	// For deterministic ordering
+	sort.Slice(destinationPrefixes, func(i, j int) bool {
+		return destinationPrefixes[i].AddressPrefix < destinationPrefixes[j].AddressPrefix
+	})
+
+	return &xds_listener.FilterChain{
+		Name: name,
+		Filters: []*xds_listener.Filter{
+			{
+				Name:       wellknown.TCPProxy,
+				ConfigType: &xds_listener.Filter_TypedConfig{TypedConfig: marshalledTCPProxy},
+			},
+		},
+		FilterChainMatch: &xds_listener.FilterChainMatch{
+			DestinationPort: &wrapperspb.UInt32Value{
+				Value: uint32(port),
+			},
+			PrefixRanges: destinationPrefixes,
+		},
+	}, nil
+}
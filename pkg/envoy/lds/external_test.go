@@ -0,0 +1,46 @@
+package lds
+
+import (
+	"net"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/endpoint"
+)
+
+func TestGetExternalServiceFilterChains(t *testing.T) {
+	assert := tassert.New(t)
+
+	externalServices := []endpoint.ExternalService{
+		{
+			Provider: "Consul",
+			Name:     "web",
+			Endpoints: []endpoint.Endpoint{
+				{IP: net.ParseIP("10.0.0.1"), Port: 9090},
+				{IP: net.ParseIP("10.0.0.1"), Port: 8080},
+				{IP: net.ParseIP("10.0.0.2"), Port: 8080},
+			},
+		},
+		{
+			Provider:  "Consul",
+			Name:      "db",
+			Endpoints: []endpoint.Endpoint{{IP: net.ParseIP("fd00::1"), Port: 5432}},
+		},
+	}
+
+	filterChains := getExternalServiceFilterChains(externalServices)
+	assert.Len(filterChains, 3)
+
+	assert.Equal("external-tcp.Consul.web.8080", filterChains[0].Name)
+	assert.Equal(uint32(8080), filterChains[0].FilterChainMatch.DestinationPort.GetValue())
+	assert.Len(filterChains[0].FilterChainMatch.PrefixRanges, 2)
+	assert.Equal("10.0.0.1", filterChains[0].FilterChainMatch.PrefixRanges[0].AddressPrefix)
+	assert.Equal(uint32(singleIpv4Mask), filterChains[0].FilterChainMatch.PrefixRanges[0].PrefixLen.GetValue())
+
+	assert.Equal("external-tcp.Consul.web.9090", filterChains[1].Name)
+	assert.Len(filterChains[1].FilterChainMatch.PrefixRanges, 1)
+
+	assert.Equal("external-tcp.Consul.db.5432", filterChains[2].Name)
+	assert.Equal(uint32(singleIpv6Mask), filterChains[2].FilterChainMatch.PrefixRanges[0].PrefixLen.GetValue())
+}
@@ -3,14 +3,18 @@ package lds
 import (
 	"fmt"
 
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	xds_tcp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
 
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
 const (
@@ -20,7 +24,11 @@ const (
 	multiclusterGatewayListenerPort = 15443
 )
 
-func (lb *listenerBuilder) buildMulticlusterGatewayListener() (*xds_listener.Listener, error) {
+// buildMulticlusterGatewayListener builds the multicluster gateway's listener. Connections from downstreams sharing the
+// local CA are passed through to the upstream services, while connections from downstreams in the given remote trust
+// domains are terminated and validated against the CA bundle of their trust domain. Downstreams from remote trust
+// domains can only reach the services exported to remote clusters.
+func (lb *listenerBuilder) buildMulticlusterGatewayListener(trustDomains []string) (*xds_listener.Listener, error) {
 	upstreamServices := lb.meshCatalog.ListOutboundServicesForMulticlusterGateway()
 	filterChains, err := getMulticlusterGatewayFilterChains(upstreamServices)
 	if err != nil {
@@ -28,6 +36,26 @@ func (lb *listenerBuilder) buildMulticlusterGatewayListener() (*xds_listener.Lis
 		return nil, err
 	}
 
+	if len(trustDomains) == 0 {
+		return newMulticlusterGatewayListener(filterChains), nil
+	}
+
+	for _, upstreamSvc := range lb.meshCatalog.ListExportedServicesForMulticlusterGateway() {
+		for _, trustDomain := range trustDomains {
+			filterChain, err := lb.getMulticlusterGatewayTrustDomainFilterChain(upstreamSvc, trustDomain)
+			if err != nil {
+				log.Error().Err(err).Str(constants.LogFieldContext, constants.LogContextMulticluster).
+					Msgf("[Multicluster] Error creating gateway filter chain for service %s and trust domain %s", upstreamSvc, trustDomain)
+				continue
+			}
+			filterChains = append(filterChains, filterChain)
+		}
+	}
+
+	return newMulticlusterGatewayListener(filterChains), nil
+}
+
+func newMulticlusterGatewayListener(filterChains []*xds_listener.FilterChain) *xds_listener.Listener {
 	return &xds_listener.Listener{
 		Name:         multiclusterListenerName,
 		Address:      envoy.GetAddress(constants.WildcardIPAddr, multiclusterGatewayListenerPort),
@@ -37,7 +65,7 @@ func (lb *listenerBuilder) buildMulticlusterGatewayListener() (*xds_listener.Lis
 				Name: wellknown.TlsInspector,
 			},
 		},
-	}, nil
+	}
 }
 
 func getMulticlusterGatewayFilterChains(upstreamServices []service.MeshService) ([]*xds_listener.FilterChain, error) {
@@ -80,3 +108,94 @@ func getMulticlusterGatewayFilterChains(upstreamServices []service.MeshService)
 	}
 	return filterChains, nil
 }
+
+// getMulticlusterGatewayTrustDomainFilterChain returns the filter chain terminating TLS connections to the given upstream
+// service from downstreams in the given remote trust domain. Such downstreams advertise their trust domain using ALPN.
+// The gateway presents the certificate of the upstream service to the downstream, and forwards the connection to the
+// upstream over an mTLS connection it originates with its own identity. Since the upstream then only sees the gateway's
+// identity, the gateway enforces the SMI TrafficTargets of the upstream on the downstream's identity, and the upstream
+// grants the gateway the routes of those TrafficTargets (see catalog.withMulticlusterGatewaySource).
+func (lb *listenerBuilder) getMulticlusterGatewayTrustDomainFilterChain(upstreamSvc service.MeshService, trustDomain string) (*xds_listener.FilterChain, error) {
+	upstreamIdentities, err := lb.meshCatalog.ListServiceIdentitiesForService(upstreamSvc)
+	if err != nil {
+		return nil, err
+	}
+	if len(upstreamIdentities) == 0 {
+		return nil, errors.Errorf("No service identity found for service %s", upstreamSvc)
+	}
+
+	marshalledDownstreamTLSContext, err := ptypes.MarshalAny(envoy.GetDownstreamTLSContextForTrustDomain(upstreamIdentities[0], trustDomain))
+	if err != nil {
+		return nil, err
+	}
+
+	tcpProxy := &xds_tcp_proxy.TcpProxy{
+		StatPrefix:       fmt.Sprintf("%s.%s", upstreamSvc, trustDomain),
+		ClusterSpecifier: &xds_tcp_proxy.TcpProxy_Cluster{Cluster: envoy.GetMulticlusterGatewayMTLSClusterName(upstreamSvc)},
+		AccessLog:        envoy.GetAccessLog(),
+	}
+	marshalledTCPProxy, err := ptypes.MarshalAny(tcpProxy)
+	if err != nil {
+		return nil, err
+	}
+
+	filters := []*xds_listener.Filter{
+		{
+			Name: wellknown.TCPProxy,
+			ConfigType: &xds_listener.Filter_TypedConfig{
+				TypedConfig: marshalledTCPProxy,
+			},
+		},
+	}
+
+	// Apply an RBAC filter when permissive mode is disabled. The RBAC filter must be the first filter in the list of filters.
+	if !lb.cfg.IsPermissiveTrafficPolicyMode() {
+		rbacFilter, err := lb.buildMulticlusterGatewayRBACFilter(upstreamIdentities[0])
+		if err != nil {
+			return nil, err
+		}
+		filters = append([]*xds_listener.Filter{rbacFilter}, filters...)
+	}
+
+	return &xds_listener.FilterChain{
+		Name: fmt.Sprintf("%s-%s-%s", multiclusterGatewayFilterChainName, upstreamSvc.Name, trustDomain),
+		FilterChainMatch: &xds_listener.FilterChainMatch{
+			ServerNames: []string{
+				upstreamSvc.ServerName(),
+			},
+			TransportProtocol:    envoy.TransportProtocolTLS,
+			ApplicationProtocols: []string{envoy.GetTrustDomainALPN(trustDomain)},
+		},
+		Filters: filters,
+		TransportSocket: &xds_core.TransportSocket{
+			Name: wellknown.TransportSocketTls,
+			ConfigType: &xds_core.TransportSocket_TypedConfig{
+				TypedConfig: marshalledDownstreamTLSContext,
+			},
+		},
+	}, nil
+}
+
+// buildMulticlusterGatewayRBACFilter builds the RBAC filter allowing the sources of the SMI TrafficTargets of the given
+// upstream identity to connect to it through the gateway. The gateway's own identity, which the upstream allows, is
+// never allowed as a downstream since downstreams from remote trust domains could share it.
+func (lb *listenerBuilder) buildMulticlusterGatewayRBACFilter(upstreamIdentity identity.ServiceIdentity) (*xds_listener.Filter, error) {
+	trafficTargets, err := lb.meshCatalog.ListInboundTrafficTargetsWithRoutes(upstreamIdentity)
+	if err != nil {
+		return nil, err
+	}
+
+	var downstreamTargets []trafficpolicy.TrafficTargetWithRoutes
+	for _, trafficTarget := range trafficTargets {
+		var sources []identity.ServiceIdentity
+		for _, source := range trafficTarget.Sources {
+			if source != lb.serviceIdentity {
+				sources = append(sources, source)
+			}
+		}
+		trafficTarget.Sources = sources
+		downstreamTargets = append(downstreamTargets, trafficTarget)
+	}
+
+	return marshalRBACFilter(buildRBACPoliciesFromTrafficTargets(upstreamIdentity, downstreamTargets, lb.newPrincipalCompactor()))
+}
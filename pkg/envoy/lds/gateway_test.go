@@ -4,7 +4,11 @@ import (
 	"fmt"
 	"testing"
 
+	xds_network_rbac "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/rbac/v3"
+	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
 	tassert "github.com/stretchr/testify/assert"
 
 	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
@@ -13,8 +17,10 @@ import (
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/envoy"
 	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
 func TestBuildMulticlusterGatewayListeners(t *testing.T) {
@@ -39,12 +45,70 @@ func TestBuildMulticlusterGatewayListeners(t *testing.T) {
 		serviceIdentity: id,
 	}
 
-	listener, err := lb.buildMulticlusterGatewayListener()
+	listener, err := lb.buildMulticlusterGatewayListener(nil)
 	assert.Nil(err)
 	assert.Equal(listener.Name, multiclusterListenerName)
 	assert.Equal(listener.Address, envoy.GetAddress(constants.WildcardIPAddr, multiclusterGatewayListenerPort))
 	assert.Equal(len(listener.ListenerFilters), 1)
 	assert.Len(listener.FilterChains, 2)
+
+	// A filter chain terminating TLS is added per exported upstream service for each remote trust domain
+	mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(true).AnyTimes()
+	mockCatalog.EXPECT().ListExportedServicesForMulticlusterGateway().Return([]service.MeshService{tests.BookstoreV1Service}).AnyTimes()
+	mockCatalog.EXPECT().ListServiceIdentitiesForService(gomock.Any()).Return([]identity.ServiceIdentity{tests.BookstoreServiceIdentity}, nil).AnyTimes()
+	listener, err = lb.buildMulticlusterGatewayListener([]string{"cluster-b.example.com", "cluster-c.example.com"})
+	assert.Nil(err)
+	assert.Len(listener.FilterChains, 4)
+}
+
+func TestGetMulticlusterGatewayTrustDomainFilterChain(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	gatewayIdentity := identity.K8sServiceAccount{Name: "osm", Namespace: "osm-system"}.ToServiceIdentity()
+	lb := &listenerBuilder{
+		meshCatalog:     mockCatalog,
+		cfg:             mockConfigurator,
+		serviceIdentity: gatewayIdentity,
+	}
+
+	mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetSPIFFETrustDomain().Return("").AnyTimes()
+	mockCatalog.EXPECT().GetKubeController().Return(mockKubeController).AnyTimes()
+	mockCatalog.EXPECT().ListServiceIdentitiesForService(tests.BookstoreV1Service).Return([]identity.ServiceIdentity{tests.BookstoreServiceIdentity}, nil)
+	mockCatalog.EXPECT().ListInboundTrafficTargetsWithRoutes(tests.BookstoreServiceIdentity).Return([]trafficpolicy.TrafficTargetWithRoutes{{
+		Name:        "ns/bookstore",
+		Destination: tests.BookstoreServiceIdentity,
+		Sources:     []identity.ServiceIdentity{tests.BookbuyerServiceIdentity, gatewayIdentity},
+	}}, nil)
+	filterChain, err := lb.getMulticlusterGatewayTrustDomainFilterChain(tests.BookstoreV1Service, "cluster-b.example.com")
+	assert.Nil(err)
+	assert.Equal(fmt.Sprintf("%s-%s-%s", multiclusterGatewayFilterChainName, tests.BookstoreV1ServiceName, "cluster-b.example.com"), filterChain.Name)
+	assert.ElementsMatch([]string{tests.BookstoreV1Service.ServerName()}, filterChain.FilterChainMatch.ServerNames)
+	assert.Equal([]string{envoy.GetTrustDomainALPN("cluster-b.example.com")}, filterChain.FilterChainMatch.ApplicationProtocols)
+	assert.NotNil(filterChain.TransportSocket)
+	assert.Len(filterChain.Filters, 2)
+
+	// The gateway enforces the TrafficTargets of the upstream on the downstream's identity, without allowing its own
+	assert.Equal(wellknown.RoleBasedAccessControl, filterChain.Filters[0].Name)
+	networkRBAC := &xds_network_rbac.RBAC{}
+	assert.Nil(ptypes.UnmarshalAny(filterChain.Filters[0].GetTypedConfig(), networkRBAC))
+	assert.Len(networkRBAC.Rules.Policies["ns/bookstore"].Principals, 1)
+	assert.Contains(networkRBAC.Rules.Policies["ns/bookstore"].Principals[0].String(), tests.BookbuyerServiceIdentity.String())
+
+	downstreamTLSContext := &xds_auth.DownstreamTlsContext{}
+	assert.Nil(ptypes.UnmarshalAny(filterChain.TransportSocket.GetTypedConfig(), downstreamTLSContext))
+	assert.True(downstreamTLSContext.RequireClientCertificate.Value)
+	assert.Equal("root-cert-for-trust-domain:cluster-b.example.com", downstreamTLSContext.CommonTlsContext.GetValidationContextSdsSecretConfig().Name)
+
+	// A filter chain can't be built for a service without a service identity
+	mockCatalog.EXPECT().ListServiceIdentitiesForService(tests.BookstoreV2Service).Return(nil, nil)
+	_, err = lb.getMulticlusterGatewayTrustDomainFilterChain(tests.BookstoreV2Service, "cluster-b.example.com")
+	assert.NotNil(err)
 }
 
 func TestGetGatewayFilterChains(t *testing.T) {
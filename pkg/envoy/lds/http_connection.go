@@ -3,15 +3,20 @@ package lds
 import (
 	"fmt"
 
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	xds_cors "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/cors/v3"
 	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/pkg/errors"
 
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/auth"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
 // connectionDirection defines, for filter terms, the direction of a connection from
@@ -35,11 +40,57 @@ type httpConnManagerOptions struct {
 	direction         connectionDirection
 	rdsRoutConfigName string
 
+	// enableHTTP3 configures the connection manager to use the HTTP/3 codec on QUIC listeners
+	enableHTTP3 bool
+
+	// scopedRoutes configures the connection manager to select the route configuration of the requests by their host
+	// through scoped RDS, the route configurations of the hosts being fetched on demand, instead of using the single
+	// route configuration rdsRoutConfigName
+	scopedRoutes bool
+
 	// Additional filters
 	wasmStatsHeaders         map[string]string
 	extAuthConfig            *auth.ExtAuthConfig
 	enableActiveHealthChecks bool
 
+	// enableCORS configures the CORS filter, which applies the CORS policies of the virtual hosts
+	enableCORS bool
+
+	// enableCSRF configures the CSRF filter, which applies the CSRF policies of the virtual hosts
+	enableCSRF bool
+
+	// rewriteCookies configures the Lua filter setting the cookie attributes of the responses as per the virtual hosts
+	rewriteCookies bool
+
+	// compression configures the compressor filters compressing responses, if set
+	compression *configv1alpha1.CompressionSpec
+
+	// loadShedding configures the adaptive concurrency filter shedding the excess load of the service, if set,
+	// only applied to inbound connections
+	loadShedding *loadSheddingConfig
+
+	// requestLimits configures the limits on the size of requests, unlimited if unset
+	requestLimits configv1alpha1.RequestLimitsSpec
+
+	// clientCertDetails configures how the details of client certificates are forwarded in the
+	// x-forwarded-client-cert header, only applied to inbound connections
+	clientCertDetails configv1alpha1.ClientCertDetailsSpec
+
+	// httpSanitization configures how the paths and headers of requests are normalized and sanitized
+	httpSanitization configv1alpha1.HTTPSanitizationSpec
+
+	// downstreamLimits configures the timeouts of the downstream connections and requests, only applied to inbound
+	// connections
+	downstreamLimits downstreamLimitsConfig
+
+	// rbacDenialLog configures the connection manager to stream the requests denied by the RBAC policies to the
+	// controller, only applied to inbound connections
+	rbacDenialLog bool
+
+	// clientAddress configures how the connection manager determines the address of the downstream clients, from the
+	// remote address of the connections and the X-Forwarded-For header of the requests
+	clientAddress trafficpolicy.ClientAddressPolicy
+
 	// Tracing options
 	enableTracing      bool
 	tracingAPIEndpoint string
@@ -67,6 +118,88 @@ func (options httpConnManagerOptions) build() (*xds_hcm.HttpConnectionManager, e
 		AccessLog: envoy.GetAccessLog(),
 	}
 
+	// Active health checks are answered before the load is shed and before the external authorization and WASM
+	// filters, so that they don't fail while the service is overloaded or the authorization server is unavailable
+	if options.enableActiveHealthChecks {
+		hc, err := getHealthCheckFilter()
+		if err != nil {
+			return nil, errors.Wrap(err, "Error getting health check filter for HTTP connection manager")
+		}
+		connManager.HttpFilters = append(connManager.HttpFilters, hc)
+	}
+
+	// Excess load is shed before the requests are processed by the other filters
+	if options.direction == inbound && options.loadShedding != nil {
+		adaptiveConcurrencyFilter, err := getAdaptiveConcurrencyFilter(options.loadShedding)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error getting adaptive concurrency filter for HTTP connection manager")
+		}
+		connManager.HttpFilters = append(connManager.HttpFilters, adaptiveConcurrencyFilter)
+	}
+
+	// The CORS filter must precede the external authorization filter so that preflight requests don't require it
+	if options.enableCORS {
+		corsFilter, err := getCORSFilter()
+		if err != nil {
+			return nil, errors.Wrap(err, "Error getting CORS filter for HTTP connection manager")
+		}
+		connManager.HttpFilters = append(connManager.HttpFilters, corsFilter)
+	}
+
+	// Cross-site requests are rejected before reaching the external authorization filter
+	if options.enableCSRF {
+		csrfFilter, err := getCSRFFilter()
+		if err != nil {
+			return nil, errors.Wrap(err, "Error getting CSRF filter for HTTP connection manager")
+		}
+		connManager.HttpFilters = append(connManager.HttpFilters, csrfFilter)
+	}
+
+	if options.rewriteCookies {
+		luaFilter, err := getCookieAttributesLuaFilter()
+		if err != nil {
+			return nil, errors.Wrap(err, "Error getting cookie attributes Lua filter for HTTP connection manager")
+		}
+		connManager.HttpFilters = append(connManager.HttpFilters, luaFilter)
+	}
+
+	// Requests larger than the limit are rejected before reaching the external authorization filter
+	if options.requestLimits.MaxRequestBytes > 0 {
+		bufferFilter, err := getBufferFilter(options.requestLimits.MaxRequestBytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error getting buffer filter for HTTP connection manager")
+		}
+		connManager.HttpFilters = append(connManager.HttpFilters, bufferFilter)
+	}
+	if options.requestLimits.MaxRequestHeadersKb > 0 {
+		connManager.MaxRequestHeadersKb = &wrappers.UInt32Value{Value: options.requestLimits.MaxRequestHeadersKb}
+	}
+
+	if options.direction == inbound {
+		setClientCertDetails(connManager, options.clientCertDetails)
+	}
+
+	setHTTPSanitization(connManager, options.httpSanitization)
+
+	if options.direction == inbound {
+		setDownstreamTimeouts(connManager, options.downstreamLimits)
+	}
+
+	if options.direction == inbound && options.rbacDenialLog {
+		rbacDenialLog, err := getRBACDenialAccessLog()
+		if err != nil {
+			return nil, errors.Wrap(err, "Error getting RBAC denial access log for HTTP connection manager")
+		}
+		connManager.AccessLog = append(connManager.AccessLog, rbacDenialLog)
+	}
+
+	setClientAddress(connManager, options.clientAddress)
+
+	if options.enableHTTP3 {
+		connManager.CodecType = xds_hcm.HttpConnectionManager_HTTP3
+		connManager.Http3ProtocolOptions = &xds_core.Http3ProtocolOptions{}
+	}
+
 	// For inbound connections, add the Authz filter
 	if options.direction == inbound && options.extAuthConfig != nil {
 		connManager.HttpFilters = append(connManager.HttpFilters, getExtAuthzHTTPFilter(options.extAuthConfig))
@@ -95,12 +228,23 @@ func (options httpConnManagerOptions) build() (*xds_hcm.HttpConnectionManager, e
 		connManager.LocalReplyConfig = wasmLocalReplyConfig
 	}
 
-	if options.enableActiveHealthChecks {
-		hc, err := getHealthCheckFilter()
+	if options.compression != nil {
+		compressorFilters, err := getCompressorFilters(options.compression)
 		if err != nil {
-			return nil, errors.Wrap(err, "Error getting health check filter for HTTP connection manager")
+			return nil, errors.Wrap(err, "Error getting compressor filters for HTTP connection manager")
 		}
-		connManager.HttpFilters = append(connManager.HttpFilters, hc)
+		connManager.HttpFilters = append(connManager.HttpFilters, compressorFilters...)
+	}
+
+	if options.scopedRoutes {
+		connManager.RouteSpecifier = getScopedRoutes()
+
+		// The on-demand filter fetches the route configuration of the host of a request before it is routed
+		onDemandFilter, err := getOnDemandFilter()
+		if err != nil {
+			return nil, errors.Wrap(err, "Error getting on-demand filter for HTTP connection manager")
+		}
+		connManager.HttpFilters = append(connManager.HttpFilters, onDemandFilter)
 	}
 
 	// *IMPORTANT NOTE*: The Router filter must always be the last filter
@@ -109,6 +253,21 @@ func (options httpConnManagerOptions) build() (*xds_hcm.HttpConnectionManager, e
 	return connManager, nil
 }
 
+// getCORSFilter returns the CORS HTTP filter, which applies the CORS policies configured on the virtual hosts
+func getCORSFilter() (*xds_hcm.HttpFilter, error) {
+	corsAny, err := ptypes.MarshalAny(&xds_cors.Cors{})
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshaling CORS filter")
+	}
+
+	return &xds_hcm.HttpFilter{
+		Name: wellknown.CORS,
+		ConfigType: &xds_hcm.HttpFilter_TypedConfig{
+			TypedConfig: corsAny,
+		},
+	}, nil
+}
+
 func getPrometheusConnectionManager() *xds_hcm.HttpConnectionManager {
 	return &xds_hcm.HttpConnectionManager{
 		StatPrefix: prometheusHTTPConnManagerStatPrefix,
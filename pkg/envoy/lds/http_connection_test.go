@@ -8,7 +8,9 @@ import (
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/stretchr/testify/assert"
 
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/auth"
+	"github.com/openservicemesh/osm/pkg/envoy/rds/route"
 )
 
 func TestHTTPConnbuild(t *testing.T) {
@@ -38,6 +40,26 @@ func TestHTTPConnbuild(t *testing.T) {
 				a.Equal("mesh-http-conn-manager.something", connManager.StatPrefix)
 			},
 		},
+		{
+			name: "codec when HTTP/3 is disabled",
+			option: httpConnManagerOptions{
+				enableHTTP3: false,
+			},
+			assertFunc: func(a *assert.Assertions, connManager *xds_hcm.HttpConnectionManager) {
+				a.Equal(xds_hcm.HttpConnectionManager_AUTO, connManager.CodecType)
+				a.Nil(connManager.Http3ProtocolOptions)
+			},
+		},
+		{
+			name: "codec when HTTP/3 is enabled",
+			option: httpConnManagerOptions{
+				enableHTTP3: true,
+			},
+			assertFunc: func(a *assert.Assertions, connManager *xds_hcm.HttpConnectionManager) {
+				a.Equal(xds_hcm.HttpConnectionManager_HTTP3, connManager.CodecType)
+				a.NotNil(connManager.Http3ProtocolOptions)
+			},
+		},
 		{
 			name: "tracing config when tracing is enabled",
 			option: httpConnManagerOptions{
@@ -120,6 +142,26 @@ func TestHTTPConnbuild(t *testing.T) {
 				a.True(contains(connManager.HttpFilters, wellknown.HealthCheck))
 			},
 		},
+		{
+			name: "health check filter precedes load shedding, external auth and WASM filters",
+			option: httpConnManagerOptions{
+				direction:                inbound,
+				enableActiveHealthChecks: true,
+				loadShedding: &loadSheddingConfig{
+					targetLatency: 100 * time.Millisecond,
+				},
+				extAuthConfig: &auth.ExtAuthConfig{
+					Enable: true,
+				},
+			},
+			assertFunc: func(a *assert.Assertions, connManager *xds_hcm.HttpConnectionManager) {
+				a.Len(connManager.HttpFilters, 5)
+				a.Equal(wellknown.HTTPRoleBasedAccessControl, connManager.HttpFilters[0].Name)
+				a.Equal(wellknown.HealthCheck, connManager.HttpFilters[1].Name)
+				a.Equal(adaptiveConcurrencyFilterName, connManager.HttpFilters[2].Name)
+				a.Equal(wellknown.HTTPExternalAuthorization, connManager.HttpFilters[3].Name)
+			},
+		},
 		{
 			name: "health check config absent when disabled",
 			option: httpConnManagerOptions{
@@ -129,6 +171,161 @@ func TestHTTPConnbuild(t *testing.T) {
 				a.True(notContains(connManager.HttpFilters, wellknown.HealthCheck))
 			},
 		},
+		{
+			name: "CORS filter precedes external auth when enabled",
+			option: httpConnManagerOptions{
+				direction:  inbound,
+				enableCORS: true,
+				extAuthConfig: &auth.ExtAuthConfig{
+					Enable: true,
+				},
+			},
+			assertFunc: func(a *assert.Assertions, connManager *xds_hcm.HttpConnectionManager) {
+				a.Len(connManager.HttpFilters, 4)
+				a.Equal(wellknown.CORS, connManager.HttpFilters[1].Name)
+				a.Equal(wellknown.HTTPExternalAuthorization, connManager.HttpFilters[2].Name)
+			},
+		},
+		{
+			name: "compressor filters present when compression is configured",
+			option: httpConnManagerOptions{
+				compression: &configv1alpha1.CompressionSpec{
+					Enable:     true,
+					Algorithms: []string{"gzip", "brotli"},
+				},
+			},
+			assertFunc: func(a *assert.Assertions, connManager *xds_hcm.HttpConnectionManager) {
+				a.Len(connManager.HttpFilters, 4)
+				a.Equal(compressorFilterName, connManager.HttpFilters[1].Name)
+				a.Equal(compressorFilterName, connManager.HttpFilters[2].Name)
+			},
+		},
+		{
+			name: "compressor filters absent when compression is not configured",
+			option: httpConnManagerOptions{
+				compression: nil,
+			},
+			assertFunc: func(a *assert.Assertions, connManager *xds_hcm.HttpConnectionManager) {
+				a.True(notContains(connManager.HttpFilters, compressorFilterName))
+			},
+		},
+		{
+			name: "request limits configured when set",
+			option: httpConnManagerOptions{
+				direction: inbound,
+				extAuthConfig: &auth.ExtAuthConfig{
+					Enable: true,
+				},
+				requestLimits: configv1alpha1.RequestLimitsSpec{
+					MaxRequestBytes:     1024,
+					MaxRequestHeadersKb: 96,
+				},
+			},
+			assertFunc: func(a *assert.Assertions, connManager *xds_hcm.HttpConnectionManager) {
+				a.Len(connManager.HttpFilters, 4)
+				a.Equal(wellknown.Buffer, connManager.HttpFilters[1].Name)
+				a.Equal(wellknown.HTTPExternalAuthorization, connManager.HttpFilters[2].Name)
+				a.Equal(uint32(96), connManager.MaxRequestHeadersKb.GetValue())
+			},
+		},
+		{
+			name:   "request limits absent when unset",
+			option: httpConnManagerOptions{},
+			assertFunc: func(a *assert.Assertions, connManager *xds_hcm.HttpConnectionManager) {
+				a.True(notContains(connManager.HttpFilters, wellknown.Buffer))
+				a.Nil(connManager.MaxRequestHeadersKb)
+			},
+		},
+		{
+			name: "adaptive concurrency filter precedes the other filters when load shedding is configured for inbound",
+			option: httpConnManagerOptions{
+				direction:  inbound,
+				enableCORS: true,
+				loadShedding: &loadSheddingConfig{
+					targetLatency: 100 * time.Millisecond,
+				},
+			},
+			assertFunc: func(a *assert.Assertions, connManager *xds_hcm.HttpConnectionManager) {
+				a.Len(connManager.HttpFilters, 4)
+				a.Equal(adaptiveConcurrencyFilterName, connManager.HttpFilters[1].Name)
+				a.Equal(wellknown.CORS, connManager.HttpFilters[2].Name)
+			},
+		},
+		{
+			name: "adaptive concurrency filter absent for outbound",
+			option: httpConnManagerOptions{
+				direction: outbound,
+				loadShedding: &loadSheddingConfig{
+					targetLatency: 100 * time.Millisecond,
+				},
+			},
+			assertFunc: func(a *assert.Assertions, connManager *xds_hcm.HttpConnectionManager) {
+				a.True(notContains(connManager.HttpFilters, adaptiveConcurrencyFilterName))
+			},
+		},
+		{
+			name: "CORS filter absent when disabled",
+			option: httpConnManagerOptions{
+				enableCORS: false,
+			},
+			assertFunc: func(a *assert.Assertions, connManager *xds_hcm.HttpConnectionManager) {
+				a.True(notContains(connManager.HttpFilters, wellknown.CORS))
+			},
+		},
+		{
+			name: "CSRF and cookie attributes Lua filters follow CORS when enabled",
+			option: httpConnManagerOptions{
+				direction:      inbound,
+				enableCORS:     true,
+				enableCSRF:     true,
+				rewriteCookies: true,
+				extAuthConfig: &auth.ExtAuthConfig{
+					Enable: true,
+				},
+			},
+			assertFunc: func(a *assert.Assertions, connManager *xds_hcm.HttpConnectionManager) {
+				a.Len(connManager.HttpFilters, 6)
+				a.Equal(wellknown.CORS, connManager.HttpFilters[1].Name)
+				a.Equal(route.CSRFFilterName, connManager.HttpFilters[2].Name)
+				a.Equal(wellknown.Lua, connManager.HttpFilters[3].Name)
+				a.Equal(wellknown.HTTPExternalAuthorization, connManager.HttpFilters[4].Name)
+			},
+		},
+		{
+			name: "CSRF and cookie attributes Lua filters absent when disabled",
+			option: httpConnManagerOptions{
+				enableCSRF:     false,
+				rewriteCookies: false,
+			},
+			assertFunc: func(a *assert.Assertions, connManager *xds_hcm.HttpConnectionManager) {
+				a.True(notContains(connManager.HttpFilters, route.CSRFFilterName))
+				a.True(notContains(connManager.HttpFilters, wellknown.Lua))
+			},
+		},
+		{
+			name: "scoped routes and on-demand filter when scoped routes are enabled",
+			option: httpConnManagerOptions{
+				rdsRoutConfigName: "something",
+				scopedRoutes:      true,
+			},
+			assertFunc: func(a *assert.Assertions, connManager *xds_hcm.HttpConnectionManager) {
+				a.NotNil(connManager.GetScopedRoutes())
+				a.Nil(connManager.GetRds())
+				a.True(contains(connManager.HttpFilters, onDemandFilterName))
+			},
+		},
+		{
+			name: "RDS route config when scoped routes are disabled",
+			option: httpConnManagerOptions{
+				rdsRoutConfigName: "something",
+				scopedRoutes:      false,
+			},
+			assertFunc: func(a *assert.Assertions, connManager *xds_hcm.HttpConnectionManager) {
+				a.Nil(connManager.GetScopedRoutes())
+				a.Equal("something", connManager.GetRds().RouteConfigName)
+				a.True(notContains(connManager.HttpFilters, onDemandFilterName))
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,56 @@
+package lds
+
+import (
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+)
+
+// escapedSlashesActions maps the MeshConfig actions on requests with escaped slashes in their paths to their Envoy values
+var escapedSlashesActions = map[configv1alpha1.EscapedSlashesAction]xds_hcm.HttpConnectionManager_PathWithEscapedSlashesAction{
+	configv1alpha1.EscapedSlashesKeepUnchanged:       xds_hcm.HttpConnectionManager_KEEP_UNCHANGED,
+	configv1alpha1.EscapedSlashesRejectRequest:       xds_hcm.HttpConnectionManager_REJECT_REQUEST,
+	configv1alpha1.EscapedSlashesUnescapeAndRedirect: xds_hcm.HttpConnectionManager_UNESCAPE_AND_REDIRECT,
+	configv1alpha1.EscapedSlashesUnescapeAndForward:  xds_hcm.HttpConnectionManager_UNESCAPE_AND_FORWARD,
+}
+
+// headersWithUnderscoresActions maps the MeshConfig actions on requests with underscores in the names of their headers
+// to their Envoy values
+var headersWithUnderscoresActions = map[configv1alpha1.HeadersWithUnderscoresAction]xds_core.HttpProtocolOptions_HeadersWithUnderscoresAction{
+	configv1alpha1.HeadersWithUnderscoresAllow:         xds_core.HttpProtocolOptions_ALLOW,
+	configv1alpha1.HeadersWithUnderscoresRejectRequest: xds_core.HttpProtocolOptions_REJECT_REQUEST,
+	configv1alpha1.HeadersWithUnderscoresDropHeader:    xds_core.HttpProtocolOptions_DROP_HEADER,
+}
+
+// setHTTPSanitization configures the given connection manager to normalize the paths and sanitize the headers of the
+// requests as per the given config, before the routes and RBAC policies are evaluated against them. Invalid values
+// are ignored, the proxy defaults being kept.
+func setHTTPSanitization(connManager *xds_hcm.HttpConnectionManager, config configv1alpha1.HTTPSanitizationSpec) {
+	if config.NormalizePath {
+		connManager.NormalizePath = &wrappers.BoolValue{Value: true}
+	}
+	connManager.MergeSlashes = config.MergeSlashes
+
+	if config.PathWithEscapedSlashesAction != "" {
+		action, ok := escapedSlashesActions[config.PathWithEscapedSlashesAction]
+		if ok {
+			connManager.PathWithEscapedSlashesAction = action
+		} else {
+			log.Warn().Msgf("Ignoring invalid pathWithEscapedSlashesAction value %q in the MeshConfig", config.PathWithEscapedSlashesAction)
+		}
+	}
+
+	if config.HeadersWithUnderscoresAction != "" {
+		action, ok := headersWithUnderscoresActions[config.HeadersWithUnderscoresAction]
+		if ok {
+			if connManager.CommonHttpProtocolOptions == nil {
+				connManager.CommonHttpProtocolOptions = &xds_core.HttpProtocolOptions{}
+			}
+			connManager.CommonHttpProtocolOptions.HeadersWithUnderscoresAction = action
+		} else {
+			log.Warn().Msgf("Ignoring invalid headersWithUnderscoresAction value %q in the MeshConfig", config.HeadersWithUnderscoresAction)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package lds
+
+import (
+	"testing"
+
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	tassert "github.com/stretchr/testify/assert"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+)
+
+func TestSetHTTPSanitization(t *testing.T) {
+	testCases := []struct {
+		name                    string
+		config                  configv1alpha1.HTTPSanitizationSpec
+		expectedNormalizePath   *wrappers.BoolValue
+		expectedMergeSlashes    bool
+		expectedEscapedSlashes  xds_hcm.HttpConnectionManager_PathWithEscapedSlashesAction
+		expectedProtocolOptions *xds_core.HttpProtocolOptions
+	}{
+		{
+			name:                   "default config keeps the proxy defaults",
+			config:                 configv1alpha1.HTTPSanitizationSpec{},
+			expectedNormalizePath:  nil,
+			expectedMergeSlashes:   false,
+			expectedEscapedSlashes: xds_hcm.HttpConnectionManager_IMPLEMENTATION_SPECIFIC_DEFAULT,
+		},
+		{
+			name: "paths are normalized and headers with underscores dropped",
+			config: configv1alpha1.HTTPSanitizationSpec{
+				NormalizePath:                true,
+				MergeSlashes:                 true,
+				PathWithEscapedSlashesAction: configv1alpha1.EscapedSlashesUnescapeAndRedirect,
+				HeadersWithUnderscoresAction: configv1alpha1.HeadersWithUnderscoresDropHeader,
+			},
+			expectedNormalizePath:  &wrappers.BoolValue{Value: true},
+			expectedMergeSlashes:   true,
+			expectedEscapedSlashes: xds_hcm.HttpConnectionManager_UNESCAPE_AND_REDIRECT,
+			expectedProtocolOptions: &xds_core.HttpProtocolOptions{
+				HeadersWithUnderscoresAction: xds_core.HttpProtocolOptions_DROP_HEADER,
+			},
+		},
+		{
+			name: "requests with escaped slashes or headers with underscores are rejected",
+			config: configv1alpha1.HTTPSanitizationSpec{
+				PathWithEscapedSlashesAction: configv1alpha1.EscapedSlashesRejectRequest,
+				HeadersWithUnderscoresAction: configv1alpha1.HeadersWithUnderscoresRejectRequest,
+			},
+			expectedEscapedSlashes: xds_hcm.HttpConnectionManager_REJECT_REQUEST,
+			expectedProtocolOptions: &xds_core.HttpProtocolOptions{
+				HeadersWithUnderscoresAction: xds_core.HttpProtocolOptions_REJECT_REQUEST,
+			},
+		},
+		{
+			name: "invalid values are ignored",
+			config: configv1alpha1.HTTPSanitizationSpec{
+				MergeSlashes:                 true,
+				PathWithEscapedSlashesAction: "invalid",
+				HeadersWithUnderscoresAction: "invalid",
+			},
+			expectedMergeSlashes:   true,
+			expectedEscapedSlashes: xds_hcm.HttpConnectionManager_IMPLEMENTATION_SPECIFIC_DEFAULT,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			connManager := &xds_hcm.HttpConnectionManager{}
+			setHTTPSanitization(connManager, tc.config)
+
+			assert.Equal(tc.expectedNormalizePath, connManager.NormalizePath)
+			assert.Equal(tc.expectedMergeSlashes, connManager.MergeSlashes)
+			assert.Equal(tc.expectedEscapedSlashes, connManager.PathWithEscapedSlashesAction)
+			assert.Equal(tc.expectedProtocolOptions, connManager.CommonHttpProtocolOptions)
+		})
+	}
+}
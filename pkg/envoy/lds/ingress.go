@@ -1,15 +1,20 @@
 package lds
 
 import (
+	"fmt"
 	"net"
+	"sort"
 	"strings"
 
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_quic "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/quic/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/pkg/errors"
 	"google.golang.org/protobuf/types/known/wrapperspb"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/envoy"
@@ -19,76 +24,59 @@ import (
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
-func (lb *listenerBuilder) getIngressFilterChains(svc service.MeshService) []*xds_listener.FilterChain {
+// getIngressFilterChains returns the ingress filter chains for the given service, along with the ingress ports
+// accepting a PROXY protocol header
+func (lb *listenerBuilder) getIngressFilterChains(svc service.MeshService) ([]*xds_listener.FilterChain, []int) {
 	ingressPolicy, err := lb.meshCatalog.GetIngressTrafficPolicy(svc)
 	if err != nil {
 		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrIngressFilterChain)).
 			Msgf("Error getting ingress filter chain for proxy with identity %s and service %s", lb.serviceIdentity, svc)
-		return nil
+		return nil, nil
 	}
 
 	if ingressPolicy == nil {
 		log.Trace().Msgf("No ingress policy confiugred for proxy with identity %s and service %s", lb.serviceIdentity, svc)
-		return nil
+		return nil, nil
 	}
 
 	var filterChains []*xds_listener.FilterChain
+	var proxyProtocolPorts []int
 	for _, trafficMatch := range ingressPolicy.TrafficMatches {
-		if filterChain, err := lb.getIngressFilterChainFromTrafficMatch(trafficMatch); err != nil {
+		if filterChain, err := lb.getIngressFilterChainFromTrafficMatch(svc, trafficMatch); err != nil {
 			log.Error().Err(err).Msgf("Error building ingress filter chain for proxy with identity %s service %s", lb.serviceIdentity, svc)
 		} else {
 			filterChains = append(filterChains, filterChain)
+			if trafficMatch.AcceptProxyProtocol {
+				proxyProtocolPorts = append(proxyProtocolPorts, int(trafficMatch.Port))
+			}
 		}
 	}
 
-	return filterChains
+	return filterChains, proxyProtocolPorts
 }
 
-func (lb *listenerBuilder) getIngressFilterChainFromTrafficMatch(trafficMatch *trafficpolicy.IngressTrafficMatch) (*xds_listener.FilterChain, error) {
+func (lb *listenerBuilder) getIngressFilterChainFromTrafficMatch(svc service.MeshService, trafficMatch *trafficpolicy.IngressTrafficMatch) (*xds_listener.FilterChain, error) {
 	if trafficMatch == nil {
 		return nil, errors.Errorf("Nil IngressTrafficMatch for ingress on proxy with identity %s", lb.serviceIdentity)
 	}
 
-	// Build the HTTP Connection Manager filter from its options
-	ingressConnManager, err := httpConnManagerOptions{
-		direction:         inbound,
-		rdsRoutConfigName: route.IngressRouteConfigName,
-
-		// Additional filters
-		wasmStatsHeaders: nil, // no WASM Stats for ingress traffic
-		extAuthConfig:    lb.getExtAuthConfig(),
-
-		// Tracing options
-		enableTracing:      lb.cfg.IsTracingEnabled(),
-		tracingAPIEndpoint: lb.cfg.GetTracingEndpoint(),
-	}.build()
-	if err != nil {
-		return nil, errors.Errorf("Error building inbound HTTP connection manager for proxy with identity %s, traffic match: %v ", lb.serviceIdentity, trafficMatch)
-	}
-
-	marshalledIngressConnManager, err := ptypes.MarshalAny(ingressConnManager)
+	ingressConnManagerFilter, err := lb.getIngressConnManagerFilter(svc, trafficMatch, false)
 	if err != nil {
-		return nil, errors.Errorf("Error marshalling ingress HttpConnectionManager object for proxy with identity %s", lb.serviceIdentity)
+		return nil, errors.Wrapf(err, "Error building ingress filter chain for traffic match %v", trafficMatch)
 	}
+	filters := []*xds_listener.Filter{ingressConnManagerFilter}
 
-	// TODO(shashankram): add helper to convert string IP ranges to xds_core.CidirRange
-	var sourcePrefixes []*xds_core.CidrRange
-	for _, ipRange := range trafficMatch.SourceIPRanges {
-		ip, ipNet, err := net.ParseCIDR(ipRange)
+	// Connections over the limit are closed before being processed by the connection manager
+	if maxConnections := lb.getDownstreamLimitsConfig(svc).maxConnections; maxConnections > 0 {
+		connectionLimitFilter, err := getConnectionLimitFilter(maxConnections, trafficMatch.Name)
 		if err != nil {
-			log.Error().Err(err).Msgf("Error parsing IP range %s while building Ingress filter chain for match %v, skipping", ipRange, trafficMatch)
-			continue
+			return nil, errors.Wrapf(err, "Error building ingress filter chain for traffic match %v", trafficMatch)
 		}
-
-		prefixLen, _ := ipNet.Mask.Size()
-		sourcePrefixes = append(sourcePrefixes, &xds_core.CidrRange{
-			AddressPrefix: ip.String(),
-			PrefixLen: &wrapperspb.UInt32Value{
-				Value: uint32(prefixLen),
-			},
-		})
+		filters = append([]*xds_listener.Filter{connectionLimitFilter}, filters...)
 	}
 
+	sourcePrefixes := getIngressSourcePrefixRanges(trafficMatch)
+
 	filterChain := &xds_listener.FilterChain{
 		Name: trafficMatch.Name,
 		FilterChainMatch: &xds_listener.FilterChainMatch{
@@ -97,14 +85,7 @@ func (lb *listenerBuilder) getIngressFilterChainFromTrafficMatch(trafficMatch *t
 			},
 			SourcePrefixRanges: sourcePrefixes,
 		},
-		Filters: []*xds_listener.Filter{
-			{
-				Name: wellknown.HTTPConnectionManager,
-				ConfigType: &xds_listener.Filter_TypedConfig{
-					TypedConfig: marshalledIngressConnManager,
-				},
-			},
-		},
+		Filters: filters,
 	}
 
 	switch strings.ToLower(trafficMatch.Protocol) {
@@ -122,7 +103,7 @@ func (lb *listenerBuilder) getIngressFilterChainFromTrafficMatch(trafficMatch *t
 		filterChain.FilterChainMatch.TransportProtocol = envoy.TransportProtocolTLS
 		filterChain.FilterChainMatch.ServerNames = trafficMatch.ServerNames
 
-		marshalledDownstreamTLSContext, err := ptypes.MarshalAny(envoy.GetDownstreamTLSContext(lb.serviceIdentity, !trafficMatch.SkipClientCertValidation))
+		marshalledDownstreamTLSContext, err := ptypes.MarshalAny(envoy.GetIngressDownstreamTLSContext(lb.serviceIdentity, !trafficMatch.SkipClientCertValidation, trafficMatch.CertificateSecret))
 		if err != nil {
 			return nil, errors.Errorf("Error marshalling DownstreamTLSContext in ingress filter chain for proxy with identity %s", lb.serviceIdentity)
 		}
@@ -142,3 +123,203 @@ func (lb *listenerBuilder) getIngressFilterChainFromTrafficMatch(trafficMatch *t
 
 	return filterChain, nil
 }
+
+// getIngressQUICListeners returns the QUIC listeners accepting HTTP/3 ingress traffic for the HTTPS ingress traffic
+// matches of the given services, one listener per ingress port. The filter chains share the ingress route configuration
+// and the SDS certificates of the TCP ingress filter chains.
+//
+// HTTP/3 ingress is direct-to-pod: inbound traffic is only redirected to the proxy for TCP, so each QUIC listener binds
+// the ingress port over UDP on the backend's pod, and HTTP/3 clients must reach the pod on that port without going
+// through a TCP ingress controller. This is the case when the backend's Service exposes the ingress port over UDP,
+// such as a LoadBalancer Service exposing the port over both TCP and UDP, so a QUIC listener is only built for the
+// ingress ports the Service exposes over UDP.
+func (lb *listenerBuilder) getIngressQUICListeners(svcList []service.MeshService) []*xds_listener.Listener {
+	filterChainsPerPort := make(map[uint32][]*xds_listener.FilterChain)
+	for _, svc := range svcList {
+		ingressPolicy, err := lb.meshCatalog.GetIngressTrafficPolicy(svc)
+		if err != nil {
+			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrIngressFilterChain)).
+				Msgf("Error getting ingress QUIC filter chain for proxy with identity %s and service %s", lb.serviceIdentity, svc)
+			continue
+		}
+		if ingressPolicy == nil {
+			continue
+		}
+
+		udpPorts := lb.getUDPTargetPorts(svc)
+		for _, trafficMatch := range ingressPolicy.TrafficMatches {
+			if trafficMatch == nil || strings.ToLower(trafficMatch.Protocol) != constants.ProtocolHTTPS {
+				// HTTP/3 requires TLS
+				continue
+			}
+			if _, ok := udpPorts[uint32(trafficMatch.Port)]; !ok {
+				log.Warn().Msgf("Service %s does not expose HTTPS ingress port %d over UDP, HTTP/3 ingress is not configured for it on proxy with identity %s",
+					svc, trafficMatch.Port, lb.serviceIdentity)
+				continue
+			}
+			filterChain, err := lb.getIngressQUICFilterChainFromTrafficMatch(svc, trafficMatch)
+			if err != nil {
+				log.Error().Err(err).Msgf("Error building ingress QUIC filter chain for proxy with identity %s service %s", lb.serviceIdentity, svc)
+				continue
+			}
+			port := uint32(trafficMatch.Port)
+			filterChainsPerPort[port] = append(filterChainsPerPort[port], filterChain)
+		}
+	}
+
+	var ports []uint32
+	for port := range filterChainsPerPort {
+		ports = append(ports, port)
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+
+	var listeners []*xds_listener.Listener
+	for _, port := range ports {
+		listeners = append(listeners, &xds_listener.Listener{
+			Name: fmt.Sprintf("%s-%d", ingressQUICListenerPrefix, port),
+			Address: &xds_core.Address{
+				Address: &xds_core.Address_SocketAddress{
+					SocketAddress: &xds_core.SocketAddress{
+						Protocol: xds_core.SocketAddress_UDP,
+						Address:  constants.WildcardIPAddr,
+						PortSpecifier: &xds_core.SocketAddress_PortValue{
+							PortValue: port,
+						},
+					},
+				},
+			},
+			TrafficDirection: xds_core.TrafficDirection_INBOUND,
+			UdpListenerConfig: &xds_listener.UdpListenerConfig{
+				QuicOptions: &xds_listener.QuicProtocolOptions{},
+			},
+			FilterChains: filterChainsPerPort[port],
+		})
+	}
+
+	return listeners
+}
+
+// getUDPTargetPorts returns the target ports the Service of the given mesh service exposes over UDP
+func (lb *listenerBuilder) getUDPTargetPorts(svc service.MeshService) map[uint32]struct{} {
+	ports := make(map[uint32]struct{})
+	k8sSvc := lb.meshCatalog.GetKubeController().GetService(svc)
+	if k8sSvc == nil {
+		return ports
+	}
+
+	for _, port := range k8sSvc.Spec.Ports {
+		if port.Protocol != corev1.ProtocolUDP {
+			continue
+		}
+		if port.TargetPort.Type == intstr.String {
+			// Named target ports can't be resolved from the Service
+			continue
+		}
+		targetPort := uint32(port.TargetPort.IntVal)
+		if targetPort == 0 {
+			// The target port defaults to the port
+			targetPort = uint32(port.Port)
+		}
+		ports[targetPort] = struct{}{}
+	}
+	return ports
+}
+
+func (lb *listenerBuilder) getIngressQUICFilterChainFromTrafficMatch(svc service.MeshService, trafficMatch *trafficpolicy.IngressTrafficMatch) (*xds_listener.FilterChain, error) {
+	ingressConnManagerFilter, err := lb.getIngressConnManagerFilter(svc, trafficMatch, true)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error building ingress QUIC filter chain for traffic match %v", trafficMatch)
+	}
+
+	marshalledQUICTransport, err := ptypes.MarshalAny(&xds_quic.QuicDownstreamTransport{
+		DownstreamTlsContext: envoy.GetIngressDownstreamTLSContext(lb.serviceIdentity, !trafficMatch.SkipClientCertValidation, trafficMatch.CertificateSecret),
+	})
+	if err != nil {
+		return nil, errors.Errorf("Error marshalling QuicDownstreamTransport in ingress QUIC filter chain for proxy with identity %s", lb.serviceIdentity)
+	}
+
+	return &xds_listener.FilterChain{
+		Name: trafficMatch.Name,
+		FilterChainMatch: &xds_listener.FilterChainMatch{
+			ServerNames:        trafficMatch.ServerNames,
+			SourcePrefixRanges: getIngressSourcePrefixRanges(trafficMatch),
+		},
+		Filters: []*xds_listener.Filter{ingressConnManagerFilter},
+		TransportSocket: &xds_core.TransportSocket{
+			Name: wellknown.TransportSocketQuic,
+			ConfigType: &xds_core.TransportSocket_TypedConfig{
+				TypedConfig: marshalledQUICTransport,
+			},
+		},
+	}, nil
+}
+
+// getIngressConnManagerFilter returns the HTTP connection manager filter for ingress traffic to the given service
+// matching the given traffic match, using the HTTP/3 codec if enableHTTP3 is set.
+// When the source IP of the ingress clients is preserved up to the backend, the remote address of the downstream
+// connections is the client address, so it is forwarded to the application in the X-Forwarded-For header. Otherwise
+// the remote address is the address of the node or ingress gateway the traffic was proxied through, and the client
+// address is extracted from the X-Forwarded-For header as per the trusted proxies configured in front of the ingress.
+func (lb *listenerBuilder) getIngressConnManagerFilter(svc service.MeshService, trafficMatch *trafficpolicy.IngressTrafficMatch, enableHTTP3 bool) (*xds_listener.Filter, error) {
+	// Build the HTTP Connection Manager filter from its options
+	ingressConnManager, err := httpConnManagerOptions{
+		direction:         inbound,
+		rdsRoutConfigName: route.IngressRouteConfigName,
+		enableHTTP3:       enableHTTP3,
+
+		// Additional filters
+		wasmStatsHeaders:  nil, // no WASM Stats for ingress traffic
+		extAuthConfig:     lb.getExtAuthConfig(),
+		enableCORS:        true,
+		enableCSRF:        trafficMatch.EnableCSRF,
+		rewriteCookies:    trafficMatch.RewriteCookies,
+		compression:       lb.getCompressionConfig(svc),
+		loadShedding:      lb.getLoadSheddingConfig(svc),
+		requestLimits:     lb.getRequestLimitsConfig(svc),
+		clientCertDetails: lb.cfg.GetClientCertDetailsConfig(),
+		httpSanitization:  lb.cfg.GetHTTPSanitizationConfig(),
+		downstreamLimits:  lb.getDownstreamLimitsConfig(svc),
+		clientAddress:     lb.getIngressClientAddressConfig(trafficMatch),
+
+		// Tracing options
+		enableTracing:      lb.cfg.IsTracingEnabled(),
+		tracingAPIEndpoint: lb.cfg.GetTracingEndpoint(),
+	}.build()
+	if err != nil {
+		return nil, errors.Errorf("Error building inbound HTTP connection manager for proxy with identity %s", lb.serviceIdentity)
+	}
+
+	marshalledIngressConnManager, err := ptypes.MarshalAny(ingressConnManager)
+	if err != nil {
+		return nil, errors.Errorf("Error marshalling ingress HttpConnectionManager object for proxy with identity %s", lb.serviceIdentity)
+	}
+
+	return &xds_listener.Filter{
+		Name: wellknown.HTTPConnectionManager,
+		ConfigType: &xds_listener.Filter_TypedConfig{
+			TypedConfig: marshalledIngressConnManager,
+		},
+	}, nil
+}
+
+// getIngressSourcePrefixRanges returns the source IP ranges allowed by the given ingress traffic match
+func getIngressSourcePrefixRanges(trafficMatch *trafficpolicy.IngressTrafficMatch) []*xds_core.CidrRange {
+	// TODO(shashankram): add helper to convert string IP ranges to xds_core.CidirRange
+	var sourcePrefixes []*xds_core.CidrRange
+	for _, ipRange := range trafficMatch.SourceIPRanges {
+		ip, ipNet, err := net.ParseCIDR(ipRange)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error parsing IP range %s while building Ingress filter chain for match %v, skipping", ipRange, trafficMatch)
+			continue
+		}
+
+		prefixLen, _ := ipNet.Mask.Size()
+		sourcePrefixes = append(sourcePrefixes, &xds_core.CidrRange{
+			AddressPrefix: ip.String(),
+			PrefixLen: &wrapperspb.UInt32Value{
+				Value: uint32(prefixLen),
+			},
+		})
+	}
+	return sourcePrefixes
+}
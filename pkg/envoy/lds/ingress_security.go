@@ -0,0 +1,58 @@
+package lds
+
+import (
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_csrf "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/csrf/v3"
+	xds_lua "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/lua/v3"
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	xds_type "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+
+	"github.com/openservicemesh/osm/pkg/envoy/rds/route"
+)
+
+// cookieAttributesLuaCode is the default code of the Lua filter, which does nothing. The cookie attributes are set
+// by the code of the ingress virtual hosts.
+const cookieAttributesLuaCode = "-- The cookie attributes are set by the Lua code of the ingress virtual hosts"
+
+// getCSRFFilter returns the CSRF HTTP filter, disabled by default and enabled by the CSRF policies of the virtual hosts
+func getCSRFFilter() (*xds_hcm.HttpFilter, error) {
+	csrfAny, err := ptypes.MarshalAny(&xds_csrf.CsrfPolicy{
+		FilterEnabled: &xds_core.RuntimeFractionalPercent{
+			DefaultValue: &xds_type.FractionalPercent{
+				Numerator:   0,
+				Denominator: xds_type.FractionalPercent_HUNDRED,
+			},
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshaling CSRF filter")
+	}
+
+	return &xds_hcm.HttpFilter{
+		Name: route.CSRFFilterName,
+		ConfigType: &xds_hcm.HttpFilter_TypedConfig{
+			TypedConfig: csrfAny,
+		},
+	}, nil
+}
+
+// getCookieAttributesLuaFilter returns the Lua HTTP filter setting the cookie attributes of the responses as per the
+// Lua code of the virtual hosts
+func getCookieAttributesLuaFilter() (*xds_hcm.HttpFilter, error) {
+	luaAny, err := ptypes.MarshalAny(&xds_lua.Lua{
+		InlineCode: cookieAttributesLuaCode,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshaling Lua filter")
+	}
+
+	return &xds_hcm.HttpFilter{
+		Name: wellknown.Lua,
+		ConfigType: &xds_hcm.HttpFilter_TypedConfig{
+			TypedConfig: luaAny,
+		},
+	}, nil
+}
@@ -4,24 +4,34 @@ import (
 	"fmt"
 	"testing"
 
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
 	tassert "github.com/stretchr/testify/assert"
 	"google.golang.org/protobuf/types/known/wrapperspb"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/auth"
 	"github.com/openservicemesh/osm/pkg/catalog"
 	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy/rds/route"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/tests"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
 func TestGetIngressFilterChains(t *testing.T) {
 	testCases := []struct {
-		name                     string
-		ingressPolicy            *trafficpolicy.IngressTrafficPolicy
-		expectedFilterChainCount int
+		name                       string
+		ingressPolicy              *trafficpolicy.IngressTrafficPolicy
+		expectedFilterChainCount   int
+		expectedProxyProtocolPorts []int
 	}{
 		{
 			name: "HTTP ingress",
@@ -55,6 +65,26 @@ func TestGetIngressFilterChains(t *testing.T) {
 			},
 			expectedFilterChainCount: 2,
 		},
+		{
+			name: "ingress accepting PROXY protocol",
+			ingressPolicy: &trafficpolicy.IngressTrafficPolicy{
+				TrafficMatches: []*trafficpolicy.IngressTrafficMatch{
+					{
+						Name:                "http-ingress",
+						Port:                80,
+						Protocol:            "http",
+						AcceptProxyProtocol: true,
+					},
+					{
+						Name:     "https-ingress",
+						Port:     443,
+						Protocol: "https",
+					},
+				},
+			},
+			expectedFilterChainCount:   2,
+			expectedProxyProtocolPorts: []int{80},
+		},
 		{
 			name:                     "no ingress",
 			ingressPolicy:            nil,
@@ -70,6 +100,7 @@ func TestGetIngressFilterChains(t *testing.T) {
 
 			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
 			mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+			mockKubeController := k8s.NewMockController(mockCtrl)
 
 			lb := &listenerBuilder{
 				serviceIdentity: tests.BookstoreServiceIdentity,
@@ -80,14 +111,33 @@ func TestGetIngressFilterChains(t *testing.T) {
 			testSvc := tests.BookstoreV1Service
 
 			mockCatalog.EXPECT().GetIngressTrafficPolicy(testSvc).Return(tc.ingressPolicy, nil)
+			mockCatalog.EXPECT().GetKubeController().Return(mockKubeController).AnyTimes()
+			mockKubeController.EXPECT().GetService(testSvc).Return(&corev1.Service{
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{
+						{Name: "https", Port: 443, Protocol: corev1.ProtocolTCP},
+						{Name: "https-quic", Port: 443, Protocol: corev1.ProtocolUDP},
+						{Name: "https-alt-quic", Port: 9443, TargetPort: intstr.FromInt(8443), Protocol: corev1.ProtocolUDP},
+						{Name: "named-quic", Port: 10443, TargetPort: intstr.FromString("quic"), Protocol: corev1.ProtocolUDP},
+					},
+				},
+			}).AnyTimes()
 			mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+			mockConfigurator.EXPECT().GetHTTPSanitizationConfig().Return(configv1alpha1.HTTPSanitizationSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetIngressClientAddressConfig().Return(configv1alpha1.IngressClientAddressSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetDownstreamLimitsConfig().Return(configv1alpha1.DownstreamLimitsSpec{}).AnyTimes()
 			mockConfigurator.EXPECT().GetTracingEndpoint().Return("test").AnyTimes()
 			mockConfigurator.EXPECT().GetInboundExternalAuthConfig().Return(auth.ExtAuthConfig{
 				Enable: false,
 			}).AnyTimes()
+			mockConfigurator.EXPECT().GetCompressionConfig().Return(configv1alpha1.CompressionSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetRequestLimitsConfig().Return(configv1alpha1.RequestLimitsSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetClientCertDetailsConfig().Return(configv1alpha1.ClientCertDetailsSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetRBACAuditConfig().Return(configv1alpha1.RBACAuditSpec{}).AnyTimes()
 
-			actual := lb.getIngressFilterChains(testSvc)
+			actual, proxyProtocolPorts := lb.getIngressFilterChains(testSvc)
 			assert.Len(actual, tc.expectedFilterChainCount)
+			assert.Equal(tc.expectedProxyProtocolPorts, proxyProtocolPorts)
 		})
 	}
 }
@@ -98,6 +148,8 @@ func TestGetIngressFilterChainFromTrafficMatch(t *testing.T) {
 		trafficMatch             *trafficpolicy.IngressTrafficMatch
 		expectedEnvoyFilters     []string
 		expectedFilterChainMatch *xds_listener.FilterChainMatch
+		expectedUseRemoteAddress bool
+		expectedXFFTrustedHops   uint32
 		expectError              bool
 	}{
 		{
@@ -114,6 +166,38 @@ func TestGetIngressFilterChainFromTrafficMatch(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "HTTP traffic match preserving the client source IP",
+			trafficMatch: &trafficpolicy.IngressTrafficMatch{
+				Name:             "http-ingress",
+				Port:             80,
+				Protocol:         "http",
+				PreserveSourceIP: true,
+			},
+			expectedEnvoyFilters: []string{wellknown.HTTPConnectionManager},
+			expectedFilterChainMatch: &xds_listener.FilterChainMatch{
+				DestinationPort:   &wrapperspb.UInt32Value{Value: 80},
+				TransportProtocol: "",
+			},
+			expectedUseRemoteAddress: true,
+			expectError:              false,
+		},
+		{
+			name: "HTTP traffic match behind trusted proxies",
+			trafficMatch: &trafficpolicy.IngressTrafficMatch{
+				Name:          "http-ingress",
+				Port:          80,
+				Protocol:      "http",
+				ClientAddress: &trafficpolicy.ClientAddressPolicy{XFFNumTrustedHops: 2},
+			},
+			expectedEnvoyFilters: []string{wellknown.HTTPConnectionManager},
+			expectedFilterChainMatch: &xds_listener.FilterChainMatch{
+				DestinationPort:   &wrapperspb.UInt32Value{Value: 80},
+				TransportProtocol: "",
+			},
+			expectedXFFTrustedHops: 2,
+			expectError:            false,
+		},
 		{
 			name: "HTTPS traffic match with SNI",
 			trafficMatch: &trafficpolicy.IngressTrafficMatch{
@@ -164,25 +248,188 @@ func TestGetIngressFilterChainFromTrafficMatch(t *testing.T) {
 			defer mockCtrl.Finish()
 
 			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+			mockKubeController := k8s.NewMockController(mockCtrl)
 
 			lb := &listenerBuilder{
 				serviceIdentity: tests.BookstoreServiceIdentity,
+				meshCatalog:     mockCatalog,
 				cfg:             mockConfigurator,
 			}
 
-			mockConfigurator.EXPECT().IsTracingEnabled().Return(false)
-			mockConfigurator.EXPECT().GetTracingEndpoint().Return("test")
+			mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+			mockConfigurator.EXPECT().GetHTTPSanitizationConfig().Return(configv1alpha1.HTTPSanitizationSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetIngressClientAddressConfig().Return(configv1alpha1.IngressClientAddressSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetDownstreamLimitsConfig().Return(configv1alpha1.DownstreamLimitsSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetTracingEndpoint().Return("test").AnyTimes()
 			mockConfigurator.EXPECT().GetInboundExternalAuthConfig().Return(auth.ExtAuthConfig{
 				Enable: false,
-			})
+			}).AnyTimes()
+			mockConfigurator.EXPECT().GetCompressionConfig().Return(configv1alpha1.CompressionSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetRequestLimitsConfig().Return(configv1alpha1.RequestLimitsSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetClientCertDetailsConfig().Return(configv1alpha1.ClientCertDetailsSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetRBACAuditConfig().Return(configv1alpha1.RBACAuditSpec{}).AnyTimes()
+			mockCatalog.EXPECT().GetKubeController().Return(mockKubeController).AnyTimes()
+			mockKubeController.EXPECT().GetService(tests.BookstoreV1Service).Return(nil).AnyTimes()
 
-			actual, err := lb.getIngressFilterChainFromTrafficMatch(tc.trafficMatch)
+			actual, err := lb.getIngressFilterChainFromTrafficMatch(tests.BookstoreV1Service, tc.trafficMatch)
 			assert.Equal(tc.expectError, err != nil)
 
 			if err == nil {
 				assert.Equal(tc.expectedFilterChainMatch, actual.FilterChainMatch)
 				assert.Len(actual.Filters, 1) // Single HTTPConnectionManager filter
 				assert.Equal(wellknown.HTTPConnectionManager, actual.Filters[0].Name)
+
+				connManager := &xds_hcm.HttpConnectionManager{}
+				assert.Nil(ptypes.UnmarshalAny(actual.Filters[0].GetTypedConfig(), connManager))
+				assert.Equal(tc.expectedUseRemoteAddress, connManager.GetUseRemoteAddress().GetValue())
+				assert.Equal(tc.expectedXFFTrustedHops, connManager.XffNumTrustedHops)
+			}
+		})
+	}
+}
+
+func TestGetIngressQUICListeners(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		ingressPolicy         *trafficpolicy.IngressTrafficPolicy
+		expectedListenerNames []string
+		expectedFilterChains  []int
+	}{
+		{
+			name: "HTTP ingress",
+			ingressPolicy: &trafficpolicy.IngressTrafficPolicy{
+				TrafficMatches: []*trafficpolicy.IngressTrafficMatch{
+					{
+						Name:     "http-ingress",
+						Port:     80,
+						Protocol: "http",
+					},
+				},
+			},
+			expectedListenerNames: nil,
+			expectedFilterChains:  nil,
+		},
+		{
+			name: "HTTPS ingress on multiple ports",
+			ingressPolicy: &trafficpolicy.IngressTrafficPolicy{
+				TrafficMatches: []*trafficpolicy.IngressTrafficMatch{
+					{
+						Name:     "https-ingress-8443",
+						Port:     8443,
+						Protocol: "https",
+					},
+					{
+						Name:        "https-ingress-443",
+						Port:        443,
+						Protocol:    "https",
+						ServerNames: []string{"foo.bar.svc.cluster.local"},
+					},
+					{
+						Name:           "https-ingress-443-restricted",
+						Port:           443,
+						Protocol:       "HTTPS",
+						SourceIPRanges: []string{"10.0.0.0/8"},
+					},
+					{
+						Name:     "http-ingress",
+						Port:     80,
+						Protocol: "http",
+					},
+				},
+			},
+			expectedListenerNames: []string{"ingress-quic-listener-443", "ingress-quic-listener-8443"},
+			expectedFilterChains:  []int{2, 1},
+		},
+		{
+			name: "HTTPS ingress on ports not exposed over UDP",
+			ingressPolicy: &trafficpolicy.IngressTrafficPolicy{
+				TrafficMatches: []*trafficpolicy.IngressTrafficMatch{
+					{
+						Name:     "https-ingress-9443",
+						Port:     9443,
+						Protocol: "https",
+					},
+					{
+						Name:     "https-ingress-10443",
+						Port:     10443,
+						Protocol: "https",
+					},
+				},
+			},
+			expectedListenerNames: nil,
+			expectedFilterChains:  nil,
+		},
+		{
+			name:                  "no ingress",
+			ingressPolicy:         nil,
+			expectedListenerNames: nil,
+			expectedFilterChains:  nil,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Testing test case %d: %s", i, tc.name), func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+			mockKubeController := k8s.NewMockController(mockCtrl)
+
+			lb := &listenerBuilder{
+				serviceIdentity: tests.BookstoreServiceIdentity,
+				cfg:             mockConfigurator,
+				meshCatalog:     mockCatalog,
+			}
+
+			testSvc := tests.BookstoreV1Service
+
+			mockCatalog.EXPECT().GetIngressTrafficPolicy(testSvc).Return(tc.ingressPolicy, nil)
+			mockCatalog.EXPECT().GetKubeController().Return(mockKubeController).AnyTimes()
+			mockKubeController.EXPECT().GetService(testSvc).Return(&corev1.Service{
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{
+						{Name: "https", Port: 443, Protocol: corev1.ProtocolTCP},
+						{Name: "https-quic", Port: 443, Protocol: corev1.ProtocolUDP},
+						{Name: "https-alt-quic", Port: 9443, TargetPort: intstr.FromInt(8443), Protocol: corev1.ProtocolUDP},
+						{Name: "named-quic", Port: 10443, TargetPort: intstr.FromString("quic"), Protocol: corev1.ProtocolUDP},
+					},
+				},
+			}).AnyTimes()
+			mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+			mockConfigurator.EXPECT().GetHTTPSanitizationConfig().Return(configv1alpha1.HTTPSanitizationSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetIngressClientAddressConfig().Return(configv1alpha1.IngressClientAddressSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetDownstreamLimitsConfig().Return(configv1alpha1.DownstreamLimitsSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetTracingEndpoint().Return("test").AnyTimes()
+			mockConfigurator.EXPECT().GetInboundExternalAuthConfig().Return(auth.ExtAuthConfig{
+				Enable: false,
+			}).AnyTimes()
+			mockConfigurator.EXPECT().GetCompressionConfig().Return(configv1alpha1.CompressionSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetRequestLimitsConfig().Return(configv1alpha1.RequestLimitsSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetClientCertDetailsConfig().Return(configv1alpha1.ClientCertDetailsSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetRBACAuditConfig().Return(configv1alpha1.RBACAuditSpec{}).AnyTimes()
+
+			actual := lb.getIngressQUICListeners([]service.MeshService{testSvc})
+			assert.Len(actual, len(tc.expectedListenerNames))
+
+			for j, listener := range actual {
+				assert.Equal(tc.expectedListenerNames[j], listener.Name)
+				assert.Equal(xds_core.SocketAddress_UDP, listener.Address.GetSocketAddress().Protocol)
+				assert.NotNil(listener.UdpListenerConfig.QuicOptions)
+				assert.Len(listener.FilterChains, tc.expectedFilterChains[j])
+
+				for _, filterChain := range listener.FilterChains {
+					assert.Equal(wellknown.TransportSocketQuic, filterChain.TransportSocket.Name)
+					assert.Len(filterChain.Filters, 1) // Single HTTPConnectionManager filter
+					assert.Equal(wellknown.HTTPConnectionManager, filterChain.Filters[0].Name)
+
+					connManager := &xds_hcm.HttpConnectionManager{}
+					assert.Nil(ptypes.UnmarshalAny(filterChain.Filters[0].GetTypedConfig(), connManager))
+					assert.Equal(xds_hcm.HttpConnectionManager_HTTP3, connManager.CodecType)
+					assert.Equal(route.IngressRouteConfigName, connManager.GetRds().RouteConfigName)
+				}
 			}
 		})
 	}
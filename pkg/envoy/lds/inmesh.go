@@ -53,6 +53,17 @@ func (lb *listenerBuilder) getInboundMeshFilterChains(proxyService service.MeshS
 			}
 			filterChains = append(filterChains, filterChainForPort)
 
+			if strings.ToLower(appProtocol) == constants.ProtocolHTTP && lb.cfg.IsProtocolDetectionEnabled(proxyService.Namespace) {
+				// Downstream proxies detecting TCP traffic on the port advertise the TCP ALPN, proxy it over TCP
+				tcpFilterChainForPort, err := lb.getInboundMeshTCPFilterChain(proxyService, port)
+				if err != nil {
+					log.Error().Err(err).Msgf("Error building inbound TCP filter chain for proxy:port %s:%d with protocol detection", proxyService, port)
+					continue // continue building filter chains for other ports on the service
+				}
+				tcpFilterChainForPort.FilterChainMatch.ApplicationProtocols = envoy.ALPNInMeshTCP
+				filterChains = append(filterChains, tcpFilterChainForPort)
+			}
+
 		case constants.ProtocolTCP:
 			filterChainForPort, err := lb.getInboundMeshTCPFilterChain(proxyService, port)
 			if err != nil {
@@ -72,7 +83,18 @@ func (lb *listenerBuilder) getInboundMeshFilterChains(proxyService service.MeshS
 func (lb *listenerBuilder) getInboundHTTPFilters(proxyService service.MeshService) ([]*xds_listener.Filter, error) {
 	var filters []*xds_listener.Filter
 
-	// Apply an RBAC filter when permissive mode is disabled. The RBAC filter must be the first filter in the list of filters.
+	// Connections over the limit are closed before being processed by the other filters
+	downstreamLimits := lb.getDownstreamLimitsConfig(proxyService)
+	if downstreamLimits.maxConnections > 0 {
+		connectionLimitFilter, err := getConnectionLimitFilter(downstreamLimits.maxConnections, proxyService.String())
+		if err != nil {
+			log.Error().Err(err).Msgf("Error building connection limit filter for proxy service %s", proxyService)
+			return nil, err
+		}
+		filters = append(filters, connectionLimitFilter)
+	}
+
+	// Apply an RBAC filter when permissive mode is disabled. The RBAC filter must be the first filter after the connection limit filter.
 	if !lb.cfg.IsPermissiveTrafficPolicyMode() {
 		// Apply RBAC policies on the inbound filters based on configured policies
 		rbacFilter, err := lb.buildRBACFilter()
@@ -80,7 +102,7 @@ func (lb *listenerBuilder) getInboundHTTPFilters(proxyService service.MeshServic
 			log.Error().Err(err).Msgf("Error applying RBAC filter for proxy service %s", proxyService)
 			return nil, err
 		}
-		// RBAC filter should be the very first filter in the filter chain
+		// RBAC filter should precede the other filters in the filter chain
 		filters = append(filters, rbacFilter)
 	}
 
@@ -93,6 +115,13 @@ func (lb *listenerBuilder) getInboundHTTPFilters(proxyService service.MeshServic
 		wasmStatsHeaders:         lb.getWASMStatsHeaders(),
 		extAuthConfig:            lb.getExtAuthConfig(),
 		enableActiveHealthChecks: lb.cfg.GetFeatureFlags().EnableEnvoyActiveHealthChecks,
+		compression:              lb.getCompressionConfig(proxyService),
+		loadShedding:             lb.getLoadSheddingConfig(proxyService),
+		requestLimits:            lb.getRequestLimitsConfig(proxyService),
+		clientCertDetails:        lb.cfg.GetClientCertDetailsConfig(),
+		httpSanitization:         lb.cfg.GetHTTPSanitizationConfig(),
+		downstreamLimits:         downstreamLimits,
+		rbacDenialLog:            lb.cfg.GetRBACAuditConfig().EnableDenialLog,
 
 		// Tracing options
 		enableTracing:      lb.cfg.IsTracingEnabled(),
@@ -172,7 +201,7 @@ func (lb *listenerBuilder) getInboundMeshHTTPFilterChain(proxyService service.Me
 
 func (lb *listenerBuilder) getInboundMeshTCPFilterChain(proxyService service.MeshService, servicePort uint32) (*xds_listener.FilterChain, error) {
 	// Construct TCP filters
-	filters, err := lb.getInboundTCPFilters(proxyService)
+	filters, err := lb.getInboundTCPFilters(proxyService, servicePort)
 	if err != nil {
 		log.Error().Err(err).Msgf("Error constructing inbound TCP filters for proxy service %s", proxyService)
 		return nil, err
@@ -217,10 +246,21 @@ func (lb *listenerBuilder) getInboundMeshTCPFilterChain(proxyService service.Mes
 	}, nil
 }
 
-func (lb *listenerBuilder) getInboundTCPFilters(proxyService service.MeshService) ([]*xds_listener.Filter, error) {
+func (lb *listenerBuilder) getInboundTCPFilters(proxyService service.MeshService, targetPort uint32) ([]*xds_listener.Filter, error) {
 	var filters []*xds_listener.Filter
 
-	// Apply an RBAC filter when permissive mode is disabled. The RBAC filter must be the first filter in the list of filters.
+	// Connections over the limit are closed before being processed by the other filters
+	downstreamLimits := lb.getDownstreamLimitsConfig(proxyService)
+	if downstreamLimits.maxConnections > 0 {
+		connectionLimitFilter, err := getConnectionLimitFilter(downstreamLimits.maxConnections, fmt.Sprintf("%s:%d", proxyService, targetPort))
+		if err != nil {
+			log.Error().Err(err).Msgf("Error building connection limit filter for proxy service %s", proxyService)
+			return nil, err
+		}
+		filters = append(filters, connectionLimitFilter)
+	}
+
+	// Apply an RBAC filter when permissive mode is disabled. The RBAC filter must be the first filter after the connection limit filter.
 	if !lb.cfg.IsPermissiveTrafficPolicyMode() {
 		// Apply RBAC policies on the inbound filters based on configured policies
 		rbacFilter, err := lb.buildRBACFilter()
@@ -228,16 +268,19 @@ func (lb *listenerBuilder) getInboundTCPFilters(proxyService service.MeshService
 			log.Error().Err(err).Msgf("Error applying RBAC filter for proxy service %s", proxyService)
 			return nil, err
 		}
-		// RBAC filter should be the very first filter in the filter chain
+		// RBAC filter should precede the other filters in the filter chain
 		filters = append(filters, rbacFilter)
 	}
 
 	// Apply the TCP Proxy Filter
-	localServiceCluster := envoy.GetLocalClusterNameForService(proxyService)
+	localServiceCluster := lb.getLocalClusterNameForTargetPort(proxyService, targetPort)
 	tcpProxy := &xds_tcp_proxy.TcpProxy{
 		StatPrefix:       fmt.Sprintf("%s.%s", inboundMeshTCPProxyStatPrefix, localServiceCluster),
 		ClusterSpecifier: &xds_tcp_proxy.TcpProxy_Cluster{Cluster: localServiceCluster},
 	}
+	if downstreamLimits.idleTimeout > 0 {
+		tcpProxy.IdleTimeout = ptypes.DurationProto(downstreamLimits.idleTimeout)
+	}
 	marshalledTCPProxy, err := ptypes.MarshalAny(tcpProxy)
 	if err != nil {
 		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingXDSResource)).
@@ -253,8 +296,9 @@ func (lb *listenerBuilder) getInboundTCPFilters(proxyService service.MeshService
 	return filters, nil
 }
 
-// getOutboundHTTPFilter returns an HTTP connection manager network filter used to filter outbound HTTP traffic for the given route configuration
-func (lb *listenerBuilder) getOutboundHTTPFilter(routeConfigName string) (*xds_listener.Filter, error) {
+// getOutboundHTTPFilter returns an HTTP connection manager network filter used to filter outbound HTTP traffic for the given route configuration,
+// or for the outbound route configurations of the hosts of the requests if scopedRoutes is set
+func (lb *listenerBuilder) getOutboundHTTPFilter(routeConfigName string, scopedRoutes bool) (*xds_listener.Filter, error) {
 	var marshalledFilter *any.Any
 	var err error
 
@@ -262,11 +306,14 @@ func (lb *listenerBuilder) getOutboundHTTPFilter(routeConfigName string) (*xds_l
 	outboundConnManager, err := httpConnManagerOptions{
 		direction:         outbound,
 		rdsRoutConfigName: routeConfigName,
+		scopedRoutes:      scopedRoutes,
 
 		// Additional filters
 		wasmStatsHeaders: lb.statsHeaders,
 		extAuthConfig:    nil, // Ext auth is not configured for outbound connections
 
+		httpSanitization: lb.cfg.GetHTTPSanitizationConfig(),
+
 		// Tracing options
 		enableTracing:      lb.cfg.IsTracingEnabled(),
 		tracingAPIEndpoint: lb.cfg.GetTracingEndpoint(),
@@ -338,7 +385,7 @@ func (lb *listenerBuilder) getOutboundFilterChainMatchForService(dstSvc service.
 
 func (lb *listenerBuilder) getOutboundHTTPFilterChainForService(upstream service.MeshService, port uint32) (*xds_listener.FilterChain, error) {
 	// Get HTTP filter for service
-	filter, err := lb.getOutboundHTTPFilter(route.OutboundRouteConfigName)
+	filter, err := lb.getOutboundHTTPFilter(route.GetOutboundRouteConfigNameForPort(int(port)), lb.scopedRoutes)
 	if err != nil {
 		log.Error().Err(err).Msgf("Error getting HTTP filter for upstream service %s", upstream)
 		return nil, err
@@ -351,7 +398,7 @@ func (lb *listenerBuilder) getOutboundHTTPFilterChainForService(upstream service
 		return nil, err
 	}
 
-	filterChainName := fmt.Sprintf("%s:%s", outboundMeshHTTPFilterChainPrefix, upstream)
+	filterChainName := fmt.Sprintf("%s:%s:%d", outboundMeshHTTPFilterChainPrefix, upstream, port)
 	return &xds_listener.FilterChain{
 		Name:             filterChainName,
 		Filters:          []*xds_listener.Filter{filter},
@@ -361,7 +408,7 @@ func (lb *listenerBuilder) getOutboundHTTPFilterChainForService(upstream service
 
 func (lb *listenerBuilder) getOutboundTCPFilterChainForService(upstream service.MeshService, port uint32) (*xds_listener.FilterChain, error) {
 	// Get TCP filter for service
-	filter, err := lb.getOutboundTCPFilter(upstream)
+	filter, err := lb.getOutboundTCPFilter(upstream, port)
 	if err != nil {
 		log.Error().Err(err).Msgf("Error getting outbound TCP filter for upstream service %s", upstream)
 		return nil, err
@@ -374,7 +421,7 @@ func (lb *listenerBuilder) getOutboundTCPFilterChainForService(upstream service.
 		return nil, err
 	}
 
-	filterChainName := fmt.Sprintf("%s:%s", outboundMeshTCPFilterChainPrefix, upstream)
+	filterChainName := fmt.Sprintf("%s:%s:%d", outboundMeshTCPFilterChainPrefix, upstream, port)
 	return &xds_listener.FilterChain{
 		Name:             filterChainName,
 		Filters:          []*xds_listener.Filter{filter},
@@ -382,22 +429,73 @@ func (lb *listenerBuilder) getOutboundTCPFilterChainForService(upstream service.
 	}, nil
 }
 
-func (lb *listenerBuilder) getOutboundTCPFilter(upstream service.MeshService) (*xds_listener.Filter, error) {
+// getOutboundProtocolDetectionFilterChainsForService returns the filter chains for a port of the given upstream with
+// protocol detection enabled. Connections detected as HTTP by the HTTP inspector listener filter match the HTTP filter
+// chain, and the remaining connections match the TCP filter chain proxying them to the upstream's TCP clusters.
+// Connections detected as TLS by the TLS inspector listener filter are proxied as TCP, because their application
+// protocol comes from the TLS ALPN extension and the HTTP filter chain cannot terminate them.
+func (lb *listenerBuilder) getOutboundProtocolDetectionFilterChainsForService(upstream service.MeshService, port uint32) ([]*xds_listener.FilterChain, error) {
+	httpFilterChain, err := lb.getOutboundHTTPFilterChainForService(upstream, port)
+	if err != nil {
+		return nil, err
+	}
+	httpFilterChain.FilterChainMatch.TransportProtocol = envoy.TransportProtocolRawBuffer
+	httpFilterChain.FilterChainMatch.ApplicationProtocols = envoy.ALPNHTTPInspector
+
+	tcpFilter, err := lb.buildOutboundTCPFilter(upstream, port, true)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error getting outbound TCP filter for upstream service %s", upstream)
+		return nil, err
+	}
+
+	tcpFilterChainMatch, err := lb.getOutboundFilterChainMatchForService(upstream, port)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error getting TCP filter chain match for upstream service %s", upstream)
+		return nil, err
+	}
+
+	tcpFilterChain := &xds_listener.FilterChain{
+		Name:             fmt.Sprintf("%s:%s:%d", outboundMeshTCPFilterChainPrefix, upstream, port),
+		Filters:          []*xds_listener.Filter{tcpFilter},
+		FilterChainMatch: tcpFilterChainMatch,
+	}
+
+	return []*xds_listener.FilterChain{httpFilterChain, tcpFilterChain}, nil
+}
+
+func (lb *listenerBuilder) getOutboundTCPFilter(upstream service.MeshService, port uint32) (*xds_listener.Filter, error) {
+	return lb.buildOutboundTCPFilter(upstream, port, false)
+}
+
+// buildOutboundTCPFilter returns the TCP proxy filter for the given port of the upstream. When detectedTCP is set, the
+// traffic is proxied to the clusters dedicated to TCP traffic detected on ports with protocol detection enabled.
+func (lb *listenerBuilder) buildOutboundTCPFilter(upstream service.MeshService, port uint32, detectedTCP bool) (*xds_listener.Filter, error) {
 	tcpProxy := &xds_tcp_proxy.TcpProxy{
 		StatPrefix: fmt.Sprintf("%s.%s", outboundMeshTCPProxyStatPrefix, upstream),
 	}
 
+	clusterName := func(cluster string) string {
+		if svc, svcPort, err := service.ParseClusterName(cluster); err == nil && svcPort == 0 {
+			// Services exposing multiple ports are backed by a cluster per port
+			cluster = lb.getClusterNameForServicePort(svc, port).String()
+		}
+		if detectedTCP {
+			return envoy.GetTCPClusterNameForServiceCluster(cluster)
+		}
+		return cluster
+	}
+
 	weightedClusters := lb.meshCatalog.GetWeightedClustersForUpstream(upstream)
 
 	if len(weightedClusters) == 0 {
 		// No weighted clusters implies a traffic split does not exist for this upstream, proxy it as is
-		tcpProxy.ClusterSpecifier = &xds_tcp_proxy.TcpProxy_Cluster{Cluster: upstream.String()}
+		tcpProxy.ClusterSpecifier = &xds_tcp_proxy.TcpProxy_Cluster{Cluster: clusterName(upstream.String())}
 	} else {
 		// Weighted clusters found for this upstream, proxy traffic meant for this upstream to its weighted clusters
 		var clusterWeights []*xds_tcp_proxy.TcpProxy_WeightedCluster_ClusterWeight
 		for _, cluster := range weightedClusters {
 			clusterWeights = append(clusterWeights, &xds_tcp_proxy.TcpProxy_WeightedCluster_ClusterWeight{
-				Name:   string(cluster.ClusterName),
+				Name:   clusterName(string(cluster.ClusterName)),
 				Weight: uint32(cluster.Weight),
 			})
 		}
@@ -445,6 +543,16 @@ func (lb *listenerBuilder) getOutboundFilterChainPerUpstream() []*xds_listener.F
 		for port, appProtocol := range protocolToPortMap {
 			switch strings.ToLower(appProtocol) {
 			case constants.ProtocolHTTP, constants.ProtocolGRPC:
+				if strings.ToLower(appProtocol) == constants.ProtocolHTTP && lb.cfg.IsProtocolDetectionEnabled(upstreamSvc.Namespace) {
+					// Construct HTTP and TCP filter chains matching the protocol detected on the port
+					if detectionFilterChains, err := lb.getOutboundProtocolDetectionFilterChainsForService(upstreamSvc, port); err != nil {
+						log.Error().Err(err).Msgf("Error constructing outbound protocol detection filter chains for upstream service %s on proxy with identity %s", upstreamSvc, lb.serviceIdentity)
+					} else {
+						filterChains = append(filterChains, detectionFilterChains...)
+					}
+					continue
+				}
+
 				// Construct HTTP filter chain
 				if httpFilterChain, err := lb.getOutboundHTTPFilterChainForService(upstreamSvc, port); err != nil {
 					log.Error().Err(err).Msgf("Error constructing outbound HTTP filter chain for upstream service %s on proxy with identity %s", upstreamSvc, lb.serviceIdentity)
@@ -468,3 +576,30 @@ func (lb *listenerBuilder) getOutboundFilterChainPerUpstream() []*xds_listener.F
 
 	return filterChains
 }
+
+// getClusterNameForServicePort returns the name of the cluster backing the given port of the service, see
+// service.GetClusterNameForPort
+func (lb *listenerBuilder) getClusterNameForServicePort(svc service.MeshService, port uint32) service.ClusterName {
+	ports, err := lb.meshCatalog.ListServicePorts(svc)
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGettingServicePorts)).
+			Msgf("Error listing the ports of service %s, using the service cluster for port %d", svc, port)
+	}
+	return service.GetClusterNameForPort(svc, ports, port)
+}
+
+// getLocalClusterNameForTargetPort returns the name of the local cluster proxying the inbound traffic to the given
+// target port of the service
+func (lb *listenerBuilder) getLocalClusterNameForTargetPort(proxyService service.MeshService, targetPort uint32) string {
+	ports, err := lb.meshCatalog.ListServicePorts(proxyService)
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGettingServicePorts)).
+			Msgf("Error listing the ports of service %s, using the service local cluster for target port %d", proxyService, targetPort)
+	}
+	for _, port := range ports {
+		if port.TargetPort == targetPort {
+			return envoy.GetLocalClusterNameForServiceCluster(service.GetClusterNameForPort(proxyService, ports, port.Port).String())
+		}
+	}
+	return envoy.GetLocalClusterNameForService(proxyService)
+}
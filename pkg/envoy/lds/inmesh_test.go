@@ -19,8 +19,10 @@ import (
 	"github.com/openservicemesh/osm/pkg/catalog"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/endpoint"
+	"github.com/openservicemesh/osm/pkg/envoy"
 	"github.com/openservicemesh/osm/pkg/envoy/rds/route"
 	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/tests"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
@@ -35,6 +37,8 @@ func TestGetOutboundHTTPFilterChainForService(t *testing.T) {
 
 	// Mock calls used to build the HTTP connection manager
 	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetHTTPSanitizationConfig().Return(v1alpha1.HTTPSanitizationSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().GetDownstreamLimitsConfig().Return(v1alpha1.DownstreamLimitsSpec{}).AnyTimes()
 	mockConfigurator.EXPECT().GetTracingEndpoint().Return("test-api").AnyTimes()
 	mockConfigurator.EXPECT().GetInboundExternalAuthConfig().Return(auth.ExtAuthConfig{
 		Enable: false,
@@ -177,6 +181,7 @@ func TestGetOutboundTCPFilterChainForService(t *testing.T) {
 
 			mockCatalog.EXPECT().GetResolvableServiceEndpoints(tests.BookstoreApexService).Return(tc.expectedEndpoints, nil)
 			mockCatalog.EXPECT().GetWeightedClustersForUpstream(tests.BookstoreApexService).Times(1)
+			mockCatalog.EXPECT().ListServicePorts(tests.BookstoreApexService).Return([]service.ServicePort{{Port: 80}}, nil).AnyTimes()
 
 			tcpFilterChain, err := lb.getOutboundTCPFilterChainForService(tests.BookstoreApexService, tc.servicePort)
 
@@ -201,10 +206,20 @@ func TestGetInboundMeshHTTPFilterChain(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockKubeController := k8s.NewMockController(mockCtrl)
 	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
 
 	// Mock calls used to build the HTTP connection manager
+	mockConfigurator.EXPECT().GetCompressionConfig().Return(v1alpha1.CompressionSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().GetRequestLimitsConfig().Return(v1alpha1.RequestLimitsSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().GetClientCertDetailsConfig().Return(v1alpha1.ClientCertDetailsSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().GetRBACAuditConfig().Return(v1alpha1.RBACAuditSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().GetSPIFFETrustDomain().Return("").AnyTimes()
+	mockCatalog.EXPECT().GetKubeController().Return(mockKubeController).AnyTimes()
+	mockKubeController.EXPECT().GetService(gomock.Any()).Return(nil).AnyTimes()
 	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetHTTPSanitizationConfig().Return(v1alpha1.HTTPSanitizationSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().GetDownstreamLimitsConfig().Return(v1alpha1.DownstreamLimitsSpec{}).AnyTimes()
 	mockConfigurator.EXPECT().GetTracingEndpoint().Return("test-api").AnyTimes()
 	mockConfigurator.EXPECT().GetInboundExternalAuthConfig().Return(auth.ExtAuthConfig{
 		Enable: false,
@@ -302,6 +317,8 @@ func TestGetInboundMeshTCPFilterChain(t *testing.T) {
 
 	// Mock calls used to build the HTTP connection manager
 	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetHTTPSanitizationConfig().Return(v1alpha1.HTTPSanitizationSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().GetDownstreamLimitsConfig().Return(v1alpha1.DownstreamLimitsSpec{}).AnyTimes()
 	mockConfigurator.EXPECT().GetTracingEndpoint().Return("test-api").AnyTimes()
 	mockConfigurator.EXPECT().GetInboundExternalAuthConfig().Return(auth.ExtAuthConfig{
 		Enable: false,
@@ -309,6 +326,11 @@ func TestGetInboundMeshTCPFilterChain(t *testing.T) {
 	mockConfigurator.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{
 		EnableMulticlusterMode: true,
 	}).AnyTimes()
+	mockConfigurator.EXPECT().GetRBACAuditConfig().Return(v1alpha1.RBACAuditSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().GetSPIFFETrustDomain().Return("").AnyTimes()
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockCatalog.EXPECT().GetKubeController().Return(mockKubeController).AnyTimes()
+	mockKubeController.EXPECT().GetService(gomock.Any()).Return(nil).AnyTimes()
 
 	lb := &listenerBuilder{
 		meshCatalog:     mockCatalog,
@@ -317,6 +339,7 @@ func TestGetInboundMeshTCPFilterChain(t *testing.T) {
 	}
 
 	proxyService := tests.BookbuyerService
+	mockCatalog.EXPECT().ListServicePorts(proxyService).Return([]service.ServicePort{{Port: 80, TargetPort: 80}, {Port: 90, TargetPort: 90}}, nil).AnyTimes()
 
 	testCases := []struct {
 		name           string
@@ -539,6 +562,8 @@ func TestGetOutboundTCPFilter(t *testing.T) {
 	type testCase struct {
 		name                   string
 		upstream               service.MeshService
+		port                   uint32
+		servicePorts           map[service.MeshService][]service.ServicePort
 		clusterWeights         []service.WeightedCluster
 		expectedTCPProxyConfig *xds_tcp_proxy.TcpProxy
 		expectError            bool
@@ -593,6 +618,71 @@ func TestGetOutboundTCPFilter(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "TCP filter for a port of upstream exposing multiple ports",
+			upstream: service.MeshService{
+				Name:      "foo",
+				Namespace: "bar",
+			},
+			port: 5432,
+			servicePorts: map[service.MeshService][]service.ServicePort{
+				{Name: "foo", Namespace: "bar"}: {
+					{Name: "http", Port: 80, TargetPort: 8080, Protocol: "http"},
+					{Name: "db", Port: 5432, TargetPort: 5432, Protocol: "tcp"},
+				},
+			},
+			clusterWeights: nil,
+			expectedTCPProxyConfig: &xds_tcp_proxy.TcpProxy{
+				StatPrefix:       "outbound-mesh-tcp-proxy.bar/foo",
+				ClusterSpecifier: &xds_tcp_proxy.TcpProxy_Cluster{Cluster: "bar/foo|5432"},
+			},
+			expectError: false,
+		},
+		{
+			name: "TCP filter for a port of upstream with traffic split backends exposing multiple ports",
+			upstream: service.MeshService{
+				Name:      "foo",
+				Namespace: "bar",
+			},
+			port: 5432,
+			servicePorts: map[service.MeshService][]service.ServicePort{
+				{Name: "foo-v1", Namespace: "bar"}: {
+					{Name: "http", Port: 80, TargetPort: 8080, Protocol: "http"},
+					{Name: "db", Port: 5432, TargetPort: 5432, Protocol: "tcp"},
+				},
+				{Name: "foo-v2", Namespace: "bar"}: {
+					{Name: "db", Port: 5432, TargetPort: 5432, Protocol: "tcp"},
+				},
+			},
+			clusterWeights: []service.WeightedCluster{
+				{
+					ClusterName: "bar/foo-v1",
+					Weight:      10,
+				},
+				{
+					ClusterName: "bar/foo-v2",
+					Weight:      90,
+				},
+			},
+			expectedTCPProxyConfig: &xds_tcp_proxy.TcpProxy{
+				StatPrefix: "outbound-mesh-tcp-proxy.bar/foo",
+				ClusterSpecifier: &xds_tcp_proxy.TcpProxy_WeightedClusters{
+					WeightedClusters: &xds_tcp_proxy.TcpProxy_WeightedCluster{
+						Clusters: []*xds_tcp_proxy.TcpProxy_WeightedCluster_ClusterWeight{
+							{
+								Name:   "bar/foo-v1|5432",
+								Weight: 10,
+							},
+							{
+								Name:   "bar/foo-v2",
+								Weight: 90,
+							},
+						},
+					},
+				},
+			},
+			expectError: false,
+		},
 	}
 
 	for i, tc := range testCases {
@@ -601,9 +691,12 @@ func TestGetOutboundTCPFilter(t *testing.T) {
 			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
 
 			mockCatalog.EXPECT().GetWeightedClustersForUpstream(tc.upstream).Return(tc.clusterWeights).Times(1)
+			mockCatalog.EXPECT().ListServicePorts(gomock.Any()).DoAndReturn(func(svc service.MeshService) ([]service.ServicePort, error) {
+				return tc.servicePorts[svc], nil
+			}).AnyTimes()
 
 			lb := newListenerBuilder(mockCatalog, tests.BookbuyerServiceIdentity, mockConfigurator, nil)
-			filter, err := lb.getOutboundTCPFilter(tc.upstream)
+			filter, err := lb.getOutboundTCPFilter(tc.upstream, tc.port)
 
 			assert := tassert.New(t)
 			assert.Equal(tc.expectError, err != nil)
@@ -632,6 +725,7 @@ func TestGetOutboundHTTPFilter(t *testing.T) {
 
 	mockConfigurator.EXPECT().IsTracingEnabled()
 	mockConfigurator.EXPECT().GetTracingEndpoint()
+	mockConfigurator.EXPECT().GetHTTPSanitizationConfig().Return(v1alpha1.HTTPSanitizationSpec{})
 	mockConfigurator.EXPECT().GetInboundExternalAuthConfig().Return(auth.ExtAuthConfig{
 		Enable: false,
 	}).AnyTimes()
@@ -639,7 +733,132 @@ func TestGetOutboundHTTPFilter(t *testing.T) {
 		EnableWASMStats: false,
 	}).AnyTimes()
 
-	filter, err := lb.getOutboundHTTPFilter(route.OutboundRouteConfigName)
+	filter, err := lb.getOutboundHTTPFilter(route.GetOutboundRouteConfigNameForPort(80), false)
 	assert.NoError(err)
 	assert.Equal(filter.Name, wellknown.HTTPConnectionManager)
 }
+
+func TestGetOutboundProtocolDetectionFilterChainsForService(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+
+	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetHTTPSanitizationConfig().Return(v1alpha1.HTTPSanitizationSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().GetDownstreamLimitsConfig().Return(v1alpha1.DownstreamLimitsSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().GetTracingEndpoint().Return("test-api").AnyTimes()
+
+	upstream := service.MeshService{Name: "foo", Namespace: "bar"}
+	mockCatalog.EXPECT().GetResolvableServiceEndpoints(upstream).Return([]endpoint.Endpoint{
+		{IP: net.ParseIP("1.1.1.1"), Port: 80},
+	}, nil).Times(2)
+	mockCatalog.EXPECT().GetWeightedClustersForUpstream(upstream).Return([]service.WeightedCluster{
+		{ClusterName: "bar/foo-v1", Weight: 10},
+		{ClusterName: "bar/foo-v2", Weight: 90},
+	})
+	mockCatalog.EXPECT().ListServicePorts(gomock.Any()).Return([]service.ServicePort{{Port: 80}}, nil).AnyTimes()
+
+	lb := newListenerBuilder(mockCatalog, tests.BookbuyerServiceIdentity, mockConfigurator, nil)
+	filterChains, err := lb.getOutboundProtocolDetectionFilterChainsForService(upstream, 80)
+	assert.Nil(err)
+	assert.Len(filterChains, 2)
+
+	// HTTP traffic detected by the HTTP inspector matches the HTTP filter chain
+	httpFilterChain := filterChains[0]
+	assert.Equal("outbound-mesh-http-filter-chain:bar/foo:80", httpFilterChain.Name)
+	assert.Equal([]string{"http/1.0", "http/1.1", "h2c"}, httpFilterChain.FilterChainMatch.ApplicationProtocols)
+	assert.Equal("raw_buffer", httpFilterChain.FilterChainMatch.TransportProtocol)
+	assert.Equal(wellknown.HTTPConnectionManager, httpFilterChain.Filters[0].Name)
+
+	// Remaining traffic matches the TCP filter chain proxying it to the TCP clusters
+	tcpFilterChain := filterChains[1]
+	assert.Equal("outbound-mesh-tcp-filter-chain:bar/foo:80", tcpFilterChain.Name)
+	assert.Empty(tcpFilterChain.FilterChainMatch.ApplicationProtocols)
+	assert.Empty(tcpFilterChain.FilterChainMatch.TransportProtocol)
+	assert.Equal(httpFilterChain.FilterChainMatch.DestinationPort, tcpFilterChain.FilterChainMatch.DestinationPort)
+	assert.Equal(httpFilterChain.FilterChainMatch.PrefixRanges, tcpFilterChain.FilterChainMatch.PrefixRanges)
+
+	tcpProxy := &xds_tcp_proxy.TcpProxy{}
+	assert.Nil(ptypes.UnmarshalAny(tcpFilterChain.Filters[0].GetTypedConfig(), tcpProxy))
+	assert.Equal(&xds_tcp_proxy.TcpProxy_WeightedClusters{
+		WeightedClusters: &xds_tcp_proxy.TcpProxy_WeightedCluster{
+			Clusters: []*xds_tcp_proxy.TcpProxy_WeightedCluster_ClusterWeight{
+				{Name: "bar/foo-v1|tcp", Weight: 10},
+				{Name: "bar/foo-v2|tcp", Weight: 90},
+			},
+		},
+	}, tcpProxy.ClusterSpecifier)
+}
+
+func TestGetInboundMeshFilterChainsWithProtocolDetection(t *testing.T) {
+	testCases := []struct {
+		name                      string
+		protocolDetection         bool
+		portToProtocolMapping     map[uint32]string
+		expectedFilterChainsALPNs [][]string
+	}{
+		{
+			name:                      "HTTP port without protocol detection",
+			protocolDetection:         false,
+			portToProtocolMapping:     map[uint32]string{80: "http"},
+			expectedFilterChainsALPNs: [][]string{{"osm"}},
+		},
+		{
+			name:                      "HTTP port with protocol detection",
+			protocolDetection:         true,
+			portToProtocolMapping:     map[uint32]string{80: "http"},
+			expectedFilterChainsALPNs: [][]string{{"osm"}, {"osm-tcp"}},
+		},
+		{
+			name:                      "TCP port with protocol detection",
+			protocolDetection:         true,
+			portToProtocolMapping:     map[uint32]string{90: "tcp"},
+			expectedFilterChainsALPNs: [][]string{{"osm"}},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Testing test case %d: %s", i, tc.name), func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+
+			proxyService := tests.BookstoreV1Service
+			mockCatalog.EXPECT().GetTargetPortToProtocolMappingForService(proxyService).Return(tc.portToProtocolMapping, nil)
+			mockCatalog.EXPECT().ListServicePorts(proxyService).Return(nil, nil).AnyTimes()
+			mockConfigurator.EXPECT().IsProtocolDetectionEnabled(proxyService.Namespace).Return(tc.protocolDetection).AnyTimes()
+			mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(true).AnyTimes()
+			mockConfigurator.EXPECT().GetCompressionConfig().Return(v1alpha1.CompressionSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetRequestLimitsConfig().Return(v1alpha1.RequestLimitsSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetClientCertDetailsConfig().Return(v1alpha1.ClientCertDetailsSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetRBACAuditConfig().Return(v1alpha1.RBACAuditSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetSPIFFETrustDomain().Return("").AnyTimes()
+			mockCatalog.EXPECT().GetKubeController().Return(mockKubeController).AnyTimes()
+			mockKubeController.EXPECT().GetService(gomock.Any()).Return(nil).AnyTimes()
+			mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+			mockConfigurator.EXPECT().GetHTTPSanitizationConfig().Return(v1alpha1.HTTPSanitizationSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetDownstreamLimitsConfig().Return(v1alpha1.DownstreamLimitsSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetTracingEndpoint().Return("test-api").AnyTimes()
+			mockConfigurator.EXPECT().GetInboundExternalAuthConfig().Return(auth.ExtAuthConfig{
+				Enable: false,
+			}).AnyTimes()
+			mockConfigurator.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{}).AnyTimes()
+
+			lb := newListenerBuilder(mockCatalog, tests.BookstoreServiceIdentity, mockConfigurator, nil)
+			filterChains := lb.getInboundMeshFilterChains(proxyService)
+			assert.Len(filterChains, len(tc.expectedFilterChainsALPNs))
+
+			for j, filterChain := range filterChains {
+				assert.Equal(tc.expectedFilterChainsALPNs[j], filterChain.FilterChainMatch.ApplicationProtocols)
+				assert.Equal(envoy.TransportProtocolTLS, filterChain.FilterChainMatch.TransportProtocol)
+			}
+		})
+	}
+}
@@ -2,19 +2,26 @@ package lds
 
 import (
 	"fmt"
+	"sort"
 
 	mapset "github.com/deckarep/golang-set"
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_rbac "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
+	xds_original_src "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/listener/original_src/v3"
 	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	xds_network_rbac "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/rbac/v3"
 	xds_tcp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
 	xds_type "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/golang/protobuf/ptypes"
 
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/rbac"
 	"github.com/openservicemesh/osm/pkg/errcode"
+	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
@@ -23,14 +30,20 @@ const (
 	outboundListenerName          = "outbound-listener"
 	multiclusterListenerName      = "multicluster-listener"
 	prometheusListenerName        = "inbound-prometheus-listener"
+	ingressQUICListenerPrefix     = "ingress-quic-listener"
 	outboundEgressFilterChainName = "outbound-egress-filter-chain"
 	egressTCPProxyStatPrefix      = "egress-tcp-proxy"
+	originalSourceListenerFilter  = "envoy.filters.listener.original_src"
 	singleIpv4Mask                = 32
+	singleIpv6Mask                = 128
 )
 
 func (lb *listenerBuilder) newOutboundListener() (*xds_listener.Listener, error) {
 	serviceFilterChains := lb.getOutboundFilterChainPerUpstream()
 
+	// Services registered outside the mesh by external endpoints providers are reachable according to Egress policies
+	serviceFilterChains = append(serviceFilterChains, getExternalServiceFilterChains(lb.meshCatalog.ListExternalServicesForIdentity(lb.serviceIdentity))...)
+
 	listener := &xds_listener.Listener{
 		Name:             outboundListenerName,
 		Address:          envoy.GetAddress(constants.WildcardIPAddr, constants.EnvoyOutboundListenerPort),
@@ -48,9 +61,10 @@ func (lb *listenerBuilder) newOutboundListener() (*xds_listener.Listener, error)
 	// Create a default passthrough filter chain when global egress is enabled.
 	// This filter chain matches any traffic not matching any of the filter chains built from
 	// mesh (SMI or permissive mode) or egress traffic policies. Traffic matching this default
-	// passthrough filter chain will be allowed to passthrough to its original destination.
+	// passthrough filter chain will be allowed to passthrough to its original destination, provided
+	// the destination is allowed by the outbound passthrough configuration.
 	if lb.cfg.IsEgressEnabled() {
-		egressFilterChain, err := getDefaultPassthroughFilterChain()
+		egressFilterChain, err := getDefaultPassthroughFilterChain(lb.cfg.GetOutboundPassthroughConfig())
 		if err != nil {
 			log.Error().Err(err).Msgf("Error getting filter chain for Egress")
 			return nil, err
@@ -90,8 +104,30 @@ func (lb *listenerBuilder) newOutboundListener() (*xds_listener.Listener, error)
 		// ListenerFilter can timeout for server-first protocols. In such cases, continue the processing of the connection
 		// and fallback to the default filter chain.
 		listener.ContinueOnListenerFiltersTimeout = true
+	} else if hasProtocolDetectionFilterChains(listener.FilterChains) {
+		// Filter chains of ports with protocol detection enabled match the transport protocol set by the TLS inspector
+		// and the application protocol set by the HTTP inspector
+		listener.ListenerFilters = append(listener.ListenerFilters,
+			&xds_listener.ListenerFilter{
+				Name: wellknown.TlsInspector,
+			},
+			&xds_listener.ListenerFilter{
+				Name: wellknown.HttpInspector,
+			},
+		)
+
+		// The HTTP inspector times out on server-first protocols. In such cases, continue the processing of the
+		// connection so that it matches the TCP filter chain of the port.
+		listener.ContinueOnListenerFiltersTimeout = true
+	}
+
+	if hasProtocolDetectionFilterChains(listener.FilterChains) {
+		// Bound the delay added to server-first connections waiting for the listener filters to time out
+		listener.ListenerFiltersTimeout = ptypes.DurationProto(lb.cfg.GetProtocolDetectionTimeout())
 	}
 
+	sortFilterChains(listener.FilterChains)
+
 	if len(listener.FilterChains) == 0 && listener.DefaultFilterChain == nil {
 		// Programming a listener with no filter chains is an error.
 		// It is possible for the outbound listener to have no filter chains if
@@ -103,6 +139,27 @@ func (lb *listenerBuilder) newOutboundListener() (*xds_listener.Listener, error)
 	return listener, nil
 }
 
+// sortFilterChains sorts the given filter chains by name, so that a listener built from an unchanged configuration is
+// identical to the previous one. On a listener update, Envoy only drains the connections of the filter chains that
+// were modified or removed, so stable filter chains keep long-lived connections such as gRPC streams and websockets
+// open across broadcasts.
+func sortFilterChains(filterChains []*xds_listener.FilterChain) {
+	sort.SliceStable(filterChains, func(i, j int) bool {
+		return filterChains[i].Name < filterChains[j].Name
+	})
+}
+
+// hasProtocolDetectionFilterChains returns whether any of the given filter chains matches the application protocols
+// set by the HTTP inspector
+func hasProtocolDetectionFilterChains(filterChains []*xds_listener.FilterChain) bool {
+	for _, filterChain := range filterChains {
+		if filterChain.FilterChainMatch != nil && len(filterChain.FilterChainMatch.ApplicationProtocols) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func newInboundListener() *xds_listener.Listener {
 	return &xds_listener.Listener{
 		Name:             inboundListenerName,
@@ -123,6 +180,60 @@ func newInboundListener() *xds_listener.Listener {
 	}
 }
 
+// setProxyProtocolListenerFilters configures the listener filters of the given inbound listener to accept a PROXY
+// protocol header on the given ingress ports, restoring the address of the ingress clients it carries as the source
+// address of the connections. The header is read once the original destination port of the connections is known,
+// and before the TLS inspector so that it inspects the connection data following the header. The original
+// destination address is restored again after the header is read, as the header also overrides it with the
+// address the clients connected to, such as the address of a load balancer.
+func setProxyProtocolListenerFilters(listener *xds_listener.Listener, ports []int) {
+	if len(ports) == 0 {
+		return
+	}
+
+	// For deterministic ordering, since a change to the listener filters drains all the connections of the listener
+	sort.Ints(ports)
+
+	listener.ListenerFilters = []*xds_listener.ListenerFilter{
+		{
+			Name: wellknown.OriginalDestination,
+		},
+		{
+			Name: wellknown.ProxyProtocol,
+			FilterDisabled: &xds_listener.ListenerFilterChainMatchPredicate{
+				Rule: &xds_listener.ListenerFilterChainMatchPredicate_NotMatch{
+					NotMatch: getFilterMatchPredicateForPorts(ports),
+				},
+			},
+		},
+		{
+			Name: wellknown.OriginalDestination,
+		},
+		{
+			Name: wellknown.TlsInspector,
+		},
+	}
+}
+
+// newOriginalSourceListenerFilter returns the listener filter proxying the connections accepted by the inbound listener
+// from the source IP of the downstream clients, instead of from localhost. The connections are marked with Envoy's
+// original source mark, with which the init container routes the responses of the applications back to Envoy.
+func newOriginalSourceListenerFilter() (*xds_listener.ListenerFilter, error) {
+	marshalledOriginalSrc, err := ptypes.MarshalAny(&xds_original_src.OriginalSrc{
+		Mark: constants.EnvoyOriginalSourceMark,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &xds_listener.ListenerFilter{
+		Name: originalSourceListenerFilter,
+		ConfigType: &xds_listener.ListenerFilter_TypedConfig{
+			TypedConfig: marshalledOriginalSrc,
+		},
+	}, nil
+}
+
 func buildPrometheusListener(connManager *xds_hcm.HttpConnectionManager) (*xds_listener.Listener, error) {
 	marshalledConnManager, err := ptypes.MarshalAny(connManager)
 	if err != nil {
@@ -150,9 +261,56 @@ func buildPrometheusListener(connManager *xds_hcm.HttpConnectionManager) (*xds_l
 	}, nil
 }
 
+// securePrometheusListener serves the metrics of the given Prometheus listener over mTLS. The proxy presents its
+// service certificate, and an RBAC filter preceding the connection manager only allows the connections of the
+// Prometheus server presenting a certificate issued for the given client identity, in the format
+// <service-account>.<namespace>.
+func (lb *listenerBuilder) securePrometheusListener(listener *xds_listener.Listener, clientIdentity string) error {
+	marshalledDownstreamTLSContext, err := ptypes.MarshalAny(envoy.GetDownstreamTLSContext(lb.serviceIdentity, true /* mTLS */))
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingXDSResource)).
+			Msgf("Error marshalling DownstreamTLSContext for the Prometheus listener of proxy identity %s", lb.serviceIdentity)
+		return err
+	}
+
+	scraperIdentity := identity.ServiceIdentity(fmt.Sprintf("%s.%s", clientIdentity, identity.ClusterLocalTrustDomain))
+	policy := &rbac.Policy{
+		Principals: []rbac.RulesList{{OrRules: rbac.GetPrincipalRules(scraperIdentity, lb.cfg.GetSPIFFETrustDomain())}},
+	}
+	xdsPolicy, err := policy.Generate()
+	if err != nil {
+		return err
+	}
+	rbacFilter, err := marshalRBACFilter(&xds_network_rbac.RBAC{
+		StatPrefix: "prometheus-", // will be displayed as prometheus-rbac.<path>
+		Rules: &xds_rbac.RBAC{
+			Action: xds_rbac.RBAC_ALLOW,
+			Policies: map[string]*xds_rbac.Policy{
+				prometheusRBACPolicyName: xdsPolicy,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, filterChain := range listener.FilterChains {
+		filterChain.Filters = append([]*xds_listener.Filter{rbacFilter}, filterChain.Filters...)
+		filterChain.TransportSocket = &xds_core.TransportSocket{
+			Name: wellknown.TransportSocketTls,
+			ConfigType: &xds_core.TransportSocket_TypedConfig{
+				TypedConfig: marshalledDownstreamTLSContext,
+			},
+		}
+	}
+	return nil
+}
+
 // getDefaultPassthroughFilterChain returns a filter chain that matches any traffic, allowing such
 // traffic to be proxied to its original destination via the OutboundPassthroughCluster.
-func getDefaultPassthroughFilterChain() (*xds_listener.FilterChain, error) {
+// When the given passthrough config restricts the allowed destinations, connections to other
+// destinations are denied by an RBAC filter preceding the TCP proxy.
+func getDefaultPassthroughFilterChain(passthrough configv1alpha1.OutboundPassthroughSpec) (*xds_listener.FilterChain, error) {
 	tcpProxy := &xds_tcp_proxy.TcpProxy{
 		StatPrefix:       fmt.Sprintf("%s.%s", egressTCPProxyStatPrefix, envoy.OutboundPassthroughCluster),
 		ClusterSpecifier: &xds_tcp_proxy.TcpProxy_Cluster{Cluster: envoy.OutboundPassthroughCluster},
@@ -164,14 +322,24 @@ func getDefaultPassthroughFilterChain() (*xds_listener.FilterChain, error) {
 		return nil, err
 	}
 
+	var filters []*xds_listener.Filter
+	if len(passthrough.AllowedPorts) != 0 || len(passthrough.AllowedIPRanges) != 0 {
+		rbacFilter, err := buildOutboundPassthroughRBACFilter(passthrough)
+		if err != nil {
+			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrBuildingRBACPolicy)).
+				Msgf("Error building RBAC filter for the outbound passthrough filter chain")
+			return nil, err
+		}
+		filters = append(filters, rbacFilter)
+	}
+	filters = append(filters, &xds_listener.Filter{
+		Name:       wellknown.TCPProxy,
+		ConfigType: &xds_listener.Filter_TypedConfig{TypedConfig: marshalledTCPProxy},
+	})
+
 	return &xds_listener.FilterChain{
-		Name: outboundEgressFilterChainName,
-		Filters: []*xds_listener.Filter{
-			{
-				Name:       wellknown.TCPProxy,
-				ConfigType: &xds_listener.Filter_TypedConfig{TypedConfig: marshalledTCPProxy},
-			},
-		},
+		Name:    outboundEgressFilterChainName,
+		Filters: filters,
 	}, nil
 }
 
@@ -197,6 +365,9 @@ func getFilterMatchPredicateForTrafficMatches(matches []*trafficpolicy.TrafficMa
 		return nil
 	}
 
+	// For deterministic ordering, since a change to the listener filters drains all the connections of the listener
+	sort.Ints(ports)
+
 	return getFilterMatchPredicateForPorts(ports)
 }
 
@@ -1,20 +1,34 @@
 package lds
 
 import (
+	"fmt"
 	"testing"
 
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_rbac "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
+	xds_original_src "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/listener/original_src/v3"
+	xds_network_rbac "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/rbac/v3"
+	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	xds_type "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	tassert "github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/catalog"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/endpoint"
 	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/tests"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
@@ -31,6 +45,8 @@ var _ = Describe("Construct inbound listeners", func() {
 	mockConfigurator = configurator.NewMockConfigurator(mockCtrl)
 
 	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetHTTPSanitizationConfig().Return(configv1alpha1.HTTPSanitizationSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().GetDownstreamLimitsConfig().Return(configv1alpha1.DownstreamLimitsSpec{}).AnyTimes()
 	mockConfigurator.EXPECT().GetTracingHost().Return(constants.DefaultTracingHost).AnyTimes()
 	mockConfigurator.EXPECT().GetTracingPort().Return(constants.DefaultTracingPort).AnyTimes()
 
@@ -166,3 +182,198 @@ func TestGetFilterMatchPredicateForTrafficMatches(t *testing.T) {
 		})
 	}
 }
+
+func TestHasProtocolDetectionFilterChains(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.False(hasProtocolDetectionFilterChains(nil))
+	assert.False(hasProtocolDetectionFilterChains([]*xds_listener.FilterChain{
+		{Name: "no-match"},
+		{Name: "port-match", FilterChainMatch: &xds_listener.FilterChainMatch{DestinationPort: &wrapperspb.UInt32Value{Value: 80}}},
+	}))
+	assert.True(hasProtocolDetectionFilterChains([]*xds_listener.FilterChain{
+		{Name: "port-match", FilterChainMatch: &xds_listener.FilterChainMatch{DestinationPort: &wrapperspb.UInt32Value{Value: 80}}},
+		{Name: "http-match", FilterChainMatch: &xds_listener.FilterChainMatch{ApplicationProtocols: envoy.ALPNHTTPInspector}},
+	}))
+}
+
+func TestSortFilterChains(t *testing.T) {
+	assert := tassert.New(t)
+
+	filterChains := []*xds_listener.FilterChain{
+		{Name: "outbound-mesh-tcp-filter-chain:ns/svc:90"},
+		{Name: "outbound-mesh-http-filter-chain:ns/svc:80"},
+		{Name: "egress-tcp.443"},
+		{Name: "outbound-mesh-http-filter-chain:ns/svc:8080"},
+	}
+	sortFilterChains(filterChains)
+
+	var names []string
+	for _, filterChain := range filterChains {
+		names = append(names, filterChain.Name)
+	}
+	assert.Equal([]string{
+		"egress-tcp.443",
+		"outbound-mesh-http-filter-chain:ns/svc:80",
+		"outbound-mesh-http-filter-chain:ns/svc:8080",
+		"outbound-mesh-tcp-filter-chain:ns/svc:90",
+	}, names)
+}
+
+func BenchmarkNewOutboundListener(b *testing.B) {
+	// Keep the logging of the builders out of the measurements
+	if err := logger.SetLogLevel("error"); err != nil {
+		b.Fatal(err)
+	}
+
+	for _, services := range tests.BenchmarkTopologyServices {
+		b.Run(fmt.Sprintf("%d services", services), func(b *testing.B) {
+			topology := tests.NewTopologyBuilder().
+				WithNamespaces(tests.BenchmarkTopologyNamespaces).
+				WithServicesPerNamespace(services / tests.BenchmarkTopologyNamespaces).
+				Build()
+
+			mockCtrl := gomock.NewController(b)
+			mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockCatalog.EXPECT().ListMeshServicesForIdentity(gomock.Any()).Return(topology.MeshServices()).AnyTimes()
+			mockCatalog.EXPECT().GetPortToProtocolMappingForService(gomock.Any()).Return(map[uint32]string{tests.ServicePort: constants.ProtocolHTTP}, nil).AnyTimes()
+			mockCatalog.EXPECT().GetResolvableServiceEndpoints(gomock.Any()).DoAndReturn(func(svc service.MeshService) ([]endpoint.Endpoint, error) {
+				return topology.ServiceEndpoints(svc), nil
+			}).AnyTimes()
+			mockCatalog.EXPECT().ListExternalServicesForIdentity(gomock.Any()).Return(nil).AnyTimes()
+			mockConfigurator.EXPECT().IsEgressEnabled().Return(false).AnyTimes()
+			mockConfigurator.EXPECT().GetFeatureFlags().Return(configv1alpha1.FeatureFlags{}).AnyTimes()
+			mockConfigurator.EXPECT().IsProtocolDetectionEnabled(gomock.Any()).Return(false).AnyTimes()
+			mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+			mockConfigurator.EXPECT().GetHTTPSanitizationConfig().Return(configv1alpha1.HTTPSanitizationSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetDownstreamLimitsConfig().Return(configv1alpha1.DownstreamLimitsSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetTracingEndpoint().Return("").AnyTimes()
+
+			lb := &listenerBuilder{
+				meshCatalog:     mockCatalog,
+				cfg:             mockConfigurator,
+				serviceIdentity: identity.ServiceIdentity("svc-0.ns-0.cluster.local"),
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := lb.newOutboundListener(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestGetDefaultPassthroughFilterChain(t *testing.T) {
+	testCases := []struct {
+		name                string
+		passthrough         configv1alpha1.OutboundPassthroughSpec
+		expectedFilterNames []string
+	}{
+		{
+			name:                "passthrough to any destination",
+			passthrough:         configv1alpha1.OutboundPassthroughSpec{},
+			expectedFilterNames: []string{wellknown.TCPProxy},
+		},
+		{
+			name: "passthrough to allowed destinations",
+			passthrough: configv1alpha1.OutboundPassthroughSpec{
+				AllowedPorts:    []int{443},
+				AllowedIPRanges: []string{"10.0.0.0/8"},
+			},
+			expectedFilterNames: []string{wellknown.RoleBasedAccessControl, wellknown.TCPProxy},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			filterChain, err := getDefaultPassthroughFilterChain(tc.passthrough)
+			assert.Nil(err)
+			assert.Equal(outboundEgressFilterChainName, filterChain.Name)
+			assert.Nil(filterChain.FilterChainMatch)
+			assert.Len(filterChain.Filters, len(tc.expectedFilterNames))
+			for i, filter := range filterChain.Filters {
+				assert.Equal(tc.expectedFilterNames[i], filter.Name)
+			}
+		})
+	}
+}
+
+func TestSetProxyProtocolListenerFilters(t *testing.T) {
+	assert := tassert.New(t)
+
+	listener := newInboundListener()
+	setProxyProtocolListenerFilters(listener, nil)
+	assert.Equal(newInboundListener().ListenerFilters, listener.ListenerFilters)
+
+	setProxyProtocolListenerFilters(listener, []int{443, 80})
+	assert.Len(listener.ListenerFilters, 4)
+	assert.Equal(wellknown.OriginalDestination, listener.ListenerFilters[0].Name)
+	assert.Equal(wellknown.ProxyProtocol, listener.ListenerFilters[1].Name)
+	assert.Equal(wellknown.OriginalDestination, listener.ListenerFilters[2].Name)
+	assert.Equal(wellknown.TlsInspector, listener.ListenerFilters[3].Name)
+
+	// The PROXY protocol listener filter is disabled on the ports not accepting the header
+	assert.Equal(&xds_listener.ListenerFilterChainMatchPredicate{
+		Rule: &xds_listener.ListenerFilterChainMatchPredicate_NotMatch{
+			NotMatch: getFilterMatchPredicateForPorts([]int{80, 443}),
+		},
+	}, listener.ListenerFilters[1].FilterDisabled)
+}
+
+func TestNewOriginalSourceListenerFilter(t *testing.T) {
+	assert := tassert.New(t)
+
+	listenerFilter, err := newOriginalSourceListenerFilter()
+	assert.Nil(err)
+	assert.Equal(originalSourceListenerFilter, listenerFilter.Name)
+
+	originalSrc := &xds_original_src.OriginalSrc{}
+	assert.Nil(ptypes.UnmarshalAny(listenerFilter.GetTypedConfig(), originalSrc))
+	assert.Equal(uint32(constants.EnvoyOriginalSourceMark), originalSrc.Mark)
+}
+
+func TestSecurePrometheusListener(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().GetSPIFFETrustDomain().Return("").AnyTimes()
+
+	lb := &listenerBuilder{
+		serviceIdentity: tests.BookstoreServiceIdentity,
+		cfg:             mockConfigurator,
+	}
+
+	listener, err := buildPrometheusListener(getPrometheusConnectionManager())
+	assert.Nil(err)
+	assert.Nil(lb.securePrometheusListener(listener, "osm-prometheus.osm-system"))
+
+	assert.Len(listener.FilterChains, 1)
+	filterChain := listener.FilterChains[0]
+	assert.Equal(wellknown.TransportSocketTls, filterChain.TransportSocket.Name)
+	assert.Len(filterChain.Filters, 2)
+	assert.Equal(wellknown.RoleBasedAccessControl, filterChain.Filters[0].Name)
+	assert.Equal(wellknown.HTTPConnectionManager, filterChain.Filters[1].Name)
+
+	// Only the Prometheus server is allowed to scrape the metrics
+	networkRBAC := &xds_network_rbac.RBAC{}
+	assert.Nil(ptypes.UnmarshalAny(filterChain.Filters[0].GetTypedConfig(), networkRBAC))
+	assert.Equal(xds_rbac.RBAC_ALLOW, networkRBAC.Rules.Action)
+	policy := networkRBAC.Rules.Policies[prometheusRBACPolicyName]
+	assert.Len(policy.Principals, 1)
+	principals := policy.Principals[0].GetOrIds().Ids
+	assert.Len(principals, 1)
+	assert.Equal("osm-prometheus.osm-system.cluster.local", principals[0].GetAuthenticated().PrincipalName.GetExact())
+
+	// The proxy presents its service certificate and requires a client certificate
+	downstreamTLSContext := &xds_auth.DownstreamTlsContext{}
+	assert.Nil(ptypes.UnmarshalAny(filterChain.TransportSocket.GetTypedConfig(), downstreamTLSContext))
+	assert.True(downstreamTLSContext.RequireClientCertificate.Value)
+}
@@ -0,0 +1,105 @@
+package lds
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	xds_adaptive_concurrency "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/adaptive_concurrency/v3"
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+const (
+	// adaptiveConcurrencyFilterName is the name of the HTTP filter shedding the excess load of a service
+	adaptiveConcurrencyFilterName = "envoy.filters.http.adaptive_concurrency"
+
+	// adaptiveConcurrencyUpdateInterval is the period the latencies of the requests are sampled over to recalculate
+	// the concurrency limit of a service
+	adaptiveConcurrencyUpdateInterval = 100 * time.Millisecond
+
+	// adaptiveConcurrencyMinRTTInterval is the period between the measurements of the latency of a service while
+	// unloaded, which the concurrency limit is calculated from
+	adaptiveConcurrencyMinRTTInterval = 60 * time.Second
+)
+
+// loadSheddingConfig is the configuration of the shedding of the excess load of a service
+type loadSheddingConfig struct {
+	// targetLatency is the latency the service is expected to serve requests in while overloaded
+	targetLatency time.Duration
+
+	// minRPS is the minimum rate of requests per second the service keeps serving while overloaded, unset if 0
+	minRPS uint32
+}
+
+// getLoadSheddingConfig returns the configuration of the shedding of the excess load of the given service, nil if
+// its excess load is not shed. The load of a service is shed when its target latency is set by its load shedding
+// annotations.
+func (lb *listenerBuilder) getLoadSheddingConfig(svc service.MeshService) *loadSheddingConfig {
+	k8sSvc := lb.meshCatalog.GetKubeController().GetService(svc)
+	if k8sSvc == nil {
+		return nil
+	}
+
+	value, ok := k8sSvc.Annotations[constants.LoadSheddingTargetLatencyAnnotation]
+	if !ok {
+		return nil
+	}
+	targetLatency, err := time.ParseDuration(value)
+	if err != nil || targetLatency <= 0 {
+		log.Warn().Err(err).Msgf("Ignoring invalid %s annotation on service %s", constants.LoadSheddingTargetLatencyAnnotation, svc)
+		return nil
+	}
+	config := &loadSheddingConfig{targetLatency: targetLatency}
+
+	if value, ok := k8sSvc.Annotations[constants.LoadSheddingMinRPSAnnotation]; ok {
+		if minRPS, err := strconv.ParseUint(value, 10, 32); err != nil {
+			log.Warn().Err(err).Msgf("Ignoring invalid %s annotation on service %s", constants.LoadSheddingMinRPSAnnotation, svc)
+		} else {
+			config.minRPS = uint32(minRPS)
+		}
+	}
+
+	return config
+}
+
+// getAdaptiveConcurrencyFilter returns the HTTP filter shedding the excess load of a service with the given
+// configuration. The filter limits the number of concurrent requests to the service, rejecting the requests over the
+// limit, and periodically adjusts the limit to the latency of the service measured while it is unloaded.
+// When a minimum rate of requests is configured, the limit is never lowered below the concurrency required to serve
+// this rate at the target latency, following Little's law.
+func getAdaptiveConcurrencyFilter(config *loadSheddingConfig) (*xds_hcm.HttpFilter, error) {
+	minRTTCalcParams := &xds_adaptive_concurrency.GradientControllerConfig_MinimumRTTCalculationParams{
+		Interval: ptypes.DurationProto(adaptiveConcurrencyMinRTTInterval),
+	}
+	if config.minRPS > 0 {
+		minConcurrency := math.Ceil(float64(config.minRPS) * config.targetLatency.Seconds())
+		minRTTCalcParams.MinConcurrency = wrapperspb.UInt32(uint32(minConcurrency))
+	}
+
+	adaptiveConcurrencyAny, err := ptypes.MarshalAny(&xds_adaptive_concurrency.AdaptiveConcurrency{
+		ConcurrencyControllerConfig: &xds_adaptive_concurrency.AdaptiveConcurrency_GradientControllerConfig{
+			GradientControllerConfig: &xds_adaptive_concurrency.GradientControllerConfig{
+				ConcurrencyLimitParams: &xds_adaptive_concurrency.GradientControllerConfig_ConcurrencyLimitCalculationParams{
+					ConcurrencyUpdateInterval: ptypes.DurationProto(adaptiveConcurrencyUpdateInterval),
+				},
+				MinRttCalcParams: minRTTCalcParams,
+			},
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshaling adaptive concurrency filter")
+	}
+
+	return &xds_hcm.HttpFilter{
+		Name: adaptiveConcurrencyFilterName,
+		ConfigType: &xds_hcm.HttpFilter_TypedConfig{
+			TypedConfig: adaptiveConcurrencyAny,
+		},
+	}, nil
+}
@@ -0,0 +1,155 @@
+package lds
+
+import (
+	"testing"
+	"time"
+
+	xds_adaptive_concurrency "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/adaptive_concurrency/v3"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/tests"
+)
+
+func TestGetLoadSheddingConfig(t *testing.T) {
+	testCases := []struct {
+		name              string
+		annotations       map[string]string
+		serviceNotInCache bool
+		expectedConfig    *loadSheddingConfig
+	}{
+		{
+			name:              "service not in cache",
+			serviceNotInCache: true,
+			expectedConfig:    nil,
+		},
+		{
+			name:           "load shedding not configured",
+			expectedConfig: nil,
+		},
+		{
+			name: "target latency configured",
+			annotations: map[string]string{
+				constants.LoadSheddingTargetLatencyAnnotation: "250ms",
+			},
+			expectedConfig: &loadSheddingConfig{targetLatency: 250 * time.Millisecond},
+		},
+		{
+			name: "target latency and minimum rate configured",
+			annotations: map[string]string{
+				constants.LoadSheddingTargetLatencyAnnotation: "250ms",
+				constants.LoadSheddingMinRPSAnnotation:        "100",
+			},
+			expectedConfig: &loadSheddingConfig{targetLatency: 250 * time.Millisecond, minRPS: 100},
+		},
+		{
+			name: "minimum rate without target latency is ignored",
+			annotations: map[string]string{
+				constants.LoadSheddingMinRPSAnnotation: "100",
+			},
+			expectedConfig: nil,
+		},
+		{
+			name: "invalid target latency is ignored",
+			annotations: map[string]string{
+				constants.LoadSheddingTargetLatencyAnnotation: "250",
+				constants.LoadSheddingMinRPSAnnotation:        "100",
+			},
+			expectedConfig: nil,
+		},
+		{
+			name: "negative target latency is ignored",
+			annotations: map[string]string{
+				constants.LoadSheddingTargetLatencyAnnotation: "-1s",
+			},
+			expectedConfig: nil,
+		},
+		{
+			name: "invalid minimum rate is ignored",
+			annotations: map[string]string{
+				constants.LoadSheddingTargetLatencyAnnotation: "1s",
+				constants.LoadSheddingMinRPSAnnotation:        "100rps",
+			},
+			expectedConfig: &loadSheddingConfig{targetLatency: time.Second},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+			mockKubeController := k8s.NewMockController(mockCtrl)
+
+			lb := &listenerBuilder{
+				meshCatalog: mockCatalog,
+			}
+
+			var svc *corev1.Service
+			if !tc.serviceNotInCache {
+				svc = &corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        tests.BookstoreV1Service.Name,
+						Namespace:   tests.BookstoreV1Service.Namespace,
+						Annotations: tc.annotations,
+					},
+				}
+			}
+			mockCatalog.EXPECT().GetKubeController().Return(mockKubeController)
+			mockKubeController.EXPECT().GetService(tests.BookstoreV1Service).Return(svc)
+
+			assert.Equal(tc.expectedConfig, lb.getLoadSheddingConfig(tests.BookstoreV1Service))
+		})
+	}
+}
+
+func TestGetAdaptiveConcurrencyFilter(t *testing.T) {
+	testCases := []struct {
+		name                   string
+		config                 *loadSheddingConfig
+		expectedMinConcurrency uint32
+	}{
+		{
+			name:                   "default minimum concurrency without minimum rate",
+			config:                 &loadSheddingConfig{targetLatency: 250 * time.Millisecond},
+			expectedMinConcurrency: 0,
+		},
+		{
+			name:                   "minimum concurrency serving the minimum rate at the target latency",
+			config:                 &loadSheddingConfig{targetLatency: 250 * time.Millisecond, minRPS: 100},
+			expectedMinConcurrency: 25,
+		},
+		{
+			name:                   "minimum concurrency rounded up",
+			config:                 &loadSheddingConfig{targetLatency: 10 * time.Millisecond, minRPS: 10},
+			expectedMinConcurrency: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			filter, err := getAdaptiveConcurrencyFilter(tc.config)
+			assert.Nil(err)
+			assert.Equal(adaptiveConcurrencyFilterName, filter.Name)
+
+			adaptiveConcurrency := &xds_adaptive_concurrency.AdaptiveConcurrency{}
+			assert.Nil(ptypes.UnmarshalAny(filter.GetTypedConfig(), adaptiveConcurrency))
+			assert.Nil(adaptiveConcurrency.Validate())
+
+			gradientControllerConfig := adaptiveConcurrency.GetGradientControllerConfig()
+			assert.Equal(ptypes.DurationProto(adaptiveConcurrencyUpdateInterval), gradientControllerConfig.ConcurrencyLimitParams.ConcurrencyUpdateInterval)
+			assert.Equal(ptypes.DurationProto(adaptiveConcurrencyMinRTTInterval), gradientControllerConfig.MinRttCalcParams.Interval)
+			assert.Equal(tc.expectedMinConcurrency, gradientControllerConfig.MinRttCalcParams.MinConcurrency.GetValue())
+		})
+	}
+}
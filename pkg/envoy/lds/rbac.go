@@ -9,12 +9,22 @@ import (
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/golang/protobuf/ptypes"
 
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/envoy/rbac"
 	"github.com/openservicemesh/osm/pkg/errcode"
 	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
+const (
+	// outboundPassthroughRBACPolicyName is the name of the RBAC policy allowing the destinations of the outbound passthrough
+	outboundPassthroughRBACPolicyName = "outbound-passthrough"
+
+	// prometheusRBACPolicyName is the name of the RBAC policy allowing the Prometheus server to scrape the metrics
+	// served over mTLS
+	prometheusRBACPolicyName = "prometheus-scraper"
+)
+
 // buildRBACFilter builds an RBAC filter based on SMI TrafficTarget policies.
 // The returned RBAC filter has policies that gives downstream principals full access to the local service.
 func (lb *listenerBuilder) buildRBACFilter() (*xds_listener.Filter, error) {
@@ -24,6 +34,24 @@ func (lb *listenerBuilder) buildRBACFilter() (*xds_listener.Filter, error) {
 		return nil, err
 	}
 
+	if lb.cfg.GetRBACAuditConfig().ShadowMode {
+		networkRBACPolicy = withShadowRules(networkRBACPolicy)
+	}
+
+	return marshalRBACFilter(networkRBACPolicy)
+}
+
+// withShadowRules returns the given RBAC policy with its rules evaluated in shadow mode: the connections they deny are
+// counted in the shadow_denied stat of the filter but allowed through
+func withShadowRules(networkRBACPolicy *xds_network_rbac.RBAC) *xds_network_rbac.RBAC {
+	return &xds_network_rbac.RBAC{
+		StatPrefix:  networkRBACPolicy.StatPrefix,
+		ShadowRules: networkRBACPolicy.Rules,
+	}
+}
+
+// marshalRBACFilter returns the network RBAC filter enforcing the given RBAC policy
+func marshalRBACFilter(networkRBACPolicy *xds_network_rbac.RBAC) (*xds_listener.Filter, error) {
 	marshalledNetworkRBACPolicy, err := ptypes.MarshalAny(networkRBACPolicy)
 	if err != nil {
 		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingXDSResource)).
@@ -49,10 +77,21 @@ func (lb *listenerBuilder) buildInboundRBACPolicies() (*xds_network_rbac.RBAC, e
 		return nil, err
 	}
 
+	return buildRBACPoliciesFromTrafficTargets(proxyIdentity, trafficTargets, lb.newPrincipalCompactor()), nil
+}
+
+// newPrincipalCompactor returns the compactor of the principals of the RBAC policies built for the proxy
+func (lb *listenerBuilder) newPrincipalCompactor() *rbac.PrincipalCompactor {
+	return rbac.NewPrincipalCompactor(lb.cfg.GetSPIFFETrustDomain())
+}
+
+// buildRBACPoliciesFromTrafficTargets builds the RBAC policies allowing the sources of the given traffic targets
+// to connect to the given identity, with the principals of the sources built by the given compactor
+func buildRBACPoliciesFromTrafficTargets(proxyIdentity identity.ServiceIdentity, trafficTargets []trafficpolicy.TrafficTargetWithRoutes, principalCompactor *rbac.PrincipalCompactor) *xds_network_rbac.RBAC {
 	rbacPolicies := make(map[string]*xds_rbac.Policy)
 	// Build an RBAC policies based on SMI TrafficTarget policies
 	for _, targetPolicy := range trafficTargets {
-		if policy, err := buildRBACPolicyFromTrafficTarget(targetPolicy); err != nil {
+		if policy, err := buildRBACPolicyFromTrafficTarget(targetPolicy, principalCompactor); err != nil {
 			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrBuildingRBACPolicy)).
 				Msgf("Error building RBAC policy for proxy identity %s from TrafficTarget %s", proxyIdentity, targetPolicy.Name)
 		} else {
@@ -63,32 +102,22 @@ func (lb *listenerBuilder) buildInboundRBACPolicies() (*xds_network_rbac.RBAC, e
 	log.Debug().Msgf("RBAC policy for proxy with identity %s: %+v", proxyIdentity, rbacPolicies)
 
 	// Create an inbound RBAC policy that denies a request by default, unless a policy explicitly allows it
-	networkRBACPolicy := &xds_network_rbac.RBAC{
+	return &xds_network_rbac.RBAC{
 		StatPrefix: "network-", // will be displayed as network-rbac.<path>
 		Rules: &xds_rbac.RBAC{
 			Action:   xds_rbac.RBAC_ALLOW, // Allows the request if and only if there is a policy that matches the request
 			Policies: rbacPolicies,
 		},
 	}
-
-	return networkRBACPolicy, nil
 }
 
-// buildRBACPolicyFromTrafficTarget creates an XDS RBAC policy from the given traffic target policy
-func buildRBACPolicyFromTrafficTarget(trafficTarget trafficpolicy.TrafficTargetWithRoutes) (*xds_rbac.Policy, error) {
+// buildRBACPolicyFromTrafficTarget creates an XDS RBAC policy from the given traffic target policy, with the
+// principals of its sources built by the given compactor
+func buildRBACPolicyFromTrafficTarget(trafficTarget trafficpolicy.TrafficTargetWithRoutes, principalCompactor *rbac.PrincipalCompactor) (*xds_rbac.Policy, error) {
 	policy := &rbac.Policy{}
 
 	// Create the list of principals for this policy
-	var principalRuleList []rbac.RulesList
-	for _, downstreamPrincipal := range trafficTarget.Sources {
-		principalRule := rbac.RulesList{
-			OrRules: []rbac.Rule{
-				{Attribute: rbac.DownstreamAuthPrincipal, Value: downstreamPrincipal.String()},
-			},
-		}
-		principalRuleList = append(principalRuleList, principalRule)
-	}
-	policy.Principals = principalRuleList
+	policy.Principals = principalCompactor.GetPrincipalRules(trafficTarget.Sources)
 
 	// Create the list of permissions for this policy
 	var permissionRuleList []rbac.RulesList
@@ -113,3 +142,52 @@ func buildRBACPolicyFromTrafficTarget(trafficTarget trafficpolicy.TrafficTargetW
 
 	return policy.Generate()
 }
+
+// buildOutboundPassthroughRBACFilter builds an RBAC filter allowing the connections to the destinations allowed
+// by the given outbound passthrough config: a destination is allowed if its port is one of the allowed ports and
+// its IP is within one of the allowed IP ranges, an empty list allowing any port or IP respectively.
+func buildOutboundPassthroughRBACFilter(passthrough configv1alpha1.OutboundPassthroughSpec) (*xds_listener.Filter, error) {
+	var portRules []rbac.Rule
+	for _, port := range passthrough.AllowedPorts {
+		portRules = append(portRules, rbac.Rule{Attribute: rbac.DestinationPort, Value: strconv.Itoa(port)})
+	}
+	var ipRangeRules []rbac.Rule
+	for _, ipRange := range passthrough.AllowedIPRanges {
+		ipRangeRules = append(ipRangeRules, rbac.Rule{Attribute: rbac.DestinationIPRange, Value: ipRange})
+	}
+
+	policy := &rbac.Policy{}
+	switch {
+	case len(portRules) != 0 && len(ipRangeRules) != 0:
+		// Each permission allows a port within an IP range
+		for _, portRule := range portRules {
+			for _, ipRangeRule := range ipRangeRules {
+				policy.Permissions = append(policy.Permissions, rbac.RulesList{
+					AndRules: []rbac.Rule{portRule, ipRangeRule},
+				})
+			}
+		}
+
+	case len(portRules) != 0:
+		policy.Permissions = []rbac.RulesList{{OrRules: portRules}}
+
+	case len(ipRangeRules) != 0:
+		policy.Permissions = []rbac.RulesList{{OrRules: ipRangeRules}}
+	}
+
+	xdsPolicy, err := policy.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	// Deny the connections to destinations not allowed by the policy
+	return marshalRBACFilter(&xds_network_rbac.RBAC{
+		StatPrefix: "outbound-passthrough-", // will be displayed as outbound-passthrough-rbac.<path>
+		Rules: &xds_rbac.RBAC{
+			Action: xds_rbac.RBAC_ALLOW,
+			Policies: map[string]*xds_rbac.Policy{
+				outboundPassthroughRBACPolicyName: xdsPolicy,
+			},
+		},
+	})
+}
@@ -0,0 +1,105 @@
+package lds
+
+import (
+	xds_accesslog_filter "github.com/envoyproxy/go-control-plane/envoy/config/accesslog/v3"
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_accesslog_grpc "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/grpc/v3"
+	xds_matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/pkg/errors"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy/als"
+)
+
+const (
+	// rbacDeniedStatusCode is the status code of the responses to requests denied by the HTTP RBAC filter
+	rbacDeniedStatusCode = 403
+
+	// rbacShadowEngineResultKey is the key of the dynamic metadata of the HTTP RBAC filter holding the result of
+	// its shadow rules
+	rbacShadowEngineResultKey = "shadow_engine_result"
+
+	// rbacShadowEngineResultDenied is the result of the shadow rules of the HTTP RBAC filter when they deny a request
+	rbacShadowEngineResultDenied = "denied"
+)
+
+// getRBACDenialAccessLog returns an access log streaming the requests denied by the HTTP RBAC filter, either by its
+// rules or its shadow rules, to the gRPC access log service of the controller
+func getRBACDenialAccessLog() (*xds_accesslog_filter.AccessLog, error) {
+	grpcAccessLog := &xds_accesslog_grpc.HttpGrpcAccessLogConfig{
+		CommonConfig: &xds_accesslog_grpc.CommonGrpcAccessLogConfig{
+			LogName: als.DenialLogName,
+			GrpcService: &xds_core.GrpcService{
+				TargetSpecifier: &xds_core.GrpcService_EnvoyGrpc_{
+					EnvoyGrpc: &xds_core.GrpcService_EnvoyGrpc{
+						ClusterName: constants.OSMControllerName,
+					},
+				},
+			},
+			TransportApiVersion: xds_core.ApiVersion_V3,
+		},
+	}
+
+	marshalledGRPCAccessLog, err := ptypes.MarshalAny(grpcAccessLog)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error marshaling gRPC access log config")
+	}
+
+	return &xds_accesslog_filter.AccessLog{
+		Name: wellknown.HTTPGRPCAccessLog,
+		Filter: &xds_accesslog_filter.AccessLogFilter{
+			FilterSpecifier: &xds_accesslog_filter.AccessLogFilter_OrFilter{
+				OrFilter: &xds_accesslog_filter.OrFilter{
+					Filters: []*xds_accesslog_filter.AccessLogFilter{
+						{
+							// Requests denied by the RBAC rules
+							FilterSpecifier: &xds_accesslog_filter.AccessLogFilter_StatusCodeFilter{
+								StatusCodeFilter: &xds_accesslog_filter.StatusCodeFilter{
+									Comparison: &xds_accesslog_filter.ComparisonFilter{
+										Op: xds_accesslog_filter.ComparisonFilter_EQ,
+										Value: &xds_core.RuntimeUInt32{
+											DefaultValue: rbacDeniedStatusCode,
+										},
+									},
+								},
+							},
+						},
+						{
+							// Requests denied by the RBAC shadow rules
+							FilterSpecifier: &xds_accesslog_filter.AccessLogFilter_MetadataFilter{
+								MetadataFilter: &xds_accesslog_filter.MetadataFilter{
+									Matcher: &xds_matcher.MetadataMatcher{
+										Filter: wellknown.HTTPRoleBasedAccessControl,
+										Path: []*xds_matcher.MetadataMatcher_PathSegment{
+											{
+												Segment: &xds_matcher.MetadataMatcher_PathSegment_Key{
+													Key: rbacShadowEngineResultKey,
+												},
+											},
+										},
+										Value: &xds_matcher.ValueMatcher{
+											MatchPattern: &xds_matcher.ValueMatcher_StringMatch{
+												StringMatch: &xds_matcher.StringMatcher{
+													MatchPattern: &xds_matcher.StringMatcher_Exact{
+														Exact: rbacShadowEngineResultDenied,
+													},
+												},
+											},
+										},
+									},
+									MatchIfKeyNotFound: &wrappers.BoolValue{Value: false},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		ConfigType: &xds_accesslog_filter.AccessLog_TypedConfig{
+			TypedConfig: marshalledGRPCAccessLog,
+		},
+	}, nil
+}
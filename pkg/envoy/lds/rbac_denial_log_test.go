@@ -0,0 +1,34 @@
+package lds
+
+import (
+	"testing"
+
+	xds_accesslog_grpc "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/grpc/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy/als"
+)
+
+func TestGetRBACDenialAccessLog(t *testing.T) {
+	assert := tassert.New(t)
+
+	accessLog, err := getRBACDenialAccessLog()
+	assert.Nil(err)
+	assert.Equal(wellknown.HTTPGRPCAccessLog, accessLog.Name)
+
+	// Requests denied by either the RBAC rules or shadow rules are logged
+	filters := accessLog.GetFilter().GetOrFilter().GetFilters()
+	assert.Len(filters, 2)
+	assert.Equal(uint32(rbacDeniedStatusCode), filters[0].GetStatusCodeFilter().GetComparison().GetValue().GetDefaultValue())
+	assert.Equal(wellknown.HTTPRoleBasedAccessControl, filters[1].GetMetadataFilter().GetMatcher().GetFilter())
+	assert.Equal(rbacShadowEngineResultDenied, filters[1].GetMetadataFilter().GetMatcher().GetValue().GetStringMatch().GetExact())
+	assert.False(filters[1].GetMetadataFilter().GetMatchIfKeyNotFound().GetValue())
+
+	grpcAccessLog := &xds_accesslog_grpc.HttpGrpcAccessLogConfig{}
+	assert.Nil(ptypes.UnmarshalAny(accessLog.GetTypedConfig(), grpcAccessLog))
+	assert.Equal(als.DenialLogName, grpcAccessLog.CommonConfig.LogName)
+	assert.Equal(constants.OSMControllerName, grpcAccessLog.CommonConfig.GrpcService.GetEnvoyGrpc().ClusterName)
+}
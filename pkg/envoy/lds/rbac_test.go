@@ -8,12 +8,19 @@ import (
 	tassert "github.com/stretchr/testify/assert"
 
 	xds_rbac "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
+	xds_network_rbac "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/rbac/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/protobuf/proto"
 
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/envoy/rbac"
 
 	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/tests"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
@@ -140,7 +147,7 @@ func TestBuildRBACPolicyFromTrafficTarget(t *testing.T) {
 			assert := tassert.New(t)
 
 			// Test the RBAC policies
-			policy, err := buildRBACPolicyFromTrafficTarget(tc.trafficTarget)
+			policy, err := buildRBACPolicyFromTrafficTarget(tc.trafficTarget, rbac.NewPrincipalCompactor(""))
 
 			assert.Equal(tc.expectErr, err != nil)
 			assert.Equal(tc.expectedPolicy, policy)
@@ -153,10 +160,14 @@ func TestBuildInboundRBACPolicies(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockCatalog.EXPECT().GetKubeController().Return(mockKubeController).AnyTimes()
 	proxySvcAccount := identity.K8sServiceAccount{Name: "sa-1", Namespace: "ns-1"}
 
 	lb := &listenerBuilder{
 		meshCatalog:     mockCatalog,
+		cfg:             mockConfigurator,
 		serviceIdentity: proxySvcAccount.ToServiceIdentity(),
 	}
 
@@ -219,6 +230,7 @@ func TestBuildInboundRBACPolicies(t *testing.T) {
 
 			// Mock catalog calls
 			mockCatalog.EXPECT().ListInboundTrafficTargetsWithRoutes(proxySvcAccount.ToServiceIdentity()).Return(tc.trafficTargets, nil).Times(1)
+			mockConfigurator.EXPECT().GetSPIFFETrustDomain().Return("").Times(1)
 
 			// Test the RBAC policies
 			policy, err := lb.buildInboundRBACPolicies()
@@ -236,21 +248,91 @@ func TestBuildInboundRBACPolicies(t *testing.T) {
 	}
 }
 
+func TestBuildRBACPoliciesFromTrafficTargetsForTopology(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		namespaces           int
+		servicesPerNamespace int
+		sourcesPerTarget     int
+		expectedPrincipals   int
+	}{
+		{
+			name:                 "single source",
+			namespaces:           2,
+			servicesPerNamespace: 2,
+			sourcesPerTarget:     1,
+			expectedPrincipals:   1,
+		},
+		{
+			name:                 "sources spanning a whole namespace are not compacted",
+			namespaces:           10,
+			servicesPerNamespace: 20,
+			sourcesPerTarget:     50,
+			expectedPrincipals:   50,
+		},
+		{
+			name:                 "all the other service accounts of the topology",
+			namespaces:           3,
+			servicesPerNamespace: 10,
+			sourcesPerTarget:     29,
+			expectedPrincipals:   29,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			topology := tests.NewTopologyBuilder().
+				WithNamespaces(tc.namespaces).
+				WithServicesPerNamespace(tc.servicesPerNamespace).
+				WithTrafficTargets(tc.sourcesPerTarget).
+				Build()
+			proxyIdentity := identity.ServiceIdentity("svc-0.ns-0.cluster.local")
+			principalCompactor := rbac.NewPrincipalCompactor("")
+
+			policy := buildRBACPoliciesFromTrafficTargets(proxyIdentity, topology.InboundTrafficTargetsWithRoutes(proxyIdentity), principalCompactor)
+			assert.Len(policy.Rules.Policies, 1)
+			assert.Len(policy.Rules.Policies["ns-0/svc-0"].Principals, tc.expectedPrincipals)
+		})
+	}
+}
+
+func BenchmarkBuildRBACPoliciesFromTrafficTargets(b *testing.B) {
+	topology := tests.NewTopologyBuilder().
+		WithNamespaces(50).
+		WithServicesPerNamespace(20).
+		WithTrafficTargets(200).
+		Build()
+	proxyIdentity := identity.ServiceIdentity("svc-0.ns-0.cluster.local")
+	trafficTargets := topology.InboundTrafficTargetsWithRoutes(proxyIdentity)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buildRBACPoliciesFromTrafficTargets(proxyIdentity, trafficTargets, rbac.NewPrincipalCompactor(""))
+	}
+}
+
 func TestBuildRBACFilter(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 
 	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockCatalog.EXPECT().GetKubeController().Return(mockKubeController).AnyTimes()
 	proxySvcAccount := identity.K8sServiceAccount{Name: "sa-1", Namespace: "ns-1"}.ToServiceIdentity()
 
 	lb := &listenerBuilder{
 		meshCatalog:     mockCatalog,
+		cfg:             mockConfigurator,
 		serviceIdentity: proxySvcAccount,
 	}
 
 	testCases := []struct {
 		name           string
 		trafficTargets []trafficpolicy.TrafficTargetWithRoutes
+		shadowMode     bool
 
 		expectErr bool
 	}{
@@ -295,6 +377,23 @@ func TestBuildRBACFilter(t *testing.T) {
 
 			expectErr: false, // no error
 		},
+
+		{
+			// Test 3
+			name: "traffic target in shadow mode",
+			trafficTargets: []trafficpolicy.TrafficTargetWithRoutes{
+				{
+					Name:        "ns-1/test-1",
+					Destination: identity.ServiceIdentity("sa-1.ns-1.cluster.local"),
+					Sources: []identity.ServiceIdentity{
+						identity.ServiceIdentity("sa-2.ns-2.cluster.local"),
+					},
+				},
+			},
+			shadowMode: true,
+
+			expectErr: false, // no error
+		},
 	}
 
 	for i, tc := range testCases {
@@ -303,11 +402,136 @@ func TestBuildRBACFilter(t *testing.T) {
 
 			// Mock catalog calls
 			mockCatalog.EXPECT().ListInboundTrafficTargetsWithRoutes(proxySvcAccount).Return(tc.trafficTargets, nil).Times(1)
+			mockConfigurator.EXPECT().GetRBACAuditConfig().Return(configv1alpha1.RBACAuditSpec{ShadowMode: tc.shadowMode}).Times(1)
+			mockConfigurator.EXPECT().GetSPIFFETrustDomain().Return("").Times(1)
 
 			rbacFilter, err := lb.buildRBACFilter()
 			assert.Equal(err != nil, tc.expectErr)
 
 			assert.Equal(rbacFilter.Name, wellknown.RoleBasedAccessControl)
+
+			networkRBAC := &xds_network_rbac.RBAC{}
+			assert.Nil(ptypes.UnmarshalAny(rbacFilter.GetTypedConfig(), networkRBAC))
+			if tc.shadowMode {
+				assert.Nil(networkRBAC.Rules)
+				assert.Len(networkRBAC.ShadowRules.Policies, len(tc.trafficTargets))
+			} else {
+				assert.Nil(networkRBAC.ShadowRules)
+				assert.Len(networkRBAC.Rules.Policies, len(tc.trafficTargets))
+			}
+		})
+	}
+}
+
+func TestBuildOutboundPassthroughRBACFilter(t *testing.T) {
+	testCases := []struct {
+		name        string
+		passthrough configv1alpha1.OutboundPassthroughSpec
+
+		expectedPermissions []*xds_rbac.Permission
+		expectErr           bool
+	}{
+		{
+			name: "allowed ports",
+			passthrough: configv1alpha1.OutboundPassthroughSpec{
+				AllowedPorts: []int{443, 8443},
+			},
+			expectedPermissions: []*xds_rbac.Permission{
+				{
+					Rule: &xds_rbac.Permission_OrRules{
+						OrRules: &xds_rbac.Permission_Set{
+							Rules: []*xds_rbac.Permission{
+								rbac.GetDestinationPortPermission(443),
+								rbac.GetDestinationPortPermission(8443),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "allowed IP ranges",
+			passthrough: configv1alpha1.OutboundPassthroughSpec{
+				AllowedIPRanges: []string{"10.0.0.0/8"},
+			},
+			expectedPermissions: []*xds_rbac.Permission{
+				{
+					Rule: &xds_rbac.Permission_OrRules{
+						OrRules: &xds_rbac.Permission_Set{
+							Rules: []*xds_rbac.Permission{
+								getDestinationIPPermission(t, "10.0.0.0/8"),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "allowed ports within allowed IP ranges",
+			passthrough: configv1alpha1.OutboundPassthroughSpec{
+				AllowedPorts:    []int{443},
+				AllowedIPRanges: []string{"10.0.0.0/8", "192.168.0.0/16"},
+			},
+			expectedPermissions: []*xds_rbac.Permission{
+				{
+					Rule: &xds_rbac.Permission_AndRules{
+						AndRules: &xds_rbac.Permission_Set{
+							Rules: []*xds_rbac.Permission{
+								rbac.GetDestinationPortPermission(443),
+								getDestinationIPPermission(t, "10.0.0.0/8"),
+							},
+						},
+					},
+				},
+				{
+					Rule: &xds_rbac.Permission_AndRules{
+						AndRules: &xds_rbac.Permission_Set{
+							Rules: []*xds_rbac.Permission{
+								rbac.GetDestinationPortPermission(443),
+								getDestinationIPPermission(t, "192.168.0.0/16"),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "invalid IP range",
+			passthrough: configv1alpha1.OutboundPassthroughSpec{
+				AllowedIPRanges: []string{"10.0.0.0"},
+			},
+			expectErr: true,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("Testing test case %d: %s", i, tc.name), func(t *testing.T) {
+			assert := tassert.New(t)
+
+			rbacFilter, err := buildOutboundPassthroughRBACFilter(tc.passthrough)
+			assert.Equal(tc.expectErr, err != nil)
+			if err != nil {
+				return
+			}
+
+			assert.Equal(wellknown.RoleBasedAccessControl, rbacFilter.Name)
+
+			networkRBAC := &xds_network_rbac.RBAC{}
+			assert.Nil(ptypes.UnmarshalAny(rbacFilter.GetTypedConfig(), networkRBAC))
+			assert.Equal(xds_rbac.RBAC_ALLOW, networkRBAC.Rules.Action)
+			assert.Len(networkRBAC.Rules.Policies, 1)
+
+			policy := networkRBAC.Rules.Policies[outboundPassthroughRBACPolicyName]
+			assert.NotNil(policy)
+			assert.True(proto.Equal(&xds_rbac.Policy{Permissions: tc.expectedPermissions}, &xds_rbac.Policy{Permissions: policy.Permissions}))
+			assert.Len(policy.Principals, 1)
+			assert.True(policy.Principals[0].GetAny())
 		})
 	}
 }
+
+func getDestinationIPPermission(t *testing.T, ipRange string) *xds_rbac.Permission {
+	permission, err := rbac.GetDestinationIPPermission(ipRange)
+	tassert.Nil(t, err)
+	return permission
+}
@@ -0,0 +1,61 @@
+package lds
+
+import (
+	"strconv"
+
+	xds_buffer "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/buffer/v3"
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// maxRequestHeadersKbLimit is the largest maximum size in KiB of the headers of requests allowed by Envoy
+const maxRequestHeadersKbLimit = 8192
+
+// getRequestLimitsConfig returns the limits on the size of the requests to the given service. The MeshConfig limits
+// are overridden by the request limit annotations of the service.
+func (lb *listenerBuilder) getRequestLimitsConfig(svc service.MeshService) configv1alpha1.RequestLimitsSpec {
+	limits := lb.cfg.GetRequestLimitsConfig()
+
+	if k8sSvc := lb.meshCatalog.GetKubeController().GetService(svc); k8sSvc != nil {
+		if value, ok := k8sSvc.Annotations[constants.MaxRequestBytesAnnotation]; ok {
+			if maxRequestBytes, err := strconv.ParseUint(value, 10, 32); err != nil {
+				log.Warn().Err(err).Msgf("Ignoring invalid %s annotation on service %s", constants.MaxRequestBytesAnnotation, svc)
+			} else {
+				limits.MaxRequestBytes = uint32(maxRequestBytes)
+			}
+		}
+	}
+
+	if limits.MaxRequestHeadersKb > maxRequestHeadersKbLimit {
+		log.Warn().Msgf("Ignoring maximum request headers size of %d KiB for service %s, larger than the limit of %d KiB",
+			limits.MaxRequestHeadersKb, svc, maxRequestHeadersKbLimit)
+		limits.MaxRequestHeadersKb = 0
+	}
+
+	return limits
+}
+
+// getBufferFilter returns the HTTP filter buffering requests, which rejects the requests whose body is larger than
+// the given size in bytes
+func getBufferFilter(maxRequestBytes uint32) (*xds_hcm.HttpFilter, error) {
+	bufferAny, err := ptypes.MarshalAny(&xds_buffer.Buffer{
+		MaxRequestBytes: wrapperspb.UInt32(maxRequestBytes),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshaling buffer filter")
+	}
+
+	return &xds_hcm.HttpFilter{
+		Name: wellknown.Buffer,
+		ConfigType: &xds_hcm.HttpFilter_TypedConfig{
+			TypedConfig: bufferAny,
+		},
+	}, nil
+}
@@ -0,0 +1,104 @@
+package lds
+
+import (
+	"testing"
+
+	xds_buffer "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/buffer/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/ptypes"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/tests"
+)
+
+func TestGetRequestLimitsConfig(t *testing.T) {
+	testCases := []struct {
+		name           string
+		meshConfig     configv1alpha1.RequestLimitsSpec
+		annotations    map[string]string
+		expectedConfig configv1alpha1.RequestLimitsSpec
+	}{
+		{
+			name:           "no limits",
+			meshConfig:     configv1alpha1.RequestLimitsSpec{},
+			expectedConfig: configv1alpha1.RequestLimitsSpec{},
+		},
+		{
+			name:           "MeshConfig limits",
+			meshConfig:     configv1alpha1.RequestLimitsSpec{MaxRequestBytes: 1024, MaxRequestHeadersKb: 96},
+			expectedConfig: configv1alpha1.RequestLimitsSpec{MaxRequestBytes: 1024, MaxRequestHeadersKb: 96},
+		},
+		{
+			name:       "maximum request size overridden by annotation",
+			meshConfig: configv1alpha1.RequestLimitsSpec{MaxRequestBytes: 1024},
+			annotations: map[string]string{
+				constants.MaxRequestBytesAnnotation: "4096",
+			},
+			expectedConfig: configv1alpha1.RequestLimitsSpec{MaxRequestBytes: 4096},
+		},
+		{
+			name:       "invalid annotation is ignored",
+			meshConfig: configv1alpha1.RequestLimitsSpec{MaxRequestBytes: 1024},
+			annotations: map[string]string{
+				constants.MaxRequestBytesAnnotation: "1MB",
+			},
+			expectedConfig: configv1alpha1.RequestLimitsSpec{MaxRequestBytes: 1024},
+		},
+		{
+			name:           "maximum request headers size larger than the limit is ignored",
+			meshConfig:     configv1alpha1.RequestLimitsSpec{MaxRequestHeadersKb: 10000},
+			expectedConfig: configv1alpha1.RequestLimitsSpec{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+			mockKubeController := k8s.NewMockController(mockCtrl)
+
+			lb := &listenerBuilder{
+				meshCatalog: mockCatalog,
+				cfg:         mockConfigurator,
+			}
+
+			mockConfigurator.EXPECT().GetRequestLimitsConfig().Return(tc.meshConfig)
+			mockConfigurator.EXPECT().GetClientCertDetailsConfig().Return(configv1alpha1.ClientCertDetailsSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetRBACAuditConfig().Return(configv1alpha1.RBACAuditSpec{}).AnyTimes()
+			mockCatalog.EXPECT().GetKubeController().Return(mockKubeController)
+			mockKubeController.EXPECT().GetService(tests.BookstoreV1Service).Return(&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        tests.BookstoreV1Service.Name,
+					Namespace:   tests.BookstoreV1Service.Namespace,
+					Annotations: tc.annotations,
+				},
+			})
+
+			assert.Equal(tc.expectedConfig, lb.getRequestLimitsConfig(tests.BookstoreV1Service))
+		})
+	}
+}
+
+func TestGetBufferFilter(t *testing.T) {
+	assert := tassert.New(t)
+
+	filter, err := getBufferFilter(1024)
+	assert.Nil(err)
+	assert.Equal(wellknown.Buffer, filter.Name)
+
+	buffer := &xds_buffer.Buffer{}
+	assert.Nil(ptypes.UnmarshalAny(filter.GetTypedConfig(), buffer))
+	assert.Equal(uint32(1024), buffer.MaxRequestBytes.GetValue())
+}
@@ -1,6 +1,7 @@
 package lds
 
 import (
+	mapset "github.com/deckarep/golang-set"
 	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
 
@@ -8,9 +9,11 @@ import (
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/rds/route"
 	"github.com/openservicemesh/osm/pkg/envoy/registry"
 	"github.com/openservicemesh/osm/pkg/errcode"
 	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s"
 )
 
 // NewResponse creates a new Listener Discovery Response.
@@ -18,10 +21,12 @@ import (
 // 1. Inbound listener to handle incoming traffic
 // 2. Outbound listener to handle outgoing traffic
 // 3. Prometheus listener for metrics
-func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_discovery.DiscoveryRequest, cfg configurator.Configurator, _ certificate.Manager, proxyRegistry *registry.ProxyRegistry) ([]types.Resource, error) {
+// When HTTP/3 ingress is enabled, a QUIC listener is also built per HTTPS ingress port.
+// When the DNS proxy is enabled, a DNS listener answering the DNS queries of the pod is also built.
+func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_discovery.DiscoveryRequest, cfg configurator.Configurator, certManager certificate.Manager, proxyRegistry *registry.ProxyRegistry) ([]types.Resource, error) {
 	proxyIdentity, err := envoy.GetServiceIdentityFromProxyCertificate(proxy.GetCertificateCommonName())
 	if err != nil {
-		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGettingServiceIdentity)).
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetricForProxy(errcode.ErrGettingServiceIdentity, proxy.GetCertificateCommonName())).
 			Msgf("Error retrieving ServiceAccount for proxy %s", proxy.String())
 		return nil, err
 	}
@@ -34,9 +39,14 @@ func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_d
 	}
 
 	lb := newListenerBuilder(meshCatalog, proxyIdentity, cfg, statsHeaders)
+	lb.scopedRoutes = route.IsScopedRoutesEnabled(cfg)
 
 	if proxy.Kind() == envoy.KindGateway && cfg.GetFeatureFlags().EnableMulticlusterMode {
-		gatewayListener, err := lb.buildMulticlusterGatewayListener()
+		var trustDomains []string
+		if store, ok := certManager.(certificate.TrustBundleStore); ok {
+			trustDomains = store.ListTrustDomains()
+		}
+		gatewayListener, err := lb.buildMulticlusterGatewayListener(trustDomains)
 
 		if err != nil {
 			log.Error().Err(err).Msgf("Error building gateway listener for proxy %s", proxy.String())
@@ -60,23 +70,50 @@ func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_d
 		}
 	}
 
+	// The proxy's pod determines the metrics and source IP preservation settings of its listeners
+	pod, err := envoy.GetPodFromCertificate(proxy.GetCertificateCommonName(), meshCatalog.GetKubeController())
+	if err != nil {
+		log.Warn().Msgf("Could not find pod for connecting proxy %s. No metadata was recorded.", proxy.GetCertificateSerialNumber())
+	}
+
 	// --- INBOUND -------------------
 	inboundListener := newInboundListener()
 
 	svcList, err := proxyRegistry.ListProxyServices(proxy)
 	if err != nil {
-		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrFetchingServiceList)).Msgf("Error looking up MeshService for proxy %s", proxy.String())
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetricForProxy(errcode.ErrFetchingServiceList, proxy.GetCertificateCommonName())).Msgf("Error looking up MeshService for proxy %s", proxy.String())
 		return nil, err
 	}
 	// Create inbound filter chains per service behind proxy
+	proxyProtocolPortSet := mapset.NewSet()
+	var proxyProtocolPorts []int
 	for _, proxyService := range svcList {
 		// Add in-mesh filter chains
 		inboundSvcFilterChains := lb.getInboundMeshFilterChains(proxyService)
 		inboundListener.FilterChains = append(inboundListener.FilterChains, inboundSvcFilterChains...)
 
 		// Add ingress filter chains
-		ingressFilterChains := lb.getIngressFilterChains(proxyService)
+		ingressFilterChains, ingressProxyProtocolPorts := lb.getIngressFilterChains(proxyService)
 		inboundListener.FilterChains = append(inboundListener.FilterChains, ingressFilterChains...)
+		for _, port := range ingressProxyProtocolPorts {
+			if proxyProtocolPortSet.Add(port) {
+				proxyProtocolPorts = append(proxyProtocolPorts, port)
+			}
+		}
+	}
+
+	sortFilterChains(inboundListener.FilterChains)
+	setProxyProtocolListenerFilters(inboundListener, proxyProtocolPorts)
+
+	// Inbound traffic is proxied from the source IP of the clients when the proxy's pod was injected accordingly,
+	// once the source IP is known, possibly from a PROXY protocol header
+	if pod != nil && k8s.PreservesInboundSourceIP(pod) {
+		if originalSourceFilter, err := newOriginalSourceListenerFilter(); err != nil {
+			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingXDSResource)).
+				Msgf("Error building original source listener filter for proxy %s", proxy.String())
+		} else {
+			inboundListener.ListenerFilters = append(inboundListener.ListenerFilters, originalSourceFilter)
+		}
 	}
 
 	if len(inboundListener.FilterChains) > 0 {
@@ -85,12 +122,30 @@ func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_d
 		ldsResources = append(ldsResources, inboundListener)
 	}
 
-	if pod, err := envoy.GetPodFromCertificate(proxy.GetCertificateCommonName(), meshCatalog.GetKubeController()); err != nil {
-		log.Warn().Msgf("Could not find pod for connecting proxy %s. No metadata was recorded.", proxy.GetCertificateSerialNumber())
-	} else if meshCatalog.GetKubeController().IsMetricsEnabled(pod) {
+	// --- INGRESS HTTP/3 -------------------
+	if cfg.GetFeatureFlags().EnableIngressHTTP3 {
+		for _, quicListener := range lb.getIngressQUICListeners(svcList) {
+			ldsResources = append(ldsResources, quicListener)
+		}
+	}
+
+	// --- DNS -------------------
+	if cfg.GetFeatureFlags().EnableDNSProxy {
+		if dnsListener, err := lb.buildDNSListener(); err != nil {
+			log.Error().Err(err).Msgf("Error building DNS listener for proxy %s", proxy.String())
+		} else {
+			ldsResources = append(ldsResources, dnsListener)
+		}
+	}
+
+	if pod != nil && meshCatalog.GetKubeController().IsMetricsEnabled(pod) {
 		// Build Prometheus listener config
 		prometheusConnManager := getPrometheusConnectionManager()
-		if prometheusListener, err := buildPrometheusListener(prometheusConnManager); err != nil {
+		prometheusListener, err := buildPrometheusListener(prometheusConnManager)
+		if scraping := cfg.GetPrometheusScrapingConfig(); err == nil && scraping.EnableMTLS {
+			err = lb.securePrometheusListener(prometheusListener, scraping.ClientIdentity)
+		}
+		if err != nil {
 			log.Error().Err(err).Msgf("Error building Prometheus listener for proxy %s", proxy.String())
 		} else {
 			ldsResources = append(ldsResources, prometheusListener)
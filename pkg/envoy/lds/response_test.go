@@ -73,9 +73,19 @@ func TestNewResponse(t *testing.T) {
 	meshCatalog := catalog.NewFakeMeshCatalog(kubeClient, configClient)
 
 	mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().IsProtocolDetectionEnabled(gomock.Any()).Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetCompressionConfig().Return(v1alpha1.CompressionSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().GetRequestLimitsConfig().Return(v1alpha1.RequestLimitsSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().GetClientCertDetailsConfig().Return(v1alpha1.ClientCertDetailsSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().GetRBACAuditConfig().Return(v1alpha1.RBACAuditSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().GetSPIFFETrustDomain().Return("").AnyTimes()
 	mockConfigurator.EXPECT().IsTracingEnabled().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetHTTPSanitizationConfig().Return(v1alpha1.HTTPSanitizationSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().GetDownstreamLimitsConfig().Return(v1alpha1.DownstreamLimitsSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().GetPrometheusScrapingConfig().Return(v1alpha1.PrometheusScrapingSpec{}).AnyTimes()
 	mockConfigurator.EXPECT().GetTracingEndpoint().Return("some-endpoint").AnyTimes()
 	mockConfigurator.EXPECT().IsEgressEnabled().Return(true).AnyTimes()
+	mockConfigurator.EXPECT().GetOutboundPassthroughConfig().Return(v1alpha1.OutboundPassthroughSpec{}).AnyTimes()
 	mockConfigurator.EXPECT().GetInboundExternalAuthConfig().Return(auth.ExtAuthConfig{
 		Enable: false,
 	}).AnyTimes()
@@ -148,6 +158,7 @@ func TestNewResponse(t *testing.T) {
 	assert.Equal(listener.TrafficDirection, xds_core.TrafficDirection_INBOUND)
 	assert.NotNil(listener.FilterChains)
 	assert.Len(listener.FilterChains, 1)
+	assert.Nil(listener.FilterChains[0].TransportSocket)
 }
 
 func TestNewResponseForMulticlusterGateway(t *testing.T) {
@@ -0,0 +1,64 @@
+package lds
+
+import (
+	xds_on_demand "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/on_demand/v3"
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/rds/route"
+)
+
+const (
+	// onDemandFilterName is the name of the HTTP filter fetching the route configurations of scoped routes on demand
+	onDemandFilterName = "envoy.filters.http.on_demand"
+
+	// scopeKeyHeader is the header whose value is the key of the scope of a request, its host
+	scopeKeyHeader = ":authority"
+)
+
+// getScopedRoutes returns the route specifier of an HTTP connection manager selecting the outbound route configuration
+// of a request by its host, among the outbound scoped route configurations discovered through SRDS. The route
+// configurations are discovered through RDS.
+func getScopedRoutes() *xds_hcm.HttpConnectionManager_ScopedRoutes {
+	return &xds_hcm.HttpConnectionManager_ScopedRoutes{
+		ScopedRoutes: &xds_hcm.ScopedRoutes{
+			Name: route.OutboundScopedRoutesName,
+			ScopeKeyBuilder: &xds_hcm.ScopedRoutes_ScopeKeyBuilder{
+				Fragments: []*xds_hcm.ScopedRoutes_ScopeKeyBuilder_FragmentBuilder{
+					{
+						Type: &xds_hcm.ScopedRoutes_ScopeKeyBuilder_FragmentBuilder_HeaderValueExtractor_{
+							HeaderValueExtractor: &xds_hcm.ScopedRoutes_ScopeKeyBuilder_FragmentBuilder_HeaderValueExtractor{
+								Name: scopeKeyHeader,
+								// Without an element separator, the whole value of the header is the key
+								ExtractType: &xds_hcm.ScopedRoutes_ScopeKeyBuilder_FragmentBuilder_HeaderValueExtractor_Index{Index: 0},
+							},
+						},
+					},
+				},
+			},
+			RdsConfigSource: envoy.GetADSConfigSource(),
+			ConfigSpecifier: &xds_hcm.ScopedRoutes_ScopedRds{
+				ScopedRds: &xds_hcm.ScopedRds{
+					ScopedRdsConfigSource: envoy.GetADSConfigSource(),
+				},
+			},
+		},
+	}
+}
+
+// getOnDemandFilter returns the HTTP filter fetching the route configurations of scoped routes on demand
+func getOnDemandFilter() (*xds_hcm.HttpFilter, error) {
+	onDemandAny, err := ptypes.MarshalAny(&xds_on_demand.OnDemand{})
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshaling on-demand filter")
+	}
+
+	return &xds_hcm.HttpFilter{
+		Name: onDemandFilterName,
+		ConfigType: &xds_hcm.HttpFilter_TypedConfig{
+			TypedConfig: onDemandAny,
+		},
+	}, nil
+}
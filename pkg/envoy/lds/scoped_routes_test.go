@@ -0,0 +1,40 @@
+package lds
+
+import (
+	"testing"
+
+	xds_hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/envoy/rds/route"
+)
+
+func TestGetScopedRoutes(t *testing.T) {
+	assert := tassert.New(t)
+
+	scopedRoutes := getScopedRoutes().ScopedRoutes
+	assert.Nil(scopedRoutes.Validate())
+	assert.Equal(route.OutboundScopedRoutesName, scopedRoutes.Name)
+	assert.NotNil(scopedRoutes.RdsConfigSource.GetAds())
+	assert.NotNil(scopedRoutes.GetScopedRds().ScopedRdsConfigSource.GetAds())
+
+	assert.Len(scopedRoutes.ScopeKeyBuilder.Fragments, 1)
+	extractor := scopedRoutes.ScopeKeyBuilder.Fragments[0].GetHeaderValueExtractor()
+	assert.Equal(":authority", extractor.Name)
+	assert.Empty(extractor.ElementSeparator)
+	assert.Equal(uint32(0), extractor.GetIndex())
+}
+
+func TestGetOnDemandFilter(t *testing.T) {
+	assert := tassert.New(t)
+
+	filter, err := getOnDemandFilter()
+	assert.Nil(err)
+	assert.Equal(onDemandFilterName, filter.Name)
+	assert.NotNil(filter.GetTypedConfig())
+
+	connManager, err := httpConnManagerOptions{direction: outbound, scopedRoutes: true}.build()
+	assert.Nil(err)
+	assert.Nil(connManager.Validate())
+	assert.IsType(&xds_hcm.HttpConnectionManager_ScopedRoutes{}, connManager.RouteSpecifier)
+}
@@ -19,4 +19,7 @@ type listenerBuilder struct {
 	meshCatalog     catalog.MeshCataloger
 	cfg             configurator.Configurator
 	statsHeaders    map[string]string
+
+	// scopedRoutes is whether the outbound HTTP routes are scoped by host
+	scopedRoutes bool
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"sync"
 	"time"
 
 	mapset "github.com/deckarep/golang-set"
@@ -33,6 +34,19 @@ type Proxy struct {
 	lastAppliedVersion map[TypeURI]uint64
 	lastNonce          map[TypeURI]string
 
+	// The time since which the proxy has not acknowledged the last version sent for a given TypeURI
+	unacknowledgedSince map[TypeURI]time.Time
+
+	// The hash of the content of the resources last sent for a given TypeURI
+	lastSentResourcesHash map[TypeURI]uint64
+
+	// The version of Envoy reported in the node of the first discovery request of the proxy
+	envoyVersion string
+
+	// inventoryLock protects the fields of the proxy read outside of its xDS stream: envoyVersion, lastSentVersion,
+	// lastAppliedVersion and unacknowledgedSince
+	inventoryLock sync.RWMutex
+
 	// Contains the last resource names sent for a given proxy and TypeURL
 	lastxDSResourcesSent map[TypeURI]mapset.Set
 
@@ -122,30 +136,101 @@ func (p *Proxy) StatsHeaders() map[string]string {
 
 // SetLastAppliedVersion records the version of the given Envoy proxy that was last acknowledged.
 func (p *Proxy) SetLastAppliedVersion(typeURI TypeURI, version uint64) {
+	p.inventoryLock.Lock()
+	defer p.inventoryLock.Unlock()
 	p.lastAppliedVersion[typeURI] = version
+	if version >= p.lastSentVersion[typeURI] {
+		delete(p.unacknowledgedSince, typeURI)
+	}
 }
 
 // GetLastAppliedVersion returns the last version successfully applied to the given Envoy proxy.
 func (p *Proxy) GetLastAppliedVersion(typeURI TypeURI) uint64 {
+	p.inventoryLock.RLock()
+	defer p.inventoryLock.RUnlock()
 	return p.lastAppliedVersion[typeURI]
 }
 
+// GetLastAppliedVersions returns a copy of the versions of each type last acknowledged by the Envoy proxy.
+func (p *Proxy) GetLastAppliedVersions() map[TypeURI]uint64 {
+	p.inventoryLock.RLock()
+	defer p.inventoryLock.RUnlock()
+	versions := make(map[TypeURI]uint64, len(p.lastAppliedVersion))
+	for typeURI, version := range p.lastAppliedVersion {
+		versions[typeURI] = version
+	}
+	return versions
+}
+
+// SetEnvoyVersion records the version of Envoy the proxy is running.
+func (p *Proxy) SetEnvoyVersion(version string) {
+	p.inventoryLock.Lock()
+	defer p.inventoryLock.Unlock()
+	p.envoyVersion = version
+}
+
+// GetEnvoyVersion returns the version of Envoy the proxy is running, empty if not reported yet.
+func (p *Proxy) GetEnvoyVersion() string {
+	p.inventoryLock.RLock()
+	defer p.inventoryLock.RUnlock()
+	return p.envoyVersion
+}
+
 // GetLastSentVersion returns the last sent version.
 func (p *Proxy) GetLastSentVersion(typeURI TypeURI) uint64 {
+	p.inventoryLock.RLock()
+	defer p.inventoryLock.RUnlock()
 	return p.lastSentVersion[typeURI]
 }
 
 // IncrementLastSentVersion increments last sent version.
 func (p *Proxy) IncrementLastSentVersion(typeURI TypeURI) uint64 {
+	p.inventoryLock.Lock()
+	defer p.inventoryLock.Unlock()
 	p.lastSentVersion[typeURI]++
-	return p.GetLastSentVersion(typeURI)
+	if _, ok := p.unacknowledgedSince[typeURI]; !ok {
+		p.unacknowledgedSince[typeURI] = time.Now()
+	}
+	return p.lastSentVersion[typeURI]
 }
 
 // SetLastSentVersion records the version of the given config last sent to the proxy.
 func (p *Proxy) SetLastSentVersion(typeURI TypeURI, ver uint64) {
+	p.inventoryLock.Lock()
+	defer p.inventoryLock.Unlock()
 	p.lastSentVersion[typeURI] = ver
 }
 
+// GetUnacknowledgedTypes returns the TypeURIs whose last version sent was not yet acknowledged by the proxy, with the
+// time since which the proxy has not acknowledged the versions sent for them. A proxy keeps lagging behind across
+// successive versions sent until it acknowledges the last one.
+func (p *Proxy) GetUnacknowledgedTypes() map[TypeURI]time.Time {
+	p.inventoryLock.RLock()
+	defer p.inventoryLock.RUnlock()
+	unacknowledged := make(map[TypeURI]time.Time, len(p.unacknowledgedSince))
+	for typeURI, since := range p.unacknowledgedSince {
+		unacknowledged[typeURI] = since
+	}
+	return unacknowledged
+}
+
+// GetLastSentResourcesHash returns the hash of the resources last sent for the given TypeURI, and whether any were sent.
+func (p *Proxy) GetLastSentResourcesHash(typeURI TypeURI) (uint64, bool) {
+	hash, ok := p.lastSentResourcesHash[typeURI]
+	return hash, ok
+}
+
+// SetLastSentResourcesHash records the hash of the resources last sent for the given TypeURI.
+func (p *Proxy) SetLastSentResourcesHash(typeURI TypeURI, hash uint64) {
+	p.lastSentResourcesHash[typeURI] = hash
+}
+
+// DeleteLastSentResourcesHash forgets the hash of the resources last sent for the given TypeURI, when they couldn't be
+// hashed.
+func (p *Proxy) DeleteLastSentResourcesHash(typeURI TypeURI) {
+	delete(p.lastSentResourcesHash, typeURI)
+}
+
 // GetLastSentNonce returns last sent nonce.
 func (p *Proxy) GetLastSentNonce(typeURI TypeURI) string {
 	nonce, ok := p.lastNonce[typeURI]
@@ -252,11 +337,13 @@ func NewProxy(certCommonName certificate.CommonName, certSerialNumber certificat
 		connectedAt: time.Now(),
 		hash:        hash,
 
-		lastNonce:            make(map[TypeURI]string),
-		lastSentVersion:      make(map[TypeURI]uint64),
-		lastAppliedVersion:   make(map[TypeURI]uint64),
-		lastxDSResourcesSent: make(map[TypeURI]mapset.Set),
-		subscribedResources:  make(map[TypeURI]mapset.Set),
+		lastNonce:             make(map[TypeURI]string),
+		lastSentVersion:       make(map[TypeURI]uint64),
+		lastSentResourcesHash: make(map[TypeURI]uint64),
+		lastAppliedVersion:    make(map[TypeURI]uint64),
+		unacknowledgedSince:   make(map[TypeURI]time.Time),
+		lastxDSResourcesSent:  make(map[TypeURI]mapset.Set),
+		subscribedResources:   make(map[TypeURI]mapset.Set),
 
 		kind: cnMeta.ProxyKind,
 	}, nil
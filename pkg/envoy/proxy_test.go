@@ -146,12 +146,12 @@ func TestStatsHeaders(t *testing.T) {
 	const unknown = "unknown"
 	tests := []struct {
 		name     string
-		proxy    Proxy
+		proxy    *Proxy
 		expected map[string]string
 	}{
 		{
 			name: "nil metadata",
-			proxy: Proxy{
+			proxy: &Proxy{
 				PodMetadata: nil,
 			},
 			expected: map[string]string{
@@ -163,7 +163,7 @@ func TestStatsHeaders(t *testing.T) {
 		},
 		{
 			name: "empty metadata",
-			proxy: Proxy{
+			proxy: &Proxy{
 				PodMetadata: &PodMetadata{},
 			},
 			expected: map[string]string{
@@ -175,7 +175,7 @@ func TestStatsHeaders(t *testing.T) {
 		},
 		{
 			name: "full metadata",
-			proxy: Proxy{
+			proxy: &Proxy{
 				PodMetadata: &PodMetadata{
 					Name:         "pod",
 					Namespace:    "ns",
@@ -192,7 +192,7 @@ func TestStatsHeaders(t *testing.T) {
 		},
 		{
 			name: "replicaset with expected name format",
-			proxy: Proxy{
+			proxy: &Proxy{
 				PodMetadata: &PodMetadata{
 					WorkloadKind: "ReplicaSet",
 					WorkloadName: "some-name-randomchars",
@@ -207,7 +207,7 @@ func TestStatsHeaders(t *testing.T) {
 		},
 		{
 			name: "replicaset without expected name format",
-			proxy: Proxy{
+			proxy: &Proxy{
 				PodMetadata: &PodMetadata{
 					WorkloadKind: "ReplicaSet",
 					WorkloadName: "name",
@@ -499,3 +499,23 @@ func TestSubscribedResources(t *testing.T) {
 	assert.True(res.Contains("B"))
 	assert.True(res.Contains("C"))
 }
+
+func TestGetUnacknowledgedTypes(t *testing.T) {
+	assert := tassert.New(t)
+
+	certCommonName := certificate.CommonName(fmt.Sprintf("%s.%s.%s.%s", uuid.New(), KindSidecar, tests.BookbuyerServiceAccountName, tests.Namespace))
+	proxy, err := NewProxy(certCommonName, "-certificate-serial-number-", nil)
+	assert.Nil(err)
+	assert.Empty(proxy.GetUnacknowledgedTypes())
+
+	// The proxy lags behind since the first version it did not acknowledge
+	proxy.IncrementLastSentVersion(TypeCDS)
+	since := proxy.GetUnacknowledgedTypes()[TypeCDS]
+	assert.False(since.IsZero())
+	proxy.IncrementLastSentVersion(TypeCDS)
+	proxy.SetLastAppliedVersion(TypeCDS, 1)
+	assert.Equal(map[TypeURI]time.Time{TypeCDS: since}, proxy.GetUnacknowledgedTypes())
+
+	proxy.SetLastAppliedVersion(TypeCDS, 2)
+	assert.Empty(proxy.GetUnacknowledgedTypes())
+}
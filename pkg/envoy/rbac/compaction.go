@@ -0,0 +1,108 @@
+package rbac
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+)
+
+// NewPrincipalCompactor returns a PrincipalCompactor of principals, which authenticates the downstreams by their
+// SPIFFE ID in the given trust domain if set
+func NewPrincipalCompactor(spiffeTrustDomain string) *PrincipalCompactor {
+	return &PrincipalCompactor{
+		spiffeTrustDomain: spiffeTrustDomain,
+		cache:             make(map[string][]RulesList),
+	}
+}
+
+// GetPrincipalRules returns the principal rules allowing the given downstream identities, with OR semantics between
+// the rules. The duplicate identities are allowed once, a wildcard identity allows any downstream, and a
+// namespace-scoped wildcard identity allows any downstream of its namespace.
+//
+// The identities of a namespace are only compacted into a namespace wildcard when a namespace-scoped wildcard
+// identity allows the namespace. Allowing each service account of a namespace known to the controller does not
+// compact them: the controller may not watch all the service accounts of the namespace, such as when the watches are
+// scoped by a label selector, nor have observed a service account just created, which the wildcard would allow
+// although no policy does. The SPIFFE IDs are only matched by the wildcard within the trust domain.
+func (c *PrincipalCompactor) GetPrincipalRules(downstreams []identity.ServiceIdentity) []RulesList {
+	allowed := make(map[identity.ServiceIdentity]struct{})
+	for _, downstream := range downstreams {
+		if downstream.IsWildcard() {
+			// An empty principal rules list allows any downstream
+			return []RulesList{{}}
+		}
+		allowed[downstream] = struct{}{}
+	}
+
+	sortedDownstreams := make([]string, 0, len(allowed))
+	for downstream := range allowed {
+		sortedDownstreams = append(sortedDownstreams, downstream.String())
+	}
+	sort.Strings(sortedDownstreams)
+
+	cacheKey := strings.Join(sortedDownstreams, ",")
+	if rules, ok := c.cache[cacheKey]; ok {
+		return rules
+	}
+
+	wildcardNamespaces := getWildcardNamespaces(allowed)
+
+	var rules []RulesList
+	uncompactedRuleCount := 0
+	for _, downstream := range sortedDownstreams {
+		downstreamIdentity := identity.ServiceIdentity(downstream)
+		principalRules := GetPrincipalRules(downstreamIdentity, c.spiffeTrustDomain)
+		uncompactedRuleCount += len(principalRules)
+
+		if namespace, ok := getIdentityNamespace(downstreamIdentity); ok {
+			if _, isWildcard := wildcardNamespaces[namespace]; isWildcard {
+				continue
+			}
+		}
+		rules = append(rules, RulesList{OrRules: principalRules})
+	}
+
+	sortedWildcardNamespaces := make([]string, 0, len(wildcardNamespaces))
+	for namespace := range wildcardNamespaces {
+		sortedWildcardNamespaces = append(sortedWildcardNamespaces, namespace)
+	}
+	sort.Strings(sortedWildcardNamespaces)
+
+	for _, namespace := range sortedWildcardNamespaces {
+		rules = append(rules, RulesList{OrRules: getNamespacePrincipalRules(namespace, c.spiffeTrustDomain)})
+	}
+
+	compactedRuleCount := 0
+	for _, rulesList := range rules {
+		compactedRuleCount += len(rulesList.OrRules)
+	}
+	if compactedRuleCount < uncompactedRuleCount {
+		metricsstore.DefaultMetricsStore.RBACCompactedPrincipalCount.Add(float64(uncompactedRuleCount - compactedRuleCount))
+	}
+
+	c.cache[cacheKey] = rules
+	return rules
+}
+
+// getWildcardNamespaces returns the namespaces allowed by a namespace-scoped wildcard identity
+func getWildcardNamespaces(allowed map[identity.ServiceIdentity]struct{}) map[string]struct{} {
+	wildcardNamespaces := make(map[string]struct{})
+	for svcIdentity := range allowed {
+		if namespace, ok := svcIdentity.GetWildcardNamespace(); ok {
+			wildcardNamespaces[namespace] = struct{}{}
+		}
+	}
+	return wildcardNamespaces
+}
+
+// getIdentityNamespace returns the namespace of the given identity, and whether the identity is in the format
+// <ServiceAccount>.<Namespace>.cluster.local
+func getIdentityNamespace(svcIdentity identity.ServiceIdentity) (string, bool) {
+	chunks := strings.SplitN(svcIdentity.String(), ".", 3)
+	if len(chunks) != 3 || chunks[0] == "" || chunks[1] == "" || chunks[2] != identity.ClusterLocalTrustDomain {
+		return "", false
+	}
+	return chunks[1], true
+}
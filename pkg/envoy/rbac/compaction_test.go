@@ -0,0 +1,90 @@
+package rbac
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/identity"
+)
+
+func TestPrincipalCompactorGetPrincipalRules(t *testing.T) {
+	testCases := []struct {
+		name              string
+		spiffeTrustDomain string
+		downstreams       []identity.ServiceIdentity
+		expectedRules     []RulesList
+	}{
+		{
+			name:          "wildcard downstream allows any downstream",
+			downstreams:   []identity.ServiceIdentity{"sa-1.ns-1.cluster.local", identity.WildcardServiceIdentity},
+			expectedRules: []RulesList{{}},
+		},
+		{
+			name:        "duplicate downstreams are allowed once",
+			downstreams: []identity.ServiceIdentity{"sa-2.ns-2.cluster.local", "sa-1.ns-1.cluster.local", "sa-2.ns-2.cluster.local"},
+			expectedRules: []RulesList{
+				{OrRules: []Rule{{Attribute: DownstreamAuthPrincipal, Value: "sa-1.ns-1.cluster.local"}}},
+				{OrRules: []Rule{{Attribute: DownstreamAuthPrincipal, Value: "sa-2.ns-2.cluster.local"}}},
+			},
+		},
+		{
+			name:              "namespace wildcard matches the SPIFFE IDs within the trust domain",
+			spiffeTrustDomain: "cluster.local",
+			downstreams:       []identity.ServiceIdentity{"sa-1.ns-1.cluster.local", "*.ns-1.cluster.local", "sa-1.ns-2.cluster.local"},
+			expectedRules: []RulesList{
+				{OrRules: []Rule{
+					{Attribute: DownstreamAuthPrincipal, Value: "sa-1.ns-2.cluster.local"},
+					{Attribute: DownstreamAuthPrincipal, Value: "spiffe://cluster.local/ns/ns-2/sa/sa-1"},
+				}},
+				{OrRules: []Rule{
+					{Attribute: DownstreamAuthPrincipalSuffix, Value: ".ns-1.cluster.local"},
+					{Attribute: DownstreamAuthPrincipalPrefix, Value: "spiffe://cluster.local/ns/ns-1/sa/"},
+				}},
+			},
+		},
+		{
+			name:        "namespace wildcards are compacted separately",
+			downstreams: []identity.ServiceIdentity{"sa-1.ns-1.cluster.local", "*.ns-1.cluster.local", "*.ns-2.cluster.local", "sa-2.ns-2.cluster.local"},
+			expectedRules: []RulesList{
+				{OrRules: []Rule{{Attribute: DownstreamAuthPrincipalSuffix, Value: ".ns-1.cluster.local"}}},
+				{OrRules: []Rule{{Attribute: DownstreamAuthPrincipalSuffix, Value: ".ns-2.cluster.local"}}},
+			},
+		},
+		{
+			name:              "namespace-scoped wildcard downstream allows the downstreams of its namespace",
+			spiffeTrustDomain: "cluster.local",
+			downstreams:       []identity.ServiceIdentity{"sa-1.ns-3.cluster.local", "*.ns-3.cluster.local"},
+			expectedRules: []RulesList{
+				{OrRules: []Rule{
+					{Attribute: DownstreamAuthPrincipalSuffix, Value: ".ns-3.cluster.local"},
+					{Attribute: DownstreamAuthPrincipalPrefix, Value: "spiffe://cluster.local/ns/ns-3/sa/"},
+				}},
+			},
+		},
+		{
+			name:        "downstreams of a namespace without a namespace wildcard are not compacted",
+			downstreams: []identity.ServiceIdentity{"sa-1.ns-3.cluster.local", "sa-2.ns-3.cluster.local"},
+			expectedRules: []RulesList{
+				{OrRules: []Rule{{Attribute: DownstreamAuthPrincipal, Value: "sa-1.ns-3.cluster.local"}}},
+				{OrRules: []Rule{{Attribute: DownstreamAuthPrincipal, Value: "sa-2.ns-3.cluster.local"}}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			compactor := NewPrincipalCompactor(tc.spiffeTrustDomain)
+			assert.Equal(tc.expectedRules, compactor.GetPrincipalRules(tc.downstreams))
+
+			// The rules of the same set of downstreams are returned from the cache
+			reversed := make([]identity.ServiceIdentity, 0, len(tc.downstreams))
+			for i := len(tc.downstreams) - 1; i >= 0; i-- {
+				reversed = append(reversed, tc.downstreams[i])
+			}
+			assert.Equal(tc.expectedRules, compactor.GetPrincipalRules(reversed))
+		})
+	}
+}
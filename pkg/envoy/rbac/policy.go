@@ -1,11 +1,18 @@
 package rbac
 
 import (
+	"fmt"
+	"net"
 	"strconv"
 
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_rbac "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
 	xds_matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
 	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
 )
 
 // Generate constructs an RBAC policy for the policy object on which this method is called
@@ -15,8 +22,18 @@ func (p *Policy) Generate() (*xds_rbac.Policy, error) {
 	// Construct the Principals ------------------------
 	var finalPrincipals []*xds_rbac.Principal
 
-	// Each RuleList follows OR semantics with other RuleList in the list of RuleList
+	// Each RuleList follows OR semantics with other RuleList in the list of RuleList, so the duplicate RuleLists
+	// are only added once
+	principalRuleLists := make(map[string]struct{})
+	principalRuleCount := 0
 	for _, principalRuleList := range p.Principals {
+		principalRuleListKey := fmt.Sprintf("%v", principalRuleList)
+		if _, ok := principalRuleLists[principalRuleListKey]; ok {
+			continue
+		}
+		principalRuleLists[principalRuleListKey] = struct{}{}
+		principalRuleCount += len(principalRuleList.AndRules) + len(principalRuleList.OrRules)
+
 		// 'principalRuleList' corresponds to a single Principal in an RBAC policy.
 		// This Principal can be defined in terms of one of AND or OR rules.
 		// When AND/OR semantics are not required to define multiple rules corresponding
@@ -34,8 +51,7 @@ func (p *Policy) Generate() (*xds_rbac.Policy, error) {
 			var andPrincipalRules []*xds_rbac.Principal
 			for _, andPrincipalRule := range principalRuleList.AndRules {
 				// Fill in the authenticated principal types
-				if andPrincipalRule.Attribute == DownstreamAuthPrincipal {
-					authPrincipal := GetAuthenticatedPrincipal(andPrincipalRule.Value)
+				if authPrincipal := getRulePrincipal(andPrincipalRule); authPrincipal != nil {
 					andPrincipalRules = append(andPrincipalRules, authPrincipal)
 				}
 			}
@@ -46,8 +62,7 @@ func (p *Policy) Generate() (*xds_rbac.Policy, error) {
 			var orPrincipalRules []*xds_rbac.Principal
 			for _, orPrincipalRule := range principalRuleList.OrRules {
 				// Fill in the authenticated principal types
-				if orPrincipalRule.Attribute == DownstreamAuthPrincipal {
-					authPrincipal := GetAuthenticatedPrincipal(orPrincipalRule.Value)
+				if authPrincipal := getRulePrincipal(orPrincipalRule); authPrincipal != nil {
 					orPrincipalRules = append(orPrincipalRules, authPrincipal)
 				}
 			}
@@ -66,6 +81,7 @@ func (p *Policy) Generate() (*xds_rbac.Policy, error) {
 	}
 
 	policy.Principals = finalPrincipals
+	metricsstore.DefaultMetricsStore.RBACPolicyPrincipalCount.Observe(float64(principalRuleCount))
 
 	// Construct the Permissions ---------------------------
 	var finalPermissions []*xds_rbac.Permission
@@ -88,14 +104,12 @@ func (p *Policy) Generate() (*xds_rbac.Policy, error) {
 			// Combine all the AND rules for this Permission rule with AND semantics
 			var andPermissionRules []*xds_rbac.Permission
 			for _, andPermissionRule := range permissionRuleList.AndRules {
-				// Fill in the destination port permission
-				if andPermissionRule.Attribute == DestinationPort {
-					port, err := strconv.ParseUint(andPermissionRule.Value, 10, 32)
-					if err != nil {
-						return nil, errors.Errorf("Error parsing destination port value %s", andPermissionRule.Value)
-					}
-					portPermission := GetDestinationPortPermission(uint32(port))
-					andPermissionRules = append(andPermissionRules, portPermission)
+				permission, err := getRulePermission(andPermissionRule)
+				if err != nil {
+					return nil, err
+				}
+				if permission != nil {
+					andPermissionRules = append(andPermissionRules, permission)
 				}
 			}
 			currentPermission = andPermissions(andPermissionRules)
@@ -104,14 +118,12 @@ func (p *Policy) Generate() (*xds_rbac.Policy, error) {
 			// Combine all the OR rules for this Permission rule with OR semantics
 			var orPermissionRules []*xds_rbac.Permission
 			for _, orPermissionRule := range permissionRuleList.OrRules {
-				// Fill in the destination port permission
-				if orPermissionRule.Attribute == DestinationPort {
-					port, err := strconv.ParseUint(orPermissionRule.Value, 10, 32)
-					if err != nil {
-						return nil, errors.Errorf("Error parsing destination port value %s", orPermissionRule.Value)
-					}
-					portPermission := GetDestinationPortPermission(uint32(port))
-					orPermissionRules = append(orPermissionRules, portPermission)
+				permission, err := getRulePermission(orPermissionRule)
+				if err != nil {
+					return nil, err
+				}
+				if permission != nil {
+					orPermissionRules = append(orPermissionRules, permission)
 				}
 			}
 			currentPermission = orPermissions(orPermissionRules)
@@ -133,16 +145,97 @@ func (p *Policy) Generate() (*xds_rbac.Policy, error) {
 	return policy, nil
 }
 
+// getRulePermission returns the RBAC permission for the given permission rule, or nil if the
+// attribute of the rule is not a supported permission attribute
+func getRulePermission(rule Rule) (*xds_rbac.Permission, error) {
+	switch rule.Attribute {
+	case DestinationPort:
+		port, err := strconv.ParseUint(rule.Value, 10, 32)
+		if err != nil {
+			return nil, errors.Errorf("Error parsing destination port value %s", rule.Value)
+		}
+		return GetDestinationPortPermission(uint32(port)), nil
+
+	case DestinationIPRange:
+		permission, err := GetDestinationIPPermission(rule.Value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error parsing destination IP range value %s", rule.Value)
+		}
+		return permission, nil
+
+	default:
+		return nil, nil
+	}
+}
+
+// getRulePrincipal returns the authenticated RBAC principal for the given principal rule, or nil if the attribute of
+// the rule is not a supported principal attribute
+func getRulePrincipal(rule Rule) *xds_rbac.Principal {
+	switch rule.Attribute {
+	case DownstreamAuthPrincipal:
+		return GetAuthenticatedPrincipal(rule.Value)
+
+	case DownstreamAuthPrincipalPrefix:
+		return getAuthenticatedPrincipalMatching(&xds_matcher.StringMatcher{
+			MatchPattern: &xds_matcher.StringMatcher_Prefix{Prefix: rule.Value},
+		})
+
+	case DownstreamAuthPrincipalSuffix:
+		return getAuthenticatedPrincipalMatching(&xds_matcher.StringMatcher{
+			MatchPattern: &xds_matcher.StringMatcher_Suffix{Suffix: rule.Value},
+		})
+
+	default:
+		return nil
+	}
+}
+
+// GetPrincipalRules returns the rules matching the authenticated principal of the given downstream identity with OR
+// semantics. When the certificates of service identities carry SPIFFE IDs in the given trust domain, downstreams are
+// authenticated by the SPIFFE ID in their URI SAN rather than by their DNS SAN, so the SPIFFE ID of the identity is
+// matched in addition to the identity, which downstreams presenting certificates without SPIFFE IDs are authenticated by.
+// A namespace-scoped wildcard identity matches the principals of all the service accounts of its namespace.
+func GetPrincipalRules(downstreamIdentity identity.ServiceIdentity, spiffeTrustDomain string) []Rule {
+	if namespace, ok := downstreamIdentity.GetWildcardNamespace(); ok {
+		return getNamespacePrincipalRules(namespace, spiffeTrustDomain)
+	}
+
+	rules := []Rule{{Attribute: DownstreamAuthPrincipal, Value: downstreamIdentity.String()}}
+	if spiffeTrustDomain == "" {
+		return rules
+	}
+	if spiffeID := downstreamIdentity.SPIFFEID(spiffeTrustDomain); spiffeID != nil {
+		rules = append(rules, Rule{Attribute: DownstreamAuthPrincipal, Value: spiffeID.String()})
+	}
+	return rules
+}
+
+// getNamespacePrincipalRules returns the rules matching the authenticated principal of any service account of the given
+// namespace with OR semantics, by its identity and by its SPIFFE ID in the given trust domain if set
+func getNamespacePrincipalRules(namespace string, spiffeTrustDomain string) []Rule {
+	rules := []Rule{{Attribute: DownstreamAuthPrincipalSuffix, Value: fmt.Sprintf(".%s.%s", namespace, identity.ClusterLocalTrustDomain)}}
+	if spiffeTrustDomain != "" {
+		rules = append(rules, Rule{Attribute: DownstreamAuthPrincipalPrefix, Value: fmt.Sprintf("spiffe://%s/ns/%s/sa/", spiffeTrustDomain, namespace)})
+	}
+	return rules
+}
+
 // GetAuthenticatedPrincipal returns an authenticated RBAC principal object for the given principal
 func GetAuthenticatedPrincipal(principalName string) *xds_rbac.Principal {
+	return getAuthenticatedPrincipalMatching(&xds_matcher.StringMatcher{
+		MatchPattern: &xds_matcher.StringMatcher_Exact{
+			Exact: principalName,
+		},
+	})
+}
+
+// getAuthenticatedPrincipalMatching returns an authenticated RBAC principal object for the principals matching the
+// given matcher
+func getAuthenticatedPrincipalMatching(principalName *xds_matcher.StringMatcher) *xds_rbac.Principal {
 	return &xds_rbac.Principal{
 		Identifier: &xds_rbac.Principal_Authenticated_{
 			Authenticated: &xds_rbac.Principal_Authenticated{
-				PrincipalName: &xds_matcher.StringMatcher{
-					MatchPattern: &xds_matcher.StringMatcher_Exact{
-						Exact: principalName,
-					},
-				},
+				PrincipalName: principalName,
 			},
 		},
 	}
@@ -208,3 +301,23 @@ func GetDestinationPortPermission(port uint32) *xds_rbac.Permission {
 		},
 	}
 }
+
+// GetDestinationIPPermission returns an RBAC permission for the given destination IP range in CIDR notation
+func GetDestinationIPPermission(ipRange string) (*xds_rbac.Permission, error) {
+	ip, ipNet, err := net.ParseCIDR(ipRange)
+	if err != nil {
+		return nil, err
+	}
+
+	prefixLen, _ := ipNet.Mask.Size()
+	return &xds_rbac.Permission{
+		Rule: &xds_rbac.Permission_DestinationIp{
+			DestinationIp: &xds_core.CidrRange{
+				AddressPrefix: ip.String(),
+				PrefixLen: &wrapperspb.UInt32Value{
+					Value: uint32(prefixLen),
+				},
+			},
+		},
+	}, nil
+}
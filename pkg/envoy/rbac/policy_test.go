@@ -5,8 +5,13 @@ import (
 	"testing"
 
 	tassert "github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 
+	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_rbac "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
+	xds_matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+
+	"github.com/openservicemesh/osm/pkg/identity"
 )
 
 func TestGenerate(t *testing.T) {
@@ -271,6 +276,97 @@ func TestGenerate(t *testing.T) {
 			},
 			expectError: false,
 		},
+
+		{
+			name: "testing permission AND rules with destination port and IP range",
+			p: &Policy{
+				Permissions: []RulesList{
+					{
+						AndRules: []Rule{
+							{Attribute: DestinationPort, Value: "443"},
+							{Attribute: DestinationIPRange, Value: "10.0.0.0/8"},
+						},
+					},
+				},
+			},
+			expectedPrincipals: []*xds_rbac.Principal{getAnyPrincipal()},
+			expectedPermissions: []*xds_rbac.Permission{
+				{
+					Rule: &xds_rbac.Permission_AndRules{
+						AndRules: &xds_rbac.Permission_Set{
+							Rules: []*xds_rbac.Permission{
+								GetDestinationPortPermission(443),
+								{
+									Rule: &xds_rbac.Permission_DestinationIp{
+										DestinationIp: &xds_core.CidrRange{
+											AddressPrefix: "10.0.0.0",
+											PrefixLen:     &wrapperspb.UInt32Value{Value: 8},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectError: false,
+		},
+
+		{
+			name: "testing permission with an invalid destination IP range",
+			p: &Policy{
+				Permissions: []RulesList{
+					{
+						OrRules: []Rule{
+							{Attribute: DestinationIPRange, Value: "10.0.0.0"},
+						},
+					},
+				},
+			},
+			expectError: true,
+		},
+
+		{
+			name: "testing wildcard principals and duplicate principals",
+			p: &Policy{
+				Principals: []RulesList{
+					{
+						OrRules: []Rule{
+							{Attribute: DownstreamAuthPrincipalSuffix, Value: ".ns-1.cluster.local"},
+							{Attribute: DownstreamAuthPrincipalPrefix, Value: "spiffe://cluster.local/ns/ns-1/sa/"},
+						},
+					},
+					{
+						OrRules: []Rule{
+							{Attribute: DownstreamAuthPrincipalSuffix, Value: ".ns-1.cluster.local"},
+							{Attribute: DownstreamAuthPrincipalPrefix, Value: "spiffe://cluster.local/ns/ns-1/sa/"},
+						},
+					},
+				},
+			},
+			expectedPrincipals: []*xds_rbac.Principal{
+				{
+					Identifier: &xds_rbac.Principal_OrIds{
+						OrIds: &xds_rbac.Principal_Set{
+							Ids: []*xds_rbac.Principal{
+								getAuthenticatedPrincipalMatching(&xds_matcher.StringMatcher{
+									MatchPattern: &xds_matcher.StringMatcher_Suffix{Suffix: ".ns-1.cluster.local"},
+								}),
+								getAuthenticatedPrincipalMatching(&xds_matcher.StringMatcher{
+									MatchPattern: &xds_matcher.StringMatcher_Prefix{Prefix: "spiffe://cluster.local/ns/ns-1/sa/"},
+								}),
+							},
+						},
+					},
+				},
+			},
+			expectedPermissions: []*xds_rbac.Permission{
+				{
+					Rule: &xds_rbac.Permission_Any{Any: true},
+				},
+			},
+			expectError: false,
+		},
 	}
 
 	for i, tc := range testCases {
@@ -290,3 +386,48 @@ func TestGenerate(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPrincipalRules(t *testing.T) {
+	downstreamIdentity := identity.ServiceIdentity("sa-1.ns-1.cluster.local")
+
+	testCases := []struct {
+		name              string
+		identity          identity.ServiceIdentity
+		spiffeTrustDomain string
+		expectedRules     []Rule
+	}{
+		{
+			name:              "SPIFFE IDs disabled",
+			identity:          downstreamIdentity,
+			spiffeTrustDomain: "",
+			expectedRules: []Rule{
+				{Attribute: DownstreamAuthPrincipal, Value: "sa-1.ns-1.cluster.local"},
+			},
+		},
+		{
+			name:              "SPIFFE IDs enabled",
+			identity:          downstreamIdentity,
+			spiffeTrustDomain: "example.com",
+			expectedRules: []Rule{
+				{Attribute: DownstreamAuthPrincipal, Value: "sa-1.ns-1.cluster.local"},
+				{Attribute: DownstreamAuthPrincipal, Value: "spiffe://example.com/ns/ns-1/sa/sa-1"},
+			},
+		},
+		{
+			name:              "identity without SPIFFE ID",
+			identity:          identity.WildcardServiceIdentity,
+			spiffeTrustDomain: "example.com",
+			expectedRules: []Rule{
+				{Attribute: DownstreamAuthPrincipal, Value: identity.WildcardServiceIdentity.String()},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			assert.Equal(tc.expectedRules, GetPrincipalRules(tc.identity, tc.spiffeTrustDomain))
+		})
+	}
+}
@@ -8,12 +8,21 @@ type RuleAttribute string
 const (
 	// DownstreamAuthPrincipal is the key used for the name of the downstream principal in a policy Rule
 	DownstreamAuthPrincipal RuleAttribute = "downstreamAuthPrincipal"
+
+	// DownstreamAuthPrincipalPrefix is the key used for a prefix of the name of the downstream principal in a policy Rule
+	DownstreamAuthPrincipalPrefix RuleAttribute = "downstreamAuthPrincipalPrefix"
+
+	// DownstreamAuthPrincipalSuffix is the key used for a suffix of the name of the downstream principal in a policy Rule
+	DownstreamAuthPrincipalSuffix RuleAttribute = "downstreamAuthPrincipalSuffix"
 )
 
 // Supported attributes for an RBAC permission
 const (
 	// DestinationPort is the key used for the destination port as a permission in a policy Rule
 	DestinationPort RuleAttribute = "destinationPort"
+
+	// DestinationIPRange is the key used for the destination IP range, in CIDR notation, as a permission in a policy Rule
+	DestinationIPRange RuleAttribute = "destinationIPRange"
 )
 
 // Rule is a type that can represent a policy's Permission and Principal rules
@@ -33,3 +42,13 @@ type Policy struct {
 	Permissions []RulesList
 	Principals  []RulesList
 }
+
+// PrincipalCompactor builds the principal rules of the downstream identities allowed by RBAC policies, with the
+// identities of the namespaces allowed by a namespace-scoped wildcard identity compacted into the wildcard. The rules
+// are cached per set of downstream identities, so that the policies allowing the same downstreams share their rules.
+type PrincipalCompactor struct {
+	spiffeTrustDomain string
+
+	// cache holds the principal rules of each set of downstream identities, keyed by their sorted identities
+	cache map[string][]RulesList
+}
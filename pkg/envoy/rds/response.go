@@ -2,6 +2,7 @@ package rds
 
 import (
 	mapset "github.com/deckarep/golang-set"
+	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
 	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
@@ -10,6 +11,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/rbac"
 	"github.com/openservicemesh/osm/pkg/envoy/rds/route"
 	"github.com/openservicemesh/osm/pkg/envoy/registry"
 	"github.com/openservicemesh/osm/pkg/errcode"
@@ -24,14 +26,14 @@ func NewResponse(cataloger catalog.MeshCataloger, proxy *envoy.Proxy, discoveryR
 
 	proxyIdentity, err := envoy.GetServiceIdentityFromProxyCertificate(proxy.GetCertificateCommonName())
 	if err != nil {
-		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGettingServiceIdentity)).
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetricForProxy(errcode.ErrGettingServiceIdentity, proxy.GetCertificateCommonName())).
 			Msgf("Error looking up Service Account for Envoy with serial number=%q", proxy.GetCertificateSerialNumber())
 		return nil, err
 	}
 
 	services, err := proxyRegistry.ListProxyServices(proxy)
 	if err != nil {
-		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrFetchingServiceList)).
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetricForProxy(errcode.ErrFetchingServiceList, proxy.GetCertificateCommonName())).
 			Msgf("Error looking up services for Envoy with serial number=%q", proxy.GetCertificateSerialNumber())
 		return nil, err
 	}
@@ -39,15 +41,32 @@ func NewResponse(cataloger catalog.MeshCataloger, proxy *envoy.Proxy, discoveryR
 	// Build traffic policies from  either SMI Traffic Target and Traffic Split or service discovery
 	// depending on whether permissive mode is enabled or not
 	inboundTrafficPolicies = cataloger.ListInboundTrafficPolicies(proxyIdentity, services)
-	outboundTrafficPolicies = cataloger.ListOutboundTrafficPolicies(proxyIdentity)
 
-	routeConfiguration := route.BuildRouteConfiguration(inboundTrafficPolicies, outboundTrafficPolicies, proxy, cfg)
+	// With scoped routes, the outbound routes are not part of the outbound route configuration but of the route
+	// configurations of their hosts, which are only built for the hosts requested by the proxy
+	scopedRoutes := route.IsScopedRoutesEnabled(cfg)
+	if !scopedRoutes {
+		outboundTrafficPolicies = cataloger.ListOutboundTrafficPolicies(proxyIdentity)
+	}
+
+	// The principals of the RBAC policies of the routes allowing the same downstreams are only built once
+	principalCompactor := rbac.NewPrincipalCompactor(cfg.GetSPIFFETrustDomain())
+	routeConfiguration := route.BuildRouteConfiguration(inboundTrafficPolicies, outboundTrafficPolicies, proxy, cfg, principalCompactor)
 	var rdsResources []types.Resource
 
 	for _, config := range routeConfiguration {
 		rdsResources = append(rdsResources, config)
 	}
 
+	if scopedRoutes && discoveryReq != nil {
+		if hosts := route.GetHostsFromOutboundRouteConfigNames(discoveryReq.ResourceNames); len(hosts) > 0 {
+			hostTrafficPolicies := cataloger.ListOutboundTrafficPoliciesForHosts(proxyIdentity, hosts)
+			for _, config := range route.BuildOutboundHostRouteConfigurations(hostTrafficPolicies) {
+				rdsResources = append(rdsResources, config)
+			}
+		}
+	}
+
 	// Build Ingress inbound policies for the services associated with this proxy
 	for _, svc := range services {
 		ingressPolicy, err := cataloger.GetIngressTrafficPolicy(svc)
@@ -62,7 +81,7 @@ func NewResponse(cataloger catalog.MeshCataloger, proxy *envoy.Proxy, discoveryR
 		ingressTrafficPolicies = trafficpolicy.MergeInboundPolicies(catalog.AllowPartialHostnamesMatch, ingressTrafficPolicies, ingressPolicy.HTTPRoutePolicies...)
 	}
 	if len(ingressTrafficPolicies) > 0 {
-		ingressRouteConfig := route.BuildIngressConfiguration(ingressTrafficPolicies)
+		ingressRouteConfig := route.BuildIngressConfiguration(ingressTrafficPolicies, principalCompactor)
 		rdsResources = append(rdsResources, ingressRouteConfig)
 	}
 
@@ -78,6 +97,16 @@ func NewResponse(cataloger catalog.MeshCataloger, proxy *envoy.Proxy, discoveryR
 		}
 	}
 
+	// The regular expressions of the route matches come from HTTPRouteGroups, the routes with regular expressions that
+	// the proxies would reject are removed so that they don't reject the whole route configurations
+	var routeConfigs []*xds_route.RouteConfiguration
+	for _, resource := range rdsResources {
+		if routeConfig, ok := resource.(*xds_route.RouteConfiguration); ok {
+			routeConfigs = append(routeConfigs, routeConfig)
+		}
+	}
+	route.ApplyRouteRegexOptions(routeConfigs, cfg.GetRouteRegexConfig())
+
 	if discoveryReq != nil {
 		// Ensure all RDS resources are responded to a given non-nil and non-empty request
 		// Empty RDS RouteConfig will be provided for resources requested that our logic did not fulfill
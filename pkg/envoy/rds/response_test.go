@@ -13,7 +13,7 @@ import (
 	"github.com/google/uuid"
 	access "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
 	spec "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
-	split "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha2"
+	split "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha4"
 	tassert "github.com/stretchr/testify/assert"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -255,6 +255,7 @@ func TestNewResponse(t *testing.T) {
 			mockEndpointProvider := endpoint.NewMockProvider(mockCtrl)
 			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
 			mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+			mockCatalog.EXPECT().GetKubeController().Return(mockKubeController).AnyTimes()
 			kubeClient := testclient.NewSimpleClientset()
 			proxy, err := getBookstoreV1Proxy(kubeClient)
 			assert.Nil(err)
@@ -277,6 +278,9 @@ func TestNewResponse(t *testing.T) {
 
 			mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
 
+			mockConfigurator.EXPECT().GetRBACAuditConfig().Return(v1alpha1.RBACAuditSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetRouteRegexConfig().Return(v1alpha1.RouteRegexSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetSPIFFETrustDomain().Return("").AnyTimes()
 			mockConfigurator.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{
 				EnableWASMStats: false,
 			}).AnyTimes()
@@ -297,7 +301,7 @@ func TestNewResponse(t *testing.T) {
 
 			// The RDS response will have two route configurations
 			// 1. rds-inbound
-			// 2. rds-outbound
+			// 2. rds-outbound.8888
 			// 3. rds-ingress
 			assert.Equal(3, len(resources))
 
@@ -335,9 +339,9 @@ func TestNewResponse(t *testing.T) {
 			routeConfig, ok = resources[1].(*xds_route.RouteConfiguration)
 			assert.True(ok)
 
-			// The rds-outbound will have the following virtual hosts :
+			// The rds-outbound.8888 will have the following virtual hosts :
 			// outbound_virtual-host|bookstore-apex
-			assert.Equal("rds-outbound", routeConfig.Name)
+			assert.Equal("rds-outbound.8888", routeConfig.Name)
 			assert.Equal(1, len(routeConfig.VirtualHosts))
 
 			assert.Equal("outbound_virtual-host|bookstore-apex", routeConfig.VirtualHosts[0].Name)
@@ -429,6 +433,8 @@ func TestNewResponseWithPermissiveMode(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockCatalog.EXPECT().GetKubeController().Return(mockKubeController).AnyTimes()
 	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
 
 	uuid := uuid.New().String()
@@ -535,6 +541,9 @@ func TestNewResponseWithPermissiveMode(t *testing.T) {
 
 	mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(true).AnyTimes()
 
+	mockConfigurator.EXPECT().GetRBACAuditConfig().Return(v1alpha1.RBACAuditSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().GetRouteRegexConfig().Return(v1alpha1.RouteRegexSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().GetSPIFFETrustDomain().Return("").AnyTimes()
 	mockConfigurator.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{
 		EnableWASMStats: false,
 	}).AnyTimes()
@@ -554,11 +563,11 @@ func TestNewResponseWithPermissiveMode(t *testing.T) {
 	assert.Equal(1, len(routeConfig.VirtualHosts[0].Routes))
 	assert.Equal(constants.RegexMatchAll, routeConfig.VirtualHosts[0].Routes[0].GetMatch().GetSafeRegex().Regex)
 
-	// Test rds-outbound route config
+	// Test rds-outbound.8888 route config
 	routeConfig, ok = resources[1].(*xds_route.RouteConfiguration)
 	assert.True(ok)
 
-	assert.Equal("rds-outbound", routeConfig.Name)
+	assert.Equal("rds-outbound.8888", routeConfig.Name)
 	assert.Equal(1, len(routeConfig.VirtualHosts))
 
 	assert.Equal("outbound_virtual-host|bookbuyer.default", routeConfig.VirtualHosts[0].Name)
@@ -586,6 +595,8 @@ func TestResponseRequestCompletion(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockCatalog.EXPECT().GetKubeController().Return(mockKubeController).AnyTimes()
 	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
 
 	uuid := uuid.New().String()
@@ -603,6 +614,9 @@ func TestResponseRequestCompletion(t *testing.T) {
 	mockCatalog.EXPECT().GetIngressTrafficPolicy(gomock.Any()).Return(nil, nil).AnyTimes()
 	mockCatalog.EXPECT().GetEgressTrafficPolicy(gomock.Any()).Return(nil, nil).AnyTimes()
 	mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetRBACAuditConfig().Return(v1alpha1.RBACAuditSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().GetRouteRegexConfig().Return(v1alpha1.RouteRegexSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().GetSPIFFETrustDomain().Return("").AnyTimes()
 	mockConfigurator.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{
 		EnableWASMStats: false,
 	}).AnyTimes()
@@ -617,7 +631,7 @@ func TestResponseRequestCompletion(t *testing.T) {
 		},
 		{
 			request: &xds_discovery.DiscoveryRequest{
-				ResourceNames: []string{"rds-inbound", "rds-outbound", "ingress", "bar", "doge"},
+				ResourceNames: []string{"rds-inbound", "rds-outbound.8888", "ingress", "bar", "doge"},
 			},
 		},
 		{
@@ -651,3 +665,67 @@ func TestResponseRequestCompletion(t *testing.T) {
 		}
 	}
 }
+
+func TestNewResponseWithScopedRoutes(t *testing.T) {
+	assert := tassert.New(t)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockCatalog.EXPECT().GetKubeController().Return(mockKubeController).AnyTimes()
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+
+	testProxy, err := envoy.NewProxy(certificate.CommonName(fmt.Sprintf("%s.%s.%s.one.two.three.co.uk", uuid.New(), "some-service", "some-namespace")), "123456", nil)
+	assert.Nil(err)
+
+	proxyRegistry := registry.NewProxyRegistry(registry.ExplicitProxyServiceMapper(func(*envoy.Proxy) ([]service.MeshService, error) {
+		return []service.MeshService{tests.BookstoreV1Service}, nil
+	}))
+
+	bookstoreV1Policy := &trafficpolicy.OutboundTrafficPolicy{
+		Name:      "bookstore-v1.default.svc.cluster.local",
+		Hostnames: tests.BookstoreV1Hostnames,
+		Routes: []*trafficpolicy.RouteWeightedClusters{
+			{
+				HTTPRouteMatch:   tests.WildCardRouteMatch,
+				WeightedClusters: mapset.NewSet(tests.BookstoreV1DefaultWeightedCluster),
+			},
+		},
+	}
+
+	mockCatalog.EXPECT().ListInboundTrafficPolicies(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockCatalog.EXPECT().ListOutboundTrafficPolicies(gomock.Any()).Times(0)
+	mockCatalog.EXPECT().ListOutboundTrafficPoliciesForHosts(gomock.Any(), []string{"bookstore-v1.default.svc.cluster.local", "unknown.default.svc.cluster.local"}).
+		Return([]*trafficpolicy.OutboundTrafficPolicy{bookstoreV1Policy}).Times(1)
+	mockCatalog.EXPECT().GetIngressTrafficPolicy(gomock.Any()).Return(nil, nil).AnyTimes()
+	mockCatalog.EXPECT().GetEgressTrafficPolicy(gomock.Any()).Return(nil, nil).AnyTimes()
+	mockConfigurator.EXPECT().GetRBACAuditConfig().Return(v1alpha1.RBACAuditSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().GetRouteRegexConfig().Return(v1alpha1.RouteRegexSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().GetSPIFFETrustDomain().Return("").AnyTimes()
+	mockConfigurator.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{EnableScopedRoutes: true}).AnyTimes()
+
+	request := &xds_discovery.DiscoveryRequest{
+		ResourceNames: []string{"rds-inbound", "rds-outbound.bookstore-v1.default.svc.cluster.local", "rds-outbound.unknown.default.svc.cluster.local"},
+	}
+	resources, err := NewResponse(mockCatalog, testProxy, request, mockConfigurator, nil, proxyRegistry)
+	assert.Nil(err)
+
+	routeConfigs := make(map[string]*xds_route.RouteConfiguration)
+	for _, res := range resources {
+		routeConfig, ok := res.(*xds_route.RouteConfiguration)
+		assert.True(ok)
+		routeConfigs[routeConfig.Name] = routeConfig
+	}
+
+	// There are no outbound route configurations per port, the outbound routes are in the route configurations of
+	// their hosts
+	assert.NotContains(routeConfigs, "rds-outbound.8888")
+
+	assert.Contains(routeConfigs, "rds-outbound.bookstore-v1.default.svc.cluster.local")
+	assert.Len(routeConfigs["rds-outbound.bookstore-v1.default.svc.cluster.local"].VirtualHosts, 1)
+
+	// Requested hosts without outbound policies get empty route configurations
+	assert.Contains(routeConfigs, "rds-outbound.unknown.default.svc.cluster.local")
+	assert.Empty(routeConfigs["rds-outbound.unknown.default.svc.cluster.local"].VirtualHosts)
+}
@@ -0,0 +1,90 @@
+package route
+
+import (
+	"sort"
+	"strings"
+
+	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openservicemesh/osm/pkg/errcode"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+)
+
+// resolveVirtualHostDomainConflicts removes the domains of the virtual hosts of the given route configuration that
+// are also domains of other virtual hosts, as Envoy rejects route configurations with domains shared by several
+// virtual hosts. Services can share hostnames, e.g. services with the same name in different meshes or ExternalName
+// aliases. A conflicting domain is kept by the virtual host with the lowest name, so that the resolution does not
+// depend on the order of the policies, and the virtual hosts left without domains are removed.
+// The virtual hosts whose domains are removed are replaced by copies, as they can be shared by route configurations.
+func resolveVirtualHostDomainConflicts(routeConfig *xds_route.RouteConfiguration) {
+	byName := make([]*xds_route.VirtualHost, len(routeConfig.VirtualHosts))
+	copy(byName, routeConfig.VirtualHosts)
+	sort.SliceStable(byName, func(i, j int) bool {
+		return byName[i].Name < byName[j].Name
+	})
+
+	// The virtual host each domain is kept by, keyed by lowercase domain as Envoy matches domains case-insensitively
+	domainOwners := make(map[string]string)
+	resolved := make(map[*xds_route.VirtualHost]*xds_route.VirtualHost)
+	for _, virtualHost := range byName {
+		var domains []string
+		var owners []string
+		conflicts := make(map[string][]string)
+		for _, domain := range virtualHost.Domains {
+			key := strings.ToLower(domain)
+			owner, ok := domainOwners[key]
+			if !ok {
+				domainOwners[key] = virtualHost.Name
+				domains = append(domains, domain)
+				continue
+			}
+			if owner != virtualHost.Name {
+				if _, ok := conflicts[owner]; !ok {
+					owners = append(owners, owner)
+				}
+				conflicts[owner] = append(conflicts[owner], domain)
+			}
+			// Duplicate domains of the same virtual host are dropped silently
+		}
+
+		if len(domains) == len(virtualHost.Domains) {
+			continue
+		}
+		for _, owner := range owners {
+			log.Error().Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrConflictingVirtualHostDomains)).
+				Msgf("Domains %v of virtual host %s in route configuration %s conflict with virtual host %s, removing them from %s",
+					conflicts[owner], virtualHost.Name, routeConfig.Name, owner, virtualHost.Name)
+			events.GenericEventRecorder().WarnEvent(events.VirtualHostDomainConflict,
+				"Domains %v of virtual host %s in route configuration %s are also domains of virtual host %s and only routed by it",
+				conflicts[owner], virtualHost.Name, routeConfig.Name, owner)
+		}
+
+		if len(domains) == 0 {
+			log.Error().Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrConflictingVirtualHostDomains)).
+				Msgf("All the domains of virtual host %s in route configuration %s conflict with other virtual hosts, removing it",
+					virtualHost.Name, routeConfig.Name)
+			resolved[virtualHost] = nil
+			continue
+		}
+		resolvedVirtualHost := proto.Clone(virtualHost).(*xds_route.VirtualHost)
+		resolvedVirtualHost.Domains = domains
+		resolved[virtualHost] = resolvedVirtualHost
+	}
+
+	if len(resolved) == 0 {
+		return
+	}
+	var virtualHosts []*xds_route.VirtualHost
+	for _, virtualHost := range routeConfig.VirtualHosts {
+		resolvedVirtualHost, ok := resolved[virtualHost]
+		if !ok {
+			virtualHosts = append(virtualHosts, virtualHost)
+			continue
+		}
+		if resolvedVirtualHost != nil {
+			virtualHosts = append(virtualHosts, resolvedVirtualHost)
+		}
+	}
+	routeConfig.VirtualHosts = virtualHosts
+}
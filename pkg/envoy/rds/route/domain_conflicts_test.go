@@ -0,0 +1,108 @@
+package route
+
+import (
+	"testing"
+
+	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestResolveVirtualHostDomainConflicts(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		virtualHosts         []*xds_route.VirtualHost
+		expectedVirtualHosts map[string][]string
+		expectedOrder        []string
+	}{
+		{
+			name: "no conflicts",
+			virtualHosts: []*xds_route.VirtualHost{
+				{Name: "inbound_virtual-host|bookstore-v1.default", Domains: []string{"bookstore-v1", "bookstore-v1.default"}},
+				{Name: "inbound_virtual-host|bookstore-v2.default", Domains: []string{"bookstore-v2", "bookstore-v2.default"}},
+			},
+			expectedVirtualHosts: map[string][]string{
+				"inbound_virtual-host|bookstore-v1.default": {"bookstore-v1", "bookstore-v1.default"},
+				"inbound_virtual-host|bookstore-v2.default": {"bookstore-v2", "bookstore-v2.default"},
+			},
+			expectedOrder: []string{"inbound_virtual-host|bookstore-v1.default", "inbound_virtual-host|bookstore-v2.default"},
+		},
+		{
+			name: "conflicting domains are kept by the virtual host with the lowest name",
+			virtualHosts: []*xds_route.VirtualHost{
+				{Name: "inbound_virtual-host|bookstore.foo", Domains: []string{"bookstore", "bookstore.foo"}},
+				{Name: "inbound_virtual-host|bookstore.bar", Domains: []string{"Bookstore", "bookstore.bar"}},
+			},
+			expectedVirtualHosts: map[string][]string{
+				"inbound_virtual-host|bookstore.foo": {"bookstore.foo"},
+				"inbound_virtual-host|bookstore.bar": {"Bookstore", "bookstore.bar"},
+			},
+			expectedOrder: []string{"inbound_virtual-host|bookstore.foo", "inbound_virtual-host|bookstore.bar"},
+		},
+		{
+			name: "virtual hosts without domains left are removed",
+			virtualHosts: []*xds_route.VirtualHost{
+				{Name: "inbound_virtual-host|bookstore-alias.default", Domains: []string{"bookstore.default"}},
+				{Name: "inbound_virtual-host|bookstore.default", Domains: []string{"bookstore", "bookstore.default"}},
+			},
+			expectedVirtualHosts: map[string][]string{
+				"inbound_virtual-host|bookstore-alias.default": {"bookstore.default"},
+				"inbound_virtual-host|bookstore.default":       {"bookstore"},
+			},
+			expectedOrder: []string{"inbound_virtual-host|bookstore-alias.default", "inbound_virtual-host|bookstore.default"},
+		},
+		{
+			name: "virtual host whose domains all conflict is removed",
+			virtualHosts: []*xds_route.VirtualHost{
+				{Name: "inbound_virtual-host|bookstore.foo", Domains: []string{"bookstore"}},
+				{Name: "inbound_virtual-host|bookstore.bar", Domains: []string{"bookstore", "bookstore.bar"}},
+			},
+			expectedVirtualHosts: map[string][]string{
+				"inbound_virtual-host|bookstore.bar": {"bookstore", "bookstore.bar"},
+			},
+			expectedOrder: []string{"inbound_virtual-host|bookstore.bar"},
+		},
+		{
+			name: "duplicate domains of a virtual host are removed",
+			virtualHosts: []*xds_route.VirtualHost{
+				{Name: "inbound_virtual-host|bookstore.default", Domains: []string{"bookstore", "bookstore", "bookstore.default"}},
+			},
+			expectedVirtualHosts: map[string][]string{
+				"inbound_virtual-host|bookstore.default": {"bookstore", "bookstore.default"},
+			},
+			expectedOrder: []string{"inbound_virtual-host|bookstore.default"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			routeConfig := NewRouteConfigurationStub(InboundRouteConfigName)
+			routeConfig.VirtualHosts = tc.virtualHosts
+			resolveVirtualHostDomainConflicts(routeConfig)
+
+			var order []string
+			for _, virtualHost := range routeConfig.VirtualHosts {
+				order = append(order, virtualHost.Name)
+				assert.Equal(tc.expectedVirtualHosts[virtualHost.Name], virtualHost.Domains)
+			}
+			assert.Equal(tc.expectedOrder, order)
+		})
+	}
+}
+
+func TestResolveVirtualHostDomainConflictsSharedVirtualHosts(t *testing.T) {
+	assert := tassert.New(t)
+
+	shared := &xds_route.VirtualHost{Name: "outbound_virtual-host|bookstore.foo", Domains: []string{"bookstore", "bookstore.foo"}}
+	conflicting := &xds_route.VirtualHost{Name: "outbound_virtual-host|bookstore.bar", Domains: []string{"bookstore"}}
+
+	routeConfig := NewRouteConfigurationStub(GetOutboundRouteConfigNameForPort(80))
+	routeConfig.VirtualHosts = []*xds_route.VirtualHost{shared, conflicting}
+	resolveVirtualHostDomainConflicts(routeConfig)
+
+	// The virtual host is not modified in place, as it can be part of other route configurations
+	assert.Equal([]string{"bookstore.foo"}, routeConfig.VirtualHosts[0].Domains)
+	assert.Equal([]string{"bookstore", "bookstore.foo"}, shared.Domains)
+	assert.Equal(conflicting, routeConfig.VirtualHosts[1])
+}
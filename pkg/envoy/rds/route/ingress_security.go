@@ -0,0 +1,143 @@
+package route
+
+import (
+	"fmt"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	xds_csrf "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/csrf/v3"
+	xds_lua "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/lua/v3"
+	xds_matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	xds_type "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/pkg/errors"
+
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+// CSRFFilterName is the name of the CSRF HTTP filter, enabled on the ingress virtual hosts with CSRF protection
+const CSRFFilterName = "envoy.filters.http.csrf"
+
+// validCookieSameSiteValues are the values of the SameSite attribute of cookies
+var validCookieSameSiteValues = map[string]bool{
+	"Strict": true,
+	"Lax":    true,
+	"None":   true,
+}
+
+// cookieAttributesLuaCode is the Lua code setting the attributes of the cookies of the responses, formatted with
+// whether the Secure and HttpOnly attributes are set and the value of the SameSite attribute.
+// The Set-Cookie headers are collected before being replaced since the headers cannot be modified while iterated.
+const cookieAttributesLuaCode = `local secure, httpOnly, sameSite = %t, %t, "%s"
+
+function envoy_on_response(response_handle)
+  local headers = response_handle:headers()
+  local cookies = {}
+  for name, value in pairs(headers) do
+    if name == "set-cookie" then
+      table.insert(cookies, value)
+    end
+  end
+  if #cookies == 0 then
+    return
+  end
+
+  headers:remove("set-cookie")
+  for _, cookie in ipairs(cookies) do
+    local attributes = string.lower(cookie) .. ";"
+    if secure and not string.find(attributes, ";%%s*secure%%s*;") then
+      cookie = cookie .. "; Secure"
+    end
+    if httpOnly and not string.find(attributes, ";%%s*httponly%%s*;") then
+      cookie = cookie .. "; HttpOnly"
+    end
+    if sameSite ~= "" then
+      cookie = string.gsub(cookie, ";%%s*[Ss][Aa][Mm][Ee][Ss][Ii][Tt][Ee]=[^;]*", "") .. "; SameSite=" .. sameSite
+    end
+    headers:add("set-cookie", cookie)
+  end
+end
+`
+
+// applyIngressSecurityPolicy configures the given ingress virtual host to protect its requests against cross-site
+// request forgery, and to set the security headers and the cookie attributes of its responses as per the given
+// policy. The CSRF and Lua filters are enabled on the virtual host through their per filter config.
+func applyIngressSecurityPolicy(virtualHost *xds_route.VirtualHost, security *trafficpolicy.IngressSecurityPolicy) error {
+	if security == nil {
+		return nil
+	}
+
+	// The security headers replace the headers set by the header mutations of the virtual host
+	virtualHost.ResponseHeadersToAdd = append(virtualHost.ResponseHeadersToAdd, buildHeaderValueOptions(security.ResponseHeaders)...)
+
+	perFilterConfig := make(map[string]*any.Any)
+	if security.CSRF != nil {
+		csrfAny, err := ptypes.MarshalAny(buildCSRFPolicy(security.CSRF))
+		if err != nil {
+			return errors.Wrap(err, "Error marshaling CSRF policy")
+		}
+		perFilterConfig[CSRFFilterName] = csrfAny
+	}
+	if security.Cookies != nil {
+		luaAny, err := ptypes.MarshalAny(buildCookieAttributesLuaPerRoute(security.Cookies))
+		if err != nil {
+			return errors.Wrap(err, "Error marshaling cookie attributes Lua code")
+		}
+		perFilterConfig[wellknown.Lua] = luaAny
+	}
+	if len(perFilterConfig) > 0 {
+		virtualHost.TypedPerFilterConfig = perFilterConfig
+	}
+
+	return nil
+}
+
+// buildCSRFPolicy returns the CSRF policy of a virtual host for the given CSRF policy, the requests with invalid
+// origins being rejected unless in shadow mode
+func buildCSRFPolicy(csrf *trafficpolicy.CSRFPolicy) *xds_csrf.CsrfPolicy {
+	percent := func(numerator uint32) *core.RuntimeFractionalPercent {
+		return &core.RuntimeFractionalPercent{
+			DefaultValue: &xds_type.FractionalPercent{
+				Numerator:   numerator,
+				Denominator: xds_type.FractionalPercent_HUNDRED,
+			},
+		}
+	}
+
+	csrfPolicy := &xds_csrf.CsrfPolicy{
+		FilterEnabled: percent(100),
+	}
+	if csrf.ShadowMode {
+		csrfPolicy.FilterEnabled = percent(0)
+		csrfPolicy.ShadowEnabled = percent(100)
+	}
+	for _, origin := range csrf.AdditionalOrigins {
+		csrfPolicy.AdditionalOrigins = append(csrfPolicy.AdditionalOrigins, &xds_matcher.StringMatcher{
+			MatchPattern: &xds_matcher.StringMatcher_Exact{Exact: origin},
+		})
+	}
+
+	return csrfPolicy
+}
+
+// buildCookieAttributesLuaPerRoute returns the per route config of the Lua filter setting the given attributes on the
+// cookies of the responses. Invalid SameSite values are ignored.
+func buildCookieAttributesLuaPerRoute(cookies *trafficpolicy.CookieAttributes) *xds_lua.LuaPerRoute {
+	sameSite := cookies.SameSite
+	if sameSite != "" && !validCookieSameSiteValues[sameSite] {
+		log.Warn().Msgf("Ignoring invalid cookie SameSite attribute %q", sameSite)
+		sameSite = ""
+	}
+
+	return &xds_lua.LuaPerRoute{
+		Override: &xds_lua.LuaPerRoute_SourceCode{
+			SourceCode: &core.DataSource{
+				Specifier: &core.DataSource_InlineString{
+					InlineString: fmt.Sprintf(cookieAttributesLuaCode, cookies.Secure, cookies.HTTPOnly, sameSite),
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,159 @@
+package route
+
+import (
+	"testing"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	xds_csrf "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/csrf/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestApplyIngressSecurityPolicy(t *testing.T) {
+	testCases := []struct {
+		name                    string
+		security                *trafficpolicy.IngressSecurityPolicy
+		expectedResponseHeaders []*core.HeaderValueOption
+		expectedFilterConfigs   []string
+	}{
+		{
+			name:     "security unset",
+			security: nil,
+		},
+		{
+			name: "security headers replace the headers of the virtual host",
+			security: &trafficpolicy.IngressSecurityPolicy{
+				ResponseHeaders: []trafficpolicy.HTTPHeaderValue{{Name: "X-Frame-Options", Value: "DENY"}},
+			},
+			expectedResponseHeaders: []*core.HeaderValueOption{
+				{
+					Header: &core.HeaderValue{Key: "X-Frame-Options", Value: "SAMEORIGIN"},
+					Append: &wrappers.BoolValue{Value: false},
+				},
+				{
+					Header: &core.HeaderValue{Key: "X-Frame-Options", Value: "DENY"},
+					Append: &wrappers.BoolValue{Value: false},
+				},
+			},
+		},
+		{
+			name: "CSRF and cookie attributes filters enabled",
+			security: &trafficpolicy.IngressSecurityPolicy{
+				CSRF:    &trafficpolicy.CSRFPolicy{},
+				Cookies: &trafficpolicy.CookieAttributes{Secure: true},
+			},
+			expectedFilterConfigs: []string{CSRFFilterName, wellknown.Lua},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			virtualHost := buildVirtualHostStub(ingressVirtualHost, "bookstore", []string{"*"})
+			applyVirtualHostHeaderMutations(virtualHost, &trafficpolicy.HTTPHeaderMutations{
+				ResponseHeadersToAdd: []trafficpolicy.HTTPHeaderValue{{Name: "X-Frame-Options", Value: "SAMEORIGIN"}},
+			})
+
+			err := applyIngressSecurityPolicy(virtualHost, tc.security)
+			assert.Nil(err)
+
+			if tc.expectedResponseHeaders != nil {
+				assert.Equal(tc.expectedResponseHeaders, virtualHost.ResponseHeadersToAdd)
+			}
+			assert.Len(virtualHost.TypedPerFilterConfig, len(tc.expectedFilterConfigs))
+			for _, name := range tc.expectedFilterConfigs {
+				assert.Contains(virtualHost.TypedPerFilterConfig, name)
+			}
+		})
+	}
+}
+
+func TestBuildCSRFPolicy(t *testing.T) {
+	assert := tassert.New(t)
+
+	csrfPolicy := buildCSRFPolicy(&trafficpolicy.CSRFPolicy{
+		AdditionalOrigins: []string{"https://bookstore.example.com"},
+	})
+	assert.Equal(uint32(100), csrfPolicy.FilterEnabled.DefaultValue.Numerator)
+	assert.Nil(csrfPolicy.ShadowEnabled)
+	assert.Len(csrfPolicy.AdditionalOrigins, 1)
+	assert.Equal("https://bookstore.example.com", csrfPolicy.AdditionalOrigins[0].GetExact())
+
+	// In shadow mode the requests with invalid origins are only counted
+	csrfPolicy = buildCSRFPolicy(&trafficpolicy.CSRFPolicy{ShadowMode: true})
+	assert.Equal(uint32(0), csrfPolicy.FilterEnabled.DefaultValue.Numerator)
+	assert.Equal(uint32(100), csrfPolicy.ShadowEnabled.DefaultValue.Numerator)
+	assert.Nil(csrfPolicy.AdditionalOrigins)
+	assert.Nil(csrfPolicy.Validate())
+}
+
+func TestBuildCookieAttributesLuaPerRoute(t *testing.T) {
+	testCases := []struct {
+		name        string
+		cookies     *trafficpolicy.CookieAttributes
+		expectedSet string
+	}{
+		{
+			name:        "secure and HttpOnly cookies with a SameSite attribute",
+			cookies:     &trafficpolicy.CookieAttributes{Secure: true, HTTPOnly: true, SameSite: "Strict"},
+			expectedSet: `local secure, httpOnly, sameSite = true, true, "Strict"`,
+		},
+		{
+			name:        "secure cookies",
+			cookies:     &trafficpolicy.CookieAttributes{Secure: true},
+			expectedSet: `local secure, httpOnly, sameSite = true, false, ""`,
+		},
+		{
+			name:        "invalid SameSite attribute is ignored",
+			cookies:     &trafficpolicy.CookieAttributes{HTTPOnly: true, SameSite: `Lax"; os.exit() --`},
+			expectedSet: `local secure, httpOnly, sameSite = false, true, ""`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			luaPerRoute := buildCookieAttributesLuaPerRoute(tc.cookies)
+			assert.Nil(luaPerRoute.Validate())
+
+			code := luaPerRoute.GetSourceCode().GetInlineString()
+			assert.Contains(code, tc.expectedSet+"\n")
+			assert.Contains(code, `string.find(attributes, ";%s*secure%s*;")`)
+		})
+	}
+}
+
+func TestBuildIngressConfigurationSecurity(t *testing.T) {
+	assert := tassert.New(t)
+
+	ingress := []*trafficpolicy.InboundTrafficPolicy{
+		{
+			Name:      "bookstore-v1-default",
+			Hostnames: []string{"*"},
+			Security: &trafficpolicy.IngressSecurityPolicy{
+				CSRF: &trafficpolicy.CSRFPolicy{ShadowMode: true},
+			},
+		},
+	}
+
+	routeConfig := BuildIngressConfiguration(ingress, nil)
+	assert.Len(routeConfig.VirtualHosts, 1)
+
+	virtualHost := routeConfig.VirtualHosts[0]
+	assert.Len(virtualHost.TypedPerFilterConfig, 1)
+
+	csrfPolicy := &xds_csrf.CsrfPolicy{}
+	assert.Nil(ptypes.UnmarshalAny(virtualHost.TypedPerFilterConfig[CSRFFilterName], csrfPolicy))
+	assert.Equal(uint32(100), csrfPolicy.ShadowEnabled.DefaultValue.Numerator)
+	assert.Nil(virtualHost.TypedPerFilterConfig[wellknown.Lua])
+
+	// The virtual hosts without security policy have no per filter config
+	routeConfig = BuildIngressConfiguration([]*trafficpolicy.InboundTrafficPolicy{{Name: "bookstore-v2-default", Hostnames: []string{"*"}}}, nil)
+	assert.Nil(routeConfig.VirtualHosts[0].TypedPerFilterConfig)
+}
@@ -19,9 +19,12 @@ const (
 )
 
 // buildInboundRBACFilterForRule builds an HTTP RBAC per route filter based on the given traffic policy rule.
-// The principals in the RBAC policy are derived from the allowed service accounts specified in the given rule.
+// The principals in the RBAC policy are derived from the allowed service accounts specified in the given rule, and
+// built by the given compactor. A wildcard allowed service account allows all downstream principals.
 // The permissions in the RBAC policy are implicitly set to ANY (all permissions).
-func buildInboundRBACFilterForRule(rule *trafficpolicy.Rule) (map[string]*any.Any, error) {
+// In shadow mode, the policy is only evaluated as shadow rules: the requests it denies are flagged in the dynamic
+// metadata of the filter and counted in its shadow_denied stat, but allowed through.
+func buildInboundRBACFilterForRule(rule *trafficpolicy.Rule, shadowMode bool, principalCompactor *rbac.PrincipalCompactor) (map[string]*any.Any, error) {
 	if rule.AllowedServiceIdentities == nil {
 		return nil, errors.Errorf("traffipolicy.Rule.AllowedServiceIdentities not set")
 	}
@@ -29,31 +32,11 @@ func buildInboundRBACFilterForRule(rule *trafficpolicy.Rule) (map[string]*any.An
 	policy := &rbac.Policy{}
 
 	// Create the list of principals for this policy
-	var principalRuleList []rbac.RulesList
+	var downstreamIdentities []identity.ServiceIdentity
 	for downstream := range rule.AllowedServiceIdentities.Iter() {
-		var principalRule rbac.RulesList
-		downstreamIdentity := downstream.(identity.ServiceIdentity)
-
-		if downstreamIdentity.IsWildcard() {
-			// When the downstream identity in a traffic policy rule is set to be empty, it implies
-			// we must allow all downstream principals. This can be accomplished by setting an empty
-			// principal rules list to generate an RBAC policy with principals set to ANY (all downstreams).
-			principalRule = rbac.RulesList{}
-		} else {
-			// The downstream principal in an RBAC policy is an authenticated principal type, which
-			// means the principal must correspond to the fully qualified SAN in the certificate presented
-			// by the downstream.
-			principalRule = rbac.RulesList{
-				OrRules: []rbac.Rule{
-					{Attribute: rbac.DownstreamAuthPrincipal, Value: downstreamIdentity.String()},
-				},
-			}
-		}
-
-		principalRuleList = append(principalRuleList, principalRule)
+		downstreamIdentities = append(downstreamIdentities, downstream.(identity.ServiceIdentity))
 	}
-
-	policy.Principals = principalRuleList
+	policy.Principals = principalCompactor.GetPrincipalRules(downstreamIdentities)
 
 	rbacPolicy, err := policy.Generate()
 	if err != nil {
@@ -64,11 +47,15 @@ func buildInboundRBACFilterForRule(rule *trafficpolicy.Rule) (map[string]*any.An
 	rbacPolicyMap := map[string]*xds_rbac.Policy{rbacPerRoutePolicyName: rbacPolicy}
 
 	// Map generic RBAC policy to HTTP RBAC policy
-	httpRBAC := &xds_http_rbac.RBAC{
-		Rules: &xds_rbac.RBAC{
-			Action:   xds_rbac.RBAC_ALLOW, // Allows the request if and only if there is a policy that matches the request
-			Policies: rbacPolicyMap,
-		},
+	rules := &xds_rbac.RBAC{
+		Action:   xds_rbac.RBAC_ALLOW, // Allows the request if and only if there is a policy that matches the request
+		Policies: rbacPolicyMap,
+	}
+	httpRBAC := &xds_http_rbac.RBAC{}
+	if shadowMode {
+		httpRBAC.ShadowRules = rules
+	} else {
+		httpRBAC.Rules = rules
 	}
 	httpRBACPerRoute := &xds_http_rbac.RBACPerRoute{
 		Rbac: httpRBAC,
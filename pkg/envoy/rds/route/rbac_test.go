@@ -108,7 +108,7 @@ func TestBuildInboundRBACFilterForRule(t *testing.T) {
 		t.Run(fmt.Sprintf("Test case %d: %s", i, tc.name), func(t *testing.T) {
 			assert := tassert.New(t)
 
-			rbacFilter, err := buildInboundRBACFilterForRule(tc.rule)
+			rbacFilter, err := buildInboundRBACFilterForRule(tc.rule, false, rbac.NewPrincipalCompactor(""))
 
 			assert.Equal(tc.expectError, err != nil)
 			if err != nil {
@@ -132,3 +132,28 @@ func TestBuildInboundRBACFilterForRule(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildInboundRBACFilterForRuleInShadowMode(t *testing.T) {
+	assert := tassert.New(t)
+
+	rule := &trafficpolicy.Rule{
+		Route: trafficpolicy.RouteWeightedClusters{
+			HTTPRouteMatch:   tests.BookstoreBuyHTTPRoute,
+			WeightedClusters: mapset.NewSet(tests.BookstoreV1DefaultWeightedCluster),
+		},
+		AllowedServiceIdentities: mapset.NewSetFromSlice([]interface{}{
+			identity.K8sServiceAccount{Name: "foo", Namespace: "ns-1"}.ToServiceIdentity(),
+		}),
+	}
+
+	rbacFilter, err := buildInboundRBACFilterForRule(rule, true, rbac.NewPrincipalCompactor(""))
+	assert.Nil(err)
+
+	httpRBACPerRoute := &xds_http_rbac.RBACPerRoute{}
+	assert.Nil(ptypes.UnmarshalAny(rbacFilter[wellknown.HTTPRoleBasedAccessControl], httpRBACPerRoute))
+
+	// The policy is only evaluated as shadow rules, the requests it denies are allowed through
+	assert.Nil(httpRBACPerRoute.Rbac.Rules)
+	assert.Equal(xds_rbac.RBAC_ALLOW, httpRBACPerRoute.Rbac.ShadowRules.Action)
+	assert.Contains(httpRBACPerRoute.Rbac.ShadowRules.Policies, rbacPerRoutePolicyName)
+}
@@ -0,0 +1,117 @@
+package route
+
+import (
+	"fmt"
+	"regexp/syntax"
+
+	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	xds_matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/pkg/errors"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/errcode"
+)
+
+// defaultRegexMaxProgramSize is the maximum program size of the regular expressions accepted by the proxies by default
+const defaultRegexMaxProgramSize = 100
+
+// ApplyRouteRegexOptions applies the given options to the regular expressions of the route matches of the given route
+// configurations. The path regular expressions are anchored as configured, and the routes with regular expressions that
+// are invalid or exceed the maximum program size are removed, as the proxies would reject their route configurations.
+func ApplyRouteRegexOptions(routeConfigs []*xds_route.RouteConfiguration, options configv1alpha1.RouteRegexSpec) {
+	maxProgramSize := options.MaxProgramSize
+	if maxProgramSize == 0 {
+		maxProgramSize = defaultRegexMaxProgramSize
+	}
+
+	// Virtual hosts can be shared by route configurations, their routes must only be updated once
+	updated := make(map[*xds_route.VirtualHost]struct{})
+	for _, routeConfig := range routeConfigs {
+		for _, virtualHost := range routeConfig.VirtualHosts {
+			if _, ok := updated[virtualHost]; ok {
+				continue
+			}
+			updated[virtualHost] = struct{}{}
+
+			var routes []*xds_route.Route
+			for _, route := range virtualHost.Routes {
+				if err := applyRouteMatchRegexOptions(route.GetMatch(), options, maxProgramSize); err != nil {
+					log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrInvalidRouteRegex)).
+						Msgf("Error programming route %s of virtual host %s in route configuration %s, skipping it",
+							route.GetMatch().String(), virtualHost.Name, routeConfig.Name)
+					continue
+				}
+				routes = append(routes, route)
+			}
+			virtualHost.Routes = routes
+		}
+	}
+}
+
+// applyRouteMatchRegexOptions anchors the path regular expression of the given route match and sets the maximum
+// program size of its regular expressions, returning an error if one of them can't be programmed
+func applyRouteMatchRegexOptions(match *xds_route.RouteMatch, options configv1alpha1.RouteRegexSpec, maxProgramSize uint32) error {
+	if match == nil {
+		return nil
+	}
+
+	if pathRegex := match.GetSafeRegex(); pathRegex != nil {
+		pathRegex.Regex = anchorPathRegex(pathRegex.Regex, options.PathAnchoring)
+		if err := applyRegexMatcherOptions(pathRegex, options.MaxProgramSize, maxProgramSize); err != nil {
+			return errors.Wrap(err, "invalid path regex")
+		}
+	}
+
+	for _, header := range match.Headers {
+		headerRegex := header.GetSafeRegexMatch()
+		if headerRegex == nil {
+			continue
+		}
+		if err := applyRegexMatcherOptions(headerRegex, options.MaxProgramSize, maxProgramSize); err != nil {
+			return errors.Wrapf(err, "invalid regex for header %s", header.Name)
+		}
+	}
+	return nil
+}
+
+// applyRegexMatcherOptions sets the configured maximum program size of the given RE2 regex matcher, if any, after
+// checking its regular expression compiles within the effective maximum program size
+func applyRegexMatcherOptions(matcher *xds_matcher.RegexMatcher, configuredMaxProgramSize uint32, maxProgramSize uint32) error {
+	programSize, err := getRegexProgramSize(matcher.Regex)
+	if err != nil {
+		return err
+	}
+	if programSize > int(maxProgramSize) {
+		return errors.Errorf("regex %s has a program size of %d, above the maximum of %d", matcher.Regex, programSize, maxProgramSize)
+	}
+
+	if googleRE2 := matcher.GetGoogleRe2(); googleRE2 != nil && configuredMaxProgramSize != 0 {
+		googleRE2.MaxProgramSize = &wrappers.UInt32Value{Value: configuredMaxProgramSize}
+	}
+	return nil
+}
+
+// anchorPathRegex returns the given path regular expression anchored as configured. The proxies match path regular
+// expressions against the entire request paths, so they are only changed to match the beginning of the paths.
+func anchorPathRegex(regex string, anchoring configv1alpha1.RoutePathAnchoring) string {
+	if anchoring != configv1alpha1.RoutePathAnchoringPrefix || regex == constants.RegexMatchAll {
+		return regex
+	}
+	return fmt.Sprintf("(?:%s).*", regex)
+}
+
+// getRegexProgramSize returns an estimate of the RE2 program size of the given regular expression, the number of
+// instructions of its compiled program using the RE2 syntax, or an error if it does not compile
+func getRegexProgramSize(regex string) (int, error) {
+	re, err := syntax.Parse(regex, syntax.Perl)
+	if err != nil {
+		return 0, err
+	}
+	prog, err := syntax.Compile(re.Simplify())
+	if err != nil {
+		return 0, err
+	}
+	return len(prog.Inst), nil
+}
@@ -0,0 +1,140 @@
+package route
+
+import (
+	"testing"
+
+	mapset "github.com/deckarep/golang-set"
+	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	xds_matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	tassert "github.com/stretchr/testify/assert"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestApplyRouteRegexOptions(t *testing.T) {
+	newRoute := func(pathRegex string, headers map[string]string) *xds_route.Route {
+		return buildRoute(trafficpolicy.PathMatchRegex, pathRegex, "GET", headers, mapset.NewSet(tests.BookstoreV1DefaultWeightedCluster), 100, outboundRoute)
+	}
+
+	testCases := []struct {
+		name                   string
+		options                configv1alpha1.RouteRegexSpec
+		routes                 []*xds_route.Route
+		expectedPathRegexes    []string
+		expectedMaxProgramSize uint32
+	}{
+		{
+			name:                "valid regexes with the default options",
+			options:             configv1alpha1.RouteRegexSpec{},
+			routes:              []*xds_route.Route{newRoute("/books/[0-9]+", nil), newRoute(constants.RegexMatchAll, nil)},
+			expectedPathRegexes: []string{"/books/[0-9]+", constants.RegexMatchAll},
+		},
+		{
+			name:                "invalid path regex",
+			options:             configv1alpha1.RouteRegexSpec{},
+			routes:              []*xds_route.Route{newRoute("/books/[0-9+", nil), newRoute("/sell", nil)},
+			expectedPathRegexes: []string{"/sell"},
+		},
+		{
+			name:                "invalid header regex",
+			options:             configv1alpha1.RouteRegexSpec{},
+			routes:              []*xds_route.Route{newRoute("/buy", map[string]string{"user-agent": "*Mozilla"}), newRoute("/sell", nil)},
+			expectedPathRegexes: []string{"/sell"},
+		},
+		{
+			name:                "path regex above the default maximum program size",
+			options:             configv1alpha1.RouteRegexSpec{},
+			routes:              []*xds_route.Route{newRoute("/books/[a-z]{1,200}", nil), newRoute("/sell", nil)},
+			expectedPathRegexes: []string{"/sell"},
+		},
+		{
+			name:                   "path regex within the configured maximum program size",
+			options:                configv1alpha1.RouteRegexSpec{MaxProgramSize: 500},
+			routes:                 []*xds_route.Route{newRoute("/books/[a-z]{1,200}", nil)},
+			expectedPathRegexes:    []string{"/books/[a-z]{1,200}"},
+			expectedMaxProgramSize: 500,
+		},
+		{
+			name:                "prefix anchoring",
+			options:             configv1alpha1.RouteRegexSpec{PathAnchoring: configv1alpha1.RoutePathAnchoringPrefix},
+			routes:              []*xds_route.Route{newRoute("/books", nil), newRoute(constants.RegexMatchAll, nil)},
+			expectedPathRegexes: []string{"(?:/books).*", constants.RegexMatchAll},
+		},
+		{
+			name:                "full anchoring",
+			options:             configv1alpha1.RouteRegexSpec{PathAnchoring: configv1alpha1.RoutePathAnchoringFull},
+			routes:              []*xds_route.Route{newRoute("/books", nil)},
+			expectedPathRegexes: []string{"/books"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			virtualHost := &xds_route.VirtualHost{Name: "outbound_virtual-host|bookstore", Routes: tc.routes}
+			routeConfigs := []*xds_route.RouteConfiguration{
+				{Name: GetOutboundRouteConfigNameForPort(80), VirtualHosts: []*xds_route.VirtualHost{virtualHost}},
+				// The virtual host shared by the route configurations is only updated once
+				{Name: GetOutboundRouteConfigNameForPort(8080), VirtualHosts: []*xds_route.VirtualHost{virtualHost}},
+			}
+			ApplyRouteRegexOptions(routeConfigs, tc.options)
+
+			var pathRegexes []string
+			for _, route := range virtualHost.Routes {
+				pathRegex := route.GetMatch().GetSafeRegex()
+				pathRegexes = append(pathRegexes, pathRegex.Regex)
+				assert.Equal(tc.expectedMaxProgramSize, pathRegex.GetGoogleRe2().GetMaxProgramSize().GetValue())
+				for _, header := range route.GetMatch().Headers {
+					assert.Equal(tc.expectedMaxProgramSize, header.GetSafeRegexMatch().GetGoogleRe2().GetMaxProgramSize().GetValue())
+				}
+			}
+			assert.Equal(tc.expectedPathRegexes, pathRegexes)
+		})
+	}
+}
+
+func TestApplyRegexMatcherOptions(t *testing.T) {
+	assert := tassert.New(t)
+
+	matcher := &xds_matcher.RegexMatcher{
+		EngineType: &xds_matcher.RegexMatcher_GoogleRe2{GoogleRe2: &xds_matcher.RegexMatcher_GoogleRE2{}},
+		Regex:      "/books",
+	}
+	assert.Nil(applyRegexMatcherOptions(matcher, 0, defaultRegexMaxProgramSize))
+	assert.Nil(matcher.GetGoogleRe2().MaxProgramSize)
+
+	assert.Nil(applyRegexMatcherOptions(matcher, 200, 200))
+	assert.Equal(uint32(200), matcher.GetGoogleRe2().MaxProgramSize.GetValue())
+
+	matcher.Regex = "(/books"
+	assert.NotNil(applyRegexMatcherOptions(matcher, 200, 200))
+}
+
+func TestGetRegexProgramSize(t *testing.T) {
+	testCases := []struct {
+		regex       string
+		expectedErr bool
+	}{
+		{regex: constants.RegexMatchAll},
+		{regex: "/books/[0-9]+"},
+		{regex: "/books/(?!new)", expectedErr: true},
+		{regex: "/books/[0-9+", expectedErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.regex, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			programSize, err := getRegexProgramSize(tc.regex)
+			assert.Equal(tc.expectedErr, err != nil)
+			if !tc.expectedErr {
+				assert.Greater(programSize, 0)
+				assert.LessOrEqual(programSize, defaultRegexMaxProgramSize)
+			}
+		})
+	}
+}
@@ -2,17 +2,23 @@ package route
 
 import (
 	"fmt"
+	"math"
+	"reflect"
 	"sort"
+	"strconv"
+	"strings"
 
 	mapset "github.com/deckarep/golang-set"
 	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	xds_matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/wrappers"
 
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/rbac"
 	"github.com/openservicemesh/osm/pkg/errcode"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
@@ -33,7 +39,7 @@ const (
 	// InboundRouteConfigName is the name of the inbound mesh RDS route configuration
 	InboundRouteConfigName = "rds-inbound"
 
-	// OutboundRouteConfigName is the name of the outbound mesh RDS route configuration
+	// OutboundRouteConfigName is the prefix for the names of the outbound mesh RDS route configurations
 	OutboundRouteConfigName = "rds-outbound"
 
 	// IngressRouteConfigName is the name of the ingress RDS route configuration
@@ -64,8 +70,12 @@ const (
 	authorityHeaderKey = ":authority"
 )
 
-// BuildRouteConfiguration constructs the Envoy constructs ([]*xds_route.RouteConfiguration) for implementing inbound and outbound routes
-func BuildRouteConfiguration(inbound []*trafficpolicy.InboundTrafficPolicy, outbound []*trafficpolicy.OutboundTrafficPolicy, proxy *envoy.Proxy, cfg configurator.Configurator) []*xds_route.RouteConfiguration {
+// idempotentHTTPMethods are the HTTP methods whose requests can safely be sent more than once
+var idempotentHTTPMethods = []string{"GET", "HEAD", "OPTIONS", "TRACE", "PUT", "DELETE"}
+
+// BuildRouteConfiguration constructs the Envoy constructs ([]*xds_route.RouteConfiguration) for implementing inbound and outbound routes,
+// with the principals of the inbound RBAC policies built by the given compactor
+func BuildRouteConfiguration(inbound []*trafficpolicy.InboundTrafficPolicy, outbound []*trafficpolicy.OutboundTrafficPolicy, proxy *envoy.Proxy, cfg configurator.Configurator, principalCompactor *rbac.PrincipalCompactor) []*xds_route.RouteConfiguration {
 	var routeConfiguration []*xds_route.RouteConfiguration
 
 	// For both Inbound and Outbound routes, we will always generate the route resource stubs and send them even when empty,
@@ -74,44 +84,119 @@ func BuildRouteConfiguration(inbound []*trafficpolicy.InboundTrafficPolicy, outb
 	inboundRouteConfig := NewRouteConfigurationStub(InboundRouteConfigName)
 	for _, in := range inbound {
 		virtualHost := buildVirtualHostStub(inboundVirtualHost, in.Name, in.Hostnames)
-		virtualHost.Routes = buildInboundRoutes(in.Rules)
+		virtualHost.Routes = buildInboundRoutes(in.Rules, cfg.GetRBACAuditConfig().ShadowMode, principalCompactor)
+		virtualHost.Cors = buildCORSPolicy(in.CORS)
+		applyVirtualHostHeaderMutations(virtualHost, in.Headers)
 		inboundRouteConfig.VirtualHosts = append(inboundRouteConfig.VirtualHosts, virtualHost)
 	}
 
 	if featureFlags := cfg.GetFeatureFlags(); featureFlags.EnableWASMStats {
-		for k, v := range proxy.StatsHeaders() {
+		statsHeaders := proxy.StatsHeaders()
+		for _, k := range sortedKeys(statsHeaders) {
 			inboundRouteConfig.ResponseHeadersToAdd = append(inboundRouteConfig.ResponseHeadersToAdd, &core.HeaderValueOption{
 				Header: &core.HeaderValue{
 					Key:   k,
-					Value: v,
+					Value: statsHeaders[k],
 				},
 			})
 		}
 	}
 
 	routeConfiguration = append(routeConfiguration, inboundRouteConfig)
-	outboundRouteConfig := NewRouteConfigurationStub(OutboundRouteConfigName)
+	routeConfiguration = append(routeConfiguration, buildOutboundRouteConfigurations(outbound)...)
+
+	for _, routeConfig := range routeConfiguration {
+		resolveVirtualHostDomainConflicts(routeConfig)
+	}
+
+	return routeConfiguration
+}
+
+// buildOutboundRouteConfigurations constructs the outbound route configurations of the given outbound policies, one
+// per destination port. The virtual host of a policy is part of the route configurations of the ports its hostnames
+// are qualified with, so that the routes of the hosts on a port are updated independently of the other ports.
+// The policies without hostnames qualified with a port are part of all the route configurations.
+func buildOutboundRouteConfigurations(outbound []*trafficpolicy.OutboundTrafficPolicy) []*xds_route.RouteConfiguration {
+	routeConfigsByPort := make(map[int]*xds_route.RouteConfiguration)
+	var portlessVirtualHosts []*xds_route.VirtualHost
 
 	for _, out := range outbound {
 		virtualHost := buildVirtualHostStub(outboundVirtualHost, out.Name, out.Hostnames)
-		virtualHost.Routes = buildOutboundRoutes(out.Routes)
-		outboundRouteConfig.VirtualHosts = append(outboundRouteConfig.VirtualHosts, virtualHost)
+		virtualHost.Routes = buildOutboundRoutes(out.Routes, out.RetryPolicy)
+		applyVirtualHostRetryPolicy(virtualHost, out.RetryPolicy)
+
+		ports := getHostnamePorts(out.Hostnames)
+		if len(ports) == 0 {
+			portlessVirtualHosts = append(portlessVirtualHosts, virtualHost)
+			continue
+		}
+		for _, port := range ports {
+			routeConfig, ok := routeConfigsByPort[port]
+			if !ok {
+				routeConfig = NewRouteConfigurationStub(GetOutboundRouteConfigNameForPort(port))
+				routeConfigsByPort[port] = routeConfig
+			}
+			routeConfig.VirtualHosts = append(routeConfig.VirtualHosts, virtualHost)
+		}
 	}
-	routeConfiguration = append(routeConfiguration, outboundRouteConfig)
 
-	return routeConfiguration
+	var ports []int
+	for port := range routeConfigsByPort {
+		ports = append(ports, port)
+	}
+	sort.Ints(ports)
+
+	var routeConfigs []*xds_route.RouteConfiguration
+	for _, port := range ports {
+		routeConfig := routeConfigsByPort[port]
+		routeConfig.VirtualHosts = append(routeConfig.VirtualHosts, portlessVirtualHosts...)
+		routeConfigs = append(routeConfigs, routeConfig)
+	}
+	return routeConfigs
+}
+
+// getHostnamePorts returns the sorted ports the given hostnames are qualified with, in the form <host>:<port>
+func getHostnamePorts(hostnames []string) []int {
+	portSet := make(map[int]struct{})
+	for _, hostname := range hostnames {
+		i := strings.LastIndex(hostname, ":")
+		if i == -1 {
+			continue
+		}
+		if port, err := strconv.ParseUint(hostname[i+1:], 10, 16); err == nil {
+			portSet[int(port)] = struct{}{}
+		}
+	}
+
+	var ports []int
+	for port := range portSet {
+		ports = append(ports, port)
+	}
+	sort.Ints(ports)
+	return ports
 }
 
-// BuildIngressConfiguration constructs the Envoy constructs ([]*xds_route.RouteConfiguration) for implementing ingress routes
-func BuildIngressConfiguration(ingress []*trafficpolicy.InboundTrafficPolicy) *xds_route.RouteConfiguration {
+// BuildIngressConfiguration constructs the Envoy constructs ([]*xds_route.RouteConfiguration) for implementing ingress routes,
+// with the principals of the ingress clients built by the given compactor
+func BuildIngressConfiguration(ingress []*trafficpolicy.InboundTrafficPolicy, principalCompactor *rbac.PrincipalCompactor) *xds_route.RouteConfiguration {
 	if len(ingress) == 0 {
 		return nil
 	}
 
 	ingressRouteConfig := NewRouteConfigurationStub(IngressRouteConfigName)
+	// Header mutations of routes take precedence over the ones of virtual hosts
+	ingressRouteConfig.MostSpecificHeaderMutationsWins = true
 	for _, in := range ingress {
 		virtualHost := buildVirtualHostStub(ingressVirtualHost, in.Name, in.Hostnames)
-		virtualHost.Routes = buildInboundRoutes(in.Rules)
+		virtualHost.Routes = buildInboundRoutes(in.Rules, false, principalCompactor)
+		virtualHost.Cors = buildCORSPolicy(in.CORS)
+		applyVirtualHostHeaderMutations(virtualHost, in.Headers)
+		// The backends are not exposed without the hardening of their security policy
+		if err := applyIngressSecurityPolicy(virtualHost, in.Security); err != nil {
+			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrMarshallingXDSResource)).
+				Msgf("Error applying the security policy of ingress virtual host %s, skipping virtual host", virtualHost.Name)
+			continue
+		}
 		ingressRouteConfig.VirtualHosts = append(ingressRouteConfig.VirtualHosts, virtualHost)
 	}
 
@@ -160,8 +245,35 @@ func buildVirtualHostStub(namePrefix string, host string, domains []string) *xds
 	return &virtualHost
 }
 
-// buildInboundRoutes takes a route information from the given inbound traffic policy and returns a list of xds routes
-func buildInboundRoutes(rules []*trafficpolicy.Rule) []*xds_route.Route {
+// buildCORSPolicy returns the Envoy CORS policy of a virtual host for the given CORS policy, nil if unset
+func buildCORSPolicy(cors *trafficpolicy.CORSPolicy) *xds_route.CorsPolicy {
+	if cors == nil {
+		return nil
+	}
+
+	corsPolicy := &xds_route.CorsPolicy{
+		AllowMethods:     strings.Join(cors.AllowMethods, ","),
+		AllowHeaders:     strings.Join(cors.AllowHeaders, ","),
+		ExposeHeaders:    strings.Join(cors.ExposeHeaders, ","),
+		AllowCredentials: &wrappers.BoolValue{Value: cors.AllowCredentials},
+	}
+	// Envoy allows any origin when an origin matches '*'
+	for _, origin := range cors.AllowOrigins {
+		corsPolicy.AllowOriginStringMatch = append(corsPolicy.AllowOriginStringMatch, &xds_matcher.StringMatcher{
+			MatchPattern: &xds_matcher.StringMatcher_Exact{Exact: origin},
+		})
+	}
+	if cors.MaxAge > 0 {
+		corsPolicy.MaxAge = strconv.FormatInt(int64(math.Ceil(cors.MaxAge.Seconds())), 10)
+	}
+
+	return corsPolicy
+}
+
+// buildInboundRoutes takes a route information from the given inbound traffic policy and returns a list of xds routes,
+// whose RBAC policies are evaluated in shadow mode if shadowMode is set and have their principals built by the given
+// compactor
+func buildInboundRoutes(rules []*trafficpolicy.Rule, shadowMode bool, principalCompactor *rbac.PrincipalCompactor) []*xds_route.Route {
 	var routes []*xds_route.Route
 	for _, rule := range rules {
 		// For a given route path, sanitize the methods in case there
@@ -170,7 +282,7 @@ func buildInboundRoutes(rules []*trafficpolicy.Rule) []*xds_route.Route {
 
 		// Create an RBAC policy derived from 'trafficpolicy.Rule'
 		// Each route is associated with an RBAC policy
-		rbacPolicyForRoute, err := buildInboundRBACFilterForRule(rule)
+		rbacPolicyForRoute, err := buildInboundRBACFilterForRule(rule, shadowMode, principalCompactor)
 		if err != nil {
 			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrBuildingRBACPolicyForRoute)).
 				Msgf("Error building RBAC policy for rule [%v], skipping route addition", rule)
@@ -181,19 +293,146 @@ func buildInboundRoutes(rules []*trafficpolicy.Rule) []*xds_route.Route {
 		for _, method := range allowedMethods {
 			route := buildRoute(rule.Route.HTTPRouteMatch.PathMatchType, rule.Route.HTTPRouteMatch.Path, method, rule.Route.HTTPRouteMatch.Headers, rule.Route.WeightedClusters, 100, inboundRoute)
 			route.TypedPerFilterConfig = rbacPolicyForRoute
+			applyRouteRewrite(route, rule.Route.Rewrite)
+			applyRouteHeaderMutations(route, rule.Route.Headers)
 			routes = append(routes, route)
 		}
 	}
 	return routes
 }
 
-func buildOutboundRoutes(outRoutes []*trafficpolicy.RouteWeightedClusters) []*xds_route.Route {
+// buildOutboundRoutes takes the routes of an outbound traffic policy and returns a list of xds routes. Since Envoy
+// uses the first route matching a request, the wildcard routes are built after the routes of specific matches. The
+// requests of idempotent methods are hedged on per-try timeout if the given retry policy of the upstream service
+// allows.
+func buildOutboundRoutes(outRoutes []*trafficpolicy.RouteWeightedClusters, retryPolicy *trafficpolicy.RetryPolicy) []*xds_route.Route {
+	hedge := retryPolicy != nil && retryPolicy.HedgeOnPerTryTimeout
 	var routes []*xds_route.Route
+	var wildcardRoutes []*xds_route.Route
 	for _, outRoute := range outRoutes {
-		emptyHeaders := map[string]string{}
-		routes = append(routes, buildRoute(trafficpolicy.PathMatchRegex, constants.RegexMatchAll, constants.WildcardHTTPMethod, emptyHeaders, outRoute.WeightedClusters, outRoute.TotalClustersWeight(), outboundRoute))
+		if reflect.DeepEqual(outRoute.HTTPRouteMatch, trafficpolicy.WildCardRouteMatch) {
+			wildcardRoutes = append(wildcardRoutes, buildOutboundMethodRoutes(outRoute, trafficpolicy.PathMatchRegex, constants.RegexMatchAll, constants.WildcardHTTPMethod, nil, hedge)...)
+			continue
+		}
+
+		// Each HTTP method corresponds to a separate route
+		for _, method := range sanitizeHTTPMethods(outRoute.HTTPRouteMatch.Methods) {
+			routes = append(routes, buildOutboundMethodRoutes(outRoute, outRoute.HTTPRouteMatch.PathMatchType, outRoute.HTTPRouteMatch.Path, method, outRoute.HTTPRouteMatch.Headers, hedge)...)
+		}
 	}
-	return routes
+	return append(routes, wildcardRoutes...)
+}
+
+// buildOutboundMethodRoutes returns the xds routes of the requests of the given HTTP method matching the given path
+// and headers for the given outbound route. If hedge is set, only the requests of idempotent methods, which can
+// safely be sent more than once, are hedged on per-try timeout: the route of all the methods is then preceded by a
+// hedged route of the idempotent methods.
+func buildOutboundMethodRoutes(outRoute *trafficpolicy.RouteWeightedClusters, pathMatchType trafficpolicy.PathMatchType, path string, method string,
+	headers map[string]string, hedge bool) []*xds_route.Route {
+	build := func(method string) *xds_route.Route {
+		route := buildRoute(pathMatchType, path, method, headers, outRoute.WeightedClusters, outRoute.TotalClustersWeight(), outboundRoute)
+		applyRouteRewrite(route, outRoute.Rewrite)
+		applyRouteHeaderMutations(route, outRoute.Headers)
+		return route
+	}
+
+	route := build(method)
+	if !hedge {
+		return []*xds_route.Route{route}
+	}
+	if method == constants.WildcardHTTPMethod {
+		hedgedRoute := build(strings.Join(idempotentHTTPMethods, "|"))
+		hedgedRoute.GetRoute().HedgePolicy = &xds_route.HedgePolicy{HedgeOnPerTryTimeout: true}
+		return []*xds_route.Route{hedgedRoute, route}
+	}
+	for _, idempotentMethod := range idempotentHTTPMethods {
+		if method == idempotentMethod {
+			route.GetRoute().HedgePolicy = &xds_route.HedgePolicy{HedgeOnPerTryTimeout: true}
+		}
+	}
+	return []*xds_route.Route{route}
+}
+
+// applyVirtualHostRetryPolicy configures the routes of the given virtual host to retry the requests as defined by the
+// given retry policy of its upstream service
+func applyVirtualHostRetryPolicy(virtualHost *xds_route.VirtualHost, retryPolicy *trafficpolicy.RetryPolicy) {
+	if retryPolicy == nil {
+		return
+	}
+
+	virtualHost.RetryPolicy = &xds_route.RetryPolicy{
+		RetryOn:    retryPolicy.RetryOn,
+		NumRetries: &wrappers.UInt32Value{Value: retryPolicy.NumRetries},
+	}
+	if retryPolicy.PerTryTimeout > 0 {
+		virtualHost.RetryPolicy.PerTryTimeout = ptypes.DurationProto(retryPolicy.PerTryTimeout)
+	}
+}
+
+// applyRouteRewrite configures the given route to rewrite the requests it matches before forwarding them
+func applyRouteRewrite(route *xds_route.Route, rewrite *trafficpolicy.HTTPRouteRewrite) {
+	if rewrite == nil {
+		return
+	}
+
+	action := route.GetRoute()
+	action.PrefixRewrite = rewrite.PrefixRewrite
+	if rewrite.RegexRewritePattern != "" {
+		action.RegexRewrite = &xds_matcher.RegexMatchAndSubstitute{
+			Pattern: &xds_matcher.RegexMatcher{
+				EngineType: &xds_matcher.RegexMatcher_GoogleRe2{GoogleRe2: &xds_matcher.RegexMatcher_GoogleRE2{}},
+				Regex:      rewrite.RegexRewritePattern,
+			},
+			Substitution: rewrite.RegexRewriteSubstitution,
+		}
+	}
+
+	if rewrite.HostRewrite != "" {
+		action.HostRewriteSpecifier = &xds_route.RouteAction_HostRewriteLiteral{HostRewriteLiteral: rewrite.HostRewrite}
+	} else if rewrite.AutoHostRewrite {
+		action.HostRewriteSpecifier = &xds_route.RouteAction_AutoHostRewrite{AutoHostRewrite: &wrappers.BoolValue{Value: true}}
+	}
+}
+
+// applyVirtualHostHeaderMutations configures the given virtual host to add and remove the given headers of the requests
+// and responses of all its routes
+func applyVirtualHostHeaderMutations(virtualHost *xds_route.VirtualHost, headers *trafficpolicy.HTTPHeaderMutations) {
+	if headers == nil {
+		return
+	}
+
+	virtualHost.RequestHeadersToAdd = buildHeaderValueOptions(headers.RequestHeadersToAdd)
+	virtualHost.RequestHeadersToRemove = headers.RequestHeadersToRemove
+	virtualHost.ResponseHeadersToAdd = buildHeaderValueOptions(headers.ResponseHeadersToAdd)
+	virtualHost.ResponseHeadersToRemove = headers.ResponseHeadersToRemove
+}
+
+// applyRouteHeaderMutations configures the given route to add and remove the given headers of the requests and
+// responses it matches
+func applyRouteHeaderMutations(route *xds_route.Route, headers *trafficpolicy.HTTPHeaderMutations) {
+	if headers == nil {
+		return
+	}
+
+	route.RequestHeadersToAdd = buildHeaderValueOptions(headers.RequestHeadersToAdd)
+	route.RequestHeadersToRemove = headers.RequestHeadersToRemove
+	route.ResponseHeadersToAdd = buildHeaderValueOptions(headers.ResponseHeadersToAdd)
+	route.ResponseHeadersToRemove = headers.ResponseHeadersToRemove
+}
+
+// buildHeaderValueOptions returns the header value options replacing the existing values of the given headers
+func buildHeaderValueOptions(headers []trafficpolicy.HTTPHeaderValue) []*core.HeaderValueOption {
+	var headerValueOptions []*core.HeaderValueOption
+	for _, header := range headers {
+		headerValueOptions = append(headerValueOptions, &core.HeaderValueOption{
+			Header: &core.HeaderValue{
+				Key:   header.Name,
+				Value: header.Value,
+			},
+			Append: &wrappers.BoolValue{Value: false},
+		})
+	}
+	return headerValueOptions
 }
 
 func buildEgressRoutes(routingRules []*trafficpolicy.EgressHTTPRoutingRule) []*xds_route.Route {
@@ -337,11 +576,12 @@ func getHeadersForRoute(method string, headersMap map[string]string) []*xds_rout
 	}
 
 	// add all other custom headers
-	for headerKey, headerValue := range headersMap {
+	for _, headerKey := range sortedKeys(headersMap) {
 		// omit the host header as this is configured above
 		if headerKey == httpHostHeaderKey {
 			continue
 		}
+		headerValue := headersMap[headerKey]
 		header := xds_route.HeaderMatcher{
 			Name: headerKey,
 			HeaderMatchSpecifier: &xds_route.HeaderMatcher_SafeRegexMatch{
@@ -356,6 +596,17 @@ func getHeadersForRoute(method string, headersMap map[string]string) []*xds_rout
 	return headers
 }
 
+// sortedKeys returns the keys of the given map in sorted order, so that the resources built from the map are the same
+// regardless of the map's iteration order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func getRegexForMethod(httpMethod string) string {
 	methodRegex := httpMethod
 	if httpMethod == constants.WildcardHTTPMethod {
@@ -364,6 +615,11 @@ func getRegexForMethod(httpMethod string) string {
 	return methodRegex
 }
 
+// GetOutboundRouteConfigNameForPort returns the name of the outbound route configuration of the given destination port
+func GetOutboundRouteConfigNameForPort(port int) string {
+	return fmt.Sprintf("%s.%d", OutboundRouteConfigName, port)
+}
+
 // GetEgressRouteConfigNameForPort returns the Egress route configuration object's name given the port it is targeted to
 func GetEgressRouteConfigNameForPort(port int) string {
 	return fmt.Sprintf("%s.%d", egressRouteConfigNamePrefix, port)
@@ -3,8 +3,10 @@ package route
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	mapset "github.com/deckarep/golang-set"
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	xds_matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
 	"github.com/golang/mock/gomock"
@@ -15,7 +17,9 @@ import (
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/rbac"
 	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/logger"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/tests"
 	"github.com/openservicemesh/osm/pkg/trafficpolicy"
@@ -70,13 +74,13 @@ func TestBuildRouteConfiguration(t *testing.T) {
 			name:                   "no policies provided",
 			inbound:                []*trafficpolicy.InboundTrafficPolicy{},
 			outbound:               []*trafficpolicy.OutboundTrafficPolicy{},
-			expectedRouteConfigLen: 2,
+			expectedRouteConfigLen: 1,
 		},
 		{
 			name:                   "inbound policy provided",
 			inbound:                []*trafficpolicy.InboundTrafficPolicy{testInbound},
 			outbound:               []*trafficpolicy.OutboundTrafficPolicy{},
-			expectedRouteConfigLen: 2,
+			expectedRouteConfigLen: 1,
 		},
 		{
 			name:                   "outbound policy provided",
@@ -96,10 +100,11 @@ func TestBuildRouteConfiguration(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			assert := tassert.New(t)
 
+			mockCfg.EXPECT().GetRBACAuditConfig().Return(v1alpha1.RBACAuditSpec{}).AnyTimes()
 			mockCfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{
 				EnableWASMStats: false,
 			}).Times(1)
-			actual := BuildRouteConfiguration(tc.inbound, tc.outbound, nil, mockCfg)
+			actual := BuildRouteConfiguration(tc.inbound, tc.outbound, nil, mockCfg, rbac.NewPrincipalCompactor(""))
 			assert.Equal(tc.expectedRouteConfigLen, len(actual))
 		})
 	}
@@ -123,16 +128,49 @@ func TestBuildRouteConfiguration(t *testing.T) {
 
 	for _, tc := range statsWASMTestCases {
 		t.Run(tc.name, func(t *testing.T) {
+			mockCfg.EXPECT().GetRBACAuditConfig().Return(v1alpha1.RBACAuditSpec{}).AnyTimes()
 			mockCfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{
 				EnableWASMStats: tc.wasmEnabled,
 			}).Times(1)
-			actual := BuildRouteConfiguration([]*trafficpolicy.InboundTrafficPolicy{testInbound}, nil, &envoy.Proxy{}, mockCfg)
-			tassert.Len(t, actual, 2)
+			actual := BuildRouteConfiguration([]*trafficpolicy.InboundTrafficPolicy{testInbound}, nil, &envoy.Proxy{}, mockCfg, rbac.NewPrincipalCompactor(""))
+			tassert.Len(t, actual, 1)
 			tassert.Len(t, actual[0].ResponseHeadersToAdd, tc.expectedResponseHeaderLen)
 		})
 	}
 }
 
+func BenchmarkBuildRouteConfiguration(b *testing.B) {
+	// Keep the logging of the builders out of the measurements
+	if err := logger.SetLogLevel("error"); err != nil {
+		b.Fatal(err)
+	}
+
+	for _, services := range tests.BenchmarkTopologyServices {
+		b.Run(fmt.Sprintf("%d services", services), func(b *testing.B) {
+			mockCtrl := gomock.NewController(b)
+			mockCfg := configurator.NewMockConfigurator(mockCtrl)
+			mockCfg.EXPECT().GetRBACAuditConfig().Return(v1alpha1.RBACAuditSpec{}).AnyTimes()
+			mockCfg.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{}).AnyTimes()
+
+			topology := tests.NewTopologyBuilder().
+				WithNamespaces(tests.BenchmarkTopologyNamespaces).
+				WithServicesPerNamespace(services / tests.BenchmarkTopologyNamespaces).
+				WithTrafficTargets(10).
+				Build()
+			proxyIdentity := identity.ServiceIdentity("svc-0.ns-0.cluster.local")
+			inbound := topology.InboundTrafficPolicies(proxyIdentity)
+			outbound := topology.OutboundTrafficPolicies()
+			principalCompactor := rbac.NewPrincipalCompactor("")
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				BuildRouteConfiguration(inbound, outbound, nil, mockCfg, principalCompactor)
+			}
+		})
+	}
+}
+
 func TestBuildIngressRouteConfiguration(t *testing.T) {
 	testCases := []struct {
 		name                      string
@@ -211,7 +249,7 @@ func TestBuildIngressRouteConfiguration(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			assert := tassert.New(t)
-			actual := BuildIngressConfiguration(tc.ingressPolicies)
+			actual := BuildIngressConfiguration(tc.ingressPolicies, rbac.NewPrincipalCompactor(""))
 
 			if tc.expectedRouteConfigFields == nil {
 				assert.Nil(actual)
@@ -327,7 +365,7 @@ func TestBuildInboundRoutes(t *testing.T) {
 
 	for i, tc := range testCases {
 		t.Run(fmt.Sprintf("Testing test case %d: %s", i, tc.name), func(t *testing.T) {
-			actual := buildInboundRoutes(tc.inputRules)
+			actual := buildInboundRoutes(tc.inputRules, false, rbac.NewPrincipalCompactor(""))
 			tc.expectFunc(tassert.New(t), actual)
 		})
 	}
@@ -340,7 +378,15 @@ func TestBuildOutboundRoutes(t *testing.T) {
 		ClusterName: "testCluster",
 		Weight:      100,
 	}
+	canaryWeightedCluster := service.WeightedCluster{
+		ClusterName: "canaryCluster",
+		Weight:      100,
+	}
 	input := []*trafficpolicy.RouteWeightedClusters{
+		{
+			HTTPRouteMatch:   trafficpolicy.WildCardRouteMatch,
+			WeightedClusters: mapset.NewSet(testWeightedCluster),
+		},
 		{
 			HTTPRouteMatch: trafficpolicy.HTTPRouteMatch{
 				Path:          "/hello",
@@ -348,17 +394,107 @@ func TestBuildOutboundRoutes(t *testing.T) {
 				Methods:       []string{"GET"},
 				Headers:       map[string]string{"hello": "world"},
 			},
-			WeightedClusters: mapset.NewSet(testWeightedCluster),
+			WeightedClusters: mapset.NewSet(canaryWeightedCluster),
 		},
 	}
-	actual := buildOutboundRoutes(input)
-	assert.Equal(1, len(actual))
-	assert.Equal(".*", actual[0].GetMatch().GetSafeRegex().Regex)
-	assert.Equal(".*", actual[0].GetMatch().GetHeaders()[0].GetSafeRegexMatch().Regex)
+	actual := buildOutboundRoutes(input, nil)
+	assert.Equal(2, len(actual))
+
+	// The route of the specific match precedes the wildcard route
+	assert.Equal("/hello", actual[0].GetMatch().GetSafeRegex().Regex)
+	assert.Len(actual[0].GetMatch().GetHeaders(), 2)
+	assert.Equal("GET", actual[0].GetMatch().GetHeaders()[0].GetSafeRegexMatch().Regex)
+	assert.Equal("hello", actual[0].GetMatch().GetHeaders()[1].Name)
+	assert.Equal("world", actual[0].GetMatch().GetHeaders()[1].GetSafeRegexMatch().Regex)
 	assert.Equal(1, len(actual[0].GetRoute().GetWeightedClusters().Clusters))
-	assert.Equal(uint32(100), actual[0].GetRoute().GetWeightedClusters().TotalWeight.GetValue())
-	assert.Equal("testCluster", actual[0].GetRoute().GetWeightedClusters().Clusters[0].Name)
-	assert.Equal(uint32(100), actual[0].GetRoute().GetWeightedClusters().Clusters[0].Weight.GetValue())
+	assert.Equal("canaryCluster", actual[0].GetRoute().GetWeightedClusters().Clusters[0].Name)
+
+	assert.Equal(".*", actual[1].GetMatch().GetSafeRegex().Regex)
+	assert.Len(actual[1].GetMatch().GetHeaders(), 1)
+	assert.Equal(".*", actual[1].GetMatch().GetHeaders()[0].GetSafeRegexMatch().Regex)
+	assert.Equal(1, len(actual[1].GetRoute().GetWeightedClusters().Clusters))
+	assert.Equal(uint32(100), actual[1].GetRoute().GetWeightedClusters().TotalWeight.GetValue())
+	assert.Equal("testCluster", actual[1].GetRoute().GetWeightedClusters().Clusters[0].Name)
+	assert.Equal(uint32(100), actual[1].GetRoute().GetWeightedClusters().Clusters[0].Weight.GetValue())
+}
+
+func TestBuildOutboundRoutesHedging(t *testing.T) {
+	newRoute := func(methods ...string) *trafficpolicy.RouteWeightedClusters {
+		return &trafficpolicy.RouteWeightedClusters{
+			HTTPRouteMatch: trafficpolicy.HTTPRouteMatch{
+				Path:          "/books",
+				PathMatchType: trafficpolicy.PathMatchExact,
+				Methods:       methods,
+			},
+			WeightedClusters: mapset.NewSet(tests.BookstoreV1DefaultWeightedCluster),
+		}
+	}
+	hedgingRetryPolicy := &trafficpolicy.RetryPolicy{
+		RetryOn:              "5xx",
+		NumRetries:           1,
+		PerTryTimeout:        250 * time.Millisecond,
+		HedgeOnPerTryTimeout: true,
+	}
+
+	testCases := []struct {
+		name            string
+		outRoutes       []*trafficpolicy.RouteWeightedClusters
+		retryPolicy     *trafficpolicy.RetryPolicy
+		expectedMethods []string
+		expectedHedged  []bool
+	}{
+		{
+			name:            "idempotent route without hedging",
+			outRoutes:       []*trafficpolicy.RouteWeightedClusters{newRoute("GET")},
+			retryPolicy:     &trafficpolicy.RetryPolicy{RetryOn: "5xx", NumRetries: 1, PerTryTimeout: 250 * time.Millisecond},
+			expectedMethods: []string{"GET"},
+			expectedHedged:  []bool{false},
+		},
+		{
+			name:            "idempotent routes with hedging",
+			outRoutes:       []*trafficpolicy.RouteWeightedClusters{newRoute("GET", "PUT")},
+			retryPolicy:     hedgingRetryPolicy,
+			expectedMethods: []string{"GET", "PUT"},
+			expectedHedged:  []bool{true, true},
+		},
+		{
+			name:            "non-idempotent routes with hedging",
+			outRoutes:       []*trafficpolicy.RouteWeightedClusters{newRoute("POST", "PATCH")},
+			retryPolicy:     hedgingRetryPolicy,
+			expectedMethods: []string{"POST", "PATCH"},
+			expectedHedged:  []bool{false, false},
+		},
+		{
+			name:            "route of all the methods with hedging",
+			outRoutes:       []*trafficpolicy.RouteWeightedClusters{newRoute("*")},
+			retryPolicy:     hedgingRetryPolicy,
+			expectedMethods: []string{"GET|HEAD|OPTIONS|TRACE|PUT|DELETE", ".*"},
+			expectedHedged:  []bool{true, false},
+		},
+		{
+			name: "wildcard route with hedging",
+			outRoutes: []*trafficpolicy.RouteWeightedClusters{{
+				HTTPRouteMatch:   trafficpolicy.WildCardRouteMatch,
+				WeightedClusters: mapset.NewSet(tests.BookstoreV1DefaultWeightedCluster),
+			}},
+			retryPolicy:     hedgingRetryPolicy,
+			expectedMethods: []string{"GET|HEAD|OPTIONS|TRACE|PUT|DELETE", ".*"},
+			expectedHedged:  []bool{true, false},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			routes := buildOutboundRoutes(tc.outRoutes, tc.retryPolicy)
+			assert.Len(routes, len(tc.expectedMethods))
+			for i, route := range routes {
+				assert.Equal(tc.expectedMethods[i], route.GetMatch().GetHeaders()[0].GetSafeRegexMatch().Regex)
+				assert.Equal(tc.expectedHedged[i], route.GetRoute().GetHedgePolicy().GetHedgeOnPerTryTimeout())
+			}
+		})
+	}
 }
 
 func TestBuildRoute(t *testing.T) {
@@ -1217,6 +1353,105 @@ func TestBuildEgressRouteConfiguration(t *testing.T) {
 	}
 }
 
+func TestBuildOutboundRouteConfigurations(t *testing.T) {
+	assert := tassert.New(t)
+
+	outbound := []*trafficpolicy.OutboundTrafficPolicy{
+		{
+			Name:      "bookstore-v1.default.svc.cluster.local:9090",
+			Hostnames: []string{"bookstore-v1.default", "bookstore-v1.default:9090"},
+		},
+		{
+			Name:      "bookstore-v1.default.svc.cluster.local:8080",
+			Hostnames: []string{"bookstore-v1.default", "bookstore-v1.default:8080"},
+		},
+		{
+			Name:      "bookstore-v2.default.svc.cluster.local",
+			Hostnames: []string{"bookstore-v2.default", "bookstore-v2.default:8080", "bookstore-v2.default:9090"},
+		},
+		{
+			Name:      "bookstore-apex.default.svc.cluster.local",
+			Hostnames: []string{"bookstore-apex.default"},
+		},
+	}
+
+	routeConfigs := buildOutboundRouteConfigurations(outbound)
+	assert.Len(routeConfigs, 2)
+
+	expected := []struct {
+		name         string
+		virtualHosts []string
+	}{
+		{
+			name: "rds-outbound.8080",
+			virtualHosts: []string{
+				"outbound_virtual-host|bookstore-v1.default.svc.cluster.local:8080",
+				"outbound_virtual-host|bookstore-v2.default.svc.cluster.local",
+				"outbound_virtual-host|bookstore-apex.default.svc.cluster.local",
+			},
+		},
+		{
+			name: "rds-outbound.9090",
+			virtualHosts: []string{
+				"outbound_virtual-host|bookstore-v1.default.svc.cluster.local:9090",
+				"outbound_virtual-host|bookstore-v2.default.svc.cluster.local",
+				"outbound_virtual-host|bookstore-apex.default.svc.cluster.local",
+			},
+		},
+	}
+	for i, routeConfig := range routeConfigs {
+		assert.Equal(expected[i].name, routeConfig.Name)
+		var virtualHosts []string
+		for _, virtualHost := range routeConfig.VirtualHosts {
+			virtualHosts = append(virtualHosts, virtualHost.Name)
+		}
+		assert.Equal(expected[i].virtualHosts, virtualHosts)
+	}
+}
+
+func TestGetHostnamePorts(t *testing.T) {
+	testCases := []struct {
+		name          string
+		hostnames     []string
+		expectedPorts []int
+	}{
+		{
+			name:          "no hostnames",
+			hostnames:     nil,
+			expectedPorts: nil,
+		},
+		{
+			name:          "hostnames without ports",
+			hostnames:     []string{"bookstore-v1", "bookstore-v1.default"},
+			expectedPorts: nil,
+		},
+		{
+			name:          "hostnames with ports",
+			hostnames:     []string{"bookstore-v1", "bookstore-v1:9090", "bookstore-v1.default:8080", "bookstore-v1.default:9090"},
+			expectedPorts: []int{8080, 9090},
+		},
+		{
+			name:          "hostnames with invalid ports",
+			hostnames:     []string{"bookstore-v1:http", "bookstore-v1:100000"},
+			expectedPorts: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expectedPorts, getHostnamePorts(tc.hostnames))
+		})
+	}
+}
+
+func TestGetOutboundRouteConfigNameForPort(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.Equal("rds-outbound.80", GetOutboundRouteConfigNameForPort(80))
+	assert.Equal("rds-outbound.8080", GetOutboundRouteConfigNameForPort(8080))
+}
+
 func TestGetEgressRouteConfigNameForPort(t *testing.T) {
 	testCases := []struct {
 		name         string
@@ -1244,3 +1479,205 @@ func TestGetEgressRouteConfigNameForPort(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildCORSPolicy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		cors     *trafficpolicy.CORSPolicy
+		expected *xds_route.CorsPolicy
+	}{
+		{
+			name:     "CORS unset",
+			cors:     nil,
+			expected: nil,
+		},
+		{
+			name: "CORS with all fields",
+			cors: &trafficpolicy.CORSPolicy{
+				AllowOrigins:     []string{"https://foo.example.com", "https://bar.example.com"},
+				AllowMethods:     []string{"GET", "POST"},
+				AllowHeaders:     []string{"Content-Type", "Authorization"},
+				ExposeHeaders:    []string{"X-Request-Id"},
+				MaxAge:           90 * time.Second,
+				AllowCredentials: true,
+			},
+			expected: &xds_route.CorsPolicy{
+				AllowOriginStringMatch: []*xds_matcher.StringMatcher{
+					{MatchPattern: &xds_matcher.StringMatcher_Exact{Exact: "https://foo.example.com"}},
+					{MatchPattern: &xds_matcher.StringMatcher_Exact{Exact: "https://bar.example.com"}},
+				},
+				AllowMethods:     "GET,POST",
+				AllowHeaders:     "Content-Type,Authorization",
+				ExposeHeaders:    "X-Request-Id",
+				MaxAge:           "90",
+				AllowCredentials: &wrappers.BoolValue{Value: true},
+			},
+		},
+		{
+			name: "CORS allowing any origin",
+			cors: &trafficpolicy.CORSPolicy{
+				AllowOrigins: []string{"*"},
+			},
+			expected: &xds_route.CorsPolicy{
+				AllowOriginStringMatch: []*xds_matcher.StringMatcher{
+					{MatchPattern: &xds_matcher.StringMatcher_Exact{Exact: "*"}},
+				},
+				AllowCredentials: &wrappers.BoolValue{Value: false},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expected, buildCORSPolicy(tc.cors))
+		})
+	}
+}
+
+func TestApplyHeaderMutations(t *testing.T) {
+	assert := tassert.New(t)
+
+	headers := &trafficpolicy.HTTPHeaderMutations{
+		RequestHeadersToAdd:     []trafficpolicy.HTTPHeaderValue{{Name: "x-correlation-id", Value: "%REQ(x-request-id)%"}},
+		RequestHeadersToRemove:  []string{"x-debug"},
+		ResponseHeadersToAdd:    []trafficpolicy.HTTPHeaderValue{{Name: "strict-transport-security", Value: "max-age=31536000"}},
+		ResponseHeadersToRemove: []string{"x-internal"},
+	}
+	expectedRequestHeadersToAdd := []*core.HeaderValueOption{
+		{
+			Header: &core.HeaderValue{Key: "x-correlation-id", Value: "%REQ(x-request-id)%"},
+			Append: &wrappers.BoolValue{Value: false},
+		},
+	}
+	expectedResponseHeadersToAdd := []*core.HeaderValueOption{
+		{
+			Header: &core.HeaderValue{Key: "strict-transport-security", Value: "max-age=31536000"},
+			Append: &wrappers.BoolValue{Value: false},
+		},
+	}
+
+	virtualHost := buildVirtualHostStub(ingressVirtualHost, "test", []string{"*"})
+	applyVirtualHostHeaderMutations(virtualHost, nil)
+	assert.Nil(virtualHost.RequestHeadersToAdd)
+	applyVirtualHostHeaderMutations(virtualHost, headers)
+	assert.Equal(expectedRequestHeadersToAdd, virtualHost.RequestHeadersToAdd)
+	assert.Equal([]string{"x-debug"}, virtualHost.RequestHeadersToRemove)
+	assert.Equal(expectedResponseHeadersToAdd, virtualHost.ResponseHeadersToAdd)
+	assert.Equal([]string{"x-internal"}, virtualHost.ResponseHeadersToRemove)
+
+	route := buildRoute(trafficpolicy.PathMatchPrefix, "/", constants.WildcardHTTPMethod, nil, mapset.NewSet(tests.BookstoreV1DefaultWeightedCluster), 100, inboundRoute)
+	applyRouteHeaderMutations(route, nil)
+	assert.Nil(route.RequestHeadersToAdd)
+	applyRouteHeaderMutations(route, headers)
+	assert.Equal(expectedRequestHeadersToAdd, route.RequestHeadersToAdd)
+	assert.Equal([]string{"x-debug"}, route.RequestHeadersToRemove)
+	assert.Equal(expectedResponseHeadersToAdd, route.ResponseHeadersToAdd)
+	assert.Equal([]string{"x-internal"}, route.ResponseHeadersToRemove)
+}
+
+func TestApplyRouteRewrite(t *testing.T) {
+	testCases := []struct {
+		name       string
+		rewrite    *trafficpolicy.HTTPRouteRewrite
+		expectFunc func(assert *tassert.Assertions, action *xds_route.RouteAction)
+	}{
+		{
+			name:    "no rewrite",
+			rewrite: nil,
+			expectFunc: func(assert *tassert.Assertions, action *xds_route.RouteAction) {
+				assert.Empty(action.PrefixRewrite)
+				assert.Nil(action.RegexRewrite)
+				assert.Nil(action.HostRewriteSpecifier)
+			},
+		},
+		{
+			name: "prefix and host rewrite",
+			rewrite: &trafficpolicy.HTTPRouteRewrite{
+				PrefixRewrite: "/",
+				HostRewrite:   "foo.example.com",
+			},
+			expectFunc: func(assert *tassert.Assertions, action *xds_route.RouteAction) {
+				assert.Equal("/", action.PrefixRewrite)
+				assert.Nil(action.RegexRewrite)
+				assert.Equal("foo.example.com", action.GetHostRewriteLiteral())
+			},
+		},
+		{
+			name: "regex and auto host rewrite",
+			rewrite: &trafficpolicy.HTTPRouteRewrite{
+				RegexRewritePattern:      "^/api/(.*)$",
+				RegexRewriteSubstitution: "/\\1",
+				AutoHostRewrite:          true,
+			},
+			expectFunc: func(assert *tassert.Assertions, action *xds_route.RouteAction) {
+				assert.Empty(action.PrefixRewrite)
+				assert.Equal("^/api/(.*)$", action.GetRegexRewrite().GetPattern().GetRegex())
+				assert.Equal("/\\1", action.GetRegexRewrite().GetSubstitution())
+				assert.True(action.GetAutoHostRewrite().GetValue())
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			route := buildRoute(trafficpolicy.PathMatchPrefix, "/api/", constants.WildcardHTTPMethod, nil, mapset.NewSet(tests.BookstoreV1DefaultWeightedCluster), 100, inboundRoute)
+			applyRouteRewrite(route, tc.rewrite)
+			tc.expectFunc(tassert.New(t), route.GetRoute())
+		})
+	}
+}
+
+func TestApplyVirtualHostRetryPolicy(t *testing.T) {
+	testCases := []struct {
+		name        string
+		retryPolicy *trafficpolicy.RetryPolicy
+		expectFunc  func(assert *tassert.Assertions, virtualHost *xds_route.VirtualHost)
+	}{
+		{
+			name:        "no retry policy",
+			retryPolicy: nil,
+			expectFunc: func(assert *tassert.Assertions, virtualHost *xds_route.VirtualHost) {
+				assert.Nil(virtualHost.RetryPolicy)
+				assert.Nil(virtualHost.HedgePolicy)
+			},
+		},
+		{
+			name: "retry policy without per-try timeout",
+			retryPolicy: &trafficpolicy.RetryPolicy{
+				RetryOn:    "5xx",
+				NumRetries: 3,
+			},
+			expectFunc: func(assert *tassert.Assertions, virtualHost *xds_route.VirtualHost) {
+				assert.Equal("5xx", virtualHost.RetryPolicy.RetryOn)
+				assert.Equal(uint32(3), virtualHost.RetryPolicy.NumRetries.GetValue())
+				assert.Nil(virtualHost.RetryPolicy.PerTryTimeout)
+				assert.Nil(virtualHost.HedgePolicy)
+			},
+		},
+		{
+			name: "retry policy hedging on per-try timeout",
+			retryPolicy: &trafficpolicy.RetryPolicy{
+				RetryOn:              "5xx,reset",
+				NumRetries:           1,
+				PerTryTimeout:        250 * time.Millisecond,
+				HedgeOnPerTryTimeout: true,
+			},
+			expectFunc: func(assert *tassert.Assertions, virtualHost *xds_route.VirtualHost) {
+				assert.Equal("5xx,reset", virtualHost.RetryPolicy.RetryOn)
+				assert.Equal(uint32(1), virtualHost.RetryPolicy.NumRetries.GetValue())
+				assert.Equal(250*time.Millisecond, virtualHost.RetryPolicy.PerTryTimeout.AsDuration())
+				// The requests are hedged by the routes of the idempotent methods
+				assert.Nil(virtualHost.HedgePolicy)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			virtualHost := buildVirtualHostStub(outboundVirtualHost, tests.BookstoreV1Service.FQDN(), []string{tests.BookstoreV1Service.FQDN()})
+			applyVirtualHostRetryPolicy(virtualHost, tc.retryPolicy)
+			tc.expectFunc(tassert.New(t), virtualHost)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package route
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+const (
+	// OutboundScopedRoutesName is the name of the outbound mesh scoped routes, which prefixes the names of their
+	// scoped route configurations
+	OutboundScopedRoutesName = "srds-outbound"
+
+	// outboundHostRouteConfigNamePrefix is the prefix of the names of the outbound route configurations of the hosts
+	outboundHostRouteConfigNamePrefix = OutboundRouteConfigName + "."
+)
+
+// IsScopedRoutesEnabled returns whether the outbound HTTP routes of the proxies are scoped by host, with a route
+// configuration per host fetched on demand by the proxies. Scoped routes are not supported with the snapshot cache.
+func IsScopedRoutesEnabled(cfg configurator.Configurator) bool {
+	featureFlags := cfg.GetFeatureFlags()
+	return featureFlags.EnableScopedRoutes && !featureFlags.EnableSnapshotCacheMode
+}
+
+// GetOutboundRouteConfigNameForHost returns the name of the outbound route configuration of the given host, the name
+// of its outbound traffic policy
+func GetOutboundRouteConfigNameForHost(host string) string {
+	return outboundHostRouteConfigNamePrefix + host
+}
+
+// GetHostsFromOutboundRouteConfigNames returns the hosts of the outbound route configurations among the given route
+// configurations, the outbound route configurations of the destination ports being skipped
+func GetHostsFromOutboundRouteConfigNames(routeConfigNames []string) []string {
+	var hosts []string
+	for _, name := range routeConfigNames {
+		host := strings.TrimPrefix(name, outboundHostRouteConfigNamePrefix)
+		if host == name || host == "" {
+			continue
+		}
+		if _, err := strconv.Atoi(host); err == nil {
+			continue
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
+
+// BuildOutboundScopedRouteConfigurations constructs the Envoy scoped route configurations mapping the hostnames of the
+// given outbound policies to the route configurations of their hosts. The route configurations are fetched on demand
+// by the proxies, on the first request to one of their hostnames.
+// A hostname shared by several policies is mapped to the route configuration of the first of the policies by name.
+func BuildOutboundScopedRouteConfigurations(outbound []*trafficpolicy.OutboundTrafficPolicy) []*xds_route.ScopedRouteConfiguration {
+	policies := make([]*trafficpolicy.OutboundTrafficPolicy, len(outbound))
+	copy(policies, outbound)
+	sort.SliceStable(policies, func(i, j int) bool {
+		return policies[i].Name < policies[j].Name
+	})
+
+	var scopedRouteConfigs []*xds_route.ScopedRouteConfiguration
+	scopedHostnames := make(map[string]struct{})
+	for _, policy := range policies {
+		for _, hostname := range policy.Hostnames {
+			if _, ok := scopedHostnames[hostname]; ok {
+				log.Debug().Msgf("Hostname %s of outbound policy %s is already scoped, skipping", hostname, policy.Name)
+				continue
+			}
+			scopedHostnames[hostname] = struct{}{}
+
+			scopedRouteConfigs = append(scopedRouteConfigs, &xds_route.ScopedRouteConfiguration{
+				Name:                   fmt.Sprintf("%s|%s", OutboundScopedRoutesName, hostname),
+				RouteConfigurationName: GetOutboundRouteConfigNameForHost(policy.Name),
+				OnDemand:               true,
+				Key: &xds_route.ScopedRouteConfiguration_Key{
+					Fragments: []*xds_route.ScopedRouteConfiguration_Key_Fragment{
+						{
+							Type: &xds_route.ScopedRouteConfiguration_Key_Fragment_StringKey{StringKey: hostname},
+						},
+					},
+				},
+			})
+		}
+	}
+
+	return scopedRouteConfigs
+}
+
+// BuildOutboundHostRouteConfigurations constructs the Envoy route configurations of the hosts of the given outbound
+// policies, each holding the virtual host of its policy
+func BuildOutboundHostRouteConfigurations(outbound []*trafficpolicy.OutboundTrafficPolicy) []*xds_route.RouteConfiguration {
+	var routeConfigs []*xds_route.RouteConfiguration
+	for _, out := range outbound {
+		routeConfig := NewRouteConfigurationStub(GetOutboundRouteConfigNameForHost(out.Name))
+		virtualHost := buildVirtualHostStub(outboundVirtualHost, out.Name, out.Hostnames)
+		virtualHost.Routes = buildOutboundRoutes(out.Routes, out.RetryPolicy)
+		applyVirtualHostRetryPolicy(virtualHost, out.RetryPolicy)
+		routeConfig.VirtualHosts = append(routeConfig.VirtualHosts, virtualHost)
+		routeConfigs = append(routeConfigs, routeConfig)
+	}
+	return routeConfigs
+}
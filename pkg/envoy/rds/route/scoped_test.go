@@ -0,0 +1,127 @@
+package route
+
+import (
+	"testing"
+
+	mapset "github.com/deckarep/golang-set"
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/tests"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestIsScopedRoutesEnabled(t *testing.T) {
+	testCases := []struct {
+		name         string
+		featureFlags v1alpha1.FeatureFlags
+		expected     bool
+	}{
+		{
+			name:         "scoped routes disabled",
+			featureFlags: v1alpha1.FeatureFlags{},
+			expected:     false,
+		},
+		{
+			name:         "scoped routes enabled",
+			featureFlags: v1alpha1.FeatureFlags{EnableScopedRoutes: true},
+			expected:     true,
+		},
+		{
+			name:         "scoped routes enabled with the snapshot cache",
+			featureFlags: v1alpha1.FeatureFlags{EnableScopedRoutes: true, EnableSnapshotCacheMode: true},
+			expected:     false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			mockCfg := configurator.NewMockConfigurator(mockCtrl)
+			mockCfg.EXPECT().GetFeatureFlags().Return(tc.featureFlags)
+
+			assert.Equal(tc.expected, IsScopedRoutesEnabled(mockCfg))
+		})
+	}
+}
+
+func TestGetHostsFromOutboundRouteConfigNames(t *testing.T) {
+	assert := tassert.New(t)
+
+	hosts := GetHostsFromOutboundRouteConfigNames([]string{
+		InboundRouteConfigName,
+		OutboundRouteConfigName,
+		GetOutboundRouteConfigNameForHost("bookstore-v1.default.svc.cluster.local"),
+		GetEgressRouteConfigNameForPort(80),
+		GetOutboundRouteConfigNameForPort(8080),
+		GetOutboundRouteConfigNameForHost("bookstore-v2.default.svc.cluster.local:8080"),
+		"rds-outbound.",
+	})
+	assert.Equal([]string{"bookstore-v1.default.svc.cluster.local", "bookstore-v2.default.svc.cluster.local:8080"}, hosts)
+}
+
+func TestBuildOutboundScopedRouteConfigurations(t *testing.T) {
+	assert := tassert.New(t)
+
+	outbound := []*trafficpolicy.OutboundTrafficPolicy{
+		{
+			Name:      "bookstore-v2.default.svc.cluster.local",
+			Hostnames: []string{"bookstore-v2.default", "bookstore.default"},
+		},
+		{
+			Name:      "bookstore-v1.default.svc.cluster.local",
+			Hostnames: []string{"bookstore-v1.default", "bookstore.default"},
+		},
+	}
+
+	scopedRouteConfigs := BuildOutboundScopedRouteConfigurations(outbound)
+	assert.Len(scopedRouteConfigs, 3)
+
+	expected := []struct {
+		name            string
+		routeConfigName string
+		key             string
+	}{
+		{"srds-outbound|bookstore-v1.default", "rds-outbound.bookstore-v1.default.svc.cluster.local", "bookstore-v1.default"},
+		{"srds-outbound|bookstore.default", "rds-outbound.bookstore-v1.default.svc.cluster.local", "bookstore.default"},
+		{"srds-outbound|bookstore-v2.default", "rds-outbound.bookstore-v2.default.svc.cluster.local", "bookstore-v2.default"},
+	}
+	for i, scope := range scopedRouteConfigs {
+		assert.Equal(expected[i].name, scope.Name)
+		assert.Equal(expected[i].routeConfigName, scope.RouteConfigurationName)
+		assert.True(scope.OnDemand)
+		assert.Len(scope.Key.Fragments, 1)
+		assert.Equal(expected[i].key, scope.Key.Fragments[0].GetStringKey())
+	}
+
+	// The given policies are not reordered
+	assert.Equal("bookstore-v2.default.svc.cluster.local", outbound[0].Name)
+}
+
+func TestBuildOutboundHostRouteConfigurations(t *testing.T) {
+	assert := tassert.New(t)
+
+	outbound := []*trafficpolicy.OutboundTrafficPolicy{
+		{
+			Name:      "bookstore-v1.default.svc.cluster.local",
+			Hostnames: tests.BookstoreV1Hostnames,
+			Routes: []*trafficpolicy.RouteWeightedClusters{
+				{
+					HTTPRouteMatch:   tests.BookstoreBuyHTTPRoute,
+					WeightedClusters: mapset.NewSet(tests.BookstoreV1DefaultWeightedCluster),
+				},
+			},
+		},
+	}
+
+	routeConfigs := BuildOutboundHostRouteConfigurations(outbound)
+	assert.Len(routeConfigs, 1)
+	assert.Equal("rds-outbound.bookstore-v1.default.svc.cluster.local", routeConfigs[0].Name)
+	assert.Len(routeConfigs[0].VirtualHosts, 1)
+	assert.Equal("outbound_virtual-host|bookstore-v1.default.svc.cluster.local", routeConfigs[0].VirtualHosts[0].Name)
+	assert.Equal(tests.BookstoreV1Hostnames, routeConfigs[0].VirtualHosts[0].Domains)
+	assert.Len(routeConfigs[0].VirtualHosts[0].Routes, 1)
+}
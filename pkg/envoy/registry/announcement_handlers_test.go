@@ -104,15 +104,15 @@ var _ = Describe("Test Announcement Handlers", func() {
 		})
 
 		It("ignores events other than pod-deleted", func() {
-			var connectedProxies []envoy.Proxy
+			var connectedProxies []*envoy.Proxy
 			proxyRegistry.connectedProxies.Range(func(key interface{}, value interface{}) bool {
 				connectedProxy := value.(connectedProxy)
-				connectedProxies = append(connectedProxies, *connectedProxy.proxy)
+				connectedProxies = append(connectedProxies, connectedProxy.proxy)
 				return true // continue the iteration
 			})
 
 			Expect(len(connectedProxies)).To(Equal(1))
-			Expect(connectedProxies[0]).To(Equal(*proxy))
+			Expect(connectedProxies[0]).To(Equal(proxy))
 
 			// Publish some event unrelated to podDeleted
 			events.Publish(events.PubSubMessage{
@@ -0,0 +1,118 @@
+package registry
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+)
+
+const (
+	// DefaultGCTTL is the default period after which the registry entries of a disconnected proxy are reclaimed
+	DefaultGCTTL = 1 * time.Hour
+
+	// DefaultGCInterval is the default interval at which the registry entries of disconnected proxies are reclaimed
+	DefaultGCInterval = 5 * time.Minute
+)
+
+// GCConfig is the configuration of the garbage collection of the registry entries of disconnected proxies
+type GCConfig struct {
+	// TTL is the period after which the registry entries of a proxy that disconnected and whose pod no longer exists
+	// are reclaimed, they are never reclaimed if 0
+	TTL time.Duration
+
+	// Interval is the interval at which the registry entries are garbage collected
+	Interval time.Duration
+}
+
+// RunGarbageCollector starts reclaiming the registry entries of the proxies that disconnected for longer than the
+// configured TTL and whose pods no longer exist, along with the certificates issued for them, until stop is closed.
+// Without it, the entries of the proxies of deleted pods accumulate over the lifetime of the controller.
+func (pr *ProxyRegistry) RunGarbageCollector(config GCConfig, certManager certificate.Manager, kubeController k8s.Controller, stop <-chan struct{}) {
+	if config.TTL == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(config.Interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				pr.collectGarbage(config.TTL, certManager, kubeController)
+			}
+		}
+	}()
+}
+
+// collectGarbage reclaims the registry entries of the proxies that disconnected for longer than the given TTL and
+// whose pods no longer exist, and releases the certificates issued for these proxies
+func (pr *ProxyRegistry) collectGarbage(ttl time.Duration, certManager certificate.Manager, kubeController k8s.Controller) {
+	existingPods := make(map[types.UID]struct{})
+	for _, pod := range kubeController.ListPods() {
+		existingPods[pod.UID] = struct{}{}
+	}
+
+	// The proxies on existing pods may reconnect, their entries are kept
+	proxiesOnExistingPods := make(map[certificate.CommonName]struct{})
+	pr.podUIDToCN.Range(func(podUIDIface, cnIface interface{}) bool {
+		if _, ok := existingPods[podUIDIface.(types.UID)]; ok {
+			proxiesOnExistingPods[cnIface.(certificate.CommonName)] = struct{}{}
+		}
+		return true
+	})
+
+	isReclaimable := func(cn certificate.CommonName) bool {
+		if _, connected := pr.connectedProxies.Load(cn); connected {
+			return false
+		}
+		_, onExistingPod := proxiesOnExistingPods[cn]
+		return !onExistingPod
+	}
+
+	pr.disconnectedProxies.Range(func(cnIface, disconnectedIface interface{}) bool {
+		cn := cnIface.(certificate.CommonName)
+		if time.Since(disconnectedIface.(disconnectedProxy).lastSeen) < ttl {
+			return true
+		}
+
+		// A proxy that reconnected is no longer disconnected, its stale entry is reclaimed but its certificate is kept
+		_, connected := pr.connectedProxies.Load(cn)
+		if !connected && !isReclaimable(cn) {
+			return true
+		}
+
+		pr.disconnectedProxies.Delete(cn)
+		metricsstore.DefaultMetricsStore.ProxyRegistryReclaimedCount.WithLabelValues("disconnected_proxy").Inc()
+
+		if !connected {
+			log.Debug().Msgf("Releasing certificate %s of proxy disconnected since %s", cn, disconnectedIface.(disconnectedProxy).lastSeen)
+			certManager.ReleaseCertificate(cn)
+			metricsstore.DefaultMetricsStore.ProxyRegistryReclaimedCount.WithLabelValues("certificate").Inc()
+		}
+		return true
+	})
+
+	pr.podUIDToCN.Range(func(podUIDIface, cnIface interface{}) bool {
+		podUID := podUIDIface.(types.UID)
+		cn := cnIface.(certificate.CommonName)
+		if _, ok := existingPods[podUID]; ok || !isReclaimable(cn) {
+			return true
+		}
+		// The proxy disconnected within the TTL
+		if _, disconnected := pr.disconnectedProxies.Load(cn); disconnected {
+			return true
+		}
+
+		log.Debug().Msgf("Reclaiming registry entries of deleted pod with UID %s", podUID)
+		pr.podUIDToCN.Delete(podUID)
+		pr.podUIDToCertificateSerialNumber.Delete(podUID)
+		metricsstore.DefaultMetricsStore.ProxyRegistryReclaimedCount.WithLabelValues("pod_mapping").Inc()
+		return true
+	})
+}
@@ -0,0 +1,97 @@
+package registry
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/k8s"
+)
+
+func TestCollectGarbage(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCertManager := certificate.NewMockManager(mockCtrl)
+	mockKubeController := k8s.NewMockController(mockCtrl)
+
+	ttl := 1 * time.Hour
+	proxyRegistry := NewProxyRegistry(nil)
+
+	newProxy := func(podName string) *envoy.Proxy {
+		cn := certificate.CommonName(fmt.Sprintf("%s.%s.sa.ns.cluster.local", uuid.New(), envoy.KindSidecar))
+		proxy, err := envoy.NewProxy(cn, "123", nil)
+		assert.Nil(err)
+		proxy.PodMetadata = &envoy.PodMetadata{
+			UID:       "uid-" + podName,
+			Name:      podName,
+			Namespace: "ns",
+		}
+		proxyRegistry.RegisterProxy(proxy)
+		return proxy
+	}
+	disconnect := func(proxy *envoy.Proxy, lastSeen time.Time) {
+		proxyRegistry.UnregisterProxy(proxy)
+		proxyRegistry.disconnectedProxies.Store(proxy.GetCertificateCommonName(), disconnectedProxy{lastSeen: lastSeen})
+	}
+
+	// Disconnected for longer than the TTL, pod deleted: reclaimed
+	deletedPodProxy := newProxy("deleted")
+	disconnect(deletedPodProxy, time.Now().Add(-2*ttl))
+
+	// Disconnected within the TTL, pod deleted: kept
+	recentlyDeletedPodProxy := newProxy("recently-deleted")
+	disconnect(recentlyDeletedPodProxy, time.Now().Add(-ttl/2))
+
+	// Disconnected for longer than the TTL, pod still existing: kept
+	existingPodProxy := newProxy("existing")
+	disconnect(existingPodProxy, time.Now().Add(-2*ttl))
+
+	// Disconnected for longer than the TTL then reconnected: stale disconnected entry reclaimed, certificate kept
+	reconnectedProxy := newProxy("reconnected")
+	disconnect(reconnectedProxy, time.Now().Add(-2*ttl))
+	proxyRegistry.RegisterProxy(reconnectedProxy)
+
+	// Connected: kept
+	connectedProxy := newProxy("connected")
+
+	mockKubeController.EXPECT().ListPods().Return([]*v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{UID: "uid-existing"}},
+	})
+	mockCertManager.EXPECT().ReleaseCertificate(deletedPodProxy.GetCertificateCommonName()).Times(1)
+
+	proxyRegistry.collectGarbage(ttl, mockCertManager, mockKubeController)
+
+	disconnectedProxies := proxyRegistry.ListDisconnectedProxies()
+	assert.Len(disconnectedProxies, 2)
+	assert.Contains(disconnectedProxies, recentlyDeletedPodProxy.GetCertificateCommonName())
+	assert.Contains(disconnectedProxies, existingPodProxy.GetCertificateCommonName())
+
+	connectedProxies := proxyRegistry.ListConnectedProxies()
+	assert.Len(connectedProxies, 2)
+	assert.Contains(connectedProxies, reconnectedProxy.GetCertificateCommonName())
+	assert.Contains(connectedProxies, connectedProxy.GetCertificateCommonName())
+
+	for podUID, expectedKept := range map[types.UID]bool{
+		"uid-deleted":          false,
+		"uid-recently-deleted": true,
+		"uid-existing":         true,
+		"uid-reconnected":      true,
+		"uid-connected":        true,
+	} {
+		_, cnKept := proxyRegistry.podUIDToCN.Load(podUID)
+		_, serialNumberKept := proxyRegistry.podUIDToCertificateSerialNumber.Load(podUID)
+		assert.Equal(expectedKept, cnKept, "pod UID %s", podUID)
+		assert.Equal(expectedKept, serialNumberKept, "pod UID %s", podUID)
+	}
+}
@@ -0,0 +1,121 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+const (
+	// inventoryNamespaceQueryKey is the query parameter of the proxy inventory handler filtering the proxies by pod namespace
+	inventoryNamespaceQueryKey = "namespace"
+)
+
+// ProxyInfo is the inventory entry of an Envoy proxy connected to the control plane.
+type ProxyInfo struct {
+	// CommonName is the CN of the certificate the proxy uses to connect to the control plane
+	CommonName certificate.CommonName `json:"commonName"`
+
+	// Kind is the kind of the proxy (sidecar, gateway)
+	Kind envoy.ProxyKind `json:"kind"`
+
+	// ServiceIdentity is the service identity of the proxy, encoded in CommonName
+	ServiceIdentity string `json:"serviceIdentity"`
+
+	// PodName, PodNamespace and PodUID identify the pod of the proxy, empty if the pod metadata is not known yet
+	PodName      string `json:"podName,omitempty"`
+	PodNamespace string `json:"podNamespace,omitempty"`
+	PodUID       string `json:"podUID,omitempty"`
+
+	// WorkloadKind and WorkloadName identify the controller of the pod of the proxy
+	WorkloadKind string `json:"workloadKind,omitempty"`
+	WorkloadName string `json:"workloadName,omitempty"`
+
+	// EnvoyVersion is the version of Envoy reported by the proxy, empty if not reported yet
+	EnvoyVersion string `json:"envoyVersion,omitempty"`
+
+	// LastAppliedVersions are the versions of each xDS type last acknowledged by the proxy, keyed by short type name
+	LastAppliedVersions map[string]uint64 `json:"lastAppliedVersions"`
+
+	// ConnectedAt is the time the proxy connected to the control plane
+	ConnectedAt time.Time `json:"connectedAt"`
+
+	// ConnectionAge is how long the proxy has been connected to the control plane
+	ConnectionAge string `json:"connectionAge"`
+}
+
+// ListProxyInventory returns the inventory of the connected Envoy proxies, sorted by pod namespace and name.
+// Only the proxies in the given pod namespace are returned if the namespace is not empty.
+func (pr *ProxyRegistry) ListProxyInventory(namespace string) []ProxyInfo {
+	inventory := []ProxyInfo{}
+	for cn, proxy := range pr.ListConnectedProxies() {
+		if namespace != "" && (!proxy.HasPodMetadata() || proxy.PodMetadata.Namespace != namespace) {
+			continue
+		}
+		inventory = append(inventory, newProxyInfo(cn, proxy))
+	}
+
+	sort.Slice(inventory, func(i, j int) bool {
+		if inventory[i].PodNamespace != inventory[j].PodNamespace {
+			return inventory[i].PodNamespace < inventory[j].PodNamespace
+		}
+		if inventory[i].PodName != inventory[j].PodName {
+			return inventory[i].PodName < inventory[j].PodName
+		}
+		return inventory[i].CommonName < inventory[j].CommonName
+	})
+
+	return inventory
+}
+
+// GetProxyInventoryHTTPHandler returns an HTTP handler listing the inventory of the connected Envoy proxies in JSON,
+// optionally filtered by the 'namespace' query parameter.
+func (pr *ProxyRegistry) GetProxyInventoryHTTPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		inventory := pr.ListProxyInventory(req.URL.Query().Get(inventoryNamespaceQueryKey))
+
+		jsonInventory, err := json.Marshal(inventory)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error marshaling proxy inventory %+v", inventory)
+			http.Error(w, "Error marshaling proxy inventory", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, string(jsonInventory))
+	})
+}
+
+func newProxyInfo(cn certificate.CommonName, proxy *envoy.Proxy) ProxyInfo {
+	info := ProxyInfo{
+		CommonName:          cn,
+		Kind:                proxy.Kind(),
+		EnvoyVersion:        proxy.GetEnvoyVersion(),
+		LastAppliedVersions: make(map[string]uint64),
+		ConnectedAt:         proxy.GetConnectedAt(),
+		ConnectionAge:       time.Since(proxy.GetConnectedAt()).Round(time.Second).String(),
+	}
+
+	if svcIdentity, err := envoy.GetServiceIdentityFromProxyCertificate(cn); err == nil {
+		info.ServiceIdentity = svcIdentity.String()
+	}
+
+	if proxy.HasPodMetadata() {
+		info.PodName = proxy.PodMetadata.Name
+		info.PodNamespace = proxy.PodMetadata.Namespace
+		info.PodUID = proxy.PodMetadata.UID
+		info.WorkloadKind = proxy.PodMetadata.WorkloadKind
+		info.WorkloadName = proxy.PodMetadata.WorkloadName
+	}
+
+	for typeURI, version := range proxy.GetLastAppliedVersions() {
+		info.LastAppliedVersions[typeURI.Short()] = version
+	}
+
+	return info
+}
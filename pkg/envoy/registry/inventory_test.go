@@ -0,0 +1,121 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+func TestListProxyInventory(t *testing.T) {
+	assert := tassert.New(t)
+
+	proxyRegistry := NewProxyRegistry(nil)
+
+	newProxy := func(namespace, podName string) *envoy.Proxy {
+		cn := certificate.CommonName(fmt.Sprintf("%s.%s.sa-%s.%s.cluster.local", uuid.New(), envoy.KindSidecar, podName, namespace))
+		proxy, err := envoy.NewProxy(cn, "123", nil)
+		assert.Nil(err)
+		proxy.PodMetadata = &envoy.PodMetadata{
+			UID:       "uid-" + podName,
+			Name:      podName,
+			Namespace: namespace,
+		}
+		return proxy
+	}
+
+	proxyB := newProxy("ns-2", "pod-b")
+	proxyA := newProxy("ns-1", "pod-a")
+	proxyA.SetEnvoyVersion("1.18.3")
+	proxyA.SetLastAppliedVersion(envoy.TypeCDS, 3)
+	proxyA.SetLastAppliedVersion(envoy.TypeLDS, 4)
+	proxyRegistry.RegisterProxy(proxyB)
+	proxyRegistry.RegisterProxy(proxyA)
+
+	inventory := proxyRegistry.ListProxyInventory("")
+	assert.Len(inventory, 2)
+
+	// The inventory is sorted by pod namespace and name
+	assert.Equal("pod-a", inventory[0].PodName)
+	assert.Equal("pod-b", inventory[1].PodName)
+
+	assert.Equal(proxyA.GetCertificateCommonName(), inventory[0].CommonName)
+	assert.Equal(envoy.KindSidecar, inventory[0].Kind)
+	assert.Equal("sa-pod-a.ns-1.cluster.local", inventory[0].ServiceIdentity)
+	assert.Equal("ns-1", inventory[0].PodNamespace)
+	assert.Equal("uid-pod-a", inventory[0].PodUID)
+	assert.Equal("1.18.3", inventory[0].EnvoyVersion)
+	assert.Equal(map[string]uint64{"CDS": 3, "LDS": 4}, inventory[0].LastAppliedVersions)
+	assert.Equal(proxyA.GetConnectedAt(), inventory[0].ConnectedAt)
+	assert.NotEmpty(inventory[0].ConnectionAge)
+
+	assert.Empty(inventory[1].EnvoyVersion)
+	assert.Empty(inventory[1].LastAppliedVersions)
+
+	// Filtered by namespace
+	inventory = proxyRegistry.ListProxyInventory("ns-2")
+	assert.Len(inventory, 1)
+	assert.Equal("pod-b", inventory[0].PodName)
+
+	// Disconnected proxies are not listed
+	proxyRegistry.UnregisterProxy(proxyB)
+	inventory = proxyRegistry.ListProxyInventory("ns-2")
+	assert.Empty(inventory)
+}
+
+func TestGetProxyInventoryHTTPHandler(t *testing.T) {
+	assert := tassert.New(t)
+
+	proxyRegistry := NewProxyRegistry(nil)
+	cn := certificate.CommonName(fmt.Sprintf("%s.%s.sa.ns.cluster.local", uuid.New(), envoy.KindSidecar))
+	proxy, err := envoy.NewProxy(cn, "123", nil)
+	assert.Nil(err)
+	proxy.PodMetadata = &envoy.PodMetadata{Name: "pod", Namespace: "ns"}
+	proxyRegistry.RegisterProxy(proxy)
+
+	testCases := []struct {
+		name          string
+		url           string
+		expectedCount int
+	}{
+		{
+			name:          "all proxies",
+			url:           "/proxies",
+			expectedCount: 1,
+		},
+		{
+			name:          "proxies in a namespace",
+			url:           "/proxies?namespace=ns",
+			expectedCount: 1,
+		},
+		{
+			name:          "proxies in a namespace without proxies",
+			url:           "/proxies?namespace=other",
+			expectedCount: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			rr := httptest.NewRecorder()
+			proxyRegistry.GetProxyInventoryHTTPHandler().ServeHTTP(rr, req)
+
+			assert.Equal(http.StatusOK, rr.Code)
+			assert.Equal("application/json", rr.Header().Get("Content-Type"))
+
+			var inventory []ProxyInfo
+			assert.Nil(json.Unmarshal(rr.Body.Bytes(), &inventory))
+			assert.Len(inventory, tc.expectedCount)
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/types"
 
+	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/envoy"
 )
 
@@ -36,7 +37,13 @@ func (pr *ProxyRegistry) RegisterProxy(proxy *envoy.Proxy) {
 }
 
 // UnregisterProxy unregisters the given proxy from the catalog.
+// A proxy whose certificate common name was registered again by a newer stream, e.g. after its Envoy restarted before
+// the stream of the previous Envoy was closed, is left registered.
 func (pr *ProxyRegistry) UnregisterProxy(p *envoy.Proxy) {
+	if connected, ok := pr.connectedProxies.Load(p.GetCertificateCommonName()); ok && connected.(connectedProxy).proxy != p {
+		log.Debug().Msgf("Proxy %s was registered again by a newer stream, not unregistering it", p.String())
+		return
+	}
 	pr.connectedProxies.Delete(p.GetCertificateCommonName())
 
 	pr.disconnectedProxies.Store(p.GetCertificateCommonName(), disconnectedProxy{
@@ -46,6 +53,16 @@ func (pr *ProxyRegistry) UnregisterProxy(p *envoy.Proxy) {
 	log.Debug().Msgf("Unregistered proxy %s", p.String())
 }
 
+// IsKnownProxy returns whether a proxy with the given certificate common name is connected, or disconnected and not
+// yet reclaimed
+func (pr *ProxyRegistry) IsKnownProxy(cn certificate.CommonName) bool {
+	if _, ok := pr.connectedProxies.Load(cn); ok {
+		return true
+	}
+	_, ok := pr.disconnectedProxies.Load(cn)
+	return ok
+}
+
 // GetConnectedProxyCount counts the number of connected proxies
 func (pr *ProxyRegistry) GetConnectedProxyCount() int {
 	return len(pr.ListConnectedProxies())
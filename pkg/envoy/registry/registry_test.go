@@ -0,0 +1,52 @@
+package registry
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+func TestIsKnownProxy(t *testing.T) {
+	assert := tassert.New(t)
+
+	proxyRegistry := NewProxyRegistry(nil)
+	certCommonName := certificate.CommonName(fmt.Sprintf("%s.sidecar.foo.bar", uuid.New()))
+	proxy, err := envoy.NewProxy(certCommonName, certificate.SerialNumber("123456"), nil)
+	assert.Nil(err)
+
+	assert.False(proxyRegistry.IsKnownProxy(certCommonName))
+
+	proxyRegistry.RegisterProxy(proxy)
+	assert.True(proxyRegistry.IsKnownProxy(certCommonName))
+
+	proxyRegistry.UnregisterProxy(proxy)
+	assert.True(proxyRegistry.IsKnownProxy(certCommonName))
+}
+
+func TestUnregisterReplacedProxy(t *testing.T) {
+	assert := tassert.New(t)
+
+	proxyRegistry := NewProxyRegistry(nil)
+	certCommonName := certificate.CommonName(fmt.Sprintf("%s.sidecar.foo.bar", uuid.New()))
+	previousProxy, err := envoy.NewProxy(certCommonName, certificate.SerialNumber("123456"), nil)
+	assert.Nil(err)
+	proxy, err := envoy.NewProxy(certCommonName, certificate.SerialNumber("123456"), nil)
+	assert.Nil(err)
+
+	// The stream of the previous proxy closes after the proxy registered again
+	proxyRegistry.RegisterProxy(previousProxy)
+	proxyRegistry.RegisterProxy(proxy)
+	proxyRegistry.UnregisterProxy(previousProxy)
+
+	assert.Equal(map[certificate.CommonName]*envoy.Proxy{certCommonName: proxy}, proxyRegistry.ListConnectedProxies())
+	assert.Empty(proxyRegistry.ListDisconnectedProxies())
+
+	proxyRegistry.UnregisterProxy(proxy)
+	assert.Empty(proxyRegistry.ListConnectedProxies())
+	assert.Len(proxyRegistry.ListDisconnectedProxies(), 1)
+}
@@ -2,6 +2,8 @@ package registry
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -9,11 +11,15 @@ import (
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
 
 	"github.com/openservicemesh/osm/pkg/announcements"
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/errcode"
 	"github.com/openservicemesh/osm/pkg/k8s"
 	"github.com/openservicemesh/osm/pkg/k8s/events"
 	"github.com/openservicemesh/osm/pkg/service"
@@ -21,6 +27,10 @@ import (
 
 // ProxyServiceMapper knows how to map Envoy instances to services.
 type ProxyServiceMapper interface {
+	// ListProxyServices returns the services of the workload of the given proxy, sorted by namespace and name.
+	// A workload selected by several services is a backend of each of them: a local cluster and inbound filter
+	// chains are configured for each port of each service. Services exposing the same target port of the workload
+	// with different application protocols conflict, only the first of them is returned.
 	ListProxyServices(*envoy.Proxy) ([]service.MeshService, error)
 }
 
@@ -41,6 +51,14 @@ type KubeProxyServiceMapper struct {
 func (k *KubeProxyServiceMapper) ListProxyServices(p *envoy.Proxy) ([]service.MeshService, error) {
 	cn := p.GetCertificateCommonName()
 
+	if p.Kind() == envoy.KindExternalWorkload {
+		externalWorkload, err := envoy.GetExternalWorkloadFromCertificate(cn, k.KubeController)
+		if err != nil {
+			return nil, err
+		}
+		return kubernetesServicesToMeshServices(listServicesForExternalWorkload(externalWorkload, k.KubeController)), nil
+	}
+
 	pod, err := envoy.GetPodFromCertificate(cn, k.KubeController)
 	if err != nil {
 		return nil, err
@@ -85,6 +103,9 @@ func NewAsyncKubeProxyServiceMapper(controller k8s.Controller) *AsyncKubeProxySe
 			announcements.ServiceAdded,
 			announcements.ServiceUpdated,
 			announcements.ServiceDeleted,
+			announcements.ExternalWorkloadAdded,
+			announcements.ExternalWorkloadUpdated,
+			announcements.ExternalWorkloadDeleted,
 		),
 	}
 }
@@ -98,6 +119,9 @@ func (k *AsyncKubeProxyServiceMapper) Run(stop <-chan struct{}) {
 	for _, pod := range k.kubeController.ListPods() {
 		k.handlePodUpdate(pod)
 	}
+	for _, externalWorkload := range k.kubeController.ListExternalWorkloads() {
+		k.handleExternalWorkloadUpdate(externalWorkload)
+	}
 	k.cacheLock.Unlock()
 
 	go func() {
@@ -126,6 +150,12 @@ func (k *AsyncKubeProxyServiceMapper) Run(stop <-chan struct{}) {
 				case announcements.ServiceDeleted:
 					svc := event.OldObj.(*v1.Service)
 					k.handleServiceDelete(svc)
+				case announcements.ExternalWorkloadAdded, announcements.ExternalWorkloadUpdated:
+					externalWorkload := event.NewObj.(*policyv1alpha1.ExternalWorkload)
+					k.handleExternalWorkloadUpdate(externalWorkload)
+				case announcements.ExternalWorkloadDeleted:
+					externalWorkload := event.OldObj.(*policyv1alpha1.ExternalWorkload)
+					k.handleExternalWorkloadDelete(externalWorkload)
 				}
 				k.cacheLock.Unlock()
 				events.Publish(events.PubSubMessage{
@@ -154,6 +184,32 @@ func (k *AsyncKubeProxyServiceMapper) handlePodUpdate(pod *v1.Pod) {
 	log.Trace().Msgf("Services associated with Pod with UID=%s Name=%s/%s: %+v",
 		pod.ObjectMeta.UID, pod.Namespace, pod.Name, servicesForPod)
 
+	k.cacheServicesForCN(cn, meshServices)
+}
+
+func (k *AsyncKubeProxyServiceMapper) handleExternalWorkloadUpdate(externalWorkload *policyv1alpha1.ExternalWorkload) {
+	if externalWorkload == nil {
+		return
+	}
+	cn, err := getCertCommonNameForExternalWorkload(*externalWorkload)
+	if err != nil {
+		log.Error().Err(err).Msgf("ignoring updated external workload %s/%s", externalWorkload.Namespace, externalWorkload.Name)
+		return
+	}
+
+	meshServices := kubernetesServicesToMeshServices(listServicesForExternalWorkload(externalWorkload, k.kubeController))
+
+	log.Trace().Msgf("Services associated with ExternalWorkload %s/%s: %+v",
+		externalWorkload.Namespace, externalWorkload.Name, strings.Join(listServiceNames(meshServices), ","))
+
+	k.cacheServicesForCN(cn, meshServices)
+}
+
+func (k *AsyncKubeProxyServiceMapper) cacheServicesForCN(cn certificate.CommonName, meshServices []service.MeshService) {
+	// The services no longer associated with the CN, e.g. because they now conflict with another service, are evicted
+	for _, svc := range k.servicesForCN[cn] {
+		delete(k.cnsForService[svc], cn)
+	}
 	k.servicesForCN[cn] = meshServices
 
 	for _, svc := range meshServices {
@@ -174,6 +230,23 @@ func (k *AsyncKubeProxyServiceMapper) handlePodDelete(pod *v1.Pod) {
 		return
 	}
 
+	k.evictCN(cn)
+}
+
+func (k *AsyncKubeProxyServiceMapper) handleExternalWorkloadDelete(externalWorkload *policyv1alpha1.ExternalWorkload) {
+	if externalWorkload == nil {
+		return
+	}
+	cn, err := getCertCommonNameForExternalWorkload(*externalWorkload)
+	if err != nil {
+		log.Error().Err(err).Msgf("ignoring deleted external workload %s/%s", externalWorkload.Namespace, externalWorkload.Name)
+		return
+	}
+
+	k.evictCN(cn)
+}
+
+func (k *AsyncKubeProxyServiceMapper) evictCN(cn certificate.CommonName) {
 	for _, svc := range k.servicesForCN[cn] {
 		delete(k.cnsForService[svc], cn)
 	}
@@ -194,25 +267,15 @@ func (k *AsyncKubeProxyServiceMapper) handleServiceUpdate(svc *v1.Service) {
 		k.cnsForService[updatedSvc] = make(map[certificate.CommonName]struct{})
 	}
 
-	pods := listPodsForService(svc, k.kubeController)
-	for _, pod := range pods {
-		cn, err := getCertCommonNameForPod(pod)
-		if err != nil {
-			log.Error().Err(err)
-			continue
-		}
-		alreadyCached := false
-		for _, cachedSvc := range k.servicesForCN[cn] {
-			if cachedSvc == updatedSvc {
-				alreadyCached = true
-				break
-			}
-		}
-		if !alreadyCached {
-			k.servicesForCN[cn] = append(k.servicesForCN[cn], updatedSvc)
-		}
-
-		k.cnsForService[updatedSvc][cn] = struct{}{}
+	// The services of the workloads selected by the updated service are listed again rather than appended to, since
+	// the updated service may conflict with the other services selecting these workloads
+	for _, pod := range listPodsForService(svc, k.kubeController) {
+		pod := pod
+		k.handlePodUpdate(&pod)
+	}
+	for _, externalWorkload := range listExternalWorkloadsForService(svc, k.kubeController) {
+		externalWorkload := externalWorkload
+		k.handleExternalWorkloadUpdate(&externalWorkload)
 	}
 }
 
@@ -262,29 +325,135 @@ func listServiceNames(meshServices []service.MeshService) (serviceNames []string
 	return serviceNames
 }
 
-// listServicesForPod lists Kubernetes services whose selectors match pod labels
+// listServicesForPod lists Kubernetes services whose selectors match pod labels, excluding conflicting services
 func listServicesForPod(pod *v1.Pod, kubeController k8s.Controller) []v1.Service {
+	containerPorts := make(map[string]int32)
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			if port.Name != "" {
+				containerPorts[port.Name] = port.ContainerPort
+			}
+		}
+	}
+	workload := fmt.Sprintf("pod %s/%s", pod.Namespace, pod.Name)
+	return excludeConflictingServices(listServicesForLabels(pod.Namespace, pod.Labels, kubeController), containerPorts, workload)
+}
+
+// listServicesForExternalWorkload lists Kubernetes services whose selectors match external workload labels, excluding
+// conflicting services
+func listServicesForExternalWorkload(externalWorkload *policyv1alpha1.ExternalWorkload, kubeController k8s.Controller) []v1.Service {
+	workload := fmt.Sprintf("external workload %s/%s", externalWorkload.Namespace, externalWorkload.Name)
+	return excludeConflictingServices(listServicesForLabels(externalWorkload.Namespace, externalWorkload.Labels, kubeController), nil, workload)
+}
+
+// excludeConflictingServices returns the given services, excluding each service that exposes a target port of the
+// workload with a different application protocol than a service preceding it. The named target ports are resolved
+// with the given container ports of the workload, and compared by name when the workload doesn't name any of its
+// container ports accordingly.
+func excludeConflictingServices(services []v1.Service, containerPorts map[string]int32, workload string) []v1.Service {
+	type portProtocol struct {
+		protocol string
+		svc      string
+	}
+	targetPortProtocols := make(map[string]portProtocol)
+
+	var nonConflictingServices []v1.Service
+	for _, svc := range services {
+		svcName := fmt.Sprintf("%s/%s", svc.Namespace, svc.Name)
+		conflicting := false
+		for _, port := range svc.Spec.Ports {
+			targetPort := getWorkloadTargetPort(port, containerPorts)
+			protocol := k8s.GetAppProtocolFromServicePort(port)
+			if existing, ok := targetPortProtocols[targetPort]; ok && existing.protocol != protocol {
+				log.Error().Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrConflictingServicePortProtocols)).
+					Msgf("Service %s exposes target port %s of %s with protocol %s, conflicting with protocol %s of service %s; ignoring service %s for %s",
+						svcName, targetPort, workload, protocol, existing.protocol, existing.svc, svcName, workload)
+				conflicting = true
+				break
+			}
+		}
+		if conflicting {
+			continue
+		}
+
+		for _, port := range svc.Spec.Ports {
+			targetPort := getWorkloadTargetPort(port, containerPorts)
+			if _, ok := targetPortProtocols[targetPort]; !ok {
+				targetPortProtocols[targetPort] = portProtocol{protocol: k8s.GetAppProtocolFromServicePort(port), svc: svcName}
+			}
+		}
+		nonConflictingServices = append(nonConflictingServices, svc)
+	}
+
+	return nonConflictingServices
+}
+
+// getWorkloadTargetPort returns the target port of the given service port on a workload with the given named container
+// ports, or the name of the target port if it can't be resolved
+func getWorkloadTargetPort(port v1.ServicePort, containerPorts map[string]int32) string {
+	if port.TargetPort.Type == intstr.String {
+		if containerPort, ok := containerPorts[port.TargetPort.StrVal]; ok {
+			return strconv.Itoa(int(containerPort))
+		}
+		return port.TargetPort.StrVal
+	}
+	if port.TargetPort.IntVal == 0 {
+		// The target port defaults to the port of the service
+		return strconv.Itoa(int(port.Port))
+	}
+	return strconv.Itoa(int(port.TargetPort.IntVal))
+}
+
+// listServicesForLabels lists Kubernetes services in the given namespace whose selectors match the given labels,
+// sorted by name
+func listServicesForLabels(namespace string, workloadLabels map[string]string, kubeController k8s.Controller) []v1.Service {
 	var serviceList []v1.Service
 	svcList := kubeController.ListServices()
 
 	for _, svc := range svcList {
-		if svc.Namespace != pod.Namespace {
+		if svc.Namespace != namespace {
 			continue
 		}
 		svcRawSelector := svc.Spec.Selector
-		// service has no selectors, we do not need to match against the pod label
+		// service has no selectors, we do not need to match against the workload labels
 		if len(svcRawSelector) == 0 {
 			continue
 		}
 		selector := labels.Set(svcRawSelector).AsSelector()
-		if selector.Matches(labels.Set(pod.Labels)) {
+		if selector.Matches(labels.Set(workloadLabels)) {
 			serviceList = append(serviceList, *svc)
 		}
 	}
 
+	// The services are listed from the cache in no particular order
+	sort.Slice(serviceList, func(i, j int) bool {
+		return serviceList[i].Name < serviceList[j].Name
+	})
+
 	return serviceList
 }
 
+func listExternalWorkloadsForService(service *v1.Service, kubeController k8s.Controller) []policyv1alpha1.ExternalWorkload {
+	svcRawSelector := service.Spec.Selector
+	// service has no selectors, we do not need to match against the external workload labels
+	if len(svcRawSelector) == 0 {
+		return nil
+	}
+	selector := labels.Set(svcRawSelector).AsSelector()
+
+	var matchedExternalWorkloads []policyv1alpha1.ExternalWorkload
+	for _, externalWorkload := range kubeController.ListExternalWorkloads() {
+		if service.Namespace != externalWorkload.Namespace {
+			continue
+		}
+		if selector.Matches(labels.Set(externalWorkload.Labels)) {
+			matchedExternalWorkloads = append(matchedExternalWorkloads, *externalWorkload)
+		}
+	}
+
+	return matchedExternalWorkloads
+}
+
 func listPodsForService(service *v1.Service, kubeController k8s.Controller) []v1.Pod {
 	svcRawSelector := service.Spec.Selector
 	// service has no selectors, we do not need to match against the pod label
@@ -320,3 +489,12 @@ func getCertCommonNameForPod(pod v1.Pod) (certificate.CommonName, error) {
 	cn := envoy.NewXDSCertCommonName(proxyUID, envoy.KindSidecar, pod.Spec.ServiceAccountName, pod.Namespace)
 	return cn, nil
 }
+
+func getCertCommonNameForExternalWorkload(externalWorkload policyv1alpha1.ExternalWorkload) (certificate.CommonName, error) {
+	proxyUID, err := uuid.Parse(externalWorkload.Spec.ProxyUUID)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid proxy UUID")
+	}
+	cn := envoy.NewXDSCertCommonName(proxyUID, envoy.KindExternalWorkload, externalWorkload.Spec.ServiceAccount, externalWorkload.Namespace)
+	return cn, nil
+}
@@ -11,11 +11,14 @@ import (
 	tassert "github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	testclient "k8s.io/client-go/kubernetes/fake"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+
 	"github.com/openservicemesh/osm/pkg/announcements"
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/constants"
@@ -315,6 +318,7 @@ func TestAsyncKubeProxyServiceMapperRun(t *testing.T) {
 	broadcast := events.Subscribe(announcements.ScheduleProxyBroadcast)
 
 	kubeController.EXPECT().ListPods().Return([]*v1.Pod{pod}).Times(1)
+	kubeController.EXPECT().ListExternalWorkloads().Return(nil).Times(1)
 	kubeController.EXPECT().ListServices().Return([]*v1.Service{svc}).Times(1)
 
 	k.Run(stop)
@@ -350,6 +354,8 @@ func TestAsyncKubeProxyServiceMapperRun(t *testing.T) {
 	assert.Empty(svcs)
 
 	kubeController.EXPECT().ListPods().Return([]*v1.Pod{pod}).Times(1)
+	kubeController.EXPECT().ListExternalWorkloads().Return(nil).Times(1)
+	kubeController.EXPECT().ListServices().Return([]*v1.Service{svc}).Times(1)
 	events.Publish(events.PubSubMessage{
 		AnnouncementType: announcements.ServiceAdded,
 		NewObj:           svc,
@@ -827,6 +833,7 @@ func TestKubeHandleServiceUpdate(t *testing.T) {
 		existingCNsToServices map[certificate.CommonName][]service.MeshService
 		existingServicesToCNs map[service.MeshService]map[certificate.CommonName]struct{}
 		existingPods          []*v1.Pod
+		existingSvcs          []*v1.Service
 		service               *v1.Service
 		expectedCNsToServices map[certificate.CommonName][]service.MeshService
 		expectedServicesToCNs map[service.MeshService]map[certificate.CommonName]struct{}
@@ -1068,6 +1075,7 @@ func TestKubeHandleServiceUpdate(t *testing.T) {
 						Labels: map[string]string{
 							constants.EnvoyUniqueIDLabelName: uid1.String(),
 							"app":                            "my-app",
+							"tier":                           "front",
 						},
 					},
 					Spec: v1.PodSpec{
@@ -1087,6 +1095,19 @@ func TestKubeHandleServiceUpdate(t *testing.T) {
 					},
 				},
 			},
+			existingSvcs: []*v1.Service{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "not-svc",
+						Namespace: "ns",
+					},
+					Spec: v1.ServiceSpec{
+						Selector: map[string]string{
+							"tier": "front",
+						},
+					},
+				},
+			},
 			existingCNsToServices: map[certificate.CommonName][]service.MeshService{
 				envoy.NewXDSCertCommonName(uid1, envoy.KindSidecar, "svcacc", "ns"): {
 					{
@@ -1132,15 +1153,12 @@ func TestKubeHandleServiceUpdate(t *testing.T) {
 						Namespace: "ns",
 					},
 				},
+				// The services of the pods are listed again, dropping the stale service of another namespace
 				envoy.NewXDSCertCommonName(uid2, envoy.KindSidecar, "svcacc", "ns"): {
 					{
 						Name:      "svc",
 						Namespace: "ns",
 					},
-					{
-						Name:      "svc",
-						Namespace: "not-ns",
-					},
 				},
 			},
 			expectedServicesToCNs: map[service.MeshService]map[certificate.CommonName]struct{}{
@@ -1151,9 +1169,7 @@ func TestKubeHandleServiceUpdate(t *testing.T) {
 				{Name: "not-svc", Namespace: "ns"}: {
 					envoy.NewXDSCertCommonName(uid1, envoy.KindSidecar, "svcacc", "ns"): {},
 				},
-				{Name: "svc", Namespace: "not-ns"}: {
-					envoy.NewXDSCertCommonName(uid2, envoy.KindSidecar, "svcacc", "ns"): {},
-				},
+				{Name: "svc", Namespace: "not-ns"}: {},
 			},
 		},
 	}
@@ -1163,6 +1179,8 @@ func TestKubeHandleServiceUpdate(t *testing.T) {
 			mockCtrl := gomock.NewController(t)
 			kubeController := k8s.NewMockController(mockCtrl)
 			kubeController.EXPECT().ListPods().Return(test.existingPods)
+			kubeController.EXPECT().ListExternalWorkloads().Return(nil)
+			kubeController.EXPECT().ListServices().Return(append(test.existingSvcs, test.service)).AnyTimes()
 
 			k := &AsyncKubeProxyServiceMapper{
 				kubeController: kubeController,
@@ -1649,6 +1667,7 @@ func TestAsyncKubeProxyServiceMapperRace(t *testing.T) {
 	stop := make(chan struct{})
 
 	kubeController.EXPECT().ListPods().Return(nil).Times(1)
+	kubeController.EXPECT().ListExternalWorkloads().Return(nil).Times(1)
 	k.Run(stop)
 
 	proxyUUID := uuid.New()
@@ -1682,6 +1701,8 @@ func TestAsyncKubeProxyServiceMapperRace(t *testing.T) {
 			})
 
 			kubeController.EXPECT().ListPods().Return([]*v1.Pod{pod}).Times(1)
+			kubeController.EXPECT().ListExternalWorkloads().Return(nil).Times(1)
+			kubeController.EXPECT().ListServices().Return([]*v1.Service{svc}).Times(1)
 			events.Publish(events.PubSubMessage{
 				AnnouncementType: announcements.ServiceAdded,
 				NewObj:           svc,
@@ -1726,3 +1747,147 @@ func TestAsyncKubeProxyServiceMapperRace(t *testing.T) {
 
 	<-stop
 }
+
+func TestListProxyServicesForExternalWorkload(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	proxyUUID := uuid.New()
+	externalWorkload := &policyv1alpha1.ExternalWorkload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vm",
+			Namespace: tests.Namespace,
+			Labels:    map[string]string{tests.SelectorKey: tests.SelectorValue},
+		},
+		Spec: policyv1alpha1.ExternalWorkloadSpec{
+			ServiceAccount: tests.BookstoreServiceAccountName,
+			ProxyUUID:      proxyUUID.String(),
+			Addresses:      []string{"10.0.0.1"},
+		},
+	}
+	svc := tests.NewServiceFixture("vm-svc", tests.Namespace, map[string]string{tests.SelectorKey: tests.SelectorValue})
+
+	kubeController := k8s.NewMockController(mockCtrl)
+	kubeController.EXPECT().ListExternalWorkloads().Return([]*policyv1alpha1.ExternalWorkload{externalWorkload}).AnyTimes()
+	kubeController.EXPECT().ListServices().Return([]*v1.Service{svc}).AnyTimes()
+
+	cn := envoy.NewXDSCertCommonName(proxyUUID, envoy.KindExternalWorkload, tests.BookstoreServiceAccountName, tests.Namespace)
+	proxy, err := envoy.NewProxy(cn, "", nil)
+	assert.NoError(err)
+
+	expected := []service.MeshService{{Name: "vm-svc", Namespace: tests.Namespace}}
+
+	// Synchronous mapping
+	mapper := &KubeProxyServiceMapper{KubeController: kubeController}
+	meshServices, err := mapper.ListProxyServices(proxy)
+	assert.NoError(err)
+	assert.Equal(expected, meshServices)
+
+	// Asynchronous mapping
+	asyncMapper := &AsyncKubeProxyServiceMapper{
+		kubeController: kubeController,
+		servicesForCN:  make(map[certificate.CommonName][]service.MeshService),
+		cnsForService:  make(map[service.MeshService]map[certificate.CommonName]struct{}),
+	}
+	asyncMapper.handleExternalWorkloadUpdate(externalWorkload)
+	meshServices, err = asyncMapper.ListProxyServices(proxy)
+	assert.NoError(err)
+	assert.Equal(expected, meshServices)
+
+	asyncMapper.handleExternalWorkloadDelete(externalWorkload)
+	assert.Empty(asyncMapper.servicesForCN)
+	assert.Empty(asyncMapper.cnsForService[expected[0]])
+
+	// A proxy whose certificate was issued for a different service account is not mapped
+	cn = envoy.NewXDSCertCommonName(proxyUUID, envoy.KindExternalWorkload, "other-sa", tests.Namespace)
+	proxy, err = envoy.NewProxy(cn, "", nil)
+	assert.NoError(err)
+	_, err = mapper.ListProxyServices(proxy)
+	assert.ErrorIs(err, envoy.ErrServiceAccountDoesNotMatchCertificate)
+}
+
+func TestListServicesForPodWithMultipleServices(t *testing.T) {
+	newService := func(name string, ports ...v1.ServicePort) *v1.Service {
+		return &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "ns",
+			},
+			Spec: v1.ServiceSpec{
+				Selector: map[string]string{"app": "my-app"},
+				Ports:    ports,
+			},
+		}
+	}
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod",
+			Namespace: "ns",
+			Labels:    map[string]string{"app": "my-app"},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Ports: []v1.ContainerPort{{Name: "web", ContainerPort: 8080}},
+				},
+			},
+		},
+	}
+	tcp := "tcp"
+
+	testCases := []struct {
+		name                 string
+		services             []*v1.Service
+		expectedServiceNames []string
+	}{
+		{
+			name: "services are sorted by name",
+			services: []*v1.Service{
+				newService("svc-b", v1.ServicePort{Name: "http-b", Port: 80, TargetPort: intstr.FromInt(8080)}),
+				newService("svc-a", v1.ServicePort{Name: "http-a", Port: 80, TargetPort: intstr.FromInt(8080)}),
+			},
+			expectedServiceNames: []string{"svc-a", "svc-b"},
+		},
+		{
+			name: "services exposing different target ports with different protocols don't conflict",
+			services: []*v1.Service{
+				newService("svc-a", v1.ServicePort{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080)}),
+				newService("svc-b", v1.ServicePort{Name: "tcp", Port: 80, TargetPort: intstr.FromInt(9090)}),
+			},
+			expectedServiceNames: []string{"svc-a", "svc-b"},
+		},
+		{
+			name: "service exposing the same target port with a different protocol conflicts",
+			services: []*v1.Service{
+				newService("svc-c", v1.ServicePort{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080)}),
+				newService("svc-b", v1.ServicePort{Name: "tcp-port", Port: 80, TargetPort: intstr.FromInt(8080)}),
+				newService("svc-a", v1.ServicePort{Name: "http", Port: 8080}),
+			},
+			expectedServiceNames: []string{"svc-a", "svc-c"},
+		},
+		{
+			name: "named target port resolved to the container port conflicts",
+			services: []*v1.Service{
+				newService("svc-a", v1.ServicePort{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080)}),
+				newService("svc-b", v1.ServicePort{Name: "http", Port: 80, TargetPort: intstr.FromString("web"), AppProtocol: &tcp}),
+			},
+			expectedServiceNames: []string{"svc-a"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			kubeController := k8s.NewMockController(mockCtrl)
+			kubeController.EXPECT().ListServices().Return(tc.services)
+
+			var actualServiceNames []string
+			for _, svc := range listServicesForPod(pod, kubeController) {
+				actualServiceNames = append(actualServiceNames, svc.Name)
+			}
+			assert.Equal(tc.expectedServiceNames, actualServiceNames)
+		})
+	}
+}
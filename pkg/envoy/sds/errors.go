@@ -5,5 +5,15 @@ import (
 )
 
 var (
-	errCertMismatch = errors.New("certificate mismatch")
+	// ErrIssuingCertificate is the error returned when the service certificate of a proxy could not be issued
+	ErrIssuingCertificate = errors.New("error issuing the service certificate")
+
+	errCertMismatch       = errors.New("certificate mismatch")
+	errNoTrustBundleStore = errors.New("certificate manager does not store trust bundles")
+	errNoTrustDomains     = errors.New("no remote trust domains are known")
+	errMulticlusterMode   = errors.New("multicluster mode is disabled")
+	errNotExported        = errors.New("service account does not back a service exported to remote clusters")
+	errSecretNotFound     = errors.New("TLS secret not found")
+	errInvalidTLSSecret   = errors.New("TLS secret does not hold a certificate and private key")
+	errNotReferenced      = errors.New("TLS secret is not referenced by the IngressBackend policies of the proxy's services")
 )
@@ -1,12 +1,18 @@
 package sds
 
 import (
+	"bytes"
+	"fmt"
+
 	xds_core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	xds_matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/catalog"
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/configurator"
@@ -15,16 +21,17 @@ import (
 	"github.com/openservicemesh/osm/pkg/envoy/secrets"
 	"github.com/openservicemesh/osm/pkg/errcode"
 	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s"
 )
 
 // NewResponse creates a new Secrets Discovery Response.
-func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, request *xds_discovery.DiscoveryRequest, cfg configurator.Configurator, certManager certificate.Manager, _ *registry.ProxyRegistry) ([]types.Resource, error) {
+func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, request *xds_discovery.DiscoveryRequest, cfg configurator.Configurator, certManager certificate.Manager, proxyRegistry *registry.ProxyRegistry) ([]types.Resource, error) {
 	log.Info().Msgf("Composing SDS Discovery Response for proxy %s", proxy.String())
 
 	// OSM currently relies on kubernetes ServiceAccount for service identity
 	proxyIdentity, err := envoy.GetServiceIdentityFromProxyCertificate(proxy.GetCertificateCommonName())
 	if err != nil {
-		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGettingServiceIdentity)).
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetricForProxy(errcode.ErrGettingServiceIdentity, proxy.GetCertificateCommonName())).
 			Msgf("Error retrieving ServiceAccount for proxy %s", proxy.String())
 		return nil, err
 	}
@@ -34,6 +41,7 @@ func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, request
 		certManager:     certManager,
 		cfg:             cfg,
 		serviceIdentity: proxyIdentity,
+		proxyRegistry:   proxyRegistry,
 	}
 
 	var sdsResources []types.Resource
@@ -47,7 +55,7 @@ func NewResponse(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, request
 	cert, err := certManager.IssueCertificate(certificate.CommonName(s.serviceIdentity), cfg.GetServiceCertValidityPeriod())
 	if err != nil {
 		log.Error().Err(err).Msgf("Error issuing a certificate for proxy %s", proxy.String())
-		return nil, err
+		return nil, fmt.Errorf("%w: %s", ErrIssuingCertificate, err)
 	}
 
 	// 2. Create SDS secret resources based on the requested certs in the DiscoveryRequest
@@ -64,12 +72,13 @@ func (s *sdsImpl) getSDSSecrets(cert certificate.Certificater, requestedCerts []
 	// - "service-cert:namespace/service-account"
 	// - "root-cert-for-mtls-outbound:namespace/service"
 	// - "root-cert-for-mtls-inbound:namespace/service-service-account"
+	// - "ingress-cert:namespace/secret-name"
 
 	// The Envoy makes a request for a list of resources (aka certificates), which we will send as a response to the SDS request.
 	for _, requestedCertificate := range requestedCerts {
 		sdsCert, err := secrets.UnmarshalSDSCert(requestedCertificate)
 		if err != nil {
-			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrUnmarshallingSDSCert)).
+			log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetricForProxy(errcode.ErrUnmarshallingSDSCert, proxy.GetCertificateCommonName())).
 				Msgf("Invalid resource kind requested: %q", requestedCertificate)
 			continue
 		}
@@ -79,9 +88,15 @@ func (s *sdsImpl) getSDSSecrets(cert certificate.Certificater, requestedCerts []
 		switch sdsCert.CertType {
 		// A service certificate is requested
 		case secrets.ServiceCertType:
-			envoySecret, err := getServiceCertSecret(cert, requestedCertificate)
+			serviceCert, err := s.getServiceCert(cert, *sdsCert, proxy)
+			if err != nil {
+				log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetricForProxy(errcode.ErrGettingServiceCertSecret, proxy.GetCertificateCommonName())).
+					Msgf("Error issuing cert %s for proxy %s", requestedCertificate, proxy.String())
+				continue
+			}
+			envoySecret, err := getServiceCertSecret(serviceCert, requestedCertificate)
 			if err != nil {
-				log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGettingServiceCertSecret)).
+				log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetricForProxy(errcode.ErrGettingServiceCertSecret, proxy.GetCertificateCommonName())).
 					Msgf("Error creating cert %s for proxy %s", requestedCertificate, proxy.String())
 				continue
 			}
@@ -96,6 +111,28 @@ func (s *sdsImpl) getSDSSecrets(cert certificate.Certificater, requestedCerts []
 			}
 			certs = append(certs, envoySecret)
 
+		// A root certificate used by the multicluster gateway to validate downstreams from a remote trust domain is requested
+		case secrets.RootCertTypeForTrustDomain:
+			if proxy.Kind() != envoy.KindGateway {
+				log.Error().Msgf("Certificate %s can only be requested by gateways, requested by proxy %s", requestedCertificate, proxy.String())
+				continue
+			}
+			envoySecret, err := s.getTrustDomainRootCert(*sdsCert)
+			if err != nil {
+				log.Error().Err(err).Msgf("Error creating cert %s for proxy %s", requestedCertificate, proxy.String())
+				continue
+			}
+			certs = append(certs, envoySecret)
+
+		// A certificate held by a Kubernetes TLS secret presented to ingress clients is requested
+		case secrets.IngressCertType:
+			envoySecret, err := s.getIngressCert(*sdsCert, proxy)
+			if err != nil {
+				log.Error().Err(err).Msgf("Error creating cert %s for proxy %s", requestedCertificate, proxy.String())
+				continue
+			}
+			certs = append(certs, envoySecret)
+
 		default:
 			log.Error().Msgf("Unexpected certificate type %s requested for proxy %s", requestedCertificate, proxy)
 		}
@@ -104,6 +141,59 @@ func (s *sdsImpl) getSDSSecrets(cert certificate.Certificater, requestedCerts []
 	return certs
 }
 
+// getServiceCert returns the service certificate for the requested SDS cert. Proxies are issued certificates for their
+// own identity, except for the multicluster gateway which presents the certificates of the exported services to
+// downstreams from remote trust domains.
+func (s *sdsImpl) getServiceCert(cert certificate.Certificater, sdscert secrets.SDSCert, proxy *envoy.Proxy) (certificate.Certificater, error) {
+	if proxy.Kind() != envoy.KindGateway {
+		return cert, nil
+	}
+
+	svcAccount, err := sdscert.GetK8sServiceAccount()
+	if err != nil {
+		return nil, err
+	}
+	svcIdentity := svcAccount.ToServiceIdentity()
+	if svcIdentity == s.serviceIdentity {
+		return cert, nil
+	}
+
+	if err := s.validateGatewayServiceCert(svcIdentity); err != nil {
+		return nil, errors.Wrapf(err, "gateway is not allowed to present the certificate of %s", svcIdentity)
+	}
+	return s.certManager.IssueCertificate(certificate.CommonName(svcIdentity), s.cfg.GetServiceCertValidityPeriod())
+}
+
+// validateGatewayServiceCert returns an error if the multicluster gateway is not allowed to present the certificate of
+// the given service identity. The gateway only presents certificates to downstreams from remote trust domains, so it
+// is only issued certificates of the services exported to remote clusters while remote trust domains are known.
+func (s *sdsImpl) validateGatewayServiceCert(svcIdentity identity.ServiceIdentity) error {
+	if !s.cfg.GetFeatureFlags().EnableMulticlusterMode {
+		return errMulticlusterMode
+	}
+
+	store, ok := s.certManager.(certificate.TrustBundleStore)
+	if !ok {
+		return errNoTrustBundleStore
+	}
+	if len(store.ListTrustDomains()) == 0 {
+		return errNoTrustDomains
+	}
+
+	for _, svc := range s.meshCatalog.ListExportedServicesForMulticlusterGateway() {
+		identities, err := s.meshCatalog.ListServiceIdentitiesForService(svc)
+		if err != nil {
+			continue
+		}
+		for _, exportedIdentity := range identities {
+			if exportedIdentity == svcIdentity {
+				return nil
+			}
+		}
+	}
+	return errNotExported
+}
+
 // getServiceCertSecret creates the struct with certificates for the service, which the
 // connected Envoy proxy belongs to.
 func getServiceCertSecret(cert certificate.Certificater, name string) (*xds_auth.Secret, error) {
@@ -128,6 +218,74 @@ func getServiceCertSecret(cert certificate.Certificater, name string) (*xds_auth
 	return secret, nil
 }
 
+// getIngressCert returns the certificate held by the Kubernetes TLS secret in the requested SDS cert, presented by the
+// proxy to its ingress clients. Proxies are only served the secrets referenced by the IngressBackend policies of their
+// services.
+func (s *sdsImpl) getIngressCert(sdscert secrets.SDSCert, proxy *envoy.Proxy) (*xds_auth.Secret, error) {
+	if err := s.validateIngressCert(sdscert, proxy); err != nil {
+		return nil, err
+	}
+
+	secretName, err := k8s.NamespacedNameFrom(sdscert.Name)
+	if err != nil {
+		return nil, err
+	}
+	secret := s.meshCatalog.GetKubeController().GetSecret(secretName.Namespace, secretName.Name)
+	if secret == nil {
+		return nil, errSecretNotFound
+	}
+
+	certChain := secret.Data[corev1.TLSCertKey]
+	privateKey := secret.Data[corev1.TLSPrivateKeyKey]
+	if len(certChain) == 0 || len(privateKey) == 0 {
+		return nil, errInvalidTLSSecret
+	}
+
+	return &xds_auth.Secret{
+		// The Name field must match the tls_context.common_tls_context.tls_certificate_sds_secret_configs.name
+		Name: sdscert.String(),
+		Type: &xds_auth.Secret_TlsCertificate{
+			TlsCertificate: &xds_auth.TlsCertificate{
+				CertificateChain: &xds_core.DataSource{
+					Specifier: &xds_core.DataSource_InlineBytes{
+						InlineBytes: certChain,
+					},
+				},
+				PrivateKey: &xds_core.DataSource{
+					Specifier: &xds_core.DataSource_InlineBytes{
+						InlineBytes: privateKey,
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// validateIngressCert returns an error if the TLS secret in the requested SDS cert is not referenced by the ingress
+// traffic policies of the services of the given proxy
+func (s *sdsImpl) validateIngressCert(sdscert secrets.SDSCert, proxy *envoy.Proxy) error {
+	if s.proxyRegistry == nil {
+		return errNotReferenced
+	}
+
+	svcList, err := s.proxyRegistry.ListProxyServices(proxy)
+	if err != nil {
+		return err
+	}
+	for _, svc := range svcList {
+		ingressPolicy, err := s.meshCatalog.GetIngressTrafficPolicy(svc)
+		if err != nil || ingressPolicy == nil {
+			continue
+		}
+		for _, trafficMatch := range ingressPolicy.TrafficMatches {
+			if trafficMatch.CertificateSecret == sdscert.Name {
+				return nil
+			}
+		}
+	}
+	return errNotReferenced
+}
+
 func (s *sdsImpl) getRootCert(cert certificate.Certificater, sdscert secrets.SDSCert) (*xds_auth.Secret, error) {
 	secret := &xds_auth.Secret{
 		// The Name field must match the tls_context.common_tls_context.tls_certificate_sds_secret_configs.name
@@ -143,6 +301,21 @@ func (s *sdsImpl) getRootCert(cert certificate.Certificater, sdscert secrets.SDS
 		},
 	}
 
+	// Upstreams in remote clusters are reached through multicluster gateways, which present certificates issued
+	// in the remote cluster's trust domain
+	if store, ok := s.certManager.(certificate.TrustBundleStore); ok && sdscert.CertType == secrets.RootCertTypeForMTLSOutbound && s.cfg.GetFeatureFlags().EnableMulticlusterMode {
+		secret.GetValidationContext().TrustedCa.Specifier = &xds_core.DataSource_InlineBytes{
+			InlineBytes: appendTrustBundles(store, cert.GetIssuingCA()),
+		}
+	}
+
+	// Downstreams in federated trust domains present certificates issued by the CAs of their trust domain
+	if federatedTrustDomains := s.cfg.GetFederatedTrustDomains(); sdscert.CertType == secrets.RootCertTypeForMTLSInbound && len(federatedTrustDomains) > 0 {
+		secret.GetValidationContext().TrustedCa.Specifier = &xds_core.DataSource_InlineBytes{
+			InlineBytes: appendFederatedTrustBundles(federatedTrustDomains, cert.GetIssuingCA()),
+		}
+	}
+
 	// SAN validation should not be performed by the root validation certificate used by the upstream server
 	// to validate a downstream client. This is because of the following:
 	// 1. SAN validation is already performed by the RBAC filter on the inbound listener's filter chain (using
@@ -160,10 +333,100 @@ func (s *sdsImpl) getRootCert(cert certificate.Certificater, sdscert secrets.SDS
 		return nil, err
 	}
 
-	secret.GetValidationContext().MatchSubjectAltNames = getSubjectAltNamesFromSvcIdentities(svcIdentitiesInCertRequest)
+	matchSANs := getSubjectAltNamesFromSvcIdentities(svcIdentitiesInCertRequest)
+	matchSANs = append(matchSANs, getSPIFFEIDsFromSvcIdentities(svcIdentitiesInCertRequest, s.cfg.GetSPIFFETrustDomain())...)
+	secret.GetValidationContext().MatchSubjectAltNames = append(matchSANs, s.getUpstreamPeerValidationSANs(sdscert, svcIdentitiesInCertRequest)...)
 	return secret, nil
 }
 
+// getUpstreamPeerValidationSANs returns the SANs accepted in the certificates of the upstream service of the given
+// outbound root cert, other than those of its identities in the cluster's trust domain: the SANs of its identities in
+// the trust domain aliases, followed by the additional SANs of its peer validation.
+func (s *sdsImpl) getUpstreamPeerValidationSANs(sdscert secrets.SDSCert, svcIdentities []identity.ServiceIdentity) []*xds_matcher.StringMatcher {
+	meshSvc, err := sdscert.GetMeshService()
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrGettingMeshService)).
+			Msgf("Error unmarshalling upstream service for outbound cert %s", sdscert)
+		return nil
+	}
+
+	peerValidation := s.meshCatalog.GetUpstreamPeerValidation(*meshSvc)
+
+	var aliasedIdentities []identity.ServiceIdentity
+	for _, trustDomain := range peerValidation.TrustDomainAliases {
+		if trustDomain == identity.ClusterLocalTrustDomain {
+			continue
+		}
+		for _, svcIdentity := range svcIdentities {
+			aliasedIdentities = append(aliasedIdentities, identity.GetKubernetesServiceIdentity(svcIdentity.ToK8sServiceAccount(), trustDomain))
+		}
+	}
+
+	matchSANs := getSubjectAltNamesFromSvcIdentities(aliasedIdentities)
+	for _, san := range peerValidation.SubjectAltNames {
+		matchSANs = append(matchSANs, &xds_matcher.StringMatcher{
+			MatchPattern: &xds_matcher.StringMatcher_Exact{
+				Exact: san,
+			},
+		})
+	}
+
+	return matchSANs
+}
+
+// appendTrustBundles returns the given CA bundle along with the CA bundles of all known remote trust domains
+func appendTrustBundles(store certificate.TrustBundleStore, caBundle []byte) []byte {
+	bundle := append([]byte{}, caBundle...)
+	for _, trustDomain := range store.ListTrustDomains() {
+		trustBundle, err := store.GetTrustBundle(trustDomain)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error getting trust bundle for trust domain %s", trustDomain)
+			continue
+		}
+		bundle = append(bundle, trustBundle...)
+	}
+	return bundle
+}
+
+// appendFederatedTrustBundles returns the given CA bundle followed by the trust bundles of the given federated trust domains
+func appendFederatedTrustBundles(federatedTrustDomains []configv1alpha1.FederatedTrustDomainSpec, caBundle []byte) []byte {
+	bundle := append([]byte{}, caBundle...)
+	for _, trustDomain := range federatedTrustDomains {
+		if len(bundle) > 0 && !bytes.HasSuffix(bundle, []byte("\n")) {
+			bundle = append(bundle, '\n')
+		}
+		bundle = append(bundle, trustDomain.TrustBundle...)
+	}
+	return bundle
+}
+
+// getTrustDomainRootCert returns the validation context used by the multicluster gateway to validate the certificates
+// of downstreams from the remote trust domain in the given SDS cert
+func (s *sdsImpl) getTrustDomainRootCert(sdscert secrets.SDSCert) (*xds_auth.Secret, error) {
+	store, ok := s.certManager.(certificate.TrustBundleStore)
+	if !ok {
+		return nil, errNoTrustBundleStore
+	}
+
+	trustBundle, err := store.GetTrustBundle(sdscert.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &xds_auth.Secret{
+		Name: sdscert.String(),
+		Type: &xds_auth.Secret_ValidationContext{
+			ValidationContext: &xds_auth.CertificateValidationContext{
+				TrustedCa: &xds_core.DataSource{
+					Specifier: &xds_core.DataSource_InlineBytes{
+						InlineBytes: trustBundle,
+					},
+				},
+			},
+		},
+	}, nil
+}
+
 // Given a requested SDS Cert, this function returns the Service Identities, which match that SDS Cert
 // Example: given "service-cert:namespace/service-account", this will return ServiceIdentity("namespace.service-account.cluster.local")
 func getServiceIdentitiesFromCert(sdscert secrets.SDSCert, serviceIdentity identity.ServiceIdentity, meshCatalog catalog.MeshCataloger) ([]identity.ServiceIdentity, error) {
@@ -235,6 +498,30 @@ func getSubjectAltNamesFromSvcIdentities(serviceIdentities []identity.ServiceIde
 	return matchSANs
 }
 
+// getSPIFFEIDsFromSvcIdentities returns the matchers of the SPIFFE IDs of the given service identities in the given
+// trust domain, which the certificates of the service identities carry as URI SANs. It returns nil if the trust domain
+// is not set, i.e. if the certificates do not carry SPIFFE IDs.
+func getSPIFFEIDsFromSvcIdentities(serviceIdentities []identity.ServiceIdentity, spiffeTrustDomain string) []*xds_matcher.StringMatcher {
+	if spiffeTrustDomain == "" {
+		return nil
+	}
+
+	var matchSANs []*xds_matcher.StringMatcher
+	for _, si := range serviceIdentities {
+		spiffeID := si.SPIFFEID(spiffeTrustDomain)
+		if spiffeID == nil {
+			continue
+		}
+		matchSANs = append(matchSANs, &xds_matcher.StringMatcher{
+			MatchPattern: &xds_matcher.StringMatcher_Exact{
+				Exact: spiffeID.String(),
+			},
+		})
+	}
+
+	return matchSANs
+}
+
 func subjectAltNamesToStr(sanMatchList []*xds_matcher.StringMatcher) []string {
 	var sanStr []string
 
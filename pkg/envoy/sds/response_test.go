@@ -3,6 +3,7 @@ package sds
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
@@ -10,8 +11,11 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	testclient "k8s.io/client-go/kubernetes/fake"
 
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/envoy/secrets"
 	configFake "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned/fake"
 
@@ -20,8 +24,11 @@ import (
 	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/registry"
 	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s"
 	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
 )
 
 // TestNewResponse sets up a fake kube client, then a pod and makes an SDS request,
@@ -138,6 +145,10 @@ func TestGetRootCert(t *testing.T) {
 					Name:      "service-2",
 					Namespace: "ns-2",
 				}).Return(associatedSvcAccounts, nil).Times(1)
+				d.mockCatalog.EXPECT().GetUpstreamPeerValidation(service.MeshService{
+					Name:      "service-2",
+					Namespace: "ns-2",
+				}).Return(&trafficpolicy.UpstreamPeerValidation{}).Times(1)
 				d.mockCertificater.EXPECT().GetIssuingCA().Return([]byte("foo")).Times(1)
 			},
 
@@ -159,6 +170,8 @@ func TestGetRootCert(t *testing.T) {
 				mockConfigurator: configurator.NewMockConfigurator(mockCtrl),
 				mockCertificater: certificate.NewMockCertificater(mockCtrl),
 			}
+			d.mockConfigurator.EXPECT().GetFederatedTrustDomains().Return(nil).AnyTimes()
+			d.mockConfigurator.EXPECT().GetSPIFFETrustDomain().Return("").AnyTimes()
 
 			// Prepare the dynamic mock expectations for each test case
 			if tc.prepare != nil {
@@ -223,6 +236,105 @@ func TestGetServiceCert(t *testing.T) {
 	}
 }
 
+func TestGetIngressCert(t *testing.T) {
+	proxySvc := service.MeshService{Name: "bookstore", Namespace: "ns"}
+	tlsSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "bookstore-tls", Namespace: "ns"},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       []byte("cert-chain"),
+			corev1.TLSPrivateKeyKey: []byte("priv-key"),
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		sdsCertName   string
+		ingressPolicy *trafficpolicy.IngressTrafficPolicy
+		secret        *corev1.Secret
+		expectedErr   error
+	}{
+		{
+			name:        "secret referenced by the IngressBackend of the proxy's service",
+			sdsCertName: "ns/bookstore-tls",
+			ingressPolicy: &trafficpolicy.IngressTrafficPolicy{
+				TrafficMatches: []*trafficpolicy.IngressTrafficMatch{{CertificateSecret: "ns/bookstore-tls"}},
+			},
+			secret:      tlsSecret,
+			expectedErr: nil,
+		},
+		{
+			name:        "secret not referenced by the IngressBackend of the proxy's service",
+			sdsCertName: "other/bookstore-tls",
+			ingressPolicy: &trafficpolicy.IngressTrafficPolicy{
+				TrafficMatches: []*trafficpolicy.IngressTrafficMatch{{CertificateSecret: "ns/bookstore-tls"}},
+			},
+			secret:      tlsSecret,
+			expectedErr: errNotReferenced,
+		},
+		{
+			name:          "no ingress policy for the proxy's service",
+			sdsCertName:   "ns/bookstore-tls",
+			ingressPolicy: nil,
+			secret:        tlsSecret,
+			expectedErr:   errNotReferenced,
+		},
+		{
+			name:        "secret not found",
+			sdsCertName: "ns/bookstore-tls",
+			ingressPolicy: &trafficpolicy.IngressTrafficPolicy{
+				TrafficMatches: []*trafficpolicy.IngressTrafficMatch{{CertificateSecret: "ns/bookstore-tls"}},
+			},
+			secret:      nil,
+			expectedErr: errSecretNotFound,
+		},
+		{
+			name:        "secret without a private key",
+			sdsCertName: "ns/bookstore-tls",
+			ingressPolicy: &trafficpolicy.IngressTrafficPolicy{
+				TrafficMatches: []*trafficpolicy.IngressTrafficMatch{{CertificateSecret: "ns/bookstore-tls"}},
+			},
+			secret: &corev1.Secret{
+				Data: map[string][]byte{corev1.TLSCertKey: []byte("cert-chain")},
+			},
+			expectedErr: errInvalidTLSSecret,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mockCatalog.EXPECT().GetIngressTrafficPolicy(proxySvc).Return(tc.ingressPolicy, nil).AnyTimes()
+			mockCatalog.EXPECT().GetKubeController().Return(mockKubeController).AnyTimes()
+			mockKubeController.EXPECT().GetSecret("ns", "bookstore-tls").Return(tc.secret).AnyTimes()
+
+			proxy, err := envoy.NewProxy(certificate.CommonName(fmt.Sprintf("%s.sidecar.bookstore.ns.cluster.local", uuid.New())), "1", nil)
+			assert.Nil(err)
+
+			s := &sdsImpl{
+				meshCatalog: mockCatalog,
+				proxyRegistry: registry.NewProxyRegistry(registry.ExplicitProxyServiceMapper(func(*envoy.Proxy) ([]service.MeshService, error) {
+					return []service.MeshService{proxySvc}, nil
+				})),
+			}
+
+			sdsCert := secrets.SDSCert{Name: tc.sdsCertName, CertType: secrets.IngressCertType}
+			actual, err := s.getIngressCert(sdsCert, proxy)
+			assert.Equal(tc.expectedErr, err)
+			if err == nil {
+				assert.Equal("ingress-cert:ns/bookstore-tls", actual.Name)
+				assert.Equal([]byte("cert-chain"), actual.GetTlsCertificate().GetCertificateChain().GetInlineBytes())
+				assert.Equal([]byte("priv-key"), actual.GetTlsCertificate().GetPrivateKey().GetInlineBytes())
+			}
+		})
+	}
+}
+
 func TestGetSDSSecrets(t *testing.T) {
 	assert := tassert.New(t)
 	mockCtrl := gomock.NewController(t)
@@ -289,6 +401,7 @@ func TestGetSDSSecrets(t *testing.T) {
 					Namespace: "ns-2",
 				}
 				d.mockCatalog.EXPECT().ListServiceIdentitiesForService(svc).Return(associatedSvcAccounts, nil).Times(1)
+				d.mockCatalog.EXPECT().GetUpstreamPeerValidation(svc).Return(&trafficpolicy.UpstreamPeerValidation{}).Times(1)
 				d.mockCertificater.EXPECT().GetIssuingCA().Return([]byte("foo")).Times(1)
 			},
 
@@ -348,6 +461,8 @@ func TestGetSDSSecrets(t *testing.T) {
 				mockConfigurator: configurator.NewMockConfigurator(mockCtrl),
 				mockCertificater: certificate.NewMockCertificater(mockCtrl),
 			}
+			d.mockConfigurator.EXPECT().GetFederatedTrustDomains().Return(nil).AnyTimes()
+			d.mockConfigurator.EXPECT().GetSPIFFETrustDomain().Return("").AnyTimes()
 
 			// Prepare the dynamic mock expectations for each test case
 			if tc.prepare != nil {
@@ -398,6 +513,219 @@ func TestGetSDSSecrets(t *testing.T) {
 	}
 }
 
+func TestGetSDSSecretsWithTrustDomains(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockCertificater := certificate.NewMockCertificater(mockCtrl)
+	mockCertManager := certificate.NewMockManager(mockCtrl)
+	mockTrustBundleStore := certificate.NewMockTrustBundleStore(mockCtrl)
+	certManager := struct {
+		certificate.Manager
+		certificate.TrustBundleStore
+	}{mockCertManager, mockTrustBundleStore}
+
+	mockConfigurator.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{EnableMulticlusterMode: true}).AnyTimes()
+	mockConfigurator.EXPECT().GetServiceCertValidityPeriod().Return(time.Hour).AnyTimes()
+	mockConfigurator.EXPECT().GetFederatedTrustDomains().Return(nil).AnyTimes()
+	mockConfigurator.EXPECT().GetSPIFFETrustDomain().Return("").AnyTimes()
+	mockTrustBundleStore.EXPECT().ListTrustDomains().Return([]string{"cluster-b.example.com"}).AnyTimes()
+	mockTrustBundleStore.EXPECT().GetTrustBundle("cluster-b.example.com").Return([]byte("ca-b"), nil).AnyTimes()
+
+	s := &sdsImpl{
+		serviceIdentity: identity.K8sServiceAccount{Name: "gateway", Namespace: "osm-system"}.ToServiceIdentity(),
+		certManager:     certManager,
+		meshCatalog:     mockCatalog,
+		cfg:             mockConfigurator,
+	}
+
+	gatewayProxy, err := envoy.NewProxy(certificate.CommonName(fmt.Sprintf("%s.%s.%s.%s", uuid.New(), envoy.KindGateway, "gateway", "osm-system")), "123456", nil)
+	assert.Nil(err)
+	sidecarProxy, err := envoy.NewProxy(certificate.CommonName(fmt.Sprintf("%s.%s.%s.%s", uuid.New(), envoy.KindSidecar, "sa-1", "ns-1")), "123456", nil)
+	assert.Nil(err)
+
+	// The gateway validates downstreams from a remote trust domain using that trust domain's CA bundle
+	sdsSecrets := s.getSDSSecrets(mockCertificater, []string{"root-cert-for-trust-domain:cluster-b.example.com"}, gatewayProxy)
+	assert.Len(sdsSecrets, 1)
+	assert.Equal("root-cert-for-trust-domain:cluster-b.example.com", sdsSecrets[0].Name)
+	assert.Equal([]byte("ca-b"), sdsSecrets[0].GetValidationContext().GetTrustedCa().GetInlineBytes())
+
+	// Sidecars cannot request the CA bundles of remote trust domains
+	sdsSecrets = s.getSDSSecrets(mockCertificater, []string{"root-cert-for-trust-domain:cluster-b.example.com"}, sidecarProxy)
+	assert.Empty(sdsSecrets)
+
+	// The gateway presents the certificate of the exported upstream service to downstreams from remote trust domains
+	mockCatalog.EXPECT().ListExportedServicesForMulticlusterGateway().Return([]service.MeshService{{Name: "service-2", Namespace: "ns-2"}}).Times(2)
+	mockCatalog.EXPECT().ListServiceIdentitiesForService(service.MeshService{Name: "service-2", Namespace: "ns-2"}).
+		Return([]identity.ServiceIdentity{identity.K8sServiceAccount{Name: "sa-2", Namespace: "ns-2"}.ToServiceIdentity()}, nil).Times(2)
+	upstreamCert := certificate.NewMockCertificater(mockCtrl)
+	upstreamCert.EXPECT().GetCertificateChain().Return([]byte("upstream-chain")).Times(1)
+	upstreamCert.EXPECT().GetPrivateKey().Return([]byte("upstream-key")).Times(1)
+	mockCertManager.EXPECT().IssueCertificate(certificate.CommonName("sa-2.ns-2.cluster.local"), time.Hour).Return(upstreamCert, nil).Times(1)
+
+	sdsSecrets = s.getSDSSecrets(mockCertificater, []string{"service-cert:ns-2/sa-2"}, gatewayProxy)
+	assert.Len(sdsSecrets, 1)
+	assert.Equal([]byte("upstream-chain"), sdsSecrets[0].GetTlsCertificate().GetCertificateChain().GetInlineBytes())
+
+	// The gateway cannot mint the certificate of a service that is not exported
+	sdsSecrets = s.getSDSSecrets(mockCertificater, []string{"service-cert:ns-3/sa-3"}, gatewayProxy)
+	assert.Empty(sdsSecrets)
+
+	// Sidecars trust the CA bundles of remote trust domains for upstreams reached through remote gateways
+	s.serviceIdentity = identity.K8sServiceAccount{Name: "sa-1", Namespace: "ns-1"}.ToServiceIdentity()
+	mockCertificater.EXPECT().GetIssuingCA().Return([]byte("ca-a")).AnyTimes()
+	mockCatalog.EXPECT().ListServiceIdentitiesForService(service.MeshService{Name: "service-2", Namespace: "ns-2"}).
+		Return([]identity.ServiceIdentity{identity.K8sServiceAccount{Name: "sa-2", Namespace: "ns-2"}.ToServiceIdentity()}, nil).Times(1)
+	mockCatalog.EXPECT().GetUpstreamPeerValidation(service.MeshService{Name: "service-2", Namespace: "ns-2"}).
+		Return(&trafficpolicy.UpstreamPeerValidation{}).Times(1)
+
+	sdsSecrets = s.getSDSSecrets(mockCertificater, []string{"root-cert-for-mtls-outbound:ns-2/service-2"}, sidecarProxy)
+	assert.Len(sdsSecrets, 1)
+	assert.Equal([]byte("ca-aca-b"), sdsSecrets[0].GetValidationContext().GetTrustedCa().GetInlineBytes())
+}
+
+func TestGetRootCertWithUpstreamPeerValidation(t *testing.T) {
+	upstreamSvc := service.MeshService{Name: "service-2", Namespace: "ns-2"}
+	upstreamIdentities := []identity.ServiceIdentity{
+		identity.K8sServiceAccount{Name: "sa-2", Namespace: "ns-2"}.ToServiceIdentity(),
+		identity.K8sServiceAccount{Name: "sa-3", Namespace: "ns-2"}.ToServiceIdentity(),
+	}
+
+	testCases := []struct {
+		name           string
+		peerValidation *trafficpolicy.UpstreamPeerValidation
+		expectedSANs   []string
+	}{
+		{
+			name:           "no peer validation customization",
+			peerValidation: &trafficpolicy.UpstreamPeerValidation{},
+			expectedSANs:   []string{"sa-2.ns-2.cluster.local", "sa-3.ns-2.cluster.local"},
+		},
+		{
+			name: "trust domain aliases",
+			peerValidation: &trafficpolicy.UpstreamPeerValidation{
+				TrustDomainAliases: []string{"cluster.local", "old.example.com"},
+			},
+			expectedSANs: []string{
+				"sa-2.ns-2.cluster.local", "sa-3.ns-2.cluster.local",
+				"sa-2.ns-2.old.example.com", "sa-3.ns-2.old.example.com",
+			},
+		},
+		{
+			name: "trust domain aliases and additional SANs",
+			peerValidation: &trafficpolicy.UpstreamPeerValidation{
+				TrustDomainAliases: []string{"old.example.com"},
+				SubjectAltNames:    []string{"spiffe://old.example.com/ns/ns-2/sa/sa-2"},
+			},
+			expectedSANs: []string{
+				"sa-2.ns-2.cluster.local", "sa-3.ns-2.cluster.local",
+				"sa-2.ns-2.old.example.com", "sa-3.ns-2.old.example.com",
+				"spiffe://old.example.com/ns/ns-2/sa/sa-2",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockCertificater := certificate.NewMockCertificater(mockCtrl)
+
+			mockCatalog.EXPECT().ListServiceIdentitiesForService(upstreamSvc).Return(upstreamIdentities, nil)
+			mockCatalog.EXPECT().GetUpstreamPeerValidation(upstreamSvc).Return(tc.peerValidation)
+			mockConfigurator.EXPECT().GetFederatedTrustDomains().Return(nil)
+			mockConfigurator.EXPECT().GetSPIFFETrustDomain().Return("")
+			mockCertificater.EXPECT().GetIssuingCA().Return([]byte("foo"))
+
+			s := &sdsImpl{
+				serviceIdentity: identity.K8sServiceAccount{Name: "sa-1", Namespace: "ns-1"}.ToServiceIdentity(),
+				certManager:     certificate.NewMockManager(mockCtrl),
+				meshCatalog:     mockCatalog,
+				cfg:             mockConfigurator,
+			}
+
+			sdsSecret, err := s.getRootCert(mockCertificater, secrets.SDSCert{Name: upstreamSvc.String(), CertType: secrets.RootCertTypeForMTLSOutbound})
+			assert.Nil(err)
+			assert.Equal(tc.expectedSANs, subjectAltNamesToStr(sdsSecret.GetValidationContext().GetMatchSubjectAltNames()))
+		})
+	}
+}
+
+func TestGetRootCertWithFederatedTrustDomains(t *testing.T) {
+	federatedTrustDomains := []v1alpha1.FederatedTrustDomainSpec{
+		{TrustDomain: "example.org", TrustBundle: "bar\n"},
+		{TrustDomain: "example.com", TrustBundle: "baz"},
+	}
+
+	testCases := []struct {
+		name                  string
+		certType              secrets.SDSCertType
+		federatedTrustDomains []v1alpha1.FederatedTrustDomainSpec
+		expectedTrustedCA     []byte
+	}{
+		{
+			name:                  "inbound root cert without federated trust domains",
+			certType:              secrets.RootCertTypeForMTLSInbound,
+			federatedTrustDomains: nil,
+			expectedTrustedCA:     []byte("foo"),
+		},
+		{
+			name:                  "inbound root cert with federated trust domains",
+			certType:              secrets.RootCertTypeForMTLSInbound,
+			federatedTrustDomains: federatedTrustDomains,
+			expectedTrustedCA:     []byte("foo\nbar\nbaz"),
+		},
+		{
+			name:                  "outbound root cert does not trust federated trust domains",
+			certType:              secrets.RootCertTypeForMTLSOutbound,
+			federatedTrustDomains: federatedTrustDomains,
+			expectedTrustedCA:     []byte("foo"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockCertificater := certificate.NewMockCertificater(mockCtrl)
+
+			serviceIdentity := identity.K8sServiceAccount{Name: "sa-1", Namespace: "ns-1"}.ToServiceIdentity()
+			sdsCert := secrets.SDSCert{Name: "ns-1/sa-1", CertType: tc.certType}
+			upstreamSvc := service.MeshService{Name: "sa-1", Namespace: "ns-1"}
+			if tc.certType == secrets.RootCertTypeForMTLSOutbound {
+				sdsCert.Name = upstreamSvc.String()
+				mockCatalog.EXPECT().ListServiceIdentitiesForService(upstreamSvc).Return([]identity.ServiceIdentity{serviceIdentity}, nil)
+				mockCatalog.EXPECT().GetUpstreamPeerValidation(upstreamSvc).Return(&trafficpolicy.UpstreamPeerValidation{})
+				mockConfigurator.EXPECT().GetSPIFFETrustDomain().Return("")
+			}
+			mockConfigurator.EXPECT().GetFederatedTrustDomains().Return(tc.federatedTrustDomains)
+			mockCertificater.EXPECT().GetIssuingCA().Return([]byte("foo")).AnyTimes()
+
+			s := &sdsImpl{
+				serviceIdentity: serviceIdentity,
+				certManager:     certificate.NewMockManager(mockCtrl),
+				meshCatalog:     mockCatalog,
+				cfg:             mockConfigurator,
+			}
+
+			sdsSecret, err := s.getRootCert(mockCertificater, sdsCert)
+			assert.Nil(err)
+			assert.Equal(tc.expectedTrustedCA, sdsSecret.GetValidationContext().GetTrustedCa().GetInlineBytes())
+		})
+	}
+}
+
 func TestGetSubjectAltNamesFromSvcAccount(t *testing.T) {
 	type testCase struct {
 		serviceIdentities   []identity.ServiceIdentity
@@ -435,6 +763,50 @@ func TestGetSubjectAltNamesFromSvcAccount(t *testing.T) {
 	}
 }
 
+func TestGetSPIFFEIDsFromSvcIdentities(t *testing.T) {
+	serviceIdentities := []identity.ServiceIdentity{
+		identity.K8sServiceAccount{Name: "sa-1", Namespace: "ns-1"}.ToServiceIdentity(),
+		identity.K8sServiceAccount{Name: "sa-2", Namespace: "ns-2"}.ToServiceIdentity(),
+	}
+
+	testCases := []struct {
+		name                string
+		spiffeTrustDomain   string
+		expectedSANMatchers []*xds_matcher.StringMatcher
+	}{
+		{
+			name:                "SPIFFE IDs disabled",
+			spiffeTrustDomain:   "",
+			expectedSANMatchers: nil,
+		},
+		{
+			name:              "SPIFFE IDs enabled",
+			spiffeTrustDomain: "example.com",
+			expectedSANMatchers: []*xds_matcher.StringMatcher{
+				{
+					MatchPattern: &xds_matcher.StringMatcher_Exact{
+						Exact: "spiffe://example.com/ns/ns-1/sa/sa-1",
+					},
+				},
+				{
+					MatchPattern: &xds_matcher.StringMatcher_Exact{
+						Exact: "spiffe://example.com/ns/ns-2/sa/sa-2",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			actual := getSPIFFEIDsFromSvcIdentities(serviceIdentities, tc.spiffeTrustDomain)
+			assert.Equal(tc.expectedSANMatchers, actual)
+		})
+	}
+}
+
 func TestSubjectAltNamesToStr(t *testing.T) {
 	type testCase struct {
 		sanMatchers []*xds_matcher.StringMatcher
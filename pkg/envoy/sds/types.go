@@ -5,6 +5,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/catalog"
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy/registry"
 	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/logger"
 )
@@ -19,4 +20,5 @@ type sdsImpl struct {
 	meshCatalog     catalog.MeshCataloger
 	cfg             configurator.Configurator
 	certManager     certificate.Manager
+	proxyRegistry   *registry.ProxyRegistry
 }
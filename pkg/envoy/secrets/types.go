@@ -43,6 +43,14 @@ const (
 
 	// RootCertTypeForMTLSInbound is the prefix for the mTLS root certificate resource name for downstream connectivity. Example: "root-cert-for-mtls-inbound:ns/name"
 	RootCertTypeForMTLSInbound SDSCertType = "root-cert-for-mtls-inbound"
+
+	// RootCertTypeForTrustDomain is the prefix for the root certificate resource name used by the multicluster gateway to validate
+	// downstreams from a remote trust domain. Example: "root-cert-for-trust-domain:cluster-b.example.com"
+	RootCertTypeForTrustDomain SDSCertType = "root-cert-for-trust-domain"
+
+	// IngressCertType is the prefix for the resource name of the certificate held by a Kubernetes TLS secret, presented
+	// by an ingress backend to its clients. Example: "ingress-cert:ns/secret-name"
+	IngressCertType SDSCertType = "ingress-cert"
 )
 
 // Defines valid cert types
@@ -50,4 +58,6 @@ var validCertTypes = map[SDSCertType]struct{}{
 	ServiceCertType:             {},
 	RootCertTypeForMTLSOutbound: {},
 	RootCertTypeForMTLSInbound:  {},
+	RootCertTypeForTrustDomain:  {},
+	IngressCertType:             {},
 }
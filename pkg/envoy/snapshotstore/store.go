@@ -0,0 +1,237 @@
+package snapshotstore
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/utils"
+)
+
+const (
+	// SnapshotKey is the key in the snapshot ConfigMap holding the encoded snapshot
+	SnapshotKey = "snapshot.pb"
+
+	// snapshotConfigMapPrefix is the prefix of the names of ConfigMaps storing snapshots
+	snapshotConfigMapPrefix = "osm-xds-snapshot-"
+
+	// snapshotLabelKey is the label identifying ConfigMaps storing snapshots
+	snapshotLabelKey = "xds.openservicemesh.io/snapshot"
+
+	// snapshotKeyAnnotation is the annotation of a snapshot ConfigMap holding the key of the snapshot
+	snapshotKeyAnnotation = "xds.openservicemesh.io/snapshot-key"
+
+	// maxConfigMapSnapshotSize is the maximum size of a snapshot that can be stored in a ConfigMap
+	maxConfigMapSnapshotSize = 1024 * 1024
+
+	// snapshotFileSuffix is the suffix of the names of files storing snapshots
+	snapshotFileSuffix = ".pb"
+)
+
+var errSnapshotTooLarge = errors.Errorf("Snapshot exceeds the maximum size of %d bytes", maxConfigMapSnapshotSize)
+
+// encodeSnapshot returns the given snapshot encoded as a DiscoveryResponse holding the resources of all the types.
+// SDS resources are never persisted as they hold private keys.
+func encodeSnapshot(snapshot Snapshot) ([]byte, error) {
+	response := &xds_discovery.DiscoveryResponse{}
+	for _, typeURI := range envoy.XDSResponseOrder {
+		if typeURI == envoy.TypeSDS {
+			continue
+		}
+		for _, resource := range snapshot[typeURI] {
+			pbResource, err := ptypes.MarshalAny(resource)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Error marshaling %s resource", typeURI.Short())
+			}
+			response.Resources = append(response.Resources, pbResource)
+		}
+	}
+
+	data, err := proto.Marshal(response)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error marshaling snapshot")
+	}
+	return data, nil
+}
+
+// decodeSnapshot returns the snapshot encoded in the given data by encodeSnapshot
+func decodeSnapshot(data []byte) (Snapshot, error) {
+	response := &xds_discovery.DiscoveryResponse{}
+	if err := proto.Unmarshal(data, response); err != nil {
+		return nil, errors.Wrap(err, "Error unmarshaling snapshot")
+	}
+
+	snapshot := make(Snapshot)
+	for _, pbResource := range response.Resources {
+		typeURI, ok := envoy.ValidURI[pbResource.TypeUrl]
+		if !ok {
+			return nil, errors.Errorf("Unknown resource type %s in snapshot", pbResource.TypeUrl)
+		}
+
+		resource, err := ptypes.Empty(pbResource)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error creating %s resource", typeURI.Short())
+		}
+		if err := ptypes.UnmarshalAny(pbResource, resource); err != nil {
+			return nil, errors.Wrapf(err, "Error unmarshaling %s resource", typeURI.Short())
+		}
+		snapshot[typeURI] = append(snapshot[typeURI], types.Resource(resource))
+	}
+	return snapshot, nil
+}
+
+// getSnapshotName returns the name, safe to use as a Kubernetes object or file name, of the snapshot with the given key
+func getSnapshotName(key string) string {
+	hash, err := utils.HashFromString(key)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Failed to get hash for snapshot key %s, 0 hash will be used", key)
+	}
+	return fmt.Sprintf("%016x", hash)
+}
+
+// configMapStore is a Store persisting snapshots in ConfigMaps in the OSM namespace
+type configMapStore struct {
+	kubeClient kubernetes.Interface
+	namespace  string
+}
+
+// NewConfigMapStore returns a Store persisting snapshots in ConfigMaps in the given namespace
+func NewConfigMapStore(kubeClient kubernetes.Interface, namespace string) Store {
+	return &configMapStore{
+		kubeClient: kubeClient,
+		namespace:  namespace,
+	}
+}
+
+// Save persists the given snapshot in the ConfigMap of the given key, creating it if it does not exist
+func (s *configMapStore) Save(key string, snapshot Snapshot) error {
+	data, err := encodeSnapshot(snapshot)
+	if err != nil {
+		return err
+	}
+	if len(data) > maxConfigMapSnapshotSize {
+		return errSnapshotTooLarge
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      snapshotConfigMapPrefix + getSnapshotName(key),
+			Namespace: s.namespace,
+			Labels: map[string]string{
+				constants.OSMAppNameLabelKey: constants.OSMAppNameLabelValue,
+				snapshotLabelKey:             "true",
+			},
+			Annotations: map[string]string{
+				snapshotKeyAnnotation: key,
+			},
+		},
+		BinaryData: map[string][]byte{
+			SnapshotKey: data,
+		},
+	}
+
+	configMaps := s.kubeClient.CoreV1().ConfigMaps(s.namespace)
+	_, err = configMaps.Update(context.Background(), configMap, metav1.UpdateOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = configMaps.Create(context.Background(), configMap, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return errors.Wrapf(err, "Error saving ConfigMap %s/%s for xDS snapshot %s", configMap.Namespace, configMap.Name, key)
+	}
+	return nil
+}
+
+// Load returns the snapshot persisted in the ConfigMap of the given key, nil if the ConfigMap does not exist
+func (s *configMapStore) Load(key string) (Snapshot, error) {
+	name := snapshotConfigMapPrefix + getSnapshotName(key)
+	configMap, err := s.kubeClient.CoreV1().ConfigMaps(s.namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error getting ConfigMap %s/%s for xDS snapshot %s", s.namespace, name, key)
+	}
+
+	// Guard against hash collisions between keys
+	if configMap.Annotations[snapshotKeyAnnotation] != key {
+		return nil, nil
+	}
+
+	data, ok := configMap.BinaryData[SnapshotKey]
+	if !ok {
+		return nil, errors.Errorf("ConfigMap %s/%s for xDS snapshot %s has no %s key", s.namespace, name, key, SnapshotKey)
+	}
+	return decodeSnapshot(data)
+}
+
+// fileStore is a Store persisting snapshots in files of a local directory
+type fileStore struct {
+	dir string
+}
+
+// NewFileStore returns a Store persisting snapshots in files of the given directory, created if it does not exist
+func NewFileStore(dir string) (Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrapf(err, "Error creating xDS snapshot directory %s", dir)
+	}
+	return &fileStore{dir: dir}, nil
+}
+
+// Save persists the given snapshot in the file of the given key, replacing it atomically
+func (s *fileStore) Save(key string, snapshot Snapshot) error {
+	data, err := encodeSnapshot(snapshot)
+	if err != nil {
+		return err
+	}
+
+	path := s.getPath(key)
+	tmpFile, err := ioutil.TempFile(s.dir, filepath.Base(path))
+	if err != nil {
+		return errors.Wrapf(err, "Error creating temporary file for xDS snapshot %s", key)
+	}
+	defer os.Remove(tmpFile.Name()) //nolint: errcheck
+
+	if _, err := tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+		return errors.Wrapf(err, "Error writing xDS snapshot %s", key)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return errors.Wrapf(err, "Error writing xDS snapshot %s", key)
+	}
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return errors.Wrapf(err, "Error saving xDS snapshot %s to %s", key, path)
+	}
+	return nil
+}
+
+// Load returns the snapshot persisted in the file of the given key, nil if the file does not exist
+func (s *fileStore) Load(key string) (Snapshot, error) {
+	path := s.getPath(key)
+	data, err := ioutil.ReadFile(filepath.Clean(path))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error reading xDS snapshot %s from %s", key, path)
+	}
+	return decodeSnapshot(data)
+}
+
+// getPath returns the path of the file storing the snapshot of the given key
+func (s *fileStore) getPath(key string) string {
+	return filepath.Join(s.dir, getSnapshotName(key)+snapshotFileSuffix)
+}
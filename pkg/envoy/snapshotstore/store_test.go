@@ -0,0 +1,123 @@
+package snapshotstore
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	xds_cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	xds_listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	xds_auth "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/golang/protobuf/proto"
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+func newTestSnapshot(clusterName string) Snapshot {
+	return Snapshot{
+		envoy.TypeCDS: []types.Resource{&xds_cluster.Cluster{Name: clusterName}},
+		envoy.TypeLDS: []types.Resource{&xds_listener.Listener{Name: "outbound-listener"}},
+		envoy.TypeSDS: []types.Resource{&xds_auth.Secret{Name: "service-cert:ns/sa"}},
+	}
+}
+
+func assertSnapshotEqual(assert *tassert.Assertions, expected Snapshot, actual Snapshot) {
+	assert.Len(actual, len(expected))
+	for typeURI, resources := range expected {
+		assert.Len(actual[typeURI], len(resources))
+		for i := range resources {
+			assert.True(proto.Equal(resources[i], actual[typeURI][i]), "%s resource %d differs", typeURI, i)
+		}
+	}
+}
+
+func TestEncodeDecodeSnapshot(t *testing.T) {
+	assert := tassert.New(t)
+
+	snapshot := newTestSnapshot("bookstore")
+	data, err := encodeSnapshot(snapshot)
+	assert.Nil(err)
+
+	decoded, err := decodeSnapshot(data)
+	assert.Nil(err)
+
+	// SDS resources are never persisted
+	delete(snapshot, envoy.TypeSDS)
+	assertSnapshotEqual(assert, snapshot, decoded)
+
+	_, err = decodeSnapshot([]byte("invalid"))
+	assert.NotNil(err)
+}
+
+func TestConfigMapStore(t *testing.T) {
+	assert := tassert.New(t)
+
+	kubeClient := testclient.NewSimpleClientset()
+	store := NewConfigMapStore(kubeClient, "osm-system")
+	key := "sidecar/sa.ns.cluster.local"
+
+	// No snapshot saved yet
+	snapshot, err := store.Load(key)
+	assert.Nil(err)
+	assert.Nil(snapshot)
+
+	// Save creates the ConfigMap
+	assert.Nil(store.Save(key, newTestSnapshot("bookstore")))
+	configMaps, err := kubeClient.CoreV1().ConfigMaps("osm-system").List(context.Background(), metav1.ListOptions{LabelSelector: snapshotLabelKey + "=true"})
+	assert.Nil(err)
+	assert.Len(configMaps.Items, 1)
+	assert.Equal(key, configMaps.Items[0].Annotations[snapshotKeyAnnotation])
+
+	// Save updates the existing ConfigMap
+	expected := newTestSnapshot("bookbuyer")
+	assert.Nil(store.Save(key, expected))
+	configMaps, err = kubeClient.CoreV1().ConfigMaps("osm-system").List(context.Background(), metav1.ListOptions{})
+	assert.Nil(err)
+	assert.Len(configMaps.Items, 1)
+
+	snapshot, err = store.Load(key)
+	assert.Nil(err)
+	delete(expected, envoy.TypeSDS)
+	assertSnapshotEqual(assert, expected, snapshot)
+
+	// Snapshots of other keys are not found
+	snapshot, err = store.Load("gateway/sa.ns.cluster.local")
+	assert.Nil(err)
+	assert.Nil(snapshot)
+}
+
+func TestFileStore(t *testing.T) {
+	assert := tassert.New(t)
+
+	dir, err := ioutil.TempDir("", "xds-snapshots")
+	assert.Nil(err)
+	defer os.RemoveAll(dir) //nolint: errcheck
+
+	store, err := NewFileStore(dir)
+	assert.Nil(err)
+	key := "sidecar/sa.ns.cluster.local"
+
+	// No snapshot saved yet
+	snapshot, err := store.Load(key)
+	assert.Nil(err)
+	assert.Nil(snapshot)
+
+	assert.Nil(store.Save(key, newTestSnapshot("bookstore")))
+	expected := newTestSnapshot("bookbuyer")
+	assert.Nil(store.Save(key, expected))
+
+	// Only the snapshot file remains, temporary files are removed
+	files, err := ioutil.ReadDir(dir)
+	assert.Nil(err)
+	assert.Len(files, 1)
+
+	snapshot, err = store.Load(key)
+	assert.Nil(err)
+	delete(expected, envoy.TypeSDS)
+	assertSnapshotEqual(assert, expected, snapshot)
+}
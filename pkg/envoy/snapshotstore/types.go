@@ -0,0 +1,35 @@
+// Package snapshotstore implements the persistence of the last xDS resources generated for each kind of proxy and
+// service identity, so that a restarted control plane can serve the last known good config to reconnecting proxies
+// before its own caches are warm.
+package snapshotstore
+
+import (
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var (
+	log = logger.New("snapshot-store")
+)
+
+const (
+	// StoreKindConfigMap is the kind of the store persisting snapshots in ConfigMaps
+	StoreKindConfigMap = "configmap"
+
+	// StoreKindFile is the kind of the store persisting snapshots in files of a local directory
+	StoreKindFile = "file"
+)
+
+// Snapshot is the set of xDS resources of each type last generated for a kind of proxy and service identity
+type Snapshot map[envoy.TypeURI][]types.Resource
+
+// Store persists snapshots keyed by the kind of proxy and service identity they were generated for
+type Store interface {
+	// Save persists the given snapshot under the given key, replacing the snapshot previously saved under it
+	Save(key string, snapshot Snapshot) error
+
+	// Load returns the snapshot saved under the given key, nil if there is none
+	Load(key string) (Snapshot, error)
+}
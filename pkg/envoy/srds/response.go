@@ -0,0 +1,40 @@
+package srds
+
+import (
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/rds/route"
+	"github.com/openservicemesh/osm/pkg/envoy/registry"
+	"github.com/openservicemesh/osm/pkg/errcode"
+)
+
+// NewResponse creates a new Scoped Route Discovery Response.
+// With scoped routes, the outbound HTTP connection manager of the proxy selects the route configuration of a request
+// by its host, among the scoped route configurations mapping the hostnames of the upstream services of the proxy to
+// their route configurations. The route configurations are only fetched through RDS on the first request to one of
+// their hostnames, so that the proxy never fetches the routes of the hosts it does not address.
+// No scoped route configurations are built when scoped routes are disabled.
+func NewResponse(cataloger catalog.MeshCataloger, proxy *envoy.Proxy, _ *xds_discovery.DiscoveryRequest, cfg configurator.Configurator, _ certificate.Manager, _ *registry.ProxyRegistry) ([]types.Resource, error) {
+	if !route.IsScopedRoutesEnabled(cfg) {
+		return nil, nil
+	}
+
+	proxyIdentity, err := envoy.GetServiceIdentityFromProxyCertificate(proxy.GetCertificateCommonName())
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetricForProxy(errcode.ErrGettingServiceIdentity, proxy.GetCertificateCommonName())).
+			Msgf("Error looking up Service Account for Envoy with serial number=%q", proxy.GetCertificateSerialNumber())
+		return nil, err
+	}
+
+	var srdsResources []types.Resource
+	for _, scopedRouteConfig := range route.BuildOutboundScopedRouteConfigurations(cataloger.ListOutboundTrafficPolicies(proxyIdentity)) {
+		srdsResources = append(srdsResources, scopedRouteConfig)
+	}
+
+	return srdsResources, nil
+}
@@ -0,0 +1,73 @@
+package srds
+
+import (
+	"fmt"
+	"testing"
+
+	xds_route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+func TestNewResponse(t *testing.T) {
+	outbound := []*trafficpolicy.OutboundTrafficPolicy{
+		{
+			Name:      "bookstore-v1.default.svc.cluster.local",
+			Hostnames: []string{"bookstore-v1.default", "bookstore-v1.default.svc.cluster.local"},
+		},
+	}
+
+	testCases := []struct {
+		name              string
+		featureFlags      v1alpha1.FeatureFlags
+		expectedResources int
+	}{
+		{
+			name:              "scoped routes disabled",
+			featureFlags:      v1alpha1.FeatureFlags{},
+			expectedResources: 0,
+		},
+		{
+			name:              "scoped routes enabled",
+			featureFlags:      v1alpha1.FeatureFlags{EnableScopedRoutes: true},
+			expectedResources: 2,
+		},
+		{
+			name:              "scoped routes enabled with the snapshot cache",
+			featureFlags:      v1alpha1.FeatureFlags{EnableScopedRoutes: true, EnableSnapshotCacheMode: true},
+			expectedResources: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+
+			mockConfigurator.EXPECT().GetFeatureFlags().Return(tc.featureFlags).AnyTimes()
+			mockCatalog.EXPECT().ListOutboundTrafficPolicies(gomock.Any()).Return(outbound).AnyTimes()
+
+			proxy, err := envoy.NewProxy(certificate.CommonName(fmt.Sprintf("%s.%s.%s.one.two.three.co.uk", uuid.New(), "some-service", "some-namespace")), "123456", nil)
+			assert.Nil(err)
+
+			resources, err := NewResponse(mockCatalog, proxy, nil, mockConfigurator, nil, nil)
+			assert.Nil(err)
+			assert.Len(resources, tc.expectedResources)
+			for _, res := range resources {
+				scopedRouteConfig, ok := res.(*xds_route.ScopedRouteConfiguration)
+				assert.True(ok)
+				assert.Equal("rds-outbound.bookstore-v1.default.svc.cluster.local", scopedRouteConfig.RouteConfigurationName)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+// Package srds implements Envoy's Scoped Route Discovery Service (SRDS).
+package srds
+
+import (
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var (
+	log = logger.New("envoy/srds")
+)
@@ -7,9 +7,9 @@ import (
 )
 
 var (
-	// XDSResponseOrder is the order in which we send xDS responses: CDS, EDS, LDS, RDS
+	// XDSResponseOrder is the order in which we send xDS responses: CDS, EDS, LDS, SRDS, RDS
 	// See: https://github.com/envoyproxy/go-control-plane/issues/59
-	XDSResponseOrder = []TypeURI{TypeCDS, TypeEDS, TypeLDS, TypeRDS, TypeSDS}
+	XDSResponseOrder = []TypeURI{TypeCDS, TypeEDS, TypeLDS, TypeSRDS, TypeRDS, TypeSDS}
 
 	log = logger.New("envoy")
 )
@@ -21,8 +21,9 @@ type TypeURI string
 // XDS proto defines general client behavior as:
 // "Envoy will always use wildcard subscriptions for Listener and Cluster resources"
 // https://www.envoyproxy.io/docs/envoy/latest/api-docs/xds_protocol#client-behavior
+// Scoped route configurations are also always subscribed to with wildcard subscriptions.
 func IsWildcardTypeURI(t TypeURI) bool {
-	return t == TypeCDS || t == TypeLDS
+	return t == TypeCDS || t == TypeLDS || t == TypeSRDS
 }
 
 func (t TypeURI) String() string {
@@ -41,6 +42,7 @@ var ValidURI = map[string]TypeURI{
 	string(TypeCDS):                TypeCDS,
 	string(TypeLDS):                TypeLDS,
 	string(TypeRDS):                TypeRDS,
+	string(TypeSRDS):               TypeSRDS,
 	string(TypeEDS):                TypeEDS,
 	string(TypeUpstreamTLSContext): TypeUpstreamTLSContext,
 	string(TypeZipkinConfig):       TypeZipkinConfig,
@@ -53,6 +55,7 @@ var XDSShortURINames = map[TypeURI]string{
 	TypeCDS:      "CDS",
 	TypeLDS:      "LDS",
 	TypeRDS:      "RDS",
+	TypeSRDS:     "SRDS",
 	TypeEDS:      "EDS",
 }
 
@@ -73,6 +76,9 @@ const (
 	// TypeRDS is the RDS type URI.
 	TypeRDS TypeURI = "type.googleapis.com/envoy.config.route.v3.RouteConfiguration"
 
+	// TypeSRDS is the SRDS type URI.
+	TypeSRDS TypeURI = "type.googleapis.com/envoy.config.route.v3.ScopedRouteConfiguration"
+
 	// TypeEDS is the EDS type URI.
 	TypeEDS TypeURI = "type.googleapis.com/envoy.config.endpoint.v3.ClusterLoadAssignment"
 
@@ -91,6 +97,10 @@ const (
 	// The local cluster refers to the cluster corresponding to the service the proxy is fronting, accessible over localhost by the proxy.
 	localClusterSuffix = "-local"
 
+	// tcpClusterSuffix is the tag to append to the name of an upstream service cluster to get the name of the cluster
+	// proxying TCP traffic detected on ports of the service with protocol detection enabled.
+	tcpClusterSuffix = "|tcp"
+
 	// EnvoyActiveHealthCheckPath is the HTTP endpoint to be used to receive
 	// active health checks.
 	EnvoyActiveHealthCheckPath = "/healthz/osm"
@@ -109,4 +119,7 @@ const (
 
 	// KindGateway implies the proxy is a gateway
 	KindGateway ProxyKind = "gateway"
+
+	// KindExternalWorkload implies the proxy runs alongside a workload outside Kubernetes, such as a VM
+	KindExternalWorkload ProxyKind = "external"
 )
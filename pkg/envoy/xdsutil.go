@@ -17,6 +17,7 @@ import (
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
 
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/envoy/secrets"
@@ -36,6 +37,9 @@ var (
 	// ErrDidNotFindPodForCertificate is an error for when OSM cannot not find a pod for the given xDS certificate.
 	ErrDidNotFindPodForCertificate = errors.New("did not find pod for certificate")
 
+	// ErrDidNotFindExternalWorkloadForCertificate is an error for when OSM cannot find an external workload for the given xDS certificate.
+	ErrDidNotFindExternalWorkloadForCertificate = errors.New("did not find external workload for certificate")
+
 	// ErrServiceAccountDoesNotMatchCertificate is an error for when the service account of a Pod does not match the xDS certificate.
 	ErrServiceAccountDoesNotMatchCertificate = errors.New("service account does not match certificate")
 
@@ -47,6 +51,9 @@ const (
 	// TransportProtocolTLS is the TLS transport protocol used in Envoy configurations
 	TransportProtocolTLS = "tls"
 
+	// TransportProtocolRawBuffer is the transport protocol of plaintext connections used in Envoy configurations
+	TransportProtocolRawBuffer = "raw_buffer"
+
 	// OutboundPassthroughCluster is the outbound passthrough cluster name
 	OutboundPassthroughCluster = "passthrough-outbound"
 
@@ -55,12 +62,27 @@ const (
 
 	// MulticlusterGatewayCluster is the tls passthough cluster name for multicluster gateway
 	MulticlusterGatewayCluster = "passthrough-multicluster-gateway"
+
+	// multiclusterGatewayMTLSClusterPrefix is the prefix of the names of the clusters used by the multicluster gateway
+	// to originate mTLS connections to local services on behalf of downstreams from remote trust domains
+	multiclusterGatewayMTLSClusterPrefix = "mtls-multicluster-gateway|"
+
+	// trustDomainALPNPrefix is the prefix of the ALPN protocol advertised by proxies to identify their trust domain
+	trustDomainALPNPrefix = "osm-trust-domain|"
 )
 
 // ALPNInMesh indicates that the proxy is connecting to an in-mesh destination.
 // It is set as a part of configuring the UpstreamTLSContext.
 var ALPNInMesh = []string{"osm"}
 
+// ALPNInMeshTCP indicates that the proxy is connecting to an in-mesh destination with TCP traffic detected on a port
+// with protocol detection enabled. It takes precedence over ALPNInMesh so that the upstream proxy matches its TCP
+// filter chain for the port.
+var ALPNInMeshTCP = []string{"osm-tcp"}
+
+// ALPNHTTPInspector lists the application protocols set by the HTTP inspector listener filter on HTTP connections.
+var ALPNHTTPInspector = []string{"http/1.0", "http/1.1", "h2c"}
+
 // GetAddress creates an Envoy Address struct.
 func GetAddress(address string, port uint32) *xds_core.Address {
 	return &xds_core.Address{
@@ -205,6 +227,47 @@ func GetDownstreamTLSContext(upstreamIdentity identity.ServiceIdentity, mTLS boo
 	return tlsConfig
 }
 
+// GetIngressDownstreamTLSContext creates a downstream Envoy TLS Context to be configured on the upstream for ingress
+// traffic to the given upstream's identity. If certSecret is set, the certificate held by the Kubernetes TLS secret
+// with the given <namespace>/<name> is presented to the downstream instead of the upstream's service certificate.
+// Note: ServiceIdentity must be in the format "name.namespace" [https://github.com/openservicemesh/osm/issues/3188]
+func GetIngressDownstreamTLSContext(upstreamIdentity identity.ServiceIdentity, mTLS bool, certSecret string) *xds_auth.DownstreamTlsContext {
+	tlsConfig := GetDownstreamTLSContext(upstreamIdentity, mTLS)
+	if certSecret == "" {
+		return tlsConfig
+	}
+
+	tlsConfig.CommonTlsContext.TlsCertificateSdsSecretConfigs = []*xds_auth.SdsSecretConfig{{
+		// Example ==> Name: "ingress-cert:NameSpaceHere/SecretNameHere"
+		Name: secrets.SDSCert{
+			Name:     certSecret,
+			CertType: secrets.IngressCertType,
+		}.String(),
+		SdsConfig: GetADSConfigSource(),
+	}}
+	return tlsConfig
+}
+
+// GetDownstreamTLSContextForTrustDomain creates a downstream Envoy TLS Context used by the multicluster gateway to terminate
+// mTLS connections to the upstream with the given identity from downstreams in the given remote trust domain. The certificate
+// of the downstream is validated against the CA bundle of the remote trust domain.
+// Note: ServiceIdentity must be in the format "name.namespace" [https://github.com/openservicemesh/osm/issues/3188]
+func GetDownstreamTLSContextForTrustDomain(upstreamIdentity identity.ServiceIdentity, trustDomain string) *xds_auth.DownstreamTlsContext {
+	upstreamSDSCert := secrets.SDSCert{
+		Name:     secrets.GetSecretNameForIdentity(upstreamIdentity),
+		CertType: secrets.ServiceCertType,
+	}
+	downstreamPeerValidationSDSCert := &secrets.SDSCert{
+		Name:     trustDomain,
+		CertType: secrets.RootCertTypeForTrustDomain,
+	}
+
+	return &xds_auth.DownstreamTlsContext{
+		CommonTlsContext:         getCommonTLSContext(upstreamSDSCert, downstreamPeerValidationSDSCert),
+		RequireClientCertificate: &wrappers.BoolValue{Value: true},
+	}
+}
+
 // GetUpstreamTLSContext creates an upstream Envoy TLS Context for the given downstream identity and upstream service pair
 // Note: ServiceIdentity must be in the format "name.namespace" [https://github.com/openservicemesh/osm/issues/3188]
 func GetUpstreamTLSContext(downstreamIdentity identity.ServiceIdentity, upstreamSvc service.MeshService) *xds_auth.UpstreamTlsContext {
@@ -231,6 +294,18 @@ func GetUpstreamTLSContext(downstreamIdentity identity.ServiceIdentity, upstream
 	return tlsConfig
 }
 
+// GetTrustDomainALPN returns the ALPN protocol advertised by proxies in the given trust domain when connecting to
+// in-mesh destinations. It is used by the multicluster gateway to match connections from remote trust domains.
+func GetTrustDomainALPN(trustDomain string) string {
+	return trustDomainALPNPrefix + trustDomain
+}
+
+// GetMulticlusterGatewayMTLSClusterName returns the name of the cluster used by the multicluster gateway to originate
+// mTLS connections to the given service on behalf of downstreams from remote trust domains
+func GetMulticlusterGatewayMTLSClusterName(svc service.MeshService) string {
+	return multiclusterGatewayMTLSClusterPrefix + svc.ServerName()
+}
+
 // GetHTTP2ProtocolOptions creates an Envoy http configuration that matches the downstream protocol
 func GetHTTP2ProtocolOptions() (map[string]*any.Any, error) {
 	marshalledHTTPProtocolOptions, err := ptypes.MarshalAny(
@@ -313,6 +388,12 @@ func GetLocalClusterNameForServiceCluster(clusterName string) string {
 	return fmt.Sprintf("%s%s", clusterName, localClusterSuffix)
 }
 
+// GetTCPClusterNameForServiceCluster returns the name of the cluster proxying TCP traffic detected on ports of the
+// given service cluster with protocol detection enabled.
+func GetTCPClusterNameForServiceCluster(clusterName string) string {
+	return fmt.Sprintf("%s%s", clusterName, tcpClusterSuffix)
+}
+
 // certificateCommonNameMeta is the type that stores the metadata present in the CommonName field in a proxy's certificate
 type certificateCommonNameMeta struct {
 	ProxyUUID uuid.UUID
@@ -400,6 +481,33 @@ func GetPodFromCertificate(cn certificate.CommonName, kubecontroller k8s.Control
 	return &pod, nil
 }
 
+// GetExternalWorkloadFromCertificate returns the ExternalWorkload object for a given certificate.
+func GetExternalWorkloadFromCertificate(cn certificate.CommonName, kubecontroller k8s.Controller) (*policyv1alpha1.ExternalWorkload, error) {
+	cnMeta, err := getCertificateCommonNameMeta(cn)
+	if err != nil {
+		return nil, err
+	}
+
+	sa := cnMeta.ServiceIdentity.ToK8sServiceAccount()
+	for _, externalWorkload := range kubecontroller.ListExternalWorkloads() {
+		if externalWorkload.Namespace != sa.Namespace || externalWorkload.Spec.ProxyUUID != cnMeta.ProxyUUID.String() {
+			continue
+		}
+
+		// Ensure the ServiceAccount encoded in the certificate matches that of the ExternalWorkload
+		if externalWorkload.Spec.ServiceAccount != sa.Name {
+			log.Warn().Msgf("ExternalWorkload %s/%s maps to ServiceAccount=%s. The proxy's xDS certificate was issued for ServiceAccount=%s",
+				externalWorkload.Namespace, externalWorkload.Name, externalWorkload.Spec.ServiceAccount, sa)
+			return nil, ErrServiceAccountDoesNotMatchCertificate
+		}
+
+		return externalWorkload, nil
+	}
+
+	log.Error().Msgf("Did not find ExternalWorkload with proxy UUID %s in namespace %s", cnMeta.ProxyUUID, sa.Namespace)
+	return nil, ErrDidNotFindExternalWorkloadForCertificate
+}
+
 // GetServiceIdentityFromProxyCertificate returns the ServiceIdentity information encoded in the XDS certificate CN
 func GetServiceIdentityFromProxyCertificate(cn certificate.CommonName) (identity.ServiceIdentity, error) {
 	cnMeta, err := getCertificateCommonNameMeta(cn)
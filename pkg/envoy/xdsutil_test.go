@@ -167,6 +167,21 @@ var _ = Describe("Test Envoy tools", func() {
 		})
 	})
 
+	Context("Test GetIngressDownstreamTLSContext()", func() {
+		It("should present the service certificate when no certificate secret is set", func() {
+			tlsContext := GetIngressDownstreamTLSContext(tests.BookstoreServiceIdentity, false, "")
+			Expect(tlsContext).To(Equal(GetDownstreamTLSContext(tests.BookstoreServiceIdentity, false)))
+		})
+
+		It("should present the certificate of the certificate secret when set", func() {
+			tlsContext := GetIngressDownstreamTLSContext(tests.BookstoreServiceIdentity, true, "default/bookstore-tls")
+			Expect(tlsContext.CommonTlsContext.TlsCertificateSdsSecretConfigs).To(HaveLen(1))
+			Expect(tlsContext.CommonTlsContext.TlsCertificateSdsSecretConfigs[0].Name).To(Equal("ingress-cert:default/bookstore-tls"))
+			Expect(tlsContext.CommonTlsContext.GetValidationContextSdsSecretConfig().GetName()).To(Equal("root-cert-for-mtls-inbound:default/bookstore"))
+			Expect(tlsContext.RequireClientCertificate).To(Equal(&wrappers.BoolValue{Value: true}))
+		})
+	})
+
 	Context("Test GetUpstreamTLSContext()", func() {
 		It("should return TLS context", func() {
 			sni := "bookstore-v1.default.svc.cluster.local"
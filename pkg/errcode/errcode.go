@@ -9,6 +9,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/metricsstore"
 )
 
@@ -68,7 +69,7 @@ const (
 	// ErrSMIHTTPRouteGroupNoMatch indicates the SMI HTTPRouteGroup resource has no matches specified
 	ErrSMIHTTPRouteGroupNoMatch
 
-	// ErrMultipleSMISplitPerServiceUnsupported indicates multiple SMI split policies per service exists and is not supported
+	// ErrMultipleSMISplitPerServiceUnsupported indicates multiple SMI split policies without matches per service exists and is not supported
 	ErrMultipleSMISplitPerServiceUnsupported
 
 	// ErrAddingRouteToOutboundTrafficPolicy indicates there was an error adding a route to an outbound traffic policy
@@ -117,6 +118,10 @@ const (
 	// ErrGettingSupportedIngressVersions indicates the mapping of Ingress API versions to the corresponding values indicating
 	// if they are supported could not be configured
 	ErrGettingSupportedIngressVersions
+
+	// ErrConflictingServicePortProtocols indicates services selecting the same workload expose its same target port
+	// with different application protocols
+	ErrConflictingServicePortProtocols
 )
 
 // Range 4000-4100 reserved for errors related to certificate providers
@@ -306,6 +311,15 @@ const (
 
 	// ErrSDSCertMismatch indicates the indentity obtained from the SDSCert request does not match the identity of the proxy
 	ErrSDSCertMismatch
+
+	// ErrInvalidDNSRefreshRate indicates the DNS refresh rate configured in the MeshConfig is invalid
+	ErrInvalidDNSRefreshRate
+
+	// ErrConflictingVirtualHostDomains indicates virtual hosts of a route configuration share domains
+	ErrConflictingVirtualHostDomains
+
+	// ErrInvalidRouteRegex indicates a regular expression of a route match is invalid or too complex to be programmed
+	ErrInvalidRouteRegex
 )
 
 // Range 6000-6500 reserved for errors related to the OSM Injector
@@ -352,6 +366,9 @@ const (
 
 	// ErrNilAdmissionReqBody indicates the admissionRequest body was nil
 	ErrNilAdmissionReqBody
+
+	// ErrUpdatingMutatingWebhookURL indicates the MutatingWebhookConfiguration could not be updated with the webhook URL
+	ErrUpdatingMutatingWebhookURL
 )
 
 // Range 6700-6800 reserved for errors related to the validating webhook
@@ -368,6 +385,9 @@ const (
 
 	// ErrParsingWebhookCert indicates the validating webhook certificate could not be parsed
 	ErrParsingValidatingWebhookCert
+
+	// ErrUpdatingValidatingWebhookURL indicates the ValidatingWebhookConfiguration could not be updated with the webhook URL
+	ErrUpdatingValidatingWebhookURL
 )
 
 // String returns the error code as a string, ex. E1000
@@ -375,10 +395,28 @@ func (e ErrCode) String() string {
 	return fmt.Sprintf("E%d", e)
 }
 
-// GetErrCodeWithMetric increments the ErrCodeCounter metric for the given error code
+// GetErrCodeWithMetric increments the ErrCodeCounter metric for the given error code and counts it in the
+// DefaultErrorLog
 // Returns the error code as a string
 func GetErrCodeWithMetric(e ErrCode) string {
+	return getErrCodeWithMetric(e, "", "")
+}
+
+// GetErrCodeWithMetricForProxy is GetErrCodeWithMetric for an error related to the proxy with the given certificate
+// common name
+func GetErrCodeWithMetricForProxy(e ErrCode, proxyCommonName certificate.CommonName) string {
+	return getErrCodeWithMetric(e, proxyCommonName.String(), "")
+}
+
+// GetErrCodeWithMetricForResource is GetErrCodeWithMetric for an error related to the given resource, as identified by
+// ResourceName
+func GetErrCodeWithMetricForResource(e ErrCode, resource string) string {
+	return getErrCodeWithMetric(e, "", resource)
+}
+
+func getErrCodeWithMetric(e ErrCode, proxy string, resource string) string {
 	metricsstore.DefaultMetricsStore.ErrCodeCounter.WithLabelValues(e.String()).Inc()
+	DefaultErrorLog.Record(e, proxy, resource)
 	return e.String()
 }
 
@@ -480,10 +518,10 @@ The SMI HTTPRouteGroup policy was ignored by the system.
 `,
 
 	ErrMultipleSMISplitPerServiceUnsupported: `
-There are multiple SMI traffic split policies associated with the same apex(root)
-service specified in the policies. The system does not support this scenario so
-onlt the first encountered policy is processed by the system, subsequent policies
-referring the same apex service are ignored.
+There are multiple SMI traffic split policies without matches associated with the
+same apex(root) service specified in the policies. The system does not support this
+scenario so onlt the first encountered policy is processed by the system, subsequent
+policies without matches referring the same apex service are ignored.
 `,
 
 	ErrAddingRouteToOutboundTrafficPolicy: `
@@ -553,6 +591,13 @@ A Kubernetes resource could not be unmarshalled.
 
 	ErrGettingSupportedIngressVersions: `
 The Ingress API versions supported by the k8s API server could not be obtained.
+`,
+
+	ErrConflictingServicePortProtocols: `
+Multiple services selecting the same pod or external workload expose the same
+target port with different application protocols. The service ordered last by
+namespace and name among the conflicting services is not associated with the
+workload, no local cluster nor inbound filter chain is configured for it.
 `,
 
 	//
@@ -825,6 +870,24 @@ The SDS secret does not correspond to a ServiceAccount.
 The identity obtained from the SDS certificate request does not match the
 identity of the proxy.
 The corresponding certificate request was ignored by the system.
+`,
+
+	ErrInvalidDNSRefreshRate: `
+The DNS refresh rate configured in the MeshConfig is not a valid duration of at
+least 1ms. Clusters resolved using DNS use the proxy's default refresh rate.
+`,
+
+	ErrConflictingVirtualHostDomains: `
+Virtual hosts of a route configuration share domains, e.g. because services with
+the same name in different meshes or ExternalName services share hostnames. Envoy
+rejects such route configurations. A shared domain is only kept by the virtual
+host with the lowest name, the virtual hosts left without domains are removed.
+`,
+
+	ErrInvalidRouteRegex: `
+A regular expression of a route match of an HTTPRouteGroup does not compile or
+its program size exceeds the maximum configured in the MeshConfig. The route is
+not programmed on the proxies, which would reject its route configuration.
 `,
 
 	//
@@ -889,6 +952,11 @@ The AdmissionRequest body could not be read.
 
 	ErrNilAdmissionReqBody: `
 The AdmissionRequest body was nil.
+`,
+
+	ErrUpdatingMutatingWebhookURL: `
+The MutatingWebhookConfiguration could not be updated with the URL at which the
+mutating webhook is reachable when OSM runs outside the cluster.
 `,
 
 	//
@@ -909,5 +977,10 @@ The ValidatingWebhookConfiguration could not be patched with the CA Bundle.
 	ErrParsingValidatingWebhookCert: `
 The validating webhook certificate could not be parsed. 
 The validating webhook HTTP server was not started.
+`,
+
+	ErrUpdatingValidatingWebhookURL: `
+The ValidatingWebhookConfiguration could not be updated with the URL at which the
+validating webhook is reachable when OSM runs outside the cluster.
 `,
 }
@@ -0,0 +1,207 @@
+package errcode
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxErrorCounts is the default number of error counts retained by an ErrorLog
+	DefaultMaxErrorCounts = 1000
+
+	// DefaultErrorRetention is the default duration an ErrorLog retains the count of an error for after its last
+	// occurrence
+	DefaultErrorRetention = time.Hour
+
+	// remediationDocsURL is the URL of the documentation of the error codes and their remediation
+	remediationDocsURL = "https://docs.openservicemesh.io/docs/guides/troubleshooting/control_plane_error_codes/"
+
+	// errorsCodeQueryKey, errorsProxyQueryKey and errorsResourceQueryKey are the query parameters of the errors
+	// handler filtering the error counts by error code, proxy and resource
+	errorsCodeQueryKey     = "code"
+	errorsProxyQueryKey    = "proxy"
+	errorsResourceQueryKey = "resource"
+)
+
+// DefaultErrorLog is the ErrorLog the error codes obtained with GetErrCodeWithMetric and its variants are counted in
+var DefaultErrorLog = NewErrorLog(DefaultMaxErrorCounts, DefaultErrorRetention)
+
+// ErrorCount is the type used to represent the recent occurrences of an error code, for a given proxy and resource if
+// known.
+type ErrorCount struct {
+	// Code is the error code, ex. E1000
+	Code string `json:"code"`
+
+	// Description is the description of the error code
+	Description string `json:"description"`
+
+	// Remediation is the link to the documentation of the error code and its remediation
+	Remediation string `json:"remediation"`
+
+	// Proxy is the proxy the errors occurred for, empty if unknown or not related to a proxy
+	Proxy string `json:"proxy,omitempty"`
+
+	// Resource is the resource the errors occurred for, empty if unknown or not related to a resource
+	Resource string `json:"resource,omitempty"`
+
+	// Count is the number of errors since FirstSeen
+	Count int `json:"count"`
+
+	// FirstSeen is the time of the first error retained
+	FirstSeen time.Time `json:"firstSeen"`
+
+	// LastSeen is the time of the most recent error
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+type errorCountKey struct {
+	code     ErrCode
+	proxy    string
+	resource string
+}
+
+// ErrorLog aggregates the recent occurrences of error codes by error code, proxy and resource. It retains up to a
+// maximum number of counts, evicting the least recently seen, and forgets the counts not seen within its retention.
+type ErrorLog struct {
+	maxCounts int
+	retention time.Duration
+
+	mutex  sync.Mutex
+	counts map[errorCountKey]*ErrorCount
+}
+
+// NewErrorLog returns an ErrorLog retaining up to the given number of error counts for the given duration after the
+// last occurrence of their error.
+func NewErrorLog(maxCounts int, retention time.Duration) *ErrorLog {
+	if maxCounts <= 0 {
+		maxCounts = DefaultMaxErrorCounts
+	}
+	if retention <= 0 {
+		retention = DefaultErrorRetention
+	}
+	return &ErrorLog{
+		maxCounts: maxCounts,
+		retention: retention,
+		counts:    make(map[errorCountKey]*ErrorCount),
+	}
+}
+
+// RemediationURL returns the link to the documentation of the given error code and its remediation
+func RemediationURL(e ErrCode) string {
+	return remediationDocsURL + "#" + strings.ToLower(e.String())
+}
+
+// ResourceName returns the identifier of the resource of the given kind, namespace and name errors are counted for,
+// ex. Pod/bookstore/bookstore-v1, omitting the namespace of cluster scoped resources
+func ResourceName(kind string, namespace string, name string) string {
+	if namespace == "" {
+		return fmt.Sprintf("%s/%s", kind, name)
+	}
+	return fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+}
+
+// Record counts an occurrence of the given error code for the given proxy and resource, either of which may be empty
+func (l *ErrorLog) Record(e ErrCode, proxy string, resource string) {
+	l.recordAt(e, proxy, resource, time.Now())
+}
+
+func (l *ErrorLog) recordAt(e ErrCode, proxy string, resource string, now time.Time) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	key := errorCountKey{code: e, proxy: proxy, resource: resource}
+	if count, ok := l.counts[key]; ok {
+		count.Count++
+		count.LastSeen = now
+		return
+	}
+
+	l.expire(now)
+	if len(l.counts) >= l.maxCounts {
+		l.evictLeastRecentlySeen()
+	}
+	l.counts[key] = &ErrorCount{
+		Code:        e.String(),
+		Description: strings.TrimSpace(ErrCodeMap[e]),
+		Remediation: RemediationURL(e),
+		Proxy:       proxy,
+		Resource:    resource,
+		Count:       1,
+		FirstSeen:   now,
+		LastSeen:    now,
+	}
+}
+
+// expire forgets the counts not seen within the retention of the log
+func (l *ErrorLog) expire(now time.Time) {
+	for key, count := range l.counts {
+		if now.Sub(count.LastSeen) > l.retention {
+			delete(l.counts, key)
+		}
+	}
+}
+
+// evictLeastRecentlySeen forgets the count seen the least recently
+func (l *ErrorLog) evictLeastRecentlySeen() {
+	var oldestKey errorCountKey
+	var oldest *ErrorCount
+	for key, count := range l.counts {
+		if oldest == nil || count.LastSeen.Before(oldest.LastSeen) {
+			oldestKey, oldest = key, count
+		}
+	}
+	delete(l.counts, oldestKey)
+}
+
+// List returns the retained error counts, most recently seen first. Only the counts of the given error code, proxy
+// and resource are returned for each of them that is not empty.
+func (l *ErrorLog) List(code string, proxy string, resource string) []ErrorCount {
+	return l.listAt(code, proxy, resource, time.Now())
+}
+
+func (l *ErrorLog) listAt(code string, proxy string, resource string, now time.Time) []ErrorCount {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.expire(now)
+	counts := []ErrorCount{}
+	for _, count := range l.counts {
+		if code != "" && count.Code != code {
+			continue
+		}
+		if proxy != "" && count.Proxy != proxy {
+			continue
+		}
+		if resource != "" && count.Resource != resource {
+			continue
+		}
+		counts = append(counts, *count)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		return counts[i].LastSeen.After(counts[j].LastSeen)
+	})
+	return counts
+}
+
+// GetErrorsHTTPHandler returns an HTTP handler listing the retained error counts in JSON, most recently seen first,
+// optionally filtered by the 'code', 'proxy' and 'resource' query parameters.
+func (l *ErrorLog) GetErrorsHTTPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		counts := l.List(query.Get(errorsCodeQueryKey), query.Get(errorsProxyQueryKey), query.Get(errorsResourceQueryKey))
+
+		jsonCounts, err := json.Marshal(counts)
+		if err != nil {
+			http.Error(w, "Error marshaling error counts", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, string(jsonCounts))
+	})
+}
@@ -0,0 +1,69 @@
+package errcode
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestErrorLog(t *testing.T) {
+	assert := tassert.New(t)
+
+	l := NewErrorLog(2, time.Hour)
+	start := time.Date(2021, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	l.recordAt(ErrInvalidCLIArgument, "", "", start)
+	l.recordAt(ErrInvalidCLIArgument, "", "", start.Add(time.Second))
+	l.recordAt(ErrSettingLogLevel, "proxy-1", "", start.Add(2*time.Second))
+
+	counts := l.listAt("", "", "", start.Add(3*time.Second))
+	assert.Len(counts, 2)
+	assert.Equal("E1001", counts[0].Code)
+	assert.Equal("proxy-1", counts[0].Proxy)
+	assert.Equal(1, counts[0].Count)
+	assert.Equal("E1000", counts[1].Code)
+	assert.Equal(2, counts[1].Count)
+	assert.Equal(start, counts[1].FirstSeen)
+	assert.Equal(start.Add(time.Second), counts[1].LastSeen)
+	assert.Equal("https://docs.openservicemesh.io/docs/guides/troubleshooting/control_plane_error_codes/#e1000", counts[1].Remediation)
+	assert.Equal("An invalid command line argument was passed to the application.", counts[1].Description)
+
+	// The least recently seen count is evicted when the log is full
+	l.recordAt(ErrParsingMeshConfig, "", ResourceName("MeshConfig", "osm-system", "osm-mesh-config"), start.Add(4*time.Second))
+	counts = l.listAt("", "", "", start.Add(5*time.Second))
+	assert.Len(counts, 2)
+	assert.Equal("E1002", counts[0].Code)
+	assert.Equal("MeshConfig/osm-system/osm-mesh-config", counts[0].Resource)
+	assert.Equal("E1001", counts[1].Code)
+
+	// Counts are filtered by error code, proxy and resource
+	assert.Len(l.listAt("E1001", "", "", start.Add(5*time.Second)), 1)
+	assert.Len(l.listAt("", "proxy-1", "", start.Add(5*time.Second)), 1)
+	assert.Len(l.listAt("", "", "MeshConfig/osm-system/osm-mesh-config", start.Add(5*time.Second)), 1)
+	assert.Len(l.listAt("E1001", "", "MeshConfig/osm-system/osm-mesh-config", start.Add(5*time.Second)), 0)
+
+	// Counts not seen within the retention are forgotten
+	assert.Len(l.listAt("", "", "", start.Add(time.Hour+3*time.Second)), 1)
+	assert.Empty(l.listAt("", "", "", start.Add(2*time.Hour)))
+}
+
+func TestGetErrorsHTTPHandler(t *testing.T) {
+	assert := tassert.New(t)
+
+	l := NewErrorLog(0, 0)
+	l.Record(ErrInvalidCLIArgument, "", ResourceName("Namespace", "", "bookstore"))
+	l.Record(ErrSettingLogLevel, "", "")
+
+	responseRecorder := httptest.NewRecorder()
+	l.GetErrorsHTTPHandler().ServeHTTP(responseRecorder, httptest.NewRequest("GET", "/errors?code=E1000", nil))
+	assert.Equal("application/json", responseRecorder.Header().Get("Content-Type"))
+
+	var counts []ErrorCount
+	assert.Nil(json.Unmarshal(responseRecorder.Body.Bytes(), &counts))
+	assert.Len(counts, 1)
+	assert.Equal("E1000", counts[0].Code)
+	assert.Equal("Namespace/bookstore", counts[0].Resource)
+}
@@ -19,6 +19,8 @@ import (
 	clientset "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned"
 	configv1alpha1 "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned/typed/config/v1alpha1"
 	fakeconfigv1alpha1 "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned/typed/config/v1alpha1/fake"
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned/typed/config/v1alpha2"
+	fakeconfigv1alpha2 "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned/typed/config/v1alpha2/fake"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
@@ -77,3 +79,8 @@ var _ clientset.Interface = &Clientset{}
 func (c *Clientset) ConfigV1alpha1() configv1alpha1.ConfigV1alpha1Interface {
 	return &fakeconfigv1alpha1.FakeConfigV1alpha1{Fake: &c.Fake}
 }
+
+// ConfigV1alpha2 retrieves the ConfigV1alpha2Client
+func (c *Clientset) ConfigV1alpha2() configv1alpha2.ConfigV1alpha2Interface {
+	return &fakeconfigv1alpha2.FakeConfigV1alpha2{Fake: &c.Fake}
+}
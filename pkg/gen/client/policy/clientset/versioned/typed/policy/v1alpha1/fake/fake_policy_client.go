@@ -29,10 +29,30 @@ func (c *FakePolicyV1alpha1) Egresses(namespace string) v1alpha1.EgressInterface
 	return &FakeEgresses{c, namespace}
 }
 
+func (c *FakePolicyV1alpha1) ExternalWorkloads(namespace string) v1alpha1.ExternalWorkloadInterface {
+	return &FakeExternalWorkloads{c, namespace}
+}
+
 func (c *FakePolicyV1alpha1) IngressBackends(namespace string) v1alpha1.IngressBackendInterface {
 	return &FakeIngressBackends{c, namespace}
 }
 
+func (c *FakePolicyV1alpha1) NamespaceIsolations(namespace string) v1alpha1.NamespaceIsolationInterface {
+	return &FakeNamespaceIsolations{c, namespace}
+}
+
+func (c *FakePolicyV1alpha1) NamespaceOffboardings(namespace string) v1alpha1.NamespaceOffboardingInterface {
+	return &FakeNamespaceOffboardings{c, namespace}
+}
+
+func (c *FakePolicyV1alpha1) ProgressiveDeliveries(namespace string) v1alpha1.ProgressiveDeliveryInterface {
+	return &FakeProgressiveDeliveries{c, namespace}
+}
+
+func (c *FakePolicyV1alpha1) UpstreamTrafficSettings(namespace string) v1alpha1.UpstreamTrafficSettingInterface {
+	return &FakeUpstreamTrafficSettings{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakePolicyV1alpha1) RESTClient() rest.Interface {
@@ -17,4 +17,14 @@ package v1alpha1
 
 type EgressExpansion interface{}
 
+type ExternalWorkloadExpansion interface{}
+
 type IngressBackendExpansion interface{}
+
+type NamespaceIsolationExpansion interface{}
+
+type NamespaceOffboardingExpansion interface{}
+
+type ProgressiveDeliveryExpansion interface{}
+
+type UpstreamTrafficSettingExpansion interface{}
@@ -24,7 +24,12 @@ import (
 type PolicyV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	EgressesGetter
+	ExternalWorkloadsGetter
 	IngressBackendsGetter
+	NamespaceIsolationsGetter
+	NamespaceOffboardingsGetter
+	ProgressiveDeliveriesGetter
+	UpstreamTrafficSettingsGetter
 }
 
 // PolicyV1alpha1Client is used to interact with features provided by the policy.openservicemesh.io group.
@@ -36,10 +41,30 @@ func (c *PolicyV1alpha1Client) Egresses(namespace string) EgressInterface {
 	return newEgresses(c, namespace)
 }
 
+func (c *PolicyV1alpha1Client) ExternalWorkloads(namespace string) ExternalWorkloadInterface {
+	return newExternalWorkloads(c, namespace)
+}
+
 func (c *PolicyV1alpha1Client) IngressBackends(namespace string) IngressBackendInterface {
 	return newIngressBackends(c, namespace)
 }
 
+func (c *PolicyV1alpha1Client) NamespaceIsolations(namespace string) NamespaceIsolationInterface {
+	return newNamespaceIsolations(c, namespace)
+}
+
+func (c *PolicyV1alpha1Client) NamespaceOffboardings(namespace string) NamespaceOffboardingInterface {
+	return newNamespaceOffboardings(c, namespace)
+}
+
+func (c *PolicyV1alpha1Client) ProgressiveDeliveries(namespace string) ProgressiveDeliveryInterface {
+	return newProgressiveDeliveries(c, namespace)
+}
+
+func (c *PolicyV1alpha1Client) UpstreamTrafficSettings(namespace string) UpstreamTrafficSettingInterface {
+	return newUpstreamTrafficSettings(c, namespace)
+}
+
 // NewForConfig creates a new PolicyV1alpha1Client for the given config.
 func NewForConfig(c *rest.Config) (*PolicyV1alpha1Client, error) {
 	config := *c
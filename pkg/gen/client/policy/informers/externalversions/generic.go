@@ -52,8 +52,18 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	// Group=policy.openservicemesh.io, Version=v1alpha1
 	case v1alpha1.SchemeGroupVersion.WithResource("egresses"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Policy().V1alpha1().Egresses().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("externalworkloads"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Policy().V1alpha1().ExternalWorkloads().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("ingressbackends"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Policy().V1alpha1().IngressBackends().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("namespaceisolations"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Policy().V1alpha1().NamespaceIsolations().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("namespaceoffboardings"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Policy().V1alpha1().NamespaceOffboardings().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("progressivedeliveries"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Policy().V1alpha1().ProgressiveDeliveries().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("upstreamtrafficsettings"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Policy().V1alpha1().UpstreamTrafficSettings().Informer()}, nil
 
 	}
 
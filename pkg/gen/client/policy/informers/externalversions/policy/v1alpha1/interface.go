@@ -23,8 +23,18 @@ import (
 type Interface interface {
 	// Egresses returns a EgressInformer.
 	Egresses() EgressInformer
+	// ExternalWorkloads returns a ExternalWorkloadInformer.
+	ExternalWorkloads() ExternalWorkloadInformer
 	// IngressBackends returns a IngressBackendInformer.
 	IngressBackends() IngressBackendInformer
+	// NamespaceIsolations returns a NamespaceIsolationInformer.
+	NamespaceIsolations() NamespaceIsolationInformer
+	// NamespaceOffboardings returns a NamespaceOffboardingInformer.
+	NamespaceOffboardings() NamespaceOffboardingInformer
+	// ProgressiveDeliveries returns a ProgressiveDeliveryInformer.
+	ProgressiveDeliveries() ProgressiveDeliveryInformer
+	// UpstreamTrafficSettings returns a UpstreamTrafficSettingInformer.
+	UpstreamTrafficSettings() UpstreamTrafficSettingInformer
 }
 
 type version struct {
@@ -43,7 +53,32 @@ func (v *version) Egresses() EgressInformer {
 	return &egressInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// ExternalWorkloads returns a ExternalWorkloadInformer.
+func (v *version) ExternalWorkloads() ExternalWorkloadInformer {
+	return &externalWorkloadInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // IngressBackends returns a IngressBackendInformer.
 func (v *version) IngressBackends() IngressBackendInformer {
 	return &ingressBackendInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// NamespaceIsolations returns a NamespaceIsolationInformer.
+func (v *version) NamespaceIsolations() NamespaceIsolationInformer {
+	return &namespaceIsolationInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// NamespaceOffboardings returns a NamespaceOffboardingInformer.
+func (v *version) NamespaceOffboardings() NamespaceOffboardingInformer {
+	return &namespaceOffboardingInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// ProgressiveDeliveries returns a ProgressiveDeliveryInformer.
+func (v *version) ProgressiveDeliveries() ProgressiveDeliveryInformer {
+	return &progressiveDeliveryInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// UpstreamTrafficSettings returns a UpstreamTrafficSettingInformer.
+func (v *version) UpstreamTrafficSettings() UpstreamTrafficSettingInformer {
+	return &upstreamTrafficSettingInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -23,6 +23,14 @@ type EgressListerExpansion interface{}
 // EgressNamespaceLister.
 type EgressNamespaceListerExpansion interface{}
 
+// ExternalWorkloadListerExpansion allows custom methods to be added to
+// ExternalWorkloadLister.
+type ExternalWorkloadListerExpansion interface{}
+
+// ExternalWorkloadNamespaceListerExpansion allows custom methods to be added to
+// ExternalWorkloadNamespaceLister.
+type ExternalWorkloadNamespaceListerExpansion interface{}
+
 // IngressBackendListerExpansion allows custom methods to be added to
 // IngressBackendLister.
 type IngressBackendListerExpansion interface{}
@@ -30,3 +38,35 @@ type IngressBackendListerExpansion interface{}
 // IngressBackendNamespaceListerExpansion allows custom methods to be added to
 // IngressBackendNamespaceLister.
 type IngressBackendNamespaceListerExpansion interface{}
+
+// NamespaceIsolationListerExpansion allows custom methods to be added to
+// NamespaceIsolationLister.
+type NamespaceIsolationListerExpansion interface{}
+
+// NamespaceIsolationNamespaceListerExpansion allows custom methods to be added to
+// NamespaceIsolationNamespaceLister.
+type NamespaceIsolationNamespaceListerExpansion interface{}
+
+// NamespaceOffboardingListerExpansion allows custom methods to be added to
+// NamespaceOffboardingLister.
+type NamespaceOffboardingListerExpansion interface{}
+
+// NamespaceOffboardingNamespaceListerExpansion allows custom methods to be added to
+// NamespaceOffboardingNamespaceLister.
+type NamespaceOffboardingNamespaceListerExpansion interface{}
+
+// ProgressiveDeliveryListerExpansion allows custom methods to be added to
+// ProgressiveDeliveryLister.
+type ProgressiveDeliveryListerExpansion interface{}
+
+// ProgressiveDeliveryNamespaceListerExpansion allows custom methods to be added to
+// ProgressiveDeliveryNamespaceLister.
+type ProgressiveDeliveryNamespaceListerExpansion interface{}
+
+// UpstreamTrafficSettingListerExpansion allows custom methods to be added to
+// UpstreamTrafficSettingLister.
+type UpstreamTrafficSettingListerExpansion interface{}
+
+// UpstreamTrafficSettingNamespaceListerExpansion allows custom methods to be added to
+// UpstreamTrafficSettingNamespaceLister.
+type UpstreamTrafficSettingNamespaceListerExpansion interface{}
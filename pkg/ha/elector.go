@@ -0,0 +1,242 @@
+package ha
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+// NewElector returns an Elector electing the leader among the controllers sharing the Lease described by the given
+// config. The config versions of proxies are handed off from and to the given VersionBook.
+func NewElector(config Config, kubeClient kubernetes.Interface, versionBook VersionBook) *Elector {
+	if config.LeaseName == "" {
+		config.LeaseName = DefaultLeaseName
+	}
+	if config.HandoffConfigMapName == "" {
+		config.HandoffConfigMapName = DefaultHandoffConfigMapName
+	}
+	if config.LeaseDuration <= 0 {
+		config.LeaseDuration = DefaultLeaseDuration
+	}
+	if config.RenewDeadline <= 0 {
+		config.RenewDeadline = DefaultRenewDeadline
+	}
+	if config.RetryPeriod <= 0 {
+		config.RetryPeriod = DefaultRetryPeriod
+	}
+	if config.HandoffInterval <= 0 {
+		config.HandoffInterval = DefaultHandoffInterval
+	}
+
+	return &Elector{
+		config:      config,
+		kubeClient:  kubeClient,
+		versionBook: versionBook,
+	}
+}
+
+// Run starts participating in the leader election until the given context is canceled.
+// When the controller becomes the leader, the state handed off by the previous leader is restored before
+// onStartedLeading is called, and the state is handed off periodically while leading.
+// When the controller stops leading, its state is handed off a last time before onStoppedLeading is called.
+// The leader label is removed from the controller's pod before participating, since a restarted container keeps the
+// labels of its pod, so that proxies don't connect to a standby controller.
+func (e *Elector) Run(ctx context.Context, onStartedLeading func(), onStoppedLeading func()) error {
+	if err := e.unlabelLeaderPod(); err != nil {
+		return errors.Wrapf(err, "Error removing the leader label from the pod of %s", e.config.Identity)
+	}
+	atomic.StoreInt32(&e.ready, 1)
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock: &resourcelock.LeaseLock{
+			LeaseMeta: metav1.ObjectMeta{
+				Name:      e.config.LeaseName,
+				Namespace: e.config.Namespace,
+			},
+			Client: e.kubeClient.CoordinationV1(),
+			LockConfig: resourcelock.ResourceLockConfig{
+				Identity: e.config.Identity,
+			},
+		},
+		LeaseDuration:   e.config.LeaseDuration,
+		RenewDeadline:   e.config.RenewDeadline,
+		RetryPeriod:     e.config.RetryPeriod,
+		ReleaseOnCancel: true,
+		Name:            e.config.LeaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(leaderCtx context.Context) {
+				log.Info().Msgf("Controller %s started leading", e.config.Identity)
+				if err := e.labelLeaderPod(); err != nil {
+					log.Error().Err(err).Msgf("Error labeling the pod of leader %s, proxies may not be able to connect to it", e.config.Identity)
+				}
+				if err := e.restore(); err != nil {
+					log.Error().Err(err).Msg("Error restoring state handed off by the previous leader")
+				}
+				onStartedLeading()
+				e.runHandoff(leaderCtx)
+			},
+			OnStoppedLeading: func() {
+				log.Info().Msgf("Controller %s stopped leading", e.config.Identity)
+				if err := e.unlabelLeaderPod(); err != nil {
+					log.Error().Err(err).Msgf("Error removing the leader label from the pod of %s", e.config.Identity)
+				}
+				if err := e.handoff(); err != nil {
+					log.Error().Err(err).Msg("Error handing off state to the next leader")
+				}
+				onStoppedLeading()
+			},
+			OnNewLeader: func(identity string) {
+				if identity != e.config.Identity {
+					log.Info().Msgf("Controller %s is the leader, %s is standing by", identity, e.config.Identity)
+				}
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "Error creating leader elector for Lease %s/%s", e.config.Namespace, e.config.LeaseName)
+	}
+
+	go elector.Run(ctx)
+	return nil
+}
+
+// labelLeaderPod labels the pod of the controller as the leader's pod, so that the Service proxies connect to selects it
+func (e *Elector) labelLeaderPod() error {
+	patch := []byte(fmt.Sprintf(`{"metadata":{"labels":{%q:"true"}}}`, LeaderLabelKey))
+	_, err := e.kubeClient.CoreV1().Pods(e.config.Namespace).Patch(context.Background(), e.config.Identity, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	if apierrors.IsNotFound(err) {
+		// The controller isn't running in a pod, such as when it runs outside the cluster
+		log.Warn().Msgf("No pod %s/%s found to label as the leader", e.config.Namespace, e.config.Identity)
+		return nil
+	}
+	return err
+}
+
+// unlabelLeaderPod removes the leader label from the pod of the controller, so that the Service proxies connect to
+// no longer selects it
+func (e *Elector) unlabelLeaderPod() error {
+	patch := []byte(fmt.Sprintf(`{"metadata":{"labels":{%q:null}}}`, LeaderLabelKey))
+	_, err := e.kubeClient.CoreV1().Pods(e.config.Namespace).Patch(context.Background(), e.config.Identity, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	if apierrors.IsNotFound(err) {
+		// The controller isn't running in a pod, such as when it runs outside the cluster
+		return nil
+	}
+	return err
+}
+
+// Liveness is the Kubernetes liveness probe handler.
+func (e *Elector) Liveness() bool {
+	return true
+}
+
+// Readiness is the Kubernetes readiness probe handler. Standby controllers are ready to take over once the leader
+// label has been removed from their pod, and proxies are kept from connecting to them by the leader label selected
+// by the ADS Service.
+func (e *Elector) Readiness() bool {
+	return atomic.LoadInt32(&e.ready) == 1
+}
+
+// GetID returns the ID of the probe
+func (e *Elector) GetID() string {
+	return probeID
+}
+
+// runHandoff hands off the leader's state at the configured interval until the given context is canceled
+func (e *Elector) runHandoff(ctx context.Context) {
+	ticker := time.NewTicker(e.config.HandoffInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := e.handoff(); err != nil {
+				log.Error().Err(err).Msg("Error handing off state to standby controllers")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// handoff stores the leader's state in the handoff ConfigMap
+func (e *Elector) handoff() error {
+	e.handoffMutex.Lock()
+	defer e.handoffMutex.Unlock()
+
+	versions, err := json.Marshal(e.versionBook.GetConfigVersions())
+	if err != nil {
+		return errors.Wrap(err, "Error marshalling config versions")
+	}
+
+	configMaps := e.kubeClient.CoreV1().ConfigMaps(e.config.Namespace)
+	configMap, err := configMaps.Get(context.Background(), e.config.HandoffConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      e.config.HandoffConfigMapName,
+				Namespace: e.config.Namespace,
+				Labels: map[string]string{
+					constants.OSMAppNameLabelKey: constants.OSMAppNameLabelValue,
+				},
+			},
+			Data: map[string]string{
+				handoffVersionsKey: string(versions),
+			},
+		}
+		if _, err := configMaps.Create(context.Background(), configMap, metav1.CreateOptions{}); err != nil {
+			return errors.Wrapf(err, "Error creating handoff ConfigMap %s/%s", e.config.Namespace, e.config.HandoffConfigMapName)
+		}
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "Error getting handoff ConfigMap %s/%s", e.config.Namespace, e.config.HandoffConfigMapName)
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = make(map[string]string)
+	}
+	configMap.Data[handoffVersionsKey] = string(versions)
+	if _, err := configMaps.Update(context.Background(), configMap, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrapf(err, "Error updating handoff ConfigMap %s/%s", e.config.Namespace, e.config.HandoffConfigMapName)
+	}
+	return nil
+}
+
+// restore restores the state handed off by the previous leader, if any
+func (e *Elector) restore() error {
+	configMap, err := e.kubeClient.CoreV1().ConfigMaps(e.config.Namespace).Get(context.Background(), e.config.HandoffConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		log.Info().Msgf("No state handed off in ConfigMap %s/%s", e.config.Namespace, e.config.HandoffConfigMapName)
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "Error getting handoff ConfigMap %s/%s", e.config.Namespace, e.config.HandoffConfigMapName)
+	}
+
+	data, ok := configMap.Data[handoffVersionsKey]
+	if !ok {
+		return nil
+	}
+
+	var versions map[string]uint64
+	if err := json.Unmarshal([]byte(data), &versions); err != nil {
+		return errors.Wrapf(err, "Error decoding config versions in handoff ConfigMap %s/%s", e.config.Namespace, e.config.HandoffConfigMapName)
+	}
+	e.versionBook.SetConfigVersions(versions)
+
+	log.Info().Msgf("Restored the config versions of %d proxies handed off by the previous leader", len(versions))
+	return nil
+}
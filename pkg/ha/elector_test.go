@@ -0,0 +1,133 @@
+package ha
+
+import (
+	"context"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testNamespace = "osm-system"
+
+type fakeVersionBook struct {
+	versions map[string]uint64
+}
+
+func (b *fakeVersionBook) GetConfigVersions() map[string]uint64 {
+	return b.versions
+}
+
+func (b *fakeVersionBook) SetConfigVersions(versions map[string]uint64) {
+	b.versions = versions
+}
+
+func TestNewElectorDefaults(t *testing.T) {
+	assert := tassert.New(t)
+
+	e := NewElector(Config{Namespace: testNamespace, Identity: "osm-controller-1"}, fake.NewSimpleClientset(), &fakeVersionBook{})
+	assert.Equal(DefaultLeaseName, e.config.LeaseName)
+	assert.Equal(DefaultHandoffConfigMapName, e.config.HandoffConfigMapName)
+	assert.Equal(DefaultLeaseDuration, e.config.LeaseDuration)
+	assert.Equal(DefaultRenewDeadline, e.config.RenewDeadline)
+	assert.Equal(DefaultRetryPeriod, e.config.RetryPeriod)
+	assert.Equal(DefaultHandoffInterval, e.config.HandoffInterval)
+}
+
+func TestHandoffAndRestore(t *testing.T) {
+	assert := tassert.New(t)
+	kubeClient := fake.NewSimpleClientset()
+
+	// Nothing is restored before the state was handed off
+	standbyBook := &fakeVersionBook{}
+	standby := NewElector(Config{Namespace: testNamespace, Identity: "osm-controller-2"}, kubeClient, standbyBook)
+	assert.Nil(standby.restore())
+	assert.Nil(standbyBook.versions)
+
+	leaderBook := &fakeVersionBook{versions: map[string]uint64{"proxy-1": 3}}
+	leader := NewElector(Config{Namespace: testNamespace, Identity: "osm-controller-1"}, kubeClient, leaderBook)
+
+	// The first handoff creates the ConfigMap
+	assert.Nil(leader.handoff())
+	configMap, err := kubeClient.CoreV1().ConfigMaps(testNamespace).Get(context.Background(), DefaultHandoffConfigMapName, metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Equal(`{"proxy-1":3}`, configMap.Data[handoffVersionsKey])
+
+	// Subsequent handoffs update it
+	leaderBook.versions = map[string]uint64{"proxy-1": 5, "proxy-2": 1}
+	assert.Nil(leader.handoff())
+
+	assert.Nil(standby.restore())
+	assert.Equal(map[string]uint64{"proxy-1": 5, "proxy-2": 1}, standbyBook.versions)
+}
+
+func TestRestoreInvalidState(t *testing.T) {
+	assert := tassert.New(t)
+	kubeClient := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: DefaultHandoffConfigMapName, Namespace: testNamespace},
+		Data:       map[string]string{handoffVersionsKey: "invalid"},
+	})
+
+	book := &fakeVersionBook{}
+	e := NewElector(Config{Namespace: testNamespace, Identity: "osm-controller-1"}, kubeClient, book)
+	assert.NotNil(e.restore())
+	assert.Nil(book.versions)
+}
+
+func TestLabelLeaderPod(t *testing.T) {
+	assert := tassert.New(t)
+	kubeClient := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "osm-controller-1",
+			Namespace: testNamespace,
+			Labels:    map[string]string{"app": "osm-controller"},
+		},
+	})
+
+	e := NewElector(Config{Namespace: testNamespace, Identity: "osm-controller-1"}, kubeClient, &fakeVersionBook{})
+	assert.Nil(e.labelLeaderPod())
+
+	pod, err := kubeClient.CoreV1().Pods(testNamespace).Get(context.Background(), "osm-controller-1", metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Equal(map[string]string{"app": "osm-controller", LeaderLabelKey: "true"}, pod.Labels)
+
+	// The label is removed when the controller stops leading
+	assert.Nil(e.unlabelLeaderPod())
+	pod, err = kubeClient.CoreV1().Pods(testNamespace).Get(context.Background(), "osm-controller-1", metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Equal(map[string]string{"app": "osm-controller"}, pod.Labels)
+
+	// Removing a missing label is a no-op
+	assert.Nil(e.unlabelLeaderPod())
+
+	// Controllers not running in a pod are not labeled
+	e = NewElector(Config{Namespace: testNamespace, Identity: "laptop"}, kubeClient, &fakeVersionBook{})
+	assert.Nil(e.labelLeaderPod())
+	assert.Nil(e.unlabelLeaderPod())
+}
+
+func TestRunRemovesStaleLeaderLabel(t *testing.T) {
+	assert := tassert.New(t)
+	kubeClient := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "osm-controller-1",
+			Namespace: testNamespace,
+			Labels:    map[string]string{"app": "osm-controller", LeaderLabelKey: "true"},
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// A restarted controller keeps the labels of its pod, the leader label is removed before it stands by
+	e := NewElector(Config{Namespace: testNamespace, Identity: "osm-controller-1"}, kubeClient, &fakeVersionBook{})
+	assert.False(e.Readiness())
+	assert.Nil(e.Run(ctx, func() {}, func() {}))
+	assert.True(e.Readiness())
+
+	pod, err := kubeClient.CoreV1().Pods(testNamespace).Get(context.Background(), "osm-controller-1", metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Equal(map[string]string{"app": "osm-controller"}, pod.Labels)
+}
@@ -0,0 +1,101 @@
+// Package ha implements an active/standby mode for the OSM controller. Controllers elect a leader using a Lease, and
+// only the leader serves ADS. Standby controllers keep their informer and certificate caches warm so that they can
+// take over within seconds when the leader fails, and the leader periodically hands off the config versions it
+// tracks for proxies so that the new leader keeps them increasing.
+package ha
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var (
+	log = logger.New("ha")
+)
+
+const (
+	// DefaultLeaseName is the default name of the Lease used to elect the leader
+	DefaultLeaseName = "osm-controller-leader"
+
+	// DefaultHandoffConfigMapName is the default name of the ConfigMap the leader hands off its state in
+	DefaultHandoffConfigMapName = "osm-controller-handoff"
+
+	// DefaultLeaseDuration is the default duration standby controllers wait before taking over a lease that
+	// hasn't been renewed
+	DefaultLeaseDuration = 10 * time.Second
+
+	// DefaultRenewDeadline is the default duration the leader retries renewing its lease before giving up leadership
+	DefaultRenewDeadline = 7 * time.Second
+
+	// DefaultRetryPeriod is the default interval at which controllers try to acquire or renew the lease
+	DefaultRetryPeriod = 2 * time.Second
+
+	// DefaultHandoffInterval is the default interval at which the leader hands off its state
+	DefaultHandoffInterval = 5 * time.Second
+
+	// minCertificateCacheWarmInterval is the minimum interval at which the certificate cache is warmed
+	minCertificateCacheWarmInterval = 1 * time.Minute
+
+	// LeaderLabelKey is the label set on the leader's pod, selected by the Service proxies connect to
+	LeaderLabelKey = "ha.openservicemesh.io/leader"
+
+	// probeID is the ID of the leader elector's health probe
+	probeID = "LeaderElector"
+
+	// handoffVersionsKey is the key in the handoff ConfigMap holding the config versions of proxies
+	handoffVersionsKey = "versions.json"
+)
+
+// VersionBook is the interface to be implemented by the ADS server to hand off the config versions it tracks for proxies
+type VersionBook interface {
+	// GetConfigVersions returns the config versions tracked for each proxy
+	GetConfigVersions() map[string]uint64
+
+	// SetConfigVersions sets the config versions tracked for the given proxies
+	SetConfigVersions(map[string]uint64)
+}
+
+// Config is the type used to represent the configuration of the leader elector
+type Config struct {
+	// Namespace is the namespace of the Lease and of the handoff ConfigMap
+	Namespace string
+
+	// Identity is the unique identity of the controller. When it is the name of the controller's pod, the pod is
+	// labeled with LeaderLabelKey while the controller is the leader.
+	Identity string
+
+	// LeaseName is the name of the Lease used to elect the leader
+	LeaseName string
+
+	// HandoffConfigMapName is the name of the ConfigMap the leader hands off its state in
+	HandoffConfigMapName string
+
+	// LeaseDuration is the duration standby controllers wait before taking over a lease that hasn't been renewed
+	LeaseDuration time.Duration
+
+	// RenewDeadline is the duration the leader retries renewing its lease before giving up leadership
+	RenewDeadline time.Duration
+
+	// RetryPeriod is the interval at which controllers try to acquire or renew the lease
+	RetryPeriod time.Duration
+
+	// HandoffInterval is the interval at which the leader hands off its state
+	HandoffInterval time.Duration
+}
+
+// Elector elects the controller serving ADS among an active/standby set of controllers
+type Elector struct {
+	config      Config
+	kubeClient  kubernetes.Interface
+	versionBook VersionBook
+
+	// handoffMutex serializes the handoffs of the leader's state
+	handoffMutex sync.Mutex
+
+	// ready is set to 1 once the leader label has been removed from the controller's pod when it starts
+	ready int32
+}
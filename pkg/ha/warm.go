@@ -0,0 +1,56 @@
+package ha
+
+import (
+	"time"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s"
+)
+
+// RunCertificateCacheWarmer warms the certificate cache, then warms it again at half the validity period of service
+// certificates until the stop channel is closed, so that the cached certificates of a standby controller are renewed
+// before they expire.
+func RunCertificateCacheWarmer(certManager certificate.Manager, kubeController k8s.Controller, cfg configurator.Configurator, stop <-chan struct{}) {
+	go func() {
+		for {
+			validityPeriod := cfg.GetServiceCertValidityPeriod()
+			WarmCertificateCache(certManager, kubeController, validityPeriod)
+
+			select {
+			case <-time.After(getCertificateCacheWarmInterval(validityPeriod)):
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// getCertificateCacheWarmInterval returns the interval at which the certificate cache is warmed for service
+// certificates valid for the given period
+func getCertificateCacheWarmInterval(validityPeriod time.Duration) time.Duration {
+	interval := validityPeriod / 2
+	if interval < minCertificateCacheWarmInterval {
+		return minCertificateCacheWarmInterval
+	}
+	return interval
+}
+
+// WarmCertificateCache issues the service certificates of the identities of the service accounts in the namespaces
+// monitored by the mesh, so that they are cached by the certificate manager when a standby controller takes over
+// serving SDS. Returns the number of certificates issued.
+func WarmCertificateCache(certManager certificate.Manager, kubeController k8s.Controller, validityPeriod time.Duration) int {
+	issued := 0
+	for _, sa := range kubeController.ListServiceAccounts() {
+		svcIdentity := identity.K8sServiceAccount{Name: sa.Name, Namespace: sa.Namespace}.ToServiceIdentity()
+		if _, err := certManager.IssueCertificate(certificate.CommonName(svcIdentity), validityPeriod); err != nil {
+			log.Error().Err(err).Msgf("Error issuing certificate for service identity %s to warm the certificate cache", svcIdentity)
+			continue
+		}
+		issued++
+	}
+
+	log.Info().Msgf("Warmed the certificate cache with %d service certificates", issued)
+	return issued
+}
@@ -0,0 +1,75 @@
+package ha
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s"
+)
+
+func TestWarmCertificateCache(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().GetServiceCertValidityPeriod().Return(time.Hour).AnyTimes()
+	certManager := tresor.NewFakeCertManager(mockConfigurator)
+
+	mockKubeController.EXPECT().ListServiceAccounts().Return([]*corev1.ServiceAccount{
+		{ObjectMeta: metav1.ObjectMeta{Name: "bookbuyer", Namespace: "bookbuyer"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "bookstore", Namespace: "bookstore"}},
+	})
+
+	assert.Equal(2, WarmCertificateCache(certManager, mockKubeController, time.Hour))
+
+	// The certificates are cached by the certificate manager
+	cn := certificate.CommonName(identity.K8sServiceAccount{Name: "bookbuyer", Namespace: "bookbuyer"}.ToServiceIdentity())
+	cert, err := certManager.GetCertificate(cn)
+	assert.Nil(err)
+	assert.Equal(cn, cert.GetCommonName())
+}
+
+func TestRunCertificateCacheWarmer(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().GetServiceCertValidityPeriod().Return(time.Hour).AnyTimes()
+	certManager := tresor.NewFakeCertManager(mockConfigurator)
+
+	warmed := make(chan struct{})
+	mockKubeController.EXPECT().ListServiceAccounts().DoAndReturn(func() []*corev1.ServiceAccount {
+		close(warmed)
+		return nil
+	})
+
+	stop := make(chan struct{})
+	defer close(stop)
+	RunCertificateCacheWarmer(certManager, mockKubeController, mockConfigurator, stop)
+
+	select {
+	case <-warmed:
+	case <-time.After(5 * time.Second):
+		assert.Fail("Certificate cache was not warmed")
+	}
+}
+
+func TestGetCertificateCacheWarmInterval(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.Equal(12*time.Hour, getCertificateCacheWarmInterval(24*time.Hour))
+	assert.Equal(minCertificateCacheWarmInterval, getCertificateCacheWarmInterval(time.Minute))
+}
@@ -3,6 +3,7 @@ package identity
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 )
 
@@ -18,6 +19,10 @@ type ServiceIdentity string
 // WildcardServiceIdentity is a wildcard to match all service identities
 const WildcardServiceIdentity ServiceIdentity = "*"
 
+// WildcardServiceAccountName is the name of a service account matching all the service accounts of its namespace, as
+// specified by the sources of TrafficTarget policies, e.g. bookstore-ns/*
+const WildcardServiceAccountName = "*"
+
 // String returns the ServiceIdentity as a string
 func (si ServiceIdentity) String() string {
 	return string(si)
@@ -28,6 +33,16 @@ func (si ServiceIdentity) IsWildcard() bool {
 	return si == WildcardServiceIdentity
 }
 
+// GetWildcardNamespace returns the namespace of the ServiceIdentity if it is a namespace-scoped wildcard, in the
+// format *.<Namespace>.cluster.local, matching all the service identities of the namespace, and whether it is one
+func (si ServiceIdentity) GetWildcardNamespace() (string, bool) {
+	chunks := strings.SplitN(si.String(), ".", 3)
+	if len(chunks) != 3 || chunks[0] != WildcardServiceAccountName || chunks[1] == "" || chunks[2] != ClusterLocalTrustDomain {
+		return "", false
+	}
+	return chunks[1], true
+}
+
 // ToK8sServiceAccount converts a ServiceIdentity to a K8sServiceAccount to help with transition from K8sServiceAccount to ServiceIdentity
 func (si ServiceIdentity) ToK8sServiceAccount() K8sServiceAccount {
 	// By convention as of release-v0.8 ServiceIdentity is in the format: <ServiceAccount>.<Namespace>.cluster.local
@@ -41,6 +56,21 @@ func (si ServiceIdentity) ToK8sServiceAccount() K8sServiceAccount {
 	}
 }
 
+// SPIFFEID returns the SPIFFE ID of the ServiceIdentity in the given trust domain, in the format
+// spiffe://<TrustDomain>/ns/<Namespace>/sa/<ServiceAccount>, or nil if the ServiceIdentity is not in the format
+// <ServiceAccount>.<Namespace>.cluster.local
+func (si ServiceIdentity) SPIFFEID(trustDomain string) *url.URL {
+	chunks := strings.SplitN(si.String(), ".", 3)
+	if len(chunks) != 3 || chunks[0] == "" || chunks[1] == "" || chunks[2] != ClusterLocalTrustDomain {
+		return nil
+	}
+	return &url.URL{
+		Scheme: "spiffe",
+		Host:   trustDomain,
+		Path:   fmt.Sprintf("/ns/%s/sa/%s", chunks[1], chunks[0]),
+	}
+}
+
 // K8sServiceAccount is a type for a namespaced service account
 type K8sServiceAccount struct {
 	Namespace string
@@ -52,6 +82,20 @@ func (sa K8sServiceAccount) String() string {
 	return fmt.Sprintf("%s%s%s", sa.Namespace, namespaceNameSeparator, sa.Name)
 }
 
+// IsNamespaceWildcard determines if the service account matches all the service accounts of its namespace
+func (sa K8sServiceAccount) IsNamespaceWildcard() bool {
+	return sa.Name == WildcardServiceAccountName
+}
+
+// Matches returns whether the given service account is the service account, or is in the namespace of the service
+// account if it is a namespace wildcard
+func (sa K8sServiceAccount) Matches(svcAccount K8sServiceAccount) bool {
+	if sa.Namespace != svcAccount.Namespace {
+		return false
+	}
+	return sa.Name == svcAccount.Name || sa.IsNamespaceWildcard()
+}
+
 // ToServiceIdentity converts K8sServiceAccount to the newer ServiceIdentity
 // TODO(draychev): ToServiceIdentity is used in many places to ease with transition from K8sServiceAccount to ServiceIdentity and should be removed (not everywhere) - [https://github.com/openservicemesh/osm/issues/2218]
 func (sa K8sServiceAccount) ToServiceIdentity() ServiceIdentity {
@@ -19,8 +19,22 @@ func TestServiceIdentityType(t *testing.T) {
 	notWildcard := ServiceIdentity("foo.bar.cluster.local")
 	assert.False(notWildcard.IsWildcard())
 
+	// Test GetWildcardNamespace()
+	namespace, ok := ServiceIdentity("*.bar.cluster.local").GetWildcardNamespace()
+	assert.True(ok)
+	assert.Equal("bar", namespace)
+	_, ok = si.GetWildcardNamespace()
+	assert.False(ok)
+	_, ok = wildcard.GetWildcardNamespace()
+	assert.False(ok)
+
 	// Test ToK8sServiceAccount()
 	assert.Equal(K8sServiceAccount{Name: "foo", Namespace: "bar"}, si.ToK8sServiceAccount())
+
+	// Test SPIFFEID()
+	assert.Equal("spiffe://example.org/ns/bar/sa/foo", si.SPIFFEID("example.org").String())
+	assert.Nil(ServiceIdentity("uuid.sidecar.foo.bar.cluster.local").SPIFFEID("example.org"))
+	assert.Nil(ServiceIdentity("osm-injector.osm-system.svc").SPIFFEID("example.org"))
 }
 
 func TestK8sServiceAccountType(t *testing.T) {
@@ -32,4 +46,15 @@ func TestK8sServiceAccountType(t *testing.T) {
 
 	// Test ToServiceIdentity
 	assert.Equal(ServiceIdentity("foo.bar.cluster.local"), svcAccount.ToServiceIdentity())
+
+	// Test IsNamespaceWildcard() and Matches()
+	wildcard := K8sServiceAccount{Name: WildcardServiceAccountName, Namespace: "bar"}
+	assert.True(wildcard.IsNamespaceWildcard())
+	assert.False(svcAccount.IsNamespaceWildcard())
+	assert.True(wildcard.Matches(svcAccount))
+	assert.False(wildcard.Matches(K8sServiceAccount{Name: "foo", Namespace: "baz"}))
+	assert.True(svcAccount.Matches(svcAccount))
+	assert.False(svcAccount.Matches(K8sServiceAccount{Name: "baz", Namespace: "bar"}))
+	assert.False(svcAccount.Matches(wildcard))
+	assert.Equal(ServiceIdentity("*.bar.cluster.local"), wildcard.ToServiceIdentity())
 }
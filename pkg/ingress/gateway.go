@@ -101,10 +101,10 @@ func (c client) storeCertInSecret(cert certificate.Certificater, secret corev1.S
 	return err
 }
 
-// handleCertificateChange updates the gateway certificate and secret when the MeshConfig resource changes or
-// when the corresponding gateway certificate is rotated.
+// handleCertificateChange updates the gateway certificate and secret when the ingress gateway certificate spec of the
+// MeshConfig resource changes or when the corresponding gateway certificate is rotated.
 func (c client) handleCertificateChange(currentCertSpec *configv1alpha1.IngressGatewayCertSpec, stop <-chan struct{}) {
-	meshConfigUpdated := events.Subscribe(announcements.MeshConfigUpdated)
+	meshConfigUpdated := events.Subscribe(announcements.MeshConfigIngressGatewayCertChanged)
 
 	certRotated := events.Subscribe(announcements.CertificateRotated)
 
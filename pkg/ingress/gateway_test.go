@@ -332,7 +332,7 @@ func TestHandleCertificateChange(t *testing.T) {
 
 			if tc.updatedMeshConfig != nil {
 				events.Publish(events.PubSubMessage{
-					AnnouncementType: announcements.MeshConfigUpdated,
+					AnnouncementType: announcements.MeshConfigIngressGatewayCertChanged,
 					NewObj:           tc.updatedMeshConfig,
 					OldObj:           tc.previousMeshConfig,
 				})
@@ -364,7 +364,7 @@ func TestHandleCertificateChange(t *testing.T) {
 
 				// Start the certificate rotor
 				mockConfigurator.EXPECT().GetServiceCertValidityPeriod().Return(1 * time.Hour).Times(1)
-				rotor.New(fakeCertProvider).Start(5 * time.Second)
+				rotor.New(fakeCertProvider, "tresor").Start(5 * time.Second)
 
 				a.Eventually(func() bool {
 					rotatedSecret, err := fakeClient.CoreV1().Secrets(testSecret.Namespace).Get(context.TODO(), testSecret.Name, metav1.GetOptions{})
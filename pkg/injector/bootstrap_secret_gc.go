@@ -0,0 +1,121 @@
+package injector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+)
+
+const (
+	// bootstrapSecretGCInterval is the interval at which the orphaned bootstrap Secrets are reclaimed
+	bootstrapSecretGCInterval = 10 * time.Minute
+
+	// bootstrapSecretGCGracePeriod is the age under which a bootstrap Secret is never reclaimed, since the Secret of a
+	// pod is created by the webhook before the pod itself
+	bootstrapSecretGCGracePeriod = 10 * time.Minute
+
+	// bootstrapSecretNamePrefix is the prefix of the name of the bootstrap Secrets, followed by the UUID of the proxy
+	bootstrapSecretNamePrefix = "envoy-bootstrap-config-"
+)
+
+// runBootstrapSecretGarbageCollector periodically reclaims the orphaned bootstrap Secrets, until stop is closed
+func (wh *mutatingWebhook) runBootstrapSecretGarbageCollector(stop <-chan struct{}) {
+	ticker := time.NewTicker(bootstrapSecretGCInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			wh.collectBootstrapSecretGarbage()
+		}
+	}
+}
+
+// collectBootstrapSecretGarbage deletes the orphaned bootstrap Secrets of the mesh: the Secrets whose proxy is
+// neither the sidecar of an existing pod nor the proxy of an ExternalWorkload, and which are not owned by any
+// existing object. The Secrets of pods are owned by the pods once created and deleted along with them, but Secrets
+// created before the ownership was set, whose pod creation failed, or whose pod crashed before being patched would
+// otherwise accumulate. Pods whose bootstrap config is delivered in a volume leave no Secret behind. Orphaned Secrets
+// are only logged in dry-run mode.
+func (wh *mutatingWebhook) collectBootstrapSecretGarbage() {
+	secrets, err := wh.kubeClient.CoreV1().Secrets(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s,%s=%s", constants.OSMAppNameLabelKey, constants.OSMAppNameLabelValue, constants.OSMAppInstanceLabelKey, wh.meshName),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error listing bootstrap Secrets")
+		return
+	}
+
+	// Pods in namespaces no longer monitored may still use their Secret, the pods are listed from the API server
+	pods, err := wh.kubeClient.CoreV1().Pods(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{
+		LabelSelector: constants.EnvoyUniqueIDLabelName,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error listing pods with sidecars")
+		return
+	}
+
+	usedSecrets := make(map[string]struct{})
+	existingPods := make(map[types.UID]struct{})
+	for _, pod := range pods.Items {
+		usedSecrets[pod.Namespace+"/"+bootstrapSecretNamePrefix+pod.Labels[constants.EnvoyUniqueIDLabelName]] = struct{}{}
+		existingPods[pod.UID] = struct{}{}
+	}
+	for _, externalWorkload := range wh.kubeController.ListExternalWorkloads() {
+		usedSecrets[externalWorkload.Namespace+"/"+bootstrapSecretNamePrefix+externalWorkload.Spec.ProxyUUID] = struct{}{}
+	}
+
+	orphaned := 0
+	for _, secret := range secrets.Items {
+		if !strings.HasPrefix(secret.Name, bootstrapSecretNamePrefix) {
+			continue
+		}
+		if time.Since(secret.CreationTimestamp.Time) < bootstrapSecretGCGracePeriod {
+			continue
+		}
+		if _, used := usedSecrets[secret.Namespace+"/"+secret.Name]; used {
+			continue
+		}
+		if hasExistingOwner(secret.OwnerReferences, existingPods) {
+			continue
+		}
+
+		orphaned++
+		if wh.config.BootstrapSecretGCDryRun {
+			log.Info().Msgf("Dry run: skipping deletion of orphaned bootstrap Secret %s/%s", secret.Namespace, secret.Name)
+			continue
+		}
+
+		err := wh.kubeClient.CoreV1().Secrets(secret.Namespace).Delete(context.Background(), secret.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			log.Error().Err(err).Msgf("Error deleting orphaned bootstrap Secret %s/%s", secret.Namespace, secret.Name)
+			continue
+		}
+		metricsstore.DefaultMetricsStore.InjectorBootstrapSecretReclaimedCount.Inc()
+		log.Info().Msgf("Deleted orphaned bootstrap Secret %s/%s", secret.Namespace, secret.Name)
+	}
+	metricsstore.DefaultMetricsStore.InjectorBootstrapSecretOrphanedCount.Set(float64(orphaned))
+}
+
+// hasExistingOwner returns whether the given owner references include an object that may still exist: any object
+// other than a pod, whose existence isn't checked, or one of the given existing pods
+func hasExistingOwner(ownerReferences []metav1.OwnerReference, existingPods map[types.UID]struct{}) bool {
+	for _, owner := range ownerReferences {
+		if owner.Kind != "Pod" {
+			return true
+		}
+		if _, ok := existingPods[owner.UID]; ok {
+			return true
+		}
+	}
+	return false
+}
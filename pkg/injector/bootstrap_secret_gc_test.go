@@ -0,0 +1,150 @@
+package injector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+)
+
+func TestCollectBootstrapSecretGarbage(t *testing.T) {
+	newSecret := func(name string, age time.Duration, owners ...metav1.OwnerReference) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "ns",
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+				Labels: map[string]string{
+					constants.OSMAppNameLabelKey:     constants.OSMAppNameLabelValue,
+					constants.OSMAppInstanceLabelKey: "osm",
+				},
+				OwnerReferences: owners,
+			},
+		}
+	}
+
+	allSecrets := []string{
+		bootstrapSecretNamePrefix + "unused",
+		bootstrapSecretNamePrefix + "deleted-owner",
+		bootstrapSecretNamePrefix + "recent",
+		bootstrapSecretNamePrefix + "existing-owner",
+		bootstrapSecretNamePrefix + "other-owner",
+		bootstrapSecretNamePrefix + "pod",
+		bootstrapSecretNamePrefix + "external-workload",
+		"other",
+	}
+
+	testCases := []struct {
+		name                  string
+		dryRun                bool
+		expectedSecrets       []string
+		expectedOrphanedCount float64
+		expectedReclaimed     float64
+	}{
+		{
+			name: "orphaned Secrets deleted",
+			expectedSecrets: []string{
+				bootstrapSecretNamePrefix + "recent",
+				bootstrapSecretNamePrefix + "existing-owner",
+				bootstrapSecretNamePrefix + "other-owner",
+				bootstrapSecretNamePrefix + "pod",
+				bootstrapSecretNamePrefix + "external-workload",
+				"other",
+			},
+			expectedOrphanedCount: 2,
+			expectedReclaimed:     2,
+		},
+		{
+			name:                  "orphaned Secrets kept in dry-run mode",
+			dryRun:                true,
+			expectedSecrets:       allSecrets,
+			expectedOrphanedCount: 2,
+			expectedReclaimed:     0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			kubeClient := fake.NewSimpleClientset(
+				// Unused and older than the grace period: orphaned
+				newSecret(bootstrapSecretNamePrefix+"unused", 2*bootstrapSecretGCGracePeriod),
+				// Owned by a deleted pod: orphaned
+				newSecret(bootstrapSecretNamePrefix+"deleted-owner", 2*bootstrapSecretGCGracePeriod, metav1.OwnerReference{Kind: "Pod", UID: "uid-deleted"}),
+				// Unused but created within the grace period: kept
+				newSecret(bootstrapSecretNamePrefix+"recent", bootstrapSecretGCGracePeriod/2),
+				// Owned by an existing pod: kept
+				newSecret(bootstrapSecretNamePrefix+"existing-owner", 2*bootstrapSecretGCGracePeriod, metav1.OwnerReference{Kind: "Pod", UID: "uid-pod"}),
+				// Owned by an object other than a pod: kept
+				newSecret(bootstrapSecretNamePrefix+"other-owner", 2*bootstrapSecretGCGracePeriod, metav1.OwnerReference{Kind: "Deployment", UID: "uid-deployment"}),
+				// Used by a pod: kept
+				newSecret(bootstrapSecretNamePrefix+"pod", 2*bootstrapSecretGCGracePeriod),
+				// Used by an ExternalWorkload: kept
+				newSecret(bootstrapSecretNamePrefix+"external-workload", 2*bootstrapSecretGCGracePeriod),
+				// Not a bootstrap Secret: kept
+				newSecret("other", 2*bootstrapSecretGCGracePeriod),
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pod",
+						Namespace: "ns",
+						UID:       types.UID("uid-pod"),
+						Labels: map[string]string{
+							constants.EnvoyUniqueIDLabelName: "pod",
+						},
+					},
+				},
+			)
+
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mockKubeController.EXPECT().ListExternalWorkloads().Return([]*v1alpha1.ExternalWorkload{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "external-workload",
+						Namespace: "ns",
+					},
+					Spec: v1alpha1.ExternalWorkloadSpec{
+						ProxyUUID: "external-workload",
+					},
+				},
+			})
+
+			wh := &mutatingWebhook{
+				config: Config{
+					BootstrapSecretGCDryRun: tc.dryRun,
+				},
+				kubeClient:     kubeClient,
+				kubeController: mockKubeController,
+				meshName:       "osm",
+			}
+
+			reclaimed := testutil.ToFloat64(metricsstore.DefaultMetricsStore.InjectorBootstrapSecretReclaimedCount)
+
+			wh.collectBootstrapSecretGarbage()
+
+			secrets, err := kubeClient.CoreV1().Secrets("ns").List(context.TODO(), metav1.ListOptions{})
+			assert.Nil(err)
+			var names []string
+			for _, secret := range secrets.Items {
+				names = append(names, secret.Name)
+			}
+			assert.ElementsMatch(tc.expectedSecrets, names)
+			assert.Equal(tc.expectedOrphanedCount, testutil.ToFloat64(metricsstore.DefaultMetricsStore.InjectorBootstrapSecretOrphanedCount))
+			assert.Equal(tc.expectedReclaimed, testutil.ToFloat64(metricsstore.DefaultMetricsStore.InjectorBootstrapSecretReclaimedCount)-reclaimed)
+		})
+	}
+}
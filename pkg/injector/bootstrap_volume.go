@@ -0,0 +1,298 @@
+package injector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+	"github.com/openservicemesh/osm/pkg/webhook"
+)
+
+const (
+	// webhookBootstrapPath is the HTTP path at which the init container of a pod fetches the Envoy bootstrap config
+	// of its sidecar
+	webhookBootstrapPath = "/bootstrap"
+
+	// bootstrapInitContainerName is the name of the init container rendering the Envoy bootstrap config of the sidecar
+	bootstrapInitContainerName = "osm-bootstrap"
+
+	// bootstrapTokenVolume is the name of the volume projecting the token the init container authenticates with
+	bootstrapTokenVolume = "osm-bootstrap-token"
+
+	// bootstrapTokenMountPath is the path at which the bootstrap token volume is mounted on the init container
+	bootstrapTokenMountPath = "/var/run/secrets/openservicemesh.io/bootstrap"
+
+	// bootstrapTokenFile is the file of the bootstrap token volume holding the token
+	bootstrapTokenFile = "token"
+
+	// bootstrapTokenAudience is the audience of the token the init container authenticates with, which can't be used
+	// against the API server
+	bootstrapTokenAudience = "osm-injector"
+
+	// bootstrapTokenExpirationSeconds is the validity period of the bootstrap token, the minimum allowed
+	bootstrapTokenExpirationSeconds = int64(600)
+
+	// bootstrapCABundleEnvVar is the environment variable holding the CA bundle the init container verifies the
+	// certificate of the injector with
+	bootstrapCABundleEnvVar = "OSM_CA_BUNDLE"
+
+	// originalHealthProbesAnnotation is the annotation storing the health probes of a pod as defined before they were
+	// rewritten, which the Envoy bootstrap config fetched by the init container proxies the rewritten probes to
+	originalHealthProbesAnnotation = "openservicemesh.io/original-health-probes"
+
+	// serviceAccountUsernamePrefix is the prefix of the username of service accounts, followed by
+	// '<namespace>:<name>'
+	serviceAccountUsernamePrefix = "system:serviceaccount:"
+
+	// podNameExtraKey and podUIDExtraKey are the keys of the extra info of the user authenticated with a token bound
+	// to a pod, holding the name and UID of the pod
+	podNameExtraKey = "authentication.kubernetes.io/pod-name"
+	podUIDExtraKey  = "authentication.kubernetes.io/pod-uid"
+)
+
+// getBootstrapURL returns the URL at which the init containers of pods fetch the Envoy bootstrap config of their
+// sidecar: the webhook URL if one is set, or the injector service otherwise
+func (c Config) getBootstrapURL(osmNamespace string) string {
+	if c.WebhookURL != "" {
+		return webhook.GetURL(c.WebhookURL, webhookBootstrapPath)
+	}
+	return fmt.Sprintf("https://%s.%s.svc:%d%s", injectorServiceName, osmNamespace, c.ListenPort, webhookBootstrapPath)
+}
+
+// getBootstrapVolumes returns the volumes of a pod whose Envoy bootstrap config is rendered by an init container: an
+// in-memory emptyDir the config is rendered into, since it holds the private key of the sidecar, and the token bound
+// to the pod the init container authenticates with
+func getBootstrapVolumes() []corev1.Volume {
+	expirationSeconds := bootstrapTokenExpirationSeconds
+	return []corev1.Volume{
+		{
+			Name: envoyBootstrapConfigVolume,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					Medium: corev1.StorageMediumMemory,
+				},
+			},
+		},
+		{
+			Name: bootstrapTokenVolume,
+			VolumeSource: corev1.VolumeSource{
+				Projected: &corev1.ProjectedVolumeSource{
+					Sources: []corev1.VolumeProjection{
+						{
+							ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+								Audience:          bootstrapTokenAudience,
+								ExpirationSeconds: &expirationSeconds,
+								Path:              bootstrapTokenFile,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// getBootstrapInitContainerSpec returns the init container rendering the Envoy bootstrap config of the sidecar into
+// the bootstrap config volume. It fetches the config from the given URL, authenticating with the token bound to the
+// pod and verifying the certificate of the injector with the given CA bundle. It must run before the iptables init
+// container, which would redirect its traffic to the sidecar.
+func getBootstrapInitContainerSpec(image string, bootstrapURL string, caBundle []byte) corev1.Container {
+	caFile := fmt.Sprintf("%s/ca.crt", envoyProxyConfigPath)
+	script := strings.Join([]string{
+		"set -e",
+		fmt.Sprintf(`echo "$%s" > %s`, bootstrapCABundleEnvVar, caFile),
+		fmt.Sprintf(`curl -sSf --retry 10 --retry-connrefused --retry-delay 2 --cacert %s -H "Authorization: Bearer $(cat %s/%s)" -o %s/%s %s`,
+			caFile, bootstrapTokenMountPath, bootstrapTokenFile, envoyProxyConfigPath, envoyBootstrapConfigFile, bootstrapURL),
+		fmt.Sprintf("rm -f %s", caFile),
+	}, "\n")
+
+	return corev1.Container{
+		Name:    bootstrapInitContainerName,
+		Image:   image,
+		Command: []string{"/bin/sh"},
+		Args: []string{
+			"-c",
+			script,
+		},
+		Env: []corev1.EnvVar{
+			{
+				Name:  bootstrapCABundleEnvVar,
+				Value: string(caBundle),
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      envoyBootstrapConfigVolume,
+				MountPath: envoyProxyConfigPath,
+			},
+			{
+				Name:      bootstrapTokenVolume,
+				MountPath: bootstrapTokenMountPath,
+				ReadOnly:  true,
+			},
+		},
+	}
+}
+
+// bootstrapHandler serves the Envoy bootstrap config of the sidecar of the pod the bearer token of the request is
+// bound to
+func (wh *mutatingWebhook) bootstrapHandler(w http.ResponseWriter, req *http.Request) {
+	token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		http.Error(w, "missing bearer token", http.StatusUnauthorized)
+		return
+	}
+
+	pod, err := wh.getBootstrapTokenPod(token)
+	if err != nil {
+		log.Error().Err(err).Msg("Error authenticating Envoy bootstrap config request")
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	yamlContent, err := wh.getPodEnvoyBootstrapConfigYAML(pod)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error creating Envoy bootstrap config for pod %s/%s", pod.Namespace, pod.Name)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	if _, err := w.Write(yamlContent); err != nil {
+		log.Error().Err(err).Msgf("Error writing Envoy bootstrap config for pod %s/%s", pod.Namespace, pod.Name)
+	}
+}
+
+// getBootstrapTokenPod returns the pod the given token is bound to, once the token is reviewed by the API server
+func (wh *mutatingWebhook) getBootstrapTokenPod(token string) (*corev1.Pod, error) {
+	review, err := wh.kubeClient.AuthenticationV1().TokenReviews().Create(context.Background(), &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{
+			Token:     token,
+			Audiences: []string{bootstrapTokenAudience},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reviewing token")
+	}
+	if !review.Status.Authenticated {
+		return nil, errors.Errorf("Token not authenticated: %s", review.Status.Error)
+	}
+
+	user := review.Status.User
+	if !strings.HasPrefix(user.Username, serviceAccountUsernamePrefix) {
+		return nil, errors.Errorf("Token of user %s is not a service account token", user.Username)
+	}
+	serviceAccount := strings.SplitN(strings.TrimPrefix(user.Username, serviceAccountUsernamePrefix), ":", 2)
+	if len(serviceAccount) != 2 {
+		return nil, errors.Errorf("Invalid service account username %s", user.Username)
+	}
+	namespace, serviceAccountName := serviceAccount[0], serviceAccount[1]
+
+	podNames, podUIDs := user.Extra[podNameExtraKey], user.Extra[podUIDExtraKey]
+	if len(podNames) != 1 || len(podUIDs) != 1 {
+		return nil, errors.Errorf("Token of service account %s/%s is not bound to a pod", namespace, serviceAccountName)
+	}
+
+	pod, err := wh.kubeClient.CoreV1().Pods(namespace).Get(context.Background(), podNames[0], metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error getting pod %s/%s", namespace, podNames[0])
+	}
+	if pod.UID != types.UID(podUIDs[0]) || pod.Spec.ServiceAccountName != serviceAccountName {
+		return nil, errors.Errorf("Token is not bound to pod %s/%s with UID %s and service account %s", namespace, pod.Name, pod.UID, pod.Spec.ServiceAccountName)
+	}
+	return pod, nil
+}
+
+// getPodEnvoyBootstrapConfigYAML returns the Envoy bootstrap config of the sidecar of the given pod, issuing the
+// certificate of the sidecar
+func (wh *mutatingWebhook) getPodEnvoyBootstrapConfigYAML(pod *corev1.Pod) ([]byte, error) {
+	proxyUUID, err := uuid.Parse(pod.Labels[constants.EnvoyUniqueIDLabelName])
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid value for label %s", constants.EnvoyUniqueIDLabelName)
+	}
+
+	originalHealthProbes, err := unmarshalHealthProbes(pod.Annotations[originalHealthProbesAnnotation])
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid value for annotation %s", originalHealthProbesAnnotation)
+	}
+
+	cn := envoy.NewXDSCertCommonName(proxyUUID, envoy.KindSidecar, pod.Spec.ServiceAccountName, pod.Namespace)
+	startTime := time.Now()
+	bootstrapCertificate, err := wh.certManager.IssueCertificate(cn, constants.XDSCertificateValidityPeriod)
+	if err != nil {
+		wh.warnCertificateIssuanceFailed(pod, pod.Namespace, err)
+		return nil, errors.Wrapf(err, "Error issuing bootstrap certificate for Envoy with CN=%s", cn)
+	}
+	metricsstore.DefaultMetricsStore.CertIssuedCount.Inc()
+	metricsstore.DefaultMetricsStore.CertIssuedTime.WithLabelValues().Observe(time.Since(startTime).Seconds())
+
+	adminSocketPath := getEnvoyAdminSocketPath(wh.configurator, pod.Spec.NodeSelector["kubernetes.io/os"])
+	return wh.getEnvoyBootstrapConfigYAML(wh.osmNamespace, bootstrapCertificate, originalHealthProbes, adminSocketPath, pod.Annotations)
+}
+
+// serializedHealthProbe is the representation of a healthProbe stored in the originalHealthProbesAnnotation
+type serializedHealthProbe struct {
+	Path           string `json:"path,omitempty"`
+	Port           int32  `json:"port"`
+	IsHTTP         bool   `json:"isHTTP,omitempty"`
+	ProxiedOverTCP bool   `json:"proxiedOverTCP,omitempty"`
+}
+
+// serializedHealthProbes is the representation of healthProbes stored in the originalHealthProbesAnnotation
+type serializedHealthProbes struct {
+	Liveness  *serializedHealthProbe `json:"liveness,omitempty"`
+	Readiness *serializedHealthProbe `json:"readiness,omitempty"`
+	Startup   *serializedHealthProbe `json:"startup,omitempty"`
+}
+
+// marshalHealthProbes returns the value of the originalHealthProbesAnnotation storing the given health probes
+func marshalHealthProbes(probes healthProbes) (string, error) {
+	serialize := func(probe *healthProbe) *serializedHealthProbe {
+		if probe == nil {
+			return nil
+		}
+		return &serializedHealthProbe{Path: probe.path, Port: probe.port, IsHTTP: probe.isHTTP, ProxiedOverTCP: probe.proxiedOverTCP}
+	}
+	value, err := json.Marshal(serializedHealthProbes{
+		Liveness:  serialize(probes.liveness),
+		Readiness: serialize(probes.readiness),
+		Startup:   serialize(probes.startup),
+	})
+	return string(value), err
+}
+
+// unmarshalHealthProbes returns the health probes stored in the given value of the originalHealthProbesAnnotation,
+// none if empty
+func unmarshalHealthProbes(value string) (healthProbes, error) {
+	if value == "" {
+		return healthProbes{}, nil
+	}
+	var serialized serializedHealthProbes
+	if err := json.Unmarshal([]byte(value), &serialized); err != nil {
+		return healthProbes{}, err
+	}
+	deserialize := func(probe *serializedHealthProbe) *healthProbe {
+		if probe == nil {
+			return nil
+		}
+		return &healthProbe{path: probe.Path, port: probe.Port, isHTTP: probe.IsHTTP, proxiedOverTCP: probe.ProxiedOverTCP}
+	}
+	return healthProbes{
+		liveness:  deserialize(serialized.Liveness),
+		readiness: deserialize(serialized.Readiness),
+		startup:   deserialize(serialized.Startup),
+	}, nil
+}
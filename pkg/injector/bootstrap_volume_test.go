@@ -0,0 +1,246 @@
+package injector
+
+import (
+	"context"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestGetBootstrapURL(t *testing.T) {
+	testCases := []struct {
+		name        string
+		config      Config
+		expectedURL string
+	}{
+		{
+			name:        "injector service",
+			config:      Config{ListenPort: 9090},
+			expectedURL: "https://osm-injector.osm-system.svc:9090/bootstrap",
+		},
+		{
+			name:        "webhook URL",
+			config:      Config{ListenPort: 9090, WebhookURL: "https://injector.example.com:443"},
+			expectedURL: "https://injector.example.com:443/bootstrap",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expectedURL, tc.config.getBootstrapURL("osm-system"))
+		})
+	}
+}
+
+func TestGetBootstrapInitContainerSpec(t *testing.T) {
+	assert := tassert.New(t)
+
+	container := getBootstrapInitContainerSpec("openservicemesh/init:latest", "https://osm-injector.osm-system.svc:9090/bootstrap", []byte("ca"))
+
+	assert.Equal(bootstrapInitContainerName, container.Name)
+	assert.Equal("openservicemesh/init:latest", container.Image)
+	assert.Equal([]corev1.EnvVar{{Name: bootstrapCABundleEnvVar, Value: "ca"}}, container.Env)
+	assert.Len(container.Args, 2)
+	assert.Contains(container.Args[1], "-o /etc/envoy/bootstrap.yaml https://osm-injector.osm-system.svc:9090/bootstrap")
+	assert.Contains(container.Args[1], `-H "Authorization: Bearer $(cat /var/run/secrets/openservicemesh.io/bootstrap/token)"`)
+	assert.ElementsMatch([]string{envoyBootstrapConfigVolume, bootstrapTokenVolume}, []string{container.VolumeMounts[0].Name, container.VolumeMounts[1].Name})
+}
+
+func TestHealthProbesSerialization(t *testing.T) {
+	testCases := []struct {
+		name   string
+		probes healthProbes
+	}{
+		{
+			name:   "no probes",
+			probes: healthProbes{},
+		},
+		{
+			name: "all probes",
+			probes: healthProbes{
+				liveness:  &healthProbe{path: "/liveness", port: 81, isHTTP: true},
+				readiness: &healthProbe{path: "/readiness", port: 82, isHTTP: true},
+				startup:   &healthProbe{port: 83},
+			},
+		},
+		{
+			name: "HTTP probes proxied over TCP",
+			probes: healthProbes{
+				liveness:  &healthProbe{path: "/liveness", port: 81, isHTTP: true, proxiedOverTCP: true},
+				readiness: &healthProbe{path: "/readiness", port: 82, isHTTP: true, proxiedOverTCP: true},
+			},
+		},
+		{
+			name: "some probes",
+			probes: healthProbes{
+				readiness: &healthProbe{port: 82},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			value, err := marshalHealthProbes(tc.probes)
+			assert.Nil(err)
+
+			probes, err := unmarshalHealthProbes(value)
+			assert.Nil(err)
+			assert.Equal(tc.probes, probes)
+		})
+	}
+
+	t.Run("empty annotation", func(t *testing.T) {
+		assert := tassert.New(t)
+
+		probes, err := unmarshalHealthProbes("")
+		assert.Nil(err)
+		assert.Equal(healthProbes{}, probes)
+	})
+
+	t.Run("invalid annotation", func(t *testing.T) {
+		assert := tassert.New(t)
+
+		_, err := unmarshalHealthProbes("{")
+		assert.NotNil(err)
+	})
+}
+
+func TestGetBootstrapTokenPod(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod",
+			Namespace: "ns",
+			UID:       "uid",
+		},
+		Spec: corev1.PodSpec{
+			ServiceAccountName: "sa",
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		status        authenticationv1.TokenReviewStatus
+		expectedError bool
+	}{
+		{
+			name: "token bound to the pod",
+			status: authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				User: authenticationv1.UserInfo{
+					Username: "system:serviceaccount:ns:sa",
+					Extra: map[string]authenticationv1.ExtraValue{
+						podNameExtraKey: {"pod"},
+						podUIDExtraKey:  {"uid"},
+					},
+				},
+			},
+			expectedError: false,
+		},
+		{
+			name: "token not authenticated",
+			status: authenticationv1.TokenReviewStatus{
+				Authenticated: false,
+				Error:         "invalid audience",
+			},
+			expectedError: true,
+		},
+		{
+			name: "token of a user",
+			status: authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				User: authenticationv1.UserInfo{
+					Username: "admin",
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "token not bound to a pod",
+			status: authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				User: authenticationv1.UserInfo{
+					Username: "system:serviceaccount:ns:sa",
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "token bound to a deleted pod with the same name",
+			status: authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				User: authenticationv1.UserInfo{
+					Username: "system:serviceaccount:ns:sa",
+					Extra: map[string]authenticationv1.ExtraValue{
+						podNameExtraKey: {"pod"},
+						podUIDExtraKey:  {"other-uid"},
+					},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "token of another service account",
+			status: authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				User: authenticationv1.UserInfo{
+					Username: "system:serviceaccount:ns:other-sa",
+					Extra: map[string]authenticationv1.ExtraValue{
+						podNameExtraKey: {"pod"},
+						podUIDExtraKey:  {"uid"},
+					},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "token bound to a pod that does not exist",
+			status: authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				User: authenticationv1.UserInfo{
+					Username: "system:serviceaccount:other-ns:sa",
+					Extra: map[string]authenticationv1.ExtraValue{
+						podNameExtraKey: {"pod"},
+						podUIDExtraKey:  {"uid"},
+					},
+				},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			kubeClient := fake.NewSimpleClientset(pod)
+			kubeClient.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+				assert.Equal("token", review.Spec.Token)
+				assert.Equal([]string{bootstrapTokenAudience}, review.Spec.Audiences)
+				review.Status = tc.status
+				return true, review, nil
+			})
+
+			wh := &mutatingWebhook{
+				kubeClient: kubeClient,
+			}
+
+			actual, err := wh.getBootstrapTokenPod("token")
+			assert.Equal(tc.expectedError, err != nil)
+			if !tc.expectedError {
+				expected, err := kubeClient.CoreV1().Pods(pod.Namespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+				assert.Nil(err)
+				assert.Equal(expected, actual)
+			}
+		})
+	}
+}
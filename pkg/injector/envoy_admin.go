@@ -0,0 +1,91 @@
+package injector
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+const (
+	// envoyAdminSocketVolume is the name of the volume holding the unix domain socket of the Envoy admin interface
+	envoyAdminSocketVolume = "envoy-admin-socket-volume"
+
+	// envoyAdminSocketDir is the directory in the Envoy container in which the volume holding the unix domain
+	// socket of the Envoy admin interface is mounted
+	envoyAdminSocketDir = "/var/run/osm/envoy-admin"
+
+	// envoyAdminSocketFile is the name of the unix domain socket of the Envoy admin interface
+	envoyAdminSocketFile = "admin.sock"
+
+	// adminBindModeUDS is the admin interface bind mode binding the Envoy admin interface to a unix domain socket
+	adminBindModeUDS = "uds"
+)
+
+// envoyAdminPrometheusPaths are the paths of the Envoy admin endpoints always served on the Envoy admin port
+var envoyAdminPrometheusPaths = []string{
+	constants.PrometheusScrapePath,
+}
+
+// envoyAdminDebugPaths are the paths of the read-only Envoy admin endpoints served on the Envoy admin port unless
+// the debug endpoints are disabled. Endpoints modifying the proxy's state are never served.
+var envoyAdminDebugPaths = []string{
+	"/certs",
+	"/clusters",
+	"/config_dump",
+	"/listeners",
+	"/memory",
+	"/ready",
+	"/runtime",
+	"/server_info",
+	"/stats",
+}
+
+// getEnvoyAdminSocketPath returns the path of the unix domain socket the Envoy admin interface of a pod running
+// the given OS binds to, empty if it binds to localhost. Windows pods always bind to localhost.
+func getEnvoyAdminSocketPath(cfg configurator.Configurator, podOS string) string {
+	adminInterface := cfg.GetAdminInterfaceConfig()
+	if adminInterface.BindMode != adminBindModeUDS && !adminInterface.DisableDebugEndpoints {
+		return ""
+	}
+
+	if strings.EqualFold(podOS, constants.OSWindows) {
+		log.Warn().Msgf("Binding the Envoy admin interface of Windows pods to localhost, unix domain sockets are not supported")
+		return ""
+	}
+
+	return strings.Join([]string{envoyAdminSocketDir, envoyAdminSocketFile}, "/")
+}
+
+// getEnvoyAdminProxyPaths returns the paths of the Envoy admin endpoints served on the Envoy admin port when the
+// Envoy admin interface is bound to a unix domain socket
+func getEnvoyAdminProxyPaths(cfg configurator.Configurator) []string {
+	if cfg.GetAdminInterfaceConfig().DisableDebugEndpoints {
+		return envoyAdminPrometheusPaths
+	}
+
+	return append(append([]string{}, envoyAdminPrometheusPaths...), envoyAdminDebugPaths...)
+}
+
+// getEnvoyAdminSocketVolume returns the volume holding the unix domain socket of the Envoy admin interface
+func getEnvoyAdminSocketVolume() corev1.Volume {
+	return corev1.Volume{
+		Name: envoyAdminSocketVolume,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{
+				Medium: corev1.StorageMediumMemory,
+			},
+		},
+	}
+}
+
+// getEnvoyAdminSocketVolumeMount returns the mount of the volume holding the unix domain socket of the Envoy admin
+// interface in the Envoy container
+func getEnvoyAdminSocketVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      envoyAdminSocketVolume,
+		MountPath: envoyAdminSocketDir,
+	}
+}
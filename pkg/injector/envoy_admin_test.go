@@ -0,0 +1,107 @@
+package injector
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+func TestGetEnvoyAdminSocketPath(t *testing.T) {
+	testCases := []struct {
+		name           string
+		adminInterface configv1alpha1.AdminInterfaceSpec
+		podOS          string
+		expected       string
+	}{
+		{
+			name:           "admin interface bound to localhost by default",
+			adminInterface: configv1alpha1.AdminInterfaceSpec{},
+			podOS:          constants.OSLinux,
+			expected:       "",
+		},
+		{
+			name:           "admin interface bound to localhost",
+			adminInterface: configv1alpha1.AdminInterfaceSpec{BindMode: "localhost"},
+			podOS:          constants.OSLinux,
+			expected:       "",
+		},
+		{
+			name:           "admin interface bound to a unix domain socket",
+			adminInterface: configv1alpha1.AdminInterfaceSpec{BindMode: "uds"},
+			podOS:          constants.OSLinux,
+			expected:       "/var/run/osm/envoy-admin/admin.sock",
+		},
+		{
+			name:           "admin interface bound to a unix domain socket when debug endpoints are disabled",
+			adminInterface: configv1alpha1.AdminInterfaceSpec{DisableDebugEndpoints: true},
+			podOS:          constants.OSLinux,
+			expected:       "/var/run/osm/envoy-admin/admin.sock",
+		},
+		{
+			name:           "admin interface of windows pods bound to localhost",
+			adminInterface: configv1alpha1.AdminInterfaceSpec{BindMode: "uds"},
+			podOS:          constants.OSWindows,
+			expected:       "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockConfigurator.EXPECT().GetAdminInterfaceConfig().Return(tc.adminInterface)
+
+			assert.Equal(tc.expected, getEnvoyAdminSocketPath(mockConfigurator, tc.podOS))
+		})
+	}
+}
+
+func TestGetEnvoyAdminProxyPaths(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		disableDebugEndpoints bool
+		expected              []string
+	}{
+		{
+			name:                  "debug endpoints enabled",
+			disableDebugEndpoints: false,
+			expected: []string{
+				"/stats/prometheus",
+				"/certs",
+				"/clusters",
+				"/config_dump",
+				"/listeners",
+				"/memory",
+				"/ready",
+				"/runtime",
+				"/server_info",
+				"/stats",
+			},
+		},
+		{
+			name:                  "debug endpoints disabled",
+			disableDebugEndpoints: true,
+			expected:              []string{"/stats/prometheus"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+			mockConfigurator.EXPECT().GetAdminInterfaceConfig().Return(configv1alpha1.AdminInterfaceSpec{
+				BindMode:              "uds",
+				DisableDebugEndpoints: tc.disableDebugEndpoints,
+			})
+
+			assert.Equal(tc.expected, getEnvoyAdminProxyPaths(mockConfigurator))
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/any"
 
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
@@ -28,8 +29,18 @@ import (
 	"github.com/openservicemesh/osm/pkg/version"
 )
 
+const (
+	// defaultShrinkHeapThreshold is the default percentage of the maximum heap size above which Envoy releases its
+	// free memory to the system
+	defaultShrinkHeapThreshold = 95
+
+	// defaultStopAcceptingRequestsThreshold is the default percentage of the maximum heap size above which Envoy
+	// stops accepting requests
+	defaultStopAcceptingRequestsThreshold = 98
+)
+
 func getEnvoyConfigYAML(config envoyBootstrapConfigMeta, cfg configurator.Configurator) ([]byte, error) {
-	bootstrapConfig, err := bootstrap.BuildFromConfig(bootstrap.Config{
+	buildConfig := bootstrap.Config{
 		NodeID:           config.NodeID,
 		AdminPort:        constants.EnvoyAdminPort,
 		XDSClusterName:   constants.OSMControllerName,
@@ -38,7 +49,15 @@ func getEnvoyConfigYAML(config envoyBootstrapConfigMeta, cfg configurator.Config
 		PrivateKey:       config.Key,
 		XDSHost:          config.XDSHost,
 		XDSPort:          config.XDSPort,
-	})
+	}
+	if config.EnvoyAdminSocketPath != "" {
+		buildConfig.AdminSocketPath = config.EnvoyAdminSocketPath
+		buildConfig.AdminProxyPaths = getEnvoyAdminProxyPaths(cfg)
+	}
+	setOverloadManagerConfig(&buildConfig, cfg.GetOverloadManagerConfig())
+	setStatsConfig(&buildConfig, cfg.GetStatsConfig(), config.PodAnnotations)
+
+	bootstrapConfig, err := bootstrap.BuildFromConfig(buildConfig)
 	if err != nil {
 		log.Error().Err(err).Msgf("Error building Envoy boostrap config")
 		return nil, err
@@ -60,6 +79,42 @@ func getEnvoyConfigYAML(config envoyBootstrapConfigMeta, cfg configurator.Config
 	return configYAML, nil
 }
 
+// setOverloadManagerConfig sets the heap size and thresholds of the Envoy overload manager in the given bootstrap
+// config, converting the thresholds from percentages to fractions of the maximum heap size
+func setOverloadManagerConfig(buildConfig *bootstrap.Config, overloadManager configv1alpha1.OverloadManagerSpec) {
+	if overloadManager.MaxHeapSizeBytes == 0 {
+		return
+	}
+
+	shrinkHeapThreshold := overloadManager.ShrinkHeapThreshold
+	if shrinkHeapThreshold == 0 {
+		shrinkHeapThreshold = defaultShrinkHeapThreshold
+	}
+	stopAcceptingRequestsThreshold := overloadManager.StopAcceptingRequestsThreshold
+	if stopAcceptingRequestsThreshold == 0 {
+		stopAcceptingRequestsThreshold = defaultStopAcceptingRequestsThreshold
+	}
+
+	buildConfig.MaxHeapSizeBytes = overloadManager.MaxHeapSizeBytes
+	buildConfig.ShrinkHeapThreshold = float64(shrinkHeapThreshold) / 100
+	buildConfig.StopAcceptingRequestsThreshold = float64(stopAcceptingRequestsThreshold) / 100
+}
+
+// getXDSAddress returns the host and port at which proxies reach the xDS server
+func (c Config) getXDSAddress(osmNamespace string) (string, uint32) {
+	host := c.XDSHost
+	if host == "" {
+		host = fmt.Sprintf("%s.%s.svc.cluster.local", constants.OSMControllerName, osmNamespace)
+	}
+
+	port := c.XDSPort
+	if port == 0 {
+		port = constants.ADSServerPort
+	}
+
+	return host, port
+}
+
 // getProbeResources returns the listener and cluster objects that are statically configured to serve
 // startup, readiness and liveness probes.
 // These will not change during the lifetime of the Pod.
@@ -104,26 +159,9 @@ func getProbeResources(config envoyBootstrapConfigMeta) ([]*xds_listener.Listene
 	return listeners, clusters, nil
 }
 
-func (wh *mutatingWebhook) createEnvoyBootstrapConfig(name, namespace, osmNamespace string, cert certificate.Certificater, originalHealthProbes healthProbes) (*corev1.Secret, error) {
-	configMeta := envoyBootstrapConfigMeta{
-		EnvoyAdminPort: constants.EnvoyAdminPort,
-		XDSClusterName: constants.OSMControllerName,
-		NodeID:         cert.GetCommonName().String(),
-
-		RootCert: cert.GetIssuingCA(),
-		Cert:     cert.GetCertificateChain(),
-		Key:      cert.GetPrivateKey(),
-
-		XDSHost: fmt.Sprintf("%s.%s.svc.cluster.local", constants.OSMControllerName, osmNamespace),
-		XDSPort: constants.ADSServerPort,
-
-		// OriginalHealthProbes stores the path and port for liveness, readiness, and startup health probes as initially
-		// defined on the Pod Spec.
-		OriginalHealthProbes: originalHealthProbes,
-	}
-	yamlContent, err := getEnvoyConfigYAML(configMeta, wh.configurator)
+func (wh *mutatingWebhook) createEnvoyBootstrapConfig(name, namespace, osmNamespace string, cert certificate.Certificater, originalHealthProbes healthProbes, adminSocketPath string, podAnnotations map[string]string) (*corev1.Secret, error) {
+	yamlContent, err := wh.getEnvoyBootstrapConfigYAML(osmNamespace, cert, originalHealthProbes, adminSocketPath, podAnnotations)
 	if err != nil {
-		log.Error().Err(err).Msg("Error creating Envoy bootstrap YAML")
 		return nil, err
 	}
 
@@ -150,6 +188,36 @@ func (wh *mutatingWebhook) createEnvoyBootstrapConfig(name, namespace, osmNamesp
 	return wh.kubeClient.CoreV1().Secrets(namespace).Create(context.Background(), secret, metav1.CreateOptions{})
 }
 
+// getEnvoyBootstrapConfigYAML returns the Envoy bootstrap config of the proxy with the given certificate
+func (wh *mutatingWebhook) getEnvoyBootstrapConfigYAML(osmNamespace string, cert certificate.Certificater, originalHealthProbes healthProbes, adminSocketPath string, podAnnotations map[string]string) ([]byte, error) {
+	xdsHost, xdsPort := wh.config.getXDSAddress(osmNamespace)
+	configMeta := envoyBootstrapConfigMeta{
+		EnvoyAdminPort:       constants.EnvoyAdminPort,
+		EnvoyAdminSocketPath: adminSocketPath,
+		XDSClusterName:       constants.OSMControllerName,
+		NodeID:               cert.GetCommonName().String(),
+
+		RootCert: cert.GetIssuingCA(),
+		Cert:     cert.GetCertificateChain(),
+		Key:      cert.GetPrivateKey(),
+
+		XDSHost: xdsHost,
+		XDSPort: xdsPort,
+
+		// OriginalHealthProbes stores the path and port for liveness, readiness, and startup health probes as initially
+		// defined on the Pod Spec.
+		OriginalHealthProbes: originalHealthProbes,
+
+		PodAnnotations: podAnnotations,
+	}
+	yamlContent, err := getEnvoyConfigYAML(configMeta, wh.configurator)
+	if err != nil {
+		log.Error().Err(err).Msg("Error creating Envoy bootstrap YAML")
+		return nil, err
+	}
+	return yamlContent, nil
+}
+
 func getXdsCluster(config envoyBootstrapConfigMeta) (*xds_cluster.Cluster, error) {
 	httpProtocolOptions := &xds_upstream_http.HttpProtocolOptions{
 		UpstreamProtocolOptions: &xds_upstream_http.HttpProtocolOptions_ExplicitHttpConfig_{
@@ -113,7 +113,7 @@ func getStartupListener(originalProbe *healthProbe) (*xds_listener.Listener, err
 
 func getProbeListener(listenerName, clusterName, newPath string, port int32, originalProbe *healthProbe) (*xds_listener.Listener, error) {
 	var filterChain *xds_listener.FilterChain
-	if originalProbe.isHTTP {
+	if originalProbe.isHTTP && !originalProbe.proxiedOverTCP {
 		httpAccessLog, err := getHTTPAccessLog()
 		if err != nil {
 			return nil, err
@@ -12,6 +12,7 @@ var _ = ginkgo.Describe("Test functions creating Envoy config and rewriting the
 
 	liveness := &healthProbe{path: "/liveness", port: 81, isHTTP: true}
 	livenessNonHTTP := &healthProbe{port: 81, isHTTP: false}
+	livenessProxiedOverTCP := &healthProbe{path: "/liveness", port: 81, isHTTP: true, proxiedOverTCP: true}
 	readiness := &healthProbe{path: "/readiness", port: 82, isHTTP: true}
 	startup := &healthProbe{path: "/startup", port: 83, isHTTP: true}
 
@@ -34,8 +35,11 @@ var _ = ginkgo.Describe("Test functions creating Envoy config and rewriting the
 		"getProbeListener":           func() (protoreflect.ProtoMessage, error) { return getProbeListener("a", "b", "c", 9, liveness) },
 		"getLivenessListener":        func() (protoreflect.ProtoMessage, error) { return getLivenessListener(liveness) },
 		"getLivenessListenerNonHTTP": func() (protoreflect.ProtoMessage, error) { return getLivenessListener(livenessNonHTTP) },
-		"getReadinessListener":       func() (protoreflect.ProtoMessage, error) { return getReadinessListener(readiness) },
-		"getStartupListener":         func() (protoreflect.ProtoMessage, error) { return getStartupListener(startup) },
+		"getLivenessListenerProxiedOverTCP": func() (protoreflect.ProtoMessage, error) {
+			return getLivenessListener(livenessProxiedOverTCP)
+		},
+		"getReadinessListener": func() (protoreflect.ProtoMessage, error) { return getReadinessListener(readiness) },
+		"getStartupListener":   func() (protoreflect.ProtoMessage, error) { return getStartupListener(startup) },
 	}
 
 	for fnName, fn := range clusterFunctionsToTest {
@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"path"
 	"path/filepath"
+	"testing"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -12,14 +13,17 @@ import (
 	mapset "github.com/deckarep/golang-set"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy/bootstrap"
 	"github.com/openservicemesh/osm/pkg/k8s"
 	"github.com/openservicemesh/osm/pkg/utils"
 	"github.com/openservicemesh/osm/pkg/version"
@@ -73,6 +77,8 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 	cert := tresor.NewFakeCertificate()
 	mockCtrl := gomock.NewController(GinkgoT())
 	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().GetOverloadManagerConfig().Return(configv1alpha1.OverloadManagerSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().GetStatsConfig().Return(configv1alpha1.StatsSpec{}).AnyTimes()
 
 	originalHealthProbes := healthProbes{
 		liveness:  &healthProbe{path: "/liveness", port: 81},
@@ -145,6 +151,7 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 			wh := &mutatingWebhook{
 				kubeClient:          fake.NewSimpleClientset(),
 				kubeController:      k8s.NewMockController(gomock.NewController(GinkgoT())),
+				configurator:        mockConfigurator,
 				nonInjectNamespaces: mapset.NewSet(),
 				meshName:            "some-mesh",
 			}
@@ -152,7 +159,7 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 			namespace := "a"
 			osmNamespace := "b"
 
-			secret, err := wh.createEnvoyBootstrapConfig(name, namespace, osmNamespace, cert, probes)
+			secret, err := wh.createEnvoyBootstrapConfig(name, namespace, osmNamespace, cert, probes, "", nil)
 			Expect(err).ToNot(HaveOccurred())
 
 			expected := corev1.Secret{
@@ -181,6 +188,20 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 		})
 	})
 
+	Context("Test getXDSAddress()", func() {
+		It("defaults to the osm-controller service", func() {
+			host, port := Config{}.getXDSAddress("b")
+			Expect(host).To(Equal("osm-controller.b.svc.cluster.local"))
+			Expect(port).To(Equal(uint32(constants.ADSServerPort)))
+		})
+
+		It("uses the configured xDS address when osm-controller runs outside the cluster", func() {
+			host, port := Config{XDSHost: "osm.example.com", XDSPort: 443}.getXDSAddress("b")
+			Expect(host).To(Equal("osm.example.com"))
+			Expect(port).To(Equal(uint32(443)))
+		})
+	})
+
 	Context("Test getXdsCluster()", func() {
 		It("creates XDS Cluster struct without health probes", func() {
 			config.OriginalHealthProbes = probes
@@ -237,7 +258,9 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 	Context("test unix getEnvoySidecarContainerSpec()", func() {
 		It("creates Envoy sidecar spec", func() {
 			mockConfigurator.EXPECT().GetEnvoyLogLevel().Return("debug").Times(1)
+			mockConfigurator.EXPECT().GetEnvoyDrainStrategy().Return("gradual").Times(1)
 			mockConfigurator.EXPECT().GetEnvoyImage().Return(envoyImage).Times(1)
+			mockConfigurator.EXPECT().GetPerformanceSettings().Return(configurator.PerformanceSettings{EnvoyConcurrency: 2}).Times(1)
 			mockConfigurator.EXPECT().GetEnvoyWindowsImage().Return(envoyImage).Times(0)
 			mockConfigurator.EXPECT().GetProxyResources().Return(corev1.ResourceRequirements{
 				// Test set Limits
@@ -285,6 +308,8 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 					"--config-path", "/etc/envoy/bootstrap.yaml",
 					"--service-cluster", "svcacc.namespace",
 					"--bootstrap-version 3",
+					"--drain-strategy", "gradual",
+					"--concurrency", "2",
 				},
 				Env: []corev1.EnvVar{
 					{
@@ -362,7 +387,9 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 	Context("test Windows getEnvoySidecarContainerSpec()", func() {
 		It("creates Envoy sidecar spec", func() {
 			mockConfigurator.EXPECT().GetEnvoyLogLevel().Return("debug").Times(1)
+			mockConfigurator.EXPECT().GetEnvoyDrainStrategy().Return("gradual").Times(1)
 			mockConfigurator.EXPECT().GetEnvoyWindowsImage().Return(envoyImage).Times(1)
+			mockConfigurator.EXPECT().GetPerformanceSettings().Return(configurator.PerformanceSettings{}).Times(1)
 			mockConfigurator.EXPECT().GetEnvoyImage().Return(envoyImage).Times(0)
 			mockConfigurator.EXPECT().GetProxyResources().Return(corev1.ResourceRequirements{
 				// Test set Limits
@@ -412,6 +439,7 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 					"--config-path", "/etc/envoy/bootstrap.yaml",
 					"--service-cluster", "svcacc.namespace",
 					"--bootstrap-version 3",
+					"--drain-strategy", "gradual",
 				},
 				Env: []corev1.EnvVar{
 					{
@@ -486,3 +514,51 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 		})
 	})
 })
+
+func TestSetOverloadManagerConfig(t *testing.T) {
+	testCases := []struct {
+		name            string
+		overloadManager configv1alpha1.OverloadManagerSpec
+		expected        bootstrap.Config
+	}{
+		{
+			name:            "overload manager disabled",
+			overloadManager: configv1alpha1.OverloadManagerSpec{},
+			expected:        bootstrap.Config{},
+		},
+		{
+			name: "default thresholds",
+			overloadManager: configv1alpha1.OverloadManagerSpec{
+				MaxHeapSizeBytes: 1073741824,
+			},
+			expected: bootstrap.Config{
+				MaxHeapSizeBytes:               1073741824,
+				ShrinkHeapThreshold:            0.95,
+				StopAcceptingRequestsThreshold: 0.98,
+			},
+		},
+		{
+			name: "configured thresholds",
+			overloadManager: configv1alpha1.OverloadManagerSpec{
+				MaxHeapSizeBytes:               1073741824,
+				ShrinkHeapThreshold:            80,
+				StopAcceptingRequestsThreshold: 90,
+			},
+			expected: bootstrap.Config{
+				MaxHeapSizeBytes:               1073741824,
+				ShrinkHeapThreshold:            0.8,
+				StopAcceptingRequestsThreshold: 0.9,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			actual := bootstrap.Config{}
+			setOverloadManagerConfig(&actual, tc.overloadManager)
+			assert.Equal(tc.expected, actual)
+		})
+	}
+}
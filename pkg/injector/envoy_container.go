@@ -2,6 +2,7 @@ package injector
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -38,11 +39,42 @@ func getPlatformSpecificSpecComponents(cfg configurator.Configurator, podOS stri
 	return
 }
 
+// getSourceIPPreservingSecurityContext returns the security context of the Envoy sidecar proxying inbound traffic from
+// the original source IP of the clients. Opening connections from non-local addresses and marking them requires the
+// NET_ADMIN capability, which is only effective for the root user, so Envoy runs as root with Envoy's group, which
+// identifies the traffic of Envoy in the iptables rules.
+func getSourceIPPreservingSecurityContext() *corev1.SecurityContext {
+	uid := int64(0)
+	gid := constants.EnvoyGID
+	runAsNonRoot := false
+	return &corev1.SecurityContext{
+		RunAsUser:    &uid,
+		RunAsGroup:   &gid,
+		RunAsNonRoot: &runAsNonRoot,
+		Capabilities: &corev1.Capabilities{
+			Add: []corev1.Capability{
+				"NET_ADMIN",
+			},
+		},
+	}
+}
+
 func getEnvoySidecarContainerSpec(pod *corev1.Pod, cfg configurator.Configurator, originalHealthProbes healthProbes, podOS string) corev1.Container {
 	// cluster ID will be used as an identifier to the tracing sink
 	clusterID := fmt.Sprintf("%s.%s", pod.Spec.ServiceAccountName, pod.Namespace)
 	securityContext, containerImage := getPlatformSpecificSpecComponents(cfg, podOS)
 
+	args := []string{
+		"--log-level", cfg.GetEnvoyLogLevel(),
+		"--config-path", strings.Join([]string{envoyProxyConfigPath, envoyBootstrapConfigFile}, "/"),
+		"--service-cluster", clusterID,
+		"--bootstrap-version 3",
+		"--drain-strategy", cfg.GetEnvoyDrainStrategy(),
+	}
+	if concurrency := cfg.GetPerformanceSettings().EnvoyConcurrency; concurrency > 0 {
+		args = append(args, "--concurrency", strconv.Itoa(concurrency))
+	}
+
 	return corev1.Container{
 		Name:            constants.EnvoyContainerName,
 		Image:           containerImage,
@@ -56,12 +88,7 @@ func getEnvoySidecarContainerSpec(pod *corev1.Pod, cfg configurator.Configurator
 		}},
 		Command:   []string{"envoy"},
 		Resources: cfg.GetProxyResources(),
-		Args: []string{
-			"--log-level", cfg.GetEnvoyLogLevel(),
-			"--config-path", strings.Join([]string{envoyProxyConfigPath, envoyBootstrapConfigFile}, "/"),
-			"--service-cluster", clusterID,
-			"--bootstrap-version 3",
-		},
+		Args:      args,
 		Env: []corev1.EnvVar{
 			{
 				Name: "POD_UID",
@@ -0,0 +1,66 @@
+package injector
+
+import (
+	"regexp"
+	"strings"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/envoy/bootstrap"
+)
+
+// setStatsConfig sets the stats generated by Envoy in the given bootstrap config. The mesh-wide inclusion and
+// exclusion lists are overridden by the stats annotations of the pod, if any. Invalid regular expressions are
+// ignored, since they would prevent Envoy from starting.
+func setStatsConfig(buildConfig *bootstrap.Config, stats configv1alpha1.StatsSpec, podAnnotations map[string]string) {
+	inclusionRegexes, exclusionRegexes := stats.InclusionRegexes, stats.ExclusionRegexes
+
+	podInclusionRegexes, hasPodInclusionRegexes := podAnnotations[statsInclusionRegexesAnnotation]
+	podExclusionRegexes, hasPodExclusionRegexes := podAnnotations[statsExclusionRegexesAnnotation]
+	if hasPodInclusionRegexes || hasPodExclusionRegexes {
+		inclusionRegexes = splitRegexesAnnotation(podInclusionRegexes)
+		exclusionRegexes = splitRegexesAnnotation(podExclusionRegexes)
+	}
+
+	buildConfig.StatsInclusionRegexes = getValidRegexes(inclusionRegexes)
+	buildConfig.StatsExclusionRegexes = getValidRegexes(exclusionRegexes)
+	if len(buildConfig.StatsInclusionRegexes) > 0 && len(buildConfig.StatsExclusionRegexes) > 0 {
+		log.Warn().Msgf("Ignoring stats exclusion regexes %v, stats inclusion regexes %v take precedence",
+			buildConfig.StatsExclusionRegexes, buildConfig.StatsInclusionRegexes)
+	}
+
+	buildConfig.StatsTags = nil
+	for _, tag := range stats.Tags {
+		if _, err := regexp.Compile(tag.Regex); err != nil {
+			log.Warn().Err(err).Msgf("Ignoring stats tag %s with invalid regex %s", tag.Name, tag.Regex)
+			continue
+		}
+		buildConfig.StatsTags = append(buildConfig.StatsTags, bootstrap.StatsTag{
+			Name:  tag.Name,
+			Regex: tag.Regex,
+		})
+	}
+}
+
+// splitRegexesAnnotation returns the non-empty regular expressions of the given comma-separated annotation value
+func splitRegexesAnnotation(value string) []string {
+	var regexes []string
+	for _, regex := range strings.Split(value, ",") {
+		if regex = strings.TrimSpace(regex); regex != "" {
+			regexes = append(regexes, regex)
+		}
+	}
+	return regexes
+}
+
+// getValidRegexes returns the valid regular expressions among the given ones
+func getValidRegexes(regexes []string) []string {
+	var valid []string
+	for _, regex := range regexes {
+		if _, err := regexp.Compile(regex); err != nil {
+			log.Warn().Err(err).Msgf("Ignoring invalid stats regex %s", regex)
+			continue
+		}
+		valid = append(valid, regex)
+	}
+	return valid
+}
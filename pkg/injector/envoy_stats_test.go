@@ -0,0 +1,74 @@
+package injector
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/envoy/bootstrap"
+)
+
+func TestSetStatsConfig(t *testing.T) {
+	testCases := []struct {
+		name           string
+		stats          configv1alpha1.StatsSpec
+		podAnnotations map[string]string
+		expected       bootstrap.Config
+	}{
+		{
+			name:     "default stats config",
+			stats:    configv1alpha1.StatsSpec{},
+			expected: bootstrap.Config{},
+		},
+		{
+			name: "mesh-wide stats config",
+			stats: configv1alpha1.StatsSpec{
+				InclusionRegexes: []string{"^cluster\\..*"},
+				Tags: []configv1alpha1.StatsTagSpec{
+					{Name: "route", Regex: "^http\\.(route\\.(.+?)\\.)"},
+				},
+			},
+			expected: bootstrap.Config{
+				StatsInclusionRegexes: []string{"^cluster\\..*"},
+				StatsTags: []bootstrap.StatsTag{
+					{Name: "route", Regex: "^http\\.(route\\.(.+?)\\.)"},
+				},
+			},
+		},
+		{
+			name: "pod annotations override the mesh-wide inclusion and exclusion lists",
+			stats: configv1alpha1.StatsSpec{
+				InclusionRegexes: []string{"^cluster\\..*"},
+			},
+			podAnnotations: map[string]string{
+				statsExclusionRegexesAnnotation: "^listener\\..*, .*\\.upstream_cx_.*",
+			},
+			expected: bootstrap.Config{
+				StatsExclusionRegexes: []string{"^listener\\..*", ".*\\.upstream_cx_.*"},
+			},
+		},
+		{
+			name: "invalid regexes are ignored",
+			stats: configv1alpha1.StatsSpec{
+				ExclusionRegexes: []string{"^listener\\..*", "(invalid"},
+				Tags: []configv1alpha1.StatsTagSpec{
+					{Name: "invalid", Regex: "[invalid"},
+				},
+			},
+			expected: bootstrap.Config{
+				StatsExclusionRegexes: []string{"^listener\\..*"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			actual := bootstrap.Config{}
+			setStatsConfig(&actual, tc.stats, tc.podAnnotations)
+			assert.Equal(tc.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package injector
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/migration"
+)
+
+// podEventTarget returns the object the events about the given pod are recorded against: the controller of the pod if
+// any, since pods being admitted don't have a UID yet and are often only named once admitted, otherwise the pod itself
+// if named. It returns nil if the events can't be attached to any object.
+func podEventTarget(pod *corev1.Pod, namespace string) *corev1.ObjectReference {
+	if owner := metav1.GetControllerOf(pod); owner != nil {
+		return &corev1.ObjectReference{
+			Kind:       owner.Kind,
+			APIVersion: owner.APIVersion,
+			Name:       owner.Name,
+			Namespace:  namespace,
+			UID:        owner.UID,
+		}
+	}
+	if pod.Name != "" {
+		return &corev1.ObjectReference{
+			Kind:       "Pod",
+			APIVersion: "v1",
+			Name:       pod.Name,
+			Namespace:  namespace,
+			UID:        pod.UID,
+		}
+	}
+	return nil
+}
+
+// podDescription returns the name of the given pod if known, its generated name prefix otherwise
+func podDescription(pod *corev1.Pod) string {
+	if pod.Name != "" {
+		return fmt.Sprintf("pod %s", pod.Name)
+	}
+	return fmt.Sprintf("pod %s*", pod.GenerateName)
+}
+
+// injectionSkipReason returns why the sidecar isn't injected into the given pod, which mustInject determined
+func (wh *mutatingWebhook) injectionSkipReason(pod *corev1.Pod, namespace string) string {
+	if wh.nonInjectNamespaces.Contains(namespace) {
+		return fmt.Sprintf("sidecars are never injected into pods in namespace %s", namespace)
+	}
+	if !wh.kubeController.IsMonitoredNamespace(namespace) {
+		return fmt.Sprintf("namespace %s is not monitored by mesh %s", namespace, wh.meshName)
+	}
+	if exists, enabled, _ := isAnnotatedForInjection(pod.Annotations, "Pod", pod.Name); exists && !enabled {
+		return fmt.Sprintf("the pod is annotated with %s: %s", constants.SidecarInjectionAnnotation, pod.Annotations[constants.SidecarInjectionAnnotation])
+	}
+	if sidecar := migration.GetOtherMeshSidecar(pod, wh.kubeController.GetNamespace(namespace)); sidecar != nil {
+		return fmt.Sprintf("the pod carries the %s", sidecar)
+	}
+	return fmt.Sprintf("neither the pod nor namespace %s is annotated with %s: enabled", namespace, constants.SidecarInjectionAnnotation)
+}
+
+// recordInjectionSkipped records a Normal event reporting why the sidecar isn't injected into the given pod
+func (wh *mutatingWebhook) recordInjectionSkipped(pod *corev1.Pod, namespace string) {
+	if wh.config.EventRecorder == nil {
+		return
+	}
+	target := podEventTarget(pod, namespace)
+	if target == nil {
+		return
+	}
+	wh.config.EventRecorder.NormalEvent(target, events.SidecarInjectionSkipped, "Sidecar not injected into %s: %s",
+		podDescription(pod), wh.injectionSkipReason(pod, namespace))
+}
+
+// warnCertificateIssuanceFailed records a Warning event reporting that the bootstrap certificate of the sidecar of the
+// given pod could not be issued
+func (wh *mutatingWebhook) warnCertificateIssuanceFailed(pod *corev1.Pod, namespace string, err error) {
+	if wh.config.EventRecorder == nil {
+		return
+	}
+	target := podEventTarget(pod, namespace)
+	if target == nil {
+		return
+	}
+	wh.config.EventRecorder.WarnEvent(target, events.ProxyCertificateIssuanceFailed, "Error issuing the bootstrap certificate of the sidecar of %s: %s",
+		podDescription(pod), err)
+}
@@ -0,0 +1,115 @@
+package injector
+
+import (
+	"testing"
+
+	mapset "github.com/deckarep/golang-set"
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s"
+)
+
+func TestPodEventTarget(t *testing.T) {
+	isController := true
+
+	testCases := []struct {
+		name     string
+		pod      *corev1.Pod
+		expected *corev1.ObjectReference
+	}{
+		{
+			name: "pod with a controller",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName: "bookstore-5d8f6c7f9b-",
+					OwnerReferences: []metav1.OwnerReference{
+						{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "bookstore-5d8f6c7f9b", UID: types.UID("rs-uid"), Controller: &isController},
+					},
+				},
+			},
+			expected: &corev1.ObjectReference{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "bookstore-5d8f6c7f9b", Namespace: "test", UID: types.UID("rs-uid")},
+		},
+		{
+			name:     "named pod without a controller",
+			pod:      &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "bookstore"}},
+			expected: &corev1.ObjectReference{APIVersion: "v1", Kind: "Pod", Name: "bookstore", Namespace: "test"},
+		},
+		{
+			name:     "unnamed pod without a controller",
+			pod:      &corev1.Pod{ObjectMeta: metav1.ObjectMeta{GenerateName: "bookstore-"}},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expected, podEventTarget(tc.pod, "test"))
+		})
+	}
+}
+
+func TestInjectionSkipReason(t *testing.T) {
+	testCases := []struct {
+		name           string
+		namespace      string
+		monitored      bool
+		podAnnotations map[string]string
+		expected       string
+	}{
+		{
+			name:      "namespace never injected",
+			namespace: "osm-system",
+			expected:  "sidecars are never injected into pods in namespace osm-system",
+		},
+		{
+			name:      "namespace not monitored",
+			namespace: "test",
+			monitored: false,
+			expected:  "namespace test is not monitored by mesh osm",
+		},
+		{
+			name:           "pod annotated to disable injection",
+			namespace:      "test",
+			monitored:      true,
+			podAnnotations: map[string]string{constants.SidecarInjectionAnnotation: "disabled"},
+			expected:       "the pod is annotated with openservicemesh.io/sidecar-injection: disabled",
+		},
+		{
+			name:           "pod carrying the sidecar of another mesh",
+			namespace:      "test",
+			monitored:      true,
+			podAnnotations: map[string]string{constants.SidecarInjectionAnnotation: "enabled", "sidecar.istio.io/status": "{}"},
+			expected:       "the pod carries the istio sidecar (annotation sidecar.istio.io/status)",
+		},
+		{
+			name:      "neither pod nor namespace annotated",
+			namespace: "test",
+			monitored: true,
+			expected:  "neither the pod nor namespace test is annotated with openservicemesh.io/sidecar-injection: enabled",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			mockKubeController := k8s.NewMockController(gomock.NewController(t))
+			mockKubeController.EXPECT().IsMonitoredNamespace(tc.namespace).Return(tc.monitored).AnyTimes()
+			mockKubeController.EXPECT().GetNamespace(tc.namespace).Return(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: tc.namespace}}).AnyTimes()
+			wh := &mutatingWebhook{
+				kubeController:      mockKubeController,
+				meshName:            "osm",
+				nonInjectNamespaces: mapset.NewSet("osm-system"),
+			}
+
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "bookstore", Annotations: tc.podAnnotations}}
+			assert.Equal(tc.expected, wh.injectionSkipReason(pod, tc.namespace))
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package injector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+)
+
+// runExternalWorkloadBootstrapper creates the Envoy bootstrap config of the proxies of ExternalWorkloads, which is
+// the onboarding path of workloads running outside Kubernetes. The bootstrap config is stored in the same Secret
+// as for pods, 'envoy-bootstrap-config-<proxy UUID>' in the ExternalWorkload's namespace, and is copied to the
+// workload's host to start its proxy. The Secret is owned by the ExternalWorkload and deleted along with it.
+func (wh *mutatingWebhook) runExternalWorkloadBootstrapper(stop <-chan struct{}) {
+	subChannel := events.Subscribe(announcements.ExternalWorkloadAdded, announcements.ExternalWorkloadUpdated)
+	defer events.Unsub(subChannel)
+
+	for {
+		select {
+		case <-stop:
+			return
+
+		case msg := <-subChannel:
+			psubMessage, castOk := msg.(events.PubSubMessage)
+			if !castOk {
+				log.Error().Msgf("Error casting PubSubMessage: %T %v", msg, msg)
+				continue
+			}
+
+			externalWorkload, castOk := psubMessage.NewObj.(*policyv1alpha1.ExternalWorkload)
+			if !castOk {
+				log.Error().Msgf("Failed to cast to *policyv1alpha1.ExternalWorkload: %T %v", psubMessage.NewObj, psubMessage.NewObj)
+				continue
+			}
+
+			// The bootstrap config is regenerated when the identity of the proxy changes, otherwise it is only created
+			// if missing so that proxies already onboarded keep their certificate.
+			regenerate := false
+			if oldExternalWorkload, ok := psubMessage.OldObj.(*policyv1alpha1.ExternalWorkload); ok {
+				regenerate = oldExternalWorkload.Spec.ServiceAccount != externalWorkload.Spec.ServiceAccount
+			}
+
+			if err := wh.bootstrapExternalWorkload(externalWorkload, regenerate); err != nil {
+				log.Error().Err(err).Msgf("Error creating Envoy bootstrap config for ExternalWorkload %s/%s", externalWorkload.Namespace, externalWorkload.Name)
+			}
+		}
+	}
+}
+
+// bootstrapExternalWorkload creates the Envoy bootstrap config of the proxy of the given ExternalWorkload, unless it
+// already exists and regenerate is false
+func (wh *mutatingWebhook) bootstrapExternalWorkload(externalWorkload *policyv1alpha1.ExternalWorkload, regenerate bool) error {
+	proxyUUID, err := uuid.Parse(externalWorkload.Spec.ProxyUUID)
+	if err != nil {
+		return errors.Wrapf(err, "Invalid proxy UUID %s", externalWorkload.Spec.ProxyUUID)
+	}
+
+	namespace := externalWorkload.Namespace
+	secretName := getExternalWorkloadBootstrapSecretName(proxyUUID)
+	if !regenerate {
+		_, err := wh.kubeClient.CoreV1().Secrets(namespace).Get(context.Background(), secretName, metav1.GetOptions{})
+		if err == nil {
+			return nil
+		}
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "Error getting Secret %s/%s", namespace, secretName)
+		}
+	}
+
+	cn := envoy.NewXDSCertCommonName(proxyUUID, envoy.KindExternalWorkload, externalWorkload.Spec.ServiceAccount, namespace)
+	bootstrapCertificate, err := wh.certManager.IssueCertificate(cn, constants.XDSCertificateValidityPeriod)
+	if err != nil {
+		return errors.Wrapf(err, "Error issuing bootstrap certificate for Envoy with CN=%s", cn)
+	}
+
+	secret, err := wh.createEnvoyBootstrapConfig(secretName, namespace, wh.osmNamespace, bootstrapCertificate, healthProbes{}, "", nil)
+	if err != nil {
+		return err
+	}
+
+	ownerReference := metav1.OwnerReference{
+		APIVersion: policyv1alpha1.SchemeGroupVersion.String(),
+		Kind:       "ExternalWorkload",
+		Name:       externalWorkload.Name,
+		UID:        externalWorkload.UID,
+	}
+	for _, existing := range secret.OwnerReferences {
+		if existing.UID == ownerReference.UID {
+			return nil
+		}
+	}
+	secret.OwnerReferences = append(secret.OwnerReferences, ownerReference)
+	if _, err = wh.kubeClient.CoreV1().Secrets(namespace).Update(context.Background(), secret, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrapf(err, "Error setting the owner of Secret %s/%s to ExternalWorkload %s", namespace, secretName, externalWorkload.Name)
+	}
+
+	log.Info().Msgf("Created Envoy bootstrap config %s/%s for ExternalWorkload %s with certificate CN=%s", namespace, secretName, externalWorkload.Name, cn)
+	return nil
+}
+
+// getExternalWorkloadBootstrapSecretName returns the name of the Secret storing the Envoy bootstrap config of the
+// proxy with the given UUID
+func getExternalWorkloadBootstrapSecretName(proxyUUID uuid.UUID) string {
+	return fmt.Sprintf("envoy-bootstrap-config-%s", proxyUUID)
+}
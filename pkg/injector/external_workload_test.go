@@ -0,0 +1,96 @@
+package injector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
+	"github.com/openservicemesh/osm/pkg/configurator"
+)
+
+func TestBootstrapExternalWorkload(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().GetCertKeyBitSize().Return(2048).AnyTimes()
+	mockConfigurator.EXPECT().GetOverloadManagerConfig().Return(configv1alpha1.OverloadManagerSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().GetStatsConfig().Return(configv1alpha1.StatsSpec{}).AnyTimes()
+
+	kubeClient := fake.NewSimpleClientset()
+	wh := &mutatingWebhook{
+		kubeClient:   kubeClient,
+		certManager:  tresor.NewFakeCertManager(mockConfigurator),
+		configurator: mockConfigurator,
+		osmNamespace: "osm-system",
+		meshName:     "osm",
+	}
+
+	proxyUUID := uuid.New()
+	externalWorkload := &policyv1alpha1.ExternalWorkload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vm-1",
+			Namespace: "bookstore",
+			UID:       types.UID("vm-1-uid"),
+		},
+		Spec: policyv1alpha1.ExternalWorkloadSpec{
+			ServiceAccount: "bookstore",
+			ProxyUUID:      proxyUUID.String(),
+			Addresses:      []string{"10.0.0.10"},
+		},
+	}
+	secretName := getExternalWorkloadBootstrapSecretName(proxyUUID)
+
+	assertBootstrapSecret := func() {
+		secret, err := kubeClient.CoreV1().Secrets("bookstore").Get(context.Background(), secretName, metav1.GetOptions{})
+		assert.Nil(err)
+		assert.Contains(secret.Data, envoyBootstrapConfigFile)
+		assert.Len(secret.OwnerReferences, 1)
+		assert.Equal("ExternalWorkload", secret.OwnerReferences[0].Kind)
+		assert.Equal(externalWorkload.UID, secret.OwnerReferences[0].UID)
+	}
+
+	// The bootstrap config is created with a certificate for the workload's identity
+	assert.Nil(wh.bootstrapExternalWorkload(externalWorkload, false))
+	assertBootstrapSecret()
+	cert, err := wh.certManager.GetCertificate(certificate.CommonName(proxyUUID.String() + ".external.bookstore.bookstore.cluster.local"))
+	assert.Nil(err)
+	assert.NotNil(cert)
+
+	// The existing bootstrap config is kept unless it must be regenerated
+	secret, err := kubeClient.CoreV1().Secrets("bookstore").Get(context.Background(), secretName, metav1.GetOptions{})
+	assert.Nil(err)
+	secret.Data[envoyBootstrapConfigFile] = []byte("existing")
+	_, err = kubeClient.CoreV1().Secrets("bookstore").Update(context.Background(), secret, metav1.UpdateOptions{})
+	assert.Nil(err)
+
+	assert.Nil(wh.bootstrapExternalWorkload(externalWorkload, false))
+	secret, err = kubeClient.CoreV1().Secrets("bookstore").Get(context.Background(), secretName, metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Equal([]byte("existing"), secret.Data[envoyBootstrapConfigFile])
+
+	externalWorkload.Spec.ServiceAccount = "bookstore-v2"
+	assert.Nil(wh.bootstrapExternalWorkload(externalWorkload, true))
+	assertBootstrapSecret()
+	cert, err = wh.certManager.GetCertificate(certificate.CommonName(proxyUUID.String() + ".external.bookstore-v2.bookstore.cluster.local"))
+	assert.Nil(err)
+	assert.NotNil(cert)
+	secret, err = kubeClient.CoreV1().Secrets("bookstore").Get(context.Background(), secretName, metav1.GetOptions{})
+	assert.Nil(err)
+	assert.NotEqual([]byte("existing"), secret.Data[envoyBootstrapConfigFile])
+
+	// ExternalWorkloads with an invalid proxy UUID are rejected
+	externalWorkload.Spec.ProxyUUID = "invalid"
+	assert.NotNil(wh.bootstrapExternalWorkload(externalWorkload, false))
+}
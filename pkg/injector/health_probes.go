@@ -26,6 +26,10 @@ type healthProbe struct {
 	// isHTTP corresponds to an httpGet probe with a scheme of HTTP or undefined.
 	// This helps inform what kind of Envoy config to add to the pod.
 	isHTTP bool
+
+	// proxiedOverTCP corresponds to an HTTP probe whose path is kept as is and which is proxied to the application
+	// over TCP, so that it is not rejected by the proxy sidecar while its overload manager stops accepting requests.
+	proxiedOverTCP bool
 }
 
 // healthProbes is to serve as an indication whether the given healthProbe has been rewritten
@@ -33,35 +37,38 @@ type healthProbes struct {
 	liveness, readiness, startup *healthProbe
 }
 
-func rewriteHealthProbes(pod *corev1.Pod) healthProbes {
+// rewriteHealthProbes rewrites the health probes of the containers of the given pod to target the probe listeners of
+// its proxy sidecar, and returns the original probes. The HTTP probes are proxied over TCP if proxyHTTPOverTCP is set,
+// so that they keep being served while the overload manager of the proxy sidecar stops accepting requests.
+func rewriteHealthProbes(pod *corev1.Pod, proxyHTTPOverTCP bool) healthProbes {
 	probes := healthProbes{}
 	for idx := range pod.Spec.Containers {
-		if probe := rewriteLiveness(&pod.Spec.Containers[idx]); probe != nil {
+		if probe := rewriteLiveness(&pod.Spec.Containers[idx], proxyHTTPOverTCP); probe != nil {
 			probes.liveness = probe
 		}
-		if probe := rewriteReadiness(&pod.Spec.Containers[idx]); probe != nil {
+		if probe := rewriteReadiness(&pod.Spec.Containers[idx], proxyHTTPOverTCP); probe != nil {
 			probes.readiness = probe
 		}
-		if probe := rewriteStartup(&pod.Spec.Containers[idx]); probe != nil {
+		if probe := rewriteStartup(&pod.Spec.Containers[idx], proxyHTTPOverTCP); probe != nil {
 			probes.startup = probe
 		}
 	}
 	return probes
 }
 
-func rewriteLiveness(container *corev1.Container) *healthProbe {
-	return rewriteProbe(container.LivenessProbe, "liveness", livenessProbePath, livenessProbePort, &container.Ports)
+func rewriteLiveness(container *corev1.Container, proxyHTTPOverTCP bool) *healthProbe {
+	return rewriteProbe(container.LivenessProbe, "liveness", livenessProbePath, livenessProbePort, &container.Ports, proxyHTTPOverTCP)
 }
 
-func rewriteReadiness(container *corev1.Container) *healthProbe {
-	return rewriteProbe(container.ReadinessProbe, "readiness", readinessProbePath, readinessProbePort, &container.Ports)
+func rewriteReadiness(container *corev1.Container, proxyHTTPOverTCP bool) *healthProbe {
+	return rewriteProbe(container.ReadinessProbe, "readiness", readinessProbePath, readinessProbePort, &container.Ports, proxyHTTPOverTCP)
 }
 
-func rewriteStartup(container *corev1.Container) *healthProbe {
-	return rewriteProbe(container.StartupProbe, "startup", startupProbePath, startupProbePort, &container.Ports)
+func rewriteStartup(container *corev1.Container, proxyHTTPOverTCP bool) *healthProbe {
+	return rewriteProbe(container.StartupProbe, "startup", startupProbePath, startupProbePort, &container.Ports, proxyHTTPOverTCP)
 }
 
-func rewriteProbe(probe *corev1.Probe, probeType, path string, port int32, containerPorts *[]corev1.ContainerPort) *healthProbe {
+func rewriteProbe(probe *corev1.Probe, probeType, path string, port int32, containerPorts *[]corev1.ContainerPort, proxyHTTPOverTCP bool) *healthProbe {
 	if probe == nil {
 		return nil
 	}
@@ -73,7 +80,11 @@ func rewriteProbe(probe *corev1.Probe, probeType, path string, port int32, conta
 		definedPort = &probe.HTTPGet.Port
 		originalProbe.isHTTP = len(probe.HTTPGet.Scheme) == 0 || probe.HTTPGet.Scheme == corev1.URISchemeHTTP
 		originalProbe.path = probe.HTTPGet.Path
-		if originalProbe.isHTTP {
+		if originalProbe.isHTTP && proxyHTTPOverTCP {
+			// The path can't be rewritten back to the original one by a TCP proxy
+			originalProbe.proxiedOverTCP = true
+			newPath = probe.HTTPGet.Path
+		} else if originalProbe.isHTTP {
 			probe.HTTPGet.Path = path
 			newPath = probe.HTTPGet.Path
 		}
@@ -82,7 +82,7 @@ func TestRewriteProbe(t *testing.T) {
 	}
 
 	t.Run("rewriteHealthProbes", func(t *testing.T) {
-		actual := rewriteHealthProbes(pod)
+		actual := rewriteHealthProbes(pod, false)
 		expected := healthProbes{
 			liveness: &healthProbe{
 				path:   "/b",
@@ -104,7 +104,7 @@ func TestRewriteProbe(t *testing.T) {
 	})
 
 	t.Run("rewriteLiveness", func(t *testing.T) {
-		actual := rewriteLiveness(container)
+		actual := rewriteLiveness(container, false)
 		expected := &healthProbe{
 			path:   "/k/l/m",
 			port:   7890,
@@ -114,7 +114,7 @@ func TestRewriteProbe(t *testing.T) {
 	})
 
 	t.Run("rewriteReadiness", func(t *testing.T) {
-		actual := rewriteReadiness(container)
+		actual := rewriteReadiness(container, false)
 		expected := &healthProbe{
 			path:   "/a/b/c",
 			port:   1234,
@@ -124,7 +124,7 @@ func TestRewriteProbe(t *testing.T) {
 	})
 
 	t.Run("rewriteStartup", func(t *testing.T) {
-		actual := rewriteStartup(container)
+		actual := rewriteStartup(container, false)
 		expected := &healthProbe{
 			path:   "/x/y/z",
 			port:   3456,
@@ -135,11 +135,12 @@ func TestRewriteProbe(t *testing.T) {
 
 	t.Run("rewriteProbe", func(t *testing.T) {
 		tests := []struct {
-			name     string
-			probe    *v1.Probe
-			newPath  string
-			newPort  int32
-			expected *healthProbe
+			name             string
+			probe            *v1.Probe
+			proxyHTTPOverTCP bool
+			newPath          string
+			newPort          int32
+			expected         *healthProbe
 		}{
 			{
 				name:    "http",
@@ -152,6 +153,19 @@ func TestRewriteProbe(t *testing.T) {
 					isHTTP: true,
 				},
 			},
+			{
+				name:             "http proxied over TCP",
+				probe:            makeHTTPProbe("/x/y/z", 3456),
+				proxyHTTPOverTCP: true,
+				newPath:          "/x/y/z",
+				newPort:          3465,
+				expected: &healthProbe{
+					path:           "/x/y/z",
+					port:           3456,
+					isHTTP:         true,
+					proxiedOverTCP: true,
+				},
+			},
 			{
 				name:    "https",
 				probe:   makeHTTPSProbe("/x/y/z", 3456),
@@ -181,7 +195,7 @@ func TestRewriteProbe(t *testing.T) {
 				// probeType left blank here because its value is only logged.
 				// containerPorts are not defined here because it's only used
 				// in getPort(), which is tested below.
-				actual := rewriteProbe(test.probe, "", test.newPath, test.newPort, nil)
+				actual := rewriteProbe(test.probe, "", test.newPath, test.newPort, nil, test.proxyHTTPOverTCP)
 				assert.Equal(test.expected, actual)
 
 				// Verify the probe was modified correctly
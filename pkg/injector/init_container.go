@@ -1,33 +1,115 @@
 package injector
 
 import (
+	"fmt"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+const (
+	// iptablesRulesAnnotation is the annotation storing the iptables rules the distroless init container or the CNI
+	// plugin programming the traffic redirection restores, in the iptables-restore format
+	iptablesRulesAnnotation = "openservicemesh.io/iptables-rules"
+
+	// iptablesRulesVolume is the name of the volume projecting the iptables rules annotation into the init container
+	iptablesRulesVolume = "iptables-rules"
+
+	// iptablesRulesMountPath is the path the iptables rules volume is mounted at in the init container
+	iptablesRulesMountPath = "/etc/osm-init"
+
+	// iptablesRulesFile is the file of the iptables rules volume holding the iptables rules
+	iptablesRulesFile = "rules"
 )
 
 func getInitContainerSpec(containerName string, cfg configurator.Configurator, outboundIPRangeExclusionList []string, outboundPortExclusionList []int,
-	inboundPortExclusionList []int, enablePrivilegedInitContainer bool) corev1.Container {
-	iptablesInitCommandsList := generateIptablesCommands(outboundIPRangeExclusionList, outboundPortExclusionList, inboundPortExclusionList)
+	inboundPortExclusionList []int, enablePrivilegedInitContainer bool, enableDNSProxy bool, preserveSourceIP bool) corev1.Container {
+	iptablesInitCommandsList := generateIptablesCommands(constants.EnvoyUID, outboundIPRangeExclusionList, outboundPortExclusionList, inboundPortExclusionList, enableDNSProxy, preserveSourceIP)
 	iptablesInitCommand := strings.Join(iptablesInitCommandsList, " && ")
 
 	return corev1.Container{
-		Name:  containerName,
-		Image: cfg.GetInitContainerImage(),
-		SecurityContext: &corev1.SecurityContext{
-			Privileged: &enablePrivilegedInitContainer,
-			Capabilities: &corev1.Capabilities{
-				Add: []corev1.Capability{
-					"NET_ADMIN",
-				},
-			},
-		},
-		Command: []string{"/bin/sh"},
+		Name:            containerName,
+		Image:           cfg.GetInitContainerImage(),
+		SecurityContext: getInitContainerSecurityContext(enablePrivilegedInitContainer),
+		Command:         []string{"/bin/sh"},
 		Args: []string{
 			"-c",
 			iptablesInitCommand,
 		},
 	}
 }
+
+// getDistrolessInitContainerSpec returns the init container restoring the iptables rules stored in the iptables rules
+// annotation of the pod with the iptables-restore binary of a distroless image, which doesn't provide a shell. The
+// rules are appended to the existing tables rather than replacing them.
+func getDistrolessInitContainerSpec(containerName string, cfg configurator.Configurator, enablePrivilegedInitContainer bool) corev1.Container {
+	return corev1.Container{
+		Name:            containerName,
+		Image:           cfg.GetInitContainerImage(),
+		SecurityContext: getInitContainerSecurityContext(enablePrivilegedInitContainer),
+		Command: []string{
+			"iptables-restore",
+			"--noflush",
+			fmt.Sprintf("%s/%s", iptablesRulesMountPath, iptablesRulesFile),
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      iptablesRulesVolume,
+				MountPath: iptablesRulesMountPath,
+				ReadOnly:  true,
+			},
+		},
+	}
+}
+
+// getIptablesRulesVolume returns the volume projecting the iptables rules annotation of the pod into the distroless
+// init container
+func getIptablesRulesVolume() corev1.Volume {
+	return corev1.Volume{
+		Name: iptablesRulesVolume,
+		VolumeSource: corev1.VolumeSource{
+			DownwardAPI: &corev1.DownwardAPIVolumeSource{
+				Items: []corev1.DownwardAPIVolumeFile{
+					{
+						Path: iptablesRulesFile,
+						FieldRef: &corev1.ObjectFieldSelector{
+							FieldPath: fmt.Sprintf("metadata.annotations['%s']", iptablesRulesAnnotation),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// getInitContainerSecurityContext returns the security context of the init container programming the iptables rules.
+// Programming them requires the NET_ADMIN capability, and the NET_RAW capability to open the raw socket iptables
+// exchanges the rules with the kernel over, which are only effective for the root user. All the other capabilities are
+// dropped, privilege escalation is disallowed unless the init container is privileged, and the default seccomp profile
+// of the container runtime is applied.
+func getInitContainerSecurityContext(privileged bool) *corev1.SecurityContext {
+	uid := int64(0)
+	runAsNonRoot := false
+	allowPrivilegeEscalation := privileged
+	return &corev1.SecurityContext{
+		Privileged:               &privileged,
+		AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+		RunAsUser:                &uid,
+		RunAsNonRoot:             &runAsNonRoot,
+		Capabilities: &corev1.Capabilities{
+			Add: []corev1.Capability{
+				"NET_ADMIN",
+				"NET_RAW",
+			},
+			Drop: []corev1.Capability{
+				"ALL",
+			},
+		},
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		},
+	}
+}
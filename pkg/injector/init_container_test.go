@@ -19,6 +19,29 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 	privilegedFalse := false
 	privilegedTrue := true
 
+	expectedSecurityContext := func(privileged bool) *corev1.SecurityContext {
+		uid := int64(0)
+		runAsNonRoot := false
+		return &corev1.SecurityContext{
+			Privileged:               &privileged,
+			AllowPrivilegeEscalation: &privileged,
+			RunAsUser:                &uid,
+			RunAsNonRoot:             &runAsNonRoot,
+			Capabilities: &corev1.Capabilities{
+				Add: []corev1.Capability{
+					"NET_ADMIN",
+					"NET_RAW",
+				},
+				Drop: []corev1.Capability{
+					"ALL",
+				},
+			},
+			SeccompProfile: &corev1.SeccompProfile{
+				Type: corev1.SeccompProfileTypeRuntimeDefault,
+			},
+		}
+	}
+
 	mockCtrl := gomock.NewController(GinkgoT())
 	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
 
@@ -26,7 +49,7 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 		It("Creates init container without ip range exclusion list", func() {
 			mockConfigurator.EXPECT().GetInitContainerImage().Return(containerImage).Times(1)
 			privileged := privilegedFalse
-			actual := getInitContainerSpec(containerName, mockConfigurator, nil, nil, nil, privileged)
+			actual := getInitContainerSpec(containerName, mockConfigurator, nil, nil, nil, privileged, false, false)
 
 			expected := corev1.Container{
 				Name:    "-container-name-",
@@ -36,19 +59,12 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 					"-c",
 					"iptables -t nat -N PROXY_INBOUND && iptables -t nat -N PROXY_IN_REDIRECT && iptables -t nat -N PROXY_OUTPUT && iptables -t nat -N PROXY_REDIRECT && iptables -t nat -A PROXY_REDIRECT -p tcp -j REDIRECT --to-port 15001 && iptables -t nat -A PROXY_REDIRECT -p tcp --dport 15000 -j ACCEPT && iptables -t nat -A OUTPUT -p tcp -j PROXY_OUTPUT && iptables -t nat -A PROXY_OUTPUT -m owner --uid-owner 1500 -j RETURN && iptables -t nat -A PROXY_OUTPUT -d 127.0.0.1/32 -j RETURN && iptables -t nat -A PROXY_OUTPUT -j PROXY_REDIRECT && iptables -t nat -A PROXY_IN_REDIRECT -p tcp -j REDIRECT --to-port 15003 && iptables -t nat -A PREROUTING -p tcp -j PROXY_INBOUND && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15010 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15901 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15902 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15903 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp -j PROXY_IN_REDIRECT",
 				},
-				WorkingDir: "",
-				Resources:  corev1.ResourceRequirements{},
-				SecurityContext: &corev1.SecurityContext{
-					Capabilities: &corev1.Capabilities{
-						Add: []corev1.Capability{
-							"NET_ADMIN",
-						},
-					},
-					Privileged: &privilegedFalse,
-				},
-				Stdin:     false,
-				StdinOnce: false,
-				TTY:       false,
+				WorkingDir:      "",
+				Resources:       corev1.ResourceRequirements{},
+				SecurityContext: expectedSecurityContext(privilegedFalse),
+				Stdin:           false,
+				StdinOnce:       false,
+				TTY:             false,
 			}
 
 			Expect(actual).To(Equal(expected))
@@ -58,7 +74,7 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 			mockConfigurator.EXPECT().GetInitContainerImage().Return(containerImage).Times(1)
 			outboundIPRangeExclusionList := []string{"1.1.1.1/32", "10.0.0.10/24"}
 			privileged := privilegedFalse
-			actual := getInitContainerSpec(containerName, mockConfigurator, outboundIPRangeExclusionList, nil, nil, privileged)
+			actual := getInitContainerSpec(containerName, mockConfigurator, outboundIPRangeExclusionList, nil, nil, privileged, false, false)
 
 			expected := corev1.Container{
 				Name:    "-container-name-",
@@ -68,19 +84,12 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 					"-c",
 					"iptables -t nat -N PROXY_INBOUND && iptables -t nat -N PROXY_IN_REDIRECT && iptables -t nat -N PROXY_OUTPUT && iptables -t nat -N PROXY_REDIRECT && iptables -t nat -A PROXY_REDIRECT -p tcp -j REDIRECT --to-port 15001 && iptables -t nat -A PROXY_REDIRECT -p tcp --dport 15000 -j ACCEPT && iptables -t nat -A OUTPUT -p tcp -j PROXY_OUTPUT && iptables -t nat -A PROXY_OUTPUT -m owner --uid-owner 1500 -j RETURN && iptables -t nat -A PROXY_OUTPUT -d 127.0.0.1/32 -j RETURN && iptables -t nat -A PROXY_OUTPUT -j PROXY_REDIRECT && iptables -t nat -A PROXY_IN_REDIRECT -p tcp -j REDIRECT --to-port 15003 && iptables -t nat -A PREROUTING -p tcp -j PROXY_INBOUND && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15010 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15901 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15902 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15903 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp -j PROXY_IN_REDIRECT && iptables -t nat -I PROXY_OUTPUT -d 1.1.1.1/32 -j RETURN && iptables -t nat -I PROXY_OUTPUT -d 10.0.0.10/24 -j RETURN",
 				},
-				WorkingDir: "",
-				Resources:  corev1.ResourceRequirements{},
-				SecurityContext: &corev1.SecurityContext{
-					Capabilities: &corev1.Capabilities{
-						Add: []corev1.Capability{
-							"NET_ADMIN",
-						},
-					},
-					Privileged: &privilegedFalse,
-				},
-				Stdin:     false,
-				StdinOnce: false,
-				TTY:       false,
+				WorkingDir:      "",
+				Resources:       corev1.ResourceRequirements{},
+				SecurityContext: expectedSecurityContext(privilegedFalse),
+				Stdin:           false,
+				StdinOnce:       false,
+				TTY:             false,
 			}
 
 			Expect(actual).To(Equal(expected))
@@ -89,7 +98,7 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 		It("Creates init container with privileged true", func() {
 			mockConfigurator.EXPECT().GetInitContainerImage().Return(containerImage).Times(1)
 			privileged := privilegedTrue
-			actual := getInitContainerSpec(containerName, mockConfigurator, nil, nil, nil, privileged)
+			actual := getInitContainerSpec(containerName, mockConfigurator, nil, nil, nil, privileged, false, false)
 
 			expected := corev1.Container{
 				Name:    "-container-name-",
@@ -99,19 +108,12 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 					"-c",
 					"iptables -t nat -N PROXY_INBOUND && iptables -t nat -N PROXY_IN_REDIRECT && iptables -t nat -N PROXY_OUTPUT && iptables -t nat -N PROXY_REDIRECT && iptables -t nat -A PROXY_REDIRECT -p tcp -j REDIRECT --to-port 15001 && iptables -t nat -A PROXY_REDIRECT -p tcp --dport 15000 -j ACCEPT && iptables -t nat -A OUTPUT -p tcp -j PROXY_OUTPUT && iptables -t nat -A PROXY_OUTPUT -m owner --uid-owner 1500 -j RETURN && iptables -t nat -A PROXY_OUTPUT -d 127.0.0.1/32 -j RETURN && iptables -t nat -A PROXY_OUTPUT -j PROXY_REDIRECT && iptables -t nat -A PROXY_IN_REDIRECT -p tcp -j REDIRECT --to-port 15003 && iptables -t nat -A PREROUTING -p tcp -j PROXY_INBOUND && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15010 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15901 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15902 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15903 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp -j PROXY_IN_REDIRECT",
 				},
-				WorkingDir: "",
-				Resources:  corev1.ResourceRequirements{},
-				SecurityContext: &corev1.SecurityContext{
-					Capabilities: &corev1.Capabilities{
-						Add: []corev1.Capability{
-							"NET_ADMIN",
-						},
-					},
-					Privileged: &privilegedTrue,
-				},
-				Stdin:     false,
-				StdinOnce: false,
-				TTY:       false,
+				WorkingDir:      "",
+				Resources:       corev1.ResourceRequirements{},
+				SecurityContext: expectedSecurityContext(privilegedTrue),
+				Stdin:           false,
+				StdinOnce:       false,
+				TTY:             false,
 			}
 
 			Expect(actual).To(Equal(expected))
@@ -120,7 +122,7 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 		It("Creates init container without outbound port exclusion list", func() {
 			mockConfigurator.EXPECT().GetInitContainerImage().Return(containerImage).Times(1)
 			privileged := privilegedFalse
-			actual := getInitContainerSpec(containerName, mockConfigurator, nil, nil, nil, privileged)
+			actual := getInitContainerSpec(containerName, mockConfigurator, nil, nil, nil, privileged, false, false)
 
 			expected := corev1.Container{
 				Name:    "-container-name-",
@@ -130,19 +132,12 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 					"-c",
 					"iptables -t nat -N PROXY_INBOUND && iptables -t nat -N PROXY_IN_REDIRECT && iptables -t nat -N PROXY_OUTPUT && iptables -t nat -N PROXY_REDIRECT && iptables -t nat -A PROXY_REDIRECT -p tcp -j REDIRECT --to-port 15001 && iptables -t nat -A PROXY_REDIRECT -p tcp --dport 15000 -j ACCEPT && iptables -t nat -A OUTPUT -p tcp -j PROXY_OUTPUT && iptables -t nat -A PROXY_OUTPUT -m owner --uid-owner 1500 -j RETURN && iptables -t nat -A PROXY_OUTPUT -d 127.0.0.1/32 -j RETURN && iptables -t nat -A PROXY_OUTPUT -j PROXY_REDIRECT && iptables -t nat -A PROXY_IN_REDIRECT -p tcp -j REDIRECT --to-port 15003 && iptables -t nat -A PREROUTING -p tcp -j PROXY_INBOUND && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15010 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15901 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15902 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15903 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp -j PROXY_IN_REDIRECT",
 				},
-				WorkingDir: "",
-				Resources:  corev1.ResourceRequirements{},
-				SecurityContext: &corev1.SecurityContext{
-					Capabilities: &corev1.Capabilities{
-						Add: []corev1.Capability{
-							"NET_ADMIN",
-						},
-					},
-					Privileged: &privilegedFalse,
-				},
-				Stdin:     false,
-				StdinOnce: false,
-				TTY:       false,
+				WorkingDir:      "",
+				Resources:       corev1.ResourceRequirements{},
+				SecurityContext: expectedSecurityContext(privilegedFalse),
+				Stdin:           false,
+				StdinOnce:       false,
+				TTY:             false,
 			}
 
 			Expect(actual).To(Equal(expected))
@@ -152,7 +147,7 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 			mockConfigurator.EXPECT().GetInitContainerImage().Return(containerImage).Times(1)
 			outboundPortExclusionList := []int{6060, 7070}
 			privileged := privilegedFalse
-			actual := getInitContainerSpec(containerName, mockConfigurator, nil, outboundPortExclusionList, nil, privileged)
+			actual := getInitContainerSpec(containerName, mockConfigurator, nil, outboundPortExclusionList, nil, privileged, false, false)
 
 			expected := corev1.Container{
 				Name:    "-container-name-",
@@ -162,22 +157,39 @@ var _ = Describe("Test functions creating Envoy bootstrap configuration", func()
 					"-c",
 					"iptables -t nat -N PROXY_INBOUND && iptables -t nat -N PROXY_IN_REDIRECT && iptables -t nat -N PROXY_OUTPUT && iptables -t nat -N PROXY_REDIRECT && iptables -t nat -A PROXY_REDIRECT -p tcp -j REDIRECT --to-port 15001 && iptables -t nat -A PROXY_REDIRECT -p tcp --dport 15000 -j ACCEPT && iptables -t nat -A OUTPUT -p tcp -j PROXY_OUTPUT && iptables -t nat -A PROXY_OUTPUT -m owner --uid-owner 1500 -j RETURN && iptables -t nat -A PROXY_OUTPUT -d 127.0.0.1/32 -j RETURN && iptables -t nat -A PROXY_OUTPUT -j PROXY_REDIRECT && iptables -t nat -A PROXY_IN_REDIRECT -p tcp -j REDIRECT --to-port 15003 && iptables -t nat -A PREROUTING -p tcp -j PROXY_INBOUND && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15010 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15901 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15902 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp --dport 15903 -j RETURN && iptables -t nat -A PROXY_INBOUND -p tcp -j PROXY_IN_REDIRECT && iptables -t nat -I PROXY_OUTPUT -p tcp --match multiport --dports 6060,7070 -j RETURN",
 				},
-				WorkingDir: "",
-				Resources:  corev1.ResourceRequirements{},
-				SecurityContext: &corev1.SecurityContext{
-					Capabilities: &corev1.Capabilities{
-						Add: []corev1.Capability{
-							"NET_ADMIN",
-						},
+				WorkingDir:      "",
+				Resources:       corev1.ResourceRequirements{},
+				SecurityContext: expectedSecurityContext(privilegedFalse),
+				Stdin:           false,
+				StdinOnce:       false,
+				TTY:             false,
+			}
+
+			Expect(actual).To(Equal(expected))
+		})
+	})
+
+	Context("test getDistrolessInitContainerSpec()", func() {
+		It("Creates init container restoring the iptables rules of the pod annotation", func() {
+			mockConfigurator.EXPECT().GetInitContainerImage().Return(containerImage).Times(1)
+			actual := getDistrolessInitContainerSpec(containerName, mockConfigurator, privilegedFalse)
+
+			expected := corev1.Container{
+				Name:            "-container-name-",
+				Image:           "-init-container-image-",
+				Command:         []string{"iptables-restore", "--noflush", "/etc/osm-init/rules"},
+				SecurityContext: expectedSecurityContext(privilegedFalse),
+				VolumeMounts: []corev1.VolumeMount{
+					{
+						Name:      "iptables-rules",
+						MountPath: "/etc/osm-init",
+						ReadOnly:  true,
 					},
-					Privileged: &privilegedFalse,
 				},
-				Stdin:     false,
-				StdinOnce: false,
-				TTY:       false,
 			}
 
 			Expect(actual).To(Equal(expected))
+			Expect(getIptablesRulesVolume().DownwardAPI.Items[0].FieldRef.FieldPath).To(Equal("metadata.annotations['openservicemesh.io/iptables-rules']"))
 		})
 	})
 })
@@ -5,6 +5,8 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/pkg/errors"
+
 	"github.com/openservicemesh/osm/pkg/constants"
 )
 
@@ -23,25 +25,28 @@ var iptablesRedirectionChains = []string{
 	"iptables -t nat -N PROXY_REDIRECT",
 }
 
-// iptablesOutboundStaticRules is the list of iptables rules related to outbound traffic interception and redirection
-var iptablesOutboundStaticRules = []string{
-	// Redirects outbound TCP traffic hitting PROXY_REDIRECT chain to Envoy's outbound listener port
-	fmt.Sprintf("iptables -t nat -A PROXY_REDIRECT -p tcp -j REDIRECT --to-port %d", constants.EnvoyOutboundListenerPort),
+// getIptablesOutboundStaticRules returns the list of iptables rules related to outbound traffic interception and
+// redirection, for the proxy sidecar running with the given UID
+func getIptablesOutboundStaticRules(proxyUID int64) []string {
+	return []string{
+		// Redirects outbound TCP traffic hitting PROXY_REDIRECT chain to Envoy's outbound listener port
+		fmt.Sprintf("iptables -t nat -A PROXY_REDIRECT -p tcp -j REDIRECT --to-port %d", constants.EnvoyOutboundListenerPort),
 
-	// Traffic to the Proxy Admin port flows to the Proxy -- not redirected
-	fmt.Sprintf("iptables -t nat -A PROXY_REDIRECT -p tcp --dport %d -j ACCEPT", constants.EnvoyAdminPort),
+		// Traffic to the Proxy Admin port flows to the Proxy -- not redirected
+		fmt.Sprintf("iptables -t nat -A PROXY_REDIRECT -p tcp --dport %d -j ACCEPT", constants.EnvoyAdminPort),
 
-	// For outbound TCP traffic jump from OUTPUT chain to PROXY_OUTPUT chain
-	"iptables -t nat -A OUTPUT -p tcp -j PROXY_OUTPUT",
+		// For outbound TCP traffic jump from OUTPUT chain to PROXY_OUTPUT chain
+		"iptables -t nat -A OUTPUT -p tcp -j PROXY_OUTPUT",
 
-	// Don't redirect Envoy traffic back to itself, return it to the next chain for processing
-	fmt.Sprintf("iptables -t nat -A PROXY_OUTPUT -m owner --uid-owner %d -j RETURN", constants.EnvoyUID),
+		// Don't redirect Envoy traffic back to itself, return it to the next chain for processing
+		fmt.Sprintf("iptables -t nat -A PROXY_OUTPUT -m owner --uid-owner %d -j RETURN", proxyUID),
 
-	// Skip localhost traffic, doesn't need to be routed via the proxy
-	"iptables -t nat -A PROXY_OUTPUT -d 127.0.0.1/32 -j RETURN",
+		// Skip localhost traffic, doesn't need to be routed via the proxy
+		"iptables -t nat -A PROXY_OUTPUT -d 127.0.0.1/32 -j RETURN",
 
-	// Redirect remaining outbound traffic to Envoy
-	"iptables -t nat -A PROXY_OUTPUT -j PROXY_REDIRECT",
+		// Redirect remaining outbound traffic to Envoy
+		"iptables -t nat -A PROXY_OUTPUT -j PROXY_REDIRECT",
+	}
 }
 
 // iptablesInboundStaticRules is the list of iptables rules related to inbound traffic interception and redirection
@@ -66,15 +71,54 @@ var iptablesInboundStaticRules = []string{
 	"iptables -t nat -A PROXY_INBOUND -p tcp -j PROXY_IN_REDIRECT",
 }
 
-// generateIptablesCommands generates a list of iptables commands to set up sidecar interception and redirection
-func generateIptablesCommands(outboundIPRangeExclusionList []string, outboundPortExclusionList []int, inboundPortExclusionList []int) []string {
+// dnsPort is the port DNS queries are sent to
+const dnsPort = 53
+
+// getIptablesDNSRedirectionRules returns the list of iptables rules redirecting the DNS queries of the pod to the proxy
+// sidecar running with the given UID
+func getIptablesDNSRedirectionRules(proxyUID int64) []string {
+	return []string{
+		// Don't redirect the DNS queries the proxy sidecar forwards to the pod's DNS resolvers
+		fmt.Sprintf("iptables -t nat -A OUTPUT -p udp --dport %d -m owner --uid-owner %d -j RETURN", dnsPort, proxyUID),
+
+		// Redirect remaining DNS queries to Envoy's DNS listener
+		fmt.Sprintf("iptables -t nat -A OUTPUT -p udp --dport %d -j REDIRECT --to-port %d", dnsPort, constants.EnvoyDNSListenerPort),
+	}
+}
+
+// originalSourceRouteTable is the routing table routing the traffic marked with Envoy's original source mark to the
+// proxy sidecar
+const originalSourceRouteTable = 133
+
+// iptablesOriginalSourceRules is the list of iptables and routing rules routing the responses of the applications to
+// the inbound traffic the proxy sidecar proxies from the original source IP of the clients back to the proxy sidecar.
+// Without them, the responses to the source IP of the clients would be routed out of the pod.
+var iptablesOriginalSourceRules = []string{
+	// Envoy runs as root to open connections from the source IP of the clients, so its traffic is identified by its
+	// group instead of its user. Don't redirect Envoy traffic back to itself.
+	fmt.Sprintf("iptables -t nat -I PROXY_OUTPUT -m owner --gid-owner %d -j RETURN", constants.EnvoyGID),
+
+	// Mark the connections Envoy opens from the original source IP of the clients, whose packets are marked by Envoy
+	fmt.Sprintf("iptables -t mangle -A PREROUTING -m mark --mark %d -j CONNMARK --save-mark", constants.EnvoyOriginalSourceMark),
+
+	// Mark the responses of the applications on these connections
+	fmt.Sprintf("iptables -t mangle -A OUTPUT -m connmark --mark %d -j CONNMARK --restore-mark", constants.EnvoyOriginalSourceMark),
+
+	// Deliver the marked responses locally, to Envoy
+	fmt.Sprintf("ip rule add fwmark %d lookup %d", constants.EnvoyOriginalSourceMark, originalSourceRouteTable),
+	fmt.Sprintf("ip route add local 0.0.0.0/0 dev lo table %d", originalSourceRouteTable),
+}
+
+// generateIptablesCommands generates a list of iptables commands to set up sidecar interception and redirection, for
+// the proxy sidecar running with the given UID
+func generateIptablesCommands(proxyUID int64, outboundIPRangeExclusionList []string, outboundPortExclusionList []int, inboundPortExclusionList []int, enableDNSProxy bool, preserveSourceIP bool) []string {
 	var cmd []string
 
 	// 1. Create redirection chains
 	cmd = append(cmd, iptablesRedirectionChains...)
 
 	// 2. Create outbound rules
-	cmd = append(cmd, iptablesOutboundStaticRules...)
+	cmd = append(cmd, getIptablesOutboundStaticRules(proxyUID)...)
 
 	// 3. Create inbound rules
 	cmd = append(cmd, iptablesInboundStaticRules...)
@@ -109,5 +153,61 @@ func generateIptablesCommands(outboundIPRangeExclusionList []string, outboundPor
 		cmd = append(cmd, rule)
 	}
 
+	// 7. Create DNS redirection rules
+	if enableDNSProxy {
+		cmd = append(cmd, getIptablesDNSRedirectionRules(proxyUID)...)
+	}
+
+	// 8. Create the rules routing the responses to the inbound traffic proxied from the original source IP back to the proxy
+	if preserveSourceIP {
+		cmd = append(cmd, iptablesOriginalSourceRules...)
+		if enableDNSProxy {
+			// Don't redirect the DNS queries Envoy forwards to the pod's DNS resolvers, identified by Envoy's group
+			cmd = append(cmd, fmt.Sprintf("iptables -t nat -I OUTPUT -p udp --dport %d -m owner --gid-owner %d -j RETURN", dnsPort, constants.EnvoyGID))
+		}
+	}
+
 	return cmd
 }
+
+// generateIptablesRestoreRules renders the given iptables commands in the iptables-restore format, grouping the rules of
+// each table in the order of the commands. The chains created with '-N' are declared in the header of their table.
+// An error is returned for commands other than iptables commands, such as the routing rules, which can't be restored.
+func generateIptablesRestoreRules(commands []string) (string, error) {
+	var tables []string
+	chains := make(map[string][]string)
+	rules := make(map[string][]string)
+
+	for _, command := range commands {
+		args := strings.Fields(command)
+		if len(args) < 2 || args[0] != "iptables" {
+			return "", errors.Errorf("Command %q can't be restored with iptables-restore", command)
+		}
+		args = args[1:]
+
+		table := "filter"
+		if len(args) >= 2 && args[0] == "-t" {
+			table = args[1]
+			args = args[2:]
+		}
+		if _, ok := rules[table]; !ok {
+			tables = append(tables, table)
+			rules[table] = nil
+		}
+
+		if len(args) == 2 && args[0] == "-N" {
+			chains[table] = append(chains[table], fmt.Sprintf(":%s - [0:0]", args[1]))
+			continue
+		}
+		rules[table] = append(rules[table], strings.Join(args, " "))
+	}
+
+	var lines []string
+	for _, table := range tables {
+		lines = append(lines, "*"+table)
+		lines = append(lines, chains[table]...)
+		lines = append(lines, rules[table]...)
+		lines = append(lines, "COMMIT")
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
@@ -1,9 +1,12 @@
 package injector
 
 import (
+	"strings"
 	"testing"
 
 	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/constants"
 )
 
 func TestGenerateIptablesCommands(t *testing.T) {
@@ -13,7 +16,7 @@ func TestGenerateIptablesCommands(t *testing.T) {
 	outboundPortExclusion := []int{10, 20}
 	inboundPortExclusion := []int{30, 40}
 
-	actual := generateIptablesCommands(outboundIPRangeExclusion, outboundPortExclusion, inboundPortExclusion)
+	actual := generateIptablesCommands(constants.EnvoyUID, outboundIPRangeExclusion, outboundPortExclusion, inboundPortExclusion, false, false)
 
 	expected := []string{
 		"iptables -t nat -N PROXY_INBOUND",
@@ -41,3 +44,101 @@ func TestGenerateIptablesCommands(t *testing.T) {
 
 	assert.ElementsMatch(expected, actual)
 }
+
+func TestGenerateIptablesCommandsWithDNSProxy(t *testing.T) {
+	assert := tassert.New(t)
+
+	actual := generateIptablesCommands(constants.EnvoyUID, nil, nil, nil, true, false)
+
+	// DNS queries are redirected to Envoy's DNS listener, except for the queries Envoy forwards to the pod's DNS resolvers
+	assert.Subset(actual, []string{
+		"iptables -t nat -A OUTPUT -p udp --dport 53 -m owner --uid-owner 1500 -j RETURN",
+		"iptables -t nat -A OUTPUT -p udp --dport 53 -j REDIRECT --to-port 15053",
+	})
+	assert.Len(actual, len(generateIptablesCommands(constants.EnvoyUID, nil, nil, nil, false, false))+2)
+}
+
+func TestGenerateIptablesCommandsWithSourceIPPreservation(t *testing.T) {
+	assert := tassert.New(t)
+
+	actual := generateIptablesCommands(constants.EnvoyUID, nil, nil, nil, false, true)
+
+	// The responses to the connections Envoy marks are routed back to Envoy
+	assert.Subset(actual, []string{
+		"iptables -t nat -I PROXY_OUTPUT -m owner --gid-owner 1500 -j RETURN",
+		"iptables -t mangle -A PREROUTING -m mark --mark 1500 -j CONNMARK --save-mark",
+		"iptables -t mangle -A OUTPUT -m connmark --mark 1500 -j CONNMARK --restore-mark",
+		"ip rule add fwmark 1500 lookup 133",
+		"ip route add local 0.0.0.0/0 dev lo table 133",
+	})
+	assert.Len(actual, len(generateIptablesCommands(constants.EnvoyUID, nil, nil, nil, false, false))+5)
+
+	// The DNS queries Envoy forwards are identified by Envoy's group
+	actual = generateIptablesCommands(constants.EnvoyUID, nil, nil, nil, true, true)
+	assert.Contains(actual, "iptables -t nat -I OUTPUT -p udp --dport 53 -m owner --gid-owner 1500 -j RETURN")
+}
+
+func TestGenerateIptablesCommandsWithProxyUID(t *testing.T) {
+	assert := tassert.New(t)
+
+	actual := generateIptablesCommands(1000680000, nil, nil, nil, true, false)
+
+	// The traffic of the proxy sidecar is identified by its UID
+	assert.Subset(actual, []string{
+		"iptables -t nat -A PROXY_OUTPUT -m owner --uid-owner 1000680000 -j RETURN",
+		"iptables -t nat -A OUTPUT -p udp --dport 53 -m owner --uid-owner 1000680000 -j RETURN",
+	})
+	assert.NotContains(strings.Join(actual, " "), "--uid-owner 1500")
+}
+
+func TestGenerateIptablesRestoreRules(t *testing.T) {
+	testCases := []struct {
+		name          string
+		commands      []string
+		expectedRules string
+		expectedErr   bool
+	}{
+		{
+			name: "chains and rules of multiple tables",
+			commands: []string{
+				"iptables -t nat -N PROXY_OUTPUT",
+				"iptables -t nat -A OUTPUT -p tcp -j PROXY_OUTPUT",
+				"iptables -t mangle -A OUTPUT -m connmark --mark 1500 -j CONNMARK --restore-mark",
+				"iptables -t nat -I PROXY_OUTPUT -d 1.1.1.1/32 -j RETURN",
+				"iptables -A INPUT -j ACCEPT",
+			},
+			expectedRules: `*nat
+:PROXY_OUTPUT - [0:0]
+-A OUTPUT -p tcp -j PROXY_OUTPUT
+-I PROXY_OUTPUT -d 1.1.1.1/32 -j RETURN
+COMMIT
+*mangle
+-A OUTPUT -m connmark --mark 1500 -j CONNMARK --restore-mark
+COMMIT
+*filter
+-A INPUT -j ACCEPT
+COMMIT
+`,
+		},
+		{
+			name:        "routing rules",
+			commands:    generateIptablesCommands(constants.EnvoyUID, nil, nil, nil, false, true),
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			actual, err := generateIptablesRestoreRules(tc.commands)
+			assert.Equal(tc.expectedErr, err != nil)
+			assert.Equal(tc.expectedRules, actual)
+		})
+	}
+
+	// Every command of the interception rules is restored
+	rules, err := generateIptablesRestoreRules(generateIptablesCommands(constants.EnvoyUID, []string{"1.1.1.1/32"}, []int{10}, []int{20}, true, false))
+	tassert.Nil(t, err)
+	tassert.Equal(t, len(generateIptablesCommands(constants.EnvoyUID, []string{"1.1.1.1/32"}, []int{10}, []int{20}, true, false))+2, len(strings.Split(strings.TrimSpace(rules), "\n")))
+}
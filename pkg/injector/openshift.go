@@ -0,0 +1,93 @@
+package injector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// openShiftUIDRangeAnnotation is the annotation of OpenShift namespaces holding the range of UIDs the pods of the
+	// namespace run with under the restricted SecurityContextConstraints, as '<first UID>/<number of UIDs>'
+	openShiftUIDRangeAnnotation = "openshift.io/sa.scc.uid-range"
+
+	// openShiftPrivilegedSCC is the SecurityContextConstraints admitting privileged containers running as any user
+	openShiftPrivilegedSCC = "privileged"
+)
+
+// isOpenShift returns whether the sidecar is injected into pods of an OpenShift cluster
+func (wh *mutatingWebhook) isOpenShift() bool {
+	return wh.config.Platform == PlatformOpenShift
+}
+
+// getOpenShiftProxyUID returns the UID the proxy sidecar of the pods of the given OpenShift namespace runs with under
+// the restricted SecurityContextConstraints, the first UID of the range of the namespace, which the iptables rules
+// identify the traffic of the proxy sidecar with
+func getOpenShiftProxyUID(ns *corev1.Namespace) (int64, error) {
+	uidRange, ok := ns.Annotations[openShiftUIDRangeAnnotation]
+	if !ok {
+		return 0, errors.Errorf("Namespace %s has no %s annotation to pick the UID of the proxy sidecar from", ns.Name, openShiftUIDRangeAnnotation)
+	}
+
+	uid, err := strconv.ParseInt(strings.SplitN(uidRange, "/", 2)[0], 10, 64)
+	if err != nil || uid <= 0 {
+		return 0, errors.Errorf("Invalid value specified for annotation %q of namespace %s: %s", openShiftUIDRangeAnnotation, ns.Name, uidRange)
+	}
+	return uid, nil
+}
+
+// getOpenShiftSidecarSecurityContext returns the security context of the proxy sidecar admitted by the restricted
+// SecurityContextConstraints, running with the given UID of the range of its namespace without any capability
+func getOpenShiftSidecarSecurityContext(uid int64) *corev1.SecurityContext {
+	runAsNonRoot := true
+	allowPrivilegeEscalation := false
+	return &corev1.SecurityContext{
+		RunAsUser:                &uid,
+		RunAsNonRoot:             &runAsNonRoot,
+		AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{
+				"ALL",
+			},
+		},
+	}
+}
+
+// verifySCCUse verifies that the service account of the given pod is allowed to use the given SecurityContextConstraints,
+// required by the given part of the injected containers. The returned error explains how to grant it.
+func (wh *mutatingWebhook) verifySCCUse(pod *corev1.Pod, namespace string, scc string, requiredBy string) error {
+	serviceAccount := pod.Spec.ServiceAccountName
+	if serviceAccount == "" {
+		serviceAccount = "default"
+	}
+
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   fmt.Sprintf("%s%s:%s", serviceAccountUsernamePrefix, namespace, serviceAccount),
+			Groups: []string{"system:serviceaccounts", fmt.Sprintf("system:serviceaccounts:%s", namespace)},
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "use",
+				Group:     "security.openshift.io",
+				Resource:  "securitycontextconstraints",
+				Name:      scc,
+			},
+		},
+	}
+	result, err := wh.kubeClient.AuthorizationV1().SubjectAccessReviews().Create(context.Background(), review, metav1.CreateOptions{})
+	if err != nil {
+		return errors.Errorf("Error verifying that service account %s/%s is allowed to use the %q SecurityContextConstraints: %s", namespace, serviceAccount, scc, err)
+	}
+	if !result.Status.Allowed {
+		return errors.Errorf("Service account %s/%s is not allowed to use the %q SecurityContextConstraints required by %s. "+
+			"Grant it with 'oc adm policy add-scc-to-user %s -z %s -n %s', or redirect the traffic of pods with a CNI plugin by running osm-injector with --traffic-redirection=%s",
+			namespace, serviceAccount, scc, requiredBy, scc, serviceAccount, namespace, TrafficRedirectionCNI)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package injector
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestGetOpenShiftProxyUID(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		expectedUID int64
+		expectedErr bool
+	}{
+		{
+			name:        "UID range of the namespace",
+			annotations: map[string]string{openShiftUIDRangeAnnotation: "1000680000/10000"},
+			expectedUID: 1000680000,
+		},
+		{
+			name:        "no UID range",
+			annotations: nil,
+			expectedErr: true,
+		},
+		{
+			name:        "invalid UID range",
+			annotations: map[string]string{openShiftUIDRangeAnnotation: "invalid/10000"},
+			expectedErr: true,
+		},
+		{
+			name:        "root UID range",
+			annotations: map[string]string{openShiftUIDRangeAnnotation: "0/10000"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "bookstore", Annotations: tc.annotations}}
+			uid, err := getOpenShiftProxyUID(ns)
+			assert.Equal(tc.expectedErr, err != nil)
+			assert.Equal(tc.expectedUID, uid)
+		})
+	}
+}
+
+func TestVerifySCCUse(t *testing.T) {
+	testCases := []struct {
+		name               string
+		serviceAccountName string
+		allowed            bool
+		expectedUser       string
+		expectedErr        bool
+	}{
+		{
+			name:               "service account allowed to use the SCC",
+			serviceAccountName: "bookstore",
+			allowed:            true,
+			expectedUser:       "system:serviceaccount:bookstore-ns:bookstore",
+		},
+		{
+			name:               "service account not allowed to use the SCC",
+			serviceAccountName: "bookstore",
+			allowed:            false,
+			expectedUser:       "system:serviceaccount:bookstore-ns:bookstore",
+			expectedErr:        true,
+		},
+		{
+			name:         "default service account",
+			allowed:      true,
+			expectedUser: "system:serviceaccount:bookstore-ns:default",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			var review *authorizationv1.SubjectAccessReview
+			client := fake.NewSimpleClientset()
+			client.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				review = action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+				review.Status.Allowed = tc.allowed
+				return true, review, nil
+			})
+			wh := &mutatingWebhook{kubeClient: client}
+
+			pod := &corev1.Pod{Spec: corev1.PodSpec{ServiceAccountName: tc.serviceAccountName}}
+			err := wh.verifySCCUse(pod, "bookstore-ns", openShiftPrivilegedSCC, "the init container")
+			assert.Equal(tc.expectedErr, err != nil)
+			if tc.expectedErr {
+				assert.Contains(err.Error(), "oc adm policy add-scc-to-user privileged -z bookstore -n bookstore-ns")
+			}
+
+			assert.Equal(tc.expectedUser, review.Spec.User)
+			assert.Equal("use", review.Spec.ResourceAttributes.Verb)
+			assert.Equal("securitycontextconstraints", review.Spec.ResourceAttributes.Resource)
+			assert.Equal(openShiftPrivilegedSCC, review.Spec.ResourceAttributes.Name)
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	mapset "github.com/deckarep/golang-set"
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 	"gomodules.xyz/jsonpatch/v2"
 	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -23,44 +24,84 @@ import (
 func (wh *mutatingWebhook) createPatch(pod *corev1.Pod, req *admissionv1.AdmissionRequest, proxyUUID uuid.UUID) ([]byte, error) {
 	namespace := req.Namespace
 
-	// Issue a certificate for the proxy sidecar - used for Envoy to connect to XDS (not Envoy-to-Envoy connections)
-	cn := envoy.NewXDSCertCommonName(proxyUUID, envoy.KindSidecar, pod.Spec.ServiceAccountName, namespace)
-	log.Debug().Msgf("Patching POD spec: service-account=%s, namespace=%s with certificate CN=%s", pod.Spec.ServiceAccountName, namespace, cn)
-	startTime := time.Now()
-	bootstrapCertificate, err := wh.certManager.IssueCertificate(cn, constants.XDSCertificateValidityPeriod)
+	// The Envoy admin interface binds to a unix domain socket on a volume of the pod if configured so
+	podOS := pod.Spec.NodeSelector["kubernetes.io/os"]
+	adminSocketPath := getEnvoyAdminSocketPath(wh.configurator, podOS)
+
+	// The HTTP health probes are proxied over TCP while the overload manager is enabled, since the proxy sidecar
+	// rejects all the HTTP requests it receives once its overload manager stops accepting requests
+	overloadManagerEnabled := wh.configurator.GetOverloadManagerConfig().MaxHeapSizeBytes > 0
+	originalHealthProbes := rewriteHealthProbes(pod, overloadManagerEnabled)
+
+	// Pod Security Admission rejects the pods of the namespaces enforcing the baseline or restricted Pod Security
+	// Standard with the init container, which requires the NET_ADMIN and NET_RAW capabilities, so the traffic of these
+	// pods is redirected by a CNI plugin
+	enforcedPodSecurity, warnedPodSecurity, err := wh.getPodSecurityLevels(pod, req)
 	if err != nil {
-		log.Error().Err(err).Msgf("Error issuing bootstrap certificate for Envoy with CN=%s", cn)
 		return nil, err
 	}
-	elapsed := time.Since(startTime)
+	redirectWithCNI := wh.config.TrafficRedirection == TrafficRedirectionCNI || enforcedPodSecurity != ""
 
-	metricsstore.DefaultMetricsStore.CertIssuedCount.Inc()
-	metricsstore.DefaultMetricsStore.CertIssuedTime.
-		WithLabelValues().Observe(elapsed.Seconds())
-	originalHealthProbes := rewriteHealthProbes(pod)
+	// On OpenShift, the proxy sidecar of the pods whose traffic is redirected by a CNI plugin runs under the
+	// restricted SecurityContextConstraints, with a UID of the range of the namespace
+	proxyUID := constants.EnvoyUID
+	if redirectWithCNI && wh.isOpenShift() && !strings.EqualFold(podOS, constants.OSWindows) {
+		proxyUID, err = getOpenShiftProxyUID(wh.kubeController.GetNamespace(namespace))
+		if err != nil {
+			log.Error().Err(err).Msgf("Error picking the UID of the proxy sidecar of pod: service-account=%s, namespace=%s", pod.Spec.ServiceAccountName, namespace)
+			return nil, err
+		}
+	}
 
-	// Create the bootstrap configuration for the Envoy proxy for the given pod
-	envoyBootstrapConfigName := fmt.Sprintf("envoy-bootstrap-config-%s", proxyUUID)
+	// The bootstrap config is rendered by an init container if configured so, Windows pods aren't injected with one
+	renderBootstrapConfig := wh.config.BootstrapDelivery == BootstrapDeliveryVolume && !strings.EqualFold(podOS, constants.OSWindows)
+	var envoyBootstrapConfigName string
+	var injectedContainers []corev1.Container
+	if renderBootstrapConfig {
+		// The certificate of the proxy sidecar is issued when the init container fetches the bootstrap config, which
+		// proxies the rewritten health probes to the original ones
+		if originalHealthProbes != (healthProbes{}) {
+			value, err := marshalHealthProbes(originalHealthProbes)
+			if err != nil {
+				log.Error().Err(err).Msgf("Error marshaling original health probes of pod: service-account=%s, namespace=%s", pod.Spec.ServiceAccountName, namespace)
+				return nil, err
+			}
+			if pod.Annotations == nil {
+				pod.Annotations = make(map[string]string)
+			}
+			pod.Annotations[originalHealthProbesAnnotation] = value
+		}
+		pod.Spec.Volumes = append(pod.Spec.Volumes, getBootstrapVolumes()...)
+		bootstrapInitContainer := getBootstrapInitContainerSpec(wh.configurator.GetInitContainerImage(), wh.config.getBootstrapURL(wh.osmNamespace), wh.cert.GetIssuingCA())
+		if enforcedPodSecurity == podSecurityRestricted {
+			bootstrapInitContainer.SecurityContext = getRestrictedSecurityContext(proxyUID)
+		}
+		pod.Spec.InitContainers = append(pod.Spec.InitContainers, bootstrapInitContainer)
+		injectedContainers = append(injectedContainers, bootstrapInitContainer)
+	} else {
+		// The secret holding the bootstrap config is created once the injected containers are verified
+		envoyBootstrapConfigName = fmt.Sprintf("envoy-bootstrap-config-%s", proxyUUID)
 
-	// The webhook has a side effect (making out-of-band changes) of creating k8s secret
-	// corresponding to the Envoy bootstrap config. Such a side effect needs to be skipped
-	// when the request is a DryRun.
-	// Ref: https://kubernetes.io/docs/reference/access-authn-authz/extensible-admission-controllers/#side-effects
-	if req.DryRun != nil && *req.DryRun {
-		log.Debug().Msgf("Skipping envoy bootstrap config creation for dry-run request: service-account=%s, namespace=%s", pod.Spec.ServiceAccountName, namespace)
-	} else if _, err = wh.createEnvoyBootstrapConfig(envoyBootstrapConfigName, namespace, wh.osmNamespace, bootstrapCertificate, originalHealthProbes); err != nil {
-		log.Error().Err(err).Msgf("Failed to create Envoy bootstrap config for pod: service-account=%s, namespace=%s, certificate CN=%s", pod.Spec.ServiceAccountName, namespace, cn)
-		return nil, err
+		// Create volume for envoy TLS secret
+		pod.Spec.Volumes = append(pod.Spec.Volumes, getVolumeSpec(envoyBootstrapConfigName)...)
 	}
 
-	// Create volume for envoy TLS secret
-	pod.Spec.Volumes = append(pod.Spec.Volumes, getVolumeSpec(envoyBootstrapConfigName)...)
-
 	// On Windows we cannot use init containers to program HNS because it requires elevated privileges
 	// As a result we assume that the HNS redirection policies are already programmed via a CNI plugin.
 	// Skip adding the init container and only patch the pod spec with sidecar container.
-	podOS := pod.Spec.NodeSelector["kubernetes.io/os"]
+	preserveSourceIP := false
+	// The SecurityContextConstraints the service account of the pod must be allowed to use on OpenShift, with the
+	// injected containers requiring them
+	requiredSCCs := make(map[string]string)
 	if !strings.EqualFold(podOS, constants.OSWindows) {
+		// Inbound traffic is proxied from the original source IP of the clients if enabled on the namespace,
+		// relying on the rules of the init container routing the responses back to the proxy sidecar
+		preserveSourceIP, err = wh.isSourceIPPreservationEnabled(namespace)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error checking if namespace %s is enabled for source IP preservation", namespace)
+			return nil, err
+		}
+
 		// Build outbound port exclusion list
 		podOutboundPortExclusionList, _ := wh.getPortExclusionListForPod(pod, namespace, outboundPortExclusionListAnnotation)
 		globalOutboundPortExclusionList := wh.configurator.GetOutboundPortExclusionList()
@@ -71,14 +112,89 @@ func (wh *mutatingWebhook) createPatch(pod *corev1.Pod, req *admissionv1.Admissi
 		globalInboundPortExclusionList := wh.configurator.GetInboundPortExclusionList()
 		inboundPortExclusionList := mergePortExclusionLists(podInboundPortExclusionList, globalInboundPortExclusionList)
 
-		// Add the Init Container
-		initContainer := getInitContainerSpec(constants.InitContainerName, wh.configurator, wh.configurator.GetOutboundIPRangeExclusionList(), outboundPortExclusionList, inboundPortExclusionList, wh.configurator.IsPrivilegedInitContainer())
-		pod.Spec.InitContainers = append(pod.Spec.InitContainers, initContainer)
+		distroless := wh.configurator.IsInitContainerDistroless()
+		if redirectWithCNI || distroless {
+			// The iptables rules are passed through an annotation of the pod to the CNI plugin or to the distroless init
+			// container, which only restore them
+			if preserveSourceIP {
+				err = errors.Errorf("Source IP preservation is not supported when the traffic is redirected by a CNI plugin or a distroless init container")
+				log.Error().Err(err).Msgf("Error redirecting the traffic of pod: service-account=%s, namespace=%s", pod.Spec.ServiceAccountName, namespace)
+				return nil, err
+			}
+			iptablesRules, err := generateIptablesRestoreRules(generateIptablesCommands(proxyUID, wh.configurator.GetOutboundIPRangeExclusionList(), outboundPortExclusionList, inboundPortExclusionList, wh.configurator.GetFeatureFlags().EnableDNSProxy, preserveSourceIP))
+			if err != nil {
+				log.Error().Err(err).Msgf("Error generating iptables rules of pod: service-account=%s, namespace=%s", pod.Spec.ServiceAccountName, namespace)
+				return nil, err
+			}
+			if pod.Annotations == nil {
+				pod.Annotations = make(map[string]string)
+			}
+			pod.Annotations[iptablesRulesAnnotation] = iptablesRules
+		}
+
+		// Add the Init Container, unless the traffic is redirected by a CNI plugin. On OpenShift, only the privileged
+		// SecurityContextConstraints admit the root user and the capabilities the init container requires, so it
+		// runs privileged.
+		if !redirectWithCNI {
+			privileged := wh.configurator.IsPrivilegedInitContainer() || wh.isOpenShift()
+			var initContainer corev1.Container
+			if distroless {
+				if renderBootstrapConfig {
+					err = errors.Errorf("Distroless init container does not support the %s bootstrap delivery mode", BootstrapDeliveryVolume)
+					log.Error().Err(err).Msgf("Error injecting init container into pod: service-account=%s, namespace=%s", pod.Spec.ServiceAccountName, namespace)
+					return nil, err
+				}
+				pod.Spec.Volumes = append(pod.Spec.Volumes, getIptablesRulesVolume())
+				initContainer = getDistrolessInitContainerSpec(constants.InitContainerName, wh.configurator, privileged)
+			} else {
+				initContainer = getInitContainerSpec(constants.InitContainerName, wh.configurator, wh.configurator.GetOutboundIPRangeExclusionList(), outboundPortExclusionList, inboundPortExclusionList, privileged, wh.configurator.GetFeatureFlags().EnableDNSProxy, preserveSourceIP)
+			}
+			pod.Spec.InitContainers = append(pod.Spec.InitContainers, initContainer)
+			injectedContainers = append(injectedContainers, initContainer)
+			if wh.isOpenShift() {
+				requiredSCCs[openShiftPrivilegedSCC] = "the init container redirecting the traffic of the pod to the proxy sidecar"
+			}
+		}
 	}
 
 	// Add the Envoy sidecar
 	sidecar := getEnvoySidecarContainerSpec(pod, wh.configurator, originalHealthProbes, podOS)
+	if preserveSourceIP {
+		sidecar.SecurityContext = getSourceIPPreservingSecurityContext()
+		if wh.isOpenShift() {
+			requiredSCCs[openShiftPrivilegedSCC] = "the proxy sidecar preserving the source IP of the clients"
+		}
+	} else if enforcedPodSecurity == podSecurityRestricted {
+		// The proxy sidecar complies with the restricted Pod Security Standard enforced on the namespace
+		sidecar.SecurityContext = getRestrictedSecurityContext(proxyUID)
+	} else if proxyUID != constants.EnvoyUID {
+		// The proxy sidecar runs with the UID the iptables rules identify its traffic with
+		sidecar.SecurityContext = getOpenShiftSidecarSecurityContext(proxyUID)
+	}
+	if adminSocketPath != "" {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, getEnvoyAdminSocketVolume())
+		sidecar.VolumeMounts = append(sidecar.VolumeMounts, getEnvoyAdminSocketVolumeMount())
+	}
 	pod.Spec.Containers = append(pod.Spec.Containers, sidecar)
+	injectedContainers = append(injectedContainers, sidecar)
+
+	// Deny the injection before making any out-of-band change if the pod would be rejected by Pod Security Admission
+	if err := verifyPodSecurity(pod, namespace, injectedContainers, enforcedPodSecurity, warnedPodSecurity); err != nil {
+		log.Error().Err(err).Msgf("Error verifying pod security of pod: service-account=%s, namespace=%s", pod.Spec.ServiceAccountName, namespace)
+		return nil, err
+	}
+	for scc, requiredBy := range requiredSCCs {
+		if err := wh.verifySCCUse(pod, namespace, scc, requiredBy); err != nil {
+			log.Error().Err(err).Msgf("Error verifying SecurityContextConstraints of pod: service-account=%s, namespace=%s", pod.Spec.ServiceAccountName, namespace)
+			return nil, err
+		}
+	}
+
+	if !renderBootstrapConfig {
+		if err := wh.createBootstrapSecret(pod, req, proxyUUID, envoyBootstrapConfigName, originalHealthProbes, adminSocketPath); err != nil {
+			return nil, err
+		}
+	}
 
 	enableMetrics, err := wh.isMetricsEnabled(namespace)
 	if err != nil {
@@ -92,6 +208,20 @@ func (wh *mutatingWebhook) createPatch(pod *corev1.Pod, req *admissionv1.Admissi
 		pod.Annotations[constants.PrometheusScrapeAnnotation] = strconv.FormatBool(true)
 		pod.Annotations[constants.PrometheusPortAnnotation] = strconv.Itoa(constants.EnvoyPrometheusInboundListenerPort)
 		pod.Annotations[constants.PrometheusPathAnnotation] = constants.PrometheusScrapePath
+		if wh.configurator.GetPrometheusScrapingConfig().EnableMTLS {
+			pod.Annotations[constants.PrometheusSchemeAnnotation] = "https"
+		}
+	}
+
+	// The control plane proxies inbound traffic from the original source IP of the clients to the pods annotated so,
+	// whose proxy sidecar and init container are set up accordingly
+	if preserveSourceIP {
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Annotations[constants.SourceIPPreservationAnnotation] = strconv.FormatBool(true)
+	} else {
+		delete(pod.Annotations, constants.SourceIPPreservationAnnotation)
 	}
 
 	// This will append a label to the pod, which points to the unique Envoy ID used in the
@@ -105,6 +235,42 @@ func (wh *mutatingWebhook) createPatch(pod *corev1.Pod, req *admissionv1.Admissi
 	return json.Marshal(makePatches(req, pod))
 }
 
+// createBootstrapSecret issues the certificate the proxy sidecar of the given pod connects to the control plane with,
+// and creates the secret with the given name holding its bootstrap config
+func (wh *mutatingWebhook) createBootstrapSecret(pod *corev1.Pod, req *admissionv1.AdmissionRequest, proxyUUID uuid.UUID, envoyBootstrapConfigName string,
+	originalHealthProbes healthProbes, adminSocketPath string) error {
+	namespace := req.Namespace
+
+	// Issue a certificate for the proxy sidecar - used for Envoy to connect to XDS (not Envoy-to-Envoy connections)
+	cn := envoy.NewXDSCertCommonName(proxyUUID, envoy.KindSidecar, pod.Spec.ServiceAccountName, namespace)
+	log.Debug().Msgf("Patching POD spec: service-account=%s, namespace=%s with certificate CN=%s", pod.Spec.ServiceAccountName, namespace, cn)
+	startTime := time.Now()
+	bootstrapCertificate, err := wh.certManager.IssueCertificate(cn, constants.XDSCertificateValidityPeriod)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error issuing bootstrap certificate for Envoy with CN=%s", cn)
+		wh.warnCertificateIssuanceFailed(pod, namespace, err)
+		return err
+	}
+	elapsed := time.Since(startTime)
+
+	metricsstore.DefaultMetricsStore.CertIssuedCount.Inc()
+	metricsstore.DefaultMetricsStore.CertIssuedTime.
+		WithLabelValues().Observe(elapsed.Seconds())
+
+	// The webhook has a side effect (making out-of-band changes) of creating k8s secret
+	// corresponding to the Envoy bootstrap config. Such a side effect needs to be skipped
+	// when the request is a DryRun.
+	// Ref: https://kubernetes.io/docs/reference/access-authn-authz/extensible-admission-controllers/#side-effects
+	if req.DryRun != nil && *req.DryRun {
+		log.Debug().Msgf("Skipping envoy bootstrap config creation for dry-run request: service-account=%s, namespace=%s", pod.Spec.ServiceAccountName, namespace)
+	} else if _, err = wh.createEnvoyBootstrapConfig(envoyBootstrapConfigName, namespace, wh.osmNamespace, bootstrapCertificate, originalHealthProbes, adminSocketPath, pod.Annotations); err != nil {
+		log.Error().Err(err).Msgf("Failed to create Envoy bootstrap config for pod: service-account=%s, namespace=%s, certificate CN=%s", pod.Spec.ServiceAccountName, namespace, cn)
+		return err
+	}
+
+	return nil
+}
+
 func makePatches(req *admissionv1.AdmissionRequest, pod *corev1.Pod) []jsonpatch.JsonPatchOperation {
 	original := req.Object.Raw
 	current, err := json.Marshal(pod)
@@ -11,11 +11,14 @@ import (
 	"github.com/google/uuid"
 	tassert "github.com/stretchr/testify/assert"
 	admissionv1 "k8s.io/api/admission/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
 	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/constants"
@@ -34,10 +37,20 @@ func TestCreatePatch(t *testing.T) {
 	)
 
 	testCases := []struct {
-		name            string
-		os              string
-		namespace       *corev1.Namespace
-		expectedPatches []string
+		name                    string
+		os                      string
+		namespace               *corev1.Namespace
+		adminInterface          configv1alpha1.AdminInterfaceSpec
+		scraping                configv1alpha1.PrometheusScrapingSpec
+		bootstrapDelivery       string
+		distroless              bool
+		platform                string
+		redirection             string
+		sccAllowed              bool
+		podSecurityExemptions   PodSecurityExemptions
+		expectedPatches         []string
+		expectedNoInitContainer bool
+		expectedErr             bool
 	}{
 		{
 			name: "creates a patch for a unix worker",
@@ -109,6 +122,295 @@ func TestCreatePatch(t *testing.T) {
 				`"command":["envoy"]`,
 			},
 		},
+		{
+			name: "metrics enabled over mTLS",
+			os:   constants.OSLinux,
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        namespace,
+					Annotations: map[string]string{constants.MetricsAnnotation: "enabled"},
+				},
+			},
+			scraping: configv1alpha1.PrometheusScrapingSpec{
+				EnableMTLS: true,
+			},
+			expectedPatches: []string{
+				// Add metrics Annotations
+				`"path":"/metadata/annotations"`,
+				`"value":{"prometheus.io/path":"/stats/prometheus","prometheus.io/port":"15010","prometheus.io/scheme":"https","prometheus.io/scrape":"true"}`,
+			},
+		},
+		{
+			name: "source IP preservation enabled",
+			os:   constants.OSLinux,
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        namespace,
+					Annotations: map[string]string{constants.SourceIPPreservationAnnotation: "enabled"},
+				},
+			},
+			expectedPatches: []string{
+				// Add source IP preservation Annotation
+				`"path":"/metadata/annotations"`,
+				`"value":{"openservicemesh.io/preserve-source-ip":"true"}`,
+				// Add Init Container routing the responses back to Envoy
+				`"path":"/spec/initContainers"`,
+				`ip rule add fwmark 1500 lookup 133`,
+				// Add Envoy Container running as root with the NET_ADMIN capability
+				`"path":"/spec/containers"`,
+				`"securityContext":{"capabilities":{"add":["NET_ADMIN"]},"runAsGroup":1500,"runAsNonRoot":false,"runAsUser":0}`,
+			},
+		},
+		{
+			name: "binds the admin interface to a unix domain socket",
+			os:   constants.OSLinux,
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: namespace,
+				},
+			},
+			adminInterface: configv1alpha1.AdminInterfaceSpec{
+				BindMode: "uds",
+			},
+			expectedPatches: []string{
+				// Add Volumes
+				`"path":"/spec/volumes"`,
+				fmt.Sprintf(`"value":[{"name":"envoy-bootstrap-config-volume","secret":{"secretName":"envoy-bootstrap-config-%v"}},{"emptyDir":{"medium":"Memory"},"name":"envoy-admin-socket-volume"}]}`, proxyUUID),
+				// Add Envoy Container
+				`"path":"/spec/containers"`,
+				`{"mountPath":"/var/run/osm/envoy-admin","name":"envoy-admin-socket-volume"}`,
+			},
+		},
+		{
+			name: "delivers the bootstrap config in a volume rendered by an init container",
+			os:   constants.OSLinux,
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: namespace,
+				},
+			},
+			bootstrapDelivery: BootstrapDeliveryVolume,
+			expectedPatches: []string{
+				// Add Volumes
+				`"path":"/spec/volumes"`,
+				`{"emptyDir":{"medium":"Memory"},"name":"envoy-bootstrap-config-volume"}`,
+				`"serviceAccountToken":{"audience":"osm-injector","expirationSeconds":600,"path":"token"}`,
+				// Add Init Containers
+				`"path":"/spec/initContainers"`,
+				`"name":"osm-bootstrap"`,
+				`https://osm-injector.osm-system.svc:9090/bootstrap`,
+				// Add Envoy Container
+				`"path":"/spec/containers"`,
+				`{"mountPath":"/etc/envoy","name":"envoy-bootstrap-config-volume"}`,
+			},
+		},
+		{
+			name: "restores the iptables rules with a distroless init container",
+			os:   constants.OSLinux,
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: namespace,
+				},
+			},
+			distroless: true,
+			expectedPatches: []string{
+				// Add iptables rules Annotation
+				`"path":"/metadata/annotations"`,
+				`"openservicemesh.io/iptables-rules":"*nat\n:PROXY_INBOUND - [0:0]\n`,
+				// Add Volumes
+				`"path":"/spec/volumes"`,
+				`"fieldPath":"metadata.annotations['openservicemesh.io/iptables-rules']"`,
+				// Add Init Container
+				`"path":"/spec/initContainers"`,
+				`"command":["iptables-restore","--noflush","/etc/osm-init/rules"]`,
+			},
+		},
+		{
+			name: "distroless init container with source IP preservation",
+			os:   constants.OSLinux,
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        namespace,
+					Annotations: map[string]string{constants.SourceIPPreservationAnnotation: "enabled"},
+				},
+			},
+			distroless:  true,
+			expectedErr: true,
+		},
+		{
+			name: "injects into a namespace enforcing the baseline Pod Security Standard",
+			os:   constants.OSLinux,
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   namespace,
+					Labels: map[string]string{podSecurityEnforceLabel: podSecurityBaseline},
+				},
+			},
+			expectedNoInitContainer: true,
+			expectedPatches: []string{
+				// Add iptables rules Annotation for the CNI plugin
+				`"path":"/metadata/annotations"`,
+				`-A PROXY_OUTPUT -m owner --uid-owner 1500 -j RETURN`,
+				// Add Envoy Container
+				`"path":"/spec/containers"`,
+				`"securityContext":{"runAsUser":1500}`,
+			},
+		},
+		{
+			name: "injects into a namespace enforcing the restricted Pod Security Standard",
+			os:   constants.OSLinux,
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   namespace,
+					Labels: map[string]string{podSecurityEnforceLabel: podSecurityRestricted},
+				},
+			},
+			expectedNoInitContainer: true,
+			expectedPatches: []string{
+				// Add iptables rules Annotation for the CNI plugin
+				`"path":"/metadata/annotations"`,
+				`-A PROXY_OUTPUT -m owner --uid-owner 1500 -j RETURN`,
+				// Add Envoy Container complying with the restricted Pod Security Standard
+				`"path":"/spec/containers"`,
+				`"securityContext":{"allowPrivilegeEscalation":false,"capabilities":{"drop":["ALL"]},"runAsNonRoot":true,"runAsUser":1500,"seccompProfile":{"type":"RuntimeDefault"}}`,
+			},
+		},
+		{
+			name: "renders the bootstrap config in a namespace enforcing the restricted Pod Security Standard",
+			os:   constants.OSLinux,
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   namespace,
+					Labels: map[string]string{podSecurityEnforceLabel: podSecurityRestricted},
+				},
+			},
+			bootstrapDelivery:       BootstrapDeliveryVolume,
+			expectedNoInitContainer: true,
+			expectedPatches: []string{
+				// Add bootstrap Init Container complying with the restricted Pod Security Standard
+				`"path":"/spec/initContainers"`,
+				`"name":"osm-bootstrap"`,
+				`"securityContext":{"allowPrivilegeEscalation":false,"capabilities":{"drop":["ALL"]},"runAsNonRoot":true,"runAsUser":1500,"seccompProfile":{"type":"RuntimeDefault"}}`,
+			},
+		},
+		{
+			name: "injects the init container into an exempted namespace enforcing the restricted Pod Security Standard",
+			os:   constants.OSLinux,
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   namespace,
+					Labels: map[string]string{podSecurityEnforceLabel: podSecurityRestricted},
+				},
+			},
+			podSecurityExemptions: PodSecurityExemptions{Namespaces: []string{namespace}},
+			expectedPatches: []string{
+				// Add Init Container
+				`"path":"/spec/initContainers"`,
+				`"command":["/bin/sh"]`,
+			},
+		},
+		{
+			name: "source IP preservation in a namespace enforcing the baseline Pod Security Standard",
+			os:   constants.OSLinux,
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        namespace,
+					Labels:      map[string]string{podSecurityEnforceLabel: podSecurityBaseline},
+					Annotations: map[string]string{constants.SourceIPPreservationAnnotation: "enabled"},
+				},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "injects into a namespace warning about the restricted Pod Security Standard",
+			os:   constants.OSLinux,
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   namespace,
+					Labels: map[string]string{podSecurityWarnLabel: podSecurityRestricted},
+				},
+			},
+			expectedPatches: []string{
+				// Add Init Container
+				`"path":"/spec/initContainers"`,
+				`"command":["/bin/sh"]`,
+			},
+		},
+		{
+			name: "runs the init container privileged on OpenShift",
+			os:   constants.OSLinux,
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: namespace,
+				},
+			},
+			platform:   PlatformOpenShift,
+			sccAllowed: true,
+			expectedPatches: []string{
+				// Add privileged Init Container
+				`"path":"/spec/initContainers"`,
+				`"privileged":true`,
+			},
+		},
+		{
+			name: "denies the injection on OpenShift if the privileged SCC can't be used",
+			os:   constants.OSLinux,
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: namespace,
+				},
+			},
+			platform:    PlatformOpenShift,
+			sccAllowed:  false,
+			expectedErr: true,
+		},
+		{
+			name: "leaves the traffic redirection to a CNI plugin",
+			os:   constants.OSLinux,
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: namespace,
+				},
+			},
+			redirection: TrafficRedirectionCNI,
+			expectedPatches: []string{
+				// Add iptables rules Annotation
+				`"path":"/metadata/annotations"`,
+				`-A PROXY_OUTPUT -m owner --uid-owner 1500 -j RETURN`,
+			},
+		},
+		{
+			name: "runs the proxy sidecar with a UID of the namespace on OpenShift with a CNI plugin",
+			os:   constants.OSLinux,
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        namespace,
+					Annotations: map[string]string{openShiftUIDRangeAnnotation: "1000680000/10000"},
+				},
+			},
+			platform:    PlatformOpenShift,
+			redirection: TrafficRedirectionCNI,
+			expectedPatches: []string{
+				// Add iptables rules Annotation identifying the traffic of the proxy sidecar with its UID
+				`"path":"/metadata/annotations"`,
+				`-A PROXY_OUTPUT -m owner --uid-owner 1000680000 -j RETURN`,
+				// Add Envoy Container running with the UID
+				`"path":"/spec/containers"`,
+				`"securityContext":{"allowPrivilegeEscalation":false,"capabilities":{"drop":["ALL"]},"runAsNonRoot":true,"runAsUser":1000680000}`,
+			},
+		},
+		{
+			name: "OpenShift namespace without UID range with a CNI plugin",
+			os:   constants.OSLinux,
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: namespace,
+				},
+			},
+			platform:    PlatformOpenShift,
+			redirection: TrafficRedirectionCNI,
+			expectedErr: true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -117,14 +419,31 @@ func TestCreatePatch(t *testing.T) {
 			mockCtrl := gomock.NewController(t)
 			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
 			mockNsController := k8s.NewMockController(mockCtrl)
-			mockNsController.EXPECT().GetNamespace(namespace).Return(tc.namespace)
+			mockNsController.EXPECT().GetNamespace(namespace).Return(tc.namespace).AnyTimes()
 			_, err := client.CoreV1().Namespaces().Create(context.TODO(), tc.namespace, metav1.CreateOptions{})
 			assert.NoError(err)
+			client.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				return true, &authorizationv1.SubjectAccessReview{Status: authorizationv1.SubjectAccessReviewStatus{Allowed: tc.sccAllowed}}, nil
+			})
+
+			mockConfigurator.EXPECT().GetCertKeyBitSize().Return(2048).AnyTimes()
+			certManager := tresor.NewFakeCertManager(mockConfigurator)
+			webhookCert, err := certManager.IssueCertificate("osm-injector.osm-system.svc", constants.XDSCertificateValidityPeriod)
+			assert.NoError(err)
 
 			wh := &mutatingWebhook{
+				config: Config{
+					ListenPort:            constants.InjectorWebhookPort,
+					BootstrapDelivery:     tc.bootstrapDelivery,
+					Platform:              tc.platform,
+					TrafficRedirection:    tc.redirection,
+					PodSecurityExemptions: tc.podSecurityExemptions,
+				},
 				kubeClient:          client,
 				kubeController:      mockNsController,
-				certManager:         tresor.NewFakeCertManager(mockConfigurator),
+				certManager:         certManager,
+				osmNamespace:        "osm-system",
+				cert:                webhookCert,
 				configurator:        mockConfigurator,
 				nonInjectNamespaces: mapset.NewSet(),
 			}
@@ -133,13 +452,20 @@ func TestCreatePatch(t *testing.T) {
 			mockConfigurator.EXPECT().GetEnvoyImage().Return("").AnyTimes()
 
 			mockConfigurator.EXPECT().GetEnvoyLogLevel().Return("").Times(1)
-			mockConfigurator.EXPECT().GetInitContainerImage().Return("").Times(1)
+			mockConfigurator.EXPECT().GetEnvoyDrainStrategy().Return("").Times(1)
+			mockConfigurator.EXPECT().GetPerformanceSettings().Return(configurator.PerformanceSettings{}).Times(1)
+			mockConfigurator.EXPECT().GetInitContainerImage().Return("").AnyTimes()
 			mockConfigurator.EXPECT().IsPrivilegedInitContainer().Return(false).Times(1)
+			mockConfigurator.EXPECT().IsInitContainerDistroless().Return(tc.distroless).AnyTimes()
+			mockConfigurator.EXPECT().GetFeatureFlags().Return(configv1alpha1.FeatureFlags{}).AnyTimes()
 			mockConfigurator.EXPECT().GetOutboundIPRangeExclusionList().Return(nil).Times(1)
 			mockConfigurator.EXPECT().GetOutboundPortExclusionList().Return(nil).Times(1)
 			mockConfigurator.EXPECT().GetInboundPortExclusionList().Return(nil).Times(1)
 			mockConfigurator.EXPECT().GetProxyResources().Return(corev1.ResourceRequirements{}).Times(1)
-			mockConfigurator.EXPECT().GetCertKeyBitSize().Return(2048).AnyTimes()
+			mockConfigurator.EXPECT().GetAdminInterfaceConfig().Return(tc.adminInterface).AnyTimes()
+			mockConfigurator.EXPECT().GetOverloadManagerConfig().Return(configv1alpha1.OverloadManagerSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetStatsConfig().Return(configv1alpha1.StatsSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetPrometheusScrapingConfig().Return(tc.scraping).AnyTimes()
 
 			pod := tests.NewOsSpecificPodFixture(namespace, podName, tests.BookstoreServiceAccountName, nil, tc.os)
 
@@ -148,6 +474,15 @@ func TestCreatePatch(t *testing.T) {
 
 			req := &admissionv1.AdmissionRequest{Namespace: namespace, Object: runtime.RawExtension{Raw: raw}}
 			rawPatches, err := wh.createPatch(&pod, req, proxyUUID)
+			if tc.expectedErr {
+				assert.Error(err)
+
+				// No Secret is created for the pods whose injection is denied
+				secrets, err := client.CoreV1().Secrets(namespace).List(context.TODO(), metav1.ListOptions{})
+				assert.NoError(err)
+				assert.Empty(secrets.Items)
+				return
+			}
 
 			assert.NoError(err)
 
@@ -156,6 +491,21 @@ func TestCreatePatch(t *testing.T) {
 			for _, expectedPatch := range tc.expectedPatches {
 				assert.Contains(patches, expectedPatch)
 			}
+
+			// No init container is injected into the pods whose traffic is redirected by a CNI plugin
+			if tc.redirection == TrafficRedirectionCNI || tc.expectedNoInitContainer {
+				assert.NotContains(patches, fmt.Sprintf(`"name":"%s"`, constants.InitContainerName))
+			}
+
+			// No Secret is created for the pods whose bootstrap config is delivered in a volume
+			secrets, err := client.CoreV1().Secrets(namespace).List(context.TODO(), metav1.ListOptions{})
+			assert.NoError(err)
+			if tc.bootstrapDelivery == BootstrapDeliveryVolume {
+				assert.Empty(secrets.Items)
+				assert.NotContains(patches, `"secretName"`)
+			} else {
+				assert.Len(secrets.Items, 1)
+			}
 		})
 	}
 }
@@ -0,0 +1,224 @@
+package injector
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// podSecurityEnforceLabel is the namespace label setting the Pod Security Admission level pods must comply with
+	podSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+
+	// podSecurityWarnLabel is the namespace label setting the Pod Security Admission level pods are warned about
+	podSecurityWarnLabel = "pod-security.kubernetes.io/warn"
+
+	// podSecurityBaseline is the Pod Security Standard preventing known privilege escalations
+	podSecurityBaseline = "baseline"
+
+	// podSecurityRestricted is the Pod Security Standard enforcing pod hardening best practices, on top of the
+	// baseline standard
+	podSecurityRestricted = "restricted"
+)
+
+// baselineCapabilities are the capabilities containers may add under the baseline Pod Security Standard
+var baselineCapabilities = map[corev1.Capability]bool{
+	"AUDIT_WRITE":      true,
+	"CHOWN":            true,
+	"DAC_OVERRIDE":     true,
+	"FOWNER":           true,
+	"FSETID":           true,
+	"KILL":             true,
+	"MKNOD":            true,
+	"NET_BIND_SERVICE": true,
+	"SETFCAP":          true,
+	"SETGID":           true,
+	"SETPCAP":          true,
+	"SETUID":           true,
+	"SYS_CHROOT":       true,
+}
+
+// PodSecurityExemptions are the exemptions from Pod Security Admission configured on the API server, which the Pod
+// Security Standards of the namespaces don't apply to
+type PodSecurityExemptions struct {
+	// Usernames are the users whose pod creations are exempted
+	Usernames []string
+
+	// RuntimeClasses are the runtime classes of the exempted pods
+	RuntimeClasses []string
+
+	// Namespaces are the exempted namespaces
+	Namespaces []string
+}
+
+// exempts returns whether the pod of the given admission request is exempted from Pod Security Admission, either by
+// the user creating it, its runtime class or its namespace
+func (e PodSecurityExemptions) exempts(pod *corev1.Pod, req *admissionv1.AdmissionRequest) bool {
+	if containsString(e.Usernames, req.UserInfo.Username) || containsString(e.Namespaces, req.Namespace) {
+		return true
+	}
+	return pod.Spec.RuntimeClassName != nil && containsString(e.RuntimeClasses, *pod.Spec.RuntimeClassName)
+}
+
+// getPodSecurityLevels returns the Pod Security Standard levels enforced and warned about by Pod Security Admission on
+// the namespace of the pod of the given admission request, either baseline or restricted. The levels are empty if
+// the namespace doesn't restrict pods, or if the pod is exempted from Pod Security Admission.
+func (wh *mutatingWebhook) getPodSecurityLevels(pod *corev1.Pod, req *admissionv1.AdmissionRequest) (enforced string, warned string, err error) {
+	if wh.config.PodSecurityExemptions.exempts(pod, req) {
+		return "", "", nil
+	}
+
+	ns := wh.kubeController.GetNamespace(req.Namespace)
+	if ns == nil {
+		log.Error().Err(errNamespaceNotFound).Msgf("Error retrieving namespace %s", req.Namespace)
+		return "", "", errNamespaceNotFound
+	}
+
+	return getPodSecurityLevel(ns.Labels[podSecurityEnforceLabel]), getPodSecurityLevel(ns.Labels[podSecurityWarnLabel]), nil
+}
+
+// getPodSecurityLevel returns the given Pod Security Standard level if it restricts pods, either baseline or
+// restricted, and an empty level otherwise
+func getPodSecurityLevel(level string) string {
+	level = strings.ToLower(level)
+	if level != podSecurityBaseline && level != podSecurityRestricted {
+		return ""
+	}
+	return level
+}
+
+// verifyPodSecurity verifies that the given containers injected into the given pod comply with the given Pod Security
+// Standard level enforced on its namespace, so that the injection is denied with the violations rather than the
+// creation of the pod being rejected after it. The violations of the given level pods are warned about on the
+// namespace are logged.
+func verifyPodSecurity(pod *corev1.Pod, namespace string, injectedContainers []corev1.Container, enforced string, warned string) error {
+	if violations := getPodSecurityViolations(pod, injectedContainers, enforced); len(violations) > 0 {
+		return errors.Errorf("Injecting the proxy sidecar would violate the %q Pod Security Standard enforced on namespace %s: %s",
+			enforced, namespace, strings.Join(violations, "; "))
+	}
+
+	if violations := getPodSecurityViolations(pod, injectedContainers, warned); len(violations) > 0 {
+		log.Warn().Msgf("Injecting the proxy sidecar into pod with service account %s violates the %q Pod Security Standard of namespace %s: %s",
+			pod.Spec.ServiceAccountName, warned, namespace, strings.Join(violations, "; "))
+	}
+
+	return nil
+}
+
+// getRestrictedSecurityContext returns the security context of the injected containers complying with the restricted
+// Pod Security Standard, running with the given non-root UID without any capability
+func getRestrictedSecurityContext(uid int64) *corev1.SecurityContext {
+	securityContext := getOpenShiftSidecarSecurityContext(uid)
+	securityContext.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+	return securityContext
+}
+
+// getPodSecurityViolations returns the violations of the given Pod Security Standard level by the given containers of
+// the given pod, taking the security context of the pod into account. Unknown levels, including privileged, allow
+// any container.
+func getPodSecurityViolations(pod *corev1.Pod, containers []corev1.Container, level string) []string {
+	level = getPodSecurityLevel(level)
+	if level == "" {
+		return nil
+	}
+
+	podSecurityContext := pod.Spec.SecurityContext
+	if podSecurityContext == nil {
+		podSecurityContext = &corev1.PodSecurityContext{}
+	}
+
+	var violations []string
+	for _, container := range containers {
+		securityContext := container.SecurityContext
+		if securityContext == nil {
+			securityContext = &corev1.SecurityContext{}
+		}
+		var capabilities corev1.Capabilities
+		if securityContext.Capabilities != nil {
+			capabilities = *securityContext.Capabilities
+		}
+		seccompProfile := securityContext.SeccompProfile
+		if seccompProfile == nil {
+			seccompProfile = podSecurityContext.SeccompProfile
+		}
+
+		var containerViolations []string
+		if securityContext.Privileged != nil && *securityContext.Privileged {
+			containerViolations = append(containerViolations, "privileged")
+		}
+		if seccompProfile != nil && seccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+			containerViolations = append(containerViolations, "unconfined seccomp profile")
+		}
+
+		var forbiddenCapabilities []string
+		for _, capability := range capabilities.Add {
+			allowed := baselineCapabilities[capability]
+			if level == podSecurityRestricted {
+				allowed = capability == "NET_BIND_SERVICE"
+			}
+			if !allowed {
+				forbiddenCapabilities = append(forbiddenCapabilities, string(capability))
+			}
+		}
+		if len(forbiddenCapabilities) > 0 {
+			containerViolations = append(containerViolations, fmt.Sprintf("adds capabilities %s", strings.Join(forbiddenCapabilities, ", ")))
+		}
+
+		if level == podSecurityRestricted {
+			if securityContext.AllowPrivilegeEscalation == nil || *securityContext.AllowPrivilegeEscalation {
+				containerViolations = append(containerViolations, "allowPrivilegeEscalation != false")
+			}
+			if !dropsAllCapabilities(capabilities) {
+				containerViolations = append(containerViolations, `does not drop capability "ALL"`)
+			}
+
+			runAsNonRoot := securityContext.RunAsNonRoot
+			if runAsNonRoot == nil {
+				runAsNonRoot = podSecurityContext.RunAsNonRoot
+			}
+			if runAsNonRoot == nil || !*runAsNonRoot {
+				containerViolations = append(containerViolations, "runAsNonRoot != true")
+			}
+			runAsUser := securityContext.RunAsUser
+			if runAsUser == nil {
+				runAsUser = podSecurityContext.RunAsUser
+			}
+			if runAsUser != nil && *runAsUser == 0 {
+				containerViolations = append(containerViolations, "runAsUser=0")
+			}
+
+			if seccompProfile == nil || (seccompProfile.Type != corev1.SeccompProfileTypeRuntimeDefault && seccompProfile.Type != corev1.SeccompProfileTypeLocalhost) {
+				containerViolations = append(containerViolations, "seccomp profile is not RuntimeDefault or Localhost")
+			}
+		}
+
+		if len(containerViolations) > 0 {
+			violations = append(violations, fmt.Sprintf("container %s: %s", container.Name, strings.Join(containerViolations, ", ")))
+		}
+	}
+
+	return violations
+}
+
+// dropsAllCapabilities returns whether the given capabilities drop all the capabilities
+func dropsAllCapabilities(capabilities corev1.Capabilities) bool {
+	for _, capability := range capabilities.Drop {
+		if capability == "ALL" {
+			return true
+		}
+	}
+	return false
+}
+
+// containsString returns whether the given strings contain the given string
+func containsString(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package injector
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestGetPodSecurityViolations(t *testing.T) {
+	nonRoot := true
+	uid := int64(1500)
+	noEscalation := false
+	hardenedContainer := corev1.Container{
+		Name: "hardened",
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: &noEscalation,
+			RunAsNonRoot:             &nonRoot,
+			RunAsUser:                &uid,
+			Capabilities: &corev1.Capabilities{
+				Add:  []corev1.Capability{"NET_BIND_SERVICE"},
+				Drop: []corev1.Capability{"ALL"},
+			},
+			SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+		},
+	}
+
+	testCases := []struct {
+		name               string
+		podSecurityContext *corev1.PodSecurityContext
+		containers         []corev1.Container
+		level              string
+		expectedViolations []string
+	}{
+		{
+			name:       "privileged level",
+			containers: []corev1.Container{{Name: "osm-init", SecurityContext: getInitContainerSecurityContext(true)}},
+			level:      "privileged",
+		},
+		{
+			name:       "init container under the baseline level",
+			containers: []corev1.Container{{Name: "osm-init", SecurityContext: getInitContainerSecurityContext(false)}},
+			level:      podSecurityBaseline,
+			expectedViolations: []string{
+				"container osm-init: adds capabilities NET_ADMIN, NET_RAW",
+			},
+		},
+		{
+			name:       "privileged init container under the baseline level",
+			containers: []corev1.Container{{Name: "osm-init", SecurityContext: getInitContainerSecurityContext(true)}},
+			level:      podSecurityBaseline,
+			expectedViolations: []string{
+				"container osm-init: privileged, adds capabilities NET_ADMIN, NET_RAW",
+			},
+		},
+		{
+			name:       "init container under the restricted level",
+			containers: []corev1.Container{{Name: "osm-init", SecurityContext: getInitContainerSecurityContext(false)}},
+			level:      podSecurityRestricted,
+			expectedViolations: []string{
+				"container osm-init: adds capabilities NET_ADMIN, NET_RAW, runAsNonRoot != true, runAsUser=0",
+			},
+		},
+		{
+			name:       "hardened container under the restricted level",
+			containers: []corev1.Container{hardenedContainer},
+			level:      podSecurityRestricted,
+		},
+		{
+			name:       "restricted security context under the restricted level",
+			containers: []corev1.Container{{Name: "envoy", SecurityContext: getRestrictedSecurityContext(1500)}},
+			level:      podSecurityRestricted,
+		},
+		{
+			name:       "container without security context under the restricted level",
+			containers: []corev1.Container{{Name: "envoy"}},
+			level:      podSecurityRestricted,
+			expectedViolations: []string{
+				`container envoy: allowPrivilegeEscalation != false, does not drop capability "ALL", runAsNonRoot != true, seccomp profile is not RuntimeDefault or Localhost`,
+			},
+		},
+		{
+			name: "security context of the pod under the restricted level",
+			podSecurityContext: &corev1.PodSecurityContext{
+				RunAsNonRoot:   &nonRoot,
+				SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeLocalhost},
+			},
+			containers: []corev1.Container{{
+				Name: "envoy",
+				SecurityContext: &corev1.SecurityContext{
+					AllowPrivilegeEscalation: &noEscalation,
+					Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+				},
+			}},
+			level: podSecurityRestricted,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			pod := &corev1.Pod{Spec: corev1.PodSpec{SecurityContext: tc.podSecurityContext}}
+			assert.Equal(tc.expectedViolations, getPodSecurityViolations(pod, tc.containers, tc.level))
+		})
+	}
+}
+
+func TestPodSecurityExemptionsExempts(t *testing.T) {
+	kata := "kata"
+	runc := "runc"
+	exemptions := PodSecurityExemptions{
+		Usernames:      []string{"system:serviceaccount:kube-system:replicaset-controller"},
+		RuntimeClasses: []string{kata},
+		Namespaces:     []string{"exempt"},
+	}
+
+	testCases := []struct {
+		name             string
+		username         string
+		runtimeClassName *string
+		namespace        string
+		expected         bool
+	}{
+		{
+			name:      "pod without exemption",
+			username:  "system:serviceaccount:kube-system:job-controller",
+			namespace: "default",
+			expected:  false,
+		},
+		{
+			name:      "pod created by an exempted user",
+			username:  "system:serviceaccount:kube-system:replicaset-controller",
+			namespace: "default",
+			expected:  true,
+		},
+		{
+			name:             "pod with an exempted runtime class",
+			runtimeClassName: &kata,
+			namespace:        "default",
+			expected:         true,
+		},
+		{
+			name:             "pod with another runtime class",
+			runtimeClassName: &runc,
+			namespace:        "default",
+			expected:         false,
+		},
+		{
+			name:      "pod of an exempted namespace",
+			namespace: "exempt",
+			expected:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			pod := &corev1.Pod{Spec: corev1.PodSpec{RuntimeClassName: tc.runtimeClassName}}
+			req := &admissionv1.AdmissionRequest{Namespace: tc.namespace, UserInfo: authenticationv1.UserInfo{Username: tc.username}}
+			assert.Equal(tc.expected, exemptions.exempts(pod, req))
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package injector
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+func (wh *mutatingWebhook) isSourceIPPreservationEnabled(namespace string) (enabled bool, err error) {
+	ns := wh.kubeController.GetNamespace(namespace)
+	if ns == nil {
+		log.Error().Err(errNamespaceNotFound).Msgf("Error retrieving namespace %s", namespace)
+		return false, errNamespaceNotFound
+	}
+
+	preserveSourceIP, ok := ns.Annotations[constants.SourceIPPreservationAnnotation]
+	if !ok {
+		return false, nil
+	}
+
+	log.Trace().Msgf("Source IP preservation annotation: '%s:%s'", constants.SourceIPPreservationAnnotation, preserveSourceIP)
+	switch strings.ToLower(preserveSourceIP) {
+	case "enabled", "yes", "true":
+		enabled = true
+	case "disabled", "no", "false", "":
+		enabled = false
+	default:
+		err = errors.Errorf("Invalid value specified for annotation %q: %s", constants.SourceIPPreservationAnnotation, preserveSourceIP)
+	}
+	return
+}
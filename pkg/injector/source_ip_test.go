@@ -0,0 +1,51 @@
+package injector
+
+import (
+	"fmt"
+	"testing"
+
+	mapset "github.com/deckarep/golang-set"
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s"
+)
+
+func TestIsSourceIPPreservationEnabled(t *testing.T) {
+	testCases := []struct {
+		namespace      string
+		annotations    map[string]string
+		expectEnabled  bool
+		expectedErr    bool
+		namespaceFound bool
+	}{
+		{"ns-1", map[string]string{constants.SourceIPPreservationAnnotation: "enabled"}, true, false, true},
+		{"ns-2", map[string]string{constants.SourceIPPreservationAnnotation: "false"}, false, false, true},
+		{"ns-3", nil, false, false, true},
+		{"ns-4", map[string]string{constants.SourceIPPreservationAnnotation: "invalid"}, false, true, true},
+		{"ns-5", nil, false, true, false},
+	}
+
+	mockController := k8s.NewMockController(gomock.NewController(t))
+	wh := &mutatingWebhook{
+		kubeController:      mockController,
+		nonInjectNamespaces: mapset.NewSet(),
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Namespace %s", tc.namespace), func(t *testing.T) {
+			assert := tassert.New(t)
+
+			if tc.namespaceFound {
+				mockController.EXPECT().GetNamespace(tc.namespace).Return(newNamespace(tc.namespace, tc.annotations))
+			} else {
+				mockController.EXPECT().GetNamespace(tc.namespace).Return(nil)
+			}
+
+			enabled, err := wh.isSourceIPPreservationEnabled(tc.namespace)
+			assert.Equal(tc.expectEnabled, enabled)
+			assert.Equal(tc.expectedErr, err != nil)
+		})
+	}
+}
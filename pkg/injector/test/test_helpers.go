@@ -9,11 +9,11 @@ import (
 
 	"github.com/onsi/ginkgo"
 	"github.com/onsi/gomega"
-	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"gopkg.in/yaml.v2"
 
 	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/tests/golden"
 )
 
 // All the YAML files listed above are in this sub-directory
@@ -21,14 +21,6 @@ const directoryForExpectationsYAML = "../../tests/envoy_xds_expectations/"
 
 var log = logger.New("sidecar-injector")
 
-func getTempDir() string {
-	dir, err := ioutil.TempDir("", "osm_test_envoy")
-	if err != nil {
-		log.Fatal().Err(err).Msg("Error creating temp directory")
-	}
-	return dir
-}
-
 // LoadExpectedEnvoyYAML loads the expectation for a given test from the file system. This must run within ginkgo.It()
 func LoadExpectedEnvoyYAML(expectationFilePath string) string {
 	// The expectationFileName will contain the name of the function by convention
@@ -43,24 +35,7 @@ func LoadExpectedEnvoyYAML(expectationFilePath string) string {
 
 // MarshalXdsStructAndSaveToFile converts a an xDS struct into YAML and saves it to a file. This must run within ginkgo.It()
 func MarshalXdsStructAndSaveToFile(m protoreflect.ProtoMessage, filePath string) string {
-	marshalOptions := protojson.MarshalOptions{
-		UseProtoNames: true,
-	}
-	configJSON, err := marshalOptions.Marshal(m)
-	gomega.Expect(err).ToNot(gomega.HaveOccurred())
-
-	// Convert the JSON to an object.
-	var jsonObj interface{}
-	// We are using yaml.Unmarshal here (instead of json.Unmarshal) because the
-	// Go JSON library doesn't try to pick the right number type (int, float,
-	// etc.) when unmarshalling to interface{}, it just picks float64
-	// universally. go-yaml does go through the effort of picking the right
-	// number type, so we can preserve number type throughout this process.
-	err = yaml.Unmarshal([]byte(configJSON), &jsonObj)
-	gomega.Expect(err).ToNot(gomega.HaveOccurred())
-
-	// Marshal this object into YAML.
-	configYAML, err := yaml.Marshal(jsonObj)
+	configYAML, err := golden.ProtoToYAML(m)
 	gomega.Expect(err).ToNot(gomega.HaveOccurred())
 
 	log.Info().Msgf("Saving %s...", filePath)
@@ -86,57 +61,47 @@ func MarshalAndSaveToFile(someStruct interface{}, filePath string) string {
 }
 
 // ThisFunction runs the given function in a ginkgo.Context(), marshals the output and compares to an expectation loaded from file.
+// The expectation is updated with the output when the tests run with the -update flag.
 func ThisFunction(functionName string, fn func() interface{}) {
 	ginkgo.Context(fmt.Sprintf("ThisFunction %s", functionName), func() {
 		ginkgo.It("creates Envoy config", func() {
-			expectationFilePath := path.Join(directoryForExpectationsYAML, fmt.Sprintf("expected_output_%s.yaml", functionName))
-			actualFilePath := path.Join(getTempDir(), fmt.Sprintf("actual_output_%s.yaml", functionName))
-			log.Info().Msgf("Actual output of %s is going to be saved in %s", functionName, actualFilePath)
-			actual := fn()
-
-			expectedYAML := LoadExpectedEnvoyYAML(expectationFilePath)
-			actualYAML := MarshalAndSaveToFile(actual, actualFilePath)
-
-			Compare(functionName, actualFilePath, expectationFilePath, actualYAML, expectedYAML)
+			golden.AssertYAML(ginkgo.GinkgoT(), getExpectationFilePath(functionName), fn())
 		})
 	})
 }
 
 // ThisXdsClusterFunction runs the given function in a ginkgo.Context(), marshals the output and compares to an expectation loaded from file.
+// The expectation is updated with the output when the tests run with the -update flag.
 func ThisXdsClusterFunction(functionName string, fn func() protoreflect.ProtoMessage) {
 	ginkgo.Context(fmt.Sprintf("ThisFunction %s", functionName), func() {
 		ginkgo.It("creates Envoy config", func() {
-			expectationFilePath := path.Join(directoryForExpectationsYAML, fmt.Sprintf("expected_output_%s.yaml", functionName))
-			actualFilePath := path.Join(getTempDir(), fmt.Sprintf("actual_output_%s.yaml", functionName))
-			log.Info().Msgf("Actual output of %s is going to be saved in %s", functionName, actualFilePath)
-			actual := fn()
-
-			expectedYAML := LoadExpectedEnvoyYAML(expectationFilePath)
-			actualYAML := MarshalXdsStructAndSaveToFile(actual, actualFilePath)
-
-			Compare(functionName, actualFilePath, expectationFilePath, actualYAML, expectedYAML)
+			actualYAML, err := golden.ProtoToYAML(fn())
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			golden.Assert(ginkgo.GinkgoT(), getExpectationFilePath(functionName), actualYAML)
 		})
 	})
 }
 
 // ThisXdsListenerFunction runs the given function in a ginkgo.Context(), marshals the output and compares to an expectation loaded from file.
+// The expectation is updated with the output when the tests run with the -update flag.
 func ThisXdsListenerFunction(functionName string, fn func() (protoreflect.ProtoMessage, error)) {
 	ginkgo.Context(fmt.Sprintf("ThisFunction %s", functionName), func() {
 		ginkgo.It("creates Envoy config", func() {
-			expectationFilePath := path.Join(directoryForExpectationsYAML, fmt.Sprintf("expected_output_%s.yaml", functionName))
-			actualFilePath := path.Join(getTempDir(), fmt.Sprintf("actual_output_%s.yaml", functionName))
-			log.Info().Msgf("Actual output of %s is going to be saved in %s", functionName, actualFilePath)
 			actual, err := fn()
 			gomega.Expect(err).To(gomega.BeNil())
 
-			expectedYAML := LoadExpectedEnvoyYAML(expectationFilePath)
-			actualYAML := MarshalXdsStructAndSaveToFile(actual, actualFilePath)
-
-			Compare(functionName, actualFilePath, expectationFilePath, actualYAML, expectedYAML)
+			actualYAML, err := golden.ProtoToYAML(actual)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			golden.Assert(ginkgo.GinkgoT(), getExpectationFilePath(functionName), actualYAML)
 		})
 	})
 }
 
+// getExpectationFilePath returns the path of the file of the expected output of the given function
+func getExpectationFilePath(functionName string) string {
+	return path.Join(directoryForExpectationsYAML, fmt.Sprintf("expected_output_%s.yaml", functionName))
+}
+
 // Compare is a wrapper around gomega.Expect().To(Equal()) and compares actualYAML and expectedYAML; It also provides a verbose message when things don't match with a tip on how to fix things.
 func Compare(functionName, actualFilename, expectedFilename, actualYAML, expectedYAML string) {
 	gomega.Expect(actualYAML).To(gomega.Equal(expectedYAML),
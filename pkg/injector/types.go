@@ -9,11 +9,39 @@ import (
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
 	"github.com/openservicemesh/osm/pkg/logger"
 )
 
 const (
 	envoyBootstrapConfigVolume = "envoy-bootstrap-config-volume"
+
+	// BootstrapDeliverySecret delivers the Envoy bootstrap config of the sidecar of each pod in a Secret created by the
+	// webhook and mounted on the pod
+	BootstrapDeliverySecret = "secret"
+
+	// BootstrapDeliveryVolume delivers the Envoy bootstrap config of the sidecar of each pod in an emptyDir volume,
+	// rendered by an init container fetching it from the injector with a token bound to the pod. No Secret is created
+	// per pod. Windows pods, which aren't injected with init containers, fall back to BootstrapDeliverySecret.
+	BootstrapDeliveryVolume = "volume"
+
+	// PlatformAuto detects the platform of the cluster with the discovery client
+	PlatformAuto = "auto"
+
+	// PlatformKubernetes injects the sidecar into pods of a Kubernetes cluster
+	PlatformKubernetes = "kubernetes"
+
+	// PlatformOpenShift injects the sidecar into pods of an OpenShift cluster, adjusting the security context of the
+	// injected containers to the SecurityContextConstraints (SCC) admitting the pods
+	PlatformOpenShift = "openshift"
+
+	// TrafficRedirectionInitContainer redirects the traffic of each pod to its sidecar with iptables rules programmed
+	// by an init container requiring the NET_ADMIN and NET_RAW capabilities
+	TrafficRedirectionInitContainer = "init-container"
+
+	// TrafficRedirectionCNI leaves the redirection of the traffic of each pod to its sidecar to a CNI plugin, which
+	// programs the iptables rules stored in the iptables rules annotation of the pod. No init container is injected.
+	TrafficRedirectionCNI = "cni"
 )
 
 var log = logger.New("sidecar-injector")
@@ -36,11 +64,52 @@ type mutatingWebhook struct {
 type Config struct {
 	// ListenPort defines the port on which the sidecar injector listens
 	ListenPort int
+
+	// XDSHost is the host at which proxies reach the xDS server. The osm-controller service within the OSM
+	// namespace is used if unset, and must be set when osm-controller runs outside the cluster.
+	XDSHost string
+
+	// XDSPort is the port at which proxies reach the xDS server. The default xDS server port is used if unset.
+	XDSPort uint32
+
+	// WebhookURL is the base URL (https://host:port) at which the API server reaches the webhook when osm-injector
+	// runs outside the cluster. The osm-injector service within the OSM namespace is used if unset.
+	WebhookURL string
+
+	// BootstrapDelivery is how the Envoy bootstrap config is delivered to the sidecars of pods, either
+	// BootstrapDeliverySecret or BootstrapDeliveryVolume. BootstrapDeliverySecret is used if unset.
+	BootstrapDelivery string
+
+	// Platform is the platform of the cluster, either PlatformKubernetes or PlatformOpenShift, or PlatformAuto to
+	// detect it. PlatformAuto is used if unset.
+	Platform string
+
+	// TrafficRedirection is how the traffic of pods is redirected to their sidecar, either
+	// TrafficRedirectionInitContainer or TrafficRedirectionCNI. TrafficRedirectionInitContainer is used if unset.
+	// The traffic of the pods of the namespaces enforcing the baseline or restricted Pod Security Standard is always
+	// redirected by a CNI plugin, since the init container requires capabilities both standards forbid.
+	TrafficRedirection string
+
+	// PodSecurityExemptions are the exemptions from Pod Security Admission configured on the API server, whose pods
+	// are injected regardless of the Pod Security Standard of their namespace
+	PodSecurityExemptions PodSecurityExemptions
+
+	// BootstrapSecretGCDryRun logs the orphaned bootstrap Secrets found by the garbage collector instead of deleting
+	// them
+	BootstrapSecretGCDryRun bool
+
+	// EventRecorder records the Kubernetes events reporting why the sidecar isn't injected into pods. Events are not
+	// recorded if nil.
+	EventRecorder *events.ObjectEventRecorder
 }
 
 // Context needed to compose the Envoy bootstrap YAML.
 type envoyBootstrapConfigMeta struct {
 	EnvoyAdminPort uint32
+
+	// Path of the unix domain socket the Envoy admin interface binds to, empty to bind it to localhost
+	EnvoyAdminSocketPath string
+
 	XDSClusterName string
 	NodeID         string
 	RootCert       []byte
@@ -54,4 +123,7 @@ type envoyBootstrapConfigMeta struct {
 	// The bootstrap Envoy config will be affected by the liveness, readiness, startup probes set on
 	// the pod this Envoy is fronting.
 	OriginalHealthProbes healthProbes
+
+	// The annotations of the pod this Envoy is fronting, overriding parts of the bootstrap Envoy config
+	PodAnnotations map[string]string
 }
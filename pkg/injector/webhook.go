@@ -27,6 +27,7 @@ import (
 	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/errcode"
 	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/migration"
 	"github.com/openservicemesh/osm/pkg/webhook"
 )
 
@@ -51,6 +52,14 @@ const (
 
 	// inboundPortExclusionListAnnotation is the annotation used for inbound port exclusions
 	inboundPortExclusionListAnnotation = "openservicemesh.io/inbound-port-exclusion-list"
+
+	// statsInclusionRegexesAnnotation is the annotation used to override the comma-separated regular expressions
+	// matching the names of the only stats generated by the Envoy proxy of a pod
+	statsInclusionRegexesAnnotation = "openservicemesh.io/stats-inclusion-regexes"
+
+	// statsExclusionRegexesAnnotation is the annotation used to override the comma-separated regular expressions
+	// matching the names of the stats not generated by the Envoy proxy of a pod
+	statsExclusionRegexesAnnotation = "openservicemesh.io/stats-exclusion-regexes"
 )
 
 // NewMutatingWebhook starts a new web server handling requests from the injector MutatingWebhookConfiguration
@@ -59,7 +68,7 @@ func NewMutatingWebhook(config Config, kubeClient kubernetes.Interface, certMana
 	// This cert does not have to be related to the Envoy certs, but it does have to match
 	// the cert provisioned with the MutatingWebhookConfiguration
 	webhookHandlerCert, err := certManager.IssueCertificate(
-		certificate.CommonName(fmt.Sprintf("%s.%s.svc", injectorServiceName, osmNamespace)),
+		webhook.GetCommonName(config.WebhookURL, injectorServiceName, osmNamespace),
 		constants.XDSCertificateValidityPeriod)
 	if err != nil {
 		return errors.Errorf("Error issuing certificate for the mutating webhook: %+v", err)
@@ -67,9 +76,25 @@ func NewMutatingWebhook(config Config, kubeClient kubernetes.Interface, certMana
 
 	// The following function ensures to atomically create or get the certificate from Kubernetes
 	// secret API store. Multiple instances should end up with the same webhookHandlerCert after this function executed.
-	webhookHandlerCert, err = providers.GetCertificateFromSecret(osmNamespace, constants.WebhookCertificateSecretName, webhookHandlerCert, kubeClient)
-	if err != nil {
-		return errors.Errorf("Error fetching webhook certificate from k8s secret: %s", err)
+	// A single instance running outside the cluster keeps its own certificate, since the one stored in the secret is
+	// issued for the injector service.
+	if config.WebhookURL == "" {
+		webhookHandlerCert, err = providers.GetCertificateFromSecret(osmNamespace, constants.WebhookCertificateSecretName, webhookHandlerCert, kubeClient)
+		if err != nil {
+			return errors.Errorf("Error fetching webhook certificate from k8s secret: %s", err)
+		}
+	}
+
+	if config.Platform == "" || config.Platform == PlatformAuto {
+		openShift, err := k8s.IsOpenShift(kubeClient)
+		if err != nil {
+			return errors.Errorf("Error detecting the platform of the cluster: %s", err)
+		}
+		config.Platform = PlatformKubernetes
+		if openShift {
+			config.Platform = PlatformOpenShift
+		}
+		log.Info().Msgf("Detected %s platform", config.Platform)
 	}
 
 	wh := mutatingWebhook{
@@ -93,10 +118,22 @@ func NewMutatingWebhook(config Config, kubeClient kubernetes.Interface, certMana
 	// Start the MutatingWebhook web server
 	go wh.run(stop)
 
+	// Onboard the proxies of workloads running outside Kubernetes
+	go wh.runExternalWorkloadBootstrapper(stop)
+
+	// Reclaim the bootstrap Secrets no longer used by any proxy
+	go wh.runBootstrapSecretGarbageCollector(stop)
+
 	// Update the MutatingWebhookConfig with the OSM CA bundle
 	if err = updateMutatingWebhookCABundle(webhookHandlerCert, webhookConfigName, wh.kubeClient); err != nil {
 		return errors.Errorf("Error configuring MutatingWebhookConfiguration %s: %+v", webhookConfigName, err)
 	}
+
+	if config.WebhookURL != "" {
+		if err = updateMutatingWebhookURL(webhookConfigName, config.WebhookURL, wh.kubeClient); err != nil {
+			return errors.Errorf("Error configuring URL for MutatingWebhookConfiguration %s: %+v", webhookConfigName, err)
+		}
+	}
 	return nil
 }
 
@@ -112,6 +149,9 @@ func (wh *mutatingWebhook) run(stop <-chan struct{}) {
 	// because of the specifics of MutatingWebhookConfiguration template in this repository.
 	mux.HandleFunc(webhookCreatePod, wh.podCreationHandler)
 
+	// The init containers of the pods whose bootstrap config is delivered in a volume fetch it at this path
+	mux.HandleFunc(webhookBootstrapPath, wh.bootstrapHandler)
+
 	server := &http.Server{
 		Addr:    fmt.Sprintf(":%d", wh.config.ListenPort),
 		Handler: mux,
@@ -254,6 +294,9 @@ func (wh *mutatingWebhook) mutate(req *admissionv1.AdmissionRequest, proxyUUID u
 		return webhook.AdmissionError(err)
 	} else if !inject {
 		log.Trace().Msgf("Skipping sidecar injection for pod with UUID %s in namespace %s", proxyUUID, req.Namespace)
+		if req.DryRun == nil || !*req.DryRun {
+			wh.recordInjectionSkipped(&pod, req.Namespace)
+		}
 		return resp
 	}
 
@@ -292,7 +335,7 @@ func (wh *mutatingWebhook) mustInject(pod *corev1.Pod, namespace string) (bool,
 	// Check if the pod is annotated for injection
 	podInjectAnnotationExists, podInject, err := isAnnotatedForInjection(pod.Annotations, "Pod", fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
 	if err != nil {
-		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrDeterminingPodInjectionEnablement)).
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetricForResource(errcode.ErrDeterminingPodInjectionEnablement, errcode.ResourceName("Pod", namespace, pod.Name))).
 			Msg("Error determining if the pod is enabled for sidecar injection")
 		return false, err
 	}
@@ -305,24 +348,35 @@ func (wh *mutatingWebhook) mustInject(pod *corev1.Pod, namespace string) (bool,
 	}
 	nsInjectAnnotationExists, nsInject, err := isAnnotatedForInjection(ns.Annotations, "Namespace", ns.Name)
 	if err != nil {
-		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrDeterminingNamespaceInjectionEnablement)).
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetricForResource(errcode.ErrDeterminingNamespaceInjectionEnablement, errcode.ResourceName("Namespace", "", namespace))).
 			Msgf("Error determining if namespace %s is enabled for sidecar injection", namespace)
 		return false, err
 	}
 
+	inject := false
 	if podInjectAnnotationExists && podInject {
 		// Pod is explicitly annotated to enable sidecar injection
-		return true, nil
+		inject = true
 	} else if nsInjectAnnotationExists && nsInject {
 		// Namespace is annotated to enable sidecar injection
 		if !podInjectAnnotationExists || podInject {
 			// If pod annotation doesn't exist or if an annotation exists to enable injection, enable it
-			return true, nil
+			inject = true
 		}
 	}
 
-	// Conditions to inject the sidecar are not met
-	return false, nil
+	if !inject {
+		// Conditions to inject the sidecar are not met
+		return false, nil
+	}
+
+	// Pods carrying the sidecar of another mesh are skipped, so that they can be migrated side by side
+	if sidecar := migration.GetOtherMeshSidecar(pod, ns); sidecar != nil {
+		log.Warn().Msgf("Mutation request is for pod with UID %s in namespace %s carrying the %s; Skipping sidecar injection", pod.ObjectMeta.UID, namespace, sidecar)
+		return false, nil
+	}
+
+	return true, nil
 }
 
 // getPortExclusionListForPod gets a list of ports to exclude from sidecar traffic interception for the given
@@ -339,7 +393,7 @@ func (wh *mutatingWebhook) getPortExclusionListForPod(pod *corev1.Pod, namespace
 	// Check if the pod is annotated for outbound port exclusion
 	ports, err := isAnnotatedForPortExclusion(pod.Annotations, annotation, pod.Kind, fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
 	if err != nil {
-		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrDeterminingPodPortExclusions)).
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetricForResource(errcode.ErrDeterminingPodPortExclusions, errcode.ResourceName("Pod", namespace, pod.Name))).
 			Msgf("Error determining port exclusions for annotation %s on pod %s/%s", annotation, namespace, pod.Name)
 		return ports, err
 	}
@@ -427,7 +481,7 @@ func updateMutatingWebhookCABundle(cert certificate.Certificater, webhookName st
 
 	if _, err = mwc.Patch(context.Background(), webhookName, types.StrategicMergePatchType, patchJSON, metav1.PatchOptions{}); err != nil {
 		// TODO: Need to push metric?
-		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrUpdatingMutatingWebhookCABundle)).
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetricForResource(errcode.ErrUpdatingMutatingWebhookCABundle, errcode.ResourceName("MutatingWebhookConfiguration", "", webhookName))).
 			Msgf("Error updating CA Bundle for MutatingWebhookConfiguration %s", webhookName)
 		return err
 	}
@@ -436,6 +490,36 @@ func updateMutatingWebhookCABundle(cert certificate.Certificater, webhookName st
 	return nil
 }
 
+// updateMutatingWebhookURL updates the existing MutatingWebhookConfiguration to reach the webhook at the given URL
+// instead of the injector service. The service reference is removed since the API server requires exactly one of
+// them to be set, which is why a merge patch cannot be used here.
+func updateMutatingWebhookURL(webhookConfigName string, webhookURL string, clientSet kubernetes.Interface) error {
+	mwc := clientSet.AdmissionregistrationV1().MutatingWebhookConfigurations()
+
+	config, err := mwc.Get(context.Background(), webhookConfigName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	url := webhook.GetURL(webhookURL, webhookCreatePod)
+	for idx := range config.Webhooks {
+		if config.Webhooks[idx].Name != MutatingWebhookName {
+			continue
+		}
+		config.Webhooks[idx].ClientConfig.URL = &url
+		config.Webhooks[idx].ClientConfig.Service = nil
+	}
+
+	if _, err = mwc.Update(context.Background(), config, metav1.UpdateOptions{}); err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetricForResource(errcode.ErrUpdatingMutatingWebhookURL, errcode.ResourceName("MutatingWebhookConfiguration", "", webhookConfigName))).
+			Msgf("Error updating URL for MutatingWebhookConfiguration %s", webhookConfigName)
+		return err
+	}
+
+	log.Info().Msgf("Finished updating URL for MutatingWebhookConfiguration %s to %s", webhookConfigName, url)
+	return nil
+}
+
 func webhookExists(mwc admissionRegistrationTypes.MutatingWebhookConfigurationInterface, webhookName string) error {
 	_, err := mwc.Get(context.Background(), webhookName, metav1.GetOptions{})
 	return err
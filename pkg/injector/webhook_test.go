@@ -96,6 +96,44 @@ var _ = Describe("Test MutatingWebhookConfiguration patch", func() {
 	})
 })
 
+func TestUpdateMutatingWebhookURL(t *testing.T) {
+	assert := tassert.New(t)
+
+	const webhookConfigName = "osm-webhook-osm"
+	kubeClient := fake.NewSimpleClientset(&admissionregv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: webhookConfigName},
+		Webhooks: []admissionregv1.MutatingWebhook{
+			{
+				Name: MutatingWebhookName,
+				ClientConfig: admissionregv1.WebhookClientConfig{
+					Service: &admissionregv1.ServiceReference{Name: injectorServiceName, Namespace: "osm-system"},
+				},
+			},
+			{
+				Name: "other-webhook",
+				ClientConfig: admissionregv1.WebhookClientConfig{
+					Service: &admissionregv1.ServiceReference{Name: "other", Namespace: "other"},
+				},
+			},
+		},
+	})
+
+	err := updateMutatingWebhookURL(webhookConfigName, "https://10.0.0.1:9090/", kubeClient)
+	assert.Nil(err)
+
+	config, err := kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.Background(), webhookConfigName, metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Nil(config.Webhooks[0].ClientConfig.Service)
+	assert.Equal("https://10.0.0.1:9090/mutate-pod-creation", *config.Webhooks[0].ClientConfig.URL)
+
+	// Webhooks not served by the injector are left untouched
+	assert.NotNil(config.Webhooks[1].ClientConfig.Service)
+	assert.Nil(config.Webhooks[1].ClientConfig.URL)
+
+	// The MutatingWebhookConfiguration must exist
+	assert.NotNil(updateMutatingWebhookURL("missing", "https://10.0.0.1:9090", kubeClient))
+}
+
 type mockCertificate struct{}
 
 func (mc mockCertificate) GetCommonName() certificate.CommonName     { return "" }
@@ -103,6 +141,7 @@ func (mc mockCertificate) GetCertificateChain() []byte               { return []
 func (mc mockCertificate) GetPrivateKey() []byte                     { return []byte("key") }
 func (mc mockCertificate) GetIssuingCA() []byte                      { return []byte("ca") }
 func (mc mockCertificate) GetExpiration() time.Time                  { return time.Now() }
+func (mc mockCertificate) GetIssueTime() time.Time                   { return time.Now() }
 func (mc mockCertificate) GetSerialNumber() certificate.SerialNumber { return "serial_number" }
 
 func TestIsAnnotatedForInjection(t *testing.T) {
@@ -342,6 +381,71 @@ var _ = Describe("Testing mustInject, isNamespaceInjectable", func() {
 		Expect(inject).To(BeFalse())
 	})
 
+	It("should return false when the pod enabled for sidecar injection carries the sidecar of another mesh", func() {
+		testNamespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: namespace,
+			},
+		}
+		retNs, err := fakeClientSet.CoreV1().Namespaces().Create(context.TODO(), testNamespace, metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		podWithOtherMeshSidecar := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pod-with-istio-sidecar",
+				Annotations: map[string]string{
+					constants.SidecarInjectionAnnotation: "enabled",
+				},
+			},
+			Spec: corev1.PodSpec{
+				ServiceAccountName: "test-SA",
+				Containers: []corev1.Container{
+					{Name: "app"},
+					{Name: "istio-proxy"},
+				},
+			},
+		}
+
+		mockKubeController.EXPECT().IsMonitoredNamespace(namespace).Return(true).Times(1)
+		mockKubeController.EXPECT().GetNamespace(namespace).Return(retNs)
+
+		inject, err := wh.mustInject(podWithOtherMeshSidecar, namespace)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inject).To(BeFalse())
+	})
+
+	It("should return false when the namespace is enabled for injection by both OSM and another mesh", func() {
+		testNamespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: namespace,
+				Annotations: map[string]string{
+					constants.SidecarInjectionAnnotation: "enabled",
+					"linkerd.io/inject":                  "enabled",
+				},
+			},
+		}
+		retNs, err := fakeClientSet.CoreV1().Namespaces().Create(context.TODO(), testNamespace, metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		podWithNoInjectAnnotation := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pod-with-no-injection-annotation",
+			},
+			Spec: corev1.PodSpec{
+				ServiceAccountName: "test-SA",
+			},
+		}
+
+		mockKubeController.EXPECT().IsMonitoredNamespace(namespace).Return(true).Times(1)
+		mockKubeController.EXPECT().GetNamespace(namespace).Return(retNs)
+
+		inject, err := wh.mustInject(podWithNoInjectAnnotation, namespace)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inject).To(BeFalse())
+	})
+
 	It("should return false when the pod's namespace is not being monitored", func() {
 		testNamespace := &corev1.Namespace{
 			ObjectMeta: metav1.ObjectMeta{
@@ -3,6 +3,8 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -44,6 +46,11 @@ func PatchSecretHandler(kubeClient kubernetes.Interface) chan struct{} {
 				namespace := addedPodObj.GetNamespace()
 				secretName := fmt.Sprintf("envoy-bootstrap-config-%s", podUUID)
 
+				// Pods whose bootstrap config is rendered into a volume by an init container have no bootstrap Secret
+				if !mountsSecret(addedPodObj, secretName) {
+					continue
+				}
+
 				secret, err := kubeClient.CoreV1().Secrets(namespace).Get(context.Background(), secretName, metav1.GetOptions{})
 				if err != nil {
 					log.Error().Err(err).Msgf("Failed to get secret %s/%s mounted to Pod %s/%s", namespace, secretName, namespace, podName)
@@ -68,3 +75,59 @@ func PatchSecretHandler(kubeClient kubernetes.Interface) chan struct{} {
 
 	return stop
 }
+
+// mountsSecret returns whether the given pod mounts a volume of the Secret with the given name
+func mountsSecret(pod *corev1.Pod, secretName string) bool {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Secret != nil && volume.Secret.SecretName == secretName {
+			return true
+		}
+	}
+	return false
+}
+
+// AppProtocolMismatchHandler records a warning event against each service whose ports have an appProtocol field
+// that is not supported or disagrees with the application protocol derived from the port's name, based on the
+// ServiceAdded and ServiceUpdated events. Supported appProtocol fields take precedence.
+// Returns a stop channel which can be used to stop the inner handler.
+func AppProtocolMismatchHandler(eventRecorder *events.ObjectEventRecorder) chan struct{} {
+	svcSubscription := events.Subscribe(announcements.ServiceAdded, announcements.ServiceUpdated)
+	stop := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			case svcMsg := <-svcSubscription:
+				psubMessage, castOk := svcMsg.(events.PubSubMessage)
+				if !castOk {
+					log.Error().Msgf("Error casting PubSubMessage: %T %v", psubMessage, psubMessage)
+					continue
+				}
+
+				svc, castOk := psubMessage.NewObj.(*corev1.Service)
+				if !castOk {
+					log.Error().Msgf("Failed to cast to *v1.Service: %T %v", psubMessage.NewObj, psubMessage.NewObj)
+					continue
+				}
+
+				mismatches := GetAppProtocolMismatches(svc)
+				if len(mismatches) == 0 {
+					continue
+				}
+
+				// Skip updates that don't change the mismatches, such as periodic resyncs
+				if oldSvc, ok := psubMessage.OldObj.(*corev1.Service); ok && reflect.DeepEqual(mismatches, GetAppProtocolMismatches(oldSvc)) {
+					continue
+				}
+
+				eventRecorder.WarnEvent(svc, events.AppProtocolMismatch,
+					"Service %s/%s has ports whose appProtocol is not supported or disagrees with the protocol derived from their name: %s",
+					svc.Namespace, svc.Name, strings.Join(mismatches, "; "))
+			}
+		}
+	}()
+
+	return stop
+}
@@ -7,10 +7,12 @@ import (
 	mapset "github.com/deckarep/golang-set"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/client-go/informers"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 
@@ -25,26 +27,49 @@ import (
 
 // NewKubernetesController returns a new kubernetes.Controller which means to provide access to locally-cached k8s resources
 func NewKubernetesController(kubeClient kubernetes.Interface, policyClient policyv1alpha1Client.Interface, meshName string, stop chan struct{}, selectInformers ...InformerKey) (Controller, error) {
+	return NewKubernetesControllerWithOptions(kubeClient, policyClient, meshName, ControllerOptions{}, stop, selectInformers...)
+}
+
+// NewKubernetesControllerWithOptions returns a new kubernetes.Controller whose informers are configured with the given options
+func NewKubernetesControllerWithOptions(kubeClient kubernetes.Interface, policyClient policyv1alpha1Client.Interface, meshName string, options ControllerOptions, stop chan struct{}, selectInformers ...InformerKey) (Controller, error) {
+	resyncInterval := options.ResyncInterval
+	if resyncInterval <= 0 {
+		resyncInterval = DefaultKubeEventResyncInterval
+	}
+
 	// Initialize client object
 	client := Client{
-		kubeClient:   kubeClient,
-		policyClient: policyClient,
-		meshName:     meshName,
-		informers:    informerCollection{},
+		kubeClient:           kubeClient,
+		policyClient:         policyClient,
+		meshName:             meshName,
+		informers:            informerCollection{},
+		resyncInterval:       resyncInterval,
+		resyncIntervals:      options.ResyncIntervals,
+		watchErrorBackoff:    options.WatchErrorBackoff,
+		watchErrorMaxBackoff: options.WatchErrorMaxBackoff,
+		watchScope:           options.WatchScope,
+		stop:                 stop,
 	}
 
 	// Initialize informers
 	informerInitHandlerMap := map[InformerKey]func(){
-		Namespaces:      client.initNamespaceMonitor,
-		Services:        client.initServicesMonitor,
-		ServiceAccounts: client.initServiceAccountsMonitor,
-		Pods:            client.initPodMonitor,
-		Endpoints:       client.initEndpointMonitor,
+		Namespaces:        client.initNamespaceMonitor,
+		Services:          client.initServicesMonitor,
+		ServiceAccounts:   client.initServiceAccountsMonitor,
+		Pods:              client.initPodMonitor,
+		Endpoints:         client.initEndpointMonitor,
+		EndpointSlices:    client.initEndpointSliceMonitor,
+		ExternalWorkloads: client.initExternalWorkloadMonitor,
+		Secrets:           client.initSecretMonitor,
 	}
 
 	// If specific informers are not selected to be initialized, initialize all informers
 	if len(selectInformers) == 0 {
-		selectInformers = []InformerKey{Namespaces, Services, ServiceAccounts, Pods, Endpoints}
+		selectInformers = []InformerKey{Namespaces, Services, ServiceAccounts, Pods, Endpoints, EndpointSlices, Secrets}
+		// ExternalWorkload resources can only be monitored with a client for OSM's policy API
+		if policyClient != nil {
+			selectInformers = append(selectInformers, ExternalWorkloads)
+		}
 	}
 
 	for _, informer := range selectInformers {
@@ -64,14 +89,19 @@ func (c *Client) initNamespaceMonitor() {
 	monitorNamespaceLabel := map[string]string{constants.OSMKubeResourceMonitorAnnotation: c.meshName}
 
 	labelSelector := fields.SelectorFromSet(monitorNamespaceLabel).String()
-	option := informers.WithTweakListOptions(func(opt *metav1.ListOptions) {
+	option := func(opt *metav1.ListOptions) {
 		opt.LabelSelector = labelSelector
-	})
-
-	informerFactory := informers.NewSharedInformerFactoryWithOptions(c.kubeClient, DefaultKubeEventResyncInterval, option)
+	}
 
-	// Add informer
-	c.informers[Namespaces] = informerFactory.Core().V1().Namespaces().Informer()
+	// Namespaces are cluster-scoped, and watched regardless of the watch scope
+	c.informers[Namespaces] = c.newInformer(Namespaces, WatchScope{}, &corev1.Namespace{},
+		func(_ string, options metav1.ListOptions) (runtime.Object, error) {
+			return c.kubeClient.CoreV1().Namespaces().List(context.Background(), options)
+		},
+		func(_ string, options metav1.ListOptions) (watch.Interface, error) {
+			return c.kubeClient.CoreV1().Namespaces().Watch(context.Background(), options)
+		},
+		option)
 
 	// Add event handler to informer
 	nsEventTypes := EventTypes{
@@ -93,8 +123,14 @@ func (c *Client) shouldObserve(obj interface{}) bool {
 
 // Initializes Service monitoring
 func (c *Client) initServicesMonitor() {
-	informerFactory := informers.NewSharedInformerFactory(c.kubeClient, DefaultKubeEventResyncInterval)
-	c.informers[Services] = informerFactory.Core().V1().Services().Informer()
+	c.informers[Services] = c.newInformer(Services, c.watchScope, &corev1.Service{},
+		func(namespace string, options metav1.ListOptions) (runtime.Object, error) {
+			return c.kubeClient.CoreV1().Services(namespace).List(context.Background(), options)
+		},
+		func(namespace string, options metav1.ListOptions) (watch.Interface, error) {
+			return c.kubeClient.CoreV1().Services(namespace).Watch(context.Background(), options)
+		},
+		nil)
 
 	svcEventTypes := EventTypes{
 		Add:    announcements.ServiceAdded,
@@ -106,8 +142,14 @@ func (c *Client) initServicesMonitor() {
 
 // Initializes Service Account monitoring
 func (c *Client) initServiceAccountsMonitor() {
-	informerFactory := informers.NewSharedInformerFactory(c.kubeClient, DefaultKubeEventResyncInterval)
-	c.informers[ServiceAccounts] = informerFactory.Core().V1().ServiceAccounts().Informer()
+	c.informers[ServiceAccounts] = c.newInformer(ServiceAccounts, c.watchScope, &corev1.ServiceAccount{},
+		func(namespace string, options metav1.ListOptions) (runtime.Object, error) {
+			return c.kubeClient.CoreV1().ServiceAccounts(namespace).List(context.Background(), options)
+		},
+		func(namespace string, options metav1.ListOptions) (watch.Interface, error) {
+			return c.kubeClient.CoreV1().ServiceAccounts(namespace).Watch(context.Background(), options)
+		},
+		nil)
 
 	svcEventTypes := EventTypes{
 		Add:    announcements.ServiceAccountAdded,
@@ -118,8 +160,14 @@ func (c *Client) initServiceAccountsMonitor() {
 }
 
 func (c *Client) initPodMonitor() {
-	informerFactory := informers.NewSharedInformerFactory(c.kubeClient, DefaultKubeEventResyncInterval)
-	c.informers[Pods] = informerFactory.Core().V1().Pods().Informer()
+	c.informers[Pods] = c.newInformer(Pods, c.watchScope, &corev1.Pod{},
+		func(namespace string, options metav1.ListOptions) (runtime.Object, error) {
+			return c.kubeClient.CoreV1().Pods(namespace).List(context.Background(), options)
+		},
+		func(namespace string, options metav1.ListOptions) (watch.Interface, error) {
+			return c.kubeClient.CoreV1().Pods(namespace).Watch(context.Background(), options)
+		},
+		nil)
 
 	podEventTypes := EventTypes{
 		Add:    announcements.PodAdded,
@@ -130,8 +178,14 @@ func (c *Client) initPodMonitor() {
 }
 
 func (c *Client) initEndpointMonitor() {
-	informerFactory := informers.NewSharedInformerFactory(c.kubeClient, DefaultKubeEventResyncInterval)
-	c.informers[Endpoints] = informerFactory.Core().V1().Endpoints().Informer()
+	c.informers[Endpoints] = c.newInformer(Endpoints, c.watchScope, &corev1.Endpoints{},
+		func(namespace string, options metav1.ListOptions) (runtime.Object, error) {
+			return c.kubeClient.CoreV1().Endpoints(namespace).List(context.Background(), options)
+		},
+		func(namespace string, options metav1.ListOptions) (watch.Interface, error) {
+			return c.kubeClient.CoreV1().Endpoints(namespace).Watch(context.Background(), options)
+		},
+		nil)
 
 	eptEventTypes := EventTypes{
 		Add:    announcements.EndpointAdded,
@@ -141,6 +195,81 @@ func (c *Client) initEndpointMonitor() {
 	c.informers[Endpoints].AddEventHandler(GetKubernetesEventHandlers((string)(Endpoints), providerName, c.shouldObserve, eptEventTypes))
 }
 
+func (c *Client) initEndpointSliceMonitor() {
+	c.informers[EndpointSlices] = c.newInformer(EndpointSlices, c.watchScope, &discoveryv1beta1.EndpointSlice{},
+		func(namespace string, options metav1.ListOptions) (runtime.Object, error) {
+			return c.kubeClient.DiscoveryV1beta1().EndpointSlices(namespace).List(context.Background(), options)
+		},
+		func(namespace string, options metav1.ListOptions) (watch.Interface, error) {
+			return c.kubeClient.DiscoveryV1beta1().EndpointSlices(namespace).Watch(context.Background(), options)
+		},
+		nil)
+
+	// EndpointSlices are looked up by the service they belong to, which they reference with a label
+	err := c.informers[EndpointSlices].AddIndexers(cache.Indexers{
+		endpointSliceServiceIndex: func(obj interface{}) ([]string, error) {
+			slice, ok := obj.(*discoveryv1beta1.EndpointSlice)
+			if !ok {
+				return nil, nil
+			}
+			serviceName, ok := slice.Labels[discoveryv1beta1.LabelServiceName]
+			if !ok {
+				return nil, nil
+			}
+			return []string{slice.Namespace + "/" + serviceName}, nil
+		},
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error adding the service index to the EndpointSlices informer")
+	}
+
+	sliceEventTypes := EventTypes{
+		Add:    announcements.EndpointSliceAdded,
+		Update: announcements.EndpointSliceUpdated,
+		Delete: announcements.EndpointSliceDeleted,
+	}
+	c.informers[EndpointSlices].AddEventHandler(GetKubernetesEventHandlers((string)(EndpointSlices), providerName, c.shouldObserve, sliceEventTypes))
+}
+
+// Initializes the monitoring of TLS secrets, which hold the certificates presented to ingress clients
+func (c *Client) initSecretMonitor() {
+	c.informers[Secrets] = c.newInformer(Secrets, c.watchScope, &corev1.Secret{},
+		func(namespace string, options metav1.ListOptions) (runtime.Object, error) {
+			return c.kubeClient.CoreV1().Secrets(namespace).List(context.Background(), options)
+		},
+		func(namespace string, options metav1.ListOptions) (watch.Interface, error) {
+			return c.kubeClient.CoreV1().Secrets(namespace).Watch(context.Background(), options)
+		},
+		func(opt *metav1.ListOptions) {
+			opt.FieldSelector = fields.OneTermEqualSelector("type", string(corev1.SecretTypeTLS)).String()
+		})
+
+	secretEventTypes := EventTypes{
+		Add:    announcements.SecretAdded,
+		Update: announcements.SecretUpdated,
+		Delete: announcements.SecretDeleted,
+	}
+	c.informers[Secrets].AddEventHandler(GetKubernetesEventHandlers((string)(Secrets), providerName, c.shouldObserve, secretEventTypes))
+}
+
+func (c *Client) initExternalWorkloadMonitor() {
+	c.informers[ExternalWorkloads] = c.newInformer(ExternalWorkloads, c.watchScope, &policyv1alpha1.ExternalWorkload{},
+		func(namespace string, options metav1.ListOptions) (runtime.Object, error) {
+			return c.policyClient.PolicyV1alpha1().ExternalWorkloads(namespace).List(context.Background(), options)
+		},
+		func(namespace string, options metav1.ListOptions) (watch.Interface, error) {
+			return c.policyClient.PolicyV1alpha1().ExternalWorkloads(namespace).Watch(context.Background(), options)
+		},
+		nil)
+
+	externalWorkloadEventTypes := EventTypes{
+		Add:    announcements.ExternalWorkloadAdded,
+		Update: announcements.ExternalWorkloadUpdated,
+		Delete: announcements.ExternalWorkloadDeleted,
+	}
+	c.informers[ExternalWorkloads].AddEventHandler(GetKubernetesEventHandlers((string)(ExternalWorkloads), providerName, c.shouldObserve, externalWorkloadEventTypes))
+}
+
 func (c *Client) run(stop <-chan struct{}) error {
 	log.Info().Msg("Namespace controller client started")
 	var hasSynced []cache.InformerSynced
@@ -170,8 +299,21 @@ func (c *Client) run(stop <-chan struct{}) error {
 	return nil
 }
 
+// HasSynced returns whether the caches of all the informers have synced
+func (c Client) HasSynced() bool {
+	for _, informer := range c.informers {
+		if informer != nil && !informer.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
 // IsMonitoredNamespace returns a boolean indicating if the namespace is among the list of monitored namespaces
 func (c Client) IsMonitoredNamespace(namespace string) bool {
+	if !c.watchScope.includesNamespace(namespace) {
+		return false
+	}
 	_, exists, _ := c.informers[Namespaces].GetStore().GetByKey(namespace)
 	return exists
 }
@@ -186,6 +328,9 @@ func (c Client) ListMonitoredNamespaces() ([]string, error) {
 			log.Error().Err(errListingNamespaces).Msg("Failed to list monitored namespaces")
 			continue
 		}
+		if !c.watchScope.includesNamespace(namespace.Name) {
+			continue
+		}
 		namespaces = append(namespaces, namespace.Name)
 	}
 	return namespaces, nil
@@ -258,6 +403,24 @@ func (c Client) ListPods() []*corev1.Pod {
 	return pods
 }
 
+// ListExternalWorkloads returns a list of external workloads part of the mesh
+func (c Client) ListExternalWorkloads() []*policyv1alpha1.ExternalWorkload {
+	informer, ok := c.informers[ExternalWorkloads]
+	if !ok {
+		return nil
+	}
+
+	var externalWorkloads []*policyv1alpha1.ExternalWorkload
+	for _, obj := range informer.GetStore().List() {
+		externalWorkload := obj.(*policyv1alpha1.ExternalWorkload)
+		if !c.IsMonitoredNamespace(externalWorkload.Namespace) {
+			continue
+		}
+		externalWorkloads = append(externalWorkloads, externalWorkload)
+	}
+	return externalWorkloads
+}
+
 // GetEndpoints returns the endpoint for a given service, otherwise returns nil if not found
 // or error if the API errored out.
 func (c Client) GetEndpoints(svc service.MeshService) (*corev1.Endpoints, error) {
@@ -271,6 +434,54 @@ func (c Client) GetEndpoints(svc service.MeshService) (*corev1.Endpoints, error)
 	return nil, nil
 }
 
+// ListEndpointSlicesForService returns the EndpointSlices of the given service
+func (c Client) ListEndpointSlicesForService(svc service.MeshService) []*discoveryv1beta1.EndpointSlice {
+	informer, ok := c.informers[EndpointSlices]
+	if !ok {
+		return nil
+	}
+
+	objs, err := informer.GetIndexer().ByIndex(endpointSliceServiceIndex, svc.NameWithoutCluster())
+	if err != nil {
+		log.Error().Err(err).Msgf("Error listing EndpointSlices for service %s", svc)
+		return nil
+	}
+
+	var slices []*discoveryv1beta1.EndpointSlice
+	for _, obj := range objs {
+		slices = append(slices, obj.(*discoveryv1beta1.EndpointSlice))
+	}
+	return slices
+}
+
+// GetPod returns the pod with the given namespace and name if found in cache, nil otherwise
+func (c Client) GetPod(namespace, name string) *corev1.Pod {
+	informer, ok := c.informers[Pods]
+	if !ok {
+		return nil
+	}
+
+	podIf, exists, err := informer.GetStore().GetByKey(namespace + "/" + name)
+	if exists && err == nil {
+		return podIf.(*corev1.Pod)
+	}
+	return nil
+}
+
+// GetSecret returns the TLS secret with the given namespace and name if found in cache, nil otherwise
+func (c Client) GetSecret(namespace, name string) *corev1.Secret {
+	informer, ok := c.informers[Secrets]
+	if !ok {
+		return nil
+	}
+
+	secretIf, exists, err := informer.GetStore().GetByKey(namespace + "/" + name)
+	if exists && err == nil {
+		return secretIf.(*corev1.Secret)
+	}
+	return nil
+}
+
 // ListServiceIdentitiesForService lists ServiceAccounts associated with the given service
 func (c Client) ListServiceIdentitiesForService(svc service.MeshService) ([]identity.K8sServiceAccount, error) {
 	var svcAccounts []identity.K8sServiceAccount
@@ -298,6 +509,18 @@ func (c Client) ListServiceIdentitiesForService(svc service.MeshService) ([]iden
 		}
 	}
 
+	for _, externalWorkload := range c.ListExternalWorkloads() {
+		if externalWorkload.Namespace != k8sSvc.Namespace || len(k8sSvc.Spec.Selector) == 0 {
+			continue
+		}
+		if labels.Set(k8sSvc.Spec.Selector).AsSelector().Matches(labels.Set(externalWorkload.Labels)) {
+			svcAccountsSet.Add(identity.K8sServiceAccount{
+				Name:      externalWorkload.Spec.ServiceAccount,
+				Namespace: externalWorkload.Namespace,
+			})
+		}
+	}
+
 	for svcAcc := range svcAccountsSet.Iter() {
 		svcAccounts = append(svcAccounts, svcAcc.(identity.K8sServiceAccount))
 	}
@@ -324,6 +547,14 @@ func (c Client) UpdateStatus(resource interface{}) (metav1.Object, error) {
 		obj := resource.(*policyv1alpha1.IngressBackend)
 		return c.policyClient.PolicyV1alpha1().IngressBackends(obj.Namespace).UpdateStatus(context.Background(), obj, metav1.UpdateOptions{})
 
+	case *policyv1alpha1.ProgressiveDelivery:
+		obj := resource.(*policyv1alpha1.ProgressiveDelivery)
+		return c.policyClient.PolicyV1alpha1().ProgressiveDeliveries(obj.Namespace).UpdateStatus(context.Background(), obj, metav1.UpdateOptions{})
+
+	case *policyv1alpha1.NamespaceOffboarding:
+		obj := resource.(*policyv1alpha1.NamespaceOffboarding)
+		return c.policyClient.PolicyV1alpha1().NamespaceOffboardings(obj.Namespace).UpdateStatus(context.Background(), obj, metav1.UpdateOptions{})
+
 	default:
 		return nil, errors.Errorf("Unsupported type: %T", t)
 	}
@@ -12,9 +12,11 @@ import (
 	. "github.com/onsi/gomega"
 	tassert "github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	testclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
 
 	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
 	fakePolicyClient "github.com/openservicemesh/osm/pkg/gen/client/policy/clientset/versioned/fake"
@@ -761,6 +763,50 @@ func TestUpdateStatus(t *testing.T) {
 					Reason:        "valid",
 				},
 			},
+		}, {
+			name: "valid ProgressiveDelivery resource",
+			existingResource: &policyv1alpha1.ProgressiveDelivery{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "canary",
+					Namespace: "test",
+				},
+				Spec: policyv1alpha1.ProgressiveDeliverySpec{
+					TrafficSplit:  "split",
+					CanaryBackend: "backend-v2",
+				},
+			},
+			updatedResource: &policyv1alpha1.ProgressiveDelivery{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "canary",
+					Namespace: "test",
+				},
+				Spec: policyv1alpha1.ProgressiveDeliverySpec{
+					TrafficSplit:  "split",
+					CanaryBackend: "backend-v2",
+				},
+				Status: policyv1alpha1.ProgressiveDeliveryStatus{
+					Phase:        policyv1alpha1.ProgressiveDeliveryProgressing,
+					CanaryWeight: 10,
+				},
+			},
+		}, {
+			name: "valid NamespaceOffboarding resource",
+			existingResource: &policyv1alpha1.NamespaceOffboarding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "osm",
+					Namespace: "test",
+				},
+			},
+			updatedResource: &policyv1alpha1.NamespaceOffboarding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "osm",
+					Namespace: "test",
+				},
+				Status: policyv1alpha1.NamespaceOffboardingStatus{
+					Phase:              policyv1alpha1.NamespaceOffboardingInProgress,
+					RestartedWorkloads: []string{"Deployment/bookstore"},
+				},
+			},
 		}, {
 			name:             "unsupported resource",
 			existingResource: &policyv1alpha1.Egress{},
@@ -783,3 +829,135 @@ func TestUpdateStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestListExternalWorkloads(t *testing.T) {
+	assert := tassert.New(t)
+
+	monitoredNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "monitored",
+			Labels: map[string]string{constants.OSMKubeResourceMonitorAnnotation: testMeshName},
+		},
+	}
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vm-svc",
+			Namespace: monitoredNamespace.Name,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "vm"},
+		},
+	}
+	monitoredWorkload := &policyv1alpha1.ExternalWorkload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vm",
+			Namespace: monitoredNamespace.Name,
+			Labels:    map[string]string{"app": "vm"},
+		},
+		Spec: policyv1alpha1.ExternalWorkloadSpec{
+			ServiceAccount: "vm-sa",
+			ProxyUUID:      uuid.New().String(),
+			Addresses:      []string{"10.0.0.1"},
+		},
+	}
+	unmonitoredWorkload := &policyv1alpha1.ExternalWorkload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vm",
+			Namespace: "unmonitored",
+			Labels:    map[string]string{"app": "vm"},
+		},
+		Spec: policyv1alpha1.ExternalWorkloadSpec{
+			ServiceAccount: "vm-sa",
+			ProxyUUID:      uuid.New().String(),
+			Addresses:      []string{"10.0.0.2"},
+		},
+	}
+
+	kubeClient := testclient.NewSimpleClientset(monitoredNamespace, svc)
+	policyClient := fakePolicyClient.NewSimpleClientset(monitoredWorkload, unmonitoredWorkload)
+
+	kubeController, err := NewKubernetesController(kubeClient, policyClient, testMeshName, make(chan struct{}))
+	assert.Nil(err)
+
+	assert.Equal([]*policyv1alpha1.ExternalWorkload{monitoredWorkload}, kubeController.ListExternalWorkloads())
+
+	serviceAccounts, err := kubeController.ListServiceIdentitiesForService(service.MeshService{Name: svc.Name, Namespace: svc.Namespace})
+	assert.Nil(err)
+	assert.ElementsMatch([]identity.K8sServiceAccount{{Name: "vm-sa", Namespace: monitoredNamespace.Name}}, serviceAccounts)
+}
+
+func TestListExternalWorkloadsWithoutPolicyClient(t *testing.T) {
+	assert := tassert.New(t)
+
+	kubeController, err := NewKubernetesController(testclient.NewSimpleClientset(), nil, testMeshName, make(chan struct{}))
+	assert.Nil(err)
+	assert.Empty(kubeController.ListExternalWorkloads())
+}
+
+func TestHasSynced(t *testing.T) {
+	assert := tassert.New(t)
+
+	// The informer caches are synced once the controller is created
+	kubeController, err := NewKubernetesController(testclient.NewSimpleClientset(), nil, testMeshName, make(chan struct{}))
+	assert.Nil(err)
+	assert.True(kubeController.HasSynced())
+
+	// Informers that have not synced their caches yet
+	c := Client{
+		informers: informerCollection{
+			Namespaces: cache.NewSharedIndexInformer(nil, &corev1.Namespace{}, 0, cache.Indexers{}),
+		},
+	}
+	assert.False(c.HasSynced())
+}
+
+func TestListEndpointSlicesForService(t *testing.T) {
+	assert := tassert.New(t)
+
+	svc := service.MeshService{Name: "bookstore", Namespace: "ns"}
+	newSlice := func(name, namespace, serviceName string) *discoveryv1beta1.EndpointSlice {
+		return &discoveryv1beta1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    map[string]string{discoveryv1beta1.LabelServiceName: serviceName},
+			},
+		}
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "ns"}}
+
+	kubeClient := testclient.NewSimpleClientset(
+		newSlice("bookstore-1", "ns", "bookstore"),
+		newSlice("bookstore-2", "ns", "bookstore"),
+		newSlice("bookstore-3", "other", "bookstore"),
+		newSlice("bookbuyer-1", "ns", "bookbuyer"),
+		pod,
+	)
+	kubeController, err := NewKubernetesController(kubeClient, nil, testMeshName, make(chan struct{}))
+	assert.Nil(err)
+
+	var names []string
+	for _, slice := range kubeController.ListEndpointSlicesForService(svc) {
+		names = append(names, slice.Name)
+	}
+	assert.ElementsMatch([]string{"bookstore-1", "bookstore-2"}, names)
+	assert.Empty(kubeController.ListEndpointSlicesForService(service.MeshService{Name: "unknown", Namespace: "ns"}))
+
+	assert.Equal(pod, kubeController.GetPod("ns", "pod"))
+	assert.Nil(kubeController.GetPod("ns", "unknown"))
+}
+
+func TestGetSecret(t *testing.T) {
+	assert := tassert.New(t)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "bookstore-tls", Namespace: "ns"},
+		Type:       corev1.SecretTypeTLS,
+	}
+	kubeClient := testclient.NewSimpleClientset(secret)
+	kubeController, err := NewKubernetesController(kubeClient, nil, testMeshName, make(chan struct{}), Namespaces, Secrets)
+	assert.Nil(err)
+
+	assert.Equal(secret, kubeController.GetSecret("ns", "bookstore-tls"))
+	assert.Nil(kubeController.GetSecret("other", "bookstore-tls"))
+}
@@ -1,6 +1,8 @@
 package events
 
 import (
+	"sync"
+
 	"github.com/cskr/pubsub"
 
 	"github.com/openservicemesh/osm/pkg/announcements"
@@ -14,6 +16,10 @@ const (
 var (
 	// Globally accessible instance, through singleton pattern using getPubSubInstance()
 	pubSubInstance *pubsub.PubSub
+
+	// publishObserver is called with every message published, guarded by publishObserverMutex
+	publishObserver      func(PubSubMessage)
+	publishObserverMutex sync.RWMutex
 )
 
 // Subscribe is the Subscribe implementation for PubSub
@@ -28,9 +34,24 @@ func Subscribe(aTypes ...announcements.AnnouncementType) chan interface{} {
 
 // Publish is the Publish implementation for PubSub
 func Publish(message PubSubMessage) {
+	publishObserverMutex.RLock()
+	if publishObserver != nil {
+		publishObserver(message)
+	}
+	publishObserverMutex.RUnlock()
+
 	getPubSubInstance().Pub(message, message.AnnouncementType.String())
 }
 
+// ObservePublished sets the function called synchronously with every message published, before the message is relayed
+// to its subscribers, so that all the messages can be observed irrespective of their announcement type. A nil function
+// removes the observer. The observer must not publish messages itself.
+func ObservePublished(observer func(PubSubMessage)) {
+	publishObserverMutex.Lock()
+	defer publishObserverMutex.Unlock()
+	publishObserver = observer
+}
+
 // Unsub is the Unsub implementation for PubSub.
 // It is synchronized, upon exit the channel is guaranteed to be both
 // unsubbed to all topics and closed.
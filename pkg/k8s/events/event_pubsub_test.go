@@ -71,3 +71,27 @@ func TestPubSubClose(t *testing.T) {
 	_, ok := <-subChannel
 	assert.False(ok)
 }
+
+func TestObservePublished(t *testing.T) {
+	assert := tassert.New(t)
+
+	var observed []announcements.AnnouncementType
+	ObservePublished(func(message PubSubMessage) {
+		observed = append(observed, message.AnnouncementType)
+	})
+
+	subChannel := Subscribe(announcements.PodAdded)
+	defer Unsub(subChannel)
+
+	// Messages are observed whether or not they have subscribers
+	Publish(PubSubMessage{AnnouncementType: announcements.PodAdded})
+	Publish(PubSubMessage{AnnouncementType: announcements.ServiceAdded})
+	assert.Equal([]announcements.AnnouncementType{announcements.PodAdded, announcements.ServiceAdded}, observed)
+	<-subChannel
+
+	// Messages are no longer observed once the observer is removed
+	ObservePublished(nil)
+	Publish(PubSubMessage{AnnouncementType: announcements.PodAdded})
+	assert.Len(observed, 2)
+	<-subChannel
+}
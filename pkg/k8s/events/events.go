@@ -56,6 +56,44 @@ func NewEventRecorder(object runtime.Object, kubeClient kubernetes.Interface, na
 	}, nil
 }
 
+// ObjectEventRecorder is a type used to record Kubernetes events against the objects the events are about, in the
+// namespaces of those objects, so that they are listed when the objects are described
+type ObjectEventRecorder struct {
+	recorder record.EventRecorder
+}
+
+// NewObjectEventRecorder returns a new ObjectEventRecorder object and an error in case of errors. The given functions
+// register the types of the objects events are recorded against that are not built into Kubernetes.
+func NewObjectEventRecorder(kubeClient kubernetes.Interface, addToSchemes ...func(*runtime.Scheme) error) (*ObjectEventRecorder, error) {
+	objectScheme := runtime.NewScheme()
+	for _, addToScheme := range append([]func(*runtime.Scheme) error{scheme.AddToScheme}, addToSchemes...) {
+		if err := addToScheme(objectScheme); err != nil {
+			return nil, err
+		}
+	}
+
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartRecordingToSink(
+		&typedcorev1.EventSinkImpl{
+			Interface: kubeClient.CoreV1().Events(metav1.NamespaceAll)})
+
+	return &ObjectEventRecorder{
+		recorder: eventBroadcaster.NewRecorder(objectScheme, corev1.EventSource{Component: eventSource}),
+	}, nil
+}
+
+// NormalEvent records a Normal Kubernetes event against the given object
+func (e *ObjectEventRecorder) NormalEvent(object runtime.Object, reason string, messageFmt string, args ...interface{}) {
+	e.recorder.Eventf(object, corev1.EventTypeNormal, reason, messageFmt, args...)
+	log.Info().Str("reason", reason).Msgf(messageFmt, args...)
+}
+
+// WarnEvent records a Warning Kubernetes event against the given object
+func (e *ObjectEventRecorder) WarnEvent(object runtime.Object, reason string, messageFmt string, args ...interface{}) {
+	e.recorder.Eventf(object, corev1.EventTypeWarning, reason, messageFmt, args...)
+	log.Warn().Str("reason", reason).Msgf(messageFmt, args...)
+}
+
 // GenericEventRecorder is a singleton that returns a generic EventRecorder type.
 // The EventRecorder returned needs to be explicitly initialized by calling the 'Initialize' method on the object
 func GenericEventRecorder() *EventRecorder {
@@ -8,6 +8,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/pkg/errors"
 )
@@ -85,3 +86,28 @@ func TestSpecificEventRecording(t *testing.T) {
 	eventRecorder.ErrorEvent(errors.New("test"), "TestReason", "Test message")
 	<-events
 }
+
+func TestObjectEventRecording(t *testing.T) {
+	assert := tassert.New(t)
+
+	eventRecorder, err := NewObjectEventRecorder(fake.NewSimpleClientset())
+	assert.Nil(err)
+	assert.NotNil(eventRecorder.recorder)
+
+	fakeRecorder := record.NewFakeRecorder(2)
+	eventRecorder.recorder = fakeRecorder
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "foo",
+			UID:       "bar",
+		},
+	}
+
+	eventRecorder.NormalEvent(svc, "TestReason", "Test message")
+	assert.Equal("Normal TestReason Test message", <-fakeRecorder.Events)
+
+	eventRecorder.WarnEvent(svc, "TestReason", "Test message")
+	assert.Equal("Warning TestReason Test message", <-fakeRecorder.Events)
+}
@@ -28,6 +28,83 @@ const (
 	CertificateIssuanceFailure = "FatalCertificateIssuanceFailure"
 )
 
+// Kubernetes Normal Event reasons
+const (
+	// TrafficSplitWeightsNormalized signifies that the weights of a TrafficSplit's backends were normalized to the
+	// effective weights programmed on the proxies
+	TrafficSplitWeightsNormalized = "TrafficSplitWeightsNormalized"
+
+	// ProgressiveDeliveryProgressed signifies that a progressive delivery shifted more traffic to its canary backend
+	ProgressiveDeliveryProgressed = "ProgressiveDeliveryProgressed"
+
+	// ProgressiveDeliverySucceeded signifies that the canary backend of a progressive delivery reached its maximum
+	// weight
+	ProgressiveDeliverySucceeded = "ProgressiveDeliverySucceeded"
+
+	// NamespaceEnrolled signifies that a namespace matching the onboarding selector was added to the mesh
+	NamespaceEnrolled = "NamespaceEnrolled"
+
+	// SidecarInjectionSkipped signifies that the sidecar was not injected into a pod, and why
+	SidecarInjectionSkipped = "SidecarInjectionSkipped"
+
+	// NamespaceOffboarded signifies that the workloads of a namespace removed from the mesh were restarted to remove
+	// their sidecars
+	NamespaceOffboarded = "NamespaceOffboarded"
+)
+
+// Kubernetes Warning Event reasons
+const (
+	// InvalidTrafficSplit signifies that the weights of a TrafficSplit's backends are invalid, and the TrafficSplit
+	// is ignored
+	InvalidTrafficSplit = "InvalidTrafficSplit"
+
+	// AppProtocolMismatch signifies that the appProtocol field of a service port is not supported or disagrees with
+	// the application protocol derived from the port's name
+	AppProtocolMismatch = "AppProtocolMismatch"
+
+	// MulticlusterExportConflict signifies that remote clusters export the same service with conflicting specs
+	MulticlusterExportConflict = "MulticlusterExportConflict"
+
+	// ProgressiveDeliveryAnalysisFailed signifies that the metrics of the canary backend of a progressive delivery
+	// did not meet its thresholds
+	ProgressiveDeliveryAnalysisFailed = "ProgressiveDeliveryAnalysisFailed"
+
+	// ProgressiveDeliveryRolledBack signifies that a progressive delivery rolled the traffic back from its canary
+	// backend after too many failed analyses
+	ProgressiveDeliveryRolledBack = "ProgressiveDeliveryRolledBack"
+
+	// NamespaceEnrollmentConflict signifies that a namespace matching the onboarding selector was not added to the
+	// mesh because it is already monitored by another mesh
+	NamespaceEnrollmentConflict = "NamespaceEnrollmentConflict"
+
+	// ProxyServiceAccountMismatch signifies that the proxy of a pod was rejected because the service account in its
+	// xDS certificate does not match the service account of the pod
+	ProxyServiceAccountMismatch = "ProxyServiceAccountMismatch"
+
+	// ProxyCertificateIssuanceFailed signifies that a certificate could not be issued for the proxy of a pod
+	ProxyCertificateIssuanceFailed = "ProxyCertificateIssuanceFailed"
+
+	// ProxyDesynced signifies that the proxy of a pod has not acknowledged the config sent to it for longer than the
+	// desync threshold of the controller
+	ProxyDesynced = "ProxyDesynced"
+
+	// PolicyConflict signifies that a policy conflicts with another policy for the same resource, only one of which is
+	// applied
+	PolicyConflict = "PolicyConflict"
+
+	// NamespaceOffboardingIncomplete signifies that pods of a namespace removed from the mesh still run sidecars
+	// because their workload could not be restarted or they are not managed by a restartable workload
+	NamespaceOffboardingIncomplete = "NamespaceOffboardingIncomplete"
+
+	// PreflightCheckFailed signifies that a preflight check of the mesh found an issue preventing the control plane or
+	// the sidecars from working
+	PreflightCheckFailed = "PreflightCheckFailed"
+
+	// VirtualHostDomainConflict signifies that virtual hosts of a route configuration share domains, which are only
+	// routed by one of them
+	VirtualHostDomainConflict = "VirtualHostDomainConflict"
+)
+
 // PubSubMessage represents a common messages abstraction to pass through the PubSub interface
 type PubSubMessage struct {
 	AnnouncementType announcements.AnnouncementType
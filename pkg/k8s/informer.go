@@ -0,0 +1,151 @@
+package k8s
+
+import (
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+)
+
+// newInformer returns an informer of the resources of the given type in the given watch scope. The informer resyncs
+// at the interval configured for it, backs off from watch errors and records its resync period, watch errors, relists,
+// the last time it heard from the API server and the size of its cache as metrics.
+func (c *Client) newInformer(key InformerKey, scope WatchScope, objType runtime.Object, listFn listFunc, watchFn watchFunc, tweak func(*metav1.ListOptions)) cache.SharedIndexInformer {
+	resyncInterval := c.resyncInterval
+	if interval, ok := c.resyncIntervals[key]; ok {
+		resyncInterval = interval
+	}
+	metricsstore.DefaultMetricsStore.K8sInformerResyncPeriod.WithLabelValues(string(key)).Set(resyncInterval.Seconds())
+
+	backoff := &watchErrorBackoff{
+		initial: c.watchErrorBackoff,
+		max:     c.watchErrorMaxBackoff,
+	}
+	informer := cache.NewSharedIndexInformer(
+		instrumentListWatch(key, scope.listWatch(listFn, watchFn, tweak), backoff, c.stop),
+		objType,
+		resyncInterval,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+
+	err := informer.SetWatchErrorHandler(func(r *cache.Reflector, err error) {
+		cache.DefaultWatchErrorHandler(r, err)
+		metricsstore.DefaultMetricsStore.K8sInformerWatchErrorCount.WithLabelValues(string(key)).Inc()
+		backoff.failed()
+	})
+	if err != nil {
+		log.Error().Err(err).Msgf("Error setting the watch error handler of the %s informer", key)
+	}
+
+	// The objects of the cache are added and deleted before the handlers are notified, resyncs and relists of existing
+	// objects are notified as updates
+	cacheSize := metricsstore.DefaultMetricsStore.K8sInformerCacheSize.WithLabelValues(string(key))
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { cacheSize.Inc() },
+		DeleteFunc: func(interface{}) { cacheSize.Dec() },
+	})
+
+	return informer
+}
+
+// instrumentListWatch returns a ListWatch that delays its lists by the given backoff after watch errors, and records
+// the relists of the informer and the last time it heard from the API server
+func instrumentListWatch(key InformerKey, lw *cache.ListWatch, backoff *watchErrorBackoff, stop <-chan struct{}) *cache.ListWatch {
+	informer := string(key)
+	markSynced := func() {
+		metricsstore.DefaultMetricsStore.K8sInformerLastSyncTime.WithLabelValues(informer).Set(float64(time.Now().Unix()))
+	}
+
+	var listed bool
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			backoff.wait(stop)
+			list, err := lw.List(options)
+			if err != nil {
+				return nil, err
+			}
+			if listed {
+				metricsstore.DefaultMetricsStore.K8sInformerRelistCount.WithLabelValues(informer).Inc()
+			}
+			listed = true
+			markSynced()
+			return list, nil
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			w, err := lw.Watch(options)
+			if err != nil {
+				return nil, err
+			}
+			markSynced()
+			return watch.Filter(w, func(event watch.Event) (watch.Event, bool) {
+				if event.Type != watch.Error {
+					markSynced()
+					backoff.reset()
+				}
+				return event, true
+			}), nil
+		},
+	}
+}
+
+// watchErrorBackoff is the exponential backoff an informer waits for before relisting its resources after consecutive
+// watch errors, in addition to the backoff of its reflector
+type watchErrorBackoff struct {
+	initial time.Duration
+	max     time.Duration
+
+	mu    sync.Mutex
+	delay time.Duration
+}
+
+// failed doubles the delay of the backoff after a watch error, up to its maximum
+func (b *watchErrorBackoff) failed() {
+	if b.initial <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.delay == 0 {
+		b.delay = b.initial
+	} else {
+		b.delay *= 2
+	}
+	if b.max > 0 && b.delay > b.max {
+		b.delay = b.max
+	}
+}
+
+// reset resets the delay of the backoff once the informer receives watch events again
+func (b *watchErrorBackoff) reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.delay = 0
+}
+
+// getDelay returns the current delay of the backoff
+func (b *watchErrorBackoff) getDelay() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.delay
+}
+
+// wait waits for the current delay of the backoff, or until stopped
+func (b *watchErrorBackoff) wait(stop <-chan struct{}) {
+	delay := b.getDelay()
+	if delay == 0 {
+		return
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-stop:
+	}
+}
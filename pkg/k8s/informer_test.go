@@ -0,0 +1,127 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+)
+
+func TestInformerMetrics(t *testing.T) {
+	assert := tassert.New(t)
+
+	kubeClient := testclient.NewSimpleClientset()
+
+	// The first watch of the pods fails, so that the informer relists them
+	watchFailed := false
+	kubeClient.PrependWatchReactor("pods", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		if watchFailed {
+			return false, nil, nil
+		}
+		watchFailed = true
+		return true, nil, errors.New("watch failed")
+	})
+
+	_, err := kubeClient.CoreV1().Pods(testNamespace).Create(context.TODO(), &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: testNamespace}}, metav1.CreateOptions{})
+	assert.Nil(err)
+
+	cacheSize := testutil.ToFloat64(metricsstore.DefaultMetricsStore.K8sInformerCacheSize.WithLabelValues(string(Pods)))
+	watchErrors := testutil.ToFloat64(metricsstore.DefaultMetricsStore.K8sInformerWatchErrorCount.WithLabelValues(string(Pods)))
+	relists := testutil.ToFloat64(metricsstore.DefaultMetricsStore.K8sInformerRelistCount.WithLabelValues(string(Pods)))
+
+	stop := make(chan struct{})
+	defer close(stop)
+	options := ControllerOptions{
+		ResyncInterval:    time.Hour,
+		ResyncIntervals:   map[InformerKey]time.Duration{Pods: time.Minute},
+		WatchErrorBackoff: 10 * time.Millisecond,
+	}
+	_, err = NewKubernetesControllerWithOptions(kubeClient, nil, testMeshName, options, stop, Namespaces, Pods)
+	assert.Nil(err)
+
+	assert.Equal(time.Hour.Seconds(), testutil.ToFloat64(metricsstore.DefaultMetricsStore.K8sInformerResyncPeriod.WithLabelValues(string(Namespaces))))
+	assert.Equal(time.Minute.Seconds(), testutil.ToFloat64(metricsstore.DefaultMetricsStore.K8sInformerResyncPeriod.WithLabelValues(string(Pods))))
+	assert.NotZero(testutil.ToFloat64(metricsstore.DefaultMetricsStore.K8sInformerLastSyncTime.WithLabelValues(string(Pods))))
+
+	assert.Eventually(func() bool {
+		return testutil.ToFloat64(metricsstore.DefaultMetricsStore.K8sInformerWatchErrorCount.WithLabelValues(string(Pods))) == watchErrors+1 &&
+			testutil.ToFloat64(metricsstore.DefaultMetricsStore.K8sInformerRelistCount.WithLabelValues(string(Pods))) == relists+1
+	}, 5*time.Second, assertEventuallyPollingInterval)
+
+	// The pod relisted after the watch error is not counted twice
+	assert.Equal(cacheSize+1, testutil.ToFloat64(metricsstore.DefaultMetricsStore.K8sInformerCacheSize.WithLabelValues(string(Pods))))
+}
+
+func TestWatchErrorBackoff(t *testing.T) {
+	assert := tassert.New(t)
+
+	backoff := &watchErrorBackoff{initial: time.Second, max: 3 * time.Second}
+	assert.Zero(backoff.getDelay())
+
+	backoff.failed()
+	assert.Equal(time.Second, backoff.getDelay())
+	backoff.failed()
+	assert.Equal(2*time.Second, backoff.getDelay())
+	backoff.failed()
+	assert.Equal(3*time.Second, backoff.getDelay())
+
+	// Waiting returns once stopped
+	stop := make(chan struct{})
+	close(stop)
+	backoff.wait(stop)
+
+	backoff.reset()
+	assert.Zero(backoff.getDelay())
+
+	// No delay is added without an initial backoff
+	backoff = &watchErrorBackoff{}
+	backoff.failed()
+	assert.Zero(backoff.getDelay())
+}
+
+func TestInstrumentListWatch(t *testing.T) {
+	assert := tassert.New(t)
+
+	fakeWatch := watch.NewFake()
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return &corev1.ServiceList{}, nil
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return fakeWatch, nil
+		},
+	}
+	backoff := &watchErrorBackoff{initial: time.Millisecond}
+	instrumented := instrumentListWatch("test", lw, backoff, make(chan struct{}))
+
+	relistCount := metricsstore.DefaultMetricsStore.K8sInformerRelistCount.WithLabelValues("test")
+
+	_, err := instrumented.List(metav1.ListOptions{})
+	assert.Nil(err)
+	assert.Zero(testutil.ToFloat64(relistCount))
+
+	backoff.failed()
+	_, err = instrumented.List(metav1.ListOptions{})
+	assert.Nil(err)
+	assert.Equal(float64(1), testutil.ToFloat64(relistCount))
+
+	// Watch events reset the backoff
+	w, err := instrumented.Watch(metav1.ListOptions{})
+	assert.Nil(err)
+	go fakeWatch.Add(&corev1.Service{})
+	<-w.ResultChan()
+	assert.Zero(backoff.getDelay())
+	w.Stop()
+}
@@ -8,9 +8,11 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	v1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
 	identity "github.com/openservicemesh/osm/pkg/identity"
 	service "github.com/openservicemesh/osm/pkg/service"
 	v1 "k8s.io/api/core/v1"
+	v1beta1 "k8s.io/api/discovery/v1beta1"
 	v10 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -66,6 +68,34 @@ func (mr *MockControllerMockRecorder) GetNamespace(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNamespace", reflect.TypeOf((*MockController)(nil).GetNamespace), arg0)
 }
 
+// GetPod mocks base method
+func (m *MockController) GetPod(arg0, arg1 string) *v1.Pod {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPod", arg0, arg1)
+	ret0, _ := ret[0].(*v1.Pod)
+	return ret0
+}
+
+// GetPod indicates an expected call of GetPod
+func (mr *MockControllerMockRecorder) GetPod(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPod", reflect.TypeOf((*MockController)(nil).GetPod), arg0, arg1)
+}
+
+// GetSecret mocks base method
+func (m *MockController) GetSecret(arg0, arg1 string) *v1.Secret {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSecret", arg0, arg1)
+	ret0, _ := ret[0].(*v1.Secret)
+	return ret0
+}
+
+// GetSecret indicates an expected call of GetSecret
+func (mr *MockControllerMockRecorder) GetSecret(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecret", reflect.TypeOf((*MockController)(nil).GetSecret), arg0, arg1)
+}
+
 // GetService mocks base method
 func (m *MockController) GetService(arg0 service.MeshService) *v1.Service {
 	m.ctrl.T.Helper()
@@ -80,6 +110,20 @@ func (mr *MockControllerMockRecorder) GetService(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetService", reflect.TypeOf((*MockController)(nil).GetService), arg0)
 }
 
+// HasSynced mocks base method
+func (m *MockController) HasSynced() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasSynced")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasSynced indicates an expected call of HasSynced
+func (mr *MockControllerMockRecorder) HasSynced() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasSynced", reflect.TypeOf((*MockController)(nil).HasSynced))
+}
+
 // IsMetricsEnabled mocks base method
 func (m *MockController) IsMetricsEnabled(arg0 *v1.Pod) bool {
 	m.ctrl.T.Helper()
@@ -108,6 +152,34 @@ func (mr *MockControllerMockRecorder) IsMonitoredNamespace(arg0 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsMonitoredNamespace", reflect.TypeOf((*MockController)(nil).IsMonitoredNamespace), arg0)
 }
 
+// ListEndpointSlicesForService mocks base method
+func (m *MockController) ListEndpointSlicesForService(arg0 service.MeshService) []*v1beta1.EndpointSlice {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEndpointSlicesForService", arg0)
+	ret0, _ := ret[0].([]*v1beta1.EndpointSlice)
+	return ret0
+}
+
+// ListEndpointSlicesForService indicates an expected call of ListEndpointSlicesForService
+func (mr *MockControllerMockRecorder) ListEndpointSlicesForService(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEndpointSlicesForService", reflect.TypeOf((*MockController)(nil).ListEndpointSlicesForService), arg0)
+}
+
+// ListExternalWorkloads mocks base method
+func (m *MockController) ListExternalWorkloads() []*v1alpha1.ExternalWorkload {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExternalWorkloads")
+	ret0, _ := ret[0].([]*v1alpha1.ExternalWorkload)
+	return ret0
+}
+
+// ListExternalWorkloads indicates an expected call of ListExternalWorkloads
+func (mr *MockControllerMockRecorder) ListExternalWorkloads() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExternalWorkloads", reflect.TypeOf((*MockController)(nil).ListExternalWorkloads))
+}
+
 // ListMonitoredNamespaces mocks base method
 func (m *MockController) ListMonitoredNamespaces() ([]string, error) {
 	m.ctrl.T.Helper()
@@ -85,6 +85,19 @@ func (pf *PortForwarder) Done() <-chan struct{} {
 	return pf.done
 }
 
+// GetLocalPort returns the local port forwarded to the pod. This is used to find the port chosen by the system
+// when the port forwarder is created with a local port of 0.
+func (pf *PortForwarder) GetLocalPort() (uint16, error) {
+	ports, err := pf.forwarder.GetPorts()
+	if err != nil {
+		return 0, err
+	}
+	if len(ports) == 0 {
+		return 0, errors.New("No ports are being forwarded")
+	}
+	return ports[0].Local, nil
+}
+
 // DialerToPod constructs a new httpstream.Dialer to connect to a pod for use
 // with a PortForwarder
 func DialerToPod(conf *rest.Config, clientSet kubernetes.Interface, podName string, namespace string) (httpstream.Dialer, error) {
@@ -49,6 +49,32 @@ func TestPortForwardSuccess(t *testing.T) {
 	pf.Stop()
 }
 
+func TestPortForwardGetLocalPort(t *testing.T) {
+	dialer := &fakeDialer{
+		conn: &noopConnection{},
+	}
+
+	pf, err := NewPortForwarder(dialer, ":80")
+	if err != nil {
+		t.Fatal("error creating PortForwarder:", err)
+	}
+
+	err = pf.Start(func(pf *PortForwarder) error {
+		port, err := pf.GetLocalPort()
+		if err != nil {
+			return err
+		}
+		if port == 0 {
+			t.Error("Expected a local port to be chosen, got 0")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error("error running port forward:", err)
+	}
+	pf.Stop()
+}
+
 func TestPortForwardInvalidPortSpec(t *testing.T) {
 	portSpec := ""
 	pf, err := NewPortForwarder(nil, "")
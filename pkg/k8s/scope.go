@@ -0,0 +1,195 @@
+package k8s
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+// WatchScope restricts the namespaced resources watched by the informers of the Controller, so that the controllers
+// of several meshes sharing a cluster only cache the resources of their own mesh. Namespaces are always watched
+// cluster-wide, by the label identifying the namespaces monitored by the mesh.
+type WatchScope struct {
+	// Namespaces restricts the namespaced resources watched to those in the given namespaces, in which case the other
+	// namespaces are not monitored even if labeled for the mesh. All namespaces are watched if empty.
+	Namespaces []string
+
+	// LabelSelector restricts the namespaced resources watched to those matching the given label selector, in which
+	// case the Services, ServiceAccounts, Pods and TLS Secrets of the mesh must be labeled accordingly. Endpoints and
+	// EndpointSlices inherit the labels of their Service. All resources are watched if empty.
+	LabelSelector string
+}
+
+// listFunc lists the resources of a type in the given namespace, all namespaces if empty
+type listFunc func(namespace string, options metav1.ListOptions) (runtime.Object, error)
+
+// watchFunc watches the resources of a type in the given namespace, all namespaces if empty
+type watchFunc func(namespace string, options metav1.ListOptions) (watch.Interface, error)
+
+// includesNamespace returns whether the resources in the given namespace are watched
+func (s WatchScope) includesNamespace(namespace string) bool {
+	if len(s.Namespaces) == 0 {
+		return true
+	}
+	for _, ns := range s.Namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// listWatch returns the ListWatch of the resources in the watch scope. The options of the requests are further
+// restricted by the given tweak function, if any.
+func (s WatchScope) listWatch(listFn listFunc, watchFn watchFunc, tweak func(*metav1.ListOptions)) *cache.ListWatch {
+	tweakOptions := func(options *metav1.ListOptions) {
+		if s.LabelSelector != "" {
+			options.LabelSelector = s.LabelSelector
+		}
+		if tweak != nil {
+			tweak(options)
+		}
+	}
+
+	if len(s.Namespaces) <= 1 {
+		var namespace string // all namespaces
+		if len(s.Namespaces) == 1 {
+			namespace = s.Namespaces[0]
+		}
+		return &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				tweakOptions(&options)
+				return listFn(namespace, options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				tweakOptions(&options)
+				return watchFn(namespace, options)
+			},
+		}
+	}
+
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			tweakOptions(&options)
+			return listNamespaces(s.Namespaces, listFn, options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			tweakOptions(&options)
+			return watchNamespaces(s.Namespaces, watchFn, options)
+		},
+	}
+}
+
+// listNamespaces lists the resources in the given namespaces as a single list. Its resource version is that of the
+// first namespace listed, so that watching from it replays rather than misses the changes made in the other namespaces
+// while they were listed.
+func listNamespaces(namespaces []string, list listFunc, options metav1.ListOptions) (runtime.Object, error) {
+	// Lists of several namespaces can't be paginated
+	options.Limit = 0
+	options.Continue = ""
+
+	var merged runtime.Object
+	var items []runtime.Object
+	for _, ns := range namespaces {
+		nsList, err := list(ns, options)
+		if err != nil {
+			return nil, err
+		}
+		nsItems, err := meta.ExtractList(nsList)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, nsItems...)
+		if merged == nil {
+			merged = nsList
+		}
+	}
+
+	if err := meta.SetList(merged, items); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// watchNamespaces watches the resources in the given namespaces as a single watch
+func watchNamespaces(namespaces []string, watchNamespace watchFunc, options metav1.ListOptions) (watch.Interface, error) {
+	var watches []watch.Interface
+	for _, ns := range namespaces {
+		nsWatch, err := watchNamespace(ns, options)
+		if err != nil {
+			for _, w := range watches {
+				w.Stop()
+			}
+			return nil, err
+		}
+		watches = append(watches, nsWatch)
+	}
+	return newMultiNamespaceWatch(watches), nil
+}
+
+// multiNamespaceWatch multiplexes the watches of several namespaces. It is stopped as soon as one of them ends, so
+// that the informer restarts all of them.
+type multiNamespaceWatch struct {
+	watches  []watch.Interface
+	result   chan watch.Event
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func newMultiNamespaceWatch(watches []watch.Interface) *multiNamespaceWatch {
+	w := &multiNamespaceWatch{
+		watches: watches,
+		result:  make(chan watch.Event),
+		stop:    make(chan struct{}),
+	}
+
+	var wg sync.WaitGroup
+	for _, nsWatch := range watches {
+		wg.Add(1)
+		go func(nsWatch watch.Interface) {
+			defer wg.Done()
+			for {
+				select {
+				case event, ok := <-nsWatch.ResultChan():
+					if !ok {
+						w.Stop()
+						return
+					}
+					select {
+					case w.result <- event:
+					case <-w.stop:
+						return
+					}
+				case <-w.stop:
+					return
+				}
+			}
+		}(nsWatch)
+	}
+
+	go func() {
+		wg.Wait()
+		close(w.result)
+	}()
+
+	return w
+}
+
+// Stop stops the watches of all the namespaces
+func (w *multiNamespaceWatch) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+		for _, nsWatch := range w.watches {
+			nsWatch.Stop()
+		}
+	})
+}
+
+// ResultChan returns the events of the watches of all the namespaces
+func (w *multiNamespaceWatch) ResultChan() <-chan watch.Event {
+	return w.result
+}
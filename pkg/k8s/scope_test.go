@@ -0,0 +1,150 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	testclient "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+func TestWatchScope(t *testing.T) {
+	newNamespace := func(name string) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{constants.OSMKubeResourceMonitorAnnotation: testMeshName},
+			},
+		}
+	}
+	newService := func(name, namespace string, labels map[string]string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    labels,
+			},
+		}
+	}
+	meshLabels := map[string]string{"mesh": testMeshName}
+
+	testCases := []struct {
+		name                 string
+		watchScope           WatchScope
+		expectedServices     []string
+		expectedNamespaces   []string
+		unmonitoredNamespace string
+	}{
+		{
+			name:               "all namespaces and resources",
+			watchScope:         WatchScope{},
+			expectedServices:   []string{"ns-1/labeled", "ns-1/unlabeled", "ns-2/labeled", "ns-3/labeled"},
+			expectedNamespaces: []string{"ns-1", "ns-2", "ns-3"},
+		},
+		{
+			name:                 "single namespace",
+			watchScope:           WatchScope{Namespaces: []string{"ns-1"}},
+			expectedServices:     []string{"ns-1/labeled", "ns-1/unlabeled"},
+			expectedNamespaces:   []string{"ns-1"},
+			unmonitoredNamespace: "ns-2",
+		},
+		{
+			name:                 "several namespaces",
+			watchScope:           WatchScope{Namespaces: []string{"ns-1", "ns-2"}},
+			expectedServices:     []string{"ns-1/labeled", "ns-1/unlabeled", "ns-2/labeled"},
+			expectedNamespaces:   []string{"ns-1", "ns-2"},
+			unmonitoredNamespace: "ns-3",
+		},
+		{
+			name:               "label selector",
+			watchScope:         WatchScope{LabelSelector: "mesh=" + testMeshName},
+			expectedServices:   []string{"ns-1/labeled", "ns-2/labeled", "ns-3/labeled"},
+			expectedNamespaces: []string{"ns-1", "ns-2", "ns-3"},
+		},
+		{
+			name:                 "several namespaces and label selector",
+			watchScope:           WatchScope{Namespaces: []string{"ns-1", "ns-2"}, LabelSelector: "mesh=" + testMeshName},
+			expectedServices:     []string{"ns-1/labeled", "ns-2/labeled"},
+			expectedNamespaces:   []string{"ns-1", "ns-2"},
+			unmonitoredNamespace: "ns-3",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			kubeClient := testclient.NewSimpleClientset(
+				newNamespace("ns-1"),
+				newNamespace("ns-2"),
+				newNamespace("ns-3"),
+				newService("labeled", "ns-1", meshLabels),
+				newService("unlabeled", "ns-1", nil),
+				newService("labeled", "ns-2", meshLabels),
+				newService("labeled", "ns-3", meshLabels),
+			)
+
+			stop := make(chan struct{})
+			defer close(stop)
+			kubeController, err := NewKubernetesControllerWithOptions(kubeClient, nil, testMeshName, ControllerOptions{WatchScope: tc.watchScope}, stop, Namespaces, Services)
+			assert.Nil(err)
+
+			var services []string
+			for _, svc := range kubeController.ListServices() {
+				services = append(services, svc.Namespace+"/"+svc.Name)
+			}
+			assert.ElementsMatch(tc.expectedServices, services)
+
+			namespaces, err := kubeController.ListMonitoredNamespaces()
+			assert.Nil(err)
+			assert.ElementsMatch(tc.expectedNamespaces, namespaces)
+
+			if tc.unmonitoredNamespace != "" {
+				assert.False(kubeController.IsMonitoredNamespace(tc.unmonitoredNamespace))
+			}
+
+			// Services created once the caches synced are watched in every namespace of the scope
+			for _, ns := range tc.expectedNamespaces {
+				_, err := kubeClient.CoreV1().Services(ns).Create(context.TODO(), newService("created", ns, meshLabels), metav1.CreateOptions{})
+				assert.Nil(err)
+			}
+			assert.Eventually(func() bool {
+				count := 0
+				for _, svc := range kubeController.ListServices() {
+					if svc.Name == "created" {
+						count++
+					}
+				}
+				return count == len(tc.expectedNamespaces)
+			}, nsInformerSyncTimeout, assertEventuallyPollingInterval)
+		})
+	}
+}
+
+func TestMultiNamespaceWatch(t *testing.T) {
+	assert := tassert.New(t)
+
+	watch1 := watch.NewFake()
+	watch2 := watch.NewFake()
+	w := newMultiNamespaceWatch([]watch.Interface{watch1, watch2})
+
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "ns-2"}}
+	go watch2.Add(svc)
+	event := <-w.ResultChan()
+	assert.Equal(watch.Added, event.Type)
+	assert.Equal(svc, event.Object)
+
+	// The watch ends as soon as the watch of one of its namespaces ends
+	watch1.Stop()
+	_, ok := <-w.ResultChan()
+	assert.False(ok)
+	assert.True(watch2.IsStopped())
+
+	// Stopping the watch again is a no-op
+	w.Stop()
+}
@@ -6,10 +6,12 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
 	policyv1alpha1Client "github.com/openservicemesh/osm/pkg/gen/client/policy/clientset/versioned"
 
 	"github.com/openservicemesh/osm/pkg/identity"
@@ -45,6 +47,9 @@ const (
 
 	// providerName is the name of the Kubernetes event provider
 	providerName = "Kubernetes"
+
+	// endpointSliceServiceIndex is the name of the index of the EndpointSlices by the <namespace>/<name> of their service
+	endpointSliceServiceIndex = "service"
 )
 
 // InformerKey stores the different Informers we keep for K8s resources
@@ -59,8 +64,14 @@ const (
 	Pods InformerKey = "Pods"
 	// Endpoints lookup identifier
 	Endpoints InformerKey = "Endpoints"
+	// EndpointSlices lookup identifier
+	EndpointSlices InformerKey = "EndpointSlices"
 	// ServiceAccounts lookup identifier
 	ServiceAccounts InformerKey = "ServiceAccounts"
+	// ExternalWorkloads lookup identifier
+	ExternalWorkloads InformerKey = "ExternalWorkloads"
+	// Secrets lookup identifier, only TLS secrets are monitored
+	Secrets InformerKey = "Secrets"
 )
 
 // informerCollection is the type holding the collection of informers we keep
@@ -68,10 +79,37 @@ type informerCollection map[InformerKey]cache.SharedIndexInformer
 
 // Client is a struct for all components necessary to connect to and maintain state of a Kubernetes cluster.
 type Client struct {
-	meshName     string
-	kubeClient   kubernetes.Interface
-	policyClient policyv1alpha1Client.Interface
-	informers    informerCollection
+	meshName             string
+	kubeClient           kubernetes.Interface
+	policyClient         policyv1alpha1Client.Interface
+	informers            informerCollection
+	resyncInterval       time.Duration
+	resyncIntervals      map[InformerKey]time.Duration
+	watchErrorBackoff    time.Duration
+	watchErrorMaxBackoff time.Duration
+	watchScope           WatchScope
+	stop                 <-chan struct{}
+}
+
+// ControllerOptions are the options of the informers of the Controller
+type ControllerOptions struct {
+	// ResyncInterval is the resync interval of the informers, DefaultKubeEventResyncInterval if zero
+	ResyncInterval time.Duration
+
+	// ResyncIntervals overrides ResyncInterval for individual informers
+	ResyncIntervals map[InformerKey]time.Duration
+
+	// WatchErrorBackoff is the initial delay the informers wait before relisting their resources after a watch error,
+	// in addition to the backoff of their reflector. It doubles with each consecutive watch error, up to
+	// WatchErrorMaxBackoff. No delay is added if zero.
+	WatchErrorBackoff time.Duration
+
+	// WatchErrorMaxBackoff is the maximum delay the informers wait before relisting after consecutive watch errors,
+	// not limited if zero
+	WatchErrorMaxBackoff time.Duration
+
+	// WatchScope restricts the namespaced resources watched by the informers, all of them if empty
+	WatchScope WatchScope
 }
 
 // Controller is the controller interface for K8s services
@@ -98,16 +136,31 @@ type Controller interface {
 	// ListPods returns a list of pods part of the mesh
 	ListPods() []*corev1.Pod
 
+	// ListExternalWorkloads returns a list of external workloads part of the mesh
+	ListExternalWorkloads() []*policyv1alpha1.ExternalWorkload
+
 	// ListServiceIdentitiesForService lists ServiceAccounts associated with the given service
 	ListServiceIdentitiesForService(svc service.MeshService) ([]identity.K8sServiceAccount, error)
 
 	// GetEndpoints returns the endpoints for a given service, if found
 	GetEndpoints(svc service.MeshService) (*corev1.Endpoints, error)
 
+	// ListEndpointSlicesForService returns the EndpointSlices of the given service
+	ListEndpointSlicesForService(svc service.MeshService) []*discoveryv1beta1.EndpointSlice
+
+	// GetPod returns the pod with the given namespace and name if found in cache, nil otherwise
+	GetPod(namespace, name string) *corev1.Pod
+
+	// GetSecret returns the TLS secret with the given namespace and name if found in cache, nil otherwise
+	GetSecret(namespace, name string) *corev1.Secret
+
 	// IsMetricsEnabled returns true if the pod in the mesh is correctly annotated for prometheus scrapping
 	IsMetricsEnabled(*corev1.Pod) bool
 
 	// UpdateStatus updates the status subresource for the given resource and GroupVersionKind
 	// The object within the 'interface{}' must be a pointer to the underlying resource
 	UpdateStatus(interface{}) (metav1.Object, error)
+
+	// HasSynced returns whether the caches of all the informers have synced
+	HasSynced() bool
 }
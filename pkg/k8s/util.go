@@ -2,6 +2,7 @@ package k8s
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	goversion "github.com/hashicorp/go-version"
@@ -16,10 +17,21 @@ import (
 
 const (
 	clusterDomain = "cluster.local"
+
+	// appProtocolH2C is the standard appProtocol value of ports serving HTTP/2 over cleartext
+	appProtocolH2C = "kubernetes.io/h2c"
+
+	// topologyAwareHintsAnnotation is the annotation enabling topology aware hints on the EndpointSlices of a service
+	topologyAwareHintsAnnotation = "service.kubernetes.io/topology-aware-hints"
+
+	// defaultHTTPPort is the port implied by a Host header that doesn't specify one
+	defaultHTTPPort = 80
 )
 
 // GetHostnamesForService returns a list of hostnames over which the service can be accessed within the local cluster.
 // If 'sameNamespace' is set to true, then the shorthand hostnames service and service:port are also returned.
+// The virtual IPs and load balancer hostnames of the service are also returned within the local cluster, see
+// getLoadBalancerHostnames.
 func GetHostnamesForService(svc *corev1.Service, locality service.Locality) []string {
 	var domains []string
 	if svc == nil {
@@ -51,9 +63,54 @@ func GetHostnamesForService(svc *corev1.Service, locality service.Locality) []st
 		domains = append(domains, fmt.Sprintf("%s.%s.svc.cluster:%d", serviceName, namespace, port))           // service.namespace.svc.cluster:port
 		domains = append(domains, fmt.Sprintf("%s.%s.svc.%s:%d", serviceName, namespace, clusterDomain, port)) // service.namespace.svc.cluster.local:port
 	}
+	if locality != service.RemoteCluster {
+		domains = append(domains, getLoadBalancerHostnames(svc)...)
+	}
 	return domains
 }
 
+// GetServiceVIPs returns the virtual IPs the given service is reachable on besides its cluster IP, i.e. its external
+// IPs and the IPs of its load balancer. In-mesh clients calling these IPs, e.g. through DNS names shared with clients
+// outside the cluster, must be proxied to the service like clients calling its cluster IP.
+// NodePort services are reachable on the IPs of the nodes, which aren't part of the service and aren't returned.
+func GetServiceVIPs(svc *corev1.Service) []string {
+	var vips []string
+	vips = append(vips, svc.Spec.ExternalIPs...)
+	if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			if ingress.IP != "" {
+				vips = append(vips, ingress.IP)
+			}
+		}
+	}
+	return vips
+}
+
+// getLoadBalancerHostnames returns the hostnames over which the given service is accessed through its virtual IPs or
+// the hostnames of its load balancer, qualified with the ports of the service. The unqualified hostnames are only
+// returned for port 80, since services sharing a virtual IP expose different ports.
+func getLoadBalancerHostnames(svc *corev1.Service) []string {
+	hosts := GetServiceVIPs(svc)
+	if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			if ingress.Hostname != "" {
+				hosts = append(hosts, ingress.Hostname)
+			}
+		}
+	}
+
+	var hostnames []string
+	for _, host := range hosts {
+		for _, portSpec := range svc.Spec.Ports {
+			if portSpec.Port == defaultHTTPPort {
+				hostnames = append(hostnames, host) // host
+			}
+			hostnames = append(hostnames, fmt.Sprintf("%s:%d", host, portSpec.Port)) // host:port
+		}
+	}
+	return hostnames
+}
+
 // GetServiceFromHostname returns the service name from its hostname
 func GetServiceFromHostname(host string) string {
 	// The service name is the first string in the host name for a service.
@@ -67,23 +124,99 @@ func GetServiceFromHostname(host string) string {
 
 // GetAppProtocolFromPortName returns the port's application protocol from its name, defaults to 'http' if not specified.
 func GetAppProtocolFromPortName(portName string) string {
+	if appProtocol, ok := getAppProtocolFromPortNamePrefix(portName); ok {
+		return appProtocol
+	}
+	return constants.ProtocolHTTP
+}
+
+// getAppProtocolFromPortNamePrefix returns the application protocol specified by the prefix of the port's name, and
+// whether the port's name specifies one
+func getAppProtocolFromPortNamePrefix(portName string) (string, bool) {
 	portName = strings.ToLower(portName)
 
 	switch {
 	case strings.HasPrefix(portName, "http-"):
-		return "http"
+		return "http", true
 
 	case strings.HasPrefix(portName, "tcp-"):
-		return "tcp"
+		return "tcp", true
 
 	case strings.HasPrefix(portName, "grpc-"):
-		return "grpc"
+		return "grpc", true
+
+	default:
+		return "", false
+	}
+}
+
+// GetAppProtocolFromServicePort returns the application protocol of the given service port.
+// The port's appProtocol field takes precedence over the protocol derived from the port's name, unless it is not
+// supported by the mesh.
+func GetAppProtocolFromServicePort(port corev1.ServicePort) string {
+	return getAppProtocol(port.AppProtocol, port.Name)
+}
+
+// GetAppProtocolFromEndpointPort returns the application protocol of the given endpoint port.
+// The port's appProtocol field takes precedence over the protocol derived from the port's name, unless it is not
+// supported by the mesh.
+func GetAppProtocolFromEndpointPort(port corev1.EndpointPort) string {
+	return getAppProtocol(port.AppProtocol, port.Name)
+}
+
+func getAppProtocol(appProtocol *string, portName string) string {
+	if appProtocol == nil || *appProtocol == "" {
+		return GetAppProtocolFromPortName(portName)
+	}
+
+	protocol, supported := normalizeAppProtocol(*appProtocol)
+	if !supported {
+		// Listeners have no filter chain for unsupported protocols, fall back to the protocol derived from the name
+		return GetAppProtocolFromPortName(portName)
+	}
+	return protocol
+}
+
+// normalizeAppProtocol returns the application protocol of the given appProtocol field value, and whether the mesh
+// supports it
+func normalizeAppProtocol(appProtocol string) (string, bool) {
+	protocol := strings.ToLower(strings.TrimSpace(appProtocol))
+	switch protocol {
+	case appProtocolH2C:
+		// HTTP/2 over cleartext is proxied by the HTTP filter chains
+		return constants.ProtocolHTTP, true
+
+	case constants.ProtocolHTTP, constants.ProtocolTCP, constants.ProtocolGRPC:
+		return protocol, true
 
 	default:
-		return constants.ProtocolHTTP
+		return protocol, false
 	}
 }
 
+// GetAppProtocolMismatches returns a description of each port of the given service whose appProtocol field is not
+// supported by the mesh, or disagrees with the application protocol derived from the port's name
+func GetAppProtocolMismatches(svc *corev1.Service) []string {
+	var mismatches []string
+	for _, port := range svc.Spec.Ports {
+		if port.AppProtocol == nil || *port.AppProtocol == "" {
+			continue
+		}
+		if _, supported := normalizeAppProtocol(*port.AppProtocol); !supported {
+			mismatches = append(mismatches, fmt.Sprintf("port %d (%s): unsupported appProtocol %s, using protocol %s", port.Port, port.Name, *port.AppProtocol, GetAppProtocolFromServicePort(port)))
+			continue
+		}
+		nameProtocol, ok := getAppProtocolFromPortNamePrefix(port.Name)
+		if !ok {
+			continue
+		}
+		if fieldProtocol := GetAppProtocolFromServicePort(port); fieldProtocol != nameProtocol {
+			mismatches = append(mismatches, fmt.Sprintf("port %d (%s): appProtocol %s, name-derived protocol %s", port.Port, port.Name, fieldProtocol, nameProtocol))
+		}
+	}
+	return mismatches
+}
+
 // GetKubernetesServerVersionNumber returns the Kubernetes server version number in chunks, ex. v1.19.3 => [1, 19, 3]
 func GetKubernetesServerVersionNumber(kubeClient kubernetes.Interface) ([]int, error) {
 	if kubeClient == nil {
@@ -103,6 +236,29 @@ func GetKubernetesServerVersionNumber(kubeClient kubernetes.Interface) ([]int, e
 	return ver.Segments(), nil
 }
 
+// openShiftSecurityGroup is the API group of the SecurityContextConstraints of OpenShift
+const openShiftSecurityGroup = "security.openshift.io"
+
+// IsOpenShift returns whether the Kubernetes cluster is an OpenShift cluster, whose API server serves the
+// SecurityContextConstraints API
+func IsOpenShift(kubeClient kubernetes.Interface) (bool, error) {
+	if kubeClient == nil {
+		return false, errors.Errorf("Kubernetes client is not initialized")
+	}
+
+	groups, err := kubeClient.Discovery().ServerGroups()
+	if err != nil {
+		return false, errors.Errorf("Error getting K8s server API groups: %s", err)
+	}
+
+	for _, group := range groups.Groups {
+		if group.Name == openShiftSecurityGroup {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // NamespacedNameFrom returns the namespaced name for the given name if possible, otherwise an error
 func NamespacedNameFrom(name string) (types.NamespacedName, error) {
 	var nsName types.NamespacedName
@@ -117,3 +273,49 @@ func NamespacedNameFrom(name string) (types.NamespacedName, error) {
 
 	return nsName, nil
 }
+
+// IsMulticlusterExported returns true if the given service is annotated to be exported to remote clusters
+func IsMulticlusterExported(svc *corev1.Service) bool {
+	export, _ := strconv.ParseBool(svc.Annotations[constants.MulticlusterExportAnnotation])
+	return export
+}
+
+// IsExposedOnNodes returns true if the given service is exposed on the nodes of the cluster, i.e. is a NodePort or
+// LoadBalancer service
+func IsExposedOnNodes(svc *corev1.Service) bool {
+	return svc.Spec.Type == corev1.ServiceTypeNodePort || svc.Spec.Type == corev1.ServiceTypeLoadBalancer
+}
+
+// PreservesClientSourceIP returns true if the given service is exposed on the nodes of the cluster and its external
+// traffic policy is Local, in which case external traffic is only forwarded to the endpoints local to the node
+// receiving it, without being SNATed, so the source IP of the clients is preserved
+func PreservesClientSourceIP(svc *corev1.Service) bool {
+	return IsExposedOnNodes(svc) && svc.Spec.ExternalTrafficPolicy == corev1.ServiceExternalTrafficPolicyTypeLocal
+}
+
+// PreservesInboundSourceIP returns true if the sidecar of the given pod was injected to proxy inbound traffic to the
+// pod's applications from the original source IP of the clients, which requires the responses of the applications
+// to be routed back to the sidecar
+func PreservesInboundSourceIP(pod *corev1.Pod) bool {
+	preserve, _ := strconv.ParseBool(pod.Annotations[constants.SourceIPPreservationAnnotation])
+	return preserve
+}
+
+// IsTopologyAware returns true if traffic to the given service is routed based on the topology of its endpoints, either
+// using topology aware hints or the deprecated topology keys
+func IsTopologyAware(svc *corev1.Service) bool {
+	return strings.EqualFold(svc.Annotations[topologyAwareHintsAnnotation], "auto") || len(svc.Spec.TopologyKeys) > 0
+}
+
+// GetNamedContainerPort returns the port of the given pod's containers with the given name, and whether the pod has one.
+// Kubernetes resolves the named target ports of services to the container ports of each pod with the same name.
+func GetNamedContainerPort(pod *corev1.Pod, portName string) (uint32, bool) {
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			if port.Name == portName {
+				return uint32(port.ContainerPort), true
+			}
+		}
+	}
+	return 0, false
+}
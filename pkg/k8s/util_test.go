@@ -6,12 +6,14 @@ import (
 
 	tassert "github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/version"
 	fakediscovery "k8s.io/client-go/discovery/fake"
 	"k8s.io/client-go/kubernetes"
 	fakeclient "k8s.io/client-go/kubernetes/fake"
 
+	"github.com/openservicemesh/osm/pkg/constants"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/tests"
 )
@@ -59,6 +61,46 @@ func TestGetHostnamesForService(t *testing.T) {
 				fmt.Sprintf("%s.%s.svc.cluster.local:%d", tests.BookbuyerServiceName, tests.Namespace, tests.ServicePort),
 			},
 		},
+		{
+			name: "hostnames corresponding to a LoadBalancer service",
+			service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "bookstore", Namespace: "bookstore-ns"},
+				Spec: corev1.ServiceSpec{
+					Type:        corev1.ServiceTypeLoadBalancer,
+					Ports:       []corev1.ServicePort{{Port: 80}, {Port: 8080}},
+					ExternalIPs: []string{"10.0.0.10"},
+				},
+				Status: corev1.ServiceStatus{
+					LoadBalancer: corev1.LoadBalancerStatus{
+						Ingress: []corev1.LoadBalancerIngress{{IP: "20.0.0.20"}, {Hostname: "bookstore.example.com"}},
+					},
+				},
+			},
+			locality: service.LocalCluster,
+			expectedHostnames: []string{
+				"bookstore.bookstore-ns",
+				"bookstore.bookstore-ns:80",
+				"bookstore.bookstore-ns:8080",
+				"bookstore.bookstore-ns.svc",
+				"bookstore.bookstore-ns.svc:80",
+				"bookstore.bookstore-ns.svc:8080",
+				"bookstore.bookstore-ns.svc.cluster",
+				"bookstore.bookstore-ns.svc.cluster:80",
+				"bookstore.bookstore-ns.svc.cluster:8080",
+				"bookstore.bookstore-ns.svc.cluster.local",
+				"bookstore.bookstore-ns.svc.cluster.local:80",
+				"bookstore.bookstore-ns.svc.cluster.local:8080",
+				"10.0.0.10",
+				"10.0.0.10:80",
+				"10.0.0.10:8080",
+				"20.0.0.20",
+				"20.0.0.20:80",
+				"20.0.0.20:8080",
+				"bookstore.example.com",
+				"bookstore.example.com:80",
+				"bookstore.example.com:8080",
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -146,6 +188,86 @@ func TestGetAppProtocolFromPortName(t *testing.T) {
 	}
 }
 
+func TestGetAppProtocolFromServicePort(t *testing.T) {
+	appProtocol := func(protocol string) *string {
+		return &protocol
+	}
+
+	testCases := []struct {
+		name             string
+		port             corev1.ServicePort
+		expectedProtocol string
+	}{
+		{
+			name:             "protocol derived from the port's name",
+			port:             corev1.ServicePort{Name: "tcp-port"},
+			expectedProtocol: "tcp",
+		},
+		{
+			name:             "default protocol",
+			port:             corev1.ServicePort{Name: "port"},
+			expectedProtocol: "http",
+		},
+		{
+			name:             "appProtocol takes precedence over the port's name",
+			port:             corev1.ServicePort{Name: "http-port", AppProtocol: appProtocol("TCP")},
+			expectedProtocol: "tcp",
+		},
+		{
+			name:             "empty appProtocol",
+			port:             corev1.ServicePort{Name: "grpc-port", AppProtocol: appProtocol("")},
+			expectedProtocol: "grpc",
+		},
+		{
+			name:             "standard h2c appProtocol",
+			port:             corev1.ServicePort{Name: "port", AppProtocol: appProtocol("kubernetes.io/h2c")},
+			expectedProtocol: "http",
+		},
+		{
+			name:             "unsupported appProtocol falls back to the port's name",
+			port:             corev1.ServicePort{Name: "tcp-port", AppProtocol: appProtocol("mysql")},
+			expectedProtocol: "tcp",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			assert.Equal(tc.expectedProtocol, GetAppProtocolFromServicePort(tc.port))
+			assert.Equal(tc.expectedProtocol, GetAppProtocolFromEndpointPort(corev1.EndpointPort{Name: tc.port.Name, AppProtocol: tc.port.AppProtocol}))
+		})
+	}
+}
+
+func TestGetAppProtocolMismatches(t *testing.T) {
+	assert := tassert.New(t)
+
+	tcp, http, mysql := "tcp", "HTTP", "mysql"
+	svc := &corev1.Service{
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				// Name-derived and field-derived protocols disagree
+				{Name: "http-port", Port: 80, AppProtocol: &tcp},
+				// Name-derived and field-derived protocols agree
+				{Name: "http-alt", Port: 8080, AppProtocol: &http},
+				// The name doesn't specify a protocol
+				{Name: "port", Port: 90, AppProtocol: &tcp},
+				// No appProtocol
+				{Name: "grpc-port", Port: 91},
+				// Unsupported appProtocol
+				{Name: "db", Port: 3306, AppProtocol: &mysql},
+			},
+		},
+	}
+
+	assert.Equal([]string{
+		"port 80 (http-port): appProtocol tcp, name-derived protocol http",
+		"port 3306 (db): unsupported appProtocol mysql, using protocol http",
+	}, GetAppProtocolMismatches(svc))
+	assert.Empty(GetAppProtocolMismatches(&corev1.Service{}))
+}
+
 func TestGetKubernetesServerVersionNumber(t *testing.T) {
 	testCases := []struct {
 		name            string
@@ -193,6 +315,51 @@ func TestGetKubernetesServerVersionNumber(t *testing.T) {
 	}
 }
 
+func TestIsOpenShift(t *testing.T) {
+	testCases := []struct {
+		name           string
+		kubeClient     kubernetes.Interface
+		groupVersions  []string
+		expectedResult bool
+		expectError    bool
+	}{
+		{
+			name:        "invalid kubeClient should error",
+			kubeClient:  nil,
+			expectError: true,
+		},
+		{
+			name:           "Kubernetes cluster",
+			kubeClient:     fakeclient.NewSimpleClientset(),
+			groupVersions:  []string{"v1", "apps/v1"},
+			expectedResult: false,
+		},
+		{
+			name:           "OpenShift cluster",
+			kubeClient:     fakeclient.NewSimpleClientset(),
+			groupVersions:  []string{"v1", "apps/v1", "security.openshift.io/v1"},
+			expectedResult: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			if tc.kubeClient != nil {
+				for _, groupVersion := range tc.groupVersions {
+					tc.kubeClient.Discovery().(*fakediscovery.FakeDiscovery).Resources = append(tc.kubeClient.Discovery().(*fakediscovery.FakeDiscovery).Resources,
+						&metav1.APIResourceList{GroupVersion: groupVersion})
+				}
+			}
+
+			actual, err := IsOpenShift(tc.kubeClient)
+			assert.Equal(tc.expectError, err != nil)
+			assert.Equal(tc.expectedResult, actual)
+		})
+	}
+}
+
 func TestNamespacedNameFrom(t *testing.T) {
 	testCases := []struct {
 		name      string
@@ -230,3 +397,115 @@ func TestNamespacedNameFrom(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceTopology(t *testing.T) {
+	testCases := []struct {
+		name                    string
+		service                 *corev1.Service
+		expectedExposedOnNodes  bool
+		expectedPreservesSrcIP  bool
+		expectedIsTopologyAware bool
+	}{
+		{
+			name:    "ClusterIP service",
+			service: &corev1.Service{},
+		},
+		{
+			name: "ClusterIP service with topology aware hints",
+			service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{topologyAwareHintsAnnotation: "Auto"}},
+			},
+			expectedIsTopologyAware: true,
+		},
+		{
+			name: "NodePort service with Cluster external traffic policy",
+			service: &corev1.Service{
+				Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort, ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeCluster},
+			},
+			expectedExposedOnNodes: true,
+		},
+		{
+			name: "LoadBalancer service with Local external traffic policy and topology keys",
+			service: &corev1.Service{
+				Spec: corev1.ServiceSpec{
+					Type:                  corev1.ServiceTypeLoadBalancer,
+					ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+					TopologyKeys:          []string{corev1.LabelTopologyZone, "*"},
+				},
+			},
+			expectedExposedOnNodes:  true,
+			expectedPreservesSrcIP:  true,
+			expectedIsTopologyAware: true,
+		},
+		{
+			name: "topology aware hints disabled",
+			service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{topologyAwareHintsAnnotation: "disabled"}},
+				Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+			},
+			expectedExposedOnNodes: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			assert.Equal(tc.expectedExposedOnNodes, IsExposedOnNodes(tc.service))
+			assert.Equal(tc.expectedPreservesSrcIP, PreservesClientSourceIP(tc.service))
+			assert.Equal(tc.expectedIsTopologyAware, IsTopologyAware(tc.service))
+		})
+	}
+}
+
+func TestGetNamedContainerPort(t *testing.T) {
+	assert := tassert.New(t)
+
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Ports: []corev1.ContainerPort{{Name: "metrics", ContainerPort: 9102}}},
+				{Ports: []corev1.ContainerPort{{ContainerPort: 8000}, {Name: "web", ContainerPort: 8081}}},
+			},
+		},
+	}
+
+	port, ok := GetNamedContainerPort(pod, "web")
+	assert.True(ok)
+	assert.Equal(uint32(8081), port)
+
+	_, ok = GetNamedContainerPort(pod, "grpc")
+	assert.False(ok)
+}
+
+func TestPreservesInboundSourceIP(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.False(PreservesInboundSourceIP(&corev1.Pod{}))
+	assert.False(PreservesInboundSourceIP(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{constants.SourceIPPreservationAnnotation: "false"}},
+	}))
+	assert.True(PreservesInboundSourceIP(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{constants.SourceIPPreservationAnnotation: "true"}},
+	}))
+}
+
+func TestGetServiceVIPs(t *testing.T) {
+	assert := tassert.New(t)
+
+	status := corev1.ServiceStatus{
+		LoadBalancer: corev1.LoadBalancerStatus{
+			Ingress: []corev1.LoadBalancerIngress{{IP: "20.0.0.20"}, {Hostname: "bookstore.example.com"}},
+		},
+	}
+
+	lbService := &corev1.Service{
+		Spec:   corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer, ExternalIPs: []string{"10.0.0.10"}},
+		Status: status,
+	}
+	assert.Equal([]string{"10.0.0.10", "20.0.0.20"}, GetServiceVIPs(lbService))
+
+	// The load balancer status of a service that is no longer a LoadBalancer service is stale
+	clusterIPService := &corev1.Service{Status: status}
+	assert.Empty(GetServiceVIPs(clusterIPService))
+}
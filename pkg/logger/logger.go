@@ -29,7 +29,7 @@ func (h CallerHook) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
 }
 
 func newLogger(component string) zerolog.Logger {
-	return log.With().Str("component", component).Logger().Hook(CallerHook{})
+	return log.With().Str("component", component).Logger().Hook(CallerHook{}).Hook(recentLogHook{component: component})
 }
 
 // New creates a new zerolog.Logger
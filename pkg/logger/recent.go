@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// recentLogsSize is the number of log messages retained by the recent logs buffer
+	recentLogsSize = 1000
+)
+
+// RecentLog is a log message recently emitted by an OSM component.
+type RecentLog struct {
+	// Time is the time the message was emitted
+	Time time.Time `json:"time"`
+
+	// Level is the level of the message
+	Level string `json:"level"`
+
+	// Component is the component which emitted the message
+	Component string `json:"component"`
+
+	// Message is the message
+	Message string `json:"message"`
+}
+
+// recentLogBuffer is a ring buffer retaining the last log messages emitted by the process
+type recentLogBuffer struct {
+	mutex   sync.Mutex
+	entries []RecentLog
+	next    int
+	full    bool
+}
+
+var recentLogs = newRecentLogBuffer(recentLogsSize)
+
+func newRecentLogBuffer(size int) *recentLogBuffer {
+	return &recentLogBuffer{
+		entries: make([]RecentLog, size),
+	}
+}
+
+func (b *recentLogBuffer) add(entry RecentLog) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.entries[b.next] = entry
+	b.next = (b.next + 1) % len(b.entries)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+func (b *recentLogBuffer) list() []RecentLog {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if !b.full {
+		return append([]RecentLog{}, b.entries[:b.next]...)
+	}
+	return append(append([]RecentLog{}, b.entries[b.next:]...), b.entries[:b.next]...)
+}
+
+// recentLogHook implements zerolog.Hook interface, recording the messages of a component in the recent logs buffer.
+type recentLogHook struct {
+	component string
+}
+
+// Run records the message in the recent logs buffer
+func (h recentLogHook) Run(_ *zerolog.Event, level zerolog.Level, msg string) {
+	recentLogs.add(RecentLog{
+		Time:      time.Now(),
+		Level:     level.String(),
+		Component: h.component,
+		Message:   msg,
+	})
+}
+
+// ListRecentLogs returns the last log messages emitted by the OSM components of the process, oldest first.
+// Only the messages enabled by the global logging level are retained.
+func ListRecentLogs() []RecentLog {
+	return recentLogs.list()
+}
@@ -0,0 +1,37 @@
+package logger
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestRecentLogBuffer(t *testing.T) {
+	assert := tassert.New(t)
+
+	buffer := newRecentLogBuffer(3)
+	assert.Empty(buffer.list())
+
+	buffer.add(RecentLog{Message: "1"})
+	buffer.add(RecentLog{Message: "2"})
+	assert.Equal([]RecentLog{{Message: "1"}, {Message: "2"}}, buffer.list())
+
+	// The oldest messages are evicted once the buffer is full
+	buffer.add(RecentLog{Message: "3"})
+	buffer.add(RecentLog{Message: "4"})
+	assert.Equal([]RecentLog{{Message: "2"}, {Message: "3"}, {Message: "4"}}, buffer.list())
+}
+
+func TestListRecentLogs(t *testing.T) {
+	assert := tassert.New(t)
+
+	log := New("logger-test")
+	log.Error().Msg("recent log message")
+
+	logs := ListRecentLogs()
+	assert.NotEmpty(logs)
+	last := logs[len(logs)-1]
+	assert.Equal("logger-test", last.Component)
+	assert.Equal("error", last.Level)
+	assert.Equal("recent log message", last.Message)
+}
@@ -0,0 +1,332 @@
+package meshmetrics
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+const (
+	// The Envoy metrics the summary is aggregated from, along with the labels of their tags
+	requestsMetric          = "envoy_cluster_upstream_rq_xx"
+	requestTimeMetric       = "envoy_cluster_upstream_rq_time"
+	activeConnectionsMetric = "envoy_cluster_upstream_cx_active"
+	totalConnectionsMetric  = "envoy_cluster_upstream_cx_total"
+	clusterNameLabel        = "envoy_cluster_name"
+	responseCodeClassLabel  = "envoy_response_code_class"
+
+	// localClusterSuffix is the suffix of the local clusters proxying the inbound traffic of a service to its app
+	localClusterSuffix = "-local"
+)
+
+// NewAggregator returns an Aggregator scraping the proxy sidecars of the pods known to the given controller, with the
+// certificate issued by the given certificate manager for the Prometheus client identity when the metrics are
+// served over mTLS.
+func NewAggregator(kubeController k8s.Controller, cfg configurator.Configurator, certManager certificate.Manager) *Aggregator {
+	return &Aggregator{
+		kubeController: kubeController,
+		cfg:            cfg,
+		certManager:    certManager,
+		metricsPort:    constants.EnvoyPrometheusInboundListenerPort,
+	}
+}
+
+// podMetrics is the type used to represent the metrics of the inbound traffic of a service scraped from a single
+// proxy sidecar
+type podMetrics struct {
+	requests          uint64
+	failures          uint64
+	activeConnections uint64
+	totalConnections  uint64
+	// latencyBuckets maps the upper bounds of the buckets of the request time histogram, in milliseconds, to their
+	// cumulative counts
+	latencyBuckets map[float64]uint64
+}
+
+// Summarize returns the metrics of the inbound traffic of the given service, aggregated across the proxy sidecars of
+// its ready pods enabled for metrics.
+func (a *Aggregator) Summarize(svc service.MeshService) (*ServiceSummary, error) {
+	if a.kubeController.GetService(svc) == nil {
+		return nil, errors.Errorf("Service %s was not found", svc)
+	}
+	pods, errs := a.listPods(svc)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	scraped := make([]*podMetrics, 0, len(pods))
+	sem := make(chan struct{}, maxConcurrentScrapes)
+	for _, pod := range pods {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(pod *corev1.Pod) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			metrics, err := a.scrape(pod, svc)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Debug().Err(err).Msgf("Error scraping the proxy sidecar of pod %s/%s", pod.Namespace, pod.Name)
+				errs = append(errs, fmt.Sprintf("Pod %s/%s: %s", pod.Namespace, pod.Name, err))
+				return
+			}
+			scraped = append(scraped, metrics)
+		}(pod)
+	}
+	wg.Wait()
+
+	sort.Strings(errs)
+	summary := aggregate(scraped)
+	summary.Service = svc.String()
+	summary.Errors = errs
+	return summary, nil
+}
+
+// listPods returns the ready pods of the given service enabled for metrics, along with the reasons the other ready
+// pods of the service are not scraped
+func (a *Aggregator) listPods(svc service.MeshService) ([]*corev1.Pod, []string) {
+	endpoints, err := a.kubeController.GetEndpoints(svc)
+	if err != nil || endpoints == nil {
+		return nil, nil
+	}
+
+	var pods []*corev1.Pod
+	var errs []string
+	for _, subset := range endpoints.Subsets {
+		for _, address := range subset.Addresses {
+			if address.TargetRef == nil || address.TargetRef.Kind != "Pod" {
+				continue
+			}
+			pod := a.kubeController.GetPod(address.TargetRef.Namespace, address.TargetRef.Name)
+			if pod == nil {
+				continue
+			}
+			if !a.kubeController.IsMetricsEnabled(pod) {
+				errs = append(errs, fmt.Sprintf("Pod %s/%s: metrics are not enabled, enable them with 'osm metrics enable --namespace %s'", pod.Namespace, pod.Name, pod.Namespace))
+				continue
+			}
+			pods = append(pods, pod)
+		}
+	}
+	return pods, errs
+}
+
+// scrape returns the metrics of the inbound traffic of the given service scraped from the proxy sidecar of the given pod
+func (a *Aggregator) scrape(pod *corev1.Pod, svc service.MeshService) (*podMetrics, error) {
+	if pod.Status.PodIP == "" {
+		return nil, errors.New("pod has no IP")
+	}
+	client, scheme, err := a.getHTTPClient(pod)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), scrapeTimeout)
+	defer cancel()
+	url := fmt.Sprintf("%s://%s%s", scheme, net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(a.metricsPort)), constants.PrometheusScrapePath)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint: errcheck,gosec
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("scraping %s failed with status code %d", url, resp.StatusCode)
+	}
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error parsing the metrics scraped from %s", url)
+	}
+	return getPodMetrics(families, svc), nil
+}
+
+// getHTTPClient returns the HTTP client and the scheme the proxy sidecar of the given pod is scraped with. The proxy
+// sidecar is scraped with the certificate of the Prometheus client identity when its metrics are served over mTLS,
+// verifying it presents the certificate of the service identity of the pod.
+func (a *Aggregator) getHTTPClient(pod *corev1.Pod) (*http.Client, string, error) {
+	scraping := a.cfg.GetPrometheusScrapingConfig()
+	if !scraping.EnableMTLS {
+		return &http.Client{Timeout: scrapeTimeout}, "http", nil
+	}
+
+	cn := certificate.CommonName(fmt.Sprintf("%s.%s", scraping.ClientIdentity, identity.ClusterLocalTrustDomain))
+	cert, err := a.certManager.IssueCertificate(cn, a.cfg.GetServiceCertValidityPeriod())
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "Error issuing a certificate for Prometheus client identity %s", scraping.ClientIdentity)
+	}
+	clientCert, err := tls.X509KeyPair(cert.GetCertificateChain(), cert.GetPrivateKey())
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "Error loading the certificate of Prometheus client identity %s", scraping.ClientIdentity)
+	}
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(cert.GetIssuingCA()) {
+		return nil, "", errors.New("Error loading the issuing CA of the certificate of the Prometheus client identity")
+	}
+
+	serverIdentity := identity.K8sServiceAccount{Name: pod.Spec.ServiceAccountName, Namespace: pod.Namespace}.ToServiceIdentity()
+	return &http.Client{
+		Timeout: scrapeTimeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				Certificates: []tls.Certificate{clientCert},
+				RootCAs:      rootCAs,
+				ServerName:   serverIdentity.String(),
+				MinVersion:   tls.VersionTLS12,
+			},
+		},
+	}, "https", nil
+}
+
+// getPodMetrics returns the metrics of the local clusters of the given service among the given metric families
+func getPodMetrics(families map[string]*dto.MetricFamily, svc service.MeshService) *podMetrics {
+	metrics := &podMetrics{latencyBuckets: map[float64]uint64{}}
+	forEachLocalClusterMetric(families[requestsMetric], svc, func(m *dto.Metric) {
+		count := uint64(m.GetCounter().GetValue())
+		metrics.requests += count
+		if getLabel(m, responseCodeClassLabel) == "5" {
+			metrics.failures += count
+		}
+	})
+	forEachLocalClusterMetric(families[activeConnectionsMetric], svc, func(m *dto.Metric) {
+		metrics.activeConnections += uint64(m.GetGauge().GetValue())
+	})
+	forEachLocalClusterMetric(families[totalConnectionsMetric], svc, func(m *dto.Metric) {
+		metrics.totalConnections += uint64(m.GetCounter().GetValue())
+	})
+	forEachLocalClusterMetric(families[requestTimeMetric], svc, func(m *dto.Metric) {
+		for _, bucket := range m.GetHistogram().GetBucket() {
+			// The +Inf bucket counts all the samples of the histogram
+			if !math.IsInf(bucket.GetUpperBound(), 1) {
+				metrics.latencyBuckets[bucket.GetUpperBound()] += bucket.GetCumulativeCount()
+			}
+		}
+		metrics.latencyBuckets[math.Inf(1)] += m.GetHistogram().GetSampleCount()
+	})
+	return metrics
+}
+
+// forEachLocalClusterMetric calls the given function with the metrics of the given family tagged with a local
+// cluster of the given service
+func forEachLocalClusterMetric(family *dto.MetricFamily, svc service.MeshService, fn func(*dto.Metric)) {
+	for _, m := range family.GetMetric() {
+		if isLocalClusterOf(getLabel(m, clusterNameLabel), svc) {
+			fn(m)
+		}
+	}
+}
+
+// isLocalClusterOf returns whether the given cluster is a local cluster of the given service: the single local
+// cluster of a service exposing a single port, or the local cluster of a port of a service exposing multiple ports
+func isLocalClusterOf(clusterName string, svc service.MeshService) bool {
+	if clusterName == envoy.GetLocalClusterNameForService(svc) {
+		return true
+	}
+	if !strings.HasSuffix(clusterName, localClusterSuffix) {
+		return false
+	}
+	clusterSvc, _, err := service.ParseClusterName(strings.TrimSuffix(clusterName, localClusterSuffix))
+	return err == nil && clusterSvc == svc
+}
+
+// getLabel returns the value of the given label of the given metric
+func getLabel(m *dto.Metric, name string) string {
+	for _, label := range m.GetLabel() {
+		if label.GetName() == name {
+			return label.GetValue()
+		}
+	}
+	return ""
+}
+
+// aggregate returns the summary of the given metrics scraped from the proxy sidecars of the pods of a service
+func aggregate(scraped []*podMetrics) *ServiceSummary {
+	summary := &ServiceSummary{
+		Pods:        len(scraped),
+		SuccessRate: 100,
+	}
+	var failures uint64
+	latencyBuckets := map[float64]uint64{}
+	for _, metrics := range scraped {
+		summary.Requests += metrics.requests
+		failures += metrics.failures
+		summary.ActiveConnections += metrics.activeConnections
+		summary.TotalConnections += metrics.totalConnections
+		for upperBound, count := range metrics.latencyBuckets {
+			latencyBuckets[upperBound] += count
+		}
+	}
+	if summary.Requests > 0 {
+		summary.SuccessRate = 100 * float64(summary.Requests-failures) / float64(summary.Requests)
+	}
+	summary.LatencyP50 = getQuantile(0.5, latencyBuckets)
+	summary.LatencyP99 = getQuantile(0.99, latencyBuckets)
+	return summary
+}
+
+// getQuantile returns the given quantile of the histogram with the given buckets, mapping their upper bounds in
+// milliseconds to their cumulative counts. As with the histogram_quantile function of Prometheus, the quantile is
+// interpolated linearly within its bucket, and is the upper bound of the last finite bucket if it falls in the +Inf
+// bucket.
+func getQuantile(q float64, buckets map[float64]uint64) time.Duration {
+	upperBounds := make([]float64, 0, len(buckets))
+	for upperBound := range buckets {
+		upperBounds = append(upperBounds, upperBound)
+	}
+	sort.Float64s(upperBounds)
+	if len(upperBounds) == 0 || buckets[upperBounds[len(upperBounds)-1]] == 0 {
+		return 0
+	}
+
+	rank := q * float64(buckets[upperBounds[len(upperBounds)-1]])
+	var lowerBound float64
+	var lowerCount uint64
+	for _, upperBound := range upperBounds {
+		count := buckets[upperBound]
+		if float64(count) < rank {
+			lowerBound, lowerCount = upperBound, count
+			continue
+		}
+		if math.IsInf(upperBound, 1) {
+			return milliseconds(lowerBound)
+		}
+		if count == lowerCount {
+			return milliseconds(upperBound)
+		}
+		return milliseconds(lowerBound + (upperBound-lowerBound)*(rank-float64(lowerCount))/float64(count-lowerCount))
+	}
+	return milliseconds(lowerBound)
+}
+
+// milliseconds returns the duration of the given number of milliseconds
+func milliseconds(ms float64) time.Duration {
+	return time.Duration(ms * float64(time.Millisecond))
+}
@@ -0,0 +1,198 @@
+package meshmetrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// podStats formats the stats of the local cluster of the bookstore service and of an outbound cluster, as served by
+// the metrics endpoint of a proxy sidecar
+func podStats(rq2xx, rq5xx, cxActive, cxTotal int, latencyBuckets [3]int) string {
+	return fmt.Sprintf(`# TYPE envoy_cluster_upstream_rq_xx counter
+envoy_cluster_upstream_rq_xx{envoy_response_code_class="2",envoy_cluster_name="bookstore/bookstore-local"} %d
+envoy_cluster_upstream_rq_xx{envoy_response_code_class="5",envoy_cluster_name="bookstore/bookstore-local"} %d
+envoy_cluster_upstream_rq_xx{envoy_response_code_class="2",envoy_cluster_name="bookwarehouse/bookwarehouse"} 1000
+# TYPE envoy_cluster_upstream_cx_active gauge
+envoy_cluster_upstream_cx_active{envoy_cluster_name="bookstore/bookstore-local"} %d
+envoy_cluster_upstream_cx_active{envoy_cluster_name="bookwarehouse/bookwarehouse"} 1000
+# TYPE envoy_cluster_upstream_cx_total counter
+envoy_cluster_upstream_cx_total{envoy_cluster_name="bookstore/bookstore-local"} %d
+# TYPE envoy_cluster_upstream_rq_time histogram
+envoy_cluster_upstream_rq_time_bucket{envoy_cluster_name="bookstore/bookstore-local",le="10"} %d
+envoy_cluster_upstream_rq_time_bucket{envoy_cluster_name="bookstore/bookstore-local",le="100"} %d
+envoy_cluster_upstream_rq_time_bucket{envoy_cluster_name="bookstore/bookstore-local",le="+Inf"} %d
+envoy_cluster_upstream_rq_time_sum{envoy_cluster_name="bookstore/bookstore-local"} 0
+envoy_cluster_upstream_rq_time_count{envoy_cluster_name="bookstore/bookstore-local"} %d
+`, rq2xx, rq5xx, cxActive, cxTotal, latencyBuckets[0], latencyBuckets[1], latencyBuckets[2], latencyBuckets[2])
+}
+
+func TestSummarize(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	svc := service.MeshService{Namespace: "bookstore", Name: "bookstore"}
+	// The pods share the address of the test server, which serves the stats of a pod to each scrape in turn
+	stats := []string{
+		podStats(90, 0, 2, 10, [3]int{40, 90, 90}),
+		podStats(0, 10, 1, 5, [3]int{0, 0, 10}),
+	}
+	var mu sync.Mutex
+	var requestedPaths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = fmt.Fprint(w, stats[len(requestedPaths)%len(stats)])
+		requestedPaths = append(requestedPaths, req.URL.Path)
+	}))
+	defer server.Close()
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	assert.Nil(err)
+	metricsPort, err := strconv.Atoi(port)
+	assert.Nil(err)
+
+	newPod := func(name string, metricsEnabled bool) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "bookstore", Name: name},
+			Status:     corev1.PodStatus{PodIP: "127.0.0.1"},
+		}
+		if metricsEnabled {
+			pod.Annotations = map[string]string{constants.PrometheusScrapeAnnotation: "true"}
+		}
+		return pod
+	}
+	pods := map[string]*corev1.Pod{
+		"bookstore-1": newPod("bookstore-1", true),
+		"bookstore-2": newPod("bookstore-2", true),
+		"bookstore-3": newPod("bookstore-3", false),
+	}
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().GetService(svc).Return(&corev1.Service{}).AnyTimes()
+	mockKubeController.EXPECT().GetService(gomock.Any()).Return(nil).AnyTimes()
+	mockKubeController.EXPECT().GetEndpoints(svc).Return(&corev1.Endpoints{
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{
+				{IP: "10.0.0.1", TargetRef: &corev1.ObjectReference{Kind: "Pod", Namespace: "bookstore", Name: "bookstore-1"}},
+				{IP: "10.0.0.2", TargetRef: &corev1.ObjectReference{Kind: "Pod", Namespace: "bookstore", Name: "bookstore-2"}},
+				{IP: "10.0.0.3", TargetRef: &corev1.ObjectReference{Kind: "Pod", Namespace: "bookstore", Name: "bookstore-3"}},
+			},
+		}},
+	}, nil).AnyTimes()
+	mockKubeController.EXPECT().GetPod("bookstore", gomock.Any()).DoAndReturn(func(_, name string) *corev1.Pod {
+		return pods[name]
+	}).AnyTimes()
+	mockKubeController.EXPECT().IsMetricsEnabled(gomock.Any()).DoAndReturn(func(pod *corev1.Pod) bool {
+		return pod.Annotations[constants.PrometheusScrapeAnnotation] == "true"
+	}).AnyTimes()
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().GetPrometheusScrapingConfig().Return(configv1alpha1.PrometheusScrapingSpec{}).AnyTimes()
+
+	a := NewAggregator(mockKubeController, mockConfigurator, nil)
+	a.metricsPort = metricsPort
+
+	summary, err := a.Summarize(svc)
+	assert.Nil(err)
+	assert.Equal(2, summary.Pods)
+	assert.Equal("bookstore/bookstore", summary.Service)
+	assert.Equal([]string{"Pod bookstore/bookstore-3: metrics are not enabled, enable them with 'osm metrics enable --namespace bookstore'"}, summary.Errors)
+	assert.Equal(uint64(100), summary.Requests)
+	assert.Equal(90.0, summary.SuccessRate)
+	assert.Equal(uint64(3), summary.ActiveConnections)
+	assert.Equal(uint64(15), summary.TotalConnections)
+	// 40 of the 100 requests took at most 10ms and 90 at most 100ms, the 50th is interpolated within (10ms, 100ms]
+	assert.InDelta(float64(28*time.Millisecond), float64(summary.LatencyP50), float64(time.Microsecond))
+	assert.Equal(100*time.Millisecond, summary.LatencyP99)
+	assert.Equal([]string{constants.PrometheusScrapePath, constants.PrometheusScrapePath}, requestedPaths)
+
+	// The summary of a service that does not exist is an error
+	_, err = a.Summarize(service.MeshService{Namespace: "bookstore", Name: "unknown"})
+	assert.NotNil(err)
+
+	// The handler returns the summary in JSON
+	requestedPaths = nil
+	recorder := httptest.NewRecorder()
+	a.GetSummaryHTTPHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics-summary?namespace=bookstore&service=bookstore", nil))
+	assert.Equal(http.StatusOK, recorder.Code)
+	var handlerSummary ServiceSummary
+	assert.Nil(json.Unmarshal(recorder.Body.Bytes(), &handlerSummary))
+	assert.Equal(*summary, handlerSummary)
+
+	recorder = httptest.NewRecorder()
+	a.GetSummaryHTTPHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics-summary?namespace=bookstore", nil))
+	assert.Equal(http.StatusBadRequest, recorder.Code)
+}
+
+func TestIsLocalClusterOf(t *testing.T) {
+	svc := service.MeshService{Namespace: "bookstore", Name: "bookstore"}
+
+	testCases := []struct {
+		clusterName string
+		expected    bool
+	}{
+		{clusterName: "bookstore/bookstore-local", expected: true},
+		{clusterName: "bookstore/bookstore|8080-local", expected: true},
+		{clusterName: "bookstore/bookstore", expected: false},
+		{clusterName: "bookstore/bookstore-v1-local", expected: false},
+		{clusterName: "bookstore/bookstore|http-local", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.clusterName, func(t *testing.T) {
+			tassert.Equal(t, tc.expected, isLocalClusterOf(tc.clusterName, svc))
+		})
+	}
+}
+
+func TestGetQuantile(t *testing.T) {
+	testCases := []struct {
+		name     string
+		q        float64
+		buckets  map[float64]uint64
+		expected time.Duration
+	}{
+		{
+			name:     "no samples",
+			q:        0.5,
+			buckets:  map[float64]uint64{10: 0, math.Inf(1): 0},
+			expected: 0,
+		},
+		{
+			name:     "quantile in the first bucket",
+			q:        0.5,
+			buckets:  map[float64]uint64{10: 10, 100: 10, math.Inf(1): 10},
+			expected: 5 * time.Millisecond,
+		},
+		{
+			name:     "quantile in the +Inf bucket",
+			q:        0.99,
+			buckets:  map[float64]uint64{10: 10, 100: 10, math.Inf(1): 20},
+			expected: 100 * time.Millisecond,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tassert.Equal(t, tc.expected, getQuantile(tc.q, tc.buckets))
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package meshmetrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// GetSummaryHTTPHandler returns an HTTP handler returning in JSON the metrics of the inbound traffic of the service
+// identified by the 'namespace' and 'service' query parameters, aggregated across the proxy sidecars of its pods.
+func (a *Aggregator) GetSummaryHTTPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		svc := service.MeshService{
+			Namespace: query.Get(namespaceQueryKey),
+			Name:      query.Get(serviceQueryKey),
+		}
+		if svc.Namespace == "" || svc.Name == "" {
+			http.Error(w, fmt.Sprintf("The %s and %s query parameters are required", namespaceQueryKey, serviceQueryKey), http.StatusBadRequest)
+			return
+		}
+
+		summary, err := a.Summarize(svc)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		jsonSummary, err := json.Marshal(summary)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error marshaling metrics summary %+v", summary)
+			http.Error(w, "Error marshaling metrics summary", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, string(jsonSummary))
+	})
+}
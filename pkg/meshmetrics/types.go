@@ -0,0 +1,67 @@
+// Package meshmetrics implements the aggregated metrics API of the mesh, which scrapes the metrics endpoint of the
+// proxy sidecars of a service and aggregates the metrics of the inbound traffic of the service across its pods.
+package meshmetrics
+
+import (
+	"time"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var (
+	log = logger.New("mesh-metrics")
+)
+
+const (
+	// namespaceQueryKey and serviceQueryKey are the query parameters of the summary handler identifying the service
+	namespaceQueryKey = "namespace"
+	serviceQueryKey   = "service"
+
+	// scrapeTimeout is the timeout of the requests to the metrics endpoint of a proxy sidecar
+	scrapeTimeout = 5 * time.Second
+
+	// maxConcurrentScrapes is the maximum number of proxy sidecars scraped concurrently for a summary
+	maxConcurrentScrapes = 10
+)
+
+// ServiceSummary is the type used to represent the metrics of the inbound traffic of a service, aggregated across
+// the proxy sidecars of its pods. The counters are cumulative since the proxy sidecars started.
+type ServiceSummary struct {
+	// Service is the namespaced name of the service
+	Service string `json:"service"`
+
+	// Pods is the number of pods of the service whose proxy sidecar was scraped
+	Pods int `json:"pods"`
+
+	// Errors are the reasons the proxy sidecars of the other ready pods of the service could not be scraped
+	Errors []string `json:"errors,omitempty"`
+
+	// Requests is the number of requests to the service
+	Requests uint64 `json:"requests"`
+
+	// SuccessRate is the percentage of the requests to the service which did not fail with a 5xx response
+	SuccessRate float64 `json:"successRate"`
+
+	// LatencyP50 and LatencyP99 are the 50th and 99th percentile latencies of the requests to the service
+	LatencyP50 time.Duration `json:"latencyP50"`
+	LatencyP99 time.Duration `json:"latencyP99"`
+
+	// ActiveConnections is the number of connections to the service currently open
+	ActiveConnections uint64 `json:"activeConnections"`
+
+	// TotalConnections is the number of connections to the service opened
+	TotalConnections uint64 `json:"totalConnections"`
+}
+
+// Aggregator aggregates the metrics of the proxy sidecars of the services in the mesh.
+type Aggregator struct {
+	kubeController k8s.Controller
+	cfg            configurator.Configurator
+	certManager    certificate.Manager
+
+	// metricsPort is the port of the metrics endpoint of the proxy sidecars
+	metricsPort int
+}
@@ -2,10 +2,13 @@
 package metricsstore
 
 import (
+	"io"
 	"net/http"
+	"runtime"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/common/expfmt"
 )
 
 // metricsRootNamespace is the root namespace for all the metrics emitted.
@@ -22,6 +25,23 @@ type MetricsStore struct {
 	// K8sAPIEventCounter is the metric counter for the number of K8s API events
 	K8sAPIEventCounter *prometheus.CounterVec
 
+	// K8sInformerResyncPeriod is the metric for the resync period of each Kubernetes informer
+	K8sInformerResyncPeriod *prometheus.GaugeVec
+
+	// K8sInformerWatchErrorCount is the metric counter for the number of watch errors of each Kubernetes informer
+	K8sInformerWatchErrorCount *prometheus.CounterVec
+
+	// K8sInformerRelistCount is the metric counter for the number of times each Kubernetes informer relisted its
+	// resources after its initial list
+	K8sInformerRelistCount *prometheus.CounterVec
+
+	// K8sInformerLastSyncTime is the metric for the time at which each Kubernetes informer last heard from the
+	// Kubernetes API server, to detect stale caches
+	K8sInformerLastSyncTime *prometheus.GaugeVec
+
+	// K8sInformerCacheSize is the metric for the number of objects in the cache of each Kubernetes informer
+	K8sInformerCacheSize *prometheus.GaugeVec
+
 	/*
 	 * Proxy metrics
 	 */
@@ -37,6 +57,67 @@ type MetricsStore struct {
 	// ProxyBroadcastEventCounter is the metric for the total number of ProxyBroadcast events published
 	ProxyBroadcastEventCount prometheus.Counter
 
+	// ProxyUnchangedConfigCount is the metric counter for the number of proxy config updates skipped because the
+	// generated resources were identical to the ones last sent
+	ProxyUnchangedConfigCount *prometheus.CounterVec
+
+	// ProxyCertRotationPushTime is the histogram to track the time between the rotation of the certificate of a proxy
+	// and the push of the rotated certificate to the proxy
+	ProxyCertRotationPushTime prometheus.Histogram
+
+	// ProxyStaleCertCount is the metric for the number of connected proxies whose certificate was rotated but not yet
+	// pushed to them, i.e. which are running on stale certificates
+	ProxyStaleCertCount prometheus.Gauge
+
+	// ProxyDesyncCount is the metric for the number of connected proxies that have not acknowledged the config of each
+	// type sent to them for longer than the desync threshold
+	ProxyDesyncCount *prometheus.GaugeVec
+
+	// ProxyDesyncRepushCount is the metric counter for the number of times the config of each type was pushed again to
+	// desynced proxies
+	ProxyDesyncRepushCount *prometheus.CounterVec
+
+	// ProxyRestartCount is the metric counter for the number of restarts of the Envoy proxy of each pod detected by
+	// the controller
+	ProxyRestartCount *prometheus.CounterVec
+
+	// ProxyRegistryReclaimedCount is the metric counter for the number of proxy registry entries of each kind
+	// reclaimed for the proxies that disconnected and whose pods no longer exist
+	ProxyRegistryReclaimedCount *prometheus.CounterVec
+
+	// RBACDenialCount is the metric counter for the number of inbound requests denied by the RBAC policies of the
+	// proxies of each service
+	RBACDenialCount *prometheus.CounterVec
+
+	// RBACPolicyPrincipalCount is the histogram of the number of principal rules of the RBAC policies generated for
+	// the proxies
+	RBACPolicyPrincipalCount prometheus.Histogram
+
+	// RBACCompactedPrincipalCount is the metric counter for the number of principal rules removed from the RBAC
+	// policies generated for the proxies by compacting their downstream identities into wildcards
+	RBACCompactedPrincipalCount prometheus.Counter
+
+	/*
+	 * Catalog metrics
+	 */
+	// CatalogShardNamespaceCount is the metric for the number of namespaces assigned to each dispatcher shard
+	CatalogShardNamespaceCount *prometheus.GaugeVec
+
+	// CatalogShardEventCount is the metric counter for the number of events dispatched by each dispatcher shard
+	CatalogShardEventCount *prometheus.CounterVec
+
+	// CatalogShardBroadcastCount is the metric counter for the number of ProxyBroadcast events published by each dispatcher shard
+	CatalogShardBroadcastCount *prometheus.CounterVec
+
+	// CatalogShardRebalanceCount is the metric counter for the number of namespaces moved between dispatcher shards
+	CatalogShardRebalanceCount prometheus.Counter
+
+	/*
+	 * Endpoint metrics
+	 */
+	// EndpointFlapPinCount is the metric counter for the number of times endpoints were pinned out due to their readiness flapping
+	EndpointFlapPinCount prometheus.Counter
+
 	/*
 	 * Injector metrics
 	 */
@@ -46,6 +127,13 @@ type MetricsStore struct {
 	// InjectorRqTime the histogram to track times for the injector webhook calls
 	InjectorRqTime *prometheus.HistogramVec
 
+	// InjectorBootstrapSecretOrphanedCount is the metric gauge for the number of orphaned bootstrap Secrets found by
+	// the last garbage collection
+	InjectorBootstrapSecretOrphanedCount prometheus.Gauge
+
+	// InjectorBootstrapSecretReclaimedCount is the metric counter for the number of orphaned bootstrap Secrets deleted
+	InjectorBootstrapSecretReclaimedCount prometheus.Counter
+
 	/*
 	 * Certificate metrics
 	 */
@@ -55,6 +143,28 @@ type MetricsStore struct {
 	// CertXdsIssuedCounter the histogram to track the time to issue a certificates
 	CertIssuedTime *prometheus.HistogramVec
 
+	// CertProviderIssuedCount is the metric counter for the number of certificates issued by each certificate provider
+	CertProviderIssuedCount *prometheus.CounterVec
+
+	// CertRotatedCount is the metric counter for the number of certificates rotated by each certificate provider
+	CertRotatedCount *prometheus.CounterVec
+
+	// CertReleasedCount is the metric counter for the number of certificates released by each certificate provider
+	CertReleasedCount *prometheus.CounterVec
+
+	// CertExpiringCount is the metric for the number of certificates of each certificate provider expiring within
+	// a number of hours
+	CertExpiringCount *prometheus.GaugeVec
+
+	// CertProviderErrorCount is the metric counter for the number of failed operations of each certificate provider
+	CertProviderErrorCount *prometheus.CounterVec
+
+	/*
+	 * Controller runtime metrics
+	 */
+	// ControllerGoroutineCount is the metric for the number of goroutines of the controller
+	ControllerGoroutineCount prometheus.GaugeFunc
+
 	/*
 	 * ErrCode metrics
 	 */
@@ -86,6 +196,56 @@ func init() {
 		[]string{"type", "namespace"},
 	)
 
+	defaultMetricsStore.K8sInformerResyncPeriod = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "k8s",
+			Name:      "informer_resync_period_seconds",
+			Help:      "Represents the resync period of each Kubernetes informer",
+		},
+		[]string{"informer"},
+	)
+
+	defaultMetricsStore.K8sInformerWatchErrorCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "k8s",
+			Name:      "informer_watch_error_count",
+			Help:      "Represents the number of watch errors of each Kubernetes informer",
+		},
+		[]string{"informer"},
+	)
+
+	defaultMetricsStore.K8sInformerRelistCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "k8s",
+			Name:      "informer_relist_count",
+			Help:      "Represents the number of times each Kubernetes informer relisted its resources after its initial list",
+		},
+		[]string{"informer"},
+	)
+
+	defaultMetricsStore.K8sInformerLastSyncTime = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "k8s",
+			Name:      "informer_last_sync_timestamp_seconds",
+			Help:      "Represents the Unix time at which each Kubernetes informer last listed its resources, started a watch or received a watch event",
+		},
+		[]string{"informer"},
+	)
+
+	defaultMetricsStore.K8sInformerCacheSize = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "k8s",
+			Name:      "informer_cache_size",
+			Help:      "Represents the number of objects in the cache of each Kubernetes informer",
+		},
+		[]string{"informer"},
+	)
+
 	/*
 	 * Proxy metrics
 	 */
@@ -123,6 +283,150 @@ func init() {
 		Help:      "Represents the number of ProxyBroadcast events published by the OSM controller",
 	})
 
+	defaultMetricsStore.ProxyUnchangedConfigCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "proxy",
+			Name:      "unchanged_config_count",
+			Help:      "Represents the number of proxy config updates skipped because the generated resources were unchanged",
+		},
+		[]string{"resource_type"},
+	)
+
+	defaultMetricsStore.ProxyCertRotationPushTime = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: metricsRootNamespace,
+		Subsystem: "proxy",
+		Name:      "cert_rotation_push_time",
+		Buckets:   []float64{.1, .25, .5, 1, 2.5, 5, 10, 20, 40, 90},
+		Help:      "Histogram to track time spent between the rotation of the certificate of a proxy and its push to the proxy",
+	})
+
+	defaultMetricsStore.ProxyStaleCertCount = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsRootNamespace,
+		Subsystem: "proxy",
+		Name:      "stale_cert_count",
+		Help:      "Represents the number of connected proxies whose certificate was rotated but not yet pushed to them",
+	})
+
+	defaultMetricsStore.ProxyDesyncCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "proxy",
+			Name:      "desync_count",
+			Help:      "Represents the number of connected proxies that have not acknowledged the config sent to them for longer than the desync threshold",
+		},
+		[]string{"resource_type"},
+	)
+
+	defaultMetricsStore.ProxyDesyncRepushCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "proxy",
+			Name:      "desync_repush_count",
+			Help:      "Represents the number of times the config was pushed again to desynced proxies",
+		},
+		[]string{"resource_type"},
+	)
+
+	defaultMetricsStore.ProxyRestartCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "proxy",
+			Name:      "restart_count",
+			Help:      "Represents the number of restarts of the Envoy proxy of each pod detected by the OSM controller",
+		},
+		[]string{"namespace", "pod"},
+	)
+
+	defaultMetricsStore.ProxyRegistryReclaimedCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "proxy",
+			Name:      "registry_reclaimed_count",
+			Help:      "Represents the number of proxy registry entries reclaimed for proxies that disconnected and whose pods no longer exist",
+		},
+		[]string{
+			"entry", // 'disconnected_proxy', 'pod_mapping' or 'certificate'
+		})
+
+	defaultMetricsStore.RBACDenialCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "proxy",
+			Name:      "rbac_denial_count",
+			Help:      "Represents the number of inbound requests denied by the RBAC policies of the proxies of each service",
+		},
+		[]string{
+			"namespace", // namespace of the service
+			"service",   // name of the service
+			"mode",      // 'enforced', or 'shadow' if the request was allowed through
+		})
+
+	defaultMetricsStore.RBACPolicyPrincipalCount = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: metricsRootNamespace,
+		Subsystem: "proxy",
+		Name:      "rbac_policy_principal_count",
+		Buckets:   []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000},
+		Help:      "Histogram of the number of principal rules of the RBAC policies generated for the proxies",
+	})
+
+	defaultMetricsStore.RBACCompactedPrincipalCount = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsRootNamespace,
+		Subsystem: "proxy",
+		Name:      "rbac_compacted_principal_count",
+		Help:      "Represents the number of principal rules removed from the RBAC policies generated for the proxies by compacting their downstream identities into wildcards",
+	})
+
+	/*
+	 * Catalog metrics
+	 */
+	defaultMetricsStore.CatalogShardNamespaceCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "catalog",
+			Name:      "shard_namespace_count",
+			Help:      "Represents the number of namespaces assigned to each dispatcher shard",
+		},
+		[]string{"shard"},
+	)
+
+	defaultMetricsStore.CatalogShardEventCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "catalog",
+			Name:      "shard_event_count",
+			Help:      "Represents the number of events dispatched by each dispatcher shard",
+		},
+		[]string{"shard"},
+	)
+
+	defaultMetricsStore.CatalogShardBroadcastCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "catalog",
+			Name:      "shard_broadcast_count",
+			Help:      "Represents the number of ProxyBroadcast events published by each dispatcher shard",
+		},
+		[]string{"shard"},
+	)
+
+	defaultMetricsStore.CatalogShardRebalanceCount = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsRootNamespace,
+		Subsystem: "catalog",
+		Name:      "shard_rebalance_count",
+		Help:      "Represents the number of namespaces moved between dispatcher shards to rebalance them",
+	})
+
+	/*
+	 * Endpoint metrics
+	 */
+	defaultMetricsStore.EndpointFlapPinCount = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsRootNamespace,
+		Subsystem: "endpoint",
+		Name:      "flap_pin_count",
+		Help:      "Represents the number of times endpoints were pinned out due to their readiness flapping",
+	})
+
 	/*
 	 * Injector metrics
 	 */
@@ -148,6 +452,20 @@ func init() {
 	/*
 	 * Certificate metrics
 	 */
+	defaultMetricsStore.InjectorBootstrapSecretOrphanedCount = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsRootNamespace,
+		Subsystem: "injector",
+		Name:      "bootstrap_secret_orphaned_count",
+		Help:      "Represents the number of orphaned Envoy bootstrap Secrets found by the last garbage collection",
+	})
+
+	defaultMetricsStore.InjectorBootstrapSecretReclaimedCount = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsRootNamespace,
+		Subsystem: "injector",
+		Name:      "bootstrap_secret_reclaimed_count",
+		Help:      "Represents the number of orphaned Envoy bootstrap Secrets deleted",
+	})
+
 	defaultMetricsStore.CertIssuedCount = prometheus.NewCounter(prometheus.CounterOpts{
 		Namespace: metricsRootNamespace,
 		Subsystem: "cert",
@@ -165,6 +483,69 @@ func init() {
 		},
 		[]string{})
 
+	defaultMetricsStore.CertProviderIssuedCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "cert",
+			Name:      "provider_issued_count",
+			Help:      "Represents the number of certificates issued by each certificate provider",
+		},
+		[]string{"provider"},
+	)
+
+	defaultMetricsStore.CertRotatedCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "cert",
+			Name:      "rotated_count",
+			Help:      "Represents the number of certificates rotated by each certificate provider",
+		},
+		[]string{"provider"},
+	)
+
+	defaultMetricsStore.CertReleasedCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "cert",
+			Name:      "released_count",
+			Help:      "Represents the number of certificates released by each certificate provider",
+		},
+		[]string{"provider"},
+	)
+
+	defaultMetricsStore.CertExpiringCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "cert",
+			Name:      "expiring_count",
+			Help:      "Represents the number of certificates of each certificate provider expiring within a number of hours",
+		},
+		[]string{"provider", "within_hours"},
+	)
+
+	defaultMetricsStore.CertProviderErrorCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "cert",
+			Name:      "provider_error_count",
+			Help:      "Represents the number of failed operations of each certificate provider",
+		},
+		[]string{"provider", "operation"},
+	)
+
+	/*
+	 * Controller runtime metrics
+	 */
+	defaultMetricsStore.ControllerGoroutineCount = prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Namespace: metricsRootNamespace,
+			Subsystem: "controller",
+			Name:      "goroutine_count",
+			Help:      "Represents the number of goroutines of the controller",
+		},
+		func() float64 { return float64(runtime.NumGoroutine()) },
+	)
+
 	/*
 	 * ErrCode metrics
 	 */
@@ -199,3 +580,19 @@ func (ms *MetricsStore) Handler() http.Handler {
 		promhttp.HandlerFor(ms.registry, promhttp.HandlerOpts{}),
 	)
 }
+
+// WriteText writes the metrics in the registry to the given writer, in the Prometheus text exposition format
+func (ms *MetricsStore) WriteText(w io.Writer) error {
+	metricFamilies, err := ms.registry.Gather()
+	if err != nil {
+		return err
+	}
+
+	encoder := expfmt.NewEncoder(w, expfmt.FmtText)
+	for _, metricFamily := range metricFamilies {
+		if err := encoder.Encode(metricFamily); err != nil {
+			return err
+		}
+	}
+	return nil
+}
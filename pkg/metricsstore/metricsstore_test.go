@@ -13,7 +13,11 @@ func setup() {
 	DefaultMetricsStore.Start(
 		DefaultMetricsStore.K8sAPIEventCounter,
 		DefaultMetricsStore.ProxyConnectCount,
+		DefaultMetricsStore.ProxyStaleCertCount,
 		DefaultMetricsStore.ErrCodeCounter,
+		DefaultMetricsStore.CertRotatedCount,
+		DefaultMetricsStore.CertExpiringCount,
+		DefaultMetricsStore.ControllerGoroutineCount,
 	)
 }
 
@@ -21,7 +25,11 @@ func teardown() {
 	DefaultMetricsStore.Stop(
 		DefaultMetricsStore.K8sAPIEventCounter,
 		DefaultMetricsStore.ProxyConnectCount,
+		DefaultMetricsStore.ProxyStaleCertCount,
 		DefaultMetricsStore.ErrCodeCounter,
+		DefaultMetricsStore.CertRotatedCount,
+		DefaultMetricsStore.CertExpiringCount,
+		DefaultMetricsStore.ControllerGoroutineCount,
 	)
 }
 
@@ -110,4 +118,96 @@ osm_error_err_code_count{err_code="E1100"} %d
 			assert.Contains(rr.Body.String(), expectedResp)
 		}
 	})
+
+	t.Run("CertRotatedCount", func(t *testing.T) {
+		assert := tassert.New(t)
+
+		certsRotated := 4
+
+		for i := 1; i <= certsRotated; i++ {
+			DefaultMetricsStore.CertRotatedCount.WithLabelValues("tresor").Inc()
+		}
+
+		handler := DefaultMetricsStore.Handler()
+
+		req, err := http.NewRequest("GET", "/metrics", nil)
+		assert.Nil(err)
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(http.StatusOK, rr.Code)
+
+		expectedResp := fmt.Sprintf(`# HELP osm_cert_rotated_count Represents the number of certificates rotated by each certificate provider
+# TYPE osm_cert_rotated_count counter
+osm_cert_rotated_count{provider="tresor"} %d
+`, certsRotated)
+		assert.Contains(rr.Body.String(), expectedResp)
+	})
+
+	t.Run("CertExpiringCount", func(t *testing.T) {
+		assert := tassert.New(t)
+
+		DefaultMetricsStore.CertExpiringCount.WithLabelValues("vault", "1").Set(2)
+		DefaultMetricsStore.CertExpiringCount.WithLabelValues("vault", "24").Set(7)
+
+		handler := DefaultMetricsStore.Handler()
+
+		req, err := http.NewRequest("GET", "/metrics", nil)
+		assert.Nil(err)
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(http.StatusOK, rr.Code)
+
+		expectedResp := `# HELP osm_cert_expiring_count Represents the number of certificates of each certificate provider expiring within a number of hours
+# TYPE osm_cert_expiring_count gauge
+osm_cert_expiring_count{provider="vault",within_hours="1"} 2
+osm_cert_expiring_count{provider="vault",within_hours="24"} 7
+`
+		assert.Contains(rr.Body.String(), expectedResp)
+	})
+
+	t.Run("ProxyStaleCertCount", func(t *testing.T) {
+		assert := tassert.New(t)
+
+		DefaultMetricsStore.ProxyStaleCertCount.Inc()
+		DefaultMetricsStore.ProxyStaleCertCount.Inc()
+		DefaultMetricsStore.ProxyStaleCertCount.Dec()
+
+		handler := DefaultMetricsStore.Handler()
+
+		req, err := http.NewRequest("GET", "/metrics", nil)
+		assert.Nil(err)
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(http.StatusOK, rr.Code)
+
+		expectedResp := `# HELP osm_proxy_stale_cert_count Represents the number of connected proxies whose certificate was rotated but not yet pushed to them
+# TYPE osm_proxy_stale_cert_count gauge
+osm_proxy_stale_cert_count 1
+`
+		assert.Contains(rr.Body.String(), expectedResp)
+	})
+	t.Run("ControllerGoroutineCount", func(t *testing.T) {
+		assert := tassert.New(t)
+
+		handler := DefaultMetricsStore.Handler()
+
+		req, err := http.NewRequest("GET", "/metrics", nil)
+		assert.Nil(err)
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(http.StatusOK, rr.Code)
+
+		expectedResp := `# HELP osm_controller_goroutine_count Represents the number of goroutines of the controller
+# TYPE osm_controller_goroutine_count gauge
+osm_controller_goroutine_count `
+		assert.Contains(rr.Body.String(), expectedResp)
+	})
 }
@@ -0,0 +1,113 @@
+package migration
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// istioProxyContainer is the name of the sidecar container injected by Istio
+	istioProxyContainer = "istio-proxy"
+
+	// istioSidecarStatusAnnotation is the annotation Istio records the injection of its sidecar with
+	istioSidecarStatusAnnotation = "sidecar.istio.io/status"
+
+	// istioSidecarInjectKey is the pod label or annotation enabling or disabling the injection of the Istio sidecar
+	istioSidecarInjectKey = "sidecar.istio.io/inject"
+
+	// istioInjectionLabel is the namespace label enabling the injection of the Istio sidecar
+	istioInjectionLabel = "istio-injection"
+
+	// istioRevisionLabel is the namespace label enabling the injection of the sidecar of an Istio control plane revision
+	istioRevisionLabel = "istio.io/rev"
+
+	// linkerdProxyContainer is the name of the sidecar container injected by Linkerd
+	linkerdProxyContainer = "linkerd-proxy"
+
+	// linkerdProxyVersionAnnotation is the annotation Linkerd records the version of its injected sidecar with
+	linkerdProxyVersionAnnotation = "linkerd.io/proxy-version"
+
+	// linkerdInjectAnnotation is the pod or namespace annotation enabling or disabling the injection of the Linkerd sidecar
+	linkerdInjectAnnotation = "linkerd.io/inject"
+)
+
+// GetOtherMeshSidecar returns the sidecar of another service mesh the given pod carries, or which the other mesh is
+// about to inject into it given its namespace, or nil if none. Injecting the OSM sidecar next to it would redirect
+// the traffic of the pod twice.
+func GetOtherMeshSidecar(pod *corev1.Pod, ns *corev1.Namespace) *OtherMeshSidecar {
+	for _, containers := range [][]corev1.Container{pod.Spec.Containers, pod.Spec.InitContainers} {
+		for _, container := range containers {
+			switch container.Name {
+			case istioProxyContainer:
+				return &OtherMeshSidecar{Mesh: MeshIstio, Evidence: fmt.Sprintf("container %s", container.Name)}
+			case linkerdProxyContainer:
+				return &OtherMeshSidecar{Mesh: MeshLinkerd, Evidence: fmt.Sprintf("container %s", container.Name)}
+			}
+		}
+	}
+
+	if _, ok := pod.Annotations[istioSidecarStatusAnnotation]; ok {
+		return &OtherMeshSidecar{Mesh: MeshIstio, Evidence: fmt.Sprintf("annotation %s", istioSidecarStatusAnnotation)}
+	}
+	if _, ok := pod.Annotations[linkerdProxyVersionAnnotation]; ok {
+		return &OtherMeshSidecar{Mesh: MeshLinkerd, Evidence: fmt.Sprintf("annotation %s", linkerdProxyVersionAnnotation)}
+	}
+
+	if sidecar := getIstioInjection(pod, ns); sidecar != nil {
+		return sidecar
+	}
+	return getLinkerdInjection(pod, ns)
+}
+
+// getIstioInjection returns the Istio sidecar Istio is about to inject into the given pod given its namespace, or nil
+// if none. The label of the pod takes precedence over its annotation, which takes precedence over the namespace labels.
+func getIstioInjection(pod *corev1.Pod, ns *corev1.Namespace) *OtherMeshSidecar {
+	if inject, ok := pod.Labels[istioSidecarInjectKey]; ok {
+		if inject == "true" {
+			return &OtherMeshSidecar{Mesh: MeshIstio, Evidence: fmt.Sprintf("pod label %s: %s", istioSidecarInjectKey, inject)}
+		}
+		return nil
+	}
+	if inject, ok := pod.Annotations[istioSidecarInjectKey]; ok {
+		if inject == "true" {
+			return &OtherMeshSidecar{Mesh: MeshIstio, Evidence: fmt.Sprintf("pod annotation %s: %s", istioSidecarInjectKey, inject)}
+		}
+		return nil
+	}
+
+	if ns == nil {
+		return nil
+	}
+	if injection := ns.Labels[istioInjectionLabel]; injection == "enabled" {
+		return &OtherMeshSidecar{Mesh: MeshIstio, Evidence: fmt.Sprintf("namespace label %s: %s", istioInjectionLabel, injection)}
+	}
+	if revision, ok := ns.Labels[istioRevisionLabel]; ok && ns.Labels[istioInjectionLabel] != "disabled" {
+		return &OtherMeshSidecar{Mesh: MeshIstio, Evidence: fmt.Sprintf("namespace label %s: %s", istioRevisionLabel, revision)}
+	}
+	return nil
+}
+
+// getLinkerdInjection returns the Linkerd sidecar Linkerd is about to inject into the given pod given its namespace,
+// or nil if none. The annotation of the pod takes precedence over the annotation of the namespace.
+func getLinkerdInjection(pod *corev1.Pod, ns *corev1.Namespace) *OtherMeshSidecar {
+	if inject, ok := pod.Annotations[linkerdInjectAnnotation]; ok {
+		if inject == "enabled" || inject == "ingress" {
+			return &OtherMeshSidecar{Mesh: MeshLinkerd, Evidence: fmt.Sprintf("pod annotation %s: %s", linkerdInjectAnnotation, inject)}
+		}
+		return nil
+	}
+
+	if ns == nil {
+		return nil
+	}
+	if inject := ns.Annotations[linkerdInjectAnnotation]; inject == "enabled" || inject == "ingress" {
+		return &OtherMeshSidecar{Mesh: MeshLinkerd, Evidence: fmt.Sprintf("namespace annotation %s: %s", linkerdInjectAnnotation, inject)}
+	}
+	return nil
+}
+
+// String returns the description of the sidecar of the other service mesh
+func (s OtherMeshSidecar) String() string {
+	return fmt.Sprintf("%s sidecar (%s)", s.Mesh, s.Evidence)
+}
@@ -0,0 +1,103 @@
+package migration
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetOtherMeshSidecar(t *testing.T) {
+	testCases := []struct {
+		name     string
+		pod      *corev1.Pod
+		ns       *corev1.Namespace
+		expected *OtherMeshSidecar
+	}{
+		{
+			name:     "plain pod in plain namespace",
+			pod:      &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}}},
+			ns:       &corev1.Namespace{},
+			expected: nil,
+		},
+		{
+			name:     "pod with istio-proxy container",
+			pod:      &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}, {Name: "istio-proxy"}}}},
+			expected: &OtherMeshSidecar{Mesh: MeshIstio, Evidence: "container istio-proxy"},
+		},
+		{
+			name:     "pod with linkerd-proxy container",
+			pod:      &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "linkerd-proxy"}}}},
+			expected: &OtherMeshSidecar{Mesh: MeshLinkerd, Evidence: "container linkerd-proxy"},
+		},
+		{
+			name:     "pod with istio sidecar status annotation",
+			pod:      &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"sidecar.istio.io/status": "{}"}}},
+			expected: &OtherMeshSidecar{Mesh: MeshIstio, Evidence: "annotation sidecar.istio.io/status"},
+		},
+		{
+			name:     "pod with linkerd proxy version annotation",
+			pod:      &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"linkerd.io/proxy-version": "stable-2.11.1"}}},
+			expected: &OtherMeshSidecar{Mesh: MeshLinkerd, Evidence: "annotation linkerd.io/proxy-version"},
+		},
+		{
+			name:     "pod labeled for istio injection",
+			pod:      &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"sidecar.istio.io/inject": "true"}}},
+			expected: &OtherMeshSidecar{Mesh: MeshIstio, Evidence: "pod label sidecar.istio.io/inject: true"},
+		},
+		{
+			name:     "pod annotated for istio injection",
+			pod:      &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"sidecar.istio.io/inject": "true"}}},
+			expected: &OtherMeshSidecar{Mesh: MeshIstio, Evidence: "pod annotation sidecar.istio.io/inject: true"},
+		},
+		{
+			name:     "namespace labeled for istio injection",
+			pod:      &corev1.Pod{},
+			ns:       &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"istio-injection": "enabled"}}},
+			expected: &OtherMeshSidecar{Mesh: MeshIstio, Evidence: "namespace label istio-injection: enabled"},
+		},
+		{
+			name:     "namespace labeled with istio revision",
+			pod:      &corev1.Pod{},
+			ns:       &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"istio.io/rev": "1-11"}}},
+			expected: &OtherMeshSidecar{Mesh: MeshIstio, Evidence: "namespace label istio.io/rev: 1-11"},
+		},
+		{
+			name:     "pod label disables istio injection enabled on namespace",
+			pod:      &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"sidecar.istio.io/inject": "false"}}},
+			ns:       &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"istio-injection": "enabled"}}},
+			expected: nil,
+		},
+		{
+			name:     "namespace with istio injection disabled",
+			pod:      &corev1.Pod{},
+			ns:       &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"istio-injection": "disabled", "istio.io/rev": "1-11"}}},
+			expected: nil,
+		},
+		{
+			name:     "pod annotated for linkerd injection",
+			pod:      &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"linkerd.io/inject": "enabled"}}},
+			expected: &OtherMeshSidecar{Mesh: MeshLinkerd, Evidence: "pod annotation linkerd.io/inject: enabled"},
+		},
+		{
+			name:     "namespace annotated for linkerd injection",
+			pod:      &corev1.Pod{},
+			ns:       &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"linkerd.io/inject": "enabled"}}},
+			expected: &OtherMeshSidecar{Mesh: MeshLinkerd, Evidence: "namespace annotation linkerd.io/inject: enabled"},
+		},
+		{
+			name:     "pod annotation disables linkerd injection enabled on namespace",
+			pod:      &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"linkerd.io/inject": "disabled"}}},
+			ns:       &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"linkerd.io/inject": "enabled"}}},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expected, GetOtherMeshSidecar(tc.pod, tc.ns))
+		})
+	}
+}
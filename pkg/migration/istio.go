@@ -0,0 +1,475 @@
+package migration
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	smiSpecs "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
+	smiSplit "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha4"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const (
+	istioNetworkingGroup = "networking.istio.io"
+	smiSpecsGroup        = "specs.smi-spec.io"
+
+	// istioMeshGateway is the reserved gateway name applying a VirtualService to the sidecars of the mesh
+	istioMeshGateway = "mesh"
+)
+
+// subsetKey identifies a subset of the DestinationRule of a Kubernetes service
+type subsetKey struct {
+	namespace string
+	service   string
+	subset    string
+}
+
+// istioResources are the Istio resources to convert
+type istioResources struct {
+	virtualServices  []virtualService
+	destinationRules []destinationRule
+}
+
+// ConvertIstioResources converts the basics of the Istio VirtualServices and DestinationRules of the given YAML or
+// JSON stream of resources into SMI TrafficSplits and HTTPRouteGroups:
+//   - each HTTP route of a VirtualService for a Kubernetes service becomes a TrafficSplit of the service, with the
+//     weights of the destinations of the route
+//   - the URI, method and header matches of the route become an HTTPRouteGroup the TrafficSplit matches
+//   - the destinations of a subset of a DestinationRule become a backend Service named '<service>-<subset>', which
+//     must be created selecting the pods with the labels of the subset
+//
+// The fields of the Istio resources OSM has no equivalent of, and the other resources, are reported as warnings.
+func ConvertIstioResources(r io.Reader) (*Conversion, error) {
+	conversion := &Conversion{}
+	resources := &istioResources{}
+
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(r))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Errorf("Error reading the Istio resources: %s", err)
+		}
+		if err := conversion.addResource(resources, doc); err != nil {
+			return nil, err
+		}
+	}
+
+	subsets := make(map[subsetKey]map[string]string)
+	for _, dr := range resources.destinationRules {
+		conversion.addDestinationRule(dr, subsets)
+	}
+
+	requiredSubsets := make(map[subsetKey]bool)
+	for _, vs := range resources.virtualServices {
+		conversion.addVirtualService(vs, requiredSubsets)
+	}
+
+	var keys []subsetKey
+	for key := range requiredSubsets {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprintf("%s/%s/%s", keys[i].namespace, keys[i].service, keys[i].subset) < fmt.Sprintf("%s/%s/%s", keys[j].namespace, keys[j].service, keys[j].subset)
+	})
+	for _, key := range keys {
+		backend := fmt.Sprintf("%s-%s", key.service, key.subset)
+		labels, ok := subsets[key]
+		if !ok {
+			conversion.warnf("Create Service %s/%s with the ports of Service %s/%s, selecting the pods of subset %s whose DestinationRule wasn't provided",
+				key.namespace, backend, key.namespace, key.service, key.subset)
+			continue
+		}
+		conversion.warnf("Create Service %s/%s with the ports of Service %s/%s, selecting the pods of subset %s with labels %s",
+			key.namespace, backend, key.namespace, key.service, key.subset, formatLabels(labels))
+	}
+
+	return conversion, nil
+}
+
+// Manifests returns the converted resources as a YAML stream, the HTTPRouteGroups before the TrafficSplits
+// referencing them
+func (c *Conversion) Manifests() ([]byte, error) {
+	var objects []interface{}
+	for i := range c.HTTPRouteGroups {
+		objects = append(objects, &c.HTTPRouteGroups[i])
+	}
+	for i := range c.TrafficSplits {
+		objects = append(objects, &c.TrafficSplits[i])
+	}
+
+	var manifests bytes.Buffer
+	for i, obj := range objects {
+		manifest, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, errors.Errorf("Error marshaling the converted resources: %s", err)
+		}
+		if i > 0 {
+			manifests.WriteString("---\n")
+		}
+		manifests.Write(manifest)
+	}
+	return manifests.Bytes(), nil
+}
+
+func (c *Conversion) warnf(format string, a ...interface{}) {
+	c.Warnings = append(c.Warnings, fmt.Sprintf(format, a...))
+}
+
+// addResource adds the Istio resource of the given YAML or JSON document to the given resources to convert, expanding
+// lists of resources
+func (c *Conversion) addResource(resources *istioResources, doc []byte) error {
+	var typeMeta metav1.TypeMeta
+	if err := yaml.Unmarshal(doc, &typeMeta); err != nil {
+		return errors.Errorf("Error decoding resource: %s", err)
+	}
+	if typeMeta.Kind == "" {
+		// Empty document
+		return nil
+	}
+
+	if strings.HasSuffix(typeMeta.Kind, "List") {
+		var list struct {
+			Items []json.RawMessage `json:"items"`
+		}
+		if err := yaml.Unmarshal(doc, &list); err != nil {
+			return errors.Errorf("Error decoding %s: %s", typeMeta.Kind, err)
+		}
+		for _, item := range list.Items {
+			if err := c.addResource(resources, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var object struct {
+		Metadata metav1.ObjectMeta `json:"metadata"`
+	}
+	if err := yaml.Unmarshal(doc, &object); err != nil {
+		return errors.Errorf("Error decoding %s: %s", typeMeta.Kind, err)
+	}
+	name := resourceName(object.Metadata)
+
+	if !strings.HasPrefix(typeMeta.APIVersion, istioNetworkingGroup+"/") {
+		c.warnf("%s %s is not an Istio networking resource and is not converted", typeMeta.Kind, name)
+		return nil
+	}
+
+	switch typeMeta.Kind {
+	case "VirtualService":
+		var vs virtualService
+		if err := yaml.Unmarshal(doc, &vs); err != nil {
+			return errors.Errorf("Error decoding VirtualService %s: %s", name, err)
+		}
+		resources.virtualServices = append(resources.virtualServices, vs)
+	case "DestinationRule":
+		var dr destinationRule
+		if err := yaml.Unmarshal(doc, &dr); err != nil {
+			return errors.Errorf("Error decoding DestinationRule %s: %s", name, err)
+		}
+		resources.destinationRules = append(resources.destinationRules, dr)
+	default:
+		c.warnf("%s %s is not converted, only VirtualServices and DestinationRules are", typeMeta.Kind, name)
+	}
+	return nil
+}
+
+// addDestinationRule records the labels of the subsets of the given DestinationRule in the given subsets, and reports
+// its traffic policies as warnings
+func (c *Conversion) addDestinationRule(dr destinationRule, subsets map[subsetKey]map[string]string) {
+	name := resourceName(dr.ObjectMeta)
+	service, namespace, ok := resolveHost(dr.Spec.Host, dr.Namespace)
+	if !ok {
+		c.warnf("DestinationRule %s: host %s is not a Kubernetes service and is not converted", name, dr.Spec.Host)
+		return
+	}
+
+	c.warnTrafficPolicy(fmt.Sprintf("DestinationRule %s", name), "trafficPolicy", dr.Spec.TrafficPolicy)
+	for _, subset := range dr.Spec.Subsets {
+		subsets[subsetKey{namespace: namespace, service: service, subset: subset.Name}] = subset.Labels
+		c.warnTrafficPolicy(fmt.Sprintf("DestinationRule %s", name), fmt.Sprintf("subsets[%s].trafficPolicy", subset.Name), subset.TrafficPolicy)
+	}
+}
+
+// warnTrafficPolicy reports the settings of the given traffic policy of a DestinationRule, which aren't converted
+func (c *Conversion) warnTrafficPolicy(resource string, field string, trafficPolicy map[string]interface{}) {
+	var settings []string
+	for setting := range trafficPolicy {
+		settings = append(settings, setting)
+	}
+	sort.Strings(settings)
+	for _, setting := range settings {
+		if setting == "tls" {
+			c.warnf("%s: %s.tls is not converted, OSM secures the traffic between the sidecars of the mesh with mTLS", resource, field)
+			continue
+		}
+		c.warnf("%s: %s.%s is not converted", resource, field, setting)
+	}
+}
+
+// addVirtualService converts the HTTP routes of the given VirtualService into TrafficSplits and HTTPRouteGroups, and
+// records the subsets of DestinationRules its routes require the backend Service of in the given required subsets
+func (c *Conversion) addVirtualService(vs virtualService, requiredSubsets map[subsetKey]bool) {
+	name := resourceName(vs.ObjectMeta)
+	resource := fmt.Sprintf("VirtualService %s", name)
+
+	if len(vs.Spec.Gateways) > 0 {
+		appliesToMesh := false
+		for _, gateway := range vs.Spec.Gateways {
+			if gateway == istioMeshGateway {
+				appliesToMesh = true
+			}
+		}
+		if !appliesToMesh {
+			c.warnf("%s applies to gateways %s only and is not converted, use an IngressBackend for ingress traffic", resource, strings.Join(vs.Spec.Gateways, ", "))
+			return
+		}
+		c.warnf("%s: gateways other than %q are not converted, use an IngressBackend for ingress traffic", resource, istioMeshGateway)
+	}
+	if len(vs.Spec.TCP) > 0 {
+		c.warnf("%s: tcp routes are not converted", resource)
+	}
+	if len(vs.Spec.TLS) > 0 {
+		c.warnf("%s: tls routes are not converted", resource)
+	}
+
+	for i, route := range vs.Spec.HTTP {
+		c.warnUnsupportedRouteFields(resource, httpRouteName(route, i), route)
+	}
+
+	for _, host := range vs.Spec.Hosts {
+		service, namespace, ok := resolveHost(host, vs.Namespace)
+		if !ok {
+			c.warnf("%s: host %s is not a Kubernetes service and is not converted", resource, host)
+			continue
+		}
+
+		var matchRoutes []string
+		for i, route := range vs.Spec.HTTP {
+			routeName := httpRouteName(route, i)
+			splitName := vs.Name
+			if len(vs.Spec.Hosts) > 1 {
+				splitName = fmt.Sprintf("%s-%s", splitName, service)
+			}
+			splitName = strings.ToLower(fmt.Sprintf("%s-%s", splitName, routeName))
+
+			var backends []smiSplit.TrafficSplitBackend
+			for _, destination := range route.Route {
+				backendService, backendNamespace, ok := resolveHost(destination.Destination.Host, vs.Namespace)
+				if !ok || backendNamespace != namespace {
+					c.warnf("%s: destination %s of route %s is not a Kubernetes service in namespace %s and is not converted",
+						resource, destination.Destination.Host, routeName, namespace)
+					continue
+				}
+				if destination.Destination.Subset != "" {
+					requiredSubsets[subsetKey{namespace: backendNamespace, service: backendService, subset: destination.Destination.Subset}] = true
+					backendService = fmt.Sprintf("%s-%s", backendService, destination.Destination.Subset)
+				}
+				weight := destination.Weight
+				if weight == 0 && len(route.Route) == 1 {
+					weight = 100
+				}
+				backends = append(backends, smiSplit.TrafficSplitBackend{
+					Service: backendService,
+					Weight:  weight,
+				})
+			}
+			if len(backends) == 0 {
+				c.warnf("%s: route %s has no destination to convert for host %s", resource, routeName, host)
+				continue
+			}
+
+			split := smiSplit.TrafficSplit{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: smiSplit.SchemeGroupVersion.String(),
+					Kind:       "TrafficSplit",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      splitName,
+					Namespace: namespace,
+				},
+				Spec: smiSplit.TrafficSplitSpec{
+					Service:  service,
+					Backends: backends,
+				},
+			}
+
+			if len(route.Match) == 0 {
+				if len(backends) > 1 || backends[0].Service != service {
+					c.TrafficSplits = append(c.TrafficSplits, split)
+				}
+				// Istio never evaluates the routes following a route without matches
+				if i < len(vs.Spec.HTTP)-1 {
+					c.warnf("%s: the routes following route %s are unreachable and are not converted", resource, routeName)
+				}
+				break
+			}
+
+			routeGroup := smiSpecs.HTTPRouteGroup{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: smiSpecs.SchemeGroupVersion.String(),
+					Kind:       "HTTPRouteGroup",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      splitName,
+					Namespace: namespace,
+				},
+			}
+			for j, match := range route.Match {
+				routeGroup.Spec.Matches = append(routeGroup.Spec.Matches, c.convertHTTPMatch(resource, routeName, j, match))
+			}
+			c.HTTPRouteGroups = append(c.HTTPRouteGroups, routeGroup)
+
+			apiGroup := smiSpecsGroup
+			split.Spec.Matches = []corev1.TypedLocalObjectReference{
+				{
+					APIGroup: &apiGroup,
+					Kind:     "HTTPRouteGroup",
+					Name:     routeGroup.Name,
+				},
+			}
+			c.TrafficSplits = append(c.TrafficSplits, split)
+			matchRoutes = append(matchRoutes, routeName)
+		}
+
+		if len(matchRoutes) > 1 {
+			c.warnf("%s: OSM doesn't evaluate the matches of routes %s of host %s in order, make sure they don't overlap",
+				resource, strings.Join(matchRoutes, ", "), host)
+		}
+	}
+}
+
+// warnUnsupportedRouteFields reports the fields of the given HTTP route of a VirtualService, which aren't converted
+func (c *Conversion) warnUnsupportedRouteFields(resource string, routeName string, route istioHTTPRoute) {
+	unsupported := []struct {
+		field string
+		set   bool
+	}{
+		{"redirect", route.Redirect != nil},
+		{"directResponse", route.DirectResponse != nil},
+		{"delegate", route.Delegate != nil},
+		{"rewrite", route.Rewrite != nil},
+		{"timeout", route.Timeout != ""},
+		{"retries", route.Retries != nil},
+		{"fault", route.Fault != nil},
+		{"mirror", route.Mirror != nil || route.MirrorPercentage != nil},
+		{"corsPolicy", route.CorsPolicy != nil},
+		{"headers", route.Headers != nil},
+	}
+	for _, field := range unsupported {
+		if field.set {
+			c.warnf("%s: %s of route %s is not converted", resource, field.field, routeName)
+		}
+	}
+}
+
+// convertHTTPMatch converts the given match of an HTTP route of a VirtualService into an HTTPRouteGroup match with
+// regular expressions matching the URI and headers
+func (c *Conversion) convertHTTPMatch(resource string, routeName string, index int, match istioHTTPMatch) smiSpecs.HTTPMatch {
+	httpMatch := smiSpecs.HTTPMatch{
+		Name: fmt.Sprintf("%s-%d", strings.ToLower(routeName), index),
+	}
+
+	if match.URI != nil {
+		httpMatch.PathRegex = stringMatchRegex(*match.URI)
+	}
+	if match.Method != nil {
+		if match.Method.Exact != "" {
+			httpMatch.Methods = []string{match.Method.Exact}
+		} else {
+			c.warnf("%s: method match of route %s is not converted, only exact method matches are", resource, routeName)
+		}
+	}
+	if len(match.Headers) > 0 {
+		headers := make(map[string]string, len(match.Headers))
+		for header, value := range match.Headers {
+			headers[header] = stringMatchRegex(value)
+		}
+		httpMatch.Headers = headers
+	}
+
+	if match.Authority != nil {
+		c.warnf("%s: authority match of route %s is not converted", resource, routeName)
+	}
+	if len(match.QueryParams) > 0 {
+		c.warnf("%s: queryParams match of route %s is not converted", resource, routeName)
+	}
+	if match.Port != 0 {
+		c.warnf("%s: port match of route %s is not converted", resource, routeName)
+	}
+
+	return httpMatch
+}
+
+// stringMatchRegex returns the regular expression matching the whole values the given Istio string match matches
+func stringMatchRegex(match istioStringMatch) string {
+	switch {
+	case match.Exact != "":
+		return regexp.QuoteMeta(match.Exact)
+	case match.Prefix != "":
+		return regexp.QuoteMeta(match.Prefix) + ".*"
+	case match.Regex != "":
+		return match.Regex
+	default:
+		return ".*"
+	}
+}
+
+// resolveHost returns the name and namespace of the Kubernetes service the given Istio host refers to, short names
+// referring to a service of the given namespace. ok is false for hosts which aren't Kubernetes services.
+func resolveHost(host string, namespace string) (service string, serviceNamespace string, ok bool) {
+	if host == "" || strings.Contains(host, "*") {
+		return "", "", false
+	}
+
+	labels := strings.Split(host, ".")
+	switch {
+	case len(labels) == 1:
+		return labels[0], namespace, true
+	case len(labels) == 2:
+		return labels[0], labels[1], true
+	case labels[2] == "svc":
+		return labels[0], labels[1], true
+	default:
+		return "", "", false
+	}
+}
+
+// httpRouteName returns the name of the given HTTP route of a VirtualService, or its index if it isn't named
+func httpRouteName(route istioHTTPRoute, index int) string {
+	if route.Name != "" {
+		return route.Name
+	}
+	return fmt.Sprintf("route-%d", index)
+}
+
+// resourceName returns the namespaced name of the resource with the given metadata
+func resourceName(meta metav1.ObjectMeta) string {
+	if meta.Namespace == "" {
+		return meta.Name
+	}
+	return fmt.Sprintf("%s/%s", meta.Namespace, meta.Name)
+}
+
+// formatLabels returns the given labels formatted as a label selector
+func formatLabels(labels map[string]string) string {
+	var pairs []string
+	for key, value := range labels {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
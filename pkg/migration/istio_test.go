@@ -0,0 +1,268 @@
+package migration
+
+import (
+	"strings"
+	"testing"
+
+	smiSpecs "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
+	smiSplit "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha4"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const reviewsVirtualService = `
+apiVersion: networking.istio.io/v1beta1
+kind: VirtualService
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  hosts:
+  - reviews
+  http:
+  - name: jason
+    match:
+    - headers:
+        end-user:
+          exact: jason
+      uri:
+        prefix: /reviews
+    route:
+    - destination:
+        host: reviews
+        subset: v2
+    retries:
+      attempts: 3
+  - route:
+    - destination:
+        host: reviews.bookinfo.svc.cluster.local
+        subset: v1
+      weight: 80
+    - destination:
+        host: reviews
+        subset: v3
+      weight: 20
+    timeout: 5s
+`
+
+const reviewsDestinationRule = `
+apiVersion: networking.istio.io/v1beta1
+kind: DestinationRule
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  host: reviews
+  trafficPolicy:
+    connectionPool:
+      tcp:
+        maxConnections: 100
+  subsets:
+  - name: v1
+    labels:
+      version: v1
+  - name: v2
+    labels:
+      version: v2
+`
+
+func TestConvertIstioResources(t *testing.T) {
+	specsGroup := "specs.smi-spec.io"
+
+	testCases := []struct {
+		name                    string
+		resources               string
+		expectedTrafficSplits   []smiSplit.TrafficSplit
+		expectedHTTPRouteGroups []smiSpecs.HTTPRouteGroup
+		expectedWarnings        []string
+		expectErr               bool
+	}{
+		{
+			name:      "routes with matches, weights and subsets",
+			resources: reviewsVirtualService + "---\n" + reviewsDestinationRule,
+			expectedTrafficSplits: []smiSplit.TrafficSplit{
+				{
+					TypeMeta:   metav1.TypeMeta{APIVersion: "split.smi-spec.io/v1alpha4", Kind: "TrafficSplit"},
+					ObjectMeta: metav1.ObjectMeta{Name: "reviews-jason", Namespace: "bookinfo"},
+					Spec: smiSplit.TrafficSplitSpec{
+						Service:  "reviews",
+						Backends: []smiSplit.TrafficSplitBackend{{Service: "reviews-v2", Weight: 100}},
+						Matches:  []corev1.TypedLocalObjectReference{{APIGroup: &specsGroup, Kind: "HTTPRouteGroup", Name: "reviews-jason"}},
+					},
+				},
+				{
+					TypeMeta:   metav1.TypeMeta{APIVersion: "split.smi-spec.io/v1alpha4", Kind: "TrafficSplit"},
+					ObjectMeta: metav1.ObjectMeta{Name: "reviews-route-1", Namespace: "bookinfo"},
+					Spec: smiSplit.TrafficSplitSpec{
+						Service: "reviews",
+						Backends: []smiSplit.TrafficSplitBackend{
+							{Service: "reviews-v1", Weight: 80},
+							{Service: "reviews-v3", Weight: 20},
+						},
+					},
+				},
+			},
+			expectedHTTPRouteGroups: []smiSpecs.HTTPRouteGroup{
+				{
+					TypeMeta:   metav1.TypeMeta{APIVersion: "specs.smi-spec.io/v1alpha4", Kind: "HTTPRouteGroup"},
+					ObjectMeta: metav1.ObjectMeta{Name: "reviews-jason", Namespace: "bookinfo"},
+					Spec: smiSpecs.HTTPRouteGroupSpec{
+						Matches: []smiSpecs.HTTPMatch{
+							{
+								Name:      "jason-0",
+								PathRegex: "/reviews.*",
+								Headers:   map[string]string{"end-user": "jason"},
+							},
+						},
+					},
+				},
+			},
+			expectedWarnings: []string{
+				"DestinationRule bookinfo/reviews: trafficPolicy.connectionPool is not converted",
+				"VirtualService bookinfo/reviews: retries of route jason is not converted",
+				"VirtualService bookinfo/reviews: timeout of route route-1 is not converted",
+				"Create Service bookinfo/reviews-v1 with the ports of Service bookinfo/reviews, selecting the pods of subset v1 with labels version=v1",
+				"Create Service bookinfo/reviews-v2 with the ports of Service bookinfo/reviews, selecting the pods of subset v2 with labels version=v2",
+				"Create Service bookinfo/reviews-v3 with the ports of Service bookinfo/reviews, selecting the pods of subset v3 whose DestinationRule wasn't provided",
+			},
+		},
+		{
+			name: "catch-all route to the host itself needs no TrafficSplit",
+			resources: `
+apiVersion: networking.istio.io/v1alpha3
+kind: VirtualService
+metadata:
+  name: ratings
+  namespace: bookinfo
+spec:
+  hosts:
+  - ratings
+  http:
+  - route:
+    - destination:
+        host: ratings
+  - route:
+    - destination:
+        host: ratings-v2
+`,
+			expectedWarnings: []string{
+				"VirtualService bookinfo/ratings: the routes following route route-0 are unreachable and are not converted",
+			},
+		},
+		{
+			name: "list of resources with gateway, external and unsupported resources",
+			resources: `
+apiVersion: v1
+kind: List
+items:
+- apiVersion: networking.istio.io/v1beta1
+  kind: VirtualService
+  metadata:
+    name: ingress
+    namespace: bookinfo
+  spec:
+    hosts:
+    - bookinfo.example.com
+    gateways:
+    - bookinfo-gateway
+    http:
+    - route:
+      - destination:
+          host: productpage
+- apiVersion: networking.istio.io/v1beta1
+  kind: VirtualService
+  metadata:
+    name: external
+    namespace: bookinfo
+  spec:
+    hosts:
+    - "*.example.com"
+- apiVersion: networking.istio.io/v1beta1
+  kind: Gateway
+  metadata:
+    name: bookinfo-gateway
+    namespace: bookinfo
+- apiVersion: v1
+  kind: Service
+  metadata:
+    name: productpage
+    namespace: bookinfo
+`,
+			expectedWarnings: []string{
+				"VirtualService bookinfo/ingress applies to gateways bookinfo-gateway only and is not converted, use an IngressBackend for ingress traffic",
+				"Gateway bookinfo/bookinfo-gateway is not converted, only VirtualServices and DestinationRules are",
+				"Service bookinfo/productpage is not an Istio networking resource and is not converted",
+				"VirtualService bookinfo/external: host *.example.com is not a Kubernetes service and is not converted",
+			},
+		},
+		{
+			name:      "invalid resources",
+			resources: "kind: [",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			conversion, err := ConvertIstioResources(strings.NewReader(tc.resources))
+			if tc.expectErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.expectedTrafficSplits, conversion.TrafficSplits)
+			assert.Equal(tc.expectedHTTPRouteGroups, conversion.HTTPRouteGroups)
+			assert.ElementsMatch(tc.expectedWarnings, conversion.Warnings)
+		})
+	}
+}
+
+func TestConversionManifests(t *testing.T) {
+	assert := tassert.New(t)
+
+	conversion, err := ConvertIstioResources(strings.NewReader(reviewsVirtualService))
+	assert.NoError(err)
+
+	manifests, err := conversion.Manifests()
+	assert.NoError(err)
+
+	// The HTTPRouteGroup precedes the TrafficSplits referencing it
+	docs := strings.Split(string(manifests), "---\n")
+	assert.Len(docs, 3)
+	assert.Contains(docs[0], "kind: HTTPRouteGroup")
+	assert.Contains(docs[1], "kind: TrafficSplit")
+	assert.Contains(docs[1], "name: reviews-jason")
+	assert.Contains(docs[2], "kind: TrafficSplit")
+	assert.Contains(docs[2], "name: reviews-route-1")
+}
+
+func TestResolveHost(t *testing.T) {
+	testCases := []struct {
+		host              string
+		expectedService   string
+		expectedNamespace string
+		expectedOK        bool
+	}{
+		{"reviews", "reviews", "default", true},
+		{"reviews.bookinfo", "reviews", "bookinfo", true},
+		{"reviews.bookinfo.svc", "reviews", "bookinfo", true},
+		{"reviews.bookinfo.svc.cluster.local", "reviews", "bookinfo", true},
+		{"www.example.com", "", "", false},
+		{"*.bookinfo.svc.cluster.local", "", "", false},
+		{"", "", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.host, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			service, namespace, ok := resolveHost(tc.host, "default")
+			assert.Equal(tc.expectedService, service)
+			assert.Equal(tc.expectedNamespace, namespace)
+			assert.Equal(tc.expectedOK, ok)
+		})
+	}
+}
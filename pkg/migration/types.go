@@ -0,0 +1,117 @@
+// Package migration implements aids to migrate workloads from other service meshes to OSM side by side: the detection
+// of pods carrying the sidecar of another mesh, which OSM must not inject its own sidecar into, and the offline
+// conversion of the basics of Istio VirtualServices and DestinationRules into the SMI resources OSM implements.
+package migration
+
+import (
+	smiSpecs "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
+	smiSplit "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha4"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// MeshIstio is the name of the Istio service mesh
+	MeshIstio = "istio"
+
+	// MeshLinkerd is the name of the Linkerd service mesh
+	MeshLinkerd = "linkerd"
+)
+
+// OtherMeshSidecar describes the sidecar of another service mesh a pod carries or is about to be injected with
+type OtherMeshSidecar struct {
+	// Mesh is the name of the other service mesh
+	Mesh string
+
+	// Evidence explains what the sidecar was detected from
+	Evidence string
+}
+
+// Conversion is the result of the conversion of Istio resources into SMI resources
+type Conversion struct {
+	// TrafficSplits are the TrafficSplits converted from the HTTP routes of the VirtualServices
+	TrafficSplits []smiSplit.TrafficSplit
+
+	// HTTPRouteGroups are the HTTPRouteGroups holding the matches of the HTTP routes of the VirtualServices, referenced
+	// by the TrafficSplits
+	HTTPRouteGroups []smiSpecs.HTTPRouteGroup
+
+	// Warnings report the parts of the Istio resources which weren't converted, and the manual steps the converted
+	// resources require
+	Warnings []string
+}
+
+// virtualService is the subset of the Istio networking.istio.io VirtualService resource the conversion understands
+type virtualService struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              virtualServiceSpec `json:"spec"`
+}
+
+type virtualServiceSpec struct {
+	Hosts    []string         `json:"hosts,omitempty"`
+	Gateways []string         `json:"gateways,omitempty"`
+	HTTP     []istioHTTPRoute `json:"http,omitempty"`
+	TCP      []interface{}    `json:"tcp,omitempty"`
+	TLS      []interface{}    `json:"tls,omitempty"`
+}
+
+type istioHTTPRoute struct {
+	Name             string                  `json:"name,omitempty"`
+	Match            []istioHTTPMatch        `json:"match,omitempty"`
+	Route            []istioRouteDestination `json:"route,omitempty"`
+	Redirect         interface{}             `json:"redirect,omitempty"`
+	DirectResponse   interface{}             `json:"directResponse,omitempty"`
+	Delegate         interface{}             `json:"delegate,omitempty"`
+	Rewrite          interface{}             `json:"rewrite,omitempty"`
+	Timeout          string                  `json:"timeout,omitempty"`
+	Retries          interface{}             `json:"retries,omitempty"`
+	Fault            interface{}             `json:"fault,omitempty"`
+	Mirror           interface{}             `json:"mirror,omitempty"`
+	MirrorPercentage interface{}             `json:"mirrorPercentage,omitempty"`
+	CorsPolicy       interface{}             `json:"corsPolicy,omitempty"`
+	Headers          interface{}             `json:"headers,omitempty"`
+}
+
+type istioHTTPMatch struct {
+	URI         *istioStringMatch           `json:"uri,omitempty"`
+	Method      *istioStringMatch           `json:"method,omitempty"`
+	Headers     map[string]istioStringMatch `json:"headers,omitempty"`
+	Authority   *istioStringMatch           `json:"authority,omitempty"`
+	QueryParams map[string]istioStringMatch `json:"queryParams,omitempty"`
+	Port        int                         `json:"port,omitempty"`
+}
+
+type istioStringMatch struct {
+	Exact  string `json:"exact,omitempty"`
+	Prefix string `json:"prefix,omitempty"`
+	Regex  string `json:"regex,omitempty"`
+}
+
+type istioRouteDestination struct {
+	Destination istioDestination `json:"destination"`
+	Weight      int              `json:"weight,omitempty"`
+}
+
+type istioDestination struct {
+	Host   string `json:"host"`
+	Subset string `json:"subset,omitempty"`
+}
+
+// destinationRule is the subset of the Istio networking.istio.io DestinationRule resource the conversion understands
+type destinationRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              destinationRuleSpec `json:"spec"`
+}
+
+type destinationRuleSpec struct {
+	Host          string                 `json:"host"`
+	Subsets       []istioSubset          `json:"subsets,omitempty"`
+	TrafficPolicy map[string]interface{} `json:"trafficPolicy,omitempty"`
+}
+
+type istioSubset struct {
+	Name          string                 `json:"name"`
+	Labels        map[string]string      `json:"labels,omitempty"`
+	TrafficPolicy map[string]interface{} `json:"trafficPolicy,omitempty"`
+}
@@ -0,0 +1,152 @@
+package multicluster
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+const (
+	// exportedServicesKey is the key in the broker ConfigMap holding the services exported by a cluster
+	exportedServicesKey = "services"
+
+	// trustDomainKey is the key in the broker ConfigMap holding the trust domain of a cluster
+	trustDomainKey = "trustDomain"
+
+	// caBundleKey is the key in the broker ConfigMap holding the CA bundle of a cluster's trust domain
+	caBundleKey = "caBundle"
+
+	// brokerClusterLabelKey is the label identifying the cluster that published a broker ConfigMap
+	brokerClusterLabelKey = "multicluster.openservicemesh.io/cluster"
+
+	// brokerConfigMapPrefix is the prefix of the names of ConfigMaps published to the broker
+	brokerConfigMapPrefix = "osm-exported-services-"
+)
+
+// configMapBroker is a Broker that stores the services exported by each cluster in a ConfigMap
+// within a namespace of a Kubernetes cluster shared by all clusters in the mesh.
+type configMapBroker struct {
+	kubeClient kubernetes.Interface
+	namespace  string
+}
+
+// NewConfigMapBroker returns a Broker backed by ConfigMaps in the given namespace of the broker cluster
+func NewConfigMapBroker(kubeClient kubernetes.Interface, namespace string) Broker {
+	return &configMapBroker{
+		kubeClient: kubeClient,
+		namespace:  namespace,
+	}
+}
+
+// Publish publishes the services exported by the given cluster
+func (b *configMapBroker) Publish(clusterName string, services []ExportedService) error {
+	data, err := json.Marshal(services)
+	if err != nil {
+		return errors.Wrapf(err, "Error marshalling services exported by cluster %s", clusterName)
+	}
+
+	return b.apply(clusterName, map[string]string{
+		exportedServicesKey: string(data),
+	})
+}
+
+// PublishTrustBundle publishes the CA bundle of the given cluster's trust domain
+func (b *configMapBroker) PublishTrustBundle(clusterName string, trustDomain string, caBundle []byte) error {
+	return b.apply(clusterName, map[string]string{
+		trustDomainKey: trustDomain,
+		caBundleKey:    string(caBundle),
+	})
+}
+
+// apply creates or updates the ConfigMap published by the given cluster with the given data. Keys not present
+// in the given data are preserved.
+func (b *configMapBroker) apply(clusterName string, data map[string]string) error {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      brokerConfigMapPrefix + clusterName,
+			Namespace: b.namespace,
+			Labels: map[string]string{
+				brokerClusterLabelKey: clusterName,
+				managedByLabelKey:     ManagedByLabelValue,
+			},
+		},
+		Data: data,
+	}
+
+	configMaps := b.kubeClient.CoreV1().ConfigMaps(b.namespace)
+	existing, err := configMaps.Get(context.Background(), configMap.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = configMaps.Create(context.Background(), configMap, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	existing.Labels = configMap.Labels
+	if existing.Data == nil {
+		existing.Data = make(map[string]string)
+	}
+	for key, value := range data {
+		existing.Data[key] = value
+	}
+	_, err = configMaps.Update(context.Background(), existing, metav1.UpdateOptions{})
+	return err
+}
+
+// List returns the services exported by all clusters publishing to the broker
+func (b *configMapBroker) List() (map[string][]ExportedService, error) {
+	configMaps, err := b.kubeClient.CoreV1().ConfigMaps(b.namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: managedByLabelKey + "=" + ManagedByLabelValue,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	servicesByCluster := make(map[string][]ExportedService)
+	for _, configMap := range configMaps.Items {
+		clusterName, ok := configMap.Labels[brokerClusterLabelKey]
+		if !ok {
+			continue
+		}
+
+		var services []ExportedService
+		if err := json.Unmarshal([]byte(configMap.Data[exportedServicesKey]), &services); err != nil {
+			log.Error().Err(err).Str(constants.LogFieldContext, constants.LogContextMulticluster).
+				Msgf("Error unmarshalling services exported by cluster %s from ConfigMap %s/%s", clusterName, configMap.Namespace, configMap.Name)
+			continue
+		}
+		servicesByCluster[clusterName] = services
+	}
+
+	return servicesByCluster, nil
+}
+
+// ListTrustBundles returns the CA bundles published by all clusters publishing to the broker
+func (b *configMapBroker) ListTrustBundles() (map[string][]byte, error) {
+	configMaps, err := b.kubeClient.CoreV1().ConfigMaps(b.namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: managedByLabelKey + "=" + ManagedByLabelValue,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	trustBundles := make(map[string][]byte)
+	for _, configMap := range configMaps.Items {
+		trustDomain := configMap.Data[trustDomainKey]
+		caBundle := configMap.Data[caBundleKey]
+		if trustDomain == "" || caBundle == "" {
+			continue
+		}
+		trustBundles[trustDomain] = []byte(caBundle)
+	}
+
+	return trustBundles, nil
+}
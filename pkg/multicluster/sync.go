@@ -0,0 +1,321 @@
+package multicluster
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/certificate/providers"
+	"github.com/openservicemesh/osm/pkg/constants"
+	configClientset "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// NewSyncer returns a Syncer that syncs exported services between the local cluster and the broker
+func NewSyncer(cfg SyncerConfig, kubeController k8s.Controller, configClient configClientset.Interface, certManager certificate.Manager) *Syncer {
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = DefaultSyncInterval
+	}
+
+	return &Syncer{
+		clusterName:    cfg.ClusterName,
+		gatewayAddress: cfg.GatewayAddress,
+		trustDomain:    cfg.TrustDomain,
+		interval:       interval,
+		broker:         NewConfigMapBroker(cfg.BrokerClient, cfg.BrokerNamespace),
+		kubeController: kubeController,
+		configClient:   configClient,
+		certManager:    certManager,
+		eventRecorder:  cfg.EventRecorder,
+	}
+}
+
+// Run starts syncing exported services at the configured interval until the stop channel is closed
+func (s *Syncer) Run(stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			s.sync()
+
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// sync publishes the services exported by the local cluster and imports the services exported by remote clusters
+func (s *Syncer) sync() {
+	if err := s.broker.Publish(s.clusterName, s.listExportedServices()); err != nil {
+		log.Error().Err(err).Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("Error publishing services exported by cluster %s", s.clusterName)
+		return
+	}
+
+	s.syncTrustBundles()
+
+	servicesByCluster, err := s.broker.List()
+	if err != nil {
+		log.Error().Err(err).Str(constants.LogFieldContext, constants.LogContextMulticluster).Msg("Error listing services exported by remote clusters")
+		return
+	}
+
+	s.importServices(servicesByCluster)
+}
+
+// syncTrustBundles publishes the CA bundle of the local trust domain and stores the CA bundles of remote trust domains
+func (s *Syncer) syncTrustBundles() {
+	if s.trustDomain == "" {
+		return
+	}
+
+	store, ok := s.certManager.(certificate.TrustBundleStore)
+	if !ok {
+		log.Error().Str(constants.LogFieldContext, constants.LogContextMulticluster).Msg("Certificate manager does not store trust bundles, skipping trust bundle sync")
+		return
+	}
+
+	localBundle, err := providers.GetLocalTrustBundle(s.certManager)
+	if err != nil {
+		log.Error().Err(err).Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("Error getting CA bundle for trust domain %s", s.trustDomain)
+		return
+	}
+	if err := s.broker.PublishTrustBundle(s.clusterName, s.trustDomain, localBundle); err != nil {
+		log.Error().Err(err).Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("Error publishing CA bundle for trust domain %s", s.trustDomain)
+		return
+	}
+
+	trustBundles, err := s.broker.ListTrustBundles()
+	if err != nil {
+		log.Error().Err(err).Str(constants.LogFieldContext, constants.LogContextMulticluster).Msg("Error listing CA bundles of remote trust domains")
+		return
+	}
+
+	for trustDomain, caBundle := range trustBundles {
+		if trustDomain == s.trustDomain {
+			continue
+		}
+		if existing, err := store.GetTrustBundle(trustDomain); err == nil && bytes.Equal(existing, caBundle) {
+			continue
+		}
+		if err := store.SetTrustBundle(trustDomain, caBundle); err != nil {
+			log.Error().Err(err).Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("Error storing CA bundle for trust domain %s", trustDomain)
+		}
+	}
+}
+
+// listExportedServices returns the services in monitored namespaces annotated to be exported to remote clusters
+func (s *Syncer) listExportedServices() []ExportedService {
+	var exported []ExportedService
+
+	for _, svc := range s.kubeController.ListServices() {
+		if !k8s.IsMulticlusterExported(svc) {
+			continue
+		}
+
+		meshSvc := service.MeshService{Name: svc.Name, Namespace: svc.Namespace}
+		serviceAccounts, err := s.kubeController.ListServiceIdentitiesForService(meshSvc)
+		if err != nil || len(serviceAccounts) == 0 {
+			log.Error().Err(err).Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("Error exporting service %s, no service account found", meshSvc)
+			continue
+		}
+		if len(serviceAccounts) > 1 {
+			log.Warn().Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("Service %s is backed by multiple service accounts, exporting it with service account %s", meshSvc, serviceAccounts[0])
+		}
+
+		var ports []v1alpha1.PortSpec
+		for _, port := range svc.Spec.Ports {
+			ports = append(ports, v1alpha1.PortSpec{
+				Port:     uint32(port.Port),
+				Protocol: k8s.GetAppProtocolFromServicePort(port),
+			})
+		}
+
+		exported = append(exported, ExportedService{
+			Name:           svc.Name,
+			Namespace:      svc.Namespace,
+			ServiceAccount: serviceAccounts[0].Name,
+			Ports:          ports,
+			GatewayAddress: s.gatewayAddress,
+			Health:         s.getServiceHealth(meshSvc),
+			TrustDomain:    s.trustDomain,
+		})
+	}
+
+	return exported
+}
+
+// getServiceHealth returns the health of the given service's endpoints in the local cluster, or nil if the
+// endpoints could not be fetched. A nil health is left unset on the importing side, where it is assumed healthy,
+// so that a transient error does not take the service out of rotation in remote clusters.
+func (s *Syncer) getServiceHealth(svc service.MeshService) *v1alpha1.ClusterHealth {
+	endpoints, err := s.kubeController.GetEndpoints(svc)
+	if err != nil {
+		log.Error().Err(err).Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("Error getting endpoints of exported service %s, not publishing its health", svc)
+		return nil
+	}
+
+	health := &v1alpha1.ClusterHealth{}
+	if endpoints == nil {
+		return health
+	}
+
+	for _, subset := range endpoints.Subsets {
+		health.HealthyEndpoints += uint32(len(subset.Addresses))
+		health.TotalEndpoints += uint32(len(subset.Addresses) + len(subset.NotReadyAddresses))
+	}
+	return health
+}
+
+// importServices creates, updates and deletes the MultiClusterService resources managed by the syncer
+// so that they match the services exported by remote clusters
+func (s *Syncer) importServices(servicesByCluster map[string][]ExportedService) {
+	desired := make(map[service.MeshService]*v1alpha1.MultiClusterService)
+	conflicts := make(map[service.MeshService][]string)
+
+	// Sort the cluster names so the generated cluster lists are stable across syncs
+	var clusterNames []string
+	for clusterName := range servicesByCluster {
+		if clusterName == s.clusterName {
+			continue
+		}
+		clusterNames = append(clusterNames, clusterName)
+	}
+	sort.Strings(clusterNames)
+
+	for _, clusterName := range clusterNames {
+		for _, exported := range servicesByCluster[clusterName] {
+			if !s.kubeController.IsMonitoredNamespace(exported.Namespace) {
+				continue
+			}
+
+			key := service.MeshService{Name: exported.Name, Namespace: exported.Namespace}
+			mcs, ok := desired[key]
+			if !ok {
+				mcs = &v1alpha1.MultiClusterService{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      exported.Name,
+						Namespace: exported.Namespace,
+						Labels:    map[string]string{managedByLabelKey: ManagedByLabelValue},
+					},
+					Spec: v1alpha1.MultiClusterServiceSpec{
+						ServiceAccount: exported.ServiceAccount,
+						Ports:          exported.Ports,
+					},
+				}
+				desired[key] = mcs
+			} else if conflict := getExportConflict(mcs, exported); conflict != "" {
+				// The first cluster in name order defines the service account and ports of the service, clusters
+				// exporting the service with a different spec are left out until the conflict is resolved.
+				log.Error().Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("Not importing service %s from cluster %s, %s", key, clusterName, conflict)
+				conflicts[key] = append(conflicts[key], fmt.Sprintf("cluster %s: %s", clusterName, conflict))
+				continue
+			}
+
+			mcs.Spec.Clusters = append(mcs.Spec.Clusters, v1alpha1.ClusterSpec{
+				Name:        clusterName,
+				Address:     exported.GatewayAddress,
+				Health:      exported.Health,
+				TrustDomain: exported.TrustDomain,
+			})
+		}
+	}
+
+	for key, mcs := range desired {
+		applied := s.applyMultiClusterService(mcs)
+		if applied == nil || len(conflicts[key]) == 0 || s.eventRecorder == nil {
+			continue
+		}
+		s.eventRecorder.WarnEvent(applied, events.MulticlusterExportConflict, "Service %s is exported with conflicting specs, ignoring %s",
+			key, strings.Join(conflicts[key], "; "))
+	}
+
+	s.deleteStaleMultiClusterServices(desired)
+}
+
+// getExportConflict returns a description of the differences between the spec of the given MultiClusterService
+// and the given exported service, or an empty string if the exported service is consistent with the spec
+func getExportConflict(mcs *v1alpha1.MultiClusterService, exported ExportedService) string {
+	var conflicts []string
+	if exported.ServiceAccount != mcs.Spec.ServiceAccount {
+		conflicts = append(conflicts, fmt.Sprintf("service account %s differs from %s", exported.ServiceAccount, mcs.Spec.ServiceAccount))
+	}
+	if !reflect.DeepEqual(exported.Ports, mcs.Spec.Ports) {
+		conflicts = append(conflicts, fmt.Sprintf("ports %v differ from %v", exported.Ports, mcs.Spec.Ports))
+	}
+	return strings.Join(conflicts, ", ")
+}
+
+// applyMultiClusterService creates or updates the given MultiClusterService and returns the stored resource, or
+// nil if it could not be applied. MultiClusterService resources that were not created by the syncer are left untouched.
+func (s *Syncer) applyMultiClusterService(mcs *v1alpha1.MultiClusterService) *v1alpha1.MultiClusterService {
+	client := s.configClient.ConfigV1alpha1().MultiClusterServices(mcs.Namespace)
+
+	existing, err := client.Get(context.Background(), mcs.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		created, err := client.Create(context.Background(), mcs, metav1.CreateOptions{})
+		if err != nil {
+			log.Error().Err(err).Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("Error creating MultiClusterService %s/%s", mcs.Namespace, mcs.Name)
+			return nil
+		}
+		return created
+	}
+	if err != nil {
+		log.Error().Err(err).Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("Error getting MultiClusterService %s/%s", mcs.Namespace, mcs.Name)
+		return nil
+	}
+
+	if existing.Labels[managedByLabelKey] != ManagedByLabelValue {
+		log.Warn().Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("MultiClusterService %s/%s is not managed by the multicluster sync controller, skipping update", mcs.Namespace, mcs.Name)
+		return nil
+	}
+	if reflect.DeepEqual(existing.Spec, mcs.Spec) {
+		return existing
+	}
+
+	existing.Spec = mcs.Spec
+	updated, err := client.Update(context.Background(), existing, metav1.UpdateOptions{})
+	if err != nil {
+		log.Error().Err(err).Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("Error updating MultiClusterService %s/%s", mcs.Namespace, mcs.Name)
+		return nil
+	}
+	return updated
+}
+
+// deleteStaleMultiClusterServices deletes the MultiClusterService resources managed by the syncer
+// that no longer correspond to a service exported by a remote cluster
+func (s *Syncer) deleteStaleMultiClusterServices(desired map[service.MeshService]*v1alpha1.MultiClusterService) {
+	list, err := s.configClient.ConfigV1alpha1().MultiClusterServices(corev1.NamespaceAll).List(context.Background(), metav1.ListOptions{
+		LabelSelector: managedByLabelKey + "=" + ManagedByLabelValue,
+	})
+	if err != nil {
+		log.Error().Err(err).Str(constants.LogFieldContext, constants.LogContextMulticluster).Msg("Error listing MultiClusterService resources managed by the multicluster sync controller")
+		return
+	}
+
+	for _, mcs := range list.Items {
+		if _, ok := desired[service.MeshService{Name: mcs.Name, Namespace: mcs.Namespace}]; ok {
+			continue
+		}
+		err := s.configClient.ConfigV1alpha1().MultiClusterServices(mcs.Namespace).Delete(context.Background(), mcs.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			log.Error().Err(err).Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("Error deleting stale MultiClusterService %s/%s", mcs.Namespace, mcs.Name)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package multicluster
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/constants"
+	fakeConfigClientset "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned/fake"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/tests"
+)
+
+func TestSync(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	brokerClient := testclient.NewSimpleClientset()
+	configClient := fakeConfigClientset.NewSimpleClientset()
+	mockKubeController := k8s.NewMockController(mockCtrl)
+
+	exportedSvc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        tests.BookstoreV1ServiceName,
+			Namespace:   tests.Namespace,
+			Annotations: map[string]string{constants.MulticlusterExportAnnotation: "true"},
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Name: "grpc-api", Port: 8080, Protocol: corev1.ProtocolTCP}},
+		},
+	}
+	notExportedSvc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      tests.BookbuyerServiceName,
+			Namespace: tests.Namespace,
+		},
+	}
+	meshSvc := service.MeshService{Name: exportedSvc.Name, Namespace: exportedSvc.Namespace}
+
+	mockKubeController.EXPECT().ListServices().Return([]*corev1.Service{exportedSvc, notExportedSvc}).AnyTimes()
+	mockKubeController.EXPECT().ListServiceIdentitiesForService(meshSvc).Return([]identity.K8sServiceAccount{tests.BookstoreServiceAccount}, nil).AnyTimes()
+	mockKubeController.EXPECT().GetEndpoints(meshSvc).Return(&corev1.Endpoints{
+		Subsets: []corev1.EndpointSubset{{
+			Addresses:         []corev1.EndpointAddress{{IP: "10.0.0.1"}, {IP: "10.0.0.2"}},
+			NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.0.3"}},
+		}},
+	}, nil).AnyTimes()
+	mockKubeController.EXPECT().IsMonitoredNamespace(tests.Namespace).Return(true).AnyTimes()
+
+	alpha := NewSyncer(SyncerConfig{
+		ClusterName:     "alpha",
+		GatewayAddress:  "1.2.3.4:15443",
+		BrokerClient:    brokerClient,
+		BrokerNamespace: "osm-broker",
+	}, mockKubeController, configClient, nil)
+	beta := NewSyncer(SyncerConfig{
+		ClusterName:     "beta",
+		GatewayAddress:  "5.6.7.8:15443",
+		BrokerClient:    brokerClient,
+		BrokerNamespace: "osm-broker",
+	}, mockKubeController, configClient, nil)
+
+	// Both clusters export the bookstore service; the syncer for cluster alpha only imports the service
+	// exported by cluster beta.
+	assert.Nil(beta.broker.Publish("beta", beta.listExportedServices()))
+	alpha.sync()
+
+	mcs, err := configClient.ConfigV1alpha1().MultiClusterServices(tests.Namespace).Get(context.TODO(), tests.BookstoreV1ServiceName, metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Equal(ManagedByLabelValue, mcs.Labels[managedByLabelKey])
+	assert.Equal(tests.BookstoreServiceAccountName, mcs.Spec.ServiceAccount)
+	assert.Equal([]v1alpha1.PortSpec{{Port: 8080, Protocol: "grpc"}}, mcs.Spec.Ports)
+	assert.Equal([]v1alpha1.ClusterSpec{{
+		Name:    "beta",
+		Address: "5.6.7.8:15443",
+		Health:  &v1alpha1.ClusterHealth{HealthyEndpoints: 2, TotalEndpoints: 3},
+	}}, mcs.Spec.Clusters)
+
+	// Once cluster beta stops exporting the service, the MultiClusterService is deleted
+	assert.Nil(beta.broker.Publish("beta", nil))
+	alpha.sync()
+
+	_, err = configClient.ConfigV1alpha1().MultiClusterServices(tests.Namespace).Get(context.TODO(), tests.BookstoreV1ServiceName, metav1.GetOptions{})
+	assert.NotNil(err)
+}
+
+func TestGetServiceHealth(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	s := &Syncer{kubeController: mockKubeController}
+	meshSvc := service.MeshService{Name: tests.BookstoreV1ServiceName, Namespace: tests.Namespace}
+
+	// A service without endpoints has zero healthy endpoints
+	mockKubeController.EXPECT().GetEndpoints(meshSvc).Return(nil, nil).Times(1)
+	assert.Equal(&v1alpha1.ClusterHealth{}, s.getServiceHealth(meshSvc))
+
+	// The health of a service whose endpoints could not be fetched is unknown
+	mockKubeController.EXPECT().GetEndpoints(meshSvc).Return(nil, errors.New("fake error")).Times(1)
+	assert.Nil(s.getServiceHealth(meshSvc))
+}
+
+func TestImportServicesConflicts(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	configClient := fakeConfigClientset.NewSimpleClientset()
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().IsMonitoredNamespace(tests.Namespace).Return(true).AnyTimes()
+	s := &Syncer{clusterName: "alpha", kubeController: mockKubeController, configClient: configClient}
+
+	ports := []v1alpha1.PortSpec{{Port: 8080, Protocol: "http"}}
+	s.importServices(map[string][]ExportedService{
+		"beta": {{Name: "bookstore", Namespace: tests.Namespace, ServiceAccount: "bookstore", Ports: ports, GatewayAddress: "5.6.7.8:15443"}},
+		// Cluster gamma exports the service with a different service account and is left out
+		"gamma": {{Name: "bookstore", Namespace: tests.Namespace, ServiceAccount: "other", Ports: ports, GatewayAddress: "9.9.9.9:15443"}},
+		"delta": {{Name: "bookstore", Namespace: tests.Namespace, ServiceAccount: "bookstore", Ports: ports, GatewayAddress: "1.1.1.1:15443"}},
+	})
+
+	mcs, err := configClient.ConfigV1alpha1().MultiClusterServices(tests.Namespace).Get(context.TODO(), "bookstore", metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Equal("bookstore", mcs.Spec.ServiceAccount)
+	assert.Equal([]v1alpha1.ClusterSpec{
+		{Name: "beta", Address: "5.6.7.8:15443"},
+		{Name: "delta", Address: "1.1.1.1:15443"},
+	}, mcs.Spec.Clusters)
+}
+
+func TestGetExportConflict(t *testing.T) {
+	assert := tassert.New(t)
+
+	mcs := &v1alpha1.MultiClusterService{
+		Spec: v1alpha1.MultiClusterServiceSpec{
+			ServiceAccount: "bookstore",
+			Ports:          []v1alpha1.PortSpec{{Port: 8080, Protocol: "http"}},
+		},
+	}
+
+	assert.Empty(getExportConflict(mcs, ExportedService{ServiceAccount: "bookstore", Ports: []v1alpha1.PortSpec{{Port: 8080, Protocol: "http"}}}))
+	assert.Contains(getExportConflict(mcs, ExportedService{ServiceAccount: "other", Ports: []v1alpha1.PortSpec{{Port: 8080, Protocol: "http"}}}), "service account")
+	assert.Contains(getExportConflict(mcs, ExportedService{ServiceAccount: "bookstore", Ports: []v1alpha1.PortSpec{{Port: 8080, Protocol: "tcp"}}}), "ports")
+}
+
+func TestSyncTrustBundles(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	brokerClient := testclient.NewSimpleClientset()
+	broker := NewConfigMapBroker(brokerClient, "osm-broker")
+
+	mockRootCert := certificate.NewMockCertificater(mockCtrl)
+	mockRootCert.EXPECT().GetIssuingCA().Return([]byte("ca-alpha")).AnyTimes()
+	mockCertManager := certificate.NewMockManager(mockCtrl)
+	mockCertManager.EXPECT().GetRootCertificate().Return(mockRootCert, nil).AnyTimes()
+	mockTrustBundleStore := certificate.NewMockTrustBundleStore(mockCtrl)
+	certManager := struct {
+		certificate.Manager
+		certificate.TrustBundleStore
+	}{mockCertManager, mockTrustBundleStore}
+
+	s := &Syncer{
+		clusterName: "alpha",
+		trustDomain: "alpha.example.com",
+		broker:      broker,
+		certManager: certManager,
+	}
+
+	// Cluster beta published its services before its trust bundle, both must be preserved
+	assert.Nil(broker.Publish("beta", []ExportedService{{Name: "bookstore", Namespace: "bookstore"}}))
+	assert.Nil(broker.PublishTrustBundle("beta", "beta.example.com", []byte("ca-beta")))
+
+	mockTrustBundleStore.EXPECT().GetTrustBundle("beta.example.com").Return(nil, errors.New("not found")).Times(1)
+	mockTrustBundleStore.EXPECT().SetTrustBundle("beta.example.com", []byte("ca-beta")).Return(nil).Times(1)
+	s.syncTrustBundles()
+
+	trustBundles, err := broker.ListTrustBundles()
+	assert.Nil(err)
+	assert.Equal(map[string][]byte{
+		"alpha.example.com": []byte("ca-alpha"),
+		"beta.example.com":  []byte("ca-beta"),
+	}, trustBundles)
+
+	servicesByCluster, err := broker.List()
+	assert.Nil(err)
+	assert.Len(servicesByCluster["beta"], 1)
+
+	// Unchanged trust bundles are not stored again
+	mockTrustBundleStore.EXPECT().GetTrustBundle("beta.example.com").Return([]byte("ca-beta"), nil).Times(1)
+	s.syncTrustBundles()
+}
+
+func TestApplyMultiClusterServiceSkipsUnmanaged(t *testing.T) {
+	assert := tassert.New(t)
+
+	unmanaged := &v1alpha1.MultiClusterService{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"},
+		Spec: v1alpha1.MultiClusterServiceSpec{
+			ServiceAccount: "sa",
+			Clusters:       []v1alpha1.ClusterSpec{{Name: "hand-authored", Address: "1.1.1.1:15443"}},
+		},
+	}
+	configClient := fakeConfigClientset.NewSimpleClientset(unmanaged)
+	s := &Syncer{configClient: configClient}
+
+	s.applyMultiClusterService(&v1alpha1.MultiClusterService{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"},
+		Spec:       v1alpha1.MultiClusterServiceSpec{ServiceAccount: "other"},
+	})
+
+	mcs, err := configClient.ConfigV1alpha1().MultiClusterServices("bar").Get(context.TODO(), "foo", metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Equal(unmanaged.Spec, mcs.Spec)
+}
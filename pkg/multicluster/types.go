@@ -0,0 +1,120 @@
+// Package multicluster implements the components used by OSM to enable communication between services across
+// clusters, such as the multicluster gateway and the controller syncing exported services between clusters.
+//
+// Downstreams from remote trust domains reach the services exported by a cluster through its multicluster gateway.
+// The gateway terminates their TLS connections, presenting the certificate of the exported service, and originates
+// new mTLS connections to the service with its own identity. The downstream's identity is therefore enforced by the
+// gateway: it only allows the sources of the service's SMI TrafficTargets, while the service allows the gateway
+// the routes of those TrafficTargets. Service identities are not qualified by trust domain, so a remote downstream
+// is granted the access of the local service account with the same name and namespace.
+package multicluster
+
+import (
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	configClientset "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var (
+	log = logger.New("multicluster")
+)
+
+const (
+	// DefaultSyncInterval is the default interval at which exported services are synced between clusters
+	DefaultSyncInterval = 30 * time.Second
+
+	// ManagedByLabelValue is the value of the 'app.kubernetes.io/managed-by' label set on MultiClusterService
+	// resources created by the multicluster sync controller. Only MultiClusterService resources carrying this
+	// label are updated or deleted by the controller.
+	ManagedByLabelValue = "osm-multicluster-sync"
+
+	// managedByLabelKey is the key of the label used to identify resources managed by the sync controller
+	managedByLabelKey = "app.kubernetes.io/managed-by"
+)
+
+// ExportedService is the type used to represent a service exported by a cluster to other clusters in the mesh.
+type ExportedService struct {
+	// Name is the name of the exported service.
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the exported service.
+	Namespace string `json:"namespace"`
+
+	// ServiceAccount is the service account backing the exported service.
+	ServiceAccount string `json:"serviceAccount"`
+
+	// Ports is the list of ports exposed by the exported service.
+	Ports []v1alpha1.PortSpec `json:"ports,omitempty"`
+
+	// GatewayAddress is the address (IP:port) of the exporting cluster's multicluster gateway.
+	GatewayAddress string `json:"gatewayAddress"`
+
+	// Health is the health of the service's endpoints in the exporting cluster, nil if it could not be determined.
+	Health *v1alpha1.ClusterHealth `json:"health,omitempty"`
+
+	// TrustDomain is the trust domain of the exporting cluster, empty if the cluster does not have one.
+	TrustDomain string `json:"trustDomain,omitempty"`
+}
+
+// Broker is the interface used to exchange exported services between the clusters participating in a mesh.
+type Broker interface {
+	// Publish publishes the services exported by the given cluster, replacing any services previously published by it.
+	Publish(clusterName string, services []ExportedService) error
+
+	// List returns the services exported by all clusters, keyed by the name of the exporting cluster.
+	List() (map[string][]ExportedService, error)
+
+	// PublishTrustBundle publishes the CA bundle of the given cluster's trust domain.
+	PublishTrustBundle(clusterName string, trustDomain string, caBundle []byte) error
+
+	// ListTrustBundles returns the CA bundles published by all clusters, keyed by trust domain.
+	ListTrustBundles() (map[string][]byte, error)
+}
+
+// Syncer is the controller that exports the services of the local cluster to the broker and creates/updates
+// MultiClusterService resources for services exported by remote clusters.
+type Syncer struct {
+	clusterName    string
+	gatewayAddress string
+	trustDomain    string
+	interval       time.Duration
+
+	broker         Broker
+	kubeController k8s.Controller
+	configClient   configClientset.Interface
+	certManager    certificate.Manager
+	eventRecorder  *events.ObjectEventRecorder
+}
+
+// SyncerConfig is the configuration used to create a Syncer.
+type SyncerConfig struct {
+	// ClusterName is the unique name of the local cluster among the clusters sharing the broker.
+	ClusterName string
+
+	// GatewayAddress is the address (IP:port) of the local multicluster gateway reachable from remote clusters.
+	GatewayAddress string
+
+	// TrustDomain is the trust domain of the local cluster. When set, the CA bundle of the local cluster is
+	// exchanged with remote clusters so that their multicluster gateways can validate the local proxies.
+	TrustDomain string
+
+	// Interval is the interval at which services are synced with the broker.
+	Interval time.Duration
+
+	// BrokerClient is the Kubernetes client for the cluster hosting the broker.
+	BrokerClient kubernetes.Interface
+
+	// BrokerNamespace is the namespace on the broker cluster in which exported services are stored.
+	BrokerNamespace string
+
+	// EventRecorder records events against the MultiClusterService resources managed by the syncer, such as
+	// conflicts between the specs of a service exported by several clusters. Events are not recorded if nil.
+	EventRecorder *events.ObjectEventRecorder
+}
@@ -0,0 +1,129 @@
+package onboarding
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+)
+
+// NewController returns a Controller adding the namespaces matching the configured label selector to the given mesh
+func NewController(cfg Config, kubeClient kubernetes.Interface, meshName, osmNamespace string, recorder *events.ObjectEventRecorder) (*Controller, error) {
+	if strings.TrimSpace(cfg.Selector) == "" {
+		return nil, errors.New("The namespace selector must not be empty")
+	}
+	selector, err := labels.Parse(cfg.Selector)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid namespace selector %s", cfg.Selector)
+	}
+
+	protected := map[string]struct{}{osmNamespace: {}}
+	for _, ns := range append(systemNamespaces, cfg.ProtectedNamespaces...) {
+		protected[ns] = struct{}{}
+	}
+
+	return &Controller{
+		kubeClient: kubeClient,
+		selector:   selector,
+		protected:  protected,
+		meshName:   meshName,
+		recorder:   recorder,
+	}, nil
+}
+
+// Run starts adding the namespaces matching the label selector to the mesh as they are created or labeled, until the
+// stop channel is closed. Namespaces that stop matching the selector are not removed from the mesh.
+func (c *Controller) Run(stop <-chan struct{}) {
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(c.kubeClient, k8s.DefaultKubeEventResyncInterval,
+		informers.WithTweakListOptions(func(opt *metav1.ListOptions) {
+			opt.LabelSelector = c.selector.String()
+		}))
+	informer := informerFactory.Core().V1().Namespaces().Informer()
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if ns, ok := obj.(*corev1.Namespace); ok {
+				c.enroll(ns)
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if ns, ok := obj.(*corev1.Namespace); ok {
+				c.enroll(ns)
+			}
+		},
+	})
+
+	log.Info().Msgf("Adding the namespaces matching %s to mesh %s", c.selector, c.meshName)
+	go informer.Run(stop)
+}
+
+// enroll adds the given namespace to the mesh if it matches the label selector and is not protected
+func (c *Controller) enroll(ns *corev1.Namespace) {
+	if !c.shouldEnroll(ns) {
+		return
+	}
+
+	monitoredBy := ns.Labels[constants.OSMKubeResourceMonitorAnnotation]
+	if monitoredBy != "" && monitoredBy != c.meshName {
+		c.recorder.WarnEvent(ns, events.NamespaceEnrollmentConflict,
+			"Namespace %s matches the onboarding selector of mesh %s but is already monitored by mesh %s", ns.Name, c.meshName, monitoredBy)
+		return
+	}
+
+	patch := getEnrollmentPatch(ns, c.meshName)
+	if patch == nil {
+		return
+	}
+	if _, err := c.kubeClient.CoreV1().Namespaces().Patch(context.Background(), ns.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		log.Error().Err(err).Msgf("Error adding namespace %s to mesh %s", ns.Name, c.meshName)
+		return
+	}
+	c.recorder.NormalEvent(ns, events.NamespaceEnrolled, "Namespace %s added to mesh %s", ns.Name, c.meshName)
+}
+
+// shouldEnroll returns whether the given namespace may be added to the mesh
+func (c *Controller) shouldEnroll(ns *corev1.Namespace) bool {
+	if !c.selector.Matches(labels.Set(ns.Labels)) {
+		return false
+	}
+	if _, ok := c.protected[ns.Name]; ok {
+		log.Debug().Msgf("Namespace %s matches the onboarding selector but is protected", ns.Name)
+		return false
+	}
+	if ns.Labels[constants.IgnoreLabel] == "true" {
+		log.Debug().Msgf("Namespace %s matches the onboarding selector but is ignored", ns.Name)
+		return false
+	}
+	return ns.DeletionTimestamp == nil
+}
+
+// getEnrollmentPatch returns the patch adding the given namespace to the given mesh, or nil if it is already part of
+// the mesh. Sidecar injection is enabled unless the namespace already sets the sidecar injection annotation, so that
+// namespaces can opt out of injection.
+func getEnrollmentPatch(ns *corev1.Namespace, meshName string) []byte {
+	metadata := map[string]interface{}{}
+	if ns.Labels[constants.OSMKubeResourceMonitorAnnotation] != meshName {
+		metadata["labels"] = map[string]string{constants.OSMKubeResourceMonitorAnnotation: meshName}
+	}
+	if _, ok := ns.Annotations[constants.SidecarInjectionAnnotation]; !ok {
+		metadata["annotations"] = map[string]string{constants.SidecarInjectionAnnotation: "enabled"}
+	}
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	// The patch only holds strings and can't fail to be marshaled
+	patch, _ := json.Marshal(map[string]interface{}{"metadata": metadata})
+	return patch
+}
@@ -0,0 +1,149 @@
+package onboarding
+
+import (
+	"context"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+)
+
+func TestNewController(t *testing.T) {
+	testCases := []struct {
+		name        string
+		selector    string
+		expectedErr bool
+	}{
+		{
+			name:     "valid selector",
+			selector: "tenant in (a, b)",
+		},
+		{
+			name:        "empty selector",
+			selector:    " ",
+			expectedErr: true,
+		},
+		{
+			name:        "invalid selector",
+			selector:    "tenant in a",
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			c, err := NewController(Config{Selector: tc.selector}, fake.NewSimpleClientset(), "osm", "osm-system", nil)
+			assert.Equal(tc.expectedErr, err != nil)
+			assert.Equal(tc.expectedErr, c == nil)
+		})
+	}
+}
+
+func TestEnroll(t *testing.T) {
+	newNamespace := func(name string, labels, annotations map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels, Annotations: annotations}}
+	}
+
+	testCases := []struct {
+		name                string
+		namespace           *corev1.Namespace
+		expectedLabels      map[string]string
+		expectedAnnotations map[string]string
+	}{
+		{
+			name:      "matching namespace",
+			namespace: newNamespace("tenant-a", map[string]string{"tenant": "a"}, nil),
+			expectedLabels: map[string]string{
+				"tenant":                                   "a",
+				constants.OSMKubeResourceMonitorAnnotation: "osm",
+			},
+			expectedAnnotations: map[string]string{constants.SidecarInjectionAnnotation: "enabled"},
+		},
+		{
+			name:           "namespace not matching the selector",
+			namespace:      newNamespace("tenant-c", map[string]string{"tenant": "c"}, nil),
+			expectedLabels: map[string]string{"tenant": "c"},
+		},
+		{
+			name:           "protected namespace",
+			namespace:      newNamespace("shared", map[string]string{"tenant": "a"}, nil),
+			expectedLabels: map[string]string{"tenant": "a"},
+		},
+		{
+			name:           "control plane namespace",
+			namespace:      newNamespace("osm-system", map[string]string{"tenant": "a"}, nil),
+			expectedLabels: map[string]string{"tenant": "a"},
+		},
+		{
+			name:           "system namespace",
+			namespace:      newNamespace("kube-system", map[string]string{"tenant": "a"}, nil),
+			expectedLabels: map[string]string{"tenant": "a"},
+		},
+		{
+			name:           "ignored namespace",
+			namespace:      newNamespace("tenant-a", map[string]string{"tenant": "a", constants.IgnoreLabel: "true"}, nil),
+			expectedLabels: map[string]string{"tenant": "a", constants.IgnoreLabel: "true"},
+		},
+		{
+			name: "namespace monitored by another mesh",
+			namespace: newNamespace("tenant-a", map[string]string{
+				"tenant":                                   "a",
+				constants.OSMKubeResourceMonitorAnnotation: "other",
+			}, nil),
+			expectedLabels: map[string]string{
+				"tenant":                                   "a",
+				constants.OSMKubeResourceMonitorAnnotation: "other",
+			},
+		},
+		{
+			name: "namespace opting out of sidecar injection",
+			namespace: newNamespace("tenant-b", map[string]string{"tenant": "b"}, map[string]string{
+				constants.SidecarInjectionAnnotation: "disabled",
+			}),
+			expectedLabels: map[string]string{
+				"tenant":                                   "b",
+				constants.OSMKubeResourceMonitorAnnotation: "osm",
+			},
+			expectedAnnotations: map[string]string{constants.SidecarInjectionAnnotation: "disabled"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			kubeClient := fake.NewSimpleClientset(tc.namespace)
+			recorder, err := events.NewObjectEventRecorder(kubeClient)
+			assert.Nil(err)
+			c, err := NewController(Config{Selector: "tenant in (a, b)", ProtectedNamespaces: []string{"shared"}}, kubeClient, "osm", "osm-system", recorder)
+			assert.Nil(err)
+
+			c.enroll(tc.namespace)
+
+			ns, err := kubeClient.CoreV1().Namespaces().Get(context.TODO(), tc.namespace.Name, metav1.GetOptions{})
+			assert.Nil(err)
+			assert.Equal(tc.expectedLabels, ns.Labels)
+			assert.Equal(tc.expectedAnnotations, ns.Annotations)
+		})
+	}
+}
+
+func TestGetEnrollmentPatch(t *testing.T) {
+	assert := tassert.New(t)
+
+	enrolled := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "tenant-a",
+		Labels:      map[string]string{constants.OSMKubeResourceMonitorAnnotation: "osm"},
+		Annotations: map[string]string{constants.SidecarInjectionAnnotation: "enabled"},
+	}}
+	assert.Nil(getEnrollmentPatch(enrolled, "osm"))
+
+	enrolled.Annotations = nil
+	assert.JSONEq(`{"metadata":{"annotations":{"openservicemesh.io/sidecar-injection":"enabled"}}}`, string(getEnrollmentPatch(enrolled, "osm")))
+}
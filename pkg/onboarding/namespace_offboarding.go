@@ -0,0 +1,223 @@
+package onboarding
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/policy"
+)
+
+const (
+	// DefaultNamespaceOffboardingTimeout is the default duration after which a NamespaceOffboarding fails if pods of
+	// its namespace still run sidecars
+	DefaultNamespaceOffboardingTimeout = 10 * time.Minute
+
+	// namespaceOffboardingReconcileInterval is the interval at which NamespaceOffboarding resources are reconciled
+	namespaceOffboardingReconcileInterval = 10 * time.Second
+)
+
+// NewNamespaceOffboardingController returns a NamespaceOffboardingController uninstalling the sidecars of the
+// namespaces of NamespaceOffboarding resources before removing them from the given mesh
+func NewNamespaceOffboardingController(kubeClient kubernetes.Interface, kubeController k8s.Controller, policyController policy.Controller,
+	certManager certificate.Manager, meshName string, recorder *events.ObjectEventRecorder) *NamespaceOffboardingController {
+	return &NamespaceOffboardingController{
+		kubeClient:       kubeClient,
+		kubeController:   kubeController,
+		policyController: policyController,
+		certManager:      certManager,
+		meshName:         meshName,
+		recorder:         recorder,
+	}
+}
+
+// Run starts reconciling NamespaceOffboarding resources periodically, and when they are added or updated, until the
+// stop channel is closed
+func (c *NamespaceOffboardingController) Run(stop <-chan struct{}) {
+	subChannel := events.Subscribe(announcements.NamespaceOffboardingAdded, announcements.NamespaceOffboardingUpdated)
+
+	go func() {
+		defer events.Unsub(subChannel)
+
+		ticker := time.NewTicker(namespaceOffboardingReconcileInterval)
+		defer ticker.Stop()
+
+		for {
+			for _, offboarding := range c.policyController.ListNamespaceOffboardings() {
+				c.reconcile(offboarding, time.Now())
+			}
+
+			select {
+			case <-ticker.C:
+			case <-subChannel:
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// reconcile advances the given NamespaceOffboarding and updates its status if it changed
+func (c *NamespaceOffboardingController) reconcile(offboarding *policyv1alpha1.NamespaceOffboarding, now time.Time) {
+	status := c.getNextStatus(offboarding, now)
+	if reflect.DeepEqual(status, offboarding.Status) {
+		return
+	}
+
+	updated := offboarding.DeepCopy()
+	updated.Status = status
+	if _, err := c.kubeController.UpdateStatus(updated); err != nil {
+		log.Error().Err(err).Msgf("Error updating status of NamespaceOffboarding %s/%s", offboarding.Namespace, offboarding.Name)
+	}
+}
+
+// getNextStatus returns the status of the given NamespaceOffboarding after its next step at the given time. The first
+// step disables the sidecar injection in the namespace and restarts the workloads running pods with sidecars. Once no
+// pod runs a sidecar anymore, the certificates of the namespace are released and the namespace is removed from the
+// mesh, which completes the offboarding.
+func (c *NamespaceOffboardingController) getNextStatus(offboarding *policyv1alpha1.NamespaceOffboarding, now time.Time) policyv1alpha1.NamespaceOffboardingStatus {
+	status := *offboarding.Status.DeepCopy()
+	if status.Phase == policyv1alpha1.NamespaceOffboardingCompleted || status.Phase == policyv1alpha1.NamespaceOffboardingFailed {
+		return status
+	}
+
+	timeout, err := getNamespaceOffboardingTimeout(offboarding.Spec)
+	if err != nil {
+		return c.failedStatus(offboarding, status, err.Error())
+	}
+
+	namespace := offboarding.Namespace
+	pods, err := c.kubeClient.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: constants.EnvoyUniqueIDLabelName,
+	})
+	if err != nil {
+		log.Error().Err(err).Msgf("Error listing the pods with sidecars of namespace %s", namespace)
+		return status
+	}
+
+	if status.Phase == "" {
+		if err := c.patchNamespace(namespace, fmt.Sprintf(`{"metadata":{"annotations":{%q:"disabled"}}}`, constants.SidecarInjectionAnnotation)); err != nil {
+			log.Error().Err(err).Msgf("Error disabling sidecar injection in namespace %s", namespace)
+			return status
+		}
+
+		restarted, failed, unmanagedPods := restartPodWorkloads(c.kubeClient, namespace, pods.Items)
+		status.Phase = policyv1alpha1.NamespaceOffboardingInProgress
+		status.StartTime = metav1.NewTime(now)
+		status.RestartedWorkloads = restarted
+		if len(failed) > 0 || len(unmanagedPods) > 0 {
+			c.recorder.WarnEvent(offboarding, events.NamespaceOffboardingIncomplete, "Pods of namespace %s will keep running sidecars: failed to restart [%s], pods not managed by a Deployment, StatefulSet or DaemonSet to delete [%s]",
+				namespace, strings.Join(failed, ", "), strings.Join(unmanagedPods, ", "))
+		}
+	}
+
+	status.PodsWithSidecars = nil
+	for _, pod := range pods.Items {
+		status.PodsWithSidecars = append(status.PodsWithSidecars, pod.Name)
+	}
+	sort.Strings(status.PodsWithSidecars)
+
+	if len(status.PodsWithSidecars) > 0 {
+		if now.Sub(status.StartTime.Time) >= timeout {
+			return c.failedStatus(offboarding, status, fmt.Sprintf("%d pods still run sidecars after %s", len(status.PodsWithSidecars), timeout))
+		}
+		status.Message = fmt.Sprintf("Waiting for %d pods to be recreated without sidecars", len(status.PodsWithSidecars))
+		return status
+	}
+
+	// Removing the namespace from the mesh stops the reconciliation of the offboarding, the certificates are released
+	// beforehand
+	status.ReleasedCertificates += c.releaseCertificates(namespace)
+	if err := c.patchNamespace(namespace, fmt.Sprintf(`{"metadata":{"labels":{%q:null},"annotations":{%q:null}}}`,
+		constants.OSMKubeResourceMonitorAnnotation, constants.SidecarInjectionAnnotation)); err != nil {
+		log.Error().Err(err).Msgf("Error removing namespace %s from mesh %s", namespace, c.meshName)
+		return status
+	}
+
+	status.Phase = policyv1alpha1.NamespaceOffboardingCompleted
+	status.Message = fmt.Sprintf("Namespace was removed from mesh %s", c.meshName)
+	c.recorder.NormalEvent(offboarding, events.NamespaceOffboarded, "Removed namespace %s from mesh %s after restarting [%s] to remove their sidecars",
+		namespace, c.meshName, strings.Join(status.RestartedWorkloads, ", "))
+	return status
+}
+
+// failedStatus returns the given status of the given NamespaceOffboarding in the Failed phase with the given message
+func (c *NamespaceOffboardingController) failedStatus(offboarding *policyv1alpha1.NamespaceOffboarding, status policyv1alpha1.NamespaceOffboardingStatus,
+	message string) policyv1alpha1.NamespaceOffboardingStatus {
+	status.Phase = policyv1alpha1.NamespaceOffboardingFailed
+	status.Message = message
+	c.recorder.WarnEvent(offboarding, events.NamespaceOffboardingIncomplete, "Offboarding of namespace %s from mesh %s failed: %s", offboarding.Namespace, c.meshName, message)
+	return status
+}
+
+// patchNamespace applies the given strategic merge patch to the given namespace
+func (c *NamespaceOffboardingController) patchNamespace(namespace string, patch string) error {
+	_, err := c.kubeClient.CoreV1().Namespaces().Patch(context.Background(), namespace, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{})
+	return err
+}
+
+// releaseCertificates releases the certificates issued to the proxies and service identities of the given namespace,
+// and returns the number of certificates released
+func (c *NamespaceOffboardingController) releaseCertificates(namespace string) int {
+	certs, err := c.certManager.ListCertificates()
+	if err != nil {
+		log.Error().Err(err).Msgf("Error listing the certificates to release for namespace %s", namespace)
+		return 0
+	}
+
+	released := 0
+	for _, cert := range certs {
+		cn := cert.GetCommonName()
+		if getCertificateNamespace(cn) != namespace {
+			continue
+		}
+		c.certManager.ReleaseCertificate(cn)
+		released++
+	}
+	return released
+}
+
+// getCertificateNamespace returns the namespace of the proxy or service identity of the given certificate, in the
+// <proxy-UUID>.<kind>.<service-account>.<namespace>.<trust-domain> format of proxy certificates or the
+// <service-account>.<namespace>.<trust-domain> format of service certificates
+func getCertificateNamespace(cn certificate.CommonName) string {
+	chunks := strings.Split(cn.String(), ".")
+	if _, err := uuid.Parse(chunks[0]); err == nil && len(chunks) > 3 {
+		return chunks[3]
+	}
+	if len(chunks) > 2 {
+		return chunks[1]
+	}
+	return ""
+}
+
+// getNamespaceOffboardingTimeout returns the timeout of the given NamespaceOffboarding spec, with the default applied
+func getNamespaceOffboardingTimeout(spec policyv1alpha1.NamespaceOffboardingSpec) (time.Duration, error) {
+	if spec.Timeout == "" {
+		return DefaultNamespaceOffboardingTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(spec.Timeout)
+	if err != nil {
+		return 0, errors.Errorf("Invalid timeout %s: %s", spec.Timeout, err)
+	}
+	if timeout <= 0 {
+		return 0, errors.Errorf("Invalid timeout %s, must be positive", spec.Timeout)
+	}
+	return timeout, nil
+}
@@ -0,0 +1,200 @@
+package onboarding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+)
+
+func TestNamespaceOffboardingGetNextStatus(t *testing.T) {
+	now := time.Now()
+	isController := true
+
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "tenant-a",
+		Labels:      map[string]string{constants.OSMKubeResourceMonitorAnnotation: "osm"},
+		Annotations: map[string]string{constants.SidecarInjectionAnnotation: "enabled"},
+	}}
+	statefulSet := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "mysql", Namespace: "tenant-a"}}
+	podWithSidecar := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:            "mysql-0",
+		Namespace:       "tenant-a",
+		Labels:          map[string]string{constants.EnvoyUniqueIDLabelName: "proxy-uuid"},
+		OwnerReferences: []metav1.OwnerReference{{Kind: "StatefulSet", Name: "mysql", Controller: &isController}},
+	}}
+	podWithoutSidecar := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "mysql-1", Namespace: "tenant-a"}}
+
+	testCases := []struct {
+		name                    string
+		spec                    policyv1alpha1.NamespaceOffboardingSpec
+		status                  policyv1alpha1.NamespaceOffboardingStatus
+		pods                    []runtime.Object
+		expectedStatus          policyv1alpha1.NamespaceOffboardingStatus
+		expectedRestarted       bool
+		expectedLabels          map[string]string
+		expectedAnnotations     map[string]string
+		expectedReleasedCertCNs []certificate.CommonName
+	}{
+		{
+			name: "offboarding starts",
+			pods: []runtime.Object{podWithSidecar, podWithoutSidecar},
+			expectedStatus: policyv1alpha1.NamespaceOffboardingStatus{
+				Phase:              policyv1alpha1.NamespaceOffboardingInProgress,
+				StartTime:          metav1.NewTime(now),
+				RestartedWorkloads: []string{"StatefulSet/mysql"},
+				PodsWithSidecars:   []string{"mysql-0"},
+				Message:            "Waiting for 1 pods to be recreated without sidecars",
+			},
+			expectedRestarted:   true,
+			expectedLabels:      map[string]string{constants.OSMKubeResourceMonitorAnnotation: "osm"},
+			expectedAnnotations: map[string]string{constants.SidecarInjectionAnnotation: "disabled"},
+		},
+		{
+			name: "offboarding completes once no pod runs a sidecar",
+			status: policyv1alpha1.NamespaceOffboardingStatus{
+				Phase:              policyv1alpha1.NamespaceOffboardingInProgress,
+				StartTime:          metav1.NewTime(now.Add(-time.Minute)),
+				RestartedWorkloads: []string{"StatefulSet/mysql"},
+				PodsWithSidecars:   []string{"mysql-0"},
+			},
+			pods: []runtime.Object{podWithoutSidecar},
+			expectedStatus: policyv1alpha1.NamespaceOffboardingStatus{
+				Phase:                policyv1alpha1.NamespaceOffboardingCompleted,
+				StartTime:            metav1.NewTime(now.Add(-time.Minute)),
+				RestartedWorkloads:   []string{"StatefulSet/mysql"},
+				ReleasedCertificates: 2,
+				Message:              "Namespace was removed from mesh osm",
+			},
+			expectedLabels:      map[string]string{},
+			expectedAnnotations: map[string]string{},
+			expectedReleasedCertCNs: []certificate.CommonName{
+				"mysql.tenant-a.cluster.local",
+				"d6bbc9a4-8ab5-4e8a-9a3c-2e58d76ef3e4.sidecar.mysql.tenant-a.cluster.local",
+			},
+		},
+		{
+			name: "offboarding fails after its timeout",
+			spec: policyv1alpha1.NamespaceOffboardingSpec{Timeout: "5m"},
+			status: policyv1alpha1.NamespaceOffboardingStatus{
+				Phase:     policyv1alpha1.NamespaceOffboardingInProgress,
+				StartTime: metav1.NewTime(now.Add(-10 * time.Minute)),
+			},
+			pods: []runtime.Object{podWithSidecar},
+			expectedStatus: policyv1alpha1.NamespaceOffboardingStatus{
+				Phase:            policyv1alpha1.NamespaceOffboardingFailed,
+				StartTime:        metav1.NewTime(now.Add(-10 * time.Minute)),
+				PodsWithSidecars: []string{"mysql-0"},
+				Message:          "1 pods still run sidecars after 5m0s",
+			},
+			expectedLabels:      map[string]string{constants.OSMKubeResourceMonitorAnnotation: "osm"},
+			expectedAnnotations: map[string]string{constants.SidecarInjectionAnnotation: "enabled"},
+		},
+		{
+			name: "offboarding with an invalid timeout fails",
+			spec: policyv1alpha1.NamespaceOffboardingSpec{Timeout: "5"},
+			pods: []runtime.Object{podWithSidecar},
+			expectedStatus: policyv1alpha1.NamespaceOffboardingStatus{
+				Phase:   policyv1alpha1.NamespaceOffboardingFailed,
+				Message: "Invalid timeout 5: time: missing unit in duration \"5\"",
+			},
+			expectedLabels:      map[string]string{constants.OSMKubeResourceMonitorAnnotation: "osm"},
+			expectedAnnotations: map[string]string{constants.SidecarInjectionAnnotation: "enabled"},
+		},
+		{
+			name:   "completed offboarding is left as is",
+			status: policyv1alpha1.NamespaceOffboardingStatus{Phase: policyv1alpha1.NamespaceOffboardingCompleted},
+			pods:   []runtime.Object{podWithSidecar},
+			expectedStatus: policyv1alpha1.NamespaceOffboardingStatus{
+				Phase: policyv1alpha1.NamespaceOffboardingCompleted,
+			},
+			expectedLabels:      map[string]string{constants.OSMKubeResourceMonitorAnnotation: "osm"},
+			expectedAnnotations: map[string]string{constants.SidecarInjectionAnnotation: "enabled"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			objects := append([]runtime.Object{namespace.DeepCopy(), statefulSet.DeepCopy()}, tc.pods...)
+			kubeClient := fake.NewSimpleClientset(objects...)
+			recorder, err := events.NewObjectEventRecorder(kubeClient, policyv1alpha1.AddToScheme)
+			assert.Nil(err)
+
+			mockCertManager := certificate.NewMockManager(mockCtrl)
+			if tc.expectedReleasedCertCNs != nil {
+				var certs []certificate.Certificater
+				for _, cn := range append(tc.expectedReleasedCertCNs, "mysql.tenant-b.cluster.local") {
+					cert := certificate.NewMockCertificater(mockCtrl)
+					cert.EXPECT().GetCommonName().Return(cn).AnyTimes()
+					certs = append(certs, cert)
+				}
+				mockCertManager.EXPECT().ListCertificates().Return(certs, nil)
+				for _, cn := range tc.expectedReleasedCertCNs {
+					mockCertManager.EXPECT().ReleaseCertificate(cn)
+				}
+			}
+
+			c := NewNamespaceOffboardingController(kubeClient, nil, nil, mockCertManager, "osm", recorder)
+			offboarding := &policyv1alpha1.NamespaceOffboarding{
+				ObjectMeta: metav1.ObjectMeta{Name: "osm", Namespace: "tenant-a"},
+				Spec:       tc.spec,
+				Status:     tc.status,
+			}
+
+			status := c.getNextStatus(offboarding, now)
+			assert.Equal(tc.expectedStatus, status)
+
+			ns, err := kubeClient.CoreV1().Namespaces().Get(context.TODO(), "tenant-a", metav1.GetOptions{})
+			assert.Nil(err)
+			assert.Equal(tc.expectedLabels, ns.Labels)
+			assert.Equal(tc.expectedAnnotations, ns.Annotations)
+
+			sts, err := kubeClient.AppsV1().StatefulSets("tenant-a").Get(context.TODO(), "mysql", metav1.GetOptions{})
+			assert.Nil(err)
+			_, restarted := sts.Spec.Template.Annotations[restartedAtAnnotation]
+			assert.Equal(tc.expectedRestarted, restarted)
+		})
+	}
+}
+
+func TestGetCertificateNamespace(t *testing.T) {
+	testCases := []struct {
+		cn       certificate.CommonName
+		expected string
+	}{
+		{
+			cn:       "d6bbc9a4-8ab5-4e8a-9a3c-2e58d76ef3e4.sidecar.bookstore.tenant-a.cluster.local",
+			expected: "tenant-a",
+		},
+		{
+			cn:       "bookstore.tenant-a.cluster.local",
+			expected: "tenant-a",
+		},
+		{
+			cn:       "localhost",
+			expected: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.cn.String(), func(t *testing.T) {
+			tassert.Equal(t, tc.expected, getCertificateNamespace(tc.cn))
+		})
+	}
+}
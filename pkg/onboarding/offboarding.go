@@ -0,0 +1,164 @@
+package onboarding
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+)
+
+// restartedAtAnnotation is the pod template annotation set to restart the pods of a workload, as
+// `kubectl rollout restart` does
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// NewOffboardingController returns an OffboardingController restarting the workloads of the namespaces removed from
+// the given mesh
+func NewOffboardingController(kubeClient kubernetes.Interface, meshName string, recorder *events.ObjectEventRecorder) *OffboardingController {
+	return &OffboardingController{
+		kubeClient: kubeClient,
+		meshName:   meshName,
+		recorder:   recorder,
+	}
+}
+
+// Run starts restarting the workloads running pods with sidecars in the namespaces removed from the mesh, until the
+// stop channel is closed. The namespaces removed from the mesh while the controller doesn't run are not handled.
+func (c *OffboardingController) Run(stop <-chan struct{}) {
+	informerFactory := informers.NewSharedInformerFactory(c.kubeClient, k8s.DefaultKubeEventResyncInterval)
+	informer := informerFactory.Core().V1().Namespaces().Informer()
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldNs, oldOk := oldObj.(*corev1.Namespace)
+			newNs, newOk := newObj.(*corev1.Namespace)
+			if oldOk && newOk && isRemovedFromMesh(oldNs, newNs, c.meshName) {
+				c.offboard(newNs)
+			}
+		},
+	})
+
+	log.Info().Msgf("Restarting the workloads of the namespaces removed from mesh %s", c.meshName)
+	go informer.Run(stop)
+}
+
+// isRemovedFromMesh returns whether the given namespace was removed from the given mesh by the given update
+func isRemovedFromMesh(oldNs, newNs *corev1.Namespace, meshName string) bool {
+	return oldNs.Labels[constants.OSMKubeResourceMonitorAnnotation] == meshName &&
+		newNs.Labels[constants.OSMKubeResourceMonitorAnnotation] != meshName &&
+		newNs.DeletionTimestamp == nil
+}
+
+// workload is a Deployment, StatefulSet or DaemonSet whose pods can be restarted
+type workload struct {
+	kind string
+	name string
+}
+
+// offboard restarts the workloads running pods with sidecars in the given namespace removed from the mesh, so that
+// their pods are recreated without sidecars rather than running sidecars orphaned from the control plane. The pods not
+// managed by such a workload are reported, since they must be deleted to run without sidecars.
+func (c *OffboardingController) offboard(ns *corev1.Namespace) {
+	pods, err := c.kubeClient.CoreV1().Pods(ns.Name).List(context.Background(), metav1.ListOptions{
+		LabelSelector: constants.EnvoyUniqueIDLabelName,
+	})
+	if err != nil {
+		log.Error().Err(err).Msgf("Error listing the pods with sidecars of namespace %s removed from mesh %s", ns.Name, c.meshName)
+		return
+	}
+	if len(pods.Items) == 0 {
+		return
+	}
+
+	restarted, failed, unmanagedPods := restartPodWorkloads(c.kubeClient, ns.Name, pods.Items)
+
+	if len(restarted) > 0 {
+		c.recorder.NormalEvent(ns, events.NamespaceOffboarded, "Restarted %s to remove their sidecars after namespace %s was removed from mesh %s",
+			strings.Join(restarted, ", "), ns.Name, c.meshName)
+	}
+	if len(failed) > 0 || len(unmanagedPods) > 0 {
+		c.recorder.WarnEvent(ns, events.NamespaceOffboardingIncomplete, "Pods of namespace %s removed from mesh %s still run sidecars: failed to restart [%s], pods not managed by a Deployment, StatefulSet or DaemonSet to delete [%s]",
+			ns.Name, c.meshName, strings.Join(failed, ", "), strings.Join(unmanagedPods, ", "))
+	}
+}
+
+// restartPodWorkloads restarts the workloads managing the given pods of the given namespace. It returns the workloads
+// restarted and those that could not be restarted as <kind>/<name>, and the names of the pods not managed by a
+// Deployment, StatefulSet or DaemonSet, all sorted.
+func restartPodWorkloads(kubeClient kubernetes.Interface, namespace string, pods []corev1.Pod) (restarted, failed, unmanagedPods []string) {
+	workloads := make(map[workload]struct{})
+	for i := range pods {
+		if w, ok := getPodWorkload(kubeClient, &pods[i]); ok {
+			workloads[w] = struct{}{}
+		} else {
+			unmanagedPods = append(unmanagedPods, pods[i].Name)
+		}
+	}
+
+	restartedAt := time.Now().Format(time.RFC3339)
+	for w := range workloads {
+		name := fmt.Sprintf("%s/%s", w.kind, w.name)
+		if err := restartWorkload(kubeClient, namespace, w, restartedAt); err != nil {
+			log.Error().Err(err).Msgf("Error restarting %s in namespace %s", name, namespace)
+			failed = append(failed, name)
+			continue
+		}
+		restarted = append(restarted, name)
+	}
+	sort.Strings(restarted)
+	sort.Strings(failed)
+	sort.Strings(unmanagedPods)
+
+	return restarted, failed, unmanagedPods
+}
+
+// getPodWorkload returns the Deployment, StatefulSet or DaemonSet managing the given pod, if any
+func getPodWorkload(kubeClient kubernetes.Interface, pod *corev1.Pod) (workload, bool) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return workload{}, false
+	}
+
+	switch owner.Kind {
+	case "StatefulSet", "DaemonSet":
+		return workload{kind: owner.Kind, name: owner.Name}, true
+
+	case "ReplicaSet":
+		rs, err := kubeClient.AppsV1().ReplicaSets(pod.Namespace).Get(context.Background(), owner.Name, metav1.GetOptions{})
+		if err != nil {
+			log.Error().Err(err).Msgf("Error getting ReplicaSet %s/%s of pod %s", pod.Namespace, owner.Name, pod.Name)
+			return workload{}, false
+		}
+		if rsOwner := metav1.GetControllerOf(rs); rsOwner != nil && rsOwner.Kind == "Deployment" {
+			return workload{kind: rsOwner.Kind, name: rsOwner.Name}, true
+		}
+	}
+
+	return workload{}, false
+}
+
+// restartWorkload restarts the pods of the given workload by updating the restartedAtAnnotation of its pod template
+func restartWorkload(kubeClient kubernetes.Interface, namespace string, w workload, restartedAt string) error {
+	patch := []byte(fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`, restartedAtAnnotation, restartedAt))
+
+	var err error
+	switch w.kind {
+	case "Deployment":
+		_, err = kubeClient.AppsV1().Deployments(namespace).Patch(context.Background(), w.name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "StatefulSet":
+		_, err = kubeClient.AppsV1().StatefulSets(namespace).Patch(context.Background(), w.name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "DaemonSet":
+		_, err = kubeClient.AppsV1().DaemonSets(namespace).Patch(context.Background(), w.name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	}
+	return err
+}
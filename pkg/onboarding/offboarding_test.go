@@ -0,0 +1,124 @@
+package onboarding
+
+import (
+	"context"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+)
+
+func TestIsRemovedFromMesh(t *testing.T) {
+	newNamespace := func(meshName string, deleted bool) *corev1.Namespace {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-a", Labels: map[string]string{}}}
+		if meshName != "" {
+			ns.Labels[constants.OSMKubeResourceMonitorAnnotation] = meshName
+		}
+		if deleted {
+			now := metav1.Now()
+			ns.DeletionTimestamp = &now
+		}
+		return ns
+	}
+
+	testCases := []struct {
+		name     string
+		oldNs    *corev1.Namespace
+		newNs    *corev1.Namespace
+		expected bool
+	}{
+		{
+			name:     "namespace unlabeled",
+			oldNs:    newNamespace("osm", false),
+			newNs:    newNamespace("", false),
+			expected: true,
+		},
+		{
+			name:     "namespace moved to another mesh",
+			oldNs:    newNamespace("osm", false),
+			newNs:    newNamespace("other", false),
+			expected: true,
+		},
+		{
+			name:  "namespace still in the mesh",
+			oldNs: newNamespace("osm", false),
+			newNs: newNamespace("osm", false),
+		},
+		{
+			name:  "namespace added to the mesh",
+			oldNs: newNamespace("", false),
+			newNs: newNamespace("osm", false),
+		},
+		{
+			name:  "namespace being deleted",
+			oldNs: newNamespace("osm", false),
+			newNs: newNamespace("", true),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tassert.Equal(t, tc.expected, isRemovedFromMesh(tc.oldNs, tc.newNs, "osm"))
+		})
+	}
+}
+
+func TestOffboard(t *testing.T) {
+	assert := tassert.New(t)
+
+	isController := true
+	newPod := func(name string, owner *metav1.OwnerReference) *corev1.Pod {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "tenant-a",
+			Labels:    map[string]string{constants.EnvoyUniqueIDLabelName: name},
+		}}
+		if owner != nil {
+			pod.OwnerReferences = []metav1.OwnerReference{*owner}
+		}
+		return pod
+	}
+	newOwner := func(kind, name string) *metav1.OwnerReference {
+		return &metav1.OwnerReference{Kind: kind, Name: name, Controller: &isController}
+	}
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-a"}}
+	kubeClient := fake.NewSimpleClientset(
+		ns,
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "bookstore", Namespace: "tenant-a"}},
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name:            "bookstore-5d4f8",
+			Namespace:       "tenant-a",
+			OwnerReferences: []metav1.OwnerReference{*newOwner("Deployment", "bookstore")},
+		}},
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "mysql", Namespace: "tenant-a"}},
+		newPod("bookstore-5d4f8-a", newOwner("ReplicaSet", "bookstore-5d4f8")),
+		newPod("bookstore-5d4f8-b", newOwner("ReplicaSet", "bookstore-5d4f8")),
+		newPod("mysql-0", newOwner("StatefulSet", "mysql")),
+		newPod("bookbuyer", nil),
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "no-sidecar", Namespace: "tenant-a"}},
+	)
+	recorder, err := events.NewObjectEventRecorder(kubeClient)
+	assert.Nil(err)
+	c := NewOffboardingController(kubeClient, "osm", recorder)
+
+	c.offboard(ns)
+
+	deployment, err := kubeClient.AppsV1().Deployments("tenant-a").Get(context.TODO(), "bookstore", metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Contains(deployment.Spec.Template.Annotations, restartedAtAnnotation)
+
+	statefulSet, err := kubeClient.AppsV1().StatefulSets("tenant-a").Get(context.TODO(), "mysql", metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Contains(statefulSet.Spec.Template.Annotations, restartedAtAnnotation)
+
+	// The pod not managed by a workload is left running
+	_, err = kubeClient.CoreV1().Pods("tenant-a").Get(context.TODO(), "bookbuyer", metav1.GetOptions{})
+	assert.Nil(err)
+}
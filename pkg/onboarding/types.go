@@ -0,0 +1,60 @@
+// Package onboarding implements the controller automatically adding the namespaces matching a label selector to the
+// mesh, as `osm namespace add` does, so that new tenant namespaces join the mesh without manual steps, and the
+// controllers removing the sidecars of the pods of namespaces removed from the mesh.
+package onboarding
+
+import (
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/policy"
+)
+
+var (
+	log = logger.New("namespace-onboarding")
+)
+
+// systemNamespaces are the namespaces of the Kubernetes system components, which are never added to the mesh
+var systemNamespaces = []string{"kube-system", "kube-public", "kube-node-lease"}
+
+// Config is the type used to represent the configuration of the Controller
+type Config struct {
+	// Selector is the label selector of the namespaces automatically added to the mesh
+	Selector string
+
+	// ProtectedNamespaces are the namespaces never added to the mesh, in addition to the Kubernetes system namespaces
+	// and the namespace of the control plane
+	ProtectedNamespaces []string
+}
+
+// Controller is the type used to represent the controller adding the namespaces matching a label selector to the mesh
+type Controller struct {
+	kubeClient kubernetes.Interface
+	selector   labels.Selector
+	protected  map[string]struct{}
+	meshName   string
+	recorder   *events.ObjectEventRecorder
+}
+
+// OffboardingController is the type used to represent the controller restarting the workloads of the namespaces
+// removed from the mesh, so that their pods no longer run sidecars orphaned from the control plane
+type OffboardingController struct {
+	kubeClient kubernetes.Interface
+	meshName   string
+	recorder   *events.ObjectEventRecorder
+}
+
+// NamespaceOffboardingController is the type used to represent the controller uninstalling the sidecars of the
+// namespaces of NamespaceOffboarding resources before removing them from the mesh
+type NamespaceOffboardingController struct {
+	kubeClient       kubernetes.Interface
+	kubeController   k8s.Controller
+	policyController policy.Controller
+	certManager      certificate.Manager
+	meshName         string
+	recorder         *events.ObjectEventRecorder
+}
@@ -36,13 +36,21 @@ func newPolicyClient(policyClient policyClientset.Interface, kubeController k8s.
 	informerFactory := policyInformers.NewSharedInformerFactory(policyClient, k8s.DefaultKubeEventResyncInterval)
 
 	informerCollection := informerCollection{
-		egress:         informerFactory.Policy().V1alpha1().Egresses().Informer(),
-		ingressBackend: informerFactory.Policy().V1alpha1().IngressBackends().Informer(),
+		egress:                 informerFactory.Policy().V1alpha1().Egresses().Informer(),
+		ingressBackend:         informerFactory.Policy().V1alpha1().IngressBackends().Informer(),
+		progressiveDelivery:    informerFactory.Policy().V1alpha1().ProgressiveDeliveries().Informer(),
+		upstreamTrafficSetting: informerFactory.Policy().V1alpha1().UpstreamTrafficSettings().Informer(),
+		namespaceIsolation:     informerFactory.Policy().V1alpha1().NamespaceIsolations().Informer(),
+		namespaceOffboarding:   informerFactory.Policy().V1alpha1().NamespaceOffboardings().Informer(),
 	}
 
 	cacheCollection := cacheCollection{
-		egress:         informerCollection.egress.GetStore(),
-		ingressBackend: informerCollection.ingressBackend.GetStore(),
+		egress:                 informerCollection.egress.GetStore(),
+		ingressBackend:         informerCollection.ingressBackend.GetStore(),
+		progressiveDelivery:    informerCollection.progressiveDelivery.GetStore(),
+		upstreamTrafficSetting: informerCollection.upstreamTrafficSetting.GetStore(),
+		namespaceIsolation:     informerCollection.namespaceIsolation.GetStore(),
+		namespaceOffboarding:   informerCollection.namespaceOffboarding.GetStore(),
 	}
 
 	client := client{
@@ -71,6 +79,30 @@ func newPolicyClient(policyClient policyClientset.Interface, kubeController k8s.
 		Delete: announcements.IngressBackendDeleted,
 	}
 	informerCollection.ingressBackend.AddEventHandler(k8s.GetKubernetesEventHandlers("IngressBackend", "Policy", shouldObserve, ingressBackendEventTypes))
+	progressiveDeliveryEventTypes := k8s.EventTypes{
+		Add:    announcements.ProgressiveDeliveryAdded,
+		Update: announcements.ProgressiveDeliveryUpdated,
+		Delete: announcements.ProgressiveDeliveryDeleted,
+	}
+	informerCollection.progressiveDelivery.AddEventHandler(k8s.GetKubernetesEventHandlers("ProgressiveDelivery", "Policy", shouldObserve, progressiveDeliveryEventTypes))
+	upstreamTrafficSettingEventTypes := k8s.EventTypes{
+		Add:    announcements.UpstreamTrafficSettingAdded,
+		Update: announcements.UpstreamTrafficSettingUpdated,
+		Delete: announcements.UpstreamTrafficSettingDeleted,
+	}
+	informerCollection.upstreamTrafficSetting.AddEventHandler(k8s.GetKubernetesEventHandlers("UpstreamTrafficSetting", "Policy", shouldObserve, upstreamTrafficSettingEventTypes))
+	namespaceIsolationEventTypes := k8s.EventTypes{
+		Add:    announcements.NamespaceIsolationAdded,
+		Update: announcements.NamespaceIsolationUpdated,
+		Delete: announcements.NamespaceIsolationDeleted,
+	}
+	informerCollection.namespaceIsolation.AddEventHandler(k8s.GetKubernetesEventHandlers("NamespaceIsolation", "Policy", shouldObserve, namespaceIsolationEventTypes))
+	namespaceOffboardingEventTypes := k8s.EventTypes{
+		Add:    announcements.NamespaceOffboardingAdded,
+		Update: announcements.NamespaceOffboardingUpdated,
+		Delete: announcements.NamespaceOffboardingDeleted,
+	}
+	informerCollection.namespaceOffboarding.AddEventHandler(k8s.GetKubernetesEventHandlers("NamespaceOffboarding", "Policy", shouldObserve, namespaceOffboardingEventTypes))
 
 	err := client.run(stop)
 	if err != nil {
@@ -88,8 +120,12 @@ func (c client) run(stop <-chan struct{}) error {
 	}
 
 	sharedInformers := map[string]cache.SharedInformer{
-		"Egress":         c.informers.egress,
-		"IngressBackend": c.informers.ingressBackend,
+		"Egress":                 c.informers.egress,
+		"IngressBackend":         c.informers.ingressBackend,
+		"ProgressiveDelivery":    c.informers.progressiveDelivery,
+		"UpstreamTrafficSetting": c.informers.upstreamTrafficSetting,
+		"NamespaceIsolation":     c.informers.namespaceIsolation,
+		"NamespaceOffboarding":   c.informers.namespaceOffboarding,
 	}
 
 	var informerNames []string
@@ -114,6 +150,16 @@ func (c client) run(stop <-chan struct{}) error {
 	return nil
 }
 
+// HasSynced returns whether the caches of all the informers have synced
+func (c client) HasSynced() bool {
+	for _, informer := range []cache.SharedIndexInformer{c.informers.egress, c.informers.ingressBackend, c.informers.progressiveDelivery, c.informers.upstreamTrafficSetting, c.informers.namespaceIsolation, c.informers.namespaceOffboarding} {
+		if informer != nil && !informer.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
 // ListEgressPoliciesForSourceIdentity lists the Egress policies for the given source identity based on service accounts
 func (c client) ListEgressPoliciesForSourceIdentity(source identity.K8sServiceAccount) []*policyV1alpha1.Egress {
 	var policies []*policyV1alpha1.Egress
@@ -156,3 +202,72 @@ func (c client) GetIngressBackendPolicy(svc service.MeshService) *policyV1alpha1
 
 	return nil
 }
+
+// ListProgressiveDeliveries lists the ProgressiveDelivery policies in monitored namespaces
+func (c client) ListProgressiveDeliveries() []*policyV1alpha1.ProgressiveDelivery {
+	var progressiveDeliveries []*policyV1alpha1.ProgressiveDelivery
+
+	for _, progressiveDeliveryIface := range c.caches.progressiveDelivery.List() {
+		progressiveDelivery := progressiveDeliveryIface.(*policyV1alpha1.ProgressiveDelivery)
+
+		if !c.kubeController.IsMonitoredNamespace(progressiveDelivery.Namespace) {
+			continue
+		}
+		progressiveDeliveries = append(progressiveDeliveries, progressiveDelivery)
+	}
+
+	return progressiveDeliveries
+}
+
+// GetUpstreamTrafficSetting returns the UpstreamTrafficSetting policy for the given upstream MeshService
+func (c client) GetUpstreamTrafficSetting(svc service.MeshService) *policyV1alpha1.UpstreamTrafficSetting {
+	for _, upstreamTrafficSettingIface := range c.caches.upstreamTrafficSetting.List() {
+		upstreamTrafficSetting := upstreamTrafficSettingIface.(*policyV1alpha1.UpstreamTrafficSetting)
+
+		if !c.kubeController.IsMonitoredNamespace(upstreamTrafficSetting.Namespace) {
+			continue
+		}
+
+		// Return the first UpstreamTrafficSetting corresponding to the given MeshService.
+		// Multiple UpstreamTrafficSetting policies for the same host are not expected.
+		if upstreamTrafficSetting.Namespace == svc.Namespace && upstreamTrafficSetting.Spec.Host == svc.FQDN() {
+			return upstreamTrafficSetting
+		}
+	}
+
+	return nil
+}
+
+// ListNamespaceIsolationPolicies lists the NamespaceIsolation policies in the given namespace, if monitored
+func (c client) ListNamespaceIsolationPolicies(namespace string) []*policyV1alpha1.NamespaceIsolation {
+	if !c.kubeController.IsMonitoredNamespace(namespace) {
+		return nil
+	}
+
+	var namespaceIsolations []*policyV1alpha1.NamespaceIsolation
+	for _, namespaceIsolationIface := range c.caches.namespaceIsolation.List() {
+		namespaceIsolation := namespaceIsolationIface.(*policyV1alpha1.NamespaceIsolation)
+
+		if namespaceIsolation.Namespace == namespace {
+			namespaceIsolations = append(namespaceIsolations, namespaceIsolation)
+		}
+	}
+
+	return namespaceIsolations
+}
+
+// ListNamespaceOffboardings lists the NamespaceOffboarding resources in monitored namespaces
+func (c client) ListNamespaceOffboardings() []*policyV1alpha1.NamespaceOffboarding {
+	var namespaceOffboardings []*policyV1alpha1.NamespaceOffboarding
+
+	for _, namespaceOffboardingIface := range c.caches.namespaceOffboarding.List() {
+		namespaceOffboarding := namespaceOffboardingIface.(*policyV1alpha1.NamespaceOffboarding)
+
+		if !c.kubeController.IsMonitoredNamespace(namespaceOffboarding.Namespace) {
+			continue
+		}
+		namespaceOffboardings = append(namespaceOffboardings, namespaceOffboarding)
+	}
+
+	return namespaceOffboardings
+}
@@ -296,3 +296,129 @@ func TestGetIngressBackendPolicy(t *testing.T) {
 		})
 	}
 }
+
+func TestListProgressiveDeliveries(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().IsMonitoredNamespace("test").Return(true).AnyTimes()
+	mockKubeController.EXPECT().IsMonitoredNamespace("unmonitored").Return(false).AnyTimes()
+
+	monitored := &policyV1alpha1.ProgressiveDelivery{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "canary",
+			Namespace: "test",
+		},
+		Spec: policyV1alpha1.ProgressiveDeliverySpec{
+			TrafficSplit:  "split",
+			CanaryBackend: "backend-v2",
+		},
+	}
+	unmonitored := monitored.DeepCopy()
+	unmonitored.Namespace = "unmonitored"
+
+	fakepolicyClientSet := fakePolicyClient.NewSimpleClientset(monitored, unmonitored)
+	policyClient, err := newPolicyClient(fakepolicyClientSet, mockKubeController, make(chan struct{}))
+	assert.Nil(err)
+
+	assert.Equal([]*policyV1alpha1.ProgressiveDelivery{monitored}, policyClient.ListProgressiveDeliveries())
+}
+
+func TestGetUpstreamTrafficSetting(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().IsMonitoredNamespace("test").Return(true).AnyTimes()
+	mockKubeController.EXPECT().IsMonitoredNamespace("other").Return(true).AnyTimes()
+
+	upstreamTrafficSetting := &policyV1alpha1.UpstreamTrafficSetting{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "bookstore",
+			Namespace: "test",
+		},
+		Spec: policyV1alpha1.UpstreamTrafficSettingSpec{
+			Host: "bookstore.test.svc.cluster.local",
+			PeerValidation: &policyV1alpha1.PeerValidationSpec{
+				TrustDomainAliases: []string{"old.domain"},
+			},
+		},
+	}
+	// An UpstreamTrafficSetting for a service in another namespace is not applied
+	otherNamespace := upstreamTrafficSetting.DeepCopy()
+	otherNamespace.Namespace = "other"
+	otherNamespace.Spec.Host = "bookstore.test.svc.cluster.local"
+
+	fakepolicyClientSet := fakePolicyClient.NewSimpleClientset(otherNamespace, upstreamTrafficSetting)
+	policyClient, err := newPolicyClient(fakepolicyClientSet, mockKubeController, make(chan struct{}))
+	assert.Nil(err)
+
+	assert.Equal(upstreamTrafficSetting, policyClient.GetUpstreamTrafficSetting(service.MeshService{Namespace: "test", Name: "bookstore"}))
+	assert.Nil(policyClient.GetUpstreamTrafficSetting(service.MeshService{Namespace: "test", Name: "bookbuyer"}))
+	assert.Nil(policyClient.GetUpstreamTrafficSetting(service.MeshService{Namespace: "other", Name: "bookstore"}))
+}
+
+func TestListNamespaceIsolationPolicies(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().IsMonitoredNamespace("tenant-a").Return(true).AnyTimes()
+	mockKubeController.EXPECT().IsMonitoredNamespace("tenant-b").Return(true).AnyTimes()
+	mockKubeController.EXPECT().IsMonitoredNamespace("unmonitored").Return(false).AnyTimes()
+	mockKubeController.EXPECT().IsMonitoredNamespace("tenant-c").Return(true).AnyTimes()
+
+	namespaceIsolation := &policyV1alpha1.NamespaceIsolation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "shared",
+			Namespace: "tenant-a",
+		},
+		Spec: policyV1alpha1.NamespaceIsolationSpec{
+			AllowedNamespaces: []string{"shared"},
+		},
+	}
+	otherNamespace := namespaceIsolation.DeepCopy()
+	otherNamespace.Namespace = "tenant-b"
+	unmonitored := namespaceIsolation.DeepCopy()
+	unmonitored.Namespace = "unmonitored"
+
+	fakepolicyClientSet := fakePolicyClient.NewSimpleClientset(namespaceIsolation, otherNamespace, unmonitored)
+	policyClient, err := newPolicyClient(fakepolicyClientSet, mockKubeController, make(chan struct{}))
+	assert.Nil(err)
+
+	assert.Equal([]*policyV1alpha1.NamespaceIsolation{namespaceIsolation}, policyClient.ListNamespaceIsolationPolicies("tenant-a"))
+	assert.Nil(policyClient.ListNamespaceIsolationPolicies("unmonitored"))
+	assert.Nil(policyClient.ListNamespaceIsolationPolicies("tenant-c"))
+}
+
+func TestListNamespaceOffboardings(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().IsMonitoredNamespace("test").Return(true).AnyTimes()
+	mockKubeController.EXPECT().IsMonitoredNamespace("unmonitored").Return(false).AnyTimes()
+
+	monitored := &policyV1alpha1.NamespaceOffboarding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "osm",
+			Namespace: "test",
+		},
+		Spec: policyV1alpha1.NamespaceOffboardingSpec{
+			Timeout: "5m",
+		},
+	}
+	unmonitored := monitored.DeepCopy()
+	unmonitored.Namespace = "unmonitored"
+
+	fakepolicyClientSet := fakePolicyClient.NewSimpleClientset(monitored, unmonitored)
+	policyClient, err := newPolicyClient(fakepolicyClientSet, mockKubeController, make(chan struct{}))
+	assert.Nil(err)
+
+	assert.Equal([]*policyV1alpha1.NamespaceOffboarding{monitored}, policyClient.ListNamespaceOffboardings())
+}
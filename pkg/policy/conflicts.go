@@ -0,0 +1,95 @@
+package policy
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// ConflictHandler records a warning event against each UpstreamTrafficSetting and IngressBackend policy that is not
+// applied because another policy applies to the same service, based on the Added and Updated events of those policies.
+// Returns a stop channel which can be used to stop the inner handler.
+func ConflictHandler(policyController Controller, eventRecorder *events.ObjectEventRecorder) chan struct{} {
+	policySubscription := events.Subscribe(
+		announcements.UpstreamTrafficSettingAdded, announcements.UpstreamTrafficSettingUpdated,
+		announcements.IngressBackendAdded, announcements.IngressBackendUpdated)
+	stop := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			case policyMsg := <-policySubscription:
+				psubMessage, castOk := policyMsg.(events.PubSubMessage)
+				if !castOk {
+					log.Error().Msgf("Error casting PubSubMessage: %T %v", psubMessage, psubMessage)
+					continue
+				}
+
+				switch policy := psubMessage.NewObj.(type) {
+				case *policyV1alpha1.UpstreamTrafficSetting:
+					// Skip updates that don't change the spec of the policy, such as periodic resyncs
+					if old, ok := psubMessage.OldObj.(*policyV1alpha1.UpstreamTrafficSetting); ok && reflect.DeepEqual(old.Spec, policy.Spec) {
+						continue
+					}
+					if applied := getConflictingUpstreamTrafficSetting(policyController, policy); applied != nil {
+						eventRecorder.WarnEvent(policy, events.PolicyConflict,
+							"UpstreamTrafficSetting %s/%s is not applied to host %s, UpstreamTrafficSetting %s applies to it instead",
+							policy.Namespace, policy.Name, policy.Spec.Host, applied.Name)
+					}
+
+				case *policyV1alpha1.IngressBackend:
+					if old, ok := psubMessage.OldObj.(*policyV1alpha1.IngressBackend); ok && reflect.DeepEqual(old.Spec, policy.Spec) {
+						continue
+					}
+					if conflicts := getIngressBackendConflicts(policyController, policy); len(conflicts) > 0 {
+						eventRecorder.WarnEvent(policy, events.PolicyConflict,
+							"IngressBackend %s/%s is not applied to some of its backends: %s",
+							policy.Namespace, policy.Name, strings.Join(conflicts, "; "))
+					}
+
+				default:
+					log.Error().Msgf("Unexpected policy type: %T %v", psubMessage.NewObj, psubMessage.NewObj)
+				}
+			}
+		}
+	}()
+
+	return stop
+}
+
+// getConflictingUpstreamTrafficSetting returns the UpstreamTrafficSetting applied to the host of the given one if it
+// is another policy, nil otherwise
+func getConflictingUpstreamTrafficSetting(policyController Controller, upstreamTrafficSetting *policyV1alpha1.UpstreamTrafficSetting) *policyV1alpha1.UpstreamTrafficSetting {
+	svcName := strings.TrimSuffix(upstreamTrafficSetting.Spec.Host, fmt.Sprintf(".%s.svc.cluster.local", upstreamTrafficSetting.Namespace))
+	if svcName == upstreamTrafficSetting.Spec.Host || svcName == "" {
+		// The host is not a service in the namespace of the policy
+		return nil
+	}
+
+	applied := policyController.GetUpstreamTrafficSetting(service.MeshService{Name: svcName, Namespace: upstreamTrafficSetting.Namespace})
+	if applied == nil || applied.Name == upstreamTrafficSetting.Name {
+		return nil
+	}
+	return applied
+}
+
+// getIngressBackendConflicts returns a description of each backend of the given IngressBackend another IngressBackend
+// applies to instead
+func getIngressBackendConflicts(policyController Controller, ingressBackend *policyV1alpha1.IngressBackend) []string {
+	var conflicts []string
+	for _, backend := range ingressBackend.Spec.Backends {
+		applied := policyController.GetIngressBackendPolicy(service.MeshService{Name: backend.Name, Namespace: ingressBackend.Namespace})
+		if applied != nil && applied.Name != ingressBackend.Name {
+			conflicts = append(conflicts, fmt.Sprintf("IngressBackend %s applies to backend %s instead", applied.Name, backend.Name))
+		}
+	}
+	return conflicts
+}
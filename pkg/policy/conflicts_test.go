@@ -0,0 +1,80 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	policyV1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+func TestGetConflictingUpstreamTrafficSetting(t *testing.T) {
+	newUpstreamTrafficSetting := func(name, host string) *policyV1alpha1.UpstreamTrafficSetting {
+		return &policyV1alpha1.UpstreamTrafficSetting{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+			Spec:       policyV1alpha1.UpstreamTrafficSettingSpec{Host: host},
+		}
+	}
+	bookstore := service.MeshService{Name: "bookstore", Namespace: "test"}
+
+	testCases := []struct {
+		name                   string
+		upstreamTrafficSetting *policyV1alpha1.UpstreamTrafficSetting
+		applied                *policyV1alpha1.UpstreamTrafficSetting
+		expected               *policyV1alpha1.UpstreamTrafficSetting
+	}{
+		{
+			name:                   "policy applied",
+			upstreamTrafficSetting: newUpstreamTrafficSetting("uts-1", bookstore.FQDN()),
+			applied:                newUpstreamTrafficSetting("uts-1", bookstore.FQDN()),
+			expected:               nil,
+		},
+		{
+			name:                   "another policy applied",
+			upstreamTrafficSetting: newUpstreamTrafficSetting("uts-2", bookstore.FQDN()),
+			applied:                newUpstreamTrafficSetting("uts-1", bookstore.FQDN()),
+			expected:               newUpstreamTrafficSetting("uts-1", bookstore.FQDN()),
+		},
+		{
+			name:                   "host not in the namespace of the policy",
+			upstreamTrafficSetting: newUpstreamTrafficSetting("uts-1", "bookstore.other.svc.cluster.local"),
+			expected:               nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			mockPolicyController := NewMockController(gomock.NewController(t))
+			mockPolicyController.EXPECT().GetUpstreamTrafficSetting(bookstore).Return(tc.applied).AnyTimes()
+
+			assert.Equal(tc.expected, getConflictingUpstreamTrafficSetting(mockPolicyController, tc.upstreamTrafficSetting))
+		})
+	}
+}
+
+func TestGetIngressBackendConflicts(t *testing.T) {
+	assert := tassert.New(t)
+
+	newIngressBackend := func(name string, backends ...string) *policyV1alpha1.IngressBackend {
+		ingressBackend := &policyV1alpha1.IngressBackend{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"}}
+		for _, backend := range backends {
+			ingressBackend.Spec.Backends = append(ingressBackend.Spec.Backends, policyV1alpha1.BackendSpec{Name: backend})
+		}
+		return ingressBackend
+	}
+
+	mockPolicyController := NewMockController(gomock.NewController(t))
+	mockPolicyController.EXPECT().GetIngressBackendPolicy(service.MeshService{Name: "bookstore", Namespace: "test"}).
+		Return(newIngressBackend("ingress-1", "bookstore"))
+	mockPolicyController.EXPECT().GetIngressBackendPolicy(service.MeshService{Name: "bookbuyer", Namespace: "test"}).
+		Return(newIngressBackend("ingress-2", "bookbuyer"))
+
+	conflicts := getIngressBackendConflicts(mockPolicyController, newIngressBackend("ingress-2", "bookstore", "bookbuyer"))
+	assert.Equal([]string{"IngressBackend ingress-1 applies to backend bookstore instead"}, conflicts)
+}
@@ -50,6 +50,34 @@ func (mr *MockControllerMockRecorder) GetIngressBackendPolicy(arg0 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngressBackendPolicy", reflect.TypeOf((*MockController)(nil).GetIngressBackendPolicy), arg0)
 }
 
+// GetUpstreamTrafficSetting mocks base method
+func (m *MockController) GetUpstreamTrafficSetting(arg0 service.MeshService) *v1alpha1.UpstreamTrafficSetting {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUpstreamTrafficSetting", arg0)
+	ret0, _ := ret[0].(*v1alpha1.UpstreamTrafficSetting)
+	return ret0
+}
+
+// GetUpstreamTrafficSetting indicates an expected call of GetUpstreamTrafficSetting
+func (mr *MockControllerMockRecorder) GetUpstreamTrafficSetting(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUpstreamTrafficSetting", reflect.TypeOf((*MockController)(nil).GetUpstreamTrafficSetting), arg0)
+}
+
+// HasSynced mocks base method
+func (m *MockController) HasSynced() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasSynced")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasSynced indicates an expected call of HasSynced
+func (mr *MockControllerMockRecorder) HasSynced() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasSynced", reflect.TypeOf((*MockController)(nil).HasSynced))
+}
+
 // ListEgressPoliciesForSourceIdentity mocks base method
 func (m *MockController) ListEgressPoliciesForSourceIdentity(arg0 identity.K8sServiceAccount) []*v1alpha1.Egress {
 	m.ctrl.T.Helper()
@@ -63,3 +91,45 @@ func (mr *MockControllerMockRecorder) ListEgressPoliciesForSourceIdentity(arg0 i
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEgressPoliciesForSourceIdentity", reflect.TypeOf((*MockController)(nil).ListEgressPoliciesForSourceIdentity), arg0)
 }
+
+// ListNamespaceIsolationPolicies mocks base method
+func (m *MockController) ListNamespaceIsolationPolicies(arg0 string) []*v1alpha1.NamespaceIsolation {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNamespaceIsolationPolicies", arg0)
+	ret0, _ := ret[0].([]*v1alpha1.NamespaceIsolation)
+	return ret0
+}
+
+// ListNamespaceIsolationPolicies indicates an expected call of ListNamespaceIsolationPolicies
+func (mr *MockControllerMockRecorder) ListNamespaceIsolationPolicies(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNamespaceIsolationPolicies", reflect.TypeOf((*MockController)(nil).ListNamespaceIsolationPolicies), arg0)
+}
+
+// ListNamespaceOffboardings mocks base method
+func (m *MockController) ListNamespaceOffboardings() []*v1alpha1.NamespaceOffboarding {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNamespaceOffboardings")
+	ret0, _ := ret[0].([]*v1alpha1.NamespaceOffboarding)
+	return ret0
+}
+
+// ListNamespaceOffboardings indicates an expected call of ListNamespaceOffboardings
+func (mr *MockControllerMockRecorder) ListNamespaceOffboardings() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNamespaceOffboardings", reflect.TypeOf((*MockController)(nil).ListNamespaceOffboardings))
+}
+
+// ListProgressiveDeliveries mocks base method
+func (m *MockController) ListProgressiveDeliveries() []*v1alpha1.ProgressiveDelivery {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProgressiveDeliveries")
+	ret0, _ := ret[0].([]*v1alpha1.ProgressiveDelivery)
+	return ret0
+}
+
+// ListProgressiveDeliveries indicates an expected call of ListProgressiveDeliveries
+func (mr *MockControllerMockRecorder) ListProgressiveDeliveries() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProgressiveDeliveries", reflect.TypeOf((*MockController)(nil).ListProgressiveDeliveries))
+}
@@ -18,14 +18,22 @@ var (
 
 // informerCollection is the type used to represent the collection of informers for the policy.openservicemesh.io API group
 type informerCollection struct {
-	egress         cache.SharedIndexInformer
-	ingressBackend cache.SharedIndexInformer
+	egress                 cache.SharedIndexInformer
+	ingressBackend         cache.SharedIndexInformer
+	progressiveDelivery    cache.SharedIndexInformer
+	upstreamTrafficSetting cache.SharedIndexInformer
+	namespaceIsolation     cache.SharedIndexInformer
+	namespaceOffboarding   cache.SharedIndexInformer
 }
 
 // cacheCollection is the type used to represent the collection of caches for the policy.openservicemesh.io API group
 type cacheCollection struct {
-	egress         cache.Store
-	ingressBackend cache.Store
+	egress                 cache.Store
+	ingressBackend         cache.Store
+	progressiveDelivery    cache.Store
+	upstreamTrafficSetting cache.Store
+	namespaceIsolation     cache.Store
+	namespaceOffboarding   cache.Store
 }
 
 // client is the type used to represent the Kubernetes client for the policy.openservicemesh.io API group
@@ -42,4 +50,19 @@ type Controller interface {
 
 	// GetIngressBackendPolicy returns the IngressBackend policy for the given backend MeshService
 	GetIngressBackendPolicy(service.MeshService) *policyV1alpha1.IngressBackend
+
+	// ListProgressiveDeliveries lists the ProgressiveDelivery policies
+	ListProgressiveDeliveries() []*policyV1alpha1.ProgressiveDelivery
+
+	// GetUpstreamTrafficSetting returns the UpstreamTrafficSetting policy for the given upstream MeshService
+	GetUpstreamTrafficSetting(service.MeshService) *policyV1alpha1.UpstreamTrafficSetting
+
+	// ListNamespaceIsolationPolicies lists the NamespaceIsolation policies in the given namespace
+	ListNamespaceIsolationPolicies(namespace string) []*policyV1alpha1.NamespaceIsolation
+
+	// ListNamespaceOffboardings lists the NamespaceOffboarding resources
+	ListNamespaceOffboardings() []*policyV1alpha1.NamespaceOffboarding
+
+	// HasSynced returns whether the caches of all the informers have synced
+	HasSynced() bool
 }
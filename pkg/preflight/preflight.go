@@ -0,0 +1,386 @@
+package preflight
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s"
+)
+
+const (
+	// KubernetesVersionCheck is the name of the check of the Kubernetes server version
+	KubernetesVersionCheck = "kubernetes-version"
+
+	// ConflictingWebhooksCheck is the name of the check of the webhooks mutating the pods alongside the sidecar injector
+	ConflictingWebhooksCheck = "conflicting-webhooks"
+
+	// CRDVersionsCheck is the name of the check of the versions served by the installed CRDs
+	CRDVersionsCheck = "crd-versions"
+
+	// LeftoverResourcesCheck is the name of the check of the resources left over by meshes no longer installed
+	LeftoverResourcesCheck = "leftover-resources"
+
+	// PodSecurityCheck is the name of the check of the pod security constraints applying to the pods with sidecars
+	PodSecurityCheck = "pod-security"
+)
+
+const (
+	// podSecurityEnforceLabel is the namespace label setting the Pod Security Admission level pods must comply with
+	podSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+
+	// podSecurityWarnLabel is the namespace label setting the Pod Security Admission level pods are warned about
+	podSecurityWarnLabel = "pod-security.kubernetes.io/warn"
+)
+
+// MinKubernetesVersion is the minimum Kubernetes server version supported, as the kubeVersion of the chart
+var MinKubernetesVersion = []int{1, 19, 0}
+
+// expectedCRDVersions are the versions each CRD must serve for the control plane to work. Helm never upgrades the
+// CRDs of a chart once installed, so CRDs installed by a previous release may lack the versions of this release.
+var expectedCRDVersions = map[string][]string{
+	"meshconfigs.config.openservicemesh.io":             {"v1alpha1", "v1alpha2"},
+	"multiclusterservices.config.openservicemesh.io":    {"v1alpha1"},
+	"egresses.policy.openservicemesh.io":                {"v1alpha1"},
+	"externalworkloads.policy.openservicemesh.io":       {"v1alpha1"},
+	"ingressbackends.policy.openservicemesh.io":         {"v1alpha1"},
+	"namespaceisolations.policy.openservicemesh.io":     {"v1alpha1"},
+	"namespaceoffboardings.policy.openservicemesh.io":   {"v1alpha1"},
+	"progressivedeliveries.policy.openservicemesh.io":   {"v1alpha1"},
+	"upstreamtrafficsettings.policy.openservicemesh.io": {"v1alpha1"},
+	"httproutegroups.specs.smi-spec.io":                 {"v1alpha4"},
+	"tcproutes.specs.smi-spec.io":                       {"v1alpha4"},
+	"traffictargets.access.smi-spec.io":                 {"v1alpha3"},
+	"trafficsplits.split.smi-spec.io":                   {"v1alpha2", "v1alpha4"},
+}
+
+// NewChecker returns a Checker running the preflight checks of the given mesh
+func NewChecker(kubeClient kubernetes.Interface, extensionsClient apiextensionsclientset.Interface, meshName string) *Checker {
+	return &Checker{
+		kubeClient:       kubeClient,
+		extensionsClient: extensionsClient,
+		meshName:         meshName,
+	}
+}
+
+// Run runs the preflight checks and returns their report
+func (c *Checker) Run() *Report {
+	return &Report{
+		MeshName: c.meshName,
+		Results: []Result{
+			c.checkKubernetesVersion(),
+			c.checkConflictingWebhooks(),
+			c.checkCRDVersions(),
+			c.checkLeftoverResources(),
+			c.checkPodSecurity(),
+		},
+	}
+}
+
+// Passed returns whether none of the checks of the report failed
+func (r *Report) Passed() bool {
+	for _, result := range r.Results {
+		if result.Status == StatusFail {
+			return false
+		}
+	}
+	return true
+}
+
+// GetReportHTTPHandler returns an HTTP handler running the preflight checks and returning their report in JSON
+func (c *Checker) GetReportHTTPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		jsonReport, err := json.Marshal(c.Run())
+		if err != nil {
+			http.Error(w, "Error marshaling the preflight report", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, string(jsonReport))
+	})
+}
+
+// checkKubernetesVersion checks that the Kubernetes server version is at least MinKubernetesVersion
+func (c *Checker) checkKubernetesVersion() Result {
+	ver, err := k8s.GetKubernetesServerVersionNumber(c.kubeClient)
+	if err != nil {
+		return Result{Name: KubernetesVersionCheck, Status: StatusFail, Message: err.Error()}
+	}
+
+	for i, minSegment := range MinKubernetesVersion {
+		segment := 0
+		if i < len(ver) {
+			segment = ver[i]
+		}
+		if segment > minSegment {
+			break
+		}
+		if segment < minSegment {
+			return Result{
+				Name:    KubernetesVersionCheck,
+				Status:  StatusFail,
+				Message: fmt.Sprintf("Kubernetes version %s is not supported, the minimum supported version is %s", formatVersion(ver), formatVersion(MinKubernetesVersion)),
+			}
+		}
+	}
+
+	return Result{
+		Name:    KubernetesVersionCheck,
+		Status:  StatusPass,
+		Message: fmt.Sprintf("Kubernetes version %s is supported", formatVersion(ver)),
+	}
+}
+
+// formatVersion returns the given version segments joined by dots, ex. [1, 19, 3] => 1.19.3
+func formatVersion(segments []int) string {
+	s := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		s = append(s, strconv.Itoa(segment))
+	}
+	return strings.Join(s, ".")
+}
+
+// checkConflictingWebhooks checks that no mutating webhook other than those of OSM mutates the pods created. Such
+// webhooks may inject containers or rewrite the pod spec in ways conflicting with the sidecar injection, in particular
+// the sidecar injectors of other service meshes.
+func (c *Checker) checkConflictingWebhooks() Result {
+	webhookConfigs, err := c.kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return Result{Name: ConflictingWebhooksCheck, Status: StatusFail, Message: fmt.Sprintf("Error listing MutatingWebhookConfigurations: %s", err)}
+	}
+
+	var conflicting []string
+	for _, webhookConfig := range webhookConfigs.Items {
+		if webhookConfig.Labels[constants.OSMAppNameLabelKey] == constants.OSMAppNameLabelValue {
+			continue
+		}
+		for _, webhook := range webhookConfig.Webhooks {
+			if mutatesPodCreation(webhook.Rules) {
+				conflicting = append(conflicting, fmt.Sprintf("%s/%s", webhookConfig.Name, webhook.Name))
+			}
+		}
+	}
+	if len(conflicting) > 0 {
+		sort.Strings(conflicting)
+		return Result{
+			Name:    ConflictingWebhooksCheck,
+			Status:  StatusWarn,
+			Message: fmt.Sprintf("Mutating webhooks [%s] mutate the pods created and may conflict with the sidecar injection of mesh %s", strings.Join(conflicting, ", "), c.meshName),
+		}
+	}
+
+	return Result{Name: ConflictingWebhooksCheck, Status: StatusPass, Message: "No other mutating webhook mutates the pods created"}
+}
+
+// mutatesPodCreation returns whether the given webhook rules match the creation of pods
+func mutatesPodCreation(rules []admissionregv1.RuleWithOperations) bool {
+	for _, rule := range rules {
+		if matchesAny(rule.APIGroups, "") && matchesAny(rule.Resources, "pods") && matchesOperation(rule.Operations, admissionregv1.Create) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAny returns whether the given webhook rule values include the given value or the wildcard
+func matchesAny(values []string, value string) bool {
+	for _, v := range values {
+		if v == value || v == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesOperation returns whether the given webhook rule operations include the given operation
+func matchesOperation(operations []admissionregv1.OperationType, operation admissionregv1.OperationType) bool {
+	for _, op := range operations {
+		if op == operation || op == admissionregv1.OperationAll {
+			return true
+		}
+	}
+	return false
+}
+
+// checkCRDVersions checks that the installed CRDs serve the versions used by the control plane. The CRDs not
+// installed yet are installed along with the chart.
+func (c *Checker) checkCRDVersions() Result {
+	names := make([]string, 0, len(expectedCRDVersions))
+	for name := range expectedCRDVersions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var outdated []string
+	for _, name := range names {
+		crd, err := c.extensionsClient.ApiextensionsV1().CustomResourceDefinitions().Get(context.Background(), name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return Result{Name: CRDVersionsCheck, Status: StatusFail, Message: fmt.Sprintf("Error getting CRD %s: %s", name, err)}
+		}
+
+		served := make(map[string]bool)
+		for _, version := range crd.Spec.Versions {
+			served[version.Name] = version.Served
+		}
+		var missing []string
+		for _, version := range expectedCRDVersions[name] {
+			if !served[version] {
+				missing = append(missing, version)
+			}
+		}
+		if len(missing) > 0 {
+			outdated = append(outdated, fmt.Sprintf("%s (%s)", name, strings.Join(missing, ", ")))
+		}
+	}
+	if len(outdated) > 0 {
+		return Result{
+			Name:    CRDVersionsCheck,
+			Status:  StatusFail,
+			Message: fmt.Sprintf("CRDs [%s] do not serve the versions used by the control plane, apply the CRDs of this release before installing or upgrading the mesh", strings.Join(outdated, "; ")),
+		}
+	}
+
+	return Result{Name: CRDVersionsCheck, Status: StatusPass, Message: "The installed CRDs serve the versions used by the control plane"}
+}
+
+// checkLeftoverResources checks that no namespace or webhook configuration belongs to a mesh whose control plane is
+// no longer installed. Namespaces left monitored by such a mesh keep stale sidecars and labels, and leftover webhooks
+// intercept the requests of a control plane that no longer serves them.
+func (c *Checker) checkLeftoverResources() Result {
+	controllers, err := c.kubeClient.AppsV1().Deployments(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("app=%s", constants.OSMControllerName),
+	})
+	if err != nil {
+		return Result{Name: LeftoverResourcesCheck, Status: StatusFail, Message: fmt.Sprintf("Error listing the OSM controllers: %s", err)}
+	}
+	installedMeshes := map[string]bool{c.meshName: true}
+	for _, controller := range controllers.Items {
+		installedMeshes[controller.Labels["meshName"]] = true
+	}
+
+	var leftovers []string
+
+	namespaces, err := c.kubeClient.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{
+		LabelSelector: constants.OSMKubeResourceMonitorAnnotation,
+	})
+	if err != nil {
+		return Result{Name: LeftoverResourcesCheck, Status: StatusFail, Message: fmt.Sprintf("Error listing the namespaces monitored by a mesh: %s", err)}
+	}
+	for _, ns := range namespaces.Items {
+		if mesh := ns.Labels[constants.OSMKubeResourceMonitorAnnotation]; !installedMeshes[mesh] {
+			leftovers = append(leftovers, fmt.Sprintf("Namespace %s of mesh %s", ns.Name, mesh))
+		}
+	}
+
+	osmSelector := metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", constants.OSMAppNameLabelKey, constants.OSMAppNameLabelValue),
+	}
+	mutatingWebhookConfigs, err := c.kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().List(context.Background(), osmSelector)
+	if err != nil {
+		return Result{Name: LeftoverResourcesCheck, Status: StatusFail, Message: fmt.Sprintf("Error listing the OSM MutatingWebhookConfigurations: %s", err)}
+	}
+	for _, webhookConfig := range mutatingWebhookConfigs.Items {
+		if mesh := webhookConfig.Labels[constants.OSMAppInstanceLabelKey]; !installedMeshes[mesh] {
+			leftovers = append(leftovers, fmt.Sprintf("MutatingWebhookConfiguration %s of mesh %s", webhookConfig.Name, mesh))
+		}
+	}
+	validatingWebhookConfigs, err := c.kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(context.Background(), osmSelector)
+	if err != nil {
+		return Result{Name: LeftoverResourcesCheck, Status: StatusFail, Message: fmt.Sprintf("Error listing the OSM ValidatingWebhookConfigurations: %s", err)}
+	}
+	for _, webhookConfig := range validatingWebhookConfigs.Items {
+		if mesh := webhookConfig.Labels[constants.OSMAppInstanceLabelKey]; !installedMeshes[mesh] {
+			leftovers = append(leftovers, fmt.Sprintf("ValidatingWebhookConfiguration %s of mesh %s", webhookConfig.Name, mesh))
+		}
+	}
+
+	if len(leftovers) > 0 {
+		return Result{
+			Name:    LeftoverResourcesCheck,
+			Status:  StatusWarn,
+			Message: fmt.Sprintf("Resources of meshes no longer installed were left over: [%s]", strings.Join(leftovers, ", ")),
+		}
+	}
+
+	return Result{Name: LeftoverResourcesCheck, Status: StatusPass, Message: "No resources of meshes no longer installed were left over"}
+}
+
+// checkPodSecurity checks that the pod security constraints allow the pods with sidecars of the mesh. The init
+// container of the sidecar requires the NET_ADMIN capability, which the baseline and restricted Pod Security
+// Admission levels forbid, so the traffic of the pods of the namespaces enforcing them is redirected by a CNI plugin
+// instead. PodSecurityPolicies must also allow it when the PodSecurityPolicy admission is enabled, which can only be
+// detected from the API being served.
+func (c *Checker) checkPodSecurity() Result {
+	namespaces, err := c.kubeClient.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", constants.OSMKubeResourceMonitorAnnotation, c.meshName),
+	})
+	if err != nil {
+		return Result{Name: PodSecurityCheck, Status: StatusFail, Message: fmt.Sprintf("Error listing the namespaces monitored by mesh %s: %s", c.meshName, err)}
+	}
+
+	var enforced, warned []string
+	for _, ns := range namespaces.Items {
+		if forbidsNetAdmin(ns, podSecurityEnforceLabel) {
+			enforced = append(enforced, fmt.Sprintf("%s (%s)", ns.Name, ns.Labels[podSecurityEnforceLabel]))
+		} else if forbidsNetAdmin(ns, podSecurityWarnLabel) {
+			warned = append(warned, fmt.Sprintf("%s (%s)", ns.Name, ns.Labels[podSecurityWarnLabel]))
+		}
+	}
+	sort.Strings(enforced)
+	sort.Strings(warned)
+
+	var warnings []string
+	if len(enforced) > 0 {
+		warnings = append(warnings, fmt.Sprintf("Namespaces [%s] enforce a pod security level forbidding the NET_ADMIN capability of the sidecar init container, the traffic of their pods with sidecars must be redirected by a CNI plugin", strings.Join(enforced, ", ")))
+	}
+	if len(warned) > 0 {
+		warnings = append(warnings, fmt.Sprintf("Namespaces [%s] warn about the NET_ADMIN capability of the sidecar init container", strings.Join(warned, ", ")))
+	}
+	if c.servesPodSecurityPolicies() {
+		warnings = append(warnings, "PodSecurityPolicies are served, pods with sidecars must be allowed the NET_ADMIN capability if the PodSecurityPolicy admission is enabled")
+	}
+	if len(warnings) > 0 {
+		return Result{Name: PodSecurityCheck, Status: StatusWarn, Message: strings.Join(warnings, "; ")}
+	}
+
+	return Result{Name: PodSecurityCheck, Status: StatusPass, Message: "No pod security constraint forbids the pods with sidecars"}
+}
+
+// forbidsNetAdmin returns whether the Pod Security Admission level set by the given label of the given namespace
+// forbids the NET_ADMIN capability
+func forbidsNetAdmin(ns corev1.Namespace, label string) bool {
+	level := ns.Labels[label]
+	return level == "baseline" || level == "restricted"
+}
+
+// servesPodSecurityPolicies returns whether the PodSecurityPolicy API is served
+func (c *Checker) servesPodSecurityPolicies() bool {
+	resources, err := c.kubeClient.Discovery().ServerResourcesForGroupVersion("policy/v1beta1")
+	if err != nil {
+		// The group version is not found once PodSecurityPolicies are removed
+		log.Debug().Err(err).Msg("Error discovering the resources of policy/v1beta1")
+		return false
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Name == "podsecuritypolicies" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,380 @@
+package preflight
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	extensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+const testMeshName = "osm"
+
+func newTestChecker(serverVersion string, kubeObjects []runtime.Object, crds ...runtime.Object) *Checker {
+	kubeClient := fake.NewSimpleClientset(kubeObjects...)
+	kubeClient.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: serverVersion}
+	return NewChecker(kubeClient, extensionsfake.NewSimpleClientset(crds...), testMeshName)
+}
+
+func TestCheckKubernetesVersion(t *testing.T) {
+	testCases := []struct {
+		name          string
+		serverVersion string
+		expStatus     Status
+		expMessage    string
+	}{
+		{
+			name:          "supported version passes",
+			serverVersion: "v1.21.2",
+			expStatus:     StatusPass,
+			expMessage:    "Kubernetes version 1.21.2 is supported",
+		},
+		{
+			name:          "minimum version passes",
+			serverVersion: "v1.19.0",
+			expStatus:     StatusPass,
+			expMessage:    "Kubernetes version 1.19.0 is supported",
+		},
+		{
+			name:          "newer major version passes",
+			serverVersion: "v2.0.0",
+			expStatus:     StatusPass,
+			expMessage:    "Kubernetes version 2.0.0 is supported",
+		},
+		{
+			name:          "older version fails",
+			serverVersion: "v1.18.9",
+			expStatus:     StatusFail,
+			expMessage:    "Kubernetes version 1.18.9 is not supported, the minimum supported version is 1.19.0",
+		},
+		{
+			name:          "unparsable version fails",
+			serverVersion: "unknown",
+			expStatus:     StatusFail,
+			expMessage:    "Error parsing k8s server version unknown",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			result := newTestChecker(tc.serverVersion, nil).checkKubernetesVersion()
+			assert.Equal(KubernetesVersionCheck, result.Name)
+			assert.Equal(tc.expStatus, result.Status)
+			assert.Contains(result.Message, tc.expMessage)
+		})
+	}
+}
+
+func TestCheckConflictingWebhooks(t *testing.T) {
+	podCreationRule := admissionregv1.RuleWithOperations{
+		Operations: []admissionregv1.OperationType{admissionregv1.Create},
+		Rule: admissionregv1.Rule{
+			APIGroups:   []string{""},
+			APIVersions: []string{"v1"},
+			Resources:   []string{"pods"},
+		},
+	}
+
+	testCases := []struct {
+		name           string
+		webhookConfigs []runtime.Object
+		expStatus      Status
+		expMessage     string
+	}{
+		{
+			name:       "no webhook passes",
+			expStatus:  StatusPass,
+			expMessage: "No other mutating webhook mutates the pods created",
+		},
+		{
+			name: "OSM injector webhook passes",
+			webhookConfigs: []runtime.Object{
+				&admissionregv1.MutatingWebhookConfiguration{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "osm-webhook-osm",
+						Labels: map[string]string{constants.OSMAppNameLabelKey: constants.OSMAppNameLabelValue},
+					},
+					Webhooks: []admissionregv1.MutatingWebhook{{Name: "osm-inject.k8s.io", Rules: []admissionregv1.RuleWithOperations{podCreationRule}}},
+				},
+			},
+			expStatus:  StatusPass,
+			expMessage: "No other mutating webhook mutates the pods created",
+		},
+		{
+			name: "webhook mutating other resources passes",
+			webhookConfigs: []runtime.Object{
+				&admissionregv1.MutatingWebhookConfiguration{
+					ObjectMeta: metav1.ObjectMeta{Name: "cert-manager-webhook"},
+					Webhooks: []admissionregv1.MutatingWebhook{{
+						Name: "webhook.cert-manager.io",
+						Rules: []admissionregv1.RuleWithOperations{{
+							Operations: []admissionregv1.OperationType{admissionregv1.Create},
+							Rule:       admissionregv1.Rule{APIGroups: []string{"cert-manager.io"}, Resources: []string{"*"}},
+						}},
+					}},
+				},
+			},
+			expStatus:  StatusPass,
+			expMessage: "No other mutating webhook mutates the pods created",
+		},
+		{
+			name: "other sidecar injector warns",
+			webhookConfigs: []runtime.Object{
+				&admissionregv1.MutatingWebhookConfiguration{
+					ObjectMeta: metav1.ObjectMeta{Name: "istio-sidecar-injector"},
+					Webhooks:   []admissionregv1.MutatingWebhook{{Name: "sidecar-injector.istio.io", Rules: []admissionregv1.RuleWithOperations{podCreationRule}}},
+				},
+				&admissionregv1.MutatingWebhookConfiguration{
+					ObjectMeta: metav1.ObjectMeta{Name: "catch-all"},
+					Webhooks: []admissionregv1.MutatingWebhook{{
+						Name: "all.example.com",
+						Rules: []admissionregv1.RuleWithOperations{{
+							Operations: []admissionregv1.OperationType{admissionregv1.OperationAll},
+							Rule:       admissionregv1.Rule{APIGroups: []string{"*"}, Resources: []string{"*"}},
+						}},
+					}},
+				},
+			},
+			expStatus:  StatusWarn,
+			expMessage: "Mutating webhooks [catch-all/all.example.com, istio-sidecar-injector/sidecar-injector.istio.io] mutate the pods created and may conflict with the sidecar injection of mesh osm",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			result := newTestChecker("v1.21.0", tc.webhookConfigs).checkConflictingWebhooks()
+			assert.Equal(ConflictingWebhooksCheck, result.Name)
+			assert.Equal(tc.expStatus, result.Status)
+			assert.Equal(tc.expMessage, result.Message)
+		})
+	}
+}
+
+func TestCheckCRDVersions(t *testing.T) {
+	newCRD := func(name string, versions ...string) *apiextensionsv1.CustomResourceDefinition {
+		crd := &apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		for _, v := range versions {
+			crd.Spec.Versions = append(crd.Spec.Versions, apiextensionsv1.CustomResourceDefinitionVersion{Name: v, Served: true})
+		}
+		return crd
+	}
+
+	testCases := []struct {
+		name       string
+		crds       []runtime.Object
+		expStatus  Status
+		expMessage string
+	}{
+		{
+			name:       "CRDs not installed pass",
+			expStatus:  StatusPass,
+			expMessage: "The installed CRDs serve the versions used by the control plane",
+		},
+		{
+			name: "up to date CRDs pass",
+			crds: []runtime.Object{
+				newCRD("meshconfigs.config.openservicemesh.io", "v1alpha1", "v1alpha2"),
+				newCRD("egresses.policy.openservicemesh.io", "v1alpha1"),
+			},
+			expStatus:  StatusPass,
+			expMessage: "The installed CRDs serve the versions used by the control plane",
+		},
+		{
+			name: "outdated CRDs fail",
+			crds: []runtime.Object{
+				newCRD("meshconfigs.config.openservicemesh.io", "v1alpha1"),
+				newCRD("trafficsplits.split.smi-spec.io", "v1alpha2"),
+				newCRD("egresses.policy.openservicemesh.io", "v1alpha1"),
+			},
+			expStatus:  StatusFail,
+			expMessage: "CRDs [meshconfigs.config.openservicemesh.io (v1alpha2); trafficsplits.split.smi-spec.io (v1alpha4)] do not serve the versions used by the control plane, apply the CRDs of this release before installing or upgrading the mesh",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			result := newTestChecker("v1.21.0", nil, tc.crds...).checkCRDVersions()
+			assert.Equal(CRDVersionsCheck, result.Name)
+			assert.Equal(tc.expStatus, result.Status)
+			assert.Equal(tc.expMessage, result.Message)
+		})
+	}
+}
+
+func TestCheckLeftoverResources(t *testing.T) {
+	newNamespace := func(name, meshName string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{constants.OSMKubeResourceMonitorAnnotation: meshName},
+		}}
+	}
+	osmLabels := func(meshName string) map[string]string {
+		return map[string]string{constants.OSMAppNameLabelKey: constants.OSMAppNameLabelValue, constants.OSMAppInstanceLabelKey: meshName}
+	}
+
+	testCases := []struct {
+		name        string
+		kubeObjects []runtime.Object
+		expStatus   Status
+		expMessage  string
+	}{
+		{
+			name: "resources of the checked and installed meshes pass",
+			kubeObjects: []runtime.Object{
+				&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+					Name:      constants.OSMControllerName,
+					Namespace: "other-system",
+					Labels:    map[string]string{"app": constants.OSMControllerName, "meshName": "other"},
+				}},
+				newNamespace("ns-1", testMeshName),
+				newNamespace("ns-2", "other"),
+				&admissionregv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "osm-webhook-other", Labels: osmLabels("other")}},
+				&admissionregv1.ValidatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "osm-validator-mesh-osm", Labels: osmLabels(testMeshName)}},
+			},
+			expStatus:  StatusPass,
+			expMessage: "No resources of meshes no longer installed were left over",
+		},
+		{
+			name: "resources of meshes no longer installed warn",
+			kubeObjects: []runtime.Object{
+				newNamespace("ns-1", "old"),
+				&admissionregv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "osm-webhook-old", Labels: osmLabels("old")}},
+				&admissionregv1.ValidatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "osm-validator-mesh-old", Labels: osmLabels("old")}},
+			},
+			expStatus:  StatusWarn,
+			expMessage: "Resources of meshes no longer installed were left over: [Namespace ns-1 of mesh old, MutatingWebhookConfiguration osm-webhook-old of mesh old, ValidatingWebhookConfiguration osm-validator-mesh-old of mesh old]",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			result := newTestChecker("v1.21.0", tc.kubeObjects).checkLeftoverResources()
+			assert.Equal(LeftoverResourcesCheck, result.Name)
+			assert.Equal(tc.expStatus, result.Status)
+			assert.Equal(tc.expMessage, result.Message)
+		})
+	}
+}
+
+func TestCheckPodSecurity(t *testing.T) {
+	newNamespace := func(name, meshName string, labels map[string]string) *corev1.Namespace {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{constants.OSMKubeResourceMonitorAnnotation: meshName},
+		}}
+		for k, v := range labels {
+			ns.Labels[k] = v
+		}
+		return ns
+	}
+
+	testCases := []struct {
+		name        string
+		kubeObjects []runtime.Object
+		servePSP    bool
+		expStatus   Status
+		expMessage  string
+	}{
+		{
+			name: "privileged namespaces pass",
+			kubeObjects: []runtime.Object{
+				newNamespace("ns-1", testMeshName, map[string]string{podSecurityEnforceLabel: "privileged"}),
+				newNamespace("ns-2", testMeshName, nil),
+			},
+			expStatus:  StatusPass,
+			expMessage: "No pod security constraint forbids the pods with sidecars",
+		},
+		{
+			name: "namespaces of other meshes are ignored",
+			kubeObjects: []runtime.Object{
+				newNamespace("ns-1", "other", map[string]string{podSecurityEnforceLabel: "restricted"}),
+			},
+			expStatus:  StatusPass,
+			expMessage: "No pod security constraint forbids the pods with sidecars",
+		},
+		{
+			name: "enforced baseline level warns",
+			kubeObjects: []runtime.Object{
+				newNamespace("ns-1", testMeshName, map[string]string{podSecurityEnforceLabel: "baseline"}),
+				newNamespace("ns-2", testMeshName, map[string]string{podSecurityWarnLabel: "restricted"}),
+			},
+			expStatus:  StatusWarn,
+			expMessage: "Namespaces [ns-1 (baseline)] enforce a pod security level forbidding the NET_ADMIN capability of the sidecar init container, the traffic of their pods with sidecars must be redirected by a CNI plugin; Namespaces [ns-2 (restricted)] warn about the NET_ADMIN capability of the sidecar init container",
+		},
+		{
+			name: "warned level and PodSecurityPolicies warn",
+			kubeObjects: []runtime.Object{
+				newNamespace("ns-1", testMeshName, map[string]string{podSecurityWarnLabel: "restricted"}),
+			},
+			servePSP:   true,
+			expStatus:  StatusWarn,
+			expMessage: "Namespaces [ns-1 (restricted)] warn about the NET_ADMIN capability of the sidecar init container; PodSecurityPolicies are served, pods with sidecars must be allowed the NET_ADMIN capability if the PodSecurityPolicy admission is enabled",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			checker := newTestChecker("v1.21.0", tc.kubeObjects)
+			if tc.servePSP {
+				checker.kubeClient.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{{
+					GroupVersion: "policy/v1beta1",
+					APIResources: []metav1.APIResource{{Name: "podsecuritypolicies"}},
+				}}
+			}
+
+			result := checker.checkPodSecurity()
+			assert.Equal(PodSecurityCheck, result.Name)
+			assert.Equal(tc.expStatus, result.Status)
+			assert.Equal(tc.expMessage, result.Message)
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	assert := tassert.New(t)
+
+	report := newTestChecker("v1.18.0", nil).Run()
+	assert.Equal(testMeshName, report.MeshName)
+	assert.Len(report.Results, 5)
+	assert.False(report.Passed())
+
+	report = newTestChecker("v1.21.0", nil).Run()
+	assert.True(report.Passed())
+	for _, result := range report.Results {
+		assert.Equal(StatusPass, result.Status)
+	}
+}
+
+func TestGetReportHTTPHandler(t *testing.T) {
+	assert := tassert.New(t)
+
+	handler := newTestChecker("v1.21.0", nil).GetReportHTTPHandler()
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, constants.HTTPServerPreflightPath, nil))
+
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.Equal("application/json", rr.Header().Get("Content-Type"))
+	assert.Contains(rr.Body.String(), `{"name":"kubernetes-version","status":"pass","message":"Kubernetes version 1.21.0 is supported"}`)
+}
@@ -0,0 +1,57 @@
+// Package preflight implements the checks of a cluster run before installing or upgrading a mesh, reporting the
+// conditions that would prevent the control plane or the sidecars from working: an unsupported Kubernetes version,
+// conflicting webhooks, outdated CRDs, resources left over by other meshes and pod security constraints.
+package preflight
+
+import (
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var (
+	log = logger.New("preflight")
+)
+
+// Status is the outcome of a preflight check
+type Status string
+
+const (
+	// StatusPass is the status of a check finding no issue
+	StatusPass Status = "pass"
+
+	// StatusWarn is the status of a check finding an issue which may affect the mesh
+	StatusWarn Status = "warn"
+
+	// StatusFail is the status of a check finding an issue preventing the mesh from working
+	StatusFail Status = "fail"
+)
+
+// Result is the result of a preflight check
+type Result struct {
+	// Name is the name of the check
+	Name string `json:"name"`
+
+	// Status is the outcome of the check
+	Status Status `json:"status"`
+
+	// Message describes the outcome of the check
+	Message string `json:"message"`
+}
+
+// Report is the result of the preflight checks of a mesh
+type Report struct {
+	// MeshName is the name of the mesh checked
+	MeshName string `json:"meshName"`
+
+	// Results are the results of the checks, in the order they were run
+	Results []Result `json:"results"`
+}
+
+// Checker runs the preflight checks of a mesh
+type Checker struct {
+	kubeClient       kubernetes.Interface
+	extensionsClient apiextensionsclientset.Interface
+	meshName         string
+}
@@ -0,0 +1,325 @@
+package progressive
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+	smiSplit "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha4"
+	smiSplitClientset "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/split/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/policy"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/smi"
+)
+
+// NewController returns a Controller adjusting the weights of TrafficSplits according to ProgressiveDelivery policies
+func NewController(cfg Config, policyController policy.Controller, meshSpec smi.MeshSpec, kubeController k8s.Controller,
+	splitClient smiSplitClientset.Interface, recorder *events.ObjectEventRecorder) *Controller {
+	return newController(cfg, policyController, meshSpec, kubeController, splitClient, NewPrometheusMetricsProvider(cfg.PrometheusURL), recorder)
+}
+
+func newController(cfg Config, policyController policy.Controller, meshSpec smi.MeshSpec, kubeController k8s.Controller,
+	splitClient smiSplitClientset.Interface, metrics MetricsProvider, recorder *events.ObjectEventRecorder) *Controller {
+	reconcileInterval := cfg.ReconcileInterval
+	if reconcileInterval <= 0 {
+		reconcileInterval = DefaultReconcileInterval
+	}
+
+	return &Controller{
+		policyController:  policyController,
+		meshSpec:          meshSpec,
+		kubeController:    kubeController,
+		splitClient:       splitClient,
+		metrics:           metrics,
+		recorder:          recorder,
+		reconcileInterval: reconcileInterval,
+	}
+}
+
+// Run starts reconciling ProgressiveDelivery policies at the configured interval, and when they are added or
+// updated, until the stop channel is closed
+func (c *Controller) Run(stop <-chan struct{}) {
+	subChannel := events.Subscribe(announcements.ProgressiveDeliveryAdded, announcements.ProgressiveDeliveryUpdated)
+
+	go func() {
+		defer events.Unsub(subChannel)
+
+		ticker := time.NewTicker(c.reconcileInterval)
+		defer ticker.Stop()
+
+		for {
+			for _, delivery := range c.policyController.ListProgressiveDeliveries() {
+				c.reconcile(delivery, time.Now())
+			}
+
+			select {
+			case <-ticker.C:
+			case <-subChannel:
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// reconcile advances the given ProgressiveDelivery policy and updates its status if it changed
+func (c *Controller) reconcile(delivery *policyv1alpha1.ProgressiveDelivery, now time.Time) {
+	status := c.getNextStatus(delivery, now)
+	if reflect.DeepEqual(status, delivery.Status) {
+		return
+	}
+
+	updated := delivery.DeepCopy()
+	updated.Status = status
+	if _, err := c.kubeController.UpdateStatus(updated); err != nil {
+		log.Error().Err(err).Msgf("Error updating status of ProgressiveDelivery %s/%s", delivery.Namespace, delivery.Name)
+	}
+}
+
+// getNextStatus returns the status of the given ProgressiveDelivery policy after the step due at the given time,
+// adjusting the weights of its TrafficSplit if the step shifts traffic
+func (c *Controller) getNextStatus(delivery *policyv1alpha1.ProgressiveDelivery, now time.Time) policyv1alpha1.ProgressiveDeliveryStatus {
+	status := *delivery.Status.DeepCopy()
+
+	restart := status.ObservedGeneration != delivery.Generation
+	if restart {
+		// The delivery restarts when its spec changes
+		status = policyv1alpha1.ProgressiveDeliveryStatus{
+			ObservedGeneration: delivery.Generation,
+			LastStepTime:       metav1.NewTime(now),
+		}
+	}
+
+	cfg, err := getDeliveryConfig(delivery.Spec)
+	if err != nil {
+		return failedStatus(status, err)
+	}
+
+	split, canaryIndex, err := c.getTrafficSplit(delivery)
+	if err != nil {
+		return failedStatus(status, err)
+	}
+
+	if restart || status.Phase == policyv1alpha1.ProgressiveDeliveryFailed {
+		// The delivery starts from the current weight of the canary backend
+		status.CanaryWeight = smi.NormalizeTrafficSplitWeights(split.Spec.Backends)[canaryIndex]
+		status.Phase = policyv1alpha1.ProgressiveDeliveryProgressing
+		status.Message = ""
+	}
+
+	if delivery.Spec.Paused {
+		status.Phase = policyv1alpha1.ProgressiveDeliveryPaused
+		status.Message = "Delivery is paused by its spec"
+		return status
+	}
+
+	if status.Phase == policyv1alpha1.ProgressiveDeliverySucceeded || status.Phase == policyv1alpha1.ProgressiveDeliveryRolledBack {
+		return status
+	}
+
+	if now.Sub(status.LastStepTime.Time) < cfg.interval {
+		return status
+	}
+	lastStepTime := status.LastStepTime
+	status.LastStepTime = metav1.NewTime(now)
+
+	// There is no traffic to analyze until some of it is shifted to the canary backend
+	if status.CanaryWeight > 0 {
+		backend := service.MeshService{Name: delivery.Spec.CanaryBackend, Namespace: delivery.Namespace}
+		failure, err := c.analyze(backend, cfg)
+		if err != nil {
+			status.Phase = policyv1alpha1.ProgressiveDeliveryPaused
+			status.Message = errors.Wrap(err, "Analysis is inconclusive").Error()
+			return status
+		}
+
+		if failure != "" {
+			status.FailedChecks++
+			if status.FailedChecks < cfg.failureThreshold {
+				status.Phase = policyv1alpha1.ProgressiveDeliveryProgressing
+				status.Message = failure
+				c.recorder.WarnEvent(delivery, events.ProgressiveDeliveryAnalysisFailed,
+					"ProgressiveDelivery %s/%s analysis failed (%d/%d): %s", delivery.Namespace, delivery.Name, status.FailedChecks, cfg.failureThreshold, failure)
+				return status
+			}
+
+			if err := c.setCanaryWeight(split, canaryIndex, 0); err != nil {
+				log.Error().Err(err).Msgf("Error rolling back ProgressiveDelivery %s/%s", delivery.Namespace, delivery.Name)
+				status.FailedChecks--
+				status.LastStepTime = lastStepTime
+				return status
+			}
+			status.Phase = policyv1alpha1.ProgressiveDeliveryRolledBack
+			status.CanaryWeight = 0
+			status.Message = failure
+			c.recorder.WarnEvent(delivery, events.ProgressiveDeliveryRolledBack,
+				"ProgressiveDelivery %s/%s rolled back traffic from %s after %d failed analyses: %s", delivery.Namespace, delivery.Name, delivery.Spec.CanaryBackend, status.FailedChecks, failure)
+			return status
+		}
+
+		if status.CanaryWeight >= cfg.maxWeight {
+			status.Phase = policyv1alpha1.ProgressiveDeliverySucceeded
+			status.Message = ""
+			c.recorder.NormalEvent(delivery, events.ProgressiveDeliverySucceeded,
+				"ProgressiveDelivery %s/%s succeeded with %d%% of traffic to %s", delivery.Namespace, delivery.Name, status.CanaryWeight, delivery.Spec.CanaryBackend)
+			return status
+		}
+	}
+
+	canaryWeight := status.CanaryWeight + cfg.stepWeight
+	if canaryWeight > cfg.maxWeight {
+		canaryWeight = cfg.maxWeight
+	}
+	if err := c.setCanaryWeight(split, canaryIndex, canaryWeight); err != nil {
+		log.Error().Err(err).Msgf("Error shifting traffic of ProgressiveDelivery %s/%s", delivery.Namespace, delivery.Name)
+		status.LastStepTime = lastStepTime
+		return status
+	}
+	status.Phase = policyv1alpha1.ProgressiveDeliveryProgressing
+	status.CanaryWeight = canaryWeight
+	status.Message = ""
+	c.recorder.NormalEvent(delivery, events.ProgressiveDeliveryProgressed,
+		"ProgressiveDelivery %s/%s shifted %d%% of traffic to %s", delivery.Namespace, delivery.Name, canaryWeight, delivery.Spec.CanaryBackend)
+	return status
+}
+
+// analyze returns a description of the threshold the metrics of the given canary backend do not meet, if any,
+// and an error if the metrics cannot be analyzed
+func (c *Controller) analyze(backend service.MeshService, cfg deliveryConfig) (string, error) {
+	metrics, err := c.metrics.GetCanaryMetrics(backend, cfg.interval)
+	if err != nil {
+		return "", err
+	}
+
+	if metrics.Requests < float64(cfg.minRequests) {
+		return "", errors.Errorf("%s received %.0f requests, fewer than the minimum of %d", backend, metrics.Requests, cfg.minRequests)
+	}
+	if cfg.minSuccessRate > 0 && metrics.SuccessRate < cfg.minSuccessRate {
+		return fmt.Sprintf("Success rate of %s is %.2f%%, below the minimum of %.2f%%", backend, metrics.SuccessRate, cfg.minSuccessRate), nil
+	}
+	if cfg.maxLatency > 0 && metrics.Latency > cfg.maxLatency {
+		return fmt.Sprintf("99th percentile latency of %s is %s, above the maximum of %s", backend, metrics.Latency, cfg.maxLatency), nil
+	}
+	return "", nil
+}
+
+// getTrafficSplit returns the TrafficSplit of the given ProgressiveDelivery policy and the index of its canary backend
+func (c *Controller) getTrafficSplit(delivery *policyv1alpha1.ProgressiveDelivery) (*smiSplit.TrafficSplit, int, error) {
+	for _, split := range c.meshSpec.ListTrafficSplits() {
+		if split.Namespace != delivery.Namespace || split.Name != delivery.Spec.TrafficSplit {
+			continue
+		}
+
+		if len(split.Spec.Backends) != 2 {
+			return nil, 0, errors.Errorf("TrafficSplit %s/%s has %d backends instead of 2", split.Namespace, split.Name, len(split.Spec.Backends))
+		}
+		if err := smi.ValidateTrafficSplitBackends(split.Spec.Backends); err != nil {
+			return nil, 0, errors.Wrapf(err, "TrafficSplit %s/%s is invalid", split.Namespace, split.Name)
+		}
+		for i, backend := range split.Spec.Backends {
+			if backend.Service == delivery.Spec.CanaryBackend {
+				return split, i, nil
+			}
+		}
+		return nil, 0, errors.Errorf("TrafficSplit %s/%s has no backend %s", split.Namespace, split.Name, delivery.Spec.CanaryBackend)
+	}
+
+	return nil, 0, errors.Errorf("TrafficSplit %s/%s not found", delivery.Namespace, delivery.Spec.TrafficSplit)
+}
+
+// setCanaryWeight sets the weight of the canary backend of the given TrafficSplit, and the remaining weight to the
+// other backend
+func (c *Controller) setCanaryWeight(split *smiSplit.TrafficSplit, canaryIndex int, weight int) error {
+	updated := split.DeepCopy()
+	for i := range updated.Spec.Backends {
+		if i == canaryIndex {
+			updated.Spec.Backends[i].Weight = weight
+		} else {
+			updated.Spec.Backends[i].Weight = smi.TrafficSplitWeightTotal - weight
+		}
+	}
+
+	if reflect.DeepEqual(updated.Spec.Backends, split.Spec.Backends) {
+		return nil
+	}
+	_, err := c.splitClient.SplitV1alpha4().TrafficSplits(split.Namespace).Update(context.Background(), updated, metav1.UpdateOptions{})
+	return err
+}
+
+// getDeliveryConfig returns the configuration of the given ProgressiveDelivery policy with the defaults applied,
+// and an error if it is invalid
+func getDeliveryConfig(spec policyv1alpha1.ProgressiveDeliverySpec) (deliveryConfig, error) {
+	cfg := deliveryConfig{
+		stepWeight:       defaultStepWeight,
+		maxWeight:        defaultMaxWeight,
+		interval:         defaultInterval,
+		failureThreshold: defaultFailureThreshold,
+		minSuccessRate:   spec.Analysis.MinSuccessRate,
+		minRequests:      defaultMinRequests,
+	}
+
+	if spec.StepWeight != 0 {
+		cfg.stepWeight = spec.StepWeight
+	}
+	if spec.MaxWeight != 0 {
+		cfg.maxWeight = spec.MaxWeight
+	}
+	if spec.FailureThreshold != 0 {
+		cfg.failureThreshold = spec.FailureThreshold
+	}
+	if spec.Analysis.MinRequests != 0 {
+		cfg.minRequests = spec.Analysis.MinRequests
+	}
+
+	if cfg.stepWeight < 1 || cfg.stepWeight > smi.TrafficSplitWeightTotal {
+		return cfg, errors.Errorf("Step weight %d must be between 1 and %d", cfg.stepWeight, smi.TrafficSplitWeightTotal)
+	}
+	if cfg.maxWeight < 1 || cfg.maxWeight > smi.TrafficSplitWeightTotal {
+		return cfg, errors.Errorf("Max weight %d must be between 1 and %d", cfg.maxWeight, smi.TrafficSplitWeightTotal)
+	}
+	if cfg.failureThreshold < 1 {
+		return cfg, errors.Errorf("Failure threshold %d must be at least 1", cfg.failureThreshold)
+	}
+	if cfg.minRequests < 0 {
+		return cfg, errors.Errorf("Minimum requests %d must not be negative", cfg.minRequests)
+	}
+	if cfg.minSuccessRate < 0 || cfg.minSuccessRate > 100 {
+		return cfg, errors.Errorf("Minimum success rate %.2f must be between 0 and 100", cfg.minSuccessRate)
+	}
+
+	if spec.Interval != "" {
+		interval, err := time.ParseDuration(spec.Interval)
+		if err != nil {
+			return cfg, errors.Wrapf(err, "Invalid interval %s", spec.Interval)
+		}
+		if interval <= 0 {
+			return cfg, errors.Errorf("Interval %s must be positive", spec.Interval)
+		}
+		cfg.interval = interval
+	}
+
+	if spec.Analysis.MaxLatency != "" {
+		maxLatency, err := time.ParseDuration(spec.Analysis.MaxLatency)
+		if err != nil {
+			return cfg, errors.Wrapf(err, "Invalid max latency %s", spec.Analysis.MaxLatency)
+		}
+		cfg.maxLatency = maxLatency
+	}
+
+	return cfg, nil
+}
+
+// failedStatus returns the given status in the failed phase because of the given error
+func failedStatus(status policyv1alpha1.ProgressiveDeliveryStatus, err error) policyv1alpha1.ProgressiveDeliveryStatus {
+	status.Phase = policyv1alpha1.ProgressiveDeliveryFailed
+	status.Message = err.Error()
+	return status
+}
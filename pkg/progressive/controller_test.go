@@ -0,0 +1,367 @@
+package progressive
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	smiSplit "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha4"
+	fakeSplitClientset "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/split/clientset/versioned/fake"
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/smi"
+)
+
+type fakeMetricsProvider struct {
+	metrics *CanaryMetrics
+	err     error
+}
+
+func (f fakeMetricsProvider) GetCanaryMetrics(service.MeshService, time.Duration) (*CanaryMetrics, error) {
+	return f.metrics, f.err
+}
+
+func TestGetNextStatus(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	longAgo := metav1.NewTime(now.Add(-time.Hour))
+
+	newSplit := func(canaryWeight int) *smiSplit.TrafficSplit {
+		return &smiSplit.TrafficSplit{
+			ObjectMeta: metav1.ObjectMeta{Name: "split", Namespace: "test"},
+			Spec: smiSplit.TrafficSplitSpec{
+				Service: "app",
+				Backends: []smiSplit.TrafficSplitBackend{
+					{Service: "app-v1", Weight: 100 - canaryWeight},
+					{Service: "app-v2", Weight: canaryWeight},
+				},
+			},
+		}
+	}
+	newDelivery := func(status policyv1alpha1.ProgressiveDeliveryStatus) *policyv1alpha1.ProgressiveDelivery {
+		status.ObservedGeneration = 1
+		return &policyv1alpha1.ProgressiveDelivery{
+			ObjectMeta: metav1.ObjectMeta{Name: "canary", Namespace: "test", Generation: 1},
+			Spec: policyv1alpha1.ProgressiveDeliverySpec{
+				TrafficSplit:     "split",
+				CanaryBackend:    "app-v2",
+				StepWeight:       20,
+				MaxWeight:        50,
+				FailureThreshold: 2,
+				Analysis: policyv1alpha1.ProgressiveDeliveryAnalysis{
+					MinSuccessRate: 99,
+					MaxLatency:     "500ms",
+				},
+			},
+			Status: status,
+		}
+	}
+	healthy := fakeMetricsProvider{metrics: &CanaryMetrics{Requests: 100, SuccessRate: 100, Latency: 100 * time.Millisecond}}
+
+	testCases := []struct {
+		name                 string
+		delivery             *policyv1alpha1.ProgressiveDelivery
+		split                *smiSplit.TrafficSplit
+		metrics              MetricsProvider
+		expectedStatus       policyv1alpha1.ProgressiveDeliveryStatus
+		expectedCanaryWeight int
+	}{
+		{
+			name: "new delivery starts from the current weight of the canary backend",
+			delivery: func() *policyv1alpha1.ProgressiveDelivery {
+				d := newDelivery(policyv1alpha1.ProgressiveDeliveryStatus{})
+				d.Status = policyv1alpha1.ProgressiveDeliveryStatus{}
+				return d
+			}(),
+			split:   newSplit(10),
+			metrics: healthy,
+			expectedStatus: policyv1alpha1.ProgressiveDeliveryStatus{
+				Phase:              policyv1alpha1.ProgressiveDeliveryProgressing,
+				CanaryWeight:       10,
+				LastStepTime:       metav1.NewTime(now),
+				ObservedGeneration: 1,
+			},
+			expectedCanaryWeight: 10,
+		},
+		{
+			name: "interval not elapsed",
+			delivery: newDelivery(policyv1alpha1.ProgressiveDeliveryStatus{
+				Phase:        policyv1alpha1.ProgressiveDeliveryProgressing,
+				CanaryWeight: 20,
+				LastStepTime: metav1.NewTime(now.Add(-time.Second)),
+			}),
+			split:   newSplit(20),
+			metrics: healthy,
+			expectedStatus: policyv1alpha1.ProgressiveDeliveryStatus{
+				Phase:              policyv1alpha1.ProgressiveDeliveryProgressing,
+				CanaryWeight:       20,
+				LastStepTime:       metav1.NewTime(now.Add(-time.Second)),
+				ObservedGeneration: 1,
+			},
+			expectedCanaryWeight: 20,
+		},
+		{
+			name: "first step is not analyzed",
+			delivery: newDelivery(policyv1alpha1.ProgressiveDeliveryStatus{
+				Phase:        policyv1alpha1.ProgressiveDeliveryProgressing,
+				LastStepTime: longAgo,
+			}),
+			split:   newSplit(0),
+			metrics: fakeMetricsProvider{err: errors.New("unreachable")},
+			expectedStatus: policyv1alpha1.ProgressiveDeliveryStatus{
+				Phase:              policyv1alpha1.ProgressiveDeliveryProgressing,
+				CanaryWeight:       20,
+				LastStepTime:       metav1.NewTime(now),
+				ObservedGeneration: 1,
+			},
+			expectedCanaryWeight: 20,
+		},
+		{
+			name: "healthy canary progresses up to the max weight",
+			delivery: newDelivery(policyv1alpha1.ProgressiveDeliveryStatus{
+				Phase:        policyv1alpha1.ProgressiveDeliveryProgressing,
+				CanaryWeight: 40,
+				LastStepTime: longAgo,
+			}),
+			split:   newSplit(40),
+			metrics: healthy,
+			expectedStatus: policyv1alpha1.ProgressiveDeliveryStatus{
+				Phase:              policyv1alpha1.ProgressiveDeliveryProgressing,
+				CanaryWeight:       50,
+				LastStepTime:       metav1.NewTime(now),
+				ObservedGeneration: 1,
+			},
+			expectedCanaryWeight: 50,
+		},
+		{
+			name: "healthy canary at the max weight succeeds",
+			delivery: newDelivery(policyv1alpha1.ProgressiveDeliveryStatus{
+				Phase:        policyv1alpha1.ProgressiveDeliveryProgressing,
+				CanaryWeight: 50,
+				LastStepTime: longAgo,
+			}),
+			split:   newSplit(50),
+			metrics: healthy,
+			expectedStatus: policyv1alpha1.ProgressiveDeliveryStatus{
+				Phase:              policyv1alpha1.ProgressiveDeliverySucceeded,
+				CanaryWeight:       50,
+				LastStepTime:       metav1.NewTime(now),
+				ObservedGeneration: 1,
+			},
+			expectedCanaryWeight: 50,
+		},
+		{
+			name: "failed analysis holds the weight",
+			delivery: newDelivery(policyv1alpha1.ProgressiveDeliveryStatus{
+				Phase:        policyv1alpha1.ProgressiveDeliveryProgressing,
+				CanaryWeight: 20,
+				LastStepTime: longAgo,
+			}),
+			split:   newSplit(20),
+			metrics: fakeMetricsProvider{metrics: &CanaryMetrics{Requests: 100, SuccessRate: 90}},
+			expectedStatus: policyv1alpha1.ProgressiveDeliveryStatus{
+				Phase:              policyv1alpha1.ProgressiveDeliveryProgressing,
+				CanaryWeight:       20,
+				FailedChecks:       1,
+				LastStepTime:       metav1.NewTime(now),
+				ObservedGeneration: 1,
+				Message:            "Success rate of test/app-v2 is 90.00%, below the minimum of 99.00%",
+			},
+			expectedCanaryWeight: 20,
+		},
+		{
+			name: "too many failed analyses roll back",
+			delivery: newDelivery(policyv1alpha1.ProgressiveDeliveryStatus{
+				Phase:        policyv1alpha1.ProgressiveDeliveryProgressing,
+				CanaryWeight: 20,
+				FailedChecks: 1,
+				LastStepTime: longAgo,
+			}),
+			split:   newSplit(20),
+			metrics: fakeMetricsProvider{metrics: &CanaryMetrics{Requests: 100, SuccessRate: 100, Latency: time.Second}},
+			expectedStatus: policyv1alpha1.ProgressiveDeliveryStatus{
+				Phase:              policyv1alpha1.ProgressiveDeliveryRolledBack,
+				FailedChecks:       2,
+				LastStepTime:       metav1.NewTime(now),
+				ObservedGeneration: 1,
+				Message:            "99th percentile latency of test/app-v2 is 1s, above the maximum of 500ms",
+			},
+			expectedCanaryWeight: 0,
+		},
+		{
+			name: "not enough traffic pauses the delivery",
+			delivery: newDelivery(policyv1alpha1.ProgressiveDeliveryStatus{
+				Phase:        policyv1alpha1.ProgressiveDeliveryProgressing,
+				CanaryWeight: 20,
+				LastStepTime: longAgo,
+			}),
+			split:   newSplit(20),
+			metrics: fakeMetricsProvider{metrics: &CanaryMetrics{SuccessRate: 100}},
+			expectedStatus: policyv1alpha1.ProgressiveDeliveryStatus{
+				Phase:              policyv1alpha1.ProgressiveDeliveryPaused,
+				CanaryWeight:       20,
+				LastStepTime:       metav1.NewTime(now),
+				ObservedGeneration: 1,
+				Message:            "Analysis is inconclusive: test/app-v2 received 0 requests, fewer than the minimum of 1",
+			},
+			expectedCanaryWeight: 20,
+		},
+		{
+			name: "paused by the spec",
+			delivery: func() *policyv1alpha1.ProgressiveDelivery {
+				d := newDelivery(policyv1alpha1.ProgressiveDeliveryStatus{
+					Phase:        policyv1alpha1.ProgressiveDeliveryProgressing,
+					CanaryWeight: 20,
+					LastStepTime: longAgo,
+				})
+				d.Spec.Paused = true
+				return d
+			}(),
+			split:   newSplit(20),
+			metrics: healthy,
+			expectedStatus: policyv1alpha1.ProgressiveDeliveryStatus{
+				Phase:              policyv1alpha1.ProgressiveDeliveryPaused,
+				CanaryWeight:       20,
+				LastStepTime:       longAgo,
+				ObservedGeneration: 1,
+				Message:            "Delivery is paused by its spec",
+			},
+			expectedCanaryWeight: 20,
+		},
+		{
+			name: "rolled back delivery is left as is",
+			delivery: newDelivery(policyv1alpha1.ProgressiveDeliveryStatus{
+				Phase:        policyv1alpha1.ProgressiveDeliveryRolledBack,
+				FailedChecks: 2,
+				LastStepTime: longAgo,
+			}),
+			split:   newSplit(0),
+			metrics: healthy,
+			expectedStatus: policyv1alpha1.ProgressiveDeliveryStatus{
+				Phase:              policyv1alpha1.ProgressiveDeliveryRolledBack,
+				FailedChecks:       2,
+				LastStepTime:       longAgo,
+				ObservedGeneration: 1,
+			},
+			expectedCanaryWeight: 0,
+		},
+		{
+			name: "canary backend missing from the TrafficSplit",
+			delivery: func() *policyv1alpha1.ProgressiveDelivery {
+				d := newDelivery(policyv1alpha1.ProgressiveDeliveryStatus{
+					Phase:        policyv1alpha1.ProgressiveDeliveryProgressing,
+					LastStepTime: longAgo,
+				})
+				d.Spec.CanaryBackend = "app-v3"
+				return d
+			}(),
+			split:   newSplit(0),
+			metrics: healthy,
+			expectedStatus: policyv1alpha1.ProgressiveDeliveryStatus{
+				Phase:              policyv1alpha1.ProgressiveDeliveryFailed,
+				LastStepTime:       longAgo,
+				ObservedGeneration: 1,
+				Message:            "TrafficSplit test/split has no backend app-v3",
+			},
+			expectedCanaryWeight: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
+			mockMeshSpec.EXPECT().ListTrafficSplits().Return([]*smiSplit.TrafficSplit{tc.split}).AnyTimes()
+			splitClient := fakeSplitClientset.NewSimpleClientset(tc.split)
+			recorder, err := events.NewObjectEventRecorder(fake.NewSimpleClientset(), policyv1alpha1.AddToScheme)
+			assert.Nil(err)
+
+			c := newController(Config{}, nil, mockMeshSpec, nil, splitClient, tc.metrics, recorder)
+			assert.Equal(tc.expectedStatus, c.getNextStatus(tc.delivery, now))
+
+			split, err := splitClient.SplitV1alpha4().TrafficSplits("test").Get(context.Background(), "split", metav1.GetOptions{})
+			assert.Nil(err)
+			assert.Equal(tc.expectedCanaryWeight, split.Spec.Backends[1].Weight)
+			assert.Equal(100-tc.expectedCanaryWeight, split.Spec.Backends[0].Weight)
+		})
+	}
+}
+
+func TestGetDeliveryConfig(t *testing.T) {
+	testCases := []struct {
+		name        string
+		spec        policyv1alpha1.ProgressiveDeliverySpec
+		expected    deliveryConfig
+		expectedErr bool
+	}{
+		{
+			name: "defaults",
+			expected: deliveryConfig{
+				stepWeight:       defaultStepWeight,
+				maxWeight:        defaultMaxWeight,
+				interval:         defaultInterval,
+				failureThreshold: defaultFailureThreshold,
+				minRequests:      defaultMinRequests,
+			},
+		},
+		{
+			name: "custom",
+			spec: policyv1alpha1.ProgressiveDeliverySpec{
+				StepWeight:       5,
+				MaxWeight:        50,
+				Interval:         "30s",
+				FailureThreshold: 3,
+				Analysis: policyv1alpha1.ProgressiveDeliveryAnalysis{
+					MinSuccessRate: 99.5,
+					MaxLatency:     "250ms",
+					MinRequests:    10,
+				},
+			},
+			expected: deliveryConfig{
+				stepWeight:       5,
+				maxWeight:        50,
+				interval:         30 * time.Second,
+				failureThreshold: 3,
+				minSuccessRate:   99.5,
+				maxLatency:       250 * time.Millisecond,
+				minRequests:      10,
+			},
+		},
+		{
+			name:        "invalid step weight",
+			spec:        policyv1alpha1.ProgressiveDeliverySpec{StepWeight: 101},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid interval",
+			spec:        policyv1alpha1.ProgressiveDeliverySpec{Interval: "soon"},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid max latency",
+			spec:        policyv1alpha1.ProgressiveDeliverySpec{Analysis: policyv1alpha1.ProgressiveDeliveryAnalysis{MaxLatency: "fast"}},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			actual, err := getDeliveryConfig(tc.spec)
+			assert.Equal(tc.expectedErr, err != nil)
+			if !tc.expectedErr {
+				assert.Equal(tc.expected, actual)
+			}
+		})
+	}
+}
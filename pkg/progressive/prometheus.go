@@ -0,0 +1,136 @@
+package progressive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+const (
+	// prometheusQueryPath is the path of the instant query endpoint of the Prometheus HTTP API
+	prometheusQueryPath = "/api/v1/query"
+
+	// requestsQuery is the query of the number of requests to a backend, formatted with the label selector of the
+	// backend's cluster and the window
+	requestsQuery = `sum(increase(envoy_cluster_upstream_rq_xx{%s}[%s]))`
+
+	// errorsQuery is the query of the number of requests to a backend that failed with a 5xx response, formatted with
+	// the label selector of the backend's cluster and the window
+	errorsQuery = `sum(increase(envoy_cluster_upstream_rq_xx{%s,envoy_response_code_class="5"}[%s]))`
+
+	// latencyQuery is the query of the 99th percentile latency in milliseconds of the requests to a backend,
+	// formatted with the label selector of the backend's cluster and the window
+	latencyQuery = `histogram_quantile(0.99, sum(rate(envoy_cluster_upstream_rq_time_bucket{%s}[%s])) by (le))`
+)
+
+// NewPrometheusMetricsProvider returns a MetricsProvider querying the metrics of canary backends from the
+// Prometheus HTTP API at the given base URL. The metrics are the ones reported by the proxies for the clusters of
+// the canary backends, i.e. from the perspective of the clients of the canary backends.
+func NewPrometheusMetricsProvider(address string) MetricsProvider {
+	return &prometheusClient{
+		address:    strings.TrimSuffix(address, "/"),
+		httpClient: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// GetCanaryMetrics returns the metrics of the requests to the given backend over the given window
+func (p *prometheusClient) GetCanaryMetrics(backend service.MeshService, window time.Duration) (*CanaryMetrics, error) {
+	selector := fmt.Sprintf("envoy_cluster_name=%q", backend.String())
+	rangeSelector := fmt.Sprintf("%ds", int64(math.Ceil(window.Seconds())))
+
+	requests, err := p.query(fmt.Sprintf(requestsQuery, selector, rangeSelector))
+	if err != nil {
+		return nil, err
+	}
+	failures, err := p.query(fmt.Sprintf(errorsQuery, selector, rangeSelector))
+	if err != nil {
+		return nil, err
+	}
+	latencyMilliseconds, err := p.query(fmt.Sprintf(latencyQuery, selector, rangeSelector))
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := &CanaryMetrics{
+		Requests:    requests,
+		SuccessRate: 100,
+		Latency:     time.Duration(latencyMilliseconds * float64(time.Millisecond)),
+	}
+	if requests > 0 {
+		metrics.SuccessRate = 100 * (requests - failures) / requests
+	}
+	return metrics, nil
+}
+
+// queryResponse is the type used to decode responses of the instant query endpoint of the Prometheus HTTP API
+type queryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Value []interface{} `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// query returns the value of the given query, which must return a vector of at most one sample.
+// An empty vector, or a sample that is not a number, returns 0.
+func (p *prometheusClient) query(query string) (float64, error) {
+	reqURL := p.address + prometheusQueryPath + "?" + url.Values{"query": []string{query}}.Encode()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, reqURL, nil)
+	if err != nil {
+		return 0, errors.Wrapf(err, "Error creating request for query %s", query)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return 0, errors.Wrapf(err, "Error requesting query %s", query)
+	}
+	defer resp.Body.Close() //nolint: errcheck,gosec
+
+	var result queryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, errors.Wrapf(err, "Error decoding response of query %s", query)
+	}
+	if resp.StatusCode != http.StatusOK || result.Status != "success" {
+		return 0, errors.Errorf("Query %s failed with status code %d: %s", query, resp.StatusCode, result.Error)
+	}
+	if result.Data.ResultType != "vector" {
+		return 0, errors.Errorf("Query %s returned a %s instead of a vector", query, result.Data.ResultType)
+	}
+	if len(result.Data.Result) == 0 {
+		return 0, nil
+	}
+	if len(result.Data.Result) > 1 {
+		return 0, errors.Errorf("Query %s returned %d samples instead of 1", query, len(result.Data.Result))
+	}
+
+	// Sample values are [<timestamp>, "<value>"]
+	sample := result.Data.Result[0].Value
+	if len(sample) != 2 {
+		return 0, errors.Errorf("Query %s returned a malformed sample %v", query, sample)
+	}
+	valueStr, ok := sample[1].(string)
+	if !ok {
+		return 0, errors.Errorf("Query %s returned a malformed sample %v", query, sample)
+	}
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "Query %s returned a malformed sample %v", query, sample)
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, nil
+	}
+	return value, nil
+}
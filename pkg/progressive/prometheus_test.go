@@ -0,0 +1,82 @@
+package progressive
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+func TestGetCanaryMetrics(t *testing.T) {
+	testCases := []struct {
+		name        string
+		responses   map[string]string
+		expected    *CanaryMetrics
+		expectedErr bool
+	}{
+		{
+			name: "metrics available",
+			responses: map[string]string{
+				"envoy_cluster_upstream_rq_xx{envoy_cluster_name=\"test/app-v2\"}[60s]":                                 `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1,"200"]}]}}`,
+				"envoy_cluster_upstream_rq_xx{envoy_cluster_name=\"test/app-v2\",envoy_response_code_class=\"5\"}[60s]": `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1,"2"]}]}}`,
+				"envoy_cluster_upstream_rq_time_bucket":                                                                 `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1,"12.5"]}]}}`,
+			},
+			expected: &CanaryMetrics{
+				Requests:    200,
+				SuccessRate: 99,
+				Latency:     12500 * time.Microsecond,
+			},
+		},
+		{
+			name: "no traffic",
+			responses: map[string]string{
+				"envoy_cluster_upstream_rq_xx":          `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+				"envoy_cluster_upstream_rq_time_bucket": `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1,"NaN"]}]}}`,
+			},
+			expected: &CanaryMetrics{
+				SuccessRate: 100,
+			},
+		},
+		{
+			name: "query error",
+			responses: map[string]string{
+				"envoy_cluster_upstream_rq_xx": `{"status":"error","error":"parse error"}`,
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(prometheusQueryPath, r.URL.Path)
+				query := r.URL.Query().Get("query")
+
+				// The most specific matching response wins
+				match := ""
+				for substring := range tc.responses {
+					if strings.Contains(query, substring) && len(substring) > len(match) {
+						match = substring
+					}
+				}
+				if match == "" || strings.Contains(tc.responses[match], `"error"`) {
+					w.WriteHeader(http.StatusBadRequest)
+				}
+				fmt.Fprint(w, tc.responses[match])
+			}))
+			defer server.Close()
+
+			metrics, err := NewPrometheusMetricsProvider(server.URL+"/").GetCanaryMetrics(service.MeshService{Name: "app-v2", Namespace: "test"}, time.Minute)
+			assert.Equal(tc.expectedErr, err != nil)
+			assert.Equal(tc.expected, metrics)
+		})
+	}
+}
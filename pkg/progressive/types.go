@@ -0,0 +1,105 @@
+// Package progressive implements the controller of ProgressiveDelivery policies, which shifts the traffic of SMI
+// TrafficSplits to canary backends step by step based on the metrics of the canary backends, and rolls the traffic
+// back when the metrics don't meet the configured thresholds.
+package progressive
+
+import (
+	"net/http"
+	"time"
+
+	smiSplitClientset "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/split/clientset/versioned"
+
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/policy"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/smi"
+)
+
+var (
+	log = logger.New("progressive-delivery")
+)
+
+const (
+	// DefaultReconcileInterval is the default interval at which ProgressiveDelivery policies are reconciled
+	DefaultReconcileInterval = 10 * time.Second
+
+	// defaultStepWeight is the default weight added to a canary backend at each step
+	defaultStepWeight = 10
+
+	// defaultMaxWeight is the default weight of a canary backend at which a delivery succeeds
+	defaultMaxWeight = smi.TrafficSplitWeightTotal
+
+	// defaultInterval is the default duration between two steps of a delivery
+	defaultInterval = 1 * time.Minute
+
+	// defaultFailureThreshold is the default number of failed analyses after which the traffic is rolled back
+	defaultFailureThreshold = 5
+
+	// defaultMinRequests is the default minimum number of requests to a canary backend for its metrics to be analyzed
+	defaultMinRequests = 1
+
+	// requestTimeout is the timeout of requests to the Prometheus HTTP API
+	requestTimeout = 10 * time.Second
+)
+
+// Config is the type used to represent the configuration of the Controller
+type Config struct {
+	// PrometheusURL is the base URL (http[s]://host:port) of the Prometheus HTTP API the metrics of canary
+	// backends are queried from
+	PrometheusURL string
+
+	// ReconcileInterval is the interval at which ProgressiveDelivery policies are reconciled, which bounds the
+	// precision of their step intervals
+	ReconcileInterval time.Duration
+}
+
+// Controller is the type used to represent the controller adjusting the weights of TrafficSplits according to
+// ProgressiveDelivery policies
+type Controller struct {
+	policyController  policy.Controller
+	meshSpec          smi.MeshSpec
+	kubeController    k8s.Controller
+	splitClient       smiSplitClientset.Interface
+	metrics           MetricsProvider
+	recorder          *events.ObjectEventRecorder
+	reconcileInterval time.Duration
+}
+
+// MetricsProvider is the interface for the source of the metrics of canary backends
+type MetricsProvider interface {
+	// GetCanaryMetrics returns the metrics of the requests to the given backend over the given window
+	GetCanaryMetrics(backend service.MeshService, window time.Duration) (*CanaryMetrics, error)
+}
+
+// CanaryMetrics is the type used to represent the metrics of the requests to a canary backend over a window
+type CanaryMetrics struct {
+	// Requests is the number of requests to the backend
+	Requests float64
+
+	// SuccessRate is the percentage of requests to the backend that did not fail with a 5xx response,
+	// 100 if there were no requests
+	SuccessRate float64
+
+	// Latency is the 99th percentile latency of the requests to the backend, 0 if unknown
+	Latency time.Duration
+}
+
+// prometheusClient is the type used to query the metrics of canary backends from the Prometheus HTTP API
+type prometheusClient struct {
+	address    string
+	httpClient *http.Client
+}
+
+// deliveryConfig is the type used to represent the validated configuration of a ProgressiveDelivery policy,
+// with the defaults applied
+type deliveryConfig struct {
+	stepWeight       int
+	maxWeight        int
+	interval         time.Duration
+	failureThreshold int
+	minSuccessRate   float64
+	maxLatency       time.Duration
+	minRequests      int
+}
@@ -0,0 +1,244 @@
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/endpoint"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+)
+
+// NewClient returns a Client that discovers services from the Consul catalog described by the given config
+func NewClient(providerIdent string, cfg Config) *Client {
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = DefaultSyncInterval
+	}
+
+	return &Client{
+		providerIdent: providerIdent,
+		address:       strings.TrimSuffix(cfg.Address, "/"),
+		datacenter:    cfg.Datacenter,
+		token:         cfg.Token,
+		tokenFile:     cfg.TokenFile,
+		interval:      interval,
+		httpClient:    &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Run starts syncing services from the Consul catalog at the configured interval until the stop channel is closed
+func (c *Client) Run(stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			c.sync()
+
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// ListExternalServices retrieves the services registered with the Consul catalog along with their endpoints
+func (c *Client) ListExternalServices() []endpoint.ExternalService {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.services
+}
+
+// GetID returns the unique identifier of the provider
+func (c *Client) GetID() string {
+	return c.providerIdent
+}
+
+// sync refreshes the services cached from the Consul catalog and requests a proxy broadcast when they changed.
+// The cached services are retained when the catalog cannot be read, so that transient errors do not disrupt traffic.
+func (c *Client) sync() {
+	token, err := c.getToken()
+	if err != nil {
+		log.Error().Err(err).Msgf("[%s] Error reading the Consul ACL token", c.providerIdent)
+		return
+	}
+
+	services, err := c.listServices(token)
+	if err != nil {
+		log.Error().Err(err).Msgf("[%s] Error listing services from the Consul catalog at %s", c.providerIdent, c.address)
+		return
+	}
+
+	c.mu.Lock()
+	changed := !reflect.DeepEqual(c.services, services)
+	c.services = services
+	c.mu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	log.Debug().Msgf("[%s] Services discovered from the Consul catalog changed, scheduling a proxy broadcast", c.providerIdent)
+	events.Publish(events.PubSubMessage{
+		AnnouncementType: announcements.ScheduleProxyBroadcast,
+		OldObj:           nil,
+		NewObj:           nil,
+	})
+}
+
+// getToken returns the ACL token used to read the Consul catalog, read from the token file if one is configured
+func (c *Client) getToken() (string, error) {
+	if c.tokenFile == "" {
+		return c.token, nil
+	}
+
+	token, err := ioutil.ReadFile(c.tokenFile)
+	if err != nil {
+		return "", errors.Wrapf(err, "Error reading token file %s", c.tokenFile)
+	}
+	return strings.TrimSpace(string(token)), nil
+}
+
+// listServices returns the services registered with the Consul catalog along with the endpoints of their healthy instances.
+// Services whose instances cannot be listed are skipped, keeping their previously synced endpoints if any, so that a
+// single failing service does not prevent the others from being synced.
+// Services and endpoints are sorted so that unchanged catalogs compare equal between syncs.
+func (c *Client) listServices(token string) ([]endpoint.ExternalService, error) {
+	var serviceTags map[string][]string
+	if err := c.get("/v1/catalog/services", nil, token, &serviceTags); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(serviceTags))
+	for name := range serviceTags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var services []endpoint.ExternalService
+	for _, name := range names {
+		endpoints, err := c.listEndpointsForService(name, token)
+		if err != nil {
+			log.Error().Err(err).Msgf("[%s] Error listing instances of Consul service %s, skipping", c.providerIdent, name)
+			if previous, ok := c.getService(name); ok {
+				services = append(services, previous)
+			}
+			continue
+		}
+		if len(endpoints) == 0 {
+			log.Trace().Msgf("[%s] No healthy instances found for Consul service %s", c.providerIdent, name)
+			continue
+		}
+
+		services = append(services, endpoint.ExternalService{
+			Provider:  c.providerIdent,
+			Name:      name,
+			Endpoints: endpoints,
+		})
+	}
+
+	return services, nil
+}
+
+// listEndpointsForService returns the endpoints of the instances of the given Consul service passing their health checks
+func (c *Client) listEndpointsForService(name string, token string) ([]endpoint.Endpoint, error) {
+	var entries []catalogServiceEntry
+	if err := c.get(fmt.Sprintf("/v1/health/service/%s", url.PathEscape(name)), url.Values{"passing": []string{"true"}}, token, &entries); err != nil {
+		return nil, err
+	}
+
+	var endpoints []endpoint.Endpoint
+	for _, entry := range entries {
+		// The service address defaults to the address of the node the instance is registered on
+		address := entry.Service.Address
+		if address == "" {
+			address = entry.Node.Address
+		}
+
+		ip := net.ParseIP(address)
+		if ip == nil {
+			log.Error().Msgf("[%s] Error parsing IP address %s of an instance of Consul service %s, skipping", c.providerIdent, address, name)
+			continue
+		}
+		if entry.Service.Port <= 0 {
+			log.Error().Msgf("[%s] Instance %s of Consul service %s has no port, skipping", c.providerIdent, address, name)
+			continue
+		}
+
+		endpoints = append(endpoints, endpoint.Endpoint{
+			IP:   ip,
+			Port: endpoint.Port(entry.Service.Port),
+		})
+	}
+
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].String() < endpoints[j].String()
+	})
+	return endpoints, nil
+}
+
+// getService returns the previously synced service with the given name
+func (c *Client) getService(name string) (endpoint.ExternalService, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, svc := range c.services {
+		if svc.Name == name {
+			return svc, true
+		}
+	}
+	return endpoint.ExternalService{}, false
+}
+
+// get issues a GET request to the given path of the Consul HTTP API and decodes the JSON response into out
+func (c *Client) get(path string, query url.Values, token string, out interface{}) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	if c.datacenter != "" {
+		query.Set("dc", c.datacenter)
+	}
+
+	reqURL := c.address + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, reqURL, nil)
+	if err != nil {
+		return errors.Wrapf(err, "Error creating request for %s", path)
+	}
+	if token != "" {
+		req.Header.Set(tokenHeader, token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "Error requesting %s", path)
+	}
+	defer resp.Body.Close() //nolint: errcheck,gosec
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("Unexpected status code %d requesting %s", resp.StatusCode, path)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return errors.Wrapf(err, "Error decoding response of %s", path)
+	}
+	return nil
+}
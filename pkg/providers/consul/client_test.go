@@ -0,0 +1,169 @@
+package consul
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/endpoint"
+)
+
+func newTestConsulServer(t *testing.T, catalog map[string][]catalogServiceEntry) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert := tassert.New(t)
+		assert.Equal("dc1", r.URL.Query().Get("dc"))
+		assert.Equal("secret", r.Header.Get(tokenHeader))
+
+		var resp interface{}
+		switch {
+		case r.URL.Path == "/v1/catalog/services":
+			serviceTags := make(map[string][]string)
+			for name := range catalog {
+				serviceTags[name] = nil
+			}
+			resp = serviceTags
+		case len(r.URL.Path) > len("/v1/health/service/"):
+			assert.Equal("true", r.URL.Query().Get("passing"))
+			entries, ok := catalog[r.URL.Path[len("/v1/health/service/"):]]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			if entries == nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			resp = entries
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		assert.Nil(json.NewEncoder(w).Encode(resp))
+	}))
+}
+
+func TestListServices(t *testing.T) {
+	assert := tassert.New(t)
+
+	server := newTestConsulServer(t, map[string][]catalogServiceEntry{
+		"web": {
+			{Node: catalogNode{Address: "10.0.0.1"}, Service: catalogService{Port: 8080}},
+			{Node: catalogNode{Address: "10.0.0.1"}, Service: catalogService{Address: "10.0.1.2", Port: 9090}},
+		},
+		"db": {
+			{Node: catalogNode{Address: "10.0.0.3"}, Service: catalogService{Port: 5432}},
+			{Node: catalogNode{Address: "db.example.com"}, Service: catalogService{Port: 5432}},
+		},
+		"unhealthy": {},
+	})
+	defer server.Close()
+
+	c := NewClient(constants.ConsulProviderName, Config{Address: server.URL + "/", Datacenter: "dc1", Token: "secret"})
+
+	services, err := c.listServices("secret")
+	assert.Nil(err)
+	assert.Equal([]endpoint.ExternalService{
+		{
+			Provider:  constants.ConsulProviderName,
+			Name:      "db",
+			Endpoints: []endpoint.Endpoint{{IP: net.ParseIP("10.0.0.3"), Port: 5432}},
+		},
+		{
+			Provider: constants.ConsulProviderName,
+			Name:     "web",
+			Endpoints: []endpoint.Endpoint{
+				{IP: net.ParseIP("10.0.0.1"), Port: 8080},
+				{IP: net.ParseIP("10.0.1.2"), Port: 9090},
+			},
+		},
+	}, services)
+}
+
+func TestSync(t *testing.T) {
+	assert := tassert.New(t)
+
+	server := newTestConsulServer(t, map[string][]catalogServiceEntry{
+		"web": {{Node: catalogNode{Address: "10.0.0.1"}, Service: catalogService{Port: 8080}}},
+	})
+
+	c := NewClient(constants.ConsulProviderName, Config{Address: server.URL, Datacenter: "dc1", Token: "secret"})
+	assert.Equal(constants.ConsulProviderName, c.GetID())
+	assert.Empty(c.ListExternalServices())
+
+	c.sync()
+	assert.Len(c.ListExternalServices(), 1)
+
+	// The cached services are retained when the catalog cannot be read
+	server.Close()
+	c.sync()
+	assert.Len(c.ListExternalServices(), 1)
+}
+
+func TestListServicesSkipsFailingService(t *testing.T) {
+	assert := tassert.New(t)
+
+	catalog := map[string][]catalogServiceEntry{
+		"web": {{Node: catalogNode{Address: "10.0.0.1"}, Service: catalogService{Port: 8080}}},
+		"db":  {{Node: catalogNode{Address: "10.0.0.3"}, Service: catalogService{Port: 5432}}},
+	}
+	server := newTestConsulServer(t, catalog)
+	defer server.Close()
+
+	c := NewClient(constants.ConsulProviderName, Config{Address: server.URL, Datacenter: "dc1", Token: "secret"})
+	c.sync()
+	assert.Len(c.ListExternalServices(), 2)
+
+	// The instances of 'db' can no longer be listed: its previously synced endpoints are kept and 'web' is still synced
+	catalog["web"] = append(catalog["web"], catalogServiceEntry{Node: catalogNode{Address: "10.0.0.2"}, Service: catalogService{Port: 8080}})
+	catalog["db"] = nil
+	services, err := c.listServices("secret")
+	assert.Nil(err)
+	assert.Equal([]endpoint.ExternalService{
+		{
+			Provider:  constants.ConsulProviderName,
+			Name:      "db",
+			Endpoints: []endpoint.Endpoint{{IP: net.ParseIP("10.0.0.3"), Port: 5432}},
+		},
+		{
+			Provider: constants.ConsulProviderName,
+			Name:     "web",
+			Endpoints: []endpoint.Endpoint{
+				{IP: net.ParseIP("10.0.0.1"), Port: 8080},
+				{IP: net.ParseIP("10.0.0.2"), Port: 8080},
+			},
+		},
+	}, services)
+}
+
+func TestGetToken(t *testing.T) {
+	assert := tassert.New(t)
+
+	c := NewClient(constants.ConsulProviderName, Config{Token: "from-env"})
+	token, err := c.getToken()
+	assert.Nil(err)
+	assert.Equal("from-env", token)
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	assert.Nil(os.WriteFile(tokenFile, []byte("from-file\n"), 0600))
+	c = NewClient(constants.ConsulProviderName, Config{Token: "from-env", TokenFile: tokenFile})
+	token, err = c.getToken()
+	assert.Nil(err)
+	assert.Equal("from-file", token)
+
+	// A rotated token is picked up
+	assert.Nil(os.WriteFile(tokenFile, []byte("rotated"), 0600))
+	token, err = c.getToken()
+	assert.Nil(err)
+	assert.Equal("rotated", token)
+
+	c = NewClient(constants.ConsulProviderName, Config{TokenFile: filepath.Join(t.TempDir(), "missing")})
+	_, err = c.getToken()
+	assert.NotNil(err)
+}
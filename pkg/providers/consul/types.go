@@ -0,0 +1,82 @@
+// Package consul implements an external endpoints provider that discovers services and their instances from a
+// Consul catalog, so that mesh workloads can reach services registered with Consul.
+package consul
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/openservicemesh/osm/pkg/endpoint"
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var (
+	log = logger.New("consul-provider")
+)
+
+const (
+	// DefaultSyncInterval is the default interval at which services are synced from the Consul catalog
+	DefaultSyncInterval = 30 * time.Second
+
+	// requestTimeout is the timeout of requests to the Consul HTTP API
+	requestTimeout = 10 * time.Second
+
+	// tokenHeader is the header carrying the ACL token in requests to the Consul HTTP API
+	tokenHeader = "X-Consul-Token"
+
+	// TokenEnvVar is the environment variable holding the ACL token used to read the Consul catalog
+	TokenEnvVar = "CONSUL_HTTP_TOKEN"
+)
+
+// Config is the type used to represent the configuration of the Consul catalog provider
+type Config struct {
+	// Address is the base URL (http[s]://host:port) of the Consul HTTP API
+	Address string
+
+	// Datacenter is the Consul datacenter services are discovered from, defaults to the datacenter of the agent
+	// serving the requests when unset.
+	Datacenter string
+
+	// Token is the ACL token used to read the Consul catalog, read from the CONSUL_HTTP_TOKEN environment variable.
+	// It is ignored when TokenFile is set.
+	Token string
+
+	// TokenFile is the path of a file holding the ACL token used to read the Consul catalog, typically mounted from
+	// a Kubernetes Secret. The file is read at every sync so that a rotated token is picked up.
+	TokenFile string
+
+	// Interval is the interval at which services are synced from the Consul catalog.
+	Interval time.Duration
+}
+
+// Client is the type used to discover services from a Consul catalog
+type Client struct {
+	providerIdent string
+	address       string
+	datacenter    string
+	token         string
+	tokenFile     string
+	interval      time.Duration
+	httpClient    *http.Client
+
+	mu       sync.RWMutex
+	services []endpoint.ExternalService
+}
+
+// catalogServiceEntry is the type used to decode the entries returned by the Consul health API for a service
+type catalogServiceEntry struct {
+	Node    catalogNode    `json:"Node"`
+	Service catalogService `json:"Service"`
+}
+
+// catalogNode is the type used to decode the node an instance of a Consul service is registered on
+type catalogNode struct {
+	Address string `json:"Address"`
+}
+
+// catalogService is the type used to decode an instance of a Consul service
+type catalogService struct {
+	Address string `json:"Address"`
+	Port    int    `json:"Port"`
+}
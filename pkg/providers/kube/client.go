@@ -2,28 +2,37 @@ package kube
 
 import (
 	"net"
+	"sort"
+	"time"
 
 	mapset "github.com/deckarep/golang-set"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
+	"github.com/openservicemesh/osm/pkg/announcements"
 	"github.com/openservicemesh/osm/pkg/config"
 	"github.com/openservicemesh/osm/pkg/configurator"
 	"github.com/openservicemesh/osm/pkg/endpoint"
 	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
 	"github.com/openservicemesh/osm/pkg/service"
 	"github.com/openservicemesh/osm/pkg/utils"
 )
 
 // NewClient returns a client that has all components necessary to connect to and maintain state of a Kubernetes cluster.
 func NewClient(kubeController k8s.Controller, configClient config.Controller, providerIdent string, cfg configurator.Configurator) *Client {
+	dampener := newEndpointDampener()
+	dampener.onPinChange = scheduleProxyBroadcast
+
 	return &Client{
 		providerIdent:    providerIdent,
 		kubeController:   kubeController,
 		configClient:     configClient,
 		meshConfigurator: cfg,
+		dampener:         dampener,
 	}
 }
 
@@ -33,10 +42,16 @@ func (c *Client) GetID() string {
 	return c.providerIdent
 }
 
-// ListEndpointsForService retrieves the list of IP addresses for the given service
+// ListEndpointsForService retrieves the list of IP addresses for the given service.
+// The endpoints are built from the service's EndpointSlices if any, and from its Endpoints resource otherwise.
 func (c *Client) ListEndpointsForService(svc service.MeshService) []endpoint.Endpoint {
 	log.Trace().Msgf("[%s] Getting Endpoints for service %s on Kubernetes", c.providerIdent, svc)
 
+	if slices := c.kubeController.ListEndpointSlicesForService(svc); len(slices) > 0 && c.kubeController.IsMonitoredNamespace(svc.Namespace) {
+		endpoints := c.getEndpointsFromSlices(svc, slices, c.getEndpointPinner())
+		return c.appendNonKubernetesEndpoints(svc, endpoints)
+	}
+
 	kubernetesEndpoints, err := c.kubeController.GetEndpoints(svc)
 	if err != nil || kubernetesEndpoints == nil {
 		log.Error().Err(err).Msgf("[%s] Error fetching Kubernetes Endpoints from cache for service %s", c.providerIdent, svc)
@@ -48,9 +63,21 @@ func (c *Client) ListEndpointsForService(svc service.MeshService) []endpoint.End
 		return nil
 	}
 
+	isPinned := c.getEndpointPinner()
+	includeTerminating := c.meshConfigurator.IncludeTerminatingEndpoints()
+
 	var endpoints []endpoint.Endpoint
 	for _, kubernetesEndpoint := range kubernetesEndpoints.Subsets {
 		for _, address := range kubernetesEndpoint.Addresses {
+			if isPinned(address.IP) {
+				log.Debug().Msgf("[%s] Skipping endpoint %s for service %s, its readiness is flapping", c.providerIdent, address.IP, svc)
+				continue
+			}
+			// Pods being deleted remain ready addresses until the endpoints controller catches up
+			draining := c.isPodTerminating(address.TargetRef)
+			if draining && !includeTerminating {
+				continue
+			}
 			for _, port := range kubernetesEndpoint.Ports {
 				ip := net.ParseIP(address.IP)
 				if ip == nil {
@@ -58,14 +85,25 @@ func (c *Client) ListEndpointsForService(svc service.MeshService) []endpoint.End
 					break
 				}
 				ept := endpoint.Endpoint{
-					IP:   ip,
-					Port: endpoint.Port(port.Port),
+					IP:       ip,
+					Port:     endpoint.Port(port.Port),
+					Draining: draining,
+					PortName: port.Name,
 				}
 				endpoints = append(endpoints, ept)
 			}
 		}
 	}
 
+	return c.appendNonKubernetesEndpoints(svc, endpoints)
+}
+
+// appendNonKubernetesEndpoints returns the given endpoints of the given service with the endpoints of the external
+// workloads it selects and its multicluster endpoints appended
+func (c *Client) appendNonKubernetesEndpoints(svc service.MeshService, endpoints []endpoint.Endpoint) []endpoint.Endpoint {
+	// Add the endpoints of external workloads selected by the service
+	endpoints = append(endpoints, c.getExternalWorkloadEndpointsForService(svc)...)
+
 	// Add multicluster service endpoints
 	if c.meshConfigurator.GetFeatureFlags().EnableMulticlusterMode {
 		endpoints = append(endpoints, c.getMulticlusterEndpoints(svc)...)
@@ -76,6 +114,79 @@ func (c *Client) ListEndpointsForService(svc service.MeshService) []endpoint.End
 	return endpoints
 }
 
+// getEndpointPinner returns a function that determines whether the endpoint with the given IP is pinned out.
+// Endpoints are never pinned out when endpoint flap dampening is disabled.
+func (c *Client) getEndpointPinner() func(ip string) bool {
+	if !c.meshConfigurator.GetEndpointFlapDampeningConfig().Enable {
+		return func(string) bool { return false }
+	}
+	return c.dampener.isPinned
+}
+
+// observeEndpoints records the readiness of the given endpoints when endpoint flap dampening is enabled
+func (c *Client) observeEndpoints(kubernetesEndpoints *corev1.Endpoints) {
+	dampening := c.meshConfigurator.GetEndpointFlapDampeningConfig()
+	if !dampening.Enable || !c.kubeController.IsMonitoredNamespace(kubernetesEndpoints.Namespace) {
+		return
+	}
+
+	window, err := time.ParseDuration(dampening.Window)
+	if err != nil || window <= 0 {
+		window = defaultFlapDampeningWindow
+	}
+	maxTransitions := dampening.MaxTransitions
+	if maxTransitions <= 0 {
+		maxTransitions = defaultFlapDampeningMaxTransitions
+	}
+
+	for _, subset := range kubernetesEndpoints.Subsets {
+		for _, address := range subset.Addresses {
+			c.dampener.observe(address.IP, true, window, maxTransitions)
+		}
+		for _, address := range subset.NotReadyAddresses {
+			c.dampener.observe(address.IP, false, window, maxTransitions)
+		}
+	}
+}
+
+// Run observes the readiness of endpoints as the Endpoints resources change until the stop channel is closed,
+// so that endpoints whose readiness flaps are pinned out irrespective of when proxy configurations are built
+func (c *Client) Run(stop <-chan struct{}) {
+	endpointsSubscription := events.Subscribe(announcements.EndpointAdded, announcements.EndpointUpdated)
+
+	go func() {
+		defer events.Unsub(endpointsSubscription)
+
+		for {
+			select {
+			case <-stop:
+				return
+			case msg := <-endpointsSubscription:
+				psubMessage, castOk := msg.(events.PubSubMessage)
+				if !castOk {
+					log.Error().Msgf("Error casting PubSubMessage: %T %v", msg, msg)
+					continue
+				}
+				kubernetesEndpoints, castOk := psubMessage.NewObj.(*corev1.Endpoints)
+				if !castOk {
+					log.Error().Msgf("Failed to cast to *v1.Endpoints: %T %v", psubMessage.NewObj, psubMessage.NewObj)
+					continue
+				}
+				c.observeEndpoints(kubernetesEndpoints)
+			}
+		}
+	}()
+}
+
+// scheduleProxyBroadcast requests proxies to be updated, used when an endpoint is pinned out or its pin expires
+func scheduleProxyBroadcast() {
+	events.Publish(events.PubSubMessage{
+		AnnouncementType: announcements.ScheduleProxyBroadcast,
+		OldObj:           nil,
+		NewObj:           nil,
+	})
+}
+
 // ListEndpointsForIdentity retrieves the list of IP addresses for the given service account
 // Note: ServiceIdentity must be in the format "name.namespace" [https://github.com/openservicemesh/osm/issues/3188]
 func (c *Client) ListEndpointsForIdentity(serviceIdentity identity.ServiceIdentity) []endpoint.Endpoint {
@@ -102,6 +213,9 @@ func (c *Client) ListEndpointsForIdentity(serviceIdentity identity.ServiceIdenti
 		}
 	}
 
+	// Add the endpoints of external workloads mapped to the service account
+	endpoints = append(endpoints, c.getExternalWorkloadEndpointsForIdentity(sa)...)
+
 	// Add multicluster service endpoints
 	if c.meshConfigurator.GetFeatureFlags().EnableMulticlusterMode {
 		endpoints = append(endpoints, c.getMultiClusterServiceEndpointsForServiceAccount(sa.Name, sa.Namespace)...)
@@ -143,6 +257,25 @@ func (c *Client) GetServicesForServiceIdentity(svcIdentity identity.ServiceIdent
 		}
 	}
 
+	for _, externalWorkload := range c.kubeController.ListExternalWorkloads() {
+		if externalWorkload.Namespace != svcAccount.Namespace || externalWorkload.Spec.ServiceAccount != svcAccount.Name {
+			continue
+		}
+
+		k8sServices, err := c.getServicesByLabels(externalWorkload.Labels, externalWorkload.Namespace)
+		if err != nil {
+			log.Error().Err(err).Msgf("[%s] Error retrieving service matching labels %v in namespace %s", c.providerIdent, externalWorkload.Labels, externalWorkload.Namespace)
+			return nil, err
+		}
+
+		for _, svc := range k8sServices {
+			services.Add(service.MeshService{
+				Namespace: externalWorkload.Namespace,
+				Name:      svc.Name,
+			})
+		}
+	}
+
 	if services.Cardinality() == 0 {
 		log.Error().Err(errServiceNotFound).Msgf("[%s] No services for service account %s", c.providerIdent, svcAccount)
 		return nil, errServiceNotFound
@@ -178,18 +311,19 @@ func (c *Client) GetTargetPortToProtocolMappingForService(svc service.MeshServic
 	// to worry about different application protocols being set.
 	for _, endpointSet := range endpoints.Subsets {
 		for _, port := range endpointSet.Ports {
-			var appProtocol string
-			if port.AppProtocol != nil {
-				appProtocol = *port.AppProtocol
-			} else {
-				appProtocol = k8s.GetAppProtocolFromPortName(port.Name)
-				log.Debug().Msgf("endpoint port name: %s, appProtocol: %s", port.Name, appProtocol)
-			}
+			appProtocol := k8s.GetAppProtocolFromEndpointPort(port)
+			log.Debug().Msgf("endpoint port name: %s, appProtocol: %s", port.Name, appProtocol)
 
 			portToProtocolMap[uint32(port.Port)] = appProtocol
 		}
 	}
 
+	for port, appProtocol := range c.getExternalWorkloadTargetPortToProtocolMapping(svc) {
+		if _, ok := portToProtocolMap[port]; !ok {
+			portToProtocolMap[port] = appProtocol
+		}
+	}
+
 	return portToProtocolMap, nil
 }
 
@@ -220,7 +354,8 @@ func (c *Client) getServicesByLabels(podLabels map[string]string, namespace stri
 }
 
 // GetResolvableEndpointsForService returns the expected endpoints that are to be reached when the service
-// FQDN is resolved
+// FQDN is resolved. The virtual IPs of the service, ie. its external and load balancer IPs, are also returned so that
+// the traffic to these IPs is proxied to the service.
 func (c *Client) GetResolvableEndpointsForService(svc service.MeshService) ([]endpoint.Endpoint, error) {
 	var endpoints []endpoint.Endpoint
 	var err error
@@ -244,11 +379,22 @@ func (c *Client) GetResolvableEndpointsForService(svc service.MeshService) ([]en
 		return nil, errParseClusterIP
 	}
 
-	for _, svcPort := range kubeService.Spec.Ports {
-		endpoints = append(endpoints, endpoint.Endpoint{
-			IP:   ip,
-			Port: endpoint.Port(svcPort.Port),
-		})
+	ips := []net.IP{ip}
+	for _, vip := range k8s.GetServiceVIPs(kubeService) {
+		if parsed := net.ParseIP(vip); parsed != nil {
+			ips = append(ips, parsed)
+		} else {
+			log.Error().Msgf("[%s] Could not parse virtual IP %s of service %s", c.providerIdent, vip, svc)
+		}
+	}
+
+	for _, ip := range ips {
+		for _, svcPort := range kubeService.Spec.Ports {
+			endpoints = append(endpoints, endpoint.Endpoint{
+				IP:   ip,
+				Port: endpoint.Port(svcPort.Port),
+			})
+		}
 	}
 
 	return endpoints, err
@@ -292,18 +438,70 @@ func (c *Client) GetPortToProtocolMappingForService(svc service.MeshService) (ma
 	}
 
 	for _, portSpec := range k8sSvc.Spec.Ports {
-		var appProtocol string
-		if portSpec.AppProtocol != nil {
-			appProtocol = *portSpec.AppProtocol
-		} else {
-			appProtocol = k8s.GetAppProtocolFromPortName(portSpec.Name)
-		}
-		portToProtocolMap[uint32(portSpec.Port)] = appProtocol
+		portToProtocolMap[uint32(portSpec.Port)] = k8s.GetAppProtocolFromServicePort(portSpec)
 	}
 
 	return portToProtocolMap, nil
 }
 
+// ListServicePorts returns the ports exposed by the service, along with their target ports and application protocols.
+// A target port referenced by name is resolved from the ports of the service's endpoints, its name being retained since
+// the pods backing the service may resolve it to different ports.
+func (c *Client) ListServicePorts(svc service.MeshService) ([]service.ServicePort, error) {
+	k8sSvc := c.kubeController.GetService(svc)
+	if k8sSvc == nil {
+		return nil, errors.Wrapf(errServiceNotFound, "Error retrieving k8s service %s", svc)
+	}
+
+	var ports []service.ServicePort
+	for _, portSpec := range k8sSvc.Spec.Ports {
+		var targetPortName string
+		if portSpec.TargetPort.Type == intstr.String {
+			targetPortName = portSpec.TargetPort.StrVal
+		}
+
+		targetPort := uint32(portSpec.TargetPort.IntValue())
+		if targetPort == 0 {
+			targetPort = c.getEndpointsTargetPort(svc, portSpec.Name)
+		}
+		if targetPort == 0 {
+			// The target port defaults to the port of the service
+			targetPort = uint32(portSpec.Port)
+		}
+
+		ports = append(ports, service.ServicePort{
+			Name:           portSpec.Name,
+			Port:           uint32(portSpec.Port),
+			TargetPort:     targetPort,
+			TargetPortName: targetPortName,
+			Protocol:       k8s.GetAppProtocolFromServicePort(portSpec),
+		})
+	}
+
+	sort.Slice(ports, func(i, j int) bool {
+		return ports[i].Port < ports[j].Port
+	})
+
+	return ports, nil
+}
+
+// getEndpointsTargetPort returns the port of the service's endpoints with the given name, or 0 if there is none
+func (c *Client) getEndpointsTargetPort(svc service.MeshService, portName string) uint32 {
+	endpoints, err := c.kubeController.GetEndpoints(svc)
+	if err != nil || endpoints == nil {
+		return 0
+	}
+
+	for _, endpointSet := range endpoints.Subsets {
+		for _, port := range endpointSet.Ports {
+			if port.Name == portName {
+				return uint32(port.Port)
+			}
+		}
+	}
+	return 0
+}
+
 // GetHostnamesForService returns a list of hostnames over which the service can be accessed within the local cluster.
 func (c *Client) GetHostnamesForService(svc service.MeshService, locality service.Locality) ([]string, error) {
 	k8svc := c.kubeController.GetService(svc)
@@ -11,6 +11,7 @@ import (
 	tassert "github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	testclient "k8s.io/client-go/kubernetes/fake"
 
 	. "github.com/onsi/ginkgo"
@@ -47,6 +48,10 @@ var _ = Describe("Test Kube Client Provider (w/o kubecontroller)", func() {
 	mockConfigController := config.NewMockController(mockCtrl)
 
 	mockKubeController.EXPECT().IsMonitoredNamespace(tests.BookbuyerService.Namespace).Return(true).AnyTimes()
+	mockKubeController.EXPECT().ListExternalWorkloads().Return(nil).AnyTimes()
+	mockKubeController.EXPECT().ListEndpointSlicesForService(gomock.Any()).Return(nil).AnyTimes()
+	mockConfigurator.EXPECT().GetEndpointFlapDampeningConfig().Return(v1alpha1.EndpointFlapDampeningSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().IncludeTerminatingEndpoints().Return(false).AnyTimes()
 
 	BeforeEach(func() {
 		client = NewClient(mockKubeController, mockConfigController, providerID, mockConfigurator)
@@ -115,6 +120,40 @@ var _ = Describe("Test Kube Client Provider (w/o kubecontroller)", func() {
 		}))
 	})
 
+	It("GetResolvableEndpoints should return the virtual IPs of LoadBalancer services along with their ClusterIP", func() {
+		mockKubeController.EXPECT().GetService(tests.BookbuyerService).Return(&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      tests.BookbuyerService.Name,
+				Namespace: tests.BookbuyerService.Namespace,
+			},
+			Spec: corev1.ServiceSpec{
+				Type:      corev1.ServiceTypeLoadBalancer,
+				ClusterIP: "192.168.0.1",
+				Ports: []corev1.ServicePort{{
+					Name:     "servicePort",
+					Protocol: corev1.ProtocolTCP,
+					Port:     tests.ServicePort,
+				}},
+			},
+			Status: corev1.ServiceStatus{
+				LoadBalancer: corev1.LoadBalancerStatus{
+					Ingress: []corev1.LoadBalancerIngress{{IP: "20.0.0.20"}, {Hostname: "bookbuyer.example.com"}},
+				},
+			},
+		})
+
+		Expect(client.GetResolvableEndpointsForService(tests.BookbuyerService)).To(Equal([]endpoint.Endpoint{
+			{
+				IP:   net.IPv4(192, 168, 0, 1),
+				Port: tests.ServicePort,
+			},
+			{
+				IP:   net.IPv4(20, 0, 0, 20),
+				Port: tests.ServicePort,
+			},
+		}))
+	})
+
 	It("GetResolvableEndpoints should properly return actual endpoints without ClusterIP when ClusterIP is not set", func() {
 		// Expect the individual pod endpoints, when no cluster IP is assigned to the service
 		mockKubeController.EXPECT().GetService(tests.BookbuyerService).Return(&corev1.Service{
@@ -158,8 +197,9 @@ var _ = Describe("Test Kube Client Provider (w/o kubecontroller)", func() {
 
 		Expect(client.GetResolvableEndpointsForService(tests.BookbuyerService)).To(Equal([]endpoint.Endpoint{
 			{
-				IP:   net.IPv4(8, 8, 8, 8),
-				Port: 88,
+				IP:       net.IPv4(8, 8, 8, 8),
+				Port:     88,
+				PortName: "port",
 			},
 		}))
 	})
@@ -209,8 +249,9 @@ var _ = Describe("Test Kube Client Provider (w/o kubecontroller)", func() {
 
 		Expect(client.GetResolvableEndpointsForService(tests.BookbuyerService)).To(Equal([]endpoint.Endpoint{
 			{
-				IP:   net.IPv4(8, 8, 8, 8),
-				Port: 88,
+				IP:       net.IPv4(8, 8, 8, 8),
+				Port:     88,
+				PortName: "port",
 			},
 		}))
 	})
@@ -746,6 +787,7 @@ func TestListEndpointsForIdentity(t *testing.T) {
 				pods = append(pods, &pod)
 			}
 			mockKubeController.EXPECT().ListPods().Return(pods).AnyTimes()
+			mockKubeController.EXPECT().ListExternalWorkloads().Return(nil).AnyTimes()
 
 			actual := provider.ListEndpointsForIdentity(tc.serviceAccount)
 			assert.NotNil(actual)
@@ -812,3 +854,93 @@ func TestGetMultiClusterServiceEndpointsForServiceAccount(t *testing.T) {
 		},
 	})
 }
+
+func TestObserveEndpointsPinsFlappingEndpoints(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockKubeController.EXPECT().IsMonitoredNamespace(tests.Namespace).Return(true).AnyTimes()
+	mockConfigurator.EXPECT().GetEndpointFlapDampeningConfig().Return(v1alpha1.EndpointFlapDampeningSpec{
+		Enable:         true,
+		Window:         "1m",
+		MaxTransitions: 2,
+	}).AnyTimes()
+
+	client := NewClient(mockKubeController, nil, "provider", mockConfigurator)
+	client.dampener.onPinChange = nil
+
+	ready := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: tests.Namespace},
+		Subsets:    []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}}},
+	}
+	notReady := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: tests.Namespace},
+		Subsets:    []corev1.EndpointSubset{{NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}}},
+	}
+
+	// Readiness is observed from the Endpoints resources, not when proxy configurations are built
+	for _, eps := range []*corev1.Endpoints{ready, notReady, ready} {
+		client.observeEndpoints(eps)
+	}
+	assert.True(client.getEndpointPinner()("10.0.0.1"))
+	assert.False(client.getEndpointPinner()("10.0.0.2"))
+}
+
+func TestNamedTargetPortResolvedPerPod(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	client := NewClient(mockKubeController, nil, "provider", mockConfigurator)
+
+	svc := tests.BookstoreV1Service
+	// The pods backing the service resolve the 'web' target port to different container ports
+	kubernetesEndpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: svc.Namespace, Name: svc.Name},
+		Subsets: []corev1.EndpointSubset{
+			{
+				Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}},
+				Ports:     []corev1.EndpointPort{{Name: "http", Port: 8080}, {Name: "tcp-db", Port: 5432}},
+			},
+			{
+				Addresses: []corev1.EndpointAddress{{IP: "10.0.0.2"}},
+				Ports:     []corev1.EndpointPort{{Name: "http", Port: 8081}, {Name: "tcp-db", Port: 5432}},
+			},
+		},
+	}
+	mockKubeController.EXPECT().GetService(svc).Return(&corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: svc.Namespace, Name: svc.Name},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{Name: "http", Port: 80, TargetPort: intstr.FromString("web")},
+				{Name: "tcp-db", Port: 5432, TargetPort: intstr.FromInt(5432)},
+			},
+		},
+	}).AnyTimes()
+	mockKubeController.EXPECT().GetEndpoints(svc).Return(kubernetesEndpoints, nil).AnyTimes()
+	mockKubeController.EXPECT().ListEndpointSlicesForService(svc).Return(nil).AnyTimes()
+	mockKubeController.EXPECT().IsMonitoredNamespace(svc.Namespace).Return(true).AnyTimes()
+	mockKubeController.EXPECT().ListExternalWorkloads().Return(nil).AnyTimes()
+	mockConfigurator.EXPECT().IncludeTerminatingEndpoints().Return(false).AnyTimes()
+	mockConfigurator.EXPECT().GetEndpointFlapDampeningConfig().Return(v1alpha1.EndpointFlapDampeningSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{}).AnyTimes()
+
+	ports, err := client.ListServicePorts(svc)
+	assert.Nil(err)
+	assert.Equal([]service.ServicePort{
+		{Name: "http", Port: 80, TargetPort: 8080, TargetPortName: "web", Protocol: "http"},
+		{Name: "tcp-db", Port: 5432, TargetPort: 5432, Protocol: "tcp"},
+	}, ports)
+
+	assert.ElementsMatch([]endpoint.Endpoint{
+		{IP: net.ParseIP("10.0.0.1"), Port: 8080, PortName: "http"},
+		{IP: net.ParseIP("10.0.0.1"), Port: 5432, PortName: "tcp-db"},
+		{IP: net.ParseIP("10.0.0.2"), Port: 8081, PortName: "http"},
+		{IP: net.ParseIP("10.0.0.2"), Port: 5432, PortName: "tcp-db"},
+	}, client.ListEndpointsForService(svc))
+}
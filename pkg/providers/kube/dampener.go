@@ -0,0 +1,169 @@
+package kube
+
+import (
+	"sync"
+	"time"
+
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+)
+
+const (
+	// defaultFlapDampeningWindow is the default window over which readiness transitions of an endpoint are counted
+	defaultFlapDampeningWindow = 1 * time.Minute
+
+	// defaultFlapDampeningMaxTransitions is the default number of readiness transitions within the window
+	// after which an endpoint is pinned out
+	defaultFlapDampeningMaxTransitions = 4
+)
+
+// endpointDampener tracks the readiness transitions of endpoints so that endpoints whose readiness flaps
+// can be pinned out, instead of causing continuous endpoint updates to be pushed to proxies.
+type endpointDampener struct {
+	sync.Mutex
+
+	// endpoints maps an endpoint IP to its readiness state
+	endpoints map[string]*endpointReadiness
+
+	lastPrune time.Time
+
+	// onPinChange is called when an endpoint is pinned out or when its pin expires, so that proxies can be
+	// updated to exclude or include the endpoint again
+	onPinChange func()
+
+	// now returns the current time, and afterFunc calls a function after a duration, overridden in tests
+	now       func() time.Time
+	afterFunc func(time.Duration, func())
+}
+
+// endpointReadiness is the readiness state of an endpoint tracked by the endpointDampener
+type endpointReadiness struct {
+	ready       bool
+	transitions []time.Time
+	pinnedUntil time.Time
+	lastSeen    time.Time
+
+	// expiryScheduled is true while a check of the pin's expiry is scheduled
+	expiryScheduled bool
+}
+
+func newEndpointDampener() *endpointDampener {
+	return &endpointDampener{
+		endpoints: make(map[string]*endpointReadiness),
+		now:       time.Now,
+		afterFunc: func(d time.Duration, f func()) { time.AfterFunc(d, f) },
+	}
+}
+
+// observe records the readiness of the endpoint with the given IP, and returns true if the endpoint
+// is pinned out. An endpoint is pinned out when its readiness transitions at least maxTransitions
+// times within the window, and remains pinned out until its readiness is stable for the window.
+func (d *endpointDampener) observe(ip string, ready bool, window time.Duration, maxTransitions int) bool {
+	d.Lock()
+	pinned, pinStarted := d.observeLocked(ip, ready, window, maxTransitions)
+	d.Unlock()
+
+	if pinStarted {
+		d.notifyPinChange()
+	}
+	return pinned
+}
+
+// observeLocked records the readiness of the endpoint with the given IP, and returns whether the endpoint is
+// pinned out and whether this observation pinned it out. The dampener must be locked by the caller.
+func (d *endpointDampener) observeLocked(ip string, ready bool, window time.Duration, maxTransitions int) (bool, bool) {
+
+	now := d.now()
+	d.prune(now, window)
+
+	state, ok := d.endpoints[ip]
+	if !ok {
+		d.endpoints[ip] = &endpointReadiness{ready: ready, lastSeen: now}
+		return false, false
+	}
+	state.lastSeen = now
+
+	pinStarted := false
+	if state.ready != ready {
+		state.ready = ready
+		state.transitions = append(state.transitions, now)
+
+		// Discard the transitions that fall outside the window
+		cutoff := now.Add(-window)
+		i := 0
+		for i < len(state.transitions) && state.transitions[i].Before(cutoff) {
+			i++
+		}
+		state.transitions = state.transitions[i:]
+
+		wasPinned := now.Before(state.pinnedUntil)
+		if wasPinned || len(state.transitions) >= maxTransitions {
+			state.pinnedUntil = now.Add(window)
+			if !wasPinned {
+				log.Warn().Msgf("Endpoint with IP %s changed readiness %d times within %s, pinning it out until %s",
+					ip, len(state.transitions), window, state.pinnedUntil)
+				metricsstore.DefaultMetricsStore.EndpointFlapPinCount.Inc()
+				pinStarted = true
+			}
+			d.scheduleExpiryCheck(ip, state, now)
+		}
+	}
+
+	return now.Before(state.pinnedUntil), pinStarted
+}
+
+// isPinned returns true if the endpoint with the given IP is pinned out
+func (d *endpointDampener) isPinned(ip string) bool {
+	d.Lock()
+	defer d.Unlock()
+
+	state, ok := d.endpoints[ip]
+	return ok && d.now().Before(state.pinnedUntil)
+}
+
+// scheduleExpiryCheck schedules a check of the expiry of the given endpoint's pin, unless one is already
+// scheduled. The dampener must be locked by the caller.
+func (d *endpointDampener) scheduleExpiryCheck(ip string, state *endpointReadiness, now time.Time) {
+	if state.expiryScheduled {
+		return
+	}
+	state.expiryScheduled = true
+	d.afterFunc(state.pinnedUntil.Sub(now), func() { d.checkExpiry(ip, state) })
+}
+
+// checkExpiry notifies that the given endpoint's pin expired, or reschedules the check if the pin was extended
+// since the check was scheduled
+func (d *endpointDampener) checkExpiry(ip string, state *endpointReadiness) {
+	d.Lock()
+	state.expiryScheduled = false
+	now := d.now()
+	if now.Before(state.pinnedUntil) {
+		d.scheduleExpiryCheck(ip, state, now)
+		d.Unlock()
+		return
+	}
+	d.Unlock()
+
+	log.Info().Msgf("Endpoint with IP %s is no longer pinned out, its readiness is stable", ip)
+	d.notifyPinChange()
+}
+
+func (d *endpointDampener) notifyPinChange() {
+	if d.onPinChange != nil {
+		d.onPinChange()
+	}
+}
+
+// prune discards the state of endpoints that have not been observed within the window and are not pinned out.
+// Pruning is performed at most once per window.
+func (d *endpointDampener) prune(now time.Time, window time.Duration) {
+	if now.Sub(d.lastPrune) < window {
+		return
+	}
+	d.lastPrune = now
+
+	for ip, state := range d.endpoints {
+		if now.Sub(state.lastSeen) > window && !now.Before(state.pinnedUntil) {
+			delete(d.endpoints, ip)
+		}
+	}
+}
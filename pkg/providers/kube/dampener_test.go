@@ -0,0 +1,146 @@
+package kube
+
+import (
+	"testing"
+	"time"
+
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestEndpointDampenerObserve(t *testing.T) {
+	const (
+		ip             = "10.0.0.1"
+		window         = time.Minute
+		maxTransitions = 3
+	)
+
+	testCases := []struct {
+		name           string
+		observations   []bool
+		interval       time.Duration
+		expectedPinned bool
+	}{
+		{
+			name:           "stable endpoint is not pinned",
+			observations:   []bool{true, true, true, true},
+			interval:       time.Second,
+			expectedPinned: false,
+		},
+		{
+			name:           "endpoint with fewer transitions than the max is not pinned",
+			observations:   []bool{true, false, true},
+			interval:       time.Second,
+			expectedPinned: false,
+		},
+		{
+			name:           "endpoint flapping within the window is pinned",
+			observations:   []bool{true, false, true, false},
+			interval:       time.Second,
+			expectedPinned: true,
+		},
+		{
+			name:           "endpoint with transitions spread beyond the window is not pinned",
+			observations:   []bool{true, false, true, false},
+			interval:       40 * time.Second,
+			expectedPinned: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			now := time.Now()
+			d := newEndpointDampener()
+			d.now = func() time.Time { return now }
+
+			var pinned bool
+			for _, ready := range tc.observations {
+				pinned = d.observe(ip, ready, window, maxTransitions)
+				now = now.Add(tc.interval)
+			}
+			assert.Equal(tc.expectedPinned, pinned)
+		})
+	}
+}
+
+func TestEndpointDampenerUnpinsStableEndpoint(t *testing.T) {
+	assert := tassert.New(t)
+
+	const ip = "10.0.0.1"
+	window := time.Minute
+
+	now := time.Now()
+	d := newEndpointDampener()
+	d.now = func() time.Time { return now }
+
+	for _, ready := range []bool{true, false, true, false, true} {
+		d.observe(ip, ready, window, 2)
+		now = now.Add(time.Second)
+	}
+	assert.True(d.observe(ip, true, window, 2))
+
+	// The endpoint remains pinned until its readiness is stable for the window
+	now = now.Add(window / 2)
+	assert.True(d.observe(ip, true, window, 2))
+
+	now = now.Add(window)
+	assert.False(d.observe(ip, true, window, 2))
+}
+
+func TestEndpointDampenerPrune(t *testing.T) {
+	assert := tassert.New(t)
+
+	window := time.Minute
+
+	now := time.Now()
+	d := newEndpointDampener()
+	d.now = func() time.Time { return now }
+
+	d.observe("10.0.0.1", true, window, 2)
+	d.observe("10.0.0.2", true, window, 2)
+	assert.Len(d.endpoints, 2)
+
+	now = now.Add(2 * window)
+	d.observe("10.0.0.2", true, window, 2)
+	assert.Len(d.endpoints, 1)
+	assert.Contains(d.endpoints, "10.0.0.2")
+}
+
+func TestEndpointDampenerNotifiesPinChanges(t *testing.T) {
+	assert := tassert.New(t)
+
+	const ip = "10.0.0.1"
+	window := time.Minute
+
+	now := time.Now()
+	var scheduled []func()
+	notifications := 0
+
+	d := newEndpointDampener()
+	d.now = func() time.Time { return now }
+	d.afterFunc = func(_ time.Duration, f func()) { scheduled = append(scheduled, f) }
+	d.onPinChange = func() { notifications++ }
+
+	for _, ready := range []bool{true, false, true} {
+		d.observe(ip, ready, window, 2)
+		now = now.Add(time.Second)
+	}
+	assert.True(d.isPinned(ip))
+	assert.Equal(1, notifications)
+	assert.Len(scheduled, 1)
+
+	// The pin is extended by a further transition, the expiry check is rescheduled rather than notifying
+	d.observe(ip, false, window, 2)
+	now = now.Add(window / 2)
+	scheduled[0]()
+	assert.Equal(1, notifications)
+	assert.Len(scheduled, 2)
+
+	// Once the pin expires, proxies are notified so the endpoint is included again
+	now = now.Add(window)
+	scheduled[1]()
+	assert.False(d.isPinned(ip))
+	assert.Equal(2, notifications)
+	assert.Len(scheduled, 2)
+}
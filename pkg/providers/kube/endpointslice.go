@@ -0,0 +1,99 @@
+package kube
+
+import (
+	"net"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
+
+	"github.com/openservicemesh/osm/pkg/endpoint"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// getEndpointsFromSlices returns the endpoints of the given service from its EndpointSlices.
+// Only ready endpoints are returned, unless terminating endpoints are included by the MeshConfig, in which case the
+// endpoints of terminating pods that are still serving are also returned as draining.
+func (c *Client) getEndpointsFromSlices(svc service.MeshService, slices []*discoveryv1beta1.EndpointSlice, isPinned func(ip string) bool) []endpoint.Endpoint {
+	includeTerminating := c.meshConfigurator.IncludeTerminatingEndpoints()
+
+	var endpoints []endpoint.Endpoint
+	for _, slice := range slices {
+		if slice.AddressType == discoveryv1beta1.AddressTypeFQDN {
+			continue
+		}
+
+		for _, sliceEndpoint := range slice.Endpoints {
+			draining := false
+			if c.isTerminating(sliceEndpoint.Conditions, sliceEndpoint.TargetRef) {
+				if !includeTerminating || !isServing(sliceEndpoint.Conditions) {
+					continue
+				}
+				draining = true
+			} else if !isReady(sliceEndpoint.Conditions) {
+				continue
+			}
+
+			for _, address := range sliceEndpoint.Addresses {
+				if isPinned(address) {
+					log.Debug().Msgf("[%s] Skipping endpoint %s for service %s, its readiness is flapping", c.providerIdent, address, svc)
+					continue
+				}
+				ip := net.ParseIP(address)
+				if ip == nil {
+					log.Error().Msgf("[%s] Error parsing IP address %s", c.providerIdent, address)
+					continue
+				}
+				for _, port := range slice.Ports {
+					if port.Port == nil {
+						continue
+					}
+					ept := endpoint.Endpoint{
+						IP:       ip,
+						Port:     endpoint.Port(*port.Port),
+						Draining: draining,
+					}
+					if port.Name != nil {
+						ept.PortName = *port.Name
+					}
+					endpoints = append(endpoints, ept)
+				}
+			}
+		}
+	}
+
+	return endpoints
+}
+
+// isReady returns whether an endpoint with the given conditions is ready, an unknown readiness being interpreted as
+// ready as per the EndpointSlice API
+func isReady(conditions discoveryv1beta1.EndpointConditions) bool {
+	return conditions.Ready == nil || *conditions.Ready
+}
+
+// isServing returns whether an endpoint with the given conditions serves requests. The serving condition is only
+// reported by clusters tracking terminating endpoints, it defaults to the ready condition otherwise.
+func isServing(conditions discoveryv1beta1.EndpointConditions) bool {
+	if conditions.Serving != nil {
+		return *conditions.Serving
+	}
+	return isReady(conditions)
+}
+
+// isTerminating returns whether an endpoint with the given conditions and target is terminating, either as reported
+// by its conditions, or because the pod it targets is being deleted
+func (c *Client) isTerminating(conditions discoveryv1beta1.EndpointConditions, targetRef *corev1.ObjectReference) bool {
+	if conditions.Terminating != nil && *conditions.Terminating {
+		return true
+	}
+	return c.isPodTerminating(targetRef)
+}
+
+// isPodTerminating returns whether the given target of an endpoint is a pod that is being deleted. Pods are marked
+// for deletion before the endpoints controller updates their endpoints, so their deletion timestamp is honored first.
+func (c *Client) isPodTerminating(targetRef *corev1.ObjectReference) bool {
+	if targetRef == nil || targetRef.Kind != "Pod" {
+		return false
+	}
+	pod := c.kubeController.GetPod(targetRef.Namespace, targetRef.Name)
+	return pod != nil && pod.DeletionTimestamp != nil
+}
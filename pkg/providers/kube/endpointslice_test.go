@@ -0,0 +1,156 @@
+package kube
+
+import (
+	"net"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/endpoint"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/tests"
+)
+
+func TestListEndpointsForServiceFromSlices(t *testing.T) {
+	trueVal := true
+	falseVal := false
+	port := int32(8080)
+	now := metav1.Now()
+
+	podRef := func(name string) *corev1.ObjectReference {
+		return &corev1.ObjectReference{Kind: "Pod", Namespace: tests.BookstoreV1Service.Namespace, Name: name}
+	}
+
+	slice := &discoveryv1beta1.EndpointSlice{
+		ObjectMeta:  metav1.ObjectMeta{Namespace: tests.BookstoreV1Service.Namespace, Name: "bookstore-v1-abc"},
+		AddressType: discoveryv1beta1.AddressTypeIPv4,
+		Ports:       []discoveryv1beta1.EndpointPort{{Port: &port}},
+		Endpoints: []discoveryv1beta1.Endpoint{
+			{
+				// Ready
+				Addresses:  []string{"10.0.0.1"},
+				Conditions: discoveryv1beta1.EndpointConditions{Ready: &trueVal},
+				TargetRef:  podRef("ready"),
+			},
+			{
+				// Unknown readiness is interpreted as ready
+				Addresses: []string{"10.0.0.2"},
+			},
+			{
+				// Not ready
+				Addresses:  []string{"10.0.0.3"},
+				Conditions: discoveryv1beta1.EndpointConditions{Ready: &falseVal},
+			},
+			{
+				// Terminating and serving
+				Addresses:  []string{"10.0.0.4"},
+				Conditions: discoveryv1beta1.EndpointConditions{Ready: &falseVal, Serving: &trueVal, Terminating: &trueVal},
+			},
+			{
+				// Terminating and no longer serving
+				Addresses:  []string{"10.0.0.5"},
+				Conditions: discoveryv1beta1.EndpointConditions{Ready: &falseVal, Serving: &falseVal, Terminating: &trueVal},
+			},
+			{
+				// Ready but its pod is being deleted
+				Addresses:  []string{"10.0.0.6"},
+				Conditions: discoveryv1beta1.EndpointConditions{Ready: &trueVal},
+				TargetRef:  podRef("deleted"),
+			},
+		},
+	}
+	fqdnSlice := &discoveryv1beta1.EndpointSlice{
+		AddressType: discoveryv1beta1.AddressTypeFQDN,
+		Ports:       []discoveryv1beta1.EndpointPort{{Port: &port}},
+		Endpoints:   []discoveryv1beta1.Endpoint{{Addresses: []string{"bookstore.example.com"}}},
+	}
+
+	testCases := []struct {
+		name               string
+		includeTerminating bool
+		expectedEndpoints  []endpoint.Endpoint
+	}{
+		{
+			name:               "only ready endpoints",
+			includeTerminating: false,
+			expectedEndpoints: []endpoint.Endpoint{
+				{IP: net.ParseIP("10.0.0.1"), Port: 8080},
+				{IP: net.ParseIP("10.0.0.2"), Port: 8080},
+			},
+		},
+		{
+			name:               "terminating endpoints that are serving are draining",
+			includeTerminating: true,
+			expectedEndpoints: []endpoint.Endpoint{
+				{IP: net.ParseIP("10.0.0.1"), Port: 8080},
+				{IP: net.ParseIP("10.0.0.2"), Port: 8080},
+				{IP: net.ParseIP("10.0.0.4"), Port: 8080, Draining: true},
+				{IP: net.ParseIP("10.0.0.6"), Port: 8080, Draining: true},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockKubeController := k8s.NewMockController(mockCtrl)
+			mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+
+			mockKubeController.EXPECT().ListEndpointSlicesForService(tests.BookstoreV1Service).Return([]*discoveryv1beta1.EndpointSlice{slice, fqdnSlice})
+			mockKubeController.EXPECT().IsMonitoredNamespace(tests.BookstoreV1Service.Namespace).Return(true).AnyTimes()
+			mockKubeController.EXPECT().GetPod(tests.BookstoreV1Service.Namespace, "ready").Return(&corev1.Pod{}).AnyTimes()
+			mockKubeController.EXPECT().GetPod(tests.BookstoreV1Service.Namespace, "deleted").
+				Return(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now}}).AnyTimes()
+			mockKubeController.EXPECT().ListExternalWorkloads().Return(nil).AnyTimes()
+			mockConfigurator.EXPECT().IncludeTerminatingEndpoints().Return(tc.includeTerminating).AnyTimes()
+			mockConfigurator.EXPECT().GetEndpointFlapDampeningConfig().Return(v1alpha1.EndpointFlapDampeningSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{}).AnyTimes()
+
+			client := NewClient(mockKubeController, nil, "provider", mockConfigurator)
+			assert.ElementsMatch(tc.expectedEndpoints, client.ListEndpointsForService(tests.BookstoreV1Service))
+		})
+	}
+}
+
+func TestListEndpointsForServiceSkipsTerminatingPods(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	now := metav1.Now()
+
+	// Without EndpointSlices, the Endpoints resource is used
+	mockKubeController.EXPECT().ListEndpointSlicesForService(tests.BookstoreV1Service).Return(nil)
+	mockKubeController.EXPECT().GetEndpoints(tests.BookstoreV1Service).Return(&corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: tests.BookstoreV1Service.Namespace},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{
+				{IP: "10.0.0.1", TargetRef: &corev1.ObjectReference{Kind: "Pod", Namespace: tests.BookstoreV1Service.Namespace, Name: "running"}},
+				{IP: "10.0.0.2", TargetRef: &corev1.ObjectReference{Kind: "Pod", Namespace: tests.BookstoreV1Service.Namespace, Name: "deleted"}},
+			},
+			Ports: []corev1.EndpointPort{{Port: 8080}},
+		}},
+	}, nil)
+	mockKubeController.EXPECT().IsMonitoredNamespace(tests.BookstoreV1Service.Namespace).Return(true).AnyTimes()
+	mockKubeController.EXPECT().GetPod(tests.BookstoreV1Service.Namespace, "running").Return(&corev1.Pod{})
+	mockKubeController.EXPECT().GetPod(tests.BookstoreV1Service.Namespace, "deleted").
+		Return(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now}})
+	mockKubeController.EXPECT().ListExternalWorkloads().Return(nil).AnyTimes()
+	mockConfigurator.EXPECT().IncludeTerminatingEndpoints().Return(false)
+	mockConfigurator.EXPECT().GetEndpointFlapDampeningConfig().Return(v1alpha1.EndpointFlapDampeningSpec{}).AnyTimes()
+	mockConfigurator.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{}).AnyTimes()
+
+	client := NewClient(mockKubeController, nil, "provider", mockConfigurator)
+	assert.Equal([]endpoint.Endpoint{{IP: net.ParseIP("10.0.0.1"), Port: 8080}}, client.ListEndpointsForService(tests.BookstoreV1Service))
+}
@@ -0,0 +1,144 @@
+package kube
+
+import (
+	"net"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/endpoint"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+// listExternalWorkloadsForService returns the Kubernetes service for the given mesh service along with the external
+// workloads it selects
+func (c *Client) listExternalWorkloadsForService(svc service.MeshService) (*corev1.Service, []*policyv1alpha1.ExternalWorkload) {
+	var candidates []*policyv1alpha1.ExternalWorkload
+	for _, externalWorkload := range c.kubeController.ListExternalWorkloads() {
+		if externalWorkload.Namespace == svc.Namespace {
+			candidates = append(candidates, externalWorkload)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	k8sSvc := c.kubeController.GetService(svc)
+	// service has no selectors, we do not need to match against the external workload labels
+	if k8sSvc == nil || len(k8sSvc.Spec.Selector) == 0 {
+		return nil, nil
+	}
+	selector := labels.Set(k8sSvc.Spec.Selector).AsSelector()
+
+	var externalWorkloads []*policyv1alpha1.ExternalWorkload
+	for _, externalWorkload := range candidates {
+		if selector.Matches(labels.Set(externalWorkload.Labels)) {
+			externalWorkloads = append(externalWorkloads, externalWorkload)
+		}
+	}
+	return k8sSvc, externalWorkloads
+}
+
+// getExternalWorkloadEndpointsForService returns the endpoints of the external workloads selected by the given service
+func (c *Client) getExternalWorkloadEndpointsForService(svc service.MeshService) []endpoint.Endpoint {
+	k8sSvc, externalWorkloads := c.listExternalWorkloadsForService(svc)
+
+	var endpoints []endpoint.Endpoint
+	for _, externalWorkload := range externalWorkloads {
+		for _, address := range externalWorkload.Spec.Addresses {
+			ip := net.ParseIP(address)
+			if ip == nil {
+				log.Error().Msgf("[%s] Error parsing IP address %s of ExternalWorkload %s/%s", c.providerIdent, address, externalWorkload.Namespace, externalWorkload.Name)
+				continue
+			}
+			for _, svcPort := range k8sSvc.Spec.Ports {
+				port, ok := getExternalWorkloadTargetPort(externalWorkload, svcPort)
+				if !ok {
+					continue
+				}
+				endpoints = append(endpoints, endpoint.Endpoint{
+					IP:       ip,
+					Port:     endpoint.Port(port.Number),
+					PortName: svcPort.Name,
+				})
+			}
+		}
+	}
+
+	return endpoints
+}
+
+// getExternalWorkloadEndpointsForIdentity returns the endpoints of the external workloads mapped to the given service account
+func (c *Client) getExternalWorkloadEndpointsForIdentity(sa identity.K8sServiceAccount) []endpoint.Endpoint {
+	var endpoints []endpoint.Endpoint
+	for _, externalWorkload := range c.kubeController.ListExternalWorkloads() {
+		if externalWorkload.Namespace != sa.Namespace || externalWorkload.Spec.ServiceAccount != sa.Name {
+			continue
+		}
+
+		for _, address := range externalWorkload.Spec.Addresses {
+			ip := net.ParseIP(address)
+			if ip == nil {
+				log.Error().Msgf("[%s] Error parsing IP address %s of ExternalWorkload %s/%s", c.providerIdent, address, externalWorkload.Namespace, externalWorkload.Name)
+				continue
+			}
+			endpoints = append(endpoints, endpoint.Endpoint{IP: ip})
+		}
+	}
+
+	return endpoints
+}
+
+// getExternalWorkloadTargetPortToProtocolMapping returns a mapping of the ports of the external workloads selected by the
+// given service to their corresponding application protocol
+func (c *Client) getExternalWorkloadTargetPortToProtocolMapping(svc service.MeshService) map[uint32]string {
+	k8sSvc, externalWorkloads := c.listExternalWorkloadsForService(svc)
+
+	portToProtocolMap := make(map[uint32]string)
+	for _, externalWorkload := range externalWorkloads {
+		for _, svcPort := range k8sSvc.Spec.Ports {
+			port, ok := getExternalWorkloadTargetPort(externalWorkload, svcPort)
+			if !ok {
+				continue
+			}
+
+			appProtocol := port.Protocol
+			if appProtocol == "" {
+				appProtocol = k8s.GetAppProtocolFromServicePort(svcPort)
+			}
+			portToProtocolMap[uint32(port.Number)] = appProtocol
+		}
+	}
+
+	return portToProtocolMap
+}
+
+// getExternalWorkloadTargetPort resolves the target port of the given service port on the given external workload.
+// Named target ports resolve to the workload's port with the same name, numbered target ports resolve to themselves.
+func getExternalWorkloadTargetPort(externalWorkload *policyv1alpha1.ExternalWorkload, svcPort corev1.ServicePort) (policyv1alpha1.ExternalWorkloadPort, bool) {
+	targetPort := svcPort.TargetPort
+	if targetPort.Type == intstr.String {
+		for _, port := range externalWorkload.Spec.Ports {
+			if port.Name == targetPort.StrVal {
+				return port, true
+			}
+		}
+		return policyv1alpha1.ExternalWorkloadPort{}, false
+	}
+
+	number := int(targetPort.IntVal)
+	if number == 0 {
+		// The target port defaults to the service port when not specified
+		number = int(svcPort.Port)
+	}
+	for _, port := range externalWorkload.Spec.Ports {
+		if port.Number == number {
+			return port, true
+		}
+	}
+	return policyv1alpha1.ExternalWorkloadPort{Number: number}, true
+}
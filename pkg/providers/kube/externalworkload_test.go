@@ -0,0 +1,178 @@
+package kube
+
+import (
+	"net"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+
+	"github.com/openservicemesh/osm/pkg/endpoint"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/service"
+)
+
+var testExternalWorkloads = []*policyv1alpha1.ExternalWorkload{
+	{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vm-1",
+			Namespace: "ns",
+			Labels:    map[string]string{"app": "vm"},
+		},
+		Spec: policyv1alpha1.ExternalWorkloadSpec{
+			ServiceAccount: "vm-sa",
+			Addresses:      []string{"10.0.0.1", "10.0.0.2"},
+			Ports: []policyv1alpha1.ExternalWorkloadPort{
+				{Name: "http", Number: 8080, Protocol: "http"},
+			},
+		},
+	},
+	{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vm-other-app",
+			Namespace: "ns",
+			Labels:    map[string]string{"app": "other"},
+		},
+		Spec: policyv1alpha1.ExternalWorkloadSpec{
+			ServiceAccount: "other-sa",
+			Addresses:      []string{"10.0.0.3"},
+		},
+	},
+	{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vm-other-ns",
+			Namespace: "other-ns",
+			Labels:    map[string]string{"app": "vm"},
+		},
+		Spec: policyv1alpha1.ExternalWorkloadSpec{
+			ServiceAccount: "vm-sa",
+			Addresses:      []string{"10.0.0.4"},
+		},
+	},
+}
+
+var testExternalWorkloadService = &corev1.Service{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:      "vm-svc",
+		Namespace: "ns",
+	},
+	Spec: corev1.ServiceSpec{
+		Selector: map[string]string{"app": "vm"},
+		Ports: []corev1.ServicePort{
+			{Name: "http", Port: 80, TargetPort: intstr.FromString("http")},
+			{Name: "tcp-admin", Port: 90, TargetPort: intstr.FromInt(9090)},
+		},
+	},
+}
+
+func TestGetExternalWorkloadEndpointsForService(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	c := &Client{kubeController: mockKubeController}
+
+	svc := service.MeshService{Name: "vm-svc", Namespace: "ns"}
+	mockKubeController.EXPECT().ListExternalWorkloads().Return(testExternalWorkloads).AnyTimes()
+	mockKubeController.EXPECT().GetService(svc).Return(testExternalWorkloadService).AnyTimes()
+
+	actual := c.getExternalWorkloadEndpointsForService(svc)
+	assert.ElementsMatch([]endpoint.Endpoint{
+		{IP: net.ParseIP("10.0.0.1"), Port: 8080, PortName: "http"},
+		{IP: net.ParseIP("10.0.0.1"), Port: 9090, PortName: "tcp-admin"},
+		{IP: net.ParseIP("10.0.0.2"), Port: 8080, PortName: "http"},
+		{IP: net.ParseIP("10.0.0.2"), Port: 9090, PortName: "tcp-admin"},
+	}, actual)
+
+	portToProtocol := c.getExternalWorkloadTargetPortToProtocolMapping(svc)
+	assert.Equal(map[uint32]string{8080: "http", 9090: "tcp"}, portToProtocol)
+}
+
+func TestGetExternalWorkloadEndpointsForServiceWithoutWorkloads(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	c := &Client{kubeController: mockKubeController}
+
+	// The service is not looked up when there are no external workloads in its namespace
+	mockKubeController.EXPECT().ListExternalWorkloads().Return(testExternalWorkloads).Times(1)
+
+	assert.Empty(c.getExternalWorkloadEndpointsForService(service.MeshService{Name: "svc", Namespace: "no-workloads"}))
+}
+
+func TestGetExternalWorkloadEndpointsForIdentity(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	c := &Client{kubeController: mockKubeController}
+
+	mockKubeController.EXPECT().ListExternalWorkloads().Return(testExternalWorkloads).Times(1)
+
+	actual := c.getExternalWorkloadEndpointsForIdentity(identity.K8sServiceAccount{Name: "vm-sa", Namespace: "ns"})
+	assert.ElementsMatch([]endpoint.Endpoint{
+		{IP: net.ParseIP("10.0.0.1")},
+		{IP: net.ParseIP("10.0.0.2")},
+	}, actual)
+}
+
+func TestGetExternalWorkloadTargetPort(t *testing.T) {
+	externalWorkload := testExternalWorkloads[0]
+
+	testCases := []struct {
+		name         string
+		svcPort      corev1.ServicePort
+		expectedPort policyv1alpha1.ExternalWorkloadPort
+		expectedOk   bool
+	}{
+		{
+			name:         "named target port exposed by the workload",
+			svcPort:      corev1.ServicePort{Port: 80, TargetPort: intstr.FromString("http")},
+			expectedPort: policyv1alpha1.ExternalWorkloadPort{Name: "http", Number: 8080, Protocol: "http"},
+			expectedOk:   true,
+		},
+		{
+			name:       "named target port not exposed by the workload",
+			svcPort:    corev1.ServicePort{Port: 80, TargetPort: intstr.FromString("grpc")},
+			expectedOk: false,
+		},
+		{
+			name:         "numbered target port exposed by the workload",
+			svcPort:      corev1.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)},
+			expectedPort: policyv1alpha1.ExternalWorkloadPort{Name: "http", Number: 8080, Protocol: "http"},
+			expectedOk:   true,
+		},
+		{
+			name:         "numbered target port not declared by the workload",
+			svcPort:      corev1.ServicePort{Port: 80, TargetPort: intstr.FromInt(9090)},
+			expectedPort: policyv1alpha1.ExternalWorkloadPort{Number: 9090},
+			expectedOk:   true,
+		},
+		{
+			name:         "unspecified target port defaults to the service port",
+			svcPort:      corev1.ServicePort{Port: 80},
+			expectedPort: policyv1alpha1.ExternalWorkloadPort{Number: 80},
+			expectedOk:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			port, ok := getExternalWorkloadTargetPort(externalWorkload, tc.svcPort)
+			assert.Equal(tc.expectedOk, ok)
+			assert.Equal(tc.expectedPort, port)
+		})
+	}
+}
@@ -124,6 +124,10 @@ func (f fakeClient) GetPortToProtocolMappingForService(svc service.MeshService)
 	return map[uint32]string{uint32(tests.Endpoint.Port): "http"}, nil
 }
 
+func (f fakeClient) ListServicePorts(svc service.MeshService) ([]service.ServicePort, error) {
+	return []service.ServicePort{{Name: "http", Port: uint32(tests.Endpoint.Port), TargetPort: uint32(tests.Endpoint.Port), Protocol: "http"}}, nil
+}
+
 // GetID returns the unique identifier of the Provider.
 func (f fakeClient) GetID() string {
 	return "Fake Kubernetes Client"
@@ -51,6 +51,18 @@ func (c *Client) getMultiClusterServiceEndpointsForServiceAccount(serviceAccount
 				IP:   ip,
 				Port: endpoint.Port(port),
 			}
+
+			// When the remote cluster reports the health of the service's endpoints, the remote gateway
+			// is weighted by the number of healthy endpoints it fronts. A remote cluster without healthy
+			// endpoints is not routed to.
+			if cluster.Health != nil {
+				if cluster.Health.HealthyEndpoints == 0 {
+					log.Debug().Str(constants.LogFieldContext, constants.LogContextMulticluster).Msgf("Skipping cluster=%s for service %s, no healthy endpoints reported (total=%d)",
+						cluster.Name, svc, cluster.Health.TotalEndpoints)
+					continue
+				}
+				ep.Weight = cluster.Health.HealthyEndpoints
+			}
 			endpoints = append(endpoints, ep)
 		}
 	}
@@ -77,6 +77,34 @@ func TestHelperFunctions(t *testing.T) {
 	actual = client.getMultiClusterServiceEndpointsForServiceAccount(tests.BookbuyerServiceAccountName, tests.Namespace)
 	assert.Equal(actual, expectedEndpoint)
 
+	// Test getMultiClusterServiceEndpointsForServiceAccount()
+	// weights remote endpoints by the health reported by the remote cluster,
+	// and skips remote clusters without healthy endpoints
+	healthReportingServices := []v1alpha1.MultiClusterService{{
+		Spec: v1alpha1.MultiClusterServiceSpec{
+			Clusters: []v1alpha1.ClusterSpec{
+				{
+					Address: "1.2.3.4:5678",
+					Name:    "alpha",
+					Health:  &v1alpha1.ClusterHealth{HealthyEndpoints: 2, TotalEndpoints: 3},
+				},
+				{
+					Address: "5.6.7.8:5678",
+					Name:    "beta",
+					Health:  &v1alpha1.ClusterHealth{HealthyEndpoints: 0, TotalEndpoints: 3},
+				},
+			},
+			ServiceAccount: tests.BookstoreServiceAccountName,
+		},
+	}}
+	mockConfigController.EXPECT().GetMultiClusterServiceByServiceAccount(tests.BookstoreServiceAccountName, tests.Namespace).Return(healthReportingServices).AnyTimes()
+	actual = client.getMultiClusterServiceEndpointsForServiceAccount(tests.BookstoreServiceAccountName, tests.Namespace)
+	assert.Equal([]endpoint.Endpoint{{
+		IP:     net.IPv4(1, 2, 3, 4),
+		Port:   5678,
+		Weight: 2,
+	}}, actual)
+
 	// Test getIPPort()
 	// returns the port number specified in a ClusterSpec
 	clusterSpec := v1alpha1.ClusterSpec{
@@ -17,4 +17,5 @@ type Client struct {
 	kubeController   k8s.Controller
 	configClient     config.Controller
 	meshConfigurator configurator.Configurator
+	dampener         *endpointDampener
 }
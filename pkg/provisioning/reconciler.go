@@ -0,0 +1,178 @@
+package provisioning
+
+import (
+	"context"
+	_ "embed" // required to embed resources
+	"io/fs"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/charts/osm/grafana"
+	"github.com/openservicemesh/osm/pkg/announcements"
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/version"
+)
+
+const (
+	// reconcileInterval is the interval at which the provisioned ConfigMaps are reconciled, so that they are restored
+	// when edited or deleted
+	reconcileInterval = time.Minute
+
+	// dashboardsConfigMapName is the name of the ConfigMap holding the Grafana dashboards, within the OSM namespace
+	dashboardsConfigMapName = "osm-dashboards"
+
+	// alertRulesConfigMapName is the name of the ConfigMap holding the Prometheus alert rules, within the OSM namespace
+	alertRulesConfigMapName = "osm-alert-rules"
+
+	// alertRulesFileName is the key of the Prometheus rule file in the alert rules ConfigMap
+	alertRulesFileName = "osm-alert-rules.yaml"
+
+	// grafanaDashboardLabelKey is the label the Grafana dashboard sidecar loads the dashboards of the ConfigMaps of
+	grafanaDashboardLabelKey = "grafana_dashboard"
+
+	// dashboardDatasourceVariable is the variable the dashboards reference their Grafana datasource with
+	dashboardDatasourceVariable = "${DS_PROMETHEUS}"
+)
+
+// alertRules is the Prometheus rule file alerting on the errors of the control plane, the desync of the proxies and
+// the expiry of the certificates
+//
+//go:embed alert_rules.yaml
+var alertRules string
+
+// NewReconciler returns a Reconciler provisioning the OSM Grafana dashboards and Prometheus alert rules
+func NewReconciler(kubeClient kubernetes.Interface, cfg configurator.Configurator, osmNamespace string) *Reconciler {
+	return &Reconciler{
+		kubeClient:   kubeClient,
+		cfg:          cfg,
+		osmNamespace: osmNamespace,
+	}
+}
+
+// Run reconciles the provisioned ConfigMaps every reconcileInterval and whenever the provisioning config of the
+// MeshConfig changes, until the stop channel is closed
+func (r *Reconciler) Run(stop <-chan struct{}) {
+	provisioningChanged := events.Subscribe(announcements.MeshConfigProvisioningChanged)
+	defer events.Unsub(provisioningChanged)
+
+	ticker := time.NewTicker(reconcileInterval)
+	defer ticker.Stop()
+	for {
+		r.reconcile()
+		select {
+		case <-stop:
+			return
+		case <-provisioningChanged:
+		case <-ticker.C:
+		}
+	}
+}
+
+// reconcile provisions the dashboards and alert rules according to the provisioning config of the MeshConfig
+func (r *Reconciler) reconcile() {
+	provisioning := r.cfg.GetProvisioningConfig()
+
+	dashboards, err := r.getDashboardsConfigMap(provisioning)
+	if err != nil {
+		log.Error().Err(err).Msg("Error loading the Grafana dashboards")
+	} else if err := r.reconcileConfigMap(dashboardsConfigMapName, dashboards); err != nil {
+		log.Error().Err(err).Msgf("Error reconciling the Grafana dashboards in ConfigMap %s/%s", r.osmNamespace, dashboardsConfigMapName)
+	}
+
+	if err := r.reconcileConfigMap(alertRulesConfigMapName, r.getAlertRulesConfigMap(provisioning)); err != nil {
+		log.Error().Err(err).Msgf("Error reconciling the Prometheus alert rules in ConfigMap %s/%s", r.osmNamespace, alertRulesConfigMapName)
+	}
+}
+
+// reconcileConfigMap creates or updates the given ConfigMap, or deletes the ConfigMap with the given name if nil
+func (r *Reconciler) reconcileConfigMap(name string, configMap *corev1.ConfigMap) error {
+	configMaps := r.kubeClient.CoreV1().ConfigMaps(r.osmNamespace)
+	existing, err := configMaps.Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if apierrors.IsNotFound(err) {
+		existing = nil
+	}
+
+	if configMap == nil {
+		// Only the ConfigMaps created by the reconciler are deleted
+		if existing == nil || existing.Labels[constants.OSMAppNameLabelKey] != constants.OSMAppNameLabelValue {
+			return nil
+		}
+		log.Info().Msgf("Deleting ConfigMap %s/%s", r.osmNamespace, name)
+		return configMaps.Delete(context.Background(), name, metav1.DeleteOptions{})
+	}
+
+	if existing == nil {
+		_, err = configMaps.Create(context.Background(), configMap, metav1.CreateOptions{})
+		return err
+	}
+	if reflect.DeepEqual(existing.Labels, configMap.Labels) && reflect.DeepEqual(existing.Data, configMap.Data) {
+		return nil
+	}
+	log.Info().Msgf("Updating ConfigMap %s/%s to version %s", r.osmNamespace, name, version.Version)
+	configMap.ResourceVersion = existing.ResourceVersion
+	_, err = configMaps.Update(context.Background(), configMap, metav1.UpdateOptions{})
+	return err
+}
+
+// getDashboardsConfigMap returns the ConfigMap holding the Grafana dashboards querying the datasource of the
+// provisioning config, or nil if the dashboards are not provisioned
+func (r *Reconciler) getDashboardsConfigMap(provisioning configv1alpha1.ProvisioningSpec) (*corev1.ConfigMap, error) {
+	if !provisioning.EnableDashboards {
+		return nil, nil
+	}
+
+	files, err := fs.Glob(grafana.Dashboards, "dashboards/*.json")
+	if err != nil {
+		return nil, err
+	}
+	data := make(map[string]string, len(files))
+	for _, file := range files {
+		dashboard, err := fs.ReadFile(grafana.Dashboards, file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error reading dashboard %s", file)
+		}
+		data[path.Base(file)] = strings.ReplaceAll(string(dashboard), dashboardDatasourceVariable, provisioning.DashboardDatasource)
+	}
+
+	configMap := r.newConfigMap(dashboardsConfigMapName, data)
+	configMap.Labels[grafanaDashboardLabelKey] = "1"
+	return configMap, nil
+}
+
+// getAlertRulesConfigMap returns the ConfigMap holding the Prometheus alert rules, or nil if the alert rules are not
+// provisioned
+func (r *Reconciler) getAlertRulesConfigMap(provisioning configv1alpha1.ProvisioningSpec) *corev1.ConfigMap {
+	if !provisioning.EnableAlertRules {
+		return nil
+	}
+	return r.newConfigMap(alertRulesConfigMapName, map[string]string{alertRulesFileName: alertRules})
+}
+
+// newConfigMap returns a ConfigMap in the OSM namespace labelled with the version of the controller provisioning it
+func (r *Reconciler) newConfigMap(name string, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: r.osmNamespace,
+			Labels: map[string]string{
+				constants.OSMAppNameLabelKey:    constants.OSMAppNameLabelValue,
+				constants.OSMAppVersionLabelKey: version.Version,
+			},
+		},
+		Data: data,
+	}
+}
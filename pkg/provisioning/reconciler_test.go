@@ -0,0 +1,101 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+const osmNamespace = "osm-system"
+
+func TestReconcile(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	provisioning := configv1alpha1.ProvisioningSpec{
+		EnableDashboards:    true,
+		EnableAlertRules:    true,
+		DashboardDatasource: "Thanos",
+	}
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().GetProvisioningConfig().DoAndReturn(func() configv1alpha1.ProvisioningSpec {
+		return provisioning
+	}).AnyTimes()
+	kubeClient := fake.NewSimpleClientset()
+	r := NewReconciler(kubeClient, mockConfigurator, osmNamespace)
+
+	getConfigMap := func(name string) *corev1.ConfigMap {
+		configMap, err := kubeClient.CoreV1().ConfigMaps(osmNamespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil
+		}
+		return configMap
+	}
+
+	r.reconcile()
+
+	dashboards := getConfigMap(dashboardsConfigMapName)
+	assert.NotNil(dashboards)
+	assert.Equal("1", dashboards.Labels[grafanaDashboardLabelKey])
+	assert.Equal(constants.OSMAppNameLabelValue, dashboards.Labels[constants.OSMAppNameLabelKey])
+	assert.Len(dashboards.Data, 5)
+	assert.Contains(dashboards.Data, "osm-control-plane.json")
+	for _, dashboard := range dashboards.Data {
+		assert.NotContains(dashboard, dashboardDatasourceVariable)
+	}
+	assert.Contains(dashboards.Data["osm-control-plane.json"], `"Thanos"`)
+
+	alertRulesConfigMap := getConfigMap(alertRulesConfigMapName)
+	assert.NotNil(alertRulesConfigMap)
+	var ruleFile struct {
+		Groups []struct {
+			Rules []struct {
+				Alert string `yaml:"alert"`
+			} `yaml:"rules"`
+		} `yaml:"groups"`
+	}
+	assert.Nil(yaml.Unmarshal([]byte(alertRulesConfigMap.Data[alertRulesFileName]), &ruleFile))
+	var alerts []string
+	for _, group := range ruleFile.Groups {
+		for _, rule := range group.Rules {
+			alerts = append(alerts, rule.Alert)
+		}
+	}
+	assert.Subset(alerts, []string{"OSMControllerErrors", "OSMProxyDesync", "OSMCertificateExpiring"})
+
+	// Unchanged ConfigMaps are not updated
+	kubeClient.ClearActions()
+	r.reconcile()
+	for _, action := range kubeClient.Actions() {
+		assert.NotEqual("update", action.GetVerb())
+	}
+
+	// Edited ConfigMaps are restored
+	alertRulesConfigMap.Data[alertRulesFileName] = "groups: []"
+	_, err := kubeClient.CoreV1().ConfigMaps(osmNamespace).Update(context.TODO(), alertRulesConfigMap, metav1.UpdateOptions{})
+	assert.Nil(err)
+	r.reconcile()
+	assert.Equal(alertRules, getConfigMap(alertRulesConfigMapName).Data[alertRulesFileName])
+
+	// The ConfigMaps are deleted when they are no longer provisioned, but ConfigMaps not created by the reconciler are kept
+	provisioning.EnableDashboards = false
+	provisioning.EnableAlertRules = false
+	_, err = kubeClient.CoreV1().ConfigMaps(osmNamespace).Update(context.TODO(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: alertRulesConfigMapName, Namespace: osmNamespace},
+	}, metav1.UpdateOptions{})
+	assert.Nil(err)
+	r.reconcile()
+	assert.Nil(getConfigMap(dashboardsConfigMapName))
+	assert.NotNil(getConfigMap(alertRulesConfigMapName))
+}
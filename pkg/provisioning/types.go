@@ -0,0 +1,23 @@
+// Package provisioning implements the reconciler provisioning the OSM Grafana dashboards and Prometheus alert rules
+// as ConfigMaps in the OSM namespace, so that they are versioned with the OSM controller rather than only shipped in
+// the Helm chart.
+package provisioning
+
+import (
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var (
+	log = logger.New("provisioning")
+)
+
+// Reconciler is the type used to represent the reconciler provisioning the OSM Grafana dashboards and Prometheus alert
+// rules
+type Reconciler struct {
+	kubeClient   kubernetes.Interface
+	cfg          configurator.Configurator
+	osmNamespace string
+}
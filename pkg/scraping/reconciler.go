@@ -0,0 +1,324 @@
+package scraping
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+)
+
+const (
+	// reconcileInterval is the interval at which the scraping resources are reconciled, so that the rotated client
+	// certificate and the namespaces enabled for metrics are picked up
+	reconcileInterval = time.Minute
+
+	// podMonitorName is the name of the PodMonitor of the proxy sidecars, within the OSM namespace
+	podMonitorName = "osm-sidecars"
+
+	// serviceMonitorName is the name of the ServiceMonitor of the control plane, within the OSM namespace
+	serviceMonitorName = "osm-control-plane"
+
+	// controllerMetricsPortName is the name of the port of the osm-controller service serving its metrics
+	controllerMetricsPortName = "healthz"
+
+	// controllerMetricsPath is the path the osm-controller serves its metrics on
+	controllerMetricsPath = "/metrics"
+)
+
+// NewReconciler returns a Reconciler provisioning the resources Prometheus scrapes the metrics of the mesh with
+func NewReconciler(kubeClient kubernetes.Interface, dynamicClient dynamic.Interface, kubeController k8s.Controller,
+	cfg configurator.Configurator, certManager certificate.Manager, osmNamespace string) *Reconciler {
+	return &Reconciler{
+		kubeClient:     kubeClient,
+		dynamicClient:  dynamicClient,
+		kubeController: kubeController,
+		cfg:            cfg,
+		certManager:    certManager,
+		osmNamespace:   osmNamespace,
+	}
+}
+
+// Run reconciles the scraping resources every reconcileInterval and whenever the scraping config of the MeshConfig
+// changes, until the stop channel is closed
+func (r *Reconciler) Run(stop <-chan struct{}) {
+	scrapingChanged := events.Subscribe(announcements.MeshConfigPrometheusScrapingChanged)
+	defer events.Unsub(scrapingChanged)
+
+	ticker := time.NewTicker(reconcileInterval)
+	defer ticker.Stop()
+	for {
+		r.reconcile()
+		select {
+		case <-stop:
+			return
+		case <-scrapingChanged:
+		case <-ticker.C:
+		}
+	}
+}
+
+// reconcile provisions the client certificate and the monitors according to the scraping config of the MeshConfig
+func (r *Reconciler) reconcile() {
+	scraping := r.cfg.GetPrometheusScrapingConfig()
+
+	if err := r.reconcileClientCert(scraping); err != nil {
+		log.Error().Err(err).Msgf("Error reconciling the Prometheus client certificate in secret %s/%s", r.osmNamespace, scraping.ClientCertSecret)
+	}
+
+	if !r.servesMonitors() {
+		if scraping.GenerateMonitors {
+			log.Warn().Msgf("Not generating the Prometheus monitors of the mesh, the %s API is not served", podMonitorGVR.GroupVersion())
+		}
+		return
+	}
+	if err := r.reconcileMonitor(podMonitorGVR, podMonitorName, r.getPodMonitor(scraping)); err != nil {
+		log.Error().Err(err).Msgf("Error reconciling PodMonitor %s/%s", r.osmNamespace, podMonitorName)
+	}
+	if err := r.reconcileMonitor(serviceMonitorGVR, serviceMonitorName, r.getServiceMonitor(scraping)); err != nil {
+		log.Error().Err(err).Msgf("Error reconciling ServiceMonitor %s/%s", r.osmNamespace, serviceMonitorName)
+	}
+}
+
+// reconcileClientCert stores the certificate issued for the client identity of the scraping config in its secret
+// when the metrics of the proxy sidecars are served over mTLS, and deletes the secret otherwise. The secret is only
+// updated when the certificate is rotated.
+func (r *Reconciler) reconcileClientCert(scraping configv1alpha1.PrometheusScrapingSpec) error {
+	secrets := r.kubeClient.CoreV1().Secrets(r.osmNamespace)
+	existing, err := secrets.Get(context.Background(), scraping.ClientCertSecret, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if apierrors.IsNotFound(err) {
+		existing = nil
+	}
+
+	if !scraping.EnableMTLS {
+		// Only the secrets created by the reconciler are deleted
+		if existing == nil || existing.Labels[constants.OSMAppNameLabelKey] != constants.OSMAppNameLabelValue {
+			return nil
+		}
+		log.Info().Msgf("Deleting the Prometheus client certificate in secret %s/%s", r.osmNamespace, scraping.ClientCertSecret)
+		return secrets.Delete(context.Background(), scraping.ClientCertSecret, metav1.DeleteOptions{})
+	}
+
+	cn := certificate.CommonName(fmt.Sprintf("%s.%s", scraping.ClientIdentity, identity.ClusterLocalTrustDomain))
+	cert, err := r.certManager.IssueCertificate(cn, r.cfg.GetServiceCertValidityPeriod())
+	if err != nil {
+		return errors.Wrapf(err, "Error issuing a certificate for Prometheus client identity %s", scraping.ClientIdentity)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      scraping.ClientCertSecret,
+			Namespace: r.osmNamespace,
+			Labels:    map[string]string{constants.OSMAppNameLabelKey: constants.OSMAppNameLabelValue},
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			"ca.crt":  cert.GetIssuingCA(),
+			"tls.crt": cert.GetCertificateChain(),
+			"tls.key": cert.GetPrivateKey(),
+		},
+	}
+
+	if existing == nil {
+		_, err = secrets.Create(context.Background(), secret, metav1.CreateOptions{})
+		return err
+	}
+	if bytes.Equal(existing.Data["tls.crt"], secret.Data["tls.crt"]) && bytes.Equal(existing.Data["ca.crt"], secret.Data["ca.crt"]) {
+		return nil
+	}
+	secret.ResourceVersion = existing.ResourceVersion
+	_, err = secrets.Update(context.Background(), secret, metav1.UpdateOptions{})
+	return err
+}
+
+// servesMonitors returns whether the API server serves the PodMonitor and ServiceMonitor APIs of the Prometheus
+// Operator
+func (r *Reconciler) servesMonitors() bool {
+	resources, err := r.kubeClient.Discovery().ServerResourcesForGroupVersion(podMonitorGVR.GroupVersion().String())
+	if err != nil {
+		// The group version is not found until the Prometheus Operator CRDs are installed
+		log.Debug().Err(err).Msgf("Error discovering the resources of %s", podMonitorGVR.GroupVersion())
+		return false
+	}
+	served := map[string]bool{}
+	for _, resource := range resources.APIResources {
+		served[resource.Name] = true
+	}
+	return served[podMonitorGVR.Resource] && served[serviceMonitorGVR.Resource]
+}
+
+// reconcileMonitor creates or updates the given monitor of the given resource, or deletes the monitor with the given
+// name if nil
+func (r *Reconciler) reconcileMonitor(gvr schema.GroupVersionResource, name string, monitor *unstructured.Unstructured) error {
+	client := r.dynamicClient.Resource(gvr).Namespace(r.osmNamespace)
+	existing, err := client.Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if apierrors.IsNotFound(err) {
+		existing = nil
+	}
+
+	if monitor == nil {
+		// Only the monitors created by the reconciler are deleted
+		if existing == nil || existing.GetLabels()[constants.OSMAppNameLabelKey] != constants.OSMAppNameLabelValue {
+			return nil
+		}
+		log.Info().Msgf("Deleting %s %s/%s", existing.GetKind(), r.osmNamespace, name)
+		return client.Delete(context.Background(), name, metav1.DeleteOptions{})
+	}
+
+	if existing == nil {
+		_, err = client.Create(context.Background(), monitor, metav1.CreateOptions{})
+		return err
+	}
+	if reflect.DeepEqual(existing.Object["spec"], monitor.Object["spec"]) {
+		return nil
+	}
+	monitor.SetResourceVersion(existing.GetResourceVersion())
+	_, err = client.Update(context.Background(), monitor, metav1.UpdateOptions{})
+	return err
+}
+
+// getPodMonitor returns the PodMonitor scraping the proxy sidecars of the namespaces enabled for metrics, or nil if
+// the monitors are not generated or no namespace is enabled for metrics
+func (r *Reconciler) getPodMonitor(scraping configv1alpha1.PrometheusScrapingSpec) *unstructured.Unstructured {
+	if !scraping.GenerateMonitors {
+		return nil
+	}
+	namespaces := r.listMetricsEnabledNamespaces()
+	if len(namespaces) == 0 {
+		return nil
+	}
+
+	endpoint := map[string]interface{}{
+		"port": constants.EnvoyInboundPrometheusListenerPortName,
+		"path": constants.PrometheusScrapePath,
+	}
+	if scraping.ScrapeInterval != "" {
+		endpoint["interval"] = scraping.ScrapeInterval
+	}
+	if scraping.EnableMTLS {
+		endpoint["scheme"] = "https"
+		endpoint["tlsConfig"] = map[string]interface{}{
+			"ca":        secretKeySelector(scraping.ClientCertSecret, "ca.crt"),
+			"cert":      secretKeySelector(scraping.ClientCertSecret, "tls.crt"),
+			"keySecret": map[string]interface{}{"name": scraping.ClientCertSecret, "key": "tls.key"},
+			// The proxy sidecars are scraped by IP address, which is not part of the SANs of their certificates
+			"insecureSkipVerify": true,
+		}
+	}
+
+	matchNames := make([]interface{}, 0, len(namespaces))
+	for _, ns := range namespaces {
+		matchNames = append(matchNames, ns)
+	}
+
+	return r.newMonitor("PodMonitor", podMonitorName, map[string]interface{}{
+		"namespaceSelector": map[string]interface{}{
+			"matchNames": matchNames,
+		},
+		"selector": map[string]interface{}{
+			"matchExpressions": []interface{}{
+				map[string]interface{}{
+					"key":      constants.EnvoyUniqueIDLabelName,
+					"operator": string(metav1.LabelSelectorOpExists),
+				},
+			},
+		},
+		"podMetricsEndpoints": []interface{}{endpoint},
+	})
+}
+
+// getServiceMonitor returns the ServiceMonitor scraping the osm-controller, or nil if the monitors are not generated
+func (r *Reconciler) getServiceMonitor(scraping configv1alpha1.PrometheusScrapingSpec) *unstructured.Unstructured {
+	if !scraping.GenerateMonitors {
+		return nil
+	}
+
+	endpoint := map[string]interface{}{
+		"port": controllerMetricsPortName,
+		"path": controllerMetricsPath,
+	}
+	if scraping.ScrapeInterval != "" {
+		endpoint["interval"] = scraping.ScrapeInterval
+	}
+
+	return r.newMonitor("ServiceMonitor", serviceMonitorName, map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchLabels": map[string]interface{}{
+				"app": constants.OSMControllerName,
+			},
+		},
+		"endpoints": []interface{}{endpoint},
+	})
+}
+
+// newMonitor returns a monitor of the given kind with the given name and spec in the OSM namespace
+func (r *Reconciler) newMonitor(kind, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	monitor := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	monitor.SetAPIVersion(podMonitorGVR.GroupVersion().String())
+	monitor.SetKind(kind)
+	monitor.SetName(name)
+	monitor.SetNamespace(r.osmNamespace)
+	monitor.SetLabels(map[string]string{constants.OSMAppNameLabelKey: constants.OSMAppNameLabelValue})
+	return monitor
+}
+
+// listMetricsEnabledNamespaces returns the sorted monitored namespaces whose pods are injected with a metrics
+// endpoint, as the sidecar injector does
+func (r *Reconciler) listMetricsEnabledNamespaces() []string {
+	monitored, err := r.kubeController.ListMonitoredNamespaces()
+	if err != nil {
+		log.Error().Err(err).Msg("Error listing the namespaces monitored by the mesh")
+		return nil
+	}
+
+	var namespaces []string
+	for _, name := range monitored {
+		ns := r.kubeController.GetNamespace(name)
+		if ns == nil {
+			continue
+		}
+		switch strings.ToLower(ns.Annotations[constants.MetricsAnnotation]) {
+		case "enabled", "yes", "true":
+			namespaces = append(namespaces, name)
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// secretKeySelector returns the selector of the given key of the given secret, as referenced by the TLS config of
+// the monitors
+func secretKeySelector(name, key string) map[string]interface{} {
+	return map[string]interface{}{
+		"secret": map[string]interface{}{
+			"name": name,
+			"key":  key,
+		},
+	}
+}
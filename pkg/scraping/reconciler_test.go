@@ -0,0 +1,183 @@
+package scraping
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s"
+)
+
+const osmNamespace = "osm-system"
+
+func newTestReconciler(mockCtrl *gomock.Controller, scraping *configv1alpha1.PrometheusScrapingSpec, objects ...runtime.Object) (*Reconciler, *fake.Clientset, *dynamicfake.FakeDynamicClient) {
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+	mockConfigurator.EXPECT().GetCertKeyBitSize().Return(2048).AnyTimes()
+	mockConfigurator.EXPECT().GetServiceCertValidityPeriod().Return(time.Hour).AnyTimes()
+	mockConfigurator.EXPECT().GetPrometheusScrapingConfig().DoAndReturn(func() configv1alpha1.PrometheusScrapingSpec {
+		return *scraping
+	}).AnyTimes()
+
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockKubeController.EXPECT().ListMonitoredNamespaces().Return([]string{"ns-2", "ns-1", "ns-3"}, nil).AnyTimes()
+	mockKubeController.EXPECT().GetNamespace("ns-1").Return(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ns-1", Annotations: map[string]string{constants.MetricsAnnotation: "enabled"}},
+	}).AnyTimes()
+	mockKubeController.EXPECT().GetNamespace("ns-2").Return(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ns-2", Annotations: map[string]string{constants.MetricsAnnotation: "true"}},
+	}).AnyTimes()
+	mockKubeController.EXPECT().GetNamespace("ns-3").Return(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ns-3"},
+	}).AnyTimes()
+
+	kubeClient := fake.NewSimpleClientset(objects...)
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		podMonitorGVR:     "PodMonitorList",
+		serviceMonitorGVR: "ServiceMonitorList",
+	})
+
+	return NewReconciler(kubeClient, dynamicClient, mockKubeController, mockConfigurator, tresor.NewFakeCertManager(mockConfigurator), osmNamespace),
+		kubeClient, dynamicClient
+}
+
+func serveMonitors(kubeClient *fake.Clientset) {
+	kubeClient.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{{
+		GroupVersion: "monitoring.coreos.com/v1",
+		APIResources: []metav1.APIResource{{Name: "podmonitors"}, {Name: "servicemonitors"}},
+	}}
+}
+
+func TestReconcileClientCert(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	unmanagedSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "unmanaged", Namespace: osmNamespace}}
+	scraping := &configv1alpha1.PrometheusScrapingSpec{
+		EnableMTLS:       true,
+		ClientIdentity:   "osm-prometheus.osm-system",
+		ClientCertSecret: "prometheus-cert",
+	}
+	r, kubeClient, _ := newTestReconciler(mockCtrl, scraping, unmanagedSecret)
+
+	// The certificate issued for the client identity is stored in the secret
+	assert.Nil(r.reconcileClientCert(*scraping))
+	secret, err := kubeClient.CoreV1().Secrets(osmNamespace).Get(context.TODO(), "prometheus-cert", metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Equal(corev1.SecretTypeTLS, secret.Type)
+	assert.NotEmpty(secret.Data["ca.crt"])
+	assert.NotEmpty(secret.Data["tls.crt"])
+	assert.NotEmpty(secret.Data["tls.key"])
+	cert, err := r.certManager.GetCertificate("osm-prometheus.osm-system.cluster.local")
+	assert.Nil(err)
+	assert.Equal(cert.GetCertificateChain(), secret.Data["tls.crt"])
+
+	// The secret is not updated until the certificate is rotated
+	kubeClient.ClearActions()
+	assert.Nil(r.reconcileClientCert(*scraping))
+	for _, action := range kubeClient.Actions() {
+		assert.NotEqual("update", action.GetVerb())
+	}
+	rotated, err := r.certManager.RotateCertificate("osm-prometheus.osm-system.cluster.local")
+	assert.Nil(err)
+	assert.Nil(r.reconcileClientCert(*scraping))
+	secret, err = kubeClient.CoreV1().Secrets(osmNamespace).Get(context.TODO(), "prometheus-cert", metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Equal(rotated.GetCertificateChain(), secret.Data["tls.crt"])
+
+	// The secret is deleted when mTLS is disabled, but secrets not created by the reconciler are kept
+	scraping.EnableMTLS = false
+	assert.Nil(r.reconcileClientCert(*scraping))
+	_, err = kubeClient.CoreV1().Secrets(osmNamespace).Get(context.TODO(), "prometheus-cert", metav1.GetOptions{})
+	assert.NotNil(err)
+	scraping.ClientCertSecret = "unmanaged"
+	assert.Nil(r.reconcileClientCert(*scraping))
+	_, err = kubeClient.CoreV1().Secrets(osmNamespace).Get(context.TODO(), "unmanaged", metav1.GetOptions{})
+	assert.Nil(err)
+}
+
+func TestReconcileMonitors(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	scraping := &configv1alpha1.PrometheusScrapingSpec{
+		EnableMTLS:       true,
+		ClientIdentity:   "osm-prometheus.osm-system",
+		ClientCertSecret: "prometheus-cert",
+		GenerateMonitors: true,
+		ScrapeInterval:   "30s",
+	}
+	r, kubeClient, dynamicClient := newTestReconciler(mockCtrl, scraping)
+
+	getMonitor := func(gvr schema.GroupVersionResource, name string) *unstructured.Unstructured {
+		monitor, err := dynamicClient.Resource(gvr).Namespace(osmNamespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil
+		}
+		return monitor
+	}
+
+	// No monitor is generated while the Prometheus Operator CRDs don't exist
+	r.reconcile()
+	assert.Nil(getMonitor(podMonitorGVR, podMonitorName))
+	assert.Nil(getMonitor(serviceMonitorGVR, serviceMonitorName))
+
+	serveMonitors(kubeClient)
+	r.reconcile()
+
+	podMonitor := getMonitor(podMonitorGVR, podMonitorName)
+	assert.NotNil(podMonitor)
+	assert.Equal("PodMonitor", podMonitor.GetKind())
+	assert.Equal(constants.OSMAppNameLabelValue, podMonitor.GetLabels()[constants.OSMAppNameLabelKey])
+	matchNames, _, _ := unstructured.NestedStringSlice(podMonitor.Object, "spec", "namespaceSelector", "matchNames")
+	assert.Equal([]string{"ns-1", "ns-2"}, matchNames)
+	endpoints, _, _ := unstructured.NestedSlice(podMonitor.Object, "spec", "podMetricsEndpoints")
+	assert.Len(endpoints, 1)
+	endpoint := endpoints[0].(map[string]interface{})
+	assert.Equal(constants.EnvoyInboundPrometheusListenerPortName, endpoint["port"])
+	assert.Equal("https", endpoint["scheme"])
+	assert.Equal("30s", endpoint["interval"])
+	certSecret, _, _ := unstructured.NestedString(endpoint, "tlsConfig", "cert", "secret", "name")
+	assert.Equal("prometheus-cert", certSecret)
+
+	serviceMonitor := getMonitor(serviceMonitorGVR, serviceMonitorName)
+	assert.NotNil(serviceMonitor)
+	assert.Equal("ServiceMonitor", serviceMonitor.GetKind())
+	app, _, _ := unstructured.NestedString(serviceMonitor.Object, "spec", "selector", "matchLabels", "app")
+	assert.Equal(constants.OSMControllerName, app)
+
+	// Unchanged monitors are not updated
+	dynamicClient.ClearActions()
+	r.reconcile()
+	for _, action := range dynamicClient.Actions() {
+		assert.NotEqual("update", action.GetVerb())
+	}
+
+	// The monitors are updated when the scraping config changes
+	scraping.EnableMTLS = false
+	r.reconcile()
+	endpoints, _, _ = unstructured.NestedSlice(getMonitor(podMonitorGVR, podMonitorName).Object, "spec", "podMetricsEndpoints")
+	assert.NotContains(endpoints[0], "tlsConfig")
+
+	// The monitors are deleted when they are no longer generated
+	scraping.GenerateMonitors = false
+	r.reconcile()
+	assert.Nil(getMonitor(podMonitorGVR, podMonitorName))
+	assert.Nil(getMonitor(serviceMonitorGVR, serviceMonitorName))
+}
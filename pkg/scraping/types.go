@@ -0,0 +1,38 @@
+// Package scraping implements the reconciler provisioning the resources Prometheus scrapes the metrics of the mesh
+// with: the certificate presented by Prometheus to the metrics endpoint of the proxy sidecars when it is served over
+// mTLS, and the PodMonitor and ServiceMonitor resources of the Prometheus Operator.
+package scraping
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+var (
+	log = logger.New("prometheus-scraping")
+)
+
+var (
+	// podMonitorGVR is the resource of the PodMonitors of the Prometheus Operator
+	podMonitorGVR = schema.GroupVersionResource{Group: "monitoring.coreos.com", Version: "v1", Resource: "podmonitors"}
+
+	// serviceMonitorGVR is the resource of the ServiceMonitors of the Prometheus Operator
+	serviceMonitorGVR = schema.GroupVersionResource{Group: "monitoring.coreos.com", Version: "v1", Resource: "servicemonitors"}
+)
+
+// Reconciler is the type used to represent the reconciler provisioning the resources Prometheus scrapes the metrics
+// of the mesh with
+type Reconciler struct {
+	kubeClient     kubernetes.Interface
+	dynamicClient  dynamic.Interface
+	kubeController k8s.Controller
+	cfg            configurator.Configurator
+	certManager    certificate.Manager
+	osmNamespace   string
+}
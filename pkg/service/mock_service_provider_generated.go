@@ -123,6 +123,21 @@ func (mr *MockProviderMockRecorder) ListServiceIdentitiesForService(arg0 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServiceIdentitiesForService", reflect.TypeOf((*MockProvider)(nil).ListServiceIdentitiesForService), arg0)
 }
 
+// ListServicePorts mocks base method
+func (m *MockProvider) ListServicePorts(arg0 MeshService) ([]ServicePort, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListServicePorts", arg0)
+	ret0, _ := ret[0].([]ServicePort)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListServicePorts indicates an expected call of ListServicePorts
+func (mr *MockProviderMockRecorder) ListServicePorts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServicePorts", reflect.TypeOf((*MockProvider)(nil).ListServicePorts), arg0)
+}
+
 // ListServices mocks base method
 func (m *MockProvider) ListServices() ([]MeshService, error) {
 	m.ctrl.T.Helper()
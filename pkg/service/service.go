@@ -3,6 +3,10 @@ package service
 import (
 	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
 )
 
 // Equals checks if two namespaced services are equal
@@ -14,3 +18,42 @@ func (ms MeshService) Equals(service MeshService) bool {
 func (ms MeshService) ServerName() string {
 	return fmt.Sprintf("%s.%s.svc.cluster.local", ms.Name, ms.Namespace)
 }
+
+// PortClusterName returns the name of the cluster backing the given port of the service
+func (ms MeshService) PortClusterName(port uint32) ClusterName {
+	return ClusterName(fmt.Sprintf("%s%s%d", ms, clusterPortSeparator, port))
+}
+
+// GetClusterNameForPort returns the name of the cluster backing the given port of a service exposing the given ports.
+// A service exposing a single port is backed by a single cluster named after the service, while each port of a
+// service exposing multiple ports is backed by its own cluster, so that every port is load balanced to its own
+// target port and routed with its own protocol.
+func GetClusterNameForPort(svc MeshService, ports []ServicePort, port uint32) ClusterName {
+	if len(ports) <= 1 {
+		return ClusterName(svc.String())
+	}
+	return svc.PortClusterName(port)
+}
+
+// ParseClusterName returns the service backed by the given cluster, along with the port of the service backed by the
+// cluster, which is 0 when the cluster backs all the ports of the service.
+func ParseClusterName(clusterName string) (MeshService, uint32, error) {
+	var port uint32
+	if i := strings.LastIndex(clusterName, clusterPortSeparator); i != -1 {
+		p, err := strconv.ParseUint(clusterName[i+len(clusterPortSeparator):], 10, 32)
+		if err != nil {
+			return MeshService{}, 0, errors.Errorf("Invalid cluster name. Expected: <namespace>/<name>|<port>, Got: %s", clusterName)
+		}
+		port = uint32(p)
+		clusterName = clusterName[:i]
+	}
+
+	chunks := strings.Split(clusterName, namespaceNameSeparator)
+	if len(chunks) != 2 {
+		return MeshService{}, 0, errors.Errorf("Invalid cluster name. Expected: <namespace>/<name>, Got: %s", clusterName)
+	}
+	return MeshService{
+		Namespace: chunks[0],
+		Name:      chunks[1],
+	}, port, nil
+}
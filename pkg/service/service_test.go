@@ -92,3 +92,65 @@ func TestString(t *testing.T) {
 		})
 	}
 }
+
+func TestGetClusterNameForPort(t *testing.T) {
+	assert := tassert.New(t)
+
+	svc := MeshService{Namespace: "ns", Name: "svc"}
+
+	// A service exposing a single port is backed by a single cluster
+	assert.Equal(ClusterName("ns/svc"), GetClusterNameForPort(svc, []ServicePort{{Port: 80}}, 80))
+
+	// Each port of a service exposing multiple ports is backed by its own cluster
+	ports := []ServicePort{{Port: 80, Protocol: "http"}, {Port: 5432, Protocol: "tcp"}}
+	assert.Equal(ClusterName("ns/svc|80"), GetClusterNameForPort(svc, ports, 80))
+	assert.Equal(ClusterName("ns/svc|5432"), GetClusterNameForPort(svc, ports, 5432))
+}
+
+func TestParseClusterName(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cluster      string
+		expectedSvc  MeshService
+		expectedPort uint32
+		expectError  bool
+	}{
+		{
+			name:        "service cluster",
+			cluster:     "ns/svc",
+			expectedSvc: MeshService{Namespace: "ns", Name: "svc"},
+		},
+		{
+			name:         "service port cluster",
+			cluster:      "ns/svc|8080",
+			expectedSvc:  MeshService{Namespace: "ns", Name: "svc"},
+			expectedPort: 8080,
+		},
+		{
+			name:        "invalid port",
+			cluster:     "ns/svc|http",
+			expectError: true,
+		},
+		{
+			name:        "invalid service",
+			cluster:     "ns/svc/local",
+			expectError: true,
+		},
+		{
+			name:        "missing namespace",
+			cluster:     "svc",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			svc, port, err := ParseClusterName(tc.cluster)
+			assert.Equal(tc.expectError, err != nil)
+			assert.Equal(tc.expectedSvc, svc)
+			assert.Equal(tc.expectedPort, port)
+		})
+	}
+}
@@ -11,6 +11,10 @@ const (
 	// namespaceNameSeparator used upon marshalling/unmarshalling MeshService to a string
 	// or viceversa
 	namespaceNameSeparator = "/"
+
+	// clusterPortSeparator separates the service from the port in the name of a cluster backing a single port of a
+	// service
+	clusterPortSeparator = "|"
 )
 
 // Locality is the relative locality of a service. ie: if a service is being accessed from the same namespace or a
@@ -51,6 +55,26 @@ func (ms MeshService) FQDN() string {
 	return fmt.Sprintf("%s.%s.svc.cluster.local", ms.Name, ms.Namespace)
 }
 
+// ServicePort is a port exposed by a service
+type ServicePort struct {
+	// Name is the name of the port, unique within the service
+	Name string
+
+	// Port is the port used by downstream clients in their requests, ie. 'spec.ports[].port' for a Kubernetes service
+	Port uint32
+
+	// TargetPort is the port on which the application serves the port, ie. 'spec.ports[].targetPort' for a Kubernetes service.
+	// When the target port is referenced by name, it is the port the name resolves to on one of the service's instances.
+	TargetPort uint32
+
+	// TargetPortName is the name of the target port when the service references it by name. The name is resolved per
+	// instance of the service, different instances possibly serving the port on different ports.
+	TargetPortName string
+
+	// Protocol is the application protocol of the port
+	Protocol string
+}
+
 // ClusterName is a type for a service name
 type ClusterName string
 
@@ -86,6 +110,9 @@ type Provider interface {
 	// ie. 'spec.ports[].targetPort' instead of 'spec.ports[].port' for a Kubernetes service.
 	GetTargetPortToProtocolMappingForService(MeshService) (map[uint32]string, error)
 
+	// ListServicePorts returns the ports exposed by the service, along with their target ports and application protocols.
+	ListServicePorts(MeshService) ([]ServicePort, error)
+
 	// GetHostnamesForService returns a list of hostnames over which the service can be accessed within the local cluster.
 	GetHostnamesForService(MeshService, Locality) ([]string, error)
 
@@ -8,7 +8,7 @@ import (
 	"github.com/pkg/errors"
 	smiAccess "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
 	smiSpecs "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
-	smiSplit "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha2"
+	smiSplit "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha4"
 	smiAccessClient "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/access/clientset/versioned"
 	smiAccessInformers "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/access/informers/externalversions"
 	smiTrafficSpecClient "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/specs/clientset/versioned"
@@ -48,6 +48,12 @@ func NewMeshSpecClient(smiKubeConfig *rest.Config, kubeClient kubernetes.Interfa
 	return client, err
 }
 
+// NewMeshSpecClientFromClientsets implements mesh.MeshSpec with the given SMI clientsets. It is used to replay SMI
+// policies held outside of a cluster, such as the ones captured in a support bundle.
+func NewMeshSpecClientFromClientsets(kubeClient kubernetes.Interface, splitClient smiTrafficSplitClient.Interface, specClient smiTrafficSpecClient.Interface, accessClient smiAccessClient.Interface, osmNamespace string, kubeController k8s.Controller, stop chan struct{}) (MeshSpec, error) {
+	return newSMIClient(kubeClient, splitClient, specClient, accessClient, osmNamespace, kubeController, kubernetesClientName, stop)
+}
+
 func (c *client) run(stop <-chan struct{}) error {
 	log.Info().Msg("SMI client started")
 	var hasSynced []cache.InformerSynced
@@ -91,7 +97,7 @@ func newSMIClient(kubeClient kubernetes.Interface, smiTrafficSplitClient smiTraf
 	smiTrafficTargetInformerFactory := smiAccessInformers.NewSharedInformerFactory(smiAccessClient, k8s.DefaultKubeEventResyncInterval)
 
 	informerCollection := informerCollection{
-		TrafficSplit:   smiTrafficSplitInformerFactory.Split().V1alpha2().TrafficSplits().Informer(),
+		TrafficSplit:   smiTrafficSplitInformerFactory.Split().V1alpha4().TrafficSplits().Informer(),
 		HTTPRouteGroup: smiTrafficSpecInformerFactory.Specs().V1alpha4().HTTPRouteGroups().Informer(),
 		TCPRoute:       smiTrafficSpecInformerFactory.Specs().V1alpha4().TCPRoutes().Informer(),
 		TrafficTarget:  smiTrafficTargetInformerFactory.Access().V1alpha3().TrafficTargets().Informer(),
@@ -155,6 +161,16 @@ func newSMIClient(kubeClient kubernetes.Interface, smiTrafficSplitClient smiTraf
 	return &client, err
 }
 
+// HasSynced implements mesh.MeshSpec by returning whether the caches of all the SMI informers have synced.
+func (c *client) HasSynced() bool {
+	for _, informer := range []cache.SharedIndexInformer{c.informers.TrafficSplit, c.informers.HTTPRouteGroup, c.informers.TCPRoute, c.informers.TrafficTarget} {
+		if informer != nil && !informer.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
 // ListTrafficSplits implements mesh.MeshSpec by returning the list of traffic splits.
 func (c *client) ListTrafficSplits() []*smiSplit.TrafficSplit {
 	var trafficSplits []*smiSplit.TrafficSplit
@@ -11,7 +11,7 @@ import (
 	. "github.com/onsi/gomega"
 	smiAccess "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
 	smiSpecs "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
-	smiSplit "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha2"
+	smiSplit "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha4"
 	testTrafficTargetClient "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/access/clientset/versioned/fake"
 	testTrafficSpecClient "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/specs/clientset/versioned/fake"
 	testTrafficSplitClient "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/split/clientset/versioned/fake"
@@ -121,7 +121,7 @@ var _ = Describe("When listing TrafficSplit", func() {
 			},
 		}
 
-		_, err := fakeClientSet.smiTrafficSplitClientSet.SplitV1alpha2().TrafficSplits(testNamespaceName).Create(context.TODO(), split, metav1.CreateOptions{})
+		_, err := fakeClientSet.smiTrafficSplitClientSet.SplitV1alpha4().TrafficSplits(testNamespaceName).Create(context.TODO(), split, metav1.CreateOptions{})
 		Expect(err).ToNot(HaveOccurred())
 		<-tsChannel
 
@@ -129,7 +129,7 @@ var _ = Describe("When listing TrafficSplit", func() {
 		Expect(len(splits)).To(Equal(1))
 		Expect(split).To(Equal(splits[0]))
 
-		err = fakeClientSet.smiTrafficSplitClientSet.SplitV1alpha2().TrafficSplits(testNamespaceName).Delete(context.TODO(), split.Name, metav1.DeleteOptions{})
+		err = fakeClientSet.smiTrafficSplitClientSet.SplitV1alpha4().TrafficSplits(testNamespaceName).Delete(context.TODO(), split.Name, metav1.DeleteOptions{})
 		Expect(err).ToNot(HaveOccurred())
 		<-tsChannel
 	})
@@ -4,7 +4,7 @@ import (
 	access "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
 	smiSpecs "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
 	spec "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
-	split "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha2"
+	split "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha4"
 
 	"github.com/openservicemesh/osm/pkg/identity"
 	"github.com/openservicemesh/osm/pkg/tests"
@@ -67,3 +67,8 @@ func (f fakeMeshSpec) GetTCPRoute(_ string) *spec.TCPRoute {
 func (f fakeMeshSpec) ListTrafficTargets() []*access.TrafficTarget {
 	return f.trafficTargets
 }
+
+// HasSynced returns true as the fake MeshSpec has no caches to sync
+func (f fakeMeshSpec) HasSynced() bool {
+	return true
+}
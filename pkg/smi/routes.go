@@ -0,0 +1,27 @@
+package smi
+
+import (
+	"regexp"
+
+	"github.com/pkg/errors"
+	smiSpecs "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
+)
+
+// ValidateHTTPRouteGroupMatches returns an error if the regular expressions of the given HTTPRouteGroup matches, their
+// path regular expressions and header values, are not valid RE2 regular expressions. The proxies would reject the
+// route configurations with such regular expressions.
+func ValidateHTTPRouteGroupMatches(matches []smiSpecs.HTTPMatch) error {
+	for _, match := range matches {
+		if match.PathRegex != "" {
+			if _, err := regexp.Compile(match.PathRegex); err != nil {
+				return errors.Wrapf(err, "Match %s has an invalid pathRegex %s", match.Name, match.PathRegex)
+			}
+		}
+		for header, value := range match.Headers {
+			if _, err := regexp.Compile(value); err != nil {
+				return errors.Wrapf(err, "Match %s has an invalid regex %s for header %s", match.Name, value, header)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package smi
+
+import (
+	"testing"
+
+	smiSpecs "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestValidateHTTPRouteGroupMatches(t *testing.T) {
+	testCases := []struct {
+		name        string
+		matches     []smiSpecs.HTTPMatch
+		expectedErr bool
+	}{
+		{
+			name:        "no matches",
+			matches:     nil,
+			expectedErr: false,
+		},
+		{
+			name: "valid regexes",
+			matches: []smiSpecs.HTTPMatch{
+				{Name: "books", PathRegex: "/books/[0-9]+", Headers: map[string]string{"user-agent": ".*Mozilla.*"}},
+				{Name: "all", Methods: []string{"GET"}},
+			},
+			expectedErr: false,
+		},
+		{
+			name: "invalid path regex",
+			matches: []smiSpecs.HTTPMatch{
+				{Name: "books", PathRegex: "/books/[0-9+"},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "path regex with syntax not supported by RE2",
+			matches: []smiSpecs.HTTPMatch{
+				{Name: "books", PathRegex: "/books/(?!new)"},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "invalid header regex",
+			matches: []smiSpecs.HTTPMatch{
+				{Name: "books", PathRegex: "/books", Headers: map[string]string{"host": "*.example.com"}},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			err := ValidateHTTPRouteGroupMatches(tc.matches)
+			assert.Equal(tc.expectedErr, err != nil)
+		})
+	}
+}
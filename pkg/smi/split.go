@@ -0,0 +1,169 @@
+package smi
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	smiSplit "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha4"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+)
+
+// TrafficSplitWeightTotal is the total the weights of the backends of a TrafficSplit are normalized to,
+// which is the total weight Envoy expects by default for weighted clusters
+const TrafficSplitWeightTotal = 100
+
+// ValidateTrafficSplitBackends returns an error if the weights of the given TrafficSplit backends are not consistent,
+// i.e. if a backend is listed more than once, a weight is negative, or all the weights are 0.
+func ValidateTrafficSplitBackends(backends []smiSplit.TrafficSplitBackend) error {
+	if len(backends) == 0 {
+		return errors.New("TrafficSplit must specify at least one backend")
+	}
+
+	total := 0
+	seen := make(map[string]struct{})
+	for _, backend := range backends {
+		if _, ok := seen[backend.Service]; ok {
+			return errors.Errorf("Backend %s is specified more than once", backend.Service)
+		}
+		seen[backend.Service] = struct{}{}
+
+		if backend.Weight < 0 {
+			return errors.Errorf("Backend %s has a negative weight %d", backend.Service, backend.Weight)
+		}
+		total += backend.Weight
+	}
+
+	if total == 0 {
+		return errors.New("TrafficSplit must specify at least one backend with a weight greater than 0")
+	}
+	return nil
+}
+
+// NormalizeTrafficSplitWeights returns the weights of the given TrafficSplit backends normalized to
+// TrafficSplitWeightTotal, in the order of the backends. Backends with a weight of 0 keep a weight of 0, and
+// backends with a weight greater than 0 keep a weight of at least 1. Weights are rounded using the largest
+// remainder method so that they sum up to TrafficSplitWeightTotal, unless there are more backends with a weight
+// greater than 0 than TrafficSplitWeightTotal. The backends are expected to be valid.
+func NormalizeTrafficSplitWeights(backends []smiSplit.TrafficSplitBackend) []int {
+	normalized := make([]int, len(backends))
+
+	total := 0
+	for _, backend := range backends {
+		if backend.Weight > 0 {
+			total += backend.Weight
+		}
+	}
+	if total == 0 {
+		return normalized
+	}
+
+	remainders := make([]int, len(backends))
+	var weighted []int // indices of the backends with a weight greater than 0
+	sum := 0
+	for i, backend := range backends {
+		if backend.Weight <= 0 {
+			continue
+		}
+		weighted = append(weighted, i)
+		normalized[i] = backend.Weight * TrafficSplitWeightTotal / total
+		remainders[i] = backend.Weight * TrafficSplitWeightTotal % total
+		if normalized[i] == 0 {
+			// Keep backends with a weight greater than 0 in the split
+			normalized[i] = 1
+			remainders[i] = 0
+		}
+		sum += normalized[i]
+	}
+
+	// Hand out the weight lost to rounding down to the backends with the largest remainders
+	sort.SliceStable(weighted, func(a, b int) bool {
+		return remainders[weighted[a]] > remainders[weighted[b]]
+	})
+	for i := 0; sum < TrafficSplitWeightTotal; i = (i + 1) % len(weighted) {
+		normalized[weighted[i]]++
+		sum++
+	}
+
+	// Take back the weight given to backends rounded up to 1 from the backends with the largest weights
+	for sum > TrafficSplitWeightTotal {
+		largest := weighted[0]
+		for _, i := range weighted {
+			if normalized[i] > normalized[largest] {
+				largest = i
+			}
+		}
+		if normalized[largest] <= 1 {
+			break
+		}
+		normalized[largest]--
+		sum--
+	}
+
+	return normalized
+}
+
+// getTrafficSplitStatus returns the effective weights of the backends of the given TrafficSplit, formatted as a
+// comma-separated list of backend=weight pairs, or an error if the weights are invalid and the TrafficSplit is ignored
+func getTrafficSplitStatus(split *smiSplit.TrafficSplit) (string, error) {
+	if err := ValidateTrafficSplitBackends(split.Spec.Backends); err != nil {
+		return "", err
+	}
+
+	normalized := NormalizeTrafficSplitWeights(split.Spec.Backends)
+	effectiveWeights := make([]string, 0, len(normalized))
+	for i, backend := range split.Spec.Backends {
+		effectiveWeights = append(effectiveWeights, fmt.Sprintf("%s=%d", backend.Service, normalized[i]))
+	}
+	return strings.Join(effectiveWeights, ","), nil
+}
+
+// TrafficSplitStatusHandler records a Kubernetes event against each TrafficSplit reporting either the effective
+// weights of its backends or why it is ignored, based on the TrafficSplitAdded and TrafficSplitUpdated events.
+// The status is reported through events rather than written to the TrafficSplit, which has no status subresource.
+// Returns a stop channel which can be used to stop the inner handler.
+func TrafficSplitStatusHandler(recorder *events.ObjectEventRecorder) chan struct{} {
+	splitSubscription := events.Subscribe(announcements.TrafficSplitAdded, announcements.TrafficSplitUpdated)
+	stop := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			case splitMsg := <-splitSubscription:
+				psubMessage, castOk := splitMsg.(events.PubSubMessage)
+				if !castOk {
+					log.Error().Msgf("Error casting PubSubMessage: %T %v", psubMessage, psubMessage)
+					continue
+				}
+
+				split, castOk := psubMessage.NewObj.(*smiSplit.TrafficSplit)
+				if !castOk {
+					log.Error().Msgf("Failed to cast to *TrafficSplit: %T %v", psubMessage.NewObj, psubMessage.NewObj)
+					continue
+				}
+
+				// Skip updates that don't change the backends, such as periodic resyncs
+				if oldSplit, ok := psubMessage.OldObj.(*smiSplit.TrafficSplit); ok && reflect.DeepEqual(oldSplit.Spec.Backends, split.Spec.Backends) {
+					continue
+				}
+
+				effectiveWeights, err := getTrafficSplitStatus(split)
+				if err != nil {
+					recorder.WarnEvent(split, events.InvalidTrafficSplit,
+						"TrafficSplit %s/%s is ignored: %s", split.Namespace, split.Name, err)
+					continue
+				}
+				recorder.NormalEvent(split, events.TrafficSplitWeightsNormalized,
+					"TrafficSplit %s/%s effective weights: %s", split.Namespace, split.Name, effectiveWeights)
+			}
+		}
+	}()
+
+	return stop
+}
@@ -0,0 +1,136 @@
+package smi
+
+import (
+	"testing"
+
+	smiSplit "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha4"
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestValidateTrafficSplitBackends(t *testing.T) {
+	testCases := []struct {
+		name        string
+		backends    []smiSplit.TrafficSplitBackend
+		expectedErr bool
+	}{
+		{
+			name:        "no backends",
+			backends:    nil,
+			expectedErr: true,
+		},
+		{
+			name: "duplicate backend",
+			backends: []smiSplit.TrafficSplitBackend{
+				{Service: "s1", Weight: 50},
+				{Service: "s1", Weight: 50},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "negative weight",
+			backends: []smiSplit.TrafficSplitBackend{
+				{Service: "s1", Weight: -1},
+				{Service: "s2", Weight: 50},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "all weights are 0",
+			backends: []smiSplit.TrafficSplitBackend{
+				{Service: "s1", Weight: 0},
+				{Service: "s2", Weight: 0},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "valid weights",
+			backends: []smiSplit.TrafficSplitBackend{
+				{Service: "s1", Weight: 0},
+				{Service: "s2", Weight: 3},
+			},
+			expectedErr: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			err := ValidateTrafficSplitBackends(tc.backends)
+			assert.Equal(tc.expectedErr, err != nil)
+		})
+	}
+}
+
+func TestNormalizeTrafficSplitWeights(t *testing.T) {
+	testCases := []struct {
+		name     string
+		weights  []int
+		expected []int
+	}{
+		{
+			name:     "weights already sum up to the total",
+			weights:  []int{90, 10},
+			expected: []int{90, 10},
+		},
+		{
+			name:     "weights are scaled up",
+			weights:  []int{1, 3},
+			expected: []int{25, 75},
+		},
+		{
+			name:     "weights are scaled down",
+			weights:  []int{500, 1500},
+			expected: []int{25, 75},
+		},
+		{
+			name:     "rounding remainders are handed out",
+			weights:  []int{1, 1, 1},
+			expected: []int{34, 33, 33},
+		},
+		{
+			name:     "weights of 0 are preserved",
+			weights:  []int{0, 2},
+			expected: []int{0, 100},
+		},
+		{
+			name:     "small weights are kept in the split",
+			weights:  []int{1, 1000},
+			expected: []int{1, 99},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			var backends []smiSplit.TrafficSplitBackend
+			for i, weight := range tc.weights {
+				backends = append(backends, smiSplit.TrafficSplitBackend{Service: string(rune('a' + i)), Weight: weight})
+			}
+			assert.Equal(tc.expected, NormalizeTrafficSplitWeights(backends))
+		})
+	}
+}
+
+func TestGetTrafficSplitStatus(t *testing.T) {
+	assert := tassert.New(t)
+
+	split := &smiSplit.TrafficSplit{
+		Spec: smiSplit.TrafficSplitSpec{
+			Service: "apex",
+			Backends: []smiSplit.TrafficSplitBackend{
+				{Service: "v1", Weight: 1},
+				{Service: "v2", Weight: 3},
+			},
+		},
+	}
+	effectiveWeights, err := getTrafficSplitStatus(split)
+	assert.Nil(err)
+	assert.Equal("v1=25,v2=75", effectiveWeights)
+
+	split.Spec.Backends = []smiSplit.TrafficSplitBackend{{Service: "v1", Weight: 0}}
+	effectiveWeights, err = getTrafficSplitStatus(split)
+	assert.NotNil(err)
+	assert.Empty(effectiveWeights)
+}
@@ -0,0 +1,202 @@
+package supportbundle
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/identity"
+)
+
+// Analysis is the outcome of the replay of a support bundle through the mesh catalog.
+type Analysis struct {
+	// Warnings describe the parts of the bundle which could not be replayed faithfully
+	Warnings []string `json:"warnings,omitempty"`
+
+	// Identities are the service identities of the bundle, with the traffic the replay allows from and to them
+	Identities []IdentityAnalysis `json:"identities"`
+
+	// Proxies are the proxies of the bundle, with the differences between their captured and replayed xDS configuration
+	Proxies []ProxyAnalysis `json:"proxies"`
+}
+
+// IdentityAnalysis is the traffic the replay allows from and to a service identity.
+type IdentityAnalysis struct {
+	// ServiceIdentity is the service identity
+	ServiceIdentity string `json:"serviceIdentity"`
+
+	// OutboundServices are the services the service identity is allowed to connect to
+	OutboundServices []string `json:"outboundServices"`
+
+	// InboundIdentities are the service identities allowed to connect to the service identity
+	InboundIdentities []string `json:"inboundIdentities"`
+}
+
+// ProxyAnalysis compares the xDS configuration of a proxy captured in a bundle with the replayed one.
+type ProxyAnalysis struct {
+	// CommonName is the CN of the certificate of the proxy
+	CommonName certificate.CommonName `json:"commonName"`
+
+	// Diffs are the differences per xDS type, only the types which differ are listed
+	Diffs []XDSDiff `json:"diffs,omitempty"`
+}
+
+// XDSDiff lists the resources of an xDS type which differ between the captured and replayed configuration of a proxy.
+type XDSDiff struct {
+	// TypeURI is the short name of the xDS type
+	TypeURI string `json:"typeURI"`
+
+	// OnlyInBundle are the names of the resources captured in the bundle, but not generated by the replay
+	OnlyInBundle []string `json:"onlyInBundle,omitempty"`
+
+	// OnlyInReplay are the names of the resources generated by the replay, but not captured in the bundle
+	OnlyInReplay []string `json:"onlyInReplay,omitempty"`
+
+	// Changed are the names of the resources whose content differs between the bundle and the replay
+	Changed []string `json:"changed,omitempty"`
+}
+
+// HasDrift returns whether the replayed configuration of any proxy differs from the one captured in the bundle
+func (a *Analysis) HasDrift() bool {
+	for _, proxy := range a.Proxies {
+		if len(proxy.Diffs) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Analyze replays a support bundle through a mesh catalog running offline on the state captured in the bundle.
+// It reports the traffic allowed from and to each service identity, and the differences between the xDS
+// configuration captured for each proxy and the one the replay generates for it. A difference indicates the
+// controller's view of the mesh diverged from the state it captured, or state the bundle does not hold, such as
+// Ingress resources and certificates, contributes to the configuration.
+func Analyze(bundle *Bundle) (*Analysis, error) {
+	stop := make(chan struct{})
+	defer close(stop)
+
+	r, err := newReplay(bundle, stop)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := &Analysis{
+		Warnings:   r.warnings,
+		Identities: []IdentityAnalysis{},
+	}
+
+	for _, serviceAccount := range bundle.Kubernetes.ServiceAccounts {
+		analysis.Identities = append(analysis.Identities, r.analyzeIdentity(identity.K8sServiceAccount{
+			Name:      serviceAccount.Name,
+			Namespace: serviceAccount.Namespace,
+		}.ToServiceIdentity()))
+	}
+	sort.Slice(analysis.Identities, func(i, j int) bool {
+		return analysis.Identities[i].ServiceIdentity < analysis.Identities[j].ServiceIdentity
+	})
+
+	if analysis.Proxies, err = r.analyzeProxies(bundle.ProxyConfigs); err != nil {
+		return nil, err
+	}
+
+	return analysis, nil
+}
+
+func (r *replay) analyzeIdentity(svcIdentity identity.ServiceIdentity) IdentityAnalysis {
+	identityAnalysis := IdentityAnalysis{
+		ServiceIdentity:   svcIdentity.String(),
+		OutboundServices:  []string{},
+		InboundIdentities: []string{},
+	}
+
+	for _, svc := range r.meshCatalog.ListOutboundServicesForIdentity(svcIdentity) {
+		identityAnalysis.OutboundServices = append(identityAnalysis.OutboundServices, svc.String())
+	}
+	sort.Strings(identityAnalysis.OutboundServices)
+
+	inboundIdentities, err := r.meshCatalog.ListInboundServiceIdentities(svcIdentity)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error listing inbound service identities of %s", svcIdentity)
+	}
+	for _, inboundIdentity := range inboundIdentities {
+		identityAnalysis.InboundIdentities = append(identityAnalysis.InboundIdentities, inboundIdentity.String())
+	}
+	sort.Strings(identityAnalysis.InboundIdentities)
+
+	return identityAnalysis
+}
+
+func (r *replay) analyzeProxy(cn certificate.CommonName, captured *ProxyConfig) (*ProxyAnalysis, error) {
+	proxy, err := envoy.NewProxy(cn, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	r.proxyRegistry.RegisterProxy(proxy)
+	defer r.proxyRegistry.UnregisterProxy(proxy)
+
+	replayed := generateProxyConfig(r.meshCatalog, proxy, captured.ResourceNames, r.cfg, nil, r.proxyRegistry)
+
+	proxyAnalysis := &ProxyAnalysis{CommonName: cn}
+	for _, generator := range xdsGenerators {
+		diff := diffResources(captured.Resources[generator.typeURI], replayed.Resources[generator.typeURI])
+		if len(diff.OnlyInBundle) > 0 || len(diff.OnlyInReplay) > 0 || len(diff.Changed) > 0 {
+			diff.TypeURI = generator.typeURI.Short()
+			proxyAnalysis.Diffs = append(proxyAnalysis.Diffs, diff)
+		}
+	}
+
+	return proxyAnalysis, nil
+}
+
+func diffResources(captured, replayed []types.Resource) XDSDiff {
+	var diff XDSDiff
+
+	replayedByName := make(map[string]types.Resource)
+	for _, resource := range replayed {
+		replayedByName[cache.GetResourceName(resource)] = resource
+	}
+
+	for _, resource := range captured {
+		name := cache.GetResourceName(resource)
+		replayedResource, ok := replayedByName[name]
+		if !ok {
+			diff.OnlyInBundle = append(diff.OnlyInBundle, name)
+			continue
+		}
+		if !resourcesEqual(resource, replayedResource) {
+			diff.Changed = append(diff.Changed, name)
+		}
+		delete(replayedByName, name)
+	}
+
+	for name := range replayedByName {
+		diff.OnlyInReplay = append(diff.OnlyInReplay, name)
+	}
+
+	sort.Strings(diff.OnlyInBundle)
+	sort.Strings(diff.OnlyInReplay)
+	sort.Strings(diff.Changed)
+
+	return diff
+}
+
+// resourcesEqual returns whether two xDS resources are equal. The resources are compared in their JSON representation:
+// the serialization of their embedded google.protobuf.Any messages is not deterministic, so that proto.Equal may
+// report equal resources as different.
+func resourcesEqual(a, b types.Resource) bool {
+	jsonA, err := protojson.Marshal(proto.MessageV2(a))
+	if err != nil {
+		return false
+	}
+	jsonB, err := protojson.Marshal(proto.MessageV2(b))
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(jsonA, jsonB)
+}
@@ -0,0 +1,146 @@
+package supportbundle
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/uuid"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+func newTestBundle() (*Bundle, certificate.CommonName) {
+	proxyUUID := uuid.New()
+	cn := envoy.NewXDSCertCommonName(proxyUUID, envoy.KindSidecar, "bookbuyer", "bookbuyer")
+
+	namespace := func(name string) corev1.Namespace {
+		return corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{constants.OSMKubeResourceMonitorAnnotation: "osm"},
+			},
+		}
+	}
+	serviceAccount := func(name string) corev1.ServiceAccount {
+		return corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: name, Name: name}}
+	}
+
+	bundle := &Bundle{
+		Metadata: Metadata{OSMNamespace: "osm-system", MeshName: "osm"},
+		MeshConfig: &configv1alpha1.MeshConfig{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "osm-system", Name: "osm-mesh-config"},
+			Spec: configv1alpha1.MeshConfigSpec{
+				Traffic: configv1alpha1.TrafficSpec{EnablePermissiveTrafficPolicyMode: true},
+			},
+		},
+		Kubernetes: KubernetesObjects{
+			Namespaces:      []corev1.Namespace{namespace("bookbuyer"), namespace("bookstore")},
+			ServiceAccounts: []corev1.ServiceAccount{serviceAccount("bookbuyer"), serviceAccount("bookstore")},
+			Services: []corev1.Service{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "bookstore", Name: "bookstore"},
+				Spec: corev1.ServiceSpec{
+					Ports:    []corev1.ServicePort{{Name: "http", Port: 80}},
+					Selector: map[string]string{"app": "bookstore"},
+				},
+			}},
+			Pods: []corev1.Pod{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "bookbuyer",
+						Name:      "bookbuyer",
+						Labels:    map[string]string{constants.EnvoyUniqueIDLabelName: proxyUUID.String(), "app": "bookbuyer"},
+					},
+					Spec: corev1.PodSpec{ServiceAccountName: "bookbuyer"},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "bookstore",
+						Name:      "bookstore",
+						Labels:    map[string]string{constants.EnvoyUniqueIDLabelName: uuid.New().String(), "app": "bookstore"},
+					},
+					Spec: corev1.PodSpec{ServiceAccountName: "bookstore"},
+				},
+			},
+			Endpoints: []corev1.Endpoints{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "bookstore", Name: "bookstore"},
+				Subsets: []corev1.EndpointSubset{{
+					Addresses: []corev1.EndpointAddress{{
+						IP:        "10.0.0.1",
+						TargetRef: &corev1.ObjectReference{Kind: "Pod", Namespace: "bookstore", Name: "bookstore"},
+					}},
+					Ports: []corev1.EndpointPort{{Name: "http", Port: 80}},
+				}},
+			}},
+		},
+		ProxyConfigs: make(map[certificate.CommonName]*ProxyConfig),
+	}
+
+	return bundle, cn
+}
+
+func TestAnalyze(t *testing.T) {
+	assert := tassert.New(t)
+
+	bundle, cn := newTestBundle()
+
+	// Capture the configuration the replay generates for the proxy, through an archive
+	stop := make(chan struct{})
+	defer close(stop)
+	r, err := newReplay(bundle, stop)
+	assert.Nil(err)
+	proxy, err := envoy.NewProxy(cn, "", nil)
+	assert.Nil(err)
+	captured := generateProxyConfig(r.meshCatalog, proxy, nil, r.cfg, nil, r.proxyRegistry)
+	assert.NotEmpty(captured.Resources[envoy.TypeCDS])
+	bundle.ProxyConfigs[cn] = captured
+
+	var archive bytes.Buffer
+	assert.Nil(bundle.Write(&archive))
+	loaded, err := Load(&archive)
+	assert.Nil(err)
+
+	analysis, err := Analyze(loaded)
+	assert.Nil(err)
+	assert.Empty(analysis.Warnings)
+	assert.Equal([]IdentityAnalysis{
+		{
+			ServiceIdentity:   "bookbuyer.bookbuyer.cluster.local",
+			OutboundServices:  []string{"bookstore/bookstore"},
+			InboundIdentities: []string{},
+		},
+		{
+			ServiceIdentity:   "bookstore.bookstore.cluster.local",
+			OutboundServices:  []string{"bookstore/bookstore"},
+			InboundIdentities: []string{},
+		},
+	}, analysis.Identities)
+	assert.Len(analysis.Proxies, 1)
+	assert.Equal(cn, analysis.Proxies[0].CommonName)
+	assert.False(analysis.HasDrift())
+
+	// A cluster the controller no longer generates is reported as a drift
+	loaded.ProxyConfigs[cn].Resources[envoy.TypeCDS] = loaded.ProxyConfigs[cn].Resources[envoy.TypeCDS][1:]
+	analysis, err = Analyze(loaded)
+	assert.Nil(err)
+	assert.True(analysis.HasDrift())
+	assert.Equal("CDS", analysis.Proxies[0].Diffs[0].TypeURI)
+	assert.Len(analysis.Proxies[0].Diffs[0].OnlyInReplay, 1)
+}
+
+func TestAnalyzeMulticlusterMode(t *testing.T) {
+	assert := tassert.New(t)
+
+	bundle, _ := newTestBundle()
+	bundle.MeshConfig.Spec.FeatureFlags.EnableMulticlusterMode = true
+
+	analysis, err := Analyze(bundle)
+	assert.Nil(err)
+	assert.Len(analysis.Warnings, 1)
+}
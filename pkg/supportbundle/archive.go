@@ -0,0 +1,226 @@
+package supportbundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/envoy"
+)
+
+// proxyConfigFile is the encoding of a ProxyConfig in a support bundle archive. The resources are encoded as
+// google.protobuf.Any messages in their JSON representation, keyed by the xDS type URI.
+type proxyConfigFile struct {
+	ResourceNames map[string][]string          `json:"resourceNames,omitempty"`
+	Resources     map[string][]json.RawMessage `json:"resources"`
+}
+
+// FileName returns the name of the support bundle archive
+func (b *Bundle) FileName() string {
+	return fmt.Sprintf("osm-support-bundle-%s-%s.tar.gz", b.Metadata.MeshName, b.Metadata.CreatedAt.UTC().Format("20060102-150405"))
+}
+
+// Write writes the support bundle to the given writer, as a gzipped tar archive of JSON files
+func (b *Bundle) Write(w io.Writer) error {
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	jsonFiles := []struct {
+		name  string
+		value interface{}
+	}{
+		{metadataFile, b.Metadata},
+		{meshConfigFile, b.MeshConfig},
+		{kubernetesFile, b.Kubernetes},
+		{smiFile, b.SMI},
+		{policiesFile, b.Policies},
+		{proxiesFile, b.Proxies},
+		{logsFile, b.Logs},
+	}
+	for _, file := range jsonFiles {
+		if err := writeJSONFile(tarWriter, file.name, file.value); err != nil {
+			return err
+		}
+	}
+
+	for cn, proxyConfig := range b.ProxyConfigs {
+		encoded, err := encodeProxyConfig(proxyConfig)
+		if err != nil {
+			return errors.Wrapf(err, "Error encoding the xDS configuration of proxy %s", cn)
+		}
+		if err := writeJSONFile(tarWriter, xdsDir+cn.String()+".json", encoded); err != nil {
+			return err
+		}
+	}
+
+	if err := writeFile(tarWriter, metricsFile, b.Metrics); err != nil {
+		return err
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return errors.Wrap(err, "Error closing support bundle archive")
+	}
+	return gzipWriter.Close()
+}
+
+// Load reads a support bundle from the given reader, as written by Bundle.Write
+func Load(r io.Reader) (*Bundle, error) {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading support bundle archive")
+	}
+	defer gzipReader.Close() //nolint: errcheck,gosec
+
+	bundle := &Bundle{
+		ProxyConfigs: make(map[certificate.CommonName]*ProxyConfig),
+	}
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "Error reading support bundle archive")
+		}
+
+		content, err := ioutil.ReadAll(tarReader)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error reading file %s of support bundle archive", header.Name)
+		}
+
+		switch {
+		case header.Name == metadataFile:
+			err = json.Unmarshal(content, &bundle.Metadata)
+		case header.Name == meshConfigFile:
+			err = json.Unmarshal(content, &bundle.MeshConfig)
+		case header.Name == kubernetesFile:
+			err = json.Unmarshal(content, &bundle.Kubernetes)
+		case header.Name == smiFile:
+			err = json.Unmarshal(content, &bundle.SMI)
+		case header.Name == policiesFile:
+			err = json.Unmarshal(content, &bundle.Policies)
+		case header.Name == proxiesFile:
+			err = json.Unmarshal(content, &bundle.Proxies)
+		case header.Name == logsFile:
+			err = json.Unmarshal(content, &bundle.Logs)
+		case header.Name == metricsFile:
+			bundle.Metrics = content
+		case strings.HasPrefix(header.Name, xdsDir):
+			cn := certificate.CommonName(strings.TrimSuffix(strings.TrimPrefix(header.Name, xdsDir), ".json"))
+			bundle.ProxyConfigs[cn], err = decodeProxyConfig(content)
+		default:
+			log.Debug().Msgf("Ignoring unknown file %s of support bundle archive", header.Name)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error decoding file %s of support bundle archive", header.Name)
+		}
+	}
+
+	if bundle.MeshConfig == nil {
+		return nil, errors.Errorf("Support bundle archive is missing %s", meshConfigFile)
+	}
+
+	return bundle, nil
+}
+
+func writeJSONFile(tarWriter *tar.Writer, name string, value interface{}) error {
+	content, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "Error marshaling %s", name)
+	}
+	return writeFile(tarWriter, name, content)
+}
+
+func writeFile(tarWriter *tar.Writer, name string, content []byte) error {
+	header := &tar.Header{
+		Name: name,
+		Mode: 0600,
+		Size: int64(len(content)),
+	}
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return errors.Wrapf(err, "Error writing header of %s", name)
+	}
+	if _, err := tarWriter.Write(content); err != nil {
+		return errors.Wrapf(err, "Error writing %s", name)
+	}
+	return nil
+}
+
+func encodeProxyConfig(proxyConfig *ProxyConfig) (*proxyConfigFile, error) {
+	encoded := &proxyConfigFile{
+		ResourceNames: make(map[string][]string),
+		Resources:     make(map[string][]json.RawMessage),
+	}
+
+	for typeURI, names := range proxyConfig.ResourceNames {
+		encoded.ResourceNames[typeURI.String()] = names
+	}
+
+	for typeURI, resources := range proxyConfig.Resources {
+		encodedResources := []json.RawMessage{}
+		for _, resource := range resources {
+			marshalledResource, err := ptypes.MarshalAny(resource)
+			if err != nil {
+				return nil, err
+			}
+			jsonResource, err := protojson.Marshal(marshalledResource)
+			if err != nil {
+				return nil, err
+			}
+			encodedResources = append(encodedResources, jsonResource)
+		}
+		encoded.Resources[typeURI.String()] = encodedResources
+	}
+
+	return encoded, nil
+}
+
+func decodeProxyConfig(content []byte) (*ProxyConfig, error) {
+	encoded := &proxyConfigFile{}
+	if err := json.Unmarshal(content, encoded); err != nil {
+		return nil, err
+	}
+	return decodeProxyConfigFile(encoded)
+}
+
+func decodeProxyConfigFile(encoded *proxyConfigFile) (*ProxyConfig, error) {
+	proxyConfig := &ProxyConfig{
+		ResourceNames: make(map[envoy.TypeURI][]string),
+		Resources:     make(map[envoy.TypeURI][]types.Resource),
+	}
+
+	for typeURI, names := range encoded.ResourceNames {
+		proxyConfig.ResourceNames[envoy.TypeURI(typeURI)] = names
+	}
+
+	for typeURI, encodedResources := range encoded.Resources {
+		resources := []types.Resource{}
+		for _, encodedResource := range encodedResources {
+			marshalledResource := &any.Any{}
+			if err := protojson.Unmarshal(encodedResource, marshalledResource); err != nil {
+				return nil, err
+			}
+			var resource ptypes.DynamicAny
+			if err := ptypes.UnmarshalAny(marshalledResource, &resource); err != nil {
+				return nil, err
+			}
+			resources = append(resources, resource.Message)
+		}
+		proxyConfig.Resources[envoy.TypeURI(typeURI)] = resources
+	}
+
+	return proxyConfig, nil
+}
@@ -0,0 +1,273 @@
+package supportbundle
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	mapset "github.com/deckarep/golang-set"
+	xds_discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/cds"
+	"github.com/openservicemesh/osm/pkg/envoy/eds"
+	"github.com/openservicemesh/osm/pkg/envoy/lds"
+	"github.com/openservicemesh/osm/pkg/envoy/rds"
+	"github.com/openservicemesh/osm/pkg/envoy/registry"
+	"github.com/openservicemesh/osm/pkg/envoy/srds"
+	policyClientset "github.com/openservicemesh/osm/pkg/gen/client/policy/clientset/versioned"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/metricsstore"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/smi"
+	"github.com/openservicemesh/osm/pkg/version"
+)
+
+// xdsGenerators are the xDS handlers generating the configuration of a proxy captured in a support bundle, in the
+// order they are sent to the proxies. SDS is deliberately left out: support bundles never contain secrets.
+var xdsGenerators = []struct {
+	typeURI  envoy.TypeURI
+	generate func(catalog.MeshCataloger, *envoy.Proxy, *xds_discovery.DiscoveryRequest, configurator.Configurator, certificate.Manager, *registry.ProxyRegistry) ([]types.Resource, error)
+}{
+	{envoy.TypeCDS, cds.NewResponse},
+	{envoy.TypeEDS, eds.NewResponse},
+	{envoy.TypeLDS, lds.NewResponse},
+	{envoy.TypeSRDS, srds.NewResponse},
+	{envoy.TypeRDS, rds.NewResponse},
+}
+
+// NewExporter returns an Exporter of support bundles capturing the state of the given control plane.
+func NewExporter(meshCatalog catalog.MeshCataloger, meshSpec smi.MeshSpec, policyClient policyClientset.Interface, proxyRegistry *registry.ProxyRegistry, certManager certificate.Manager, cfg configurator.Configurator, osmNamespace, meshName string) *Exporter {
+	return &Exporter{
+		meshCatalog:   meshCatalog,
+		meshSpec:      meshSpec,
+		policyClient:  policyClient,
+		proxyRegistry: proxyRegistry,
+		certManager:   certManager,
+		cfg:           cfg,
+		osmNamespace:  osmNamespace,
+		meshName:      meshName,
+	}
+}
+
+// GetSupportBundleHTTPHandler returns an HTTP handler responding with a support bundle archive capturing the current
+// state of the control plane.
+func (e *Exporter) GetSupportBundleHTTPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		bundle := e.Export()
+
+		// The archive is built in memory, so that an error is reported with the status of the response
+		var archive bytes.Buffer
+		if err := bundle.Write(&archive); err != nil {
+			log.Error().Err(err).Msg("Error writing support bundle")
+			http.Error(w, "Error writing support bundle", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", bundle.FileName()))
+		_, _ = w.Write(archive.Bytes())
+	})
+}
+
+// Export captures the current state of the control plane in a support bundle.
+// The xDS configuration of each connected proxy is generated from the current state of the mesh catalog, the way the
+// controller would generate it for an update sent to the proxy.
+func (e *Exporter) Export() *Bundle {
+	bundle := e.exportState()
+	bundle.Logs = logger.ListRecentLogs()
+
+	var metrics bytes.Buffer
+	if err := metricsstore.DefaultMetricsStore.WriteText(&metrics); err != nil {
+		log.Error().Err(err).Msg("Error gathering metrics for the support bundle")
+	}
+	bundle.Metrics = metrics.Bytes()
+
+	return bundle
+}
+
+// exportState captures the state of the control plane the xDS configuration of the proxies is generated from, without
+// the controller metrics and logs
+func (e *Exporter) exportState() *Bundle {
+	kubeController := e.meshCatalog.GetKubeController()
+
+	bundle := &Bundle{
+		Metadata: Metadata{
+			OSMNamespace: e.osmNamespace,
+			MeshName:     e.meshName,
+			Version:      version.Version,
+			CreatedAt:    time.Now(),
+		},
+		MeshConfig:   e.cfg.GetMeshConfig(),
+		Kubernetes:   e.exportKubernetesObjects(kubeController),
+		SMI:          e.exportSMIPolicies(),
+		Policies:     e.exportPolicies(kubeController),
+		Proxies:      e.proxyRegistry.ListProxyInventory(""),
+		ProxyConfigs: make(map[certificate.CommonName]*ProxyConfig),
+	}
+
+	for cn, proxy := range e.proxyRegistry.ListConnectedProxies() {
+		bundle.ProxyConfigs[cn] = e.exportProxyConfig(proxy)
+	}
+
+	return bundle
+}
+
+func (e *Exporter) exportKubernetesObjects(kubeController k8s.Controller) KubernetesObjects {
+	var objects KubernetesObjects
+
+	namespaces, err := kubeController.ListMonitoredNamespaces()
+	if err != nil {
+		log.Error().Err(err).Msg("Error listing monitored namespaces for the support bundle")
+	}
+	for _, name := range namespaces {
+		if ns := kubeController.GetNamespace(name); ns != nil {
+			objects.Namespaces = append(objects.Namespaces, *ns)
+		}
+	}
+
+	for _, svc := range kubeController.ListServices() {
+		objects.Services = append(objects.Services, *svc)
+
+		meshSvc := service.MeshService{Namespace: svc.Namespace, Name: svc.Name}
+		if endpoints, err := kubeController.GetEndpoints(meshSvc); err == nil && endpoints != nil {
+			objects.Endpoints = append(objects.Endpoints, *endpoints)
+		}
+		for _, endpointSlice := range kubeController.ListEndpointSlicesForService(meshSvc) {
+			objects.EndpointSlices = append(objects.EndpointSlices, *endpointSlice)
+		}
+	}
+
+	for _, serviceAccount := range kubeController.ListServiceAccounts() {
+		objects.ServiceAccounts = append(objects.ServiceAccounts, *serviceAccount)
+	}
+
+	for _, pod := range kubeController.ListPods() {
+		objects.Pods = append(objects.Pods, *pod)
+	}
+
+	return objects
+}
+
+func (e *Exporter) exportSMIPolicies() SMIPolicies {
+	var policies SMIPolicies
+
+	for _, trafficTarget := range e.meshSpec.ListTrafficTargets() {
+		policies.TrafficTargets = append(policies.TrafficTargets, *trafficTarget)
+	}
+	for _, routeGroup := range e.meshSpec.ListHTTPTrafficSpecs() {
+		policies.HTTPRouteGroups = append(policies.HTTPRouteGroups, *routeGroup)
+	}
+	for _, tcpRoute := range e.meshSpec.ListTCPTrafficSpecs() {
+		policies.TCPRoutes = append(policies.TCPRoutes, *tcpRoute)
+	}
+	for _, trafficSplit := range e.meshSpec.ListTrafficSplits() {
+		policies.TrafficSplits = append(policies.TrafficSplits, *trafficSplit)
+	}
+
+	return policies
+}
+
+func (e *Exporter) exportPolicies(kubeController k8s.Controller) Policies {
+	var policies Policies
+	client := e.policyClient.PolicyV1alpha1()
+	ctx := context.Background()
+
+	if egresses, err := client.Egresses(metav1.NamespaceAll).List(ctx, metav1.ListOptions{}); err != nil {
+		log.Error().Err(err).Msg("Error listing Egress policies for the support bundle")
+	} else {
+		for _, egress := range egresses.Items {
+			if kubeController.IsMonitoredNamespace(egress.Namespace) {
+				policies.Egresses = append(policies.Egresses, egress)
+			}
+		}
+	}
+
+	if ingressBackends, err := client.IngressBackends(metav1.NamespaceAll).List(ctx, metav1.ListOptions{}); err != nil {
+		log.Error().Err(err).Msg("Error listing IngressBackend policies for the support bundle")
+	} else {
+		for _, ingressBackend := range ingressBackends.Items {
+			if kubeController.IsMonitoredNamespace(ingressBackend.Namespace) {
+				policies.IngressBackends = append(policies.IngressBackends, ingressBackend)
+			}
+		}
+	}
+
+	if upstreamTrafficSettings, err := client.UpstreamTrafficSettings(metav1.NamespaceAll).List(ctx, metav1.ListOptions{}); err != nil {
+		log.Error().Err(err).Msg("Error listing UpstreamTrafficSetting policies for the support bundle")
+	} else {
+		for _, upstreamTrafficSetting := range upstreamTrafficSettings.Items {
+			if kubeController.IsMonitoredNamespace(upstreamTrafficSetting.Namespace) {
+				policies.UpstreamTrafficSettings = append(policies.UpstreamTrafficSettings, upstreamTrafficSetting)
+			}
+		}
+	}
+
+	if progressiveDeliveries, err := client.ProgressiveDeliveries(metav1.NamespaceAll).List(ctx, metav1.ListOptions{}); err != nil {
+		log.Error().Err(err).Msg("Error listing ProgressiveDelivery policies for the support bundle")
+	} else {
+		for _, progressiveDelivery := range progressiveDeliveries.Items {
+			if kubeController.IsMonitoredNamespace(progressiveDelivery.Namespace) {
+				policies.ProgressiveDeliveries = append(policies.ProgressiveDeliveries, progressiveDelivery)
+			}
+		}
+	}
+
+	for _, externalWorkload := range kubeController.ListExternalWorkloads() {
+		policies.ExternalWorkloads = append(policies.ExternalWorkloads, *externalWorkload)
+	}
+
+	return policies
+}
+
+func (e *Exporter) exportProxyConfig(proxy *envoy.Proxy) *ProxyConfig {
+	resourceNames := make(map[envoy.TypeURI][]string)
+	for _, generator := range xdsGenerators {
+		if names := getResourceNames(proxy.GetSubscribedResources(generator.typeURI)); len(names) > 0 {
+			resourceNames[generator.typeURI] = names
+		}
+	}
+
+	return generateProxyConfig(e.meshCatalog, proxy, resourceNames, e.cfg, e.certManager, e.proxyRegistry)
+}
+
+// generateProxyConfig generates the xDS configuration of the given proxy for the resource names it subscribed to
+func generateProxyConfig(meshCatalog catalog.MeshCataloger, proxy *envoy.Proxy, resourceNames map[envoy.TypeURI][]string, cfg configurator.Configurator, certManager certificate.Manager, proxyRegistry *registry.ProxyRegistry) *ProxyConfig {
+	proxyConfig := &ProxyConfig{
+		ResourceNames: resourceNames,
+		Resources:     make(map[envoy.TypeURI][]types.Resource),
+	}
+
+	for _, generator := range xdsGenerators {
+		request := &xds_discovery.DiscoveryRequest{
+			TypeUrl:       generator.typeURI.String(),
+			ResourceNames: resourceNames[generator.typeURI],
+		}
+		resources, err := generator.generate(meshCatalog, proxy, request, cfg, certManager, proxyRegistry)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error generating %s resources for proxy %s", generator.typeURI.Short(), proxy.String())
+			continue
+		}
+		proxyConfig.Resources[generator.typeURI] = resources
+	}
+
+	return proxyConfig
+}
+
+func getResourceNames(resources mapset.Set) []string {
+	var names []string
+	for resource := range resources.Iter() {
+		names = append(names, resource.(string))
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,83 @@
+package supportbundle
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	smiAccess "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy/registry"
+	policyFake "github.com/openservicemesh/osm/pkg/gen/client/policy/clientset/versioned/fake"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/smi"
+	"github.com/openservicemesh/osm/pkg/tests"
+)
+
+func TestGetSupportBundleHTTPHandler(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+
+	bookstore := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "bookstore", Name: "bookstore"}}
+	meshConfig := &configv1alpha1.MeshConfig{ObjectMeta: metav1.ObjectMeta{Namespace: "osm-system", Name: "osm-mesh-config"}}
+
+	mockCatalog.EXPECT().GetKubeController().Return(mockKubeController)
+	mockConfigurator.EXPECT().GetMeshConfig().Return(meshConfig)
+	mockKubeController.EXPECT().ListMonitoredNamespaces().Return([]string{"bookstore"}, nil)
+	mockKubeController.EXPECT().GetNamespace("bookstore").Return(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "bookstore"}})
+	mockKubeController.EXPECT().ListServices().Return([]*corev1.Service{bookstore})
+	mockKubeController.EXPECT().GetEndpoints(service.MeshService{Namespace: "bookstore", Name: "bookstore"}).Return(&corev1.Endpoints{ObjectMeta: bookstore.ObjectMeta}, nil)
+	mockKubeController.EXPECT().ListEndpointSlicesForService(service.MeshService{Namespace: "bookstore", Name: "bookstore"}).Return(nil)
+	mockKubeController.EXPECT().ListServiceAccounts().Return(nil)
+	mockKubeController.EXPECT().ListPods().Return(nil)
+	mockKubeController.EXPECT().ListExternalWorkloads().Return(nil)
+	mockKubeController.EXPECT().IsMonitoredNamespace("bookstore").Return(true).AnyTimes()
+	mockKubeController.EXPECT().IsMonitoredNamespace("other").Return(false).AnyTimes()
+	mockMeshSpec.EXPECT().ListTrafficTargets().Return([]*smiAccess.TrafficTarget{&tests.TrafficTarget})
+	mockMeshSpec.EXPECT().ListHTTPTrafficSpecs().Return(nil)
+	mockMeshSpec.EXPECT().ListTCPTrafficSpecs().Return(nil)
+	mockMeshSpec.EXPECT().ListTrafficSplits().Return(nil)
+
+	policyClient := policyFake.NewSimpleClientset(
+		&policyv1alpha1.Egress{ObjectMeta: metav1.ObjectMeta{Namespace: "bookstore", Name: "egress"}},
+		&policyv1alpha1.Egress{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "egress"}},
+	)
+
+	exporter := NewExporter(mockCatalog, mockMeshSpec, policyClient, registry.NewProxyRegistry(nil), nil, mockConfigurator, "osm-system", "osm")
+
+	rr := httptest.NewRecorder()
+	exporter.GetSupportBundleHTTPHandler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/support-bundle", nil))
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.Equal("application/gzip", rr.Header().Get("Content-Type"))
+	assert.Contains(rr.Header().Get("Content-Disposition"), "osm-support-bundle-osm-")
+
+	bundle, err := Load(bytes.NewReader(rr.Body.Bytes()))
+	assert.Nil(err)
+	assert.Equal("osm-system", bundle.Metadata.OSMNamespace)
+	assert.Equal("osm", bundle.Metadata.MeshName)
+	assert.Equal("osm-mesh-config", bundle.MeshConfig.Name)
+	assert.Len(bundle.Kubernetes.Namespaces, 1)
+	assert.Len(bundle.Kubernetes.Services, 1)
+	assert.Len(bundle.Kubernetes.Endpoints, 1)
+	assert.Len(bundle.SMI.TrafficTargets, 1)
+	assert.Len(bundle.Policies.Egresses, 1)
+	assert.Equal("bookstore", bundle.Policies.Egresses[0].Namespace)
+	assert.Empty(bundle.ProxyConfigs)
+}
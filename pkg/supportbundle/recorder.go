@@ -0,0 +1,344 @@
+package supportbundle
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+	smiAccess "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	smiSpecs "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
+	smiSplit "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha4"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/envoy/registry"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+)
+
+const (
+	// DefaultRecordingSnapshotInterval is the default interval at which a Recorder records snapshots
+	DefaultRecordingSnapshotInterval = 5 * time.Minute
+
+	// recorderBufferSize is the number of published messages buffered while the recorder writes to its file
+	recorderBufferSize = 4096
+)
+
+// replayableKind is a kind of object captured in support bundles, whose changes are recorded and applied to replays
+type replayableKind struct {
+	gvr        schema.GroupVersionResource
+	eventTypes k8s.EventTypes
+	newObject  func() runtime.Object
+}
+
+// replayableKinds are the kinds of objects captured in support bundles, keyed by kind
+var replayableKinds = map[string]replayableKind{
+	"Namespace": {
+		gvr:        corev1.SchemeGroupVersion.WithResource("namespaces"),
+		eventTypes: k8s.EventTypes{Add: announcements.NamespaceAdded, Update: announcements.NamespaceUpdated, Delete: announcements.NamespaceDeleted},
+		newObject:  func() runtime.Object { return &corev1.Namespace{} },
+	},
+	"Service": {
+		gvr:        corev1.SchemeGroupVersion.WithResource("services"),
+		eventTypes: k8s.EventTypes{Add: announcements.ServiceAdded, Update: announcements.ServiceUpdated, Delete: announcements.ServiceDeleted},
+		newObject:  func() runtime.Object { return &corev1.Service{} },
+	},
+	"ServiceAccount": {
+		gvr:        corev1.SchemeGroupVersion.WithResource("serviceaccounts"),
+		eventTypes: k8s.EventTypes{Add: announcements.ServiceAccountAdded, Update: announcements.ServiceAccountUpdated, Delete: announcements.ServiceAccountDeleted},
+		newObject:  func() runtime.Object { return &corev1.ServiceAccount{} },
+	},
+	"Pod": {
+		gvr:        corev1.SchemeGroupVersion.WithResource("pods"),
+		eventTypes: k8s.EventTypes{Add: announcements.PodAdded, Update: announcements.PodUpdated, Delete: announcements.PodDeleted},
+		newObject:  func() runtime.Object { return &corev1.Pod{} },
+	},
+	"Endpoints": {
+		gvr:        corev1.SchemeGroupVersion.WithResource("endpoints"),
+		eventTypes: k8s.EventTypes{Add: announcements.EndpointAdded, Update: announcements.EndpointUpdated, Delete: announcements.EndpointDeleted},
+		newObject:  func() runtime.Object { return &corev1.Endpoints{} },
+	},
+	"EndpointSlice": {
+		gvr:        discoveryv1beta1.SchemeGroupVersion.WithResource("endpointslices"),
+		eventTypes: k8s.EventTypes{Add: announcements.EndpointSliceAdded, Update: announcements.EndpointSliceUpdated, Delete: announcements.EndpointSliceDeleted},
+		newObject:  func() runtime.Object { return &discoveryv1beta1.EndpointSlice{} },
+	},
+	"TrafficTarget": {
+		gvr:        smiAccess.SchemeGroupVersion.WithResource("traffictargets"),
+		eventTypes: k8s.EventTypes{Add: announcements.TrafficTargetAdded, Update: announcements.TrafficTargetUpdated, Delete: announcements.TrafficTargetDeleted},
+		newObject:  func() runtime.Object { return &smiAccess.TrafficTarget{} },
+	},
+	"HTTPRouteGroup": {
+		gvr:        smiSpecs.SchemeGroupVersion.WithResource("httproutegroups"),
+		eventTypes: k8s.EventTypes{Add: announcements.RouteGroupAdded, Update: announcements.RouteGroupUpdated, Delete: announcements.RouteGroupDeleted},
+		newObject:  func() runtime.Object { return &smiSpecs.HTTPRouteGroup{} },
+	},
+	"TCPRoute": {
+		gvr:        smiSpecs.SchemeGroupVersion.WithResource("tcproutes"),
+		eventTypes: k8s.EventTypes{Add: announcements.TCPRouteAdded, Update: announcements.TCPRouteUpdated, Delete: announcements.TCPRouteDeleted},
+		newObject:  func() runtime.Object { return &smiSpecs.TCPRoute{} },
+	},
+	"TrafficSplit": {
+		gvr:        smiSplit.SchemeGroupVersion.WithResource("trafficsplits"),
+		eventTypes: k8s.EventTypes{Add: announcements.TrafficSplitAdded, Update: announcements.TrafficSplitUpdated, Delete: announcements.TrafficSplitDeleted},
+		newObject:  func() runtime.Object { return &smiSplit.TrafficSplit{} },
+	},
+	"Egress": {
+		gvr:        policyv1alpha1.SchemeGroupVersion.WithResource("egresses"),
+		eventTypes: k8s.EventTypes{Add: announcements.EgressAdded, Update: announcements.EgressUpdated, Delete: announcements.EgressDeleted},
+		newObject:  func() runtime.Object { return &policyv1alpha1.Egress{} },
+	},
+	"IngressBackend": {
+		gvr:        policyv1alpha1.SchemeGroupVersion.WithResource("ingressbackends"),
+		eventTypes: k8s.EventTypes{Add: announcements.IngressBackendAdded, Update: announcements.IngressBackendUpdated, Delete: announcements.IngressBackendDeleted},
+		newObject:  func() runtime.Object { return &policyv1alpha1.IngressBackend{} },
+	},
+	"UpstreamTrafficSetting": {
+		gvr:        policyv1alpha1.SchemeGroupVersion.WithResource("upstreamtrafficsettings"),
+		eventTypes: k8s.EventTypes{Add: announcements.UpstreamTrafficSettingAdded, Update: announcements.UpstreamTrafficSettingUpdated, Delete: announcements.UpstreamTrafficSettingDeleted},
+		newObject:  func() runtime.Object { return &policyv1alpha1.UpstreamTrafficSetting{} },
+	},
+	"ProgressiveDelivery": {
+		gvr:        policyv1alpha1.SchemeGroupVersion.WithResource("progressivedeliveries"),
+		eventTypes: k8s.EventTypes{Add: announcements.ProgressiveDeliveryAdded, Update: announcements.ProgressiveDeliveryUpdated, Delete: announcements.ProgressiveDeliveryDeleted},
+		newObject:  func() runtime.Object { return &policyv1alpha1.ProgressiveDelivery{} },
+	},
+	"ExternalWorkload": {
+		gvr:        policyv1alpha1.SchemeGroupVersion.WithResource("externalworkloads"),
+		eventTypes: k8s.EventTypes{Add: announcements.ExternalWorkloadAdded, Update: announcements.ExternalWorkloadUpdated, Delete: announcements.ExternalWorkloadDeleted},
+		newObject:  func() runtime.Object { return &policyv1alpha1.ExternalWorkload{} },
+	},
+	"MeshConfig": {
+		gvr:        configv1alpha1.SchemeGroupVersion.WithResource("meshconfigs"),
+		eventTypes: k8s.EventTypes{Add: announcements.MeshConfigAdded, Update: announcements.MeshConfigUpdated, Delete: announcements.MeshConfigDeleted},
+		newObject:  func() runtime.Object { return &configv1alpha1.MeshConfig{} },
+	},
+}
+
+// replayableKindsByType are the names of the replayable kinds, keyed by the type of their objects
+var replayableKindsByType = func() map[reflect.Type]string {
+	kinds := make(map[reflect.Type]string)
+	for name, kind := range replayableKinds {
+		kinds[reflect.TypeOf(kind.newObject())] = name
+	}
+	return kinds
+}()
+
+// RecordedMessage is a message published in the controller. The objects of the message are recorded when they are of
+// a kind captured in support bundles.
+type RecordedMessage struct {
+	// AnnouncementType is the announcement type of the message
+	AnnouncementType announcements.AnnouncementType `json:"announcementType"`
+
+	// Kind is the kind of the objects of the message, empty if they are not recorded
+	Kind string `json:"kind,omitempty"`
+
+	// OldObj is the JSON representation of the old object of the message
+	OldObj json.RawMessage `json:"oldObj,omitempty"`
+
+	// NewObj is the JSON representation of the new object of the message
+	NewObj json.RawMessage `json:"newObj,omitempty"`
+}
+
+// recordEntry is a line of a recording file, holding either a message or a snapshot
+type recordEntry struct {
+	Time     time.Time        `json:"time"`
+	Message  *RecordedMessage `json:"message,omitempty"`
+	Snapshot *recordSnapshot  `json:"snapshot,omitempty"`
+}
+
+// recordSnapshot is the encoding of a snapshot in a recording file. Snapshots hold the state captured in support
+// bundles, without the controller metrics and logs.
+type recordSnapshot struct {
+	Metadata     Metadata                                    `json:"metadata"`
+	MeshConfig   *configv1alpha1.MeshConfig                  `json:"meshConfig"`
+	Kubernetes   KubernetesObjects                           `json:"kubernetes"`
+	SMI          SMIPolicies                                 `json:"smi"`
+	Policies     Policies                                    `json:"policies"`
+	Proxies      []registry.ProxyInfo                        `json:"proxies"`
+	ProxyConfigs map[certificate.CommonName]*proxyConfigFile `json:"proxyConfigs"`
+}
+
+// Recorder records the messages published in the controller, and periodic snapshots of the state of the control plane,
+// to a file. The recording can be replayed offline through the mesh catalog and the xDS builders with
+// ReplayRecording, to reproduce the configuration the proxies received as the state of the mesh changed.
+type Recorder struct {
+	exporter         *Exporter
+	encoder          *json.Encoder
+	snapshotInterval time.Duration
+}
+
+// NewRecorder returns a Recorder writing to the given writer, as JSON lines, the messages published in the controller
+// and snapshots of the state captured by the given exporter at the given interval. Snapshots are only recorded when
+// the recorder starts and stops if the interval is 0.
+func NewRecorder(exporter *Exporter, w io.Writer, snapshotInterval time.Duration) *Recorder {
+	return &Recorder{
+		exporter:         exporter,
+		encoder:          json.NewEncoder(w),
+		snapshotInterval: snapshotInterval,
+	}
+}
+
+// Run records the messages published in the controller until the stop channel is closed, starting and ending the
+// recording with a snapshot
+func (r *Recorder) Run(stop <-chan struct{}) {
+	messages := make(chan events.PubSubMessage, recorderBufferSize)
+	stopped := make(chan struct{})
+	events.ObservePublished(func(message events.PubSubMessage) {
+		// Messages are never dropped: the replay of a recording with gaps would diverge from the controller
+		select {
+		case messages <- message:
+		case <-stopped:
+		}
+	})
+
+	r.recordSnapshot(messages)
+
+	var snapshots <-chan time.Time
+	if r.snapshotInterval > 0 {
+		ticker := time.NewTicker(r.snapshotInterval)
+		defer ticker.Stop()
+		snapshots = ticker.C
+	}
+
+	for {
+		select {
+		case message := <-messages:
+			r.recordMessage(message)
+
+		case <-snapshots:
+			r.recordSnapshot(messages)
+
+		case <-stop:
+			close(stopped)
+			events.ObservePublished(nil)
+			r.recordSnapshot(messages)
+			return
+		}
+	}
+}
+
+// recordSnapshot records the buffered messages, whose changes the snapshot reflects, followed by a snapshot
+func (r *Recorder) recordSnapshot(messages <-chan events.PubSubMessage) {
+	for len(messages) > 0 {
+		r.recordMessage(<-messages)
+	}
+
+	snapshot, err := encodeSnapshot(r.exporter.exportState())
+	if err != nil {
+		log.Error().Err(err).Msg("Error encoding snapshot of the control plane for the recording")
+		return
+	}
+	r.write(&recordEntry{Time: time.Now(), Snapshot: snapshot})
+}
+
+func (r *Recorder) recordMessage(message events.PubSubMessage) {
+	recorded := &RecordedMessage{AnnouncementType: message.AnnouncementType}
+
+	oldKind, oldObj := getReplayableObject(message.OldObj)
+	newKind, newObj := getReplayableObject(message.NewObj)
+	recorded.Kind = oldKind
+	if recorded.Kind == "" {
+		recorded.Kind = newKind
+	}
+
+	var err error
+	if oldObj != nil {
+		if recorded.OldObj, err = json.Marshal(oldObj); err != nil {
+			log.Error().Err(err).Msgf("Error marshaling the old %s of a %s message for the recording", oldKind, message.AnnouncementType)
+		}
+	}
+	if newObj != nil {
+		if recorded.NewObj, err = json.Marshal(newObj); err != nil {
+			log.Error().Err(err).Msgf("Error marshaling the new %s of a %s message for the recording", newKind, message.AnnouncementType)
+		}
+	}
+
+	r.write(&recordEntry{Time: time.Now(), Message: recorded})
+}
+
+func (r *Recorder) write(entry *recordEntry) {
+	if err := r.encoder.Encode(entry); err != nil {
+		log.Error().Err(err).Msg("Error writing to the recording")
+	}
+}
+
+// getReplayableObject returns the kind of the given object of a message and the object to record, or an empty kind
+// and nil if the object is not of a kind captured in support bundles
+func getReplayableObject(obj interface{}) (string, interface{}) {
+	switch o := obj.(type) {
+	case cache.DeletedFinalStateUnknown:
+		return getReplayableObject(o.Obj)
+	case *configv1alpha2.MeshConfig:
+		// The replays watch the MeshConfig through the v1alpha1 API
+		return getReplayableObject(configv1alpha2.ConvertToV1alpha1(o))
+	}
+
+	if obj == nil {
+		return "", nil
+	}
+	kind, ok := replayableKindsByType[reflect.TypeOf(obj)]
+	if !ok {
+		return "", nil
+	}
+	return kind, obj
+}
+
+func encodeSnapshot(bundle *Bundle) (*recordSnapshot, error) {
+	snapshot := &recordSnapshot{
+		Metadata:     bundle.Metadata,
+		MeshConfig:   bundle.MeshConfig,
+		Kubernetes:   bundle.Kubernetes,
+		SMI:          bundle.SMI,
+		Policies:     bundle.Policies,
+		Proxies:      bundle.Proxies,
+		ProxyConfigs: make(map[certificate.CommonName]*proxyConfigFile),
+	}
+
+	for cn, proxyConfig := range bundle.ProxyConfigs {
+		encoded, err := encodeProxyConfig(proxyConfig)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error encoding the xDS configuration of proxy %s", cn)
+		}
+		snapshot.ProxyConfigs[cn] = encoded
+	}
+
+	return snapshot, nil
+}
+
+func decodeSnapshot(snapshot *recordSnapshot) (*Bundle, error) {
+	if snapshot.MeshConfig == nil {
+		return nil, errors.New("Snapshot is missing the MeshConfig")
+	}
+
+	bundle := &Bundle{
+		Metadata:     snapshot.Metadata,
+		MeshConfig:   snapshot.MeshConfig,
+		Kubernetes:   snapshot.Kubernetes,
+		SMI:          snapshot.SMI,
+		Policies:     snapshot.Policies,
+		Proxies:      snapshot.Proxies,
+		ProxyConfigs: make(map[certificate.CommonName]*ProxyConfig),
+	}
+
+	for cn, encoded := range snapshot.ProxyConfigs {
+		proxyConfig, err := decodeProxyConfigFile(encoded)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error decoding the xDS configuration of proxy %s", cn)
+		}
+		bundle.ProxyConfigs[cn] = proxyConfig
+	}
+
+	return bundle, nil
+}
@@ -0,0 +1,172 @@
+package supportbundle
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	tassert "github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy/registry"
+	policyFake "github.com/openservicemesh/osm/pkg/gen/client/policy/clientset/versioned/fake"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+	"github.com/openservicemesh/osm/pkg/smi"
+)
+
+// syncBuffer is a buffer which can be read while being written to
+type syncBuffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buffer.Write(p)
+}
+
+func (b *syncBuffer) Bytes() []byte {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return append([]byte(nil), b.buffer.Bytes()...)
+}
+
+func TestRecorder(t *testing.T) {
+	assert := tassert.New(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCatalog := catalog.NewMockMeshCataloger(mockCtrl)
+	mockKubeController := k8s.NewMockController(mockCtrl)
+	mockMeshSpec := smi.NewMockMeshSpec(mockCtrl)
+	mockConfigurator := configurator.NewMockConfigurator(mockCtrl)
+
+	meshConfig := &configv1alpha1.MeshConfig{ObjectMeta: metav1.ObjectMeta{Namespace: "osm-system", Name: "osm-mesh-config"}}
+	mockCatalog.EXPECT().GetKubeController().Return(mockKubeController).AnyTimes()
+	mockConfigurator.EXPECT().GetMeshConfig().Return(meshConfig).AnyTimes()
+	mockKubeController.EXPECT().ListMonitoredNamespaces().Return(nil, nil).AnyTimes()
+	mockKubeController.EXPECT().ListServices().Return(nil).AnyTimes()
+	mockKubeController.EXPECT().ListServiceAccounts().Return(nil).AnyTimes()
+	mockKubeController.EXPECT().ListPods().Return(nil).AnyTimes()
+	mockKubeController.EXPECT().ListExternalWorkloads().Return(nil).AnyTimes()
+	mockMeshSpec.EXPECT().ListTrafficTargets().Return(nil).AnyTimes()
+	mockMeshSpec.EXPECT().ListHTTPTrafficSpecs().Return(nil).AnyTimes()
+	mockMeshSpec.EXPECT().ListTCPTrafficSpecs().Return(nil).AnyTimes()
+	mockMeshSpec.EXPECT().ListTrafficSplits().Return(nil).AnyTimes()
+
+	exporter := NewExporter(mockCatalog, mockMeshSpec, policyFake.NewSimpleClientset(), registry.NewProxyRegistry(nil), nil, mockConfigurator, "osm-system", "osm")
+
+	var recordingFile syncBuffer
+	recorder := NewRecorder(exporter, &recordingFile, 0)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		recorder.Run(stop)
+		close(done)
+	}()
+
+	// Wait for the initial snapshot, after which the published messages are recorded
+	assert.Eventually(func() bool {
+		return bytes.Contains(recordingFile.Bytes(), []byte(`"snapshot"`))
+	}, 5*time.Second, 10*time.Millisecond)
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "bookstore", Name: "bookstore"}}
+	events.Publish(events.PubSubMessage{AnnouncementType: announcements.PodAdded, NewObj: pod})
+	events.Publish(events.PubSubMessage{AnnouncementType: announcements.PodDeleted, OldObj: cache.DeletedFinalStateUnknown{Key: "bookstore/bookstore", Obj: pod}})
+	events.Publish(events.PubSubMessage{
+		AnnouncementType: announcements.MeshConfigUpdated,
+		OldObj:           configv1alpha2.ConvertFromV1alpha1(meshConfig),
+		NewObj:           configv1alpha2.ConvertFromV1alpha1(meshConfig),
+	})
+	events.Publish(events.PubSubMessage{AnnouncementType: announcements.ScheduleProxyBroadcast})
+	close(stop)
+	<-done
+
+	// Messages published after the recorder stopped are not recorded
+	events.Publish(events.PubSubMessage{AnnouncementType: announcements.PodAdded, NewObj: pod})
+
+	recording, err := LoadRecording(bytes.NewReader(recordingFile.Bytes()))
+	assert.Nil(err)
+	assert.Len(recording.Entries, 6)
+	assert.NotNil(recording.Entries[0].Snapshot)
+	assert.Equal("osm-mesh-config", recording.Entries[0].Snapshot.MeshConfig.Name)
+	assert.NotNil(recording.Entries[5].Snapshot)
+
+	var messages []RecordedMessage
+	for _, entry := range recording.Entries[1:5] {
+		assert.NotNil(entry.Message)
+		messages = append(messages, *entry.Message)
+	}
+	assert.Equal(announcements.PodAdded, messages[0].AnnouncementType)
+	assert.Equal("Pod", messages[0].Kind)
+	assert.Contains(string(messages[0].NewObj), `"name":"bookstore"`)
+	assert.Empty(messages[0].OldObj)
+
+	// Deleted objects are recorded without their tombstone
+	assert.Equal("Pod", messages[1].Kind)
+	assert.Contains(string(messages[1].OldObj), `"name":"bookstore"`)
+
+	// MeshConfigs are recorded in their v1alpha1 representation
+	assert.Equal("MeshConfig", messages[2].Kind)
+	assert.Contains(string(messages[2].NewObj), `"name":"osm-mesh-config"`)
+
+	// The objects of other messages are not recorded
+	assert.Equal(RecordedMessage{AnnouncementType: announcements.ScheduleProxyBroadcast}, messages[3])
+}
+
+func TestGetReplayableObject(t *testing.T) {
+	assert := tassert.New(t)
+
+	testCases := []struct {
+		name         string
+		obj          interface{}
+		expectedKind string
+	}{
+		{
+			name:         "nil object",
+			obj:          nil,
+			expectedKind: "",
+		},
+		{
+			name:         "replayable object",
+			obj:          &corev1.Service{},
+			expectedKind: "Service",
+		},
+		{
+			name:         "tombstone of a replayable object",
+			obj:          cache.DeletedFinalStateUnknown{Obj: &corev1.Endpoints{}},
+			expectedKind: "Endpoints",
+		},
+		{
+			name:         "v1alpha2 MeshConfig",
+			obj:          &configv1alpha2.MeshConfig{},
+			expectedKind: "MeshConfig",
+		},
+		{
+			name:         "object not captured in snapshots",
+			obj:          &corev1.Secret{},
+			expectedKind: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			kind, obj := getReplayableObject(tc.obj)
+			assert.Equal(tc.expectedKind, kind)
+			assert.Equal(tc.expectedKind == "", obj == nil)
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package supportbundle
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/cache"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+)
+
+// replayInformerTimeout is the time the replay of a recording waits for its informers to observe a recorded change
+const replayInformerTimeout = 2 * time.Second
+
+// Recording is the sequence of messages and snapshots recorded by a Recorder.
+type Recording struct {
+	Entries []RecordingEntry
+}
+
+// RecordingEntry is either a message published in the controller or a snapshot of the state of the control plane.
+type RecordingEntry struct {
+	// Time is the time the entry was recorded
+	Time time.Time
+
+	// Message is the message published in the controller, nil for a snapshot
+	Message *RecordedMessage
+
+	// Snapshot is the state of the control plane, nil for a message
+	Snapshot *Bundle
+}
+
+// ReplayReport is the outcome of the replay of a recording through the mesh catalog.
+type ReplayReport struct {
+	// Warnings describe the parts of the recording which could not be replayed faithfully
+	Warnings []string `json:"warnings,omitempty"`
+
+	// AppliedMessages is the number of recorded changes to objects captured in snapshots applied to the replay
+	AppliedMessages int `json:"appliedMessages"`
+
+	// SkippedMessages is the number of recorded messages not applied to the replay, per announcement type. These are
+	// the messages the controller published in reaction to other messages, which the replay publishes itself, and the
+	// changes to objects not captured in snapshots, such as Secrets and Ingresses.
+	SkippedMessages map[announcements.AnnouncementType]int `json:"skippedMessages"`
+
+	// Snapshots compare the xDS configuration of the proxies captured in each snapshot with the replayed one
+	Snapshots []SnapshotAnalysis `json:"snapshots"`
+}
+
+// SnapshotAnalysis compares the xDS configuration of the proxies captured in a snapshot with the replayed one.
+type SnapshotAnalysis struct {
+	// Time is the time the snapshot was recorded
+	Time time.Time `json:"time"`
+
+	// Messages is the number of messages recorded since the first snapshot
+	Messages int `json:"messages"`
+
+	// Proxies are the proxies of the snapshot, with the differences between their captured and replayed configuration
+	Proxies []ProxyAnalysis `json:"proxies"`
+}
+
+// HasDrift returns whether the replayed configuration of any proxy differs from the one captured in any snapshot
+func (r *ReplayReport) HasDrift() bool {
+	for _, snapshot := range r.Snapshots {
+		for _, proxy := range snapshot.Proxies {
+			if len(proxy.Diffs) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LoadRecording reads a recording from the given reader, as written by a Recorder. A truncated last entry, written
+// while the controller was terminated, is ignored.
+func LoadRecording(r io.Reader) (*Recording, error) {
+	recording := &Recording{}
+
+	decoder := json.NewDecoder(r)
+	for {
+		var entry recordEntry
+		err := decoder.Decode(&entry)
+		if err == io.EOF {
+			break
+		}
+		if err == io.ErrUnexpectedEOF {
+			log.Warn().Msgf("Ignoring truncated entry %d of recording", len(recording.Entries)+1)
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error decoding entry %d of recording", len(recording.Entries)+1)
+		}
+
+		recordingEntry := RecordingEntry{
+			Time:    entry.Time,
+			Message: entry.Message,
+		}
+		if entry.Snapshot != nil {
+			if recordingEntry.Snapshot, err = decodeSnapshot(entry.Snapshot); err != nil {
+				return nil, errors.Wrapf(err, "Error decoding snapshot of entry %d of recording", len(recording.Entries)+1)
+			}
+		}
+		recording.Entries = append(recording.Entries, recordingEntry)
+	}
+
+	return recording, nil
+}
+
+// ReplayRecording replays a recording through a mesh catalog running offline. The replay starts from the state
+// captured in the first snapshot of the recording, and applies the recorded changes to the objects captured in
+// snapshots in order, waiting for each change to be observed by the informers of the replay. At each later snapshot,
+// it compares the xDS configuration captured for each proxy with the one the replay generates, so that the change
+// after which the configuration of a proxy diverged can be narrowed down. Messages recorded before the first snapshot
+// are reflected in it and not replayed.
+func ReplayRecording(recording *Recording) (*ReplayReport, error) {
+	first := -1
+	for i, entry := range recording.Entries {
+		if entry.Snapshot != nil {
+			first = i
+			break
+		}
+	}
+	if first < 0 {
+		return nil, errors.New("Recording does not hold any snapshot")
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	r, err := newReplay(recording.Entries[first].Snapshot, stop)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ReplayReport{
+		SkippedMessages: make(map[announcements.AnnouncementType]int),
+		Snapshots:       []SnapshotAnalysis{},
+	}
+
+	// Subscribe before applying any change, so that the informer messages the changes trigger are not missed
+	var informerAnnouncements []announcements.AnnouncementType
+	for _, kind := range replayableKinds {
+		informerAnnouncements = append(informerAnnouncements, kind.eventTypes.Add, kind.eventTypes.Update, kind.eventTypes.Delete)
+	}
+	informerMessages := events.Subscribe(informerAnnouncements...)
+	defer events.Unsub(informerMessages)
+
+	messages := 0
+	for _, entry := range recording.Entries[first:] {
+		if entry.Snapshot != nil {
+			proxies, err := r.analyzeProxies(entry.Snapshot.ProxyConfigs)
+			if err != nil {
+				return nil, err
+			}
+			report.Snapshots = append(report.Snapshots, SnapshotAnalysis{
+				Time:     entry.Time,
+				Messages: messages,
+				Proxies:  proxies,
+			})
+			continue
+		}
+		if entry.Message == nil {
+			continue
+		}
+
+		messages++
+		applied, err := r.applyMessage(entry.Message, informerMessages)
+		if err != nil {
+			r.warnings = append(r.warnings, errors.Wrapf(err, "Error applying %s message %d", entry.Message.AnnouncementType, messages).Error())
+			continue
+		}
+		if applied {
+			report.AppliedMessages++
+		} else {
+			report.SkippedMessages[entry.Message.AnnouncementType]++
+		}
+	}
+
+	report.Warnings = r.warnings
+	return report, nil
+}
+
+// analyzeProxies compares the xDS configuration captured for each of the given proxies with the replayed one
+func (r *replay) analyzeProxies(proxyConfigs map[certificate.CommonName]*ProxyConfig) ([]ProxyAnalysis, error) {
+	proxies := []ProxyAnalysis{}
+	for cn, proxyConfig := range proxyConfigs {
+		proxyAnalysis, err := r.analyzeProxy(cn, proxyConfig)
+		if err != nil {
+			return nil, err
+		}
+		proxies = append(proxies, *proxyAnalysis)
+	}
+	sort.Slice(proxies, func(i, j int) bool {
+		return proxies[i].CommonName < proxies[j].CommonName
+	})
+	return proxies, nil
+}
+
+// applyMessage applies the change of the given message to the fake clientsets of the replay, and waits for the
+// informers of the replay to observe it. It returns false if the message is not a change to an object captured in
+// snapshots.
+func (r *replay) applyMessage(message *RecordedMessage, informerMessages chan interface{}) (bool, error) {
+	kind, ok := replayableKinds[message.Kind]
+	if !ok {
+		return false, nil
+	}
+
+	var content []byte
+	switch message.AnnouncementType {
+	case kind.eventTypes.Add, kind.eventTypes.Update:
+		content = message.NewObj
+	case kind.eventTypes.Delete:
+		content = message.OldObj
+	default:
+		// Published by the controller in reaction to a change, the replay publishes it itself
+		return false, nil
+	}
+
+	obj := kind.newObject()
+	if err := json.Unmarshal(content, obj); err != nil {
+		return false, err
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return false, err
+	}
+	// The objects of the fake clientsets are versioned independently from the controller's
+	accessor.SetResourceVersion("")
+	if meshConfig, ok := obj.(*configv1alpha1.MeshConfig); ok {
+		// The replay only covers the local cluster
+		meshConfig.Spec.FeatureFlags.EnableMulticlusterMode = false
+	}
+
+	tracker := r.trackers[kind.gvr.Group]
+	namespace, name := accessor.GetNamespace(), accessor.GetName()
+	if message.AnnouncementType == kind.eventTypes.Delete {
+		err = tracker.Delete(kind.gvr, namespace, name)
+		if apierrors.IsNotFound(err) {
+			// Already reflected in the snapshot the replay started from
+			return true, nil
+		}
+	} else if _, err = tracker.Get(kind.gvr, namespace, name); apierrors.IsNotFound(err) {
+		err = tracker.Create(kind.gvr, obj, namespace)
+	} else {
+		err = tracker.Update(kind.gvr, obj, namespace)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if !waitForInformerMessage(informerMessages, kind.eventTypes, namespace, name) {
+		r.warnings = append(r.warnings, errors.Errorf("Timed out waiting for the replay to observe %s of %s %s/%s",
+			message.AnnouncementType, message.Kind, namespace, name).Error())
+	}
+	return true, nil
+}
+
+// waitForInformerMessage waits for an informer to publish a message about the given object, so that the caches of the
+// replay reflect the change to the object before the next one is applied
+func waitForInformerMessage(informerMessages chan interface{}, eventTypes k8s.EventTypes, namespace, name string) bool {
+	timeout := time.After(replayInformerTimeout)
+	for {
+		select {
+		case msg := <-informerMessages:
+			psubMessage, ok := msg.(events.PubSubMessage)
+			if !ok {
+				continue
+			}
+			if psubMessage.AnnouncementType != eventTypes.Add && psubMessage.AnnouncementType != eventTypes.Update &&
+				psubMessage.AnnouncementType != eventTypes.Delete {
+				continue
+			}
+			obj := psubMessage.NewObj
+			if obj == nil {
+				obj = psubMessage.OldObj
+			}
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			accessor, err := meta.Accessor(obj)
+			if err != nil {
+				continue
+			}
+			if accessor.GetNamespace() == namespace && accessor.GetName() == name {
+				return true
+			}
+
+		case <-timeout:
+			return false
+		}
+	}
+}
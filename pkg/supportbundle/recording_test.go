@@ -0,0 +1,135 @@
+package supportbundle
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/k8s/events"
+)
+
+// captureProxyConfig returns the xDS configuration a replay of the given bundle generates for the given proxy
+func captureProxyConfig(t *testing.T, bundle *Bundle, cn certificate.CommonName) *ProxyConfig {
+	stop := make(chan struct{})
+	defer close(stop)
+
+	r, err := newReplay(bundle, stop)
+	tassert.Nil(t, err)
+	proxy, err := envoy.NewProxy(cn, "", nil)
+	tassert.Nil(t, err)
+	return generateProxyConfig(r.meshCatalog, proxy, nil, r.cfg, nil, r.proxyRegistry)
+}
+
+func TestReplayRecording(t *testing.T) {
+	assert := tassert.New(t)
+
+	// The bookstore service is deleted between the two snapshots of the recording
+	before, cn := newTestBundle()
+	before.ProxyConfigs[cn] = captureProxyConfig(t, before, cn)
+	bookstore := before.Kubernetes.Services[0]
+
+	after := *before
+	after.Kubernetes.Services = nil
+	after.ProxyConfigs = map[certificate.CommonName]*ProxyConfig{}
+	after.ProxyConfigs[cn] = captureProxyConfig(t, &after, cn)
+	assert.Less(len(after.ProxyConfigs[cn].Resources[envoy.TypeCDS]), len(before.ProxyConfigs[cn].Resources[envoy.TypeCDS]))
+
+	writeRecording := func(messages ...events.PubSubMessage) *Recording {
+		var recordingFile bytes.Buffer
+		recorder := NewRecorder(nil, &recordingFile, 0)
+
+		for _, bundle := range []*Bundle{before, &after} {
+			snapshot, err := encodeSnapshot(bundle)
+			assert.Nil(err)
+			recorder.write(&recordEntry{Snapshot: snapshot})
+			if bundle == before {
+				for _, message := range messages {
+					recorder.recordMessage(message)
+				}
+			}
+		}
+
+		recording, err := LoadRecording(&recordingFile)
+		assert.Nil(err)
+		return recording
+	}
+
+	recording := writeRecording(
+		events.PubSubMessage{AnnouncementType: announcements.ServiceDeleted, OldObj: &bookstore},
+		events.PubSubMessage{AnnouncementType: announcements.ScheduleProxyBroadcast},
+	)
+	report, err := ReplayRecording(recording)
+	assert.Nil(err)
+	assert.Empty(report.Warnings)
+	assert.Equal(1, report.AppliedMessages)
+	assert.Equal(map[announcements.AnnouncementType]int{announcements.ScheduleProxyBroadcast: 1}, report.SkippedMessages)
+	assert.Len(report.Snapshots, 2)
+	assert.Equal(0, report.Snapshots[0].Messages)
+	assert.Equal(2, report.Snapshots[1].Messages)
+	assert.Equal(cn, report.Snapshots[1].Proxies[0].CommonName)
+	assert.False(report.HasDrift())
+
+	// Without the deletion of the service, the replay diverges from the second snapshot
+	recording = writeRecording()
+	report, err = ReplayRecording(recording)
+	assert.Nil(err)
+	assert.True(report.HasDrift())
+	assert.Empty(report.Snapshots[0].Proxies[0].Diffs)
+	assert.Equal("CDS", report.Snapshots[1].Proxies[0].Diffs[0].TypeURI)
+	assert.Len(report.Snapshots[1].Proxies[0].Diffs[0].OnlyInReplay, 1)
+}
+
+func TestLoadRecording(t *testing.T) {
+	testCases := []struct {
+		name            string
+		content         string
+		expectedEntries int
+		expectedErr     bool
+	}{
+		{
+			name:            "empty recording",
+			content:         "",
+			expectedEntries: 0,
+		},
+		{
+			name: "truncated last entry",
+			content: `{"time":"2021-06-01T00:00:00Z","message":{"announcementType":"schedule-proxy-broadcast"}}
+{"time":"2021-06-01T00:00:01Z","message":{"announce`,
+			expectedEntries: 1,
+		},
+		{
+			name:        "snapshot without MeshConfig",
+			content:     `{"time":"2021-06-01T00:00:00Z","snapshot":{"metadata":{}}}`,
+			expectedErr: true,
+		},
+		{
+			name:        "invalid entry",
+			content:     `[]`,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			recording, err := LoadRecording(strings.NewReader(tc.content))
+			assert.Equal(tc.expectedErr, err != nil)
+			if !tc.expectedErr {
+				assert.Len(recording.Entries, tc.expectedEntries)
+			}
+		})
+	}
+}
+
+func TestReplayRecordingWithoutSnapshot(t *testing.T) {
+	assert := tassert.New(t)
+
+	_, err := ReplayRecording(&Recording{Entries: []RecordingEntry{{Message: &RecordedMessage{AnnouncementType: announcements.ScheduleProxyBroadcast}}}})
+	assert.NotNil(err)
+}
@@ -0,0 +1,165 @@
+package supportbundle
+
+import (
+	smiAccess "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	smiSpecs "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
+	smiSplit "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha4"
+	smiAccessFake "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/access/clientset/versioned/fake"
+	smiSpecsFake "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/specs/clientset/versioned/fake"
+	smiSplitFake "github.com/servicemeshinterface/smi-sdk-go/pkg/gen/client/split/clientset/versioned/fake"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
+	networkingV1 "k8s.io/api/networking/v1"
+	networkingV1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/endpoint"
+	"github.com/openservicemesh/osm/pkg/envoy/registry"
+	configFake "github.com/openservicemesh/osm/pkg/gen/client/config/clientset/versioned/fake"
+	policyFake "github.com/openservicemesh/osm/pkg/gen/client/policy/clientset/versioned/fake"
+	"github.com/openservicemesh/osm/pkg/k8s"
+	"github.com/openservicemesh/osm/pkg/policy"
+	"github.com/openservicemesh/osm/pkg/providers/kube"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/smi"
+)
+
+// replay is a control plane running on fake clientsets holding the state captured in a support bundle
+type replay struct {
+	meshCatalog   catalog.MeshCataloger
+	proxyRegistry *registry.ProxyRegistry
+	cfg           configurator.Configurator
+
+	// trackers hold the objects of the fake clientsets, keyed by API group, so that changes recorded in the controller
+	// can be applied to the replay
+	trackers map[string]k8stesting.ObjectTracker
+
+	// warnings describe the parts of the bundle which could not be replayed faithfully
+	warnings []string
+}
+
+// noIngressMonitor implements ingress.Monitor for replays: Ingress resources are not captured in support bundles
+type noIngressMonitor struct{}
+
+// GetIngressNetworkingV1beta1 returns no ingress resources
+func (noIngressMonitor) GetIngressNetworkingV1beta1(service.MeshService) ([]*networkingV1beta1.Ingress, error) {
+	return nil, nil
+}
+
+// GetIngressNetworkingV1 returns no ingress resources
+func (noIngressMonitor) GetIngressNetworkingV1(service.MeshService) ([]*networkingV1.Ingress, error) {
+	return nil, nil
+}
+
+func newReplay(bundle *Bundle, stop chan struct{}) (*replay, error) {
+	r := &replay{
+		trackers: make(map[string]k8stesting.ObjectTracker),
+	}
+
+	meshConfig := bundle.MeshConfig.DeepCopy()
+	if meshConfig.Spec.FeatureFlags.EnableMulticlusterMode {
+		// The MultiClusterService resources are not captured, the replay only covers the local cluster
+		meshConfig.Spec.FeatureFlags.EnableMulticlusterMode = false
+		r.warnings = append(r.warnings, "Multicluster mode is enabled: endpoints of remote clusters are not replayed")
+	}
+	configClient := configFake.NewSimpleClientset(meshConfig)
+	r.trackers[configv1alpha1.SchemeGroupVersion.Group] = configClient.Tracker()
+	r.cfg = configurator.NewConfigurator(configClient, stop, meshConfig.Namespace, meshConfig.Name)
+
+	var kubeObjects []runtime.Object
+	for i := range bundle.Kubernetes.Namespaces {
+		kubeObjects = append(kubeObjects, &bundle.Kubernetes.Namespaces[i])
+	}
+	for i := range bundle.Kubernetes.Services {
+		kubeObjects = append(kubeObjects, &bundle.Kubernetes.Services[i])
+	}
+	for i := range bundle.Kubernetes.ServiceAccounts {
+		kubeObjects = append(kubeObjects, &bundle.Kubernetes.ServiceAccounts[i])
+	}
+	for i := range bundle.Kubernetes.Pods {
+		kubeObjects = append(kubeObjects, &bundle.Kubernetes.Pods[i])
+	}
+	for i := range bundle.Kubernetes.Endpoints {
+		kubeObjects = append(kubeObjects, &bundle.Kubernetes.Endpoints[i])
+	}
+	for i := range bundle.Kubernetes.EndpointSlices {
+		kubeObjects = append(kubeObjects, &bundle.Kubernetes.EndpointSlices[i])
+	}
+	kubeClient := fake.NewSimpleClientset(kubeObjects...)
+	r.trackers[corev1.GroupName] = kubeClient.Tracker()
+	r.trackers[discoveryv1beta1.GroupName] = kubeClient.Tracker()
+
+	var policyObjects []runtime.Object
+	for i := range bundle.Policies.Egresses {
+		policyObjects = append(policyObjects, &bundle.Policies.Egresses[i])
+	}
+	for i := range bundle.Policies.IngressBackends {
+		policyObjects = append(policyObjects, &bundle.Policies.IngressBackends[i])
+	}
+	for i := range bundle.Policies.UpstreamTrafficSettings {
+		policyObjects = append(policyObjects, &bundle.Policies.UpstreamTrafficSettings[i])
+	}
+	for i := range bundle.Policies.ProgressiveDeliveries {
+		policyObjects = append(policyObjects, &bundle.Policies.ProgressiveDeliveries[i])
+	}
+	for i := range bundle.Policies.ExternalWorkloads {
+		policyObjects = append(policyObjects, &bundle.Policies.ExternalWorkloads[i])
+	}
+	policyClient := policyFake.NewSimpleClientset(policyObjects...)
+	r.trackers[policyv1alpha1.SchemeGroupVersion.Group] = policyClient.Tracker()
+
+	var accessObjects, specsObjects, splitObjects []runtime.Object
+	for i := range bundle.SMI.TrafficTargets {
+		accessObjects = append(accessObjects, &bundle.SMI.TrafficTargets[i])
+	}
+	for i := range bundle.SMI.HTTPRouteGroups {
+		specsObjects = append(specsObjects, &bundle.SMI.HTTPRouteGroups[i])
+	}
+	for i := range bundle.SMI.TCPRoutes {
+		specsObjects = append(specsObjects, &bundle.SMI.TCPRoutes[i])
+	}
+	for i := range bundle.SMI.TrafficSplits {
+		splitObjects = append(splitObjects, &bundle.SMI.TrafficSplits[i])
+	}
+
+	kubeController, err := k8s.NewKubernetesController(kubeClient, policyClient, bundle.Metadata.MeshName, stop)
+	if err != nil {
+		return nil, err
+	}
+
+	splitClient := smiSplitFake.NewSimpleClientset(splitObjects...)
+	specsClient := smiSpecsFake.NewSimpleClientset(specsObjects...)
+	accessClient := smiAccessFake.NewSimpleClientset(accessObjects...)
+	r.trackers[smiSplit.SchemeGroupVersion.Group] = splitClient.Tracker()
+	r.trackers[smiSpecs.SchemeGroupVersion.Group] = specsClient.Tracker()
+	r.trackers[smiAccess.SchemeGroupVersion.Group] = accessClient.Tracker()
+
+	meshSpec, err := smi.NewMeshSpecClientFromClientsets(kubeClient, splitClient, specsClient, accessClient,
+		bundle.Metadata.OSMNamespace, kubeController, stop)
+	if err != nil {
+		return nil, err
+	}
+
+	policyController, err := policy.NewPolicyController(kubeController, policyClient, stop)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeProvider := kube.NewClient(kubeController, nil, constants.KubeProviderName, r.cfg)
+	kubeProvider.Run(stop)
+
+	// Certificates are not captured in support bundles, the replay runs without a certificate manager
+	r.meshCatalog = catalog.NewMeshCatalog(kubeController, meshSpec, nil, noIngressMonitor{}, policyController, stop, r.cfg,
+		[]service.Provider{kubeProvider}, []endpoint.Provider{kubeProvider}, nil, catalog.DefaultNumShards)
+	r.proxyRegistry = registry.NewProxyRegistry(&registry.KubeProxyServiceMapper{KubeController: kubeController})
+
+	return r, nil
+}
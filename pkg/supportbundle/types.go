@@ -0,0 +1,131 @@
+// Package supportbundle implements the export of support bundles capturing the state of the control plane: the
+// MeshConfig, the Kubernetes objects and policies of the monitored namespaces, the xDS configuration of the connected
+// proxies, the controller metrics and recent logs. It also implements the offline analysis of a support bundle, which
+// replays the captured state through the mesh catalog to reproduce the configuration the proxies received.
+package supportbundle
+
+import (
+	"time"
+
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	smiAccess "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	smiSpecs "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
+	smiSplit "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha4"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
+
+	"github.com/openservicemesh/osm/pkg/catalog"
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/envoy"
+	"github.com/openservicemesh/osm/pkg/envoy/registry"
+	policyClientset "github.com/openservicemesh/osm/pkg/gen/client/policy/clientset/versioned"
+	"github.com/openservicemesh/osm/pkg/logger"
+	"github.com/openservicemesh/osm/pkg/smi"
+)
+
+var (
+	log = logger.New("supportbundle")
+)
+
+// Names of the files of a support bundle archive
+const (
+	metadataFile   = "metadata.json"
+	meshConfigFile = "meshconfig.json"
+	kubernetesFile = "kubernetes.json"
+	smiFile        = "smi.json"
+	policiesFile   = "policies.json"
+	proxiesFile    = "proxies.json"
+	metricsFile    = "metrics.txt"
+	logsFile       = "logs.json"
+
+	// xdsDir holds a file per proxy with its xDS configuration, named after the proxy's certificate common name
+	xdsDir = "xds/"
+)
+
+// Metadata describes the control plane a support bundle was exported from.
+type Metadata struct {
+	// OSMNamespace is the namespace of the control plane
+	OSMNamespace string `json:"osmNamespace"`
+
+	// MeshName is the name of the mesh
+	MeshName string `json:"meshName"`
+
+	// Version is the version of the controller
+	Version string `json:"version"`
+
+	// CreatedAt is the time the bundle was exported
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// KubernetesObjects are the Kubernetes objects of the monitored namespaces, as seen by the controller.
+type KubernetesObjects struct {
+	Namespaces      []corev1.Namespace               `json:"namespaces"`
+	Services        []corev1.Service                 `json:"services"`
+	ServiceAccounts []corev1.ServiceAccount          `json:"serviceAccounts"`
+	Pods            []corev1.Pod                     `json:"pods"`
+	Endpoints       []corev1.Endpoints               `json:"endpoints"`
+	EndpointSlices  []discoveryv1beta1.EndpointSlice `json:"endpointSlices"`
+}
+
+// SMIPolicies are the SMI policies of the monitored namespaces, as seen by the controller.
+type SMIPolicies struct {
+	TrafficTargets  []smiAccess.TrafficTarget `json:"trafficTargets"`
+	HTTPRouteGroups []smiSpecs.HTTPRouteGroup `json:"httpRouteGroups"`
+	TCPRoutes       []smiSpecs.TCPRoute       `json:"tcpRoutes"`
+	TrafficSplits   []smiSplit.TrafficSplit   `json:"trafficSplits"`
+}
+
+// Policies are the policy.openservicemesh.io resources of the monitored namespaces.
+type Policies struct {
+	Egresses                []policyv1alpha1.Egress                 `json:"egresses"`
+	IngressBackends         []policyv1alpha1.IngressBackend         `json:"ingressBackends"`
+	UpstreamTrafficSettings []policyv1alpha1.UpstreamTrafficSetting `json:"upstreamTrafficSettings"`
+	ProgressiveDeliveries   []policyv1alpha1.ProgressiveDelivery    `json:"progressiveDeliveries"`
+	ExternalWorkloads       []policyv1alpha1.ExternalWorkload       `json:"externalWorkloads"`
+}
+
+// ProxyConfig is the xDS configuration generated for a proxy.
+type ProxyConfig struct {
+	// ResourceNames are the resource names the proxy subscribed to, per xDS type
+	ResourceNames map[envoy.TypeURI][]string
+
+	// Resources are the resources generated for the proxy, per xDS type
+	Resources map[envoy.TypeURI][]types.Resource
+}
+
+// Bundle is the content of a support bundle.
+type Bundle struct {
+	Metadata   Metadata
+	MeshConfig *configv1alpha1.MeshConfig
+	Kubernetes KubernetesObjects
+	SMI        SMIPolicies
+	Policies   Policies
+
+	// Proxies is the inventory of the proxies connected to the controller
+	Proxies []registry.ProxyInfo
+
+	// ProxyConfigs are the xDS configurations of the proxies, keyed by the proxies' certificate common name
+	ProxyConfigs map[certificate.CommonName]*ProxyConfig
+
+	// Metrics are the controller metrics, in the Prometheus text exposition format
+	Metrics []byte
+
+	// Logs are the recent log messages of the controller
+	Logs []logger.RecentLog
+}
+
+// Exporter exports support bundles from the state of the control plane.
+type Exporter struct {
+	meshCatalog   catalog.MeshCataloger
+	meshSpec      smi.MeshSpec
+	policyClient  policyClientset.Interface
+	proxyRegistry *registry.ProxyRegistry
+	certManager   certificate.Manager
+	cfg           configurator.Configurator
+	osmNamespace  string
+	meshName      string
+}
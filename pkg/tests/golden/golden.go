@@ -0,0 +1,200 @@
+// Package golden implements a harness comparing the output of the code under test, such as xDS resources, to golden
+// files. The golden files are rewritten with the actual output when the tests run with the -update flag:
+//
+//	go test ./pkg/... -run <Test> -update
+package golden
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v2"
+)
+
+// RedactedValue replaces the values of the redacted fields of the golden files
+const RedactedValue = "<redacted>"
+
+var update = flag.Bool("update", false, "update the golden files with the actual output of the tests")
+
+// TestingT is the subset of testing.TB used by the harness, also implemented by ginkgo.GinkgoT()
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+}
+
+// ProtoToObject converts the given proto message to a generic object, as decoded from its JSON encoding with the
+// original proto field names
+func ProtoToObject(m proto.Message) (interface{}, error) {
+	configJSON, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(m)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error marshaling %T", m)
+	}
+
+	// We are using yaml.Unmarshal here (instead of json.Unmarshal) because the
+	// Go JSON library doesn't try to pick the right number type (int, float,
+	// etc.) when unmarshalling to interface{}, it just picks float64
+	// universally. go-yaml does go through the effort of picking the right
+	// number type, so we can preserve number type throughout this process.
+	var object interface{}
+	if err := yaml.Unmarshal(configJSON, &object); err != nil {
+		return nil, errors.Wrapf(err, "Error decoding %T", m)
+	}
+	return object, nil
+}
+
+// ProtoToYAML returns the YAML encoding of the given proto message, with the original proto field names
+func ProtoToYAML(m proto.Message) ([]byte, error) {
+	object, err := ProtoToObject(m)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(object)
+}
+
+// Redact replaces the values of the fields of the given names of the given generic object, at any depth, with
+// RedactedValue. It is used to keep values changing from one run to the other, such as keys, out of golden files.
+func Redact(object interface{}, fields ...string) interface{} {
+	switch o := object.(type) {
+	case map[interface{}]interface{}:
+		for key, value := range o {
+			if isOneOf(fmt.Sprint(key), fields) {
+				o[key] = RedactedValue
+				continue
+			}
+			o[key] = Redact(value, fields...)
+		}
+	case []interface{}:
+		for i, value := range o {
+			o[i] = Redact(value, fields...)
+		}
+	}
+	return object
+}
+
+// SortLists sorts the list values of the fields of the given names of the given generic object, at any depth, by the
+// YAML encoding of their items. It is used to keep lists whose order is not meaningful, and may change from one run
+// to the other, such as the virtual hosts of a route configuration, from making golden files flaky.
+func SortLists(object interface{}, fields ...string) interface{} {
+	switch o := object.(type) {
+	case map[interface{}]interface{}:
+		for key, value := range o {
+			value = SortLists(value, fields...)
+			if list, ok := value.([]interface{}); ok && isOneOf(fmt.Sprint(key), fields) {
+				sortByYAML(list)
+			}
+			o[key] = value
+		}
+	case []interface{}:
+		for i, value := range o {
+			o[i] = SortLists(value, fields...)
+		}
+	}
+	return object
+}
+
+// sortByYAML sorts the given list by the YAML encoding of its items
+func sortByYAML(list []interface{}) {
+	keys := make(map[int]string, len(list))
+	for i, item := range list {
+		encoded, _ := yaml.Marshal(item)
+		keys[i] = string(encoded)
+	}
+	indexes := make([]int, len(list))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return keys[indexes[i]] < keys[indexes[j]]
+	})
+	sorted := make([]interface{}, len(list))
+	for i, index := range indexes {
+		sorted[i] = list[index]
+	}
+	copy(list, sorted)
+}
+
+// isOneOf returns whether the given field is one of the given fields
+func isOneOf(field string, fields []string) bool {
+	for _, redacted := range fields {
+		if field == redacted {
+			return true
+		}
+	}
+	return false
+}
+
+// AssertYAML compares the YAML encoding of the given value to the given golden file, or writes it to the golden file
+// when the tests run with the -update flag
+func AssertYAML(t TestingT, goldenFile string, value interface{}) {
+	t.Helper()
+
+	actual, err := yaml.Marshal(value)
+	if err != nil {
+		t.Fatalf("Error marshaling the actual output for golden file %s: %s", goldenFile, err)
+		return
+	}
+	Assert(t, goldenFile, actual)
+}
+
+// Assert compares the given actual output to the given golden file, or writes it to the golden file when the tests
+// run with the -update flag
+func Assert(t TestingT, goldenFile string, actual []byte) {
+	t.Helper()
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(goldenFile), 0750); err != nil {
+			t.Fatalf("Error creating the directory of golden file %s: %s", goldenFile, err)
+			return
+		}
+		if err := ioutil.WriteFile(filepath.Clean(goldenFile), actual, 0600); err != nil {
+			t.Fatalf("Error updating golden file %s: %s", goldenFile, err)
+		}
+		return
+	}
+
+	expected, err := ioutil.ReadFile(filepath.Clean(goldenFile))
+	if err != nil {
+		t.Fatalf("Error reading golden file %s, run the tests with -update to create it: %s", goldenFile, err)
+		return
+	}
+	if string(expected) == string(actual) {
+		return
+	}
+
+	line := firstDifferentLine(expected, actual)
+	t.Errorf(`The actual output does not match golden file %s at line %d:
+expected: %s
+actual:   %s
+If the change is intended, run the tests with -update to update the golden file and review its diff`,
+		goldenFile, line+1, lineAt(expected, line), lineAt(actual, line))
+}
+
+// firstDifferentLine returns the index of the first line differing between the given contents
+func firstDifferentLine(expected, actual []byte) int {
+	expectedLines := strings.Split(string(expected), "\n")
+	actualLines := strings.Split(string(actual), "\n")
+	for i := range expectedLines {
+		if i >= len(actualLines) || expectedLines[i] != actualLines[i] {
+			return i
+		}
+	}
+	return len(expectedLines)
+}
+
+// lineAt returns the line of the given index of the given content, or <EOF> past its end
+func lineAt(content []byte, index int) string {
+	lines := strings.Split(string(content), "\n")
+	if index >= len(lines) {
+		return "<EOF>"
+	}
+	return lines[index]
+}
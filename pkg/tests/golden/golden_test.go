@@ -0,0 +1,114 @@
+package golden
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+type fakeT struct {
+	errors []string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeT) Fatalf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRedactAndSortLists(t *testing.T) {
+	assert := tassert.New(t)
+
+	var object interface{}
+	assert.Nil(yaml.Unmarshal([]byte(`
+secrets:
+- name: b
+  inline_bytes: abc
+- name: a
+  inline_bytes: def
+hosts:
+  virtual_hosts: [c, a, b]
+`), &object))
+
+	object = SortLists(Redact(object, "inline_bytes"), "secrets", "virtual_hosts")
+
+	actual, err := yaml.Marshal(object)
+	assert.Nil(err)
+	assert.Equal(`hosts:
+  virtual_hosts:
+  - a
+  - b
+  - c
+secrets:
+- inline_bytes: <redacted>
+  name: a
+- inline_bytes: <redacted>
+  name: b
+`, string(actual))
+}
+
+func TestAssert(t *testing.T) {
+	testCases := []struct {
+		name           string
+		expected       string
+		actual         string
+		expectedErrors int
+	}{
+		{
+			name:     "matching output",
+			expected: "a: 1\nb: 2\n",
+			actual:   "a: 1\nb: 2\n",
+		},
+		{
+			name:           "differing output",
+			expected:       "a: 1\nb: 2\n",
+			actual:         "a: 1\nb: 3\n",
+			expectedErrors: 1,
+		},
+		{
+			name:           "truncated output",
+			expected:       "a: 1\nb: 2\n",
+			actual:         "a: 1\n",
+			expectedErrors: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			goldenFile := filepath.Join(t.TempDir(), "golden.yaml")
+			assert.Nil(ioutil.WriteFile(goldenFile, []byte(tc.expected), 0600))
+
+			fake := &fakeT{}
+			Assert(fake, goldenFile, []byte(tc.actual))
+			assert.Len(fake.errors, tc.expectedErrors)
+		})
+	}
+}
+
+func TestAssertMissingGoldenFile(t *testing.T) {
+	assert := tassert.New(t)
+
+	fake := &fakeT{}
+	Assert(fake, filepath.Join(t.TempDir(), "missing.yaml"), []byte("a: 1\n"))
+	assert.Len(fake.errors, 1)
+	assert.Contains(fake.errors[0], "-update")
+}
+
+func TestFirstDifferentLine(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.Equal(1, firstDifferentLine([]byte("a\nb\nc"), []byte("a\nx\nc")))
+	assert.Equal(2, firstDifferentLine([]byte("a\nb\nc"), []byte("a\nb")))
+	assert.Equal(2, firstDifferentLine([]byte("a\nb"), []byte("a\nb\nc")))
+	assert.Equal("<EOF>", lineAt([]byte("a\nb"), 2))
+}
@@ -0,0 +1,336 @@
+package tests
+
+import (
+	"fmt"
+	"net"
+
+	access "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	spec "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
+	split "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha4"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/endpoint"
+	"github.com/openservicemesh/osm/pkg/identity"
+	"github.com/openservicemesh/osm/pkg/service"
+	"github.com/openservicemesh/osm/pkg/trafficpolicy"
+)
+
+const (
+	// topologyAppLabel is the label selecting the pods of a service of a topology
+	topologyAppLabel = "app"
+
+	// topologyVersionLabel is the label selecting the pods of a backend of a TrafficSplit of a topology
+	topologyVersionLabel = "version"
+
+	// topologyMatchName is the name of the match of the HTTPRouteGroups of a topology
+	topologyMatchName = "all"
+)
+
+// BenchmarkTopologyServices are the numbers of services of the topologies of the benchmarks of the xDS builders and
+// the mesh catalog, spread across BenchmarkTopologyNamespaces namespaces
+var BenchmarkTopologyServices = []int{100, 1000, 10000}
+
+// BenchmarkTopologyNamespaces is the number of namespaces of the topologies of the benchmarks
+const BenchmarkTopologyNamespaces = 10
+
+// Topology is a mesh topology built by a TopologyBuilder: the namespaces of the mesh, their services, each with a
+// service account and a pod, and the SMI policies between them
+type Topology struct {
+	Namespaces      []*corev1.Namespace
+	Services        []*corev1.Service
+	ServiceAccounts []*corev1.ServiceAccount
+	Pods            []*corev1.Pod
+	TrafficTargets  []*access.TrafficTarget
+	HTTPRouteGroups []*spec.HTTPRouteGroup
+	TrafficSplits   []*split.TrafficSplit
+
+	endpoints        map[service.MeshService][]endpoint.Endpoint
+	identityServices map[identity.ServiceIdentity][]service.MeshService
+}
+
+// TopologyBuilder builds mesh topologies of any size for unit tests and benchmarks. The namespaces of a topology are
+// named ns-<i> and their services svc-<j>, each service running with the service account of the same name.
+type TopologyBuilder struct {
+	namespaces           int
+	servicesPerNamespace int
+	sourcesPerTarget     int
+	backendsPerSplit     int
+}
+
+// NewTopologyBuilder returns a TopologyBuilder of a topology of a single namespace with a single service, without
+// SMI policies
+func NewTopologyBuilder() *TopologyBuilder {
+	return &TopologyBuilder{
+		namespaces:           1,
+		servicesPerNamespace: 1,
+	}
+}
+
+// WithNamespaces sets the number of namespaces of the topology
+func (b *TopologyBuilder) WithNamespaces(namespaces int) *TopologyBuilder {
+	b.namespaces = namespaces
+	return b
+}
+
+// WithServicesPerNamespace sets the number of services of each namespace of the topology
+func (b *TopologyBuilder) WithServicesPerNamespace(services int) *TopologyBuilder {
+	b.servicesPerNamespace = services
+	return b
+}
+
+// WithTrafficTargets adds a TrafficTarget per service account of the topology, allowing the given number of source
+// service accounts: the ones following it in the topology, across namespaces, with HTTP routes matching any request
+func (b *TopologyBuilder) WithTrafficTargets(sourcesPerTarget int) *TopologyBuilder {
+	b.sourcesPerTarget = sourcesPerTarget
+	return b
+}
+
+// WithTrafficSplits makes each service of the topology the apex service of a TrafficSplit with the given number of
+// backends, named <service>-v<k>, splitting the traffic evenly between them. The pods of the backends run with the
+// service account of the apex service.
+func (b *TopologyBuilder) WithTrafficSplits(backendsPerSplit int) *TopologyBuilder {
+	b.backendsPerSplit = backendsPerSplit
+	return b
+}
+
+// Build returns the topology
+func (b *TopologyBuilder) Build() *Topology {
+	topology := &Topology{
+		endpoints:        make(map[service.MeshService][]endpoint.Endpoint),
+		identityServices: make(map[identity.ServiceIdentity][]service.MeshService),
+	}
+
+	var svcAccounts []identity.K8sServiceAccount
+	for i := 0; i < b.namespaces; i++ {
+		namespace := fmt.Sprintf("ns-%d", i)
+		topology.Namespaces = append(topology.Namespaces, &corev1.Namespace{ObjectMeta: v1.ObjectMeta{Name: namespace}})
+
+		for j := 0; j < b.servicesPerNamespace; j++ {
+			name := fmt.Sprintf("svc-%d", j)
+			svcAccounts = append(svcAccounts, identity.K8sServiceAccount{Namespace: namespace, Name: name})
+			topology.ServiceAccounts = append(topology.ServiceAccounts, NewServiceAccountFixture(name, namespace))
+
+			if b.backendsPerSplit == 0 {
+				topology.addService(namespace, name, name, map[string]string{topologyAppLabel: name})
+				continue
+			}
+
+			// The apex service has no pods of its own, the traffic being split between its backends
+			topology.Services = append(topology.Services, NewServiceFixture(name, namespace, nil))
+			topology.endpoints[NewMeshServiceFixture(name, namespace)] = nil
+			trafficSplit := &split.TrafficSplit{
+				ObjectMeta: v1.ObjectMeta{Name: name, Namespace: namespace},
+				Spec:       split.TrafficSplitSpec{Service: name},
+			}
+			for k := 0; k < b.backendsPerSplit; k++ {
+				version := fmt.Sprintf("v%d", k)
+				backend := fmt.Sprintf("%s-%s", name, version)
+				topology.addService(namespace, backend, name, map[string]string{topologyAppLabel: name, topologyVersionLabel: version})
+				trafficSplit.Spec.Backends = append(trafficSplit.Spec.Backends, split.TrafficSplitBackend{Service: backend, Weight: 100 / b.backendsPerSplit})
+			}
+			topology.TrafficSplits = append(topology.TrafficSplits, trafficSplit)
+		}
+	}
+
+	if b.sourcesPerTarget == 0 {
+		return topology
+	}
+
+	sourcesPerTarget := b.sourcesPerTarget
+	if sourcesPerTarget > len(svcAccounts)-1 {
+		sourcesPerTarget = len(svcAccounts) - 1
+	}
+	for i, destination := range svcAccounts {
+		var sources []identity.K8sServiceAccount
+		for k := 1; k <= sourcesPerTarget; k++ {
+			sources = append(sources, svcAccounts[(i+k)%len(svcAccounts)])
+		}
+		topology.addTrafficTarget(destination, sources)
+	}
+
+	return topology
+}
+
+// addService adds a service of the topology, along with a pod of the given service account selected by the service
+func (t *Topology) addService(namespace, name, svcAccount string, labels map[string]string) {
+	t.Services = append(t.Services, NewServiceFixture(name, namespace, labels))
+	pod := NewPodFixture(namespace, fmt.Sprintf("%s-0", name), svcAccount, labels)
+	podIndex := len(t.Pods) + 1
+	pod.Status.PodIP = fmt.Sprintf("10.%d.%d.%d", podIndex/65536%256, podIndex/256%256, podIndex%256)
+	t.Pods = append(t.Pods, &pod)
+
+	meshService := NewMeshServiceFixture(name, namespace)
+	t.endpoints[meshService] = []endpoint.Endpoint{{IP: net.ParseIP(pod.Status.PodIP), Port: endpoint.Port(ServicePort)}}
+	svcIdentity := identity.K8sServiceAccount{Namespace: namespace, Name: svcAccount}.ToServiceIdentity()
+	t.identityServices[svcIdentity] = append(t.identityServices[svcIdentity], meshService)
+}
+
+// addTrafficTarget adds a TrafficTarget allowing the given sources to access the given destination, with its
+// HTTPRouteGroup matching any request
+func (t *Topology) addTrafficTarget(destination identity.K8sServiceAccount, sources []identity.K8sServiceAccount) {
+	routeGroup := &spec.HTTPRouteGroup{
+		TypeMeta: v1.TypeMeta{
+			APIVersion: "specs.smi-spec.io/v1alpha4",
+			Kind:       "HTTPRouteGroup",
+		},
+		ObjectMeta: v1.ObjectMeta{Name: destination.Name, Namespace: destination.Namespace},
+		Spec: spec.HTTPRouteGroupSpec{
+			Matches: []spec.HTTPMatch{{
+				Name:      topologyMatchName,
+				PathRegex: constants.RegexMatchAll,
+				Methods:   []string{constants.WildcardHTTPMethod},
+			}},
+		},
+	}
+	t.HTTPRouteGroups = append(t.HTTPRouteGroups, routeGroup)
+
+	trafficTarget := &access.TrafficTarget{
+		TypeMeta: v1.TypeMeta{
+			APIVersion: "access.smi-spec.io/v1alpha3",
+			Kind:       "TrafficTarget",
+		},
+		ObjectMeta: v1.ObjectMeta{Name: destination.Name, Namespace: destination.Namespace},
+		Spec: access.TrafficTargetSpec{
+			Destination: access.IdentityBindingSubject{
+				Kind:      "ServiceAccount",
+				Name:      destination.Name,
+				Namespace: destination.Namespace,
+			},
+			Rules: []access.TrafficTargetRule{{
+				Kind:    "HTTPRouteGroup",
+				Name:    routeGroup.Name,
+				Matches: []string{topologyMatchName},
+			}},
+		},
+	}
+	for _, source := range sources {
+		trafficTarget.Spec.Sources = append(trafficTarget.Spec.Sources, access.IdentityBindingSubject{
+			Kind:      "ServiceAccount",
+			Name:      source.Name,
+			Namespace: source.Namespace,
+		})
+	}
+	t.TrafficTargets = append(t.TrafficTargets, trafficTarget)
+}
+
+// KubeObjects returns the Kubernetes objects of the topology, to be served by a fake Kubernetes clientset
+func (t *Topology) KubeObjects() []runtime.Object {
+	var objects []runtime.Object
+	for _, ns := range t.Namespaces {
+		objects = append(objects, ns)
+	}
+	for _, svc := range t.Services {
+		objects = append(objects, svc)
+	}
+	for _, svcAccount := range t.ServiceAccounts {
+		objects = append(objects, svcAccount)
+	}
+	for _, pod := range t.Pods {
+		objects = append(objects, pod)
+	}
+	return objects
+}
+
+// MeshServices returns the services of the topology
+func (t *Topology) MeshServices() []service.MeshService {
+	var meshServices []service.MeshService
+	for _, svc := range t.Services {
+		meshServices = append(meshServices, NewMeshServiceFixture(svc.Name, svc.Namespace))
+	}
+	return meshServices
+}
+
+// ServiceIdentities returns the service identities of the service accounts of the topology
+func (t *Topology) ServiceIdentities() []identity.ServiceIdentity {
+	var identities []identity.ServiceIdentity
+	for _, svcAccount := range t.ServiceAccounts {
+		identities = append(identities, identity.K8sServiceAccount{Namespace: svcAccount.Namespace, Name: svcAccount.Name}.ToServiceIdentity())
+	}
+	return identities
+}
+
+// InboundTrafficTargetsWithRoutes returns the TrafficTargets of the topology whose destination is the given service
+// identity, as listed by the mesh catalog
+func (t *Topology) InboundTrafficTargetsWithRoutes(upstream identity.ServiceIdentity) []trafficpolicy.TrafficTargetWithRoutes {
+	var trafficTargets []trafficpolicy.TrafficTargetWithRoutes
+	for _, trafficTarget := range t.TrafficTargets {
+		destination := identity.K8sServiceAccount{Namespace: trafficTarget.Spec.Destination.Namespace, Name: trafficTarget.Spec.Destination.Name}
+		if destination.ToServiceIdentity() != upstream {
+			continue
+		}
+
+		trafficTargetWithRoutes := trafficpolicy.TrafficTargetWithRoutes{
+			Name:        fmt.Sprintf("%s/%s", trafficTarget.Namespace, trafficTarget.Name),
+			Destination: upstream,
+		}
+		for _, source := range trafficTarget.Spec.Sources {
+			sourceIdentity := identity.K8sServiceAccount{Namespace: source.Namespace, Name: source.Name}.ToServiceIdentity()
+			trafficTargetWithRoutes.Sources = append(trafficTargetWithRoutes.Sources, sourceIdentity)
+		}
+		trafficTargets = append(trafficTargets, trafficTargetWithRoutes)
+	}
+	return trafficTargets
+}
+
+// ServiceEndpoints returns the endpoints of the pods of the given service of the topology
+func (t *Topology) ServiceEndpoints(svc service.MeshService) []endpoint.Endpoint {
+	return t.endpoints[svc]
+}
+
+// ServicesForIdentity returns the services of the topology whose pods run with the given service identity
+func (t *Topology) ServicesForIdentity(svcIdentity identity.ServiceIdentity) []service.MeshService {
+	return t.identityServices[svcIdentity]
+}
+
+// OutboundTrafficPolicies returns an outbound traffic policy per service of the topology routing any request to the
+// service, as built by the mesh catalog in permissive traffic policy mode
+func (t *Topology) OutboundTrafficPolicies() []*trafficpolicy.OutboundTrafficPolicy {
+	var policies []*trafficpolicy.OutboundTrafficPolicy
+	for _, svc := range t.MeshServices() {
+		policy := trafficpolicy.NewOutboundTrafficPolicy(svc.FQDN(), t.ServiceHostnames(svc))
+		weightedCluster := service.WeightedCluster{ClusterName: service.ClusterName(svc.String()), Weight: constants.ClusterWeightAcceptAll}
+		if err := policy.AddRoute(trafficpolicy.WildCardRouteMatch, weightedCluster); err != nil {
+			continue
+		}
+		policies = append(policies, policy)
+	}
+	return policies
+}
+
+// InboundTrafficPolicies returns an inbound traffic policy per service of the given upstream service identity,
+// allowing the sources of the TrafficTargets of the topology whose destination is the service identity to send any
+// request to the service
+func (t *Topology) InboundTrafficPolicies(upstream identity.ServiceIdentity) []*trafficpolicy.InboundTrafficPolicy {
+	var policies []*trafficpolicy.InboundTrafficPolicy
+	trafficTargets := t.InboundTrafficTargetsWithRoutes(upstream)
+	for _, svc := range t.ServicesForIdentity(upstream) {
+		policy := trafficpolicy.NewInboundTrafficPolicy(svc.FQDN(), t.ServiceHostnames(svc))
+		weightedCluster := service.WeightedCluster{ClusterName: service.ClusterName(svc.String()), Weight: constants.ClusterWeightAcceptAll}
+		route := trafficpolicy.NewRouteWeightedCluster(trafficpolicy.WildCardRouteMatch, []service.WeightedCluster{weightedCluster})
+		for _, trafficTarget := range trafficTargets {
+			for _, source := range trafficTarget.Sources {
+				policy.AddRule(*route, source)
+			}
+		}
+		policies = append(policies, policy)
+	}
+	return policies
+}
+
+// ServiceHostnames returns the hostnames of the given service of the topology, as resolved from any namespace
+func (t *Topology) ServiceHostnames(svc service.MeshService) []string {
+	var hostnames []string
+	for _, host := range []string{
+		svc.Name,
+		fmt.Sprintf("%s.%s", svc.Name, svc.Namespace),
+		fmt.Sprintf("%s.%s.svc", svc.Name, svc.Namespace),
+		fmt.Sprintf("%s.%s.svc.cluster", svc.Name, svc.Namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", svc.Name, svc.Namespace),
+	} {
+		hostnames = append(hostnames, host, fmt.Sprintf("%s:%d", host, ServicePort))
+	}
+	return hostnames
+}
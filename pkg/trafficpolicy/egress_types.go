@@ -63,6 +63,11 @@ type EgressClusterConfig struct {
 
 	// Port defines the port number of the external cluster's endpoint
 	Port int
+
+	// ProxyProtocolVersion defines the version of the PROXY protocol header sent on the
+	// connections to the external cluster's endpoint. The header isn't sent if unspecified.
+	// +optional
+	ProxyProtocolVersion string
 }
 
 // EgressHTTPRouteConfig is the type used to represent an HTTP route configuration along with associated routing rules
@@ -0,0 +1,98 @@
+package trafficpolicy
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+const (
+	// grpcHTTPMethod is the HTTP method of all gRPC requests
+	grpcHTTPMethod = "POST"
+
+	// grpcAllMethodsWildcard stands for all the methods of a gRPC service in the name of a GRPCMethod
+	grpcAllMethodsWildcard = "*"
+)
+
+var (
+	// grpcServiceRegex matches the fully qualified name of a gRPC service, ex. hello.HelloService. The service must
+	// be qualified by its package, so that the paths of plain HTTP routes such as /api/v1 are not mistaken for gRPC
+	// paths.
+	grpcServiceRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)+$`)
+
+	// grpcMethodRegex matches the name of a gRPC method, ex. SayHello
+	grpcMethodRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// GRPCMethod is a method of a gRPC service, or all the methods of the service if Method is empty
+type GRPCMethod struct {
+	// Service is the fully qualified name of the gRPC service, ex. hello.HelloService
+	Service string
+
+	// Method is the name of the gRPC method, ex. SayHello
+	Method string
+}
+
+// ParseGRPCPath parses the path of an HTTP route of the form /<package>.<Service>/<Method> into the gRPC method it
+// matches. It returns false if the path is not the path of a single gRPC method. Paths matching several methods, such
+// as /<package>.<Service>/.*, are not gRPC paths, so that they keep their regex semantics.
+func ParseGRPCPath(path string) (GRPCMethod, bool) {
+	if !strings.HasPrefix(path, "/") {
+		return GRPCMethod{}, false
+	}
+
+	parts := strings.Split(path[1:], "/")
+	if len(parts) != 2 || !grpcServiceRegex.MatchString(parts[0]) || !grpcMethodRegex.MatchString(parts[1]) {
+		return GRPCMethod{}, false
+	}
+
+	return GRPCMethod{Service: parts[0], Method: parts[1]}, true
+}
+
+// AllowsGRPCRequests returns whether an HTTP route with the given methods allows gRPC requests, which are all POST
+// requests. All the methods are allowed if none is given.
+func AllowsGRPCRequests(methods []string) bool {
+	if len(methods) == 0 {
+		return true
+	}
+	for _, method := range methods {
+		if method == grpcHTTPMethod || method == constants.WildcardHTTPMethod {
+			return true
+		}
+	}
+	return false
+}
+
+// Path returns the path of the requests to the gRPC method, or the path prefix of the requests to all the methods of
+// the service if Method is empty
+func (m GRPCMethod) Path() string {
+	return "/" + m.Service + "/" + m.Method
+}
+
+// PathMatch returns the path and path match type of the HTTP route matching the requests to the gRPC method: an exact
+// match for a method, a prefix match for all the methods of a service.
+func (m GRPCMethod) PathMatch() (string, PathMatchType) {
+	if m.Method == "" {
+		return m.Path(), PathMatchPrefix
+	}
+	return m.Path(), PathMatchExact
+}
+
+// String returns the gRPC method in the form <package>.<Service>/<Method>
+func (m GRPCMethod) String() string {
+	if m.Method == "" {
+		return m.Service + "/" + grpcAllMethodsWildcard
+	}
+	return m.Service + "/" + m.Method
+}
+
+// NewGRPCRouteMatch returns the HTTP route match of the requests to the given gRPC method
+func NewGRPCRouteMatch(m GRPCMethod) HTTPRouteMatch {
+	path, pathMatchType := m.PathMatch()
+	return HTTPRouteMatch{
+		Path:          path,
+		PathMatchType: pathMatchType,
+		Methods:       []string{grpcHTTPMethod},
+	}
+}
@@ -0,0 +1,113 @@
+package trafficpolicy
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+)
+
+func TestParseGRPCPath(t *testing.T) {
+	testCases := []struct {
+		path               string
+		expectedOK         bool
+		expectedMethod     GRPCMethod
+		expectedMatchPath  string
+		expectedMatchType  PathMatchType
+		expectedMethodName string
+	}{
+		{
+			path:               "/hello.HelloService/SayHello",
+			expectedOK:         true,
+			expectedMethod:     GRPCMethod{Service: "hello.HelloService", Method: "SayHello"},
+			expectedMatchPath:  "/hello.HelloService/SayHello",
+			expectedMatchType:  PathMatchExact,
+			expectedMethodName: "hello.HelloService/SayHello",
+		},
+		{
+			path:               "/com.example.v1.Greeter/say_hello_2",
+			expectedOK:         true,
+			expectedMethod:     GRPCMethod{Service: "com.example.v1.Greeter", Method: "say_hello_2"},
+			expectedMatchPath:  "/com.example.v1.Greeter/say_hello_2",
+			expectedMatchType:  PathMatchExact,
+			expectedMethodName: "com.example.v1.Greeter/say_hello_2",
+		},
+		{
+			// Paths matching all the methods of a service keep their regex semantics
+			path: "/grpcbin.GRPCBin/*",
+		},
+		{
+			path: "/grpcbin.GRPCBin/.*",
+		},
+		{
+			path: "/grpcbin.GRPCBin/",
+		},
+		{
+			// Services must be qualified by their package
+			path: "/api/v1",
+		},
+		{
+			path: "/HelloService/SayHello",
+		},
+		{
+			path: "hello.HelloService/SayHello",
+		},
+		{
+			path: "/hello.HelloService",
+		},
+		{
+			path: "/hello.HelloService/SayHello/extra",
+		},
+		{
+			path: "/hello.HelloService/Say.*",
+		},
+		{
+			path: "/hello..HelloService/SayHello",
+		},
+		{
+			path: ".*",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			grpcMethod, ok := ParseGRPCPath(tc.path)
+			assert.Equal(tc.expectedOK, ok)
+			assert.Equal(tc.expectedMethod, grpcMethod)
+			if !ok {
+				return
+			}
+
+			path, pathMatchType := grpcMethod.PathMatch()
+			assert.Equal(tc.expectedMatchPath, path)
+			assert.Equal(tc.expectedMatchType, pathMatchType)
+			assert.Equal(tc.expectedMethodName, grpcMethod.String())
+		})
+	}
+}
+
+func TestAllowsGRPCRequests(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.True(AllowsGRPCRequests(nil))
+	assert.True(AllowsGRPCRequests([]string{"POST"}))
+	assert.True(AllowsGRPCRequests([]string{"GET", "*"}))
+	assert.False(AllowsGRPCRequests([]string{"GET", "PUT"}))
+}
+
+func TestNewGRPCRouteMatch(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.Equal(HTTPRouteMatch{
+		Path:          "/hello.HelloService/SayHello",
+		PathMatchType: PathMatchExact,
+		Methods:       []string{"POST"},
+	}, NewGRPCRouteMatch(GRPCMethod{Service: "hello.HelloService", Method: "SayHello"}))
+
+	assert.Equal(HTTPRouteMatch{
+		Path:          "/hello.HelloService/",
+		PathMatchType: PathMatchPrefix,
+		Methods:       []string{"POST"},
+	}, NewGRPCRouteMatch(GRPCMethod{Service: "hello.HelloService"}))
+}
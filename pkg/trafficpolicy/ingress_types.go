@@ -14,4 +14,73 @@ type IngressTrafficMatch struct {
 	SourceIPRanges           []string
 	ServerNames              []string
 	SkipClientCertValidation bool
+
+	// PreserveSourceIP indicates the source IP of the ingress clients is preserved up to the backend
+	PreserveSourceIP bool
+
+	// CertificateSecret is the <namespace>/<name> of the Kubernetes TLS secret holding the certificate presented to
+	// HTTPS ingress clients, the backend's service certificate being presented if unset
+	CertificateSecret string
+
+	// AcceptProxyProtocol indicates the connections to the port start with a PROXY protocol header carrying the
+	// address of the ingress clients
+	AcceptProxyProtocol bool
+
+	// EnableCSRF indicates the cross-site request forgery protection of the ingress security policies is applied to
+	// the requests on the port
+	EnableCSRF bool
+
+	// RewriteCookies indicates the cookie attributes of the ingress security policies are applied to the responses
+	// to the requests on the port
+	RewriteCookies bool
+
+	// ClientAddress is how the address of the ingress clients is determined for the requests on the port, the
+	// MeshConfig's configuration applying if unset
+	ClientAddress *ClientAddressPolicy
+}
+
+// ClientAddressPolicy is a struct to represent how the address of the clients of ingress requests is determined
+type ClientAddressPolicy struct {
+	// UseRemoteAddress is whether the remote address of the downstream connections is trusted as the client address
+	UseRemoteAddress bool
+
+	// XFFNumTrustedHops is the number of trusted proxies appending addresses to the X-Forwarded-For header
+	XFFNumTrustedHops uint32
+
+	// InternalUnixSockets is whether the clients connected over Unix domain sockets are considered internal
+	InternalUnixSockets bool
+}
+
+// IngressSecurityPolicy is a struct to represent the hardening of the ingress requests on a set of Hostnames and of
+// their responses
+type IngressSecurityPolicy struct {
+	// CSRF is the cross-site request forgery protection of the requests, disabled if unset
+	CSRF *CSRFPolicy `json:"csrf:omitempty"`
+
+	// ResponseHeaders is the list of security headers set on the responses, replacing existing values
+	ResponseHeaders []HTTPHeaderValue `json:"response_headers:omitempty"`
+
+	// Cookies is the attributes set on the cookies of the responses, the cookies being unchanged if unset
+	Cookies *CookieAttributes `json:"cookies:omitempty"`
+}
+
+// CSRFPolicy is a struct to represent the cross-site request forgery protection of ingress requests
+type CSRFPolicy struct {
+	// AdditionalOrigins is the list of origins allowed in addition to the destination of the requests
+	AdditionalOrigins []string `json:"additional_origins:omitempty"`
+
+	// ShadowMode is whether the requests with invalid origins are only counted instead of being rejected
+	ShadowMode bool `json:"shadow_mode:omitempty"`
+}
+
+// CookieAttributes is a struct to represent the attributes set on the cookies of HTTP responses
+type CookieAttributes struct {
+	// Secure is whether the Secure attribute is set
+	Secure bool `json:"secure:omitempty"`
+
+	// HTTPOnly is whether the HttpOnly attribute is set
+	HTTPOnly bool `json:"http_only:omitempty"`
+
+	// SameSite is the value of the SameSite attribute, replacing existing values, unchanged if empty
+	SameSite string `json:"same_site:omitempty"`
 }
@@ -3,6 +3,8 @@
 package trafficpolicy
 
 import (
+	"time"
+
 	mapset "github.com/deckarep/golang-set"
 
 	"github.com/openservicemesh/osm/pkg/identity"
@@ -43,15 +45,84 @@ type TCPRouteMatch struct {
 
 // RouteWeightedClusters is a struct of an HTTPRoute, associated weighted clusters and the domains
 type RouteWeightedClusters struct {
-	HTTPRouteMatch   HTTPRouteMatch `json:"http_route_match:omitempty"`
-	WeightedClusters mapset.Set     `json:"weighted_clusters:omitempty"`
+	HTTPRouteMatch   HTTPRouteMatch       `json:"http_route_match:omitempty"`
+	WeightedClusters mapset.Set           `json:"weighted_clusters:omitempty"`
+	Rewrite          *HTTPRouteRewrite    `json:"rewrite:omitempty"`
+	Headers          *HTTPHeaderMutations `json:"headers:omitempty"`
+}
+
+// HTTPHeaderMutations is a struct to represent the headers added to or removed from HTTP requests and responses
+type HTTPHeaderMutations struct {
+	// RequestHeadersToAdd is the list of headers added to the requests, replacing existing values
+	RequestHeadersToAdd []HTTPHeaderValue `json:"request_headers_to_add:omitempty"`
+
+	// RequestHeadersToRemove is the list of names of the headers removed from the requests
+	RequestHeadersToRemove []string `json:"request_headers_to_remove:omitempty"`
+
+	// ResponseHeadersToAdd is the list of headers added to the responses, replacing existing values
+	ResponseHeadersToAdd []HTTPHeaderValue `json:"response_headers_to_add:omitempty"`
+
+	// ResponseHeadersToRemove is the list of names of the headers removed from the responses
+	ResponseHeadersToRemove []string `json:"response_headers_to_remove:omitempty"`
+}
+
+// HTTPHeaderValue is a struct to represent an HTTP header
+type HTTPHeaderValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HTTPRouteRewrite is a struct to represent how the requests matching an HTTP route are rewritten before being forwarded
+type HTTPRouteRewrite struct {
+	// PrefixRewrite replaces the matched path prefix of the requests
+	PrefixRewrite string `json:"prefix_rewrite:omitempty"`
+
+	// RegexRewritePattern is the regex matched against the path of the requests, whose matches are replaced with
+	// RegexRewriteSubstitution
+	RegexRewritePattern string `json:"regex_rewrite_pattern:omitempty"`
+
+	// RegexRewriteSubstitution replaces the portions of the path of the requests matching RegexRewritePattern
+	RegexRewriteSubstitution string `json:"regex_rewrite_substitution:omitempty"`
+
+	// HostRewrite replaces the Host header of the requests
+	HostRewrite string `json:"host_rewrite:omitempty"`
+
+	// AutoHostRewrite replaces the Host header of the requests with the hostname of the upstream host
+	AutoHostRewrite bool `json:"auto_host_rewrite:omitempty"`
 }
 
 // InboundTrafficPolicy is a struct that associates incoming traffic on a set of Hostnames with a list of Rules
 type InboundTrafficPolicy struct {
-	Name      string   `json:"name:omitempty"`
-	Hostnames []string `json:"hostnames"`
-	Rules     []*Rule  `json:"rules:omitempty"`
+	Name      string               `json:"name:omitempty"`
+	Hostnames []string             `json:"hostnames"`
+	Rules     []*Rule              `json:"rules:omitempty"`
+	CORS      *CORSPolicy          `json:"cors:omitempty"`
+	Headers   *HTTPHeaderMutations `json:"headers:omitempty"`
+
+	// Security is the hardening of the ingress requests on the Hostnames and of their responses, unset for in-mesh
+	// traffic
+	Security *IngressSecurityPolicy `json:"security:omitempty"`
+}
+
+// CORSPolicy is a struct to represent the CORS policy applied to the requests on a set of Hostnames
+type CORSPolicy struct {
+	// AllowOrigins is the list of origins allowed to make cross-origin requests, '*' allowing any origin
+	AllowOrigins []string `json:"allow_origins:omitempty"`
+
+	// AllowMethods is the list of HTTP methods allowed in cross-origin requests
+	AllowMethods []string `json:"allow_methods:omitempty"`
+
+	// AllowHeaders is the list of HTTP headers allowed in cross-origin requests
+	AllowHeaders []string `json:"allow_headers:omitempty"`
+
+	// ExposeHeaders is the list of HTTP headers of the responses exposed to the clients
+	ExposeHeaders []string `json:"expose_headers:omitempty"`
+
+	// MaxAge is how long the responses to preflight requests can be cached by the clients, unset if 0
+	MaxAge time.Duration `json:"max_age:omitempty"`
+
+	// AllowCredentials is whether the clients can send credentials in cross-origin requests
+	AllowCredentials bool `json:"allow_credentials:omitempty"`
 }
 
 // Rule is a struct that represents which service identities (authenticated principals) can access a Route
@@ -65,6 +136,9 @@ type OutboundTrafficPolicy struct {
 	Name      string                   `json:"name:omitempty"`
 	Hostnames []string                 `json:"hostnames"`
 	Routes    []*RouteWeightedClusters `json:"routes:omitempty"`
+
+	// RetryPolicy is the retry policy of the upstream service of the policy, applied to all its routes
+	RetryPolicy *RetryPolicy `json:"retry_policy:omitempty"`
 }
 
 // TrafficTargetWithRoutes is a struct to represent an SMI TrafficTarget resource composed of its associated routes
@@ -74,3 +148,40 @@ type TrafficTargetWithRoutes struct {
 	Sources         []identity.ServiceIdentity `json:"sources:omitempty"`
 	TCPRouteMatches []TCPRouteMatch            `json:"tcp_route_matches:omitempty"`
 }
+
+// UpstreamPeerValidation is a struct to represent how the certificates presented by an upstream service are validated
+type UpstreamPeerValidation struct {
+	// TrustDomainAliases are the trust domains, other than the cluster's own, the certificates of the upstream
+	// service's identities are accepted from
+	TrustDomainAliases []string `json:"trust_domain_aliases:omitempty"`
+
+	// SubjectAltNames are the SANs accepted in the certificates of the upstream service, in addition to those of its
+	// identities
+	SubjectAltNames []string `json:"subject_alt_names:omitempty"`
+}
+
+// RetryBudget is a struct to represent the budget of the concurrent retries to an upstream service
+type RetryBudget struct {
+	// BudgetPercent is the maximum percentage of the active requests to the upstream service that can be retries
+	BudgetPercent uint32 `json:"budget_percent:omitempty"`
+
+	// MinRetryConcurrency is the number of concurrent retries allowed whatever the number of active requests
+	MinRetryConcurrency uint32 `json:"min_retry_concurrency:omitempty"`
+}
+
+// RetryPolicy is a struct to represent how the requests to an upstream service are retried
+type RetryPolicy struct {
+	// RetryOn is the comma-separated list of the conditions the requests are retried on
+	RetryOn string `json:"retry_on:omitempty"`
+
+	// NumRetries is the maximum number of retries of a request
+	NumRetries uint32 `json:"num_retries:omitempty"`
+
+	// PerTryTimeout is the timeout of each try of a request, or 0 if the tries are only bounded by the timeout of
+	// the request
+	PerTryTimeout time.Duration `json:"per_try_timeout:omitempty"`
+
+	// HedgeOnPerTryTimeout indicates whether the requests of idempotent HTTP methods whose try exceeds the per-try
+	// timeout are retried without cancelling the outstanding try
+	HedgeOnPerTryTimeout bool `json:"hedge_on_per_try_timeout:omitempty"`
+}
@@ -0,0 +1,292 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	"github.com/openservicemesh/osm/pkg/configurator"
+	"github.com/openservicemesh/osm/pkg/logger"
+)
+
+// envoyLogLevels are the log levels of the proxy sidecars
+var envoyLogLevels = []string{"trace", "debug", "info", "warning", "warn", "error", "critical", "off"}
+
+// newMeshConfigValidator returns a validateFunc validating the MeshConfig of the given OSM namespace. The webhook
+// does not select the OSM namespace, whose kubernetes.io/metadata.name label is missing before Kubernetes 1.21, so the
+// MeshConfigs of other namespaces, such as those of other meshes, are allowed without being validated.
+func newMeshConfigValidator(osmNamespace string) validateFunc {
+	return func(req *admissionv1.AdmissionRequest) (*admissionv1.AdmissionResponse, error) {
+		if req.Namespace != osmNamespace {
+			return nil, nil
+		}
+		return meshConfigValidator(req)
+	}
+}
+
+// meshConfigValidator validates the MeshConfig custom resource, so that an invalid edit is rejected rather than
+// silently replaced by defaults or breaking the mesh. The use of deprecated fields is allowed and reported as
+// warnings. The MeshConfig is validated as the v1alpha1 spec the control plane works with, whatever the version of
+// the request.
+func meshConfigValidator(req *admissionv1.AdmissionRequest) (*admissionv1.AdmissionResponse, error) {
+	var spec configv1alpha1.MeshConfigSpec
+	var warnings []string
+	switch req.Kind.Version {
+	case configv1alpha1.SchemeGroupVersion.Version:
+		meshConfig := &configv1alpha1.MeshConfig{}
+		if err := json.NewDecoder(bytes.NewBuffer(req.Object.Raw)).Decode(meshConfig); err != nil {
+			return nil, err
+		}
+		spec = meshConfig.Spec
+		warnings = append(warnings, getV1alpha1MeshConfigWarnings(spec)...)
+
+	case configv1alpha2.SchemeGroupVersion.Version:
+		meshConfig := &configv1alpha2.MeshConfig{}
+		if err := json.NewDecoder(bytes.NewBuffer(req.Object.Raw)).Decode(meshConfig); err != nil {
+			return nil, err
+		}
+		spec = configv1alpha2.ConvertToV1alpha1(meshConfig).Spec
+
+	default:
+		return nil, errors.Errorf("Unsupported MeshConfig version %s", req.Kind.Version)
+	}
+
+	if err := validateMeshConfigSpec(spec); err != nil {
+		return nil, err
+	}
+	warnings = append(warnings, getMeshConfigWarnings(spec)...)
+
+	if len(warnings) == 0 {
+		return nil, nil
+	}
+	return &admissionv1.AdmissionResponse{Allowed: true, Warnings: warnings}, nil
+}
+
+// validateMeshConfigSpec validates the settings of the given MeshConfig spec
+func validateMeshConfigSpec(spec configv1alpha1.MeshConfigSpec) error {
+	// Log levels
+	if spec.Sidecar.LogLevel != "" && !containsString(envoyLogLevels, strings.ToLower(spec.Sidecar.LogLevel)) {
+		return errors.Errorf("Expected 'sidecar.logLevel' to be one of %v, got: %s", envoyLogLevels, spec.Sidecar.LogLevel)
+	}
+	if spec.Observability.OSMLogLevel != "" && !containsString(logger.AllowedLevels, strings.ToLower(spec.Observability.OSMLogLevel)) {
+		return errors.Errorf("Expected 'observability.osmLogLevel' to be one of %v, got: %s", logger.AllowedLevels, spec.Observability.OSMLogLevel)
+	}
+
+	// Timeouts and intervals
+	durations := []durationSetting{
+		{field: "sidecar.configResyncInterval", value: spec.Sidecar.ConfigResyncInterval},
+		{field: "traffic.inboundExternalAuthorization.timeout", value: spec.Traffic.InboundExternalAuthorization.Timeout, min: time.Millisecond},
+		{field: "traffic.protocolDetectionTimeout", value: spec.Traffic.ProtocolDetectionTimeout, min: configurator.MinProtocolDetectionTimeout, max: configurator.MaxProtocolDetectionTimeout},
+		{field: "traffic.endpointFlapDampening.window", value: spec.Traffic.EndpointFlapDampening.Window, min: time.Second},
+		{field: "traffic.dnsResolution.refreshRate", value: spec.Traffic.DNSResolution.RefreshRate, min: time.Millisecond},
+		{field: "traffic.downstreamLimits.idleTimeout", value: spec.Traffic.DownstreamLimits.IdleTimeout, min: time.Millisecond},
+		{field: "traffic.downstreamLimits.requestTimeout", value: spec.Traffic.DownstreamLimits.RequestTimeout, min: time.Millisecond},
+		{field: "traffic.downstreamLimits.requestHeadersTimeout", value: spec.Traffic.DownstreamLimits.RequestHeadersTimeout, min: time.Millisecond},
+		{field: "traffic.downstreamLimits.streamIdleTimeout", value: spec.Traffic.DownstreamLimits.StreamIdleTimeout, min: time.Millisecond},
+		{field: "observability.prometheusScraping.scrapeInterval", value: spec.Observability.PrometheusScraping.ScrapeInterval, min: time.Second},
+		{field: "certificate.serviceCertValidityDuration", value: spec.Certificate.ServiceCertValidityDuration, min: time.Minute},
+		{field: "performance.broadcastGracePeriod", value: spec.Performance.BroadcastGracePeriod, min: time.Millisecond},
+		{field: "performance.maxBroadcastDelay", value: spec.Performance.MaxBroadcastDelay, min: time.Millisecond},
+		{field: "performance.informerResyncInterval", value: spec.Performance.InformerResyncInterval, min: time.Millisecond},
+		{field: "performance.informerWatchErrorBackoff", value: spec.Performance.InformerWatchErrorBackoff, min: time.Millisecond},
+		{field: "performance.informerWatchErrorMaxBackoff", value: spec.Performance.InformerWatchErrorMaxBackoff, min: time.Millisecond},
+	}
+	if spec.Certificate.IngressGateway != nil {
+		durations = append(durations, durationSetting{field: "certificate.ingressGateway.validityDuration", value: spec.Certificate.IngressGateway.ValidityDuration, min: time.Minute})
+	}
+	for informer, value := range spec.Performance.InformerResyncIntervals {
+		durations = append(durations, durationSetting{field: fmt.Sprintf("performance.informerResyncIntervals.%s", informer), value: value, min: time.Millisecond})
+	}
+	for _, d := range durations {
+		if err := d.validate(); err != nil {
+			return errors.Wrapf(err, "Invalid '%s'", d.field)
+		}
+	}
+
+	switch spec.Performance.Profile {
+	case "", configurator.PerformanceProfileSmall, configurator.PerformanceProfileMedium, configurator.PerformanceProfileLarge:
+	default:
+		return errors.Errorf("Expected 'performance.profile' to be one of [%s %s %s], got: %s",
+			configurator.PerformanceProfileSmall, configurator.PerformanceProfileMedium, configurator.PerformanceProfileLarge, spec.Performance.Profile)
+	}
+
+	if err := validateMeshConfigTracing(spec.Observability.Tracing); err != nil {
+		return err
+	}
+
+	extAuthz := spec.Traffic.InboundExternalAuthorization
+	if extAuthz.Enable && (extAuthz.Address == "" || extAuthz.Port == 0) {
+		return errors.New("'traffic.inboundExternalAuthorization.address' and 'traffic.inboundExternalAuthorization.port' must be specified when external authorization is enabled")
+	}
+
+	return validateMeshConfigCertificate(spec.Certificate)
+}
+
+// validateMeshConfigTracing validates the tracing settings of a MeshConfig. The port of the tracing collector is
+// specified in 'port' alone, the address being a host.
+func validateMeshConfigTracing(tracing configv1alpha1.TracingSpec) error {
+	if tracing.Port < 0 {
+		return errors.Errorf("Expected 'observability.tracing.port' to be in the range 1-32767, got: %d", tracing.Port)
+	}
+	if strings.Contains(tracing.Address, "://") {
+		return errors.Errorf("Expected 'observability.tracing.address' to be a host without scheme, got: %s", tracing.Address)
+	}
+	if _, port, err := net.SplitHostPort(tracing.Address); err == nil {
+		return errors.Errorf("'observability.tracing.address' %s cannot specify port %s, which must be specified in 'observability.tracing.port'", tracing.Address, port)
+	}
+	if tracing.Endpoint != "" && !strings.HasPrefix(tracing.Endpoint, "/") {
+		return errors.Errorf("Expected 'observability.tracing.endpoint' to start with '/', got: %s", tracing.Endpoint)
+	}
+	return nil
+}
+
+// validateMeshConfigCertificate validates the consistency of the certificate settings of a MeshConfig
+func validateMeshConfigCertificate(cert configv1alpha1.CertificateSpec) error {
+	switch cert.KeyAlgorithm {
+	case "", certificate.KeyAlgorithmRSA:
+		if cert.CertKeyBitSize < configurator.MinCertKeyBitSize || cert.CertKeyBitSize > configurator.MaxCertKeyBitSize {
+			return errors.Errorf("Expected 'certificate.certKeyBitSize' of RSA keys to be in the range %d-%d, got: %d",
+				configurator.MinCertKeyBitSize, configurator.MaxCertKeyBitSize, cert.CertKeyBitSize)
+		}
+	case certificate.KeyAlgorithmECDSA:
+		// The key size of ECDSA P-256 keys is fixed
+	default:
+		return errors.Errorf("Expected 'certificate.keyAlgorithm' to be '%s' or '%s', got: %s", certificate.KeyAlgorithmRSA, certificate.KeyAlgorithmECDSA, cert.KeyAlgorithm)
+	}
+
+	switch cert.SubjectAltNameFormat {
+	case "", certificate.SubjectAltNameFormatDNS, certificate.SubjectAltNameFormatDNSSPIFFE:
+	default:
+		return errors.Errorf("Expected 'certificate.subjectAltNameFormat' to be '%s' or '%s', got: %s",
+			certificate.SubjectAltNameFormatDNS, certificate.SubjectAltNameFormatDNSSPIFFE, cert.SubjectAltNameFormat)
+	}
+
+	if cert.TrustDomain != "" {
+		if errs := validation.IsDNS1123Subdomain(cert.TrustDomain); len(errs) > 0 {
+			return errors.Errorf("Invalid 'certificate.trustDomain' %s: %s", cert.TrustDomain, strings.Join(errs, ", "))
+		}
+	}
+	for _, alias := range cert.TrustDomainAliases {
+		if errs := validation.IsDNS1123Subdomain(alias); len(errs) > 0 {
+			return errors.Errorf("Invalid trust domain %s in 'certificate.trustDomainAliases': %s", alias, strings.Join(errs, ", "))
+		}
+		if alias == cert.TrustDomain {
+			return errors.Errorf("Trust domain %s in 'certificate.trustDomainAliases' is the trust domain of the mesh", alias)
+		}
+	}
+
+	federatedTrustDomains := make(map[string]bool)
+	for _, federated := range cert.FederatedTrustDomains {
+		if federated.TrustDomain == "" || federated.TrustDomain == cert.TrustDomain {
+			return errors.Errorf("Invalid trust domain '%s' in 'certificate.federatedTrustDomains', expected an external trust domain", federated.TrustDomain)
+		}
+		if federatedTrustDomains[federated.TrustDomain] {
+			return errors.Errorf("Trust domain %s is specified more than once in 'certificate.federatedTrustDomains'", federated.TrustDomain)
+		}
+		federatedTrustDomains[federated.TrustDomain] = true
+		if federated.TrustBundle == "" {
+			return errors.Errorf("'trustBundle' of trust domain %s in 'certificate.federatedTrustDomains' must be specified", federated.TrustDomain)
+		}
+	}
+
+	if cert.IngressGateway != nil {
+		if len(cert.IngressGateway.SubjectAltNames) == 0 {
+			return errors.New("At least one SAN must be specified in 'certificate.ingressGateway.subjectAltNames'")
+		}
+		if cert.IngressGateway.Secret.Name == "" {
+			return errors.New("'certificate.ingressGateway.secret.name' must be specified")
+		}
+	}
+
+	return nil
+}
+
+// getMeshConfigWarnings returns the warnings about the settings of the given MeshConfig spec that are deprecated or
+// have no effect
+func getMeshConfigWarnings(spec configv1alpha1.MeshConfigSpec) []string {
+	var warnings []string
+	if spec.FeatureFlags.EnableValidatingWebhook {
+		warnings = append(warnings, "'featureFlags.enableValidatingWebhook' is deprecated and has no effect, the validating webhook is always enabled")
+	}
+
+	tracing := spec.Observability.Tracing
+	if !tracing.Enable && (tracing.Address != "" || tracing.Port != 0 || tracing.Endpoint != "") {
+		warnings = append(warnings, "'observability.tracing' settings have no effect while 'observability.tracing.enable' is false")
+	}
+
+	extAuthz := spec.Traffic.InboundExternalAuthorization
+	if !extAuthz.Enable && (extAuthz.Address != "" || extAuthz.Port != 0) {
+		warnings = append(warnings, "'traffic.inboundExternalAuthorization' settings have no effect while 'traffic.inboundExternalAuthorization.enable' is false")
+	}
+
+	return warnings
+}
+
+// getV1alpha1MeshConfigWarnings returns the warnings about the fields of the given v1alpha1 MeshConfig spec that are
+// deprecated in favor of their v1alpha2 counterparts
+func getV1alpha1MeshConfigWarnings(spec configv1alpha1.MeshConfigSpec) []string {
+	var warnings []string
+	deprecatedFields := []struct {
+		field string
+		isSet bool
+	}{
+		{field: "compression", isSet: !reflect.DeepEqual(spec.Traffic.Compression, configv1alpha1.CompressionSpec{})},
+		{field: "requestLimits", isSet: spec.Traffic.RequestLimits != configv1alpha1.RequestLimitsSpec{}},
+		{field: "clientCertDetails", isSet: spec.Traffic.ClientCertDetails != configv1alpha1.ClientCertDetailsSpec{}},
+	}
+	for _, deprecated := range deprecatedFields {
+		if deprecated.isSet {
+			warnings = append(warnings, fmt.Sprintf("%s MeshConfig 'traffic.%s' is deprecated, use 'traffic.inboundHTTP.%s' of %s",
+				configv1alpha1.SchemeGroupVersion, deprecated.field, deprecated.field, configv1alpha2.SchemeGroupVersion))
+		}
+	}
+	return warnings
+}
+
+// durationSetting is a duration setting of a MeshConfig, whose value must be within [min, max] when set. No maximum
+// is enforced if max is 0.
+type durationSetting struct {
+	field    string
+	value    string
+	min, max time.Duration
+}
+
+// validate validates the value of the duration setting
+func (d durationSetting) validate() error {
+	if d.value == "" {
+		return nil
+	}
+	duration, err := time.ParseDuration(d.value)
+	if err != nil {
+		return err
+	}
+	if d.max > 0 && (duration < d.min || duration > d.max) {
+		return errors.Errorf("Expected a duration between %s and %s, got: %s", d.min, d.max, d.value)
+	}
+	if duration < d.min {
+		return errors.Errorf("Expected a duration of at least %s, got: %s", d.min, d.value)
+	}
+	return nil
+}
+
+// containsString returns whether the given slice contains the given string
+func containsString(slice []string, s string) bool {
+	for _, item := range slice {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
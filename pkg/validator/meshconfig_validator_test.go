@@ -0,0 +1,228 @@
+package validator
+
+import (
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestMeshConfigValidator(t *testing.T) {
+	const validCertificate = `"certificate": {"serviceCertValidityDuration": "24h", "certKeyBitSize": 2048}`
+
+	testCases := []struct {
+		name        string
+		version     string
+		spec        string
+		expErrStr   string
+		expWarnings []string
+	}{
+		{
+			name:    "MeshConfig with a valid spec passes",
+			version: "v1alpha2",
+			spec: `{
+				"sidecar": {"logLevel": "error", "configResyncInterval": "60s"},
+				"traffic": {"protocolDetectionTimeout": "250ms", "inboundExternalAuthorization": {"enable": true, "address": "authz.example.com", "port": 9191, "timeout": "1s"}},
+				"observability": {"osmLogLevel": "info", "tracing": {"enable": true, "address": "jaeger.osm-system.svc.cluster.local", "port": 9411, "endpoint": "/api/v2/spans"}},
+				` + validCertificate + `,
+				"performance": {"profile": "medium", "broadcastGracePeriod": "2s"}
+			}`,
+		},
+		{
+			name:    "v1alpha1 MeshConfig with a valid spec passes",
+			version: "v1alpha1",
+			spec:    `{` + validCertificate + `}`,
+		},
+		{
+			name:      "MeshConfig with an invalid sidecar log level fails",
+			version:   "v1alpha2",
+			spec:      `{"sidecar": {"logLevel": "verbose"}, ` + validCertificate + `}`,
+			expErrStr: "Expected 'sidecar.logLevel' to be one of",
+		},
+		{
+			name:      "MeshConfig with an invalid OSM log level fails",
+			version:   "v1alpha2",
+			spec:      `{"observability": {"osmLogLevel": "critical"}, ` + validCertificate + `}`,
+			expErrStr: "Expected 'observability.osmLogLevel' to be one of",
+		},
+		{
+			name:      "MeshConfig with an invalid duration fails",
+			version:   "v1alpha2",
+			spec:      `{"sidecar": {"configResyncInterval": "60"}, ` + validCertificate + `}`,
+			expErrStr: "Invalid 'sidecar.configResyncInterval'",
+		},
+		{
+			name:      "MeshConfig with an out of range protocol detection timeout fails",
+			version:   "v1alpha2",
+			spec:      `{"traffic": {"protocolDetectionTimeout": "5s"}, ` + validCertificate + `}`,
+			expErrStr: "Invalid 'traffic.protocolDetectionTimeout': Expected a duration between 100ms and 1s, got: 5s",
+		},
+		{
+			name:      "MeshConfig with an invalid downstream request timeout fails",
+			version:   "v1alpha2",
+			spec:      `{"traffic": {"downstreamLimits": {"maxConnections": 1024, "requestTimeout": "30"}}, ` + validCertificate + `}`,
+			expErrStr: "Invalid 'traffic.downstreamLimits.requestTimeout'",
+		},
+		{
+			name:      "MeshConfig with a too short Prometheus scrape interval fails",
+			version:   "v1alpha2",
+			spec:      `{"observability": {"prometheusScraping": {"generateMonitors": true, "scrapeInterval": "100ms"}}, ` + validCertificate + `}`,
+			expErrStr: "Invalid 'observability.prometheusScraping.scrapeInterval': Expected a duration of at least 1s, got: 100ms",
+		},
+		{
+			name:      "MeshConfig with a too short service certificate validity fails",
+			version:   "v1alpha2",
+			spec:      `{"certificate": {"serviceCertValidityDuration": "30s", "certKeyBitSize": 2048}}`,
+			expErrStr: "Invalid 'certificate.serviceCertValidityDuration': Expected a duration of at least 1m0s, got: 30s",
+		},
+		{
+			name:      "MeshConfig with an unknown performance profile fails",
+			version:   "v1alpha2",
+			spec:      `{"performance": {"profile": "huge"}, ` + validCertificate + `}`,
+			expErrStr: "Expected 'performance.profile' to be one of [small medium large], got: huge",
+		},
+		{
+			name:      "MeshConfig with a tracing address specifying a port fails",
+			version:   "v1alpha2",
+			spec:      `{"observability": {"tracing": {"enable": true, "address": "jaeger:9411", "port": 9411}}, ` + validCertificate + `}`,
+			expErrStr: "'observability.tracing.address' jaeger:9411 cannot specify port 9411",
+		},
+		{
+			name:      "MeshConfig with a tracing address specifying a scheme fails",
+			version:   "v1alpha2",
+			spec:      `{"observability": {"tracing": {"enable": true, "address": "http://jaeger"}}, ` + validCertificate + `}`,
+			expErrStr: "Expected 'observability.tracing.address' to be a host without scheme",
+		},
+		{
+			name:      "MeshConfig with a relative tracing endpoint fails",
+			version:   "v1alpha2",
+			spec:      `{"observability": {"tracing": {"enable": true, "endpoint": "api/v2/spans"}}, ` + validCertificate + `}`,
+			expErrStr: "Expected 'observability.tracing.endpoint' to start with '/'",
+		},
+		{
+			name:      "MeshConfig with external authorization enabled without address fails",
+			version:   "v1alpha2",
+			spec:      `{"traffic": {"inboundExternalAuthorization": {"enable": true, "port": 9191}}, ` + validCertificate + `}`,
+			expErrStr: "must be specified when external authorization is enabled",
+		},
+		{
+			name:      "MeshConfig with an out of range RSA key size fails",
+			version:   "v1alpha2",
+			spec:      `{"certificate": {"serviceCertValidityDuration": "24h", "certKeyBitSize": 1024}}`,
+			expErrStr: "Expected 'certificate.certKeyBitSize' of RSA keys to be in the range 2048-4096, got: 1024",
+		},
+		{
+			name:    "MeshConfig with ECDSA keys ignores the key size",
+			version: "v1alpha2",
+			spec:    `{"certificate": {"serviceCertValidityDuration": "24h", "certKeyBitSize": 1024, "keyAlgorithm": "ecdsa"}}`,
+		},
+		{
+			name:      "MeshConfig with a trust domain alias identical to the trust domain fails",
+			version:   "v1alpha2",
+			spec:      `{"certificate": {"serviceCertValidityDuration": "24h", "certKeyBitSize": 2048, "trustDomain": "cluster.local", "trustDomainAliases": ["cluster.local"]}}`,
+			expErrStr: "Trust domain cluster.local in 'certificate.trustDomainAliases' is the trust domain of the mesh",
+		},
+		{
+			name:      "MeshConfig with a federated trust domain without trust bundle fails",
+			version:   "v1alpha2",
+			spec:      `{"certificate": {"serviceCertValidityDuration": "24h", "certKeyBitSize": 2048, "federatedTrustDomains": [{"trustDomain": "example.com", "trustBundle": ""}]}}`,
+			expErrStr: "'trustBundle' of trust domain example.com in 'certificate.federatedTrustDomains' must be specified",
+		},
+		{
+			name:      "MeshConfig with an ingress gateway certificate without secret fails",
+			version:   "v1alpha2",
+			spec:      `{"certificate": {"serviceCertValidityDuration": "24h", "certKeyBitSize": 2048, "ingressGateway": {"subjectAltNames": ["ingress.osm-system.cluster.local"], "validityDuration": "24h", "secret": {}}}}`,
+			expErrStr: "'certificate.ingressGateway.secret.name' must be specified",
+		},
+		{
+			name:        "MeshConfig with settings having no effect passes with warnings",
+			version:     "v1alpha2",
+			spec:        `{"observability": {"tracing": {"enable": false, "port": 9411}}, "featureFlags": {"enableValidatingWebhook": true}, ` + validCertificate + `}`,
+			expWarnings: []string{"'featureFlags.enableValidatingWebhook' is deprecated and has no effect, the validating webhook is always enabled", "'observability.tracing' settings have no effect while 'observability.tracing.enable' is false"},
+		},
+		{
+			name:        "v1alpha1 MeshConfig with deprecated fields passes with warnings",
+			version:     "v1alpha1",
+			spec:        `{"traffic": {"requestLimits": {"maxRequestBytes": 1024}}, ` + validCertificate + `}`,
+			expWarnings: []string{"config.openservicemesh.io/v1alpha1 MeshConfig 'traffic.requestLimits' is deprecated, use 'traffic.inboundHTTP.requestLimits' of config.openservicemesh.io/v1alpha2"},
+		},
+		{
+			name:      "MeshConfig of an unknown version fails",
+			version:   "v1",
+			spec:      `{}`,
+			expErrStr: "Unsupported MeshConfig version v1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			req := &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "config.openservicemesh.io",
+					Version: tc.version,
+					Kind:    "MeshConfig",
+				},
+				Namespace: "osm-system",
+				Object: runtime.RawExtension{
+					Raw: []byte(`{"apiVersion": "config.openservicemesh.io/` + tc.version + `", "kind": "MeshConfig", "spec": ` + tc.spec + `}`),
+				},
+			}
+
+			resp, err := meshConfigValidator(req)
+			if tc.expErrStr != "" {
+				assert.Nil(resp)
+				assert.NotNil(err)
+				assert.Contains(err.Error(), tc.expErrStr)
+				return
+			}
+			assert.Nil(err)
+			if len(tc.expWarnings) == 0 {
+				assert.Nil(resp)
+				return
+			}
+			assert.True(resp.Allowed)
+			assert.Equal(tc.expWarnings, resp.Warnings)
+		})
+	}
+}
+
+func TestNewMeshConfigValidator(t *testing.T) {
+	invalidMeshConfig := []byte(`{"apiVersion": "config.openservicemesh.io/v1alpha2", "kind": "MeshConfig", "spec": {"sidecar": {"logLevel": "verbose"}}}`)
+
+	testCases := []struct {
+		name      string
+		namespace string
+		expectErr bool
+	}{
+		{
+			name:      "MeshConfig of the OSM namespace is validated",
+			namespace: "osm-system",
+			expectErr: true,
+		},
+		{
+			name:      "MeshConfig of another namespace is allowed",
+			namespace: "other-osm-system",
+			expectErr: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			req := &admissionv1.AdmissionRequest{
+				Kind:      metav1.GroupVersionKind{Group: "config.openservicemesh.io", Version: "v1alpha2", Kind: "MeshConfig"},
+				Namespace: tc.namespace,
+				Object:    runtime.RawExtension{Raw: invalidMeshConfig},
+			}
+
+			resp, err := newMeshConfigValidator("osm-system")(req)
+			assert.Nil(resp)
+			assert.Equal(tc.expectErr, err != nil)
+		})
+	}
+}
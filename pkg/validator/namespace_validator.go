@@ -0,0 +1,67 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+const (
+	// NamespaceRemovalPolicyWarn allows removing a namespace running pods with sidecars from the mesh, with a warning
+	NamespaceRemovalPolicyWarn = "warn"
+
+	// NamespaceRemovalPolicyBlock rejects removing a namespace running pods with sidecars from the mesh
+	NamespaceRemovalPolicyBlock = "block"
+)
+
+// newNamespaceValidator returns the validator of the Namespaces removed from the given mesh. The sidecars of the pods
+// of a namespace removed from the mesh are orphaned from the control plane until the pods are restarted, so the
+// removal of a namespace running pods with sidecars is warned about or rejected depending on the given policy.
+func newNamespaceValidator(kubeClient kubernetes.Interface, meshName string, removalPolicy string) validateFunc {
+	return func(req *admissionv1.AdmissionRequest) (*admissionv1.AdmissionResponse, error) {
+		if req.Operation != admissionv1.Update {
+			return nil, nil
+		}
+
+		oldNs, newNs := &corev1.Namespace{}, &corev1.Namespace{}
+		if err := json.NewDecoder(bytes.NewBuffer(req.OldObject.Raw)).Decode(oldNs); err != nil {
+			return nil, err
+		}
+		if err := json.NewDecoder(bytes.NewBuffer(req.Object.Raw)).Decode(newNs); err != nil {
+			return nil, err
+		}
+		if oldNs.Labels[constants.OSMKubeResourceMonitorAnnotation] != meshName || newNs.Labels[constants.OSMKubeResourceMonitorAnnotation] == meshName {
+			return nil, nil
+		}
+		// The pods of a namespace being deleted are deleted along with it
+		if newNs.DeletionTimestamp != nil {
+			return nil, nil
+		}
+
+		pods, err := kubeClient.CoreV1().Pods(newNs.Name).List(context.Background(), metav1.ListOptions{
+			LabelSelector: constants.EnvoyUniqueIDLabelName,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error listing the pods with sidecars of namespace %s", newNs.Name)
+		}
+		if len(pods.Items) == 0 {
+			return nil, nil
+		}
+
+		message := fmt.Sprintf("Namespace %s runs %d pods with sidecars, which are orphaned from mesh %s until they are restarted once the namespace is removed from the mesh",
+			newNs.Name, len(pods.Items), meshName)
+		if removalPolicy == NamespaceRemovalPolicyBlock {
+			return nil, errors.Errorf("%s; disable sidecar injection and restart the pods, or delete them, before removing the namespace from the mesh", message)
+		}
+		return &admissionv1.AdmissionResponse{Allowed: true, Warnings: []string{message}}, nil
+	}
+}
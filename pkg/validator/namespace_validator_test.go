@@ -0,0 +1,129 @@
+package validator
+
+import (
+	"encoding/json"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+)
+
+func TestNamespaceValidator(t *testing.T) {
+	newNamespace := func(meshName string, deleted bool) *corev1.Namespace {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-a", Labels: map[string]string{}}}
+		if meshName != "" {
+			ns.Labels[constants.OSMKubeResourceMonitorAnnotation] = meshName
+		}
+		if deleted {
+			now := metav1.Now()
+			ns.DeletionTimestamp = &now
+		}
+		return ns
+	}
+	podWithSidecar := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "bookstore",
+		Namespace: "tenant-a",
+		Labels:    map[string]string{constants.EnvoyUniqueIDLabelName: "proxy-uuid"},
+	}}
+	podWithoutSidecar := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "bookbuyer", Namespace: "tenant-a"}}
+
+	testCases := []struct {
+		name          string
+		removalPolicy string
+		operation     admissionv1.Operation
+		oldNs         *corev1.Namespace
+		newNs         *corev1.Namespace
+		pods          []runtime.Object
+		expErrStr     string
+		expWarning    bool
+	}{
+		{
+			name:          "removal of a namespace running pods with sidecars is warned about",
+			removalPolicy: NamespaceRemovalPolicyWarn,
+			operation:     admissionv1.Update,
+			oldNs:         newNamespace("osm", false),
+			newNs:         newNamespace("", false),
+			pods:          []runtime.Object{podWithSidecar, podWithoutSidecar},
+			expWarning:    true,
+		},
+		{
+			name:          "removal of a namespace running pods with sidecars is blocked",
+			removalPolicy: NamespaceRemovalPolicyBlock,
+			operation:     admissionv1.Update,
+			oldNs:         newNamespace("osm", false),
+			newNs:         newNamespace("other", false),
+			pods:          []runtime.Object{podWithSidecar},
+			expErrStr:     "Namespace tenant-a runs 1 pods with sidecars",
+		},
+		{
+			name:          "removal of a namespace without pods with sidecars passes",
+			removalPolicy: NamespaceRemovalPolicyBlock,
+			operation:     admissionv1.Update,
+			oldNs:         newNamespace("osm", false),
+			newNs:         newNamespace("", false),
+			pods:          []runtime.Object{podWithoutSidecar},
+		},
+		{
+			name:          "update of a namespace remaining in the mesh passes",
+			removalPolicy: NamespaceRemovalPolicyBlock,
+			operation:     admissionv1.Update,
+			oldNs:         newNamespace("osm", false),
+			newNs:         newNamespace("osm", false),
+			pods:          []runtime.Object{podWithSidecar},
+		},
+		{
+			name:          "update of a namespace being deleted passes",
+			removalPolicy: NamespaceRemovalPolicyBlock,
+			operation:     admissionv1.Update,
+			oldNs:         newNamespace("osm", false),
+			newNs:         newNamespace("", true),
+			pods:          []runtime.Object{podWithSidecar},
+		},
+		{
+			name:          "creation of a namespace passes",
+			removalPolicy: NamespaceRemovalPolicyBlock,
+			operation:     admissionv1.Create,
+			newNs:         newNamespace("osm", false),
+			pods:          []runtime.Object{podWithSidecar},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			req := &admissionv1.AdmissionRequest{Operation: tc.operation}
+			newRaw, err := json.Marshal(tc.newNs)
+			assert.Nil(err)
+			req.Object = runtime.RawExtension{Raw: newRaw}
+			if tc.oldNs != nil {
+				oldRaw, err := json.Marshal(tc.oldNs)
+				assert.Nil(err)
+				req.OldObject = runtime.RawExtension{Raw: oldRaw}
+			}
+
+			validate := newNamespaceValidator(fake.NewSimpleClientset(tc.pods...), "osm", tc.removalPolicy)
+			resp, err := validate(req)
+			if tc.expErrStr != "" {
+				assert.Nil(resp)
+				assert.NotNil(err)
+				assert.Contains(err.Error(), tc.expErrStr)
+				return
+			}
+
+			assert.Nil(err)
+			if tc.expWarning {
+				assert.True(resp.Allowed)
+				assert.Len(resp.Warnings, 1)
+			} else {
+				assert.Nil(resp)
+			}
+		})
+	}
+}
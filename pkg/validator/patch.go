@@ -11,42 +11,80 @@ import (
 
 	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/errcode"
+	"github.com/openservicemesh/osm/pkg/webhook"
 )
 
 const (
 	// validatingWebhookName is the name of the validating webhook.
 	validatingWebhookName = "osm-validator.k8s.io"
+
+	// meshConfigValidatingWebhookName is the name of the validating webhook of the MeshConfig, which lives in the OSM
+	// namespace excluded by the namespace selector of the validatingWebhookName webhook
+	meshConfigValidatingWebhookName = "osm-meshconfig-validator.k8s.io"
+
+	// namespaceValidatingWebhookName is the name of the validating webhook of the Namespaces removed from the mesh,
+	// which select the Namespaces by their former labels
+	namespaceValidatingWebhookName = "osm-namespace-validator.k8s.io"
 )
 
+// validatorWebhookNames are the names of the webhooks of the ValidatingWebhookConfiguration served by the validator
+var validatorWebhookNames = []string{validatingWebhookName, meshConfigValidatingWebhookName, namespaceValidatingWebhookName}
+
+// isValidatorWebhook returns whether the webhook with the given name is served by the validator
+func isValidatorWebhook(name string) bool {
+	for _, webhookName := range validatorWebhookNames {
+		if name == webhookName {
+			return true
+		}
+	}
+	return false
+}
+
 // getPartialValidatingWebhookConfiguration returns only the portion of the ValidatingWebhookConfiguration that needs
-// to be updated.
-func getPartialValidatingWebhookConfiguration(name string, cert certificate.Certificater) admissionregv1.ValidatingWebhookConfiguration {
-	return admissionregv1.ValidatingWebhookConfiguration{
+// to be updated, for the webhooks with the given names.
+func getPartialValidatingWebhookConfiguration(name string, cert certificate.Certificater, webhookNames []string) admissionregv1.ValidatingWebhookConfiguration {
+	config := admissionregv1.ValidatingWebhookConfiguration{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: name,
 		},
-		Webhooks: []admissionregv1.ValidatingWebhook{
-			{
-				Name: validatingWebhookName,
-				ClientConfig: admissionregv1.WebhookClientConfig{
-					CABundle: cert.GetCertificateChain(),
-				},
-				SideEffects: func() *admissionregv1.SideEffectClass {
-					sideEffect := admissionregv1.SideEffectClassNoneOnDryRun
-					return &sideEffect
-				}(),
-				AdmissionReviewVersions: []string{"v1"},
+	}
+	for _, webhookName := range webhookNames {
+		config.Webhooks = append(config.Webhooks, admissionregv1.ValidatingWebhook{
+			Name: webhookName,
+			ClientConfig: admissionregv1.WebhookClientConfig{
+				CABundle: cert.GetCertificateChain(),
 			},
-		},
+			SideEffects: func() *admissionregv1.SideEffectClass {
+				sideEffect := admissionregv1.SideEffectClassNoneOnDryRun
+				return &sideEffect
+			}(),
+			AdmissionReviewVersions: []string{"v1"},
+		})
 	}
+	return config
 }
 
 // updateValidatingWebhookCABundle updates the existing ValidatingWebhookConfiguration with the CA this OSM instance runs with.
 // It is necessary to perform this patch because the original ValidatingWebhookConfig YAML does not contain the root certificate.
+// Only the webhooks served by the validator that exist are patched, since the patch would otherwise add incomplete
+// webhooks, e.g. to the configuration of a control plane being upgraded.
 func updateValidatingWebhookCABundle(webhookConfigName string, certificater certificate.Certificater, kubeClient kubernetes.Interface) error {
 	vwc := kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations()
 
-	patchJSON, err := json.Marshal(getPartialValidatingWebhookConfiguration(webhookConfigName, certificater))
+	config, err := vwc.Get(context.Background(), webhookConfigName, metav1.GetOptions{})
+	if err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrUpdatingValidatingWebhookCABundle)).
+			Msgf("Error getting ValidatingWebhookConfiguration %s", webhookConfigName)
+		return err
+	}
+	var webhookNames []string
+	for _, webhook := range config.Webhooks {
+		if isValidatorWebhook(webhook.Name) {
+			webhookNames = append(webhookNames, webhook.Name)
+		}
+	}
+
+	patchJSON, err := json.Marshal(getPartialValidatingWebhookConfiguration(webhookConfigName, certificater, webhookNames))
 	if err != nil {
 		return err
 	}
@@ -61,3 +99,33 @@ func updateValidatingWebhookCABundle(webhookConfigName string, certificater cert
 	log.Info().Msgf("Finished updating CA Bundle for ValidatingWebhookConfiguration %s", webhookConfigName)
 	return nil
 }
+
+// updateValidatingWebhookURL updates the existing ValidatingWebhookConfiguration to reach the webhook at the given URL
+// instead of the validator service. The service reference is removed since the API server requires exactly one of
+// them to be set, which is why a merge patch cannot be used here.
+func updateValidatingWebhookURL(webhookConfigName string, webhookURL string, kubeClient kubernetes.Interface) error {
+	vwc := kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations()
+
+	config, err := vwc.Get(context.Background(), webhookConfigName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	url := webhook.GetURL(webhookURL, validationAPIPath)
+	for idx := range config.Webhooks {
+		if !isValidatorWebhook(config.Webhooks[idx].Name) {
+			continue
+		}
+		config.Webhooks[idx].ClientConfig.URL = &url
+		config.Webhooks[idx].ClientConfig.Service = nil
+	}
+
+	if _, err = vwc.Update(context.Background(), config, metav1.UpdateOptions{}); err != nil {
+		log.Error().Err(err).Str(errcode.Kind, errcode.GetErrCodeWithMetric(errcode.ErrUpdatingValidatingWebhookURL)).
+			Msgf("Error updating URL for ValidatingWebhookConfiguration %s", webhookConfigName)
+		return err
+	}
+
+	log.Info().Msgf("Finished updating URL for ValidatingWebhookConfiguration %s to %s", webhookConfigName, url)
+	return nil
+}
@@ -0,0 +1,99 @@
+package validator
+
+import (
+	"context"
+	"testing"
+
+	tassert "github.com/stretchr/testify/assert"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openservicemesh/osm/pkg/certificate/providers/tresor"
+)
+
+func TestUpdateValidatingWebhookCABundle(t *testing.T) {
+	assert := tassert.New(t)
+
+	const webhookConfigName = "osm-validator-mesh-osm"
+	kubeClient := fake.NewSimpleClientset(&admissionregv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: webhookConfigName},
+		Webhooks: []admissionregv1.ValidatingWebhook{
+			{
+				Name: validatingWebhookName,
+				ClientConfig: admissionregv1.WebhookClientConfig{
+					Service: &admissionregv1.ServiceReference{Name: "osm-validator", Namespace: "osm-system"},
+				},
+			},
+			{
+				Name: "other-webhook",
+				ClientConfig: admissionregv1.WebhookClientConfig{
+					Service: &admissionregv1.ServiceReference{Name: "other", Namespace: "other"},
+				},
+			},
+		},
+	})
+	cert := tresor.NewFakeCertificate()
+
+	err := updateValidatingWebhookCABundle(webhookConfigName, cert, kubeClient)
+	assert.Nil(err)
+
+	config, err := kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(context.Background(), webhookConfigName, metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Equal(cert.GetCertificateChain(), config.Webhooks[0].ClientConfig.CABundle)
+
+	// Webhooks not served by the validator are left untouched, and missing webhooks served by the validator, such as
+	// the MeshConfig webhook of a configuration predating it, are not added
+	assert.Len(config.Webhooks, 2)
+	assert.Equal("other-webhook", config.Webhooks[1].Name)
+	assert.Nil(config.Webhooks[1].ClientConfig.CABundle)
+
+	// The ValidatingWebhookConfiguration must exist
+	assert.NotNil(updateValidatingWebhookCABundle("missing", cert, kubeClient))
+}
+
+func TestUpdateValidatingWebhookURL(t *testing.T) {
+	assert := tassert.New(t)
+
+	const webhookConfigName = "osm-validator-mesh-osm"
+	kubeClient := fake.NewSimpleClientset(&admissionregv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: webhookConfigName},
+		Webhooks: []admissionregv1.ValidatingWebhook{
+			{
+				Name: validatingWebhookName,
+				ClientConfig: admissionregv1.WebhookClientConfig{
+					Service: &admissionregv1.ServiceReference{Name: "osm-validator", Namespace: "osm-system"},
+				},
+			},
+			{
+				Name: meshConfigValidatingWebhookName,
+				ClientConfig: admissionregv1.WebhookClientConfig{
+					Service: &admissionregv1.ServiceReference{Name: "osm-validator", Namespace: "osm-system"},
+				},
+			},
+			{
+				Name: "other-webhook",
+				ClientConfig: admissionregv1.WebhookClientConfig{
+					Service: &admissionregv1.ServiceReference{Name: "other", Namespace: "other"},
+				},
+			},
+		},
+	})
+
+	err := updateValidatingWebhookURL(webhookConfigName, "https://osm.example.com:9093/", kubeClient)
+	assert.Nil(err)
+
+	config, err := kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(context.Background(), webhookConfigName, metav1.GetOptions{})
+	assert.Nil(err)
+	assert.Nil(config.Webhooks[0].ClientConfig.Service)
+	assert.Equal("https://osm.example.com:9093/validate", *config.Webhooks[0].ClientConfig.URL)
+	assert.Nil(config.Webhooks[1].ClientConfig.Service)
+	assert.Equal("https://osm.example.com:9093/validate", *config.Webhooks[1].ClientConfig.URL)
+
+	// Webhooks not served by the validator are left untouched
+	assert.NotNil(config.Webhooks[2].ClientConfig.Service)
+	assert.Nil(config.Webhooks[2].ClientConfig.URL)
+
+	// The ValidatingWebhookConfiguration must exist
+	assert.NotNil(updateValidatingWebhookURL("missing", "https://osm.example.com:9093", kubeClient))
+}
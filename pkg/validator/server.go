@@ -9,9 +9,14 @@ import (
 	"net/http"
 
 	"github.com/pkg/errors"
+	smiSpecs "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
+	smiSplit "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha4"
 	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 
+	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
+	configv1alpha2 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha2"
 	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
 
 	"github.com/openservicemesh/osm/pkg/certificate"
@@ -34,11 +39,24 @@ type validatingWebhookServer struct {
 }
 
 // NewValidatingWebhook returns a validatingWebhookServer with the defaultValidators that were previously registered.
-func NewValidatingWebhook(webhookConfigName string, port int, certificater certificate.Certificater, kubeClient kubernetes.Interface, stop <-chan struct{}) error {
+// If webhookURL is set, the ValidatingWebhookConfiguration is updated to reach the webhook at the given URL instead of
+// the validator service, which is used when OSM runs outside the cluster. Only the MeshConfig of the given OSM
+// namespace is validated. The removal of namespaces running pods with
+// sidecars from the given mesh is handled according to namespaceRemovalPolicy, one of NamespaceRemovalPolicyWarn or
+// NamespaceRemovalPolicyBlock.
+func NewValidatingWebhook(webhookConfigName string, port int, webhookURL string, osmNamespace string, meshName string, namespaceRemovalPolicy string, certificater certificate.Certificater, kubeClient kubernetes.Interface, stop <-chan struct{}) error {
 	v := &validatingWebhookServer{
 		validators: map[string]validateFunc{
-			policyv1alpha1.SchemeGroupVersion.WithKind("IngressBackend").String(): ingressBackendValidator,
-			policyv1alpha1.SchemeGroupVersion.WithKind("Egress").String():         egressValidator,
+			policyv1alpha1.SchemeGroupVersion.WithKind("IngressBackend").String():         ingressBackendValidator,
+			policyv1alpha1.SchemeGroupVersion.WithKind("Egress").String():                 egressValidator,
+			policyv1alpha1.SchemeGroupVersion.WithKind("ExternalWorkload").String():       externalWorkloadValidator,
+			policyv1alpha1.SchemeGroupVersion.WithKind("UpstreamTrafficSetting").String(): upstreamTrafficSettingValidator,
+			policyv1alpha1.SchemeGroupVersion.WithKind("NamespaceIsolation").String():     namespaceIsolationValidator,
+			smiSplit.SchemeGroupVersion.WithKind("TrafficSplit").String():                 trafficSplitValidator,
+			smiSpecs.SchemeGroupVersion.WithKind("HTTPRouteGroup").String():               httpRouteGroupValidator,
+			configv1alpha1.SchemeGroupVersion.WithKind("MeshConfig").String():             newMeshConfigValidator(osmNamespace),
+			configv1alpha2.SchemeGroupVersion.WithKind("MeshConfig").String():             newMeshConfigValidator(osmNamespace),
+			corev1.SchemeGroupVersion.WithKind("Namespace").String():                      newNamespaceValidator(kubeClient, meshName, namespaceRemovalPolicy),
 		},
 	}
 
@@ -47,6 +65,12 @@ func NewValidatingWebhook(webhookConfigName string, port int, certificater certi
 		return errors.Wrapf(err, "Error configuring ValidatingWebhookConfiguration %s", webhookConfigName)
 	}
 
+	if webhookURL != "" {
+		if err := updateValidatingWebhookURL(webhookConfigName, webhookURL, kubeClient); err != nil {
+			return errors.Wrapf(err, "Error configuring ValidatingWebhookConfiguration %s", webhookConfigName)
+		}
+	}
+
 	go v.run(port, certificater, stop)
 	return nil
 }
@@ -4,16 +4,22 @@ import (
 	"bytes"
 	"encoding/json"
 	"net"
+	"regexp"
 	"strconv"
 	"strings"
 
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	smiSpecs "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
+	smiSplit "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha4"
 	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	configv1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
 	policyv1alpha1 "github.com/openservicemesh/osm/pkg/apis/policy/v1alpha1"
 
 	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/smi"
 )
 
 // validateFunc is a function type that accepts an AdmissionRequest and returns an AdmissionResponse.
@@ -90,11 +96,134 @@ func ingressBackendValidator(req *admissionv1.AdmissionRequest) (*admissionv1.Ad
 		default:
 			return nil, errors.Errorf("Expected 'port.protocol' to be 'http' or 'https', got: %s", backend.Port.Protocol)
 		}
+
+		if err := validateIngressBackendRewrite(backend.Rewrite); err != nil {
+			return nil, errors.Wrapf(err, "Invalid 'rewrite' for backend %s", backend.Name)
+		}
+		if err := validateIngressBackendHeaders(backend.Headers); err != nil {
+			return nil, errors.Wrapf(err, "Invalid 'headers' for backend %s", backend.Name)
+		}
+	}
+
+	if err := validateIngressBackendCORS(ingressBackend.Spec.CORS); err != nil {
+		return nil, errors.Wrap(err, "Invalid 'cors'")
+	}
+	if err := validateIngressBackendHeaders(ingressBackend.Spec.Headers); err != nil {
+		return nil, errors.Wrap(err, "Invalid 'headers'")
+	}
+	if err := validateIngressBackendSecurity(ingressBackend.Spec.Security); err != nil {
+		return nil, errors.Wrap(err, "Invalid 'security'")
 	}
 
 	return nil, nil
 }
 
+// validateIngressBackendCORS validates the CORS policy of the requests to IngressBackend backends
+func validateIngressBackendCORS(cors *policyv1alpha1.CORSSpec) error {
+	if cors == nil {
+		return nil
+	}
+
+	if len(cors.AllowOrigins) == 0 {
+		return errors.New("Expected at least one origin in 'allowOrigins'")
+	}
+	if cors.MaxAge != nil && cors.MaxAge.Duration < 0 {
+		return errors.Errorf("Expected 'maxAge' to be non-negative, got: %s", cors.MaxAge.Duration)
+	}
+
+	return nil
+}
+
+// validateIngressBackendSecurity validates the hardening of the requests to IngressBackend backends and of their
+// responses
+func validateIngressBackendSecurity(security *policyv1alpha1.IngressSecuritySpec) error {
+	if security == nil {
+		return nil
+	}
+
+	if security.CSRF != nil {
+		for _, origin := range security.CSRF.AdditionalOrigins {
+			if origin == "" {
+				return errors.New("Expected 'csrf.additionalOrigins' to not contain empty origins")
+			}
+		}
+	}
+	if headers := security.ResponseHeaders; headers != nil {
+		switch headers.FrameOptions {
+		case "", "DENY", "SAMEORIGIN":
+		default:
+			return errors.Errorf("Expected 'responseHeaders.frameOptions' to be DENY or SAMEORIGIN, got: %s", headers.FrameOptions)
+		}
+	}
+	if cookies := security.Cookies; cookies != nil {
+		switch cookies.SameSite {
+		case "", "Strict", "Lax":
+		case "None":
+			// Browsers reject the cookies with SameSite=None that are not secure
+			if !cookies.Secure {
+				return errors.New("'cookies.sameSite' None requires 'cookies.secure' to be set")
+			}
+		default:
+			return errors.Errorf("Expected 'cookies.sameSite' to be Strict, Lax or None, got: %s", cookies.SameSite)
+		}
+	}
+
+	return nil
+}
+
+// validateIngressBackendRewrite validates the rewrite of the requests to an IngressBackend backend
+func validateIngressBackendRewrite(rewrite *policyv1alpha1.RewriteSpec) error {
+	if rewrite == nil {
+		return nil
+	}
+
+	if rewrite.PathPrefix != "" && !strings.HasPrefix(rewrite.PathPrefix, "/") {
+		return errors.Errorf("Expected 'pathPrefix' to start with '/', got: %s", rewrite.PathPrefix)
+	}
+	if rewrite.PrefixRewrite != "" && rewrite.RegexRewrite != nil {
+		return errors.New("'prefixRewrite' and 'regexRewrite' cannot both be specified")
+	}
+	if rewrite.RegexRewrite != nil {
+		if _, err := regexp.Compile(rewrite.RegexRewrite.Pattern); err != nil {
+			return errors.Wrapf(err, "Invalid 'regexRewrite.pattern' %s", rewrite.RegexRewrite.Pattern)
+		}
+	}
+	if rewrite.Host != "" && rewrite.AutoHost {
+		return errors.New("'host' and 'autoHost' cannot both be specified")
+	}
+
+	return nil
+}
+
+// validateIngressBackendHeaders validates the manipulation of the headers of the requests to IngressBackend backends
+// and of their responses. Envoy doesn't allow modifying pseudo-headers or the Host header.
+func validateIngressBackendHeaders(headers *policyv1alpha1.HeadersSpec) error {
+	if headers == nil {
+		return nil
+	}
+
+	var names []string
+	for _, header := range headers.RequestHeadersToAdd {
+		names = append(names, header.Name)
+	}
+	for _, header := range headers.ResponseHeadersToAdd {
+		names = append(names, header.Name)
+	}
+	names = append(names, headers.RequestHeadersToRemove...)
+	names = append(names, headers.ResponseHeadersToRemove...)
+
+	for _, name := range names {
+		if name == "" {
+			return errors.New("Expected header names to be non-empty")
+		}
+		if strings.HasPrefix(name, ":") || strings.EqualFold(name, "host") {
+			return errors.Errorf("Header %s cannot be modified", name)
+		}
+	}
+
+	return nil
+}
+
 // egressValidator validates the Egress custom resource
 func egressValidator(req *admissionv1.AdmissionRequest) (*admissionv1.AdmissionResponse, error) {
 	egress := &policyv1alpha1.Egress{}
@@ -115,6 +244,166 @@ func egressValidator(req *admissionv1.AdmissionRequest) (*admissionv1.AdmissionR
 	return nil, nil
 }
 
+// externalWorkloadValidator validates the ExternalWorkload custom resource
+func externalWorkloadValidator(req *admissionv1.AdmissionRequest) (*admissionv1.AdmissionResponse, error) {
+	externalWorkload := &policyv1alpha1.ExternalWorkload{}
+	if err := json.NewDecoder(bytes.NewBuffer(req.Object.Raw)).Decode(externalWorkload); err != nil {
+		return nil, err
+	}
+
+	spec := externalWorkload.Spec
+	if errs := validation.IsDNS1123Subdomain(spec.ServiceAccount); len(errs) > 0 {
+		return nil, errors.Errorf("Invalid 'serviceAccount' %s: %s", spec.ServiceAccount, strings.Join(errs, ", "))
+	}
+
+	if _, err := uuid.Parse(spec.ProxyUUID); err != nil {
+		return nil, errors.Errorf("Invalid 'proxyUUID' %s: %s", spec.ProxyUUID, err)
+	}
+
+	if len(spec.Addresses) == 0 {
+		return nil, errors.New("At least one address must be specified in 'addresses'")
+	}
+	addresses := make(map[string]bool)
+	for _, address := range spec.Addresses {
+		ip := net.ParseIP(address)
+		if ip == nil {
+			return nil, errors.Errorf("Expected 'addresses' to be IP addresses, got: %s", address)
+		}
+		if ip.IsUnspecified() || ip.IsLoopback() {
+			return nil, errors.Errorf("Address %s in 'addresses' is not routable", address)
+		}
+		if addresses[ip.String()] {
+			return nil, errors.Errorf("Address %s is specified more than once in 'addresses'", address)
+		}
+		addresses[ip.String()] = true
+	}
+
+	portNames := make(map[string]bool)
+	portNumbers := make(map[int]bool)
+	for _, port := range spec.Ports {
+		if port.Number < 1 || port.Number > 65535 {
+			return nil, errors.Errorf("Port number %d is not in the range 1-65535", port.Number)
+		}
+		if portNumbers[port.Number] {
+			return nil, errors.Errorf("Port number %d is specified more than once in 'ports'", port.Number)
+		}
+		portNumbers[port.Number] = true
+
+		if port.Name == "" {
+			continue
+		}
+		if portNames[port.Name] {
+			return nil, errors.Errorf("Port name %s is specified more than once in 'ports'", port.Name)
+		}
+		portNames[port.Name] = true
+	}
+
+	return nil, nil
+}
+
+// upstreamTrafficSettingValidator validates the UpstreamTrafficSetting custom resource
+func upstreamTrafficSettingValidator(req *admissionv1.AdmissionRequest) (*admissionv1.AdmissionResponse, error) {
+	upstreamTrafficSetting := &policyv1alpha1.UpstreamTrafficSetting{}
+	if err := json.NewDecoder(bytes.NewBuffer(req.Object.Raw)).Decode(upstreamTrafficSetting); err != nil {
+		return nil, err
+	}
+
+	spec := upstreamTrafficSetting.Spec
+	hostSuffix := "." + req.Namespace + ".svc.cluster.local"
+	svcName := strings.TrimSuffix(spec.Host, hostSuffix)
+	if svcName == spec.Host {
+		return nil, errors.Errorf("Expected 'host' to be the FQDN of a service in namespace %s of the form <service>%s, got: %s", req.Namespace, hostSuffix, spec.Host)
+	}
+	if errs := validation.IsDNS1035Label(svcName); len(errs) > 0 {
+		return nil, errors.Errorf("Invalid service name %s in 'host': %s", svcName, strings.Join(errs, ", "))
+	}
+
+	if retryPolicy := spec.RetryPolicy; retryPolicy != nil {
+		if retryPolicy.RetryOn != "" {
+			for _, retryOn := range strings.Split(retryPolicy.RetryOn, ",") {
+				if strings.TrimSpace(retryOn) == "" {
+					return nil, errors.Errorf("Empty retry condition in 'retryPolicy.retryOn': %s", retryPolicy.RetryOn)
+				}
+			}
+		}
+		if retryPolicy.PerTryTimeout != nil && retryPolicy.PerTryTimeout.Duration <= 0 {
+			return nil, errors.Errorf("Expected 'retryPolicy.perTryTimeout' to be greater than 0, got: %s", retryPolicy.PerTryTimeout.Duration)
+		}
+		if retryPolicy.Idempotent && retryPolicy.PerTryTimeout == nil {
+			return nil, errors.New("'retryPolicy.idempotent' requires 'retryPolicy.perTryTimeout' to hedge the requests exceeding it")
+		}
+	}
+
+	if spec.PeerValidation == nil {
+		return nil, nil
+	}
+
+	trustDomains := make(map[string]bool)
+	for _, trustDomain := range spec.PeerValidation.TrustDomainAliases {
+		if errs := validation.IsDNS1123Subdomain(trustDomain); len(errs) > 0 {
+			return nil, errors.Errorf("Invalid trust domain %s in 'peerValidation.trustDomainAliases': %s", trustDomain, strings.Join(errs, ", "))
+		}
+		if trustDomains[trustDomain] {
+			return nil, errors.Errorf("Trust domain %s is specified more than once in 'peerValidation.trustDomainAliases'", trustDomain)
+		}
+		trustDomains[trustDomain] = true
+	}
+
+	subjectAltNames := make(map[string]bool)
+	for _, san := range spec.PeerValidation.SubjectAltNames {
+		if san == "" {
+			return nil, errors.New("Empty Subject Alternative Name in 'peerValidation.subjectAltNames'")
+		}
+		if subjectAltNames[san] {
+			return nil, errors.Errorf("Subject Alternative Name %s is specified more than once in 'peerValidation.subjectAltNames'", san)
+		}
+		subjectAltNames[san] = true
+	}
+
+	return nil, nil
+}
+
+// namespaceIsolationValidator validates the NamespaceIsolation custom resource
+func namespaceIsolationValidator(req *admissionv1.AdmissionRequest) (*admissionv1.AdmissionResponse, error) {
+	namespaceIsolation := &policyv1alpha1.NamespaceIsolation{}
+	if err := json.NewDecoder(bytes.NewBuffer(req.Object.Raw)).Decode(namespaceIsolation); err != nil {
+		return nil, err
+	}
+
+	namespaces := make(map[string]bool)
+	for _, ns := range namespaceIsolation.Spec.AllowedNamespaces {
+		if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+			return nil, errors.Errorf("Invalid namespace %s in 'allowedNamespaces': %s", ns, strings.Join(errs, ", "))
+		}
+		if namespaces[ns] {
+			return nil, errors.Errorf("Namespace %s is specified more than once in 'allowedNamespaces'", ns)
+		}
+		namespaces[ns] = true
+	}
+
+	return nil, nil
+}
+
+// trafficSplitValidator validates the weights of the backends of the SMI TrafficSplit custom resource
+func trafficSplitValidator(req *admissionv1.AdmissionRequest) (*admissionv1.AdmissionResponse, error) {
+	trafficSplit := &smiSplit.TrafficSplit{}
+	if err := json.NewDecoder(bytes.NewBuffer(req.Object.Raw)).Decode(trafficSplit); err != nil {
+		return nil, err
+	}
+
+	return nil, smi.ValidateTrafficSplitBackends(trafficSplit.Spec.Backends)
+}
+
+// httpRouteGroupValidator validates the regular expressions of the matches of the SMI HTTPRouteGroup custom resource
+func httpRouteGroupValidator(req *admissionv1.AdmissionRequest) (*admissionv1.AdmissionResponse, error) {
+	httpRouteGroup := &smiSpecs.HTTPRouteGroup{}
+	if err := json.NewDecoder(bytes.NewBuffer(req.Object.Raw)).Decode(httpRouteGroup); err != nil {
+		return nil, err
+	}
+
+	return nil, smi.ValidateHTTPRouteGroupMatches(httpRouteGroup.Spec.Matches)
+}
+
 // MultiClusterServiceValidator validates the MultiClusterService CRD.
 func MultiClusterServiceValidator(req *admissionv1.AdmissionRequest) (*admissionv1.AdmissionResponse, error) {
 	config := &configv1alpha1.MultiClusterService{}
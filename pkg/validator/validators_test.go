@@ -78,6 +78,423 @@ func TestIngressBackendValidator(t *testing.T) {
 			expResp:   nil,
 			expErrStr: "Expected 'port.protocol' to be 'http' or 'https', got: invalid",
 		},
+		{
+			name: "IngressBackend with valid rewrite succeeds",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "policy.openservicemesh.io",
+					Kind:    "IngressBackend",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "IngressBackend",
+						"spec": {
+							"backends": [
+								{
+									"name": "test",
+									"port": {
+										"number": 80,
+										"protocol": "http"
+									},
+									"rewrite": {"pathPrefix": "/api/v1/", "prefixRewrite": "/", "host": "backend.example.com"}
+								}
+							]
+						}
+					}
+					`),
+				},
+			},
+			expResp:   nil,
+			expErrStr: "",
+		},
+		{
+			name: "IngressBackend with prefix and regex rewrites errors",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "policy.openservicemesh.io",
+					Kind:    "IngressBackend",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "IngressBackend",
+						"spec": {
+							"backends": [
+								{
+									"name": "test",
+									"port": {
+										"number": 80,
+										"protocol": "http"
+									},
+									"rewrite": {"prefixRewrite": "/", "regexRewrite": {"pattern": "^/api/(.*)$", "substitution": "/\\1"}}
+								}
+							]
+						}
+					}
+					`),
+				},
+			},
+			expResp:   nil,
+			expErrStr: "Invalid 'rewrite' for backend test: 'prefixRewrite' and 'regexRewrite' cannot both be specified",
+		},
+		{
+			name: "IngressBackend with invalid regex rewrite pattern errors",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "policy.openservicemesh.io",
+					Kind:    "IngressBackend",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "IngressBackend",
+						"spec": {
+							"backends": [
+								{
+									"name": "test",
+									"port": {
+										"number": 80,
+										"protocol": "http"
+									},
+									"rewrite": {"regexRewrite": {"pattern": "^/api/(.*$", "substitution": "/"}}
+								}
+							]
+						}
+					}
+					`),
+				},
+			},
+			expResp:   nil,
+			expErrStr: "Invalid 'rewrite' for backend test: Invalid 'regexRewrite.pattern' ^/api/(.*$: error parsing regexp: missing closing ): `^/api/(.*$`",
+		},
+		{
+			name: "IngressBackend with host and auto host rewrites errors",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "policy.openservicemesh.io",
+					Kind:    "IngressBackend",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "IngressBackend",
+						"spec": {
+							"backends": [
+								{
+									"name": "test",
+									"port": {
+										"number": 80,
+										"protocol": "http"
+									},
+									"rewrite": {"host": "backend.example.com", "autoHost": true}
+								}
+							]
+						}
+					}
+					`),
+				},
+			},
+			expResp:   nil,
+			expErrStr: "Invalid 'rewrite' for backend test: 'host' and 'autoHost' cannot both be specified",
+		},
+		{
+			name: "IngressBackend with valid CORS policy succeeds",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "policy.openservicemesh.io",
+					Kind:    "IngressBackend",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "IngressBackend",
+						"spec": {
+							"backends": [
+								{
+									"name": "test",
+									"port": {
+										"number": 80,
+										"protocol": "http"
+									}
+								}
+							],
+							"cors": {"allowOrigins": ["https://example.com"], "allowMethods": ["GET", "POST"], "maxAge": "1h"}
+						}
+					}
+					`),
+				},
+			},
+			expResp:   nil,
+			expErrStr: "",
+		},
+		{
+			name: "IngressBackend with CORS policy without origins errors",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "policy.openservicemesh.io",
+					Kind:    "IngressBackend",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "IngressBackend",
+						"spec": {
+							"backends": [
+								{
+									"name": "test",
+									"port": {
+										"number": 80,
+										"protocol": "http"
+									}
+								}
+							],
+							"cors": {"allowOrigins": [], "allowMethods": ["GET"]}
+						}
+					}
+					`),
+				},
+			},
+			expResp:   nil,
+			expErrStr: "Invalid 'cors': Expected at least one origin in 'allowOrigins'",
+		},
+		{
+			name: "IngressBackend with CORS policy with negative max age errors",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "policy.openservicemesh.io",
+					Kind:    "IngressBackend",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "IngressBackend",
+						"spec": {
+							"backends": [
+								{
+									"name": "test",
+									"port": {
+										"number": 80,
+										"protocol": "http"
+									}
+								}
+							],
+							"cors": {"allowOrigins": ["*"], "maxAge": "-1m"}
+						}
+					}
+					`),
+				},
+			},
+			expResp:   nil,
+			expErrStr: "Invalid 'cors': Expected 'maxAge' to be non-negative, got: -1m0s",
+		},
+		{
+			name: "IngressBackend with valid security policy succeeds",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "policy.openservicemesh.io",
+					Kind:    "IngressBackend",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "IngressBackend",
+						"spec": {
+							"backends": [
+								{
+									"name": "test",
+									"port": {
+										"number": 80,
+										"protocol": "http"
+									}
+								}
+							],
+							"security": {"csrf": {"additionalOrigins": ["https://example.com"]}, "responseHeaders": {"frameOptions": "DENY", "contentTypeNoSniff": true}, "cookies": {"secure": true, "sameSite": "None"}}
+						}
+					}
+					`),
+				},
+			},
+			expResp:   nil,
+			expErrStr: "",
+		},
+		{
+			name: "IngressBackend with invalid frame options errors",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "policy.openservicemesh.io",
+					Kind:    "IngressBackend",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "IngressBackend",
+						"spec": {
+							"backends": [
+								{
+									"name": "test",
+									"port": {
+										"number": 80,
+										"protocol": "http"
+									}
+								}
+							],
+							"security": {"responseHeaders": {"frameOptions": "ALLOW-FROM https://example.com"}}
+						}
+					}
+					`),
+				},
+			},
+			expResp:   nil,
+			expErrStr: "Invalid 'security': Expected 'responseHeaders.frameOptions' to be DENY or SAMEORIGIN, got: ALLOW-FROM https://example.com",
+		},
+		{
+			name: "IngressBackend with SameSite None cookies that are not secure errors",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "policy.openservicemesh.io",
+					Kind:    "IngressBackend",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "IngressBackend",
+						"spec": {
+							"backends": [
+								{
+									"name": "test",
+									"port": {
+										"number": 80,
+										"protocol": "http"
+									}
+								}
+							],
+							"security": {"cookies": {"httpOnly": true, "sameSite": "None"}}
+						}
+					}
+					`),
+				},
+			},
+			expResp:   nil,
+			expErrStr: "Invalid 'security': 'cookies.sameSite' None requires 'cookies.secure' to be set",
+		},
+		{
+			name: "IngressBackend with valid header manipulation succeeds",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "policy.openservicemesh.io",
+					Kind:    "IngressBackend",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "IngressBackend",
+						"spec": {
+							"backends": [
+								{
+									"name": "test",
+									"port": {
+										"number": 80,
+										"protocol": "http"
+									},
+									"headers": {"requestHeadersToAdd": [{"name": "x-correlation-id", "value": "%REQ(x-request-id)%"}]}
+								}
+							],
+							"headers": {"responseHeadersToAdd": [{"name": "strict-transport-security", "value": "max-age=31536000"}], "responseHeadersToRemove": ["x-internal"]}
+						}
+					}
+					`),
+				},
+			},
+			expResp:   nil,
+			expErrStr: "",
+		},
+		{
+			name: "IngressBackend removing the host header errors",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "policy.openservicemesh.io",
+					Kind:    "IngressBackend",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "IngressBackend",
+						"spec": {
+							"backends": [
+								{
+									"name": "test",
+									"port": {
+										"number": 80,
+										"protocol": "http"
+									}
+								}
+							],
+							"headers": {"requestHeadersToRemove": ["Host"]}
+						}
+					}
+					`),
+				},
+			},
+			expResp:   nil,
+			expErrStr: "Invalid 'headers': Header Host cannot be modified",
+		},
+		{
+			name: "IngressBackend adding a pseudo-header to a backend errors",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "v1alpha1",
+					Version: "policy.openservicemesh.io",
+					Kind:    "IngressBackend",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "v1alpha1",
+						"kind": "IngressBackend",
+						"spec": {
+							"backends": [
+								{
+									"name": "test",
+									"port": {
+										"number": 80,
+										"protocol": "http"
+									},
+									"headers": {"requestHeadersToAdd": [{"name": ":path", "value": "/"}]}
+								}
+							]
+						}
+					}
+					`),
+				},
+			},
+			expResp:   nil,
+			expErrStr: "Invalid 'headers' for backend test: Header :path cannot be modified",
+		},
 		{
 			name: "IngressBackend with valid TLS config succeeds",
 			input: &admissionv1.AdmissionRequest{
@@ -313,6 +730,376 @@ func TestEgressValidator(t *testing.T) {
 	}
 }
 
+func TestTrafficSplitValidator(t *testing.T) {
+	testCases := []struct {
+		name      string
+		input     *admissionv1.AdmissionRequest
+		expResp   *admissionv1.AdmissionResponse
+		expErrStr string
+	}{
+		{
+			name: "TrafficSplit with valid weights passes",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "split.smi-spec.io",
+					Version: "v1alpha2",
+					Kind:    "TrafficSplit",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "split.smi-spec.io/v1alpha2",
+						"kind": "TrafficSplit",
+						"spec": {
+							"service": "apex",
+							"backends": [
+								{
+									"service": "v1",
+									"weight": 1
+								},
+								{
+									"service": "v2",
+									"weight": 0
+								}
+							]
+						}
+					}
+					`),
+				},
+			},
+			expResp:   nil,
+			expErrStr: "",
+		},
+		{
+			name: "TrafficSplit with negative weight fails",
+			input: &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "split.smi-spec.io",
+					Version: "v1alpha2",
+					Kind:    "TrafficSplit",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`
+					{
+						"apiVersion": "split.smi-spec.io/v1alpha2",
+						"kind": "TrafficSplit",
+						"spec": {
+							"service": "apex",
+							"backends": [
+								{
+									"service": "v1",
+									"weight": -10
+								}
+							]
+						}
+					}
+					`),
+				},
+			},
+			expResp:   nil,
+			expErrStr: "Backend v1 has a negative weight -10",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			resp, err := trafficSplitValidator(tc.input)
+			assert.Equal(tc.expResp, resp)
+			if err != nil {
+				assert.Equal(tc.expErrStr, err.Error())
+			}
+		})
+	}
+}
+
+func TestHTTPRouteGroupValidator(t *testing.T) {
+	testCases := []struct {
+		name      string
+		spec      string
+		expErrStr string
+	}{
+		{
+			name:      "HTTPRouteGroup with valid regexes passes",
+			spec:      `{"matches": [{"name": "books", "pathRegex": "/books/[0-9]+", "methods": ["GET"], "headers": [{"user-agent": ".*Mozilla.*"}]}]}`,
+			expErrStr: "",
+		},
+		{
+			name:      "HTTPRouteGroup with an invalid path regex fails",
+			spec:      `{"matches": [{"name": "books", "pathRegex": "/books/(?!new)"}]}`,
+			expErrStr: "Match books has an invalid pathRegex /books/(?!new): error parsing regexp: invalid or unsupported Perl syntax: `(?!`",
+		},
+		{
+			name:      "HTTPRouteGroup with an invalid header regex fails",
+			spec:      `{"matches": [{"name": "books", "pathRegex": "/books", "headers": [{"host": "*.example.com"}]}]}`,
+			expErrStr: "Match books has an invalid regex *.example.com for header host: error parsing regexp: missing argument to repetition operator: `*`",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			req := &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "specs.smi-spec.io",
+					Version: "v1alpha4",
+					Kind:    "HTTPRouteGroup",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`{"apiVersion": "specs.smi-spec.io/v1alpha4", "kind": "HTTPRouteGroup", "spec": ` + tc.spec + `}`),
+				},
+			}
+
+			resp, err := httpRouteGroupValidator(req)
+			assert.Nil(resp)
+			if tc.expErrStr == "" {
+				assert.Nil(err)
+			} else {
+				assert.EqualError(err, tc.expErrStr)
+			}
+		})
+	}
+}
+
+func TestExternalWorkloadValidator(t *testing.T) {
+	testCases := []struct {
+		name      string
+		spec      string
+		expErrStr string
+	}{
+		{
+			name:      "ExternalWorkload with a valid spec passes",
+			spec:      `{"serviceAccount": "bookstore", "proxyUUID": "b2c4b7a0-4f0b-4c6e-9b4e-4f6a1d2c3b4a", "addresses": ["10.0.0.10", "fd00::10"], "ports": [{"name": "http", "number": 8080}, {"number": 9090}]}`,
+			expErrStr: "",
+		},
+		{
+			name:      "ExternalWorkload with an invalid service account fails",
+			spec:      `{"serviceAccount": "Book_Store", "proxyUUID": "b2c4b7a0-4f0b-4c6e-9b4e-4f6a1d2c3b4a", "addresses": ["10.0.0.10"]}`,
+			expErrStr: "Invalid 'serviceAccount' Book_Store",
+		},
+		{
+			name:      "ExternalWorkload with an invalid proxy UUID fails",
+			spec:      `{"serviceAccount": "bookstore", "proxyUUID": "not-a-uuid", "addresses": ["10.0.0.10"]}`,
+			expErrStr: "Invalid 'proxyUUID' not-a-uuid",
+		},
+		{
+			name:      "ExternalWorkload without addresses fails",
+			spec:      `{"serviceAccount": "bookstore", "proxyUUID": "b2c4b7a0-4f0b-4c6e-9b4e-4f6a1d2c3b4a", "addresses": []}`,
+			expErrStr: "At least one address must be specified in 'addresses'",
+		},
+		{
+			name:      "ExternalWorkload with a hostname address fails",
+			spec:      `{"serviceAccount": "bookstore", "proxyUUID": "b2c4b7a0-4f0b-4c6e-9b4e-4f6a1d2c3b4a", "addresses": ["vm.example.com"]}`,
+			expErrStr: "Expected 'addresses' to be IP addresses, got: vm.example.com",
+		},
+		{
+			name:      "ExternalWorkload with a loopback address fails",
+			spec:      `{"serviceAccount": "bookstore", "proxyUUID": "b2c4b7a0-4f0b-4c6e-9b4e-4f6a1d2c3b4a", "addresses": ["127.0.0.1"]}`,
+			expErrStr: "Address 127.0.0.1 in 'addresses' is not routable",
+		},
+		{
+			name:      "ExternalWorkload with a duplicate address fails",
+			spec:      `{"serviceAccount": "bookstore", "proxyUUID": "b2c4b7a0-4f0b-4c6e-9b4e-4f6a1d2c3b4a", "addresses": ["10.0.0.10", "10.0.0.10"]}`,
+			expErrStr: "Address 10.0.0.10 is specified more than once in 'addresses'",
+		},
+		{
+			name:      "ExternalWorkload with an out of range port fails",
+			spec:      `{"serviceAccount": "bookstore", "proxyUUID": "b2c4b7a0-4f0b-4c6e-9b4e-4f6a1d2c3b4a", "addresses": ["10.0.0.10"], "ports": [{"number": 70000}]}`,
+			expErrStr: "Port number 70000 is not in the range 1-65535",
+		},
+		{
+			name:      "ExternalWorkload with a duplicate port number fails",
+			spec:      `{"serviceAccount": "bookstore", "proxyUUID": "b2c4b7a0-4f0b-4c6e-9b4e-4f6a1d2c3b4a", "addresses": ["10.0.0.10"], "ports": [{"name": "http", "number": 8080}, {"name": "metrics", "number": 8080}]}`,
+			expErrStr: "Port number 8080 is specified more than once in 'ports'",
+		},
+		{
+			name:      "ExternalWorkload with a duplicate port name fails",
+			spec:      `{"serviceAccount": "bookstore", "proxyUUID": "b2c4b7a0-4f0b-4c6e-9b4e-4f6a1d2c3b4a", "addresses": ["10.0.0.10"], "ports": [{"name": "http", "number": 8080}, {"name": "http", "number": 9090}]}`,
+			expErrStr: "Port name http is specified more than once in 'ports'",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			req := &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "policy.openservicemesh.io",
+					Version: "v1alpha1",
+					Kind:    "ExternalWorkload",
+				},
+				Object: runtime.RawExtension{
+					Raw: []byte(`{"apiVersion": "policy.openservicemesh.io/v1alpha1", "kind": "ExternalWorkload", "spec": ` + tc.spec + `}`),
+				},
+			}
+
+			resp, err := externalWorkloadValidator(req)
+			assert.Nil(resp)
+			if tc.expErrStr == "" {
+				assert.Nil(err)
+				return
+			}
+			assert.NotNil(err)
+			assert.Contains(err.Error(), tc.expErrStr)
+		})
+	}
+}
+
+func TestUpstreamTrafficSettingValidator(t *testing.T) {
+	testCases := []struct {
+		name      string
+		spec      string
+		expErrStr string
+	}{
+		{
+			name:      "UpstreamTrafficSetting with a valid spec passes",
+			spec:      `{"host": "bookstore.test.svc.cluster.local", "peerValidation": {"trustDomainAliases": ["old.example.com"], "subjectAltNames": ["spiffe://old.example.com/ns/test/sa/bookstore"]}}`,
+			expErrStr: "",
+		},
+		{
+			name:      "UpstreamTrafficSetting without peer validation passes",
+			spec:      `{"host": "bookstore.test.svc.cluster.local"}`,
+			expErrStr: "",
+		},
+		{
+			name:      "UpstreamTrafficSetting for a service in another namespace fails",
+			spec:      `{"host": "bookstore.other.svc.cluster.local"}`,
+			expErrStr: "Expected 'host' to be the FQDN of a service in namespace test of the form <service>.test.svc.cluster.local, got: bookstore.other.svc.cluster.local",
+		},
+		{
+			name:      "UpstreamTrafficSetting with an invalid service name fails",
+			spec:      `{"host": "Book_Store.test.svc.cluster.local"}`,
+			expErrStr: "Invalid service name Book_Store in 'host'",
+		},
+		{
+			name:      "UpstreamTrafficSetting with an invalid trust domain alias fails",
+			spec:      `{"host": "bookstore.test.svc.cluster.local", "peerValidation": {"trustDomainAliases": ["spiffe://old.example.com"]}}`,
+			expErrStr: "Invalid trust domain spiffe://old.example.com in 'peerValidation.trustDomainAliases'",
+		},
+		{
+			name:      "UpstreamTrafficSetting with a duplicate trust domain alias fails",
+			spec:      `{"host": "bookstore.test.svc.cluster.local", "peerValidation": {"trustDomainAliases": ["old.example.com", "old.example.com"]}}`,
+			expErrStr: "Trust domain old.example.com is specified more than once in 'peerValidation.trustDomainAliases'",
+		},
+		{
+			name:      "UpstreamTrafficSetting with an empty SAN fails",
+			spec:      `{"host": "bookstore.test.svc.cluster.local", "peerValidation": {"subjectAltNames": [""]}}`,
+			expErrStr: "Empty Subject Alternative Name in 'peerValidation.subjectAltNames'",
+		},
+		{
+			name:      "UpstreamTrafficSetting with a duplicate SAN fails",
+			spec:      `{"host": "bookstore.test.svc.cluster.local", "peerValidation": {"subjectAltNames": ["bookstore.example.com", "bookstore.example.com"]}}`,
+			expErrStr: "Subject Alternative Name bookstore.example.com is specified more than once in 'peerValidation.subjectAltNames'",
+		},
+		{
+			name:      "UpstreamTrafficSetting with a valid retry policy passes",
+			spec:      `{"host": "bookstore.test.svc.cluster.local", "retryPolicy": {"retryOn": "5xx,reset", "numRetries": 2, "perTryTimeout": "250ms", "idempotent": true}}`,
+			expErrStr: "",
+		},
+		{
+			name:      "UpstreamTrafficSetting with an empty retry condition fails",
+			spec:      `{"host": "bookstore.test.svc.cluster.local", "retryPolicy": {"retryOn": "5xx,,reset"}}`,
+			expErrStr: "Empty retry condition in 'retryPolicy.retryOn': 5xx,,reset",
+		},
+		{
+			name:      "UpstreamTrafficSetting with a zero per-try timeout fails",
+			spec:      `{"host": "bookstore.test.svc.cluster.local", "retryPolicy": {"perTryTimeout": "0s"}}`,
+			expErrStr: "Expected 'retryPolicy.perTryTimeout' to be greater than 0, got: 0s",
+		},
+		{
+			name:      "UpstreamTrafficSetting with idempotent requests without a per-try timeout fails",
+			spec:      `{"host": "bookstore.test.svc.cluster.local", "retryPolicy": {"idempotent": true}}`,
+			expErrStr: "'retryPolicy.idempotent' requires 'retryPolicy.perTryTimeout'",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			req := &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "policy.openservicemesh.io",
+					Version: "v1alpha1",
+					Kind:    "UpstreamTrafficSetting",
+				},
+				Namespace: "test",
+				Object: runtime.RawExtension{
+					Raw: []byte(`{"apiVersion": "policy.openservicemesh.io/v1alpha1", "kind": "UpstreamTrafficSetting", "spec": ` + tc.spec + `}`),
+				},
+			}
+
+			resp, err := upstreamTrafficSettingValidator(req)
+			assert.Nil(resp)
+			if tc.expErrStr == "" {
+				assert.Nil(err)
+				return
+			}
+			assert.NotNil(err)
+			assert.Contains(err.Error(), tc.expErrStr)
+		})
+	}
+}
+
+func TestNamespaceIsolationValidator(t *testing.T) {
+	testCases := []struct {
+		name      string
+		spec      string
+		expErrStr string
+	}{
+		{
+			name:      "NamespaceIsolation with a valid spec passes",
+			spec:      `{"allowedNamespaces": ["shared", "monitoring"]}`,
+			expErrStr: "",
+		},
+		{
+			name:      "NamespaceIsolation without allowed namespaces passes",
+			spec:      `{"allowedNamespaces": []}`,
+			expErrStr: "",
+		},
+		{
+			name:      "NamespaceIsolation with an invalid namespace fails",
+			spec:      `{"allowedNamespaces": ["Shared_NS"]}`,
+			expErrStr: "Invalid namespace Shared_NS in 'allowedNamespaces'",
+		},
+		{
+			name:      "NamespaceIsolation with a duplicate namespace fails",
+			spec:      `{"allowedNamespaces": ["shared", "shared"]}`,
+			expErrStr: "Namespace shared is specified more than once in 'allowedNamespaces'",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+
+			req := &admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   "policy.openservicemesh.io",
+					Version: "v1alpha1",
+					Kind:    "NamespaceIsolation",
+				},
+				Namespace: "test",
+				Object: runtime.RawExtension{
+					Raw: []byte(`{"apiVersion": "policy.openservicemesh.io/v1alpha1", "kind": "NamespaceIsolation", "spec": ` + tc.spec + `}`),
+				},
+			}
+
+			resp, err := namespaceIsolationValidator(req)
+			assert.Nil(resp)
+			if tc.expErrStr == "" {
+				assert.Nil(err)
+				return
+			}
+			assert.NotNil(err)
+			assert.Contains(err.Error(), tc.expErrStr)
+		})
+	}
+}
+
 func TestMulticlusterServiceValidator(t *testing.T) {
 	assert := tassert.New(t)
 	testCases := []struct {
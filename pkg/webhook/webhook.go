@@ -2,14 +2,20 @@
 package webhook
 
 import (
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 
+	"github.com/openservicemesh/osm/pkg/certificate"
 	"github.com/openservicemesh/osm/pkg/errcode"
 	"github.com/openservicemesh/osm/pkg/logger"
 )
@@ -67,3 +73,33 @@ func AdmissionError(err error) *admissionv1.AdmissionResponse {
 		},
 	}
 }
+
+// ValidateURL checks that the given base URL, at which the API server reaches a webhook running outside the
+// cluster, is of the form https://host[:port]
+func ValidateURL(webhookURL string) error {
+	parsed, err := url.Parse(webhookURL)
+	if err != nil {
+		return errors.Errorf("Invalid webhook URL %s: %s", webhookURL, err)
+	}
+	if parsed.Scheme != "https" || parsed.Hostname() == "" {
+		return errors.Errorf("Invalid webhook URL %s, expected https://host[:port]", webhookURL)
+	}
+	return nil
+}
+
+// GetURL returns the URL at which the API server reaches the given path of a webhook served at the base URL
+func GetURL(webhookURL string, path string) string {
+	return strings.TrimRight(webhookURL, "/") + path
+}
+
+// GetCommonName returns the common name of a webhook's certificate, which must match the host the API server uses
+// to reach the webhook: the host of the webhook URL if one is set, or the given service otherwise.
+// Certificates issued for an IP host carry it as an IP SAN.
+func GetCommonName(webhookURL string, serviceName string, namespace string) certificate.CommonName {
+	if webhookURL != "" {
+		if parsed, err := url.Parse(webhookURL); err == nil && parsed.Hostname() != "" {
+			return certificate.CommonName(parsed.Hostname())
+		}
+	}
+	return certificate.CommonName(fmt.Sprintf("%s.%s.svc", serviceName, namespace))
+}
@@ -11,6 +11,8 @@ import (
 	tassert "github.com/stretchr/testify/assert"
 	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
 )
 
 var (
@@ -79,3 +81,51 @@ func TestAdmissionError(t *testing.T) {
 type err int
 
 func (err) Read(_ []byte) (i int, err error) { return 1, errorTest }
+
+func TestValidateURL(t *testing.T) {
+	testCases := []struct {
+		name      string
+		url       string
+		expectErr bool
+	}{
+		{name: "https URL with a DNS host", url: "https://osm.example.com:9093", expectErr: false},
+		{name: "https URL with an IP host", url: "https://10.0.0.1:9093/", expectErr: false},
+		{name: "http URL", url: "http://osm.example.com:9093", expectErr: true},
+		{name: "URL without a host", url: "https://", expectErr: true},
+		{name: "unparseable URL", url: "https://[::1", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expectErr, ValidateURL(tc.url) != nil)
+		})
+	}
+}
+
+func TestGetURL(t *testing.T) {
+	assert := tassert.New(t)
+
+	assert.Equal("https://osm.example.com:9093/validate", GetURL("https://osm.example.com:9093", "/validate"))
+	assert.Equal("https://osm.example.com:9093/validate", GetURL("https://osm.example.com:9093/", "/validate"))
+}
+
+func TestGetCommonName(t *testing.T) {
+	testCases := []struct {
+		name       string
+		url        string
+		expectedCN certificate.CommonName
+	}{
+		{name: "no URL", url: "", expectedCN: "osm-injector.osm-system.svc"},
+		{name: "URL with a DNS host", url: "https://osm.example.com:9090", expectedCN: "osm.example.com"},
+		{name: "URL with an IP host", url: "https://10.0.0.1:9090", expectedCN: "10.0.0.1"},
+		{name: "URL with an IPv6 host", url: "https://[fd00::1]:9090", expectedCN: "fd00::1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := tassert.New(t)
+			assert.Equal(tc.expectedCN, GetCommonName(tc.url, "osm-injector", "osm-system"))
+		})
+	}
+}
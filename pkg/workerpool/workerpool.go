@@ -1,6 +1,7 @@
 package workerpool
 
 import (
+	"math/rand"
 	"runtime"
 	"sync"
 	"sync/atomic"
@@ -89,6 +90,20 @@ func (wp *WorkerPool) AddJob(job Job) <-chan struct{} {
 	return job.GetDoneCh()
 }
 
+// AddJobWithJitter posts the job on a worker queue after a random delay of up to maxJitter, spreading out the jobs
+// added at the same time. The job is posted right away if maxJitter is not positive.
+func (wp *WorkerPool) AddJobWithJitter(job Job, maxJitter time.Duration) <-chan struct{} {
+	if maxJitter <= 0 {
+		return wp.AddJob(job)
+	}
+
+	delay := time.Duration(rand.Int63n(int64(maxJitter))) // #nosec G404
+	time.AfterFunc(delay, func() {
+		wp.AddJob(job)
+	})
+	return job.GetDoneCh()
+}
+
 // AddJobRoundRobin adds a job in round robin to the queues
 // Concurrent calls to AddJobRoundRobin are thread safe and fair
 // between each other
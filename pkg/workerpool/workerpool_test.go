@@ -3,6 +3,7 @@ package workerpool
 import (
 	"runtime"
 	"testing"
+	"time"
 
 	tassert "github.com/stretchr/testify/assert"
 )
@@ -104,3 +105,50 @@ func TestAddJobRoundRobin(t *testing.T) {
 		assert.Equal(uint64(1), wp.workerContext[i].jobsProcessed)
 	}
 }
+
+// Uses AddJobWithJitter, which delays the jobs randomly before relying on job hash for queue assignment
+func TestAddJobWithJitter(t *testing.T) {
+	assert := tassert.New(t)
+
+	njobs := 10 // also worker routines
+	maxJitter := 100 * time.Millisecond
+	wp := NewWorkerPool(njobs)
+	joblist := make([]testJob, njobs)
+
+	start := time.Now()
+
+	// Create and add jobs
+	for i := 0; i < njobs; i++ {
+		joblist[i] = testJob{
+			jobDone: make(chan struct{}, 1),
+			hash:    uint64(i),
+		}
+
+		wp.AddJobWithJitter(&joblist[i], maxJitter)
+	}
+
+	// Verify all jobs ran through the workers, no later than the maximum jitter allows
+	for i := 0; i < njobs; i++ {
+		select {
+		case <-joblist[i].jobDone:
+		case <-time.After(maxJitter + time.Second):
+			assert.Fail("Job was not run in time", "job %d", i)
+		}
+	}
+	assert.Less(int64(time.Since(start)), int64(maxJitter+time.Second))
+
+	wp.Stop()
+
+	for i := 0; i < njobs; i++ {
+		assert.Equal(uint64(1), wp.workerContext[i].jobsProcessed)
+	}
+
+	// Without jitter, the job is posted right away
+	wp = NewWorkerPool(1)
+	job := testJob{
+		jobDone: make(chan struct{}, 1),
+	}
+	<-wp.AddJobWithJitter(&job, 0)
+	wp.Stop()
+	assert.Equal(uint64(1), wp.workerContext[0].jobsProcessed)
+}
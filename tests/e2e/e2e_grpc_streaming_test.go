@@ -0,0 +1,235 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	smiAccess "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
+	smiSpecs "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/openservicemesh/osm/tests/framework"
+)
+
+const (
+	// grpcReplyField is the field of the responses of the hello.HelloService of grpcbin
+	grpcReplyField = `"reply"`
+
+	// grpcStreamMessages is the number of messages sent over the long-lived stream
+	grpcStreamMessages = 12
+
+	// grpcStreamMessageInterval is the interval at which the messages are sent over the long-lived stream
+	grpcStreamMessageInterval = 5 * time.Second
+)
+
+// This test originates unary, server streaming and bidirectional streaming gRPC traffic between a client and server,
+// allowed by SMI HTTP routes matching gRPC services and methods, and verifies that long-lived streams survive the xDS
+// updates made while they are open.
+// <Client app plaintext request> --> <local proxy> -- |mTLS over HTTP routes| --> <remote proxy> -- <server app plaintext request>
+var _ = OSMDescribe("gRPC streaming traffic for client pod -> server pod using gRPC method routes",
+	OSMDescribeInfo{
+		Tier:   1,
+		Bucket: 1,
+	},
+	func() {
+		Context("gRPC unary, server streaming and bidirectional streaming traffic with SMI HTTP routes", func() {
+			testGRPCStreamingTraffic()
+		})
+	})
+
+func testGRPCStreamingTraffic() {
+	const sourceName = "client"
+	const destName = "server"
+	const trafficTargetName = "grpc-target"
+	const trafficRouteName = "grpc-routes"
+	const helloMatchName = "hello-service"
+	const indexMatchName = "grpcbin-index"
+	var ns = []string{sourceName, destName}
+
+	It("Tests gRPC streaming traffic for client pod -> server pod using gRPC method routes", func() {
+		// Install OSM
+		Expect(Td.InstallOSM(Td.GetOSMInstallOpts())).To(Succeed())
+
+		// Create Test NS
+		for _, n := range ns {
+			Expect(Td.CreateNs(n, nil)).To(Succeed())
+			Expect(Td.AddNsToMesh(true, n)).To(Succeed())
+		}
+
+		// Get simple pod definitions for the gRPC server
+		svcAccDef, podDef, svcDef, err := Td.SimplePodApp(
+			SimplePodAppDef{
+				Name:        destName,
+				Namespace:   destName,
+				Image:       "moul/grpcbin",
+				Ports:       []int{grpcbinInsecurePort},
+				AppProtocol: "grpc",
+				OS:          Td.ClusterOS,
+			})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = Td.CreateServiceAccount(destName, &svcAccDef)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = Td.CreatePod(destName, podDef)
+		Expect(err).NotTo(HaveOccurred())
+		dstSvc, err := Td.CreateService(destName, svcDef)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Expect it to be up and running in it's receiver namespace
+		Expect(Td.WaitForPodsRunningReady(destName, 90*time.Second, 1, nil)).To(Succeed())
+
+		srcPod := setupGRPCClient(sourceName)
+
+		By("Creating SMI policies allowing the methods of the hello.HelloService")
+		// The route group matches all the methods of the hello.HelloService with a regex
+		httpRG := smiSpecs.HTTPRouteGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: trafficRouteName,
+			},
+			Spec: smiSpecs.HTTPRouteGroupSpec{
+				Matches: []smiSpecs.HTTPMatch{
+					{
+						Name:      helloMatchName,
+						PathRegex: "/hello.HelloService/.*",
+						Methods:   []string{"POST"},
+					},
+				},
+			},
+		}
+		trafficTarget := smiAccess.TrafficTarget{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: trafficTargetName,
+			},
+			Spec: smiAccess.TrafficTargetSpec{
+				Sources: []smiAccess.IdentityBindingSubject{
+					{
+						Kind:      "ServiceAccount",
+						Name:      sourceName,
+						Namespace: sourceName,
+					},
+				},
+				Destination: smiAccess.IdentityBindingSubject{
+					Kind:      "ServiceAccount",
+					Name:      destName,
+					Namespace: destName,
+				},
+				Rules: []smiAccess.TrafficTargetRule{
+					{
+						Kind:    "HTTPRouteGroup",
+						Name:    trafficRouteName,
+						Matches: []string{helloMatchName},
+					},
+				},
+			},
+		}
+
+		// Configs have to be put into a monitored NS
+		_, err = Td.CreateHTTPRouteGroup(sourceName, httpRG)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = Td.CreateTrafficTarget(sourceName, trafficTarget)
+		Expect(err).NotTo(HaveOccurred())
+
+		request := func(symbol string, jsonRequest string) GRPCRequestDef {
+			return GRPCRequestDef{
+				SourceNs:        sourceName,
+				SourcePod:       srcPod.Name,
+				SourceContainer: sourceName,
+
+				Destination: fmt.Sprintf("%s.%s:%d", dstSvc.Name, dstSvc.Namespace, grpcbinInsecurePort),
+
+				JSONRequest: jsonRequest,
+				Symbol:      symbol,
+				UseTLS:      false, // insecure gRPC, service mesh will upgrade connection to mTLS
+			}
+		}
+		expectReplies := func(req GRPCRequestDef, minReplies int) {
+			srcToDestStr := fmt.Sprintf("%s/%s -> %s %s", req.SourceNs, req.SourcePod, req.Destination, req.Symbol)
+
+			cond := Td.WaitForRepeatedSuccess(func() bool {
+				result := Td.GRPCRequest(req)
+				if result.Err != nil {
+					Td.T.Logf("> (%s) gRPC req failed, response: %s, err: %s", srcToDestStr, result.Response, result.Err)
+					return false
+				}
+				if replies := strings.Count(result.Response, grpcReplyField); replies < minReplies {
+					Td.T.Logf("> (%s) gRPC req got %d replies, expected at least %d, response: %s", srcToDestStr, replies, minReplies, result.Response)
+					return false
+				}
+				Td.T.Logf("> (%s) gRPC req succeeded, response: %s", srcToDestStr, result.Response)
+				return true
+			}, 5, 90*time.Second)
+			Expect(cond).To(BeTrue(), "Failed testing gRPC traffic for: %s", srcToDestStr)
+		}
+		expectDenied := func(req GRPCRequestDef) {
+			srcToDestStr := fmt.Sprintf("%s/%s -> %s %s", req.SourceNs, req.SourcePod, req.Destination, req.Symbol)
+
+			cond := Td.WaitForRepeatedSuccess(func() bool {
+				result := Td.GRPCRequest(req)
+				if result.Err == nil {
+					Td.T.Logf("> (%s) gRPC req did not fail, expected it to fail, response: %s", srcToDestStr, result.Response)
+					return false
+				}
+				Td.T.Logf("> (%s) gRPC req failed correctly, response: %s, err: %s", srcToDestStr, result.Response, result.Err)
+				return true
+			}, 5, 90*time.Second)
+			Expect(cond).To(BeTrue(), "gRPC traffic not denied for: %s", srcToDestStr)
+		}
+
+		By("Sending unary gRPC requests")
+		expectReplies(request("hello.HelloService/SayHello", `{"greeting": "client"}`), 1)
+
+		By("Sending server streaming gRPC requests")
+		expectReplies(request("hello.HelloService/LotsOfReplies", `{"greeting": "client"}`), 2)
+
+		By("Sending bidirectional streaming gRPC requests")
+		expectReplies(request("hello.HelloService/BidiHello", `{"greeting": "one"} {"greeting": "two"} {"greeting": "three"}`), 3)
+
+		By("Denying the gRPC methods not matched by the SMI policies")
+		indexRequest := request("grpcbin.GRPCBin/Index", `{}`)
+		expectDenied(indexRequest)
+
+		By("Opening a long-lived bidirectional stream")
+		var messages []string
+		for i := 0; i < grpcStreamMessages; i++ {
+			messages = append(messages, fmt.Sprintf(`{"greeting": "message-%d"}`, i))
+		}
+		streamResult := make(chan GRPCRequestResult, 1)
+		go func() {
+			defer GinkgoRecover()
+			streamResult <- Td.GRPCStreamRequest(request("hello.HelloService/BidiHello", ""), messages, grpcStreamMessageInterval)
+		}()
+
+		By("Updating the SMI policies to allow the grpcbin.GRPCBin/Index method while the stream is open")
+		rg, err := Td.SmiClients.SpecClient.SpecsV1alpha4().HTTPRouteGroups(sourceName).Get(context.TODO(), trafficRouteName, metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		rg.Spec.Matches = append(rg.Spec.Matches, smiSpecs.HTTPMatch{
+			Name:      indexMatchName,
+			PathRegex: "/grpcbin.GRPCBin/Index",
+			Methods:   []string{"POST"},
+		})
+		_, err = Td.SmiClients.SpecClient.SpecsV1alpha4().HTTPRouteGroups(sourceName).Update(context.TODO(), rg, metav1.UpdateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		tt, err := Td.SmiClients.AccessClient.AccessV1alpha3().TrafficTargets(sourceName).Get(context.TODO(), trafficTargetName, metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		tt.Spec.Rules[0].Matches = append(tt.Spec.Rules[0].Matches, indexMatchName)
+		_, err = Td.SmiClients.AccessClient.AccessV1alpha3().TrafficTargets(sourceName).Update(context.TODO(), tt, metav1.UpdateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		// The method is allowed once the proxies received the xDS updates
+		expectReplies(indexRequest, 0)
+
+		By("Expecting the long-lived stream to survive the xDS updates")
+		streamTimeout := grpcStreamMessages*grpcStreamMessageInterval + 60*time.Second
+		var result GRPCRequestResult
+		Eventually(streamResult, streamTimeout).Should(Receive(&result))
+		Expect(result.Err).NotTo(HaveOccurred(), "Long-lived gRPC stream failed, response: %s", result.Response)
+		Expect(strings.Count(result.Response, grpcReplyField)).To(Equal(grpcStreamMessages),
+			"Long-lived gRPC stream did not get a reply to every message, response: %s", result.Response)
+	})
+}
@@ -0,0 +1,96 @@
+package e2e
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+
+	. "github.com/openservicemesh/osm/tests/framework"
+)
+
+var _ = OSMDescribe("Test HTTP traffic on an IPv6 cluster",
+	OSMDescribeInfo{
+		Tier:        2,
+		Bucket:      10,
+		KindCluster: KindClusterSpec{IPFamily: v1alpha4.IPv6Family},
+	},
+	func() {
+		const sourceNs = "client"
+		const destNs = "server"
+
+		It("Tests HTTP traffic from a client pod to a server service over IPv6", func() {
+			ipv6, err := Td.IsIPv6Cluster()
+			Expect(err).NotTo(HaveOccurred())
+			if !ipv6 {
+				Skip("The cluster does not use IPv6 networking")
+			}
+
+			// Install OSM
+			installOpts := Td.GetOSMInstallOpts()
+			installOpts.EnablePermissiveMode = true
+			Expect(Td.InstallOSM(installOpts)).To(Succeed())
+
+			for _, ns := range []string{sourceNs, destNs} {
+				Expect(Td.CreateNs(ns, nil)).To(Succeed())
+				Expect(Td.AddNsToMesh(true, ns)).To(Succeed())
+			}
+
+			// Server
+			svcAccDef, podDef, svcDef, err := Td.SimplePodApp(
+				SimplePodAppDef{
+					Name:      "server",
+					Namespace: destNs,
+					Image:     "kennethreitz/httpbin",
+					Ports:     []int{80},
+					OS:        Td.ClusterOS,
+				})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = Td.CreateServiceAccount(destNs, &svcAccDef)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = Td.CreatePod(destNs, podDef)
+			Expect(err).NotTo(HaveOccurred())
+			dstSvc, err := Td.CreateService(destNs, svcDef)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(Td.WaitForPodsRunningReady(destNs, 90*time.Second, 1, nil)).To(Succeed())
+
+			// Client
+			svcAccDef, podDef, _, err = Td.SimplePodApp(SimplePodAppDef{
+				Name:      "client",
+				Namespace: sourceNs,
+				Command:   []string{"/bin/bash", "-c", "--"},
+				Args:      []string{"while true; do sleep 30; done;"},
+				Image:     "songrgg/alpine-debug",
+				Ports:     []int{80},
+				OS:        Td.ClusterOS,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = Td.CreateServiceAccount(sourceNs, &svcAccDef)
+			Expect(err).NotTo(HaveOccurred())
+			srcPod, err := Td.CreatePod(sourceNs, podDef)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(Td.WaitForPodsRunningReady(sourceNs, 90*time.Second, 1, nil)).To(Succeed())
+
+			req := HTTPRequestDef{
+				SourceNs:        srcPod.Namespace,
+				SourcePod:       srcPod.Name,
+				SourceContainer: "client",
+
+				Destination: fmt.Sprintf("%s.%s", dstSvc.Name, dstSvc.Namespace),
+			}
+
+			By("Ensuring traffic to the IPv6 service is allowed in permissive mode")
+			cond := Td.WaitForRepeatedSuccess(func() bool {
+				result := Td.HTTPRequest(req)
+				if result.Err != nil || result.StatusCode != 200 {
+					Td.T.Logf("> REST req failed (status: %d) %v", result.StatusCode, result.Err)
+					return false
+				}
+				Td.T.Logf("> REST req succeeded: %d", result.StatusCode)
+				return true
+			}, 5 /*consecutive success threshold*/, 90*time.Second /*timeout*/)
+			Expect(cond).To(BeTrue())
+		})
+	})
@@ -0,0 +1,180 @@
+package e2e
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/openservicemesh/osm/tests/framework"
+)
+
+// Parameters and regression thresholds of the proxy churn scenario, which can be raised to run it at scale
+var (
+	churnNamespaces  = flag.Int("churnNamespaces", 2, "Number of namespaces of the proxy churn scenario")
+	churnDeployments = flag.Int("churnDeployments", 3, "Number of deployments per namespace of the proxy churn scenario")
+	churnIterations  = flag.Int("churnIterations", 3, "Number of times the proxy churn scenario scales the deployments up and down")
+
+	churnMaxXDSLatencyP99 = flag.Duration("churnMaxXDSLatencyP99", 5*time.Second,
+		"Maximum 99th percentile of the xDS configuration generation time during the proxy churn scenario")
+	churnMaxConvergenceTime = flag.Duration("churnMaxConvergenceTime", 3*time.Minute,
+		"Maximum time for the connected proxies to converge after each scaling of the proxy churn scenario")
+	churnMaxBroadcastsPerScaling = flag.Float64("churnMaxBroadcastsPerScaling", 50,
+		"Maximum average number of proxy broadcasts per scaling of the proxy churn scenario, 0 to not check it")
+)
+
+const (
+	// churnMetricsLocalPort is the local port the metrics of osm-controller are port forwarded from
+	churnMetricsLocalPort = 19091
+
+	// churnPollInterval is the interval at which the connected proxies are counted while waiting for convergence
+	churnPollInterval = 2 * time.Second
+)
+
+var _ = OSMDescribe("Proxy churn performance",
+	OSMDescribeInfo{
+		Tier:   2,
+		Bucket: 10,
+	},
+	func() {
+		It("Measures the xDS latency, broadcasts and convergence time while deployments are continuously scaled", func() {
+			Expect(Td.InstallOSM(Td.GetOSMInstallOpts())).To(Succeed())
+
+			var namespaces []string
+			for i := 0; i < *churnNamespaces; i++ {
+				namespaces = append(namespaces, fmt.Sprintf("churn-%d", i))
+			}
+			Expect(Td.CreateMultipleNs(namespaces...)).To(Succeed())
+			Expect(Td.AddNsToMesh(true, namespaces...)).To(Succeed())
+
+			By(fmt.Sprintf("Creating %d deployments in each of %d namespaces", *churnDeployments, *churnNamespaces))
+			var wg sync.WaitGroup
+			for _, ns := range namespaces {
+				for i := 0; i < *churnDeployments; i++ {
+					svcAccDef, deploymentDef, svcDef, err := Td.SimpleDeploymentApp(
+						SimpleDeploymentAppDef{
+							Name:         fmt.Sprintf("app-%d", i),
+							Namespace:    ns,
+							ReplicaCount: 1,
+							Command:      []string{"/bin/bash", "-c", "--"},
+							Args:         []string{"while true; do sleep 30; done;"},
+							Image:        "songrgg/alpine-debug",
+							Ports:        []int{DefaultUpstreamServicePort},
+							OS:           Td.ClusterOS,
+						})
+					Expect(err).NotTo(HaveOccurred())
+					_, err = Td.CreateServiceAccount(ns, &svcAccDef)
+					Expect(err).NotTo(HaveOccurred())
+					_, err = Td.CreateDeployment(ns, deploymentDef)
+					Expect(err).NotTo(HaveOccurred())
+					_, err = Td.CreateService(ns, svcDef)
+					Expect(err).NotTo(HaveOccurred())
+				}
+
+				wg.Add(1)
+				go func(ns string) {
+					defer GinkgoRecover()
+					defer wg.Done()
+					Expect(Td.WaitForPodsRunningReady(ns, 200*time.Second, *churnDeployments, nil)).To(Succeed())
+				}(ns)
+			}
+			wg.Wait()
+
+			proxies := *churnNamespaces * *churnDeployments
+			convergence, err := waitForConnectedProxies(proxies)
+			Expect(err).NotTo(HaveOccurred())
+			Td.T.Logf("%d proxies connected %s after their pods were ready", proxies, convergence)
+
+			before, err := Td.GetOSMControllerMetrics(Td.OsmNamespace, churnMetricsLocalPort)
+			Expect(err).NotTo(HaveOccurred())
+
+			var convergenceTimes []time.Duration
+			for iteration := 0; iteration < *churnIterations; iteration++ {
+				for _, replicas := range []int32{2, 1} {
+					By(fmt.Sprintf("Iteration %d: scaling the deployments to %d replicas", iteration, replicas))
+					start := time.Now()
+					Expect(scaleDeployments(namespaces, replicas)).To(Succeed())
+
+					_, err := waitForConnectedProxies(proxies * int(replicas))
+					Expect(err).NotTo(HaveOccurred())
+					convergence := time.Since(start)
+					Td.T.Logf("Proxies converged %s after scaling to %d replicas", convergence, replicas)
+					convergenceTimes = append(convergenceTimes, convergence)
+				}
+			}
+
+			after, err := Td.GetOSMControllerMetrics(Td.OsmNamespace, churnMetricsLocalPort)
+			Expect(err).NotTo(HaveOccurred())
+
+			xdsLatencyP99 := after.HistogramQuantile(before, "osm_proxy_config_update_time", map[string]string{"success": "true"}, 0.99)
+			xdsFailures := after.Value("osm_proxy_config_update_time_count", map[string]string{"success": "false"}) -
+				before.Value("osm_proxy_config_update_time_count", map[string]string{"success": "false"})
+			broadcasts := after.Value("osm_proxy_broadcast_event_count", nil) - before.Value("osm_proxy_broadcast_event_count", nil)
+			broadcastsPerScaling := broadcasts / float64(len(convergenceTimes))
+			var maxConvergence time.Duration
+			for _, convergence := range convergenceTimes {
+				if convergence > maxConvergence {
+					maxConvergence = convergence
+				}
+			}
+
+			Td.T.Logf("Proxy churn results for %d namespaces x %d deployments over %d iterations:", *churnNamespaces, *churnDeployments, *churnIterations)
+			Td.T.Logf("  xDS generation time p99: %.3fs (max %s), failed generations: %.0f", xdsLatencyP99, *churnMaxXDSLatencyP99, xdsFailures)
+			Td.T.Logf("  Broadcasts: %.0f, %.1f per scaling (max %.1f)", broadcasts, broadcastsPerScaling, *churnMaxBroadcastsPerScaling)
+			Td.T.Logf("  Convergence times: %v (max %s)", convergenceTimes, *churnMaxConvergenceTime)
+
+			Expect(math.IsNaN(xdsLatencyP99)).To(BeFalse(), "no xDS configuration was generated during the proxy churn")
+			Expect(xdsLatencyP99).To(BeNumerically("<=", churnMaxXDSLatencyP99.Seconds()))
+			Expect(maxConvergence).To(BeNumerically("<=", *churnMaxConvergenceTime))
+			if *churnMaxBroadcastsPerScaling > 0 {
+				Expect(broadcastsPerScaling).To(BeNumerically("<=", *churnMaxBroadcastsPerScaling))
+			}
+		})
+	})
+
+// scaleDeployments scales all the deployments in the given namespaces to the given number of replicas
+func scaleDeployments(namespaces []string, replicas int32) error {
+	for _, ns := range namespaces {
+		deployments, err := Td.Client.AppsV1().Deployments(ns).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "error listing the deployments in namespace %s", ns)
+		}
+		for _, deployment := range deployments.Items {
+			scale := &autoscalingv1.Scale{
+				ObjectMeta: metav1.ObjectMeta{Name: deployment.Name, Namespace: ns},
+				Spec:       autoscalingv1.ScaleSpec{Replicas: replicas},
+			}
+			if _, err := Td.Client.AppsV1().Deployments(ns).UpdateScale(context.TODO(), deployment.Name, scale, metav1.UpdateOptions{}); err != nil {
+				return errors.Wrapf(err, "error scaling deployment %s/%s", ns, deployment.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// waitForConnectedProxies waits for the given number of proxies to be connected to osm-controller, according to its
+// osm_proxy_connect_count metric, returning how long it waited
+func waitForConnectedProxies(expected int) (time.Duration, error) {
+	start := time.Now()
+	for {
+		metrics, err := Td.GetOSMControllerMetrics(Td.OsmNamespace, churnMetricsLocalPort)
+		if err != nil {
+			Td.T.Logf("Error fetching osm-controller metrics: %s", err)
+		} else if connected := int(metrics.Value("osm_proxy_connect_count", nil)); connected == expected {
+			return time.Since(start), nil
+		}
+
+		if time.Since(start) > *churnMaxConvergenceTime {
+			return time.Since(start), errors.Errorf("%d proxies were not connected within %s", expected, *churnMaxConvergenceTime)
+		}
+		time.Sleep(churnPollInterval)
+	}
+}
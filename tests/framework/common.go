@@ -41,8 +41,6 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/remotecommand"
-	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
-	"sigs.k8s.io/kind/pkg/cluster"
 	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
 
 	configV1alpha1 "github.com/openservicemesh/osm/pkg/apis/config/v1alpha1"
@@ -86,7 +84,9 @@ func (o OSMDescribeInfo) String() string {
 
 // OSMDescribe givens the description of an e2e test
 func OSMDescribe(name string, opts OSMDescribeInfo, body func()) bool {
-	return Describe(fmt.Sprintf("%s %s", opts, name), body)
+	text := fmt.Sprintf("%s %s", opts, name)
+	registerKindClusterSpec(text, opts.KindCluster)
+	return Describe(text, body)
 }
 
 const (
@@ -219,40 +219,9 @@ func (td *OsmTestData) InitTestData(t GinkgoTInterface) error {
 		return err
 	}
 
-	if (td.InstType == KindCluster) && td.ClusterProvider == nil {
-		td.ClusterProvider = cluster.NewProvider()
-		td.T.Logf("Creating local kind cluster")
-		clusterConfig := &v1alpha4.Cluster{
-			Nodes: []v1alpha4.Node{
-				{
-					Role: v1alpha4.ControlPlaneRole,
-				},
-				{
-					Role: v1alpha4.WorkerRole,
-					KubeadmConfigPatches: []string{`kind: JoinConfiguration
-nodeRegistration:
-  kubeletExtraArgs:
-    node-labels: "ingress-ready=true"`},
-					ExtraPortMappings: []v1alpha4.PortMapping{
-						{
-							ContainerPort: 80,
-							HostPort:      80,
-							Protocol:      v1alpha4.PortMappingProtocolTCP,
-						},
-					},
-				},
-				{
-					Role: v1alpha4.WorkerRole,
-				},
-			},
-		}
-		if Td.ClusterVersion != "" {
-			for i := 0; i < len(clusterConfig.Nodes); i++ {
-				clusterConfig.Nodes[i].Image = fmt.Sprintf("kindest/node:%s", td.ClusterVersion)
-			}
-		}
-		if err := td.ClusterProvider.Create(td.ClusterName, cluster.CreateWithV1Alpha4Config(clusterConfig)); err != nil {
-			return errors.Wrap(err, "failed to create kind cluster")
+	if td.InstType == KindCluster {
+		if err := td.ensureKindCluster(currentKindClusterSpec()); err != nil {
+			return err
 		}
 	}
 
@@ -0,0 +1,239 @@
+package framework
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openservicemesh/osm/pkg/constants"
+	"github.com/openservicemesh/osm/pkg/k8s"
+)
+
+// ControllerMetrics is a snapshot of the metrics osm-controller exposes in the Prometheus text format, so that tests
+// can measure the control plane without a Prometheus deployment
+type ControllerMetrics struct {
+	samples map[string][]metricSample
+}
+
+// metricSample is the value of a metric for a set of labels
+type metricSample struct {
+	labels map[string]string
+	value  float64
+}
+
+// GetOSMControllerMetrics returns a snapshot of the metrics of the osm-controller running in the given namespace,
+// port forwarded from the given local port
+func (td *OsmTestData) GetOSMControllerMetrics(controllerNs string, localPort uint16) (*ControllerMetrics, error) {
+	controllerPods, err := td.Client.CoreV1().Pods(controllerNs).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("app=%s", constants.OSMControllerName),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error fetching controller pod")
+	}
+	var controllerPod string
+	for _, pod := range controllerPods.Items {
+		if pod.Status.Phase == corev1.PodRunning {
+			controllerPod = pod.Name
+			break
+		}
+	}
+	if controllerPod == "" {
+		return nil, errors.Errorf("no running osm-controller pod in namespace %s", controllerNs)
+	}
+
+	dialer, err := k8s.DialerToPod(td.RestConfig, td.Client, controllerPod, controllerNs)
+	if err != nil {
+		return nil, err
+	}
+	portForwarder, err := k8s.NewPortForwarder(dialer, fmt.Sprintf("%d:%d", localPort, constants.OSMHTTPServerPort))
+	if err != nil {
+		return nil, errors.Errorf("Error setting up port forwarding: %s", err)
+	}
+
+	var metrics *ControllerMetrics
+	err = portForwarder.Start(func(pf *k8s.PortForwarder) error {
+		defer pf.Stop()
+		url := fmt.Sprintf("http://localhost:%d/metrics", localPort)
+
+		// #nosec G107: Potential HTTP request made with variable url
+		resp, err := http.Get(url)
+		if err != nil {
+			return errors.Errorf("Error fetching url %s: %s", url, err)
+		}
+		defer resp.Body.Close() //nolint: errcheck,gosec
+
+		metrics, err = parseControllerMetrics(resp.Body)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "error fetching the metrics of osm-controller pod %s", controllerPod)
+	}
+	return metrics, nil
+}
+
+// parseControllerMetrics parses metrics in the Prometheus text format
+func parseControllerMetrics(r io.Reader) (*ControllerMetrics, error) {
+	metrics := &ControllerMetrics{samples: make(map[string][]metricSample)}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, labels, rest, err := parseMetricNameAndLabels(line)
+		if err != nil {
+			return nil, err
+		}
+		// The value may be followed by a timestamp
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			return nil, errors.Errorf("metric without a value: %s", line)
+		}
+		value, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid metric value: %s", line)
+		}
+		metrics.samples[name] = append(metrics.samples[name], metricSample{labels: labels, value: value})
+	}
+	return metrics, scanner.Err()
+}
+
+// parseMetricNameAndLabels parses the name and labels of a metric sample line, returning the remainder of the line
+func parseMetricNameAndLabels(line string) (string, map[string]string, string, error) {
+	labels := make(map[string]string)
+	end := strings.IndexAny(line, "{ ")
+	if end < 0 {
+		return "", nil, "", errors.Errorf("metric without a value: %s", line)
+	}
+	name := line[:end]
+	if line[end] == ' ' {
+		return name, labels, line[end:], nil
+	}
+
+	i := end + 1
+	for i < len(line) && line[i] != '}' {
+		eq := strings.IndexByte(line[i:], '=')
+		if eq < 0 || i+eq+1 >= len(line) || line[i+eq+1] != '"' {
+			return "", nil, "", errors.Errorf("invalid metric labels: %s", line)
+		}
+		key := strings.TrimSpace(line[i : i+eq])
+		i += eq + 2
+
+		var value strings.Builder
+		for ; i < len(line) && line[i] != '"'; i++ {
+			if line[i] == '\\' && i+1 < len(line) {
+				i++
+				if line[i] == 'n' {
+					value.WriteByte('\n')
+					continue
+				}
+			}
+			value.WriteByte(line[i])
+		}
+		if i >= len(line) {
+			return "", nil, "", errors.Errorf("invalid metric labels: %s", line)
+		}
+		labels[key] = value.String()
+		i++ // closing quote
+		if i < len(line) && line[i] == ',' {
+			i++
+		}
+	}
+	if i >= len(line) {
+		return "", nil, "", errors.Errorf("invalid metric labels: %s", line)
+	}
+	return name, labels, line[i+1:], nil
+}
+
+// Value returns the sum of the values of the given metric over the samples having the given labels, 0 if none
+func (m *ControllerMetrics) Value(name string, labels map[string]string) float64 {
+	var sum float64
+	for _, sample := range m.samples[name] {
+		if hasLabels(sample.labels, labels) {
+			sum += sample.value
+		}
+	}
+	return sum
+}
+
+// HistogramQuantile returns the given quantile of the observations of the given histogram with the given labels made
+// since the given snapshot, nil to consider all observations, interpolated within buckets the same way as the
+// histogram_quantile function of Prometheus. It returns NaN if there are no such observations.
+func (m *ControllerMetrics) HistogramQuantile(since *ControllerMetrics, name string, labels map[string]string, q float64) float64 {
+	buckets := m.histogramBuckets(name, labels)
+	if since != nil {
+		sinceBuckets := since.histogramBuckets(name, labels)
+		for upperBound := range buckets {
+			buckets[upperBound] -= sinceBuckets[upperBound]
+		}
+	}
+
+	var upperBounds []float64
+	for upperBound := range buckets {
+		upperBounds = append(upperBounds, upperBound)
+	}
+	sort.Float64s(upperBounds)
+	if len(upperBounds) == 0 {
+		return math.NaN()
+	}
+	total := buckets[upperBounds[len(upperBounds)-1]]
+	if total == 0 {
+		return math.NaN()
+	}
+
+	rank := q * total
+	lowerBound, lowerCount := 0.0, 0.0
+	for _, upperBound := range upperBounds {
+		count := buckets[upperBound]
+		if count >= rank {
+			if math.IsInf(upperBound, 1) {
+				// Observations above the highest bucket are reported at its bound
+				return lowerBound
+			}
+			if count == lowerCount {
+				return upperBound
+			}
+			return lowerBound + (upperBound-lowerBound)*(rank-lowerCount)/(count-lowerCount)
+		}
+		lowerBound, lowerCount = upperBound, count
+	}
+	return lowerBound
+}
+
+// histogramBuckets returns the cumulative counts of the buckets of the given histogram with the given labels, by
+// upper bound
+func (m *ControllerMetrics) histogramBuckets(name string, labels map[string]string) map[float64]float64 {
+	buckets := make(map[float64]float64)
+	for _, sample := range m.samples[name+"_bucket"] {
+		if !hasLabels(sample.labels, labels) {
+			continue
+		}
+		upperBound, err := strconv.ParseFloat(sample.labels["le"], 64)
+		if err != nil {
+			continue
+		}
+		buckets[upperBound] += sample.value
+	}
+	return buckets
+}
+
+// hasLabels returns whether the given labels include the expected ones
+func hasLabels(labels map[string]string, expected map[string]string) bool {
+	for key, value := range expected {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/fatih/color"
 	. "github.com/onsi/ginkgo"
@@ -182,6 +183,45 @@ func (td *OsmTestData) GRPCRequest(req GRPCRequestDef) GRPCRequestResult {
 	}
 }
 
+// GRPCStreamRequest runs a streaming GRPC request, sending the given JSON messages to the stream of the GRPCRequestDef
+// at the given interval so that the stream stays open until the last message is sent, and returns a GRPCRequestResult
+// holding the responses received. The JSONRequest of the GRPCRequestDef is ignored.
+func (td *OsmTestData) GRPCStreamRequest(req GRPCRequestDef, messages []string, interval time.Duration) GRPCRequestResult {
+	tlsFlag := "-plaintext"
+	if req.UseTLS {
+		tlsFlag = "-insecure"
+	}
+
+	// grpcurl reads the messages from stdin with '-d @' and sends each of them as soon as it is read
+	var script []string
+	for i, message := range messages {
+		if i > 0 && interval > 0 {
+			script = append(script, fmt.Sprintf("sleep %d", int(interval.Seconds())))
+		}
+		script = append(script, fmt.Sprintf("echo '%s'", message))
+	}
+	commandArgs := fmt.Sprintf("(%s) | /grpcurl -d @ %s %s %s", strings.Join(script, "; "), tlsFlag, req.Destination, req.Symbol)
+	command := []string{"sh", "-c", commandArgs}
+
+	stdout, stderr, err := td.RunRemote(req.SourceNs, req.SourcePod, req.SourceContainer, command)
+	if err != nil {
+		// Error codes from the execution come through err
+		return GRPCRequestResult{
+			stdout,
+			fmt.Errorf("Remote exec err: %v | stderr: %s | cmd: %s", err, stderr, command),
+		}
+	}
+	if len(stderr) > 0 {
+		// no error from execution and proper exit code, we got some stderr though
+		td.T.Logf("[warn] Stderr: %v", stderr)
+	}
+
+	return GRPCRequestResult{
+		stdout,
+		nil,
+	}
+}
+
 // MapCurlOuput maps stdout from our specific curl,
 // it expects headers on stdout like "<name>: <value...>"
 func mapCurlOuput(curlOut string) map[string]string {
@@ -0,0 +1,161 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+	"sigs.k8s.io/kind/pkg/cluster"
+)
+
+const (
+	// defaultKindWorkers is the number of worker nodes of the kind clusters whose spec doesn't set it
+	defaultKindWorkers = 2
+)
+
+// KindClusterSpec describes the kind cluster an e2e test requires when run with the kindCluster install type. The zero
+// value is a cluster with a control plane node and 2 worker nodes, with IPv4 networking and without zones.
+type KindClusterSpec struct {
+	// Workers is the number of worker nodes of the cluster, 2 if 0
+	Workers int
+
+	// IPFamily is the IP family of the cluster networking: ipv4, ipv6 or dual, ipv4 if empty
+	IPFamily v1alpha4.ClusterIPFamily
+
+	// Zones is the number of zones the worker nodes are spread over round robin, by labeling them with the
+	// topology.kubernetes.io/zone label (zone-0, zone-1, ...), so that locality aware features can be tested. The
+	// nodes are not labeled if 0.
+	Zones int
+}
+
+// kindClusterSpecs are the kind clusters required by the tests of each OSMDescribe container, by container text
+var kindClusterSpecs = make(map[string]KindClusterSpec)
+
+// withDefaults returns the spec with the defaults of its unset fields
+func (s KindClusterSpec) withDefaults() KindClusterSpec {
+	if s.Workers <= 0 {
+		s.Workers = defaultKindWorkers
+	}
+	if s.IPFamily == "" {
+		s.IPFamily = v1alpha4.IPv4Family
+	}
+	return s
+}
+
+// String returns a description of the cluster, ex. 2 workers, ipv4
+func (s KindClusterSpec) String() string {
+	desc := fmt.Sprintf("%d workers, %s", s.Workers, s.IPFamily)
+	if s.Zones > 0 {
+		desc += fmt.Sprintf(", %d zones", s.Zones)
+	}
+	return desc
+}
+
+// registerKindClusterSpec records the kind cluster required by the tests of the OSMDescribe container with the given text
+func registerKindClusterSpec(containerText string, spec KindClusterSpec) {
+	kindClusterSpecs[containerText] = spec.withDefaults()
+}
+
+// currentKindClusterSpec returns the kind cluster required by the running test
+func currentKindClusterSpec() KindClusterSpec {
+	if texts := CurrentGinkgoTestDescription().ComponentTexts; len(texts) > 0 {
+		if spec, ok := kindClusterSpecs[texts[0]]; ok {
+			return spec
+		}
+	}
+	return KindClusterSpec{}.withDefaults()
+}
+
+// kindClusterConfig returns the configuration of the kind cluster of the given spec and Kubernetes version, the
+// default version of kind if empty. The first worker node is labeled as ready for ingress and maps the port 80 of
+// the host.
+func kindClusterConfig(spec KindClusterSpec, version string) *v1alpha4.Cluster {
+	clusterConfig := &v1alpha4.Cluster{
+		Networking: v1alpha4.Networking{
+			IPFamily: spec.IPFamily,
+		},
+		Nodes: []v1alpha4.Node{
+			{
+				Role: v1alpha4.ControlPlaneRole,
+			},
+		},
+	}
+
+	for i := 0; i < spec.Workers; i++ {
+		nodeLabels := ""
+		worker := v1alpha4.Node{
+			Role: v1alpha4.WorkerRole,
+		}
+		if i == 0 {
+			nodeLabels = "ingress-ready=true"
+			worker.ExtraPortMappings = []v1alpha4.PortMapping{
+				{
+					ContainerPort: 80,
+					HostPort:      80,
+					Protocol:      v1alpha4.PortMappingProtocolTCP,
+				},
+			}
+		}
+		if spec.Zones > 0 {
+			if nodeLabels != "" {
+				nodeLabels += ","
+			}
+			nodeLabels += fmt.Sprintf("%s=zone-%d", corev1.LabelTopologyZone, i%spec.Zones)
+		}
+		if nodeLabels != "" {
+			worker.KubeadmConfigPatches = []string{fmt.Sprintf(`kind: JoinConfiguration
+nodeRegistration:
+  kubeletExtraArgs:
+    node-labels: "%s"`, nodeLabels)}
+		}
+		clusterConfig.Nodes = append(clusterConfig.Nodes, worker)
+	}
+
+	if version != "" {
+		for i := range clusterConfig.Nodes {
+			clusterConfig.Nodes[i].Image = fmt.Sprintf("kindest/node:%s", version)
+		}
+	}
+	return clusterConfig
+}
+
+// ensureKindCluster creates the kind cluster of the given spec, replacing the cluster created for a previous test if
+// it doesn't match the spec
+func (td *OsmTestData) ensureKindCluster(spec KindClusterSpec) error {
+	if td.ClusterProvider != nil {
+		if td.kindClusterSpec == spec {
+			return nil
+		}
+		td.T.Logf("Deleting kind cluster %s (%s) to create one with %s", td.ClusterName, td.kindClusterSpec, spec)
+		if err := td.ClusterProvider.Delete(td.ClusterName, clientcmd.RecommendedHomeFile); err != nil {
+			return errors.Wrap(err, "failed to delete kind cluster")
+		}
+		td.ClusterProvider = nil
+	}
+
+	td.ClusterProvider = cluster.NewProvider()
+	td.T.Logf("Creating local kind cluster with %s", spec)
+	if err := td.ClusterProvider.Create(td.ClusterName, cluster.CreateWithV1Alpha4Config(kindClusterConfig(spec, td.ClusterVersion))); err != nil {
+		td.ClusterProvider = nil
+		return errors.Wrap(err, "failed to create kind cluster")
+	}
+	td.kindClusterSpec = spec
+	return nil
+}
+
+// IsIPv6Cluster returns whether the primary IP family of the services of the cluster the test runs on is IPv6, based
+// on the cluster IP of the kubernetes API service
+func (td *OsmTestData) IsIPv6Cluster() (bool, error) {
+	svc, err := td.Client.CoreV1().Services(metav1.NamespaceDefault).Get(context.Background(), "kubernetes", metav1.GetOptions{})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get the kubernetes API service")
+	}
+	ip := net.ParseIP(svc.Spec.ClusterIP)
+	return ip != nil && ip.To4() == nil, nil
+}
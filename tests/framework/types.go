@@ -19,6 +19,10 @@ type OSMDescribeInfo struct {
 	// Bucket indicates in which test Bucket the test will run in for CI. Each
 	// Bucket is run in parallel while tests in the same Bucket run sequentially.
 	Bucket int
+
+	// KindCluster is the kind cluster the tests require when run with the kindCluster install type. Tests run one after
+	// the other on the same cluster as long as they require the same one, the cluster is recreated otherwise.
+	KindCluster KindClusterSpec
 }
 
 // InstallType defines several OSM test deployment scenarios
@@ -71,6 +75,7 @@ type OsmTestData struct {
 	ConfigClient *versioned2.Clientset
 
 	ClusterProvider *cluster.Provider // provider, used when kindCluster is used
+	kindClusterSpec KindClusterSpec   // spec of the kind cluster created by the provider
 
 	DeployOnOpenShift bool // Determines whether to configure tests for OpenShift
 }
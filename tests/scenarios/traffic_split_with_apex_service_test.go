@@ -55,11 +55,13 @@ var _ = Describe(``+
 
 			// ---[  Get the config from rds.NewResponse()  ]-------
 			mockConfigurator.EXPECT().IsPermissiveTrafficPolicyMode().Return(false).AnyTimes()
+			mockConfigurator.EXPECT().GetSPIFFETrustDomain().Return("").AnyTimes()
 
 			mockConfigurator.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{
 				EnableWASMStats:    false,
 				EnableEgressPolicy: false,
 			}).AnyTimes()
+			mockConfigurator.EXPECT().GetRouteRegexConfig().Return(v1alpha1.RouteRegexSpec{}).AnyTimes()
 
 			resources, err := rds.NewResponse(meshCatalog, proxy, nil, mockConfigurator, nil, proxyRegistry)
 			It("did not return an error", func() {
@@ -73,7 +75,7 @@ var _ = Describe(``+
 			routeCfg, ok := resources[1].(*xds_route.RouteConfiguration)
 			It("returns a response that can be unmarshalled into an xds RouteConfiguration struct", func() {
 				Expect(ok).To(BeTrue())
-				Expect(routeCfg.Name).To(Equal("rds-outbound"))
+				Expect(routeCfg.Name).To(Equal("rds-outbound.8888"))
 			})
 
 			const (
@@ -11,7 +11,7 @@ import (
 	"github.com/google/uuid"
 	access "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/access/v1alpha3"
 	spec "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/specs/v1alpha4"
-	split "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha2"
+	split "github.com/servicemeshinterface/smi-sdk-go/pkg/apis/split/v1alpha4"
 	tassert "github.com/stretchr/testify/assert"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -238,6 +238,9 @@ func TestRDSRespose(t *testing.T) {
 			mockConfigurator.EXPECT().GetFeatureFlags().Return(v1alpha1.FeatureFlags{
 				EnableWASMStats: false,
 			}).AnyTimes()
+			mockConfigurator.EXPECT().GetRBACAuditConfig().Return(v1alpha1.RBACAuditSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetRouteRegexConfig().Return(v1alpha1.RouteRegexSpec{}).AnyTimes()
+			mockConfigurator.EXPECT().GetSPIFFETrustDomain().Return("").AnyTimes()
 
 			proxyRegistry := registry.NewProxyRegistry(registry.ExplicitProxyServiceMapper(func(*envoy.Proxy) ([]service.MeshService, error) {
 				return []service.MeshService{tests.BookstoreV1Service}, nil
@@ -247,6 +250,7 @@ func TestRDSRespose(t *testing.T) {
 			mockCatalog.EXPECT().ListOutboundTrafficPolicies(gomock.Any()).Return(tc.expectedOutboundPolicies).AnyTimes()
 			mockCatalog.EXPECT().GetIngressTrafficPolicy(gomock.Any()).Return(nil, nil).AnyTimes()
 			mockCatalog.EXPECT().GetEgressTrafficPolicy(gomock.Any()).Return(nil, nil).AnyTimes()
+			mockCatalog.EXPECT().GetKubeController().Return(mockKubeController).AnyTimes()
 
 			resources, err := rds.NewResponse(mockCatalog, proxy, nil, mockConfigurator, nil, proxyRegistry)
 			assert.Nil(err)
@@ -254,7 +258,7 @@ func TestRDSRespose(t *testing.T) {
 
 			// The RDS response will have two route configurations
 			// 1. rds-inbound
-			// 2. rds-outbound
+			// 2. rds-outbound.8888
 			assert.Equal(2, len(resources))
 
 			// Check the inbound route configuration
@@ -291,9 +295,9 @@ func TestRDSRespose(t *testing.T) {
 			routeConfig, ok = resources[1].(*xds_route.RouteConfiguration)
 			assert.True(ok)
 
-			// The rds-outbound will have the following virtual hosts :
+			// The rds-outbound.8888 will have the following virtual hosts :
 			// outbound_virtual-host|bookstore-apex
-			assert.Equal("rds-outbound", routeConfig.Name)
+			assert.Equal("rds-outbound.8888", routeConfig.Name)
 			assert.Equal(1, len(routeConfig.VirtualHosts))
 
 			assert.Equal("outbound_virtual-host|bookstore-apex.default.svc.cluster.local", routeConfig.VirtualHosts[0].Name)